@@ -36,9 +36,14 @@ if [ -f "portunix.rc" ]; then
     echo "portunix.rc updated successfully"
 fi
 
-# Build main binary with ldflags to set version
+# Build main binary with ldflags to set version and, when given, the
+# release signing key verified by self-update
+SIGNING_FLAG=""
+if [ -n "$PORTUNIX_SIGNING_PUBLIC_KEY" ]; then
+    SIGNING_FLAG="-X portunix.ai/app/update.SigningPublicKey=$PORTUNIX_SIGNING_PUBLIC_KEY"
+fi
 echo "Building main binary: portunix..."
-go build -ldflags "-X main.version=$VERSION -X portunix.ai/app/update.Version=$VERSION -s -w" -o portunix${EXT} .
+go build -ldflags "-X main.version=$VERSION -X portunix.ai/app/update.Version=$VERSION $SIGNING_FLAG -s -w" -o portunix${EXT} .
 
 if [ $? -ne 0 ]; then
     echo "Main binary build failed!"
@@ -118,8 +123,43 @@ go build -ldflags "-X main.version=$VERSION -X portunix.ai/app/update.Version=$V
 INSTALLER_BUILD=$?
 cd ../../..
 
+# Build ptx-go
+echo "Building ptx-go..."
+cd src/helpers/ptx-go
+go build -ldflags "-X main.version=$VERSION -X portunix.ai/app/update.Version=$VERSION -s -w" -o ../../../ptx-go${EXT} .
+GO_BUILD=$?
+cd ../../..
+
+# Build ptx-java
+echo "Building ptx-java..."
+cd src/helpers/ptx-java
+go build -ldflags "-X main.version=$VERSION -X portunix.ai/app/update.Version=$VERSION -s -w" -o ../../../ptx-java${EXT} .
+JAVA_BUILD=$?
+cd ../../..
+
+# Build ptx-docs
+echo "Building ptx-docs..."
+cd src/helpers/ptx-docs
+go build -ldflags "-X main.version=$VERSION -X portunix.ai/app/update.Version=$VERSION -s -w" -o ../../../ptx-docs${EXT} .
+DOCS_BUILD=$?
+cd ../../..
+
+# Build ptx-db
+echo "Building ptx-db..."
+cd src/helpers/ptx-db
+go build -ldflags "-X main.version=$VERSION -X portunix.ai/app/update.Version=$VERSION -s -w" -o ../../../ptx-db${EXT} .
+DB_BUILD=$?
+cd ../../..
+
+# Build ptx-ai
+echo "Building ptx-ai..."
+cd src/helpers/ptx-ai
+go build -ldflags "-X main.version=$VERSION -X portunix.ai/app/update.Version=$VERSION -s -w" -o ../../../ptx-ai${EXT} .
+AI_BUILD=$?
+cd ../../..
+
 # Check all builds
-if [ $CONTAINER_BUILD -ne 0 ] || [ $MCP_BUILD -ne 0 ] || [ $VIRT_BUILD -ne 0 ] || [ $ANSIBLE_BUILD -ne 0 ] || [ $PROMPTING_BUILD -ne 0 ] || [ $AIOPS_BUILD -ne 0 ] || [ $MAKE_BUILD -ne 0 ] || [ $PFT_BUILD -ne 0 ] || [ $TRACE_BUILD -ne 0 ] || [ $INSTALLER_BUILD -ne 0 ]; then
+if [ $CONTAINER_BUILD -ne 0 ] || [ $MCP_BUILD -ne 0 ] || [ $VIRT_BUILD -ne 0 ] || [ $ANSIBLE_BUILD -ne 0 ] || [ $PROMPTING_BUILD -ne 0 ] || [ $AIOPS_BUILD -ne 0 ] || [ $MAKE_BUILD -ne 0 ] || [ $PFT_BUILD -ne 0 ] || [ $TRACE_BUILD -ne 0 ] || [ $INSTALLER_BUILD -ne 0 ] || [ $GO_BUILD -ne 0 ] || [ $JAVA_BUILD -ne 0 ] || [ $DOCS_BUILD -ne 0 ] || [ $DB_BUILD -ne 0 ] || [ $AI_BUILD -ne 0 ]; then
     echo "Helper binary build failed!"
     exit 1
 fi
@@ -138,4 +178,9 @@ echo "Helper binaries:"
 ./ptx-make${EXT} --version
 ./ptx-pft${EXT} --version
 ./ptx-trace${EXT} --version
-./ptx-installer${EXT} --version
\ No newline at end of file
+./ptx-installer${EXT} --version
+./ptx-go${EXT} --version
+./ptx-java${EXT} --version
+./ptx-docs${EXT} --version
+./ptx-db${EXT} --version
+./ptx-ai${EXT} --version
\ No newline at end of file
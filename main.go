@@ -4,11 +4,19 @@ import (
 	_ "embed"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"portunix.ai/app/config"
+	"portunix.ai/app/journal"
+	"portunix.ai/app/metrics"
+	"portunix.ai/app/proxy"
+	"portunix.ai/app/remote"
 	"portunix.ai/app/sandbox"
 	"portunix.ai/app/update"
 	appversion "portunix.ai/app/version"
 	"portunix.ai/cmd"
+	"portunix.ai/portunix/pkg/logging"
 	"portunix.ai/portunix/src/dispatcher"
 )
 
@@ -29,13 +37,49 @@ func main() {
 	update.Version = version
 	appversion.ProductVersion = version
 
+	// Initialize logging from leading global flags (portunix --verbose container ...)
+	// and export the options so helper binaries inherit them
+	logOpts, args := logging.ParseFlags(os.Args[1:])
+	if !config.GetBool("logging.file", true) {
+		logOpts.NoFile = true
+	}
+	logging.Init("portunix", logOpts)
+	logging.Export(logOpts)
+	defer logging.Close()
+
+	// Apply the configured proxy before any network access; the exported
+	// variables reach helpers and every tool started from here
+	if _, err := proxy.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: proxy configuration: %v\n", err)
+	}
+	if keys := proxy.IgnoredProjectKeys(); len(keys) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring %s of the project configuration; proxy settings are read from the user and system scopes only\n", strings.Join(keys, ", "))
+	}
+
 	// Initialize dispatcher
 	disp := dispatcher.NewDispatcher(version)
 
+	// Run the command on another host over SSH (portunix --host user@server ...)
+	host, agentless, args, err := remote.ParseFlags(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if host != "" {
+		os.Exit(runRemote(disp, host, agentless, args))
+	} else if agentless {
+		fmt.Fprintln(os.Stderr, "Error: --agentless requires --host")
+		os.Exit(1)
+	}
+
 	// Check if we should dispatch to a helper binary
-	args := os.Args[1:]
 	if helperPath, shouldDispatch := disp.ShouldDispatch(args); shouldDispatch {
-		if err := disp.Dispatch(helperPath, args); err != nil {
+		tracker := metrics.Start(args, version)
+		tracker.PrepareHelper()
+		journal.Start(args)
+		err := disp.Dispatch(helperPath, args)
+		tracker.Finish(filepath.Base(helperPath), err)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -53,3 +97,36 @@ func main() {
 	// Normal command execution - always show help when no arguments
 	cmd.Execute()
 }
+
+// runRemote executes the command on a remote host and returns the exit code
+func runRemote(disp *dispatcher.Dispatcher, host string, agentless bool, args []string) int {
+	executor, err := remote.NewExecutor(host)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	executor.Agentless = agentless
+	executor.TTY = isTerminal(os.Stdin) && isTerminal(os.Stdout)
+	if helperPath, ok := disp.ShouldDispatch(args); ok {
+		executor.Helper = strings.TrimSuffix(filepath.Base(helperPath), ".exe")
+	}
+
+	tracker := metrics.Start(args, version)
+	mode, err := executor.Execute(args)
+	tracker.Finish("remote", err)
+	logging.Debug("remote command finished", "host", host, "mode", mode, "args", args)
+	if err != nil {
+		// The remote command already reported its own failure
+		if code := remote.ExitCode(err); code != 0 {
+			return code
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
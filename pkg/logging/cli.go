@@ -0,0 +1,393 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Command line logging shared by portunix and its helper binaries. Console
+// output honours --verbose/--quiet/--log-json, while every record is also
+// captured as JSON in ~/.portunix/logs so failed operations can be
+// diagnosed afterwards.
+
+// Environment variables used to pass logging options to helper binaries
+const (
+	EnvVerbose = "PORTUNIX_VERBOSE"
+	EnvQuiet   = "PORTUNIX_QUIET"
+	EnvLogJSON = "PORTUNIX_LOG_JSON"
+	EnvLogDir  = "PORTUNIX_LOG_DIR"
+	EnvNoFile  = "PORTUNIX_LOG_NO_FILE"
+)
+
+// DefaultRetentionDays is how long daily log files are kept
+const DefaultRetentionDays = 14
+
+// Options configures command line logging
+type Options struct {
+	Verbose bool   // Show debug records on the console
+	Quiet   bool   // Show only errors on the console
+	JSON    bool   // Console records as JSON lines
+	LogDir  string // Log file directory (default ~/.portunix/logs)
+	NoFile  bool   // Disable log file capture
+}
+
+var (
+	cliMu      sync.Mutex
+	cliLogger  = zerolog.Nop()
+	fileLogger = zerolog.Nop() // records that are not echoed to the console
+	cliOptions Options
+	logFile    *os.File
+)
+
+// ParseFlags consumes the leading --verbose, --quiet and --log-json flags
+// and returns the options with the remaining arguments. Parsing stops at the
+// first other argument, so flags of subcommands and wrapped tools
+// (portunix python venv info --verbose) pass through untouched.
+func ParseFlags(args []string) (Options, []string) {
+	opts := optionsFromEnv()
+	for len(args) > 0 {
+		switch args[0] {
+		case "--verbose":
+			opts.Verbose, opts.Quiet = true, false
+		case "--quiet":
+			opts.Quiet, opts.Verbose = true, false
+		case "--log-json":
+			opts.JSON = true
+		default:
+			return opts, args
+		}
+		args = args[1:]
+	}
+	return opts, args
+}
+
+// optionsFromEnv reads options exported by a parent portunix process
+func optionsFromEnv() Options {
+	return Options{
+		Verbose: envBool(EnvVerbose),
+		Quiet:   envBool(EnvQuiet),
+		JSON:    envBool(EnvLogJSON),
+		LogDir:  os.Getenv(EnvLogDir),
+		NoFile:  envBool(EnvNoFile),
+	}
+}
+
+func envBool(name string) bool {
+	b, _ := strconv.ParseBool(os.Getenv(name))
+	return b
+}
+
+// Export sets environment variables so helper processes inherit the options
+func Export(opts Options) {
+	os.Setenv(EnvVerbose, strconv.FormatBool(opts.Verbose))
+	os.Setenv(EnvQuiet, strconv.FormatBool(opts.Quiet))
+	os.Setenv(EnvLogJSON, strconv.FormatBool(opts.JSON))
+	os.Setenv(EnvNoFile, strconv.FormatBool(opts.NoFile))
+	if opts.LogDir != "" {
+		os.Setenv(EnvLogDir, opts.LogDir)
+	}
+}
+
+// Init configures command line logging for a component ("portunix",
+// "ptx-container", ...). A log file that cannot be opened only disables
+// file capture.
+func Init(component string, opts Options) error {
+	cliMu.Lock()
+	defer cliMu.Unlock()
+
+	closeFile()
+	cliOptions = opts
+
+	consoleLevel := zerolog.WarnLevel
+	if opts.Verbose {
+		consoleLevel = zerolog.DebugLevel
+	} else if opts.Quiet {
+		consoleLevel = zerolog.ErrorLevel
+	}
+
+	var console io.Writer = os.Stderr
+	if !opts.JSON {
+		console = &cliConsoleWriter{out: os.Stderr, verbose: opts.Verbose}
+	}
+	writers := []io.Writer{&levelFilterWriter{w: console, min: consoleLevel}}
+
+	var file io.Writer = io.Discard
+	var fileErr error
+	if !opts.NoFile {
+		var f *os.File
+		if f, fileErr = openLogFile(opts.LogDir); fileErr == nil {
+			logFile = f
+			file = f
+			writers = append(writers, f)
+		}
+	}
+
+	// The file captures debug records whatever the console shows
+	if zerolog.GlobalLevel() > zerolog.DebugLevel {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+
+	cliLogger = zerolog.New(zerolog.MultiLevelWriter(writers...)).With().
+		Timestamp().Str("component", component).Int("pid", os.Getpid()).Logger()
+	fileLogger = zerolog.New(file).With().
+		Timestamp().Str("component", component).Int("pid", os.Getpid()).Logger()
+	return fileErr
+}
+
+// InitFromEnv configures logging of a helper binary from the options
+// exported by the parent portunix process
+func InitFromEnv(component string) {
+	Init(component, optionsFromEnv())
+}
+
+// Close flushes and closes the log file
+func Close() {
+	cliMu.Lock()
+	defer cliMu.Unlock()
+	closeFile()
+	cliLogger = zerolog.Nop()
+	fileLogger = zerolog.Nop()
+}
+
+func closeFile() {
+	if logFile != nil {
+		logFile.Close()
+		logFile = nil
+	}
+}
+
+// LogDir returns the log directory
+func LogDir(override string) string {
+	if override != "" {
+		return override
+	}
+	if dir := os.Getenv(EnvLogDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-logs")
+	}
+	return filepath.Join(home, ".portunix", "logs")
+}
+
+// LogFileName returns the daily log file name for t
+func LogFileName(t time.Time) string {
+	return fmt.Sprintf("portunix-%s.log", t.Format("2006-01-02"))
+}
+
+// openLogFile opens today's log file for appending
+func openLogFile(dir string) (*os.File, error) {
+	dir = LogDir(dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(filepath.Join(dir, LogFileName(time.Now())), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+}
+
+// LogFiles returns log files in dir, newest first
+func LogFiles(dir string) ([]string, error) {
+	dir = LogDir(dir)
+	matches, err := filepath.Glob(filepath.Join(dir, "portunix-*.log"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(matches)))
+	return matches, nil
+}
+
+// Cleanup removes log files older than retentionDays and returns the number removed
+func Cleanup(dir string, retentionDays int) (int, error) {
+	files, err := LogFiles(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := LogFileName(time.Now().AddDate(0, 0, -retentionDays))
+	removed := 0
+	for _, f := range files {
+		if filepath.Base(f) < cutoff {
+			if err := os.Remove(f); err == nil {
+				removed++
+			}
+		}
+	}
+	return removed, nil
+}
+
+// IsVerbose reports whether --verbose is active
+func IsVerbose() bool {
+	return cliOptions.Verbose
+}
+
+// IsQuiet reports whether --quiet is active
+func IsQuiet() bool {
+	return cliOptions.Quiet
+}
+
+// cliLog writes a record with key/value fields to l
+func cliLog(l zerolog.Logger, level zerolog.Level, msg string, fields ...interface{}) {
+	event := l.WithLevel(level)
+	for i := 0; i < len(fields)-1; i += 2 {
+		if key, ok := fields[i].(string); ok {
+			if err, ok := fields[i+1].(error); ok {
+				event = event.AnErr(key, err)
+			} else {
+				event = event.Interface(key, fields[i+1])
+			}
+		}
+	}
+	event.Msg(msg)
+}
+
+func currentLogger() zerolog.Logger {
+	cliMu.Lock()
+	defer cliMu.Unlock()
+	return cliLogger
+}
+
+func currentFileLogger() zerolog.Logger {
+	cliMu.Lock()
+	defer cliMu.Unlock()
+	return fileLogger
+}
+
+// Debug logs a debug record with key/value fields
+func Debug(msg string, fields ...interface{}) {
+	cliLog(currentLogger(), zerolog.DebugLevel, msg, fields...)
+}
+
+// Info logs an informational record with key/value fields
+func Info(msg string, fields ...interface{}) {
+	cliLog(currentLogger(), zerolog.InfoLevel, msg, fields...)
+}
+
+// Warn logs a warning record with key/value fields
+func Warn(msg string, fields ...interface{}) {
+	cliLog(currentLogger(), zerolog.WarnLevel, msg, fields...)
+}
+
+// Error logs an error record with key/value fields
+func Error(msg string, fields ...interface{}) {
+	cliLog(currentLogger(), zerolog.ErrorLevel, msg, fields...)
+}
+
+// Capture records a message in the log file only. Use it for failures that
+// the command already reported to the user in its own format.
+func Capture(level zerolog.Level, msg string, fields ...interface{}) {
+	cliLog(currentFileLogger(), level, msg, fields...)
+}
+
+// Printf prints user-facing output to stdout unless --quiet is active and
+// records it in the log file
+func Printf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	Capture(zerolog.InfoLevel, strings.TrimRight(msg, "\n"), "output", true)
+	if !cliOptions.Quiet {
+		fmt.Print(msg)
+	}
+}
+
+// levelFilterWriter passes on records at or above min
+type levelFilterWriter struct {
+	w   io.Writer
+	min zerolog.Level
+}
+
+func (f *levelFilterWriter) Write(p []byte) (int, error) {
+	return f.w.Write(p)
+}
+
+func (f *levelFilterWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < f.min {
+		return len(p), nil
+	}
+	return f.w.Write(p)
+}
+
+// cliConsoleWriter renders JSON records for humans: "❌ message: error",
+// with all fields appended in verbose mode
+type cliConsoleWriter struct {
+	out     io.Writer
+	verbose bool
+	mu      sync.Mutex
+}
+
+func (w *cliConsoleWriter) Write(p []byte) (int, error) {
+	fields, err := orderedFields(p)
+	if err != nil {
+		return 0, err
+	}
+
+	var b strings.Builder
+	values := map[string]interface{}{}
+	for _, f := range fields {
+		values[f.key] = f.value
+	}
+	switch level, _ := zerolog.ParseLevel(fmt.Sprint(values[zerolog.LevelFieldName])); {
+	case level >= zerolog.ErrorLevel:
+		b.WriteString("❌ ")
+	case level == zerolog.WarnLevel:
+		b.WriteString("⚠️  ")
+	case level < zerolog.InfoLevel:
+		b.WriteString("🔍 ")
+	}
+	fmt.Fprint(&b, values[zerolog.MessageFieldName])
+
+	for _, f := range fields {
+		switch {
+		case f.key == zerolog.LevelFieldName || f.key == zerolog.MessageFieldName ||
+			f.key == zerolog.TimestampFieldName || f.key == "component" || f.key == "pid":
+			continue
+		case w.verbose:
+			fmt.Fprintf(&b, " %s=%v", f.key, f.value)
+		case f.key == zerolog.ErrorFieldName:
+			fmt.Fprintf(&b, ": %v", f.value)
+		}
+	}
+	b.WriteString("\n")
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := io.WriteString(w.out, b.String()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+type jsonField struct {
+	key   string
+	value interface{}
+}
+
+// orderedFields decodes a JSON record keeping the order of its fields
+func orderedFields(p []byte) ([]jsonField, error) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var fields []jsonField
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, jsonField{key: fmt.Sprint(tok), value: value})
+	}
+	return fields, nil
+}
@@ -61,6 +61,7 @@ PLATFORMS = [
     "linux-arm64",
     "windows-amd64",
     "darwin-amd64",
+    "darwin-arm64",
 ]
 
 
@@ -14,11 +14,6 @@ mkdir -p $DIST_DIR
 # Generate checksums for each platform
 for OS in linux windows darwin; do
     for ARCH in amd64 arm64; do
-        # Skip darwin/arm64 for now
-        if [ "$OS" = "darwin" ] && [ "$ARCH" = "arm64" ]; then
-            continue
-        fi
-        
         BINARY_NAME="portunix-$VERSION-$OS-$ARCH"
         if [ "$OS" = "windows" ]; then
             BINARY_NAME="${BINARY_NAME}.exe"
@@ -18,10 +18,12 @@ This script:
 4. Creates platform archives for cross-platform distribution
 5. Creates separate platforms bundle (ADR-035)
 6. Generates checksums and release notes
+7. Signs the checksums when PORTUNIX_SIGNING_PUBLIC_KEY is set
 
 The script automatically uses the project's .venv if available.
 """
 
+import base64
 import hashlib
 import os
 import re
@@ -260,6 +262,7 @@ def build_platform_archives() -> bool:
         ("linux", "arm64"),
         ("windows", "amd64"),
         ("darwin", "amd64"),
+        ("darwin", "arm64"),
     ]
 
     # Define helper binaries to build
@@ -453,6 +456,55 @@ def copy_quickstart_scripts() -> None:
     print()
 
 
+def sign_checksums() -> bool:
+    """Sign the checksums file with the release signing key.
+
+    Binaries built with PORTUNIX_SIGNING_PUBLIC_KEY refuse releases without a
+    valid checksums_<version>.txt.sig, so the key pair must be provided together:
+    PORTUNIX_SIGNING_KEY is the path of the ed25519 private key (PEM).
+    """
+    print_step("Signing checksums...")
+    public_key = os.environ.get("PORTUNIX_SIGNING_PUBLIC_KEY", "")
+    private_key = os.environ.get("PORTUNIX_SIGNING_KEY", "")
+    if not public_key:
+        print_warning("PORTUNIX_SIGNING_PUBLIC_KEY not set, release is not signed")
+        print()
+        return True
+    if not private_key:
+        print_error("PORTUNIX_SIGNING_KEY (ed25519 private key file) is required to sign the release")
+        return False
+
+    project_root = get_project_root()
+    dist_dir = project_root / "dist"
+    checksums_files = list(dist_dir.glob("checksums_*.txt"))
+    if not checksums_files:
+        print_error("No checksums file to sign")
+        return False
+    checksums_file = checksums_files[0]
+
+    try:
+        # The raw public key is the last 32 bytes of its DER encoding
+        der = subprocess.run(
+            ["openssl", "pkey", "-in", private_key, "-pubout", "-outform", "DER"],
+            check=True, capture_output=True).stdout
+        if base64.b64encode(der[-32:]).decode() != public_key:
+            print_error("PORTUNIX_SIGNING_KEY does not match PORTUNIX_SIGNING_PUBLIC_KEY")
+            return False
+
+        signature = subprocess.run(
+            ["openssl", "pkeyutl", "-sign", "-inkey", private_key, "-rawin", "-in", str(checksums_file)],
+            check=True, capture_output=True).stdout
+    except (subprocess.CalledProcessError, FileNotFoundError) as e:
+        print_error(f"Failed to sign checksums: {e}")
+        return False
+
+    sig_file = checksums_file.with_name(checksums_file.name + ".sig")
+    sig_file.write_text(base64.b64encode(signature).decode() + "\n")
+    print_info(f"✓ Signed {checksums_file.name} -> {sig_file.name}")
+    print()
+    return True
+
+
 def verify_outputs() -> bool:
     """Verify generated files"""
     print_step("Verifying generated files...")
@@ -579,8 +631,14 @@ cd portunix_{version_num}_linux_amd64
 
 ### macOS
 ```bash
+# Apple Silicon (M1 and newer)
+curl -LO https://github.com/cassandragargoyle/portunix/releases/download/{version}/portunix_{version_num}_darwin_arm64.tar.gz
+tar -xzf portunix_{version_num}_darwin_arm64.tar.gz
+cd portunix_{version_num}_darwin_arm64
+./install.sh
+
 # Intel Macs
-wget https://github.com/cassandragargoyle/portunix/releases/download/{version}/portunix_{version_num}_darwin_amd64.tar.gz
+curl -LO https://github.com/cassandragargoyle/portunix/releases/download/{version}/portunix_{version_num}_darwin_amd64.tar.gz
 tar -xzf portunix_{version_num}_darwin_amd64.tar.gz
 cd portunix_{version_num}_darwin_amd64
 ./install.sh
@@ -762,6 +820,10 @@ def main() -> int:
     # Copy quickstart scripts
     copy_quickstart_scripts()
 
+    # Sign checksums (after the last change to the checksums file)
+    if not sign_checksums():
+        return 1
+
     # Verify outputs
     if not verify_outputs():
         return 1
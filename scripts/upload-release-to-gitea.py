@@ -255,6 +255,9 @@ def upload_release(tea_bin: str, version: str, archives: List[Path],
     all_files = list(archives)
     if checksum_file and checksum_file.exists():
         all_files.append(checksum_file)
+        signature_file = checksum_file.with_name(checksum_file.name + ".sig")
+        if signature_file.exists():
+            all_files.append(signature_file)
 
     for f in all_files:
         cmd.extend(["--asset", str(f)])
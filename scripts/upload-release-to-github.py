@@ -182,6 +182,9 @@ def upload_release(version: str, archives: List[Path], checksum_file: Optional[P
 
     if checksum_file and checksum_file.exists():
         cmd.append(str(checksum_file))
+        signature_file = checksum_file.with_name(checksum_file.name + ".sig")
+        if signature_file.exists():
+            cmd.append(str(signature_file))
 
     # Show what we're uploading
     print_info("Files to upload:")
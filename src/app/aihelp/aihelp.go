@@ -0,0 +1,111 @@
+// Package aihelp defines the machine-readable help format printed by
+// "--help-ai" in portunix and its helpers, so AI agents and shells can
+// introspect commands, arguments, flags and examples.
+package aihelp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersion is incremented on incompatible changes of the format
+const SchemaVersion = 1
+
+// Help is the top-level --help-ai document of a tool
+type Help struct {
+	Schema      int           `json:"schema"`
+	Tool        string        `json:"tool"`
+	Version     string        `json:"version"`
+	Description string        `json:"description"`
+	Commands    []Command     `json:"commands"`
+	GlobalFlags []Flag        `json:"global_flags,omitempty"`
+	Environment []Environment `json:"environment,omitempty"`
+}
+
+// Command describes a command or subcommand, named by its full path
+// without the binary name (e.g. "container run")
+type Command struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Category    string     `json:"category,omitempty"`
+	Aliases     []string   `json:"aliases,omitempty"`
+	Arguments   []Argument `json:"arguments,omitempty"`
+	Flags       []Flag     `json:"flags,omitempty"`
+	Examples    []string   `json:"examples,omitempty"`
+}
+
+// Argument describes a positional argument
+type Argument struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"` // string, integer, boolean, path, url, version
+	Required    bool     `json:"required"`
+	Variadic    bool     `json:"variadic,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Choices     []string `json:"choices,omitempty"`
+}
+
+// Flag describes a command-line flag
+type Flag struct {
+	Name        string   `json:"name"` // without leading dashes
+	Shorthand   string   `json:"shorthand,omitempty"`
+	Type        string   `json:"type"` // string, integer, boolean, duration, stringArray
+	Default     string   `json:"default,omitempty"`
+	Description string   `json:"description"`
+	Choices     []string `json:"choices,omitempty"`
+}
+
+// Environment describes an environment variable honoured by the tool
+type Environment struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// StandardGlobalFlags lists the flags accepted by every portunix command
+func StandardGlobalFlags() []Flag {
+	return []Flag{
+		{Name: "help-ai", Type: "boolean", Description: "Show machine-readable help in JSON format"},
+		{Name: "help-expert", Type: "boolean", Description: "Show extended help with all options and examples"},
+		{Name: "verbose", Type: "boolean", Description: "Show debug output"},
+		{Name: "quiet", Type: "boolean", Description: "Show errors only"},
+		{Name: "log-json", Type: "boolean", Description: "Write log output as JSON lines"},
+	}
+}
+
+// Marshal renders the help document as indented JSON
+func (h Help) Marshal() (string, error) {
+	if h.Schema == 0 {
+		h.Schema = SchemaVersion
+	}
+	if h.GlobalFlags == nil {
+		h.GlobalFlags = StandardGlobalFlags()
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Print writes the help document to stdout
+func (h Help) Print() {
+	out, err := h.Marshal()
+	if err != nil {
+		fmt.Printf("{\"error\": %q}\n", err.Error())
+		return
+	}
+	fmt.Println(out)
+}
+
+// Requested reports whether --help-ai appears in args before a "--"
+// separator, so it is not mistaken for an argument of a wrapped command
+func Requested(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if arg == "--help-ai" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,231 @@
+package aihelp
+
+import (
+	"sort"
+	"strings"
+)
+
+// Candidate is a single shell completion suggestion
+type Candidate struct {
+	Value       string
+	Description string
+}
+
+// Completion is the result of completing a partially typed command line
+type Completion struct {
+	Candidates []Candidate
+	Files      bool // fall back to file name completion
+}
+
+// Complete suggests values for the word being typed (toComplete) after the
+// already typed words, e.g. words ["container", "network"] and toComplete
+// "cr" yields "create". Words are matched against the full command names of
+// the help document, flags against the flags of the deepest matched command.
+func (h Help) Complete(words []string, toComplete string) Completion {
+	var path []string
+	var positional int
+	var cmd *Command
+
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if word == "--" {
+			return Completion{Files: true}
+		}
+		if strings.HasPrefix(word, "-") {
+			if f := h.lookupFlag(cmd, word); f != nil && takesValue(f) && !strings.Contains(word, "=") {
+				if i == len(words)-1 {
+					return flagValueCompletion(f, toComplete)
+				}
+				i++
+			}
+			continue
+		}
+		if positional == 0 && h.hasCommandPrefix(append(path, word)) {
+			path = append(path, word)
+			cmd = h.command(path)
+			continue
+		}
+		positional++
+	}
+
+	if strings.HasPrefix(toComplete, "-") {
+		return Completion{Candidates: filterCandidates(h.flagCandidates(cmd), toComplete)}
+	}
+
+	var candidates []Candidate
+	if positional == 0 {
+		candidates = h.subcommandCandidates(path)
+	}
+	if cmd == nil {
+		return Completion{Candidates: filterCandidates(candidates, toComplete)}
+	}
+
+	files := false
+	if arg := argumentAt(cmd.Arguments, positional); arg != nil {
+		for _, choice := range arg.Choices {
+			candidates = append(candidates, Candidate{Value: choice, Description: arg.Description})
+		}
+		files = arg.Type == "path" && len(arg.Choices) == 0
+	}
+	return Completion{Candidates: filterCandidates(candidates, toComplete), Files: files}
+}
+
+// hasCommandPrefix reports whether some command name starts with the words
+func (h Help) hasCommandPrefix(words []string) bool {
+	prefix := strings.Join(words, " ")
+	for _, c := range h.Commands {
+		if c.Name == prefix || strings.HasPrefix(c.Name, prefix+" ") {
+			return true
+		}
+		for _, alias := range c.Aliases {
+			if commandParent(c.Name, alias) == prefix {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// command returns the command named exactly by the words, if any
+func (h Help) command(words []string) *Command {
+	name := strings.Join(words, " ")
+	for i := range h.Commands {
+		c := &h.Commands[i]
+		if c.Name == name {
+			return c
+		}
+		for _, alias := range c.Aliases {
+			if commandParent(c.Name, alias) == name {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// commandParent replaces the last word of a command name with an alias
+func commandParent(name, alias string) string {
+	if i := strings.LastIndex(name, " "); i >= 0 {
+		return name[:i+1] + alias
+	}
+	return alias
+}
+
+// subcommandCandidates lists the next command words after path
+func (h Help) subcommandCandidates(path []string) []Candidate {
+	prefix := strings.Join(path, " ")
+	if prefix != "" {
+		prefix += " "
+	}
+
+	seen := make(map[string]bool)
+	var candidates []Candidate
+	for _, c := range h.Commands {
+		if !strings.HasPrefix(c.Name, prefix) {
+			continue
+		}
+		rest := strings.Fields(strings.TrimPrefix(c.Name, prefix))
+		if len(rest) == 0 || seen[rest[0]] {
+			continue
+		}
+		seen[rest[0]] = true
+
+		description := ""
+		if len(rest) == 1 {
+			description = firstLine(c.Description)
+		}
+		candidates = append(candidates, Candidate{Value: rest[0], Description: description})
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Value < candidates[j].Value })
+	return candidates
+}
+
+// flagCandidates lists the flags of cmd followed by the global flags
+func (h Help) flagCandidates(cmd *Command) []Candidate {
+	var candidates []Candidate
+	add := func(flags []Flag) {
+		for _, f := range flags {
+			candidates = append(candidates, Candidate{Value: "--" + f.Name, Description: f.Description})
+		}
+	}
+	if cmd != nil {
+		add(cmd.Flags)
+	}
+	add(h.globalFlags())
+	return candidates
+}
+
+// lookupFlag finds a flag of cmd or a global flag by its command-line form
+func (h Help) lookupFlag(cmd *Command, word string) *Flag {
+	name := strings.SplitN(strings.TrimLeft(word, "-"), "=", 2)[0]
+	long := strings.HasPrefix(word, "--")
+
+	var flags []Flag
+	if cmd != nil {
+		flags = append(flags, cmd.Flags...)
+	}
+	flags = append(flags, h.globalFlags()...)
+	for i := range flags {
+		if (long && flags[i].Name == name) || (!long && flags[i].Shorthand != "" && flags[i].Shorthand == name) {
+			return &flags[i]
+		}
+	}
+	return nil
+}
+
+// globalFlags returns the documented global flags, or the standard ones for
+// helpers whose --help-ai output predates the field
+func (h Help) globalFlags() []Flag {
+	if h.GlobalFlags != nil {
+		return h.GlobalFlags
+	}
+	return StandardGlobalFlags()
+}
+
+// takesValue reports whether a flag consumes the following word
+func takesValue(f *Flag) bool {
+	return f.Type != "boolean" && f.Type != "bool"
+}
+
+// flagValueCompletion completes the value of a flag
+func flagValueCompletion(f *Flag, toComplete string) Completion {
+	if len(f.Choices) == 0 {
+		return Completion{Files: f.Type == "path"}
+	}
+	var candidates []Candidate
+	for _, choice := range f.Choices {
+		candidates = append(candidates, Candidate{Value: choice})
+	}
+	return Completion{Candidates: filterCandidates(candidates, toComplete)}
+}
+
+// argumentAt returns the positional argument at index, honouring a
+// trailing variadic argument
+func argumentAt(args []Argument, index int) *Argument {
+	if index < len(args) {
+		return &args[index]
+	}
+	if n := len(args); n > 0 && args[n-1].Variadic {
+		return &args[n-1]
+	}
+	return nil
+}
+
+// filterCandidates keeps the candidates starting with prefix
+func filterCandidates(candidates []Candidate, prefix string) []Candidate {
+	var result []Candidate
+	for _, c := range candidates {
+		if strings.HasPrefix(c.Value, prefix) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// firstLine shortens multi-line descriptions for completion menus
+func firstLine(s string) string {
+	if i := strings.Index(s, "\n"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
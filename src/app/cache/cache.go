@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"runtime"
 	"time"
+
+	"portunix.ai/app/config"
 )
 
 // Category represents a cache category with its own storage limits
@@ -60,16 +62,17 @@ func DefaultConfig() *Config {
 	}
 }
 
-// LoadConfig loads cache configuration from environment variables,
+// LoadConfig loads cache configuration from the cache.dir and
+// cache.disabled settings (PORTUNIX_CACHE_DIR, PORTUNIX_CACHE_DISABLED),
 // falling back to defaults
 func LoadConfig() *Config {
 	cfg := DefaultConfig()
 
-	if dir := os.Getenv("PORTUNIX_CACHE_DIR"); dir != "" {
+	if dir := config.GetString("cache.dir", ""); dir != "" {
 		cfg.BaseDir = dir
 	}
 
-	if disabled := os.Getenv("PORTUNIX_CACHE_DISABLED"); disabled == "true" || disabled == "1" {
+	if config.GetBool("cache.disabled", false) {
 		cfg.Enabled = false
 	}
 
@@ -131,6 +134,17 @@ func (m *Manager) CategoryDir(cat Category) string {
 	return filepath.Join(m.config.BaseDir, string(cat))
 }
 
+// PartialDir returns the directory keeping interrupted downloads until they
+// are resumed
+func (m *Manager) PartialDir() string {
+	return filepath.Join(m.config.BaseDir, "partial")
+}
+
+// PartialPath returns where the interrupted download of key is kept
+func (m *Manager) PartialPath(key string) string {
+	return filepath.Join(m.PartialDir(), key)
+}
+
 // EnsureDirs creates the cache directory structure if it doesn't exist
 func (m *Manager) EnsureDirs() error {
 	for _, cat := range AllCategories() {
@@ -156,6 +156,33 @@ func TestGetExpired(t *testing.T) {
 	}
 }
 
+func TestLookupExpired(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Categories[CategoryDownloads] = CategoryConfig{MaxSize: 100 << 20, TTL: 1 * time.Millisecond}
+	mgr := NewManagerWithConfig(cfg)
+
+	tmpFile := filepath.Join(t.TempDir(), "offline.bin")
+	os.WriteFile(tmpFile, []byte("data"), 0644)
+
+	key := CacheKey("offline-test")
+	if _, err := mgr.StoreWithLabel(CategoryDownloads, key, tmpFile, "offline-test", "tool 1.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	path, meta, err := mgr.Lookup(CategoryDownloads, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path == "" || meta == nil || meta.Label != "tool 1.0" {
+		t.Error("Lookup should return expired entries")
+	}
+	if path != mgr.EntryPath(meta) {
+		t.Errorf("EntryPath mismatch: %s != %s", mgr.EntryPath(meta), path)
+	}
+}
+
 func TestRemove(t *testing.T) {
 	cfg := testConfig(t)
 	mgr := NewManagerWithConfig(cfg)
@@ -240,6 +267,84 @@ func TestClean(t *testing.T) {
 	}
 }
 
+func TestPrune(t *testing.T) {
+	cfg := testConfig(t)
+	mgr := NewManagerWithConfig(cfg)
+
+	for _, name := range []string{"old.bin", "new.bin", "missing.bin"} {
+		tmpFile := filepath.Join(t.TempDir(), name)
+		os.WriteFile(tmpFile, []byte("prune test"), 0644)
+		mgr.Store(CategoryDownloads, CacheKey(name), tmpFile, name)
+	}
+
+	// Age one entry, drop the file of another and leave an interrupted store
+	old, _ := mgr.loadMeta(CategoryDownloads, CacheKey("old.bin"))
+	old.CreatedAt = time.Now().Add(-48 * time.Hour)
+	mgr.saveMeta(CategoryDownloads, old.Key, old)
+	os.Remove(mgr.entryFilePath(CategoryDownloads, CacheKey("missing.bin"), "missing.bin"))
+	os.MkdirAll(mgr.entryDir(CategoryDownloads, "partial"), 0755)
+
+	// Interrupted downloads are kept until they are too old to resume
+	os.MkdirAll(mgr.PartialDir(), 0755)
+	os.WriteFile(mgr.PartialPath("stale"), []byte("half"), 0644)
+	os.Chtimes(mgr.PartialPath("stale"), time.Now().Add(-48*time.Hour), time.Now().Add(-48*time.Hour))
+	os.WriteFile(mgr.PartialPath("recent"), []byte("half"), 0644)
+
+	result, err := mgr.Prune(24 * time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RemovedFiles != 4 {
+		t.Errorf("Expected 4 removed, got %d", result.RemovedFiles)
+	}
+	if _, err := os.Stat(mgr.PartialPath("stale")); !os.IsNotExist(err) {
+		t.Error("Stale interrupted download should be removed")
+	}
+	if _, err := os.Stat(mgr.PartialPath("recent")); err != nil {
+		t.Error("Recent interrupted download should be kept")
+	}
+
+	entries, _ := mgr.ListEntries(CategoryDownloads)
+	if len(entries) != 1 || entries[0].Filename != "new.bin" {
+		t.Errorf("Only new.bin should remain, got %v", entries)
+	}
+	if _, err := os.Stat(mgr.entryDir(CategoryDownloads, "partial")); !os.IsNotExist(err) {
+		t.Error("Orphaned entry directory should be removed")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	cfg := testConfig(t)
+	mgr := NewManagerWithConfig(cfg)
+
+	for _, name := range []string{"good.bin", "corrupt.bin"} {
+		tmpFile := filepath.Join(t.TempDir(), name)
+		os.WriteFile(tmpFile, []byte("verify test"), 0644)
+		mgr.Store(CategoryDownloads, CacheKey(name), tmpFile, name)
+	}
+	corrupt := mgr.entryFilePath(CategoryDownloads, CacheKey("corrupt.bin"), "corrupt.bin")
+	os.WriteFile(corrupt, []byte("tampered"), 0644)
+
+	result, err := mgr.Verify(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Checked != 2 || len(result.Issues) != 1 || result.Issues[0].Entry.Filename != "corrupt.bin" {
+		t.Fatalf("Unexpected verify result: %+v", result)
+	}
+	if _, err := os.Stat(corrupt); err != nil {
+		t.Error("Verify without remove should keep entries")
+	}
+
+	if _, err := mgr.Verify(true); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := mgr.ListEntries(CategoryDownloads)
+	if len(entries) != 1 {
+		t.Errorf("Corrupt entry should be removed, %d entries left", len(entries))
+	}
+}
+
 func TestPurge(t *testing.T) {
 	cfg := testConfig(t)
 	mgr := NewManagerWithConfig(cfg)
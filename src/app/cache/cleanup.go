@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"hash"
 	"os"
+	"path/filepath"
 	"sort"
 	"time"
 )
@@ -45,6 +46,125 @@ func (m *Manager) Clean() (*CleanResult, error) {
 	return result, nil
 }
 
+// Prune removes entries created more than maxAge ago, or expired entries
+// when maxAge is zero, together with entries whose file is gone and
+// directories left behind by interrupted stores. Interrupted downloads not
+// resumed within maxAge, or the download TTL, are removed too. Category
+// size limits are enforced afterwards.
+func (m *Manager) Prune(maxAge time.Duration) (*CleanResult, error) {
+	result := &CleanResult{}
+	cutoff := time.Now().Add(-maxAge)
+
+	partialCutoff := cutoff
+	if maxAge == 0 {
+		partialCutoff = time.Now().Add(-m.config.Categories[CategoryDownloads].TTL)
+	}
+	if partials, err := os.ReadDir(m.PartialDir()); err == nil {
+		for _, partial := range partials {
+			info, err := partial.Info()
+			if err != nil || !info.ModTime().Before(partialCutoff) {
+				continue
+			}
+			if os.Remove(filepath.Join(m.PartialDir(), partial.Name())) == nil {
+				result.FreedBytes += info.Size()
+				result.RemovedFiles++
+			}
+		}
+	}
+
+	for _, cat := range AllCategories() {
+		dirs, err := os.ReadDir(m.CategoryDir(cat))
+		if err != nil {
+			continue
+		}
+
+		for _, dir := range dirs {
+			if !dir.IsDir() {
+				continue
+			}
+			key := dir.Name()
+			entry, err := m.loadMeta(cat, key)
+			if err != nil {
+				size, _, _ := dirStats(m.entryDir(cat, key))
+				result.FreedBytes += size
+				result.RemovedFiles++
+				_ = m.Remove(cat, key)
+				continue
+			}
+
+			stale := entry.IsExpired()
+			if maxAge > 0 {
+				stale = entry.CreatedAt.Before(cutoff)
+			}
+			if _, err := os.Stat(m.entryFilePath(cat, key, entry.Filename)); err != nil {
+				stale = true
+			}
+			if stale {
+				result.FreedBytes += entry.Size
+				result.RemovedFiles++
+				_ = m.Remove(cat, key)
+			}
+		}
+	}
+
+	enforced, err := m.EnforceSize()
+	if err != nil {
+		return result, err
+	}
+	result.RemovedFiles += enforced.RemovedFiles
+	result.FreedBytes += enforced.FreedBytes
+
+	return result, nil
+}
+
+// VerifyIssue describes a cache entry that failed verification
+type VerifyIssue struct {
+	Entry   *EntryMeta `json:"entry"`
+	Problem string     `json:"problem"`
+}
+
+// VerifyResult holds results of a verification run
+type VerifyResult struct {
+	Checked int           `json:"checked"`
+	Issues  []VerifyIssue `json:"issues"`
+}
+
+// Verify recomputes the checksum of every cached file and reports entries
+// whose file is missing or no longer matches the checksum recorded when it
+// was stored. When remove is set, failing entries are deleted.
+func (m *Manager) Verify(remove bool) (*VerifyResult, error) {
+	result := &VerifyResult{}
+
+	for _, cat := range AllCategories() {
+		entries, err := m.ListEntries(cat)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			result.Checked++
+			problem := ""
+			checksum, err := fileChecksum(m.EntryPath(entry))
+			switch {
+			case err != nil:
+				problem = "file is missing or unreadable"
+			case entry.Checksum != "" && checksum != entry.Checksum:
+				problem = fmt.Sprintf("checksum mismatch: expected %s, got %s", entry.Checksum, checksum)
+			}
+			if problem == "" {
+				continue
+			}
+
+			result.Issues = append(result.Issues, VerifyIssue{Entry: entry, Problem: problem})
+			if remove {
+				_ = m.Remove(cat, entry.Key)
+			}
+		}
+	}
+
+	return result, nil
+}
+
 // Purge removes all cache contents
 func (m *Manager) Purge() (*CleanResult, error) {
 	info, err := m.GetInfo()
@@ -63,6 +183,9 @@ func (m *Manager) Purge() (*CleanResult, error) {
 			return result, fmt.Errorf("failed to purge %s: %w", cat, err)
 		}
 	}
+	if err := os.RemoveAll(m.PartialDir()); err != nil {
+		return result, fmt.Errorf("failed to purge interrupted downloads: %w", err)
+	}
 
 	return result, nil
 }
@@ -13,8 +13,9 @@ import (
 type EntryMeta struct {
 	Key       string    `json:"key"`
 	Category  Category  `json:"category"`
-	Source    string    `json:"source"`   // original URL or identifier
-	Filename  string    `json:"filename"` // original filename
+	Source    string    `json:"source"`          // original URL or identifier
+	Filename  string    `json:"filename"`        // original filename
+	Label     string    `json:"label,omitempty"` // owner of the entry, e.g. "nodejs 20.11.1"
 	Size      int64     `json:"size"`
 	Checksum  string    `json:"checksum"` // SHA256 checksum
 	CreatedAt time.Time `json:"created_at"`
@@ -58,8 +59,34 @@ func (m *Manager) Get(cat Category, key string) (string, *EntryMeta, error) {
 	return filePath, meta, nil
 }
 
+// Lookup returns a cached file path like Get but also returns expired
+// entries. Offline installs use it to reach artifacts past their TTL.
+func (m *Manager) Lookup(cat Category, key string) (string, *EntryMeta, error) {
+	if !m.config.Enabled {
+		return "", nil, nil
+	}
+
+	meta, err := m.loadMeta(cat, key)
+	if err != nil {
+		return "", nil, nil // not found
+	}
+
+	filePath := m.entryFilePath(cat, key, meta.Filename)
+	if _, err := os.Stat(filePath); err != nil {
+		return "", nil, nil
+	}
+
+	return filePath, meta, nil
+}
+
 // Store copies a file into the cache under the given category and key
 func (m *Manager) Store(cat Category, key string, sourcePath string, source string) (*EntryMeta, error) {
+	return m.StoreWithLabel(cat, key, sourcePath, source, "")
+}
+
+// StoreWithLabel stores a file like Store and records which package or
+// component the entry belongs to
+func (m *Manager) StoreWithLabel(cat Category, key string, sourcePath string, source string, label string) (*EntryMeta, error) {
 	if !m.config.Enabled {
 		return nil, nil
 	}
@@ -105,6 +132,7 @@ func (m *Manager) Store(cat Category, key string, sourcePath string, source stri
 		Category:  cat,
 		Source:    source,
 		Filename:  filename,
+		Label:     label,
 		Size:      srcInfo.Size(),
 		Checksum:  checksum,
 		CreatedAt: time.Now(),
@@ -118,6 +146,11 @@ func (m *Manager) Store(cat Category, key string, sourcePath string, source stri
 	return meta, nil
 }
 
+// EntryPath returns the path of the cached file of an entry
+func (m *Manager) EntryPath(entry *EntryMeta) string {
+	return m.entryFilePath(entry.Category, entry.Key, entry.Filename)
+}
+
 // Remove removes a cached entry by key and category
 func (m *Manager) Remove(cat Category, key string) error {
 	entryDir := m.entryDir(cat, key)
@@ -0,0 +1,281 @@
+package cert
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/acme"
+)
+
+const accountsDir = "accounts"
+
+// DefaultPropagationTimeout is how long to wait for challenge records to
+// become visible in public DNS
+const DefaultPropagationTimeout = 3 * time.Minute
+
+var certDomainPattern = regexp.MustCompile(`^(\*\.)?([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// IssueOptions configures a certificate request
+type IssueOptions struct {
+	Domains            []string // the first domain names the certificate
+	Email              string   // ACME account contact, optional
+	DirectoryURL       string   // ACME directory, Let's Encrypt when empty
+	PropagationTimeout time.Duration
+	Out                io.Writer
+}
+
+// ValidateDomains checks the domains of a certificate request
+func ValidateDomains(domains []string) error {
+	if len(domains) == 0 {
+		return fmt.Errorf("at least one domain is required")
+	}
+	seen := map[string]bool{}
+	for _, domain := range domains {
+		if !certDomainPattern.MatchString(domain) {
+			return fmt.Errorf("invalid domain: %s", domain)
+		}
+		if seen[domain] {
+			return fmt.Errorf("duplicate domain: %s", domain)
+		}
+		seen[domain] = true
+	}
+	return nil
+}
+
+// Issue obtains a certificate for the domains, answering the DNS-01
+// challenges through the DNS provider, and stores it
+func Issue(ctx context.Context, opts IssueOptions, dns DNSProvider) (*Certificate, error) {
+	for i := range opts.Domains {
+		opts.Domains[i] = strings.ToLower(strings.TrimSuffix(opts.Domains[i], "."))
+	}
+	if err := ValidateDomains(opts.Domains); err != nil {
+		return nil, err
+	}
+	if opts.DirectoryURL == "" {
+		opts.DirectoryURL = LetsEncryptURL
+	}
+	if opts.PropagationTimeout == 0 {
+		opts.PropagationTimeout = DefaultPropagationTimeout
+	}
+	out := opts.Out
+	if out == nil {
+		out = io.Discard
+	}
+
+	client, err := accountClient(ctx, opts.DirectoryURL, opts.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(out, "Requesting certificate for %s from %s\n", strings.Join(opts.Domains, ", "), opts.DirectoryURL)
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(opts.Domains...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	type record struct{ fqdn, value string }
+	var presented []record
+	defer func() {
+		// Remove challenge records even when the order failed
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		for _, r := range presented {
+			if err := dns.CleanUp(cleanupCtx, r.fqdn, r.value); err != nil {
+				fmt.Fprintf(out, "Warning: %v\n", err)
+			}
+		}
+	}()
+
+	var pending []*acme.Challenge
+	var pendingAuthz []string
+	for _, authzURL := range order.AuthzURLs {
+		authz, err := client.GetAuthorization(ctx, authzURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get authorization: %w", err)
+		}
+		if authz.Status == acme.StatusValid {
+			continue
+		}
+		var challenge *acme.Challenge
+		for _, c := range authz.Challenges {
+			if c.Type == "dns-01" {
+				challenge = c
+				break
+			}
+		}
+		if challenge == nil {
+			return nil, fmt.Errorf("CA offers no dns-01 challenge for %s", authz.Identifier.Value)
+		}
+		value, err := client.DNS01ChallengeRecord(challenge.Token)
+		if err != nil {
+			return nil, err
+		}
+		fqdn := challengeRecord(authz.Identifier.Value)
+		fmt.Fprintf(out, "Creating TXT record %s via %s\n", fqdn, dns.Name())
+		if err := dns.Present(ctx, fqdn, value); err != nil {
+			return nil, err
+		}
+		presented = append(presented, record{fqdn, value})
+		pending = append(pending, challenge)
+		pendingAuthz = append(pendingAuthz, authz.URI)
+	}
+
+	for _, r := range presented {
+		fmt.Fprintf(out, "Waiting for %s to propagate...\n", r.fqdn)
+		if err := waitForPropagation(ctx, r.fqdn, r.value, opts.PropagationTimeout, 5*time.Second); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, challenge := range pending {
+		if _, err := client.Accept(ctx, challenge); err != nil {
+			return nil, fmt.Errorf("failed to accept challenge: %w", err)
+		}
+		if _, err := client.WaitAuthorization(ctx, pendingAuthz[i]); err != nil {
+			return nil, fmt.Errorf("authorization failed: %w", err)
+		}
+	}
+
+	if _, err := client.WaitOrder(ctx, order.URI); err != nil {
+		return nil, fmt.Errorf("order failed: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: opts.Domains}, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CSR: %w", err)
+	}
+	chain, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to finalize order: %w", err)
+	}
+
+	var chainPEM []byte
+	for _, der := range chain {
+		chainPEM = append(chainPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Certificate{
+		Domains:     opts.Domains,
+		DNSProvider: dns.Name(),
+		Email:       opts.Email,
+		CA:          opts.DirectoryURL,
+		IssuedAt:    time.Now(),
+	}
+	if previous, err := Load(opts.Domains[0]); err == nil {
+		c.Exports = previous.Exports
+	}
+	if err := Store(c, chainPEM, keyPEM); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(out, "Certificate issued, valid until %s\n", c.NotAfter.Format("2006-01-02"))
+	return c, nil
+}
+
+// Renew issues a new certificate with the settings of a stored one and
+// refreshes its exports
+func Renew(ctx context.Context, c *Certificate, out io.Writer) (*Certificate, error) {
+	dns, err := NewDNSProvider(c.DNSProvider)
+	if err != nil {
+		return nil, err
+	}
+	renewed, err := Issue(ctx, IssueOptions{
+		Domains:      append([]string(nil), c.Domains...),
+		Email:        c.Email,
+		DirectoryURL: c.CA,
+		Out:          out,
+	}, dns)
+	if err != nil {
+		return nil, err
+	}
+	for _, export := range renewed.Exports {
+		if _, err := ExportTo(renewed, export.Format, export.Dir); err != nil {
+			return renewed, fmt.Errorf("renewed, but export to %s failed: %w", export.Dir, err)
+		}
+	}
+	return renewed, nil
+}
+
+// accountClient returns an ACME client with the account of the CA,
+// registering a new account key on first use
+func accountClient(ctx context.Context, directoryURL, email string) (*acme.Client, error) {
+	u, err := url.Parse(directoryURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid ACME directory URL: %s", directoryURL)
+	}
+	dir := filepath.Join(Dir(), accountsDir, strings.ReplaceAll(u.Host, ":", "_"))
+	keyPath := filepath.Join(dir, "account.key")
+
+	key, err := loadKey(keyPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		data, err := encodeKey(ecKey)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(keyPath, data, 0600); err != nil {
+			return nil, err
+		}
+		key = ecKey
+	}
+
+	client := &acme.Client{Key: key, DirectoryURL: directoryURL, UserAgent: "portunix"}
+	account := &acme.Account{}
+	if email != "" {
+		account.Contact = []string{"mailto:" + email}
+	}
+	if _, err := client.Register(ctx, account, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return nil, fmt.Errorf("failed to register ACME account: %w", err)
+	}
+	return client, nil
+}
+
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+func loadKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid key file %s", path)
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
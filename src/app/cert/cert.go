@@ -0,0 +1,187 @@
+// Package cert issues and renews TLS certificates from an ACME CA (Let's
+// Encrypt by default) using DNS-01 challenges, so wildcard certificates and
+// hosts that are not reachable from the internet are supported. Issued
+// certificates are kept in ~/.portunix/certs and can be exported in the
+// layouts expected by Caddy, HAProxy and nginx.
+package cert
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EnvDir overrides the certificate store directory
+const EnvDir = "PORTUNIX_CERT_DIR"
+
+// ACME directories
+const (
+	LetsEncryptURL        = "https://acme-v02.api.letsencrypt.org/directory"
+	LetsEncryptStagingURL = "https://acme-staging-v02.api.letsencrypt.org/directory"
+)
+
+// DefaultRenewBefore is how long before expiry certificates are renewed
+const DefaultRenewBefore = 30 * 24 * time.Hour
+
+const (
+	metaFile  = "certificate.json"
+	chainFile = "fullchain.pem"
+	keyFile   = "privkey.pem"
+)
+
+// Certificate is the stored metadata of an issued certificate
+type Certificate struct {
+	Name        string    `json:"name"` // store directory, derived from the first domain
+	Domains     []string  `json:"domains"`
+	DNSProvider string    `json:"dns_provider"`
+	Email       string    `json:"email,omitempty"`
+	CA          string    `json:"ca"` // ACME directory URL
+	Serial      string    `json:"serial"`
+	NotBefore   time.Time `json:"not_before"`
+	NotAfter    time.Time `json:"not_after"`
+	IssuedAt    time.Time `json:"issued_at"`
+	// Exports are re-written after every renewal
+	Exports []Export `json:"exports,omitempty"`
+}
+
+// Export is a location the certificate was exported to
+type Export struct {
+	Format string `json:"format"`
+	Dir    string `json:"dir"`
+}
+
+// Dir returns the certificate store directory
+func Dir() string {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-certs")
+	}
+	return filepath.Join(home, ".portunix", "certs")
+}
+
+// StorageName returns the store directory name of a domain; the wildcard
+// label is replaced because "*" is not valid in Windows file names
+func StorageName(domain string) string {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if strings.HasPrefix(domain, "*.") {
+		return "_wildcard." + domain[2:]
+	}
+	return domain
+}
+
+func certDir(name string) string {
+	return filepath.Join(Dir(), name)
+}
+
+// ChainPath returns the path of the PEM certificate chain
+func (c *Certificate) ChainPath() string {
+	return filepath.Join(certDir(c.Name), chainFile)
+}
+
+// KeyPath returns the path of the PEM private key
+func (c *Certificate) KeyPath() string {
+	return filepath.Join(certDir(c.Name), keyFile)
+}
+
+// DaysLeft returns the number of whole days until the certificate expires
+func (c *Certificate) DaysLeft() int {
+	return int(time.Until(c.NotAfter).Hours() / 24)
+}
+
+// NeedsRenewal reports whether the certificate expires within renewBefore
+func (c *Certificate) NeedsRenewal(renewBefore time.Duration) bool {
+	return time.Until(c.NotAfter) < renewBefore
+}
+
+// Load reads the stored certificate of a domain
+func Load(domain string) (*Certificate, error) {
+	name := StorageName(domain)
+	data, err := os.ReadFile(filepath.Join(certDir(name), metaFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no certificate for %s", domain)
+		}
+		return nil, err
+	}
+	var c Certificate
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid certificate metadata of %s: %w", domain, err)
+	}
+	return &c, nil
+}
+
+// List returns all stored certificates sorted by name
+func List() ([]*Certificate, error) {
+	entries, err := os.ReadDir(Dir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var certs []*Certificate
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == accountsDir {
+			continue
+		}
+		if c, err := Load(entry.Name()); err == nil {
+			certs = append(certs, c)
+		}
+	}
+	sort.Slice(certs, func(i, j int) bool { return certs[i].Name < certs[j].Name })
+	return certs, nil
+}
+
+// Remove deletes a stored certificate and its key
+func Remove(domain string) error {
+	if _, err := Load(domain); err != nil {
+		return err
+	}
+	return os.RemoveAll(certDir(StorageName(domain)))
+}
+
+// Store saves a certificate chain and key, filling the metadata from the
+// leaf certificate
+func Store(c *Certificate, chainPEM, keyPEM []byte) error {
+	block, _ := pem.Decode(chainPEM)
+	if block == nil {
+		return fmt.Errorf("invalid certificate chain")
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("invalid certificate: %w", err)
+	}
+	c.Name = StorageName(c.Domains[0])
+	c.Serial = leaf.SerialNumber.Text(16)
+	c.NotBefore = leaf.NotBefore
+	c.NotAfter = leaf.NotAfter
+
+	dir := certDir(c.Name)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create certificate directory: %w", err)
+	}
+	if err := os.WriteFile(c.KeyPath(), keyPEM, 0600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.ChainPath(), chainPEM, 0644); err != nil {
+		return err
+	}
+	return c.saveMeta()
+}
+
+func (c *Certificate) saveMeta() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(certDir(c.Name), metaFile), data, 0644)
+}
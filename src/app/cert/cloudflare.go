@@ -0,0 +1,81 @@
+package cert
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// cloudflareDNS manages challenge records through the Cloudflare API
+type cloudflareDNS struct {
+	api *dnsAPI
+
+	mu      sync.Mutex
+	records map[string]cloudflareRecord // fqdn + value -> created record
+}
+
+type cloudflareRecord struct {
+	zoneID string
+	id     string
+}
+
+func (p *cloudflareDNS) Name() string {
+	return "cloudflare"
+}
+
+func (p *cloudflareDNS) findZone(ctx context.Context, fqdn string) (string, error) {
+	for _, zone := range zoneCandidates(fqdn) {
+		var resp struct {
+			Result []struct {
+				ID string `json:"id"`
+			} `json:"result"`
+		}
+		if err := p.api.do(ctx, http.MethodGet, "/zones?name="+url.QueryEscape(zone), nil, &resp); err != nil {
+			return "", err
+		}
+		if len(resp.Result) > 0 {
+			return resp.Result[0].ID, nil
+		}
+	}
+	return "", fmt.Errorf("no Cloudflare zone found for %s", fqdn)
+}
+
+func (p *cloudflareDNS) Present(ctx context.Context, fqdn, value string) error {
+	zoneID, err := p.findZone(ctx, fqdn)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{"type": "TXT", "name": fqdn, "content": value, "ttl": 120}
+	var resp struct {
+		Result struct {
+			ID string `json:"id"`
+		} `json:"result"`
+	}
+	if err := p.api.do(ctx, http.MethodPost, "/zones/"+zoneID+"/dns_records", body, &resp); err != nil {
+		return fmt.Errorf("failed to create TXT record %s: %w", fqdn, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.records == nil {
+		p.records = map[string]cloudflareRecord{}
+	}
+	p.records[fqdn+" "+value] = cloudflareRecord{zoneID: zoneID, id: resp.Result.ID}
+	return nil
+}
+
+func (p *cloudflareDNS) CleanUp(ctx context.Context, fqdn, value string) error {
+	p.mu.Lock()
+	record, ok := p.records[fqdn+" "+value]
+	delete(p.records, fqdn+" "+value)
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	if err := p.api.do(ctx, http.MethodDelete, "/zones/"+record.zoneID+"/dns_records/"+record.id, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete TXT record %s: %w", fqdn, err)
+	}
+	return nil
+}
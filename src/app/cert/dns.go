@@ -0,0 +1,216 @@
+package cert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DNSProvider creates and removes the TXT records of DNS-01 challenges
+type DNSProvider interface {
+	Name() string
+	// Present creates a TXT record with value at fqdn. Several values may be
+	// present at the same name, e.g. for example.com and *.example.com.
+	Present(ctx context.Context, fqdn, value string) error
+	// CleanUp removes a record created by Present
+	CleanUp(ctx context.Context, fqdn, value string) error
+}
+
+// dnsProviderInfo describes a supported DNS provider
+type dnsProviderInfo struct {
+	env      []string // credential variables, the first ones required
+	required int
+	baseURL  string
+	create   func(creds map[string]string, baseURL string) DNSProvider
+}
+
+var dnsProviders = map[string]dnsProviderInfo{
+	"cloudflare": {
+		env:      []string{"CLOUDFLARE_API_TOKEN"},
+		required: 1,
+		baseURL:  "https://api.cloudflare.com/client/v4",
+		create: func(creds map[string]string, baseURL string) DNSProvider {
+			return &cloudflareDNS{api: &dnsAPI{baseURL: baseURL, header: "Authorization", token: "Bearer " + creds["CLOUDFLARE_API_TOKEN"]}}
+		},
+	},
+	"hetzner": {
+		env:      []string{"HETZNER_DNS_TOKEN"},
+		required: 1,
+		baseURL:  "https://dns.hetzner.com/api/v1",
+		create: func(creds map[string]string, baseURL string) DNSProvider {
+			return &hetznerDNS{api: &dnsAPI{baseURL: baseURL, header: "Auth-API-Token", token: creds["HETZNER_DNS_TOKEN"]}}
+		},
+	},
+	"route53": {
+		env:      []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN"},
+		required: 2,
+		baseURL:  "https://route53.amazonaws.com",
+		create: func(creds map[string]string, baseURL string) DNSProvider {
+			return newRoute53DNS(creds, baseURL)
+		},
+	},
+}
+
+// DNSProviderNames returns the supported DNS provider identifiers
+func DNSProviderNames() []string {
+	names := make([]string, 0, len(dnsProviders))
+	for name := range dnsProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DNSProviderEnv returns the credential variables of a DNS provider
+func DNSProviderEnv(name string) []string {
+	return dnsProviders[name].env
+}
+
+// NewDNSProvider creates a DNS provider with credentials from the
+// environment
+func NewDNSProvider(name string) (DNSProvider, error) {
+	info, ok := dnsProviders[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported DNS provider: %s (supported: %v)", name, DNSProviderNames())
+	}
+	creds := map[string]string{}
+	for i, env := range info.env {
+		creds[env] = os.Getenv(env)
+		if i < info.required && creds[env] == "" {
+			return nil, fmt.Errorf("%s credentials not set; export %s", name, strings.Join(info.env[:info.required], " and "))
+		}
+	}
+	return info.create(creds, info.baseURL), nil
+}
+
+// NewDNSProviderWithURL creates a DNS provider for a specific API endpoint
+func NewDNSProviderWithURL(name string, creds map[string]string, baseURL string) (DNSProvider, error) {
+	info, ok := dnsProviders[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported DNS provider: %s (supported: %v)", name, DNSProviderNames())
+	}
+	return info.create(creds, baseURL), nil
+}
+
+// zoneCandidates returns the names a challenge record may belong to, from
+// the most to the least specific, e.g. "_acme-challenge.a.example.com" gives
+// a.example.com and example.com
+func zoneCandidates(fqdn string) []string {
+	labels := strings.Split(strings.TrimSuffix(fqdn, "."), ".")
+	var zones []string
+	for i := 1; i < len(labels)-1; i++ {
+		zones = append(zones, strings.Join(labels[i:], "."))
+	}
+	return zones
+}
+
+// relativeName returns fqdn relative to zone, e.g. "_acme-challenge.a"
+func relativeName(fqdn, zone string) string {
+	name := strings.TrimSuffix(strings.TrimSuffix(fqdn, "."), "."+zone)
+	if name == zone {
+		return "@"
+	}
+	return name
+}
+
+// challengeRecord returns the TXT record name of the DNS-01 challenge of a
+// domain; wildcard domains use the record of their base domain
+func challengeRecord(domain string) string {
+	return "_acme-challenge." + strings.TrimPrefix(domain, "*.")
+}
+
+// resolvers are queried directly to see new records without waiting for
+// the cached negative answers of the local resolver to expire
+var resolvers = []string{"1.1.1.1:53", "8.8.8.8:53"}
+
+// lookupTXT queries the TXT records of name at a resolver
+var lookupTXT = func(ctx context.Context, resolver, name string) ([]string, error) {
+	r := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, resolver)
+		},
+	}
+	return r.LookupTXT(ctx, name)
+}
+
+// waitForPropagation waits until all resolvers return the TXT value
+func waitForPropagation(ctx context.Context, fqdn, value string, timeout, interval time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		visible := 0
+		for _, resolver := range resolvers {
+			records, _ := lookupTXT(ctx, resolver, fqdn)
+			for _, record := range records {
+				if record == value {
+					visible++
+					break
+				}
+			}
+		}
+		if visible == len(resolvers) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("TXT record %s not visible after %s", fqdn, timeout)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// dnsAPI performs authenticated JSON requests against a DNS provider API
+type dnsAPI struct {
+	baseURL string
+	header  string
+	token   string
+	http    *http.Client
+}
+
+func (a *dnsAPI) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(a.header, a.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := a.http
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("DNS API error %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out != nil && len(data) > 0 {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
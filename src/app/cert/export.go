@@ -0,0 +1,114 @@
+package cert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ExportFormats are the supported export layouts
+var ExportFormats = []string{"caddy", "haproxy", "nginx"}
+
+// ExportResult lists the written files and the configuration snippet that
+// uses them
+type ExportResult struct {
+	Files   []string `json:"files"`
+	Snippet string   `json:"snippet"`
+}
+
+// ExportTo writes the certificate into dir in the layout of format:
+//   - caddy:   <name>.crt and <name>.key for the "tls" directive
+//   - haproxy: <name>.pem holding the chain followed by the key
+//   - nginx:   <name>.fullchain.pem and <name>.key
+func ExportTo(c *Certificate, format, dir string) (*ExportResult, error) {
+	chain, err := os.ReadFile(c.ChainPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate: %w", err)
+	}
+	key, err := os.ReadFile(c.KeyPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	var snippet string
+	switch format {
+	case "caddy":
+		certPath := filepath.Join(dir, c.Name+".crt")
+		keyPath := filepath.Join(dir, c.Name+".key")
+		files[certPath], files[keyPath] = chain, key
+		snippet = fmt.Sprintf("tls %s %s", certPath, keyPath)
+	case "haproxy":
+		pemPath := filepath.Join(dir, c.Name+".pem")
+		files[pemPath] = append(append([]byte{}, chain...), key...)
+		snippet = fmt.Sprintf("bind :443 ssl crt %s", pemPath)
+	case "nginx":
+		certPath := filepath.Join(dir, c.Name+".fullchain.pem")
+		keyPath := filepath.Join(dir, c.Name+".key")
+		files[certPath], files[keyPath] = chain, key
+		snippet = fmt.Sprintf("ssl_certificate %s;\nssl_certificate_key %s;", certPath, keyPath)
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s (supported: %v)", format, ExportFormats)
+	}
+
+	result := &ExportResult{Snippet: snippet}
+	for path, data := range files {
+		// Every export except a lone certificate contains the private key
+		mode := os.FileMode(0600)
+		if filepath.Ext(path) == ".crt" || filepath.Base(path) == c.Name+".fullchain.pem" {
+			mode = 0644
+		}
+		if err := writeFileAtomic(path, data, mode); err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, path)
+	}
+	sort.Strings(result.Files)
+	return result, nil
+}
+
+// Export writes the certificate like ExportTo and remembers the location
+// so renewals update it
+func (c *Certificate) Export(format, dir string) (*ExportResult, error) {
+	result, err := ExportTo(c, format, dir)
+	if err != nil {
+		return nil, err
+	}
+	abs, _ := filepath.Abs(dir)
+	for _, e := range c.Exports {
+		if e.Format == format && e.Dir == abs {
+			return result, nil
+		}
+	}
+	c.Exports = append(c.Exports, Export{Format: format, Dir: abs})
+	return result, c.saveMeta()
+}
+
+// writeFileAtomic replaces path so that servers reading the file during a
+// renewal never see a partial certificate
+func writeFileAtomic(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".portunix-cert-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,87 @@
+package cert
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// hetznerDNS manages challenge records through the Hetzner DNS API
+type hetznerDNS struct {
+	api *dnsAPI
+
+	mu      sync.Mutex
+	records map[string]string // fqdn + value -> record ID
+}
+
+func (p *hetznerDNS) Name() string {
+	return "hetzner"
+}
+
+func (p *hetznerDNS) findZone(ctx context.Context, fqdn string) (string, string, error) {
+	for _, zone := range zoneCandidates(fqdn) {
+		var resp struct {
+			Zones []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"zones"`
+		}
+		err := p.api.do(ctx, http.MethodGet, "/zones?name="+url.QueryEscape(zone), nil, &resp)
+		if err != nil {
+			// The API answers 404 for unknown zone names
+			continue
+		}
+		for _, z := range resp.Zones {
+			if z.Name == zone {
+				return z.ID, z.Name, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("no Hetzner DNS zone found for %s", fqdn)
+}
+
+func (p *hetznerDNS) Present(ctx context.Context, fqdn, value string) error {
+	zoneID, zone, err := p.findZone(ctx, fqdn)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"zone_id": zoneID,
+		"type":    "TXT",
+		"name":    relativeName(fqdn, zone),
+		"value":   value,
+		"ttl":     60,
+	}
+	var resp struct {
+		Record struct {
+			ID string `json:"id"`
+		} `json:"record"`
+	}
+	if err := p.api.do(ctx, http.MethodPost, "/records", body, &resp); err != nil {
+		return fmt.Errorf("failed to create TXT record %s: %w", fqdn, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.records == nil {
+		p.records = map[string]string{}
+	}
+	p.records[fqdn+" "+value] = resp.Record.ID
+	return nil
+}
+
+func (p *hetznerDNS) CleanUp(ctx context.Context, fqdn, value string) error {
+	p.mu.Lock()
+	id, ok := p.records[fqdn+" "+value]
+	delete(p.records, fqdn+" "+value)
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	if err := p.api.do(ctx, http.MethodDelete, "/records/"+url.PathEscape(id), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete TXT record %s: %w", fqdn, err)
+	}
+	return nil
+}
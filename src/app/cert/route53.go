@@ -0,0 +1,264 @@
+package cert
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const route53Namespace = "https://route53.amazonaws.com/doc/2013-04-01/"
+
+// route53DNS manages challenge records through the AWS Route 53 API. Route
+// 53 keeps all TXT values of a name in one record set, so the values
+// presented per name are tracked and the whole set is written each time.
+type route53DNS struct {
+	baseURL      string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	http         *http.Client
+
+	mu     sync.Mutex
+	values map[string][]string // fqdn -> presented values
+	zones  map[string]string   // fqdn -> hosted zone ID
+}
+
+func newRoute53DNS(creds map[string]string, baseURL string) *route53DNS {
+	return &route53DNS{
+		baseURL:      baseURL,
+		accessKey:    creds["AWS_ACCESS_KEY_ID"],
+		secretKey:    creds["AWS_SECRET_ACCESS_KEY"],
+		sessionToken: creds["AWS_SESSION_TOKEN"],
+		http:         &http.Client{Timeout: 30 * time.Second},
+		values:       map[string][]string{},
+		zones:        map[string]string{},
+	}
+}
+
+func (p *route53DNS) Name() string {
+	return "route53"
+}
+
+type route53ResourceRecord struct {
+	Value string `xml:"Value"`
+}
+
+type route53ResourceRecordSet struct {
+	Name            string                  `xml:"Name"`
+	Type            string                  `xml:"Type"`
+	TTL             int                     `xml:"TTL"`
+	ResourceRecords []route53ResourceRecord `xml:"ResourceRecords>ResourceRecord"`
+}
+
+type route53Change struct {
+	Action            string                   `xml:"Action"`
+	ResourceRecordSet route53ResourceRecordSet `xml:"ResourceRecordSet"`
+}
+
+type route53ChangeRequest struct {
+	XMLName xml.Name        `xml:"ChangeResourceRecordSetsRequest"`
+	Xmlns   string          `xml:"xmlns,attr"`
+	Changes []route53Change `xml:"ChangeBatch>Changes>Change"`
+}
+
+func (p *route53DNS) findZone(ctx context.Context, fqdn string) (string, error) {
+	for _, zone := range zoneCandidates(fqdn) {
+		var resp struct {
+			HostedZones []struct {
+				ID   string `xml:"Id"`
+				Name string `xml:"Name"`
+			} `xml:"HostedZones>HostedZone"`
+		}
+		query := url.Values{"dnsname": {zone}, "maxitems": {"1"}}
+		if err := p.do(ctx, http.MethodGet, "/2013-04-01/hostedzonesbyname", query, nil, &resp); err != nil {
+			return "", err
+		}
+		for _, z := range resp.HostedZones {
+			if strings.TrimSuffix(z.Name, ".") == zone {
+				return strings.TrimPrefix(z.ID, "/hostedzone/"), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no Route 53 hosted zone found for %s", fqdn)
+}
+
+func (p *route53DNS) Present(ctx context.Context, fqdn, value string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	zoneID, ok := p.zones[fqdn]
+	if !ok {
+		var err error
+		if zoneID, err = p.findZone(ctx, fqdn); err != nil {
+			return err
+		}
+		p.zones[fqdn] = zoneID
+	}
+	values := append(p.values[fqdn], value)
+	if err := p.change(ctx, zoneID, "UPSERT", fqdn, values); err != nil {
+		return fmt.Errorf("failed to create TXT record %s: %w", fqdn, err)
+	}
+	p.values[fqdn] = values
+	return nil
+}
+
+func (p *route53DNS) CleanUp(ctx context.Context, fqdn, value string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	zoneID, ok := p.zones[fqdn]
+	if !ok {
+		return nil
+	}
+	var remaining []string
+	for _, v := range p.values[fqdn] {
+		if v != value {
+			remaining = append(remaining, v)
+		}
+	}
+	var err error
+	if len(remaining) > 0 {
+		err = p.change(ctx, zoneID, "UPSERT", fqdn, remaining)
+	} else {
+		// DELETE must match the current record set exactly
+		err = p.change(ctx, zoneID, "DELETE", fqdn, p.values[fqdn])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete TXT record %s: %w", fqdn, err)
+	}
+	p.values[fqdn] = remaining
+	return nil
+}
+
+func (p *route53DNS) change(ctx context.Context, zoneID, action, fqdn string, values []string) error {
+	records := make([]route53ResourceRecord, len(values))
+	for i, v := range values {
+		records[i] = route53ResourceRecord{Value: `"` + v + `"`}
+	}
+	req := route53ChangeRequest{
+		Xmlns: route53Namespace,
+		Changes: []route53Change{{
+			Action: action,
+			ResourceRecordSet: route53ResourceRecordSet{
+				Name:            strings.TrimSuffix(fqdn, ".") + ".",
+				Type:            "TXT",
+				TTL:             60,
+				ResourceRecords: records,
+			},
+		}},
+	}
+	body, err := xml.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return p.do(ctx, http.MethodPost, "/2013-04-01/hostedzone/"+zoneID+"/rrset", nil, append([]byte(xml.Header), body...), nil)
+}
+
+func (p *route53DNS) do(ctx context.Context, method, path string, query url.Values, body []byte, out interface{}) error {
+	u := p.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+	signAWSv4(req, body, p.accessKey, p.secretKey, p.sessionToken, "us-east-1", "route53", time.Now().UTC())
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Code    string `xml:"Error>Code"`
+			Message string `xml:"Error>Message"`
+		}
+		if xml.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("Route 53 error %d %s: %s", resp.StatusCode, apiErr.Code, apiErr.Message)
+		}
+		return fmt.Errorf("Route 53 error %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out != nil {
+		return xml.Unmarshal(data, out)
+	}
+	return nil
+}
+
+// signAWSv4 adds an AWS Signature Version 4 authorization to the request
+func signAWSv4(req *http.Request, body []byte, accessKey, secretKey, sessionToken, region, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host, "x-amz-date": amzDate}
+	if sessionToken != "" {
+		headers["x-amz-security-token"] = sessionToken
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+	canonicalQuery := strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20")
+	canonicalRequest := strings.Join([]string{
+		req.Method, canonicalURI, canonicalQuery, canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
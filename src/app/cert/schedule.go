@@ -0,0 +1,197 @@
+package cert
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"portunix.ai/app/launchd"
+)
+
+const (
+	scheduleName   = "portunix-cert-renew"
+	windowsTask    = "PortunixCertRenew"
+	launchdLabel   = "ai.portunix.cert-renew"
+	crontabMarker  = "# " + scheduleName
+	renewArguments = "cert renew --all"
+)
+
+// InstallSchedule registers a daily "portunix cert renew --all" run with
+// the platform scheduler: a systemd user timer on Linux (crontab when
+// systemd is not available), a launchd agent on macOS and a scheduled
+// task on Windows. It returns a description of what was installed.
+func InstallSchedule(executable string) (string, error) {
+	switch {
+	case runtime.GOOS == "windows":
+		command := fmt.Sprintf(`"%s" %s`, executable, renewArguments)
+		out, err := exec.Command("schtasks", "/Create", "/F", "/SC", "DAILY", "/ST", "03:17",
+			"/TN", windowsTask, "/TR", command).CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("schtasks failed: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return "scheduled task " + windowsTask + " (daily at 03:17)", nil
+	case runtime.GOOS == "darwin":
+		if err := launchd.Install(renewAgent(executable)); err != nil {
+			return "", err
+		}
+		return "launchd agent " + launchd.PlistPath(launchdLabel) + " (daily at 03:17)", nil
+	case runtime.GOOS == "linux" && systemdUserAvailable():
+		return installSystemdTimer(executable)
+	default:
+		return installCrontab(executable)
+	}
+}
+
+// RemoveSchedule removes the renewal schedule installed by InstallSchedule
+func RemoveSchedule() error {
+	if runtime.GOOS == "windows" {
+		out, err := exec.Command("schtasks", "/Delete", "/F", "/TN", windowsTask).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("schtasks failed: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	if runtime.GOOS == "darwin" {
+		if err := launchd.Remove(launchdLabel); err != nil {
+			return err
+		}
+		// Schedules of older versions used crontab
+	}
+	if runtime.GOOS == "linux" {
+		dir := systemdUserDir()
+		if _, err := os.Stat(filepath.Join(dir, scheduleName+".timer")); err == nil {
+			exec.Command("systemctl", "--user", "disable", "--now", scheduleName+".timer").Run()
+			os.Remove(filepath.Join(dir, scheduleName+".timer"))
+			os.Remove(filepath.Join(dir, scheduleName+".service"))
+			exec.Command("systemctl", "--user", "daemon-reload").Run()
+		}
+	}
+	current, err := readCrontab()
+	if err != nil || !strings.Contains(current, crontabMarker) {
+		return nil
+	}
+	return writeCrontab(removeCrontabEntry(current))
+}
+
+// renewAgent returns the launchd agent running the daily renewal
+func renewAgent(executable string) *launchd.Agent {
+	logDir := filepath.Join(filepath.Dir(launchd.AgentsDir()), "Logs", "portunix")
+	os.MkdirAll(logDir, 0755)
+	return &launchd.Agent{
+		Label:             launchdLabel,
+		ProgramArguments:  append([]string{executable}, strings.Fields(renewArguments)...),
+		StartCalendar:     launchd.Daily(3, 17),
+		StandardOutPath:   filepath.Join(logDir, "cert-renew.log"),
+		StandardErrorPath: filepath.Join(logDir, "cert-renew.log"),
+	}
+}
+
+func systemdUserAvailable() bool {
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return false
+	}
+	return exec.Command("systemctl", "--user", "is-system-running").Run() == nil ||
+		exec.Command("systemctl", "--user", "show-environment").Run() == nil
+}
+
+func systemdUserDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "systemd", "user")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "systemd", "user")
+}
+
+func installSystemdTimer(executable string) (string, error) {
+	dir := systemdUserDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	service := fmt.Sprintf(`[Unit]
+Description=Renew portunix ACME certificates
+
+[Service]
+Type=oneshot
+ExecStart=%s %s
+`, executable, renewArguments)
+	timer := `[Unit]
+Description=Daily renewal of portunix ACME certificates
+
+[Timer]
+OnCalendar=daily
+RandomizedDelaySec=1h
+Persistent=true
+
+[Install]
+WantedBy=timers.target
+`
+	if err := os.WriteFile(filepath.Join(dir, scheduleName+".service"), []byte(service), 0644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, scheduleName+".timer"), []byte(timer), 0644); err != nil {
+		return "", err
+	}
+	for _, args := range [][]string{
+		{"--user", "daemon-reload"},
+		{"--user", "enable", "--now", scheduleName + ".timer"},
+	} {
+		if out, err := exec.Command("systemctl", args...).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("systemctl %s failed: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	return "systemd user timer " + scheduleName + ".timer (daily)", nil
+}
+
+func installCrontab(executable string) (string, error) {
+	if _, err := exec.LookPath("crontab"); err != nil {
+		return "", fmt.Errorf("no scheduler found: neither systemd nor crontab is available")
+	}
+	current, err := readCrontab()
+	if err != nil {
+		return "", err
+	}
+	entry := fmt.Sprintf("17 3 * * * %s %s >/dev/null 2>&1 %s", executable, renewArguments, crontabMarker)
+	updated := removeCrontabEntry(current)
+	if updated != "" && !strings.HasSuffix(updated, "\n") {
+		updated += "\n"
+	}
+	if err := writeCrontab(updated + entry + "\n"); err != nil {
+		return "", err
+	}
+	return "crontab entry (daily at 03:17)", nil
+}
+
+func readCrontab() (string, error) {
+	out, err := exec.Command("crontab", "-l").Output()
+	if err != nil {
+		// crontab -l fails when the user has no crontab yet
+		if _, ok := err.(*exec.ExitError); ok {
+			return "", nil
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
+func writeCrontab(content string) error {
+	cmd := exec.Command("crontab", "-")
+	cmd.Stdin = bytes.NewBufferString(content)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("crontab failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func removeCrontabEntry(crontab string) string {
+	var kept []string
+	for _, line := range strings.Split(crontab, "\n") {
+		if !strings.Contains(line, crontabMarker) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
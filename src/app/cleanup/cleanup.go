@@ -0,0 +1,486 @@
+package cleanup
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"portunix.ai/app/cache"
+	"portunix.ai/app/config"
+	"portunix.ai/app/container"
+	"portunix.ai/app/virt"
+)
+
+// Subsystems reported by 'portunix cleanup analyze', in display order
+const (
+	SubsystemCache      = "cache"
+	SubsystemContainers = "containers"
+	SubsystemVenvs      = "venvs"
+	SubsystemVMs        = "vms"
+)
+
+// Subsystems returns all subsystem names
+func Subsystems() []string {
+	return []string{SubsystemCache, SubsystemContainers, SubsystemVenvs, SubsystemVMs}
+}
+
+// runCommand runs a container runtime command; replaced in tests
+var runCommand = func(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// containerRuntimes returns the installed container runtimes; replaced in tests
+var containerRuntimes = func() []string {
+	var runtimes []string
+	for _, rt := range container.EventRuntimes() {
+		runtimes = append(runtimes, string(rt))
+	}
+	return runtimes
+}
+
+// Options selects what Analyze looks at
+type Options struct {
+	OlderThan  time.Duration // Items unused for longer are reclaimable
+	Subsystems []string      // Empty for all
+	Now        time.Time     // Zero for the current time
+}
+
+// Item is one consumer of disk space, e.g. a venv or the images of a runtime
+type Item struct {
+	Name        string     `json:"name"`
+	Path        string     `json:"path,omitempty"`
+	Size        int64      `json:"size"`
+	Reclaimable int64      `json:"reclaimable"`
+	Modified    *time.Time `json:"modified,omitempty"` // Newest change of the files
+	Note        string     `json:"note,omitempty"`
+}
+
+// Action reclaims space; run returns the bytes freed
+type Action struct {
+	Description string `json:"description"`
+	Reclaimable int64  `json:"reclaimable"`
+	run         func() (int64, error)
+}
+
+// Usage is the disk space of one subsystem
+type Usage struct {
+	Subsystem   string   `json:"subsystem"`
+	Size        int64    `json:"size"`
+	Reclaimable int64    `json:"reclaimable"`
+	Items       []Item   `json:"items"`
+	Actions     []Action `json:"actions,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// Report is the disk usage of all analyzed subsystems
+type Report struct {
+	OlderThan   string  `json:"older_than"`
+	Size        int64   `json:"size"`
+	Reclaimable int64   `json:"reclaimable"`
+	Usage       []Usage `json:"subsystems"`
+}
+
+// Analyze measures the disk space of the selected subsystems and plans the
+// actions reclaiming what has not been used for opts.OlderThan
+func Analyze(opts Options) (*Report, error) {
+	if opts.OlderThan <= 0 {
+		return nil, fmt.Errorf("the age of reclaimable items must be positive")
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	selected := map[string]bool{}
+	for _, name := range opts.Subsystems {
+		found := false
+		for _, known := range Subsystems() {
+			if strings.EqualFold(name, known) {
+				selected[known] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown subsystem %q (available: %s)", name, strings.Join(Subsystems(), ", "))
+		}
+	}
+
+	scanners := map[string]func(Options) Usage{
+		SubsystemCache:      analyzeCache,
+		SubsystemContainers: analyzeContainers,
+		SubsystemVenvs:      analyzeVenvs,
+		SubsystemVMs:        analyzeVMs,
+	}
+	report := &Report{OlderThan: formatAge(opts.OlderThan)}
+	for _, name := range Subsystems() {
+		if len(selected) > 0 && !selected[name] {
+			continue
+		}
+		usage := scanners[name](opts)
+		usage.Subsystem = name
+		for _, item := range usage.Items {
+			usage.Size += item.Size
+			usage.Reclaimable += item.Reclaimable
+		}
+		report.Size += usage.Size
+		report.Reclaimable += usage.Reclaimable
+		report.Usage = append(report.Usage, usage)
+	}
+	return report, nil
+}
+
+// Run performs the actions of a report; with dryRun it only lists them.
+// It returns the bytes freed and keeps going when an action fails.
+func Run(report *Report, dryRun bool, out io.Writer) (int64, error) {
+	var freed int64
+	var failed []string
+	for _, usage := range report.Usage {
+		for _, action := range usage.Actions {
+			if dryRun {
+				fmt.Fprintf(out, "  would %s (%s)\n", action.Description, cache.FormatSize(action.Reclaimable))
+				continue
+			}
+			n, err := action.run()
+			freed += n
+			if err != nil {
+				fmt.Fprintf(out, "  ❌ %s: %v\n", action.Description, err)
+				failed = append(failed, action.Description)
+				continue
+			}
+			fmt.Fprintf(out, "  ✅ %s, freed %s\n", action.Description, cache.FormatSize(n))
+		}
+	}
+	if len(failed) > 0 {
+		return freed, fmt.Errorf("%d cleanup actions failed", len(failed))
+	}
+	return freed, nil
+}
+
+// analyzeCache reports the install cache per category. Entries created
+// before the cutoff, expired or broken are pruned by 'cache prune'.
+func analyzeCache(opts Options) Usage {
+	var usage Usage
+	mgr := cache.NewManager()
+	info, err := mgr.GetInfo()
+	if err != nil {
+		usage.Error = err.Error()
+		return usage
+	}
+	entries, _ := mgr.ListAllEntries()
+
+	cutoff := opts.Now.Add(-opts.OlderThan)
+	var reclaimable int64
+	for _, cat := range cache.AllCategories() {
+		catInfo, ok := info.Categories[cat]
+		if !ok || catInfo.Size == 0 {
+			continue
+		}
+		item := Item{Name: string(cat), Path: mgr.CategoryDir(cat), Size: catInfo.Size}
+		for _, entry := range entries[cat] {
+			if entry.IsExpired() || entry.CreatedAt.Before(cutoff) {
+				item.Reclaimable += entry.Size
+			}
+		}
+		reclaimable += item.Reclaimable
+		usage.Items = append(usage.Items, item)
+	}
+	if reclaimable > 0 {
+		usage.Actions = append(usage.Actions, Action{
+			Description: fmt.Sprintf("prune install cache entries older than %s", formatAge(opts.OlderThan)),
+			Reclaimable: reclaimable,
+			run: func() (int64, error) {
+				result, err := mgr.Prune(opts.OlderThan)
+				if result == nil {
+					return 0, err
+				}
+				return result.FreedBytes, err
+			},
+		})
+	}
+	return usage
+}
+
+// dfRow is a line of '<runtime> system df'
+type dfRow struct {
+	Type        string
+	Size        int64
+	Reclaimable int64
+}
+
+// parseSystemDF parses 'system df --format "{{json .}}"' of Docker and
+// Podman: one JSON object per line with human-readable sizes
+func parseSystemDF(output []byte) ([]dfRow, error) {
+	var rows []dfRow
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var raw struct {
+			Type        string
+			Size        string
+			Reclaimable string
+		}
+		if err := json.Unmarshal([]byte(line), &raw); err != nil {
+			return nil, fmt.Errorf("unexpected system df output: %w", err)
+		}
+		size, err := parseHumanSize(raw.Size)
+		if err != nil {
+			return nil, err
+		}
+		// Reclaimable reads "1.2GB (50%)"
+		var reclaimable int64
+		if fields := strings.Fields(raw.Reclaimable); len(fields) > 0 {
+			if reclaimable, err = parseHumanSize(fields[0]); err != nil {
+				return nil, err
+			}
+		}
+		rows = append(rows, dfRow{Type: raw.Type, Size: size, Reclaimable: reclaimable})
+	}
+	return rows, scanner.Err()
+}
+
+// parseHumanSize parses sizes such as "1.5GB", "512kB" or "0B" as printed
+// by the container runtimes, which count in powers of 1000
+func parseHumanSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	units := []struct {
+		suffix string
+		factor float64
+	}{{"TB", 1e12}, {"GB", 1e9}, {"MB", 1e6}, {"kB", 1e3}, {"KB", 1e3}, {"B", 1}}
+	for _, unit := range units {
+		if number, ok := strings.CutSuffix(s, unit.suffix); ok {
+			n, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+			if err != nil || n < 0 {
+				break
+			}
+			return int64(n * unit.factor), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid size %q", s)
+}
+
+// pruneCommands are the prune commands of the system df types; volumes hold
+// data and are never pruned
+var pruneCommands = map[string][]string{
+	"Images":      {"image", "prune", "--all", "--force"},
+	"Containers":  {"container", "prune", "--force"},
+	"Build Cache": {"builder", "prune", "--force"},
+}
+
+// analyzeContainers reports the images, containers, volumes and build cache
+// of each installed runtime. Reclaimable is the runtime's estimate; the
+// prune commands remove only what is older than the cutoff.
+func analyzeContainers(opts Options) Usage {
+	var usage Usage
+	runtimes := containerRuntimes()
+	if len(runtimes) == 0 {
+		usage.Error = "neither Docker nor Podman is available"
+		return usage
+	}
+
+	until := fmt.Sprintf("until=%dh", max(int(opts.OlderThan.Hours()), 1))
+	var errs []string
+	for _, rt := range runtimes {
+		output, err := runCommand(rt, "system", "df", "--format", "{{json .}}")
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s system df: %v", rt, err))
+			continue
+		}
+		rows, err := parseSystemDF(output)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", rt, err))
+			continue
+		}
+		for _, row := range rows {
+			item := Item{Name: rt + " " + strings.ToLower(row.Type), Size: row.Size, Reclaimable: row.Reclaimable}
+			prune, ok := pruneCommands[row.Type]
+			if row.Type == "Build Cache" && rt != "docker" {
+				ok = false
+			}
+			if !ok {
+				item.Reclaimable = 0
+				if strings.Contains(row.Type, "Volume") && row.Reclaimable > 0 {
+					item.Note = fmt.Sprintf("%s in unused volumes; volumes hold data and are not removed (see 'portunix container volume prune')", cache.FormatSize(row.Reclaimable))
+				}
+				usage.Items = append(usage.Items, item)
+				continue
+			}
+			usage.Items = append(usage.Items, item)
+			if row.Reclaimable == 0 {
+				continue
+			}
+
+			args := append(append([]string{}, prune...), "--filter", until)
+			usage.Actions = append(usage.Actions, Action{
+				Description: fmt.Sprintf("%s %s", rt, strings.Join(args, " ")),
+				Reclaimable: row.Reclaimable,
+				run: func() (int64, error) {
+					if _, err := runCommand(rt, args...); err != nil {
+						return 0, commandError(err)
+					}
+					output, err := runCommand(rt, "system", "df", "--format", "{{json .}}")
+					if err != nil {
+						return 0, nil
+					}
+					rows, _ := parseSystemDF(output)
+					for _, after := range rows {
+						if after.Type == row.Type && after.Size < row.Size {
+							return row.Size - after.Size, nil
+						}
+					}
+					return 0, nil
+				},
+			})
+		}
+	}
+	usage.Error = strings.Join(errs, "; ")
+	return usage
+}
+
+// commandError adds the stderr of a failed command to its error
+func commandError(err error) error {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
+
+// venvDir returns the directory of the centralized Python venvs
+func venvDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.TempDir()
+	}
+	return config.GetString("python.venv_dir", filepath.Join(home, ".portunix", "python", "venvs"))
+}
+
+// analyzeVenvs reports the centralized Python venvs; a venv without changes
+// for longer than the cutoff is reclaimable, it is recreated with
+// 'portunix python venv create'
+func analyzeVenvs(opts Options) Usage {
+	var usage Usage
+	dir := venvDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			usage.Error = err.Error()
+		}
+		return usage
+	}
+	cutoff := opts.Now.Add(-opts.OlderThan)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		size, modified := dirUsage(path)
+		item := Item{Name: entry.Name(), Path: path, Size: size, Modified: &modified}
+		if modified.Before(cutoff) {
+			item.Reclaimable = size
+			usage.Actions = append(usage.Actions, removeAction("remove venv "+entry.Name(), path, size))
+		}
+		usage.Items = append(usage.Items, item)
+	}
+	return usage
+}
+
+// analyzeVMs reports the cloud images and VM instances of 'portunix virt'.
+// Images no VM is created from are reclaimable; VM disks are only reported,
+// they are removed with 'portunix virt destroy'.
+func analyzeVMs(opts Options) Usage {
+	var usage Usage
+	dataDir := virt.DataDir()
+
+	used := map[string]bool{}
+	instances, _ := os.ReadDir(filepath.Join(dataDir, "instances"))
+	for _, entry := range instances {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dataDir, "instances", entry.Name())
+		size, modified := dirUsage(path)
+		item := Item{Name: "vm " + entry.Name(), Path: path, Size: size, Modified: &modified,
+			Note: fmt.Sprintf("remove with 'portunix virt destroy %s'", entry.Name())}
+		if inst, err := virt.LoadInstance(entry.Name()); err == nil {
+			used[inst.Image] = true
+		}
+		usage.Items = append(usage.Items, item)
+	}
+
+	cutoff := opts.Now.Add(-opts.OlderThan)
+	images, _ := os.ReadDir(filepath.Join(dataDir, "images"))
+	for _, entry := range images {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		path := filepath.Join(dataDir, "images", entry.Name())
+		modified := info.ModTime()
+		item := Item{Name: "image " + name, Path: path, Size: info.Size(), Modified: &modified}
+		switch {
+		case used[name]:
+			// VM disks are overlays on top of the image
+			item.Note = "backing image of existing VMs"
+		case modified.Before(cutoff):
+			item.Reclaimable = info.Size()
+			usage.Actions = append(usage.Actions, removeAction("remove cloud image "+name, path, info.Size()))
+		}
+		usage.Items = append(usage.Items, item)
+	}
+	sort.SliceStable(usage.Items, func(i, j int) bool { return usage.Items[i].Size > usage.Items[j].Size })
+	return usage
+}
+
+// removeAction deletes a file or directory
+func removeAction(description, path string, size int64) Action {
+	return Action{
+		Description: description,
+		Reclaimable: size,
+		run: func() (int64, error) {
+			if err := os.RemoveAll(path); err != nil {
+				return 0, err
+			}
+			return size, nil
+		},
+	}
+}
+
+// dirUsage returns the size of a directory tree and its newest modification
+func dirUsage(dir string) (int64, time.Time) {
+	var size int64
+	var newest time.Time
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+		return nil
+	})
+	return size, newest
+}
+
+// formatAge writes an age in days when it is a whole number of days
+func formatAge(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", int(d.Hours())/24)
+	}
+	return d.String()
+}
@@ -0,0 +1,128 @@
+package cleanup
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSystemDF(t *testing.T) {
+	docker := `{"Active":"2","Reclaimable":"1.2GB (50%)","Size":"2.4GB","TotalCount":"5","Type":"Images"}
+{"Active":"1","Reclaimable":"0B (0%)","Size":"12.5kB","TotalCount":"3","Type":"Containers"}
+{"Active":"0","Reclaimable":"","Size":"0B","TotalCount":"0","Type":"Build Cache"}
+`
+	rows, err := parseSystemDF([]byte(docker))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0].Size != 2400000000 || rows[0].Reclaimable != 1200000000 || rows[1].Size != 12500 || rows[2].Reclaimable != 0 {
+		t.Errorf("unexpected rows %+v", rows)
+	}
+	if _, err := parseSystemDF([]byte(`{"Type":"Images","Size":"lots"}`)); err == nil {
+		t.Error("invalid size accepted")
+	}
+}
+
+func TestAnalyzeContainers(t *testing.T) {
+	oldRuntimes, oldRun := containerRuntimes, runCommand
+	defer func() { containerRuntimes, runCommand = oldRuntimes, oldRun }()
+	containerRuntimes = func() []string { return []string{"podman"} }
+	var commands []string
+	pruned := false
+	runCommand = func(name string, args ...string) ([]byte, error) {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		if args[0] == "image" {
+			pruned = true
+			return nil, nil
+		}
+		images := "2GB"
+		if pruned {
+			images = "500MB"
+		}
+		return []byte(fmt.Sprintf(`{"Type":"Images","Size":"%s","Reclaimable":"1.5GB (75%%)"}
+{"Type":"Containers","Size":"0B","Reclaimable":"0B (0%%)"}
+{"Type":"Local Volumes","Size":"3GB","Reclaimable":"1GB (33%%)"}
+`, images)), nil
+	}
+
+	usage := analyzeContainers(Options{OlderThan: 30 * 24 * time.Hour})
+	if len(usage.Items) != 3 || len(usage.Actions) != 1 {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+	if volumes := usage.Items[2]; volumes.Reclaimable != 0 || !strings.Contains(volumes.Note, "not removed") {
+		t.Errorf("volumes must only be reported: %+v", volumes)
+	}
+	freed, err := usage.Actions[0].run()
+	if err != nil || freed != 1500000000 {
+		t.Errorf("freed %d, %v", freed, err)
+	}
+	if commands[1] != "podman image prune --all --force --filter until=720h" {
+		t.Errorf("unexpected prune command %q", commands[1])
+	}
+}
+
+func TestAnalyzeVenvsAndVMs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("PORTUNIX_PYTHON_VENV_DIR", filepath.Join(home, "venvs"))
+	t.Setenv("PORTUNIX_VIRT_DIR", filepath.Join(home, "virt"))
+	now := time.Now()
+	old := now.Add(-60 * 24 * time.Hour)
+
+	write := func(path string, size int, modified time.Time) {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, make([]byte, size), 0644)
+		os.Chtimes(path, modified, modified)
+		os.Chtimes(filepath.Dir(path), modified, modified)
+	}
+	write(filepath.Join(home, "venvs", "stale", "pyvenv.cfg"), 100, old)
+	write(filepath.Join(home, "venvs", "active", "pyvenv.cfg"), 200, now)
+	write(filepath.Join(home, "virt", "images", "debian-12.qcow2"), 300, old)
+	write(filepath.Join(home, "virt", "images", "rocky-9.qcow2"), 400, old)
+	os.MkdirAll(filepath.Join(home, "virt", "instances", "dev"), 0700)
+	os.WriteFile(filepath.Join(home, "virt", "instances", "dev", "instance.json"), []byte(`{"name":"dev","image":"debian-12"}`), 0600)
+	write(filepath.Join(home, "virt", "instances", "dev", "disk.qcow2"), 500, old)
+
+	report, err := Analyze(Options{OlderThan: 30 * 24 * time.Hour, Subsystems: []string{"venvs", "VMs"}, Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Usage) != 2 || report.OlderThan != "30d" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	venvs, vms := report.Usage[0], report.Usage[1]
+	if venvs.Size != 300 || venvs.Reclaimable != 100 || len(venvs.Actions) != 1 {
+		t.Errorf("unexpected venvs %+v", venvs)
+	}
+	// The image of the VM and its disk and record (34 bytes) are kept
+	if vms.Size != 1234 || vms.Reclaimable != 400 || len(vms.Actions) != 1 || !strings.Contains(vms.Actions[0].Description, "rocky-9") {
+		t.Errorf("unexpected VMs %+v", vms)
+	}
+
+	var out bytes.Buffer
+	if _, err := Run(report, true, &out); err != nil || !strings.Contains(out.String(), "would remove venv stale") {
+		t.Errorf("dry run: %v\n%s", err, out.String())
+	}
+	if _, err := os.Stat(filepath.Join(home, "venvs", "stale")); err != nil {
+		t.Error("dry run removed a venv")
+	}
+	freed, err := Run(report, false, &out)
+	if err != nil || freed != 500 {
+		t.Errorf("freed %d, %v", freed, err)
+	}
+	for path, kept := range map[string]bool{
+		"venvs/stale": false, "venvs/active": true, "virt/images/rocky-9.qcow2": false,
+		"virt/images/debian-12.qcow2": true, "virt/instances/dev": true,
+	} {
+		if _, err := os.Stat(filepath.Join(home, path)); (err == nil) != kept {
+			t.Errorf("%s: kept %v, want %v", path, err == nil, kept)
+		}
+	}
+
+	if _, err := Analyze(Options{OlderThan: time.Hour, Subsystems: []string{"disks"}}); err == nil {
+		t.Error("unknown subsystem accepted")
+	}
+}
@@ -3,7 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -24,31 +25,31 @@ func DefaultConfig() *Config {
 	}
 }
 
-// LoadConfig loads configuration from file with priority order
+// LoadConfig loads configuration merged from all scopes. Priority, highest
+// first: PORTUNIX_* environment variables, project (./portunix-config.yaml),
+// user (~/.portunix/config.yaml), system (/etc/portunix/config.yaml), defaults.
 func LoadConfig() (*Config, error) {
 	config := DefaultConfig()
 
-	// Try configuration files in priority order
-	configPaths := []string{
-		"./portunix-config.yaml",                                           // Project directory
-		filepath.Join(getUserHome(), ".portunix", "config.yaml"),           // User directory
-		filepath.Join(getUserHome(), ".config", "portunix", "config.yaml"), // Linux standard
-	}
-
-	var loadedFrom string
-	for _, path := range configPaths {
-		if fileExists(path) {
-			if err := loadConfigFromFile(config, path); err != nil {
-				return nil, fmt.Errorf("failed to load config from %s: %w", path, err)
-			}
-			loadedFrom = path
-			break
+	for _, scope := range Scopes {
+		path := ScopePath(scope)
+		if !fileExists(path) {
+			continue
+		}
+		if err := loadConfigFromFile(config, path); err != nil {
+			return nil, fmt.Errorf("failed to load config from %s: %w", path, err)
 		}
 	}
 
-	// If no config file found, use defaults
-	if loadedFrom == "" {
-		// Use built-in defaults
+	// Environment variables override all files
+	if value, ok := os.LookupEnv(EnvVarName("container_runtime")); ok {
+		config.ContainerRuntime = value
+	}
+	if value, ok := os.LookupEnv(EnvVarName("verbose")); ok {
+		config.Verbose, _ = strconv.ParseBool(value)
+	}
+	if value, ok := os.LookupEnv(EnvVarName("auto_update")); ok {
+		config.AutoUpdate, _ = strconv.ParseBool(value)
 	}
 
 	return config, nil
@@ -64,69 +65,31 @@ func loadConfigFromFile(config *Config, path string) error {
 	return yaml.Unmarshal(data, config)
 }
 
-// SaveConfig saves configuration to the user config file
+// SaveConfig saves configuration to the user config file, keeping other
+// (helper specific) keys already stored there
 func SaveConfig(config *Config) error {
-	userConfigDir := filepath.Join(getUserHome(), ".portunix")
-	if err := os.MkdirAll(userConfigDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
-	}
-
-	configPath := filepath.Join(userConfigDir, "config.yaml")
-	data, err := yaml.Marshal(config)
-	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
-	}
-
-	return os.WriteFile(configPath, data, 0644)
+	return updateScope(ScopeUser, func(raw map[string]interface{}) {
+		raw["container_runtime"] = config.ContainerRuntime
+		raw["verbose"] = config.Verbose
+		raw["auto_update"] = config.AutoUpdate
+	})
 }
 
 // GetConfigValue returns a specific configuration value
 func GetConfigValue(key string) (string, error) {
-	config, err := LoadConfig()
-	if err != nil {
-		return "", err
-	}
-
-	switch key {
-	case "container_runtime":
-		return config.ContainerRuntime, nil
-	case "verbose":
-		if config.Verbose {
-			return "true", nil
-		}
-		return "false", nil
-	case "auto_update":
-		if config.AutoUpdate {
-			return "true", nil
+	value, _, ok := Lookup(key)
+	if !ok {
+		if _, known := lookupKey(key); !known && !strings.Contains(key, ".") {
+			return "", fmt.Errorf("unknown configuration key: %s", key)
 		}
-		return "false", nil
-	default:
-		return "", fmt.Errorf("unknown configuration key: %s", key)
+		return "", nil
 	}
+	return value, nil
 }
 
-// SetConfigValue sets a specific configuration value
+// SetConfigValue sets a specific configuration value in the user scope
 func SetConfigValue(key, value string) error {
-	config, err := LoadConfig()
-	if err != nil {
-		return err
-	}
-
-	switch key {
-	case "container_runtime":
-		if value != "docker" && value != "podman" {
-			return fmt.Errorf("invalid container runtime: %s (must be 'docker' or 'podman')", value)
-		}
-		config.ContainerRuntime = value
-	case "verbose":
-		config.Verbose = value == "true"
-	case "auto_update":
-		config.AutoUpdate = value == "true"
-	default:
-		return fmt.Errorf("unknown configuration key: %s", key)
-	}
-
-	return SaveConfig(config)
+	return Set(ScopeUser, key, value)
 }
 
 // fileExists checks if a file exists
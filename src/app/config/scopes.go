@@ -0,0 +1,516 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scope identifies a configuration layer
+type Scope string
+
+// Configuration scopes, from lowest to highest priority
+const (
+	ScopeSystem  Scope = "system"
+	ScopeUser    Scope = "user"
+	ScopeProject Scope = "project"
+)
+
+// EnvPrefix is the prefix of environment variables overriding configuration
+// keys, e.g. PORTUNIX_CONTAINER_RUNTIME or PORTUNIX_PYTHON_VENV_DIR
+const EnvPrefix = "PORTUNIX_"
+
+// ProjectConfigFile is the name of the project scope configuration file
+const ProjectConfigFile = "portunix-config.yaml"
+
+// Scopes lists all scopes from lowest to highest priority
+var Scopes = []Scope{ScopeSystem, ScopeUser, ScopeProject}
+
+// KeyInfo documents a known configuration key
+type KeyInfo struct {
+	Key         string
+	Default     string
+	Description string
+	Validate    func(value string) error
+	// UserOnly keys are read from the user and system scopes only, so a
+	// cloned repository cannot set them in its portunix-config.yaml
+	UserOnly bool
+}
+
+// knownKeys lists configuration keys consumed by portunix and its helpers.
+// Helpers may use additional namespaced keys (e.g. "pft.area").
+var knownKeys = []KeyInfo{
+	{Key: "container_runtime", Default: "podman", Description: "Container runtime to use (docker, podman)", Validate: validateContainerRuntime},
+	{Key: "verbose", Default: "false", Description: "Enable verbose output", Validate: validateBool},
+	{Key: "auto_update", Default: "true", Description: "Enable automatic updates", Validate: validateBool},
+	{Key: "update.channel", Default: "stable", Description: "Release channel of 'portunix self-update' (stable, beta)", Validate: validateUpdateChannel},
+	{Key: "python.venv_dir", Description: "Directory for centralized Python virtual environments (default: ~/.portunix/python/venvs)"},
+	{Key: "go.root_dir", Description: "Directory for managed Go toolchains (default: ~/.portunix/go)"},
+	{Key: "docs.image", Description: "Hugo container image of 'portunix docs' (default: hugomods/hugo:exts)"},
+	{Key: "java.root_dir", Description: "Directory for managed JDKs (default: ~/.portunix/java)"},
+	{Key: "ai.root_dir", Description: "Directory for local AI backends, models and server state (default: ~/.portunix/ai)"},
+	{Key: "cache.dir", Description: "Download and build cache directory (default: ~/.cache/portunix)"},
+	{Key: "cache.disabled", Default: "false", Description: "Disable the download and build cache", Validate: validateBool},
+	{Key: "init.roles", Description: "Roles chosen in 'portunix init' (dev, devops, product)"},
+	{Key: "logging.file", Default: "true", Description: "Capture logs in ~/.portunix/logs", Validate: validateBool},
+	{Key: "logging.retention_days", Default: "14", Description: "Days to keep log files before 'portunix logs clean' removes them"},
+	{Key: "metrics.enabled", Default: "false", Description: "Record local usage metrics in ~/.portunix/metrics (opt-in)", Validate: validateBool},
+	{Key: "metrics.endpoint", Description: "URL that 'portunix metrics export' posts recorded metrics to"},
+	{Key: "proxy.http", Description: "Proxy for HTTP requests, e.g. http://proxy.corp:3128", Validate: validateProxyURL, UserOnly: true},
+	{Key: "proxy.https", Description: "Proxy for HTTPS requests (default: proxy.http)", Validate: validateProxyURL, UserOnly: true},
+	{Key: "proxy.no_proxy", Description: "Comma separated hosts and domains reached directly, e.g. localhost,.corp.example.com", UserOnly: true},
+	{Key: "proxy.pac", Description: "URL or path of a proxy auto-config file, used when proxy.http/https are not set", UserOnly: true},
+	{Key: "proxy.ca_bundle", Description: "PEM file with the CA certificates of a TLS-intercepting proxy ('portunix proxy import-ca')", UserOnly: true},
+	{Key: "network.profile", Description: "Network profile selecting the download and registry mirrors of mirrors.yaml (e.g. office, home, airgapped)"},
+	{Key: "run.env_profile", Description: "Env profiles of 'portunix run' when --env-profile is not given (comma separated)"},
+	{Key: "repo.template", Default: "default", Description: "Template of 'portunix repo init': a name in repo-templates/, a file or a URL"},
+	{Key: "secret.backend", Default: "auto", Description: "Store of new 'portunix secret' values (auto, keychain, file)", Validate: validateSecretBackend},
+	{Key: "vuln.interval", Default: "24h", Description: "Time between scans of 'portunix vuln watch' (e.g. 6h, 24h)", Validate: validateDuration},
+	{Key: "vuln.notify", Default: "desktop", Description: "Notifications of new vulnerabilities: desktop, email, both comma separated, or none", Validate: validateVulnNotify},
+	{Key: "vuln.email_to", Description: "Comma separated recipients of vulnerability emails"},
+	{Key: "vuln.pft_config", Description: "pft configuration file whose SMTP server sends vulnerability emails (default: ./.pft-config.json)"},
+	{Key: "vuln.images", Default: "true", Description: "Scan local container images in 'portunix vuln' (needs syft)", Validate: validateBool},
+	{Key: "vuln.min_severity", Description: "Lowest severity that triggers a notification (low, medium, high, critical)", Validate: validateSeverity},
+	{Key: "pft.language", Description: "Language of content generated by pft (en, cs, de) where .pft-config.json sets none"},
+	{Key: "pft.ai.provider", Description: "Models of 'pft analyze': local, ollama or openai"},
+	{Key: "pft.ai.url", Description: "API endpoint of the pft analysis models", UserOnly: true},
+	{Key: "pft.ai.model", Description: "Embedding model of 'pft analyze'"},
+	{Key: "pft.ai.chat_model", Description: "Model writing the cluster summaries of 'pft analyze'"},
+	{Key: "pft.ai.api_key", Description: "API key of the pft analysis models, usually a secret reference", UserOnly: true},
+	{Key: "pft.smtp.host", Description: "SMTP server of pft notifications", UserOnly: true},
+	{Key: "pft.smtp.port", Description: "Port of the pft SMTP server", UserOnly: true},
+	{Key: "pft.smtp.username", Description: "User of the pft SMTP server", UserOnly: true},
+	{Key: "pft.smtp.password", Description: "Password of the pft SMTP server, usually a secret reference", UserOnly: true},
+	{Key: "pft.smtp.from", Description: "Sender address of pft notifications", UserOnly: true},
+}
+
+// Entry is a resolved configuration value with the layer it came from
+type Entry struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"` // scope name, environment variable, or "default"
+}
+
+// KnownKeys returns the documented configuration keys
+func KnownKeys() []KeyInfo {
+	return knownKeys
+}
+
+// lookupKey returns the documentation of a known key
+func lookupKey(key string) (KeyInfo, bool) {
+	for _, k := range knownKeys {
+		if k.Key == key {
+			return k, true
+		}
+	}
+	return KeyInfo{}, false
+}
+
+// ValidateKey checks a key and value before they are stored. Unknown keys are
+// accepted when namespaced (containing a dot) so helpers can define their own.
+func ValidateKey(key, value string) error {
+	if info, ok := lookupKey(key); ok {
+		if info.Validate != nil {
+			return info.Validate(value)
+		}
+		return nil
+	}
+	if !strings.Contains(key, ".") {
+		return fmt.Errorf("unknown configuration key: %s", key)
+	}
+	return nil
+}
+
+func validateContainerRuntime(value string) error {
+	if value != "docker" && value != "podman" {
+		return fmt.Errorf("invalid container runtime: %s (must be 'docker' or 'podman')", value)
+	}
+	return nil
+}
+
+func validateUpdateChannel(value string) error {
+	if value != "stable" && value != "beta" {
+		return fmt.Errorf("invalid update channel: %s (must be 'stable' or 'beta')", value)
+	}
+	return nil
+}
+
+func validateSecretBackend(value string) error {
+	if value != "auto" && value != "keychain" && value != "file" {
+		return fmt.Errorf("invalid secret backend: %s (must be 'auto', 'keychain' or 'file')", value)
+	}
+	return nil
+}
+
+func validateDuration(value string) error {
+	if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+		return fmt.Errorf("invalid duration: %s (expected e.g. 30m, 6h)", value)
+	}
+	return nil
+}
+
+func validateVulnNotify(value string) error {
+	for _, channel := range strings.Split(value, ",") {
+		switch strings.TrimSpace(channel) {
+		case "desktop", "email", "none":
+		default:
+			return fmt.Errorf("invalid notification channel: %s (must be 'desktop', 'email' or 'none')", channel)
+		}
+	}
+	return nil
+}
+
+func validateSeverity(value string) error {
+	switch value {
+	case "", "low", "medium", "high", "critical":
+		return nil
+	}
+	return fmt.Errorf("invalid severity: %s (must be 'low', 'medium', 'high' or 'critical')", value)
+}
+
+func validateProxyURL(value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" || strings.ContainsAny(value, " \"'`$") {
+		return fmt.Errorf("invalid proxy URL: %s (expected e.g. http://proxy.example.com:3128)", value)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return nil
+	}
+	return fmt.Errorf("unsupported proxy scheme: %s (use http, https or socks5)", u.Scheme)
+}
+
+func validateBool(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("invalid boolean value: %s (must be 'true' or 'false')", value)
+	}
+	return nil
+}
+
+// ScopePath returns the configuration file of a scope
+func ScopePath(scope Scope) string {
+	switch scope {
+	case ScopeSystem:
+		if runtime.GOOS == "windows" {
+			programData := os.Getenv("ProgramData")
+			if programData == "" {
+				programData = `C:\ProgramData`
+			}
+			return filepath.Join(programData, "Portunix", "config.yaml")
+		}
+		return filepath.Join("/etc", "portunix", "config.yaml")
+	case ScopeUser:
+		path := filepath.Join(getUserHome(), ".portunix", "config.yaml")
+		legacy := filepath.Join(getUserHome(), ".config", "portunix", "config.yaml")
+		if !fileExists(path) && fileExists(legacy) {
+			return legacy
+		}
+		return path
+	case ScopeProject:
+		if path := findProjectConfig(); path != "" {
+			return path
+		}
+		return ProjectConfigFile
+	}
+	return ""
+}
+
+// findProjectConfig searches the current and parent directories for the
+// project configuration file
+func findProjectConfig() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ProjectConfigFile)
+		if fileExists(path) {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// EnvVarName returns the environment variable overriding a key
+func EnvVarName(key string) string {
+	r := strings.NewReplacer(".", "_", "-", "_")
+	return EnvPrefix + strings.ToUpper(r.Replace(key))
+}
+
+// loadScope reads a scope file into a flat key/value map
+func loadScope(scope Scope) (map[string]string, error) {
+	values := make(map[string]string)
+	path := ScopePath(scope)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	flatten("", raw, values)
+	return values, nil
+}
+
+// flatten converts nested maps into dotted keys
+func flatten(prefix string, in map[string]interface{}, out map[string]string) {
+	for k, v := range in {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch val := v.(type) {
+		case map[string]interface{}:
+			flatten(key, val, out)
+		case nil:
+			out[key] = ""
+		default:
+			out[key] = fmt.Sprint(val)
+		}
+	}
+}
+
+// Lookup resolves a key through environment, project, user and system scopes.
+// The project scope is skipped for UserOnly keys. Returns the value, its
+// source and whether it was set anywhere.
+func Lookup(key string) (string, string, bool) {
+	if value, ok := os.LookupEnv(EnvVarName(key)); ok {
+		return value, EnvVarName(key), true
+	}
+
+	info, known := lookupKey(key)
+	for i := len(Scopes) - 1; i >= 0; i-- {
+		if Scopes[i] == ScopeProject && known && info.UserOnly {
+			continue
+		}
+		values, err := loadScope(Scopes[i])
+		if err != nil {
+			continue
+		}
+		if value, ok := values[key]; ok {
+			return value, string(Scopes[i]), true
+		}
+	}
+
+	if known && info.Default != "" {
+		return info.Default, "default", true
+	}
+	return "", "", false
+}
+
+// IgnoredProjectKeys returns the UserOnly keys set in the project
+// configuration, which Lookup ignores
+func IgnoredProjectKeys() []string {
+	values, err := loadScope(ScopeProject)
+	if err != nil {
+		return nil
+	}
+	var keys []string
+	for _, k := range knownKeys {
+		if _, ok := values[k.Key]; ok && k.UserOnly {
+			keys = append(keys, k.Key)
+		}
+	}
+	return keys
+}
+
+// GetString returns a configuration value or def when unset
+func GetString(key, def string) string {
+	if value, _, ok := Lookup(key); ok && value != "" {
+		return value
+	}
+	return def
+}
+
+// GetBool returns a boolean configuration value or def when unset or invalid
+func GetBool(key string, def bool) bool {
+	value, _, ok := Lookup(key)
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+// GetInt returns an integer configuration value or def when unset or invalid
+func GetInt(key string, def int) int {
+	value, _, ok := Lookup(key)
+	if !ok {
+		return def
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return def
+	}
+	return i
+}
+
+// List returns all configured keys and known defaults with their sources
+func List() ([]Entry, error) {
+	resolved := make(map[string]Entry)
+
+	for _, k := range knownKeys {
+		if k.Default != "" {
+			resolved[k.Key] = Entry{Key: k.Key, Value: k.Default, Source: "default"}
+		}
+	}
+
+	for _, scope := range Scopes {
+		values, err := loadScope(scope)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range values {
+			if info, ok := lookupKey(k); ok && info.UserOnly && scope == ScopeProject {
+				continue
+			}
+			resolved[k] = Entry{Key: k, Value: v, Source: string(scope)}
+		}
+	}
+
+	// Environment overrides apply to known and already configured keys
+	keys := make([]string, 0, len(resolved)+len(knownKeys))
+	for k := range resolved {
+		keys = append(keys, k)
+	}
+	for _, k := range knownKeys {
+		keys = append(keys, k.Key)
+	}
+	for _, key := range keys {
+		if value, ok := os.LookupEnv(EnvVarName(key)); ok {
+			resolved[key] = Entry{Key: key, Value: value, Source: EnvVarName(key)}
+		}
+	}
+
+	entries := make([]Entry, 0, len(resolved))
+	for _, e := range resolved {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+// Set stores a value in the given scope
+func Set(scope Scope, key, value string) error {
+	if err := ValidateKey(key, value); err != nil {
+		return err
+	}
+	if info, ok := lookupKey(key); ok && info.UserOnly && scope == ScopeProject {
+		return fmt.Errorf("%s can only be set in the user or system scope", key)
+	}
+	return updateScope(scope, func(raw map[string]interface{}) {
+		setNested(raw, strings.Split(key, "."), parseScalar(value))
+	})
+}
+
+// Unset removes a value from the given scope
+func Unset(scope Scope, key string) error {
+	return updateScope(scope, func(raw map[string]interface{}) {
+		unsetNested(raw, strings.Split(key, "."))
+	})
+}
+
+// updateScope loads a scope file, applies fn and writes it back
+func updateScope(scope Scope, fn func(map[string]interface{})) error {
+	path := ScopePath(scope)
+	if scope == ScopeUser {
+		// Writes always go to the primary user file, not the legacy location
+		path = filepath.Join(getUserHome(), ".portunix", "config.yaml")
+		if !fileExists(path) {
+			if legacy := ScopePath(ScopeUser); legacy != path {
+				if data, err := os.ReadFile(legacy); err == nil {
+					os.MkdirAll(filepath.Dir(path), 0755)
+					os.WriteFile(path, data, 0644)
+				}
+			}
+		}
+	}
+
+	raw := make(map[string]interface{})
+	if data, err := os.ReadFile(path); err == nil {
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if raw == nil {
+			raw = make(map[string]interface{})
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	fn(raw)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// setNested sets a dotted key path in nested maps
+func setNested(m map[string]interface{}, path []string, value interface{}) {
+	if len(path) == 1 {
+		m[path[0]] = value
+		return
+	}
+	child, ok := m[path[0]].(map[string]interface{})
+	if !ok {
+		child = make(map[string]interface{})
+		m[path[0]] = child
+	}
+	setNested(child, path[1:], value)
+}
+
+// unsetNested removes a dotted key path, pruning empty parents
+func unsetNested(m map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(m, path[0])
+		return
+	}
+	child, ok := m[path[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	unsetNested(child, path[1:])
+	if len(child) == 0 {
+		delete(m, path[0])
+	}
+}
+
+// parseScalar stores booleans and numbers with their YAML type
+func parseScalar(value string) interface{} {
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	if i, err := strconv.Atoi(value); err == nil {
+		return i
+	}
+	return value
+}
@@ -0,0 +1,262 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventActions are the container lifecycle events reported by StreamEvents
+var EventActions = []string{"start", "stop", "die", "oom"}
+
+// Event is a container lifecycle event of Docker or Podman
+type Event struct {
+	Time     time.Time `json:"time"`
+	Runtime  string    `json:"runtime"`
+	Action   string    `json:"action"`
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Image    string    `json:"image,omitempty"`
+	ExitCode *int      `json:"exit_code,omitempty"`
+}
+
+// EventFilter selects events. Names are glob patterns (e.g. fider-*) and
+// Actions a subset of EventActions; empty lists match everything. Other
+// filters (label=..., image=...) are passed to the runtime.
+type EventFilter struct {
+	Names   []string
+	Actions []string
+	Runtime []string
+}
+
+// ParseEventFilters parses "key=value" filters of the events command
+func ParseEventFilters(filters []string) (EventFilter, error) {
+	var f EventFilter
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok || value == "" {
+			return f, fmt.Errorf("invalid filter '%s' (expected key=value)", filter)
+		}
+		switch key {
+		case "name", "container":
+			if _, err := path.Match(value, ""); err != nil {
+				return f, fmt.Errorf("invalid name pattern '%s': %w", value, err)
+			}
+			f.Names = append(f.Names, value)
+		case "event", "action":
+			if !isEventAction(value) {
+				return f, fmt.Errorf("unsupported event '%s' (supported: %s)", value, strings.Join(EventActions, ", "))
+			}
+			f.Actions = append(f.Actions, value)
+		case "type":
+			if value != "container" {
+				return f, fmt.Errorf("only container events are supported")
+			}
+		default:
+			f.Runtime = append(f.Runtime, filter)
+		}
+	}
+	return f, nil
+}
+
+func isEventAction(action string) bool {
+	for _, a := range EventActions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether the filter selects the event
+func (f EventFilter) Match(e *Event) bool {
+	if len(f.Actions) > 0 {
+		found := false
+		for _, action := range f.Actions {
+			found = found || action == e.Action
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Names) == 0 {
+		return true
+	}
+	for _, pattern := range f.Names {
+		if ok, _ := path.Match(pattern, e.Name); ok || strings.HasPrefix(e.ID, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventsArgs returns the arguments of "<runtime> events" streaming the
+// lifecycle events as JSON lines
+func eventsArgs(runtime RuntimeType, f EventFilter, since string) []string {
+	args := []string{"events", "--filter", "type=container"}
+	for _, action := range EventActions {
+		if runtime == RuntimePodman {
+			// Podman has no oom event; an OOM kill is reported as die
+			if action == "oom" {
+				continue
+			}
+			if action == "die" {
+				action = "died"
+			}
+		}
+		args = append(args, "--filter", "event="+action)
+	}
+	for _, filter := range f.Runtime {
+		args = append(args, "--filter", filter)
+	}
+	if since != "" {
+		args = append(args, "--since", since)
+	}
+	if runtime == RuntimePodman {
+		return append(args, "--format", "json")
+	}
+	return append(args, "--format", "{{json .}}")
+}
+
+// dockerEvent is a line of "docker events --format '{{json .}}'"
+type dockerEvent struct {
+	Type   string `json:"Type"`
+	Action string `json:"Action"`
+	Actor  struct {
+		ID         string            `json:"ID"`
+		Attributes map[string]string `json:"Attributes"`
+	} `json:"Actor"`
+	Time     int64 `json:"time"`
+	TimeNano int64 `json:"timeNano"`
+}
+
+// podmanEvent is a line of "podman events --format json". Podman 4 writes
+// Time as a timestamp string, Podman 5 as Unix seconds with timeNano.
+type podmanEvent struct {
+	ID       string          `json:"ID"`
+	Name     string          `json:"Name"`
+	Image    string          `json:"Image"`
+	Status   string          `json:"Status"`
+	Type     string          `json:"Type"`
+	Time     json.RawMessage `json:"Time"`
+	TimeNano int64           `json:"timeNano"`
+	ExitCode *int            `json:"ContainerExitCode"`
+}
+
+// ParseEvent parses a JSON line of "docker events" or "podman events". It
+// returns nil for events other than EventActions.
+func ParseEvent(runtime RuntimeType, line []byte) (*Event, error) {
+	e := &Event{Runtime: string(runtime)}
+	if runtime == RuntimePodman {
+		var p podmanEvent
+		if err := json.Unmarshal(line, &p); err != nil {
+			return nil, fmt.Errorf("unexpected podman event: %w", err)
+		}
+		if p.Type != "" && p.Type != "container" {
+			return nil, nil
+		}
+		e.Action, e.ID, e.Name, e.Image = p.Status, p.ID, p.Name, p.Image
+		if e.Action == "died" {
+			e.Action = "die"
+		}
+		if e.Action == "die" {
+			e.ExitCode = p.ExitCode
+		}
+		if p.TimeNano > 0 {
+			e.Time = time.Unix(0, p.TimeNano)
+		} else if seconds, err := strconv.ParseInt(string(p.Time), 10, 64); err == nil {
+			e.Time = time.Unix(seconds, 0)
+		} else {
+			var stamp string
+			if json.Unmarshal(p.Time, &stamp) == nil {
+				e.Time, _ = time.Parse(time.RFC3339Nano, stamp)
+			}
+		}
+	} else {
+		var d dockerEvent
+		if err := json.Unmarshal(line, &d); err != nil {
+			return nil, fmt.Errorf("unexpected docker event: %w", err)
+		}
+		if d.Type != "container" {
+			return nil, nil
+		}
+		e.Action, e.ID = d.Action, d.Actor.ID
+		e.Name, e.Image = d.Actor.Attributes["name"], d.Actor.Attributes["image"]
+		if code, err := strconv.Atoi(d.Actor.Attributes["exitCode"]); err == nil && e.Action == "die" {
+			e.ExitCode = &code
+		}
+		if d.TimeNano > 0 {
+			e.Time = time.Unix(0, d.TimeNano)
+		} else {
+			e.Time = time.Unix(d.Time, 0)
+		}
+	}
+
+	if !isEventAction(e.Action) {
+		return nil, nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.Time = e.Time.UTC()
+	return e, nil
+}
+
+// StreamEvents runs "<runtime> events" and calls fn for each lifecycle event
+// selected by the filter until ctx is cancelled or the runtime exits
+func StreamEvents(ctx context.Context, runtime RuntimeType, f EventFilter, since string, fn func(*Event)) error {
+	cmd := exec.CommandContext(ctx, string(runtime), eventsArgs(runtime, f, since)...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s events: %w", runtime, err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		e, err := ParseEvent(runtime, []byte(line))
+		if err != nil || e == nil || !f.Match(e) {
+			continue
+		}
+		fn(e)
+	}
+
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s events: %s", runtime, msg)
+		}
+		return fmt.Errorf("%s events: %w", runtime, err)
+	}
+	return nil
+}
+
+// EventRuntimes returns the installed runtimes events can be streamed from
+func EventRuntimes() []RuntimeType {
+	var runtimes []RuntimeType
+	if IsDockerAvailable() {
+		runtimes = append(runtimes, RuntimeDocker)
+	}
+	if IsPodmanAvailable() {
+		runtimes = append(runtimes, RuntimePodman)
+	}
+	return runtimes
+}
@@ -0,0 +1,86 @@
+package container
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEvent(t *testing.T) {
+	docker := `{"status":"die","id":"4f1c2a","from":"postgres:17","Type":"container","Action":"die","Actor":{"ID":"4f1c2a","Attributes":{"exitCode":"137","image":"postgres:17","name":"fider-db"}},"scope":"local","time":1760000000,"timeNano":1760000000123456789}`
+	e, err := ParseEvent(RuntimeDocker, []byte(docker))
+	if err != nil || e == nil {
+		t.Fatalf("docker event: %v %v", e, err)
+	}
+	if e.Action != "die" || e.Name != "fider-db" || e.Image != "postgres:17" || e.ExitCode == nil || *e.ExitCode != 137 {
+		t.Errorf("unexpected docker event: %+v", e)
+	}
+	if !e.Time.Equal(time.Unix(0, 1760000000123456789)) {
+		t.Errorf("docker time: %v", e.Time)
+	}
+
+	podman4 := `{"ID":"9ab3","Image":"docker.io/getfider/fider:stable","Name":"fider-app","Status":"died","Time":"2025-10-09T10:13:20.5+02:00","Type":"container","ContainerExitCode":1}`
+	e, err = ParseEvent(RuntimePodman, []byte(podman4))
+	if err != nil || e == nil || e.Action != "die" || e.ExitCode == nil || *e.ExitCode != 1 {
+		t.Fatalf("podman 4 event: %+v %v", e, err)
+	}
+	if want := time.Date(2025, 10, 9, 8, 13, 20, 500000000, time.UTC); !e.Time.Equal(want) {
+		t.Errorf("podman 4 time: %v", e.Time)
+	}
+
+	podman5 := `{"ID":"9ab3","Image":"mailhog/mailhog","Name":"pft-mailhog","Status":"start","time":1760000000,"timeNano":1760000000000000000,"Type":"container"}`
+	if e, err = ParseEvent(RuntimePodman, []byte(podman5)); err != nil || e == nil || e.Action != "start" || e.ExitCode != nil {
+		t.Fatalf("podman 5 event: %+v %v", e, err)
+	}
+
+	for _, line := range []string{
+		`{"Type":"network","Action":"connect","Actor":{"ID":"n1"}}`,
+		`{"Type":"container","Action":"exec_start: sh","Actor":{"ID":"c1"}}`,
+	} {
+		if e, err := ParseEvent(RuntimeDocker, []byte(line)); err != nil || e != nil {
+			t.Errorf("event %s should be skipped: %+v %v", line, e, err)
+		}
+	}
+	if _, err := ParseEvent(RuntimeDocker, []byte("not json")); err == nil {
+		t.Error("invalid JSON accepted")
+	}
+}
+
+func TestEventFilter(t *testing.T) {
+	f, err := ParseEventFilters([]string{"name=fider-*", "event=die", "event=oom", "label=app=pft"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Runtime) != 1 || f.Runtime[0] != "label=app=pft" {
+		t.Errorf("runtime filters: %v", f.Runtime)
+	}
+
+	tests := []struct {
+		event Event
+		want  bool
+	}{
+		{Event{Name: "fider-db", Action: "die"}, true},
+		{Event{Name: "fider-voc-app", Action: "oom"}, true},
+		{Event{Name: "fider-db", Action: "start"}, false},
+		{Event{Name: "clearflask-db", Action: "die"}, false},
+	}
+	for _, tt := range tests {
+		if got := f.Match(&tt.event); got != tt.want {
+			t.Errorf("Match(%+v) = %v, want %v", tt.event, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"name", "event=restart", "type=image", "name=[a"} {
+		if _, err := ParseEventFilters([]string{bad}); err == nil {
+			t.Errorf("filter %q accepted", bad)
+		}
+	}
+
+	args := strings.Join(eventsArgs(RuntimePodman, f, "10m"), " ")
+	if !strings.Contains(args, "event=died") || strings.Contains(args, "event=oom") || !strings.Contains(args, "--filter label=app=pft --since 10m --format json") {
+		t.Errorf("podman args: %s", args)
+	}
+	if args := strings.Join(eventsArgs(RuntimeDocker, EventFilter{}, ""), " "); !strings.Contains(args, "event=oom") || !strings.HasSuffix(args, "--format {{json .}}") {
+		t.Errorf("docker args: %s", args)
+	}
+}
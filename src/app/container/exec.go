@@ -0,0 +1,299 @@
+package container
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ExecOptions are the options of an exec in a running container
+type ExecOptions struct {
+	// Interactive keeps stdin attached to the command
+	Interactive bool
+	// TTY allocates a pseudo-terminal
+	TTY bool
+	// Detach runs the command in the background
+	Detach bool
+	// Env holds KEY=VALUE variables set in the container
+	Env     []string
+	WorkDir string
+	User    string
+}
+
+// Command returns 'docker exec' or 'podman exec' of the options. The
+// variables are passed in a private temporary --env-file, so their values
+// neither show up in the process list nor change the environment of the
+// runtime CLI itself (DOCKER_HOST, PATH, ...). The returned function
+// removes the env file once the command has run.
+func (o ExecOptions) Command(runtime, containerID string, command []string) (*exec.Cmd, func(), error) {
+	envFile, cleanup, err := o.writeEnvFile()
+	if err != nil {
+		return nil, nil, err
+	}
+	return exec.Command(runtime, o.Args(containerID, envFile, command)...), cleanup, nil
+}
+
+// writeEnvFile writes the variables of the exec to a file readable by the
+// owner only; without variables the path is empty
+func (o ExecOptions) writeEnvFile() (string, func(), error) {
+	if len(o.Env) == 0 {
+		return "", func() {}, nil
+	}
+	var b strings.Builder
+	for _, kv := range o.Env {
+		if strings.ContainsAny(kv, "\r\n") {
+			name, _, _ := strings.Cut(kv, "=")
+			return "", nil, fmt.Errorf("the value of %s contains a line break, which cannot be passed to the container", name)
+		}
+		b.WriteString(kv + "\n")
+	}
+	f, err := os.CreateTemp("", "portunix-exec-*.env")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.Remove(f.Name()) }
+	// CreateTemp opens the file with mode 0600
+	if _, err := f.WriteString(b.String()); err != nil {
+		f.Close()
+		cleanup()
+		return "", nil, err
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return f.Name(), cleanup, nil
+}
+
+// Args returns the arguments of 'docker exec' or 'podman exec' with the
+// variables read from envFile (see Command)
+func (o ExecOptions) Args(containerID, envFile string, command []string) []string {
+	args := []string{"exec"}
+	switch {
+	case o.Detach:
+		args = append(args, "-d")
+	case o.Interactive && o.TTY:
+		args = append(args, "-it")
+	case o.Interactive:
+		args = append(args, "-i")
+	case o.TTY:
+		args = append(args, "-t")
+	}
+	if envFile != "" {
+		args = append(args, "--env-file", envFile)
+	}
+	if o.WorkDir != "" {
+		args = append(args, "-w", o.WorkDir)
+	}
+	if o.User != "" {
+		args = append(args, "-u", o.User)
+	}
+	args = append(args, containerID)
+	return append(args, command...)
+}
+
+// ForStdin adapts the options to the stdin of portunix: without a
+// terminal no pseudo-terminal is allocated, which the runtimes refuse, and
+// piped input is passed to the command
+func (o ExecOptions) ForStdin() ExecOptions {
+	terminal, piped := stdinState()
+	return o.forStdin(terminal, piped)
+}
+
+func (o ExecOptions) forStdin(terminal, piped bool) ExecOptions {
+	if o.Detach {
+		o.Interactive, o.TTY = false, false
+		return o
+	}
+	if !terminal {
+		o.TTY = false
+	}
+	if piped {
+		o.Interactive = true
+	}
+	return o
+}
+
+// stdinState reports whether stdin is a terminal, and whether it is a
+// pipe or a file
+func stdinState() (terminal, piped bool) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return true, false
+	}
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false, false
+	}
+	return false, info.Mode()&os.ModeNamedPipe != 0 || info.Mode().IsRegular()
+}
+
+// ParseExecFlags parses the flags of container exec preceding the
+// container name and returns the options and the container name and
+// command; flags after the container name belong to the command
+func ParseExecFlags(args []string) (ExecOptions, []string, error) {
+	var opts ExecOptions
+	var envFiles, copyEnv []string
+	noTTY := false
+	i := 0
+	for ; i < len(args) && strings.HasPrefix(args[i], "-"); i++ {
+		flag, value, hasValue := strings.Cut(args[i], "=")
+		switch flag {
+		case "-i", "--interactive", "-it", "-ti":
+			opts.Interactive, opts.TTY = true, true
+			continue
+		case "-t", "--tty":
+			opts.TTY = true
+			continue
+		case "-T", "--no-tty":
+			noTTY = true
+			continue
+		case "-d", "--detach":
+			opts.Detach = true
+			continue
+		case "--env-file", "-e", "--env", "-w", "--workdir", "-u", "--user", "--copy-env":
+		default:
+			return opts, nil, fmt.Errorf("unknown flag %s", flag)
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return opts, nil, fmt.Errorf("flag %s needs a value", flag)
+			}
+			i++
+			value = args[i]
+		}
+		switch flag {
+		case "--env-file":
+			envFiles = append(envFiles, value)
+		case "-e", "--env":
+			// KEY without a value forwards the host variable
+			if !strings.Contains(value, "=") {
+				v, ok := os.LookupEnv(value)
+				if !ok {
+					continue
+				}
+				value += "=" + v
+			}
+			opts.Env = append(opts.Env, value)
+		case "-w", "--workdir":
+			opts.WorkDir = value
+		case "-u", "--user":
+			opts.User = value
+		case "--copy-env":
+			copyEnv = append(copyEnv, strings.Split(value, ",")...)
+		}
+	}
+
+	// Later sources win: env files, host variables, then -e
+	var env []string
+	for _, file := range envFiles {
+		vars, err := ParseEnvFile(file)
+		if err != nil {
+			return opts, nil, err
+		}
+		env = append(env, vars...)
+	}
+	if len(copyEnv) > 0 {
+		vars, err := MatchEnv(os.Environ(), copyEnv)
+		if err != nil {
+			return opts, nil, err
+		}
+		env = append(env, vars...)
+	}
+	opts.Env = append(env, opts.Env...)
+	if noTTY {
+		opts.TTY = false
+	}
+	return opts, args[i:], nil
+}
+
+// ParseEnvFile reads a .env file: KEY=VALUE lines with optional "export "
+// prefixes, quoted values and # comments. A line holding only KEY takes
+// the value of the host variable, as with docker --env-file.
+func ParseEnvFile(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var env []string
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, hasValue := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("%s:%d: invalid variable %q", file, lineNo, line)
+		}
+		if !hasValue {
+			if v, ok := os.LookupEnv(key); ok {
+				env = append(env, key+"="+v)
+			}
+			continue
+		}
+		value, err = envValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", file, lineNo, err)
+		}
+		env = append(env, key+"="+value)
+	}
+	return env, scanner.Err()
+}
+
+// envValue unquotes a value of a .env file; unquoted values end at a
+// " #" comment
+func envValue(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		end := strings.LastIndex(value, `"`)
+		if end == 0 {
+			return "", fmt.Errorf("unterminated quote in %s", value)
+		}
+		unquoted, err := strconv.Unquote(value[:end+1])
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted value %s", value)
+		}
+		return unquoted, nil
+	case strings.HasPrefix(value, "'"):
+		end := strings.LastIndex(value, "'")
+		if end == 0 {
+			return "", fmt.Errorf("unterminated quote in %s", value)
+		}
+		return value[1:end], nil
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value, nil
+}
+
+// MatchEnv returns the variables of environ whose name matches one of the
+// glob patterns, e.g. "AWS_*" or "CI"
+func MatchEnv(environ, patterns []string) ([]string, error) {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q", pattern)
+		}
+	}
+	var env []string
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				env = append(env, kv)
+				break
+			}
+		}
+	}
+	return env, nil
+}
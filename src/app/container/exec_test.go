@@ -0,0 +1,189 @@
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExecOptionsArgs(t *testing.T) {
+	opts := ExecOptions{Interactive: true, TTY: true, Env: []string{"TOKEN=s3cret", "EMPTY="}, WorkDir: "/app", User: "1000"}
+	args := strings.Join(opts.Args("web", "/tmp/exec.env", []string{"sh", "-c", "env"}), " ")
+	if args != "exec -it --env-file /tmp/exec.env -w /app -u 1000 web sh -c env" {
+		t.Errorf("unexpected args %s", args)
+	}
+	if args := strings.Join(ExecOptions{}.Args("db", "", []string{"ls"}), " "); args != "exec db ls" {
+		t.Errorf("unexpected args %s", args)
+	}
+}
+
+func TestExecOptionsCommand(t *testing.T) {
+	// A variable of an env file must reach the container, not the runtime CLI
+	file := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(file, []byte("DOCKER_HOST=tcp://attacker:2375\nTOKEN=s3cret\n"), 0644)
+	opts, _, err := ParseExecFlags([]string{"--env-file", file, "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, cleanup, err := opts.Command("docker", "web", []string{"env"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Env != nil {
+		t.Errorf("the runtime must run with the unmodified environment, got %v", cmd.Env)
+	}
+	args := strings.Join(cmd.Args, " ")
+	if strings.Contains(args, "attacker") || strings.Contains(args, "s3cret") {
+		t.Errorf("values should not be passed as arguments: %s", args)
+	}
+	envFile := cmd.Args[3]
+	if cmd.Args[2] != "--env-file" {
+		t.Fatalf("unexpected args %s", args)
+	}
+	info, err := os.Stat(envFile)
+	if err != nil || info.Mode().Perm()&0077 != 0 {
+		t.Errorf("env file should be private: %v %v", info, err)
+	}
+	if data, _ := os.ReadFile(envFile); string(data) != "DOCKER_HOST=tcp://attacker:2375\nTOKEN=s3cret\n" {
+		t.Errorf("unexpected env file %q", data)
+	}
+	cleanup()
+	if _, err := os.Stat(envFile); !os.IsNotExist(err) {
+		t.Error("env file should be removed")
+	}
+
+	if _, _, err := (ExecOptions{Env: []string{"KEY=a\nb"}}).Command("docker", "web", nil); err == nil {
+		t.Error("values with line breaks should be rejected")
+	}
+}
+
+func TestExecOptionsForStdin(t *testing.T) {
+	tests := []struct {
+		name            string
+		opts            ExecOptions
+		terminal, piped bool
+		want            string
+	}{
+		{"terminal keeps tty", ExecOptions{Interactive: true, TTY: true}, true, false, "exec -it c sh"},
+		{"no terminal drops tty", ExecOptions{Interactive: true, TTY: true}, false, false, "exec -i c sh"},
+		{"piped input is attached", ExecOptions{}, false, true, "exec -i c sh"},
+		{"plain exec in CI", ExecOptions{}, false, false, "exec c sh"},
+		{"detach wins", ExecOptions{Interactive: true, TTY: true, Detach: true}, true, true, "exec -d c sh"},
+		{"tty only on terminal", ExecOptions{TTY: true}, true, false, "exec -t c sh"},
+	}
+	for _, tt := range tests {
+		opts := tt.opts.forStdin(tt.terminal, tt.piped)
+		if got := strings.Join(opts.Args("c", "", []string{"sh"}), " "); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseEnvFile(t *testing.T) {
+	t.Setenv("PTX_HOST_VAR", "from-host")
+	file := filepath.Join(t.TempDir(), ".env")
+	content := `# settings
+export NODE_ENV=production
+DB_URL = postgres://db:5432/app
+GREETING="hello \"world\"\n"
+RAW='$HOME # not a comment'
+PORT=8080 # comment
+PTX_HOST_VAR
+PTX_MISSING_VAR
+EMPTY=
+`
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	env, err := ParseEnvFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"NODE_ENV=production",
+		"DB_URL=postgres://db:5432/app",
+		"GREETING=hello \"world\"\n",
+		"RAW=$HOME # not a comment",
+		"PORT=8080",
+		"PTX_HOST_VAR=from-host",
+		"EMPTY=",
+	}
+	if strings.Join(env, "|") != strings.Join(want, "|") {
+		t.Errorf("ParseEnvFile = %q, want %q", env, want)
+	}
+
+	os.WriteFile(file, []byte("BAD KEY=1\n"), 0600)
+	if _, err := ParseEnvFile(file); err == nil || !strings.Contains(err.Error(), ":1:") {
+		t.Errorf("expected error with line number, got %v", err)
+	}
+	os.WriteFile(file, []byte("A=\"open\n"), 0600)
+	if _, err := ParseEnvFile(file); err == nil {
+		t.Error("unterminated quote should fail")
+	}
+}
+
+func TestMatchEnv(t *testing.T) {
+	environ := []string{"AWS_REGION=eu-west-1", "AWS_PROFILE=dev", "CI=true", "CIRCLE=1", "HOME=/root"}
+	env, err := MatchEnv(environ, []string{"AWS_*", "CI"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(env, " ") != "AWS_REGION=eu-west-1 AWS_PROFILE=dev CI=true" {
+		t.Errorf("unexpected match %v", env)
+	}
+	if _, err := MatchEnv(environ, []string{"[AWS"}); err == nil {
+		t.Error("invalid pattern should fail")
+	}
+}
+
+func TestParseExecFlags(t *testing.T) {
+	t.Setenv("PTX_TEST_TOKEN", "abc")
+	t.Setenv("PTX_TEST_REGION", "eu")
+	envFile := t.TempDir() + "/.env"
+	if err := os.WriteFile(envFile, []byte("APP_ENV=dev\nPTX_TEST_TOKEN=from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, rest, err := ParseExecFlags([]string{
+		"-it", "--env-file", envFile, "--copy-env", "PTX_TEST_*", "-e", "DEBUG=1",
+		"--workdir=/app", "-u", "1000", "web", "mysql", "-u", "root",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.Interactive || !opts.TTY || opts.WorkDir != "/app" || opts.User != "1000" {
+		t.Errorf("unexpected options %+v", opts)
+	}
+	// later sources win when the runtime reads the environment
+	want := "APP_ENV=dev PTX_TEST_TOKEN=from-file PTX_TEST_TOKEN=abc PTX_TEST_REGION=eu DEBUG=1"
+	got := strings.Join(opts.Env, " ")
+	for _, kv := range strings.Fields(want) {
+		if !strings.Contains(got, kv) {
+			t.Errorf("environment %q lacks %s", got, kv)
+		}
+	}
+	if strings.Index(got, "from-file") > strings.Index(got, "PTX_TEST_TOKEN=abc") || !strings.HasSuffix(got, "DEBUG=1") {
+		t.Errorf("unexpected order %q", got)
+	}
+	if strings.Join(rest, " ") != "web mysql -u root" {
+		t.Errorf("unexpected container and command %v", rest)
+	}
+
+	opts, _, _ = ParseExecFlags([]string{"-T", "-i", "web", "cat"})
+	if !opts.Interactive || opts.TTY {
+		t.Errorf("--no-tty should keep stdin without a TTY: %+v", opts)
+	}
+	opts, _, _ = ParseExecFlags([]string{"--detach", "web", "sleep", "60"})
+	if !opts.Detach {
+		t.Errorf("--detach not parsed: %+v", opts)
+	}
+
+	if _, _, err := ParseExecFlags([]string{"--workdir"}); err == nil {
+		t.Error("missing value should fail")
+	}
+	if _, _, err := ParseExecFlags([]string{"--bogus", "web", "ls"}); err == nil {
+		t.Error("unknown flag should fail")
+	}
+}
@@ -0,0 +1,256 @@
+package container
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed templates/*.yaml
+var templateFS embed.FS
+
+// Template is a named environment of run-in-container: a base image, the
+// tools installed with 'portunix install', cache volumes kept between
+// containers, published ports and environment variables
+type Template struct {
+	Name        string          `yaml:"-" json:"name"`
+	Description string          `yaml:"description" json:"description"`
+	Image       string          `yaml:"image" json:"image"`
+	Tools       []string        `yaml:"tools" json:"tools"`
+	Caches      []TemplateCache `yaml:"caches" json:"caches,omitempty"`
+	// Ports are "port" (published on the same host port) or
+	// "host:container" mappings
+	Ports   []string `yaml:"ports" json:"ports,omitempty"`
+	Env     []string `yaml:"env" json:"env,omitempty"`
+	WorkDir string   `yaml:"workdir" json:"workdir,omitempty"`
+	// Source is "builtin" or the path of a custom template
+	Source string `yaml:"-" json:"source"`
+}
+
+// TemplateCache is a cache directory of a template, kept in the named
+// volume portunix-cache-<name> so containers of all templates share it
+type TemplateCache struct {
+	Name string `yaml:"name" json:"name"`
+	Path string `yaml:"path" json:"path"`
+}
+
+var cacheName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// TemplateDirs returns the directories searched for custom templates:
+// extra, then PORTUNIX_CONTAINER_TEMPLATE_PATH, then
+// ~/.portunix/container-templates. Earlier directories override later
+// ones and the built-in templates.
+func TemplateDirs(extra ...string) []string {
+	dirs := append([]string{}, extra...)
+	if env := os.Getenv("PORTUNIX_CONTAINER_TEMPLATE_PATH"); env != "" {
+		dirs = append(dirs, filepath.SplitList(env)...)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".portunix", "container-templates"))
+	}
+	return dirs
+}
+
+// ListTemplates returns the built-in templates and the *.yaml templates
+// found in dirs, sorted by name
+func ListTemplates(dirs []string) ([]*Template, error) {
+	byName := map[string]*Template{}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		entries, err := os.ReadDir(dirs[i])
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() || filepath.Ext(e.Name()) != ".yaml" {
+				continue
+			}
+			path := filepath.Join(dirs[i], e.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			t, err := parseTemplate(strings.TrimSuffix(e.Name(), ".yaml"), path, data)
+			if err != nil {
+				return nil, err
+			}
+			byName[t.Name] = t
+		}
+	}
+	builtin, err := fs.Glob(templateFS, "templates/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range builtin {
+		name := strings.TrimSuffix(filepath.Base(path), ".yaml")
+		if _, ok := byName[name]; ok {
+			continue
+		}
+		data, err := templateFS.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		t, err := parseTemplate(name, "builtin", data)
+		if err != nil {
+			return nil, err
+		}
+		byName[name] = t
+	}
+
+	templates := make([]*Template, 0, len(byName))
+	for _, t := range byName {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// LoadTemplate returns the template called name
+func LoadTemplate(name string, dirs []string) (*Template, error) {
+	templates, err := ListTemplates(dirs)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+		names = append(names, t.Name)
+	}
+	return nil, fmt.Errorf("unknown container template %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+func parseTemplate(name, source string, data []byte) (*Template, error) {
+	t := &Template{}
+	if err := yaml.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	t.Name, t.Source = name, source
+	if t.Image == "" {
+		return nil, fmt.Errorf("template %s: image is required", name)
+	}
+	if len(t.Tools) == 0 {
+		return nil, fmt.Errorf("template %s: at least one tool is required", name)
+	}
+	for _, c := range t.Caches {
+		if !cacheName.MatchString(c.Name) || !strings.HasPrefix(c.Path, "/") {
+			return nil, fmt.Errorf("template %s: cache %q needs a name and an absolute path", name, c.Name)
+		}
+	}
+	for _, env := range t.Env {
+		if !strings.Contains(env, "=") {
+			return nil, fmt.Errorf("template %s: invalid env %q, expected KEY=value", name, env)
+		}
+	}
+	return t, nil
+}
+
+// Volumes returns the volume mappings of the template caches
+func (t *Template) Volumes() []string {
+	var volumes []string
+	for _, c := range t.Caches {
+		volumes = append(volumes, fmt.Sprintf("portunix-cache-%s:%s", c.Name, c.Path))
+	}
+	return volumes
+}
+
+// PortMappings returns the port mappings of the template
+func (t *Template) PortMappings() []string {
+	var ports []string
+	for _, p := range t.Ports {
+		if !strings.Contains(p, ":") {
+			p = p + ":" + p
+		}
+		ports = append(ports, p)
+	}
+	return ports
+}
+
+// Args returns the run-in-container arguments creating the environment of
+// the template; arguments given after them override the image, name and
+// working directory
+func (t *Template) Args() []string {
+	args := []string{"--image", t.Image}
+	for _, tool := range t.Tools {
+		args = append(args, "--install", tool)
+	}
+	for _, v := range t.Volumes() {
+		args = append(args, "-v", v)
+	}
+	for _, p := range t.PortMappings() {
+		args = append(args, "-p", p)
+	}
+	for _, env := range t.Env {
+		args = append(args, "-e", env)
+	}
+	if t.WorkDir != "" {
+		args = append(args, "--workdir", t.WorkDir)
+	}
+	return args
+}
+
+// ApplyTemplate replaces --template NAME in run-in-container arguments with
+// the installation type and arguments of the template found in dirs
+func ApplyTemplate(args, dirs []string) ([]string, error) {
+	var name string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--template":
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("flag --template needs a value")
+			}
+			name = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--template="):
+			name = strings.TrimPrefix(args[i], "--template=")
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	if name == "" {
+		return args, nil
+	}
+	if len(rest) > 0 && !strings.HasPrefix(rest[0], "-") {
+		return nil, fmt.Errorf("--template replaces the installation type %q", rest[0])
+	}
+	t, err := LoadTemplate(name, dirs)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]string{t.Name}, t.Args()...), rest...), nil
+}
+
+// PrintTemplates writes templates as a table, or as JSON with asJSON
+func PrintTemplates(w io.Writer, templates []*Template, asJSON bool) error {
+	if asJSON {
+		data, err := json.MarshalIndent(templates, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	fmt.Fprintf(w, "%-16s %-16s %-24s %-12s %s\n", "NAME", "IMAGE", "TOOLS", "PORTS", "DESCRIPTION")
+	for _, t := range templates {
+		description := t.Description
+		if t.Source != "builtin" {
+			description += " (" + t.Source + ")"
+		}
+		fmt.Fprintf(w, "%-16s %-16s %-24s %-12s %s\n", t.Name, t.Image, strings.Join(t.Tools, ","), strings.Join(t.Ports, ","), description)
+	}
+	return nil
+}
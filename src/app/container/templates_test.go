@@ -0,0 +1,125 @@
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinTemplates(t *testing.T) {
+	templates, err := ListTemplates(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tmpl := range templates {
+		names = append(names, tmpl.Name)
+		if tmpl.Source != "builtin" {
+			t.Errorf("%s: unexpected source %s", tmpl.Name, tmpl.Source)
+		}
+	}
+	if strings.Join(names, ",") != "ansible-test,go-dev,node-dev,python-dev" {
+		t.Errorf("unexpected templates %v", names)
+	}
+
+	goDev, err := LoadTemplate("go-dev", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := strings.Join(goDev.Args(), " ")
+	for _, want := range []string{
+		"--image ubuntu:22.04",
+		"--install go --install git --install make",
+		"-v portunix-cache-go-mod:/root/go/pkg/mod",
+		"-p 8080:8080",
+		"--workdir /workspace",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("go-dev args lack %q: %s", want, args)
+		}
+	}
+	if _, err := LoadTemplate("cobol-dev", nil); err == nil || !strings.Contains(err.Error(), "go-dev") {
+		t.Errorf("expected unknown template error listing templates, got %v", err)
+	}
+}
+
+func TestCustomTemplates(t *testing.T) {
+	dir := t.TempDir()
+	custom := `description: Team Go image
+image: registry.example.com/go:1.23
+tools: [go]
+ports: ["9090:8080"]
+env: [GOPRIVATE=example.com]
+`
+	if err := os.WriteFile(filepath.Join(dir, "go-dev.yaml"), []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := LoadTemplate("go-dev", []string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Image != "registry.example.com/go:1.23" || tmpl.Source != filepath.Join(dir, "go-dev.yaml") {
+		t.Errorf("custom template should override the built-in one: %+v", tmpl)
+	}
+	if args := strings.Join(tmpl.Args(), " "); args != "--image registry.example.com/go:1.23 --install go -p 9090:8080 -e GOPRIVATE=example.com" {
+		t.Errorf("unexpected args %s", args)
+	}
+
+	for name, content := range map[string]string{
+		"no-image":  "tools: [go]\n",
+		"no-tools":  "image: ubuntu:22.04\n",
+		"bad-cache": "image: ubuntu:22.04\ntools: [go]\ncaches:\n  - name: go\n    path: relative\n",
+		"bad-env":   "image: ubuntu:22.04\ntools: [go]\nenv: [NOVALUE]\n",
+	} {
+		bad := t.TempDir()
+		os.WriteFile(filepath.Join(bad, name+".yaml"), []byte(content), 0644)
+		if _, err := ListTemplates([]string{bad}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestApplyTemplate(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "rust-dev.yaml"), []byte("image: rust:1\ntools: [rust]\nworkdir: /src\n"), 0644)
+	dirs := []string{dir}
+
+	args, err := ApplyTemplate([]string{"--template", "rust-dev", "--name", "web", "-e", "RUST_LOG=debug"}, dirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(args, " "); got != "rust-dev --image rust:1 --install rust --workdir /src --name web -e RUST_LOG=debug" {
+		t.Errorf("unexpected arguments %s", got)
+	}
+	if args, err := ApplyTemplate([]string{"--template=node-dev"}, dirs); err != nil || args[0] != "node-dev" {
+		t.Errorf("built-in template: %v %v", args, err)
+	}
+	if args, err := ApplyTemplate([]string{"python", "--keep-running"}, dirs); err != nil || strings.Join(args, " ") != "python --keep-running" {
+		t.Errorf("arguments without a template should be kept: %v %v", args, err)
+	}
+
+	for _, bad := range [][]string{
+		{"--template"},
+		{"--template", "unknown"},
+		{"python", "--template=go-dev"},
+	} {
+		if _, err := ApplyTemplate(bad, dirs); err == nil {
+			t.Errorf("expected an error for %v", bad)
+		}
+	}
+}
+
+func TestPrintTemplates(t *testing.T) {
+	templates := []*Template{{Name: "rust-dev", Image: "rust:1", Tools: []string{"rust", "git"}, Description: "Rust", Source: "/t/rust-dev.yaml"}}
+	var table, js strings.Builder
+	if err := PrintTemplates(&table, templates, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(table.String(), "rust-dev") || !strings.Contains(table.String(), "rust,git") || !strings.Contains(table.String(), "Rust (/t/rust-dev.yaml)") {
+		t.Errorf("unexpected table:\n%s", table.String())
+	}
+	if err := PrintTemplates(&js, templates, true); err != nil || !strings.Contains(js.String(), `"name": "rust-dev"`) {
+		t.Errorf("unexpected JSON %s %v", js.String(), err)
+	}
+}
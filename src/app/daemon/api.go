@@ -0,0 +1,170 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"portunix.ai/app/progress"
+	"portunix.ai/app/system"
+)
+
+// ServiceName is the fully qualified gRPC service name
+const ServiceName = "portunix.daemon.v1.Daemon"
+
+// Method names of the daemon service
+const (
+	MethodPing           = "Ping"
+	MethodSystemInfo     = "SystemInfo"
+	MethodInstall        = "Install"
+	MethodInstallStream  = "InstallStream"
+	MethodListContainers = "ListContainers"
+	MethodContainer      = "Container"
+	MethodPftSync        = "PftSync"
+	MethodShutdown       = "Shutdown"
+)
+
+// Container actions accepted by the Container method
+var ContainerActions = []string{"start", "stop", "rm", "logs"}
+
+// PingRequest checks that the daemon is alive
+type PingRequest struct{}
+
+// PingResponse describes the running daemon
+type PingResponse struct {
+	Version string    `json:"version"`
+	PID     int       `json:"pid"`
+	Started time.Time `json:"started"`
+}
+
+// SystemInfoRequest asks for the host system information
+type SystemInfoRequest struct{}
+
+// SystemInfoResponse carries the same data as 'portunix system info --json'
+type SystemInfoResponse struct {
+	Info *system.SystemInfo `json:"info"`
+}
+
+// InstallRequest installs a package from the portunix registry
+type InstallRequest struct {
+	Package string `json:"package"`
+	Variant string `json:"variant,omitempty"`
+	DryRun  bool   `json:"dry_run,omitempty"`
+}
+
+// InstallProgress is a message of the InstallStream method: progress
+// events of the install while it runs, then its result
+type InstallProgress struct {
+	Event  *progress.Event  `json:"event,omitempty"`
+	Result *CommandResponse `json:"result,omitempty"`
+}
+
+// ListContainersRequest lists containers of the preferred runtime
+type ListContainersRequest struct{}
+
+// Container is a single container as reported by the runtime
+type Container struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Image  string `json:"image"`
+	State  string `json:"state"`
+	Status string `json:"status"`
+}
+
+// ListContainersResponse holds all containers, running or not
+type ListContainersResponse struct {
+	Runtime    string      `json:"runtime"`
+	Containers []Container `json:"containers"`
+}
+
+// ContainerRequest runs a lifecycle action on one container
+type ContainerRequest struct {
+	Action string `json:"action"` // one of ContainerActions
+	Name   string `json:"name"`
+}
+
+// PftSyncRequest synchronizes a PFT project with its external tools
+type PftSyncRequest struct {
+	Dir    string `json:"dir"` // project directory
+	DryRun bool   `json:"dry_run,omitempty"`
+}
+
+// CommandResponse is the outcome of an operation run as a portunix command
+type CommandResponse struct {
+	Command  []string `json:"command"`
+	ExitCode int      `json:"exit_code"`
+	Output   string   `json:"output"`
+}
+
+// ShutdownRequest stops the daemon
+type ShutdownRequest struct{}
+
+// ShutdownResponse confirms the daemon is stopping
+type ShutdownResponse struct{}
+
+// jsonCodec encodes messages as JSON instead of protobuf
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                       { return "json" }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// serviceDesc describes the daemon service for grpc.Server
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		unaryMethod(MethodPing, (*Server).ping),
+		unaryMethod(MethodSystemInfo, (*Server).systemInfo),
+		unaryMethod(MethodInstall, (*Server).install),
+		unaryMethod(MethodListContainers, (*Server).listContainers),
+		unaryMethod(MethodContainer, (*Server).container),
+		unaryMethod(MethodPftSync, (*Server).pftSync),
+		unaryMethod(MethodShutdown, (*Server).shutdown),
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    MethodInstallStream,
+			ServerStreams: true,
+			Handler: func(srv any, stream grpc.ServerStream) error {
+				req := &InstallRequest{}
+				if err := stream.RecvMsg(req); err != nil {
+					return err
+				}
+				return srv.(*Server).installStream(req, stream)
+			},
+		},
+	},
+	Metadata: "portunix/daemon.v1",
+}
+
+// unaryMethod adapts a typed server method to a gRPC method handler
+func unaryMethod[Req, Resp any](name string, call func(*Server, context.Context, *Req) (*Resp, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			req := new(Req)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, r any) (any, error) {
+				return call(srv.(*Server), ctx, r.(*Req))
+			}
+			if interceptor == nil {
+				return handler(ctx, req)
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod(name)}
+			return interceptor(ctx, req, info, handler)
+		},
+	}
+}
+
+func fullMethod(name string) string {
+	return "/" + ServiceName + "/" + name
+}
@@ -0,0 +1,129 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"portunix.ai/app/progress"
+)
+
+// Client calls a running daemon
+type Client struct {
+	conn  *grpc.ClientConn
+	token string
+}
+
+// Connect creates a client for the daemon of the current user
+func Connect() (*Client, error) {
+	target, err := ReadEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	token, err := ReadToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read daemon token: %w", err)
+	}
+	return NewClient(target, token)
+}
+
+// NewClient creates a client for the daemon at target, e.g.
+// "unix:///home/user/.portunix/daemon/daemon.sock" or "127.0.0.1:50123".
+// The connection is local only, so transport security is not used; calls
+// are authenticated with the token.
+func NewClient(target, token string) (*Client, error) {
+	conn, err := grpc.NewClient(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(jsonCodec{}.Name())),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	return &Client{conn: conn, token: token}, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) invoke(ctx context.Context, method string, req, resp any) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.token)
+	return c.conn.Invoke(ctx, fullMethod(method), req, resp)
+}
+
+// Ping returns the version and uptime of the daemon
+func (c *Client) Ping(ctx context.Context) (*PingResponse, error) {
+	resp := &PingResponse{}
+	return resp, c.invoke(ctx, MethodPing, &PingRequest{}, resp)
+}
+
+// SystemInfo returns the host system information
+func (c *Client) SystemInfo(ctx context.Context) (*SystemInfoResponse, error) {
+	resp := &SystemInfoResponse{}
+	return resp, c.invoke(ctx, MethodSystemInfo, &SystemInfoRequest{}, resp)
+}
+
+// Install installs a package
+func (c *Client) Install(ctx context.Context, req *InstallRequest) (*CommandResponse, error) {
+	resp := &CommandResponse{}
+	return resp, c.invoke(ctx, MethodInstall, req, resp)
+}
+
+// InstallStream installs a package, calling onProgress with its progress
+// events while it runs
+func (c *Client) InstallStream(ctx context.Context, req *InstallRequest, onProgress func(progress.Event)) (*CommandResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.token)
+	desc := &grpc.StreamDesc{StreamName: MethodInstallStream, ServerStreams: true}
+	stream, err := c.conn.NewStream(ctx, desc, fullMethod(MethodInstallStream))
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	for {
+		msg := &InstallProgress{}
+		if err := stream.RecvMsg(msg); err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("install stream ended without a result")
+			}
+			return nil, err
+		}
+		if msg.Event != nil && onProgress != nil {
+			onProgress(*msg.Event)
+		}
+		if msg.Result != nil {
+			return msg.Result, nil
+		}
+	}
+}
+
+// ListContainers lists containers of the preferred runtime
+func (c *Client) ListContainers(ctx context.Context) (*ListContainersResponse, error) {
+	resp := &ListContainersResponse{}
+	return resp, c.invoke(ctx, MethodListContainers, &ListContainersRequest{}, resp)
+}
+
+// Container runs a lifecycle action on a container
+func (c *Client) Container(ctx context.Context, req *ContainerRequest) (*CommandResponse, error) {
+	resp := &CommandResponse{}
+	return resp, c.invoke(ctx, MethodContainer, req, resp)
+}
+
+// PftSync synchronizes a PFT project
+func (c *Client) PftSync(ctx context.Context, req *PftSyncRequest) (*CommandResponse, error) {
+	resp := &CommandResponse{}
+	return resp, c.invoke(ctx, MethodPftSync, req, resp)
+}
+
+// Shutdown stops the daemon
+func (c *Client) Shutdown(ctx context.Context) error {
+	return c.invoke(ctx, MethodShutdown, &ShutdownRequest{}, &ShutdownResponse{})
+}
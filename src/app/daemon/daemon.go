@@ -0,0 +1,144 @@
+// Package daemon serves core portunix operations (install, container
+// lifecycle, system info, PFT sync) over a local gRPC API, so IDE plugins and
+// the MCP server can drive portunix without starting a process per command.
+//
+// The daemon listens on a Unix socket in ~/.portunix/daemon (a loopback TCP
+// port on Windows) and requires the bearer token stored next to it in every
+// call. Messages are JSON encoded with the gRPC content-subtype "json"
+// (content-type application/grpc+json), so clients in any language can call
+// the service without generated stubs.
+package daemon
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// EnvDir overrides the directory holding the socket, token and endpoint
+	EnvDir = "PORTUNIX_DAEMON_DIR"
+
+	socketFile   = "daemon.sock"
+	tokenFile    = "token"
+	endpointFile = "endpoint"
+	logFile      = "daemon.log"
+)
+
+// Dir returns the daemon state directory
+func Dir() string {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-daemon")
+	}
+	return filepath.Join(home, ".portunix", "daemon")
+}
+
+// SocketPath returns the Unix socket the daemon listens on
+func SocketPath() string {
+	return filepath.Join(Dir(), socketFile)
+}
+
+// TokenPath returns the file holding the authentication token
+func TokenPath() string {
+	return filepath.Join(Dir(), tokenFile)
+}
+
+// EndpointPath returns the file holding the gRPC target of the running daemon
+func EndpointPath() string {
+	return filepath.Join(Dir(), endpointFile)
+}
+
+// LogPath returns the file receiving the output of a background daemon
+func LogPath() string {
+	return filepath.Join(Dir(), logFile)
+}
+
+// StartBackground starts "self args..." detached from the terminal with its
+// output appended to LogPath and returns the process ID
+func StartBackground(self string, args ...string) (int, error) {
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		return 0, fmt.Errorf("failed to create daemon directory: %w", err)
+	}
+	return StartDetached(LogPath(), self, args...)
+}
+
+// StartDetached starts "self args..." detached from the terminal with its
+// output appended to logPath and returns the process ID. Other background
+// services, like 'portunix vuln watch', use it with their own log.
+func StartDetached(logPath, self string, args ...string) (int, error) {
+	log, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open log %s: %w", logPath, err)
+	}
+	defer log.Close()
+
+	c := exec.Command(self, args...)
+	c.Stdout, c.Stderr = log, log
+	c.SysProcAttr = detachSysProcAttr()
+	if err := c.Start(); err != nil {
+		return 0, err
+	}
+	pid := c.Process.Pid
+	return pid, c.Process.Release()
+}
+
+// EnsureToken returns the authentication token, creating it on first use.
+// The token file is readable by the current user only.
+func EnsureToken() (string, error) {
+	if token, err := ReadToken(); err == nil {
+		return token, nil
+	}
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		return "", fmt.Errorf("failed to create daemon directory: %w", err)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	if err := os.WriteFile(TokenPath(), []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to write token: %w", err)
+	}
+	return token, nil
+}
+
+// ReadToken returns the stored authentication token
+func ReadToken() (string, error) {
+	data, err := os.ReadFile(TokenPath())
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", TokenPath())
+	}
+	return token, nil
+}
+
+// ReadEndpoint returns the gRPC target written by the running daemon
+func ReadEndpoint() (string, error) {
+	data, err := os.ReadFile(EndpointPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("daemon is not running (start it with 'portunix daemon start')")
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// useUnixSocket reports whether the daemon listens on a Unix socket rather
+// than a loopback TCP port
+func useUnixSocket() bool {
+	return runtime.GOOS != "windows"
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package daemon
+
+import "syscall"
+
+// detachSysProcAttr starts the background daemon in its own session so it
+// survives the terminal that started it
+func detachSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Setsid: true,
+	}
+}
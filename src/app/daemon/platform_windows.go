@@ -0,0 +1,15 @@
+//go:build windows
+
+package daemon
+
+import "syscall"
+
+// CREATE_NEW_PROCESS_GROUP | DETACHED_PROCESS
+const detachedProcess = 0x00000200 | 0x00000008
+
+// detachSysProcAttr starts the background daemon without a console
+func detachSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		CreationFlags: detachedProcess,
+	}
+}
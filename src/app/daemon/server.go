@@ -0,0 +1,278 @@
+package daemon
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"portunix.ai/app/progress"
+	"portunix.ai/app/system"
+)
+
+// Backend performs the operations requested through the API
+type Backend interface {
+	SystemInfo(ctx context.Context) (*system.SystemInfo, error)
+	ListContainers(ctx context.Context) (*ListContainersResponse, error)
+	// Run executes portunix with args in dir and reports its exit code and
+	// combined output; an error means the command could not be started.
+	// With onProgress the command reports progress events, which are
+	// passed to it instead of being part of the output.
+	Run(ctx context.Context, dir string, args []string, onProgress func(progress.Event)) (*CommandResponse, error)
+}
+
+// Server is the daemon gRPC service
+type Server struct {
+	backend Backend
+	token   string
+	version string
+	started time.Time
+
+	grpc *grpc.Server
+	// mu serializes operations that change the system, so two clients
+	// cannot run package managers or container actions concurrently
+	mu sync.Mutex
+}
+
+var (
+	packageNamePattern   = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]*$`)
+	containerNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+)
+
+// NewServer creates a daemon accepting calls authenticated with token
+func NewServer(backend Backend, token, version string) *Server {
+	s := &Server{
+		backend: backend,
+		token:   token,
+		version: version,
+		started: time.Now(),
+	}
+	s.grpc = grpc.NewServer(grpc.UnaryInterceptor(s.authenticate), grpc.StreamInterceptor(s.authenticateStream))
+	s.grpc.RegisterService(&serviceDesc, s)
+	return s
+}
+
+// Serve accepts connections on lis until Stop is called
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// Stop waits for running calls to finish and stops the server
+func (s *Server) Stop() {
+	s.grpc.GracefulStop()
+}
+
+// Listen opens the daemon endpoint and records its target for clients.
+// A stale socket of a daemon that is no longer running is replaced.
+func Listen() (net.Listener, error) {
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create daemon directory: %w", err)
+	}
+
+	var (
+		lis    net.Listener
+		target string
+		err    error
+	)
+	if useUnixSocket() {
+		path := SocketPath()
+		if _, statErr := os.Stat(path); statErr == nil {
+			if conn, dialErr := net.DialTimeout("unix", path, time.Second); dialErr == nil {
+				conn.Close()
+				return nil, fmt.Errorf("daemon is already running on %s", path)
+			}
+			os.Remove(path)
+		}
+		lis, err = net.Listen("unix", path)
+		if err == nil {
+			err = os.Chmod(path, 0600)
+		}
+		abs, _ := filepath.Abs(path)
+		target = "unix://" + filepath.ToSlash(abs)
+	} else {
+		lis, err = net.Listen("tcp", "127.0.0.1:0")
+		if err == nil {
+			target = lis.Addr().String()
+		}
+	}
+	if err != nil {
+		if lis != nil {
+			lis.Close()
+		}
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+
+	if err := os.WriteFile(EndpointPath(), []byte(target+"\n"), 0600); err != nil {
+		lis.Close()
+		return nil, fmt.Errorf("failed to write endpoint: %w", err)
+	}
+	return lis, nil
+}
+
+// Cleanup removes the endpoint and socket files of a stopped daemon
+func Cleanup() {
+	os.Remove(EndpointPath())
+	if useUnixSocket() {
+		os.Remove(SocketPath())
+	}
+}
+
+// authorized reports whether the call carries the daemon token
+func (s *Server) authorized(ctx context.Context) bool {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token := strings.TrimPrefix(value, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate rejects calls without the daemon token
+func (s *Server) authenticate(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !s.authorized(ctx) {
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid daemon token")
+	}
+	return handler(ctx, req)
+}
+
+// authenticateStream rejects streams without the daemon token
+func (s *Server) authenticateStream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !s.authorized(stream.Context()) {
+		return status.Error(codes.Unauthenticated, "missing or invalid daemon token")
+	}
+	return handler(srv, stream)
+}
+
+func (s *Server) ping(ctx context.Context, req *PingRequest) (*PingResponse, error) {
+	return &PingResponse{Version: s.version, PID: os.Getpid(), Started: s.started}, nil
+}
+
+func (s *Server) systemInfo(ctx context.Context, req *SystemInfoRequest) (*SystemInfoResponse, error) {
+	info, err := s.backend.SystemInfo(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get system info: %v", err)
+	}
+	return &SystemInfoResponse{Info: info}, nil
+}
+
+// installArgs validates an install request and returns its command
+func installArgs(req *InstallRequest) ([]string, error) {
+	if !packageNamePattern.MatchString(req.Package) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid package name %q", req.Package)
+	}
+	args := []string{"install", req.Package}
+	if req.Variant != "" {
+		if !packageNamePattern.MatchString(req.Variant) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid variant %q", req.Variant)
+		}
+		args = append(args, "--variant", req.Variant)
+	}
+	if req.DryRun {
+		args = append(args, "--dry-run")
+	}
+	return args, nil
+}
+
+func (s *Server) install(ctx context.Context, req *InstallRequest) (*CommandResponse, error) {
+	args, err := installArgs(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, "", args, !req.DryRun, nil)
+}
+
+// installStream installs a package, sending its progress events while it
+// runs and the result last
+func (s *Server) installStream(req *InstallRequest, stream grpc.ServerStream) error {
+	args, err := installArgs(req)
+	if err != nil {
+		return err
+	}
+	var sendErr error
+	resp, err := s.run(stream.Context(), "", args, !req.DryRun, func(e progress.Event) {
+		if sendErr == nil {
+			sendErr = stream.SendMsg(&InstallProgress{Event: &e})
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if sendErr != nil {
+		return sendErr
+	}
+	return stream.SendMsg(&InstallProgress{Result: resp})
+}
+
+func (s *Server) listContainers(ctx context.Context, req *ListContainersRequest) (*ListContainersResponse, error) {
+	resp, err := s.backend.ListContainers(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to list containers: %v", err)
+	}
+	return resp, nil
+}
+
+func (s *Server) container(ctx context.Context, req *ContainerRequest) (*CommandResponse, error) {
+	action := req.Action
+	if action == "remove" {
+		action = "rm"
+	}
+	valid := false
+	for _, a := range ContainerActions {
+		if a == action {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid container action %q (valid: %s)", req.Action, strings.Join(ContainerActions, ", "))
+	}
+	if !containerNamePattern.MatchString(req.Name) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid container name %q", req.Name)
+	}
+	return s.run(ctx, "", []string{"container", action, req.Name}, action != "logs", nil)
+}
+
+func (s *Server) pftSync(ctx context.Context, req *PftSyncRequest) (*CommandResponse, error) {
+	if req.Dir == "" || !filepath.IsAbs(req.Dir) {
+		return nil, status.Error(codes.InvalidArgument, "dir must be an absolute project path")
+	}
+	if info, err := os.Stat(req.Dir); err != nil || !info.IsDir() {
+		return nil, status.Errorf(codes.NotFound, "project directory %s not found", req.Dir)
+	}
+	args := []string{"pft", "sync"}
+	if req.DryRun {
+		args = append(args, "--dry-run")
+	}
+	return s.run(ctx, req.Dir, args, !req.DryRun, nil)
+}
+
+func (s *Server) shutdown(ctx context.Context, req *ShutdownRequest) (*ShutdownResponse, error) {
+	// Stop after the response is sent; GracefulStop waits for this call
+	go s.Stop()
+	return &ShutdownResponse{}, nil
+}
+
+// run executes a portunix command, one changing operation at a time
+func (s *Server) run(ctx context.Context, dir string, args []string, exclusive bool, onProgress func(progress.Event)) (*CommandResponse, error) {
+	if exclusive {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	resp, err := s.backend.Run(ctx, dir, args, onProgress)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to run %s: %v", strings.Join(args, " "), err)
+	}
+	return resp, nil
+}
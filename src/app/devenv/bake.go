@@ -0,0 +1,171 @@
+package devenv
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// BakeOptions controls 'portunix env bake'
+type BakeOptions struct {
+	Output  string // Image reference, e.g. myteam/dev:latest
+	Runtime string // Container runtime building the image: docker or podman
+	Push    bool
+	// Portunix is the Linux portunix binary copied into the image; default
+	// the running executable
+	Portunix  string
+	BuildArgs []string // NAME=value build arguments, e.g. the proxy
+}
+
+// BakeResult reports what Bake did
+type BakeResult struct {
+	Image         string   `json:"image"`
+	Containerfile string   `json:"containerfile"`
+	Tools         int      `json:"tools"`
+	Pushed        bool     `json:"pushed"`
+	SkippedTasks  []string `json:"skipped_tasks,omitempty"` // Tasks need the repository, they are not baked
+}
+
+// caCertificatesScript installs the CA certificates portunix downloads need
+// when the base image lacks them
+const caCertificatesScript = `if [ ! -e /etc/ssl/certs/ca-certificates.crt ] && [ ! -e /etc/pki/tls/certs/ca-bundle.crt ]; then \
+      if command -v apt-get >/dev/null; then apt-get update && apt-get install -y --no-install-recommends ca-certificates && rm -rf /var/lib/apt/lists/*; \
+      elif command -v dnf >/dev/null; then dnf install -y ca-certificates && dnf clean all; \
+      elif command -v apk >/dev/null; then apk add --no-cache ca-certificates; fi; \
+    fi`
+
+// Containerfile returns the Containerfile baking the environment into an
+// image: the image of the development container with portunix, the tools
+// installed by 'portunix install apply' and the env section. ${VAR} in the
+// env section refers to the environment of the image.
+func Containerfile(m *Manifest) []byte {
+	c := m.Container
+	if c == nil {
+		c = &Container{}
+	}
+	image, workdir := c.Image, c.Workdir
+	if image == "" {
+		image = DefaultContainerImage
+	}
+	if workdir == "" {
+		workdir = "/workspace"
+	}
+
+	var tools []string
+	for _, t := range m.Tools {
+		if t.Version != "" {
+			tools = append(tools, t.Name+" "+t.Version)
+		} else {
+			tools = append(tools, t.Name)
+		}
+	}
+	description := "Development environment " + m.Name
+	if len(tools) > 0 {
+		description += " with " + strings.Join(tools, ", ")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Development environment %s, generated by 'portunix env bake' from %s\n",
+		m.Name, filepath.Join(m.Dir, ManifestFile))
+	fmt.Fprintf(&b, "FROM %s\n", image)
+	fmt.Fprintf(&b, "LABEL org.opencontainers.image.title=%s \\\n      org.opencontainers.image.description=%s\n",
+		dockerQuote(m.Name), dockerQuote(description))
+	b.WriteString("COPY portunix /usr/local/bin/portunix\n")
+	if len(m.Tools) > 0 {
+		b.WriteString("COPY tools.yaml /etc/portunix/env-tools.yaml\n")
+		b.WriteString("RUN " + caCertificatesScript + " && \\\n    portunix install apply /etc/portunix/env-tools.yaml\n")
+	}
+	names := make([]string, 0, len(m.Env))
+	for name := range m.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "ENV %s=%s\n", name, dockerQuote(m.Env[name]))
+	}
+	fmt.Fprintf(&b, "WORKDIR %s\n", workdir)
+	return []byte(b.String())
+}
+
+// dockerQuote quotes a value for LABEL and ENV instructions, keeping ${VAR}
+// references
+func dockerQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+// BakeDir returns the build context of the image of the environment
+func BakeDir(m *Manifest) string {
+	return filepath.Join(StateDir(m), "bake")
+}
+
+// Bake builds an image with the tools and settings of the environment
+// pre-installed, and with opts.Push pushes it to its registry. Tasks are not
+// baked: they run in the repository, which is not part of the image.
+func Bake(m *Manifest, opts BakeOptions, out io.Writer) (*BakeResult, error) {
+	if opts.Output == "" {
+		return nil, fmt.Errorf("image name required (--output myteam/dev:latest)")
+	}
+	if opts.Runtime != "docker" && opts.Runtime != "podman" {
+		return nil, fmt.Errorf("invalid container runtime: %s (must be 'docker' or 'podman')", opts.Runtime)
+	}
+	portunix := opts.Portunix
+	if portunix == "" {
+		// The binary runs in the image, so it has to be a Linux one
+		if runtime.GOOS != "linux" {
+			return nil, fmt.Errorf("baking an image needs a Linux host, the portunix binary is copied into the image")
+		}
+		exe, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate portunix: %w", err)
+		}
+		portunix = exe
+	}
+
+	dir := BakeDir(m)
+	result := &BakeResult{Image: opts.Output, Containerfile: filepath.Join(dir, "Containerfile"), Tools: len(m.Tools)}
+	for _, t := range m.Tasks {
+		result.SkippedTasks = append(result.SkippedTasks, t.Name)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	if err := os.WriteFile(result.Containerfile, Containerfile(m), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write Containerfile: %w", err)
+	}
+	tools, err := toolsManifest(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tools.yaml"), tools, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write tools manifest: %w", err)
+	}
+	binary, err := os.ReadFile(portunix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read portunix binary: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "portunix"), binary, 0755); err != nil {
+		return nil, fmt.Errorf("failed to copy portunix binary: %w", err)
+	}
+
+	fmt.Fprintf(out, "🔨 Building %s (%d tools)...\n", opts.Output, result.Tools)
+	args := []string{"build", "-t", opts.Output, "-f", result.Containerfile}
+	for _, arg := range opts.BuildArgs {
+		args = append(args, "--build-arg", arg)
+	}
+	if err := runCommand(m.Dir, os.Environ(), out, opts.Runtime, append(args, dir)...); err != nil {
+		return nil, fmt.Errorf("building the image failed: %w", err)
+	}
+
+	if opts.Push {
+		fmt.Fprintf(out, "📤 Pushing %s...\n", opts.Output)
+		if err := runCommand(m.Dir, os.Environ(), out, opts.Runtime, "push", opts.Output); err != nil {
+			return result, fmt.Errorf("pushing the image failed (log in with '%s login'): %w", opts.Runtime, err)
+		}
+		result.Pushed = true
+	}
+	return result, nil
+}
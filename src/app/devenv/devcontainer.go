@@ -0,0 +1,389 @@
+package devenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DevcontainerFile is where VS Code looks for the development container
+// definition, relative to the repository root
+const DevcontainerFile = ".devcontainer/devcontainer.json"
+
+// Devcontainer is the part of devcontainer.json that maps to the portunix
+// env model
+type Devcontainer struct {
+	Name              string                 `json:"name,omitempty"`
+	Image             string                 `json:"image,omitempty"`
+	Features          map[string]interface{} `json:"features,omitempty"`
+	ForwardPorts      []interface{}          `json:"forwardPorts,omitempty"`
+	ContainerEnv      map[string]string      `json:"containerEnv,omitempty"`
+	RemoteEnv         map[string]string      `json:"remoteEnv,omitempty"`
+	WorkspaceFolder   string                 `json:"workspaceFolder,omitempty"`
+	OnCreateCommand   interface{}            `json:"onCreateCommand,omitempty"`
+	PostCreateCommand interface{}            `json:"postCreateCommand,omitempty"`
+	PostStartCommand  interface{}            `json:"postStartCommand,omitempty"`
+}
+
+// devcontainerKeys are the devcontainer.json properties the conversion
+// handles; others are reported as not converted
+var devcontainerKeys = map[string]bool{
+	"$schema": true, "name": true, "image": true, "features": true, "forwardPorts": true,
+	"containerEnv": true, "remoteEnv": true, "workspaceFolder": true,
+	"onCreateCommand": true, "postCreateCommand": true, "postStartCommand": true,
+}
+
+// featureTools maps dev container features to portunix packages
+var featureTools = []struct {
+	Feature string // ID without registry and version, e.g. go
+	Ref     string // Reference written on export
+	Tool    string
+}{
+	{"go", "ghcr.io/devcontainers/features/go:1", "go"},
+	{"node", "ghcr.io/devcontainers/features/node:1", "nodejs"},
+	{"python", "ghcr.io/devcontainers/features/python:1", "python"},
+	{"java", "ghcr.io/devcontainers/features/java:1", "java"},
+	{"rust", "ghcr.io/devcontainers/features/rust:1", "rust"},
+	{"git", "ghcr.io/devcontainers/features/git:1", "git"},
+	{"github-cli", "ghcr.io/devcontainers/features/github-cli:1", "gh"},
+	{"docker-in-docker", "ghcr.io/devcontainers/features/docker-in-docker:2", "docker"},
+	{"docker-outside-of-docker", "ghcr.io/devcontainers/features/docker-outside-of-docker:1", "docker"},
+	{"kubectl-helm-minikube", "ghcr.io/devcontainers/features/kubectl-helm-minikube:1", "kubectl"},
+	{"powershell", "ghcr.io/devcontainers/features/powershell:1", "powershell"},
+	{"hugo", "ghcr.io/devcontainers/features/hugo:1", "hugo"},
+}
+
+// featureID returns the ID of a feature reference:
+// ghcr.io/devcontainers/features/go:1 -> go
+func featureID(ref string) string {
+	id := ref[strings.LastIndex(ref, "/")+1:]
+	if i := strings.IndexAny(id, ":@"); i >= 0 {
+		id = id[:i]
+	}
+	return id
+}
+
+// stripJSONC removes the comments and trailing commas devcontainer.json
+// allows, so it can be decoded as JSON
+func stripJSONC(data []byte) []byte {
+	var out []byte
+	inString, escaped := false, false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			if escaped {
+				escaped = false
+			} else if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+			continue
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := strings.Index(string(data[i+2:]), "*/")
+			if end < 0 {
+				return out
+			}
+			i += end + 3
+			continue
+		case c == '}' || c == ']':
+			// Drop a trailing comma before the closing bracket
+			j := len(out) - 1
+			for j >= 0 && strings.ContainsRune(" \t\r\n", rune(out[j])) {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// commandString returns a lifecycle command in string or array form as a
+// shell command
+func commandString(v interface{}) (string, bool) {
+	switch c := v.(type) {
+	case string:
+		return c, c != ""
+	case []interface{}:
+		var args []string
+		for _, arg := range c {
+			s := fmt.Sprint(arg)
+			if s == "" || strings.ContainsAny(s, " \t\n'\"$`\\|&;<>()*?") {
+				s = "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+			}
+			args = append(args, s)
+		}
+		return strings.Join(args, " "), len(args) > 0
+	}
+	return "", false
+}
+
+// lifecycleTasks converts a lifecycle command to tasks. The object form
+// runs commands in parallel in VS Code; as tasks they run in name order.
+func lifecycleTasks(v interface{}, name string, always bool) ([]Task, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if object, ok := v.(map[string]interface{}); ok {
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var tasks []Task
+		for _, key := range keys {
+			run, ok := commandString(object[key])
+			if !ok {
+				return nil, fmt.Errorf("%s: invalid command %q", name, key)
+			}
+			tasks = append(tasks, Task{Name: key, Run: run, Always: always})
+		}
+		return tasks, nil
+	}
+	run, ok := commandString(v)
+	if !ok {
+		return nil, fmt.Errorf("%s: invalid command", name)
+	}
+	return []Task{{Name: name, Run: run, Always: always}}, nil
+}
+
+var (
+	localEnvRef     = regexp.MustCompile(`\$\{localEnv:([A-Za-z_][A-Za-z0-9_]*)(:[^}]*)?\}`)
+	containerEnvRef = regexp.MustCompile(`\$\{containerEnv:[^}]*\}`)
+	envRef          = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// ImportDevcontainer converts devcontainer.json to a manifest of the
+// repository in dir. It returns what could not be converted as warnings.
+func ImportDevcontainer(data []byte, dir string) (*Manifest, []string, error) {
+	data = stripJSONC(data)
+	var dc Devcontainer
+	if err := json.Unmarshal(data, &dc); err != nil {
+		return nil, nil, fmt.Errorf("invalid devcontainer.json: %w", err)
+	}
+	var keys map[string]json.RawMessage
+	json.Unmarshal(data, &keys)
+
+	var warnings []string
+	var unknown []string
+	for key := range keys {
+		if !devcontainerKeys[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		switch key {
+		case "build", "dockerComposeFile":
+			warnings = append(warnings, fmt.Sprintf("%s is not supported; set container.image to a built image", key))
+		default:
+			warnings = append(warnings, fmt.Sprintf("%s is not converted", key))
+		}
+	}
+
+	m := &Manifest{Dir: dir, Container: &Container{Image: dc.Image, Workdir: dc.WorkspaceFolder}}
+	if dc.Name != "" {
+		m.Name = sanitizeName(dc.Name)
+	}
+
+	refs := make([]string, 0, len(dc.Features))
+	for ref := range dc.Features {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		id := featureID(ref)
+		tool := ""
+		for _, ft := range featureTools {
+			if ft.Feature == id {
+				tool = ft.Tool
+				break
+			}
+		}
+		if tool == "" {
+			warnings = append(warnings, fmt.Sprintf("feature %s has no portunix package", ref))
+			continue
+		}
+		version, maven := "", false
+		switch opts := dc.Features[ref].(type) {
+		case string:
+			version = opts
+		case map[string]interface{}:
+			if v, ok := opts["version"]; ok {
+				version = fmt.Sprint(v)
+			}
+			maven = id == "java" && fmt.Sprint(opts["installMaven"]) == "true"
+		}
+		if version == "latest" || version == "lts" || version == "none" || version == "os-provided" {
+			version = ""
+		}
+		if !seen[tool] {
+			seen[tool] = true
+			m.Tools = append(m.Tools, Tool{Name: tool, Version: version})
+		}
+		if maven && !seen["maven"] {
+			seen["maven"] = true
+			m.Tools = append(m.Tools, Tool{Name: "maven"})
+		}
+	}
+
+	for _, port := range dc.ForwardPorts {
+		switch p := port.(type) {
+		case float64:
+			m.Container.Ports = append(m.Container.Ports, fmt.Sprintf("%d:%d", int(p), int(p)))
+		default:
+			warnings = append(warnings, fmt.Sprintf("forwarded port %v of another container is not converted", p))
+		}
+	}
+
+	for _, env := range []map[string]string{dc.ContainerEnv, dc.RemoteEnv} {
+		for name, value := range env {
+			if containerEnvRef.MatchString(value) {
+				warnings = append(warnings, fmt.Sprintf("%s refers to the container environment and is not converted", name))
+				continue
+			}
+			if m.Env == nil {
+				m.Env = make(map[string]string)
+			}
+			m.Env[name] = localEnvRef.ReplaceAllString(value, "$${$1}")
+		}
+	}
+
+	for _, lc := range []struct {
+		value  interface{}
+		name   string
+		always bool
+	}{
+		{dc.OnCreateCommand, "on-create", false},
+		{dc.PostCreateCommand, "post-create", false},
+		{dc.PostStartCommand, "post-start", true},
+	} {
+		tasks, err := lifecycleTasks(lc.value, lc.name, lc.always)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, t := range tasks {
+			for _, existing := range m.Tasks {
+				if existing.Name == t.Name {
+					t.Name = lc.name + "-" + t.Name
+				}
+			}
+			m.Tasks = append(m.Tasks, t)
+		}
+	}
+
+	if m.Container.Image == "" {
+		m.Container.Image = DefaultContainerImage
+		warnings = append(warnings, "no image; using "+DefaultContainerImage)
+	}
+	if m.Name == "" {
+		m.Name = sanitizeName(filepath.Base(dir))
+	}
+	if m.Container.Workdir == "" {
+		m.Container.Workdir = "/workspaces/" + filepath.Base(dir)
+	}
+	if err := m.validate(); err != nil {
+		return nil, nil, err
+	}
+	return m, warnings, nil
+}
+
+// ExportDevcontainer converts a manifest to devcontainer.json for VS Code.
+// It returns what could not be converted as warnings.
+func ExportDevcontainer(m *Manifest) (*Devcontainer, []string) {
+	var warnings []string
+	dc := &Devcontainer{Name: m.Name, Image: DefaultContainerImage}
+	if m.Container != nil {
+		dc.Image = m.Container.Image
+		dc.WorkspaceFolder = m.Container.Workdir
+		for _, port := range m.Container.Ports {
+			container := port[strings.LastIndex(port, ":")+1:]
+			if n, err := strconv.Atoi(strings.TrimSuffix(container, "/tcp")); err == nil {
+				dc.ForwardPorts = append(dc.ForwardPorts, n)
+			} else {
+				warnings = append(warnings, fmt.Sprintf("port %s is not converted", port))
+			}
+		}
+	}
+
+	for _, t := range m.Tools {
+		ref := ""
+		for _, ft := range featureTools {
+			if ft.Tool == t.Name {
+				ref = ft.Ref
+				break
+			}
+		}
+		if ref == "" {
+			warnings = append(warnings, fmt.Sprintf("tool %s has no dev container feature", t.Name))
+			continue
+		}
+		if dc.Features == nil {
+			dc.Features = make(map[string]interface{})
+		}
+		opts := map[string]interface{}{}
+		if t.Version != "" {
+			opts["version"] = t.Version
+		}
+		dc.Features[ref] = opts
+	}
+
+	if len(m.Services) > 0 {
+		warnings = append(warnings, fmt.Sprintf("services need a compose setup and are not exported: %s", strings.Join(m.ServiceNames(), ", ")))
+	}
+
+	for name, value := range m.Env {
+		if dc.ContainerEnv == nil {
+			dc.ContainerEnv = make(map[string]string)
+		}
+		dc.ContainerEnv[name] = envRef.ReplaceAllString(value, "$${localEnv:$1}")
+	}
+
+	var once, always []Task
+	for _, t := range m.Tasks {
+		if t.Always {
+			always = append(always, t)
+		} else {
+			once = append(once, t)
+		}
+	}
+	dc.PostCreateCommand = exportTasks(once)
+	dc.PostStartCommand = exportTasks(always)
+	return dc, warnings
+}
+
+// exportTasks returns tasks as one lifecycle command. VS Code runs the
+// commands of the object form in parallel, so the tasks are chained to
+// keep their order.
+func exportTasks(tasks []Task) interface{} {
+	if len(tasks) == 0 {
+		return nil
+	}
+	runs := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		if len(tasks) > 1 && strings.ContainsAny(t.Run, "&|;") {
+			runs = append(runs, "("+t.Run+")")
+		} else {
+			runs = append(runs, t.Run)
+		}
+	}
+	return strings.Join(runs, " && ")
+}
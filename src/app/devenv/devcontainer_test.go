@@ -0,0 +1,114 @@
+package devenv
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDevcontainer = `// Generated by VS Code
+{
+	"name": "Shop API",
+	"image": "mcr.microsoft.com/devcontainers/go:1.24",
+	"features": {
+		"ghcr.io/devcontainers/features/go:1": {"version": "1.24"},
+		"ghcr.io/devcontainers/features/node:1": "20",
+		"ghcr.io/devcontainers/features/java:1": {"version": "latest", "installMaven": true},
+		"ghcr.io/example/features/unknown:1": {},
+	},
+	/* Ports of the API and the docs */
+	"forwardPorts": [8080, "db:5432"],
+	"containerEnv": {"GOFLAGS": "-mod=mod", "TOKEN": "${localEnv:GITHUB_TOKEN}"},
+	"remoteEnv": {"PATH": "${containerEnv:PATH}:/go/bin"},
+	"postCreateCommand": {"deps": "go mod download", "tools": ["go", "install", "./cmd/..."]},
+	"postStartCommand": "make serve // not a comment",
+	"customizations": {"vscode": {"extensions": ["golang.go"]}},
+}
+`
+
+func TestImportDevcontainer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "shop")
+	m, warnings, err := ImportDevcontainer([]byte(testDevcontainer), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "shop-api" {
+		t.Errorf("name = %q", m.Name)
+	}
+	wantTools := []Tool{{Name: "go", Version: "1.24"}, {Name: "java"}, {Name: "maven"}, {Name: "nodejs", Version: "20"}}
+	if !reflect.DeepEqual(m.Tools, wantTools) {
+		t.Errorf("tools = %+v", m.Tools)
+	}
+	wantContainer := &Container{
+		Image:   "mcr.microsoft.com/devcontainers/go:1.24",
+		Workdir: "/workspaces/shop",
+		Ports:   []string{"8080:8080"},
+	}
+	if !reflect.DeepEqual(m.Container, wantContainer) {
+		t.Errorf("container = %+v", m.Container)
+	}
+	wantEnv := map[string]string{"GOFLAGS": "-mod=mod", "TOKEN": "${GITHUB_TOKEN}"}
+	if !reflect.DeepEqual(m.Env, wantEnv) {
+		t.Errorf("env = %v", m.Env)
+	}
+	wantTasks := []Task{
+		{Name: "deps", Run: "go mod download"},
+		{Name: "tools", Run: "go install ./cmd/..."},
+		{Name: "post-start", Run: "make serve // not a comment", Always: true},
+	}
+	if !reflect.DeepEqual(m.Tasks, wantTasks) {
+		t.Errorf("tasks = %+v", m.Tasks)
+	}
+
+	report := strings.Join(warnings, "\n")
+	for _, want := range []string{"customizations", "unknown:1", "db:5432", "PATH"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("no warning about %s in:\n%s", want, report)
+		}
+	}
+
+	if _, _, err := ImportDevcontainer([]byte(`{"image": "alpine"`), dir); err == nil {
+		t.Error("invalid devcontainer.json accepted")
+	}
+}
+
+func TestExportDevcontainer(t *testing.T) {
+	m, err := ParseManifest([]byte(testManifest+"container:\n  image: golang:1.24\n  ports: [\"9000:8080\"]\n"), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Tasks = append(m.Tasks, Task{Name: "lint", Run: "make lint || true"})
+	m.Env["TOKEN"] = "${GITHUB_TOKEN}"
+
+	dc, warnings := ExportDevcontainer(m)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "postgres") {
+		t.Errorf("warnings = %v", warnings)
+	}
+	if dc.Image != "golang:1.24" || !reflect.DeepEqual(dc.ForwardPorts, []interface{}{8080}) {
+		t.Errorf("image %s, ports %v", dc.Image, dc.ForwardPorts)
+	}
+	if dc.ContainerEnv["TOKEN"] != "${localEnv:GITHUB_TOKEN}" {
+		t.Errorf("TOKEN = %s", dc.ContainerEnv["TOKEN"])
+	}
+	if dc.PostCreateCommand != "go mod download && (make lint || true)" || dc.PostStartCommand != "make migrate" {
+		t.Errorf("postCreate %v, postStart %v", dc.PostCreateCommand, dc.PostStartCommand)
+	}
+
+	// Importing the export gives back the environment
+	data, err := json.Marshal(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, _, err := ImportDevcontainer(data, m.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back.Tools, m.Tools) || !reflect.DeepEqual(back.Env, m.Env) {
+		t.Errorf("round trip: tools %+v, env %v", back.Tools, back.Env)
+	}
+	if back.Container.Image != "golang:1.24" || !reflect.DeepEqual(back.Container.Ports, []string{"8080:8080"}) {
+		t.Errorf("round trip: container %+v", back.Container)
+	}
+}
@@ -0,0 +1,356 @@
+package devenv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UpOptions controls 'portunix env up'
+type UpOptions struct {
+	// Container runs tools and tasks in a development container instead of
+	// on this machine
+	Container  bool
+	DryRun     bool
+	RerunTasks bool // Run tasks that already ran
+	SkipTools  bool
+	// Portunix is the portunix binary; default the running executable
+	Portunix string
+}
+
+// Task actions reported by Up
+const (
+	TaskRan     = "ran"
+	TaskSkipped = "skipped"
+	TaskPending = "pending"
+)
+
+// TaskResult is the outcome of a task
+type TaskResult struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
+// UpResult reports what Up did
+type UpResult struct {
+	Tools       int          `json:"tools"`
+	Services    []string     `json:"services,omitempty"`
+	ComposeFile string       `json:"compose_file,omitempty"`
+	Tasks       []TaskResult `json:"tasks,omitempty"`
+}
+
+// state is what 'env up' remembers about a clone
+type state struct {
+	// Tasks maps completed tasks to the hash of their command
+	Tasks map[string]string `json:"tasks"`
+}
+
+// runCommand runs a command with output to out; tests replace it
+var runCommand = defaultRunCommand
+
+func defaultRunCommand(dir string, env []string, out io.Writer, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
+
+// StateDir returns the directory of the generated files and state of the
+// environment, one per clone: ~/.portunix/env/<name>-<hash of path>
+func StateDir(m *Manifest) string {
+	sum := sha256.Sum256([]byte(m.Dir))
+	dir := m.Name + "-" + hex.EncodeToString(sum[:])[:8]
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-env", dir)
+	}
+	return filepath.Join(home, ".portunix", "env", dir)
+}
+
+// ProjectName returns the compose project of the environment
+func ProjectName(m *Manifest) string {
+	return "portunix-env-" + m.Name
+}
+
+type composeService struct {
+	Image       string            `yaml:"image"`
+	Command     []string          `yaml:"command,omitempty"`
+	WorkingDir  string            `yaml:"working_dir,omitempty"`
+	Environment map[string]string `yaml:"environment,omitempty"`
+	Ports       []string          `yaml:"ports,omitempty"`
+	Volumes     []string          `yaml:"volumes,omitempty"`
+	DependsOn   []string          `yaml:"depends_on,omitempty"`
+}
+
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+	Volumes  map[string]struct{}       `yaml:"volumes,omitempty"`
+}
+
+// isNamedVolume reports whether the source of a volume is a named volume
+// rather than a host path
+func isNamedVolume(source string) bool {
+	return !strings.ContainsAny(source, `/\`) && !strings.HasPrefix(source, ".") && !strings.HasPrefix(source, "~")
+}
+
+// ComposeFile returns the compose file running the services, and with
+// container the development container. Relative host paths of volumes are
+// relative to the repository.
+func ComposeFile(m *Manifest, container bool, portunixBinary string) ([]byte, error) {
+	compose := composeFile{Services: make(map[string]composeService)}
+	addVolumes := func(volumes []string) []string {
+		var result []string
+		for _, volume := range volumes {
+			source, target, ok := strings.Cut(volume, ":")
+			if ok && isNamedVolume(source) {
+				if compose.Volumes == nil {
+					compose.Volumes = make(map[string]struct{})
+				}
+				compose.Volumes[source] = struct{}{}
+			} else if ok && !filepath.IsAbs(source) && !strings.HasPrefix(source, "~") {
+				volume = filepath.Join(m.Dir, source) + ":" + target
+			}
+			result = append(result, volume)
+		}
+		return result
+	}
+
+	for _, name := range m.ServiceNames() {
+		s := m.Services[name]
+		compose.Services[name] = composeService{
+			Image:       s.Image,
+			Command:     s.Command,
+			Environment: s.Env,
+			Ports:       s.Ports,
+			Volumes:     addVolumes(s.Volumes),
+		}
+	}
+
+	if container {
+		c := m.Container
+		if c == nil {
+			c = &Container{Image: DefaultContainerImage, Workdir: "/workspace"}
+		}
+		volumes := []string{m.Dir + ":" + c.Workdir}
+		if portunixBinary != "" {
+			volumes = append(volumes, portunixBinary+":/usr/local/bin/portunix:ro")
+		}
+		compose.Services[WorkspaceService] = composeService{
+			Image:       c.Image,
+			Command:     []string{"sleep", "infinity"},
+			WorkingDir:  c.Workdir,
+			Environment: m.Environment(),
+			Ports:       c.Ports,
+			Volumes:     volumes,
+			DependsOn:   m.ServiceNames(),
+		}
+	}
+
+	data, err := yaml.Marshal(compose)
+	if err != nil {
+		return nil, err
+	}
+	header := fmt.Sprintf("# Development environment %s, generated by 'portunix env up' from %s\n",
+		m.Name, filepath.Join(m.Dir, ManifestFile))
+	return append([]byte(header), data...), nil
+}
+
+// toolsManifest returns the tools as a 'portunix install apply' manifest
+func toolsManifest(m *Manifest) ([]byte, error) {
+	return yaml.Marshal(map[string][]Tool{"packages": m.Tools})
+}
+
+// taskHash identifies the command of a task, so a changed task runs again
+func taskHash(t Task) string {
+	sum := sha256.Sum256([]byte(t.Run))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func loadState(stateDir string) *state {
+	s := &state{Tasks: make(map[string]string)}
+	if data, err := os.ReadFile(filepath.Join(stateDir, "state.json")); err == nil {
+		json.Unmarshal(data, s)
+	}
+	if s.Tasks == nil {
+		s.Tasks = make(map[string]string)
+	}
+	return s
+}
+
+func saveState(stateDir string, s *state) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(stateDir, "state.json"), data, 0644)
+}
+
+// environ returns the environment of host tasks: the current environment
+// with the env section of the manifest
+func environ(m *Manifest) []string {
+	env := os.Environ()
+	for name, value := range m.Environment() {
+		env = append(env, name+"="+value)
+	}
+	return env
+}
+
+// Up converges the machine, or with opts.Container a development
+// container, to the manifest: tools are installed with 'portunix install
+// apply', services are started with compose and tasks that have not run
+// in this clone are run in the repository root.
+func Up(m *Manifest, opts UpOptions, out io.Writer) (*UpResult, error) {
+	portunix := opts.Portunix
+	if portunix == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate portunix: %w", err)
+		}
+		portunix = exe
+	}
+	stateDir := StateDir(m)
+	result := &UpResult{Services: m.ServiceNames()}
+	st := loadState(stateDir)
+
+	for _, t := range m.Tasks {
+		action := TaskPending
+		if !t.Always && !opts.RerunTasks && st.Tasks[t.Name] == taskHash(t) {
+			action = TaskSkipped
+		}
+		result.Tasks = append(result.Tasks, TaskResult{Name: t.Name, Action: action})
+	}
+
+	// The development container mounts this binary, which only runs there
+	// when it is a Linux one
+	containerBinary := ""
+	if opts.Container && runtime.GOOS == "linux" {
+		containerBinary = portunix
+	}
+	if opts.Container && containerBinary == "" && len(m.Tools) > 0 && !opts.SkipTools {
+		return nil, fmt.Errorf("installing tools in the development container needs a Linux host; use an image with the tools and --skip-tools")
+	}
+	if !opts.SkipTools {
+		result.Tools = len(m.Tools)
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", stateDir, err)
+	}
+
+	compose := func(args ...string) error {
+		base := []string{"container", "compose", "-f", result.ComposeFile, "-p", ProjectName(m)}
+		return runCommand(m.Dir, os.Environ(), out, portunix, append(base, args...)...)
+	}
+	hasCompose := len(m.Services) > 0 || opts.Container
+	if hasCompose {
+		data, err := ComposeFile(m, opts.Container, containerBinary)
+		if err != nil {
+			return nil, err
+		}
+		result.ComposeFile = filepath.Join(stateDir, "docker-compose.yaml")
+		if err := os.WriteFile(result.ComposeFile, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write compose file: %w", err)
+		}
+	}
+
+	// Tools first on the host, so services and tasks can use them; in the
+	// container after it started
+	tools, err := toolsManifest(m)
+	if err != nil {
+		return nil, err
+	}
+	if result.Tools > 0 && !opts.Container {
+		fmt.Fprintf(out, "🔧 Installing tools (%d)...\n", result.Tools)
+		toolsPath := filepath.Join(stateDir, "tools.yaml")
+		if err := os.WriteFile(toolsPath, tools, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write tools manifest: %w", err)
+		}
+		if err := runCommand(m.Dir, os.Environ(), out, portunix, "install", "apply", toolsPath); err != nil {
+			return nil, fmt.Errorf("installing tools failed: %w", err)
+		}
+	}
+
+	if hasCompose {
+		fmt.Fprintf(out, "🐳 Starting services (%s)...\n", strings.Join(composeServices(m, opts.Container), ", "))
+		if err := compose("up", "-d"); err != nil {
+			return nil, fmt.Errorf("starting services failed: %w", err)
+		}
+	}
+
+	if result.Tools > 0 && opts.Container {
+		fmt.Fprintf(out, "🔧 Installing tools in the development container (%d)...\n", result.Tools)
+		script := "cat > /tmp/portunix-tools.yaml <<'EOF'\n" + string(tools) + "EOF\nportunix install apply /tmp/portunix-tools.yaml"
+		if err := compose("exec", "-T", WorkspaceService, "sh", "-c", script); err != nil {
+			return nil, fmt.Errorf("installing tools failed: %w", err)
+		}
+	}
+
+	for i, t := range m.Tasks {
+		if result.Tasks[i].Action == TaskSkipped {
+			continue
+		}
+		fmt.Fprintf(out, "▶ Task %s: %s\n", t.Name, t.Run)
+		var err error
+		switch {
+		case opts.Container:
+			err = compose("exec", "-T", WorkspaceService, "sh", "-c", t.Run)
+		case runtime.GOOS == "windows":
+			err = runCommand(m.Dir, environ(m), out, "cmd", "/C", t.Run)
+		default:
+			err = runCommand(m.Dir, environ(m), out, "sh", "-c", t.Run)
+		}
+		if err != nil {
+			return result, fmt.Errorf("task %s failed: %w", t.Name, err)
+		}
+		result.Tasks[i].Action = TaskRan
+		if !t.Always {
+			st.Tasks[t.Name] = taskHash(t)
+			if err := saveState(stateDir, st); err != nil {
+				return result, fmt.Errorf("failed to save state: %w", err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// composeServices returns the compose services of the environment
+func composeServices(m *Manifest, container bool) []string {
+	services := m.ServiceNames()
+	if container {
+		services = append(services, WorkspaceService)
+	}
+	return services
+}
+
+// Down stops and removes the containers of the environment. Named volumes
+// are kept unless volumes is set.
+func Down(m *Manifest, volumes bool, out io.Writer) error {
+	composePath := filepath.Join(StateDir(m), "docker-compose.yaml")
+	if _, err := os.Stat(composePath); err != nil {
+		return fmt.Errorf("environment %s has no services started by 'portunix env up'", m.Name)
+	}
+	portunix, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate portunix: %w", err)
+	}
+	args := []string{"container", "compose", "-f", composePath, "-p", ProjectName(m), "down"}
+	if volumes {
+		args = append(args, "--volumes")
+	}
+	return runCommand(m.Dir, os.Environ(), out, portunix, args...)
+}
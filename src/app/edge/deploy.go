@@ -0,0 +1,335 @@
+package edge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"portunix.ai/app/shellquote"
+)
+
+// DeployOptions configures a VPS edge deployment
+type DeployOptions struct {
+	Name       string
+	Template   string
+	Region     string // provider default when empty
+	Size       string
+	Image      string
+	Domain     string // served by Caddy with automatic TLS; ":80" when empty
+	AdminEmail string // ACME account email
+	// UserData is cloud-init user-data for a new server, e.g. rendered from
+	// the edge-proxy template of the cloud-init library
+	UserData string
+	DryRun   bool
+}
+
+// Deployer provisions a VPS and installs an edge template on it
+type Deployer struct {
+	Provider Provider
+	Out      io.Writer
+}
+
+var domainPattern = regexp.MustCompile(`^(\*\.)?([a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?\.)+[a-zA-Z]{2,}$`)
+
+const (
+	defaultWireGuardPort    = 51820
+	defaultWireGuardNetwork = "10.100.0.0/24"
+	defaultWireGuardServer  = "10.100.0.1"
+	defaultWireGuardClient  = "10.100.0.2"
+)
+
+// Deploy provisions the VPS (or reuses the one of an earlier, interrupted
+// run of the same deployment), waits for SSH and runs the template's
+// ptxbook on it. State is saved after every step.
+func (dp *Deployer) Deploy(ctx context.Context, opts DeployOptions) (*Deployment, error) {
+	if err := ValidateDeploymentName(opts.Name); err != nil {
+		return nil, err
+	}
+	tmpl, err := GetTemplate(opts.Template)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Domain != "" && !domainPattern.MatchString(opts.Domain) {
+		return nil, fmt.Errorf("invalid domain: %s", opts.Domain)
+	}
+	if opts.AdminEmail != "" {
+		if _, err := mail.ParseAddress(opts.AdminEmail); err != nil || strings.ContainsAny(opts.AdminEmail, " <>") {
+			return nil, fmt.Errorf("invalid admin email: %s", opts.AdminEmail)
+		}
+	}
+
+	d, err := LoadDeployment(opts.Name)
+	if err != nil {
+		d = dp.newDeployment(opts)
+	} else if d.Provider != dp.Provider.Name() {
+		return nil, fmt.Errorf("deployment %s already exists on %s", d.Name, d.Provider)
+	} else {
+		fmt.Fprintf(dp.Out, "Resuming deployment %s (%s)\n", d.Name, d.Status)
+		if opts.Domain != "" {
+			d.Domain = opts.Domain
+		}
+		if opts.AdminEmail != "" {
+			d.AdminEmail = opts.AdminEmail
+		}
+	}
+
+	if opts.DryRun {
+		dp.printPlan(d, tmpl)
+		if opts.UserData != "" {
+			fmt.Fprintf(dp.Out, "  Cloud-init: %d bytes of user-data\n", len(opts.UserData))
+		}
+		return d, nil
+	}
+
+	// 1. SSH key
+	fmt.Fprintln(dp.Out, "[1/5] Preparing SSH key...")
+	publicKey, err := EnsureSSHKey(d.SSHKey)
+	if err != nil {
+		return d, err
+	}
+	if err := d.Save(); err != nil {
+		return d, err
+	}
+
+	// 2. Server
+	if d.ServerID == "" {
+		fmt.Fprintf(dp.Out, "[2/5] Creating %s server %s (%s, %s, %s)...\n", d.Provider, d.Name, d.Region, d.Size, d.Image)
+		keyRef, err := dp.Provider.ImportSSHKey(ctx, "portunix-edge-"+d.Name, publicKey)
+		if err != nil {
+			return d, dp.fail(d, err)
+		}
+		server, err := dp.Provider.CreateServer(ctx, ServerSpec{
+			Name:     d.Name,
+			Region:   d.Region,
+			Size:     d.Size,
+			Image:    d.Image,
+			SSHKeys:  []string{keyRef},
+			UserData: opts.UserData,
+		})
+		if err != nil {
+			return d, dp.fail(d, err)
+		}
+		d.ServerID = server.ID
+		d.Status = StatusProvisioning
+		if err := d.Save(); err != nil {
+			return d, err
+		}
+	} else {
+		fmt.Fprintf(dp.Out, "[2/5] Using existing server %s\n", d.ServerID)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+	server, err := WaitForServer(waitCtx, dp.Provider, d.ServerID, 5*time.Second)
+	if err != nil {
+		return d, dp.fail(d, err)
+	}
+	d.PublicIP = server.PublicIP
+	d.Status = StatusProvisioned
+	if err := d.Save(); err != nil {
+		return d, err
+	}
+	fmt.Fprintf(dp.Out, "      Server running at %s\n", d.PublicIP)
+
+	// 3. Render template
+	fmt.Fprintf(dp.Out, "[3/5] Rendering template %s...\n", tmpl.Name)
+	files, err := dp.render(d, tmpl)
+	if err != nil {
+		return d, dp.fail(d, err)
+	}
+
+	// 4. Upload
+	fmt.Fprintln(dp.Out, "[4/5] Waiting for SSH and uploading configuration...")
+	client, err := WaitForSSH(d, 5*time.Minute)
+	if err != nil {
+		return d, dp.fail(d, err)
+	}
+	defer client.Close()
+	// Persist the host key recorded on first connection
+	if err := d.Save(); err != nil {
+		return d, err
+	}
+	for name, data := range files {
+		file := templateFile(tmpl, name)
+		if err := client.Upload(path.Join(RemoteDir, name), data, file.Mode); err != nil {
+			return d, dp.fail(d, err)
+		}
+	}
+
+	// 5. Run the ptxbook
+	fmt.Fprintf(dp.Out, "[5/5] Running %s (deploy)...\n", PtxbookFile)
+	if err := RunPtxbookScript(client, files[PtxbookFile], "deploy", dp.Out); err != nil {
+		return d, dp.fail(d, err)
+	}
+
+	d.Status = StatusDeployed
+	d.Error = ""
+	d.DeployedAt = time.Now()
+	if err := d.Save(); err != nil {
+		return d, err
+	}
+	return d, nil
+}
+
+// RunPtxbookScript uploads a script of the ptxbook to the edge host and
+// runs it there with bash, streaming its output
+func RunPtxbookScript(client *SSHClient, ptxbook []byte, name string, out io.Writer) error {
+	script, err := PtxbookScript(ptxbook, name)
+	if err != nil {
+		return err
+	}
+	remote := path.Join(RemoteDir, name+".sh")
+	if err := client.Upload(remote, []byte(script), 0700); err != nil {
+		return err
+	}
+	if err := client.Run("bash "+shellquote.Quote(remote), nil, out, out); err != nil {
+		return fmt.Errorf("ptxbook script %s failed on edge host: %w", name, err)
+	}
+	return nil
+}
+
+// Destroy deletes the VPS of a deployment and its local state
+func (dp *Deployer) Destroy(ctx context.Context, d *Deployment) error {
+	if d.ServerID != "" {
+		fmt.Fprintf(dp.Out, "Deleting %s server %s (%s)...\n", d.Provider, d.ServerID, d.PublicIP)
+		if err := dp.Provider.DeleteServer(ctx, d.ServerID); err != nil {
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 {
+				return err
+			}
+		}
+	}
+	return RemoveDeployment(d.Name)
+}
+
+func (dp *Deployer) newDeployment(opts DeployOptions) *Deployment {
+	defaults := dp.Provider.Defaults()
+	d := &Deployment{
+		Name:       opts.Name,
+		Provider:   dp.Provider.Name(),
+		Template:   opts.Template,
+		Region:     firstNonEmpty(opts.Region, defaults.Region),
+		Size:       firstNonEmpty(opts.Size, defaults.Size),
+		Image:      firstNonEmpty(opts.Image, defaults.Image),
+		SSHUser:    "root",
+		SSHKey:     filepath.Join(DeploymentDir(opts.Name), "id_ed25519"),
+		Domain:     opts.Domain,
+		AdminEmail: opts.AdminEmail,
+		WireGuard: WireGuardState{
+			Port:     defaultWireGuardPort,
+			Network:  defaultWireGuardNetwork,
+			ServerIP: defaultWireGuardServer,
+			ClientIP: defaultWireGuardClient,
+		},
+		CreatedAt: time.Now(),
+	}
+	return d
+}
+
+// render generates WireGuard keys on first use, writes local-only files to
+// the deployment directory and returns the files to upload
+func (dp *Deployer) render(d *Deployment, tmpl DeployTemplate) (map[string][]byte, error) {
+	clientPrivateKey := ""
+	if d.WireGuard.ServerPrivateKey == "" {
+		server, err := GenerateWireGuardKeyPair()
+		if err != nil {
+			return nil, err
+		}
+		client, err := GenerateWireGuardKeyPair()
+		if err != nil {
+			return nil, err
+		}
+		d.WireGuard.ServerPrivateKey = server.PrivateKey
+		d.WireGuard.ServerPublicKey = server.PublicKey
+		d.WireGuard.ClientPublicKey = client.PublicKey
+		clientPrivateKey = client.PrivateKey
+	}
+
+	remote := map[string][]byte{}
+	for _, file := range tmpl.Files {
+		if !file.Remote {
+			local := filepath.Join(DeploymentDir(d.Name), file.Name)
+			if clientPrivateKey == "" {
+				// Rendered on the first run; the client key is not stored
+				continue
+			}
+			data, err := tmpl.Render(file, d, clientPrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(local, data, file.Mode); err != nil {
+				return nil, err
+			}
+			if file.Name == "wg-client.conf" {
+				d.WireGuard.ClientConfig = local
+			}
+			continue
+		}
+		data, err := tmpl.Render(file, d, "")
+		if err != nil {
+			return nil, err
+		}
+		remote[file.Name] = data
+		// Keep a local copy so the deployment can be inspected and re-run
+		if err := os.WriteFile(filepath.Join(DeploymentDir(d.Name), file.Name), data, file.Mode); err != nil {
+			return nil, err
+		}
+	}
+	return remote, d.Save()
+}
+
+func (dp *Deployer) printPlan(d *Deployment, tmpl DeployTemplate) {
+	fmt.Fprintln(dp.Out, "Dry run - nothing will be created")
+	fmt.Fprintf(dp.Out, "  Deployment: %s\n", d.Name)
+	fmt.Fprintf(dp.Out, "  Provider:   %s (region %s, size %s, image %s)\n", d.Provider, d.Region, d.Size, d.Image)
+	fmt.Fprintf(dp.Out, "  Template:   %s - %s\n", tmpl.Name, tmpl.Description)
+	if d.Domain != "" {
+		fmt.Fprintf(dp.Out, "  Domain:     %s\n", d.Domain)
+	}
+	fmt.Fprintf(dp.Out, "  WireGuard:  %s, port %d/udp\n", d.WireGuard.Network, d.WireGuard.Port)
+	fmt.Fprintf(dp.Out, "  State:      %s\n", DeploymentDir(d.Name))
+	fmt.Fprintln(dp.Out, "  Files:")
+	for _, file := range tmpl.Files {
+		if file.Remote {
+			fmt.Fprintf(dp.Out, "    %s -> %s\n", file.Name, path.Join(RemoteDir, file.Name))
+		} else {
+			fmt.Fprintf(dp.Out, "    %s (local)\n", file.Name)
+		}
+	}
+}
+
+// fail records the error in the deployment state
+func (dp *Deployer) fail(d *Deployment, err error) error {
+	d.Error = err.Error()
+	if d.Status != StatusDeployed {
+		d.Status = StatusFailed
+	}
+	d.Save()
+	return err
+}
+
+func templateFile(tmpl DeployTemplate, name string) TemplateFile {
+	for _, file := range tmpl.Files {
+		if file.Name == name {
+			return file
+		}
+	}
+	return TemplateFile{Name: name, Mode: 0644}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+package edge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// digitalOceanProvider talks to the DigitalOcean API
+type digitalOceanProvider struct {
+	api *apiClient
+}
+
+type digitalOceanDroplet struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Networks struct {
+		V4 []struct {
+			IPAddress string `json:"ip_address"`
+			Type      string `json:"type"`
+		} `json:"v4"`
+	} `json:"networks"`
+}
+
+func (d digitalOceanDroplet) toServer() *Server {
+	server := &Server{
+		ID:     strconv.FormatInt(d.ID, 10),
+		Name:   d.Name,
+		Status: d.Status,
+		Ready:  d.Status == "active",
+	}
+	for _, network := range d.Networks.V4 {
+		if network.Type == "public" {
+			server.PublicIP = network.IPAddress
+			break
+		}
+	}
+	return server
+}
+
+func (p *digitalOceanProvider) Name() string {
+	return "digitalocean"
+}
+
+func (p *digitalOceanProvider) Defaults() ServerSpec {
+	return ServerSpec{Region: "fra1", Size: "s-1vcpu-1gb", Image: "ubuntu-24-04-x64"}
+}
+
+// ImportSSHKey uploads the key and returns its fingerprint, which droplet
+// creation accepts in place of the key ID
+func (p *digitalOceanProvider) ImportSSHKey(ctx context.Context, name, publicKey string) (string, error) {
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return "", fmt.Errorf("invalid SSH public key: %w", err)
+	}
+	fingerprint := ssh.FingerprintLegacyMD5(pub)
+
+	err = p.api.do(ctx, http.MethodPost, "/account/keys", map[string]string{"name": name, "public_key": publicKey}, nil)
+	if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusUnprocessableEntity {
+		// Key already uploaded by an earlier deployment
+		err = nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to upload SSH key: %w", err)
+	}
+	return fingerprint, nil
+}
+
+func (p *digitalOceanProvider) CreateServer(ctx context.Context, spec ServerSpec) (*Server, error) {
+	body := map[string]interface{}{
+		"name":     spec.Name,
+		"region":   spec.Region,
+		"size":     spec.Size,
+		"image":    spec.Image,
+		"ssh_keys": spec.SSHKeys,
+		"tags":     []string{"portunix-edge"},
+	}
+	if spec.UserData != "" {
+		body["user_data"] = spec.UserData
+	}
+	var created struct {
+		Droplet digitalOceanDroplet `json:"droplet"`
+	}
+	if err := p.api.do(ctx, http.MethodPost, "/droplets", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create droplet: %w", err)
+	}
+	return created.Droplet.toServer(), nil
+}
+
+func (p *digitalOceanProvider) GetServer(ctx context.Context, id string) (*Server, error) {
+	var resp struct {
+		Droplet digitalOceanDroplet `json:"droplet"`
+	}
+	if err := p.api.do(ctx, http.MethodGet, "/droplets/"+url.PathEscape(id), nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get droplet %s: %w", id, err)
+	}
+	return resp.Droplet.toServer(), nil
+}
+
+func (p *digitalOceanProvider) DeleteServer(ctx context.Context, id string) error {
+	if err := p.api.do(ctx, http.MethodDelete, "/droplets/"+url.PathEscape(id), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete droplet %s: %w", id, err)
+	}
+	return nil
+}
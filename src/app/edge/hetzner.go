@@ -0,0 +1,119 @@
+package edge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// hetznerProvider talks to the Hetzner Cloud API
+type hetznerProvider struct {
+	api *apiClient
+}
+
+type hetznerServer struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	PublicNet struct {
+		IPv4 struct {
+			IP string `json:"ip"`
+		} `json:"ipv4"`
+	} `json:"public_net"`
+}
+
+func (s hetznerServer) toServer() *Server {
+	return &Server{
+		ID:       strconv.FormatInt(s.ID, 10),
+		Name:     s.Name,
+		Status:   s.Status,
+		PublicIP: s.PublicNet.IPv4.IP,
+		Ready:    s.Status == "running",
+	}
+}
+
+func (p *hetznerProvider) Name() string {
+	return "hetzner"
+}
+
+func (p *hetznerProvider) Defaults() ServerSpec {
+	return ServerSpec{Region: "fsn1", Size: "cx22", Image: "ubuntu-24.04"}
+}
+
+func (p *hetznerProvider) ImportSSHKey(ctx context.Context, name, publicKey string) (string, error) {
+	var created struct {
+		SSHKey struct {
+			ID int64 `json:"id"`
+		} `json:"ssh_key"`
+	}
+	err := p.api.do(ctx, http.MethodPost, "/ssh_keys", map[string]string{"name": name, "public_key": publicKey}, &created)
+	if err == nil {
+		return strconv.FormatInt(created.SSHKey.ID, 10), nil
+	}
+	if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusConflict {
+		return "", fmt.Errorf("failed to upload SSH key: %w", err)
+	}
+
+	// Key already uploaded by an earlier deployment
+	var existing struct {
+		SSHKeys []struct {
+			ID int64 `json:"id"`
+		} `json:"ssh_keys"`
+	}
+	if err := p.api.do(ctx, http.MethodGet, "/ssh_keys?name="+url.QueryEscape(name), nil, &existing); err != nil {
+		return "", fmt.Errorf("failed to look up SSH key: %w", err)
+	}
+	if len(existing.SSHKeys) == 0 {
+		return "", fmt.Errorf("SSH key %s exists but could not be found", name)
+	}
+	return strconv.FormatInt(existing.SSHKeys[0].ID, 10), nil
+}
+
+func (p *hetznerProvider) CreateServer(ctx context.Context, spec ServerSpec) (*Server, error) {
+	keys := make([]int64, 0, len(spec.SSHKeys))
+	for _, key := range spec.SSHKeys {
+		id, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Hetzner SSH key ID %q", key)
+		}
+		keys = append(keys, id)
+	}
+
+	body := map[string]interface{}{
+		"name":        spec.Name,
+		"server_type": spec.Size,
+		"image":       spec.Image,
+		"location":    spec.Region,
+		"ssh_keys":    keys,
+		"labels":      map[string]string{"managed-by": "portunix"},
+	}
+	if spec.UserData != "" {
+		body["user_data"] = spec.UserData
+	}
+	var created struct {
+		Server hetznerServer `json:"server"`
+	}
+	if err := p.api.do(ctx, http.MethodPost, "/servers", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create server: %w", err)
+	}
+	return created.Server.toServer(), nil
+}
+
+func (p *hetznerProvider) GetServer(ctx context.Context, id string) (*Server, error) {
+	var resp struct {
+		Server hetznerServer `json:"server"`
+	}
+	if err := p.api.do(ctx, http.MethodGet, "/servers/"+url.PathEscape(id), nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get server %s: %w", id, err)
+	}
+	return resp.Server.toServer(), nil
+}
+
+func (p *hetznerProvider) DeleteServer(ctx context.Context, id string) error {
+	if err := p.api.do(ctx, http.MethodDelete, "/servers/"+url.PathEscape(id), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete server %s: %w", id, err)
+	}
+	return nil
+}
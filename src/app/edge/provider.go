@@ -0,0 +1,220 @@
+package edge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"portunix.ai/app/secret"
+)
+
+// Provider provisions VPS instances through a cloud provider API
+type Provider interface {
+	// Name returns the provider identifier, e.g. "hetzner"
+	Name() string
+	// Defaults returns the region, size and image used when not specified
+	Defaults() ServerSpec
+	// ImportSSHKey registers a public key and returns the reference to pass
+	// in ServerSpec.SSHKeys; an already registered key is reused
+	ImportSSHKey(ctx context.Context, name, publicKey string) (string, error)
+	CreateServer(ctx context.Context, spec ServerSpec) (*Server, error)
+	GetServer(ctx context.Context, id string) (*Server, error)
+	DeleteServer(ctx context.Context, id string) error
+}
+
+// ServerSpec describes a VPS to create
+type ServerSpec struct {
+	Name    string
+	Region  string
+	Size    string
+	Image   string
+	SSHKeys []string
+	// UserData is cloud-init user-data run on first boot
+	UserData string
+}
+
+// Server is a VPS as reported by the provider
+type Server struct {
+	ID       string
+	Name     string
+	Status   string
+	PublicIP string
+	Ready    bool // running and reachable from the network
+}
+
+// providerInfo describes a supported provider
+type providerInfo struct {
+	tokenEnv string
+	baseURL  string
+	create   func(api *apiClient) Provider
+}
+
+var providers = map[string]providerInfo{
+	"hetzner": {
+		tokenEnv: "HCLOUD_TOKEN",
+		baseURL:  "https://api.hetzner.cloud/v1",
+		create:   func(api *apiClient) Provider { return &hetznerProvider{api: api} },
+	},
+	"digitalocean": {
+		tokenEnv: "DIGITALOCEAN_TOKEN",
+		baseURL:  "https://api.digitalocean.com/v2",
+		create:   func(api *apiClient) Provider { return &digitalOceanProvider{api: api} },
+	},
+}
+
+// ProviderNames returns the supported provider identifiers
+func ProviderNames() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProviderTokenEnv returns the environment variable holding the API token
+func ProviderTokenEnv(name string) string {
+	return providers[name].tokenEnv
+}
+
+// ProviderSecretName returns the name of the provider's API token in the
+// portunix secret store
+func ProviderSecretName(name string) string {
+	return "edge/" + name
+}
+
+// NewProvider creates a provider client authenticated with the API token
+// from the provider's environment variable, or the secret store when unset
+func NewProvider(name string) (Provider, error) {
+	info, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider: %s (supported: %v)", name, ProviderNames())
+	}
+	token := os.Getenv(info.tokenEnv)
+	if token == "" {
+		// An unavailable secret store counts as no stored token
+		stored, found, err := secret.Lookup(ProviderSecretName(name), "edge")
+		if !found {
+			msg := fmt.Sprintf("%s API token not set; export %s=<token> or run 'portunix secret set %s'",
+				name, info.tokenEnv, ProviderSecretName(name))
+			if err != nil {
+				return nil, fmt.Errorf("%s (secret store: %v)", msg, err)
+			}
+			return nil, fmt.Errorf("%s", msg)
+		}
+		token = stored
+	}
+	return NewProviderWithURL(name, token, info.baseURL)
+}
+
+// NewProviderWithURL creates a provider client for a specific API endpoint
+func NewProviderWithURL(name, token, baseURL string) (Provider, error) {
+	info, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider: %s (supported: %v)", name, ProviderNames())
+	}
+	api := &apiClient{
+		baseURL: baseURL,
+		token:   token,
+		http:    &http.Client{Timeout: 60 * time.Second},
+	}
+	return info.create(api), nil
+}
+
+// WaitForServer polls the provider until the server is ready and has a
+// public address
+func WaitForServer(ctx context.Context, p Provider, id string, interval time.Duration) (*Server, error) {
+	for {
+		server, err := p.GetServer(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if server.Ready && server.PublicIP != "" {
+			return server, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for server %s (status %s)", id, server.Status)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// APIError is an error response of a provider API
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// apiClient performs authenticated JSON requests against a provider API
+type apiClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func (c *apiClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return &APIError{StatusCode: resp.StatusCode, Message: apiErrorMessage(data)}
+	}
+	if out != nil && len(data) > 0 {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+// apiErrorMessage extracts the message of Hetzner ({"error":{"message"}})
+// and DigitalOcean ({"message"}) error bodies
+func apiErrorMessage(data []byte) string {
+	var body struct {
+		Message string `json:"message"`
+		Error   struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		if body.Error.Message != "" {
+			return body.Error.Message
+		}
+		if body.Message != "" {
+			return body.Message
+		}
+	}
+	return string(bytes.TrimSpace(data))
+}
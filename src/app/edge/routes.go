@@ -0,0 +1,203 @@
+package edge
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Route forwards traffic arriving at the edge host to a target reachable
+// from it, usually a machine on the WireGuard tunnel
+type Route struct {
+	Domain string `json:"domain"`
+	Target string `json:"target"` // host:port
+	// TCPPort is the public port of a raw TCP route proxied by HAProxy;
+	// zero for HTTP(S) routes served by Caddy with automatic TLS
+	TCPPort   int       `json:"tcp_port,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Protocol returns "https" or "tcp"
+func (r Route) Protocol() string {
+	if r.TCPPort != 0 {
+		return "tcp"
+	}
+	return "https"
+}
+
+// Files generated from the routes of a deployment
+const (
+	caddyRoutesFile   = "routes.caddy"
+	haproxyConfigFile = "haproxy.cfg"
+)
+
+var targetHostPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9.-]*[a-zA-Z0-9])?$`)
+
+// reservedTCPPorts are used by the edge host itself
+var reservedTCPPorts = map[int]string{22: "SSH", 80: "HTTP", 443: "HTTPS"}
+
+// ValidateRoute checks a route before it is added to a deployment
+func ValidateRoute(r Route) error {
+	if !domainPattern.MatchString(r.Domain) {
+		return fmt.Errorf("invalid domain: %s", r.Domain)
+	}
+	host, port, err := net.SplitHostPort(r.Target)
+	if err != nil {
+		return fmt.Errorf("invalid target %q: expected host:port", r.Target)
+	}
+	if net.ParseIP(host) == nil && !targetHostPattern.MatchString(host) {
+		return fmt.Errorf("invalid target host: %s", host)
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return fmt.Errorf("invalid target port: %s", port)
+	}
+	if r.TCPPort != 0 {
+		if r.TCPPort < 1 || r.TCPPort > 65535 {
+			return fmt.Errorf("invalid TCP port: %d", r.TCPPort)
+		}
+		if use, ok := reservedTCPPorts[r.TCPPort]; ok {
+			return fmt.Errorf("TCP port %d is reserved for %s on the edge host", r.TCPPort, use)
+		}
+	}
+	return nil
+}
+
+// SetRoute adds a route to the deployment or replaces the target of an
+// existing route with the same domain and protocol. It returns false when
+// the route was replaced.
+func (d *Deployment) SetRoute(r Route) (bool, error) {
+	if err := ValidateRoute(r); err != nil {
+		return false, err
+	}
+	for i, existing := range d.Routes {
+		if existing.Domain == r.Domain && existing.TCPPort == r.TCPPort {
+			d.Routes[i].Target = r.Target
+			return false, nil
+		}
+		if r.TCPPort != 0 && existing.TCPPort == r.TCPPort {
+			return false, fmt.Errorf("TCP port %d is already used by %s", r.TCPPort, existing.Domain)
+		}
+	}
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+	d.Routes = append(d.Routes, r)
+	sort.Slice(d.Routes, func(i, j int) bool {
+		if d.Routes[i].Domain != d.Routes[j].Domain {
+			return d.Routes[i].Domain < d.Routes[j].Domain
+		}
+		return d.Routes[i].TCPPort < d.Routes[j].TCPPort
+	})
+	return true, nil
+}
+
+// RemoveRoute removes the routes of a domain; with a non-zero tcpPort only
+// that TCP route is removed. It returns the number of removed routes.
+func (d *Deployment) RemoveRoute(domain string, tcpPort int) int {
+	kept := d.Routes[:0]
+	removed := 0
+	for _, r := range d.Routes {
+		if r.Domain == domain && (tcpPort == 0 || r.TCPPort == tcpPort) {
+			removed++
+			continue
+		}
+		kept = append(kept, r)
+	}
+	d.Routes = kept
+	return removed
+}
+
+// caddyAccessLog is the access log of the routed sites
+const caddyAccessLog = "/var/log/caddy/access.log"
+
+// RenderCaddyRoutes returns the Caddy site blocks of the HTTP routes, which
+// the edge Caddyfile imports from /etc/caddy/routes.d
+func RenderCaddyRoutes(d *Deployment) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Managed by portunix edge (%s) - changes are overwritten by 'portunix edge route'\n", d.Name)
+	for _, r := range d.Routes {
+		if r.TCPPort != 0 {
+			continue
+		}
+		// JSON access logs let 'portunix harden fail2ban' ban abusive clients
+		fmt.Fprintf(&b, "\n%s {\n\treverse_proxy %s\n\tlog {\n\t\toutput file %s\n\t}\n}\n", r.Domain, r.Target, caddyAccessLog)
+	}
+	return b.Bytes()
+}
+
+// RenderHAProxyConfig returns the HAProxy configuration of the TCP routes,
+// or nil when the deployment has none
+func RenderHAProxyConfig(d *Deployment) []byte {
+	var b bytes.Buffer
+	for _, r := range d.Routes {
+		if r.TCPPort == 0 {
+			continue
+		}
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "# Managed by portunix edge (%s) - changes are overwritten by 'portunix edge route'\n", d.Name)
+			b.WriteString("global\n\tlog /dev/log local0\n\tmaxconn 4096\n\n")
+			b.WriteString("defaults\n\tmode tcp\n\tlog global\n\toption tcplog\n")
+			b.WriteString("\ttimeout connect 10s\n\ttimeout client 1h\n\ttimeout server 1h\n")
+		}
+		fmt.Fprintf(&b, "\n# %s\nfrontend tcp_%d\n\tbind :%d\n\tdefault_backend tcp_%d\n", r.Domain, r.TCPPort, r.TCPPort, r.TCPPort)
+		fmt.Fprintf(&b, "\nbackend tcp_%d\n\tserver target %s check\n", r.TCPPort, r.Target)
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+	return b.Bytes()
+}
+
+// applyRoutesScript installs the generated route configuration. Invalid
+// configuration is rolled back before anything is reloaded, and reloads
+// are graceful so open connections are kept.
+const applyRoutesScript = `set -eu
+dir=/opt/portunix-edge
+routes=/etc/caddy/routes.d/portunix.caddy
+install -d /etc/caddy/routes.d
+backup=$(mktemp)
+if [ -f "$routes" ]; then cp "$routes" "$backup"; fi
+install -m 644 "$dir/routes.caddy" "$routes"
+if ! caddy validate --config /etc/caddy/Caddyfile --adapter caddyfile >/dev/null 2>&1; then
+  cp "$backup" "$routes"
+  caddy validate --config /etc/caddy/Caddyfile --adapter caddyfile || true
+  echo "invalid Caddy configuration, previous routes restored" >&2
+  exit 1
+fi
+systemctl reload caddy
+
+if [ -s "$dir/haproxy.cfg" ]; then
+  if ! command -v haproxy >/dev/null 2>&1; then
+    DEBIAN_FRONTEND=noninteractive apt-get install -y -q haproxy
+  fi
+  haproxy -c -q -f "$dir/haproxy.cfg"
+  install -m 644 "$dir/haproxy.cfg" /etc/haproxy/haproxy.cfg
+  systemctl enable -q haproxy
+  systemctl reload-or-restart haproxy
+elif [ -f /etc/haproxy/haproxy.cfg ] && grep -q "Managed by portunix edge" /etc/haproxy/haproxy.cfg; then
+  systemctl disable -q --now haproxy
+  rm -f /etc/haproxy/haproxy.cfg
+fi
+`
+
+// ApplyRoutes uploads the route configuration of the deployment to the
+// edge host and reloads Caddy and HAProxy
+func ApplyRoutes(client *SSHClient, d *Deployment, out io.Writer) error {
+	if err := client.Upload(path.Join(RemoteDir, caddyRoutesFile), RenderCaddyRoutes(d), 0644); err != nil {
+		return err
+	}
+	if err := client.Upload(path.Join(RemoteDir, haproxyConfigFile), RenderHAProxyConfig(d), 0644); err != nil {
+		return err
+	}
+	if err := client.Run("bash -s", strings.NewReader(applyRoutesScript), out, out); err != nil {
+		return fmt.Errorf("failed to apply routes on %s: %w", d.Name, err)
+	}
+	return nil
+}
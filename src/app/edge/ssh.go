@@ -0,0 +1,160 @@
+package edge
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"portunix.ai/app/shellquote"
+)
+
+// SSHClient runs commands on an edge host
+type SSHClient struct {
+	client *ssh.Client
+}
+
+// EnsureSSHKey returns the public key of the deployment key pair, creating
+// an ed25519 key in the deployment directory on first use
+func EnsureSSHKey(keyPath string) (string, error) {
+	if data, err := os.ReadFile(keyPath + ".pub"); err == nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return "", err
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate SSH key: %w", err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "portunix-edge")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode SSH key: %w", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600); err != nil {
+		return "", err
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	authorized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub))) + " portunix-edge"
+	if err := os.WriteFile(keyPath+".pub", []byte(authorized+"\n"), 0644); err != nil {
+		return "", err
+	}
+	return authorized, nil
+}
+
+// ConnectSSH connects to the deployment host. The host key is trusted on
+// first use and stored in the deployment; a different key later is refused.
+func ConnectSSH(d *Deployment, timeout time.Duration) (*SSHClient, error) {
+	if d.PublicIP == "" {
+		return nil, fmt.Errorf("deployment %s has no public IP yet", d.Name)
+	}
+	keyData, err := os.ReadFile(d.SSHKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSH key: %w", err)
+	}
+	signer, err := ssh.ParsePrivateKey(keyData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH key: %w", err)
+	}
+
+	config := &ssh.ClientConfig{
+		User:            d.SSHUser,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: d.verifyHostKey,
+		Timeout:         timeout,
+	}
+	client, err := ssh.Dial("tcp", net.JoinHostPort(d.PublicIP, "22"), config)
+	if err != nil {
+		return nil, err
+	}
+	return &SSHClient{client: client}, nil
+}
+
+// WaitForSSH retries ConnectSSH until the freshly booted host accepts
+// connections or the timeout expires
+func WaitForSSH(d *Deployment, timeout time.Duration) (*SSHClient, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		client, err := ConnectSSH(d, 10*time.Second)
+		if err == nil {
+			return client, nil
+		}
+		if strings.Contains(err.Error(), "host key mismatch") || time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to connect to %s: %w", d.PublicIP, err)
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func (d *Deployment) verifyHostKey(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	presented := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+	if d.HostKey == "" {
+		d.HostKey = presented
+		return nil
+	}
+	if d.HostKey != presented {
+		return fmt.Errorf("host key mismatch for %s (expected %s, got %s)",
+			hostname, fingerprint(d.HostKey), ssh.FingerprintSHA256(key))
+	}
+	return nil
+}
+
+func fingerprint(authorizedKey string) string {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(authorizedKey))
+	if err != nil {
+		return "unknown"
+	}
+	return ssh.FingerprintSHA256(key)
+}
+
+// Close closes the connection
+func (c *SSHClient) Close() error {
+	return c.client.Close()
+}
+
+// Run executes a shell command, feeding stdin to it when given
+func (c *SSHClient) Run(command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+	return session.Run(command)
+}
+
+// Output executes a command and returns its combined output
+func (c *SSHClient) Output(command string) (string, error) {
+	var out bytes.Buffer
+	err := c.Run(command, nil, &out, &out)
+	return out.String(), err
+}
+
+// Upload writes data to a remote file with the given mode
+func (c *SSHClient) Upload(remotePath string, data []byte, mode os.FileMode) error {
+	command := fmt.Sprintf("mkdir -p %s && cat > %s && chmod %o %s",
+		shellquote.Quote(path.Dir(remotePath)), shellquote.Quote(remotePath), mode.Perm(), shellquote.Quote(remotePath))
+	var stderr bytes.Buffer
+	if err := c.Run(command, bytes.NewReader(data), io.Discard, &stderr); err != nil {
+		return fmt.Errorf("failed to upload %s: %v %s", remotePath, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package edge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// EnvStateDir overrides the directory holding deployment state
+const EnvStateDir = "PORTUNIX_EDGE_DIR"
+
+// Deployment statuses
+const (
+	StatusProvisioning = "provisioning"
+	StatusProvisioned  = "provisioned"
+	StatusDeployed     = "deployed"
+	StatusFailed       = "failed"
+)
+
+var deploymentNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Deployment is the locally stored state of a VPS edge deployment
+type Deployment struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	Template string `json:"template"`
+	Region   string `json:"region"`
+	Size     string `json:"size"`
+	Image    string `json:"image"`
+
+	ServerID string `json:"server_id,omitempty"`
+	PublicIP string `json:"public_ip,omitempty"`
+
+	SSHUser string `json:"ssh_user"`
+	SSHKey  string `json:"ssh_key"` // private key path
+	// HostKey is the server host key recorded on first connection and
+	// verified on every later one
+	HostKey string `json:"host_key,omitempty"`
+
+	Domain     string `json:"domain,omitempty"`
+	AdminEmail string `json:"admin_email,omitempty"`
+
+	WireGuard WireGuardState `json:"wireguard"`
+	Routes    []Route        `json:"routes,omitempty"`
+
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	DeployedAt time.Time `json:"deployed_at,omitempty"`
+}
+
+// WireGuardState holds the tunnel between the edge host and this machine
+type WireGuardState struct {
+	Port             int    `json:"port"`
+	Network          string `json:"network"`
+	ServerIP         string `json:"server_ip"`
+	ClientIP         string `json:"client_ip"`
+	ServerPublicKey  string `json:"server_public_key,omitempty"`
+	ServerPrivateKey string `json:"server_private_key,omitempty"`
+	ClientPublicKey  string `json:"client_public_key,omitempty"`
+	ClientConfig     string `json:"client_config,omitempty"` // path of the client wg config
+}
+
+// StateDir returns the directory holding all edge deployments
+func StateDir() string {
+	if dir := os.Getenv(EnvStateDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-edge")
+	}
+	return filepath.Join(home, ".portunix", "edge")
+}
+
+// DeploymentDir returns the directory of one deployment
+func DeploymentDir(name string) string {
+	return filepath.Join(StateDir(), name)
+}
+
+func statePath(name string) string {
+	return filepath.Join(DeploymentDir(name), "state.json")
+}
+
+// ValidateDeploymentName checks that name is usable as a host name
+func ValidateDeploymentName(name string) error {
+	if !deploymentNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid deployment name %q: use lowercase letters, digits and dashes", name)
+	}
+	return nil
+}
+
+// LoadDeployment reads the state of a deployment
+func LoadDeployment(name string) (*Deployment, error) {
+	data, err := os.ReadFile(statePath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("edge deployment %q not found", name)
+		}
+		return nil, err
+	}
+	var d Deployment
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("invalid state of deployment %s: %w", name, err)
+	}
+	return &d, nil
+}
+
+// Save writes the deployment state. The file contains the WireGuard server
+// key, so it is readable by the current user only.
+func (d *Deployment) Save() error {
+	if err := os.MkdirAll(DeploymentDir(d.Name), 0700); err != nil {
+		return fmt.Errorf("failed to create deployment directory: %w", err)
+	}
+	d.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(statePath(d.Name), data, 0600)
+}
+
+// ListDeployments returns all stored deployments sorted by name
+func ListDeployments() ([]*Deployment, error) {
+	entries, err := os.ReadDir(StateDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var deployments []*Deployment
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if d, err := LoadDeployment(entry.Name()); err == nil {
+			deployments = append(deployments, d)
+		}
+	}
+	sort.Slice(deployments, func(i, j int) bool { return deployments[i].Name < deployments[j].Name })
+	return deployments, nil
+}
+
+// RemoveDeployment deletes the local state and keys of a deployment
+func RemoveDeployment(name string) error {
+	return os.RemoveAll(DeploymentDir(name))
+}
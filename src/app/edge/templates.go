@@ -0,0 +1,122 @@
+package edge
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"sort"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// RemoteDir is where deployment files are uploaded on the edge host
+const RemoteDir = "/opt/portunix-edge"
+
+// PtxbookFile is the name of the playbook of a deployment template
+const PtxbookFile = "edge.ptxbook"
+
+// DeployTemplate is a set of files installed on a new edge host
+type DeployTemplate struct {
+	Name        string
+	Description string
+	Files       []TemplateFile
+}
+
+// TemplateFile is a file rendered from a deployment template
+type TemplateFile struct {
+	Name   string      // file in the template directory
+	Remote bool        // uploaded to RemoteDir, otherwise kept locally only
+	Mode   os.FileMode // mode of the rendered file
+}
+
+var deployTemplates = map[string]DeployTemplate{
+	"caddy-wireguard": {
+		Name:        "caddy-wireguard",
+		Description: "Caddy reverse proxy with automatic TLS and a WireGuard tunnel back to this machine",
+		Files: []TemplateFile{
+			{Name: PtxbookFile, Remote: true, Mode: 0644},
+			{Name: "Caddyfile", Remote: true, Mode: 0644},
+			{Name: "wg0.conf", Remote: true, Mode: 0600},
+			{Name: "wg-client.conf", Mode: 0600},
+		},
+	},
+}
+
+// TemplateNames returns the available deployment templates
+func TemplateNames() []string {
+	names := make([]string, 0, len(deployTemplates))
+	for name := range deployTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetTemplate returns a deployment template by name
+func GetTemplate(name string) (DeployTemplate, error) {
+	t, ok := deployTemplates[name]
+	if !ok {
+		return DeployTemplate{}, fmt.Errorf("unknown template: %s (available: %v)", name, TemplateNames())
+	}
+	return t, nil
+}
+
+// templateData is passed to template files
+type templateData struct {
+	*Deployment
+	// ClientPrivateKey is only known while the client config is first rendered
+	ClientPrivateKey string
+}
+
+var templateFuncs = template.FuncMap{
+	// prefixLen returns the prefix length of a CIDR, e.g. 24 for 10.0.0.0/24
+	"prefixLen": func(cidr string) (int, error) {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return 0, err
+		}
+		ones, _ := network.Mask.Size()
+		return ones, nil
+	},
+}
+
+// Render renders one file of the template for the deployment
+func (t DeployTemplate) Render(file TemplateFile, d *Deployment, clientPrivateKey string) ([]byte, error) {
+	source, err := templateFS.ReadFile(path.Join("templates", t.Name, file.Name))
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(file.Name).Funcs(templateFuncs).Option("missingkey=error").Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s/%s: %w", t.Name, file.Name, err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, templateData{Deployment: d, ClientPrivateKey: clientPrivateKey}); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", file.Name, err)
+	}
+	return out.Bytes(), nil
+}
+
+// PtxbookScript returns a named script of a rendered ptxbook
+func PtxbookScript(ptxbook []byte, name string) (string, error) {
+	var book struct {
+		Spec struct {
+			Scripts map[string]string `yaml:"scripts"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(ptxbook, &book); err != nil {
+		return "", fmt.Errorf("invalid ptxbook: %w", err)
+	}
+	script, ok := book.Spec.Scripts[name]
+	if !ok {
+		return "", fmt.Errorf("ptxbook has no script %q", name)
+	}
+	return script, nil
+}
@@ -0,0 +1,367 @@
+// Package envprofile composes managed environment variable sets (proxy
+// variables, registry credentials, JAVA_HOME, Python venv activation) that
+// 'portunix run' injects into arbitrary commands
+package envprofile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"portunix.ai/app/config"
+	"portunix.ai/app/proxy"
+	"portunix.ai/app/secret"
+)
+
+// EnvProfilesFile overrides the profile definition files
+const EnvProfilesFile = "PORTUNIX_ENV_PROFILES_FILE"
+
+// FileName is the profile definition file kept next to config.yaml of the
+// system and user scopes
+const FileName = "env-profiles.yaml"
+
+// Profile is a named set of environment variables
+type Profile struct {
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Include lists profiles composed before this one
+	Include []string `yaml:"include,omitempty" json:"include,omitempty"`
+	// Proxy adds the proxy and CA variables of the portunix proxy settings
+	Proxy bool `yaml:"proxy,omitempty" json:"proxy,omitempty"`
+	// Java sets JAVA_HOME to a managed JDK ("21", "temurin-21") or a path
+	Java string `yaml:"java,omitempty" json:"java,omitempty"`
+	// Venv activates a Python virtual environment: the name of a portunix
+	// managed venv or a path
+	Venv string `yaml:"venv,omitempty" json:"venv,omitempty"`
+	// Secrets maps variables to names in the portunix secret store
+	Secrets map[string]string `yaml:"secrets,omitempty" json:"secrets,omitempty"`
+	// Env sets variables; ${NAME} refers to the environment composed so far
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+	// Unset removes variables
+	Unset []string `yaml:"unset,omitempty" json:"unset,omitempty"`
+}
+
+// File is the content of an env-profiles.yaml file
+type File struct {
+	Profiles map[string]*Profile `yaml:"profiles" json:"profiles"`
+}
+
+// Files returns the profile definition files in load order; profiles of
+// later files replace profiles of the same name
+func Files() []string {
+	if path := os.Getenv(EnvProfilesFile); path != "" {
+		return []string{path}
+	}
+	return []string{
+		filepath.Join(filepath.Dir(config.ScopePath(config.ScopeSystem)), FileName),
+		filepath.Join(filepath.Dir(config.ScopePath(config.ScopeUser)), FileName),
+	}
+}
+
+// Load reads and merges the profile definition files
+func Load() (*File, error) {
+	merged := &File{Profiles: make(map[string]*Profile)}
+	for _, path := range Files() {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var f File
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for name, p := range f.Profiles {
+			if p == nil {
+				p = &Profile{}
+			}
+			merged.Profiles[name] = p
+		}
+	}
+	return merged, nil
+}
+
+// Names returns the defined profile names, sorted
+func (f *File) Names() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Sources resolve the managed values a profile refers to
+type Sources struct {
+	Proxy    func() (map[string]string, error)
+	Secret   func(name string) (string, error)
+	JavaHome func(spec string) (string, error)
+	Venv     func(spec string) (string, error)
+}
+
+// DefaultSources resolves values from the portunix configuration, the
+// secret store and the managed JDK and venv directories
+func DefaultSources() Sources {
+	return Sources{
+		Proxy:    proxy.ToolEnvironment,
+		Secret:   func(name string) (string, error) { return secret.Get(name, "run") },
+		JavaHome: JavaHome,
+		Venv:     VenvDir,
+	}
+}
+
+// Variable is a variable set by a profile
+type Variable struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Profile string `json:"profile"`
+	Secret  bool   `json:"secret,omitempty"` // Value comes from the secret store
+}
+
+// Environment is the environment of a command with profiles applied
+type Environment struct {
+	vars  map[string]string
+	names []string // Variables of the environment in first-set order
+	set   map[string]*Variable
+	unset map[string]bool
+}
+
+// Compose applies profiles in order to the base environment ("NAME=value"
+// entries, usually os.Environ())
+func (f *File) Compose(names []string, base []string, src Sources) (*Environment, error) {
+	env := &Environment{vars: make(map[string]string), set: make(map[string]*Variable), unset: make(map[string]bool)}
+	for _, entry := range base {
+		name, value, _ := strings.Cut(entry, "=")
+		env.put(name, value)
+	}
+	applied := make(map[string]bool)
+	for _, name := range names {
+		if err := f.apply(env, name, src, applied, nil); err != nil {
+			return nil, err
+		}
+	}
+	return env, nil
+}
+
+// apply composes a profile after its includes; stack detects include cycles
+func (f *File) apply(env *Environment, name string, src Sources, applied map[string]bool, stack []string) error {
+	for _, parent := range stack {
+		if parent == name {
+			return fmt.Errorf("env profile %s includes itself (%s)", name, strings.Join(append(stack, name), " -> "))
+		}
+	}
+	if applied[name] {
+		return nil
+	}
+	p, ok := f.Profiles[name]
+	if !ok {
+		return fmt.Errorf("env profile %s is not defined (profiles: %s)", name, strings.Join(f.Names(), ", "))
+	}
+	for _, include := range p.Include {
+		if err := f.apply(env, include, src, applied, append(stack, name)); err != nil {
+			return err
+		}
+	}
+	applied[name] = true
+
+	if p.Proxy {
+		vars, err := src.Proxy()
+		if err != nil {
+			return fmt.Errorf("env profile %s: %w", name, err)
+		}
+		for _, v := range sortedKeys(vars) {
+			env.setVar(Variable{Name: v, Value: vars[v], Profile: name})
+		}
+	}
+	if p.Java != "" {
+		home, err := src.JavaHome(p.Java)
+		if err != nil {
+			return fmt.Errorf("env profile %s: %w", name, err)
+		}
+		env.setVar(Variable{Name: "JAVA_HOME", Value: home, Profile: name})
+		env.prependPath(filepath.Join(home, "bin"), name)
+	}
+	if p.Venv != "" {
+		dir, err := src.Venv(p.Venv)
+		if err != nil {
+			return fmt.Errorf("env profile %s: %w", name, err)
+		}
+		env.setVar(Variable{Name: "VIRTUAL_ENV", Value: dir, Profile: name})
+		env.prependPath(venvBin(dir), name)
+		env.unsetVar("PYTHONHOME") // Would point python outside the venv
+	}
+	for _, v := range sortedKeys(p.Secrets) {
+		value, err := src.Secret(p.Secrets[v])
+		if err != nil {
+			return fmt.Errorf("env profile %s: %s: %w", name, v, err)
+		}
+		env.setVar(Variable{Name: v, Value: value, Profile: name, Secret: true})
+	}
+	for _, v := range sortedKeys(p.Env) {
+		value := os.Expand(p.Env[v], func(ref string) string { return env.vars[ref] })
+		env.setVar(Variable{Name: v, Value: value, Profile: name})
+	}
+	for _, v := range p.Unset {
+		env.unsetVar(v)
+	}
+	return nil
+}
+
+func (e *Environment) put(name, value string) {
+	if _, ok := e.vars[name]; !ok {
+		e.names = append(e.names, name)
+	}
+	e.vars[name] = value
+}
+
+func (e *Environment) setVar(v Variable) {
+	e.put(v.Name, v.Value)
+	delete(e.unset, v.Name)
+	e.set[v.Name] = &v
+}
+
+func (e *Environment) unsetVar(name string) {
+	if _, ok := e.vars[name]; ok {
+		delete(e.vars, name)
+		for i, n := range e.names {
+			if n == name {
+				e.names = append(e.names[:i], e.names[i+1:]...)
+				break
+			}
+		}
+	}
+	delete(e.set, name)
+	e.unset[name] = true
+}
+
+// prependPath puts a directory first in PATH
+func (e *Environment) prependPath(dir, profile string) {
+	path := dir
+	if current := e.vars[pathVar()]; current != "" {
+		path += string(os.PathListSeparator) + current
+	}
+	e.setVar(Variable{Name: pathVar(), Value: path, Profile: profile})
+}
+
+// Environ returns the environment as "NAME=value" entries for exec
+func (e *Environment) Environ() []string {
+	entries := make([]string, 0, len(e.names))
+	for _, name := range e.names {
+		entries = append(entries, name+"="+e.vars[name])
+	}
+	return entries
+}
+
+// Lookup returns a variable of the composed environment
+func (e *Environment) Lookup(name string) (string, bool) {
+	value, ok := e.vars[name]
+	return value, ok
+}
+
+// Variables returns the variables the profiles set, sorted by name
+func (e *Environment) Variables() []Variable {
+	vars := make([]Variable, 0, len(e.set))
+	for _, v := range e.set {
+		vars = append(vars, *v)
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// Unset returns the variables the profiles removed, sorted
+func (e *Environment) Unset() []string {
+	return sortedKeys(e.unset)
+}
+
+// JavaHome returns JAVA_HOME of a JDK installed with 'portunix java install'
+// ("21", "temurin-21") or of a JDK directory
+func JavaHome(spec string) (string, error) {
+	if filepath.IsAbs(spec) {
+		if _, err := os.Stat(filepath.Join(spec, "bin")); err != nil {
+			return "", fmt.Errorf("%s is not a JDK directory", spec)
+		}
+		return spec, nil
+	}
+
+	home, _ := os.UserHomeDir()
+	jdks := filepath.Join(config.GetString("java.root_dir", filepath.Join(home, ".portunix", "java")), "jdks")
+	dist, major := "", strings.ToLower(spec)
+	if i := strings.LastIndex(major, "-"); i >= 0 {
+		dist, major = major[:i], major[i+1:]
+	}
+	candidates := []string{filepath.Join(jdks, dist+"-"+major)}
+	if dist == "" {
+		// Temurin first, as 'portunix java install' defaults to it
+		candidates = []string{filepath.Join(jdks, "temurin-"+major)}
+		others, _ := filepath.Glob(filepath.Join(jdks, "*-"+major))
+		candidates = append(candidates, others...)
+	}
+	for _, dir := range candidates {
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		// macOS archives keep the JDK under Contents/Home
+		if macHome := filepath.Join(dir, "Contents", "Home"); dirExists(macHome) {
+			return macHome, nil
+		}
+		return dir, nil
+	}
+	return "", fmt.Errorf("JDK %s is not installed (install it with 'portunix java install %s')", spec, spec)
+}
+
+// VenvDir returns the directory of a venv created with 'portunix python venv
+// create <name>', or of a venv path
+func VenvDir(spec string) (string, error) {
+	dir := spec
+	if !strings.ContainsAny(spec, `/\`) && spec != "." && spec != ".." {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(config.GetString("python.venv_dir", filepath.Join(home, ".portunix", "python", "venvs")), spec)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if !dirExists(venvBin(dir)) {
+		return "", fmt.Errorf("virtual environment %s not found (create it with 'portunix python venv create %s')", spec, spec)
+	}
+	return dir, nil
+}
+
+// venvBin returns the directory of the executables of a venv
+func venvBin(dir string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(dir, "Scripts")
+	}
+	return filepath.Join(dir, "bin")
+}
+
+// pathVar returns the name of the PATH variable; Windows spells it Path
+func pathVar() string {
+	if runtime.GOOS == "windows" {
+		for _, entry := range os.Environ() {
+			if name, _, _ := strings.Cut(entry, "="); strings.EqualFold(name, "PATH") {
+				return name
+			}
+		}
+	}
+	return "PATH"
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,152 @@
+package envprofile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const testProfiles = `profiles:
+  corp:
+    proxy: true
+    env:
+      MAVEN_OPTS: -Dhttps.proxyHost=proxy.corp
+  java21:
+    include: [corp]
+    java: "21"
+  ci:
+    description: Build agents
+    include: [java21, corp]
+    venv: tools
+    secrets:
+      NPM_TOKEN: registry/npm
+    env:
+      GRADLE_OPTS: "${MAVEN_OPTS} -Xmx2g"
+    unset: [DEBUG]
+  loop:
+    include: [loop2]
+  loop2:
+    include: [loop]
+`
+
+// testSources resolves values without the configuration and secret store
+func testSources() Sources {
+	return Sources{
+		Proxy: func() (map[string]string, error) {
+			return map[string]string{"HTTPS_PROXY": "http://proxy.corp:3128"}, nil
+		},
+		Secret: func(name string) (string, error) {
+			if name == "registry/npm" {
+				return "npm-token", nil
+			}
+			return "", errors.New("not found")
+		},
+		JavaHome: func(spec string) (string, error) { return "/jdks/temurin-" + spec, nil },
+		Venv:     func(spec string) (string, error) { return "/venvs/" + spec, nil },
+	}
+}
+
+func loadTestProfiles(t *testing.T) *File {
+	path := filepath.Join(t.TempDir(), FileName)
+	os.WriteFile(path, []byte(testProfiles), 0644)
+	t.Setenv(EnvProfilesFile, path)
+	f, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestCompose(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("PATH layout differs on Windows")
+	}
+	f := loadTestProfiles(t)
+	if strings.Join(f.Names(), ",") != "ci,corp,java21,loop,loop2" {
+		t.Fatalf("profiles %v", f.Names())
+	}
+
+	env, err := f.Compose([]string{"ci"}, []string{"PATH=/usr/bin", "DEBUG=1", "HOME=/home/dev", "PYTHONHOME=/opt/py"}, testSources())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"HTTPS_PROXY": "http://proxy.corp:3128",
+		"JAVA_HOME":   "/jdks/temurin-21",
+		"VIRTUAL_ENV": "/venvs/tools",
+		"PATH":        "/venvs/tools/bin:/jdks/temurin-21/bin:/usr/bin",
+		"NPM_TOKEN":   "npm-token",
+		"GRADLE_OPTS": "-Dhttps.proxyHost=proxy.corp -Xmx2g",
+		"HOME":        "/home/dev",
+	}
+	for name, value := range want {
+		if got, _ := env.Lookup(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+	for _, name := range []string{"DEBUG", "PYTHONHOME"} {
+		if _, ok := env.Lookup(name); ok {
+			t.Errorf("%s not unset", name)
+		}
+	}
+	if strings.Join(env.Unset(), ",") != "DEBUG,PYTHONHOME" {
+		t.Errorf("unset %v", env.Unset())
+	}
+
+	// corp is included twice but applied once, so PATH holds one JDK
+	var names []string
+	for _, v := range env.Variables() {
+		names = append(names, v.Name+"@"+v.Profile)
+		if v.Name == "NPM_TOKEN" && !v.Secret {
+			t.Error("secret variable not marked")
+		}
+	}
+	if strings.Join(names, " ") != "GRADLE_OPTS@ci HTTPS_PROXY@corp JAVA_HOME@java21 MAVEN_OPTS@corp NPM_TOKEN@ci PATH@ci VIRTUAL_ENV@ci" {
+		t.Errorf("variables %v", names)
+	}
+	for _, entry := range env.Environ() {
+		if strings.HasPrefix(entry, "DEBUG=") {
+			t.Error("unset variable in environ")
+		}
+	}
+}
+
+func TestComposeErrors(t *testing.T) {
+	f := loadTestProfiles(t)
+	if _, err := f.Compose([]string{"loop"}, nil, testSources()); err == nil || !strings.Contains(err.Error(), "includes itself") {
+		t.Errorf("include cycle: %v", err)
+	}
+	if _, err := f.Compose([]string{"missing"}, nil, testSources()); err == nil || !strings.Contains(err.Error(), "not defined") {
+		t.Errorf("unknown profile: %v", err)
+	}
+
+	f.Profiles["ci"].Secrets["AWS_SECRET"] = "cloud/aws"
+	if _, err := f.Compose([]string{"ci"}, nil, testSources()); err == nil || !strings.Contains(err.Error(), "AWS_SECRET") {
+		t.Errorf("missing secret: %v", err)
+	}
+}
+
+func TestJavaHomeAndVenv(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("PORTUNIX_JAVA_ROOT_DIR", root)
+	t.Setenv("PORTUNIX_PYTHON_VENV_DIR", filepath.Join(root, "venvs"))
+	os.MkdirAll(filepath.Join(root, "jdks", "zulu-17", "bin"), 0755)
+
+	if home, err := JavaHome("17"); err != nil || home != filepath.Join(root, "jdks", "zulu-17") {
+		t.Errorf("JavaHome(17) = %q, %v", home, err)
+	}
+	if _, err := JavaHome("temurin-17"); err == nil {
+		t.Error("JDK of another distribution accepted")
+	}
+
+	os.MkdirAll(venvBin(filepath.Join(root, "venvs", "tools")), 0755)
+	if dir, err := VenvDir("tools"); err != nil || dir != filepath.Join(root, "venvs", "tools") {
+		t.Errorf("VenvDir(tools) = %q, %v", dir, err)
+	}
+	if _, err := VenvDir("missing"); err == nil {
+		t.Error("missing venv accepted")
+	}
+}
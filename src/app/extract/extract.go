@@ -0,0 +1,175 @@
+// Package extract unpacks downloaded .tar.gz and .zip archives. Entries
+// and symlink targets that would land outside the destination directory
+// are rejected.
+package extract
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TarGz extracts a .tar.gz archive into destDir. Relative symlinks are
+// kept; they are created after all files so nothing is written through them.
+func TarGz(archive, destDir string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	type symlink struct{ target, linkname string }
+	var symlinks []symlink
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := safeJoin(destDir, hdr.Name)
+		if err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, os.FileMode(hdr.Mode)); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := checkLink(destDir, target, hdr.Linkname); err != nil {
+				return err
+			}
+			symlinks = append(symlinks, symlink{target, hdr.Linkname})
+		}
+	}
+
+	for _, l := range symlinks {
+		if err := os.MkdirAll(filepath.Dir(l.target), 0755); err != nil {
+			return err
+		}
+		// A link created through an earlier link would be checked against
+		// the wrong directory
+		if err := checkParents(destDir, l.target); err != nil {
+			return err
+		}
+		if err := os.Symlink(l.linkname, l.target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Zip extracts a .zip archive into destDir
+func Zip(archive, destDir string) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		target, err := safeJoin(destDir, f.Name)
+		if err != nil {
+			return err
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = writeFile(target, rc, f.Mode())
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// safeJoin joins an archive entry name to destDir, rejecting path traversal
+func safeJoin(destDir, name string) (string, error) {
+	target := filepath.Join(destDir, name)
+	if !within(destDir, target) {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+	return target, nil
+}
+
+// checkLink rejects symlinks that are absolute or point outside destDir
+func checkLink(destDir, target, linkname string) error {
+	if filepath.IsAbs(linkname) || strings.HasPrefix(linkname, "/") || filepath.VolumeName(linkname) != "" {
+		return fmt.Errorf("illegal symlink in archive: %s -> %s", target, linkname)
+	}
+	if !within(destDir, filepath.Join(filepath.Dir(target), linkname)) {
+		return fmt.Errorf("illegal symlink in archive: %s -> %s", target, linkname)
+	}
+	return nil
+}
+
+// checkParents rejects paths whose directories below destDir include a symlink
+func checkParents(destDir, target string) error {
+	destDir = filepath.Clean(destDir)
+	rel, err := filepath.Rel(destDir, filepath.Dir(target))
+	if err != nil {
+		return err
+	}
+	dir := destDir
+	for _, part := range strings.Split(rel, string(os.PathSeparator)) {
+		if part == "." {
+			continue
+		}
+		dir = filepath.Join(dir, part)
+		if info, err := os.Lstat(dir); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("illegal path in archive: %s is below the symlink %s", target, dir)
+		}
+	}
+	return nil
+}
+
+// within reports whether path is destDir or below it
+func within(destDir, path string) bool {
+	destDir = filepath.Clean(destDir)
+	return path == destDir || strings.HasPrefix(path, destDir+string(os.PathSeparator))
+}
+
+// writeFile writes a reader to path, creating parent directories
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode|0200)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, r)
+	return err
+}
@@ -0,0 +1,102 @@
+package extract
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTarGz creates a .tar.gz with the given headers; regular files get
+// their name as content
+func writeTarGz(t *testing.T, headers ...*tar.Header) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, hdr := range headers {
+		if hdr.Typeflag == tar.TypeReg {
+			hdr.Size = int64(len(hdr.Name))
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			tw.Write([]byte(hdr.Name))
+		}
+	}
+	tw.Close()
+	gz.Close()
+	return path
+}
+
+func TestTarGz(t *testing.T) {
+	archive := writeTarGz(t,
+		&tar.Header{Name: "jdk/", Typeflag: tar.TypeDir, Mode: 0755},
+		&tar.Header{Name: "jdk/legal/java.base/LICENSE", Typeflag: tar.TypeReg, Mode: 0644},
+		&tar.Header{Name: "jdk/legal/java.sql/LICENSE", Typeflag: tar.TypeSymlink, Linkname: "../java.base/LICENSE"},
+	)
+	dest := t.TempDir()
+	if err := TarGz(archive, dest); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dest, "jdk/legal/java.sql/LICENSE"))
+	if err != nil || string(data) != "jdk/legal/java.base/LICENSE" {
+		t.Errorf("symlinked file: %q, %v", data, err)
+	}
+}
+
+func TestTarGzRejectsEscapes(t *testing.T) {
+	tests := []struct {
+		name   string
+		header *tar.Header
+	}{
+		{"path traversal", &tar.Header{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0644}},
+		{"absolute symlink", &tar.Header{Name: "jdk/link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}},
+		{"escaping symlink", &tar.Header{Name: "jdk/link", Typeflag: tar.TypeSymlink, Linkname: "../../outside"}},
+	}
+	for _, tt := range tests {
+		archive := writeTarGz(t, tt.header)
+		dest := t.TempDir()
+		err := TarGz(archive, filepath.Join(dest, "out"))
+		if err == nil || !strings.Contains(err.Error(), "illegal") {
+			t.Errorf("%s: got %v", tt.name, err)
+		}
+		if _, err := os.Lstat(filepath.Join(dest, "out", "jdk", "link")); err == nil {
+			t.Errorf("%s: symlink was created", tt.name)
+		}
+	}
+}
+
+func TestTarGzRejectsLinkChains(t *testing.T) {
+	for _, headers := range [][]*tar.Header{
+		{
+			{Name: "d1/d2/s", Typeflag: tar.TypeSymlink, Linkname: ".."},
+			{Name: "d1/d2/s/t", Typeflag: tar.TypeSymlink, Linkname: "../.."},
+			{Name: "d1/d2/s/t/evil", Typeflag: tar.TypeReg, Mode: 0644},
+		},
+		{
+			{Name: "d1/d2/s", Typeflag: tar.TypeSymlink, Linkname: ".."},
+			{Name: "d1/d2/s/t", Typeflag: tar.TypeSymlink, Linkname: "../.."},
+		},
+	} {
+		dest := t.TempDir()
+		if err := TarGz(writeTarGz(t, headers...), filepath.Join(dest, "out")); err == nil {
+			t.Errorf("%d entries: link chain was accepted", len(headers))
+		}
+		if _, err := os.Lstat(filepath.Join(dest, "evil")); err == nil {
+			t.Error("file was written outside the destination")
+		}
+		if _, err := os.Lstat(filepath.Join(dest, "out", "d1", "t")); err == nil {
+			t.Error("link was created through another link")
+		}
+	}
+}
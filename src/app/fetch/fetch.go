@@ -0,0 +1,380 @@
+// Package fetch downloads files over unreliable networks: interrupted
+// downloads are retried with exponential backoff and resumed with HTTP
+// range requests, and when a URL keeps failing the next one, e.g. the next
+// mirror, is tried. The partial file survives failed runs, so the next
+// download of the same URL continues where the last one stopped.
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"portunix.ai/app/progress"
+)
+
+// Defaults of Options
+const (
+	DefaultAttempts     = 5
+	DefaultBackoff      = time.Second
+	DefaultMaxBackoff   = 30 * time.Second
+	DefaultStallTimeout = time.Minute
+)
+
+// Options controls downloads. The zero value uses the defaults.
+type Options struct {
+	// Attempts is the number of tries of each URL
+	Attempts int
+	// Backoff is the delay before the first retry, doubled for every
+	// further retry up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// StallTimeout aborts an attempt that receives no data for this long
+	StallTimeout time.Duration
+	// Header is added to the requests
+	Header http.Header
+	Client *http.Client
+	// Reporter reports the progress as Stage ("download" by default)
+	Reporter *progress.Reporter
+	Stage    string
+	// Log receives a line for every retry and failover; nil discards them
+	Log io.Writer
+}
+
+// Result describes a finished download
+type Result struct {
+	// URL is the URL the file was completed from
+	URL string
+	// Response holds the headers of the last response; its body is closed.
+	// It is nil when the partial file was already complete.
+	Response *http.Response
+	Size     int64
+	// Resumed is the number of bytes taken over from an earlier attempt
+	Resumed int64
+}
+
+// state is kept next to a partial file so that it is only resumed from the
+// URL it was started from, and only while the remote file is unchanged
+type state struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Total        int64  `json:"total,omitempty"`
+}
+
+// permanentError fails a URL without retrying it
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// File downloads the first URL that works to dest, keeping the incomplete
+// file at dest + ".part" in between
+func File(urls []string, dest string, opts Options) (*Result, error) {
+	partial := dest + ".part"
+	result, err := Download(urls, partial, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(partial, dest); err != nil {
+		return nil, fmt.Errorf("failed to move download to %s: %w", dest, err)
+	}
+	return result, nil
+}
+
+// Download downloads the first URL that works to partial, resuming what an
+// earlier run left there. The complete file is left at partial for the
+// caller to move; on failure the incomplete file is kept for the next run.
+func Download(urls []string, partial string, opts Options) (*Result, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no download URL")
+	}
+	opts = opts.withDefaults()
+	if err := os.MkdirAll(filepath.Dir(partial), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create download directory: %w", err)
+	}
+
+	var errs []error
+	for i, url := range urls {
+		if i > 0 {
+			opts.logf("🔀 Trying %s\n", url)
+		}
+		result, err := downloadURL(url, partial, opts)
+		if err == nil {
+			os.Remove(statePath(partial))
+			return result, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", url, err))
+	}
+	if opts.Reporter != nil {
+		opts.Reporter.Fail(opts.Stage, errs[len(errs)-1])
+	}
+	if len(errs) == 1 {
+		return nil, fmt.Errorf("failed to download %w", errs[0])
+	}
+	return nil, fmt.Errorf("failed to download from %d sources: %w", len(errs), errors.Join(errs...))
+}
+
+// downloadURL tries one URL with retries
+func downloadURL(url, partial string, opts Options) (*Result, error) {
+	delay := opts.Backoff
+	var err error
+	for attempt := 1; attempt <= opts.Attempts; attempt++ {
+		var result *Result
+		result, err = attemptDownload(url, partial, opts)
+		if err == nil {
+			return result, nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return nil, permanent.err
+		}
+		if attempt == opts.Attempts {
+			break
+		}
+		opts.logf("⚠️  Download interrupted: %v; retrying in %s (attempt %d/%d)\n", err, delay, attempt+1, opts.Attempts)
+		time.Sleep(delay)
+		delay = min(delay*2, opts.MaxBackoff)
+	}
+	return nil, err
+}
+
+// attemptDownload makes one request, appending to the partial file when
+// the server resumes it and starting over when it does not
+func attemptDownload(url, partial string, opts Options) (*Result, error) {
+	offset, st := resumable(url, partial)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, &permanentError{err}
+	}
+	for key, values := range opts.Header {
+		req.Header[key] = values
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		// Without a validator the server could append a different file
+		if validator := st.validator(); validator != "" {
+			req.Header.Set("If-Range", validator)
+		}
+	}
+
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		start, total, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			os.Remove(partial)
+			return nil, fmt.Errorf("server resumed at an unexpected position (%s)", resp.Header.Get("Content-Range"))
+		}
+		flags |= os.O_APPEND
+		if total > 0 {
+			st.Total = total
+		}
+	case resp.StatusCode == http.StatusOK:
+		offset = 0
+		flags |= os.O_TRUNC
+		st = &state{
+			URL:          url,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Total:        resp.ContentLength,
+		}
+		if err := st.save(partial); err != nil {
+			return nil, &permanentError{err}
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The partial file is complete when the server has nothing after it
+		if _, total, ok := parseContentRange(resp.Header.Get("Content-Range")); ok && total == offset {
+			return &Result{URL: url, Size: offset, Resumed: offset}, nil
+		}
+		os.Remove(partial)
+		return nil, fmt.Errorf("partial download does not match the remote file; starting over")
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode >= 500:
+		return nil, fmt.Errorf("bad status: %s", resp.Status)
+	default:
+		return nil, &permanentError{fmt.Errorf("bad status: %s", resp.Status)}
+	}
+
+	out, err := os.OpenFile(partial, flags, 0644)
+	if err != nil {
+		return nil, &permanentError{fmt.Errorf("failed to create file: %w", err)}
+	}
+	defer out.Close()
+
+	if offset > 0 {
+		opts.logf("⏯️  Resuming at %s\n", progress.FormatBytes(offset))
+	}
+	var body io.Reader = &stallReader{reader: resp.Body, timer: time.AfterFunc(opts.StallTimeout, cancel), timeout: opts.StallTimeout}
+	if opts.Reporter != nil {
+		body = opts.Reporter.ResumeReader(opts.Stage, body, offset, st.Total)
+	}
+	written, err := io.Copy(out, body)
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("no data received for %s", opts.StallTimeout)
+		}
+		return nil, err
+	}
+	size := offset + written
+	if st.Total > 0 && size != st.Total {
+		return nil, fmt.Errorf("connection closed after %s of %s: %w", progress.FormatBytes(size), progress.FormatBytes(st.Total), io.ErrUnexpectedEOF)
+	}
+	if err := out.Close(); err != nil {
+		return nil, &permanentError{err}
+	}
+	return &Result{URL: url, Response: resp, Size: size, Resumed: offset}, nil
+}
+
+// resumable returns the size of the partial file and its state when it can
+// be resumed from url, and removes a partial file that cannot
+func resumable(url, partial string) (int64, *state) {
+	info, err := os.Stat(partial)
+	if err != nil {
+		return 0, &state{}
+	}
+	st, err := loadState(partial)
+	if err != nil || st.URL != url || info.Size() == 0 {
+		os.Remove(partial)
+		os.Remove(statePath(partial))
+		return 0, &state{}
+	}
+	return info.Size(), st
+}
+
+func statePath(partial string) string {
+	return partial + ".json"
+}
+
+func loadState(partial string) (*state, error) {
+	data, err := os.ReadFile(statePath(partial))
+	if err != nil {
+		return nil, err
+	}
+	st := &state{}
+	return st, json.Unmarshal(data, st)
+}
+
+func (s *state) save(partial string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(statePath(partial), data, 0644)
+}
+
+// validator returns the If-Range value identifying the remote file; weak
+// ETags are not allowed there
+func (s *state) validator() string {
+	if s.ETag != "" && !strings.HasPrefix(s.ETag, "W/") {
+		return s.ETag
+	}
+	return s.LastModified
+}
+
+// parseContentRange parses "bytes 100-199/1000" and "bytes */1000"; total
+// is 0 when the server does not know it
+func parseContentRange(value string) (start, total int64, ok bool) {
+	spec, found := strings.CutPrefix(value, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	rng, size, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+	if size != "*" {
+		if total, ok = parseInt(size); !ok {
+			return 0, 0, false
+		}
+	}
+	if rng == "*" {
+		return 0, total, true
+	}
+	first, _, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, ok = parseInt(first)
+	return start, total, ok
+}
+
+func parseInt(s string) (int64, bool) {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	return n, err == nil && n >= 0
+}
+
+// stallReader restarts the stall timer of an attempt on every read
+type stallReader struct {
+	reader  io.Reader
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (s *stallReader) Read(p []byte) (int, error) {
+	n, err := s.reader.Read(p)
+	if n > 0 {
+		s.timer.Reset(s.timeout)
+	}
+	if err != nil {
+		s.timer.Stop()
+	}
+	return n, err
+}
+
+func (o Options) withDefaults() Options {
+	if o.Attempts <= 0 {
+		o.Attempts = DefaultAttempts
+	}
+	if o.Backoff <= 0 {
+		o.Backoff = DefaultBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DefaultMaxBackoff
+	}
+	if o.StallTimeout <= 0 {
+		o.StallTimeout = DefaultStallTimeout
+	}
+	if o.Client == nil {
+		o.Client = defaultClient
+	}
+	if o.Stage == "" {
+		o.Stage = "download"
+	}
+	return o
+}
+
+func (o Options) logf(format string, args ...any) {
+	if o.Log != nil {
+		fmt.Fprintf(o.Log, format, args...)
+	}
+}
+
+// defaultClient fails fast on unreachable servers; the body has no overall
+// timeout as large files take long, stalls are caught by StallTimeout
+var defaultClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+		TLSHandshakeTimeout:   30 * time.Second,
+		ResponseHeaderTimeout: time.Minute,
+		ForceAttemptHTTP2:     true,
+	},
+}
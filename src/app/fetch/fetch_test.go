@@ -0,0 +1,145 @@
+package fetch
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testData = bytes.Repeat([]byte("portunix "), 10000)
+
+// serveFile serves testData with range support; the first cut requests
+// are broken off after half of the file
+func serveFile(cut int32) (*httptest.Server, *[]string) {
+	var requests atomic.Int32
+	var ranges []string
+	modified := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if requests.Add(1) <= cut {
+			w.Header().Set("Content-Length", strconv.Itoa(len(testData)))
+			w.Header().Set("ETag", `"v1"`)
+			w.Write(testData[:len(testData)/2])
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "file.bin", modified, bytes.NewReader(testData))
+	}))
+	return server, &ranges
+}
+
+func quick() Options {
+	return Options{Attempts: 3, Backoff: time.Millisecond}
+}
+
+func TestFileResumesInterruptedDownload(t *testing.T) {
+	server, ranges := serveFile(1)
+	defer server.Close()
+	dest := filepath.Join(t.TempDir(), "file.bin")
+
+	result, err := File([]string{server.URL + "/file.bin"}, dest, quick())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(dest)
+	if !bytes.Equal(data, testData) {
+		t.Fatalf("downloaded %d bytes, want %d", len(data), len(testData))
+	}
+	if result.Resumed != int64(len(testData)/2) || result.Size != int64(len(testData)) {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if len(*ranges) != 2 || (*ranges)[1] != "bytes="+strconv.Itoa(len(testData)/2)+"-" {
+		t.Errorf("second request should resume, ranges %q", *ranges)
+	}
+	if _, err := os.Stat(dest + ".part.json"); !os.IsNotExist(err) {
+		t.Error("download state should be removed")
+	}
+}
+
+func TestDownloadKeepsPartialBetweenRuns(t *testing.T) {
+	server, ranges := serveFile(1)
+	defer server.Close()
+	partial := filepath.Join(t.TempDir(), "partial", "key")
+	url := server.URL + "/file.bin"
+
+	opts := quick()
+	opts.Attempts = 1
+	if _, err := Download([]string{url}, partial, opts); err == nil {
+		t.Fatal("first run should fail")
+	}
+	if info, err := os.Stat(partial); err != nil || info.Size() != int64(len(testData)/2) {
+		t.Fatalf("partial file should be kept: %v", err)
+	}
+
+	result, err := Download([]string{url}, partial, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Resumed == 0 || (*ranges)[1] == "" {
+		t.Errorf("second run should resume, result %+v ranges %q", result, *ranges)
+	}
+	if data, _ := os.ReadFile(partial); !bytes.Equal(data, testData) {
+		t.Error("resumed file differs")
+	}
+
+	// A complete partial file is recognized without downloading it again
+	(&state{URL: url, ETag: `"v1"`}).save(partial)
+	result, err = Download([]string{url}, partial, opts)
+	if err != nil || result.Size != int64(len(testData)) || result.Response != nil {
+		t.Errorf("complete partial: %+v, %v", result, err)
+	}
+}
+
+func TestDownloadRetriesAndFailsOver(t *testing.T) {
+	var failures atomic.Int32
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures.Add(1) <= 2 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(testData)
+	}))
+	defer flaky.Close()
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+
+	var log bytes.Buffer
+	opts := quick()
+	opts.Log = &log
+	dest := filepath.Join(t.TempDir(), "file.bin")
+	result, err := File([]string{missing.URL + "/file.bin", flaky.URL + "/file.bin"}, dest, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.URL != flaky.URL+"/file.bin" || failures.Load() != 3 {
+		t.Errorf("unexpected result %+v after %d requests", result, failures.Load())
+	}
+	if !bytes.Contains(log.Bytes(), []byte("Trying "+flaky.URL)) || !bytes.Contains(log.Bytes(), []byte("attempt 3/3")) {
+		t.Errorf("unexpected log:\n%s", log.String())
+	}
+
+	if _, err := File([]string{missing.URL + "/file.bin"}, dest, opts); err == nil {
+		t.Error("missing file should fail")
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	cases := map[string][3]int64{
+		"bytes 100-199/1000": {100, 1000, 1},
+		"bytes 0-9/*":        {0, 0, 1},
+		"bytes */500":        {0, 500, 1},
+		"items 1-2/3":        {0, 0, 0},
+	}
+	for value, want := range cases {
+		start, total, ok := parseContentRange(value)
+		if start != want[0] || total != want[1] || ok != (want[2] == 1) {
+			t.Errorf("parseContentRange(%q) = %d, %d, %v", value, start, total, ok)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+
+// Package fileutil persists configuration, registry and cache files safely
+// when several portunix processes (a sync daemon and manual commands) write
+// the same file: writes go to a temporary file renamed over the target, and
+// writers are serialized by an advisory lock on <file>.lock.
+package fileutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LockSuffix is appended to a file's path to get its lock file
+const LockSuffix = ".lock"
+
+// WriteFile writes data to path atomically while holding the file's lock
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	return WithLock(path, func() error {
+		return WriteAtomic(path, data, perm)
+	})
+}
+
+// WriteAtomic writes data to a temporary file next to path and renames it
+// over path, so readers see either the old or the new content, never a
+// partially written file
+func WriteAtomic(path string, data []byte, perm os.FileMode) error {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+name+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Lock acquires an exclusive advisory lock on path, blocking while another
+// process or goroutine holds it. The lock is released by the returned
+// function, or by the operating system when the process exits, so a crashed
+// writer never leaves the file locked.
+func Lock(path string) (func(), error) {
+	lockPath := path + LockSuffix
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := platformFileLock(f.Fd()); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return func() {
+		platformFileUnlock(f.Fd())
+		f.Close()
+	}, nil
+}
+
+// WithLock runs fn while holding the lock of path, for read-modify-write
+// updates that must not interleave with other writers
+func WithLock(path string, fn func() error) error {
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
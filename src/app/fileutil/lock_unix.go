@@ -0,0 +1,24 @@
+//go:build !windows
+
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package fileutil
+
+import "syscall"
+
+// platformFileLock acquires an exclusive file lock
+func platformFileLock(fd uintptr) error {
+	for {
+		err := syscall.Flock(int(fd), syscall.LOCK_EX)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// platformFileUnlock releases a file lock
+func platformFileUnlock(fd uintptr) error {
+	return syscall.Flock(int(fd), syscall.LOCK_UN)
+}
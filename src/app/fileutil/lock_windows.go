@@ -0,0 +1,41 @@
+//go:build windows
+
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package fileutil
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const lockfileExclusiveLock = 0x02
+
+var (
+	modkernel32      = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = modkernel32.NewProc("LockFileEx")
+	procUnlockFileEx = modkernel32.NewProc("UnlockFileEx")
+)
+
+// platformFileLock acquires an exclusive file lock using LockFileEx,
+// blocking until it is available
+func platformFileLock(fd uintptr) error {
+	ol := new(syscall.Overlapped)
+	r1, _, err := procLockFileEx.Call(fd, lockfileExclusiveLock, 0, 1, 0, uintptr(unsafe.Pointer(ol)))
+	if r1 == 0 {
+		return err
+	}
+	return nil
+}
+
+// platformFileUnlock releases a file lock using UnlockFileEx
+func platformFileUnlock(fd uintptr) error {
+	ol := new(syscall.Overlapped)
+	r1, _, err := procUnlockFileEx.Call(fd, 0, 1, 0, uintptr(unsafe.Pointer(ol)))
+	if r1 == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package firstrun
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// completionMarker tags the lines added to shell startup files
+const completionMarker = "# portunix shell completion"
+
+// CompletionPath returns where the completion script of a shell is
+// installed for the current user
+func CompletionPath(shell string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	switch shell {
+	case "bash":
+		// Loaded on demand by bash-completion
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+		return filepath.Join(dataHome, "bash-completion", "completions", "portunix"), nil
+	case "zsh":
+		return filepath.Join(home, ".zfunc", "_portunix"), nil
+	case "fish":
+		return filepath.Join(home, ".config", "fish", "completions", "portunix.fish"), nil
+	case "powershell":
+		return filepath.Join(home, ".portunix", "completion", "portunix.ps1"), nil
+	}
+	return "", fmt.Errorf("unsupported shell: %s", shell)
+}
+
+// InstallCompletion writes the completion script of a shell and, for
+// shells that do not load completions from a directory, adds it to the
+// shell's startup file. It returns the files written.
+func InstallCompletion(shell string, script []byte) ([]string, error) {
+	path, err := CompletionPath(shell)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, script, 0644); err != nil {
+		return nil, err
+	}
+	written := []string{path}
+
+	var rcFile, snippet string
+	switch shell {
+	case "zsh":
+		home, _ := os.UserHomeDir()
+		rcFile = filepath.Join(home, ".zshrc")
+		snippet = "fpath=(~/.zfunc $fpath)\nautoload -Uz compinit && compinit"
+	case "powershell":
+		if rcFile, err = powershellProfile(); err != nil {
+			return written, err
+		}
+		snippet = fmt.Sprintf(". '%s'", strings.ReplaceAll(path, "'", "''"))
+	default:
+		return written, nil
+	}
+	added, err := appendOnce(rcFile, snippet)
+	if err != nil {
+		return written, err
+	}
+	if added {
+		written = append(written, rcFile)
+	}
+	return written, nil
+}
+
+// appendOnce appends a marked snippet to a startup file unless it was
+// added before. It reports whether the file changed.
+func appendOnce(path, snippet string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	if strings.Contains(string(data), completionMarker) {
+		return false, nil
+	}
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += "\n" + completionMarker + "\n" + snippet + "\n"
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, []byte(content), 0644)
+}
+
+// powershellProfile asks PowerShell for the current user's profile path
+func powershellProfile() (string, error) {
+	for _, shell := range []string{"pwsh", "powershell"} {
+		if _, err := exec.LookPath(shell); err != nil {
+			continue
+		}
+		out, err := exec.Command(shell, "-NoProfile", "-NonInteractive", "-Command", "$PROFILE").Output()
+		if err == nil && strings.TrimSpace(string(out)) != "" {
+			return strings.TrimSpace(string(out)), nil
+		}
+	}
+	return "", fmt.Errorf("PowerShell not found")
+}
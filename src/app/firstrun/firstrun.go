@@ -0,0 +1,193 @@
+// Package firstrun implements the first-run setup of 'portunix init':
+// environment detection, role based helper selection, and shell
+// completion and PATH setup
+package firstrun
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Roles of team members, selecting the helpers installed by 'portunix init'
+const (
+	RoleDev     = "dev"
+	RoleDevOps  = "devops"
+	RoleProduct = "product"
+)
+
+// KeyRoles is the configuration key recording the roles chosen at setup
+const KeyRoles = "init.roles"
+
+// roleHelpers lists the helpers each role works with. ptx-installer is
+// needed by everyone for 'portunix install'.
+var roleHelpers = map[string][]string{
+	RoleDev:     {"ptx-installer", "ptx-container", "ptx-python", "ptx-go", "ptx-java", "ptx-db", "ptx-ai", "ptx-make", "ptx-trace"},
+	RoleDevOps:  {"ptx-installer", "ptx-container", "ptx-virt", "ptx-ansible", "ptx-credential", "ptx-aiops", "ptx-make"},
+	RoleProduct: {"ptx-installer", "ptx-pft", "ptx-prompting"},
+}
+
+var roleDescriptions = map[string]string{
+	RoleDev:     "Developer: language toolchains, containers, builds and tracing",
+	RoleDevOps:  "DevOps: containers, VMs, Ansible, credentials and operations",
+	RoleProduct: "Product: feedback tracking and prompt templates",
+}
+
+// Roles returns the known roles in display order
+func Roles() []string {
+	return []string{RoleDev, RoleDevOps, RoleProduct}
+}
+
+// RoleDescription describes a role
+func RoleDescription(role string) string {
+	return roleDescriptions[role]
+}
+
+// ValidateRole checks a role name
+func ValidateRole(role string) error {
+	if _, ok := roleHelpers[role]; !ok {
+		return fmt.Errorf("unknown role %q (use %s)", role, strings.Join(Roles(), ", "))
+	}
+	return nil
+}
+
+// HelpersForRoles returns the helpers of the given roles, sorted and
+// without duplicates
+func HelpersForRoles(roles []string) []string {
+	seen := make(map[string]bool)
+	var helpers []string
+	for _, role := range roles {
+		for _, helper := range roleHelpers[role] {
+			if !seen[helper] {
+				seen[helper] = true
+				helpers = append(helpers, helper)
+			}
+		}
+	}
+	sort.Strings(helpers)
+	return helpers
+}
+
+// Environment is what 'portunix init' detects before asking questions
+type Environment struct {
+	OS    string `json:"os"`
+	Arch  string `json:"arch"`
+	Shell string `json:"shell,omitempty"`
+	// ContainerRuntime is docker or podman when one is installed
+	ContainerRuntime string `json:"container_runtime,omitempty"`
+	// Tools lists the developer tools found in PATH
+	Tools []string `json:"tools,omitempty"`
+	// Proxy is the proxy from the HTTPS_PROXY/HTTP_PROXY environment
+	Proxy   string `json:"proxy,omitempty"`
+	NoProxy string `json:"no_proxy,omitempty"`
+	// ExecDir is the directory of the portunix binary and its helpers
+	ExecDir string `json:"exec_dir"`
+	InPath  bool   `json:"in_path"`
+}
+
+// detectedTools are looked up in PATH; the roles they suggest are used as
+// defaults for the role question
+var detectedTools = []struct {
+	name string
+	role string
+}{
+	{"git", ""},
+	{"go", RoleDev},
+	{"python3", RoleDev},
+	{"java", RoleDev},
+	{"node", RoleDev},
+	{"code", RoleDev},
+	{"kubectl", RoleDevOps},
+	{"ansible", RoleDevOps},
+	{"terraform", RoleDevOps},
+	{"helm", RoleDevOps},
+}
+
+// Detect inspects the local environment
+func Detect() *Environment {
+	env := &Environment{
+		OS:    runtime.GOOS,
+		Arch:  runtime.GOARCH,
+		Shell: DetectShell(),
+	}
+	for _, runtimeName := range []string{"podman", "docker"} {
+		if _, err := exec.LookPath(runtimeName); err == nil {
+			env.ContainerRuntime = runtimeName
+			break
+		}
+	}
+	for _, tool := range detectedTools {
+		if _, err := exec.LookPath(tool.name); err == nil {
+			env.Tools = append(env.Tools, tool.name)
+		}
+	}
+	for _, name := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy"} {
+		if value := os.Getenv(name); value != "" {
+			env.Proxy = value
+			break
+		}
+	}
+	env.NoProxy = os.Getenv("NO_PROXY")
+	if env.NoProxy == "" {
+		env.NoProxy = os.Getenv("no_proxy")
+	}
+	if execPath, err := os.Executable(); err == nil {
+		if resolved, err := filepath.EvalSymlinks(execPath); err == nil {
+			execPath = resolved
+		}
+		env.ExecDir = filepath.Dir(execPath)
+		env.InPath = inPath(env.ExecDir)
+	}
+	return env
+}
+
+// SuggestedRoles returns the roles suggested by the installed tools,
+// defaulting to the developer role
+func (e *Environment) SuggestedRoles() []string {
+	suggested := make(map[string]bool)
+	for _, tool := range detectedTools {
+		for _, found := range e.Tools {
+			if found == tool.name && tool.role != "" {
+				suggested[tool.role] = true
+			}
+		}
+	}
+	var roles []string
+	for _, role := range Roles() {
+		if suggested[role] {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		roles = []string{RoleDev}
+	}
+	return roles
+}
+
+// DetectShell returns the user's shell: bash, zsh, fish or powershell
+func DetectShell() string {
+	if runtime.GOOS == "windows" {
+		return "powershell"
+	}
+	shell := filepath.Base(os.Getenv("SHELL"))
+	switch shell {
+	case "bash", "zsh", "fish":
+		return shell
+	case "pwsh":
+		return "powershell"
+	}
+	return ""
+}
+
+func inPath(dir string) bool {
+	for _, p := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(p) == filepath.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
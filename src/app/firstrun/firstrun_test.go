@@ -0,0 +1,68 @@
+package firstrun
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHelpersForRoles(t *testing.T) {
+	helpers := HelpersForRoles([]string{RoleDev, RoleDevOps})
+	seen := make(map[string]int)
+	for _, h := range helpers {
+		seen[h]++
+	}
+	if seen["ptx-installer"] != 1 || seen["ptx-container"] != 1 {
+		t.Errorf("shared helpers should be listed once: %v", helpers)
+	}
+	if seen["ptx-ansible"] != 1 || seen["ptx-python"] != 1 || seen["ptx-pft"] != 0 {
+		t.Errorf("unexpected helpers %v", helpers)
+	}
+	if err := ValidateRole("manager"); err == nil {
+		t.Error("unknown role should be rejected")
+	}
+}
+
+func TestSuggestedRoles(t *testing.T) {
+	env := &Environment{Tools: []string{"git", "kubectl", "go"}}
+	if got := strings.Join(env.SuggestedRoles(), ","); got != "dev,devops" {
+		t.Errorf("unexpected roles %s", got)
+	}
+	env = &Environment{Tools: []string{"git"}}
+	if got := strings.Join(env.SuggestedRoles(), ","); got != "dev" {
+		t.Errorf("default role should be dev, got %s", got)
+	}
+}
+
+func TestInstallCompletion(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+
+	files, err := InstallCompletion("bash", []byte("# bash completion"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0] != filepath.Join(home, ".local", "share", "bash-completion", "completions", "portunix") {
+		t.Errorf("unexpected bash files %v", files)
+	}
+
+	os.WriteFile(filepath.Join(home, ".zshrc"), []byte("export EDITOR=vim"), 0644)
+	for i := 0; i < 2; i++ {
+		if _, err := InstallCompletion("zsh", []byte("#compdef portunix")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rc, _ := os.ReadFile(filepath.Join(home, ".zshrc"))
+	if !strings.HasPrefix(string(rc), "export EDITOR=vim\n") || strings.Count(string(rc), completionMarker) != 1 {
+		t.Errorf("unexpected .zshrc:\n%s", rc)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".zfunc", "_portunix")); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := InstallCompletion("tcsh", nil); err == nil {
+		t.Error("unsupported shell should fail")
+	}
+}
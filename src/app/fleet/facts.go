@@ -0,0 +1,227 @@
+package fleet
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Facts describe the state of a host at the last scan
+type Facts struct {
+	Hostname  string `json:"hostname"`
+	OS        string `json:"os"` // os-release ID, e.g. ubuntu
+	OSName    string `json:"os_name"`
+	OSVersion string `json:"os_version"`
+	Kernel    string `json:"kernel"`
+	Arch      string `json:"arch"`
+	// Packages are the installed distribution packages
+	Packages []string `json:"packages"`
+	// Tools are well-known commands found on the PATH
+	Tools        []string          `json:"tools"`
+	Tunnels      []Tunnel          `json:"tunnels,omitempty"`
+	Certificates []CertificateFact `json:"certificates,omitempty"`
+}
+
+// Tunnel is a VPN interface on the host
+type Tunnel struct {
+	Interface string `json:"interface"`
+	Type      string `json:"type"` // wireguard
+	Address   string `json:"address,omitempty"`
+	Peers     int    `json:"peers"`
+}
+
+// CertificateFact is a TLS certificate found on the host
+type CertificateFact struct {
+	Path     string    `json:"path"`
+	Domains  []string  `json:"domains"`
+	NotAfter time.Time `json:"not_after"`
+}
+
+// DaysLeft returns the days until the certificate expires
+func (c CertificateFact) DaysLeft() int {
+	return int(time.Until(c.NotAfter).Hours() / 24)
+}
+
+// scanTools are the commands recorded in Facts.Tools
+var scanTools = []string{"docker", "podman", "git", "python3", "node", "go", "java", "caddy", "nginx", "haproxy", "wg", "fail2ban-client", "ufw", "nft", "portunix"}
+
+// packageAliases maps a name used in queries to the package names that
+// provide it on different distributions
+var packageAliases = map[string][]string{
+	"docker": {"docker", "docker.io", "docker-ce", "moby-engine"},
+	"python": {"python3", "python"},
+	"node":   {"nodejs", "node"},
+	"java":   {"default-jdk", "openjdk", "java-latest-openjdk", "jdk"},
+}
+
+// factScript prints the facts in sections introduced by "### <name>"
+var factScript = `export LC_ALL=C
+echo "### hostname"; hostname 2>/dev/null
+echo "### os-release"; cat /etc/os-release 2>/dev/null
+echo "### kernel"; uname -r
+echo "### arch"; uname -m
+echo "### packages"
+if command -v dpkg-query >/dev/null 2>&1; then dpkg-query -W -f '${db:Status-Abbrev} ${Package}\n' 2>/dev/null | awk '$1 == "ii" { print $2 }'
+elif command -v rpm >/dev/null 2>&1; then rpm -qa --qf '%{NAME}\n'
+elif command -v apk >/dev/null 2>&1; then apk info 2>/dev/null
+elif command -v pacman >/dev/null 2>&1; then pacman -Qq
+fi
+echo "### tools"
+for t in ` + strings.Join(scanTools, " ") + `; do command -v "$t" >/dev/null 2>&1 && echo "$t"; done
+echo "### wireguard"
+if command -v wg >/dev/null 2>&1; then
+	for i in $(wg show interfaces 2>/dev/null); do
+		echo "$i $(ip -o -4 addr show dev "$i" 2>/dev/null | awk '{ print $4 }' | head -n1) $(wg show "$i" peers 2>/dev/null | wc -l)"
+	done
+fi
+for f in /etc/letsencrypt/live/*/cert.pem /var/lib/caddy/.local/share/caddy/certificates/*/*/*.crt "$HOME"/.portunix/certs/*/fullchain.pem; do
+	[ -r "$f" ] || continue
+	echo "### cert $f"; cat "$f"
+done
+true
+`
+
+// Runner runs a shell command line on a host; remote.SSHRunner satisfies it
+type Runner interface {
+	Run(command string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error
+}
+
+// Scan collects the facts of the host reached through r
+func Scan(r Runner) (*Facts, error) {
+	var out, stderr bytes.Buffer
+	if err := r.Run("sh -s", strings.NewReader(factScript), &out, &stderr, false); err != nil {
+		return nil, fmt.Errorf("scan failed: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return ParseFacts(out.String())
+}
+
+// ParseFacts parses the output of the fact script
+func ParseFacts(output string) (*Facts, error) {
+	sections := map[string][]string{}
+	var certs []string
+	var section string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "### "); ok {
+			section = name
+			if strings.HasPrefix(name, "cert ") {
+				certs = append(certs, name)
+			}
+			continue
+		}
+		sections[section] = append(sections[section], line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if _, ok := sections["kernel"]; !ok {
+		return nil, fmt.Errorf("unexpected scan output: a POSIX shell is required")
+	}
+
+	f := &Facts{
+		Hostname: first(sections["hostname"]),
+		Kernel:   first(sections["kernel"]),
+		Arch:     first(sections["arch"]),
+		Packages: sortedLines(sections["packages"]),
+		Tools:    sortedLines(sections["tools"]),
+	}
+	for _, line := range sections["os-release"] {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "ID":
+			f.OS = value
+		case "PRETTY_NAME":
+			f.OSName = value
+		case "VERSION_ID":
+			f.OSVersion = value
+		}
+	}
+	for _, line := range sections["wireguard"] {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		t := Tunnel{Interface: fields[0], Type: "wireguard"}
+		if len(fields) == 3 {
+			t.Address = fields[1]
+		}
+		fmt.Sscan(fields[len(fields)-1], &t.Peers)
+		f.Tunnels = append(f.Tunnels, t)
+	}
+	for _, name := range certs {
+		if cert, ok := parseCertificate(strings.TrimPrefix(name, "cert "), sections[name]); ok {
+			f.Certificates = append(f.Certificates, cert)
+		}
+	}
+	return f, nil
+}
+
+// parseCertificate reads the leaf certificate of a PEM file
+func parseCertificate(path string, lines []string) (CertificateFact, bool) {
+	block, _ := pem.Decode([]byte(strings.Join(lines, "\n")))
+	if block == nil {
+		return CertificateFact{}, false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return CertificateFact{}, false
+	}
+	domains := cert.DNSNames
+	if len(domains) == 0 && cert.Subject.CommonName != "" {
+		domains = []string{cert.Subject.CommonName}
+	}
+	return CertificateFact{Path: path, Domains: domains, NotAfter: cert.NotAfter}, true
+}
+
+// Has reports whether a package or tool is present on the host. Common
+// names such as "docker" also match their distribution package names.
+func (f *Facts) Has(name string) bool {
+	candidates := append([]string{name}, packageAliases[strings.ToLower(name)]...)
+	for _, candidate := range candidates {
+		if contains(f.Packages, candidate) || contains(f.Tools, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func first(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(lines[0])
+}
+
+func sortedLines(lines []string) []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" && !seen[line] {
+			seen[line] = true
+			out = append(out, line)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
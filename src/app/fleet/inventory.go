@@ -0,0 +1,222 @@
+// Package fleet keeps the inventory of managed hosts and the facts last
+// collected from them. Each host is one JSON file, so the inventory
+// directory can be a git repository shared by a team: every change is then
+// committed and 'fleet sync' exchanges it with the remote.
+package fleet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EnvDir overrides the inventory directory
+const EnvDir = "PORTUNIX_FLEET_DIR"
+
+var hostNamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._-]{0,62})$`)
+
+// Host is a managed host
+type Host struct {
+	Name string `json:"name"`
+	// Address is the --host value used to reach the host: [user@]host[:port],
+	// an ~/.ssh/config alias or an edge deployment name
+	Address   string    `json:"address"`
+	Tags      []string  `json:"tags,omitempty"`
+	AddedAt   time.Time `json:"added_at"`
+	Facts     *Facts    `json:"facts,omitempty"`
+	ScannedAt time.Time `json:"scanned_at,omitempty"`
+	// ScanError is the error of the last scan, empty when it succeeded
+	ScanError string `json:"scan_error,omitempty"`
+}
+
+// HasTag reports whether the host carries tag
+func (h *Host) HasTag(tag string) bool {
+	for _, t := range h.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dir returns the inventory directory
+func Dir() string {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-fleet")
+	}
+	return filepath.Join(home, ".portunix", "fleet")
+}
+
+func hostsDir() string {
+	return filepath.Join(Dir(), "hosts")
+}
+
+func hostPath(name string) string {
+	return filepath.Join(hostsDir(), name+".json")
+}
+
+// ValidateHostName checks that name is usable as an inventory name
+func ValidateHostName(name string) error {
+	if !hostNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid host name %q: use letters, digits, dots, dashes and underscores", name)
+	}
+	return nil
+}
+
+// Load reads a host from the inventory
+func Load(name string) (*Host, error) {
+	if err := ValidateHostName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(hostPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("host %q is not in the fleet inventory", name)
+		}
+		return nil, err
+	}
+	var h Host
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("invalid inventory entry %s: %w", name, err)
+	}
+	return &h, nil
+}
+
+// List returns all hosts sorted by name
+func List() ([]*Host, error) {
+	entries, err := os.ReadDir(hostsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var hosts []*Host
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if h, err := Load(name); err == nil {
+			hosts = append(hosts, h)
+		}
+	}
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Name < hosts[j].Name })
+	return hosts, nil
+}
+
+// Save writes the host and commits the change when the inventory is a git
+// repository
+func Save(h *Host, message string) error {
+	if err := ValidateHostName(h.Name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(hostsDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create inventory directory: %w", err)
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(hostPath(h.Name), append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return commit(message)
+}
+
+// Remove deletes a host from the inventory
+func Remove(name string) error {
+	if _, err := Load(name); err != nil {
+		return err
+	}
+	if err := os.Remove(hostPath(name)); err != nil {
+		return err
+	}
+	return commit("fleet: remove " + name)
+}
+
+// IsGit reports whether the inventory is a git repository
+func IsGit() bool {
+	_, err := os.Stat(filepath.Join(Dir(), ".git"))
+	return err == nil
+}
+
+// Init prepares the inventory directory. With a git URL the inventory is
+// cloned from it; an empty URL with useGit creates a local repository.
+func Init(gitURL string, useGit bool) error {
+	dir := Dir()
+	if IsGit() {
+		return fmt.Errorf("inventory %s is already a git repository", dir)
+	}
+	if gitURL != "" {
+		if hosts, _ := List(); len(hosts) > 0 {
+			return fmt.Errorf("inventory %s already has hosts; move them away before cloning", dir)
+		}
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return err
+		}
+		os.Remove(filepath.Join(dir, "hosts"))
+		os.Remove(dir)
+		return git(filepath.Dir(dir), "clone", gitURL, dir)
+	}
+	if err := os.MkdirAll(hostsDir(), 0755); err != nil {
+		return err
+	}
+	if !useGit {
+		return nil
+	}
+	if err := git(dir, "init", "-q"); err != nil {
+		return err
+	}
+	return commit("fleet: initialize inventory")
+}
+
+// Sync pulls inventory changes from the git remote and pushes local ones
+func Sync() error {
+	if !IsGit() {
+		return fmt.Errorf("inventory %s is not a git repository; run 'portunix fleet init --git <url>'", Dir())
+	}
+	if err := git(Dir(), "pull", "--rebase", "--quiet"); err != nil {
+		return err
+	}
+	return git(Dir(), "push", "--quiet")
+}
+
+// commit records all inventory changes in git; a plain directory is left
+// as is
+func commit(message string) error {
+	if !IsGit() {
+		return nil
+	}
+	if err := git(Dir(), "add", "-A"); err != nil {
+		return err
+	}
+	// Nothing staged, e.g. a scan that found the same facts
+	if exec.Command("git", "-C", Dir(), "diff", "--cached", "--quiet").Run() == nil {
+		return nil
+	}
+	args := []string{"commit", "-q", "-m", message}
+	if out, _ := exec.Command("git", "-C", Dir(), "config", "user.email").Output(); len(strings.TrimSpace(string(out))) == 0 {
+		// Hosts without a git identity still record inventory history
+		args = append([]string{"-c", "user.name=portunix", "-c", "user.email=portunix@localhost"}, args...)
+	}
+	return git(Dir(), args...)
+}
+
+func git(dir string, args ...string) error {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s failed: %v %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package fleet
+
+import (
+	"strings"
+)
+
+// Filter selects hosts; empty fields match every host
+type Filter struct {
+	OS      string   // os-release ID or a word of the OS name, e.g. ubuntu
+	Tags    []string // all tags must be present
+	Has     []string // all packages or tools must be present
+	Missing []string // at least one package or tool must be absent
+	// ExpiringDays matches hosts with a certificate expiring within the
+	// given number of days; 0 disables the check
+	ExpiringDays int
+}
+
+// Match reports whether the host passes the filter. Hosts that were never
+// scanned have no facts and only match filters on tags.
+func (f Filter) Match(h *Host) bool {
+	for _, tag := range f.Tags {
+		if !h.HasTag(tag) {
+			return false
+		}
+	}
+	if f.OS == "" && len(f.Has) == 0 && len(f.Missing) == 0 && f.ExpiringDays == 0 {
+		return true
+	}
+	facts := h.Facts
+	if facts == nil {
+		return false
+	}
+	if f.OS != "" && !strings.EqualFold(facts.OS, f.OS) && !containsWord(facts.OSName, f.OS) {
+		return false
+	}
+	for _, name := range f.Has {
+		if !facts.Has(name) {
+			return false
+		}
+	}
+	if len(f.Missing) > 0 {
+		missing := false
+		for _, name := range f.Missing {
+			missing = missing || !facts.Has(name)
+		}
+		if !missing {
+			return false
+		}
+	}
+	if f.ExpiringDays > 0 {
+		expiring := false
+		for _, c := range facts.Certificates {
+			expiring = expiring || c.DaysLeft() <= f.ExpiringDays
+		}
+		if !expiring {
+			return false
+		}
+	}
+	return true
+}
+
+// Select returns the hosts passing the filter
+func Select(hosts []*Host, f Filter) []*Host {
+	var selected []*Host
+	for _, h := range hosts {
+		if f.Match(h) {
+			selected = append(selected, h)
+		}
+	}
+	return selected
+}
+
+func containsWord(text, word string) bool {
+	for _, w := range strings.Fields(strings.ToLower(text)) {
+		if w == strings.ToLower(word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,286 @@
+package harden
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	fail2banJailFile   = "/etc/fail2ban/jail.d/portunix.local"
+	fail2banCaddyFile  = "/etc/fail2ban/filter.d/portunix-caddy.conf"
+	fail2banCaddyLog   = "/var/log/caddy/access.log"
+	fail2banNginxLog   = "/var/log/nginx/error.log"
+	fail2banNginxAcc   = "/var/log/nginx/access.log"
+	fail2banDefaultBan = time.Hour
+)
+
+// Fail2banProxies are the reverse proxies a jail can be generated for
+var Fail2banProxies = []string{"caddy", "nginx", "none"}
+
+// Fail2banOptions configures the generated jails
+type Fail2banOptions struct {
+	SSHPort  int           // 22 when zero
+	Proxy    string        // caddy, nginx or none; detected when empty
+	BanTime  time.Duration // 1h when zero
+	FindTime time.Duration // 10m when zero
+	MaxRetry int           // 5 when zero
+	// Banaction is the fail2ban action used to ban addresses; derived from
+	// the firewall backend when empty
+	Banaction string
+}
+
+var fail2banJailTemplate = template.Must(template.New("jail").Parse(`# Managed by portunix harden fail2ban - changes will be overwritten
+[DEFAULT]
+bantime  = {{.BanTime}}
+findtime = {{.FindTime}}
+maxretry = {{.MaxRetry}}
+backend  = systemd
+banaction = {{.Banaction}}
+
+[sshd]
+enabled = true
+port    = {{.SSHPort}}
+{{- if eq .Proxy "caddy"}}
+
+[portunix-caddy]
+enabled  = true
+port     = http,https
+filter   = portunix-caddy
+backend  = auto
+logpath  = ` + fail2banCaddyLog + `
+{{- else if eq .Proxy "nginx"}}
+
+[nginx-http-auth]
+enabled = true
+port    = http,https
+backend = auto
+logpath = ` + fail2banNginxLog + `
+
+[nginx-botsearch]
+enabled = true
+port    = http,https
+backend = auto
+logpath = ` + fail2banNginxAcc + `
+{{- end}}
+`))
+
+// fail2banCaddyFilter matches authentication failures in Caddy's JSON
+// access log
+const fail2banCaddyFilter = `# Managed by portunix harden fail2ban - changes will be overwritten
+[Definition]
+failregex = "remote_ip":"<HOST>".*"status":(?:401|403)\b
+ignoreregex =
+datepattern = "ts":{EPOCH}
+`
+
+// BanactionFor returns the fail2ban ban action matching a firewall backend
+func BanactionFor(backend string) string {
+	switch backend {
+	case "ufw":
+		return "ufw"
+	case "nftables":
+		return "nftables-multiport"
+	default:
+		return "iptables-multiport"
+	}
+}
+
+// DetectProxy returns the reverse proxy installed on this host, or "none"
+func DetectProxy() string {
+	for _, proxy := range []string{"caddy", "nginx"} {
+		if _, err := exec.LookPath(proxy); err == nil {
+			return proxy
+		}
+	}
+	return "none"
+}
+
+// Fail2banPlan returns the jail configuration and the commands that
+// activate it
+func Fail2banPlan(opts Fail2banOptions) (*Plan, error) {
+	if opts.SSHPort == 0 {
+		opts.SSHPort = 22
+	}
+	if opts.SSHPort < 1 || opts.SSHPort > 65535 {
+		return nil, fmt.Errorf("invalid SSH port: %d", opts.SSHPort)
+	}
+	if opts.Proxy == "" {
+		opts.Proxy = DetectProxy()
+	}
+	valid := false
+	for _, proxy := range Fail2banProxies {
+		valid = valid || proxy == opts.Proxy
+	}
+	if !valid {
+		return nil, fmt.Errorf("unsupported proxy: %s (supported: %v)", opts.Proxy, Fail2banProxies)
+	}
+	if opts.BanTime == 0 {
+		opts.BanTime = fail2banDefaultBan
+	}
+	if opts.FindTime == 0 {
+		opts.FindTime = 10 * time.Minute
+	}
+	if opts.MaxRetry == 0 {
+		opts.MaxRetry = 5
+	}
+	if opts.BanTime < time.Second || opts.FindTime < time.Second || opts.MaxRetry < 1 {
+		return nil, fmt.Errorf("bantime, findtime and maxretry must be positive")
+	}
+	if opts.Banaction == "" {
+		opts.Banaction = BanactionFor("")
+		if fw, err := NewFirewall(""); err == nil {
+			opts.Banaction = BanactionFor(fw.Backend.Name())
+		}
+	}
+
+	var jail bytes.Buffer
+	err := fail2banJailTemplate.Execute(&jail, map[string]interface{}{
+		"BanTime":   int(opts.BanTime.Seconds()),
+		"FindTime":  int(opts.FindTime.Seconds()),
+		"MaxRetry":  opts.MaxRetry,
+		"Banaction": opts.Banaction,
+		"SSHPort":   opts.SSHPort,
+		"Proxy":     opts.Proxy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		Backend: "fail2ban",
+		Profile: "sshd",
+		Rules:   []Rule{{Port: opts.SSHPort, Proto: "tcp", Comment: "sshd"}},
+		Files:   []PlanFile{{Path: fail2banJailFile, Content: jail.String(), Mode: 0644}},
+	}
+	switch opts.Proxy {
+	case "caddy":
+		plan.Profile += ",portunix-caddy"
+		plan.Files = append(plan.Files, PlanFile{Path: fail2banCaddyFile, Content: fail2banCaddyFilter, Mode: 0644})
+		// fail2ban refuses to start a jail whose log file does not exist yet
+		plan.Commands = append(plan.Commands, []string{"sh", "-c", "mkdir -p /var/log/caddy && touch " + fail2banCaddyLog})
+	case "nginx":
+		plan.Profile += ",nginx-http-auth,nginx-botsearch"
+	}
+	plan.Commands = append(plan.Commands,
+		[]string{"fail2ban-client", "-t"},
+		[]string{"systemctl", "enable", "fail2ban"},
+		[]string{"systemctl", "reload-or-restart", "fail2ban"},
+	)
+	return plan, nil
+}
+
+// ApplyFail2ban writes the jail configuration and restarts fail2ban; a nil
+// run executes the commands directly. When a step fails the generated files
+// are removed again.
+func ApplyFail2ban(plan *Plan, run CommandRunner) error {
+	if run == nil {
+		run = execRunner
+	}
+	if err := executePlan(plan, run); err != nil {
+		for _, file := range plan.Files {
+			os.Remove(file.Path)
+		}
+		return err
+	}
+	return nil
+}
+
+// RemoveFail2ban deletes the generated jails and reloads fail2ban
+func RemoveFail2ban(run CommandRunner) error {
+	if run == nil {
+		run = execRunner
+	}
+	for _, path := range []string{fail2banJailFile, fail2banCaddyFile} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if out, err := run("systemctl", "reload-or-restart", "fail2ban"); err != nil {
+		return commandError("systemctl reload-or-restart fail2ban", err, out)
+	}
+	return nil
+}
+
+// Jail is the state of a fail2ban jail
+type Jail struct {
+	Name            string   `json:"name"`
+	CurrentlyFailed int      `json:"currently_failed"`
+	TotalFailed     int      `json:"total_failed"`
+	CurrentlyBanned int      `json:"currently_banned"`
+	TotalBanned     int      `json:"total_banned"`
+	BannedIPs       []string `json:"banned_ips"`
+}
+
+// Fail2banStatus queries fail2ban-client for the active jails and their
+// ban counts
+func Fail2banStatus(run CommandRunner) ([]Jail, error) {
+	if run == nil {
+		run = execRunner
+	}
+	out, err := run("fail2ban-client", "status")
+	if err != nil {
+		return nil, commandError("fail2ban-client status", err, out)
+	}
+	var jails []Jail
+	for _, name := range ParseFail2banJails(string(out)) {
+		out, err := run("fail2ban-client", "status", name)
+		if err != nil {
+			return nil, commandError("fail2ban-client status "+name, err, out)
+		}
+		jail := ParseFail2banJail(string(out))
+		jail.Name = name
+		jails = append(jails, jail)
+	}
+	return jails, nil
+}
+
+// ParseFail2banJails parses the jail list of "fail2ban-client status"
+func ParseFail2banJails(output string) []string {
+	for _, line := range strings.Split(output, "\n") {
+		if _, list, found := strings.Cut(line, "Jail list:"); found {
+			var jails []string
+			for _, name := range strings.Split(list, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					jails = append(jails, name)
+				}
+			}
+			return jails
+		}
+	}
+	return nil
+}
+
+var fail2banFieldPattern = regexp.MustCompile(`^[\s|` + "`" + `-]*([A-Za-z ]+):\s*(.*)$`)
+
+// ParseFail2banJail parses "fail2ban-client status <jail>"
+func ParseFail2banJail(output string) Jail {
+	var jail Jail
+	for _, line := range strings.Split(output, "\n") {
+		m := fail2banFieldPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		value := strings.TrimSpace(m[2])
+		count, _ := strconv.Atoi(value)
+		switch strings.TrimSpace(m[1]) {
+		case "Currently failed":
+			jail.CurrentlyFailed = count
+		case "Total failed":
+			jail.TotalFailed = count
+		case "Currently banned":
+			jail.CurrentlyBanned = count
+		case "Total banned":
+			jail.TotalBanned = count
+		case "Banned IP list":
+			jail.BannedIPs = strings.Fields(value)
+		}
+	}
+	return jail
+}
@@ -0,0 +1,378 @@
+// Package harden applies security hardening profiles to the local host.
+// Firewall rules are generated for nftables, ufw or Windows Firewall from
+// a small set of profiles; every apply saves a snapshot of the previous
+// firewall state so it can be rolled back.
+package harden
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvStateDir overrides the directory holding firewall snapshots
+const EnvStateDir = "PORTUNIX_HARDEN_DIR"
+
+// Rule allows inbound traffic to a local port
+type Rule struct {
+	Port    int    `json:"port"`
+	Proto   string `json:"proto"` // tcp or udp
+	Comment string `json:"comment"`
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%d/%s", r.Port, r.Proto)
+}
+
+// Profile is a firewall template: inbound traffic is denied except for the
+// listed rules, outbound traffic is allowed
+type Profile struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// SSH keeps the SSH port open so remote hosts stay reachable
+	SSH   bool   `json:"ssh"`
+	Rules []Rule `json:"rules"`
+}
+
+var profiles = map[string]Profile{
+	"edge": {
+		Name:        "edge",
+		Description: "Public edge/bastion host: SSH, HTTP(S) and WireGuard",
+		SSH:         true,
+		Rules: []Rule{
+			{Port: 80, Proto: "tcp", Comment: "HTTP"},
+			{Port: 443, Proto: "tcp", Comment: "HTTPS"},
+			{Port: 443, Proto: "udp", Comment: "HTTP/3"},
+			{Port: 51820, Proto: "udp", Comment: "WireGuard"},
+		},
+	},
+	"server": {
+		Name:        "server",
+		Description: "Server: SSH only, open service ports with --allow",
+		SSH:         true,
+	},
+	"workstation": {
+		Name:        "workstation",
+		Description: "Workstation: no inbound connections",
+	},
+}
+
+// ProfileNames returns the available firewall profiles
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProfileOptions customizes a profile
+type ProfileOptions struct {
+	SSHPort int      // SSH port of profiles with SSH, 22 when zero
+	Allow   []string // extra rules such as "8080/tcp" or "53/udp"
+}
+
+// GetProfile returns a profile with the options applied
+func GetProfile(name string, opts ProfileOptions) (Profile, error) {
+	p, ok := profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile: %s (available: %v)", name, ProfileNames())
+	}
+	rules := []Rule{}
+	if p.SSH {
+		port := opts.SSHPort
+		if port == 0 {
+			port = 22
+		}
+		if port < 1 || port > 65535 {
+			return Profile{}, fmt.Errorf("invalid SSH port: %d", port)
+		}
+		rules = append(rules, Rule{Port: port, Proto: "tcp", Comment: "SSH"})
+	}
+	rules = append(rules, p.Rules...)
+	for _, spec := range opts.Allow {
+		rule, err := ParseRule(spec)
+		if err != nil {
+			return Profile{}, err
+		}
+		rules = append(rules, rule)
+	}
+	p.Rules = dedupeRules(rules)
+	return p, nil
+}
+
+// ParseRule parses "port/proto"; the protocol defaults to tcp
+func ParseRule(spec string) (Rule, error) {
+	portText, proto, found := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), "/")
+	if !found {
+		proto = "tcp"
+	}
+	port, err := strconv.Atoi(portText)
+	if err != nil || port < 1 || port > 65535 {
+		return Rule{}, fmt.Errorf("invalid port in rule %q", spec)
+	}
+	if proto != "tcp" && proto != "udp" {
+		return Rule{}, fmt.Errorf("invalid protocol in rule %q: use tcp or udp", spec)
+	}
+	return Rule{Port: port, Proto: proto, Comment: "custom"}, nil
+}
+
+func dedupeRules(rules []Rule) []Rule {
+	seen := map[string]bool{}
+	var out []Rule
+	for _, r := range rules {
+		if seen[r.String()] {
+			continue
+		}
+		seen[r.String()] = true
+		out = append(out, r)
+	}
+	return out
+}
+
+// CommandRunner runs a system command and returns its combined output
+type CommandRunner func(name string, args ...string) ([]byte, error)
+
+func execRunner(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).CombinedOutput()
+}
+
+// PlanFile is a file written by a plan
+type PlanFile struct {
+	Path    string      `json:"path"`
+	Content string      `json:"content"`
+	Mode    os.FileMode `json:"mode"`
+}
+
+// Plan is the exact set of changes applying a profile makes
+type Plan struct {
+	Backend  string     `json:"backend"`
+	Profile  string     `json:"profile"`
+	Rules    []Rule     `json:"rules"`
+	Files    []PlanFile `json:"files,omitempty"`
+	Commands [][]string `json:"commands"`
+}
+
+// Backend is a firewall implementation
+type Backend interface {
+	Name() string
+	Plan(p Profile) (*Plan, error)
+	// Snapshot saves the current firewall state into dir
+	Snapshot(dir string) error
+	// Restore returns the firewall to a state saved by Snapshot
+	Restore(dir string) error
+	// Active reports whether the firewall is currently filtering
+	Active() (bool, error)
+}
+
+// BackendNames returns the supported backends
+func BackendNames() []string {
+	return []string{"nftables", "ufw", "windows"}
+}
+
+// Firewall plans, applies and rolls back firewall profiles
+type Firewall struct {
+	Backend Backend
+	Run     CommandRunner
+}
+
+// NewFirewall returns a firewall for the named backend; "auto" or an empty
+// name picks Windows Firewall on Windows, ufw when installed and nftables
+// otherwise
+func NewFirewall(backend string) (*Firewall, error) {
+	return newFirewall(backend, execRunner, exec.LookPath)
+}
+
+// NewFirewallWithRunner returns a firewall that runs commands through run
+func NewFirewallWithRunner(backend string, run CommandRunner) (*Firewall, error) {
+	lookPath := func(file string) (string, error) { return file, nil }
+	return newFirewall(backend, run, lookPath)
+}
+
+func newFirewall(backend string, run CommandRunner, lookPath func(string) (string, error)) (*Firewall, error) {
+	if backend == "" || backend == "auto" {
+		switch {
+		case runtime.GOOS == "windows":
+			backend = "windows"
+		case runtime.GOOS != "linux":
+			return nil, fmt.Errorf("firewall hardening is not supported on %s", runtime.GOOS)
+		default:
+			if _, err := lookPath("ufw"); err == nil {
+				backend = "ufw"
+			} else if _, err := lookPath("nft"); err == nil {
+				backend = "nftables"
+			} else {
+				return nil, fmt.Errorf("neither ufw nor nft found; install one with 'portunix install nftables'")
+			}
+		}
+	}
+
+	var b Backend
+	switch backend {
+	case "nftables":
+		b = &nftablesBackend{run: run}
+	case "ufw":
+		b = &ufwBackend{run: run}
+	case "windows":
+		b = &windowsBackend{run: run}
+	default:
+		return nil, fmt.Errorf("unsupported firewall backend: %s (supported: %v)", backend, BackendNames())
+	}
+	return &Firewall{Backend: b, Run: run}, nil
+}
+
+// StateDir returns the directory holding firewall snapshots
+func StateDir() string {
+	if dir := os.Getenv(EnvStateDir); dir != "" {
+		return dir
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramData"), "portunix", "harden", "firewall")
+	}
+	return "/var/lib/portunix/harden/firewall"
+}
+
+// Snapshot is a saved firewall state
+type Snapshot struct {
+	ID      string    `json:"id"`
+	Backend string    `json:"backend"`
+	Profile string    `json:"profile"`
+	Created time.Time `json:"created"`
+	Dir     string    `json:"-"`
+}
+
+// Plan returns the changes applying the profile makes
+func (f *Firewall) Plan(p Profile) (*Plan, error) {
+	return f.Backend.Plan(p)
+}
+
+// Apply snapshots the current firewall state and applies the plan. When a
+// step fails the snapshot is restored right away.
+func (f *Firewall) Apply(plan *Plan) (*Snapshot, error) {
+	snap := &Snapshot{
+		ID:      time.Now().Format("20060102-150405"),
+		Backend: plan.Backend,
+		Profile: plan.Profile,
+		Created: time.Now(),
+	}
+	snap.Dir = filepath.Join(StateDir(), snap.ID)
+	if err := os.MkdirAll(snap.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	if err := f.Backend.Snapshot(snap.Dir); err != nil {
+		os.RemoveAll(snap.Dir)
+		return nil, fmt.Errorf("failed to snapshot current firewall: %w", err)
+	}
+	data, _ := json.MarshalIndent(snap, "", "  ")
+	if err := os.WriteFile(filepath.Join(snap.Dir, "snapshot.json"), data, 0600); err != nil {
+		return nil, err
+	}
+	planData, _ := json.MarshalIndent(plan, "", "  ")
+	os.WriteFile(filepath.Join(snap.Dir, "plan.json"), planData, 0600)
+
+	if err := executePlan(plan, f.Run); err != nil {
+		if restoreErr := f.Backend.Restore(snap.Dir); restoreErr != nil {
+			return nil, fmt.Errorf("%v; restoring previous firewall also failed: %v", err, restoreErr)
+		}
+		os.RemoveAll(snap.Dir)
+		return nil, fmt.Errorf("%v (previous firewall restored)", err)
+	}
+	return snap, nil
+}
+
+// executePlan writes the files of a plan and runs its commands in order
+func executePlan(plan *Plan, run CommandRunner) error {
+	for _, file := range plan.Files {
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file.Path, []byte(file.Content), file.Mode); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+	}
+	for _, command := range plan.Commands {
+		if out, err := run(command[0], command[1:]...); err != nil {
+			return commandError(strings.Join(command, " "), err, out)
+		}
+	}
+	return nil
+}
+
+// FirewallStatus summarizes the current firewall
+type FirewallStatus struct {
+	Backend string `json:"backend"`
+	Active  bool   `json:"active"`
+	// Profile is the profile of the last apply, empty when none is recorded
+	Profile   string     `json:"profile,omitempty"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Status reports whether the firewall is active and which profile was
+// applied last
+func (f *Firewall) Status() (*FirewallStatus, error) {
+	active, err := f.Backend.Active()
+	if err != nil {
+		return nil, err
+	}
+	status := &FirewallStatus{Backend: f.Backend.Name(), Active: active}
+	if snaps, err := Snapshots(); err == nil && len(snaps) > 0 && snaps[0].Backend == status.Backend {
+		status.Profile = snaps[0].Profile
+		status.AppliedAt = &snaps[0].Created
+	}
+	return status, nil
+}
+
+// Snapshots returns the saved snapshots, newest first
+func Snapshots() ([]*Snapshot, error) {
+	entries, err := os.ReadDir(StateDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var snaps []*Snapshot
+	for _, entry := range entries {
+		dir := filepath.Join(StateDir(), entry.Name())
+		data, err := os.ReadFile(filepath.Join(dir, "snapshot.json"))
+		if err != nil {
+			continue
+		}
+		var snap Snapshot
+		if json.Unmarshal(data, &snap) != nil {
+			continue
+		}
+		snap.Dir = dir
+		snaps = append(snaps, &snap)
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].ID > snaps[j].ID })
+	return snaps, nil
+}
+
+// Rollback restores the firewall state saved before the last apply and
+// discards that snapshot, so repeated rollbacks step further back
+func (f *Firewall) Rollback() (*Snapshot, error) {
+	snaps, err := Snapshots()
+	if err != nil {
+		return nil, err
+	}
+	if len(snaps) == 0 {
+		return nil, fmt.Errorf("no firewall snapshot to roll back to")
+	}
+	snap := snaps[0]
+	if snap.Backend != f.Backend.Name() {
+		return nil, fmt.Errorf("last snapshot was taken with %s, not %s", snap.Backend, f.Backend.Name())
+	}
+	if err := f.Backend.Restore(snap.Dir); err != nil {
+		return nil, err
+	}
+	return snap, os.RemoveAll(snap.Dir)
+}
@@ -0,0 +1,138 @@
+package harden
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	nftTable    = "portunix_filter"
+	nftRuleset  = "/etc/portunix/firewall.nft"
+	nftUnitName = "portunix-firewall.service"
+	nftUnitPath = "/etc/systemd/system/" + nftUnitName
+)
+
+// nftablesTemplate only manages its own table, so rules of other tools
+// (Docker, libvirt) are left alone. Declaring and deleting the table first
+// makes loading the file idempotent.
+var nftablesTemplate = template.Must(template.New("nftables").Parse(`#!/usr/sbin/nft -f
+# Managed by portunix harden firewall (profile {{.Name}})
+table inet {{.Table}}
+delete table inet {{.Table}}
+
+table inet {{.Table}} {
+	chain input {
+		type filter hook input priority filter; policy drop;
+		ct state established,related accept
+		ct state invalid drop
+		iif "lo" accept
+		meta l4proto { icmp, ipv6-icmp } accept
+{{- range .Rules}}
+		{{.Proto}} dport {{.Port}} accept comment "{{.Comment}}"
+{{- end}}
+	}
+}
+`))
+
+var nftablesUnit = `[Unit]
+Description=Portunix firewall rules
+Wants=network-pre.target
+Before=network-pre.target
+
+[Service]
+Type=oneshot
+ExecStart=/usr/sbin/nft -f ` + nftRuleset + `
+RemainAfterExit=yes
+
+[Install]
+WantedBy=multi-user.target
+`
+
+type nftablesBackend struct {
+	run CommandRunner
+}
+
+func (b *nftablesBackend) Name() string {
+	return "nftables"
+}
+
+func (b *nftablesBackend) Plan(p Profile) (*Plan, error) {
+	var ruleset bytes.Buffer
+	data := struct {
+		Profile
+		Table string
+	}{p, nftTable}
+	if err := nftablesTemplate.Execute(&ruleset, data); err != nil {
+		return nil, err
+	}
+	return &Plan{
+		Backend: b.Name(),
+		Profile: p.Name,
+		Rules:   p.Rules,
+		Files: []PlanFile{
+			{Path: nftRuleset, Content: ruleset.String(), Mode: 0644},
+			{Path: nftUnitPath, Content: nftablesUnit, Mode: 0644},
+		},
+		Commands: [][]string{
+			{"nft", "-c", "-f", nftRuleset},
+			{"nft", "-f", nftRuleset},
+			{"systemctl", "daemon-reload"},
+			{"systemctl", "enable", nftUnitName},
+		},
+	}, nil
+}
+
+func (b *nftablesBackend) Snapshot(dir string) error {
+	// A missing table means portunix rules were not active
+	if out, err := b.run("nft", "list", "table", "inet", nftTable); err == nil {
+		if err := os.WriteFile(filepath.Join(dir, "previous.nft"), out, 0600); err != nil {
+			return err
+		}
+	}
+	if data, err := os.ReadFile(nftRuleset); err == nil {
+		return os.WriteFile(filepath.Join(dir, "firewall.nft"), data, 0600)
+	}
+	return nil
+}
+
+func (b *nftablesBackend) Restore(dir string) error {
+	b.run("nft", "delete", "table", "inet", nftTable)
+	previous := filepath.Join(dir, "previous.nft")
+	if _, err := os.Stat(previous); err == nil {
+		if out, err := b.run("nft", "-f", previous); err != nil {
+			return commandError("nft -f "+previous, err, out)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "firewall.nft")); err == nil {
+		return os.WriteFile(nftRuleset, data, 0644)
+	}
+	// Rules were not persisted before the apply
+	b.run("systemctl", "disable", nftUnitName)
+	os.Remove(nftUnitPath)
+	os.Remove(nftRuleset)
+	return nil
+}
+
+// Active reports whether the portunix table is loaded
+func (b *nftablesBackend) Active() (bool, error) {
+	_, err := b.run("nft", "list", "table", "inet", nftTable)
+	return err == nil, nil
+}
+
+func commandError(command string, err error, out []byte) error {
+	return &CommandError{Command: command, Err: err, Output: strings.TrimSpace(string(out))}
+}
+
+// CommandError is a failed firewall command
+type CommandError struct {
+	Command string
+	Err     error
+	Output  string
+}
+
+func (e *CommandError) Error() string {
+	return e.Command + " failed: " + e.Err.Error() + ": " + e.Output
+}
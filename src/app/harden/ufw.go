@@ -0,0 +1,130 @@
+package harden
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type ufwBackend struct {
+	run CommandRunner
+}
+
+func (b *ufwBackend) Name() string {
+	return "ufw"
+}
+
+func (b *ufwBackend) Plan(p Profile) (*Plan, error) {
+	// Allow rules go first so an active firewall never blocks SSH in between
+	var commands [][]string
+	for _, r := range p.Rules {
+		commands = append(commands, []string{"ufw", "allow", r.String(), "comment", r.Comment})
+	}
+	commands = append(commands,
+		[]string{"ufw", "default", "deny", "incoming"},
+		[]string{"ufw", "default", "allow", "outgoing"},
+		[]string{"ufw", "--force", "enable"},
+	)
+	return &Plan{Backend: b.Name(), Profile: p.Name, Rules: p.Rules, Commands: commands}, nil
+}
+
+// Snapshot saves "ufw status verbose" for the default policies and the
+// enabled state, and "ufw show added" for the user rules
+func (b *ufwBackend) Snapshot(dir string) error {
+	status, err := b.run("ufw", "status", "verbose")
+	if err != nil {
+		return commandError("ufw status verbose", err, status)
+	}
+	added, err := b.run("ufw", "show", "added")
+	if err != nil {
+		return commandError("ufw show added", err, added)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ufw-status.txt"), status, 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "ufw-added.txt"), added, 0600)
+}
+
+// Restore deletes the rules added since the snapshot and resets the default
+// policies and the enabled state
+func (b *ufwBackend) Restore(dir string) error {
+	status, err := os.ReadFile(filepath.Join(dir, "ufw-status.txt"))
+	if err != nil {
+		return err
+	}
+	before, err := os.ReadFile(filepath.Join(dir, "ufw-added.txt"))
+	if err != nil {
+		return err
+	}
+	current, err := b.run("ufw", "show", "added")
+	if err != nil {
+		return commandError("ufw show added", err, current)
+	}
+
+	previous := map[string]bool{}
+	for _, rule := range UfwAddedRules(string(before)) {
+		previous[strings.Join(rule, " ")] = true
+	}
+	for _, rule := range UfwAddedRules(string(current)) {
+		if previous[strings.Join(rule, " ")] {
+			continue
+		}
+		args := append([]string{"delete"}, rule...)
+		if out, err := b.run("ufw", args...); err != nil {
+			return commandError("ufw "+strings.Join(args, " "), err, out)
+		}
+	}
+
+	incoming, outgoing, active := parseUfwStatus(string(status))
+	for direction, policy := range map[string]string{"incoming": incoming, "outgoing": outgoing} {
+		if policy == "" {
+			continue
+		}
+		if out, err := b.run("ufw", "default", policy, direction); err != nil {
+			return commandError(fmt.Sprintf("ufw default %s %s", policy, direction), err, out)
+		}
+	}
+	if !active {
+		if out, err := b.run("ufw", "disable"); err != nil {
+			return commandError("ufw disable", err, out)
+		}
+	}
+	return nil
+}
+
+func (b *ufwBackend) Active() (bool, error) {
+	status, err := b.run("ufw", "status")
+	if err != nil {
+		return false, commandError("ufw status", err, status)
+	}
+	_, _, active := parseUfwStatus(string(status))
+	return active, nil
+}
+
+// UfwAddedRules parses "ufw show added" into rule arguments without the
+// comment, e.g. ["allow", "22/tcp"]
+func UfwAddedRules(output string) [][]string {
+	var rules [][]string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "ufw ") {
+			continue
+		}
+		if i := strings.Index(line, " comment "); i >= 0 {
+			line = line[:i]
+		}
+		rules = append(rules, strings.Fields(line)[1:])
+	}
+	return rules
+}
+
+var ufwDefaultPattern = regexp.MustCompile(`Default: (\w+) \(incoming\), (\w+) \(outgoing\)`)
+
+func parseUfwStatus(status string) (incoming, outgoing string, active bool) {
+	if m := ufwDefaultPattern.FindStringSubmatch(status); m != nil {
+		incoming, outgoing = m[1], m[2]
+	}
+	return incoming, outgoing, strings.Contains(status, "Status: active")
+}
@@ -0,0 +1,68 @@
+package harden
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// windowsRuleGroup groups the rules created by portunix so they can be
+// replaced as a whole
+const windowsRuleGroup = "Portunix"
+
+type windowsBackend struct {
+	run CommandRunner
+}
+
+func (b *windowsBackend) Name() string {
+	return "windows"
+}
+
+func (b *windowsBackend) Plan(p Profile) (*Plan, error) {
+	commands := [][]string{
+		powershell(fmt.Sprintf("Remove-NetFirewallRule -Group '%s' -ErrorAction SilentlyContinue; exit 0", windowsRuleGroup)),
+		powershell("Set-NetFirewallProfile -All -Enabled True -DefaultInboundAction Block -DefaultOutboundAction Allow"),
+	}
+	for _, r := range p.Rules {
+		commands = append(commands, powershell(fmt.Sprintf(
+			"New-NetFirewallRule -Group '%s' -DisplayName '%s %s %s' -Direction Inbound -Action Allow -Protocol %s -LocalPort %d | Out-Null",
+			windowsRuleGroup, windowsRuleGroup, r.Comment, r.String(), strings.ToUpper(r.Proto), r.Port)))
+	}
+	return &Plan{Backend: b.Name(), Profile: p.Name, Rules: p.Rules, Commands: commands}, nil
+}
+
+func powershell(script string) []string {
+	return []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", script}
+}
+
+// Active reports whether all firewall profiles are enabled
+func (b *windowsBackend) Active() (bool, error) {
+	command := powershell("(Get-NetFirewallProfile | Where-Object { -not $_.Enabled }).Count")
+	out, err := b.run(command[0], command[1:]...)
+	if err != nil {
+		return false, commandError("Get-NetFirewallProfile", err, out)
+	}
+	return strings.TrimSpace(string(out)) == "0", nil
+}
+
+// Snapshot exports the complete firewall policy
+func (b *windowsBackend) Snapshot(dir string) error {
+	file := filepath.Join(dir, "policy.wfw")
+	if out, err := b.run("netsh", "advfirewall", "export", file); err != nil {
+		return commandError("netsh advfirewall export", err, out)
+	}
+	return nil
+}
+
+// Restore imports the exported policy, replacing all current rules
+func (b *windowsBackend) Restore(dir string) error {
+	file := filepath.Join(dir, "policy.wfw")
+	if _, err := os.Stat(file); err != nil {
+		return fmt.Errorf("snapshot policy missing: %w", err)
+	}
+	if out, err := b.run("netsh", "advfirewall", "import", file); err != nil {
+		return commandError("netsh advfirewall import", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+// Package ide connects editors to machines portunix manages: VS Code
+// Remote-SSH and JetBrains Gateway to VMs and SSH hosts, VS Code Dev
+// Containers to running containers.
+package ide
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"portunix.ai/app/container"
+	"portunix.ai/app/remote"
+	"portunix.ai/app/virt"
+)
+
+// Editors
+const (
+	VSCode    = "vscode"
+	JetBrains = "jetbrains"
+)
+
+// Target kinds
+const (
+	KindVM        = "vm"
+	KindContainer = "container"
+	KindHost      = "host" // fleet hosts, edge deployments, user@host
+)
+
+// AliasPrefix starts the SSH host aliases portunix writes
+const AliasPrefix = "portunix-"
+
+// Options controls Connect
+type Options struct {
+	Editor string
+	// Kind forces the target kind; empty detects it
+	Kind string
+	// Path is the folder to open; empty opens the home directory of VMs and
+	// the working directory of containers
+	Path string
+	// Runtime is the container runtime; empty uses the configured one
+	Runtime string
+	// Product is the JetBrains IDE product code, e.g. IU or GO
+	Product string
+}
+
+// Connection tells how to open an editor on a target
+type Connection struct {
+	Name   string   `json:"name"`
+	Kind   string   `json:"kind"`
+	Editor string   `json:"editor"`
+	Path   string   `json:"path,omitempty"`
+	Host   *SSHHost `json:"ssh_host,omitempty"`
+	// Command opens the editor; empty when only URL does
+	Command []string `json:"command,omitempty"`
+	// URL opens the editor from a browser or the desktop
+	URL   string   `json:"url"`
+	Notes []string `json:"notes,omitempty"`
+}
+
+// Connect resolves the target and returns how to open the editor on it.
+// For SSH targets the host entry still has to be written with
+// UpdateHosts.
+func Connect(name string, opts Options) (*Connection, error) {
+	if opts.Editor == "" {
+		opts.Editor = VSCode
+	}
+	if opts.Editor != VSCode && opts.Editor != JetBrains {
+		return nil, fmt.Errorf("unknown editor %q (use %s or %s)", opts.Editor, VSCode, JetBrains)
+	}
+
+	kind := opts.Kind
+	if kind == "" {
+		kind = detectKind(name, opts.Runtime)
+	}
+	switch kind {
+	case KindContainer:
+		return connectContainer(name, opts)
+	case KindVM, KindHost:
+		t, err := remote.Resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		return connectSSH(name, kind, t, opts)
+	default:
+		return nil, fmt.Errorf("unknown target kind %q (use %s or %s)", kind, KindVM, KindContainer)
+	}
+}
+
+// detectKind returns whether name is a VM created by portunix, a container
+// of the runtime or another SSH host
+func detectKind(name, rt string) string {
+	if virt.ValidateInstanceName(name) == nil {
+		if _, err := virt.LoadInstance(name); err == nil {
+			return KindVM
+		}
+	}
+	if rt == "" {
+		rt = containerRuntime()
+	}
+	if rt != "" && exec.Command(rt, "container", "inspect", name).Run() == nil {
+		return KindContainer
+	}
+	return KindHost
+}
+
+// Alias returns the SSH host alias of a target
+func Alias(name string) string {
+	alias := strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return '-'
+	}, name)
+	return AliasPrefix + alias
+}
+
+func connectSSH(name, kind string, t *remote.Target, opts Options) (*Connection, error) {
+	c := &Connection{
+		Name:   name,
+		Kind:   kind,
+		Editor: opts.Editor,
+		Path:   opts.Path,
+		Host: &SSHHost{
+			Alias:            Alias(name),
+			HostName:         t.Host,
+			User:             t.User,
+			Port:             t.Port,
+			IdentityFile:     t.IdentityFile,
+			KnownHosts:       t.KnownHosts,
+			AcceptNewHostKey: t.AcceptNewHostKey,
+		},
+	}
+	if c.Path == "" {
+		c.Path = homeDir(t.User)
+	}
+
+	switch opts.Editor {
+	case VSCode:
+		c.Command = []string{"code", "--remote", "ssh-remote+" + c.Host.Alias, c.Path}
+		c.URL = "vscode://vscode-remote/ssh-remote+" + c.Host.Alias + c.Path
+	case JetBrains:
+		c.URL = GatewayURL(c.Host, c.Path, opts.Product)
+		if t.IdentityFile != "" {
+			c.Notes = append(c.Notes, fmt.Sprintf("If Gateway asks for credentials, choose the %s entry of ~/.ssh/config, which has the key of the VM", c.Host.Alias))
+		}
+	}
+	return c, nil
+}
+
+// homeDir returns the usual home directory of a user on Linux
+func homeDir(user string) string {
+	switch user {
+	case "":
+		return "/"
+	case "root":
+		return "/root"
+	}
+	return "/home/" + user
+}
+
+// GatewayURL returns the jetbrains-gateway:// link that opens a project on
+// an SSH host, deploying the IDE backend when needed
+func GatewayURL(h *SSHHost, path, product string) string {
+	params := url.Values{}
+	params.Set("type", "ssh")
+	params.Set("deploy", "true")
+	params.Set("host", h.HostName)
+	params.Set("user", h.User)
+	port := h.Port
+	if port == 0 {
+		port = 22
+	}
+	params.Set("port", strconv.Itoa(port))
+	params.Set("projectPath", path)
+	if product != "" {
+		params.Set("productCode", product)
+	}
+	return "jetbrains-gateway://connect#" + params.Encode()
+}
+
+// AttachedContainerURI returns the VS Code folder URI of a folder in a
+// running container, opened with the Dev Containers extension
+func AttachedContainerURI(name, path string) string {
+	spec, _ := json.Marshal(map[string]string{"containerName": "/" + strings.TrimPrefix(name, "/")})
+	return "vscode-remote://attached-container+" + hex.EncodeToString(spec) + path
+}
+
+func connectContainer(name string, opts Options) (*Connection, error) {
+	if opts.Editor == JetBrains {
+		return nil, fmt.Errorf("JetBrains Gateway connects over SSH; open container %s with --editor vscode, or use a VM", name)
+	}
+	rt := opts.Runtime
+	if rt == "" {
+		rt = containerRuntime()
+	}
+	if rt == "" {
+		return nil, fmt.Errorf("no container runtime found")
+	}
+	output, err := exec.Command(rt, "container", "inspect", "--format", "{{.State.Running}} {{.Config.WorkingDir}}", name).Output()
+	if err != nil {
+		return nil, fmt.Errorf("container %s not found in %s", name, rt)
+	}
+	running, workdir, _ := strings.Cut(strings.TrimSpace(string(output)), " ")
+	if running != "true" {
+		return nil, fmt.Errorf("container %s is not running; start it with 'portunix container start %s'", name, name)
+	}
+
+	c := &Connection{Name: name, Kind: KindContainer, Editor: opts.Editor, Path: opts.Path}
+	if c.Path == "" {
+		c.Path = workdir
+	}
+	if c.Path == "" {
+		c.Path = "/"
+	}
+	uri := AttachedContainerURI(name, c.Path)
+	c.Command = []string{"code", "--folder-uri", uri}
+	c.URL = strings.Replace(uri, "vscode-remote://", "vscode://vscode-remote/", 1)
+	c.Notes = append(c.Notes, "Needs the Dev Containers extension (ms-vscode-remote.remote-containers)")
+	if rt == "podman" {
+		c.Notes = append(c.Notes, `For Podman set "dev.containers.dockerPath": "podman" in the VS Code settings`)
+	}
+	return c, nil
+}
+
+// containerRuntime returns the configured container runtime, or the one
+// installed
+func containerRuntime() string {
+	if rt, err := container.GetSelectedRuntime(); err == nil {
+		return rt
+	}
+	for _, rt := range []string{"docker", "podman"} {
+		if _, err := exec.LookPath(rt); err == nil {
+			return rt
+		}
+	}
+	return ""
+}
+
+// OpenCommand returns the command that opens the connection: the editor
+// command, or the URL with the desktop's opener
+func (c *Connection) OpenCommand() []string {
+	if len(c.Command) > 0 {
+		if _, err := exec.LookPath(c.Command[0]); err == nil {
+			return c.Command
+		}
+	}
+	switch runtime.GOOS {
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler", c.URL}
+	case "darwin":
+		return []string{"open", c.URL}
+	default:
+		return []string{"xdg-open", c.URL}
+	}
+}
@@ -0,0 +1,79 @@
+package ide
+
+import (
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUpdateHosts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ssh", "config")
+	vm := &SSHHost{
+		Alias:            Alias("ubuntu-dev"),
+		HostName:         "192.168.122.10",
+		User:             "ubuntu",
+		IdentityFile:     "/home/me/.portunix/virt/instances/ubuntu-dev/id_ed25519",
+		KnownHosts:       "/home/me/.portunix/virt/instances/ubuntu-dev/known_hosts",
+		AcceptNewHostKey: true,
+	}
+	edge := &SSHHost{Alias: Alias("edge eu"), HostName: "203.0.113.5", User: "root", Port: 2222}
+	if err := UpdateHosts(path, vm, edge); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new address replaces the entry in place
+	vm.HostName = "192.168.122.20"
+	if err := UpdateHosts(path, vm); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	config := string(data)
+	if strings.Count(config, "Host portunix-ubuntu-dev\n") != 1 || !strings.Contains(config, "HostName 192.168.122.20") ||
+		strings.Contains(config, "192.168.122.10") {
+		t.Errorf("entry not replaced:\n%s", config)
+	}
+	if strings.Index(config, "portunix-ubuntu-dev") > strings.Index(config, "portunix-edge-eu") {
+		t.Errorf("entries reordered:\n%s", config)
+	}
+	for _, want := range []string{"StrictHostKeyChecking accept-new", "IdentitiesOnly yes", "Port 2222"} {
+		if !strings.Contains(config, want) {
+			t.Errorf("no %q in:\n%s", want, config)
+		}
+	}
+}
+
+func TestEnsureInclude(t *testing.T) {
+	dir := t.TempDir()
+	userConfig := filepath.Join(dir, "config")
+	os.WriteFile(userConfig, []byte("Host work\n  HostName work.example.com\n"), 0600)
+
+	included := filepath.Join(dir, "portunix", "config")
+	for i, want := range []bool{true, false} {
+		changed, err := EnsureInclude(userConfig, included)
+		if err != nil || changed != want {
+			t.Errorf("call %d: changed %v, %v", i+1, changed, err)
+		}
+	}
+	data, _ := os.ReadFile(userConfig)
+	if !strings.HasPrefix(string(data), "# Added by portunix") || !strings.Contains(string(data), "Include "+included+"\n\nHost work") {
+		t.Errorf("include not before the hosts:\n%s", data)
+	}
+}
+
+func TestEditorLinks(t *testing.T) {
+	uri := AttachedContainerURI("dev", "/workspace")
+	spec, err := hex.DecodeString(strings.TrimSuffix(strings.TrimPrefix(uri, "vscode-remote://attached-container+"), "/workspace"))
+	if err != nil || string(spec) != `{"containerName":"/dev"}` {
+		t.Errorf("URI %s: %s, %v", uri, spec, err)
+	}
+
+	link := GatewayURL(&SSHHost{Alias: "portunix-vm", HostName: "10.0.0.5", User: "ubuntu"}, "/home/ubuntu", "GO")
+	fragment, _ := strings.CutPrefix(link, "jetbrains-gateway://connect#")
+	params, err := url.ParseQuery(fragment)
+	if err != nil || params.Get("host") != "10.0.0.5" || params.Get("port") != "22" || params.Get("projectPath") != "/home/ubuntu" || params.Get("productCode") != "GO" {
+		t.Errorf("link %s", link)
+	}
+}
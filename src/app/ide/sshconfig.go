@@ -0,0 +1,164 @@
+package ide
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SSHHost is a host entry of the SSH configuration portunix maintains for
+// editors
+type SSHHost struct {
+	Alias        string `json:"alias"`
+	HostName     string `json:"hostname"`
+	User         string `json:"user,omitempty"`
+	Port         int    `json:"port,omitempty"`
+	IdentityFile string `json:"identity_file,omitempty"`
+	KnownHosts   string `json:"known_hosts,omitempty"`
+	// AcceptNewHostKey trusts the host key on first use, for VMs whose key
+	// is created at first boot
+	AcceptNewHostKey bool `json:"accept_new_host_key,omitempty"`
+}
+
+// sshValue quotes a value of an ssh_config option if it contains spaces
+func sshValue(s string) string {
+	if strings.ContainsAny(s, " \t") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// Block returns the Host block of the entry
+func (h *SSHHost) Block() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Host %s\n", h.Alias)
+	fmt.Fprintf(&b, "  HostName %s\n", h.HostName)
+	if h.User != "" {
+		fmt.Fprintf(&b, "  User %s\n", h.User)
+	}
+	if h.Port != 0 && h.Port != 22 {
+		fmt.Fprintf(&b, "  Port %s\n", strconv.Itoa(h.Port))
+	}
+	if h.IdentityFile != "" {
+		fmt.Fprintf(&b, "  IdentityFile %s\n", sshValue(h.IdentityFile))
+		b.WriteString("  IdentitiesOnly yes\n")
+	}
+	if h.KnownHosts != "" {
+		checking := "yes"
+		if h.AcceptNewHostKey {
+			checking = "accept-new"
+		}
+		fmt.Fprintf(&b, "  UserKnownHostsFile %s\n", sshValue(h.KnownHosts))
+		fmt.Fprintf(&b, "  StrictHostKeyChecking %s\n", checking)
+	}
+	return b.String()
+}
+
+// SSHConfigPath returns the SSH configuration portunix maintains. It is
+// included from ~/.ssh/config, so ssh, VS Code and JetBrains Gateway know
+// the hosts by their alias.
+func SSHConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-ssh-config")
+	}
+	return filepath.Join(home, ".portunix", "ssh", "config")
+}
+
+// UserSSHConfigPath returns ~/.ssh/config
+func UserSSHConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ssh", "config")
+}
+
+// splitHosts splits an SSH configuration into the text before the first
+// Host line and the Host blocks keyed by alias, in order
+func splitHosts(config string) (string, []string, map[string]string) {
+	var head strings.Builder
+	var order []string
+	blocks := make(map[string]string)
+	current := ""
+	for _, line := range strings.SplitAfter(config, "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && strings.EqualFold(fields[0], "Host") {
+			current = fields[1]
+			if _, ok := blocks[current]; !ok {
+				order = append(order, current)
+			}
+			blocks[current] = ""
+		}
+		if current == "" {
+			head.WriteString(line)
+		} else if strings.TrimSpace(line) != "" {
+			blocks[current] += line
+		}
+	}
+	return head.String(), order, blocks
+}
+
+// UpdateHosts adds or replaces the entries in the SSH configuration at path
+func UpdateHosts(path string, hosts ...*SSHHost) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	head, order, blocks := splitHosts(string(data))
+	if head == "" {
+		head = "# Hosts of portunix VMs and containers, written by 'portunix ide connect'\n\n"
+	}
+	for _, h := range hosts {
+		if _, ok := blocks[h.Alias]; !ok {
+			order = append(order, h.Alias)
+		}
+		blocks[h.Alias] = h.Block()
+	}
+
+	var b strings.Builder
+	b.WriteString(head)
+	for i, alias := range order {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(blocks[alias])
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(b.String()), 0600)
+}
+
+// EnsureInclude makes the SSH configuration at userConfig include the one
+// portunix maintains. The Include goes first: after a Host line it would
+// only apply to that host. It reports whether userConfig was changed.
+func EnsureInclude(userConfig, included string) (bool, error) {
+	data, err := os.ReadFile(userConfig)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read %s: %w", userConfig, err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && strings.EqualFold(fields[0], "Include") {
+			for _, f := range fields[1:] {
+				if strings.Trim(f, `"`) == included {
+					return false, nil
+				}
+			}
+		}
+	}
+	include := "# Added by portunix: VMs and containers for editors\nInclude " + sshValue(included) + "\n\n"
+	if err := os.MkdirAll(filepath.Dir(userConfig), 0700); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(userConfig, append([]byte(include), data...), 0600); err != nil {
+		return false, fmt.Errorf("failed to update %s: %w", userConfig, err)
+	}
+	return true, nil
+}
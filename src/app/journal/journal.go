@@ -0,0 +1,489 @@
+// Package journal records what portunix changed on the machine - files
+// written, packages installed, containers created - so an operation can be
+// reversed with "portunix undo <op-id>". The dispatcher opens an operation
+// for every helper command and passes its ID to the helper, which attaches
+// the changes it made; a command that runs several helpers is one operation.
+// The journal is kept in ~/.portunix/journal, with the previous content of
+// overwritten files under backups/<op-id>.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"portunix.ai/app/metrics"
+)
+
+// Environment variables
+const (
+	// EnvDir overrides the journal directory
+	EnvDir = "PORTUNIX_JOURNAL_DIR"
+	// EnvOperation is set by the dispatcher to the ID of the running
+	// operation; helpers record nothing without it
+	EnvOperation = "PORTUNIX_JOURNAL_OP"
+	// EnvCommand is set by the dispatcher to the command of the operation
+	EnvCommand = "PORTUNIX_JOURNAL_COMMAND"
+)
+
+// Change kinds
+const (
+	FileCreated      = "file_created"
+	FileModified     = "file_modified"
+	PackageInstalled = "package_installed"
+	ContainerCreated = "container_created"
+)
+
+const (
+	journalFile = "journal.jsonl"
+	backupsDir  = "backups"
+)
+
+// ErrNotFound is returned by Undo for an unknown operation ID
+var ErrNotFound = errors.New("operation not found")
+
+// Change is a single change of an operation
+type Change struct {
+	Kind    string `json:"kind"`
+	Target  string `json:"target"`            // File path, package name or container ID
+	Backup  string `json:"backup,omitempty"`  // Previous file content, relative to the journal directory
+	Hash    string `json:"hash,omitempty"`    // SHA-256 of the file as written, to detect later edits
+	Version string `json:"version,omitempty"` // Installed package version
+	Runtime string `json:"runtime,omitempty"` // docker or podman
+}
+
+// Operation is a recorded portunix command and the changes it made
+type Operation struct {
+	ID        string     `json:"id"`
+	Time      time.Time  `json:"time"`
+	Command   string     `json:"command,omitempty"`
+	Component string     `json:"component,omitempty"` // Helper that made the changes
+	Changes   []Change   `json:"changes,omitempty"`
+	UndoneAt  *time.Time `json:"undone_at,omitempty"`
+}
+
+// Reversible reports whether the operation made changes undo can reverse
+func (o *Operation) Reversible() bool {
+	return o.UndoneAt == nil && len(o.Changes) > 0
+}
+
+// entry is a line of the journal: the changes of an operation as recorded by
+// one component, or the undo of an operation
+type entry struct {
+	Operation
+	Undo string `json:"undo,omitempty"`
+}
+
+// Dir returns the journal directory
+func Dir() string {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-journal")
+	}
+	return filepath.Join(home, ".portunix", "journal")
+}
+
+// StorePath returns the journal file
+func StorePath() string {
+	return filepath.Join(Dir(), journalFile)
+}
+
+// NewID returns a new operation ID: the start time and a random suffix, so
+// IDs sort by time
+func NewID() string {
+	suffix := make([]byte, 2)
+	rand.Read(suffix)
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// Start opens an operation for a helper command by exporting its ID and
+// command to the helper process. Portunix run by a helper joins the
+// operation of its parent.
+func Start(args []string) {
+	if os.Getenv(EnvOperation) != "" {
+		return
+	}
+	command, pkg := metrics.CommandFromArgs(args)
+	if pkg != "" {
+		command += " " + pkg
+	}
+	os.Setenv(EnvOperation, NewID())
+	os.Setenv(EnvCommand, command)
+}
+
+// Op collects the changes a component makes during an operation. A nil Op
+// records nothing and is safe to use.
+type Op struct {
+	op     Operation
+	saved  map[string]bool
+	failed bool
+}
+
+// Current returns the operation opened by the dispatcher, nil when the
+// component does not run under one
+func Current(component string) *Op {
+	id := os.Getenv(EnvOperation)
+	if id == "" {
+		return nil
+	}
+	return &Op{
+		op:    Operation{ID: id, Command: os.Getenv(EnvCommand), Component: component},
+		saved: make(map[string]bool),
+	}
+}
+
+// ID returns the operation ID
+func (o *Op) ID() string {
+	if o == nil {
+		return ""
+	}
+	return o.op.ID
+}
+
+// SaveFile must be called before a file is written. It keeps the previous
+// content of an existing file for undo; a new file is removed on undo.
+func (o *Op) SaveFile(path string) {
+	if o == nil {
+		return
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if o.saved[path] {
+		return
+	}
+	o.saved[path] = true
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		o.op.Changes = append(o.op.Changes, Change{Kind: FileCreated, Target: path})
+		return
+	}
+	if err != nil {
+		o.failed = true
+		return
+	}
+
+	backup := filepath.Join(backupsDir, o.op.ID, o.op.Component+"-"+strconv.Itoa(len(o.op.Changes)))
+	if err := os.MkdirAll(filepath.Join(Dir(), filepath.Dir(backup)), 0700); err != nil {
+		o.failed = true
+		return
+	}
+	if err := os.WriteFile(filepath.Join(Dir(), backup), content, 0600); err != nil {
+		o.failed = true
+		return
+	}
+	o.op.Changes = append(o.op.Changes, Change{Kind: FileModified, Target: path, Backup: backup})
+}
+
+// PackageInstalled records a package installed by portunix
+func (o *Op) PackageInstalled(name, version string) {
+	if o == nil {
+		return
+	}
+	o.op.Changes = append(o.op.Changes, Change{Kind: PackageInstalled, Target: name, Version: version})
+}
+
+// ContainerCreated records a container left behind by the operation
+func (o *Op) ContainerCreated(runtime, id string) {
+	if o == nil || id == "" {
+		return
+	}
+	o.op.Changes = append(o.op.Changes, Change{Kind: ContainerCreated, Target: id, Runtime: runtime})
+}
+
+// Commit appends the recorded changes to the journal. Saved files are hashed
+// as they are now, so undo notices when they were edited afterwards.
+func (o *Op) Commit() error {
+	if o == nil || len(o.op.Changes) == 0 {
+		return nil
+	}
+	if o.failed {
+		return fmt.Errorf("operation %s: previous file content could not be saved", o.op.ID)
+	}
+	o.op.Time = time.Now()
+	for i := range o.op.Changes {
+		change := &o.op.Changes[i]
+		if change.Kind == FileCreated || change.Kind == FileModified {
+			change.Hash = hashFile(change.Target)
+		}
+	}
+	return appendEntry(entry{Operation: o.op})
+}
+
+// appendEntry appends a line to the journal
+func appendEntry(e entry) error {
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(StorePath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Load reads the recorded operations, oldest first. Entries of the same
+// operation from several components are merged.
+func Load() ([]*Operation, error) {
+	f, err := os.Open(StorePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var operations []*Operation
+	byID := make(map[string]*Operation)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if e.Undo != "" {
+			if op := byID[e.Undo]; op != nil {
+				undoneAt := e.Time
+				op.UndoneAt = &undoneAt
+			}
+			continue
+		}
+		op := byID[e.ID]
+		if op == nil {
+			op = &Operation{ID: e.ID, Time: e.Time, Command: e.Command, Component: e.Component}
+			byID[e.ID] = op
+			operations = append(operations, op)
+		} else if !strings.Contains(","+op.Component+",", ","+e.Component+",") {
+			op.Component += "," + e.Component
+		}
+		op.Changes = append(op.Changes, e.Changes...)
+	}
+	sort.SliceStable(operations, func(i, j int) bool { return operations[i].ID < operations[j].ID })
+	return operations, scanner.Err()
+}
+
+// Find returns a recorded operation; "last" is the latest operation that can
+// still be undone
+func Find(id string) (*Operation, error) {
+	operations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(operations) - 1; i >= 0; i-- {
+		op := operations[i]
+		if op.ID == id || (id == "last" && op.Reversible()) {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", id, ErrNotFound)
+}
+
+// UndoOptions controls how an operation is reversed
+type UndoOptions struct {
+	DryRun bool
+	Force  bool // Also reverse files edited since the operation
+	// Uninstall removes an installed package; packages are skipped without it
+	Uninstall func(name string) error
+	// RemoveContainer removes a container; defaults to "<runtime> rm -f <id>"
+	RemoveContainer func(runtime, id string) error
+}
+
+// Undo result states
+const (
+	StatusDone    = "done"
+	StatusSkipped = "skipped"
+	StatusFailed  = "failed"
+)
+
+// UndoResult is the outcome of reversing a change
+type UndoResult struct {
+	Change  Change
+	Status  string
+	Message string
+}
+
+// Undo reverses the changes of an operation, newest first. Files edited
+// since the operation are skipped unless forced. The operation is marked
+// undone only when every change was reversed, so a partial undo can be run
+// again.
+func Undo(id string, opts UndoOptions) (*Operation, []UndoResult, error) {
+	op, err := Find(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if op.UndoneAt != nil {
+		return op, nil, fmt.Errorf("operation %s was already undone at %s", op.ID, op.UndoneAt.Format("2006-01-02 15:04"))
+	}
+	if len(op.Changes) == 0 {
+		return op, nil, fmt.Errorf("operation %s made no reversible changes", op.ID)
+	}
+	if opts.RemoveContainer == nil {
+		opts.RemoveContainer = removeContainer
+	}
+
+	var results []UndoResult
+	complete := true
+	for i := len(op.Changes) - 1; i >= 0; i-- {
+		result := undoChange(op.Changes[i], opts)
+		if result.Status != StatusDone {
+			complete = false
+		}
+		results = append(results, result)
+	}
+
+	if complete && !opts.DryRun {
+		if err := appendEntry(entry{Operation: Operation{Time: time.Now()}, Undo: op.ID}); err != nil {
+			return op, results, err
+		}
+		os.RemoveAll(filepath.Join(Dir(), backupsDir, op.ID))
+	}
+	return op, results, nil
+}
+
+// undoChange reverses a single change
+func undoChange(change Change, opts UndoOptions) UndoResult {
+	result := UndoResult{Change: change, Status: StatusDone}
+	fail := func(err error) UndoResult {
+		result.Status, result.Message = StatusFailed, err.Error()
+		return result
+	}
+
+	switch change.Kind {
+	case FileCreated, FileModified:
+		current := hashFile(change.Target)
+		var backup []byte
+		if change.Kind == FileModified {
+			var err error
+			if backup, err = os.ReadFile(filepath.Join(Dir(), change.Backup)); err != nil {
+				return fail(fmt.Errorf("backup missing: %w", err))
+			}
+		}
+		switch {
+		case change.Kind == FileCreated && current == "":
+			result.Message = "already removed"
+			return result
+		case change.Kind == FileModified && current == hashContent(backup):
+			result.Message = "already restored"
+			return result
+		case current != change.Hash && !opts.Force:
+			result.Status, result.Message = StatusSkipped, "changed since the operation (use --force)"
+			return result
+		}
+
+		if change.Kind == FileCreated {
+			result.Message = "remove"
+			if !opts.DryRun {
+				if err := os.Remove(change.Target); err != nil && !os.IsNotExist(err) {
+					return fail(err)
+				}
+			}
+			return result
+		}
+		result.Message = "restore previous content"
+		if !opts.DryRun {
+			mode := os.FileMode(0644)
+			if info, err := os.Stat(change.Target); err == nil {
+				mode = info.Mode().Perm()
+			}
+			if err := os.MkdirAll(filepath.Dir(change.Target), 0755); err != nil {
+				return fail(err)
+			}
+			if err := os.WriteFile(change.Target, backup, mode); err != nil {
+				return fail(err)
+			}
+		}
+		return result
+
+	case PackageInstalled:
+		if opts.Uninstall == nil {
+			result.Status, result.Message = StatusSkipped, "no uninstaller available"
+			return result
+		}
+		result.Message = "uninstall"
+		if !opts.DryRun {
+			if err := opts.Uninstall(change.Target); err != nil {
+				return fail(err)
+			}
+		}
+		return result
+
+	case ContainerCreated:
+		result.Message = "remove container"
+		if !opts.DryRun {
+			if err := opts.RemoveContainer(change.Runtime, change.Target); err != nil {
+				return fail(err)
+			}
+		}
+		return result
+	}
+
+	result.Status, result.Message = StatusSkipped, "unknown change kind "+change.Kind
+	return result
+}
+
+// removeContainer force-removes a container; a container that is already
+// gone counts as removed
+func removeContainer(runtime, id string) error {
+	output, err := exec.Command(runtime, "rm", "-f", id).CombinedOutput()
+	if err != nil && !bytes.Contains(bytes.ToLower(output), []byte("no such container")) {
+		return fmt.Errorf("%s rm: %s", runtime, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// hashFile returns the SHA-256 of a file, empty when it does not exist
+func hashFile(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return hashContent(content)
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Describe returns a one-line description of a change
+func (c Change) Describe() string {
+	switch c.Kind {
+	case FileCreated:
+		return "created " + c.Target
+	case FileModified:
+		return "modified " + c.Target
+	case PackageInstalled:
+		if c.Version != "" {
+			return "installed " + c.Target + " " + c.Version
+		}
+		return "installed " + c.Target
+	case ContainerCreated:
+		return fmt.Sprintf("created %s container %s", c.Runtime, c.Target)
+	}
+	return c.Kind + " " + c.Target
+}
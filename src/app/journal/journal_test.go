@@ -0,0 +1,138 @@
+package journal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// startOperation opens an operation as the dispatcher does
+func startOperation(t *testing.T, args []string) {
+	t.Setenv(EnvOperation, "")
+	t.Setenv(EnvCommand, "")
+	Start(args)
+}
+
+func TestCurrentWithoutOperation(t *testing.T) {
+	t.Setenv(EnvOperation, "")
+	op := Current("ptx-test")
+	if op != nil {
+		t.Fatal("operation outside the dispatcher")
+	}
+	// A nil operation records nothing
+	op.SaveFile("file.txt")
+	op.PackageInstalled("tool", "1.0")
+	if err := op.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUndoFiles(t *testing.T) {
+	t.Setenv(EnvDir, t.TempDir())
+	dir := t.TempDir()
+	modified := filepath.Join(dir, "modified.md")
+	created := filepath.Join(dir, "created.md")
+	os.WriteFile(modified, []byte("before"), 0644)
+
+	startOperation(t, []string{"pft", "update", "VOC-001"})
+	op := Current("ptx-pft")
+	op.SaveFile(modified)
+	os.WriteFile(modified, []byte("after"), 0644)
+	op.SaveFile(created)
+	os.WriteFile(created, []byte("new"), 0644)
+	if err := op.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	recorded, err := Find("last")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded.ID != op.ID() || recorded.Command != "pft update" || len(recorded.Changes) != 2 {
+		t.Fatalf("recorded %+v", recorded)
+	}
+
+	if _, _, err := Undo(op.ID(), UndoOptions{DryRun: true}); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := os.ReadFile(modified); string(content) != "after" {
+		t.Error("dry run restored the file")
+	}
+
+	if _, results, err := Undo(op.ID(), UndoOptions{}); err != nil || len(results) != 2 {
+		t.Fatalf("undo: %v, %+v", err, results)
+	}
+	if content, _ := os.ReadFile(modified); string(content) != "before" {
+		t.Errorf("modified file not restored: %q", content)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Error("created file not removed")
+	}
+	if _, _, err := Undo(op.ID(), UndoOptions{}); err == nil {
+		t.Error("operation undone twice")
+	}
+
+	// A nested portunix joins the running operation
+	Start([]string{"container", "run"})
+	if Current("ptx-container").ID() != op.ID() {
+		t.Error("nested command opened a new operation")
+	}
+	if _, err := Find("last"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("undone operation still reversible: %v", err)
+	}
+}
+
+func TestUndoSkipsEditedFiles(t *testing.T) {
+	t.Setenv(EnvDir, t.TempDir())
+	path := filepath.Join(t.TempDir(), "item.md")
+
+	startOperation(t, []string{"pft", "add"})
+	op := Current("ptx-pft")
+	op.SaveFile(path)
+	os.WriteFile(path, []byte("added"), 0644)
+	op.Commit()
+	os.WriteFile(path, []byte("edited by hand"), 0644)
+
+	_, results, err := Undo(op.ID(), UndoOptions{})
+	if err != nil || len(results) != 1 || results[0].Status != StatusSkipped {
+		t.Fatalf("undo: %v, %+v", err, results)
+	}
+	if recorded, _ := Find(op.ID()); recorded.UndoneAt != nil {
+		t.Error("partial undo marked the operation undone")
+	}
+
+	if _, _, err := Undo(op.ID(), UndoOptions{Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("forced undo kept the file")
+	}
+}
+
+func TestUndoMergesComponents(t *testing.T) {
+	t.Setenv(EnvDir, t.TempDir())
+	startOperation(t, []string{"install", "nodejs"})
+
+	installer := Current("ptx-installer")
+	installer.PackageInstalled("nodejs", "22.1.0")
+	installer.Commit()
+	container := Current("ptx-container")
+	container.ContainerCreated("podman", "abc123")
+	container.Commit()
+
+	var uninstalled, removed []string
+	op, results, err := Undo("last", UndoOptions{
+		Uninstall:       func(name string) error { uninstalled = append(uninstalled, name); return nil },
+		RemoveContainer: func(runtime, id string) error { removed = append(removed, runtime+"/"+id); return nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.Command != "install nodejs" || op.Component != "ptx-installer,ptx-container" || len(results) != 2 {
+		t.Errorf("operation %+v, results %+v", op, results)
+	}
+	if len(uninstalled) != 1 || uninstalled[0] != "nodejs" || len(removed) != 1 || removed[0] != "podman/abc123" {
+		t.Errorf("uninstalled %v, removed %v", uninstalled, removed)
+	}
+}
@@ -0,0 +1,113 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// EnvStateDir overrides the directory holding cluster records
+const EnvStateDir = "PORTUNIX_K8S_DIR"
+
+var clusterNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,30}[a-z0-9])?$`)
+
+// Cluster is the local record of a cluster created by portunix
+type Cluster struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	Nodes    int    `json:"nodes"`
+	// Kubeconfig is the file with the admin credentials of the cluster
+	Kubeconfig string `json:"kubeconfig"`
+	// Context is the kubeconfig context of the cluster
+	Context   string    `json:"context"`
+	Deployed  []string  `json:"deployed,omitempty"` // applied manifests
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StateDir returns the directory holding all cluster records
+func StateDir() string {
+	if dir := os.Getenv(EnvStateDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-k8s")
+	}
+	return filepath.Join(home, ".portunix", "k8s")
+}
+
+// ClusterDir returns the directory of one cluster
+func ClusterDir(name string) string {
+	return filepath.Join(StateDir(), name)
+}
+
+func recordPath(name string) string {
+	return filepath.Join(ClusterDir(name), "cluster.json")
+}
+
+// ValidateClusterName checks that name is accepted by kind and k3d
+func ValidateClusterName(name string) error {
+	if !clusterNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid cluster name %q: use up to 32 lowercase letters, digits and dashes", name)
+	}
+	return nil
+}
+
+// LoadCluster reads the record of a cluster
+func LoadCluster(name string) (*Cluster, error) {
+	data, err := os.ReadFile(recordPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("cluster %q not found", name)
+		}
+		return nil, err
+	}
+	var c Cluster
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid record of cluster %s: %w", name, err)
+	}
+	return &c, nil
+}
+
+// Save writes the cluster record
+func (c *Cluster) Save() error {
+	if err := os.MkdirAll(ClusterDir(c.Name), 0700); err != nil {
+		return fmt.Errorf("failed to create cluster directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(recordPath(c.Name), data, 0600)
+}
+
+// ListClusters returns all recorded clusters sorted by name
+func ListClusters() ([]*Cluster, error) {
+	entries, err := os.ReadDir(StateDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var clusters []*Cluster
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if c, err := LoadCluster(entry.Name()); err == nil {
+			clusters = append(clusters, c)
+		}
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+	return clusters, nil
+}
+
+// RemoveCluster deletes the record and kubeconfig of a cluster
+func RemoveCluster(name string) error {
+	return os.RemoveAll(ClusterDir(name))
+}
@@ -0,0 +1,596 @@
+package k8s
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest is one Kubernetes object produced from a compose file
+type Manifest struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   Metadata               `yaml:"metadata"`
+	Spec       map[string]interface{} `yaml:"spec,omitempty"`
+}
+
+// Metadata is the object metadata of a Manifest
+type Metadata struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+// Conversion is the result of converting a compose file
+type Conversion struct {
+	Namespace string
+	Manifests []Manifest
+	// Warnings lists compose features without a Kubernetes equivalent
+	Warnings []string
+}
+
+// YAML renders the manifests as one multi-document file
+func (c *Conversion) YAML() (string, error) {
+	var docs []string
+	for _, m := range c.Manifests {
+		data, err := yaml.Marshal(m)
+		if err != nil {
+			return "", err
+		}
+		docs = append(docs, string(data))
+	}
+	return strings.Join(docs, "---\n"), nil
+}
+
+type composeProject struct {
+	Name     string                    `yaml:"name"`
+	Services map[string]composeService `yaml:"services"`
+	Volumes  map[string]interface{}    `yaml:"volumes"`
+}
+
+type composeService struct {
+	Image       string        `yaml:"image"`
+	Build       interface{}   `yaml:"build"`
+	Command     interface{}   `yaml:"command"`
+	Entrypoint  interface{}   `yaml:"entrypoint"`
+	Environment interface{}   `yaml:"environment"`
+	Ports       []interface{} `yaml:"ports"`
+	Expose      []interface{} `yaml:"expose"`
+	Volumes     []interface{} `yaml:"volumes"`
+	Healthcheck *struct {
+		Test     interface{} `yaml:"test"`
+		Interval string      `yaml:"interval"`
+		Timeout  string      `yaml:"timeout"`
+		Retries  int         `yaml:"retries"`
+		Disable  bool        `yaml:"disable"`
+	} `yaml:"healthcheck"`
+	Deploy struct {
+		Replicas *int `yaml:"replicas"`
+	} `yaml:"deploy"`
+}
+
+// LoadComposeEnv returns the variables available to a compose file: the
+// .env file next to it, overridden by the process environment
+func LoadComposeEnv(composePath string) (map[string]string, error) {
+	env := map[string]string{}
+	file, err := os.Open(filepath.Join(filepath.Dir(composePath), ".env"))
+	if err == nil {
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			name, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+			if !ok {
+				continue
+			}
+			env[strings.TrimSpace(name)] = strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, kv := range os.Environ() {
+		if name, value, ok := strings.Cut(kv, "="); ok {
+			env[name] = value
+		}
+	}
+	return env, nil
+}
+
+var interpolation = regexp.MustCompile(`\$(\$|\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-?])([^}]*))?\}|[A-Za-z_][A-Za-z0-9_]*)`)
+
+// Interpolate expands $VAR, ${VAR}, ${VAR:-default}, ${VAR-default},
+// ${VAR:?error} and $$ the way docker compose does
+func Interpolate(s string, env map[string]string) (string, error) {
+	var firstErr error
+	out := interpolation.ReplaceAllStringFunc(s, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+		parts := interpolation.FindStringSubmatch(match)
+		name, op, arg := parts[2], parts[3], parts[4]
+		if name == "" {
+			name = match[1:]
+		}
+		value, set := env[name]
+		switch op {
+		case ":-":
+			if value == "" {
+				return arg
+			}
+		case "-":
+			if !set {
+				return arg
+			}
+		case ":?", "?":
+			if (op == ":?" && value == "") || !set {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("required variable %s is missing: %s", name, arg)
+				}
+			}
+		}
+		return value
+	})
+	return out, firstErr
+}
+
+// interpolateNode expands variables in every scalar value of a YAML tree
+func interpolateNode(node *yaml.Node, env map[string]string) error {
+	if node.Kind == yaml.ScalarNode && node.Tag != "!!null" {
+		value, err := Interpolate(node.Value, env)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		node.Value = value
+		return nil
+	}
+	for i, child := range node.Content {
+		// Mapping keys are not interpolated
+		if node.Kind == yaml.MappingNode && i%2 == 0 {
+			continue
+		}
+		if err := interpolateNode(child, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var dnsInvalid = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// DNSName turns a compose name into a Kubernetes object name
+func DNSName(name string) string {
+	name = dnsInvalid.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-")
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-")
+	}
+	return name
+}
+
+// ComposeNamespace returns the namespace for a compose file: its top-level
+// name or the name of its directory, like the compose project name
+func ComposeNamespace(composePath string, data []byte) string {
+	var project struct {
+		Name string `yaml:"name"`
+	}
+	if yaml.Unmarshal(data, &project) == nil && project.Name != "" {
+		return DNSName(project.Name)
+	}
+	abs, err := filepath.Abs(composePath)
+	if err != nil {
+		return "default"
+	}
+	if name := DNSName(filepath.Base(filepath.Dir(abs))); name != "" {
+		return name
+	}
+	return "default"
+}
+
+// ConvertCompose converts a compose file into a Namespace and a Deployment,
+// Service and PersistentVolumeClaims per service. Services keep their compose
+// names, so "db:5432" style addresses work inside the namespace. Published
+// host ports have no equivalent; use 'kubectl port-forward' to reach them.
+func ConvertCompose(data []byte, env map[string]string, namespace string) (*Conversion, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid compose file: %w", err)
+	}
+	if err := interpolateNode(&root, env); err != nil {
+		return nil, err
+	}
+	var project composeProject
+	if err := root.Decode(&project); err != nil {
+		return nil, fmt.Errorf("invalid compose file: %w", err)
+	}
+	if len(project.Services) == 0 {
+		return nil, fmt.Errorf("compose file defines no services")
+	}
+
+	c := &Conversion{Namespace: namespace}
+	c.Manifests = append(c.Manifests, Manifest{
+		APIVersion: "v1",
+		Kind:       "Namespace",
+		Metadata:   Metadata{Name: namespace, Labels: map[string]string{"app.kubernetes.io/managed-by": "portunix"}},
+	})
+
+	names := make([]string, 0, len(project.Services))
+	for name := range project.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	claims := map[string]bool{}
+	for _, name := range names {
+		if err := c.convertService(name, project.Services[name], project.Volumes, claims); err != nil {
+			return nil, fmt.Errorf("service %s: %w", name, err)
+		}
+	}
+	return c, nil
+}
+
+func (c *Conversion) warnf(format string, args ...interface{}) {
+	c.Warnings = append(c.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (c *Conversion) convertService(name string, svc composeService, volumes map[string]interface{}, claims map[string]bool) error {
+	objName := DNSName(name)
+	if objName != name {
+		c.warnf("service %s is named %s in Kubernetes; update addresses that use the old name", name, objName)
+	}
+	if svc.Image == "" {
+		if svc.Build != nil {
+			return fmt.Errorf("build without image is not supported; build and push the image, then set image")
+		}
+		return fmt.Errorf("image is required")
+	}
+	labels := map[string]string{
+		"app.kubernetes.io/name":       objName,
+		"app.kubernetes.io/part-of":    c.Namespace,
+		"app.kubernetes.io/managed-by": "portunix",
+	}
+	selector := map[string]string{"app.kubernetes.io/name": objName}
+
+	container := map[string]interface{}{"name": objName, "image": svc.Image}
+	if svc.Entrypoint != nil {
+		container["command"] = commandList(svc.Entrypoint)
+	}
+	if svc.Command != nil {
+		container["args"] = commandList(svc.Command)
+	}
+	if env := environment(svc.Environment); len(env) > 0 {
+		container["env"] = env
+	}
+
+	ports, err := servicePorts(svc)
+	if err != nil {
+		return err
+	}
+	if len(ports) > 0 {
+		var containerPorts []map[string]interface{}
+		for _, p := range ports {
+			containerPorts = append(containerPorts, map[string]interface{}{"containerPort": p.port, "protocol": p.protocol})
+		}
+		container["ports"] = containerPorts
+	}
+
+	var mounts, podVolumes []map[string]interface{}
+	for i, v := range svc.Volumes {
+		mount, err := parseVolume(v)
+		if err != nil {
+			return err
+		}
+		switch {
+		case mount.bind:
+			c.warnf("service %s: bind mount %s skipped; build the files into the image or use a ConfigMap", name, mount.source)
+			continue
+		case mount.source == "":
+			volName := fmt.Sprintf("%s-tmp-%d", objName, i)
+			podVolumes = append(podVolumes, map[string]interface{}{"name": volName, "emptyDir": map[string]interface{}{}})
+			mounts = append(mounts, map[string]interface{}{"name": volName, "mountPath": mount.target})
+		default:
+			if _, declared := volumes[mount.source]; !declared && volumes != nil {
+				c.warnf("service %s: volume %s is not declared in the top-level volumes", name, mount.source)
+			}
+			claim := DNSName(mount.source)
+			if !claims[claim] {
+				claims[claim] = true
+				c.Manifests = append(c.Manifests, Manifest{
+					APIVersion: "v1",
+					Kind:       "PersistentVolumeClaim",
+					Metadata:   Metadata{Name: claim, Namespace: c.Namespace, Labels: map[string]string{"app.kubernetes.io/part-of": c.Namespace}},
+					Spec: map[string]interface{}{
+						"accessModes": []string{"ReadWriteOnce"},
+						"resources":   map[string]interface{}{"requests": map[string]string{"storage": "1Gi"}},
+					},
+				})
+			}
+			podVolumes = append(podVolumes, map[string]interface{}{"name": claim, "persistentVolumeClaim": map[string]string{"claimName": claim}})
+			m := map[string]interface{}{"name": claim, "mountPath": mount.target}
+			if mount.readOnly {
+				m["readOnly"] = true
+			}
+			mounts = append(mounts, m)
+		}
+	}
+	if len(mounts) > 0 {
+		container["volumeMounts"] = mounts
+	}
+	if probe := readinessProbe(svc); probe != nil {
+		container["readinessProbe"] = probe
+	}
+
+	replicas := 1
+	if svc.Deploy.Replicas != nil {
+		replicas = *svc.Deploy.Replicas
+	}
+	podSpec := map[string]interface{}{"containers": []map[string]interface{}{container}}
+	if len(podVolumes) > 0 {
+		podSpec["volumes"] = podVolumes
+	}
+	spec := map[string]interface{}{
+		"replicas": replicas,
+		"selector": map[string]interface{}{"matchLabels": selector},
+		"template": map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": labels},
+			"spec":     podSpec,
+		},
+	}
+	if hasClaim(podVolumes) {
+		// A ReadWriteOnce claim cannot be mounted by the old and new pod at once
+		spec["strategy"] = map[string]string{"type": "Recreate"}
+	}
+	c.Manifests = append(c.Manifests, Manifest{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Metadata:   Metadata{Name: objName, Namespace: c.Namespace, Labels: labels},
+		Spec:       spec,
+	})
+
+	if len(ports) == 0 {
+		c.warnf("service %s has no ports or expose entries; other services cannot reach it", name)
+		return nil
+	}
+	var servicePorts []map[string]interface{}
+	for _, p := range ports {
+		servicePorts = append(servicePorts, map[string]interface{}{
+			"name":       fmt.Sprintf("%s-%d", strings.ToLower(p.protocol), p.port),
+			"port":       p.port,
+			"targetPort": p.port,
+			"protocol":   p.protocol,
+		})
+	}
+	c.Manifests = append(c.Manifests, Manifest{
+		APIVersion: "v1",
+		Kind:       "Service",
+		Metadata:   Metadata{Name: objName, Namespace: c.Namespace, Labels: labels},
+		Spec: map[string]interface{}{
+			"type":     "ClusterIP",
+			"selector": selector,
+			"ports":    servicePorts,
+		},
+	})
+	return nil
+}
+
+func hasClaim(volumes []map[string]interface{}) bool {
+	for _, v := range volumes {
+		if _, ok := v["persistentVolumeClaim"]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+type containerPort struct {
+	port     int
+	protocol string
+}
+
+// servicePorts returns the container ports of the ports and expose entries
+func servicePorts(svc composeService) ([]containerPort, error) {
+	var ports []containerPort
+	seen := map[string]bool{}
+	add := func(spec string) error {
+		spec, protocol, _ := strings.Cut(spec, "/")
+		protocol = strings.ToUpper(protocol)
+		if protocol == "" {
+			protocol = "TCP"
+		}
+		// host_ip:published:target, published:target or target
+		parts := strings.Split(spec, ":")
+		target := parts[len(parts)-1]
+		if strings.Contains(target, "-") {
+			return fmt.Errorf("port ranges are not supported: %s", spec)
+		}
+		port, err := strconv.Atoi(target)
+		if err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("invalid port %q", spec)
+		}
+		key := fmt.Sprintf("%d/%s", port, protocol)
+		if !seen[key] {
+			seen[key] = true
+			ports = append(ports, containerPort{port: port, protocol: protocol})
+		}
+		return nil
+	}
+	for _, p := range append(append([]interface{}{}, svc.Ports...), svc.Expose...) {
+		switch v := p.(type) {
+		case map[string]interface{}:
+			spec := fmt.Sprint(v["target"])
+			if proto, ok := v["protocol"].(string); ok {
+				spec += "/" + proto
+			}
+			if err := add(spec); err != nil {
+				return nil, err
+			}
+		default:
+			if err := add(fmt.Sprint(v)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ports, nil
+}
+
+type volumeMount struct {
+	source   string
+	target   string
+	bind     bool
+	readOnly bool
+}
+
+func parseVolume(v interface{}) (volumeMount, error) {
+	if m, ok := v.(map[string]interface{}); ok {
+		mount := volumeMount{}
+		mount.source, _ = m["source"].(string)
+		mount.target, _ = m["target"].(string)
+		mount.readOnly, _ = m["read_only"].(bool)
+		mount.bind = m["type"] == "bind"
+		if mount.target == "" {
+			return mount, fmt.Errorf("volume without target")
+		}
+		return mount, nil
+	}
+	spec := fmt.Sprint(v)
+	parts := strings.Split(spec, ":")
+	mount := volumeMount{}
+	switch len(parts) {
+	case 1:
+		mount.target = parts[0]
+	case 2, 3:
+		mount.source, mount.target = parts[0], parts[1]
+		if len(parts) == 3 {
+			mount.readOnly = strings.Contains(parts[2], "ro")
+		}
+	default:
+		return mount, fmt.Errorf("invalid volume %q", spec)
+	}
+	mount.bind = strings.HasPrefix(mount.source, ".") || strings.HasPrefix(mount.source, "/") || strings.HasPrefix(mount.source, "~")
+	return mount, nil
+}
+
+// environment converts the map and list forms of environment into EnvVars.
+// Variables listed without a value are omitted, as compose does when the
+// variable is unset.
+func environment(env interface{}) []map[string]string {
+	values := map[string]string{}
+	switch v := env.(type) {
+	case map[string]interface{}:
+		for name, value := range v {
+			if value != nil {
+				values[name] = fmt.Sprint(value)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if name, value, ok := strings.Cut(fmt.Sprint(item), "="); ok {
+				values[name] = value
+			}
+		}
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var vars []map[string]string
+	for _, name := range names {
+		vars = append(vars, map[string]string{"name": name, "value": values[name]})
+	}
+	return vars
+}
+
+// commandList converts a compose command or entrypoint into an argv list
+func commandList(cmd interface{}) []string {
+	switch v := cmd.(type) {
+	case []interface{}:
+		args := make([]string, len(v))
+		for i, arg := range v {
+			args[i] = fmt.Sprint(arg)
+		}
+		return args
+	default:
+		return splitWords(fmt.Sprint(v))
+	}
+}
+
+// splitWords splits a command string on spaces, honouring quotes
+func splitWords(s string) []string {
+	var words []string
+	var word strings.Builder
+	var quote rune
+	inWord := false
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// readinessProbe converts a compose healthcheck into an exec probe
+func readinessProbe(svc composeService) map[string]interface{} {
+	hc := svc.Healthcheck
+	if hc == nil || hc.Disable || hc.Test == nil {
+		return nil
+	}
+	var command []string
+	switch test := commandList(hc.Test); {
+	case len(test) == 0 || test[0] == "NONE":
+		return nil
+	case test[0] == "CMD":
+		command = test[1:]
+	case test[0] == "CMD-SHELL":
+		command = []string{"sh", "-c", strings.Join(test[1:], " ")}
+	default:
+		if _, isString := hc.Test.(string); isString {
+			command = []string{"sh", "-c", fmt.Sprint(hc.Test)}
+		} else {
+			command = test
+		}
+	}
+	probe := map[string]interface{}{"exec": map[string]interface{}{"command": command}}
+	if d, err := time.ParseDuration(hc.Interval); err == nil && d >= time.Second {
+		probe["periodSeconds"] = int(d.Seconds())
+	}
+	if d, err := time.ParseDuration(hc.Timeout); err == nil && d >= time.Second {
+		probe["timeoutSeconds"] = int(d.Seconds())
+	}
+	if hc.Retries > 0 {
+		probe["failureThreshold"] = hc.Retries
+	}
+	return probe
+}
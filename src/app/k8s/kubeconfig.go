@@ -0,0 +1,183 @@
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kubeconfig holds the parts of a kubeconfig file portunix merges. Entries
+// keep their remaining fields so nothing written by other tools is lost.
+type kubeconfig struct {
+	APIVersion     string                 `yaml:"apiVersion"`
+	Kind           string                 `yaml:"kind"`
+	Clusters       []namedEntry           `yaml:"clusters"`
+	Contexts       []namedEntry           `yaml:"contexts"`
+	Users          []namedEntry           `yaml:"users"`
+	CurrentContext string                 `yaml:"current-context"`
+	Preferences    map[string]interface{} `yaml:"preferences,omitempty"`
+	Extra          map[string]interface{} `yaml:",inline"`
+}
+
+type namedEntry struct {
+	Name string                 `yaml:"name"`
+	Rest map[string]interface{} `yaml:",inline"`
+}
+
+func parseKubeconfig(data []byte) (*kubeconfig, error) {
+	cfg := &kubeconfig{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	if cfg.APIVersion == "" {
+		cfg.APIVersion = "v1"
+	}
+	if cfg.Kind == "" {
+		cfg.Kind = "Config"
+	}
+	return cfg, nil
+}
+
+// DefaultKubeconfigPath returns the kubeconfig kubectl uses by default: the
+// first file of KUBECONFIG or ~/.kube/config
+func DefaultKubeconfigPath() string {
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		if paths := filepath.SplitList(env); len(paths) > 0 && paths[0] != "" {
+			return paths[0]
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".kube", "config")
+	}
+	return filepath.Join(home, ".kube", "config")
+}
+
+// KubeconfigContext returns the current context of a kubeconfig
+func KubeconfigContext(data []byte) (string, error) {
+	cfg, err := parseKubeconfig(data)
+	if err != nil {
+		return "", err
+	}
+	return cfg.CurrentContext, nil
+}
+
+// RenameKubeconfig renames the cluster, user and context of a single-cluster
+// kubeconfig to name. k3s writes all of them as "default", which would
+// clash with other clusters once merged.
+func RenameKubeconfig(data []byte, name string) ([]byte, error) {
+	cfg, err := parseKubeconfig(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Clusters) != 1 || len(cfg.Users) != 1 || len(cfg.Contexts) != 1 {
+		return nil, fmt.Errorf("expected a kubeconfig with a single cluster, user and context")
+	}
+	cfg.Clusters[0].Name = name
+	cfg.Users[0].Name = name
+	cfg.Contexts[0].Name = name
+	if ctx, ok := cfg.Contexts[0].Rest["context"].(map[string]interface{}); ok {
+		ctx["cluster"] = name
+		ctx["user"] = name
+	}
+	cfg.CurrentContext = name
+	return yaml.Marshal(cfg)
+}
+
+// MergeKubeconfig merges the clusters, users and contexts of data into the
+// kubeconfig file at path, replacing entries with the same name. With
+// setCurrent the current context of data becomes the current context of
+// the file.
+func MergeKubeconfig(data []byte, path string, setCurrent bool) error {
+	incoming, err := parseKubeconfig(data)
+	if err != nil {
+		return err
+	}
+	target := &kubeconfig{APIVersion: "v1", Kind: "Config"}
+	if existing, err := os.ReadFile(path); err == nil {
+		if target, err = parseKubeconfig(existing); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	target.Clusters = mergeEntries(target.Clusters, incoming.Clusters)
+	target.Users = mergeEntries(target.Users, incoming.Users)
+	target.Contexts = mergeEntries(target.Contexts, incoming.Contexts)
+	if setCurrent || target.CurrentContext == "" {
+		target.CurrentContext = incoming.CurrentContext
+	}
+
+	out, err := yaml.Marshal(target)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0600)
+}
+
+// RemoveFromKubeconfig removes the context and its cluster and user from the
+// kubeconfig file at path
+func RemoveFromKubeconfig(path, context string) error {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := parseKubeconfig(existing)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	cluster, user := context, context
+	for _, entry := range cfg.Contexts {
+		if ctx, ok := entry.Rest["context"].(map[string]interface{}); ok && entry.Name == context {
+			cluster, _ = ctx["cluster"].(string)
+			user, _ = ctx["user"].(string)
+		}
+	}
+	cfg.Contexts = dropEntry(cfg.Contexts, context)
+	cfg.Clusters = dropEntry(cfg.Clusters, cluster)
+	cfg.Users = dropEntry(cfg.Users, user)
+	if cfg.CurrentContext == context {
+		cfg.CurrentContext = ""
+	}
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0600)
+}
+
+func mergeEntries(existing, incoming []namedEntry) []namedEntry {
+	for _, entry := range incoming {
+		replaced := false
+		for i := range existing {
+			if existing[i].Name == entry.Name {
+				existing[i] = entry
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			existing = append(existing, entry)
+		}
+	}
+	return existing
+}
+
+func dropEntry(entries []namedEntry, name string) []namedEntry {
+	kept := entries[:0]
+	for _, entry := range entries {
+		if entry.Name != name {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
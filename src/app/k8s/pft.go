@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//go:embed stacks/pft-compose.yaml
+var pftCompose []byte
+
+// PFTCompose returns the compose file of the PFT feedback stack
+func PFTCompose() []byte {
+	return pftCompose
+}
+
+// PFTEnv returns the variables of the PFT stack for a cluster. Secrets are
+// generated once and kept in pft.env of the cluster directory, so
+// redeploying keeps the database password.
+func PFTEnv(clusterName string) (map[string]string, error) {
+	path := filepath.Join(ClusterDir(clusterName), "pft.env")
+	env := map[string]string{}
+	if data, err := os.ReadFile(path); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if name, value, ok := strings.Cut(line, "="); ok && !strings.HasPrefix(name, "#") {
+				env[name] = value
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	changed := false
+	for name, size := range map[string]int{"FIDER_DB_PASSWORD": 16, "FIDER_JWT_SECRET": 32} {
+		if env[name] != "" {
+			continue
+		}
+		secret := make([]byte, size)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate secret: %w", err)
+		}
+		env[name] = hex.EncodeToString(secret)
+		changed = true
+	}
+	if changed {
+		names := make([]string, 0, len(env))
+		for name := range env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var b strings.Builder
+		b.WriteString("# PFT stack secrets, generated by portunix k8s create\n")
+		for _, name := range names {
+			fmt.Fprintf(&b, "%s=%s\n", name, env[name])
+		}
+		if err := os.MkdirAll(ClusterDir(clusterName), 0700); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return env, nil
+}
@@ -0,0 +1,237 @@
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Provider creates and deletes local clusters with one Kubernetes distribution
+type Provider interface {
+	Name() string
+	// Tool is the command the provider needs; portunix installs it when missing
+	Tool() string
+	// Context is the kubeconfig context of a cluster
+	Context(name string) string
+	Create(name string, nodes int, kubeconfigPath string) error
+	Delete(name string) error
+	// Kubeconfig returns the admin kubeconfig of a running cluster
+	Kubeconfig(name string) ([]byte, error)
+	// Exists reports whether the provider knows the cluster
+	Exists(name string) (bool, error)
+}
+
+// ProviderNames lists the supported providers
+func ProviderNames() []string {
+	return []string{"kind", "k3d", "k3s"}
+}
+
+// NewProvider returns the provider called name
+func NewProvider(name string) (Provider, error) {
+	switch name {
+	case "kind":
+		return &kindProvider{}, nil
+	case "k3d":
+		return &k3dProvider{}, nil
+	case "k3s":
+		return &k3sProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q (supported: %s)", name, strings.Join(ProviderNames(), ", "))
+	}
+}
+
+// run executes a provider command with its output shown to the user
+func run(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// output executes a provider command and returns its standard output
+func output(name string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s %s: %s", name, strings.Join(args, " "), msg)
+		}
+		return nil, fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return out, nil
+}
+
+type kindProvider struct{}
+
+func (p *kindProvider) Name() string               { return "kind" }
+func (p *kindProvider) Tool() string               { return "kind" }
+func (p *kindProvider) Context(name string) string { return "kind-" + name }
+
+// KindConfig returns the kind cluster configuration with one control plane
+// and nodes-1 workers
+func KindConfig(nodes int) string {
+	var b strings.Builder
+	b.WriteString("kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nnodes:\n  - role: control-plane\n")
+	for i := 1; i < nodes; i++ {
+		b.WriteString("  - role: worker\n")
+	}
+	return b.String()
+}
+
+func (p *kindProvider) Create(name string, nodes int, kubeconfigPath string) error {
+	cmd := exec.Command("kind", "create", "cluster", "--name", name, "--config", "-", "--kubeconfig", kubeconfigPath, "--wait", "120s")
+	cmd.Stdin = strings.NewReader(KindConfig(nodes))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (p *kindProvider) Delete(name string) error {
+	return run("kind", "delete", "cluster", "--name", name)
+}
+
+func (p *kindProvider) Kubeconfig(name string) ([]byte, error) {
+	return output("kind", "get", "kubeconfig", "--name", name)
+}
+
+func (p *kindProvider) Exists(name string) (bool, error) {
+	out, err := output("kind", "get", "clusters")
+	if err != nil {
+		return false, err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.TrimSpace(line) == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type k3dProvider struct{}
+
+func (p *k3dProvider) Name() string               { return "k3d" }
+func (p *k3dProvider) Tool() string               { return "k3d" }
+func (p *k3dProvider) Context(name string) string { return "k3d-" + name }
+
+// K3dCreateArgs returns the k3d arguments creating a cluster with one server
+// and nodes-1 agents. The default kubeconfig is left alone; portunix merges
+// it itself unless asked not to.
+func K3dCreateArgs(name string, nodes int) []string {
+	args := []string{"cluster", "create", name, "--servers", "1"}
+	if nodes > 1 {
+		args = append(args, "--agents", strconv.Itoa(nodes-1))
+	}
+	return append(args, "--kubeconfig-update-default=false", "--kubeconfig-switch-context=false", "--wait")
+}
+
+func (p *k3dProvider) Create(name string, nodes int, kubeconfigPath string) error {
+	if err := run("k3d", K3dCreateArgs(name, nodes)...); err != nil {
+		return err
+	}
+	data, err := p.Kubeconfig(name)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(kubeconfigPath, data, 0600)
+}
+
+func (p *k3dProvider) Delete(name string) error {
+	return run("k3d", "cluster", "delete", name)
+}
+
+func (p *k3dProvider) Kubeconfig(name string) ([]byte, error) {
+	return output("k3d", "kubeconfig", "get", name)
+}
+
+func (p *k3dProvider) Exists(name string) (bool, error) {
+	out, err := output("k3d", "cluster", "list", "-o", "json")
+	if err != nil {
+		return false, err
+	}
+	var clusters []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(out, &clusters); err != nil {
+		return false, fmt.Errorf("unexpected k3d output: %w", err)
+	}
+	for _, c := range clusters {
+		if c.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// k3sProvider installs k3s directly on the host. It is a single-node
+// cluster; multi-node k3s runs in VMs created from the k3s-node cloud-init
+// template.
+type k3sProvider struct{}
+
+const k3sKubeconfig = "/etc/rancher/k3s/k3s.yaml"
+
+func (p *k3sProvider) Name() string               { return "k3s" }
+func (p *k3sProvider) Tool() string               { return "" }
+func (p *k3sProvider) Context(name string) string { return "k3s-" + name }
+
+func (p *k3sProvider) Create(name string, nodes int, kubeconfigPath string) error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("k3s runs on Linux hosts only, use --provider kind or k3d")
+	}
+	if nodes > 1 {
+		return fmt.Errorf("k3s on the host is a single node; use --provider k3d for more nodes or create VMs with 'portunix vm create --cloud-init-template k3s-node'")
+	}
+	if _, err := exec.LookPath("k3s"); err == nil {
+		return fmt.Errorf("k3s is already installed on this host; a host has one k3s cluster")
+	}
+	script := `curl -sfL https://get.k3s.io | INSTALL_K3S_EXEC="server --write-kubeconfig-mode 644" sh -`
+	if err := runArgs(sudo("sh", "-c", script)); err != nil {
+		return fmt.Errorf("k3s installation failed: %w", err)
+	}
+	data, err := p.Kubeconfig(name)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(kubeconfigPath, data, 0600)
+}
+
+func (p *k3sProvider) Delete(name string) error {
+	return runArgs(sudo("/usr/local/bin/k3s-uninstall.sh"))
+}
+
+func (p *k3sProvider) Kubeconfig(name string) ([]byte, error) {
+	data, err := os.ReadFile(k3sKubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read k3s kubeconfig: %w", err)
+	}
+	return RenameKubeconfig(data, p.Context(name))
+}
+
+func (p *k3sProvider) Exists(name string) (bool, error) {
+	_, err := os.Stat(k3sKubeconfig)
+	return err == nil, nil
+}
+
+// sudo prefixes a command with sudo unless running as root
+func sudo(args ...string) []string {
+	if os.Geteuid() == 0 {
+		return args
+	}
+	return append([]string{"sudo"}, args...)
+}
+
+// runArgs runs a command given as one slice
+func runArgs(args []string) error {
+	return run(args[0], args[1:]...)
+}
+
+// Apply applies a manifest file to the cluster of kubeconfigPath
+func Apply(kubeconfigPath, manifestPath string) error {
+	return run("kubectl", "--kubeconfig", kubeconfigPath, "apply", "-f", manifestPath)
+}
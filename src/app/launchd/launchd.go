@@ -0,0 +1,226 @@
+// Package launchd manages per-user launchd agents on macOS
+package launchd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CalendarInterval is a StartCalendarInterval entry; nil fields match any
+// value, like "*" in crontab
+type CalendarInterval struct {
+	Minute  *int
+	Hour    *int
+	Day     *int
+	Weekday *int
+	Month   *int
+}
+
+// Daily returns an interval firing every day at hour:minute
+func Daily(hour, minute int) *CalendarInterval {
+	return &CalendarInterval{Hour: &hour, Minute: &minute}
+}
+
+// Agent describes a launchd job running in the user's GUI session
+type Agent struct {
+	// Label is the unique reverse-DNS job name, e.g. "ai.portunix.cert-renew"
+	Label            string
+	ProgramArguments []string
+	// RunAtLoad starts the job as soon as it is loaded
+	RunAtLoad bool
+	// KeepAlive restarts the job whenever it exits
+	KeepAlive            bool
+	StartCalendar        *CalendarInterval
+	StartInterval        int
+	WorkingDirectory     string
+	EnvironmentVariables map[string]string
+	StandardOutPath      string
+	StandardErrorPath    string
+}
+
+// AgentsDir returns ~/Library/LaunchAgents
+func AgentsDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "Library", "LaunchAgents")
+}
+
+// PlistPath returns the path of the agent's property list
+func PlistPath(label string) string {
+	return filepath.Join(AgentsDir(), label+".plist")
+}
+
+// Plist renders the agent as a launchd property list
+func (a *Agent) Plist() []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	writeString(&b, 1, "Label", a.Label)
+	writeKey(&b, 1, "ProgramArguments")
+	b.WriteString("\t<array>\n")
+	for _, arg := range a.ProgramArguments {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", escape(arg))
+	}
+	b.WriteString("\t</array>\n")
+	if a.RunAtLoad {
+		writeKey(&b, 1, "RunAtLoad")
+		b.WriteString("\t<true/>\n")
+	}
+	if a.KeepAlive {
+		writeKey(&b, 1, "KeepAlive")
+		b.WriteString("\t<true/>\n")
+	}
+	if c := a.StartCalendar; c != nil {
+		writeKey(&b, 1, "StartCalendarInterval")
+		b.WriteString("\t<dict>\n")
+		for _, field := range []struct {
+			key   string
+			value *int
+		}{{"Month", c.Month}, {"Day", c.Day}, {"Weekday", c.Weekday}, {"Hour", c.Hour}, {"Minute", c.Minute}} {
+			if field.value != nil {
+				writeKey(&b, 2, field.key)
+				fmt.Fprintf(&b, "\t\t<integer>%d</integer>\n", *field.value)
+			}
+		}
+		b.WriteString("\t</dict>\n")
+	}
+	if a.StartInterval > 0 {
+		writeKey(&b, 1, "StartInterval")
+		fmt.Fprintf(&b, "\t<integer>%d</integer>\n", a.StartInterval)
+	}
+	if a.WorkingDirectory != "" {
+		writeString(&b, 1, "WorkingDirectory", a.WorkingDirectory)
+	}
+	if len(a.EnvironmentVariables) > 0 {
+		writeKey(&b, 1, "EnvironmentVariables")
+		b.WriteString("\t<dict>\n")
+		for _, name := range sortedKeys(a.EnvironmentVariables) {
+			writeString(&b, 2, name, a.EnvironmentVariables[name])
+		}
+		b.WriteString("\t</dict>\n")
+	}
+	if a.StandardOutPath != "" {
+		writeString(&b, 1, "StandardOutPath", a.StandardOutPath)
+	}
+	if a.StandardErrorPath != "" {
+		writeString(&b, 1, "StandardErrorPath", a.StandardErrorPath)
+	}
+	b.WriteString("</dict>\n</plist>\n")
+	return b.Bytes()
+}
+
+// Install writes the agent's plist to ~/Library/LaunchAgents and loads it
+// into the user's GUI domain, replacing a previously loaded version
+func Install(a *Agent) error {
+	if a.Label == "" || len(a.ProgramArguments) == 0 {
+		return fmt.Errorf("launchd agent needs a label and program arguments")
+	}
+	if err := os.MkdirAll(AgentsDir(), 0755); err != nil {
+		return err
+	}
+	path := PlistPath(a.Label)
+	if err := os.WriteFile(path, a.Plist(), 0644); err != nil {
+		return err
+	}
+	// bootstrap fails when the job is already loaded
+	exec.Command("launchctl", "bootout", domain()+"/"+a.Label).Run()
+	if out, err := exec.Command("launchctl", "bootstrap", domain(), path).CombinedOutput(); err != nil {
+		return fmt.Errorf("launchctl bootstrap failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Remove unloads the agent and deletes its plist; a missing agent is not
+// an error
+func Remove(label string) error {
+	path := PlistPath(label)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	exec.Command("launchctl", "bootout", domain()+"/"+label).Run()
+	return os.Remove(path)
+}
+
+// IsLoaded reports whether the agent is loaded in the user's GUI domain
+func IsLoaded(label string) bool {
+	return exec.Command("launchctl", "print", domain()+"/"+label).Run() == nil
+}
+
+// Start runs the job of an installed agent: it loads the agent when it is
+// not loaded, which starts RunAtLoad jobs, and kicks the job otherwise
+func Start(label string) error {
+	if !IsLoaded(label) {
+		if out, err := exec.Command("launchctl", "bootstrap", domain(), PlistPath(label)).CombinedOutput(); err != nil {
+			return fmt.Errorf("launchctl bootstrap failed: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	if out, err := exec.Command("launchctl", "kickstart", domain()+"/"+label).CombinedOutput(); err != nil {
+		return fmt.Errorf("launchctl kickstart failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Stop unloads the agent, which stops its job even with KeepAlive; the
+// plist stays, so the agent loads again at the next login
+func Stop(label string) error {
+	if !IsLoaded(label) {
+		return nil
+	}
+	if out, err := exec.Command("launchctl", "bootout", domain()+"/"+label).CombinedOutput(); err != nil {
+		return fmt.Errorf("launchctl bootout failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// State returns the state launchctl reports for the job of a loaded agent,
+// e.g. "running" or "not running", and "" when it is not loaded
+func State(label string) string {
+	out, err := exec.Command("launchctl", "print", domain()+"/"+label).Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "state = "); ok {
+			return value
+		}
+	}
+	return "loaded"
+}
+
+// domain returns the launchctl domain of the current user's GUI session
+func domain() string {
+	return fmt.Sprintf("gui/%d", os.Getuid())
+}
+
+func writeKey(b *bytes.Buffer, indent int, key string) {
+	fmt.Fprintf(b, "%s<key>%s</key>\n", strings.Repeat("\t", indent), escape(key))
+}
+
+func writeString(b *bytes.Buffer, indent int, key, value string) {
+	writeKey(b, indent, key)
+	fmt.Fprintf(b, "%s<string>%s</string>\n", strings.Repeat("\t", indent), escape(value))
+}
+
+func escape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,712 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"portunix.ai/app/daemon"
+	"portunix.ai/app/progress"
+)
+
+// Install policies controlling how install_package behaves
+const (
+	// InstallPolicyConfirm returns an install plan with a plan token first
+	// and installs only when the tool is called again with that token
+	// (default)
+	InstallPolicyConfirm = "confirm"
+	// InstallPolicyAllow installs immediately
+	InstallPolicyAllow = "allow"
+	// InstallPolicyDeny rejects all installations
+	InstallPolicyDeny = "deny"
+)
+
+// InstallPolicies lists the accepted install policies
+var InstallPolicies = []string{InstallPolicyConfirm, InstallPolicyAllow, InstallPolicyDeny}
+
+var containerNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// planTokenTTL is how long an install plan can be approved
+const planTokenTTL = 15 * time.Minute
+
+// installPlans holds the plan tokens issued by install_package under the
+// confirm policy; a token installs the planned package once
+type installPlans struct {
+	mu    sync.Mutex
+	plans map[string]installPlan
+}
+
+type installPlan struct {
+	pkg, variant string
+	expires      time.Time
+}
+
+// issue returns a new token for the plan of pkg and variant
+func (p *installPlans) issue(pkg, variant string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.plans == nil {
+		p.plans = make(map[string]installPlan)
+	}
+	now := time.Now()
+	for t, plan := range p.plans {
+		if now.After(plan.expires) {
+			delete(p.plans, t)
+		}
+	}
+	p.plans[token] = installPlan{pkg: pkg, variant: variant, expires: now.Add(planTokenTTL)}
+	return token, nil
+}
+
+// redeem consumes a token issued for the plan of pkg and variant
+func (p *installPlans) redeem(token, pkg, variant string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	plan, ok := p.plans[token]
+	if !ok {
+		return false
+	}
+	delete(p.plans, token)
+	return plan.pkg == pkg && plan.variant == variant && time.Now().Before(plan.expires)
+}
+
+// portunixInstall runs 'portunix install'; replaced in tests
+var portunixInstall = runPortunixInstall
+
+// devEnvTools returns tool definitions for package install, containers and
+// PFT queries
+func devEnvTools() []map[string]interface{} {
+	stringArray := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"description": description,
+		}
+	}
+	systemParam := map[string]interface{}{
+		"type":        "string",
+		"description": "Container runtime (default: podman, then docker)",
+		"enum":        []string{"podman", "docker", "nerdctl"},
+	}
+
+	return []map[string]interface{}{
+		{
+			"name":        "install_package",
+			"description": "Install a package using Portunix package manager. Depending on the server install policy the first call returns an install plan and a plan_token; show the plan to the user and call again with that plan_token once approved.",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"package": map[string]interface{}{
+						"type":        "string",
+						"description": "Package name to install",
+					},
+					"variant": map[string]interface{}{
+						"type":        "string",
+						"description": "Package variant, e.g. a version like 17 for java",
+					},
+					"plan_token": map[string]interface{}{
+						"type":        "string",
+						"description": "Token of the install plan the user approved",
+					},
+				},
+				"required": []string{"package"},
+			},
+		},
+		{
+			"name":        "container_list",
+			"description": "List containers (running and stopped) of the container runtime",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"system": systemParam,
+				},
+			},
+		},
+		{
+			"name":        "container_run",
+			"description": "Start a new detached container from an image",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"image": map[string]interface{}{
+						"type":        "string",
+						"description": "Image to run, e.g. postgres:16",
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Container name",
+					},
+					"ports":   stringArray("Port mappings host:container, e.g. 5432:5432"),
+					"env":     stringArray("Environment variables KEY=VALUE"),
+					"volumes": stringArray("Named volume mounts name:target; host directories need full permissions"),
+					"command": stringArray("Command and arguments overriding the image default"),
+					"system":  systemParam,
+				},
+				"required": []string{"image"},
+			},
+		},
+		{
+			"name":        "container_exec",
+			"description": "Run a command inside a running container and return its output",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"container": map[string]interface{}{
+						"type":        "string",
+						"description": "Container name or ID",
+					},
+					"command": stringArray("Command and arguments to run"),
+					"system":  systemParam,
+				},
+				"required": []string{"container", "command"},
+			},
+		},
+		{
+			"name":        "container_logs",
+			"description": "Get the most recent log output of a container",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"container": map[string]interface{}{
+						"type":        "string",
+						"description": "Container name or ID",
+					},
+					"tail": map[string]interface{}{
+						"type":        "integer",
+						"description": "Number of lines from the end of the log",
+						"default":     100,
+					},
+					"system": systemParam,
+				},
+				"required": []string{"container"},
+			},
+		},
+		{
+			"name":        "pft_list",
+			"description": "List PTX-PFT feedback items (voice of customer / voice of stakeholder) of a project",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Path to document directory (where .pft-config.json is located)",
+					},
+					"area": map[string]interface{}{
+						"type":        "string",
+						"description": "Only items of this area",
+						"enum":        []string{"voc", "vos"},
+					},
+					"category": map[string]interface{}{
+						"type":        "string",
+						"description": "Only items of this category",
+					},
+				},
+			},
+		},
+		{
+			"name":        "pft_show",
+			"description": "Show details of a PTX-PFT feedback item",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "Feedback item ID",
+					},
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Path to document directory (where .pft-config.json is located)",
+					},
+				},
+				"required": []string{"id"},
+			},
+		},
+	}
+}
+
+// handleToolsCallProgress handles tool calls whose client asked for
+// progress notifications with a progress token. It reports whether the
+// tool reports progress; other calls are left to handleToolsCall.
+func (s *Server) handleToolsCallProgress(params json.RawMessage, notify Notifier) (interface{}, bool, error) {
+	var request struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+		Meta      struct {
+			ProgressToken interface{} `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	if err := json.Unmarshal(params, &request); err != nil || request.Meta.ProgressToken == nil {
+		return nil, false, nil
+	}
+	if request.Name != "install_package" {
+		return nil, false, nil
+	}
+
+	result, err := s.installTool(request.Arguments, progressNotifier(request.Meta.ProgressToken, notify))
+	if err != nil {
+		return nil, true, err
+	}
+	return toolResponse(result), true, nil
+}
+
+// progressNotifier sends progress events as notifications/progress. MCP
+// progress must increase, so every stage adds 100 to the progress of the
+// stages before it: 1-99 while it runs, 100 when done. Events that would
+// not increase it are not sent.
+func progressNotifier(token interface{}, notify Notifier) func(progress.Event) {
+	stages := make(map[string]int)
+	last := 0.0
+	return func(e progress.Event) {
+		index, ok := stages[e.Stage]
+		if !ok {
+			index = len(stages)
+			stages[e.Stage] = index
+		}
+		percent := e.Percent
+		if e.Done {
+			percent = 100
+		} else if percent < 1 {
+			percent = 1
+		} else if percent > 99 {
+			percent = 99
+		}
+		value := float64(index*100) + percent
+		if value <= last {
+			return
+		}
+		last = value
+		message := e.Stage
+		switch {
+		case e.Error != "":
+			message += " failed: " + e.Error
+		case e.Done:
+			message += " done"
+		case e.Percent >= 0:
+			message += fmt.Sprintf(" %.0f%%", e.Percent)
+		}
+		if e.Message != "" && e.Error == "" {
+			message += " " + e.Message
+		}
+		notify(MCPNotification{
+			JSONRPC: "2.0",
+			Method:  "notifications/progress",
+			Params: map[string]interface{}{
+				"progressToken": token,
+				"progress":      value,
+				"message":       message,
+			},
+		})
+	}
+}
+
+// handleInstallTool installs a package through portunix, honouring the
+// install policy of the server
+func (s *Server) handleInstallTool(args map[string]interface{}) (interface{}, error) {
+	return s.installTool(args, nil)
+}
+
+// installTool runs the install_package tool; onProgress receives the
+// progress events of the installation
+func (s *Server) installTool(args map[string]interface{}, onProgress func(progress.Event)) (interface{}, error) {
+	if !s.hasPermission("package:install") {
+		return nil, fmt.Errorf("package installation requires standard or full permissions")
+	}
+	if s.InstallPolicy == InstallPolicyDeny {
+		return nil, fmt.Errorf("package installation is disabled by the server install policy")
+	}
+
+	req := &daemon.InstallRequest{}
+	req.Package, _ = args["package"].(string)
+	req.Variant, _ = args["variant"].(string)
+	if req.Package == "" {
+		return nil, fmt.Errorf("package parameter required")
+	}
+	if !isValidPackageName(req.Package) || strings.HasPrefix(req.Package, "-") {
+		return nil, fmt.Errorf("invalid package name: %s", req.Package)
+	}
+	if req.Variant != "" && (!isValidPackageName(req.Variant) || strings.HasPrefix(req.Variant, "-")) {
+		return nil, fmt.Errorf("invalid variant: %s", req.Variant)
+	}
+
+	if s.InstallPolicy != InstallPolicyAllow {
+		token, _ := args["plan_token"].(string)
+		if token == "" {
+			req.DryRun = true
+			plan, err := portunixInstall(req, nil)
+			if err != nil {
+				return nil, err
+			}
+			token, err := s.installPlans.issue(req.Package, req.Variant)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"status":     "confirmation_required",
+				"package":    req.Package,
+				"variant":    req.Variant,
+				"plan":       plan.Output,
+				"plan_token": token,
+				"message":    "Show this plan to the user. After approval call install_package again with this plan_token.",
+			}, nil
+		}
+		if !s.installPlans.redeem(token, req.Package, req.Variant) {
+			return nil, fmt.Errorf("invalid or expired plan_token; call install_package without it for a new install plan")
+		}
+	}
+
+	resp, err := portunixInstall(req, onProgress)
+	if err != nil {
+		return nil, err
+	}
+	status := "installed"
+	if resp.ExitCode != 0 {
+		status = "failed"
+	}
+	return map[string]interface{}{
+		"status":    status,
+		"package":   req.Package,
+		"variant":   req.Variant,
+		"exit_code": resp.ExitCode,
+		"output":    resp.Output,
+	}, nil
+}
+
+// runPortunixInstall runs 'portunix install', through the daemon when one
+// is running so no new process has to be started. With onProgress the
+// progress events of the installation are passed to it.
+func runPortunixInstall(req *daemon.InstallRequest, onProgress func(progress.Event)) (*daemon.CommandResponse, error) {
+	if client, err := daemon.Connect(); err == nil {
+		defer client.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+		var resp *daemon.CommandResponse
+		if onProgress != nil {
+			resp, err = client.InstallStream(ctx, req, onProgress)
+		} else {
+			resp, err = client.Install(ctx, req)
+		}
+		if err == nil {
+			return resp, nil
+		}
+	}
+
+	args := []string{"install", req.Package}
+	if req.Variant != "" {
+		args = append(args, "--variant", req.Variant)
+	}
+	if req.DryRun {
+		args = append(args, "--dry-run")
+	}
+	binary, err := portunixBinary()
+	if err != nil {
+		return nil, err
+	}
+	output, err := progress.CombinedOutput(exec.Command(binary, args...), onProgress)
+	resp := &daemon.CommandResponse{Command: append([]string{"portunix"}, args...), Output: string(output)}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		resp.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to run portunix install: %w", err)
+	}
+	return resp, nil
+}
+
+// portunixBinary locates the portunix executable next to this helper or in PATH
+func portunixBinary() (string, error) {
+	name := "portunix"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	if execPath, err := os.Executable(); err == nil {
+		candidate := filepath.Join(filepath.Dir(execPath), name)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+	return "", fmt.Errorf("portunix binary not found")
+}
+
+// containerRuntime resolves the runtime to use, preferring podman like
+// 'portunix container'
+func containerRuntime(args map[string]interface{}) (string, error) {
+	if requested, _ := args["system"].(string); requested != "" {
+		switch requested {
+		case "podman", "docker", "nerdctl":
+		default:
+			return "", fmt.Errorf("unsupported container system: %s", requested)
+		}
+		if _, err := exec.LookPath(requested); err != nil {
+			return "", fmt.Errorf("container system '%s' not available", requested)
+		}
+		return requested, nil
+	}
+	for _, candidate := range []string{"podman", "docker", "nerdctl"} {
+		if _, err := exec.LookPath(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no container runtime detected (install one with 'portunix install podman')")
+}
+
+// stringList reads a JSON array of strings from tool arguments
+func stringList(args map[string]interface{}, key string) ([]string, error) {
+	raw, ok := args[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of strings", key)
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		value, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array of strings", key)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func (s *Server) handleContainerListTool(args map[string]interface{}) (interface{}, error) {
+	if !s.hasPermission("container:list") {
+		return nil, fmt.Errorf("insufficient permissions to list containers")
+	}
+	system, err := containerRuntime(args)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := exec.Command(system, "ps", "-a", "--format", "{{json .}}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s ps failed: %w", system, err)
+	}
+	var containers []map[string]interface{}
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var c map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			return nil, fmt.Errorf("unexpected %s ps output: %w", system, err)
+		}
+		containers = append(containers, map[string]interface{}{
+			"id":     c["ID"],
+			"names":  c["Names"],
+			"image":  c["Image"],
+			"state":  c["State"],
+			"status": c["Status"],
+		})
+	}
+	return map[string]interface{}{
+		"system":     system,
+		"count":      len(containers),
+		"containers": containers,
+	}, nil
+}
+
+func (s *Server) handleContainerRunTool(args map[string]interface{}) (interface{}, error) {
+	if !s.hasPermission("container:run") {
+		return nil, fmt.Errorf("running containers requires standard or full permissions")
+	}
+	image, _ := args["image"].(string)
+	if image == "" || strings.HasPrefix(image, "-") {
+		return nil, fmt.Errorf("image parameter required")
+	}
+	cmdArgs := []string{"run", "-d"}
+	if name, _ := args["name"].(string); name != "" {
+		if !containerNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid container name: %s", name)
+		}
+		cmdArgs = append(cmdArgs, "--name", name)
+	}
+	for _, option := range []struct{ key, flag string }{{"ports", "-p"}, {"env", "-e"}, {"volumes", "-v"}} {
+		values, err := stringList(args, option.key)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			if option.key == "volumes" && isBindMount(value) && s.Permissions != "full" {
+				return nil, fmt.Errorf("mounting host directories (%s) requires full permissions; use a named volume", value)
+			}
+			cmdArgs = append(cmdArgs, option.flag, value)
+		}
+	}
+	command, err := stringList(args, "command")
+	if err != nil {
+		return nil, err
+	}
+	cmdArgs = append(cmdArgs, image)
+	cmdArgs = append(cmdArgs, command...)
+	system, err := containerRuntime(args)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := exec.Command(system, cmdArgs...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%s run failed: %w\nOutput: %s", system, err, strings.TrimSpace(string(output)))
+	}
+	return map[string]interface{}{
+		"status":       "running",
+		"system":       system,
+		"image":        image,
+		"container_id": strings.TrimSpace(string(output)),
+	}, nil
+}
+
+// isBindMount reports whether a -v value mounts a host path rather than a
+// named or anonymous volume
+func isBindMount(volume string) bool {
+	source, _, hasTarget := strings.Cut(volume, ":")
+	if !hasTarget {
+		// An anonymous volume at a container path
+		return false
+	}
+	// One letter sources are Windows drives (C:\src:/src)
+	return len(source) < 2 || !containerNamePattern.MatchString(source)
+}
+
+func (s *Server) handleContainerExecTool(args map[string]interface{}) (interface{}, error) {
+	if !s.hasPermission("container:exec") {
+		return nil, fmt.Errorf("executing commands in containers requires standard or full permissions")
+	}
+	container, _ := args["container"].(string)
+	if !containerNamePattern.MatchString(container) {
+		return nil, fmt.Errorf("container parameter required")
+	}
+	command, err := stringList(args, "command")
+	if err != nil {
+		return nil, err
+	}
+	if len(command) == 0 {
+		return nil, fmt.Errorf("command parameter required")
+	}
+	system, err := containerRuntime(args)
+	if err != nil {
+		return nil, err
+	}
+
+	cmdArgs := append([]string{"exec", container}, command...)
+	output, err := exec.Command(system, cmdArgs...).CombinedOutput()
+	exitCode := 0
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return nil, fmt.Errorf("%s exec failed: %w", system, err)
+	}
+	return map[string]interface{}{
+		"container": container,
+		"command":   strings.Join(command, " "),
+		"exit_code": exitCode,
+		"output":    string(output),
+	}, nil
+}
+
+func (s *Server) handleContainerLogsTool(args map[string]interface{}) (interface{}, error) {
+	if !s.hasPermission("container:info") {
+		return nil, fmt.Errorf("insufficient permissions to read container logs")
+	}
+	container, _ := args["container"].(string)
+	if !containerNamePattern.MatchString(container) {
+		return nil, fmt.Errorf("container parameter required")
+	}
+	tail := 100
+	if t, ok := args["tail"].(float64); ok && t > 0 {
+		tail = int(t)
+	}
+	system, err := containerRuntime(args)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := exec.Command(system, "logs", "--tail", fmt.Sprint(tail), container).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%s logs failed: %w\nOutput: %s", system, err, strings.TrimSpace(string(output)))
+	}
+	return map[string]interface{}{
+		"container": container,
+		"lines":     tail,
+		"logs":      string(output),
+	}, nil
+}
+
+func (s *Server) handlePftListTool(args map[string]interface{}) (interface{}, error) {
+	cmdArgs := []string{"pft", "list", "--json"}
+	switch area, _ := args["area"].(string); area {
+	case "voc", "vos":
+		cmdArgs = append(cmdArgs, "--"+area)
+	case "":
+	default:
+		return nil, fmt.Errorf("invalid area: %s (valid: voc, vos)", area)
+	}
+	if category, _ := args["category"].(string); category != "" {
+		cmdArgs = append(cmdArgs, "--category", category)
+	}
+	if path, _ := args["path"].(string); path != "" {
+		cmdArgs = append(cmdArgs, "--path", path)
+	}
+
+	output, err := s.executePtxPft(cmdArgs...)
+	if err != nil {
+		return nil, err
+	}
+	var items []interface{}
+	if err := json.Unmarshal([]byte(output), &items); err != nil {
+		// Not configured or no items: ptx-pft prints a plain message
+		return map[string]interface{}{
+			"status":  "success",
+			"message": strings.TrimSpace(output),
+		}, nil
+	}
+	return map[string]interface{}{
+		"status": "success",
+		"count":  len(items),
+		"items":  items,
+	}, nil
+}
+
+func (s *Server) handlePftShowTool(args map[string]interface{}) (interface{}, error) {
+	id, _ := args["id"].(string)
+	if id == "" || strings.HasPrefix(id, "-") {
+		return nil, fmt.Errorf("id parameter required")
+	}
+	cmdArgs := []string{"pft", "show", id}
+	if path, _ := args["path"].(string); path != "" {
+		cmdArgs = append(cmdArgs, "--path", path)
+	}
+
+	output, err := s.executePtxPft(cmdArgs...)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"status": "success",
+		"item":   output,
+	}, nil
+}
@@ -0,0 +1,204 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"portunix.ai/app/daemon"
+	"portunix.ai/app/progress"
+)
+
+func TestDevEnvToolsListed(t *testing.T) {
+	server := NewServer(0, "limited", "")
+	result, err := server.handleToolsList(nil)
+	if err != nil {
+		t.Fatalf("handleToolsList failed: %v", err)
+	}
+
+	names := map[string]int{}
+	for _, tool := range result.(map[string]interface{})["tools"].([]map[string]interface{}) {
+		names[tool["name"].(string)]++
+	}
+	for _, want := range []string{"install_package", "container_list", "container_run", "container_exec", "container_logs", "pft_list", "pft_show"} {
+		if names[want] != 1 {
+			t.Errorf("tool %s listed %d times, want 1", want, names[want])
+		}
+	}
+}
+
+func TestInstallToolPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions string
+		policy      string
+		args        map[string]interface{}
+		wantErr     string
+	}{
+		{
+			name:        "limited permissions",
+			permissions: "limited",
+			policy:      InstallPolicyAllow,
+			args:        map[string]interface{}{"package": "nodejs"},
+			wantErr:     "requires standard or full permissions",
+		},
+		{
+			name:        "deny policy",
+			permissions: "full",
+			policy:      InstallPolicyDeny,
+			args:        map[string]interface{}{"package": "nodejs", "plan_token": "x"},
+			wantErr:     "disabled by the server install policy",
+		},
+		{
+			name:        "missing package",
+			permissions: "standard",
+			policy:      InstallPolicyConfirm,
+			args:        map[string]interface{}{},
+			wantErr:     "package parameter required",
+		},
+		{
+			name:        "injection in package name",
+			permissions: "standard",
+			policy:      InstallPolicyConfirm,
+			args:        map[string]interface{}{"package": "nodejs; rm -rf /"},
+			wantErr:     "invalid package name",
+		},
+		{
+			name:        "option as variant",
+			permissions: "standard",
+			policy:      InstallPolicyConfirm,
+			args:        map[string]interface{}{"package": "java", "variant": "--force"},
+			wantErr:     "invalid variant",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer(0, tt.permissions, "")
+			server.InstallPolicy = tt.policy
+			_, err := server.handleInstallTool(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInstallToolPlanToken(t *testing.T) {
+	var installed []*daemon.InstallRequest
+	previous := portunixInstall
+	portunixInstall = func(req *daemon.InstallRequest, _ func(progress.Event)) (*daemon.CommandResponse, error) {
+		installed = append(installed, req)
+		return &daemon.CommandResponse{Output: "plan of " + req.Package}, nil
+	}
+	t.Cleanup(func() { portunixInstall = previous })
+
+	server := NewServer(0, "standard", "")
+	install := func(args map[string]interface{}) (map[string]interface{}, error) {
+		result, err := server.handleInstallTool(args)
+		if err != nil {
+			return nil, err
+		}
+		return result.(map[string]interface{}), nil
+	}
+
+	// confirm=true on the first call only returns the plan
+	result, err := install(map[string]interface{}{"package": "nodejs", "confirm": true})
+	if err != nil || result["status"] != "confirmation_required" || !installed[0].DryRun {
+		t.Fatalf("first call should return a plan: %v %v", result, err)
+	}
+	token := result["plan_token"].(string)
+
+	if _, err := install(map[string]interface{}{"package": "nodejs", "plan_token": "forged"}); err == nil {
+		t.Error("a forged plan token must be rejected")
+	}
+	if _, err := install(map[string]interface{}{"package": "python", "plan_token": token}); err == nil {
+		t.Error("a plan token must only install the planned package")
+	}
+
+	// The token was consumed by the mismatching call; plan again
+	result, _ = install(map[string]interface{}{"package": "nodejs"})
+	token = result["plan_token"].(string)
+	if result, err := install(map[string]interface{}{"package": "nodejs", "plan_token": token}); err != nil || result["status"] != "installed" {
+		t.Fatalf("approved plan should install: %v %v", result, err)
+	}
+	if last := installed[len(installed)-1]; last.DryRun || last.Package != "nodejs" {
+		t.Errorf("unexpected install %+v", last)
+	}
+	if _, err := install(map[string]interface{}{"package": "nodejs", "plan_token": token}); err == nil {
+		t.Error("a plan token must install only once")
+	}
+}
+
+func TestContainerRunBindMounts(t *testing.T) {
+	for volume, bind := range map[string]bool{
+		"/:/host":             true,
+		"./src:/src":          true,
+		"~/.ssh:/root/.ssh":   true,
+		`C:\Users:/users`:     true,
+		"pgdata:/var/lib/pg":  false,
+		"/var/lib/postgresql": false,
+	} {
+		if got := isBindMount(volume); got != bind {
+			t.Errorf("isBindMount(%q) = %v, want %v", volume, got, bind)
+		}
+	}
+
+	server := NewServer(0, "standard", "")
+	_, err := server.handleContainerRunTool(map[string]interface{}{"image": "alpine", "volumes": []interface{}{"/:/host"}})
+	if err == nil || !strings.Contains(err.Error(), "full permissions") {
+		t.Errorf("host mounts must be rejected below full permissions, got %v", err)
+	}
+}
+
+func TestContainerToolValidation(t *testing.T) {
+	server := NewServer(0, "limited", "")
+	if _, err := server.handleContainerRunTool(map[string]interface{}{"image": "alpine"}); err == nil {
+		t.Error("container_run must be rejected with limited permissions")
+	}
+	if _, err := server.handleContainerExecTool(map[string]interface{}{"container": "web", "command": []interface{}{"ls"}}); err == nil {
+		t.Error("container_exec must be rejected with limited permissions")
+	}
+
+	server = NewServer(0, "standard", "")
+	if _, err := server.handleContainerExecTool(map[string]interface{}{"container": "web", "command": "ls"}); err == nil {
+		t.Error("container_exec must reject a command that is not an array")
+	}
+	if _, err := server.handleContainerLogsTool(map[string]interface{}{"container": "-f"}); err == nil {
+		t.Error("container_logs must reject an option as container name")
+	}
+	if _, err := server.handleContainerRunTool(map[string]interface{}{"image": "alpine", "system": "lxc"}); err == nil {
+		t.Error("container_run must reject an unsupported runtime")
+	}
+}
+
+func TestProgressNotifications(t *testing.T) {
+	var sent []MCPNotification
+	onProgress := progressNotifier("tok-1", func(n MCPNotification) { sent = append(sent, n) })
+	onProgress(progress.Event{Stage: "download", Percent: 40, Message: "go.tar.gz"})
+	onProgress(progress.Event{Stage: "download", Percent: 100, Done: true})
+	onProgress(progress.Event{Stage: "extract", Percent: progress.Unknown})
+
+	if len(sent) != 3 {
+		t.Fatalf("sent %d notifications", len(sent))
+	}
+	last := -1.0
+	for _, n := range sent {
+		params := n.Params.(map[string]interface{})
+		if n.Method != "notifications/progress" || params["progressToken"] != "tok-1" {
+			t.Errorf("notification %+v", n)
+		}
+		if p := params["progress"].(float64); p <= last {
+			t.Errorf("progress %v after %v does not increase", p, last)
+		} else {
+			last = p
+		}
+	}
+	if msg := sent[0].Params.(map[string]interface{})["message"]; msg != "download 40% go.tar.gz" {
+		t.Errorf("message %q", msg)
+	}
+}
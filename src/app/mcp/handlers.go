@@ -75,20 +75,6 @@ func (s *Server) handleToolsList(params json.RawMessage) (interface{}, error) {
 				"description": "No parameters required",
 			},
 		},
-		{
-			"name":        "install_package",
-			"description": "Install a package using Portunix package manager",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"package": map[string]interface{}{
-						"type":        "string",
-						"description": "Package name to install",
-					},
-				},
-				"required": []string{"package"},
-			},
-		},
 		{
 			"name":        "detect_project_type",
 			"description": "Analyze current directory and detect project type and technologies",
@@ -703,6 +689,8 @@ func (s *Server) handleToolsList(params json.RawMessage) (interface{}, error) {
 		},
 	}
 
+	tools = append(tools, devEnvTools()...)
+
 	return map[string]interface{}{
 		"tools": tools,
 	}, nil
@@ -729,11 +717,19 @@ func (s *Server) handleToolsCall(params json.RawMessage) (interface{}, error) {
 	case "list_packages":
 		result, err = s.handleListAvailablePackages(nil)
 	case "install_package":
-		if packageName, ok := request.Arguments["package"].(string); ok {
-			result, err = s.handleInstallPackageWithName(packageName)
-		} else {
-			err = fmt.Errorf("package parameter required")
-		}
+		result, err = s.handleInstallTool(request.Arguments)
+	case "container_list":
+		result, err = s.handleContainerListTool(request.Arguments)
+	case "container_run":
+		result, err = s.handleContainerRunTool(request.Arguments)
+	case "container_exec":
+		result, err = s.handleContainerExecTool(request.Arguments)
+	case "container_logs":
+		result, err = s.handleContainerLogsTool(request.Arguments)
+	case "pft_list":
+		result, err = s.handlePftListTool(request.Arguments)
+	case "pft_show":
+		result, err = s.handlePftShowTool(request.Arguments)
 	case "detect_project_type":
 		result, err = s.handleDetectProjectType(nil)
 	case "vm_list":
@@ -817,8 +813,12 @@ func (s *Server) handleToolsCall(params json.RawMessage) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	return toolResponse(result), nil
+}
 
-	// Return MCP-compliant tool response with formatted text
+// toolResponse returns a tool result as MCP-compliant content with
+// formatted text
+func toolResponse(result interface{}) map[string]interface{} {
 	formattedText := formatResultAsText(result)
 	return map[string]interface{}{
 		"content": []map[string]interface{}{
@@ -827,7 +827,7 @@ func (s *Server) handleToolsCall(params json.RawMessage) (interface{}, error) {
 				"text": formattedText,
 			},
 		},
-	}, nil
+	}
 }
 
 // System Information Handlers
@@ -837,6 +837,7 @@ func (s *Server) handleGetSystemInfo(params json.RawMessage) (interface{}, error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get system information: %w", err)
 	}
+	system.CollectHardware(systemInfo)
 
 	return map[string]interface{}{
 		"portunix_version": version.ProductVersion,
@@ -4728,23 +4729,6 @@ func executeDeployment(projectPath, projectType, environment, platform string, c
 	return result, nil
 }
 
-// Helper function for install_package tool
-func (s *Server) handleInstallPackageWithName(packageName string) (interface{}, error) {
-	// Create a JSON request for the package installation
-	params := map[string]interface{}{
-		"package":      packageName,
-		"auto_install": true,
-	}
-
-	// Convert to JSON and call the existing handler
-	jsonParams, err := json.Marshal(params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal parameters: %w", err)
-	}
-
-	return s.handleInstallPackage(jsonParams)
-}
-
 // formatResultAsText converts result to human-readable text
 func formatResultAsText(result interface{}) string {
 	switch v := result.(type) {
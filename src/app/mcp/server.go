@@ -24,11 +24,13 @@ import (
 
 // Server represents the MCP server instance
 type Server struct {
-	Port        int
-	Permissions string
-	Config      string
-	upgrader    websocket.Upgrader
-	handlers    map[string]MethodHandler
+	Port          int
+	Permissions   string
+	Config        string
+	InstallPolicy string // InstallPolicyConfirm, InstallPolicyAllow or InstallPolicyDeny
+	installPlans  installPlans
+	upgrader      websocket.Upgrader
+	handlers      map[string]MethodHandler
 }
 
 // MethodHandler defines the interface for MCP method handlers
@@ -50,6 +52,17 @@ type MCPResponse struct {
 	ID      interface{} `json:"id,omitempty"`
 }
 
+// MCPNotification is a JSON-RPC notification sent to the client, such as
+// the progress of a tool call
+type MCPNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// Notifier sends notifications on the connection of a request
+type Notifier func(MCPNotification)
+
 // MCPError represents an MCP error
 type MCPError struct {
 	Code    int         `json:"code"`
@@ -60,9 +73,10 @@ type MCPError struct {
 // NewServer creates a new MCP server instance
 func NewServer(port int, permissions, config string) *Server {
 	server := &Server{
-		Port:        port,
-		Permissions: permissions,
-		Config:      config,
+		Port:          port,
+		Permissions:   permissions,
+		Config:        config,
+		InstallPolicy: InstallPolicyConfirm,
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				return true // Allow all origins for now
@@ -219,7 +233,7 @@ func (s *Server) StartStdioNewline() error {
 			}
 
 			// Process the request
-			response := s.processRequest(request)
+			response := s.processRequestNotify(request, lineNotifier(writer))
 
 			// Marshal response to JSON (compact, no whitespace)
 			responseJSON, err := json.Marshal(response)
@@ -299,7 +313,9 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			break
 		}
 
-		response := s.processRequest(request)
+		response := s.processRequestNotify(request, func(n MCPNotification) {
+			conn.WriteJSON(n)
+		})
 
 		if err := conn.WriteJSON(response); err != nil {
 			log.Printf("Error writing JSON: %v", err)
@@ -324,11 +340,29 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 
 // processRequest processes an incoming MCP request
 func (s *Server) processRequest(request MCPRequest) MCPResponse {
+	return s.processRequestNotify(request, nil)
+}
+
+// processRequestNotify processes a request on a connection that can carry
+// notifications: tool calls with a progress token report the progress of
+// long operations as notifications/progress
+func (s *Server) processRequestNotify(request MCPRequest, notify Notifier) MCPResponse {
 	response := MCPResponse{
 		JSONRPC: "2.0",
 		ID:      request.ID,
 	}
 
+	if request.Method == "tools/call" && notify != nil {
+		if result, handled, err := s.handleToolsCallProgress(request.Params, notify); handled {
+			if err != nil {
+				response.Error = &MCPError{Code: -32603, Message: "Internal error", Data: err.Error()}
+			} else {
+				response.Result = result
+			}
+			return response
+		}
+	}
+
 	handler, exists := s.handlers[request.Method]
 	if !exists {
 		response.Error = &MCPError{
@@ -353,6 +387,19 @@ func (s *Server) processRequest(request MCPRequest) MCPResponse {
 	return response
 }
 
+// lineNotifier sends notifications as newline-delimited JSON-RPC
+func lineNotifier(writer *bufio.Writer) Notifier {
+	return func(n MCPNotification) {
+		data, err := json.Marshal(n)
+		if err != nil {
+			return
+		}
+		writer.Write(data)
+		writer.WriteByte('\n')
+		writer.Flush()
+	}
+}
+
 // registerHandlers registers all MCP method handlers
 func (s *Server) registerHandlers() {
 	// Standard MCP protocol methods
@@ -562,7 +609,7 @@ func (s *Server) handleGenericConnection(conn net.Conn) {
 		}
 
 		// Process the request
-		response := s.processRequest(request)
+		response := s.processRequestNotify(request, lineNotifier(writer))
 
 		// Marshal response to JSON
 		responseJSON, err := json.Marshal(response)
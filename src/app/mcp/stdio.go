@@ -208,7 +208,9 @@ func (h *StdioHandler) Run() error {
 			}
 
 			// Process the request
-			response := h.server.processRequest(request)
+			response := h.server.processRequestNotify(request, func(n MCPNotification) {
+				encoder.Encode(n)
+			})
 
 			// Send response
 			if err := encoder.Encode(response); err != nil {
@@ -278,7 +280,7 @@ func StartStdioWithConfig(config *ServerConfig) error {
 			}
 
 			// Process request
-			response := server.processRequest(request)
+			response := server.processRequestNotify(request, lineNotifier(writer))
 
 			// Write response
 			responseBytes, err := json.Marshal(response)
@@ -0,0 +1,499 @@
+// Package metrics records opt-in usage metrics of portunix and its helpers:
+// which commands run, how long they take and why they fail. Events are kept
+// locally in ~/.portunix/metrics and only leave the machine when exported to
+// the endpoint configured in "metrics.endpoint". Arguments are never
+// recorded, only the command path and the package name of installs.
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"portunix.ai/app/config"
+)
+
+// Environment variables
+const (
+	// EnvDir overrides the metrics directory
+	EnvDir = "PORTUNIX_METRICS_DIR"
+	// EnvFailureFile is set by the dispatcher for helpers to report the
+	// failure category of a failed command (see ReportFailure)
+	EnvFailureFile = "PORTUNIX_METRICS_FAILURE_FILE"
+)
+
+// Configuration keys
+const (
+	KeyEnabled  = "metrics.enabled"
+	KeyEndpoint = "metrics.endpoint"
+)
+
+// Failure categories
+const (
+	FailureNetwork     = "network"
+	FailurePermission  = "permission"
+	FailureNotFound    = "not_found"
+	FailureUnsupported = "unsupported"
+	FailureDependency  = "dependency"
+	FailureChecksum    = "checksum"
+	FailureTimeout     = "timeout"
+	FailureCancelled   = "cancelled"
+	FailureUsage       = "usage"
+	FailureUnknown     = "unknown"
+)
+
+const (
+	eventsFile = "events.jsonl"
+	exportFile = "export.json"
+)
+
+// Event is a single recorded command run
+type Event struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`           // command path, e.g. "container run"
+	Package    string    `json:"package,omitempty"` // package of "install"
+	Helper     string    `json:"helper,omitempty"`  // helper binary that ran the command
+	DurationMs int64     `json:"duration_ms"`
+	Success    bool      `json:"success"`
+	ExitCode   int       `json:"exit_code,omitempty"`
+	Failure    string    `json:"failure,omitempty"` // failure category
+	Version    string    `json:"version"`
+	OS         string    `json:"os"`
+	Arch       string    `json:"arch"`
+}
+
+// Enabled reports whether metrics collection is turned on
+func Enabled() bool {
+	return config.GetBool(KeyEnabled, false)
+}
+
+// Dir returns the metrics directory
+func Dir() string {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-metrics")
+	}
+	return filepath.Join(home, ".portunix", "metrics")
+}
+
+// StorePath returns the local event store
+func StorePath() string {
+	return filepath.Join(Dir(), eventsFile)
+}
+
+// Record appends an event to the local store when metrics are enabled
+func Record(e Event) error {
+	if !Enabled() {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.OS, e.Arch = runtime.GOOS, runtime.GOARCH
+
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(StorePath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Load reads all events from the local store
+func Load() ([]Event, error) {
+	f, err := os.Open(StorePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err == nil {
+			events = append(events, e)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// Clear removes all recorded events and the export state
+func Clear() error {
+	for _, name := range []string{eventsFile, exportFile} {
+		if err := os.Remove(filepath.Join(Dir(), name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// CommandFromArgs derives the recorded command path and install package
+// from command-line arguments. Only words that look like subcommand names
+// are kept, so paths, images and other user data are not recorded.
+func CommandFromArgs(args []string) (command, pkg string) {
+	var words []string
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		words = append(words, arg)
+		if len(words) == 2 {
+			break
+		}
+	}
+	if len(words) == 0 {
+		return "", ""
+	}
+
+	command = words[0]
+	if len(words) < 2 {
+		return command, ""
+	}
+	if command == "install" {
+		return command, words[1]
+	}
+	if isCommandWord(words[1]) {
+		command += " " + words[1]
+	}
+	return command, ""
+}
+
+// isCommandWord reports whether a word looks like a subcommand name
+func isCommandWord(word string) bool {
+	if word == "" || len(word) > 24 {
+		return false
+	}
+	for _, c := range word {
+		if (c < 'a' || c > 'z') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// Categorize maps an error message to a failure category
+func Categorize(message string) string {
+	msg := strings.ToLower(message)
+	categories := []struct {
+		category string
+		patterns []string
+	}{
+		{FailureChecksum, []string{"checksum", "signature", "sha256"}},
+		{FailureTimeout, []string{"timeout", "timed out", "deadline exceeded"}},
+		{FailureCancelled, []string{"interrupt", "cancelled", "canceled", "signal: killed"}},
+		{FailureNetwork, []string{"connection refused", "no such host", "network", "dial tcp", "tls", "http", "download", "proxy", "certificate"}},
+		{FailurePermission, []string{"permission denied", "access is denied", "operation not permitted", "administrator", "sudo", "root privileges"}},
+		{FailureUnsupported, []string{"not supported", "unsupported", "no variant", "platform"}},
+		{FailureDependency, []string{"dependency", "prerequisite", "requires"}},
+		{FailureNotFound, []string{"not found", "no such file", "does not exist", "unknown package", "executable file not found"}},
+		{FailureUsage, []string{"unknown command", "unknown flag", "invalid argument", "usage:", "required"}},
+	}
+	for _, c := range categories {
+		for _, p := range c.patterns {
+			if strings.Contains(msg, p) {
+				return c.category
+			}
+		}
+	}
+	return FailureUnknown
+}
+
+// ReportFailure lets a helper pass the failure category of the current
+// command to the dispatcher. Does nothing when not run by a recording
+// dispatcher.
+func ReportFailure(err error) {
+	path := os.Getenv(EnvFailureFile)
+	if path == "" || err == nil {
+		return
+	}
+	_ = os.WriteFile(path, []byte(Categorize(err.Error())), 0600)
+}
+
+// Tracker measures a single command run
+type Tracker struct {
+	command     string
+	pkg         string
+	version     string
+	start       time.Time
+	failureFile string
+}
+
+// Start begins tracking a command. Returns nil when metrics are disabled;
+// a nil Tracker is safe to use.
+func Start(args []string, version string) *Tracker {
+	if !Enabled() {
+		return nil
+	}
+	command, pkg := CommandFromArgs(args)
+	if command == "" || command == "metrics" || strings.HasPrefix(command, "metrics ") || strings.HasPrefix(command, "__complete") {
+		return nil
+	}
+	return &Tracker{command: command, pkg: pkg, version: version, start: time.Now()}
+}
+
+// PrepareHelper exports the failure report file to a helper process
+func (t *Tracker) PrepareHelper() {
+	if t == nil {
+		return
+	}
+	f, err := os.CreateTemp("", "portunix-failure-*")
+	if err != nil {
+		return
+	}
+	f.Close()
+	t.failureFile = f.Name()
+	os.Setenv(EnvFailureFile, t.failureFile)
+}
+
+// Finish records the outcome of the tracked command
+func (t *Tracker) Finish(helper string, runErr error) {
+	if t == nil {
+		return
+	}
+
+	e := Event{
+		Command:    t.command,
+		Package:    t.pkg,
+		Helper:     helper,
+		DurationMs: time.Since(t.start).Milliseconds(),
+		Success:    runErr == nil,
+		Version:    t.version,
+	}
+
+	if t.failureFile != "" {
+		reported, _ := os.ReadFile(t.failureFile)
+		os.Remove(t.failureFile)
+		os.Unsetenv(EnvFailureFile)
+		e.Failure = strings.TrimSpace(string(reported))
+	}
+
+	if runErr != nil {
+		e.ExitCode = 1
+		var exitErr *exec.ExitError
+		if errors.As(runErr, &exitErr) {
+			e.ExitCode = exitErr.ExitCode()
+		} else if e.Failure == "" {
+			e.Failure = Categorize(runErr.Error())
+		}
+		if e.Failure == "" {
+			e.Failure = FailureUnknown
+		}
+	}
+
+	_ = Record(e)
+}
+
+// CommandStats aggregates runs of a command
+type CommandStats struct {
+	Command       string `json:"command"`
+	Runs          int    `json:"runs"`
+	Failures      int    `json:"failures"`
+	AvgDurationMs int64  `json:"avg_duration_ms"`
+}
+
+// PackageStats aggregates installs of a package
+type PackageStats struct {
+	Package  string         `json:"package"`
+	Installs int            `json:"installs"`
+	Failures int            `json:"failures"`
+	Causes   map[string]int `json:"causes,omitempty"` // failure category counts
+}
+
+// Summary aggregates recorded events
+type Summary struct {
+	Events     int            `json:"events"`
+	Since      time.Time      `json:"since,omitempty"`
+	Commands   []CommandStats `json:"commands"`
+	Packages   []PackageStats `json:"packages"`
+	Categories map[string]int `json:"failure_categories"`
+}
+
+// Summarize aggregates events; commands are ordered by runs, packages by
+// failures so the most failing installs come first
+func Summarize(events []Event) Summary {
+	s := Summary{Events: len(events), Categories: map[string]int{}}
+	commands := make(map[string]*CommandStats)
+	packages := make(map[string]*PackageStats)
+	totalDuration := make(map[string]int64)
+
+	for _, e := range events {
+		if s.Since.IsZero() || e.Time.Before(s.Since) {
+			s.Since = e.Time
+		}
+
+		c, ok := commands[e.Command]
+		if !ok {
+			c = &CommandStats{Command: e.Command}
+			commands[e.Command] = c
+		}
+		c.Runs++
+		totalDuration[e.Command] += e.DurationMs
+		if !e.Success {
+			c.Failures++
+			s.Categories[e.Failure]++
+		}
+
+		if e.Package != "" {
+			p, ok := packages[e.Package]
+			if !ok {
+				p = &PackageStats{Package: e.Package, Causes: map[string]int{}}
+				packages[e.Package] = p
+			}
+			p.Installs++
+			if !e.Success {
+				p.Failures++
+				p.Causes[e.Failure]++
+			}
+		}
+	}
+
+	for name, c := range commands {
+		c.AvgDurationMs = totalDuration[name] / int64(c.Runs)
+		s.Commands = append(s.Commands, *c)
+	}
+	sort.Slice(s.Commands, func(i, j int) bool {
+		if s.Commands[i].Runs != s.Commands[j].Runs {
+			return s.Commands[i].Runs > s.Commands[j].Runs
+		}
+		return s.Commands[i].Command < s.Commands[j].Command
+	})
+
+	for _, p := range packages {
+		s.Packages = append(s.Packages, *p)
+	}
+	sort.Slice(s.Packages, func(i, j int) bool {
+		if s.Packages[i].Failures != s.Packages[j].Failures {
+			return s.Packages[i].Failures > s.Packages[j].Failures
+		}
+		if s.Packages[i].Installs != s.Packages[j].Installs {
+			return s.Packages[i].Installs > s.Packages[j].Installs
+		}
+		return s.Packages[i].Package < s.Packages[j].Package
+	})
+	return s
+}
+
+// Since filters events recorded after t
+func Since(events []Event, t time.Time) []Event {
+	var result []Event
+	for _, e := range events {
+		if !e.Time.Before(t) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// exportState tracks how many stored events were already exported
+type exportState struct {
+	Exported int       `json:"exported"`
+	LastTime time.Time `json:"last_export"`
+}
+
+func loadExportState() exportState {
+	var state exportState
+	if data, err := os.ReadFile(filepath.Join(Dir(), exportFile)); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+// Pending returns the events not yet exported
+func Pending() ([]Event, error) {
+	pending, _, err := pendingEvents()
+	return pending, err
+}
+
+// pendingEvents returns the events not yet exported with their offset in the store
+func pendingEvents() ([]Event, int, error) {
+	events, err := Load()
+	if err != nil {
+		return nil, 0, err
+	}
+	offset := loadExportState().Exported
+	if offset > len(events) {
+		// store was cleared since the last export
+		offset = 0
+	}
+	return events[offset:], offset, nil
+}
+
+// Export sends pending events to the endpoint as a JSON batch and marks
+// them exported. Returns the number of exported events.
+func Export(endpoint string) (int, error) {
+	if endpoint == "" {
+		return 0, fmt.Errorf("no export endpoint configured (portunix config set %s <url>)", KeyEndpoint)
+	}
+
+	pending, offset, err := pendingEvents()
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"schema": 1, "events": pending})
+	if err != nil {
+		return 0, err
+	}
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("export failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("export failed: endpoint returned %s", resp.Status)
+	}
+
+	state := exportState{Exported: offset + len(pending), LastTime: time.Now()}
+	data, _ := json.MarshalIndent(state, "", "  ")
+	if err := os.WriteFile(filepath.Join(Dir(), exportFile), data, 0644); err != nil {
+		return 0, err
+	}
+	return len(pending), nil
+}
+
+// LastExport returns the time of the last successful export
+func LastExport() time.Time {
+	return loadExportState().LastTime
+}
+
+// FormatDuration renders milliseconds for reports
+func FormatDuration(ms int64) string {
+	if ms < 1000 {
+		return strconv.FormatInt(ms, 10) + "ms"
+	}
+	return (time.Duration(ms) * time.Millisecond).Round(100 * time.Millisecond).String()
+}
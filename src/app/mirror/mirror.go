@@ -0,0 +1,246 @@
+// Package mirror redirects downloads and container image pulls to internal
+// mirrors and registries (e.g. Artifactory, Harbor), selected by the active
+// network profile
+package mirror
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"portunix.ai/app/config"
+)
+
+// KeyProfile is the configuration key selecting the network profile
+const KeyProfile = "network.profile"
+
+// EnvMirrorsFile overrides the mirror definition files
+const EnvMirrorsFile = "PORTUNIX_MIRRORS_FILE"
+
+// FileName is the mirror definition file kept next to config.yaml of the
+// system and user scopes
+const FileName = "mirrors.yaml"
+
+// ErrBlocked is returned in strict profiles for downloads and images
+// without a mirror
+var ErrBlocked = errors.New("no mirror configured")
+
+// Rule redirects URLs starting with From to To
+type Rule struct {
+	From string `yaml:"from" json:"from"`
+	To   string `yaml:"to" json:"to"`
+}
+
+// Profile holds the mirrors used on one network, e.g. office or airgapped
+type Profile struct {
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Downloads are URL prefix rewrites; the longest matching From wins
+	Downloads []Rule `yaml:"downloads,omitempty" json:"downloads,omitempty"`
+	// Registries maps a registry (docker.io, ghcr.io, ...) to the mirror
+	// repository prefix replacing it; "*" matches any registry
+	Registries map[string]string `yaml:"registries,omitempty" json:"registries,omitempty"`
+	// Strict refuses downloads and image pulls that have no mirror, for
+	// networks without internet access
+	Strict bool `yaml:"strict,omitempty" json:"strict,omitempty"`
+}
+
+// File is the content of a mirrors.yaml file
+type File struct {
+	Profiles map[string]*Profile `yaml:"profiles" json:"profiles"`
+}
+
+// Files returns the mirror definition files in load order; profiles of
+// later files replace profiles of the same name
+func Files() []string {
+	if path := os.Getenv(EnvMirrorsFile); path != "" {
+		return []string{path}
+	}
+	return []string{
+		filepath.Join(filepath.Dir(config.ScopePath(config.ScopeSystem)), FileName),
+		filepath.Join(filepath.Dir(config.ScopePath(config.ScopeUser)), FileName),
+	}
+}
+
+// Load reads and merges the mirror definition files
+func Load() (*File, error) {
+	merged := &File{Profiles: make(map[string]*Profile)}
+	for _, path := range Files() {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var f File
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for name, p := range f.Profiles {
+			if p == nil {
+				p = &Profile{}
+			}
+			merged.Profiles[name] = p
+		}
+	}
+	return merged, nil
+}
+
+// Names returns the defined profile names, sorted
+func (f *File) Names() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActiveName returns the configured network profile, "" when none is set
+func ActiveName() string {
+	return config.GetString(KeyProfile, "")
+}
+
+// Active returns the active network profile. It returns a nil profile when
+// no profile is selected or the selected one defines no mirrors.
+func Active() (string, *Profile, error) {
+	name := ActiveName()
+	if name == "" {
+		return "", nil, nil
+	}
+	f, err := Load()
+	if err != nil {
+		return name, nil, err
+	}
+	return name, f.Profiles[name], nil
+}
+
+// RewriteURL returns the mirror URL of a download for the active profile
+func RewriteURL(rawURL string) (string, error) {
+	name, p, err := Active()
+	if err != nil || p == nil {
+		return rawURL, err
+	}
+	if mirrored, ok := p.URL(rawURL); ok {
+		return mirrored, nil
+	}
+	if p.Strict {
+		return "", fmt.Errorf("%s: %w for network profile %q", rawURL, ErrBlocked, name)
+	}
+	return rawURL, nil
+}
+
+// DownloadURLs returns the URLs to try for a download, in order: the
+// mirrors of the active profile, longest matching rule first, and the
+// original URL unless the profile is strict. Later URLs are failovers for
+// mirrors that are down.
+func DownloadURLs(rawURL string) ([]string, error) {
+	name, p, err := Active()
+	if err != nil || p == nil {
+		return []string{rawURL}, err
+	}
+	urls := p.URLs(rawURL)
+	if len(urls) == 0 && p.Strict {
+		return nil, fmt.Errorf("%s: %w for network profile %q", rawURL, ErrBlocked, name)
+	}
+	if !p.Strict {
+		urls = append(urls, rawURL)
+	}
+	return urls, nil
+}
+
+// RewriteImage returns the mirror image reference for the active profile
+func RewriteImage(image string) (string, error) {
+	name, p, err := Active()
+	if err != nil || p == nil {
+		return image, err
+	}
+	if mirrored, ok := p.Image(image); ok {
+		return mirrored, nil
+	}
+	if p.Strict {
+		return "", fmt.Errorf("image %s: %w for network profile %q", image, ErrBlocked, name)
+	}
+	return image, nil
+}
+
+// URL rewrites rawURL with the longest matching download rule
+func (p *Profile) URL(rawURL string) (string, bool) {
+	best := -1
+	for i, rule := range p.Downloads {
+		if rule.From == "" || !strings.HasPrefix(rawURL, rule.From) {
+			continue
+		}
+		if best < 0 || len(rule.From) > len(p.Downloads[best].From) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return rawURL, false
+	}
+	rule := p.Downloads[best]
+	return rule.To + strings.TrimPrefix(rawURL, rule.From), true
+}
+
+// URLs returns the rewrites of rawURL by every matching download rule,
+// longest From first; rules with the same From keep their order, so a
+// profile can list failover mirrors of one source
+func (p *Profile) URLs(rawURL string) []string {
+	var rules []Rule
+	for _, rule := range p.Downloads {
+		if rule.From != "" && strings.HasPrefix(rawURL, rule.From) {
+			rules = append(rules, rule)
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return len(rules[i].From) > len(rules[j].From) })
+	urls := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		urls = append(urls, rule.To+strings.TrimPrefix(rawURL, rule.From))
+	}
+	return urls
+}
+
+// Image rewrites an image reference to the mirror of its registry. Images
+// already referring to a mirror are left alone.
+func (p *Profile) Image(image string) (string, bool) {
+	registry, path := SplitImage(image)
+	for _, prefix := range p.Registries {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if image == prefix || strings.HasPrefix(image, prefix+"/") {
+			return image, true
+		}
+	}
+	prefix, ok := p.Registries[registry]
+	if !ok {
+		if prefix, ok = p.Registries["*"]; !ok {
+			return image, false
+		}
+		// A catch-all mirror keeps the registry to tell sources apart
+		path = registry + "/" + path
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + path, true
+}
+
+// SplitImage splits an image reference into its registry and the
+// repository path with tag or digest, applying Docker's defaults:
+// "ubuntu:22.04" is docker.io, library/ubuntu:22.04
+func SplitImage(image string) (string, string) {
+	first, rest, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		if first == "index.docker.io" || first == "registry-1.docker.io" {
+			first = "docker.io"
+		}
+		if first == "docker.io" && !strings.Contains(rest, "/") {
+			rest = "library/" + rest
+		}
+		return first, rest
+	}
+	if !found {
+		return "docker.io", "library/" + image
+	}
+	return "docker.io", image
+}
@@ -0,0 +1,145 @@
+package mirror
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testMirrors = `profiles:
+  office:
+    description: Corporate network
+    downloads:
+      - from: https://github.com/
+        to: https://artifactory.corp.example.com/github/
+      - from: https://github.com/cassandragargoyle/
+        to: https://artifactory.corp.example.com/portunix/
+    registries:
+      docker.io: harbor.corp.example.com/dockerhub
+      ghcr.io: harbor.corp.example.com/ghcr/
+  home:
+  airgapped:
+    strict: true
+    downloads:
+      - from: https://go.dev/dl/
+        to: http://files.lab/go/
+    registries:
+      "*": registry.lab:5000/mirror
+  hotel:
+    downloads:
+      - from: https://go.dev/dl/
+        to: https://mirror-a.example.com/go/
+      - from: https://go.dev/
+        to: https://mirror-c.example.com/
+      - from: https://go.dev/dl/
+        to: https://mirror-b.example.com/go/
+`
+
+func TestSplitImage(t *testing.T) {
+	cases := map[string][2]string{
+		"ubuntu:22.04":                   {"docker.io", "library/ubuntu:22.04"},
+		"bitnami/redis":                  {"docker.io", "bitnami/redis"},
+		"docker.io/nginx":                {"docker.io", "library/nginx"},
+		"ghcr.io/org/app:1.0":            {"ghcr.io", "org/app:1.0"},
+		"localhost/test":                 {"localhost", "test"},
+		"registry.lab:5000/x/y@sha256:1": {"registry.lab:5000", "x/y@sha256:1"},
+	}
+	for image, want := range cases {
+		registry, path := SplitImage(image)
+		if registry != want[0] || path != want[1] {
+			t.Errorf("SplitImage(%q) = %q, %q; want %q, %q", image, registry, path, want[0], want[1])
+		}
+	}
+}
+
+func TestProfileRewrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(testMirrors), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvMirrorsFile, path)
+
+	f, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := f.Names(); len(names) != 4 || f.Profiles["home"] == nil {
+		t.Fatalf("unexpected profiles %v", names)
+	}
+
+	office := f.Profiles["office"]
+	if got, _ := office.URL("https://github.com/cassandragargoyle/portunix/releases/x.zip"); got != "https://artifactory.corp.example.com/portunix/portunix/releases/x.zip" {
+		t.Errorf("longest rule should win, got %s", got)
+	}
+	if got, ok := office.URL("https://nodejs.org/dist/node.tar.gz"); ok || got != "https://nodejs.org/dist/node.tar.gz" {
+		t.Errorf("unmatched URL should be unchanged, got %s", got)
+	}
+	if got, _ := office.Image("ubuntu:22.04"); got != "harbor.corp.example.com/dockerhub/library/ubuntu:22.04" {
+		t.Errorf("unexpected docker.io mirror %s", got)
+	}
+	if got, _ := office.Image("ghcr.io/org/app:1.0"); got != "harbor.corp.example.com/ghcr/org/app:1.0" {
+		t.Errorf("unexpected ghcr.io mirror %s", got)
+	}
+	if got, ok := office.Image("harbor.corp.example.com/dockerhub/library/ubuntu"); !ok || got != "harbor.corp.example.com/dockerhub/library/ubuntu" {
+		t.Errorf("mirror images should be kept, got %s", got)
+	}
+	if _, ok := office.Image("quay.io/podman/stable"); ok {
+		t.Error("registry without mirror should not be rewritten")
+	}
+
+	airgapped := f.Profiles["airgapped"]
+	if got, _ := airgapped.Image("quay.io/podman/stable"); got != "registry.lab:5000/mirror/quay.io/podman/stable" {
+		t.Errorf("unexpected catch-all mirror %s", got)
+	}
+}
+
+func TestStrictProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(testMirrors), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvMirrorsFile, path)
+
+	t.Setenv("PORTUNIX_NETWORK_PROFILE", "airgapped")
+	if got, err := RewriteURL("https://go.dev/dl/go1.22.linux-amd64.tar.gz"); err != nil || got != "http://files.lab/go/go1.22.linux-amd64.tar.gz" {
+		t.Errorf("unexpected rewrite %s, %v", got, err)
+	}
+	if _, err := RewriteURL("https://nodejs.org/dist/node.tar.gz"); !errors.Is(err, ErrBlocked) {
+		t.Errorf("strict profile should block unmirrored downloads, got %v", err)
+	}
+
+	t.Setenv("PORTUNIX_NETWORK_PROFILE", "home")
+	if got, err := RewriteImage("ubuntu:22.04"); err != nil || got != "ubuntu:22.04" {
+		t.Errorf("profile without mirrors should not rewrite, got %s, %v", got, err)
+	}
+}
+
+func TestDownloadURLs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(testMirrors), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvMirrorsFile, path)
+
+	t.Setenv("PORTUNIX_NETWORK_PROFILE", "hotel")
+	urls, err := DownloadURLs("https://go.dev/dl/go1.22.linux-amd64.tar.gz")
+	want := []string{
+		"https://mirror-a.example.com/go/go1.22.linux-amd64.tar.gz",
+		"https://mirror-b.example.com/go/go1.22.linux-amd64.tar.gz",
+		"https://mirror-c.example.com/dl/go1.22.linux-amd64.tar.gz",
+		"https://go.dev/dl/go1.22.linux-amd64.tar.gz",
+	}
+	if err != nil || strings.Join(urls, " ") != strings.Join(want, " ") {
+		t.Errorf("DownloadURLs = %v, %v; want %v", urls, err, want)
+	}
+
+	t.Setenv("PORTUNIX_NETWORK_PROFILE", "airgapped")
+	if urls, err := DownloadURLs("https://go.dev/dl/go1.22.linux-amd64.tar.gz"); err != nil || len(urls) != 1 {
+		t.Errorf("strict profile should not fall back to the source, got %v, %v", urls, err)
+	}
+	if _, err := DownloadURLs("https://nodejs.org/dist/node.tar.gz"); !errors.Is(err, ErrBlocked) {
+		t.Errorf("strict profile should block unmirrored downloads, got %v", err)
+	}
+}
@@ -18,7 +18,10 @@ import (
 	"strings"
 	"time"
 
+	"portunix.ai/app/container"
 	"portunix.ai/app/docker"
+	"portunix.ai/app/mirror"
+	"portunix.ai/app/proxy"
 	"portunix.ai/app/system"
 )
 
@@ -42,6 +45,7 @@ type PodmanConfig struct {
 	CacheShared       bool
 	CachePath         string
 	InstallationType  string
+	Packages          []string // installed instead of InstallationType, e.g. template tools
 	DryRun            bool
 	AutoInstallPodman bool
 	Rootless          bool   // Podman-specific: run in rootless mode
@@ -234,6 +238,13 @@ func RunInContainer(config PodmanConfig) error {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 
+	// Pull through the registry mirror of the network profile
+	image, err := mirror.RewriteImage(config.Image)
+	if err != nil {
+		return err
+	}
+	config.Image = image
+
 	// In dry-run mode, show what would be executed
 	if config.DryRun {
 		return runInContainerDryRun(config)
@@ -290,8 +301,13 @@ func RunInContainer(config PodmanConfig) error {
 		return fmt.Errorf("container failed to start: %w", err)
 	}
 
-	// Install software based on installation type
-	if err := installSoftwareInPodmanContainer(config.ContainerName, config.InstallationType, pkgManager); err != nil {
+	// Install software based on installation type or the packages of a template
+	if len(config.Packages) > 0 {
+		err = installPackagesInPodmanContainer(config.ContainerName, config.InstallationType, config.Packages, pkgManager)
+	} else {
+		err = installSoftwareInPodmanContainer(config.ContainerName, config.InstallationType, pkgManager)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to install software in container: %w", err)
 	}
 
@@ -432,25 +448,31 @@ func ShowLogs(containerID string, follow bool) error {
 	return cmd.Run()
 }
 
-// ExecCommand executes a command in a running container (interactive mode by default)
+// ExecCommand executes a command in a running container (interactive mode
+// by default, without a terminal when stdin is not one)
 func ExecCommand(containerID string, command []string) error {
 	return ExecCommandWithOptions(containerID, command, true)
 }
 
 // ExecCommandWithOptions executes a command in a running container with configurable options
 func ExecCommandWithOptions(containerID string, command []string, interactive bool) error {
-	var args []string
-	if interactive {
-		args = append([]string{"exec", "-it", containerID}, command...)
-	} else {
-		args = append([]string{"exec", containerID}, command...)
-	}
+	return ExecWithOptions(containerID, command, container.ExecOptions{Interactive: interactive, TTY: interactive})
+}
 
-	cmd := exec.Command("podman", args...)
+// ExecWithOptions executes a command in a running container with
+// environment variables, working directory and user. The error of a
+// command that fails is an *exec.ExitError with its exit code.
+func ExecWithOptions(containerID string, command []string, opts container.ExecOptions) error {
+	opts = opts.ForStdin()
+	cmd, cleanup, err := opts.Command("podman", containerID, command)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	if interactive {
+	if opts.Interactive {
 		cmd.Stdin = os.Stdin
 	}
 
@@ -661,6 +683,9 @@ func buildPodmanRunArgs(config PodmanConfig) []string {
 	for _, env := range config.Environment {
 		args = append(args, "-e", env)
 	}
+	for _, env := range proxy.ContainerEnvironment(config.Environment) {
+		args = append(args, "-e", env)
+	}
 
 	// Cache directory mounting
 	if config.CacheShared {
@@ -1153,6 +1178,9 @@ func runInContainerDryRun(config PodmanConfig) error {
 
 	// 5. Install packages based on type
 	fmt.Printf("5. Install software in container:\n")
+	for _, pkg := range config.Packages {
+		fmt.Printf("   portunix install %s\n", pkg)
+	}
 	switch config.InstallationType {
 	case "default":
 		fmt.Printf("   • Install Python, Java, and VSCode\n")
@@ -1206,8 +1234,13 @@ func installSoftwareInPodmanContainer(containerName string, installationType str
 		fmt.Println("✓ Empty installation type - skipping software installation")
 		return nil
 	}
+	return installPackagesInPodmanContainer(containerName, installationType, []string{installationType}, pkgManager)
+}
 
-	fmt.Printf("\n📦 Installing %s environment in container using Portunix install command...\n", installationType)
+// installPackagesInPodmanContainer installs packages with Portunix install commands; label names
+// the environment in messages
+func installPackagesInPodmanContainer(containerName, label string, packages []string, pkgManager *PackageManagerInfo) error {
+	fmt.Printf("\n📦 Installing %s environment in container using Portunix install command...\n", label)
 
 	// Setup certificates before installation if needed for HTTPS downloads
 	if err := setupContainerCertificates(containerName, pkgManager); err != nil {
@@ -1220,11 +1253,13 @@ func installSoftwareInPodmanContainer(containerName string, installationType str
 	}
 
 	// Run standard Portunix install command inside container
-	if err := runPortunixInstallInPodmanContainer(containerName, installationType); err != nil {
-		return fmt.Errorf("failed to run Portunix install in container: %w", err)
+	for _, pkg := range packages {
+		if err := runPortunixInstallInPodmanContainer(containerName, pkg); err != nil {
+			return fmt.Errorf("failed to run Portunix install in container: %w", err)
+		}
 	}
 
-	fmt.Printf("✅ %s environment installed successfully!\n", installationType)
+	fmt.Printf("✅ %s environment installed successfully!\n", label)
 	return nil
 }
 
@@ -2196,6 +2231,20 @@ func parsePodmanArgs(installationType string, args []string) (PodmanConfig, erro
 			config.Privileged = true
 		case arg == "--no-ssh":
 			config.EnableSSH = false
+		case arg == "--install":
+			if i+1 < len(args) {
+				config.Packages = append(config.Packages, args[i+1])
+				i++ // Skip next argument
+			}
+		case strings.HasPrefix(arg, "--install="):
+			config.Packages = append(config.Packages, strings.SplitN(arg, "=", 2)[1])
+		case arg == "--workdir":
+			if i+1 < len(args) {
+				config.WorkingDir = args[i+1]
+				i++ // Skip next argument
+			}
+		case strings.HasPrefix(arg, "--workdir="):
+			config.WorkingDir = strings.SplitN(arg, "=", 2)[1]
 		case arg == "--image":
 			if i+1 < len(args) {
 				config.Image = args[i+1]
@@ -2221,6 +2270,11 @@ func parsePodmanArgs(installationType string, args []string) (PodmanConfig, erro
 func setupContainerCertificates(containerName string, pkgManager *PackageManagerInfo) error {
 	fmt.Println("🔐 Setting up CA certificates for HTTPS connectivity...")
 
+	// Package managers need the proxy before the first index update
+	if err := proxy.ConfigureContainer("podman", containerName); err != nil {
+		fmt.Printf("⚠️  Warning: %v\n", err)
+	}
+
 	// Update package manager first
 	updateCmd := system.GeneratePackageUpdateCommand(pkgManager.Manager)
 	if len(updateCmd) > 0 && updateCmd[0] != "echo" {
@@ -2277,6 +2331,11 @@ type ContainerRunOptions struct {
 
 // RunContainer runs a generic Podman container with specified options
 func RunContainer(image string, command []string, options ContainerRunOptions) error {
+	image, err := mirror.RewriteImage(image)
+	if err != nil {
+		return err
+	}
+
 	// Build podman run command
 	args := []string{"run"}
 
@@ -2311,6 +2370,9 @@ func RunContainer(image string, command []string, options ContainerRunOptions) e
 	for _, env := range options.Environment {
 		args = append(args, "-e", env)
 	}
+	for _, env := range proxy.ContainerEnvironment(options.Environment) {
+		args = append(args, "-e", env)
+	}
 
 	// Add image
 	args = append(args, image)
@@ -2332,7 +2394,7 @@ func RunContainer(image string, command []string, options ContainerRunOptions) e
 		cmd.Stdin = os.Stdin
 	}
 
-	err := cmd.Run()
+	err = cmd.Run()
 	if err != nil {
 		return fmt.Errorf("failed to run Podman container: %w", err)
 	}
@@ -0,0 +1,326 @@
+// Package progress is the protocol long operations (downloads, installs,
+// image pulls, deployments) report their progress with. Events are
+// rendered as progress bars on a terminal, as plain lines otherwise, and
+// as JSON lines when PORTUNIX_PROGRESS=json, so the daemon and the MCP
+// server can relay them from the portunix processes they run to clients.
+package progress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// EnvFormat selects the output of reporters: "json" writes events as JSON
+// lines, "text" plain lines, "bar" progress bars; unset draws bars on
+// terminals and lines otherwise. The daemon sets it for the commands it
+// runs.
+const EnvFormat = "PORTUNIX_PROGRESS"
+
+// Output formats of a Reporter
+const (
+	FormatBar  = "bar"
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// EventType marks the JSON lines of the protocol in mixed command output
+const EventType = "progress"
+
+// Unknown is the percent of operations whose size is not known
+const Unknown = -1
+
+// Event is a progress report of one stage of an operation
+type Event struct {
+	Type    string  `json:"type"` // always EventType
+	Stage   string  `json:"stage"`
+	Percent float64 `json:"percent"` // 0-100, or Unknown
+	Message string  `json:"message,omitempty"`
+	// Current and Total count bytes or items when known
+	Current int64     `json:"current,omitempty"`
+	Total   int64     `json:"total,omitempty"`
+	Done    bool      `json:"done,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// ParseLine returns the event of a protocol line; ok is false for other
+// output
+func ParseLine(line []byte) (*Event, bool) {
+	line = []byte(strings.TrimSpace(string(line)))
+	if len(line) == 0 || line[0] != '{' {
+		return nil, false
+	}
+	var e Event
+	if err := json.Unmarshal(line, &e); err != nil || e.Type != EventType || e.Stage == "" {
+		return nil, false
+	}
+	return &e, true
+}
+
+// Relay reads command output, passes progress events to fn and writes the
+// other lines to text (which may be nil). It returns when r is exhausted.
+func Relay(r io.Reader, text io.Writer, fn func(Event)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if e, ok := ParseLine(scanner.Bytes()); ok {
+			if fn != nil {
+				fn(*e)
+			}
+			continue
+		}
+		if text != nil {
+			fmt.Fprintln(text, scanner.Text())
+		}
+	}
+	return scanner.Err()
+}
+
+// CombinedOutput runs cmd like exec.Cmd.CombinedOutput. With onProgress
+// the command is asked for JSON progress events, which are passed to it
+// and left out of the output.
+func CombinedOutput(cmd *exec.Cmd, onProgress func(Event)) ([]byte, error) {
+	if onProgress == nil {
+		return cmd.CombinedOutput()
+	}
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, EnvFormat+"="+FormatJSON)
+	pr, pw := io.Pipe()
+	cmd.Stdout, cmd.Stderr = pw, pw
+	var output bytes.Buffer
+	relayed := make(chan struct{})
+	go func() {
+		defer close(relayed)
+		Relay(pr, &output, onProgress)
+		// Drain what an overlong line left, so the command cannot block
+		io.Copy(io.Discard, pr)
+	}()
+	err := cmd.Run()
+	pw.Close()
+	<-relayed
+	return output.Bytes(), err
+}
+
+// Reporter writes progress events in one of the formats
+type Reporter struct {
+	w      io.Writer
+	format string
+
+	mu sync.Mutex
+	// lastDraw throttles bar redraws; lastText the lines of text output
+	lastDraw time.Time
+	lastText map[string]float64
+	drawing  bool // a bar is drawn without a newline
+}
+
+// NewReporter creates a reporter writing to w in the format of
+// PORTUNIX_PROGRESS, or bars when w is a terminal and lines otherwise
+func NewReporter(w io.Writer) *Reporter {
+	format := os.Getenv(EnvFormat)
+	switch format {
+	case FormatBar, FormatText, FormatJSON:
+	default:
+		format = FormatText
+		if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			format = FormatBar
+		}
+	}
+	return NewReporterFormat(w, format)
+}
+
+// NewReporterFormat creates a reporter writing to w in format
+func NewReporterFormat(w io.Writer, format string) *Reporter {
+	return &Reporter{w: w, format: format, lastText: make(map[string]float64)}
+}
+
+// Format returns the output format of the reporter
+func (r *Reporter) Format() string {
+	return r.format
+}
+
+// Report writes an event
+func (r *Reporter) Report(e Event) {
+	e.Type = EventType
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if e.Done && e.Error == "" && e.Percent != Unknown {
+		e.Percent = 100
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch r.format {
+	case FormatJSON:
+		data, _ := json.Marshal(e)
+		fmt.Fprintf(r.w, "%s\n", data)
+	case FormatBar:
+		r.drawBar(e)
+	default:
+		r.writeText(e)
+	}
+	if e.Done {
+		delete(r.lastText, e.Stage)
+	}
+}
+
+// Update reports the progress of a stage
+func (r *Reporter) Update(stage string, percent float64, message string) {
+	r.Report(Event{Stage: stage, Percent: percent, Message: message})
+}
+
+// Done reports that a stage completed
+func (r *Reporter) Done(stage, message string) {
+	r.Report(Event{Stage: stage, Percent: 100, Message: message, Done: true})
+}
+
+// Fail reports that a stage failed
+func (r *Reporter) Fail(stage string, err error) {
+	r.Report(Event{Stage: stage, Percent: Unknown, Message: err.Error(), Done: true, Error: err.Error()})
+}
+
+// Reader returns r reporting the bytes read from it as the progress of
+// stage; total is the expected size, 0 when unknown
+func (r *Reporter) Reader(stage string, reader io.Reader, total int64) io.Reader {
+	return r.ResumeReader(stage, reader, 0, total)
+}
+
+// ResumeReader is Reader for a download resumed after offset bytes, which
+// count as read
+func (r *Reporter) ResumeReader(stage string, reader io.Reader, offset, total int64) io.Reader {
+	return &countingReader{reporter: r, stage: stage, reader: reader, total: total, read: offset}
+}
+
+// barWidth is the number of cells of drawn progress bars
+const barWidth = 20
+
+// Bar returns a progress bar of percent
+func Bar(percent float64) string {
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	filled := int(percent / 100 * barWidth)
+	return strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+}
+
+func (r *Reporter) drawBar(e Event) {
+	// Redraw at most 10 times per second, except for the final state
+	if !e.Done && r.drawing && time.Since(r.lastDraw) < 100*time.Millisecond {
+		return
+	}
+	r.lastDraw = time.Now()
+
+	var line string
+	switch {
+	case e.Error != "":
+		line = fmt.Sprintf("❌ %s: %s", e.Stage, e.Error)
+	case e.Done:
+		line = fmt.Sprintf("✅ %s", e.Stage)
+		if e.Message != "" {
+			line += ": " + e.Message
+		}
+	case e.Percent == Unknown:
+		line = fmt.Sprintf("⏳ %s", e.Stage)
+		if e.Current > 0 {
+			line += " " + FormatBytes(e.Current)
+		}
+		if e.Message != "" {
+			line += " " + e.Message
+		}
+	default:
+		line = fmt.Sprintf("⏳ %s [%s] %5.1f%%", e.Stage, Bar(e.Percent), e.Percent)
+		if e.Total > 0 {
+			line += fmt.Sprintf(" (%s / %s)", FormatBytes(e.Current), FormatBytes(e.Total))
+		}
+		if e.Message != "" {
+			line += " " + e.Message
+		}
+	}
+	// Pad to overwrite a longer previous line
+	fmt.Fprintf(r.w, "\r%-70s", line)
+	r.drawing = !e.Done
+	if e.Done {
+		fmt.Fprintln(r.w)
+	}
+}
+
+func (r *Reporter) writeText(e Event) {
+	switch {
+	case e.Error != "":
+		fmt.Fprintf(r.w, "%s failed: %s\n", e.Stage, e.Error)
+		return
+	case e.Done:
+		if e.Message != "" {
+			fmt.Fprintf(r.w, "%s done: %s\n", e.Stage, e.Message)
+		} else {
+			fmt.Fprintf(r.w, "%s done\n", e.Stage)
+		}
+		return
+	}
+	// Lines at the start of a stage and every 10%
+	last, started := r.lastText[e.Stage]
+	if started && (e.Percent == Unknown || int(e.Percent/10) == int(last/10)) {
+		return
+	}
+	r.lastText[e.Stage] = e.Percent
+	line := e.Stage
+	if e.Percent != Unknown {
+		line += fmt.Sprintf(" %3.0f%%", e.Percent)
+	}
+	if e.Message != "" {
+		line += " " + e.Message
+	}
+	fmt.Fprintln(r.w, line)
+}
+
+type countingReader struct {
+	reporter *Reporter
+	stage    string
+	reader   io.Reader
+	total    int64
+	read     int64
+	last     time.Time
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.read += int64(n)
+	if n > 0 && (time.Since(c.last) >= 100*time.Millisecond || c.read == c.total) {
+		c.last = time.Now()
+		percent := float64(Unknown)
+		if c.total > 0 {
+			percent = float64(c.read) / float64(c.total) * 100
+		}
+		c.reporter.Report(Event{Stage: c.stage, Percent: percent, Current: c.read, Total: c.total})
+	}
+	return n, err
+}
+
+// FormatBytes formats a byte count for humans
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
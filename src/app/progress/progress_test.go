@@ -0,0 +1,83 @@
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRelayJSONEvents(t *testing.T) {
+	var output bytes.Buffer
+	output.WriteString("📥 Downloading go1.24.tar.gz\n")
+	r := NewReporterFormat(&output, FormatJSON)
+	data := strings.Repeat("x", 1000)
+	io.Copy(io.Discard, r.Reader("download", strings.NewReader(data), int64(len(data))))
+	r.Done("download", "1000 B")
+	r.Fail("install", errors.New("exit status 1"))
+	output.WriteString(`{"type":"other","stage":"x"}` + "\n")
+
+	var events []Event
+	var text bytes.Buffer
+	if err := Relay(&output, &text, func(e Event) { events = append(events, e) }); err != nil {
+		t.Fatal(err)
+	}
+	if text.String() != "📥 Downloading go1.24.tar.gz\n{\"type\":\"other\",\"stage\":\"x\"}\n" {
+		t.Errorf("text lines: %q", text.String())
+	}
+	if len(events) != 3 {
+		t.Fatalf("events: %+v", events)
+	}
+	if e := events[0]; e.Stage != "download" || e.Percent != 100 || e.Current != 1000 || e.Total != 1000 || e.Done {
+		t.Errorf("read event: %+v", e)
+	}
+	if e := events[1]; !e.Done || e.Message != "1000 B" {
+		t.Errorf("done event: %+v", e)
+	}
+	if e := events[2]; !e.Done || e.Error != "exit status 1" || e.Percent != Unknown {
+		t.Errorf("fail event: %+v", e)
+	}
+}
+
+func TestTextReporter(t *testing.T) {
+	var output bytes.Buffer
+	r := NewReporterFormat(&output, FormatText)
+	for _, percent := range []float64{0, 3, 9, 10, 15, 55, 56} {
+		r.Update("pull", percent, "postgres:16")
+	}
+	r.Done("pull", "")
+	want := "pull   0% postgres:16\npull  10% postgres:16\npull  55% postgres:16\npull done\n"
+	if output.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", output.String(), want)
+	}
+}
+
+func TestBar(t *testing.T) {
+	for percent, want := range map[float64]string{
+		-1:  strings.Repeat("░", 20),
+		50:  strings.Repeat("█", 10) + strings.Repeat("░", 10),
+		120: strings.Repeat("█", 20),
+	} {
+		if got := Bar(percent); got != want {
+			t.Errorf("Bar(%v) = %s", percent, got)
+		}
+	}
+}
+
+func TestCombinedOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	script := `echo "format $` + EnvFormat + `"; echo '{"type":"progress","stage":"pull","percent":50}' >&2`
+	var events []Event
+	output, err := CombinedOutput(exec.Command("sh", "-c", script), func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(output) != "format json\n" || len(events) != 1 || events[0].Stage != "pull" {
+		t.Errorf("output %q, events %+v", output, events)
+	}
+}
@@ -0,0 +1,98 @@
+package proxy
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"portunix.ai/app/config"
+)
+
+// ImportedCAPath returns the file imported proxy CA certificates are
+// collected in
+func ImportedCAPath() string {
+	return filepath.Join(Dir(), "proxy-ca.pem")
+}
+
+// ImportCA adds the certificates of a PEM or DER file to the imported
+// proxy CA certificates and makes them the configured proxy.ca_bundle.
+// It returns the subjects of the newly added certificates.
+func ImportCA(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := parseCertificates(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	dest := ImportedCAPath()
+	existing, _ := os.ReadFile(dest)
+	known := make(map[string]bool)
+	if current, err := parseCertificates(existing); err == nil {
+		for _, c := range current {
+			known[string(c.Raw)] = true
+		}
+	}
+
+	var added []string
+	content := bytes.TrimRight(existing, "\n")
+	for _, c := range certs {
+		if known[string(c.Raw)] {
+			continue
+		}
+		known[string(c.Raw)] = true
+		if len(content) > 0 {
+			content = append(content, '\n')
+		}
+		content = append(content, bytes.TrimRight(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}), "\n")...)
+		added = append(added, c.Subject.String())
+	}
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(dest, append(content, '\n'), 0644); err != nil {
+		return nil, err
+	}
+	// Rebuild the combined bundle on next use
+	os.Remove(CombinedBundlePath())
+	if err := config.Set(config.ScopeUser, KeyCABundle, dest); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// parseCertificates reads PEM encoded certificates, or a single DER
+// certificate as exported by Windows and browsers
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 && len(data) > 0 {
+		if cert, err := x509.ParseCertificate(data); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates found")
+	}
+	return certs, nil
+}
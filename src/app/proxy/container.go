@@ -0,0 +1,74 @@
+package proxy
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// containerCAPath is where the proxy CA is copied inside containers
+const containerCAPath = "/tmp/portunix-proxy-ca.crt"
+
+// ConfigureContainer makes the package managers of a running container use
+// the proxy and trust the proxy CA. runtime is "docker" or "podman". It
+// must run before the container's package index is updated.
+func ConfigureContainer(runtime, container string) error {
+	s := Load()
+	if err := s.resolvePAC(); err != nil {
+		return err
+	}
+	if !s.HasProxy() && s.CABundle == "" {
+		return nil
+	}
+	fmt.Println("🌐 Applying proxy configuration to container...")
+	if s.CABundle != "" {
+		if out, err := exec.Command(runtime, "cp", s.CABundle, container+":"+containerCAPath).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to copy proxy CA: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+	}
+	script := ContainerSetupScript(s, s.CABundle != "")
+	if out, err := exec.Command(runtime, "exec", container, "sh", "-c", script).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to configure proxy in container: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ContainerSetupScript returns the shell script configuring apt, dnf and
+// yum for the proxy and installing the proxy CA copied to the container.
+// apk and most other tools read the proxy environment variables passed
+// to the container.
+func ContainerSetupScript(s *Settings, withCA bool) string {
+	var script []string
+	httpsProxy := s.HTTPS
+	if httpsProxy == "" {
+		httpsProxy = s.HTTP
+	}
+	if s.HasProxy() {
+		var apt []string
+		if s.HTTP != "" {
+			apt = append(apt, fmt.Sprintf(`Acquire::http::Proxy \"%s\";`, s.HTTP))
+		}
+		apt = append(apt, fmt.Sprintf(`Acquire::https::Proxy \"%s\";`, httpsProxy))
+		script = append(script,
+			fmt.Sprintf(`if [ -d /etc/apt/apt.conf.d ]; then printf '%%s\n' "%s" > /etc/apt/apt.conf.d/95portunix-proxy; fi`, strings.Join(apt, `" "`)),
+		)
+		yumProxy := s.HTTP
+		if yumProxy == "" {
+			yumProxy = httpsProxy
+		}
+		for _, conf := range []string{"/etc/dnf/dnf.conf", "/etc/yum.conf"} {
+			script = append(script, fmt.Sprintf(
+				`if [ -f %[1]s ]; then sed -i '/^proxy=/d' %[1]s; sed -i '/^\[main\]/a proxy=%[2]s' %[1]s; fi`, conf, yumProxy))
+		}
+	}
+	if withCA {
+		script = append(script,
+			`if [ -d /usr/local/share/ca-certificates ] || command -v update-ca-certificates >/dev/null 2>&1; then mkdir -p /usr/local/share/ca-certificates && cp `+containerCAPath+` /usr/local/share/ca-certificates/portunix-proxy.crt; command -v update-ca-certificates >/dev/null 2>&1 && update-ca-certificates >/dev/null 2>&1; fi`,
+			`if [ -d /etc/pki/ca-trust/source/anchors ]; then cp `+containerCAPath+` /etc/pki/ca-trust/source/anchors/portunix-proxy.crt && update-ca-trust >/dev/null 2>&1; fi`,
+			// Until the CA tools are installed, append to an existing bundle
+			`for b in /etc/ssl/certs/ca-certificates.crt /etc/pki/tls/certs/ca-bundle.crt; do if [ -f "$b" ] && ! grep -q "$(sed -n 2p `+containerCAPath+`)" "$b"; then cat `+containerCAPath+` >> "$b"; fi; done`,
+		)
+	}
+	script = append(script, "true")
+	return strings.Join(script, "\n")
+}
@@ -0,0 +1,90 @@
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// pacCacheTTL is how long a resolved PAC file is reused
+const pacCacheTTL = time.Hour
+
+// pacDirective matches the proxy entries of FindProxyForURL results
+var pacDirective = regexp.MustCompile(`\b(PROXY|HTTPS|SOCKS5?|SOCKS4)\s+([A-Za-z0-9._\-\[\]:]+)`)
+
+// ResolvePAC returns the proxy of a proxy auto-config file, given as URL
+// or path. PAC files are JavaScript; portunix does not evaluate them but
+// uses their first PROXY, HTTPS or SOCKS entry, which covers the common
+// "one corporate proxy, direct for intranet hosts" files when the
+// intranet hosts are listed in proxy.no_proxy. It returns "" for files
+// that only return DIRECT.
+func ResolvePAC(location string) (string, error) {
+	cache := filepath.Join(Dir(), "pac-cache")
+	if data, err := os.ReadFile(cache); err == nil {
+		cachedLocation, proxyURL, _ := strings.Cut(string(data), "\n")
+		if info, err := os.Stat(cache); err == nil && cachedLocation == location && time.Since(info.ModTime()) < pacCacheTTL {
+			return proxyURL, nil
+		}
+	}
+
+	script, err := fetchPAC(location)
+	if err != nil {
+		return "", err
+	}
+	proxyURL := ParsePAC(script)
+	if os.MkdirAll(Dir(), 0755) == nil {
+		os.WriteFile(cache, []byte(location+"\n"+proxyURL), 0644)
+	}
+	return proxyURL, nil
+}
+
+// ParsePAC returns the first proxy of a PAC script as URL
+func ParsePAC(script string) string {
+	match := pacDirective.FindStringSubmatch(script)
+	if match == nil {
+		return ""
+	}
+	scheme := "http"
+	switch match[1] {
+	case "HTTPS":
+		scheme = "https"
+	case "SOCKS", "SOCKS5":
+		scheme = "socks5"
+	case "SOCKS4":
+		scheme = "socks4"
+	}
+	return scheme + "://" + match[2]
+}
+
+// fetchPAC reads a PAC file from a URL, a file:// URL or a path. The PAC
+// file is fetched directly, as the proxy is not known yet.
+func fetchPAC(location string) (string, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		data, err := os.ReadFile(strings.TrimPrefix(location, "file://"))
+		return string(data), err
+	}
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{Proxy: nil},
+	}
+	resp, err := client.Get(location)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	return string(data), err
+}
+
+func execOutput(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
@@ -0,0 +1,267 @@
+// Package proxy applies the configured corporate proxy and CA bundle to
+// portunix, the processes it starts, containers and developer tools
+package proxy
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"portunix.ai/app/config"
+)
+
+// Configuration keys
+const (
+	KeyHTTP     = "proxy.http"
+	KeyHTTPS    = "proxy.https"
+	KeyNoProxy  = "proxy.no_proxy"
+	KeyPAC      = "proxy.pac"
+	KeyCABundle = "proxy.ca_bundle"
+)
+
+// Settings is the effective proxy configuration
+type Settings struct {
+	HTTP    string `json:"http,omitempty"`
+	HTTPS   string `json:"https,omitempty"`
+	NoProxy string `json:"no_proxy,omitempty"`
+	// PAC is the URL or path of a proxy auto-config file; it is used when
+	// no explicit proxy is configured
+	PAC string `json:"pac,omitempty"`
+	// CABundle is a PEM file with the CA certificates of a TLS-intercepting
+	// proxy, trusted in addition to the system certificates
+	CABundle string `json:"ca_bundle,omitempty"`
+}
+
+// Load reads the proxy settings from the portunix configuration. The keys
+// are user only: a project configuration found in a cloned repository must
+// not route traffic through its own proxy or add a trusted CA.
+func Load() *Settings {
+	return &Settings{
+		HTTP:     config.GetString(KeyHTTP, ""),
+		HTTPS:    config.GetString(KeyHTTPS, ""),
+		NoProxy:  config.GetString(KeyNoProxy, ""),
+		PAC:      config.GetString(KeyPAC, ""),
+		CABundle: config.GetString(KeyCABundle, ""),
+	}
+}
+
+// IgnoredProjectKeys returns the proxy keys set in the project
+// configuration, which Load ignores
+func IgnoredProjectKeys() []string {
+	return config.IgnoredProjectKeys()
+}
+
+// Empty reports whether nothing is configured
+func (s *Settings) Empty() bool {
+	return *s == Settings{}
+}
+
+// HasProxy reports whether a proxy server is set
+func (s *Settings) HasProxy() bool {
+	return s.HTTP != "" || s.HTTPS != ""
+}
+
+// Apply loads the settings and applies them to this process: the proxy
+// environment variables, inherited by helpers and every tool portunix
+// runs, and the CA bundle for Go HTTP clients and common tools. It must
+// run before the first HTTP request, as Go reads the proxy variables once.
+func Apply() (*Settings, error) {
+	s := Load()
+	if s.Empty() {
+		return s, nil
+	}
+	if err := s.resolvePAC(); err != nil {
+		return s, err
+	}
+	for name, value := range s.Environment() {
+		os.Setenv(name, value)
+	}
+	if s.CABundle != "" {
+		if err := trustCABundle(s.CABundle); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// resolvePAC fills in the proxy from the PAC file when no explicit proxy
+// is configured
+func (s *Settings) resolvePAC() error {
+	if s.PAC == "" || s.HasProxy() {
+		return nil
+	}
+	proxyURL, err := ResolvePAC(s.PAC)
+	if err != nil {
+		return fmt.Errorf("failed to resolve PAC file %s: %w", s.PAC, err)
+	}
+	s.HTTP, s.HTTPS = proxyURL, proxyURL
+	return nil
+}
+
+// Environment returns the environment variables carrying the settings.
+// Both spellings are set because tools disagree on the case they read.
+func (s *Settings) Environment() map[string]string {
+	env := make(map[string]string)
+	httpsProxy := s.HTTPS
+	if httpsProxy == "" {
+		httpsProxy = s.HTTP
+	}
+	for _, v := range []struct{ name, value string }{
+		{"HTTP_PROXY", s.HTTP},
+		{"HTTPS_PROXY", httpsProxy},
+		{"NO_PROXY", s.NoProxy},
+	} {
+		if v.value != "" {
+			env[v.name] = v.value
+			env[strings.ToLower(v.name)] = v.value
+		}
+	}
+	if s.CABundle != "" {
+		// Node.js adds NODE_EXTRA_CA_CERTS to its built-in certificates
+		env["NODE_EXTRA_CA_CERTS"] = s.CABundle
+		// The others replace the system certificates, so they get the
+		// system bundle with the proxy CA appended
+		if bundle := CombinedBundlePath(); fileExists(bundle) {
+			for _, name := range []string{"SSL_CERT_FILE", "REQUESTS_CA_BUNDLE", "CURL_CA_BUNDLE", "PIP_CERT", "GIT_SSL_CAINFO"} {
+				env[name] = bundle
+			}
+		}
+	}
+	return env
+}
+
+// ToolEnvironment returns the variables Apply sets for the tools portunix
+// runs, with a PAC file resolved
+func ToolEnvironment() (map[string]string, error) {
+	s := Load()
+	if err := s.resolvePAC(); err != nil {
+		return nil, err
+	}
+	return s.Environment(), nil
+}
+
+// ContainerEnvironment returns "NAME=value" entries for the proxy variables
+// not already present in existing, for passing to container runs
+func ContainerEnvironment(existing []string) []string {
+	s := Load()
+	if !s.HasProxy() && s.PAC == "" {
+		return nil
+	}
+	if err := s.resolvePAC(); err != nil {
+		return nil
+	}
+	set := make(map[string]bool)
+	for _, e := range existing {
+		name, _, _ := strings.Cut(e, "=")
+		set[name] = true
+	}
+	var env []string
+	for _, name := range []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy", "NO_PROXY", "no_proxy"} {
+		value := s.Environment()[name]
+		if value != "" && !set[name] {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// Dir returns the directory holding imported CA certificates
+func Dir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-proxy")
+	}
+	return filepath.Join(home, ".portunix", "proxy")
+}
+
+// CombinedBundlePath returns the system CA bundle with the proxy CA
+// appended, written by Apply
+func CombinedBundlePath() string {
+	return filepath.Join(Dir(), "ca-bundle.pem")
+}
+
+// trustCABundle adds the proxy CA to the certificates trusted by Go HTTP
+// clients and refreshes the combined bundle for other tools
+func trustCABundle(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return fmt.Errorf("CA bundle %s contains no PEM certificates", path)
+	}
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		t.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	if err := writeCombinedBundle(path, data); err != nil {
+		return err
+	}
+	// Point tools at the combined bundle now that it exists
+	for name, value := range (&Settings{CABundle: path}).Environment() {
+		os.Setenv(name, value)
+	}
+	return nil
+}
+
+// writeCombinedBundle writes the system bundle followed by the proxy CA,
+// unless the combined bundle is newer than the proxy CA
+func writeCombinedBundle(caPath string, ca []byte) error {
+	combined := CombinedBundlePath()
+	if caInfo, err := os.Stat(caPath); err == nil {
+		if info, err := os.Stat(combined); err == nil && info.ModTime().After(caInfo.ModTime()) {
+			return nil
+		}
+	}
+	system := systemBundle()
+	if system == nil {
+		// Without a PEM system bundle, tools keep their own certificates
+		return nil
+	}
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return err
+	}
+	content := append(system, '\n')
+	content = append(content, ca...)
+	return os.WriteFile(combined, content, 0644)
+}
+
+// systemBundlePaths are the PEM bundles of common Linux distributions
+var systemBundlePaths = []string{
+	"/etc/ssl/certs/ca-certificates.crt",
+	"/etc/pki/tls/certs/ca-bundle.crt",
+	"/etc/ssl/ca-bundle.pem",
+	"/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem",
+	"/etc/ssl/cert.pem",
+	"/usr/local/share/certs/ca-root-nss.crt",
+}
+
+// systemBundle returns the system CA certificates in PEM format
+func systemBundle() []byte {
+	if runtime.GOOS == "darwin" {
+		if out, err := execOutput("security", "find-certificate", "-a", "-p",
+			"/System/Library/Keychains/SystemRootCertificates.keychain"); err == nil && len(out) > 0 {
+			return out
+		}
+	}
+	for _, path := range systemBundlePaths {
+		if data, err := os.ReadFile(path); err == nil && len(data) > 0 {
+			return data
+		}
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,148 @@
+package proxy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"portunix.ai/app/config"
+)
+
+func TestParsePAC(t *testing.T) {
+	script := `function FindProxyForURL(url, host) {
+  if (isPlainHostName(host) || dnsDomainIs(host, ".corp.example.com")) return "DIRECT";
+  return "PROXY proxy.corp.example.com:8080; DIRECT";
+}`
+	if got := ParsePAC(script); got != "http://proxy.corp.example.com:8080" {
+		t.Errorf("unexpected proxy %q", got)
+	}
+	if got := ParsePAC(`return "SOCKS5 10.0.0.1:1080";`); got != "socks5://10.0.0.1:1080" {
+		t.Errorf("unexpected SOCKS proxy %q", got)
+	}
+	if got := ParsePAC(`function FindProxyForURL(url, host) { return "DIRECT"; }`); got != "" {
+		t.Errorf("DIRECT only PAC should resolve to no proxy, got %q", got)
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	s := &Settings{HTTP: "http://proxy:3128", NoProxy: "localhost,.corp"}
+	env := s.Environment()
+	if env["HTTPS_PROXY"] != "http://proxy:3128" || env["https_proxy"] != "http://proxy:3128" || env["no_proxy"] != "localhost,.corp" {
+		t.Errorf("unexpected environment %v", env)
+	}
+	if _, ok := env["NODE_EXTRA_CA_CERTS"]; ok {
+		t.Error("CA variables must not be set without a CA bundle")
+	}
+}
+
+func TestContainerSetupScript(t *testing.T) {
+	script := ContainerSetupScript(&Settings{HTTP: "http://proxy:3128"}, true)
+	for _, want := range []string{
+		`Acquire::http::Proxy \"http://proxy:3128\";`,
+		`Acquire::https::Proxy \"http://proxy:3128\";`,
+		"/etc/apt/apt.conf.d/95portunix-proxy",
+		"proxy=http://proxy:3128",
+		"update-ca-certificates",
+		"update-ca-trust",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script is missing %q:\n%s", want, script)
+		}
+	}
+	if script := ContainerSetupScript(&Settings{CABundle: "/ca.pem"}, true); strings.Contains(script, "Acquire") {
+		t.Error("no package manager proxy without a proxy")
+	}
+}
+
+func TestToolConfig(t *testing.T) {
+	pip := "[global]\ntimeout = 60\nproxy = http://old:1\n\n[install]\nuser = true\n"
+	got := setINIValues(pip, "global", [][2]string{{"proxy", "http://proxy:3128"}, {"cert", ""}})
+	want := "[global]\ntimeout = 60\nproxy = http://proxy:3128\n\n[install]\nuser = true\n"
+	if got != want {
+		t.Errorf("unexpected pip.conf:\n%s", got)
+	}
+	if got := setINIValues("", "global", [][2]string{{"proxy", "http://p:1"}}); got != "[global]\nproxy = http://p:1\n" {
+		t.Errorf("unexpected new pip.conf:\n%q", got)
+	}
+
+	npmrc := "registry=https://registry.npmjs.org/\nproxy=http://old:1\n"
+	got = setKeyValues(npmrc, [][2]string{{"proxy", ""}, {"https-proxy", "http://p:1"}})
+	if got != "registry=https://registry.npmjs.org/\nhttps-proxy = http://p:1\n" {
+		t.Errorf("unexpected .npmrc:\n%q", got)
+	}
+}
+
+func TestImportCA(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Corp Proxy CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, _ := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	// Browsers and Windows export DER
+	caFile := filepath.Join(t.TempDir(), "corp.cer")
+	os.WriteFile(caFile, der, 0644)
+
+	added, err := ImportCA(caFile)
+	if err != nil || len(added) != 1 || !strings.Contains(added[0], "Corp Proxy CA") {
+		t.Fatalf("import failed: %v %v", added, err)
+	}
+	if added, _ := ImportCA(caFile); len(added) != 0 {
+		t.Error("importing the same certificate twice should add nothing")
+	}
+	data, _ := os.ReadFile(ImportedCAPath())
+	if block, _ := pem.Decode(data); block == nil || block.Type != "CERTIFICATE" {
+		t.Errorf("imported CA should be stored as PEM:\n%s", data)
+	}
+	if s := Load(); s.CABundle != ImportedCAPath() {
+		t.Errorf("proxy.ca_bundle should point to the imported CA, got %q", s.CABundle)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.pem")
+	os.WriteFile(bad, []byte("not a certificate"), 0644)
+	if _, err := ImportCA(bad); err == nil {
+		t.Error("files without certificates should be rejected")
+	}
+}
+
+func TestLoadIgnoresProjectScope(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	os.MkdirAll(filepath.Join(home, ".portunix"), 0755)
+	os.WriteFile(filepath.Join(home, ".portunix", "config.yaml"), []byte("proxy:\n  http: http://corp:3128\n"), 0644)
+
+	// A cloned repository sets its own proxy and CA
+	project := t.TempDir()
+	os.WriteFile(filepath.Join(project, "portunix-config.yaml"),
+		[]byte("proxy:\n  http: http://attacker:8080\n  ca_bundle: evil.pem\n"), 0644)
+	t.Chdir(project)
+
+	s := Load()
+	if s.HTTP != "http://corp:3128" || s.CABundle != "" {
+		t.Errorf("project proxy settings must be ignored, got %+v", s)
+	}
+	if keys := IgnoredProjectKeys(); strings.Join(keys, ",") != "proxy.http,proxy.ca_bundle" {
+		t.Errorf("unexpected ignored keys %v", keys)
+	}
+	if err := config.Set(config.ScopeProject, KeyHTTPS, "http://attacker:8080"); err == nil {
+		t.Error("proxy keys must not be stored in the project scope")
+	}
+}
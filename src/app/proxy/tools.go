@@ -0,0 +1,138 @@
+package proxy
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// SyncTools writes the proxy settings to the pip and npm configuration of
+// the current user, so the tools work behind the proxy also when started
+// outside portunix. It returns the files written.
+func SyncTools(s *Settings) ([]string, error) {
+	httpsProxy := s.HTTPS
+	if httpsProxy == "" {
+		httpsProxy = s.HTTP
+	}
+	cert := ""
+	if s.CABundle != "" && fileExists(CombinedBundlePath()) {
+		cert = CombinedBundlePath()
+	}
+
+	var written []string
+	pip := pipConfigPath()
+	if err := updateFile(pip, func(content string) string {
+		return setINIValues(content, "global", [][2]string{{"proxy", httpsProxy}, {"cert", cert}})
+	}); err != nil {
+		return written, err
+	}
+	written = append(written, pip)
+
+	npm := npmrcPath()
+	if err := updateFile(npm, func(content string) string {
+		return setKeyValues(content, [][2]string{
+			{"proxy", s.HTTP}, {"https-proxy", httpsProxy}, {"noproxy", s.NoProxy}, {"cafile", cert},
+		})
+	}); err != nil {
+		return written, err
+	}
+	return append(written, npm), nil
+}
+
+func pipConfigPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "pip", "pip.ini")
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "pip", "pip.conf")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "pip", "pip.conf")
+}
+
+func npmrcPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".npmrc")
+}
+
+func updateFile(path string, update func(string) string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(update(string(data))), 0644)
+}
+
+// setINIValues sets keys of an INI section, creating the section when
+// needed; empty values remove the key. Other content is kept.
+func setINIValues(content, section string, values [][2]string) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+	header := "[" + section + "]"
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == header {
+			start = i
+		} else if start >= 0 && strings.HasPrefix(trimmed, "[") && i > start {
+			end = i
+			break
+		}
+	}
+	if start < 0 {
+		lines = append(lines, header)
+		start, end = len(lines)-1, len(lines)
+	}
+
+	// Keep the blank lines separating the section from the next one
+	sectionEnd := end
+	for sectionEnd > start+1 && strings.TrimSpace(lines[sectionEnd-1]) == "" {
+		sectionEnd--
+	}
+	body := setKeyValues(strings.Join(lines[start+1:sectionEnd], "\n"), values)
+	var out []string
+	out = append(out, lines[:start+1]...)
+	if body = strings.TrimRight(body, "\n"); body != "" {
+		out = append(out, strings.Split(body, "\n")...)
+	}
+	out = append(out, lines[sectionEnd:]...)
+	return strings.Join(out, "\n") + "\n"
+}
+
+// setKeyValues sets "key = value" lines; empty values remove the key
+func setKeyValues(content string, values [][2]string) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if strings.TrimSpace(content) == "" {
+		lines = nil
+	}
+	for _, kv := range values {
+		key, value := kv[0], kv[1]
+		found := false
+		kept := lines[:0:0]
+		for _, line := range lines {
+			name, _, ok := strings.Cut(line, "=")
+			if ok && strings.TrimSpace(name) == key {
+				if value != "" && !found {
+					kept = append(kept, key+" = "+value)
+				}
+				found = true
+				continue
+			}
+			kept = append(kept, line)
+		}
+		if !found && value != "" {
+			kept = append(kept, key+" = "+value)
+		}
+		lines = kept
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
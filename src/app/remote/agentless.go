@@ -0,0 +1,108 @@
+package remote
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// packagePattern restricts agentless package names to characters that are
+// safe to pass to a package manager unquoted
+var packagePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9.+_-]*$`)
+
+// debianNames maps portunix package names whose Debian/Ubuntu package is
+// named differently
+var debianNames = map[string]string{
+	"docker": "docker.io",
+	"python": "python3",
+	"node":   "nodejs",
+	"java":   "default-jdk",
+}
+
+const agentlessPrelude = `set -e
+SUDO=""
+if [ "$(id -u)" -ne 0 ]; then SUDO="sudo"; fi
+`
+
+// agentlessInstall installs packages with the distribution package manager
+const agentlessInstall = `if command -v apt-get >/dev/null 2>&1; then
+	$SUDO apt-get update -q
+	$SUDO env DEBIAN_FRONTEND=noninteractive apt-get install -y %[1]s
+elif command -v dnf >/dev/null 2>&1; then
+	$SUDO dnf install -y %[2]s
+elif command -v yum >/dev/null 2>&1; then
+	$SUDO yum install -y %[2]s
+elif command -v zypper >/dev/null 2>&1; then
+	$SUDO zypper --non-interactive install %[2]s
+elif command -v apk >/dev/null 2>&1; then
+	$SUDO apk add --no-cache %[2]s
+elif command -v pacman >/dev/null 2>&1; then
+	$SUDO pacman -S --noconfirm --needed %[2]s
+else
+	echo "no supported package manager found" >&2
+	exit 1
+fi
+`
+
+const agentlessContainerList = `if command -v docker >/dev/null 2>&1; then
+	$SUDO docker ps -a
+elif command -v podman >/dev/null 2>&1; then
+	podman ps -a
+else
+	echo "neither docker nor podman is installed" >&2
+	exit 1
+fi
+`
+
+const agentlessSystemInfo = `set +e
+echo "Hostname: $(hostname)"
+if [ -r /etc/os-release ]; then . /etc/os-release; echo "OS:       $PRETTY_NAME"; fi
+echo "Kernel:   $(uname -sr)"
+echo "Arch:     $(uname -m)"
+echo "Uptime:   $(uptime)"
+echo
+df -h / 2>/dev/null
+free -h 2>/dev/null
+`
+
+// AgentlessScript translates a portunix command into a shell script for
+// hosts without a usable portunix binary. Only a few commands have an
+// agentless equivalent.
+func AgentlessScript(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("no command given")
+	}
+	switch {
+	case args[0] == "install":
+		if len(args) < 2 {
+			return "", fmt.Errorf("install requires a package name")
+		}
+		var native, debian []string
+		for _, name := range args[1:] {
+			if strings.HasPrefix(name, "-") {
+				return "", fmt.Errorf("install option %s is not supported in agentless mode", name)
+			}
+			if !packagePattern.MatchString(name) {
+				return "", fmt.Errorf("invalid package name %q", name)
+			}
+			native = append(native, name)
+			if deb, ok := debianNames[name]; ok {
+				name = deb
+			}
+			debian = append(debian, name)
+		}
+		return agentlessPrelude + fmt.Sprintf(agentlessInstall, strings.Join(debian, " "), strings.Join(native, " ")), nil
+	case len(args) == 2 && args[0] == "container" && (args[1] == "list" || args[1] == "ls" || args[1] == "ps"):
+		return agentlessPrelude + agentlessContainerList, nil
+	case len(args) == 2 && args[0] == "system" && args[1] == "info":
+		return agentlessSystemInfo, nil
+	}
+	return "", fmt.Errorf("'%s' needs portunix on the remote host; agentless mode supports: %s",
+		strings.Join(args, " "), strings.Join(AgentlessCommands(), ", "))
+}
+
+// AgentlessCommands lists the commands available without portunix on the
+// remote host
+func AgentlessCommands() []string {
+	return []string{"install <package>...", "container list", "system info"}
+}
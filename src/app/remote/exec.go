@@ -0,0 +1,280 @@
+package remote
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"portunix.ai/app/cache"
+	"portunix.ai/app/shellquote"
+)
+
+// remoteDir is where uploaded binaries are cached on the remote host, one
+// subdirectory per binary checksum so different versions never collide
+const remoteDir = "~/.portunix/remote"
+
+// Modes in which a command runs on the remote host
+const (
+	ModeUploaded  = "uploaded"  // portunix uploaded from this machine
+	ModeInstalled = "installed" // portunix already installed remotely
+	ModeAgentless = "agentless" // plain shell script
+)
+
+// Runner runs a shell command line on the remote host
+type Runner interface {
+	Run(command string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error
+}
+
+// SSHRunner runs commands through the system ssh client, so ~/.ssh/config,
+// the SSH agent and known_hosts apply as usual
+type SSHRunner struct {
+	Target *Target
+}
+
+func (r *SSHRunner) Run(command string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+	cmd := exec.Command("ssh", r.Target.SSHArgs(tty, command)...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// ExitCode returns the exit status of a failed remote command, or 0 when
+// err did not come from the command itself
+func ExitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	return 0
+}
+
+// Platform describes the remote host
+type Platform struct {
+	OS   string // GOOS naming, e.g. linux
+	Arch string // GOARCH naming, e.g. amd64
+	// Installed is the path of an installed portunix, empty when none
+	Installed string
+	// Cached lists the binaries present in the upload directory
+	Cached map[string]bool
+}
+
+// probeScript reports the remote platform in key=value lines
+const probeScript = `echo "os=$(uname -s)"; echo "arch=$(uname -m)"
+echo "installed=$(command -v portunix 2>/dev/null)"
+for f in %s/%s/*; do [ -x "$f" ] && echo "cached=${f##*/}"; done; true`
+
+// Probe inspects the remote host; id is the upload directory to check
+func Probe(r Runner, id string) (*Platform, error) {
+	var out, stderr bytes.Buffer
+	if err := r.Run(fmt.Sprintf(probeScript, remoteDir, id), nil, &out, &stderr, false); err != nil {
+		return nil, fmt.Errorf("failed to connect: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	p := &Platform{Cached: map[string]bool{}}
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), "=")
+		switch key {
+		case "os":
+			p.OS = goOS(value)
+		case "arch":
+			p.Arch = goArch(value)
+		case "installed":
+			p.Installed = value
+		case "cached":
+			p.Cached[value] = true
+		}
+	}
+	if p.OS == "" {
+		return nil, fmt.Errorf("remote host did not report its platform; a POSIX shell is required")
+	}
+	return p, nil
+}
+
+func goOS(uname string) string {
+	switch strings.ToLower(uname) {
+	case "linux":
+		return "linux"
+	case "darwin":
+		return "darwin"
+	case "freebsd":
+		return "freebsd"
+	}
+	return strings.ToLower(uname)
+}
+
+func goArch(uname string) string {
+	switch uname {
+	case "x86_64", "amd64":
+		return "amd64"
+	case "aarch64", "arm64":
+		return "arm64"
+	case "armv7l", "armv6l":
+		return "arm"
+	case "i386", "i686":
+		return "386"
+	}
+	return uname
+}
+
+// BinaryDir returns the local directory holding portunix binaries for a
+// platform: the directory of this executable for the local platform, the
+// download cache (<cache>/remote/<os>-<arch>) for others. Empty when no
+// binary is available.
+func BinaryDir(goos, goarch string) string {
+	dir := filepath.Join(cache.DefaultCacheDir(), "remote", goos+"-"+goarch)
+	if goos == runtime.GOOS && goarch == runtime.GOARCH {
+		if self, err := os.Executable(); err == nil {
+			dir = filepath.Dir(self)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "portunix")); err == nil {
+		return dir
+	}
+	return ""
+}
+
+// Executor runs portunix commands on a remote host
+type Executor struct {
+	Target *Target
+	Runner Runner
+	// Helper is the helper binary the command is dispatched to locally,
+	// e.g. ptx-container; it is uploaded next to portunix
+	Helper string
+	// BinaryDir overrides the local directory of the binaries to upload
+	BinaryDir string
+	// Agentless skips portunix binaries and uses shell fallbacks only
+	Agentless bool
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	TTY    bool
+}
+
+// NewExecutor returns an executor for a --host value
+func NewExecutor(spec string) (*Executor, error) {
+	t, err := Resolve(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{
+		Target: t,
+		Runner: &SSHRunner{Target: t},
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}, nil
+}
+
+// Execute runs portunix with args on the remote host and returns the mode
+// that was used
+func (e *Executor) Execute(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("no command given to run on %s", e.Target.Name)
+	}
+
+	if !e.Agentless {
+		// The checksum of the local binary is only known once the platform
+		// matches, so probe without an upload directory first
+		platform, err := Probe(e.Runner, "none")
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", e.Target.Name, err)
+		}
+		dir := e.BinaryDir
+		if dir == "" {
+			dir = BinaryDir(platform.OS, platform.Arch)
+		}
+		if dir != "" {
+			binary, err := e.upload(dir)
+			if err != nil {
+				return "", err
+			}
+			return ModeUploaded, e.run(binary, args)
+		}
+		if platform.Installed != "" {
+			return ModeInstalled, e.run(platform.Installed, args)
+		}
+	}
+
+	script, err := AgentlessScript(args)
+	if err != nil {
+		return ModeAgentless, fmt.Errorf("%s: %w", e.Target.Name, err)
+	}
+	return ModeAgentless, e.Runner.Run("sh -s", strings.NewReader(script), e.Stdout, e.Stderr, false)
+}
+
+func (e *Executor) run(binary string, args []string) error {
+	command := binary + " " + shellquote.Join(args)
+	return e.Runner.Run(command, e.Stdin, e.Stdout, e.Stderr, e.TTY)
+}
+
+// upload copies portunix and the helper from dir to the remote upload
+// directory unless they are already there, and returns the remote path of
+// portunix
+func (e *Executor) upload(dir string) (string, error) {
+	names := []string{"portunix"}
+	if e.Helper != "" {
+		names = append(names, e.Helper)
+	}
+
+	id, err := fileID(filepath.Join(dir, "portunix"))
+	if err != nil {
+		return "", err
+	}
+	platform, err := Probe(e.Runner, id)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", e.Target.Name, err)
+	}
+
+	target := remoteDir + "/" + id
+	for _, name := range names {
+		if platform.Cached[name] {
+			continue
+		}
+		local := filepath.Join(dir, name)
+		file, err := os.Open(local)
+		if err != nil {
+			if name != "portunix" && os.IsNotExist(err) {
+				// The remote portunix reports the missing helper itself
+				continue
+			}
+			return "", err
+		}
+		info, _ := file.Stat()
+		fmt.Fprintf(e.Stderr, "Uploading %s to %s (%.1f MB)...\n", name, e.Target.Name, float64(info.Size())/(1<<20))
+		// Upload under a temporary name so an interrupted transfer is never
+		// mistaken for a cached binary
+		command := fmt.Sprintf("mkdir -p %[1]s && cat > %[1]s/.%[2]s.part && chmod 755 %[1]s/.%[2]s.part && mv %[1]s/.%[2]s.part %[1]s/%[2]s", target, name)
+		var stderr bytes.Buffer
+		err = e.Runner.Run(command, file, io.Discard, &stderr, false)
+		file.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to upload %s: %v %s", name, err, strings.TrimSpace(stderr.String()))
+		}
+	}
+	return target + "/portunix", nil
+}
+
+// fileID returns a short checksum identifying a binary
+func fileID(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
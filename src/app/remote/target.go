@@ -0,0 +1,187 @@
+// Package remote runs portunix commands on other hosts over SSH. It needs
+// nothing installed on the remote side: a matching portunix binary is
+// uploaded and cached there, an installed portunix is used otherwise, and a
+// few commands fall back to plain shell scripts (agentless mode).
+package remote
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"portunix.ai/app/edge"
+	"portunix.ai/app/fleet"
+	"portunix.ai/app/virt"
+)
+
+// Target is a remote host reachable over SSH
+type Target struct {
+	Name string `json:"name"` // as given with --host
+	User string `json:"user,omitempty"`
+	Host string `json:"host"`
+	Port int    `json:"port,omitempty"` // 0 uses the SSH default or ~/.ssh/config
+	// IdentityFile is the private key to authenticate with; empty uses the
+	// SSH agent and the keys configured for ssh
+	IdentityFile string `json:"identity_file,omitempty"`
+	// KnownHosts pins the host key to a separate known_hosts file
+	KnownHosts string `json:"known_hosts,omitempty"`
+	// AcceptNewHostKey records an unknown host key in KnownHosts instead of
+	// refusing the connection, for VMs whose key is created at first boot
+	AcceptNewHostKey bool `json:"accept_new_host_key,omitempty"`
+}
+
+// ParseTarget parses [user@]host[:port]; IPv6 addresses with a port are
+// written as [addr]:port. Host aliases from ~/.ssh/config work as host.
+func ParseTarget(spec string) (*Target, error) {
+	t := &Target{Name: spec}
+	rest := spec
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		t.User, rest = rest[:at], rest[at+1:]
+		if t.User == "" || strings.HasPrefix(t.User, "-") {
+			return nil, fmt.Errorf("invalid user in host %q", spec)
+		}
+	}
+	if host, port, err := net.SplitHostPort(rest); err == nil {
+		p, err := strconv.Atoi(port)
+		if err != nil || p < 1 || p > 65535 {
+			return nil, fmt.Errorf("invalid port in host %q", spec)
+		}
+		t.Host, t.Port = host, p
+	} else {
+		t.Host = strings.Trim(rest, "[]")
+	}
+	if t.Host == "" || strings.ContainsAny(t.Host, " \t/") || strings.HasPrefix(t.Host, "-") {
+		return nil, fmt.Errorf("invalid host %q", spec)
+	}
+	return t, nil
+}
+
+// Resolve returns the target for a --host value. A fleet inventory name
+// connects to the recorded address; the name of an edge deployment
+// connects with its deployment key and recorded host key; the name of a VM
+// created from a cloud image connects with its generated key; anything else
+// is parsed by ParseTarget.
+func Resolve(spec string) (*Target, error) {
+	if !strings.ContainsAny(spec, "@:") {
+		if h, err := fleet.Load(spec); err == nil && h.Address != "" && h.Address != spec {
+			t, err := resolveAddress(h.Address)
+			if err != nil {
+				return nil, fmt.Errorf("fleet host %s: %w", spec, err)
+			}
+			t.Name = spec
+			return t, nil
+		}
+	}
+	return resolveAddress(spec)
+}
+
+func resolveAddress(spec string) (*Target, error) {
+	if !strings.ContainsAny(spec, "@:") && edge.ValidateDeploymentName(spec) == nil {
+		if d, err := edge.LoadDeployment(spec); err == nil {
+			return edgeTarget(d)
+		}
+	}
+	if !strings.ContainsAny(spec, "@:") && virt.ValidateInstanceName(spec) == nil {
+		if inst, err := virt.LoadInstance(spec); err == nil {
+			return vmTarget(inst)
+		}
+	}
+	return ParseTarget(spec)
+}
+
+// vmTarget connects to a VM created by 'portunix virt create --image' at
+// the address last reported by its hypervisor
+func vmTarget(inst *virt.Instance) (*Target, error) {
+	if inst.KeyPath == "" {
+		return nil, fmt.Errorf("VM %s is not reachable over SSH; use 'portunix vm ssh %s'", inst.Name, inst.Name)
+	}
+	if inst.IP == "" {
+		return nil, fmt.Errorf("VM %s has no IP address yet; run 'portunix virt info %s'", inst.Name, inst.Name)
+	}
+	return &Target{
+		Name:             inst.Name,
+		User:             inst.User,
+		Host:             inst.IP,
+		Port:             inst.SSHPort,
+		IdentityFile:     inst.KeyPath,
+		KnownHosts:       inst.KnownHostsPath(),
+		AcceptNewHostKey: true,
+	}, nil
+}
+
+func edgeTarget(d *edge.Deployment) (*Target, error) {
+	if d.PublicIP == "" {
+		return nil, fmt.Errorf("edge deployment %s has no public IP yet", d.Name)
+	}
+	t := &Target{Name: d.Name, User: d.SSHUser, Host: d.PublicIP, IdentityFile: d.SSHKey}
+	if d.HostKey != "" {
+		t.KnownHosts = filepath.Join(edge.DeploymentDir(d.Name), "known_hosts")
+		entry := d.PublicIP + " " + d.HostKey + "\n"
+		if err := os.WriteFile(t.KnownHosts, []byte(entry), 0600); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// ParseFlags extracts the leading --host and --agentless flags from the
+// command line, e.g. "portunix --host user@server install docker". Only
+// flags before the command are taken, so helper flags named --host are
+// left alone.
+func ParseFlags(args []string) (host string, agentless bool, rest []string, err error) {
+	for len(args) > 0 {
+		switch arg := args[0]; {
+		case arg == "--host":
+			if len(args) < 2 || strings.HasPrefix(args[1], "-") {
+				return "", false, nil, fmt.Errorf("--host requires a value")
+			}
+			host, args = args[1], args[2:]
+		case strings.HasPrefix(arg, "--host="):
+			host, args = strings.TrimPrefix(arg, "--host="), args[1:]
+			if host == "" {
+				return "", false, nil, fmt.Errorf("--host requires a value")
+			}
+		case arg == "--agentless":
+			agentless, args = true, args[1:]
+		default:
+			return host, agentless, args, nil
+		}
+	}
+	return host, agentless, args, nil
+}
+
+// Destination returns user@host, or host when no user is set
+func (t *Target) Destination() string {
+	if t.User == "" {
+		return t.Host
+	}
+	return t.User + "@" + t.Host
+}
+
+// SSHArgs returns the ssh arguments that connect to the target and run
+// command; tty allocates a terminal for interactive commands
+func (t *Target) SSHArgs(tty bool, command string) []string {
+	var args []string
+	if t.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(t.Port))
+	}
+	if t.IdentityFile != "" {
+		args = append(args, "-i", t.IdentityFile, "-o", "IdentitiesOnly=yes")
+	}
+	if t.KnownHosts != "" {
+		checking := "yes"
+		if t.AcceptNewHostKey {
+			checking = "accept-new"
+		}
+		args = append(args, "-o", "UserKnownHostsFile="+t.KnownHosts, "-o", "StrictHostKeyChecking="+checking)
+	}
+	if tty {
+		args = append(args, "-t")
+	} else {
+		args = append(args, "-T")
+	}
+	return append(args, t.Destination(), command)
+}
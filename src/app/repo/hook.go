@@ -0,0 +1,168 @@
+package repo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ErrCheckFailed is returned by hooks that reject a commit
+var ErrCheckFailed = errors.New("commit rejected by repository checks")
+
+// RunHook runs a git hook installed by Init in the repository containing
+// the working directory. Findings are written to out.
+func RunHook(hook string, args []string, out io.Writer) error {
+	root, err := gitOutput(".", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return err
+	}
+	cfg, err := LoadConfig(root)
+	if err != nil {
+		return err
+	}
+	switch hook {
+	case "pre-commit":
+		if cfg.Hooks.PreCommit == nil {
+			return nil
+		}
+		return runPreCommit(root, cfg.Hooks.PreCommit, out)
+	case "commit-msg":
+		if cfg.Hooks.CommitMsg == nil || len(args) == 0 {
+			return nil
+		}
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		if err := CheckCommitMessage(cfg.Hooks.CommitMsg, string(data)); err != nil {
+			fmt.Fprintf(out, "✗ %v\n", err)
+			return ErrCheckFailed
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported hook %q (pre-commit, commit-msg)", hook)
+}
+
+func runPreCommit(root string, pc *PreCommit, out io.Writer) error {
+	staged, err := gitOutput(root, "diff", "--cached", "--name-only", "--diff-filter=ACMR")
+	if err != nil {
+		return err
+	}
+	var files []string
+	if staged != "" {
+		files = strings.Split(staged, "\n")
+	}
+
+	failed := false
+	for _, rule := range pc.Lint {
+		matched := MatchFiles(rule.Files, files)
+		if len(rule.Files) > 0 && len(matched) == 0 {
+			continue
+		}
+		if _, err := exec.LookPath(rule.Command[0]); err != nil {
+			fmt.Fprintf(out, "⚠️  %s skipped: %s is not installed\n", rule.Name, rule.Command[0])
+			continue
+		}
+		cmd := exec.Command(rule.Command[0], append(rule.Command[1:], matched...)...)
+		cmd.Dir = root
+		var output bytes.Buffer
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+		err := cmd.Run()
+		if err != nil || (rule.FailOnOutput && strings.TrimSpace(output.String()) != "") {
+			fmt.Fprintf(out, "✗ %s\n", rule.Name)
+			if text := strings.TrimSpace(output.String()); text != "" {
+				fmt.Fprintf(out, "%s\n", indent(text))
+			}
+			failed = true
+		}
+	}
+
+	if pc.SecretScan && len(files) > 0 {
+		if _, err := exec.LookPath("gitleaks"); err != nil {
+			fmt.Fprintln(out, "⚠️  secret scan skipped: gitleaks is not installed (portunix install gitleaks)")
+		} else {
+			cmd := exec.Command("gitleaks", "git", "--pre-commit", "--staged", "--redact", "--no-banner", "--log-level", "warn")
+			cmd.Dir = root
+			var output bytes.Buffer
+			cmd.Stdout = &output
+			cmd.Stderr = &output
+			if err := cmd.Run(); err != nil {
+				fmt.Fprintln(out, "✗ secret scan: staged changes contain secrets")
+				if text := strings.TrimSpace(output.String()); text != "" {
+					fmt.Fprintf(out, "%s\n", indent(text))
+				}
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		fmt.Fprintln(out, "Fix the findings above, or bypass the checks once with 'git commit --no-verify'")
+		return ErrCheckFailed
+	}
+	return nil
+}
+
+// MatchFiles returns the files matching any of the glob patterns. Patterns
+// without a slash match the base name, others the whole path.
+func MatchFiles(patterns, files []string) []string {
+	var matched []string
+	for _, file := range files {
+		for _, pattern := range patterns {
+			target := file
+			if !strings.Contains(pattern, "/") {
+				target = path.Base(file)
+			}
+			if ok, _ := path.Match(pattern, target); ok {
+				matched = append(matched, file)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// CheckCommitMessage checks the subject of a commit message. Comments,
+// merge commits and fixup/squash commits are accepted.
+func CheckCommitMessage(cm *CommitMsg, message string) error {
+	subject := ""
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		subject = line
+		break
+	}
+	if subject == "" {
+		return nil // git aborts empty messages itself
+	}
+	for _, prefix := range []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "} {
+		if strings.HasPrefix(subject, prefix) {
+			return nil
+		}
+	}
+	re, err := regexp.Compile(cm.Pattern)
+	if err != nil {
+		return err
+	}
+	if !re.MatchString(subject) {
+		msg := fmt.Sprintf("commit message %q does not match %s", subject, cm.Pattern)
+		if cm.Hint != "" {
+			msg += "\n  " + cm.Hint
+		}
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func indent(text string) string {
+	return "    " + strings.ReplaceAll(text, "\n", "\n    ")
+}
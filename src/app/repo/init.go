@@ -0,0 +1,215 @@
+package repo
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is the hook configuration written to the repository, relative
+// to its root. It is meant to be committed so that every clone runs the
+// same checks.
+const ConfigFile = ".portunix/repo.yaml"
+
+// hookMarker identifies hooks installed by portunix
+const hookMarker = "portunix-managed hook"
+
+// localSuffix is appended to hooks found at installation; the installed
+// hook runs them first
+const localSuffix = ".local"
+
+// Config is the content of ConfigFile
+type Config struct {
+	Template string `yaml:"template"`
+	Hooks    Hooks  `yaml:"hooks"`
+}
+
+// InitOptions selects what 'portunix repo init' sets up
+type InitOptions struct {
+	NoHooks        bool
+	NoEditorConfig bool
+	// Force overwrites an existing .editorconfig and template files
+	Force bool
+}
+
+// InitResult reports what was set up
+type InitResult struct {
+	Root     string
+	Created  bool     // git init was run
+	Written  []string // files written, relative to Root
+	Skipped  []string // existing files left alone
+	Hooks    []string // installed hooks
+	Chained  []string // existing hooks kept as <hook>.local
+	HooksDir string
+}
+
+// Init bootstraps the repository containing dir, running git init when dir
+// is not in a repository
+func Init(dir string, tmpl *Template, opts InitOptions) (*InitResult, error) {
+	if err := tmpl.Validate(); err != nil {
+		return nil, err
+	}
+	result := &InitResult{}
+	root, err := gitOutput(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+		if _, err := gitOutput(dir, "init"); err != nil {
+			return nil, err
+		}
+		result.Created = true
+		if root, err = gitOutput(dir, "rev-parse", "--show-toplevel"); err != nil {
+			return nil, err
+		}
+	}
+	result.Root = filepath.FromSlash(root)
+
+	files := map[string]string{}
+	for path, content := range tmpl.Files {
+		files[filepath.FromSlash(path)] = content
+	}
+	if !opts.NoEditorConfig && tmpl.EditorConfig != "" {
+		files[".editorconfig"] = tmpl.EditorConfig
+	}
+	for path, content := range files {
+		target := filepath.Join(result.Root, path)
+		if _, err := os.Stat(target); err == nil && !opts.Force {
+			result.Skipped = append(result.Skipped, path)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(target, []byte(content), 0644); err != nil {
+			return nil, err
+		}
+		result.Written = append(result.Written, path)
+	}
+
+	if opts.NoHooks {
+		return result, nil
+	}
+	data, err := yaml.Marshal(Config{Template: tmpl.Name, Hooks: tmpl.Hooks})
+	if err != nil {
+		return nil, err
+	}
+	configPath := filepath.Join(result.Root, filepath.FromSlash(ConfigFile))
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return nil, err
+	}
+	header := "# Git hook configuration of 'portunix repo init' (template " + tmpl.Name + ")\n"
+	if err := os.WriteFile(configPath, append([]byte(header), data...), 0644); err != nil {
+		return nil, err
+	}
+	result.Written = append(result.Written, filepath.FromSlash(ConfigFile))
+
+	hooksDir, err := gitOutput(result.Root, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(result.Root, hooksDir)
+	}
+	result.HooksDir = hooksDir
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return nil, err
+	}
+	var hooks []string
+	if tmpl.Hooks.PreCommit != nil {
+		hooks = append(hooks, "pre-commit")
+	}
+	if tmpl.Hooks.CommitMsg != nil && tmpl.Hooks.CommitMsg.Pattern != "" {
+		hooks = append(hooks, "commit-msg")
+	}
+	for _, hook := range hooks {
+		chained, err := installHook(hooksDir, hook)
+		if err != nil {
+			return nil, err
+		}
+		result.Hooks = append(result.Hooks, hook)
+		if chained {
+			result.Chained = append(result.Chained, hook)
+		}
+	}
+	return result, nil
+}
+
+// installHook writes a hook that delegates to 'portunix repo hook'. A hook
+// not installed by portunix is kept as <hook>.local and run first; it
+// reports whether that happened.
+func installHook(hooksDir, hook string) (bool, error) {
+	path := filepath.Join(hooksDir, hook)
+	chained := false
+	if existing, err := os.ReadFile(path); err == nil && !bytes.Contains(existing, []byte(hookMarker)) {
+		local := path + localSuffix
+		if _, err := os.Stat(local); err == nil {
+			return false, fmt.Errorf("%s and %s both exist; merge them and retry", path, local)
+		}
+		if err := os.Rename(path, local); err != nil {
+			return false, err
+		}
+		chained = true
+	}
+	return chained, os.WriteFile(path, []byte(hookScript(hook)), 0755)
+}
+
+// hookScript returns the shell script of a hook. Git runs hooks with sh on
+// every platform, including Git for Windows.
+func hookScript(hook string) string {
+	fallback := "portunix"
+	if self, err := os.Executable(); err == nil {
+		fallback = filepath.ToSlash(self)
+	}
+	return fmt.Sprintf(`#!/bin/sh
+# %s: installed by 'portunix repo init', configured in %s
+hook_dir=$(dirname "$0")
+if [ -x "$hook_dir/%s%s" ]; then
+    "$hook_dir/%s%s" "$@" || exit $?
+fi
+portunix=$(command -v portunix || echo '%s')
+exec "$portunix" repo hook %s "$@"
+`, hookMarker, ConfigFile, hook, localSuffix, hook, localSuffix, fallback, hook)
+}
+
+// LoadConfig reads the hook configuration of a repository. Repositories
+// without one use the hooks of the built-in template.
+func LoadConfig(root string) (*Config, error) {
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(ConfigFile)))
+	if os.IsNotExist(err) {
+		tmpl, err := builtinTemplate()
+		if err != nil {
+			return nil, err
+		}
+		return &Config{Template: tmpl.Name, Hooks: tmpl.Hooks}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ConfigFile, err)
+	}
+	return &cfg, nil
+}
+
+// gitOutput runs git in dir and returns its trimmed output
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,129 @@
+package repo
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadTemplateOverridesDefault(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	path := filepath.Join(t.TempDir(), "acme.yaml")
+	os.WriteFile(path, []byte(`hooks:
+  commit-msg:
+    pattern: '^[A-Z]+-[0-9]+ .+'
+files:
+  .gitleaks.toml: "[extend]\nuseDefault = true\n"
+`), 0644)
+
+	tmpl, err := LoadTemplate(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Name != "acme" {
+		t.Errorf("name should default to the file name, got %s", tmpl.Name)
+	}
+	if tmpl.EditorConfig == "" || tmpl.Hooks.PreCommit == nil || !tmpl.Hooks.PreCommit.SecretScan {
+		t.Error("fields not set by the template should come from the default")
+	}
+	if tmpl.Hooks.CommitMsg.Pattern != "^[A-Z]+-[0-9]+ .+" {
+		t.Errorf("commit-msg pattern not overridden: %s", tmpl.Hooks.CommitMsg.Pattern)
+	}
+	if _, err := LoadTemplate("missing"); err == nil {
+		t.Error("unknown template should fail")
+	}
+
+	os.WriteFile(path, []byte("files:\n  ../outside: x\n"), 0644)
+	if _, err := LoadTemplate(path); err == nil {
+		t.Error("files outside the repository should be rejected")
+	}
+}
+
+func TestCheckCommitMessage(t *testing.T) {
+	tmpl, err := builtinTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cm := tmpl.Hooks.CommitMsg
+	for _, msg := range []string{
+		"feat(install): add gitleaks package\n\nBody",
+		"# Please enter the commit message\nfix: handle missing PATH\n",
+		"Merge branch 'main' into feature",
+		"fixup! feat: something",
+	} {
+		if err := CheckCommitMessage(cm, msg); err != nil {
+			t.Errorf("%q should be accepted: %v", msg, err)
+		}
+	}
+	for _, msg := range []string{"Added stuff", "feat:missing space", "feature: x"} {
+		if err := CheckCommitMessage(cm, msg); err == nil {
+			t.Errorf("%q should be rejected", msg)
+		}
+	}
+}
+
+func TestMatchFiles(t *testing.T) {
+	files := []string{"main.go", "cmd/root.go", "scripts/build.sh", ".github/workflows/ci.yml", "README.md"}
+	if got := MatchFiles([]string{"*.go"}, files); strings.Join(got, ",") != "main.go,cmd/root.go" {
+		t.Errorf("got %v", got)
+	}
+	if got := MatchFiles([]string{".github/workflows/*.yml"}, files); len(got) != 1 {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestInitInstallsHooks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	tmpl, err := builtinTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A hook that existed before is kept and chained
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v %s", err, out)
+	}
+	hooksDir := filepath.Join(dir, ".git", "hooks")
+	os.MkdirAll(hooksDir, 0755)
+	os.WriteFile(filepath.Join(hooksDir, "pre-commit"), []byte("#!/bin/sh\nexit 0\n"), 0755)
+	os.WriteFile(filepath.Join(dir, ".editorconfig"), []byte("root = true\n"), 0644)
+
+	result, err := Init(dir, tmpl, InitOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Hooks) != 2 || len(result.Chained) != 1 || len(result.Skipped) != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	script, _ := os.ReadFile(filepath.Join(hooksDir, "pre-commit"))
+	if !strings.Contains(string(script), "repo hook pre-commit") {
+		t.Errorf("hook does not delegate to portunix:\n%s", script)
+	}
+	if _, err := os.Stat(filepath.Join(hooksDir, "pre-commit.local")); err != nil {
+		t.Error("existing hook should be kept as pre-commit.local")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, ".editorconfig")); string(data) != "root = true\n" {
+		t.Error("existing .editorconfig should be kept without --force")
+	}
+
+	// Re-running replaces the portunix hooks without chaining them
+	result, err = Init(dir, tmpl, InitOptions{Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Chained) != 0 || len(result.Skipped) != 0 {
+		t.Errorf("unexpected result on re-run %+v", result)
+	}
+	cfg, err := LoadConfig(result.Root)
+	if err != nil || cfg.Template != "default" || cfg.Hooks.CommitMsg == nil {
+		t.Errorf("repository config not written: %+v %v", cfg, err)
+	}
+}
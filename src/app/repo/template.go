@@ -0,0 +1,224 @@
+// Package repo bootstraps git repositories: it installs the standard git
+// hooks (lint, commit message format, secret scanning with gitleaks) and
+// an .editorconfig from a repository template. Organizations provide their
+// own templates in repo-templates/ next to the system or user config.yaml.
+package repo
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"portunix.ai/app/config"
+)
+
+// KeyTemplate is the configuration key naming the template used when
+// 'portunix repo init' is run without --template
+const KeyTemplate = "repo.template"
+
+// DefaultTemplate is the name of the built-in template
+const DefaultTemplate = "default"
+
+// TemplatesDirName is the directory of organization templates next to the
+// system and user config.yaml
+const TemplatesDirName = "repo-templates"
+
+//go:embed templates/default.yaml
+var builtinTemplates embed.FS
+
+// Template describes how a repository is bootstrapped
+type Template struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// EditorConfig is the content of the .editorconfig file; empty skips it
+	EditorConfig string `yaml:"editorconfig,omitempty" json:"editorconfig,omitempty"`
+	Hooks        Hooks  `yaml:"hooks" json:"hooks"`
+	// Files are additional files written to the repository, e.g. a
+	// .gitleaks.toml with organization rules, by relative path
+	Files map[string]string `yaml:"files,omitempty" json:"files,omitempty"`
+}
+
+// Hooks configures the installed git hooks
+type Hooks struct {
+	PreCommit *PreCommit `yaml:"pre-commit,omitempty" json:"pre-commit,omitempty"`
+	CommitMsg *CommitMsg `yaml:"commit-msg,omitempty" json:"commit-msg,omitempty"`
+}
+
+// PreCommit runs lint rules on the staged files and scans the staged
+// changes for secrets
+type PreCommit struct {
+	SecretScan bool       `yaml:"secret_scan" json:"secret_scan"`
+	Lint       []LintRule `yaml:"lint,omitempty" json:"lint,omitempty"`
+}
+
+// LintRule runs a command on the staged files matching its patterns
+type LintRule struct {
+	Name string `yaml:"name" json:"name"`
+	// Files are glob patterns matched against the path and base name of
+	// staged files, which are appended to the command. Without patterns
+	// the command runs once without file arguments.
+	Files   []string `yaml:"files,omitempty" json:"files,omitempty"`
+	Command []string `yaml:"command" json:"command"`
+	// FailOnOutput fails the rule when the command prints anything, for
+	// tools like 'gofmt -l' that report problems with exit code 0
+	FailOnOutput bool `yaml:"fail_on_output,omitempty" json:"fail_on_output,omitempty"`
+}
+
+// CommitMsg checks commit messages against a regular expression
+type CommitMsg struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+	Hint    string `yaml:"hint,omitempty" json:"hint,omitempty"`
+}
+
+// TemplateDirs returns the directories searched for organization templates,
+// system first
+func TemplateDirs() []string {
+	return []string{
+		filepath.Join(filepath.Dir(config.ScopePath(config.ScopeSystem)), TemplatesDirName),
+		filepath.Join(filepath.Dir(config.ScopePath(config.ScopeUser)), TemplatesDirName),
+	}
+}
+
+// LoadTemplate loads a template by name, file path or http(s) URL. The
+// built-in default is loaded first and the named template overrides the
+// fields it sets. An empty name uses the repo.template setting.
+func LoadTemplate(ref string) (*Template, error) {
+	if ref == "" {
+		ref = config.GetString(KeyTemplate, DefaultTemplate)
+	}
+	tmpl, err := builtinTemplate()
+	if err != nil {
+		return nil, err
+	}
+	if ref == DefaultTemplate {
+		return tmpl, nil
+	}
+
+	data, source, err := readTemplate(ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, tmpl); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", source, err)
+	}
+	if tmpl.Name == DefaultTemplate {
+		tmpl.Name = strings.TrimSuffix(filepath.Base(ref), filepath.Ext(ref))
+	}
+	return tmpl, tmpl.Validate()
+}
+
+func builtinTemplate() (*Template, error) {
+	data, err := builtinTemplates.ReadFile("templates/default.yaml")
+	if err != nil {
+		return nil, err
+	}
+	var tmpl Template
+	if err := yaml.Unmarshal(data, &tmpl); err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// readTemplate returns the content of a template reference and where it
+// was found
+func readTemplate(ref string) ([]byte, string, error) {
+	if strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "http://") {
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Get(ref)
+		if err != nil {
+			return nil, ref, fmt.Errorf("failed to download template: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, ref, fmt.Errorf("failed to download template %s: HTTP %d", ref, resp.StatusCode)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		return data, ref, err
+	}
+	if strings.ContainsAny(ref, `/\`) || strings.HasSuffix(ref, ".yaml") || strings.HasSuffix(ref, ".yml") {
+		data, err := os.ReadFile(ref)
+		return data, ref, err
+	}
+
+	// Named template; the user directory wins over the system one
+	dirs := TemplateDirs()
+	for i := len(dirs) - 1; i >= 0; i-- {
+		for _, ext := range []string{".yaml", ".yml"} {
+			path := filepath.Join(dirs[i], ref+ext)
+			if data, err := os.ReadFile(path); err == nil {
+				return data, path, nil
+			}
+		}
+	}
+	return nil, ref, fmt.Errorf("repository template %q not found in %s", ref, strings.Join(dirs, " or "))
+}
+
+// Validate checks the hook configuration of a template
+func (t *Template) Validate() error {
+	if pc := t.Hooks.PreCommit; pc != nil {
+		for _, rule := range pc.Lint {
+			if rule.Name == "" || len(rule.Command) == 0 {
+				return fmt.Errorf("template %s: lint rules need a name and a command", t.Name)
+			}
+		}
+	}
+	if cm := t.Hooks.CommitMsg; cm != nil && cm.Pattern != "" {
+		if _, err := regexp.Compile(cm.Pattern); err != nil {
+			return fmt.Errorf("template %s: invalid commit-msg pattern: %w", t.Name, err)
+		}
+	}
+	for path := range t.Files {
+		if filepath.IsAbs(path) || strings.HasPrefix(filepath.Clean(path), "..") {
+			return fmt.Errorf("template %s: file %s must be relative to the repository", t.Name, path)
+		}
+	}
+	return nil
+}
+
+// TemplateInfo describes an available template
+type TemplateInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Path        string `json:"path,omitempty"`
+}
+
+// ListTemplates returns the built-in and organization templates
+func ListTemplates() []TemplateInfo {
+	found := map[string]TemplateInfo{}
+	if tmpl, err := builtinTemplate(); err == nil {
+		found[DefaultTemplate] = TemplateInfo{Name: DefaultTemplate, Description: tmpl.Description}
+	}
+	for _, dir := range TemplateDirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			info := TemplateInfo{Name: strings.TrimSuffix(entry.Name(), ext), Path: path}
+			var tmpl Template
+			if data, err := os.ReadFile(path); err == nil && yaml.Unmarshal(data, &tmpl) == nil {
+				info.Description = tmpl.Description
+			}
+			found[info.Name] = info
+		}
+	}
+	list := make([]TemplateInfo, 0, len(found))
+	for _, info := range found {
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
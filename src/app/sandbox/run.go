@@ -0,0 +1,274 @@
+package sandbox
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"portunix.ai/app/cache"
+)
+
+// Folders inside the sandbox used by the install test
+const (
+	runPortunixDir = `C:\Portunix`
+	runCacheDir    = `C:\PortunixCache`
+	runWorkDir     = `C:\PortunixRun`
+)
+
+// runArgPattern restricts package names and install arguments to
+// characters that need no quoting in a batch file
+var runArgPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._:/=+@-]*$|^--[a-zA-Z0-9-]+(=[a-zA-Z0-9._:/+@-]+)?$`)
+
+// RunOptions configures an install test in Windows Sandbox
+type RunOptions struct {
+	Package string
+	// Args are extra install arguments, e.g. --variant 17
+	Args []string
+	// Verify is a command run after the install; its exit code decides the
+	// test together with the install exit code
+	Verify     string
+	Networking bool
+	// PortunixDir is the host directory with portunix.exe and its helpers,
+	// mapped read-only; the directory of this executable when empty
+	PortunixDir string
+	// CacheDir is the host download cache shared with the sandbox
+	CacheDir string
+	// WorkDir receives the generated files and the test results
+	WorkDir string
+}
+
+// RunPlan is a prepared install test
+type RunPlan struct {
+	Options RunOptions
+	WsbPath string
+	Script  string
+}
+
+// RunResult is the outcome of an install test
+type RunResult struct {
+	Package     string        `json:"package"`
+	Success     bool          `json:"success"`
+	InstallExit int           `json:"install_exit_code"`
+	VerifyExit  *int          `json:"verify_exit_code,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	InstallLog  string        `json:"install_log"`
+	VerifyLog   string        `json:"verify_log,omitempty"`
+	WorkDir     string        `json:"work_dir"`
+}
+
+var runWsbTemplate = template.Must(template.New("wsb").Funcs(template.FuncMap{"xml": xmlEscape}).Parse(`<Configuration>
+  <Networking>{{if .Networking}}Enable{{else}}Disable{{end}}</Networking>
+  <ClipboardRedirection>Enable</ClipboardRedirection>
+  <MappedFolders>
+{{- range .Folders}}
+    <MappedFolder>
+      <HostFolder>{{xml .HostPath}}</HostFolder>
+      <SandboxFolder>{{xml .SandboxPath}}</SandboxFolder>
+      <ReadOnly>{{.ReadOnly}}</ReadOnly>
+    </MappedFolder>
+{{- end}}
+  </MappedFolders>
+  <LogonCommand>
+    <Command>{{xml .Command}}</Command>
+  </LogonCommand>
+</Configuration>
+`))
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// PrepareRun validates the options and writes the .wsb configuration and
+// the test script into the work directory
+func PrepareRun(opts RunOptions) (*RunPlan, error) {
+	for _, arg := range append([]string{opts.Package}, opts.Args...) {
+		if !runArgPattern.MatchString(arg) {
+			return nil, fmt.Errorf("unsupported package name or argument %q", arg)
+		}
+	}
+	if strings.ContainsAny(opts.Verify, "\r\n") {
+		return nil, fmt.Errorf("verify command must be a single line")
+	}
+
+	if opts.PortunixDir == "" {
+		self, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate portunix: %w", err)
+		}
+		opts.PortunixDir = filepath.Dir(self)
+	}
+	if opts.CacheDir == "" {
+		opts.CacheDir = cache.LoadConfig().BaseDir
+	}
+	if opts.WorkDir == "" {
+		opts.WorkDir = filepath.Join(".tmp", "sandbox_run_"+time.Now().Format("20060102_150405"))
+	}
+	for _, dir := range []*string{&opts.PortunixDir, &opts.CacheDir, &opts.WorkDir} {
+		abs, err := filepath.Abs(*dir)
+		if err != nil {
+			return nil, err
+		}
+		*dir = abs
+	}
+	if err := os.MkdirAll(opts.CacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.MkdirAll(opts.WorkDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+
+	plan := &RunPlan{Options: opts, Script: runScript(opts)}
+	if err := os.WriteFile(filepath.Join(opts.WorkDir, "run.cmd"), []byte(plan.Script), 0644); err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		Networking bool
+		Folders    []MappedFolder
+		Command    string
+	}{
+		Networking: opts.Networking,
+		Folders: []MappedFolder{
+			{HostPath: opts.PortunixDir, SandboxPath: runPortunixDir, ReadOnly: true},
+			{HostPath: opts.CacheDir, SandboxPath: runCacheDir},
+			{HostPath: opts.WorkDir, SandboxPath: runWorkDir},
+		},
+		Command: `cmd.exe /c ` + runWorkDir + `\run.cmd`,
+	}
+	var wsb strings.Builder
+	if err := runWsbTemplate.Execute(&wsb, data); err != nil {
+		return nil, err
+	}
+	plan.WsbPath = filepath.Join(opts.WorkDir, "install-test.wsb")
+	if err := os.WriteFile(plan.WsbPath, []byte(wsb.String()), 0644); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// runScript returns the batch file run at sandbox logon. Results are
+// written to the mapped work directory; done.txt comes last and tells the
+// host that the test finished.
+func runScript(opts RunOptions) string {
+	install := strings.Join(append([]string{runPortunixDir + `\portunix.exe`, "install", opts.Package}, opts.Args...), " ")
+	lines := []string{
+		"@echo off",
+		"title Portunix install test: " + opts.Package,
+		"set PORTUNIX_CACHE_DIR=" + runCacheDir,
+		"set R=" + runWorkDir,
+		"echo %DATE% %TIME%> %R%\\started.txt",
+		"echo Installing " + opts.Package + "...",
+		install + " > %R%\\install.log 2>&1",
+		"echo %ERRORLEVEL%> %R%\\install.exit",
+	}
+	if opts.Verify != "" {
+		lines = append(lines,
+			"rem Pick up PATH changes made by the installer",
+			`for /f "usebackq tokens=*" %%p in (`+"`"+`powershell -NoProfile -Command "[Environment]::GetEnvironmentVariable('PATH','Machine') + ';' + [Environment]::GetEnvironmentVariable('PATH','User')"`+"`"+`) do set "PATH=%%p"`,
+			"echo Verifying: "+strings.NewReplacer("%", "%%", "&", "^&", "|", "^|", "<", "^<", ">", "^>").Replace(opts.Verify),
+			"cmd /c \""+opts.Verify+"\" > %R%\\verify.log 2>&1",
+			"echo %ERRORLEVEL%> %R%\\verify.exit",
+		)
+	}
+	lines = append(lines, "echo done> %R%\\done.txt", "")
+	return strings.Join(lines, "\r\n")
+}
+
+// ReadRunResult reads the results of a finished test from the work
+// directory
+func ReadRunResult(opts RunOptions) (*RunResult, error) {
+	dir := opts.WorkDir
+	if _, err := os.Stat(filepath.Join(dir, "done.txt")); err != nil {
+		return nil, fmt.Errorf("install test did not finish")
+	}
+	result := &RunResult{Package: opts.Package, WorkDir: dir}
+	code, err := readExitCode(filepath.Join(dir, "install.exit"))
+	if err != nil {
+		return nil, err
+	}
+	result.InstallExit = code
+	log, _ := os.ReadFile(filepath.Join(dir, "install.log"))
+	result.InstallLog = string(log)
+	result.Success = code == 0
+
+	if opts.Verify != "" {
+		code, err := readExitCode(filepath.Join(dir, "verify.exit"))
+		if err != nil {
+			return nil, err
+		}
+		result.VerifyExit = &code
+		log, _ := os.ReadFile(filepath.Join(dir, "verify.log"))
+		result.VerifyLog = string(log)
+		result.Success = result.Success && code == 0
+	}
+
+	if started, err := os.Stat(filepath.Join(dir, "started.txt")); err == nil {
+		if done, err := os.Stat(filepath.Join(dir, "done.txt")); err == nil {
+			result.Duration = done.ModTime().Sub(started.ModTime()).Round(time.Second)
+		}
+	}
+	return result, nil
+}
+
+func readExitCode(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("missing test result %s: %w", filepath.Base(path), err)
+	}
+	code, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid exit code in %s: %q", filepath.Base(path), data)
+	}
+	return code, nil
+}
+
+// Run launches Windows Sandbox with the plan and waits until the test
+// finishes or the timeout expires. The sandbox is closed afterwards unless
+// keepOpen is set.
+func (p *RunPlan) Run(timeout time.Duration, keepOpen bool) (*RunResult, error) {
+	if runtime.GOOS != "windows" {
+		return nil, fmt.Errorf("Windows Sandbox is only available on Windows")
+	}
+	if _, err := exec.LookPath("WindowsSandbox.exe"); err != nil {
+		return nil, fmt.Errorf("Windows Sandbox is not enabled; enable the optional feature 'Containers-DisposableClientVM' and reboot")
+	}
+
+	cmd := exec.Command("WindowsSandbox.exe", p.WsbPath)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start Windows Sandbox: %w", err)
+	}
+	go cmd.Wait()
+
+	done := filepath.Join(p.Options.WorkDir, "done.txt")
+	deadline := time.Now().Add(timeout)
+	for {
+		if _, err := os.Stat(done); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			if !keepOpen {
+				CloseSandbox()
+			}
+			return nil, fmt.Errorf("install test did not finish within %s; logs are in %s", timeout, p.Options.WorkDir)
+		}
+		time.Sleep(2 * time.Second)
+	}
+
+	result, err := ReadRunResult(p.Options)
+	if !keepOpen {
+		if closeErr := CloseSandbox(); closeErr != nil && err == nil {
+			fmt.Printf("Warning: %v\n", closeErr)
+		}
+	}
+	return result, err
+}
@@ -0,0 +1,180 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Component is a package listed in an SBOM
+type Component struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Type    string `json:"type,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+}
+
+// key identifies a component across versions: the package URL without
+// version and qualifiers, or type and name
+func (c Component) key() string {
+	if c.PURL != "" {
+		purl := c.PURL
+		if i := strings.IndexAny(purl, "?#"); i >= 0 {
+			purl = purl[:i]
+		}
+		if i := strings.LastIndex(purl, "@"); i > strings.LastIndex(purl, "/") {
+			purl = purl[:i]
+		}
+		return purl
+	}
+	return c.Type + "/" + c.Name
+}
+
+// document holds the fields read from CycloneDX and SPDX JSON documents
+type document struct {
+	// CycloneDX
+	BOMFormat  string `json:"bomFormat"`
+	Components []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Type    string `json:"type"`
+		PURL    string `json:"purl"`
+	} `json:"components"`
+	// SPDX
+	SPDXVersion string `json:"spdxVersion"`
+	Packages    []struct {
+		Name         string `json:"name"`
+		VersionInfo  string `json:"versionInfo"`
+		SPDXID       string `json:"SPDXID"`
+		ExternalRefs []struct {
+			ReferenceType    string `json:"referenceType"`
+			ReferenceLocator string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+}
+
+// ReadComponents reads the components of a CycloneDX or SPDX JSON SBOM
+func ReadComponents(path string) ([]Component, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid SBOM %s: %w", path, err)
+	}
+
+	var components []Component
+	switch {
+	case doc.BOMFormat == "CycloneDX":
+		for _, c := range doc.Components {
+			components = append(components, Component{Name: c.Name, Version: c.Version, Type: c.Type, PURL: c.PURL})
+		}
+	case doc.SPDXVersion != "":
+		for _, p := range doc.Packages {
+			c := Component{Name: p.Name, Version: p.VersionInfo}
+			for _, ref := range p.ExternalRefs {
+				if ref.ReferenceType == "purl" {
+					c.PURL = ref.ReferenceLocator
+				}
+			}
+			// The described source itself is not a dependency
+			if c.PURL == "" && strings.HasPrefix(p.SPDXID, "SPDXRef-DocumentRoot") {
+				continue
+			}
+			components = append(components, c)
+		}
+	default:
+		return nil, fmt.Errorf("%s is neither a CycloneDX nor an SPDX JSON document", path)
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i].key() < components[j].key() })
+	return components, nil
+}
+
+// Change is a component whose version changed
+type Change struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Diff lists the differences between two SBOMs
+type Diff struct {
+	Added   []Component `json:"added"`
+	Removed []Component `json:"removed"`
+	Changed []Change    `json:"changed"`
+}
+
+// Empty reports whether the SBOMs list the same components
+func (d *Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Compare returns the components added, removed and changed between two
+// SBOMs. Components present in several versions are compared by their
+// sorted version lists.
+func Compare(before, after []Component) *Diff {
+	versions := func(components []Component) (map[string][]string, map[string]Component) {
+		byKey := make(map[string][]string)
+		first := make(map[string]Component)
+		for _, c := range components {
+			k := c.key()
+			byKey[k] = append(byKey[k], c.Version)
+			if _, ok := first[k]; !ok {
+				first[k] = c
+			}
+		}
+		for k := range byKey {
+			sort.Strings(byKey[k])
+		}
+		return byKey, first
+	}
+	oldVersions, oldComponents := versions(before)
+	newVersions, newComponents := versions(after)
+
+	diff := &Diff{}
+	for k, vs := range newVersions {
+		previous, ok := oldVersions[k]
+		switch {
+		case !ok:
+			for _, v := range vs {
+				c := newComponents[k]
+				c.Version = v
+				diff.Added = append(diff.Added, c)
+			}
+		case strings.Join(previous, ",") != strings.Join(vs, ","):
+			c := newComponents[k]
+			diff.Changed = append(diff.Changed, Change{
+				Name: c.Name, From: strings.Join(previous, ", "), To: strings.Join(vs, ", "),
+			})
+		}
+	}
+	for k, vs := range oldVersions {
+		if _, ok := newVersions[k]; !ok {
+			for _, v := range vs {
+				c := oldComponents[k]
+				c.Version = v
+				diff.Removed = append(diff.Removed, c)
+			}
+		}
+	}
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Name < diff.Added[j].Name })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Name < diff.Removed[j].Name })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Name < diff.Changed[j].Name })
+	return diff
+}
+
+// CompareFiles compares two stored SBOMs, which may use different formats
+func CompareFiles(oldPath, newPath string) (*Diff, error) {
+	before, err := ReadComponents(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	after, err := ReadComponents(newPath)
+	if err != nil {
+		return nil, err
+	}
+	return Compare(before, after), nil
+}
@@ -0,0 +1,244 @@
+// Package sbom generates software bills of materials with syft for
+// containers, images and the local system, keeps them in the project
+// directory and compares them with the previous SBOM of the same target.
+package sbom
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"portunix.ai/app/container"
+)
+
+// Formats
+const (
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+)
+
+// Target kinds
+const (
+	KindContainer = "container"
+	KindImage     = "image"
+	KindSystem    = "system"
+	KindDir       = "dir"
+)
+
+// DefaultDir is the directory, relative to the project, holding the SBOMs
+const DefaultDir = ".portunix/sbom"
+
+// timeLayout names SBOM files by creation time
+const timeLayout = "20060102-150405"
+
+// Target is what an SBOM describes
+type Target struct {
+	Kind string `json:"kind"`
+	Ref  string `json:"ref,omitempty"`
+}
+
+// ParseTarget parses container:<name>, image:<ref>, dir:<path> or system
+func ParseTarget(value string) (Target, error) {
+	if value == KindSystem {
+		return Target{Kind: KindSystem}, nil
+	}
+	kind, ref, ok := strings.Cut(value, ":")
+	if !ok || ref == "" {
+		return Target{}, fmt.Errorf("invalid target %q (use container:<name>, image:<ref>, dir:<path> or system)", value)
+	}
+	switch kind {
+	case KindContainer, KindImage, KindDir:
+		return Target{Kind: kind, Ref: ref}, nil
+	}
+	return Target{}, fmt.Errorf("unknown target kind %q (use container, image, dir or system)", kind)
+}
+
+func (t Target) String() string {
+	if t.Ref == "" {
+		return t.Kind
+	}
+	return t.Kind + ":" + t.Ref
+}
+
+var slugInvalid = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Slug returns the directory name of the target's SBOMs
+func (t Target) Slug() string {
+	if t.Ref == "" {
+		return t.Kind
+	}
+	ref := t.Ref
+	if t.Kind == KindDir {
+		if abs, err := filepath.Abs(ref); err == nil {
+			ref = abs
+		}
+	}
+	return t.Kind + "-" + strings.Trim(slugInvalid.ReplaceAllString(ref, "_"), "_")
+}
+
+// Record is a stored SBOM
+type Record struct {
+	Path    string    `json:"path"`
+	Target  string    `json:"target"`
+	Format  string    `json:"format"`
+	Created time.Time `json:"created"`
+}
+
+// syftOutput maps formats to syft output names
+var syftOutput = map[string]string{
+	FormatCycloneDX: "cyclonedx-json",
+	FormatSPDX:      "spdx-json",
+}
+
+// systemExcludes are the pseudo and volatile filesystems skipped when
+// scanning the system
+var systemExcludes = []string{"./proc", "./sys", "./dev", "./run", "./tmp", "./var/tmp", "./var/lib/docker", "./var/lib/containers", "./home/*/.cache"}
+
+// Generate runs syft for the target and stores the SBOM in dir/<slug>
+func Generate(target Target, format, dir string) (*Record, error) {
+	output, ok := syftOutput[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported format %q (use %s or %s)", format, FormatCycloneDX, FormatSPDX)
+	}
+	source, err := syftSource(target)
+	if err != nil {
+		return nil, err
+	}
+
+	created := time.Now()
+	targetDir := filepath.Join(dir, target.Slug())
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(targetDir, created.Format(timeLayout)+"."+format+".json")
+	partial := path + ".partial"
+
+	args := []string{"scan", source, "-o", output + "=" + partial, "-q"}
+	if target.Kind == KindSystem {
+		for _, exclude := range systemExcludes {
+			args = append(args, "--exclude", exclude)
+		}
+	}
+	cmd := exec.Command("syft", args...)
+	cmd.Env = append(os.Environ(), "SYFT_CHECK_FOR_APP_UPDATE=false")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		os.Remove(partial)
+		return nil, fmt.Errorf("syft failed for %s: %v: %s", target, err, strings.TrimSpace(stderr.String()))
+	}
+	if err := os.Rename(partial, path); err != nil {
+		return nil, err
+	}
+	return &Record{Path: path, Target: target.String(), Format: format, Created: created}, nil
+}
+
+// syftSource returns the syft source of a target. Containers are scanned
+// through their image in the selected runtime.
+func syftSource(target Target) (string, error) {
+	switch target.Kind {
+	case KindSystem:
+		if runtime.GOOS == "windows" {
+			return "", fmt.Errorf("system SBOMs are supported on Linux and macOS; use dir:<path> on Windows")
+		}
+		return "dir:/", nil
+	case KindDir:
+		if _, err := os.Stat(target.Ref); err != nil {
+			return "", err
+		}
+		return "dir:" + target.Ref, nil
+	}
+
+	rt, err := container.GetSelectedRuntime()
+	if err != nil {
+		return "", err
+	}
+	image := target.Ref
+	if target.Kind == KindContainer {
+		out, err := exec.Command(rt, "inspect", "--type", "container", "--format", "{{.Image}}", target.Ref).Output()
+		if err != nil {
+			return "", fmt.Errorf("container %s not found in %s", target.Ref, rt)
+		}
+		image = strings.TrimSpace(string(out))
+	}
+	return rt + ":" + image, nil
+}
+
+// List returns the stored SBOMs of a target, oldest first
+func List(dir string, target Target) ([]Record, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, target.Slug()))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	for _, entry := range entries {
+		record, ok := parseRecordName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		record.Path = filepath.Join(dir, target.Slug(), entry.Name())
+		record.Target = target.String()
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Created.Before(records[j].Created) })
+	return records, nil
+}
+
+// Targets returns the slugs of targets with stored SBOMs
+func Targets(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var slugs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			slugs = append(slugs, entry.Name())
+		}
+	}
+	return slugs, nil
+}
+
+// parseRecordName parses <YYYYMMDD-HHMMSS>.<format>.json
+func parseRecordName(name string) (Record, bool) {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 || parts[2] != "json" {
+		return Record{}, false
+	}
+	if _, ok := syftOutput[parts[1]]; !ok {
+		return Record{}, false
+	}
+	created, err := time.ParseInLocation(timeLayout, parts[0], time.Local)
+	if err != nil {
+		return Record{}, false
+	}
+	return Record{Format: parts[1], Created: created}, true
+}
+
+// Previous returns the newest stored SBOM of the target created before
+// record, or nil
+func Previous(dir string, target Target, record *Record) (*Record, error) {
+	records, err := List(dir, target)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].Path != record.Path && records[i].Created.Before(record.Created) {
+			return &records[i], nil
+		}
+	}
+	return nil, nil
+}
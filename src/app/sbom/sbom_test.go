@@ -0,0 +1,91 @@
+package sbom
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const cycloneDX = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.6",
+  "components": [
+    {"type": "library", "name": "openssl", "version": "3.0.2", "purl": "pkg:deb/ubuntu/openssl@3.0.2?arch=amd64"},
+    {"type": "library", "name": "zlib", "version": "1.2.11", "purl": "pkg:deb/ubuntu/zlib@1.2.11"},
+    {"type": "library", "name": "curl", "version": "7.81.0", "purl": "pkg:deb/ubuntu/curl@7.81.0"}
+  ]
+}`
+
+const spdx = `{
+  "spdxVersion": "SPDX-2.3",
+  "packages": [
+    {"name": "web", "SPDXID": "SPDXRef-DocumentRoot-Image-web"},
+    {"name": "openssl", "versionInfo": "3.0.13", "SPDXID": "SPDXRef-Package-1",
+     "externalRefs": [{"referenceType": "purl", "referenceLocator": "pkg:deb/ubuntu/openssl@3.0.13?arch=amd64"}]},
+    {"name": "zlib", "versionInfo": "1.2.11", "SPDXID": "SPDXRef-Package-2",
+     "externalRefs": [{"referenceType": "purl", "referenceLocator": "pkg:deb/ubuntu/zlib@1.2.11"}]},
+    {"name": "express", "versionInfo": "4.19.2", "SPDXID": "SPDXRef-Package-3",
+     "externalRefs": [{"referenceType": "purl", "referenceLocator": "pkg:npm/express@4.19.2"}]}
+  ]
+}`
+
+func TestCompareAcrossFormats(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.json")
+	newPath := filepath.Join(dir, "new.json")
+	os.WriteFile(oldPath, []byte(cycloneDX), 0644)
+	os.WriteFile(newPath, []byte(spdx), 0644)
+
+	components, err := ReadComponents(newPath)
+	if err != nil || len(components) != 3 {
+		t.Fatalf("expected 3 SPDX components without the document root, got %v %v", components, err)
+	}
+
+	diff, err := CompareFiles(oldPath, newPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].Name != "express" {
+		t.Errorf("added: %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Name != "curl" {
+		t.Errorf("removed: %+v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].From != "3.0.2" || diff.Changed[0].To != "3.0.13" {
+		t.Errorf("changed: %+v", diff.Changed)
+	}
+	if same := Compare(components, components); !same.Empty() {
+		t.Errorf("identical SBOMs should not differ: %+v", same)
+	}
+}
+
+func TestTargetsAndRecords(t *testing.T) {
+	for _, value := range []string{"container", "vm:web", "container:"} {
+		if _, err := ParseTarget(value); err == nil {
+			t.Errorf("target %q should be rejected", value)
+		}
+	}
+	target, err := ParseTarget("image:ghcr.io/acme/web:1.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slug := target.Slug(); slug != "image-ghcr.io_acme_web_1.2" {
+		t.Errorf("unexpected slug %s", slug)
+	}
+
+	dir := t.TempDir()
+	targetDir := filepath.Join(dir, target.Slug())
+	os.MkdirAll(targetDir, 0755)
+	for _, name := range []string{"20260101-100000.cyclonedx.json", "20260102-100000.spdx.json", "20260103-100000.cyclonedx.json.partial", "notes.txt"} {
+		os.WriteFile(filepath.Join(targetDir, name), []byte("{}"), 0644)
+	}
+	records, err := List(dir, target)
+	if err != nil || len(records) != 2 || records[1].Format != FormatSPDX {
+		t.Fatalf("unexpected records %+v %v", records, err)
+	}
+	previous, err := Previous(dir, target, &Record{Path: "new", Created: time.Now()})
+	if err != nil || previous == nil || previous.Path != records[1].Path {
+		t.Errorf("previous should be the newest stored SBOM: %+v %v", previous, err)
+	}
+}
@@ -0,0 +1,306 @@
+// Package schedule runs portunix commands periodically with the scheduler
+// of the OS: the user's crontab on Linux and macOS, the Task Scheduler on
+// Windows. Schedules are kept in ~/.portunix/schedule/schedules.json; the
+// scheduler entry of each runs 'portunix schedule run <name>', which
+// appends the output of the command to a run log and records its exit
+// status.
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxLogSize is the size at which a run log is rotated to <name>.log.1
+const maxLogSize = 1 << 20
+
+// Schedule is a portunix command run periodically
+type Schedule struct {
+	Name string `json:"name"`
+	// Command holds the portunix arguments, e.g. ["pft", "sync"]
+	Command []string `json:"command"`
+	Cron    string   `json:"cron"`
+	// WorkDir is the working directory of the command
+	WorkDir string    `json:"workdir,omitempty"`
+	Created time.Time `json:"created"`
+	LastRun *Run      `json:"last_run,omitempty"`
+}
+
+// Run is the record of one run of a schedule
+type Run struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	ExitCode int       `json:"exit_code"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// OK reports whether the command succeeded
+func (r *Run) OK() bool {
+	return r.ExitCode == 0 && r.Error == ""
+}
+
+// Next returns the next time the schedule runs after t
+func (s *Schedule) Next(t time.Time) time.Time {
+	c, err := ParseCron(s.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	return c.Next(t)
+}
+
+// CommandLine returns the command as typed
+func (s *Schedule) CommandLine() string {
+	return "portunix " + strings.Join(s.Command, " ")
+}
+
+// Dir returns the directory holding schedules and run logs
+func Dir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".portunix", "schedule")
+	}
+	return filepath.Join(home, ".portunix", "schedule")
+}
+
+func storePath() string {
+	return filepath.Join(Dir(), "schedules.json")
+}
+
+// LogPath returns the run log of a schedule
+func LogPath(name string) string {
+	return filepath.Join(Dir(), "logs", name+".log")
+}
+
+// Load returns the schedules
+func Load() ([]*Schedule, error) {
+	data, err := os.ReadFile(storePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var schedules []*Schedule
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return nil, fmt.Errorf("%s: %w", storePath(), err)
+	}
+	return schedules, nil
+}
+
+func save(schedules []*Schedule) error {
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(schedules, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := storePath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, storePath())
+}
+
+// Get returns the schedule name
+func Get(name string) (*Schedule, error) {
+	schedules, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range schedules {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("schedule %q not found", name)
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// DefaultName derives a schedule name from the command, e.g. "pft-sync"
+func DefaultName(command []string) string {
+	var words []string
+	for _, arg := range command {
+		if strings.HasPrefix(arg, "-") || len(words) == 2 {
+			break
+		}
+		words = append(words, arg)
+	}
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, strings.Join(words, "-"))
+	return strings.Trim(name, "-_")
+}
+
+// Add validates a schedule, registers it with the scheduler and stores it
+func Add(s *Schedule, scheduler Scheduler, executable string) error {
+	if !validName.MatchString(s.Name) {
+		return fmt.Errorf("invalid schedule name %q (lowercase letters, digits, - and _)", s.Name)
+	}
+	if len(s.Command) == 0 {
+		return errors.New("no command to schedule")
+	}
+	c, err := ParseCron(s.Cron)
+	if err != nil {
+		return err
+	}
+	s.Cron = c.Expr
+	schedules, err := Load()
+	if err != nil {
+		return err
+	}
+	for _, existing := range schedules {
+		if existing.Name == s.Name {
+			return fmt.Errorf("schedule %q already exists; remove it first or choose another --name", s.Name)
+		}
+	}
+	if err := scheduler.Install(s, c, executable); err != nil {
+		return err
+	}
+	if s.Created.IsZero() {
+		s.Created = time.Now().UTC()
+	}
+	if err := save(append(schedules, s)); err != nil {
+		scheduler.Remove(s.Name)
+		return err
+	}
+	return nil
+}
+
+// Remove unregisters a schedule and deletes it with its run logs
+func Remove(name string, scheduler Scheduler) error {
+	schedules, err := Load()
+	if err != nil {
+		return err
+	}
+	kept := schedules[:0]
+	for _, s := range schedules {
+		if s.Name != name {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) == len(schedules) {
+		return fmt.Errorf("schedule %q not found", name)
+	}
+	if err := scheduler.Remove(name); err != nil {
+		return err
+	}
+	os.Remove(LogPath(name))
+	os.Remove(LogPath(name) + ".1")
+	return save(kept)
+}
+
+// Execute runs a schedule with executable, appending its output to the
+// run log, and records the run
+func Execute(name, executable string) (*Run, error) {
+	s, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	logFile, err := openLog(name)
+	if err != nil {
+		return nil, err
+	}
+	defer logFile.Close()
+
+	run := &Run{Start: time.Now()}
+	fmt.Fprintf(logFile, "=== %s %s\n", run.Start.Format(time.RFC3339), s.CommandLine())
+	cmd := exec.Command(executable, s.Command...)
+	cmd.Dir = s.WorkDir
+	cmd.Stdout, cmd.Stderr = logFile, logFile
+	err = cmd.Run()
+	run.End = time.Now()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		run.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		run.ExitCode = -1
+		run.Error = err.Error()
+		fmt.Fprintf(logFile, "%v\n", err)
+	}
+	fmt.Fprintf(logFile, "=== exit %d after %s\n\n", run.ExitCode, run.End.Sub(run.Start).Round(time.Millisecond))
+
+	return run, record(name, run)
+}
+
+// openLog opens the run log for appending, rotating it when it is large
+func openLog(name string) (*os.File, error) {
+	path := LogPath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > maxLogSize {
+		os.Rename(path, path+".1")
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+}
+
+// record stores the last run of a schedule
+func record(name string, run *Run) error {
+	schedules, err := Load()
+	if err != nil {
+		return err
+	}
+	for _, s := range schedules {
+		if s.Name == name {
+			s.LastRun = run
+		}
+	}
+	return save(schedules)
+}
+
+// SplitCommand splits a command line into arguments, honoring single and
+// double quotes; a leading "portunix" is dropped
+func SplitCommand(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) > 0 && (args[0] == "portunix" || args[0] == "portunix.exe") {
+		args = args[1:]
+	}
+	return args, nil
+}
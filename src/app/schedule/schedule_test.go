@@ -0,0 +1,221 @@
+package schedule
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, expr := range []string{"0 * * * *", "*/15 9-17 * * mon-fri", "@daily", "5/10 0 1,15 jan-jun 7"} {
+		if _, err := ParseCron(expr); err != nil {
+			t.Errorf("ParseCron(%q): %v", expr, err)
+		}
+	}
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "* * * foo *", "5-1 * * * *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) should fail", expr)
+		}
+	}
+	if c, _ := ParseCron("@hourly"); c.Expr != "0 * * * *" {
+		t.Errorf("unexpected macro expansion %q", c.Expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	base := time.Date(2026, 3, 6, 10, 20, 30, 0, time.UTC) // Friday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 * * * *", time.Date(2026, 3, 6, 11, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 6, 10, 30, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2026, 3, 7, 2, 30, 0, 0, time.UTC)},
+		{"0 9 * * mon", time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		// both day fields restricted: the 10th or a Monday
+		{"0 0 10 * mon", time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Next(base); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+		if !c.Matches(tt.want) {
+			t.Errorf("%q should match %v", tt.expr, tt.want)
+		}
+	}
+	if c, _ := ParseCron("0 0 30 2 *"); !c.Next(base).IsZero() {
+		t.Error("impossible expression should never fire")
+	}
+}
+
+func TestTaskTrigger(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"*/10 * * * *", "/SC MINUTE /MO 10"},
+		{"0 * * * *", "/SC HOURLY /MO 1 /ST 00:00"},
+		{"15 */6 * * *", "/SC HOURLY /MO 6 /ST 00:15"},
+		{"@daily", "/SC DAILY /ST 00:00"},
+		{"30 8 * * mon-fri", "/SC WEEKLY /D MON,TUE,WED,THU,FRI /ST 08:30"},
+		{"0 12 * * 7", "/SC WEEKLY /D SUN /ST 12:00"},
+		{"0 3 1,15 * *", "/SC MONTHLY /D 1,15 /ST 03:00"},
+		{"0 3 1 jan,jul *", "/SC MONTHLY /D 1 /M JAN,JUL /ST 03:00"},
+	}
+	for _, tt := range tests {
+		c, _ := ParseCron(tt.expr)
+		args, err := TaskTrigger(c)
+		if err != nil {
+			t.Errorf("TaskTrigger(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := strings.Join(args, " "); got != tt.want {
+			t.Errorf("TaskTrigger(%q) = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+	for _, expr := range []string{"0,30 9 * * *", "0 9-17 * * *", "0 0 1 * mon"} {
+		c, _ := ParseCron(expr)
+		if _, err := TaskTrigger(c); err == nil {
+			t.Errorf("TaskTrigger(%q) should fail", expr)
+		}
+	}
+}
+
+func TestUpdateCrontab(t *testing.T) {
+	c, _ := ParseCron("0 * * * *")
+	s := &Schedule{Name: "pft-sync"}
+	line := CrontabLine(s, c, "/opt/my tools/portunix")
+	if line != "0 * * * * '/opt/my tools/portunix' schedule run pft-sync >/dev/null 2>&1 # portunix-schedule:pft-sync" {
+		t.Errorf("unexpected line %q", line)
+	}
+
+	current := "MAILTO=dev\n17 3 * * * portunix cert renew # portunix-cert-renew\n"
+	added := UpdateCrontab(current, "pft-sync", line)
+	if added != current+line+"\n" {
+		t.Errorf("unexpected crontab after add:\n%s", added)
+	}
+	// replacing keeps a single line of the schedule
+	if replaced := UpdateCrontab(added, "pft-sync", line); replaced != added {
+		t.Errorf("unexpected crontab after replace:\n%s", replaced)
+	}
+	// a schedule whose name is a prefix of another is not touched
+	other := UpdateCrontab(added, "pft", "")
+	if other != added {
+		t.Errorf("removing another schedule changed the crontab:\n%s", other)
+	}
+	if removed := UpdateCrontab(added, "pft-sync", ""); removed != current {
+		t.Errorf("unexpected crontab after remove:\n%s", removed)
+	}
+	if empty := UpdateCrontab(line+"\n", "pft-sync", ""); empty != "" {
+		t.Errorf("expected empty crontab, got %q", empty)
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"pft sync", "pft|sync"},
+		{"portunix  vuln scan --path '/my project'", "vuln|scan|--path|/my project"},
+		{`cache prune --older-than "7 days"`, "cache|prune|--older-than|7 days"},
+	}
+	for _, tt := range tests {
+		args, err := SplitCommand(tt.line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(args, "|"); got != tt.want {
+			t.Errorf("SplitCommand(%q) = %s, want %s", tt.line, got, tt.want)
+		}
+	}
+	if _, err := SplitCommand(`pft "sync`); err == nil {
+		t.Error("unterminated quote should fail")
+	}
+
+	if name := DefaultName([]string{"pft", "sync", "--force"}); name != "pft-sync" {
+		t.Errorf("unexpected name %q", name)
+	}
+	if name := DefaultName([]string{"Cache", "prune", "old"}); name != "cache-prune" {
+		t.Errorf("unexpected name %q", name)
+	}
+}
+
+type fakeScheduler struct {
+	installed map[string]string
+}
+
+func (f *fakeScheduler) Name() string { return "fake" }
+
+func (f *fakeScheduler) Install(s *Schedule, c *Cron, executable string) error {
+	f.installed[s.Name] = c.Expr
+	return nil
+}
+
+func (f *fakeScheduler) Remove(name string) error {
+	delete(f.installed, name)
+	return nil
+}
+
+func TestAddExecuteRemove(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runs sh")
+	}
+	t.Setenv("HOME", t.TempDir())
+	scheduler := &fakeScheduler{installed: make(map[string]string)}
+
+	s := &Schedule{Name: "greet", Command: []string{"-c", "echo hello; exit 3"}, Cron: "@hourly"}
+	if err := Add(s, scheduler, "sh"); err != nil {
+		t.Fatal(err)
+	}
+	if scheduler.installed["greet"] != "0 * * * *" {
+		t.Errorf("unexpected installed schedules %v", scheduler.installed)
+	}
+	if err := Add(&Schedule{Name: "greet", Command: []string{"true"}, Cron: "* * * * *"}, scheduler, "sh"); err == nil {
+		t.Error("duplicate name should fail")
+	}
+	if err := Add(&Schedule{Name: "Bad Name", Command: []string{"true"}, Cron: "* * * * *"}, scheduler, "sh"); err == nil {
+		t.Error("invalid name should fail")
+	}
+
+	run, err := Execute("greet", "sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.ExitCode != 3 || run.OK() {
+		t.Errorf("unexpected run %+v", run)
+	}
+	log, err := os.ReadFile(LogPath("greet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "hello\n") || !strings.Contains(string(log), "=== exit 3 after") {
+		t.Errorf("unexpected log:\n%s", log)
+	}
+	stored, err := Get("greet")
+	if err != nil || stored.LastRun == nil || stored.LastRun.ExitCode != 3 {
+		t.Errorf("last run not recorded: %+v %v", stored, err)
+	}
+
+	if err := Remove("greet", scheduler); err != nil {
+		t.Fatal(err)
+	}
+	if len(scheduler.installed) != 0 {
+		t.Errorf("schedule still installed: %v", scheduler.installed)
+	}
+	if _, err := os.Stat(LogPath("greet")); !os.IsNotExist(err) {
+		t.Error("run log should be removed")
+	}
+	if err := Remove("greet", scheduler); err == nil {
+		t.Error("removing an unknown schedule should fail")
+	}
+}
@@ -0,0 +1,248 @@
+package schedule
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"portunix.ai/app/shellquote"
+)
+
+// Scheduler is the scheduler of the OS running schedules
+type Scheduler interface {
+	// Name is the scheduler, e.g. "cron"
+	Name() string
+	// Install registers 'executable schedule run <name>' at the times of
+	// the expression, replacing an earlier entry of the schedule
+	Install(s *Schedule, c *Cron, executable string) error
+	// Remove deletes the entry of a schedule; a missing entry is not an
+	// error
+	Remove(name string) error
+}
+
+// Detect returns the scheduler of the running OS
+func Detect() (Scheduler, error) {
+	if runtime.GOOS == "windows" {
+		return &TaskScheduler{}, nil
+	}
+	if _, err := exec.LookPath("crontab"); err != nil {
+		return nil, fmt.Errorf("crontab not found; install cron to schedule commands")
+	}
+	return &Crontab{}, nil
+}
+
+// Crontab keeps schedules in the user's crontab, one line per schedule
+// marked with a "# portunix-schedule:<name>" comment
+type Crontab struct{}
+
+// Name implements Scheduler
+func (c *Crontab) Name() string { return "cron" }
+
+func crontabMarker(name string) string {
+	return "# portunix-schedule:" + name
+}
+
+// CrontabLine returns the crontab line of a schedule
+func CrontabLine(s *Schedule, c *Cron, executable string) string {
+	// cron passes the line to sh; its output goes to the run log
+	return fmt.Sprintf("%s %s schedule run %s >/dev/null 2>&1 %s", c.Expr, shellquote.Quote(executable), s.Name, crontabMarker(s.Name))
+}
+
+// UpdateCrontab returns crontab without the line of schedule name, and
+// with line appended when it is not empty
+func UpdateCrontab(crontab, name, line string) string {
+	var kept []string
+	for _, l := range strings.Split(crontab, "\n") {
+		if !strings.HasSuffix(strings.TrimSpace(l), crontabMarker(name)) {
+			kept = append(kept, l)
+		}
+	}
+	updated := strings.TrimRight(strings.Join(kept, "\n"), "\n")
+	if line != "" {
+		if updated != "" {
+			updated += "\n"
+		}
+		updated += line
+	}
+	if updated == "" {
+		return ""
+	}
+	return updated + "\n"
+}
+
+// Install implements Scheduler
+func (c *Crontab) Install(s *Schedule, expr *Cron, executable string) error {
+	current, err := readCrontab()
+	if err != nil {
+		return err
+	}
+	return writeCrontab(UpdateCrontab(current, s.Name, CrontabLine(s, expr, executable)))
+}
+
+// Remove implements Scheduler
+func (c *Crontab) Remove(name string) error {
+	current, err := readCrontab()
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(current, crontabMarker(name)) {
+		return nil
+	}
+	return writeCrontab(UpdateCrontab(current, name, ""))
+}
+
+func readCrontab() (string, error) {
+	out, err := exec.Command("crontab", "-l").Output()
+	if err != nil {
+		// crontab -l fails when the user has no crontab yet
+		if _, ok := err.(*exec.ExitError); ok {
+			return "", nil
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
+func writeCrontab(content string) error {
+	cmd := exec.Command("crontab", "-")
+	cmd.Stdin = bytes.NewBufferString(content)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("crontab failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// TaskScheduler keeps schedules as tasks of the Windows Task Scheduler in
+// the \Portunix\Schedule folder. Task Scheduler has no cron, so only the
+// expressions it can express are accepted; see TaskTrigger.
+type TaskScheduler struct{}
+
+// Name implements Scheduler
+func (t *TaskScheduler) Name() string { return "taskscheduler" }
+
+func taskName(name string) string {
+	return `\Portunix\Schedule\` + name
+}
+
+// Install implements Scheduler
+func (t *TaskScheduler) Install(s *Schedule, c *Cron, executable string) error {
+	trigger, err := TaskTrigger(c)
+	if err != nil {
+		return err
+	}
+	command := fmt.Sprintf(`"%s" schedule run %s`, executable, s.Name)
+	args := append([]string{"/Create", "/F", "/TN", taskName(s.Name), "/TR", command}, trigger...)
+	if out, err := exec.Command("schtasks", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("schtasks failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Remove implements Scheduler
+func (t *TaskScheduler) Remove(name string) error {
+	if exec.Command("schtasks", "/Query", "/TN", taskName(name)).Run() != nil {
+		return nil
+	}
+	if out, err := exec.Command("schtasks", "/Delete", "/F", "/TN", taskName(name)).CombinedOutput(); err != nil {
+		return fmt.Errorf("schtasks failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// TaskTrigger translates a cron expression to schtasks trigger flags.
+// Supported are every N minutes ("*/N * * * *"), every N hours at a minute
+// ("M */N * * *"), daily ("M H * * *"), weekly ("M H * * mon,fri") and
+// monthly ("M H D[,D] [months] *") schedules.
+func TaskTrigger(c *Cron) ([]string, error) {
+	f := strings.Fields(c.Expr)
+	minute, hour, dom, month, dow := f[0], f[1], f[2], f[3], f[4]
+	unsupported := fmt.Errorf("cron expression %q cannot be expressed in Task Scheduler; use a fixed minute and hour with daily, weekly or monthly days, or */N minutes or hours", c.Expr)
+
+	if hour == "*" && dom == "*" && month == "*" && dow == "*" {
+		if minute == "*" {
+			return []string{"/SC", "MINUTE", "/MO", "1"}, nil
+		}
+		if n, ok := everyN(minute); ok {
+			return []string{"/SC", "MINUTE", "/MO", strconv.Itoa(n)}, nil
+		}
+	}
+	m, err := strconv.Atoi(minute)
+	if err != nil {
+		return nil, unsupported
+	}
+	if dom == "*" && month == "*" && dow == "*" {
+		if hour == "*" {
+			return []string{"/SC", "HOURLY", "/MO", "1", "/ST", fmt.Sprintf("00:%02d", m)}, nil
+		}
+		if n, ok := everyN(hour); ok {
+			return []string{"/SC", "HOURLY", "/MO", strconv.Itoa(n), "/ST", fmt.Sprintf("00:%02d", m)}, nil
+		}
+	}
+	h, err := strconv.Atoi(hour)
+	if err != nil {
+		return nil, unsupported
+	}
+	start := fmt.Sprintf("%02d:%02d", h, m)
+	switch {
+	case dom == "*" && month == "*" && dow == "*":
+		return []string{"/SC", "DAILY", "/ST", start}, nil
+	case dom == "*" && month == "*":
+		days := names(c.fields[4], []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}, 0)
+		return []string{"/SC", "WEEKLY", "/D", days, "/ST", start}, nil
+	case dom != "*" && dow == "*":
+		args := []string{"/SC", "MONTHLY", "/D", numbers(c.fields[2])}
+		if month != "*" {
+			args = append(args, "/M", names(c.fields[3], []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}, 1))
+		}
+		return append(args, "/ST", start), nil
+	}
+	return nil, unsupported
+}
+
+// everyN parses "*/N"
+func everyN(field string) (int, bool) {
+	step, ok := strings.CutPrefix(field, "*/")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(step)
+	return n, err == nil && n > 0
+}
+
+func sortedValues(values map[int]bool) []int {
+	var sorted []int
+	for v, ok := range values {
+		if ok {
+			sorted = append(sorted, v)
+		}
+	}
+	sort.Ints(sorted)
+	return sorted
+}
+
+func numbers(values map[int]bool) string {
+	var parts []string
+	for _, v := range sortedValues(values) {
+		parts = append(parts, strconv.Itoa(v))
+	}
+	return strings.Join(parts, ",")
+}
+
+// names returns the names of values; values past the names wrap around,
+// so day 7 is Sunday
+func names(values map[int]bool, all []string, min int) string {
+	seen := make(map[string]bool)
+	var parts []string
+	for _, v := range sortedValues(values) {
+		name := all[(v-min)%len(all)]
+		if !seen[name] {
+			seen[name] = true
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,104 @@
+package secret
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+)
+
+// AuditEntry records one access to a secret
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"` // get, set, delete
+	Name   string    `json:"name"`
+	// Consumer is the subsystem accessing the secret, e.g. cli, pft,
+	// ansible, edge, registry
+	Consumer string `json:"consumer"`
+	User     string `json:"user,omitempty"`
+	PID      int    `json:"pid"`
+	OK       bool   `json:"ok"`
+	Error    string `json:"error,omitempty"`
+}
+
+func auditPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit.log"), nil
+}
+
+// audit appends an access to the audit log. Failing to audit does not
+// fail the access.
+func audit(action, name, consumer string, accessErr error) {
+	path, err := auditPath()
+	if err != nil {
+		return
+	}
+	entry := AuditEntry{
+		Time:     time.Now(),
+		Action:   action,
+		Name:     name,
+		Consumer: consumer,
+		PID:      os.Getpid(),
+		OK:       accessErr == nil,
+	}
+	if accessErr != nil {
+		entry.Error = accessErr.Error()
+	}
+	if u, err := user.Current(); err == nil {
+		entry.User = u.Username
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	file.Write(append(data, '\n'))
+}
+
+// AuditLog returns the recorded accesses, oldest first. name limits them
+// to one secret; limit, when positive, keeps the most recent ones.
+func AuditLog(name string, limit int) ([]AuditEntry, error) {
+	path, err := auditPath()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			continue
+		}
+		if name == "" || entry.Name == name {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return entries, nil
+}
@@ -0,0 +1,219 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"portunix.ai/app/fileutil"
+)
+
+// EnvPassphrase, when set, derives the key of the encrypted file from a
+// passphrase instead of the generated key file
+const EnvPassphrase = "PORTUNIX_SECRET_PASSPHRASE"
+
+const (
+	kdfKeyFile = "keyfile"
+	kdfPBKDF2  = "pbkdf2-sha256"
+	// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-SHA256
+	pbkdf2Iterations = 600000
+)
+
+// encryptedFile is the on-disk format of the file backend
+type encryptedFile struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Salt    []byte `json:"salt,omitempty"`
+	// Data is the AES-256-GCM nonce followed by the sealed JSON map of
+	// secret names to values
+	Data []byte `json:"data"`
+}
+
+// fileBackend keeps all secrets in one AES-256-GCM encrypted file. The key
+// is a random key file next to it, readable by the owner only, or derived
+// from $PORTUNIX_SECRET_PASSPHRASE when the file was created with it set.
+type fileBackend struct {
+	path    string
+	keyPath string
+}
+
+func newFileBackend(dir string) *fileBackend {
+	return &fileBackend{
+		path:    filepath.Join(dir, "secrets.enc"),
+		keyPath: filepath.Join(dir, "master.key"),
+	}
+}
+
+func (f *fileBackend) Name() string {
+	return BackendFile
+}
+
+func (f *fileBackend) Available() bool {
+	return true
+}
+
+func (f *fileBackend) Get(name string) (string, error) {
+	values, _, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	value, ok := values[name]
+	if !ok {
+		return "", fmt.Errorf("%w in %s: %s", ErrNotFound, f.path, name)
+	}
+	return value, nil
+}
+
+// Set and Delete hold the lock of the encrypted file while they update it,
+// so concurrent updates do not drop each other's secrets
+func (f *fileBackend) Set(name, value string) error {
+	return f.update(func(values map[string]string) error {
+		values[name] = value
+		return nil
+	})
+}
+
+func (f *fileBackend) Delete(name string) error {
+	return f.update(func(values map[string]string) error {
+		if _, ok := values[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		delete(values, name)
+		return nil
+	})
+}
+
+func (f *fileBackend) update(fn func(values map[string]string) error) error {
+	// Create the directory before the lock does, with private permissions
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return err
+	}
+	return fileutil.WithLock(f.path, func() error {
+		values, header, err := f.load()
+		if err != nil {
+			return err
+		}
+		if err := fn(values); err != nil {
+			return err
+		}
+		return f.save(values, header)
+	})
+}
+
+// load decrypts the secrets. A missing file yields an empty set and the
+// header a new file is created with.
+func (f *fileBackend) load() (map[string]string, *encryptedFile, error) {
+	values := make(map[string]string)
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		header := &encryptedFile{Version: 1, KDF: kdfKeyFile}
+		if os.Getenv(EnvPassphrase) != "" {
+			header.KDF = kdfPBKDF2
+			header.Salt = make([]byte, 16)
+			if _, err := rand.Read(header.Salt); err != nil {
+				return nil, nil, err
+			}
+		}
+		return values, header, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var header encryptedFile
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", f.path, err)
+	}
+	gcm, err := f.cipher(&header, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(header.Data) < gcm.NonceSize() {
+		return nil, nil, fmt.Errorf("%s is corrupted", f.path)
+	}
+	nonce, sealed := header.Data[:gcm.NonceSize()], header.Data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt %s (wrong key or passphrase)", f.path)
+	}
+	if err := json.Unmarshal(plain, &values); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse decrypted secrets: %w", err)
+	}
+	return values, &header, nil
+}
+
+func (f *fileBackend) save(values map[string]string, header *encryptedFile) error {
+	gcm, err := f.cipher(header, true)
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	header.Data = gcm.Seal(nonce, nonce, plain, nil)
+	data, err := json.MarshalIndent(header, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivate(f.path, data)
+}
+
+// cipher returns the AES-GCM cipher of a file; create allows generating
+// the key file
+func (f *fileBackend) cipher(header *encryptedFile, create bool) (cipher.AEAD, error) {
+	var key []byte
+	switch header.KDF {
+	case kdfPBKDF2:
+		passphrase := os.Getenv(EnvPassphrase)
+		if passphrase == "" {
+			return nil, fmt.Errorf("%s is protected by a passphrase; set %s", f.path, EnvPassphrase)
+		}
+		key = pbkdf2.Key([]byte(passphrase), header.Salt, pbkdf2Iterations, 32, sha256.New)
+	case kdfKeyFile:
+		var err error
+		if key, err = f.keyFile(create); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key derivation %q in %s", header.KDF, f.path)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// keyFile reads the random key of the file backend, generating it on
+// first use
+func (f *fileBackend) keyFile(create bool) ([]byte, error) {
+	key, err := os.ReadFile(f.keyPath)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("invalid key file %s", f.keyPath)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) || !create {
+		return nil, fmt.Errorf("cannot read key file %s: %w", f.keyPath, err)
+	}
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, writePrivate(f.keyPath, key)
+}
@@ -0,0 +1,105 @@
+package secret
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keychainService is the service under which secrets are stored in the
+// OS keychain
+const keychainService = "portunix"
+
+// keychain stores secrets in the macOS Keychain through security(1) or in
+// the freedesktop Secret Service (GNOME Keyring, KWallet) through
+// secret-tool(1). Values are passed on stdin, never on the command line.
+// Windows uses the encrypted file backend.
+type keychain struct{}
+
+func newKeychain() *keychain {
+	return &keychain{}
+}
+
+func (k *keychain) Name() string {
+	return BackendKeychain
+}
+
+func (k *keychain) Available() bool {
+	switch runtime.GOOS {
+	case "darwin":
+		_, err := exec.LookPath("security")
+		return err == nil
+	case "linux", "freebsd":
+		// The Secret Service is reached over the session bus, which
+		// headless machines and containers do not have
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return false
+		}
+		return os.Getenv("DBUS_SESSION_BUS_ADDRESS") != ""
+	}
+	return false
+}
+
+func (k *keychain) Get(name string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", name, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "account", name)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// Both tools exit non-zero when the item does not exist;
+		// secret-tool says nothing, security says so
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" || strings.Contains(msg, "could not be found") {
+			return "", fmt.Errorf("%w in keychain: %s", ErrNotFound, name)
+		}
+		return "", fmt.Errorf("keychain: %s", msg)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func (k *keychain) Set(name, value string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		// 'security -i' reads commands from stdin, keeping the value out
+		// of the process list
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -l %s -w %s\n",
+			securityQuote(keychainService), securityQuote(name), securityQuote("portunix: "+name), securityQuote(value)))
+	} else {
+		cmd = exec.Command("secret-tool", "store", "--label", "portunix: "+name, "service", keychainService, "account", name)
+		cmd.Stdin = strings.NewReader(value)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("keychain: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (k *keychain) Delete(name string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "delete-generic-password", "-s", keychainService, "-a", name)
+	} else {
+		cmd = exec.Command("secret-tool", "clear", "service", keychainService, "account", name)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("keychain: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// securityQuote quotes an argument for the command language of
+// 'security -i'
+func securityQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
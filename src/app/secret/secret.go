@@ -0,0 +1,345 @@
+// Package secret is the secret store shared by portunix subsystems: pft
+// API tokens, Ansible playbook secrets, package registry tokens and edge
+// provider API keys. Values live in the OS keychain or in an encrypted
+// file; an index records names and metadata, and every access is written
+// to an audit log.
+package secret
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"portunix.ai/app/config"
+)
+
+// KeyBackend is the configuration key selecting the backend of new
+// secrets: auto, keychain or file
+const KeyBackend = "secret.backend"
+
+// Backend names
+const (
+	BackendAuto     = "auto"
+	BackendKeychain = "keychain"
+	BackendFile     = "file"
+)
+
+// RefPrefix marks configuration values that name a secret instead of
+// holding it, e.g. "secret:pft/voc"
+const RefPrefix = "secret:"
+
+// ErrNotFound is returned for secrets that are not stored
+var ErrNotFound = errors.New("secret not found")
+
+// Backend stores secret values
+type Backend interface {
+	Name() string
+	Available() bool
+	Get(name string) (string, error)
+	Set(name, value string) error
+	Delete(name string) error
+}
+
+// Info describes a stored secret without its value
+type Info struct {
+	Name    string    `json:"name"`
+	Backend string    `json:"backend"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+	// SetBy is the subsystem that last stored the value
+	SetBy string `json:"set_by,omitempty"`
+}
+
+// Dir returns the directory holding the index, encrypted file and audit
+// log: ~/.portunix/secrets
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".portunix", "secrets"), nil
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+
+// ValidateName checks a secret name. Names are namespaced by subsystem
+// with '/', e.g. pft/voc, edge/hetzner, registry/team.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) || len(name) > 128 || strings.Contains(name, "..") {
+		return fmt.Errorf("invalid secret name %q (letters, digits, '.', '_', '-' and '/')", name)
+	}
+	return nil
+}
+
+// ValidateBackend checks a secret.backend value
+func ValidateBackend(name string) error {
+	switch name {
+	case BackendAuto, BackendKeychain, BackendFile:
+		return nil
+	}
+	return fmt.Errorf("unknown secret backend %q (use auto, keychain or file)", name)
+}
+
+// backends returns the backend implementations by name
+func backends() (map[string]Backend, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]Backend{
+		BackendKeychain: newKeychain(),
+		BackendFile:     newFileBackend(dir),
+	}, nil
+}
+
+// Backends returns the backends with their availability on this machine
+func Backends() (map[string]bool, error) {
+	all, err := backends()
+	if err != nil {
+		return nil, err
+	}
+	available := make(map[string]bool)
+	for name, backend := range all {
+		available[name] = backend.Available()
+	}
+	return available, nil
+}
+
+// selectBackend returns the backend storing new secrets: the configured
+// one, or the keychain when available with the encrypted file as fallback
+func selectBackend(requested string) (Backend, error) {
+	all, err := backends()
+	if err != nil {
+		return nil, err
+	}
+	if requested == "" {
+		requested = config.GetString(KeyBackend, BackendAuto)
+	}
+	if err := ValidateBackend(requested); err != nil {
+		return nil, err
+	}
+	if requested == BackendAuto {
+		if keychain := all[BackendKeychain]; keychain.Available() {
+			return keychain, nil
+		}
+		return all[BackendFile], nil
+	}
+	backend := all[requested]
+	if !backend.Available() {
+		return nil, fmt.Errorf("secret backend %s is not available on this system", requested)
+	}
+	return backend, nil
+}
+
+// Set stores a secret. A new secret goes to the backend argument, or the
+// configured backend when empty; an existing one stays in its backend
+// unless another is named. consumer names the calling subsystem for the
+// audit log.
+func Set(name, value, backendName, consumer string) (err error) {
+	defer func() { audit("set", name, consumer, err) }()
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	index, err := loadIndex()
+	if err != nil {
+		return err
+	}
+	info, exists := index[name]
+	if backendName == "" && exists {
+		backendName = info.Backend
+	}
+	backend, err := selectBackend(backendName)
+	if err != nil {
+		return err
+	}
+	if err := backend.Set(name, value); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if exists && info.Backend != backend.Name() {
+		// Moved to another backend; remove the old copy
+		if old, err := backendByName(info.Backend); err == nil {
+			old.Delete(name)
+		}
+	}
+	if !exists {
+		info = &Info{Name: name, Created: now}
+		index[name] = info
+	}
+	info.Backend = backend.Name()
+	info.Updated = now
+	info.SetBy = consumer
+	return saveIndex(index)
+}
+
+// Get returns a secret value
+func Get(name, consumer string) (value string, err error) {
+	defer func() { audit("get", name, consumer, err) }()
+	index, err := loadIndex()
+	if err != nil {
+		return "", err
+	}
+	info, ok := index[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s (store it with 'portunix secret set %s')", ErrNotFound, name, name)
+	}
+	backend, err := backendByName(info.Backend)
+	if err != nil {
+		return "", err
+	}
+	return backend.Get(name)
+}
+
+// Lookup returns a secret value when the secret is stored. Secrets that
+// are not stored are reported with ok false and no error, and are not
+// audited.
+func Lookup(name, consumer string) (string, bool, error) {
+	index, err := loadIndex()
+	if err != nil {
+		return "", false, err
+	}
+	if _, ok := index[name]; !ok {
+		return "", false, nil
+	}
+	value, err := Get(name, consumer)
+	return value, err == nil, err
+}
+
+// Delete removes a secret
+func Delete(name, consumer string) (err error) {
+	defer func() { audit("delete", name, consumer, err) }()
+	index, err := loadIndex()
+	if err != nil {
+		return err
+	}
+	info, ok := index[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	backend, err := backendByName(info.Backend)
+	if err != nil {
+		return err
+	}
+	if err := backend.Delete(name); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	delete(index, name)
+	return saveIndex(index)
+}
+
+// List returns the stored secrets sorted by name
+func List() ([]Info, error) {
+	index, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Info, 0, len(index))
+	for _, info := range index {
+		list = append(list, *info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// IsReference reports whether a configuration value names a secret
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, RefPrefix)
+}
+
+// Reference returns the configuration value naming a secret
+func Reference(name string) string {
+	return RefPrefix + name
+}
+
+// Resolve returns the secret named by a "secret:<name>" value; other
+// values are returned unchanged
+func Resolve(value, consumer string) (string, error) {
+	name, ok := strings.CutPrefix(value, RefPrefix)
+	if !ok {
+		return value, nil
+	}
+	return Get(strings.TrimSpace(name), consumer)
+}
+
+func backendByName(name string) (Backend, error) {
+	all, err := backends()
+	if err != nil {
+		return nil, err
+	}
+	backend, ok := all[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown secret backend %q", name)
+	}
+	return backend, nil
+}
+
+func indexPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "index.json"), nil
+}
+
+func loadIndex() (map[string]*Info, error) {
+	path, err := indexPath()
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]*Info)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return index, nil
+}
+
+func saveIndex(index map[string]*Info) error {
+	path, err := indexPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivate(path, data)
+}
+
+// writePrivate atomically writes a file readable by the owner only
+func writePrivate(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
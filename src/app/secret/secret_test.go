@@ -0,0 +1,154 @@
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func setupHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv(EnvPassphrase, "")
+	return home
+}
+
+func TestFileBackend(t *testing.T) {
+	home := setupHome(t)
+
+	if err := Set("edge/hetzner", "tok-123", BackendFile, "cli"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set("pft/app/voc", "fider-456", BackendFile, "pft"); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := Get("edge/hetzner", "edge"); err != nil || value != "tok-123" {
+		t.Fatalf("got %q %v", value, err)
+	}
+
+	// Values are encrypted at rest and the files are private
+	dir := filepath.Join(home, ".portunix", "secrets")
+	data, err := os.ReadFile(filepath.Join(dir, "secrets.enc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "tok-123") {
+		t.Error("secret value stored in plain text")
+	}
+	for _, file := range []string{"secrets.enc", "master.key", "index.json"} {
+		if info, err := os.Stat(filepath.Join(dir, file)); err != nil || info.Mode().Perm() != 0600 {
+			t.Errorf("%s should be private: %v %v", file, info, err)
+		}
+	}
+
+	list, err := List()
+	if err != nil || len(list) != 2 || list[0].Name != "edge/hetzner" || list[1].SetBy != "pft" {
+		t.Fatalf("unexpected list %+v %v", list, err)
+	}
+
+	if err := Delete("edge/hetzner", "cli"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get("edge/hetzner", "edge"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleted secret should not be found: %v", err)
+	}
+	if _, ok, err := Lookup("edge/hetzner", "edge"); ok || err != nil {
+		t.Errorf("lookup of a missing secret should report not found: %v %v", ok, err)
+	}
+}
+
+func TestFileBackendConcurrentSet(t *testing.T) {
+	setupHome(t)
+	dir := t.TempDir()
+
+	// Separate backends stand in for separate processes sharing the file
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := newFileBackend(dir).Set(fmt.Sprintf("app/%d", i), "value"); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	values, _, err := newFileBackend(dir).load()
+	if err != nil || len(values) != 8 {
+		t.Errorf("concurrent updates lost secrets: %d stored, %v", len(values), err)
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	setupHome(t)
+	t.Setenv(EnvPassphrase, "correct horse")
+
+	if err := Set("registry/team", "abc", BackendFile, "cli"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvPassphrase, "wrong")
+	if _, err := Get("registry/team", "registry"); err == nil {
+		t.Error("wrong passphrase should fail")
+	}
+	t.Setenv(EnvPassphrase, "correct horse")
+	if value, err := Get("registry/team", "registry"); err != nil || value != "abc" {
+		t.Errorf("got %q %v", value, err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	setupHome(t)
+	if err := Set("pft/app/smtp", "pw", BackendFile, "pft"); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := Resolve("secret:pft/app/smtp", "pft"); err != nil || value != "pw" {
+		t.Errorf("got %q %v", value, err)
+	}
+	if value, err := Resolve("plain-token", "pft"); err != nil || value != "plain-token" {
+		t.Errorf("plain values should be returned unchanged: %q %v", value, err)
+	}
+	if _, err := Resolve(Reference("pft/missing"), "pft"); err == nil {
+		t.Error("missing reference should fail")
+	}
+}
+
+func TestAudit(t *testing.T) {
+	setupHome(t)
+	Set("edge/digitalocean", "do", BackendFile, "cli")
+	Get("edge/digitalocean", "edge")
+	Get("edge/unknown", "edge")
+
+	entries, err := AuditLog("", 0)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("expected 3 audit entries, got %+v %v", entries, err)
+	}
+	if e := entries[1]; e.Action != "get" || e.Consumer != "edge" || !e.OK {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if e := entries[2]; e.OK || e.Error == "" {
+		t.Errorf("failed access should be recorded: %+v", e)
+	}
+	if entries, _ := AuditLog("edge/digitalocean", 1); len(entries) != 1 || entries[0].Action != "get" {
+		t.Errorf("filtered log should keep the most recent entry: %+v", entries)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"pft/voc", "edge/hetzner", "db_password", "registry/team.internal"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "/abs", "a/../b", "has space", "-flag"} {
+		if ValidateName(name) == nil {
+			t.Errorf("name %q should be rejected", name)
+		}
+	}
+}
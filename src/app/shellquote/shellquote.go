@@ -0,0 +1,28 @@
+// Package shellquote quotes arguments for command lines run by a POSIX
+// shell: sh -c inside containers and VMs, commands sent over ssh and
+// crontab lines.
+package shellquote
+
+import "strings"
+
+// safeChars never have a special meaning to a POSIX shell
+const safeChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:,+@%"
+
+// Quote returns s as one shell word. Anything but a non-empty run of
+// safeChars is wrapped in single quotes, inside which the shell expands
+// nothing, and embedded single quotes are closed, escaped and reopened.
+func Quote(s string) string {
+	if s != "" && strings.Trim(s, safeChars) == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Join quotes args and joins them into one command line
+func Join(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = Quote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
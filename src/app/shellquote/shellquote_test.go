@@ -0,0 +1,68 @@
+package shellquote
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+var words = []string{
+	"",
+	"plain",
+	"/usr/local/bin/portunix",
+	"two words",
+	"line\nbreak",
+	"it's",
+	"'",
+	`back\slash`,
+	"~/home",
+	"# comment",
+	"*.go",
+	"file?[ab]",
+	"$HOME ${PATH} $(id) `id`",
+	"a;b&c|d<e>f",
+	"!event",
+	"{a,b}",
+	"FOO=bar",
+	"-n",
+	"tab\there",
+}
+
+func TestQuote(t *testing.T) {
+	for _, word := range []string{"plain", "/usr/local/bin/portunix", "v1.2.3", "user@host:22", "a,b+c%d"} {
+		if got := Quote(word); got != word {
+			t.Errorf("Quote(%q) = %s, safe words stay bare", word, got)
+		}
+	}
+	for _, word := range []string{"", "line\nbreak", "~/home", "# comment", "*.go", "FOO=bar", "$HOME"} {
+		if got := Quote(word); !strings.HasPrefix(got, "'") {
+			t.Errorf("Quote(%q) = %s, expected single quotes", word, got)
+		}
+	}
+	if got := Quote("it's"); got != `'it'\''s'` {
+		t.Errorf("Quote(it's) = %s", got)
+	}
+}
+
+// TestJoinShell runs the quoted words through sh and checks that each
+// comes back as one unchanged argument
+func TestJoinShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no POSIX shell")
+	}
+	script := "set -- " + Join(words) + `; for arg; do printf '%s\0' "$arg"; done`
+	out, err := exec.Command("sh", "-c", script).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	if len(got) != len(words) {
+		t.Fatalf("sh saw %d arguments, expected %d: %q", len(got), len(words), got)
+	}
+	for i, word := range words {
+		if got[i] != word {
+			t.Errorf("sh saw %q, expected %q", got[i], word)
+		}
+	}
+}
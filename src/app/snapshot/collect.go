@@ -0,0 +1,214 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// portunixPackages reads the database of packages installed by portunix
+func portunixPackages() []Package {
+	path := os.Getenv("PORTUNIX_INSTALLED_DB")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		path = filepath.Join(home, ".portunix", "installed.json")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var state struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+	}
+	if json.Unmarshal(data, &state) != nil {
+		return nil
+	}
+	var packages []Package
+	for name, p := range state.Packages {
+		packages = append(packages, Package{Name: name, Version: p.Version, Source: "portunix"})
+	}
+	return packages
+}
+
+// packageQuery lists the packages of one package database
+type packageQuery struct {
+	source  string
+	command []string
+	parse   func(output, source string) []Package
+}
+
+var packageQueries = map[string][]packageQuery{
+	"linux": {
+		{"dpkg", []string{"dpkg-query", "-W", "-f", "${db:Status-Status}\t${Package}\t${Version}\n"}, parseDpkg},
+		{"rpm", []string{"rpm", "-qa", "--qf", "%{NAME}\t%{VERSION}-%{RELEASE}\n"}, parseFields},
+		{"pacman", []string{"pacman", "-Q"}, parseFields},
+	},
+	"darwin": {
+		{"brew", []string{"brew", "list", "--formula", "--versions"}, parseFields},
+		{"brew-cask", []string{"brew", "list", "--cask", "--versions"}, parseFields},
+	},
+	"windows": {
+		{"windows", []string{"powershell", "-NoProfile", "-Command",
+			`@(Get-ItemProperty HKLM:\Software\Microsoft\Windows\CurrentVersion\Uninstall\*, HKLM:\Software\WOW6432Node\Microsoft\Windows\CurrentVersion\Uninstall\*, HKCU:\Software\Microsoft\Windows\CurrentVersion\Uninstall\* -ErrorAction SilentlyContinue | Where-Object DisplayName | Select-Object DisplayName,DisplayVersion) | ConvertTo-Json -Compress`},
+			parseWindowsPackages},
+	},
+}
+
+// systemPackages lists the packages of the system package databases
+func systemPackages() []Package {
+	var packages []Package
+	for _, q := range packageQueries[runtime.GOOS] {
+		if _, err := exec.LookPath(q.command[0]); err != nil {
+			continue
+		}
+		output, err := exec.Command(q.command[0], q.command[1:]...).Output()
+		if err != nil {
+			continue
+		}
+		packages = append(packages, q.parse(string(output), q.source)...)
+	}
+	return packages
+}
+
+// parseDpkg keeps the installed packages of dpkg-query output
+func parseDpkg(output, source string) []Package {
+	var packages []Package
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) == 3 && fields[0] == "installed" {
+			packages = append(packages, Package{Name: fields[1], Version: fields[2], Source: source})
+		}
+	}
+	return packages
+}
+
+// parseFields parses "name version [version...]" lines; the last version
+// wins when several are installed
+func parseFields(output, source string) []Package {
+	var packages []Package
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		packages = append(packages, Package{Name: fields[0], Version: fields[len(fields)-1], Source: source})
+	}
+	return packages
+}
+
+// parseWindowsPackages parses the uninstall registry entries
+func parseWindowsPackages(output, source string) []Package {
+	output = strings.TrimSpace(output)
+	// PowerShell 5 unwraps single element arrays
+	if strings.HasPrefix(output, "{") {
+		output = "[" + output + "]"
+	}
+	var entries []struct {
+		DisplayName    string
+		DisplayVersion string
+	}
+	if json.Unmarshal([]byte(output), &entries) != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var packages []Package
+	for _, e := range entries {
+		name := strings.TrimSpace(e.DisplayName)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		packages = append(packages, Package{Name: name, Version: e.DisplayVersion, Source: source})
+	}
+	return packages
+}
+
+// systemServices lists the services of the platform service manager
+func systemServices() []Service {
+	switch runtime.GOOS {
+	case "linux":
+		if output, err := exec.Command("systemctl", "list-units", "--type=service", "--all",
+			"--no-legend", "--no-pager", "--plain").Output(); err == nil {
+			return parseSystemctl(string(output))
+		}
+	case "darwin":
+		if output, err := exec.Command("launchctl", "list").Output(); err == nil {
+			return parseLaunchctl(string(output))
+		}
+	case "windows":
+		if output, err := exec.Command("powershell", "-NoProfile", "-Command",
+			`@(Get-Service | Select-Object Name,@{n='Status';e={$_.Status.ToString()}}) | ConvertTo-Json -Compress`).Output(); err == nil {
+			return parseWindowsServices(string(output))
+		}
+	}
+	return nil
+}
+
+// parseSystemctl parses "systemctl list-units --plain" output: unit, load,
+// active and sub state, description
+func parseSystemctl(output string) []Service {
+	var services []Service
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasSuffix(fields[0], ".service") || fields[1] == "not-found" {
+			continue
+		}
+		state := fields[3]
+		if fields[2] == "failed" {
+			state = "failed"
+		} else if fields[2] == "inactive" {
+			state = "stopped"
+		}
+		services = append(services, Service{Name: strings.TrimSuffix(fields[0], ".service"), State: state, Manager: "systemd"})
+	}
+	return services
+}
+
+// parseLaunchctl parses "launchctl list" output: PID, last exit status,
+// label
+func parseLaunchctl(output string) []Service {
+	var services []Service
+	for i, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) != 3 {
+			continue
+		}
+		state := "running"
+		if fields[0] == "-" {
+			state = "stopped"
+			if fields[1] != "0" {
+				state = "failed"
+			}
+		}
+		services = append(services, Service{Name: fields[2], State: state, Manager: "launchd"})
+	}
+	return services
+}
+
+// parseWindowsServices parses Get-Service JSON output
+func parseWindowsServices(output string) []Service {
+	output = strings.TrimSpace(output)
+	if strings.HasPrefix(output, "{") {
+		output = "[" + output + "]"
+	}
+	var entries []struct {
+		Name   string
+		Status string
+	}
+	if json.Unmarshal([]byte(output), &entries) != nil {
+		return nil
+	}
+	var services []Service
+	for _, e := range entries {
+		services = append(services, Service{Name: e.Name, State: strings.ToLower(e.Status), Manager: "windows"})
+	}
+	return services
+}
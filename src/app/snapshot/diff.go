@@ -0,0 +1,92 @@
+package snapshot
+
+import (
+	"sort"
+)
+
+// Change is a value that differs between two snapshots; Old is empty for
+// added and New for removed items
+type Change struct {
+	Name string `json:"name"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// ChangeSet groups the changes of one kind of item
+type ChangeSet struct {
+	Added   []Change `json:"added,omitempty"`
+	Removed []Change `json:"removed,omitempty"`
+	Changed []Change `json:"changed,omitempty"`
+}
+
+// Empty reports whether nothing changed
+func (c *ChangeSet) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// Count returns the number of changes
+func (c *ChangeSet) Count() int {
+	return len(c.Added) + len(c.Removed) + len(c.Changed)
+}
+
+// Diff is the difference between two snapshots
+type Diff struct {
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+	Packages ChangeSet `json:"packages"`
+	Env      ChangeSet `json:"env"`
+	Services ChangeSet `json:"services"`
+}
+
+// Empty reports whether the snapshots are equal
+func (d *Diff) Empty() bool {
+	return d.Packages.Empty() && d.Env.Empty() && d.Services.Empty()
+}
+
+// Compare returns what changed from a to b
+func Compare(a, b *Snapshot) *Diff {
+	d := &Diff{From: a.Name, To: b.Name}
+	d.Packages = compareMaps(packageVersions(a.Packages), packageVersions(b.Packages))
+	d.Env = compareMaps(a.Env, b.Env)
+	d.Services = compareMaps(serviceStates(a.Services), serviceStates(b.Services))
+	return d
+}
+
+func packageVersions(packages []Package) map[string]string {
+	m := make(map[string]string, len(packages))
+	for _, p := range packages {
+		m[p.Key()] = p.Version
+	}
+	return m
+}
+
+func serviceStates(services []Service) map[string]string {
+	m := make(map[string]string, len(services))
+	for _, s := range services {
+		m[s.Name] = s.State
+	}
+	return m
+}
+
+// compareMaps returns the changes from a to b, sorted by name
+func compareMaps(a, b map[string]string) ChangeSet {
+	var c ChangeSet
+	for name, old := range a {
+		value, ok := b[name]
+		switch {
+		case !ok:
+			c.Removed = append(c.Removed, Change{Name: name, Old: old})
+		case value != old:
+			c.Changed = append(c.Changed, Change{Name: name, Old: old, New: value})
+		}
+	}
+	for name, value := range b {
+		if _, ok := a[name]; !ok {
+			c.Added = append(c.Added, Change{Name: name, New: value})
+		}
+	}
+	for _, changes := range [][]Change{c.Added, c.Removed, c.Changed} {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	}
+	return c
+}
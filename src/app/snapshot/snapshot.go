@@ -0,0 +1,207 @@
+// Package snapshot captures the installed packages, environment variables
+// and services of the machine so two points in time can be compared
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EnvSnapshotDir overrides the directory snapshots are stored in
+const EnvSnapshotDir = "PORTUNIX_SNAPSHOT_DIR"
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Snapshot is the state of the machine at one point in time
+type Snapshot struct {
+	Name      string            `json:"name"`
+	CreatedAt time.Time         `json:"created_at"`
+	Hostname  string            `json:"hostname"`
+	OS        string            `json:"os"`
+	Packages  []Package         `json:"packages"`
+	Env       map[string]string `json:"env"`
+	Services  []Service         `json:"services"`
+}
+
+// Package is an installed package
+type Package struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Source is the package database: portunix, dpkg, rpm, pacman, brew,
+	// brew-cask or windows
+	Source string `json:"source"`
+}
+
+// Key identifies the package across snapshots
+func (p Package) Key() string {
+	return p.Source + "/" + p.Name
+}
+
+// Service is a system service and its state
+type Service struct {
+	Name    string `json:"name"`
+	State   string `json:"state"`   // running, stopped, failed, ...
+	Manager string `json:"manager"` // systemd, launchd or windows
+}
+
+// Dir returns the directory holding saved snapshots
+func Dir() string {
+	if dir := os.Getenv(EnvSnapshotDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-snapshots")
+	}
+	return filepath.Join(home, ".portunix", "snapshots")
+}
+
+func snapshotPath(name string) string {
+	return filepath.Join(Dir(), name+".json")
+}
+
+// ValidateName checks that name is usable as a snapshot file name
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q: use letters, digits, dots, dashes and underscores", name)
+	}
+	return nil
+}
+
+// Capture records the current state of the machine. Package databases
+// and service managers that are not available are skipped.
+func Capture(name string) (*Snapshot, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	hostname, _ := os.Hostname()
+	s := &Snapshot{
+		Name:      name,
+		CreatedAt: time.Now(),
+		Hostname:  hostname,
+		OS:        runtime.GOOS + "/" + runtime.GOARCH,
+		Env:       captureEnv(os.Environ()),
+	}
+	s.Packages = append(s.Packages, portunixPackages()...)
+	s.Packages = append(s.Packages, systemPackages()...)
+	sort.Slice(s.Packages, func(i, j int) bool { return s.Packages[i].Key() < s.Packages[j].Key() })
+	s.Services = systemServices()
+	sort.Slice(s.Services, func(i, j int) bool { return s.Services[i].Name < s.Services[j].Name })
+	return s, nil
+}
+
+// Save stores the snapshot; an existing snapshot of the same name is only
+// replaced when force is set
+func (s *Snapshot) Save(force bool) error {
+	if err := ValidateName(s.Name); err != nil {
+		return err
+	}
+	path := snapshotPath(s.Name)
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("snapshot %q already exists (use --force to replace it)", s.Name)
+	}
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Environment variables may reveal local paths and accounts
+	return os.WriteFile(path, data, 0600)
+}
+
+// Load reads a saved snapshot
+func Load(name string) (*Snapshot, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(snapshotPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("snapshot %q not found", name)
+		}
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", name, err)
+	}
+	return &s, nil
+}
+
+// List returns the saved snapshots, oldest first
+func List() ([]*Snapshot, error) {
+	entries, err := os.ReadDir(Dir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []*Snapshot
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || name == entry.Name() {
+			continue
+		}
+		if s, err := Load(name); err == nil {
+			snapshots = append(snapshots, s)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt) })
+	return snapshots, nil
+}
+
+// Delete removes a saved snapshot
+func Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if err := os.Remove(snapshotPath(name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("snapshot %q not found", name)
+		}
+		return err
+	}
+	return nil
+}
+
+// volatileEnv are variables that change between shells without any change
+// to the machine
+var volatileEnv = map[string]bool{
+	"PWD": true, "OLDPWD": true, "SHLVL": true, "_": true, "TERM_SESSION_ID": true,
+	"SSH_CLIENT": true, "SSH_CONNECTION": true, "SSH_TTY": true, "SSH_AUTH_SOCK": true,
+	"WINDOWID": true, "XDG_SESSION_ID": true, "COLUMNS": true, "LINES": true,
+	"SECURITYSESSIONID": true, "TMPDIR": true, "RANDOM": true,
+}
+
+// secretEnv matches variable names whose values must not be stored
+var secretEnv = regexp.MustCompile(`(?i)(TOKEN|SECRET|PASSWORD|PASSWD|CREDENTIAL|API_?KEY|PRIVATE_?KEY|ACCESS_?KEY)`)
+
+// captureEnv records the environment. Values of secret looking variables
+// are replaced by a hash, so changes are still detected.
+func captureEnv(environ []string) map[string]string {
+	env := make(map[string]string)
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || name == "" || volatileEnv[name] {
+			continue
+		}
+		if secretEnv.MatchString(name) {
+			sum := sha256.Sum256([]byte(value))
+			value = "sha256:" + hex.EncodeToString(sum[:])[:12]
+		}
+		env[name] = value
+	}
+	return env
+}
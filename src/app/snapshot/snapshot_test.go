@@ -0,0 +1,100 @@
+package snapshot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCaptureEnv(t *testing.T) {
+	env := captureEnv([]string{"PATH=/usr/bin", "PWD=/tmp", "GITHUB_TOKEN=ghp_secret", "=C:=C:\\", "EMPTY="})
+	if env["PATH"] != "/usr/bin" || env["EMPTY"] != "" {
+		t.Errorf("regular variables should be kept: %v", env)
+	}
+	if _, ok := env["PWD"]; ok {
+		t.Error("volatile variables should be skipped")
+	}
+	if token := env["GITHUB_TOKEN"]; !strings.HasPrefix(token, "sha256:") || strings.Contains(token, "ghp_secret") {
+		t.Errorf("secrets must be hashed, got %q", token)
+	}
+	if len(env) != 3 {
+		t.Errorf("unexpected variables %v", env)
+	}
+}
+
+func TestParsers(t *testing.T) {
+	dpkg := parseDpkg("installed\tgit\t1:2.39.2-1\nconfig-files\told\t1.0\ninstalled\tcurl\t7.88.1\n", "dpkg")
+	if len(dpkg) != 2 || dpkg[0].Version != "1:2.39.2-1" || dpkg[1].Name != "curl" {
+		t.Errorf("unexpected dpkg packages %+v", dpkg)
+	}
+	brew := parseFields("go 1.21.5 1.22.0\njq 1.7.1\n", "brew")
+	if len(brew) != 2 || brew[0].Version != "1.22.0" {
+		t.Errorf("unexpected brew packages %+v", brew)
+	}
+	windows := parseWindowsPackages(`{"DisplayName":"Git","DisplayVersion":"2.43.0"}`, "windows")
+	if len(windows) != 1 || windows[0].Name != "Git" {
+		t.Errorf("unexpected windows packages %+v", windows)
+	}
+
+	services := parseSystemctl("ssh.service loaded active running OpenBSD Secure Shell server\n" +
+		"cron.service loaded inactive dead Regular background program processing daemon\n" +
+		"broken.service loaded failed failed Broken\n" +
+		"gone.service not-found inactive dead gone.service\n")
+	if len(services) != 3 || services[0].State != "running" || services[1].State != "stopped" || services[2].State != "failed" {
+		t.Errorf("unexpected systemd services %+v", services)
+	}
+	launchd := parseLaunchctl("PID\tStatus\tLabel\n412\t0\tcom.apple.Finder\n-\t0\tcom.apple.idle\n-\t78\tcom.example.crash\n")
+	if len(launchd) != 3 || launchd[0].State != "running" || launchd[1].State != "stopped" || launchd[2].State != "failed" {
+		t.Errorf("unexpected launchd services %+v", launchd)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before := &Snapshot{
+		Name:     "before",
+		Packages: []Package{{Name: "git", Version: "2.39", Source: "dpkg"}, {Name: "vim", Version: "9.0", Source: "dpkg"}},
+		Env:      map[string]string{"PATH": "/usr/bin", "OLD": "1"},
+		Services: []Service{{Name: "docker", State: "stopped"}},
+	}
+	after := &Snapshot{
+		Name:     "after",
+		Packages: []Package{{Name: "git", Version: "2.43", Source: "dpkg"}, {Name: "go", Version: "1.22.0", Source: "portunix"}},
+		Env:      map[string]string{"PATH": "/usr/local/go/bin:/usr/bin", "GOPATH": "/home/dev/go"},
+		Services: []Service{{Name: "docker", State: "running"}},
+	}
+	d := Compare(before, after)
+	if len(d.Packages.Added) != 1 || d.Packages.Added[0].Name != "portunix/go" ||
+		len(d.Packages.Removed) != 1 || d.Packages.Removed[0].Name != "dpkg/vim" ||
+		len(d.Packages.Changed) != 1 || d.Packages.Changed[0].New != "2.43" {
+		t.Errorf("unexpected package changes %+v", d.Packages)
+	}
+	if d.Env.Count() != 3 || d.Services.Count() != 1 || d.Services.Changed[0].New != "running" {
+		t.Errorf("unexpected changes %+v %+v", d.Env, d.Services)
+	}
+	if !Compare(before, before).Empty() {
+		t.Error("a snapshot compared with itself should have no changes")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Setenv(EnvSnapshotDir, t.TempDir())
+	s := &Snapshot{Name: "base", Env: map[string]string{"A": "1"}}
+	if err := s.Save(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(false); err == nil {
+		t.Error("saving over an existing snapshot needs force")
+	}
+	loaded, err := Load("base")
+	if err != nil || loaded.Env["A"] != "1" {
+		t.Fatalf("load failed: %v %+v", err, loaded)
+	}
+	if list, _ := List(); len(list) != 1 {
+		t.Errorf("expected one snapshot, got %d", len(list))
+	}
+	if err := Delete("base"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load("../etc/passwd"); err == nil {
+		t.Error("path-like names must be rejected")
+	}
+}
@@ -0,0 +1,98 @@
+package svcmgr
+
+import (
+	"fmt"
+	"os"
+
+	"portunix.ai/app/launchd"
+)
+
+// Launchd manages per-user launchd agents. Oneshot services have no stop
+// hook in launchd, so Stop runs their stop command itself.
+type Launchd struct{}
+
+// Name implements Manager
+func (m *Launchd) Name() string { return "launchd" }
+
+// Unit implements Manager
+func (m *Launchd) Unit(name string) string { return "ai.portunix." + name }
+
+// Agent returns the launchd agent of a service
+func (m *Launchd) Agent(s *Service, executable string) *launchd.Agent {
+	a := &launchd.Agent{
+		Label:             m.Unit(s.Name),
+		ProgramArguments:  append([]string{executable}, s.Args...),
+		RunAtLoad:         true,
+		WorkingDirectory:  s.WorkDir,
+		StandardOutPath:   LogPath(s.Name),
+		StandardErrorPath: LogPath(s.Name),
+	}
+	switch {
+	case s.Interval > 0:
+		a.StartInterval = int(s.Interval.Seconds())
+	case len(s.StopArgs) == 0:
+		a.KeepAlive = true
+	}
+	return a
+}
+
+// Install implements Manager. launchd starts agents as it loads them, so
+// the service is loaded at the next login or by Start.
+func (m *Launchd) Install(s *Service, executable string) error {
+	if err := os.MkdirAll(logDir(), 0755); err != nil {
+		return err
+	}
+	a := m.Agent(s, executable)
+	launchd.Stop(a.Label)
+	if err := os.MkdirAll(launchd.AgentsDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(launchd.PlistPath(a.Label), a.Plist(), 0644)
+}
+
+// Uninstall implements Manager
+func (m *Launchd) Uninstall(name string) error {
+	if !m.installed(name) {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	m.Stop(name)
+	return launchd.Remove(m.Unit(name))
+}
+
+// Start implements Manager
+func (m *Launchd) Start(name string) error {
+	if !m.installed(name) {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	return launchd.Start(m.Unit(name))
+}
+
+// Stop implements Manager
+func (m *Launchd) Stop(name string) error {
+	if !m.installed(name) {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	if err := launchd.Stop(m.Unit(name)); err != nil {
+		return err
+	}
+	return runStop(name)
+}
+
+// Status implements Manager
+func (m *Launchd) Status(name string) (*Status, error) {
+	st := &Status{Name: name, Manager: m.Name(), Unit: m.Unit(name), Installed: m.installed(name)}
+	if !st.Installed {
+		return st, nil
+	}
+	st.State = launchd.State(m.Unit(name))
+	if st.State == "" {
+		st.State = "not loaded"
+	}
+	st.Running = st.State == "running"
+	return st, nil
+}
+
+func (m *Launchd) installed(name string) bool {
+	_, err := os.Stat(launchd.PlistPath(m.Unit(name)))
+	return err == nil
+}
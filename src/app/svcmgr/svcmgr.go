@@ -0,0 +1,182 @@
+// Package svcmgr installs the background pieces of portunix (the gRPC
+// daemon, the vulnerability watcher, periodic PFT sync, edge services) as
+// services of the user with the service manager of the OS: systemd user
+// units on Linux, launchd agents on macOS and Task Scheduler logon tasks on
+// Windows. They run as the user, start at login and are restarted when
+// they fail.
+package svcmgr
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Service is a portunix command run in the background by the service
+// manager
+type Service struct {
+	// Name identifies the service in portunix commands, e.g. "daemon"
+	Name        string
+	Description string
+	// Args are the arguments of the portunix executable
+	Args []string
+	// Interval runs Args periodically instead of keeping them running
+	Interval time.Duration
+	// StopArgs make a oneshot service: Args run at start and return,
+	// StopArgs run at stop
+	StopArgs []string
+	// WorkDir is the working directory of the commands
+	WorkDir string
+}
+
+// Kind describes how the service runs
+func (s *Service) Kind() string {
+	switch {
+	case s.Interval > 0:
+		return "every " + s.Interval.String()
+	case len(s.StopArgs) > 0:
+		return "oneshot"
+	default:
+		return "long-running"
+	}
+}
+
+// Status is the state of an installed service
+type Status struct {
+	Name      string `json:"name"`
+	Manager   string `json:"manager"`
+	Unit      string `json:"unit"`
+	Installed bool   `json:"installed"`
+	Running   bool   `json:"running"`
+	// State is the state reported by the service manager, e.g. "active"
+	State string `json:"state,omitempty"`
+}
+
+// Manager is the service manager of one OS
+type Manager interface {
+	// Name is the service manager, e.g. "systemd"
+	Name() string
+	// Unit returns the name of the service in the service manager
+	Unit(name string) string
+	// Install registers the service to start at login, replacing an
+	// earlier version; it does not start it
+	Install(s *Service, executable string) error
+	Uninstall(name string) error
+	Start(name string) error
+	Stop(name string) error
+	Status(name string) (*Status, error)
+}
+
+// Components are the background pieces of portunix that can be installed
+// as services
+var Components = map[string]Service{
+	"daemon": {
+		Name:        "daemon",
+		Description: "Portunix gRPC daemon for IDE plugins and the MCP server",
+		Args:        []string{"daemon", "start", "--foreground"},
+	},
+	"vuln-watch": {
+		Name:        "vuln-watch",
+		Description: "Portunix vulnerability watcher",
+		Args:        []string{"vuln", "watch"},
+	},
+	"pft-sync": {
+		Name:        "pft-sync",
+		Description: "Portunix PFT project synchronization",
+		Args:        []string{"pft", "sync"},
+		Interval:    time.Hour,
+	},
+	"edge": {
+		Name:        "edge",
+		Description: "Portunix edge reverse proxy, VPN and security services",
+		Args:        []string{"edge", "start"},
+		StopArgs:    []string{"edge", "stop"},
+	},
+}
+
+// Lookup returns a copy of the component name
+func Lookup(name string) (*Service, bool) {
+	s, ok := Components[name]
+	return &s, ok
+}
+
+// Names returns the component names, sorted
+func Names() []string {
+	names := make([]string, 0, len(Components))
+	for name := range Components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Detect returns the service manager of the running OS
+func Detect() (Manager, error) {
+	switch runtime.GOOS {
+	case "linux":
+		if !systemdUserAvailable() {
+			return nil, fmt.Errorf("systemd user instance not available (on WSL enable systemd in /etc/wsl.conf)")
+		}
+		return &Systemd{Dir: systemdUserDir()}, nil
+	case "darwin":
+		return &Launchd{}, nil
+	case "windows":
+		return &TaskScheduler{}, nil
+	default:
+		return nil, fmt.Errorf("no service manager supported on %s", runtime.GOOS)
+	}
+}
+
+// runStop runs the stop command of a oneshot component, for service
+// managers without a stop hook
+func runStop(name string) error {
+	s, ok := Lookup(name)
+	if !ok || len(s.StopArgs) == 0 {
+		return nil
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if out, err := exec.Command(self, s.StopArgs...).CombinedOutput(); err != nil {
+		return fmt.Errorf("portunix %s failed: %v: %s", strings.Join(s.StopArgs, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// logDir returns the directory for service output where the service
+// manager keeps none
+func logDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.TempDir()
+	}
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, "Library", "Logs", "portunix")
+	}
+	return filepath.Join(home, ".portunix", "logs")
+}
+
+// LogPath returns the file receiving the output of a service on launchd
+// and Task Scheduler; systemd keeps it in the journal
+func LogPath(name string) string {
+	return filepath.Join(logDir(), name+".log")
+}
+
+// commandLine joins a command for service files that split arguments on
+// spaces and honor double quotes
+func commandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"") {
+			arg = `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
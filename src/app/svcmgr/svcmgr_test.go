@@ -0,0 +1,116 @@
+package svcmgr
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSystemdUnitFiles(t *testing.T) {
+	m := &Systemd{Dir: t.TempDir()}
+	exe := "/opt/portunix bin/portunix"
+
+	daemon, _ := Lookup("daemon")
+	files := m.UnitFiles(daemon, exe)
+	unit := files["portunix-daemon.service"]
+	for _, want := range []string{
+		`ExecStart="/opt/portunix bin/portunix" daemon start --foreground`,
+		"Type=simple",
+		"Restart=on-failure",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("daemon unit lacks %q:\n%s", want, unit)
+		}
+	}
+	if len(files) != 1 {
+		t.Errorf("long-running service should have no timer: %v", files)
+	}
+
+	sync, _ := Lookup("pft-sync")
+	sync.Interval = 30 * time.Minute
+	sync.WorkDir = "/home/dev/100%project"
+	files = m.UnitFiles(sync, exe)
+	if unit := files["portunix-pft-sync.service"]; !strings.Contains(unit, "Type=oneshot") || strings.Contains(unit, "[Install]") ||
+		!strings.Contains(unit, "WorkingDirectory=/home/dev/100%%project") {
+		t.Errorf("unexpected periodic unit:\n%s", unit)
+	}
+	if timer := files["portunix-pft-sync.timer"]; !strings.Contains(timer, "OnUnitActiveSec=1800s") || !strings.Contains(timer, "WantedBy=timers.target") {
+		t.Errorf("unexpected timer:\n%s", timer)
+	}
+
+	edge, _ := Lookup("edge")
+	unit = m.UnitFiles(edge, exe)["portunix-edge.service"]
+	if !strings.Contains(unit, "RemainAfterExit=yes") || !strings.Contains(unit, "ExecStop=\"/opt/portunix bin/portunix\" edge stop") {
+		t.Errorf("unexpected oneshot unit:\n%s", unit)
+	}
+}
+
+func TestLaunchdAgent(t *testing.T) {
+	m := &Launchd{}
+	daemon, _ := Lookup("daemon")
+	a := m.Agent(daemon, "/usr/local/bin/portunix")
+	if a.Label != "ai.portunix.daemon" || !a.RunAtLoad || !a.KeepAlive || a.StartInterval != 0 {
+		t.Errorf("unexpected daemon agent %+v", a)
+	}
+	if strings.Join(a.ProgramArguments, " ") != "/usr/local/bin/portunix daemon start --foreground" {
+		t.Errorf("unexpected arguments %v", a.ProgramArguments)
+	}
+
+	sync, _ := Lookup("pft-sync")
+	if a := m.Agent(sync, "portunix"); a.KeepAlive || a.StartInterval != 3600 {
+		t.Errorf("unexpected periodic agent %+v", a)
+	}
+	edge, _ := Lookup("edge")
+	if a := m.Agent(edge, "portunix"); a.KeepAlive || a.StartInterval != 0 || !a.RunAtLoad {
+		t.Errorf("unexpected oneshot agent %+v", a)
+	}
+}
+
+func TestTaskXML(t *testing.T) {
+	m := &TaskScheduler{}
+	exe := `C:\Program Files\Portunix\portunix.exe`
+
+	daemon, _ := Lookup("daemon")
+	task := m.TaskXML(daemon, exe, `CORP\dev`)
+	for _, want := range []string{
+		"<LogonTrigger>",
+		`<UserId>CORP\dev</UserId>`,
+		"<LogonType>S4U</LogonType>",
+		"<ExecutionTimeLimit>PT0S</ExecutionTimeLimit>",
+		"<RestartOnFailure>",
+		`<Arguments>/c &#34;&#34;C:\Program Files\Portunix\portunix.exe&#34; daemon start --foreground &gt;&gt; `,
+	} {
+		if !strings.Contains(task, want) {
+			t.Errorf("daemon task lacks %q:\n%s", want, task)
+		}
+	}
+
+	sync, _ := Lookup("pft-sync")
+	task = m.TaskXML(sync, exe, `CORP\dev`)
+	if !strings.Contains(task, "<Interval>PT60M</Interval>") || strings.Contains(task, "<LogonTrigger>") || strings.Contains(task, "<RestartOnFailure>") {
+		t.Errorf("unexpected periodic task:\n%s", task)
+	}
+
+	data := encodeUTF16("<a/>")
+	if len(data) != 10 || data[0] != 0xFF || data[1] != 0xFE || data[2] != '<' || data[3] != 0 {
+		t.Errorf("unexpected UTF-16 encoding % x", data)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	s, ok := Lookup("pft-sync")
+	if !ok || s.Kind() != "every 1h0m0s" {
+		t.Fatalf("unexpected pft-sync %+v", s)
+	}
+	s.Interval = time.Minute
+	if Components["pft-sync"].Interval != time.Hour {
+		t.Error("Lookup should return a copy")
+	}
+	if _, ok := Lookup("unknown"); ok {
+		t.Error("unknown component should not be found")
+	}
+	if names := strings.Join(Names(), ","); names != "daemon,edge,pft-sync,vuln-watch" {
+		t.Errorf("unexpected names %s", names)
+	}
+}
@@ -0,0 +1,193 @@
+package svcmgr
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Systemd manages user units of systemd. Periodic services get a timer.
+type Systemd struct {
+	// Dir holds the unit files, usually ~/.config/systemd/user
+	Dir string
+}
+
+// Name implements Manager
+func (m *Systemd) Name() string { return "systemd" }
+
+// Unit implements Manager
+func (m *Systemd) Unit(name string) string { return "portunix-" + name + ".service" }
+
+func (m *Systemd) timer(name string) string { return "portunix-" + name + ".timer" }
+
+// UnitFiles renders the unit files of a service, keyed by file name
+func (m *Systemd) UnitFiles(s *Service, executable string) map[string]string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Unit]\nDescription=%s\n", s.Description)
+	b.WriteString("Wants=network-online.target\nAfter=network-online.target\n\n[Service]\n")
+	start := systemdEscape(commandLine(append([]string{executable}, s.Args...)))
+	switch {
+	case s.Interval > 0:
+		fmt.Fprintf(&b, "Type=oneshot\nExecStart=%s\n", start)
+	case len(s.StopArgs) > 0:
+		fmt.Fprintf(&b, "Type=oneshot\nRemainAfterExit=yes\nExecStart=%s\n", start)
+		fmt.Fprintf(&b, "ExecStop=%s\n", systemdEscape(commandLine(append([]string{executable}, s.StopArgs...))))
+	default:
+		fmt.Fprintf(&b, "Type=simple\nExecStart=%s\nRestart=on-failure\nRestartSec=10\n", start)
+	}
+	if s.WorkDir != "" {
+		fmt.Fprintf(&b, "WorkingDirectory=%s\n", systemdEscape(s.WorkDir))
+	}
+	files := make(map[string]string)
+	if s.Interval > 0 {
+		files[m.Unit(s.Name)] = b.String()
+		files[m.timer(s.Name)] = fmt.Sprintf(`[Unit]
+Description=%s (every %s)
+
+[Timer]
+OnBootSec=2min
+OnUnitActiveSec=%ds
+Persistent=true
+
+[Install]
+WantedBy=timers.target
+`, s.Description, s.Interval, int(s.Interval.Seconds()))
+		return files
+	}
+	b.WriteString("\n[Install]\nWantedBy=default.target\n")
+	files[m.Unit(s.Name)] = b.String()
+	return files
+}
+
+// Install implements Manager
+func (m *Systemd) Install(s *Service, executable string) error {
+	if err := os.MkdirAll(m.Dir, 0755); err != nil {
+		return err
+	}
+	// A service switched between periodic and not leaves no stale timer
+	if s.Interval == 0 && m.hasTimer(s.Name) {
+		systemctl("disable", "--now", m.timer(s.Name))
+		os.Remove(filepath.Join(m.Dir, m.timer(s.Name)))
+	}
+	for file, content := range m.UnitFiles(s, executable) {
+		if err := os.WriteFile(filepath.Join(m.Dir, file), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	if err := systemctl("daemon-reload"); err != nil {
+		return err
+	}
+	return systemctl("enable", m.enabled(s.Name, s.Interval > 0))
+}
+
+// Uninstall implements Manager
+func (m *Systemd) Uninstall(name string) error {
+	if !m.installed(name) {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	timer := m.hasTimer(name)
+	systemctl("disable", "--now", m.enabled(name, timer))
+	if timer {
+		systemctl("stop", m.Unit(name))
+		os.Remove(filepath.Join(m.Dir, m.timer(name)))
+	}
+	if err := os.Remove(filepath.Join(m.Dir, m.Unit(name))); err != nil {
+		return err
+	}
+	return systemctl("daemon-reload")
+}
+
+// Start implements Manager. Periodic services start their timer and run
+// once right away.
+func (m *Systemd) Start(name string) error {
+	if !m.installed(name) {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	if m.hasTimer(name) {
+		if err := systemctl("start", m.timer(name)); err != nil {
+			return err
+		}
+		return systemctl("start", "--no-block", m.Unit(name))
+	}
+	return systemctl("start", m.Unit(name))
+}
+
+// Stop implements Manager
+func (m *Systemd) Stop(name string) error {
+	if !m.installed(name) {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	if m.hasTimer(name) {
+		if err := systemctl("stop", m.timer(name)); err != nil {
+			return err
+		}
+	}
+	return systemctl("stop", m.Unit(name))
+}
+
+// Status implements Manager. Periodic services report their timer.
+func (m *Systemd) Status(name string) (*Status, error) {
+	st := &Status{Name: name, Manager: m.Name(), Unit: m.Unit(name), Installed: m.installed(name)}
+	if !st.Installed {
+		return st, nil
+	}
+	unit := m.Unit(name)
+	if m.hasTimer(name) {
+		unit = m.timer(name)
+		st.Unit = unit
+	}
+	// is-active exits non-zero for inactive units but still prints the state
+	out, _ := exec.Command("systemctl", "--user", "is-active", unit).Output()
+	st.State = strings.TrimSpace(string(out))
+	st.Running = st.State == "active"
+	return st, nil
+}
+
+func (m *Systemd) installed(name string) bool {
+	_, err := os.Stat(filepath.Join(m.Dir, m.Unit(name)))
+	return err == nil
+}
+
+func (m *Systemd) hasTimer(name string) bool {
+	_, err := os.Stat(filepath.Join(m.Dir, m.timer(name)))
+	return err == nil
+}
+
+// enabled returns the unit enabled at login: the timer of periodic
+// services, the service otherwise
+func (m *Systemd) enabled(name string, timer bool) string {
+	if timer {
+		return m.timer(name)
+	}
+	return m.Unit(name)
+}
+
+func systemctl(args ...string) error {
+	args = append([]string{"--user"}, args...)
+	if out, err := exec.Command("systemctl", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("systemctl %s failed: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// systemdEscape escapes the specifier and variable expansion of unit files
+func systemdEscape(s string) string {
+	return strings.NewReplacer("%", "%%", "$", "$$").Replace(s)
+}
+
+func systemdUserAvailable() bool {
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return false
+	}
+	return exec.Command("systemctl", "--user", "show-environment").Run() == nil
+}
+
+func systemdUserDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "systemd", "user")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "systemd", "user")
+}
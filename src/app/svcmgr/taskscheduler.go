@@ -0,0 +1,192 @@
+package svcmgr
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// TaskScheduler manages tasks of the Windows Task Scheduler in the
+// \Portunix folder. Windows services need a binary speaking the service
+// control protocol and an administrator to install them; logon tasks run
+// as the user like systemd user units and launchd agents, without a
+// console window and without the 72 hour limit of tasks created by
+// schtasks flags.
+type TaskScheduler struct{}
+
+// Name implements Manager
+func (m *TaskScheduler) Name() string { return "taskscheduler" }
+
+// Unit implements Manager
+func (m *TaskScheduler) Unit(name string) string { return `\Portunix\` + name }
+
+// TaskXML renders the task definition of a service for the user
+func (m *TaskScheduler) TaskXML(s *Service, executable, userID string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-16"?>
+<Task version="1.2" xmlns="http://schemas.microsoft.com/windows/2004/02/mit/task">
+  <RegistrationInfo>
+`)
+	fmt.Fprintf(&b, "    <Description>%s</Description>\n", xmlEscape(s.Description))
+	b.WriteString("  </RegistrationInfo>\n  <Triggers>\n")
+	if s.Interval > 0 {
+		fmt.Fprintf(&b, `    <TimeTrigger>
+      <StartBoundary>%s</StartBoundary>
+      <Repetition>
+        <Interval>PT%dM</Interval>
+      </Repetition>
+      <Enabled>true</Enabled>
+    </TimeTrigger>
+`, time.Now().Truncate(time.Hour).Format("2006-01-02T15:04:05"), int(s.Interval.Minutes()))
+	} else {
+		fmt.Fprintf(&b, `    <LogonTrigger>
+      <Enabled>true</Enabled>
+      <UserId>%s</UserId>
+    </LogonTrigger>
+`, xmlEscape(userID))
+	}
+	fmt.Fprintf(&b, `  </Triggers>
+  <Principals>
+    <Principal id="Author">
+      <UserId>%s</UserId>
+      <LogonType>S4U</LogonType>
+      <RunLevel>LeastPrivilege</RunLevel>
+    </Principal>
+  </Principals>
+  <Settings>
+    <MultipleInstancesPolicy>IgnoreNew</MultipleInstancesPolicy>
+    <DisallowStartIfOnBatteries>false</DisallowStartIfOnBatteries>
+    <StopIfGoingOnBatteries>false</StopIfGoingOnBatteries>
+    <StartWhenAvailable>true</StartWhenAvailable>
+    <ExecutionTimeLimit>PT0S</ExecutionTimeLimit>
+    <Hidden>true</Hidden>
+`, xmlEscape(userID))
+	if s.Interval == 0 && len(s.StopArgs) == 0 {
+		b.WriteString(`    <RestartOnFailure>
+      <Interval>PT1M</Interval>
+      <Count>999</Count>
+    </RestartOnFailure>
+`)
+	}
+	// cmd.exe redirects the output, which Task Scheduler discards
+	command := commandLine(append([]string{executable}, s.Args...))
+	arguments := fmt.Sprintf(`/c "%s >> "%s" 2>&1"`, command, LogPath(s.Name))
+	fmt.Fprintf(&b, `  </Settings>
+  <Actions Context="Author">
+    <Exec>
+      <Command>cmd.exe</Command>
+      <Arguments>%s</Arguments>
+`, xmlEscape(arguments))
+	if s.WorkDir != "" {
+		fmt.Fprintf(&b, "      <WorkingDirectory>%s</WorkingDirectory>\n", xmlEscape(s.WorkDir))
+	}
+	b.WriteString("    </Exec>\n  </Actions>\n</Task>\n")
+	return b.String()
+}
+
+// Install implements Manager
+func (m *TaskScheduler) Install(s *Service, executable string) error {
+	current, err := user.Current()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(logDir(), 0755); err != nil {
+		return err
+	}
+	// schtasks reads task files in UTF-16
+	file, err := os.CreateTemp("", "portunix-task-*.xml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(encodeUTF16(m.TaskXML(s, executable, current.Username)))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return schtasks("/Create", "/F", "/TN", m.Unit(s.Name), "/XML", file.Name())
+}
+
+// Uninstall implements Manager
+func (m *TaskScheduler) Uninstall(name string) error {
+	if !m.installed(name) {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	m.Stop(name)
+	return schtasks("/Delete", "/F", "/TN", m.Unit(name))
+}
+
+// Start implements Manager
+func (m *TaskScheduler) Start(name string) error {
+	if !m.installed(name) {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	return schtasks("/Run", "/TN", m.Unit(name))
+}
+
+// Stop implements Manager
+func (m *TaskScheduler) Stop(name string) error {
+	if !m.installed(name) {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	// /End fails for tasks that are not running
+	if st, _ := m.Status(name); st != nil && st.Running {
+		if err := schtasks("/End", "/TN", m.Unit(name)); err != nil {
+			return err
+		}
+	}
+	return runStop(name)
+}
+
+// Status implements Manager
+func (m *TaskScheduler) Status(name string) (*Status, error) {
+	st := &Status{Name: name, Manager: m.Name(), Unit: m.Unit(name)}
+	out, err := exec.Command("schtasks", "/Query", "/TN", m.Unit(name), "/FO", "CSV", "/NH").Output()
+	if err != nil {
+		return st, nil
+	}
+	st.Installed = true
+	// "\Portunix\daemon","N/A","Running"
+	records, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+	if err == nil && len(records) > 0 && len(records[0]) >= 3 {
+		st.State = records[0][2]
+	}
+	st.Running = st.State == "Running"
+	return st, nil
+}
+
+func (m *TaskScheduler) installed(name string) bool {
+	return exec.Command("schtasks", "/Query", "/TN", m.Unit(name)).Run() == nil
+}
+
+func schtasks(args ...string) error {
+	if out, err := exec.Command("schtasks", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("schtasks %s failed: %v: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// encodeUTF16 encodes s as UTF-16LE with a byte order mark
+func encodeUTF16(s string) []byte {
+	units := utf16.Encode([]rune("\uFEFF" + s))
+	data := make([]byte, 0, len(units)*2)
+	for _, u := range units {
+		data = append(data, byte(u), byte(u>>8))
+	}
+	return data
+}
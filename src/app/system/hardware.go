@@ -0,0 +1,433 @@
+package system
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// HardwareInfo contains the CPU, memory, disk and GPU inventory
+type HardwareInfo struct {
+	CPU    CPUInfo    `json:"cpu"`
+	Memory MemoryInfo `json:"memory"`
+	Disks  []DiskInfo `json:"disks"`
+	GPUs   []GPUInfo  `json:"gpus"`
+}
+
+// CPUInfo describes the processor
+type CPUInfo struct {
+	Model   string `json:"model"`
+	Vendor  string `json:"vendor"`
+	Cores   int    `json:"cores"`   // physical cores
+	Threads int    `json:"threads"` // logical processors
+}
+
+// MemoryInfo describes the physical memory
+type MemoryInfo struct {
+	TotalBytes     uint64 `json:"total_bytes"`
+	AvailableBytes uint64 `json:"available_bytes"`
+}
+
+// DiskInfo describes a mounted local filesystem
+type DiskInfo struct {
+	Mount      string `json:"mount"`
+	Device     string `json:"device,omitempty"`
+	Filesystem string `json:"filesystem,omitempty"`
+	TotalBytes uint64 `json:"total_bytes"`
+	FreeBytes  uint64 `json:"free_bytes"`
+}
+
+// GPUInfo describes a graphics adapter
+type GPUInfo struct {
+	Vendor        string `json:"vendor"` // NVIDIA, AMD, Intel, Apple or as reported
+	Model         string `json:"model"`
+	Driver        string `json:"driver,omitempty"`
+	DriverVersion string `json:"driver_version,omitempty"`
+}
+
+// CollectHardware adds the hardware inventory and the container readiness
+// assessment to info. It runs several external tools, so it is separate
+// from GetSystemInfo, which is called on many hot paths.
+func CollectHardware(info *SystemInfo) {
+	hw := &HardwareInfo{}
+	switch runtime.GOOS {
+	case "linux":
+		collectLinuxHardware(hw)
+	case "darwin":
+		collectMacOSHardware(hw)
+	case "windows":
+		collectWindowsHardware(hw)
+	}
+	if hw.CPU.Threads == 0 {
+		hw.CPU.Threads = runtime.NumCPU()
+	}
+	if hw.CPU.Cores == 0 {
+		hw.CPU.Cores = hw.CPU.Threads
+	}
+	addNvidiaDriverVersions(hw.GPUs)
+	info.Hardware = hw
+
+	info.Capabilities.ContainerReadiness = assessContainerReadiness(info, detectKernelFeatures())
+}
+
+// collectLinuxHardware reads /proc and sysfs
+func collectLinuxHardware(hw *HardwareInfo) {
+	if data, err := os.ReadFile("/proc/cpuinfo"); err == nil {
+		hw.CPU = parseCPUInfo(string(data))
+	}
+	if data, err := os.ReadFile("/proc/meminfo"); err == nil {
+		hw.Memory = parseMeminfo(string(data))
+	}
+	if output, err := exec.Command("df", "-kPT").Output(); err == nil {
+		hw.Disks = parseDF(string(output), true)
+	}
+	if output, err := exec.Command("lspci", "-mm").Output(); err == nil {
+		for _, device := range parseLspci(string(output)) {
+			gpu := device.GPUInfo
+			if link, err := os.Readlink(filepath.Join("/sys/bus/pci/devices", "0000:"+device.slot, "driver")); err == nil {
+				gpu.Driver = filepath.Base(link)
+				if version, err := os.ReadFile(filepath.Join("/sys/module", gpu.Driver, "version")); err == nil {
+					gpu.DriverVersion = strings.TrimSpace(string(version))
+				}
+			}
+			hw.GPUs = append(hw.GPUs, gpu)
+		}
+	}
+}
+
+// collectMacOSHardware uses sysctl, vm_stat and system_profiler
+func collectMacOSHardware(hw *HardwareInfo) {
+	hw.CPU.Model = sysctlValue("machdep.cpu.brand_string")
+	hw.CPU.Vendor = cpuVendor(hw.CPU.Model)
+	hw.CPU.Cores, _ = strconv.Atoi(sysctlValue("hw.physicalcpu"))
+	hw.CPU.Threads, _ = strconv.Atoi(sysctlValue("hw.logicalcpu"))
+
+	hw.Memory.TotalBytes, _ = strconv.ParseUint(sysctlValue("hw.memsize"), 10, 64)
+	if output, err := exec.Command("vm_stat").Output(); err == nil {
+		hw.Memory.AvailableBytes = parseVMStat(string(output))
+	}
+
+	if output, err := exec.Command("df", "-kP").Output(); err == nil {
+		hw.Disks = parseDF(string(output), false)
+	}
+
+	if output, err := exec.Command("system_profiler", "SPDisplaysDataType", "-json").Output(); err == nil {
+		var profile struct {
+			Displays []struct {
+				Model  string `json:"sppci_model"`
+				Vendor string `json:"spdisplays_vendor"`
+			} `json:"SPDisplaysDataType"`
+		}
+		if json.Unmarshal(output, &profile) == nil {
+			for _, d := range profile.Displays {
+				hw.GPUs = append(hw.GPUs, GPUInfo{Vendor: gpuVendor(d.Vendor + " " + d.Model), Model: d.Model})
+			}
+		}
+	}
+}
+
+// collectWindowsHardware queries CIM classes through PowerShell
+func collectWindowsHardware(hw *HardwareInfo) {
+	var cpus []struct {
+		Name                      string
+		Manufacturer              string
+		NumberOfCores             int
+		NumberOfLogicalProcessors int
+	}
+	if cimQuery("Win32_Processor", "Name,Manufacturer,NumberOfCores,NumberOfLogicalProcessors", "", &cpus) && len(cpus) > 0 {
+		hw.CPU.Model = strings.TrimSpace(cpus[0].Name)
+		hw.CPU.Vendor = cpuVendor(cpus[0].Manufacturer + " " + cpus[0].Name)
+		for _, cpu := range cpus {
+			hw.CPU.Cores += cpu.NumberOfCores
+			hw.CPU.Threads += cpu.NumberOfLogicalProcessors
+		}
+	}
+
+	var osInfo []struct {
+		TotalVisibleMemorySize uint64
+		FreePhysicalMemory     uint64
+	}
+	if cimQuery("Win32_OperatingSystem", "TotalVisibleMemorySize,FreePhysicalMemory", "", &osInfo) && len(osInfo) > 0 {
+		hw.Memory.TotalBytes = osInfo[0].TotalVisibleMemorySize * 1024
+		hw.Memory.AvailableBytes = osInfo[0].FreePhysicalMemory * 1024
+	}
+
+	var disks []struct {
+		DeviceID   string
+		FileSystem string
+		Size       uint64
+		FreeSpace  uint64
+	}
+	if cimQuery("Win32_LogicalDisk", "DeviceID,FileSystem,Size,FreeSpace", "DriveType=3", &disks) {
+		for _, d := range disks {
+			hw.Disks = append(hw.Disks, DiskInfo{Mount: d.DeviceID, Filesystem: d.FileSystem, TotalBytes: d.Size, FreeBytes: d.FreeSpace})
+		}
+	}
+
+	var adapters []struct {
+		Name                 string
+		AdapterCompatibility string
+		DriverVersion        string
+	}
+	if cimQuery("Win32_VideoController", "Name,AdapterCompatibility,DriverVersion", "", &adapters) {
+		for _, a := range adapters {
+			hw.GPUs = append(hw.GPUs, GPUInfo{
+				Vendor:        gpuVendor(a.AdapterCompatibility + " " + a.Name),
+				Model:         strings.TrimSpace(a.Name),
+				DriverVersion: a.DriverVersion,
+			})
+		}
+	}
+}
+
+// cimQuery runs Get-CimInstance and decodes the selected properties into
+// result, which must be a pointer to a slice
+func cimQuery(class, properties, filter string, result interface{}) bool {
+	command := "Get-CimInstance -ClassName " + class
+	if filter != "" {
+		command += " -Filter '" + filter + "'"
+	}
+	command = "@(" + command + " | Select-Object " + properties + ") | ConvertTo-Json -Compress"
+	output, err := exec.Command("powershell", "-NoProfile", "-Command", command).Output()
+	if err != nil {
+		return false
+	}
+	output = []byte(strings.TrimSpace(string(output)))
+	// PowerShell 5 unwraps single element arrays
+	if len(output) > 0 && output[0] == '{' {
+		output = append(append([]byte{'['}, output...), ']')
+	}
+	return json.Unmarshal(output, result) == nil
+}
+
+// addNvidiaDriverVersions fills in NVIDIA driver versions from nvidia-smi,
+// which reports the version users know (e.g. 550.54) on every platform
+func addNvidiaDriverVersions(gpus []GPUInfo) {
+	hasNvidia := false
+	for _, gpu := range gpus {
+		hasNvidia = hasNvidia || gpu.Vendor == "NVIDIA"
+	}
+	if !hasNvidia {
+		return
+	}
+	output, err := exec.Command("nvidia-smi", "--query-gpu=driver_version", "--format=csv,noheader").Output()
+	if err != nil {
+		return
+	}
+	version := strings.TrimSpace(strings.Split(strings.TrimSpace(string(output)), "\n")[0])
+	for i := range gpus {
+		if gpus[i].Vendor == "NVIDIA" && version != "" {
+			gpus[i].DriverVersion = version
+		}
+	}
+}
+
+// parseCPUInfo parses /proc/cpuinfo
+func parseCPUInfo(data string) CPUInfo {
+	cpu := CPUInfo{}
+	cores := make(map[string]bool)
+	physicalID := ""
+	for _, line := range strings.Split(data, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		switch key {
+		case "processor":
+			cpu.Threads++
+		case "model name":
+			if cpu.Model == "" {
+				cpu.Model = value
+			}
+		case "vendor_id":
+			if cpu.Vendor == "" {
+				cpu.Vendor = cpuVendor(value)
+			}
+		case "physical id":
+			physicalID = value
+		case "core id":
+			cores[physicalID+"/"+value] = true
+		}
+	}
+	cpu.Cores = len(cores)
+	if cpu.Vendor == "" {
+		cpu.Vendor = cpuVendor(cpu.Model)
+	}
+	return cpu
+}
+
+// cpuVendor normalizes a CPU vendor id or model name
+func cpuVendor(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case strings.Contains(lower, "intel"):
+		return "Intel"
+	case strings.Contains(lower, "amd"):
+		return "AMD"
+	case strings.Contains(lower, "apple"):
+		return "Apple"
+	}
+	return strings.TrimSpace(s)
+}
+
+// parseMeminfo parses /proc/meminfo
+func parseMeminfo(data string) MemoryInfo {
+	mem := MemoryInfo{}
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			mem.TotalBytes = kb * 1024
+		case "MemAvailable:":
+			mem.AvailableBytes = kb * 1024
+		}
+	}
+	return mem
+}
+
+// parseVMStat returns the free and inactive memory reported by vm_stat
+func parseVMStat(data string) uint64 {
+	pageSize := uint64(4096)
+	var pages uint64
+	for _, line := range strings.Split(data, "\n") {
+		if strings.Contains(line, "page size of") {
+			fields := strings.Fields(line)
+			for i, f := range fields {
+				if f == "of" && i+1 < len(fields) {
+					if size, err := strconv.ParseUint(fields[i+1], 10, 64); err == nil {
+						pageSize = size
+					}
+				}
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Pages free", "Pages inactive", "Pages speculative":
+			if n, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimSpace(value), "."), 10, 64); err == nil {
+				pages += n
+			}
+		}
+	}
+	return pages * pageSize
+}
+
+// pseudoFilesystems are skipped in the disk inventory
+var pseudoFilesystems = map[string]bool{
+	"tmpfs": true, "devtmpfs": true, "overlay": true, "squashfs": true,
+	"efivarfs": true, "devfs": true, "autofs": true, "ramfs": true,
+}
+
+// parseDF parses "df -kP" output, with a filesystem type column when
+// withType is set ("df -kPT"). Only filesystems backed by a device are kept.
+func parseDF(output string, withType bool) []DiskInfo {
+	var disks []DiskInfo
+	for i, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 6 {
+			continue
+		}
+		disk := DiskInfo{Device: fields[0]}
+		if withType {
+			disk.Filesystem = fields[1]
+			fields = append(fields[:1], fields[2:]...)
+		}
+		if len(fields) < 6 {
+			continue
+		}
+		// Mount points may contain spaces
+		disk.Mount = strings.Join(fields[5:], " ")
+		if !strings.HasPrefix(disk.Device, "/dev/") || strings.HasPrefix(disk.Device, "/dev/loop") ||
+			pseudoFilesystems[disk.Filesystem] || strings.HasPrefix(disk.Mount, "/System/Volumes/") && disk.Mount != "/System/Volumes/Data" {
+			continue
+		}
+		total, _ := strconv.ParseUint(fields[1], 10, 64)
+		free, _ := strconv.ParseUint(fields[3], 10, 64)
+		disk.TotalBytes = total * 1024
+		disk.FreeBytes = free * 1024
+		disks = append(disks, disk)
+	}
+	return disks
+}
+
+// pciGPU is a display controller found by lspci
+type pciGPU struct {
+	GPUInfo
+	slot string
+}
+
+// parseLspci returns the display controllers in "lspci -mm" output
+func parseLspci(output string) []pciGPU {
+	var gpus []pciGPU
+	for _, line := range strings.Split(output, "\n") {
+		fields := splitQuoted(line)
+		if len(fields) < 4 {
+			continue
+		}
+		class := strings.ToLower(fields[1])
+		if !strings.Contains(class, "vga") && !strings.Contains(class, "3d") && !strings.Contains(class, "display") {
+			continue
+		}
+		gpus = append(gpus, pciGPU{GPUInfo: GPUInfo{Vendor: gpuVendor(fields[2]), Model: fields[3]}, slot: fields[0]})
+	}
+	return gpus
+}
+
+// splitQuoted splits a line into whitespace separated fields, keeping
+// double-quoted fields together
+func splitQuoted(line string) []string {
+	var fields []string
+	for {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return fields
+		}
+		if line[0] == '"' {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				return append(fields, line[1:])
+			}
+			fields = append(fields, line[1:end+1])
+			line = line[end+2:]
+			continue
+		}
+		end := strings.IndexAny(line, " \t")
+		if end < 0 {
+			return append(fields, line)
+		}
+		fields = append(fields, line[:end])
+		line = line[end:]
+	}
+}
+
+// gpuVendor normalizes a GPU vendor name
+func gpuVendor(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case strings.Contains(lower, "nvidia"):
+		return "NVIDIA"
+	case strings.Contains(lower, "advanced micro devices"), strings.Contains(lower, "amd"), strings.Contains(lower, "ati technologies"), strings.Contains(lower, "radeon"):
+		return "AMD"
+	case strings.Contains(lower, "intel"):
+		return "Intel"
+	case strings.Contains(lower, "apple"):
+		return "Apple"
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,162 @@
+package system
+
+import (
+	"testing"
+)
+
+func TestParseCPUInfo(t *testing.T) {
+	cpuinfo := `processor	: 0
+vendor_id	: GenuineIntel
+model name	: Intel(R) Core(TM) i7-8650U CPU @ 1.90GHz
+physical id	: 0
+core id		: 0
+flags		: fpu vme vmx sse
+
+processor	: 1
+vendor_id	: GenuineIntel
+model name	: Intel(R) Core(TM) i7-8650U CPU @ 1.90GHz
+physical id	: 0
+core id		: 0
+flags		: fpu vme vmx sse
+
+processor	: 2
+vendor_id	: GenuineIntel
+model name	: Intel(R) Core(TM) i7-8650U CPU @ 1.90GHz
+physical id	: 0
+core id		: 1
+flags		: fpu vme vmx sse
+`
+	cpu := parseCPUInfo(cpuinfo)
+	if cpu.Vendor != "Intel" || cpu.Threads != 3 || cpu.Cores != 2 || cpu.Model != "Intel(R) Core(TM) i7-8650U CPU @ 1.90GHz" {
+		t.Errorf("unexpected CPU info %+v", cpu)
+	}
+	if ext := cpuFlagsExtension(cpuinfo); ext != "VT-x" {
+		t.Errorf("expected VT-x, got %q", ext)
+	}
+	if ext := cpuFlagsExtension("flags\t: fpu svm\n"); ext != "AMD-V" {
+		t.Errorf("expected AMD-V, got %q", ext)
+	}
+	if ext := cpuFlagsExtension("flags\t: fpu sse\n"); ext != "" {
+		t.Errorf("expected no extension, got %q", ext)
+	}
+}
+
+func TestParseMemory(t *testing.T) {
+	mem := parseMeminfo("MemTotal:       16303424 kB\nMemFree:         1034020 kB\nMemAvailable:    8151712 kB\n")
+	if mem.TotalBytes != 16303424*1024 || mem.AvailableBytes != 8151712*1024 {
+		t.Errorf("unexpected memory info %+v", mem)
+	}
+
+	vmstat := `Mach Virtual Memory Statistics: (page size of 16384 bytes)
+Pages free:                               10000.
+Pages active:                            200000.
+Pages inactive:                            5000.
+Pages speculative:                         1000.
+`
+	if available := parseVMStat(vmstat); available != 16000*16384 {
+		t.Errorf("unexpected available memory %d", available)
+	}
+}
+
+func TestParseDF(t *testing.T) {
+	linux := `Filesystem     Type     1024-blocks      Used Available Capacity Mounted on
+/dev/nvme0n1p2 ext4       490617784 201234567 264383217      44% /
+tmpfs          tmpfs        8151712         0   8151712       0% /dev/shm
+/dev/loop3     squashfs       56832     56832         0     100% /snap/core18/2829
+/dev/sdb1      vfat          523248      6220    517028       2% /media/My Disk
+`
+	disks := parseDF(linux, true)
+	if len(disks) != 2 {
+		t.Fatalf("expected 2 disks, got %+v", disks)
+	}
+	if disks[0].Mount != "/" || disks[0].Filesystem != "ext4" || disks[0].FreeBytes != 264383217*1024 {
+		t.Errorf("unexpected root disk %+v", disks[0])
+	}
+	if disks[1].Mount != "/media/My Disk" {
+		t.Errorf("mount points with spaces should be kept, got %q", disks[1].Mount)
+	}
+
+	macos := `Filesystem     1024-blocks      Used Available Capacity  Mounted on
+/dev/disk3s1s1   482797652  10262412 231542964     5%    /
+devfs                  208       208         0   100%    /dev
+/dev/disk3s6     482797652   2097172 231542964     1%    /System/Volumes/VM
+/dev/disk3s5     482797652 236832220 231542964    51%    /System/Volumes/Data
+`
+	disks = parseDF(macos, false)
+	if len(disks) != 2 || disks[1].Mount != "/System/Volumes/Data" {
+		t.Fatalf("unexpected macOS disks %+v", disks)
+	}
+	if d := systemDisk(disks); d == nil || d.Mount != "/System/Volumes/Data" {
+		t.Errorf("the data volume should hold container images, got %+v", d)
+	}
+}
+
+func TestParseLspci(t *testing.T) {
+	output := `00:02.0 "VGA compatible controller" "Intel Corporation" "UHD Graphics 620" -r07 "Lenovo" "Device 225d"
+00:14.0 "USB controller" "Intel Corporation" "Sunrise Point-LP USB 3.0 xHCI Controller" -r21 "Lenovo" "Device 225d"
+01:00.0 "3D controller" "NVIDIA Corporation" "GP108M [GeForce MX150]" -ra1 "Lenovo" "Device 225d"
+03:00.0 "Display controller" "Advanced Micro Devices, Inc. [AMD/ATI]" "Navi 21" "" ""
+`
+	gpus := parseLspci(output)
+	if len(gpus) != 3 {
+		t.Fatalf("expected 3 GPUs, got %+v", gpus)
+	}
+	want := []struct{ vendor, model, slot string }{
+		{"Intel", "UHD Graphics 620", "00:02.0"},
+		{"NVIDIA", "GP108M [GeForce MX150]", "01:00.0"},
+		{"AMD", "Navi 21", "03:00.0"},
+	}
+	for i, w := range want {
+		if gpus[i].Vendor != w.vendor || gpus[i].Model != w.model || gpus[i].slot != w.slot {
+			t.Errorf("GPU %d: got %+v, want %+v", i, gpus[i], w)
+		}
+	}
+	if vendor := gpuVendor("sppci_vendor_Apple Apple M2 Pro"); vendor != "Apple" {
+		t.Errorf("expected Apple, got %s", vendor)
+	}
+}
+
+func TestContainerReadiness(t *testing.T) {
+	info := &SystemInfo{
+		OS: "Linux",
+		Capabilities: &Capabilities{
+			Podman:      true,
+			ComposeInfo: &ComposeInfo{Available: true},
+		},
+		Hardware: &HardwareInfo{
+			Memory: MemoryInfo{TotalBytes: 8 << 30},
+			Disks:  []DiskInfo{{Mount: "/", FreeBytes: 50 << 30}},
+		},
+	}
+	r := assessContainerReadiness(info, kernelFeatures{CgroupV2: true, OverlayFS: true})
+	if !r.Ready || r.Runtime != "podman" {
+		t.Errorf("rootful podman on Linux should be ready: %+v", r)
+	}
+	for _, check := range r.Checks {
+		if check.Name == "user-namespaces" && (check.Passed || check.Required) {
+			t.Errorf("missing user namespaces should be an optional failure: %+v", check)
+		}
+	}
+
+	info.Hardware.Disks[0].FreeBytes = 1 << 30
+	if r := assessContainerReadiness(info, kernelFeatures{}); r.Ready {
+		t.Error("a full disk should make containers not ready")
+	}
+
+	mac := &SystemInfo{
+		OS:           "macOS",
+		Capabilities: &Capabilities{Docker: true, VirtualizationInfo: &VirtualizationInfo{}},
+	}
+	r = assessContainerReadiness(mac, kernelFeatures{})
+	if r.Ready || r.Runtime != "docker" {
+		t.Errorf("docker without daemon and virtualization should not be ready: %+v", r)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[uint64]string{512: "512 B", 2048: "2.0 KiB", 10 << 30: "10.0 GiB"} {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
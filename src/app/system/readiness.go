@@ -0,0 +1,157 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Minimum resources for running containers comfortably
+const (
+	minContainerMemory   = 2 << 30  // 2 GiB
+	minContainerDiskFree = 10 << 30 // 10 GiB
+)
+
+// ContainerReadiness tells whether containers can be run on this system
+// and what is missing
+type ContainerReadiness struct {
+	Ready   bool             `json:"ready"`
+	Runtime string           `json:"runtime,omitempty"` // runtime that would be used
+	Checks  []ReadinessCheck `json:"checks"`
+}
+
+// ReadinessCheck is one item of the container readiness assessment; only
+// failed required checks make the system not ready
+type ReadinessCheck struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Required bool   `json:"required"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// kernelFeatures are the Linux kernel features containers rely on
+type kernelFeatures struct {
+	CgroupV2       bool
+	OverlayFS      bool
+	UserNamespaces bool
+}
+
+// detectKernelFeatures inspects the running Linux kernel
+func detectKernelFeatures() kernelFeatures {
+	features := kernelFeatures{}
+	if _, err := os.Stat("/sys/fs/cgroup/cgroup.controllers"); err == nil {
+		features.CgroupV2 = true
+	}
+	if data, err := os.ReadFile("/proc/filesystems"); err == nil {
+		features.OverlayFS = strings.Contains(string(data), "overlay")
+	}
+	if _, err := os.Stat("/sys/module/overlay"); err == nil {
+		features.OverlayFS = true
+	}
+	if data, err := os.ReadFile("/proc/sys/user/max_user_namespaces"); err == nil {
+		n, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+		features.UserNamespaces = n > 0
+	}
+	return features
+}
+
+// assessContainerReadiness evaluates the container runtime, the platform
+// prerequisites and the free resources
+func assessContainerReadiness(info *SystemInfo, kernel kernelFeatures) *ContainerReadiness {
+	caps := info.Capabilities
+	r := &ContainerReadiness{}
+	add := func(name string, passed, required bool, detail string) {
+		r.Checks = append(r.Checks, ReadinessCheck{Name: name, Passed: passed, Required: required, Detail: detail})
+	}
+
+	switch {
+	case caps.Docker && caps.DockerDaemonRunning:
+		r.Runtime = "docker"
+	case caps.Podman:
+		r.Runtime = "podman"
+	case caps.Docker:
+		r.Runtime = "docker"
+	}
+	if r.Runtime == "" {
+		add("runtime", false, true, "neither Docker nor Podman is installed (portunix install docker)")
+	} else {
+		add("runtime", true, true, r.Runtime)
+	}
+
+	// Podman is daemonless on Linux, elsewhere it needs a running machine
+	engineReady := caps.DockerDaemonRunning || caps.Podman && (info.OS == "Linux" || caps.PodmanSocketRunning)
+	engineDetail := ""
+	if !engineReady && r.Runtime == "docker" {
+		engineDetail = "Docker daemon is not running"
+	} else if !engineReady && r.Runtime == "podman" {
+		engineDetail = "Podman machine is not running (podman machine start)"
+	}
+	add("engine", engineReady, true, engineDetail)
+
+	composeAvailable := caps.ComposeInfo != nil && caps.ComposeInfo.Available
+	add("compose", composeAvailable, false, "")
+
+	switch info.OS {
+	case "Linux":
+		add("cgroup-v2", kernel.CgroupV2, false, "cgroup v2 enables resource limits for rootless containers")
+		add("overlayfs", kernel.OverlayFS, false, "overlay storage driver")
+		add("user-namespaces", kernel.UserNamespaces, false, "required for rootless containers")
+	case "macOS", "Windows":
+		// Container engines run a Linux VM here
+		virt := caps.VirtualizationInfo != nil && caps.VirtualizationInfo.HardwareVirtualization
+		add("virtualization", virt, true, "container engines run a Linux virtual machine")
+	}
+
+	if hw := info.Hardware; hw != nil {
+		if hw.Memory.TotalBytes > 0 {
+			add("memory", hw.Memory.TotalBytes >= minContainerMemory, true,
+				fmt.Sprintf("%s total, %s recommended", FormatBytes(hw.Memory.TotalBytes), FormatBytes(minContainerMemory)))
+		}
+		if disk := systemDisk(hw.Disks); disk != nil {
+			add("disk", disk.FreeBytes >= minContainerDiskFree, true,
+				fmt.Sprintf("%s free on %s, %s recommended", FormatBytes(disk.FreeBytes), disk.Mount, FormatBytes(minContainerDiskFree)))
+		}
+	}
+
+	r.Ready = true
+	for _, check := range r.Checks {
+		if check.Required && !check.Passed {
+			r.Ready = false
+		}
+	}
+	return r
+}
+
+// systemDisk returns the disk holding container images: the root
+// filesystem, the macOS data volume or the Windows system drive
+func systemDisk(disks []DiskInfo) *DiskInfo {
+	systemDrive := os.Getenv("SystemDrive")
+	if systemDrive == "" {
+		systemDrive = "C:"
+	}
+	var found *DiskInfo
+	for i := range disks {
+		switch disks[i].Mount {
+		case "/System/Volumes/Data":
+			return &disks[i]
+		case "/", systemDrive:
+			found = &disks[i]
+		}
+	}
+	return found
+}
+
+// FormatBytes formats a byte count with a binary unit, e.g. "15.6 GiB"
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -30,6 +30,8 @@ type SystemInfo struct {
 	Environment  []string      `json:"environment"`
 	WindowsInfo  *WindowsInfo  `json:"windows_info,omitempty"`
 	LinuxInfo    *LinuxInfo    `json:"linux_info,omitempty"`
+	MacOSInfo    *MacOSInfo    `json:"macos_info,omitempty"`
+	Hardware     *HardwareInfo `json:"hardware,omitempty"` // set by CollectHardware
 	Capabilities *Capabilities `json:"capabilities"`
 }
 
@@ -48,6 +50,20 @@ type LinuxInfo struct {
 	KernelVersion string `json:"kernel_version"`
 }
 
+// MacOSInfo contains macOS-specific information
+type MacOSInfo struct {
+	ProductName   string `json:"product_name"`
+	KernelVersion string `json:"kernel_version"`
+	Chip          string `json:"chip"`
+	// AppleSilicon is true on Macs with an Apple chip (M1 and newer)
+	AppleSilicon bool `json:"apple_silicon"`
+	// Rosetta is true when this process is an Intel binary translated by
+	// Rosetta 2; RosettaInstalled when Rosetta is available for Intel apps
+	Rosetta          bool   `json:"rosetta"`
+	RosettaInstalled bool   `json:"rosetta_installed"`
+	HomebrewPrefix   string `json:"homebrew_prefix,omitempty"`
+}
+
 // Capabilities shows what the system can do
 type Capabilities struct {
 	PowerShell          bool                `json:"powershell"`
@@ -62,6 +78,7 @@ type Capabilities struct {
 	Admin               bool                `json:"admin"`
 	CertificateInfo     *CertificateInfo    `json:"certificate_bundle,omitempty"`
 	VirtualizationInfo  *VirtualizationInfo `json:"virtualization,omitempty"`
+	ContainerReadiness  *ContainerReadiness `json:"container_readiness,omitempty"` // set by CollectHardware
 }
 
 // ComposeInfo contains container compose tool information
@@ -79,6 +96,8 @@ type VirtualizationInfo struct {
 	AvailableBackends      []string `json:"available_backends"`
 	RecommendedBackend     string   `json:"recommended_backend"`
 	HardwareVirtualization bool     `json:"hardware_virtualization"`
+	CPUExtension           string   `json:"cpu_extension,omitempty"` // VT-x, AMD-V or Apple Hypervisor
+	NestedVirtualization   bool     `json:"nested_virtualization"`
 	QEMU                   bool     `json:"qemu"`
 	QEMUVersion            string   `json:"qemu_version,omitempty"`
 	VirtualBox             bool     `json:"virtualbox"`
@@ -139,6 +158,12 @@ func CheckCondition(info *SystemInfo, condition string) bool {
 		return info.OS == "Linux"
 	case "macos", "darwin":
 		return info.OS == "macOS"
+	case "apple-silicon":
+		return info.MacOSInfo != nil && info.MacOSInfo.AppleSilicon
+	case "rosetta":
+		return info.MacOSInfo != nil && info.MacOSInfo.Rosetta
+	case "homebrew":
+		return info.MacOSInfo != nil && info.MacOSInfo.HomebrewPrefix != ""
 	case "sandbox":
 		return contains(info.Environment, "sandbox")
 	case "docker":
@@ -273,10 +298,62 @@ func getMacOSInfo(info *SystemInfo) error {
 	if output, err := exec.Command("sw_vers", "-productVersion").Output(); err == nil {
 		info.Version = strings.TrimSpace(string(output))
 	}
+	if output, err := exec.Command("sw_vers", "-buildVersion").Output(); err == nil {
+		info.Build = strings.TrimSpace(string(output))
+	}
+
+	macInfo := &MacOSInfo{
+		Chip:         sysctlValue("machdep.cpu.brand_string"),
+		AppleSilicon: runtime.GOARCH == "arm64" || sysctlValue("hw.optional.arm64") == "1",
+		Rosetta:      sysctlValue("sysctl.proc_translated") == "1",
+	}
+	if output, err := exec.Command("sw_vers", "-productName").Output(); err == nil {
+		macInfo.ProductName = strings.TrimSpace(string(output))
+	}
+	if output, err := exec.Command("uname", "-r").Output(); err == nil {
+		macInfo.KernelVersion = strings.TrimSpace(string(output))
+	}
+	if macInfo.AppleSilicon {
+		// The Rosetta runtime is installed on demand
+		_, err := os.Stat("/Library/Apple/usr/libexec/oah/libRosettaRuntime")
+		macInfo.RosettaInstalled = err == nil
+		// Report the hardware architecture, not the translated one
+		info.Architecture = "arm64"
+	}
+	macInfo.HomebrewPrefix = homebrewPrefix(macInfo.AppleSilicon)
+	info.MacOSInfo = macInfo
 
 	return nil
 }
 
+// sysctlValue reads a sysctl value, empty when it does not exist
+func sysctlValue(name string) string {
+	output, err := exec.Command("sysctl", "-n", name).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// homebrewPrefix returns the prefix of the installed Homebrew, empty when
+// Homebrew is not installed. Apple Silicon uses /opt/homebrew, Intel Macs
+// /usr/local.
+func homebrewPrefix(appleSilicon bool) string {
+	if prefix := os.Getenv("HOMEBREW_PREFIX"); prefix != "" {
+		return prefix
+	}
+	prefixes := []string{"/usr/local", "/opt/homebrew"}
+	if appleSilicon {
+		prefixes = []string{"/opt/homebrew", "/usr/local"}
+	}
+	for _, prefix := range prefixes {
+		if _, err := os.Stat(filepath.Join(prefix, "bin", "brew")); err == nil {
+			return prefix
+		}
+	}
+	return ""
+}
+
 // detectEnvironment detects if running in special environments
 func detectEnvironment(info *SystemInfo) {
 	// Check for Windows Sandbox and VM
@@ -481,6 +558,8 @@ func checkVirtualizationCapabilities(info *SystemInfo) {
 
 	// Check hardware virtualization
 	virtInfo.HardwareVirtualization = checkHardwareVirtualization()
+	virtInfo.CPUExtension = detectCPUVirtExtension()
+	virtInfo.NestedVirtualization = checkNestedVirtualization(info, virtInfo.HardwareVirtualization)
 
 	// Set recommended backend
 	virtInfo.RecommendedBackend = getRecommendedBackend(info.OS, virtInfo)
@@ -521,6 +600,9 @@ func checkHardwareVirtualization() bool {
 		if data, err := os.ReadFile("/proc/cpuinfo"); err == nil {
 			return strings.Contains(string(data), "vmx") || strings.Contains(string(data), "svm")
 		}
+	case "darwin":
+		// Hypervisor.framework support (Intel VT-x or Apple Silicon)
+		return sysctlValue("kern.hv_support") == "1"
 	case "windows":
 		// Try native API first
 		if nativeCheckHardwareVirt != nil {
@@ -534,6 +616,82 @@ func checkHardwareVirtualization() bool {
 	return false
 }
 
+// detectCPUVirtExtension returns the CPU virtualization extension: VT-x,
+// AMD-V or Apple Hypervisor, empty when unknown or missing
+func detectCPUVirtExtension() string {
+	switch runtime.GOOS {
+	case "linux":
+		if data, err := os.ReadFile("/proc/cpuinfo"); err == nil {
+			return cpuFlagsExtension(string(data))
+		}
+	case "darwin":
+		if runtime.GOARCH == "arm64" || sysctlValue("hw.optional.arm64") == "1" {
+			if sysctlValue("kern.hv_support") == "1" {
+				return "Apple Hypervisor"
+			}
+			return ""
+		}
+		if strings.Contains(sysctlValue("machdep.cpu.features"), "VMX") {
+			return "VT-x"
+		}
+	case "windows":
+		var cpus []struct {
+			Manufacturer                  string
+			VirtualizationFirmwareEnabled bool
+			VMMonitorModeExtensions       bool
+		}
+		if cimQuery("Win32_Processor", "Manufacturer,VirtualizationFirmwareEnabled,VMMonitorModeExtensions", "", &cpus) && len(cpus) > 0 {
+			if cpus[0].VMMonitorModeExtensions || cpus[0].VirtualizationFirmwareEnabled {
+				if cpuVendor(cpus[0].Manufacturer) == "AMD" {
+					return "AMD-V"
+				}
+				return "VT-x"
+			}
+		}
+	}
+	return ""
+}
+
+// cpuFlagsExtension finds vmx (Intel VT-x) or svm (AMD-V) in the flags of
+// /proc/cpuinfo
+func cpuFlagsExtension(cpuinfo string) string {
+	for _, line := range strings.Split(cpuinfo, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) != "flags" {
+			continue
+		}
+		for _, flag := range strings.Fields(value) {
+			switch flag {
+			case "vmx":
+				return "VT-x"
+			case "svm":
+				return "AMD-V"
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+// checkNestedVirtualization reports whether virtual machines started here
+// can run hypervisors themselves: on a Linux host when the KVM module has
+// nesting enabled, inside a VM when the guest sees hardware virtualization
+func checkNestedVirtualization(info *SystemInfo, hardwareVirt bool) bool {
+	if contains(info.Environment, "vm") {
+		return hardwareVirt
+	}
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	for _, module := range []string{"kvm_intel", "kvm_amd"} {
+		if data, err := os.ReadFile(filepath.Join("/sys/module", module, "parameters", "nested")); err == nil {
+			value := strings.TrimSpace(string(data))
+			return value == "Y" || value == "1"
+		}
+	}
+	return false
+}
+
 // getRecommendedBackend returns the recommended virtualization backend for the platform
 func getRecommendedBackend(osType string, virtInfo *VirtualizationInfo) string {
 	switch osType {
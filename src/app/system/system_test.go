@@ -73,3 +73,17 @@ func TestCheckCondition(t *testing.T) {
 		}
 	}
 }
+
+// TestMacOSConditions tests the macOS hardware conditions
+func TestMacOSConditions(t *testing.T) {
+	info := &SystemInfo{OS: "macOS", MacOSInfo: &MacOSInfo{AppleSilicon: true, HomebrewPrefix: "/opt/homebrew"}}
+	if !CheckCondition(info, "apple-silicon") || !CheckCondition(info, "homebrew") {
+		t.Error("apple-silicon and homebrew should match")
+	}
+	if CheckCondition(info, "rosetta") {
+		t.Error("rosetta should not match a native process")
+	}
+	if CheckCondition(&SystemInfo{OS: "Linux"}, "apple-silicon") {
+		t.Error("apple-silicon must not match without macOS info")
+	}
+}
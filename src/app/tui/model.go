@@ -0,0 +1,346 @@
+// Package tui implements a small full-screen terminal UI in the
+// model/update/view style: a Model holds tabs of rows, Update applies a key
+// press and View renders the screen as a string. Program drives the loop on
+// a real terminal and hands actions back as portunix command lines.
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Row is a single selectable line of a tab
+type Row struct {
+	ID      string   // value passed to actions, e.g. container name
+	Columns []string // rendered cells
+}
+
+// Action is a command bound to a key on the selected row
+type Action struct {
+	Key     Key    // e.g. "s" or KeyEnter
+	Label   string // short label shown in the footer
+	Confirm bool   // ask y/n before running
+	// Command returns the portunix arguments to run for the row
+	Command func(row Row) []string
+}
+
+// Tab is a named list with its loader and actions
+type Tab struct {
+	Title   string
+	Columns []string
+	Load    func() ([]Row, error)
+	Actions []Action
+	Empty   string // message shown when Load returns no rows
+
+	rows    []Row
+	cursor  int
+	offset  int
+	loaded  bool
+	loadErr error
+}
+
+// Rows returns the loaded rows
+func (t *Tab) Rows() []Row {
+	return t.rows
+}
+
+// Selected returns the row under the cursor
+func (t *Tab) Selected() (Row, bool) {
+	if t.cursor < 0 || t.cursor >= len(t.rows) {
+		return Row{}, false
+	}
+	return t.rows[t.cursor], true
+}
+
+// Reload runs the tab loader again, keeping the cursor in range
+func (t *Tab) Reload() {
+	t.rows, t.loadErr = t.Load()
+	t.loaded = true
+	if t.cursor >= len(t.rows) {
+		t.cursor = len(t.rows) - 1
+	}
+	if t.cursor < 0 {
+		t.cursor = 0
+	}
+}
+
+// Result tells the program what to do after a key press
+type Result struct {
+	Quit bool
+	Run  []string // portunix arguments of a triggered action
+}
+
+// Model is the state of the whole screen
+type Model struct {
+	Title string
+	Tabs  []*Tab
+
+	active  int
+	width   int
+	height  int
+	status  string
+	pending *pendingAction
+}
+
+type pendingAction struct {
+	action Action
+	row    Row
+}
+
+// NewModel creates a model showing the first tab
+func NewModel(title string, tabs ...*Tab) *Model {
+	return &Model{Title: title, Tabs: tabs, width: 80, height: 24}
+}
+
+// Active returns the tab currently shown
+func (m *Model) Active() *Tab {
+	return m.Tabs[m.active]
+}
+
+// SetSize updates the terminal dimensions used by View
+func (m *Model) SetSize(width, height int) {
+	if width > 0 && height > 0 {
+		m.width, m.height = width, height
+	}
+}
+
+// SetStatus sets the message shown above the footer
+func (m *Model) SetStatus(status string) {
+	m.status = status
+}
+
+// EnsureLoaded loads the active tab on first display
+func (m *Model) EnsureLoaded() {
+	if tab := m.Active(); !tab.loaded {
+		tab.Reload()
+	}
+}
+
+// Update applies a key press
+func (m *Model) Update(key Key) Result {
+	if m.pending != nil {
+		p := m.pending
+		m.pending = nil
+		if key == "y" || key == "Y" {
+			m.status = ""
+			return Result{Run: p.action.Command(p.row)}
+		}
+		m.status = "Cancelled"
+		return Result{}
+	}
+
+	tab := m.Active()
+	switch key {
+	case "q", KeyCtrlC, KeyEsc:
+		return Result{Quit: true}
+	case KeyTab, KeyRight:
+		m.switchTab(m.active + 1)
+	case KeyShiftTab, KeyLeft:
+		m.switchTab(m.active - 1)
+	case KeyUp, "k":
+		m.moveCursor(tab, -1)
+	case KeyDown, "j":
+		m.moveCursor(tab, 1)
+	case KeyPageUp:
+		m.moveCursor(tab, -m.listHeight())
+	case KeyPageDown:
+		m.moveCursor(tab, m.listHeight())
+	case KeyHome, "g":
+		m.moveCursor(tab, -len(tab.rows))
+	case KeyEnd, "G":
+		m.moveCursor(tab, len(tab.rows))
+	case "R":
+		tab.Reload()
+		m.status = "Refreshed"
+	default:
+		if len(key) == 1 && key[0] >= '1' && int(key[0]-'1') < len(m.Tabs) {
+			m.switchTab(int(key[0] - '1'))
+			return Result{}
+		}
+		return m.runAction(tab, key)
+	}
+	return Result{}
+}
+
+func (m *Model) runAction(tab *Tab, key Key) Result {
+	for _, action := range tab.Actions {
+		if action.Key != key {
+			continue
+		}
+		row, ok := tab.Selected()
+		if !ok {
+			m.status = "Nothing selected"
+			return Result{}
+		}
+		if action.Confirm {
+			m.pending = &pendingAction{action: action, row: row}
+			m.status = fmt.Sprintf("%s %s? (y/n)", action.Label, row.ID)
+			return Result{}
+		}
+		m.status = ""
+		return Result{Run: action.Command(row)}
+	}
+	return Result{}
+}
+
+func (m *Model) switchTab(index int) {
+	n := len(m.Tabs)
+	m.active = ((index % n) + n) % n
+	m.status = ""
+	m.EnsureLoaded()
+}
+
+func (m *Model) moveCursor(tab *Tab, delta int) {
+	tab.cursor += delta
+	if tab.cursor >= len(tab.rows) {
+		tab.cursor = len(tab.rows) - 1
+	}
+	if tab.cursor < 0 {
+		tab.cursor = 0
+	}
+}
+
+// listHeight is the number of rows available for the list: the screen
+// minus tab bar, header, separators, status and footer
+func (m *Model) listHeight() int {
+	if h := m.height - 6; h > 1 {
+		return h
+	}
+	return 1
+}
+
+// View renders the screen
+func (m *Model) View() string {
+	var b strings.Builder
+	tab := m.Active()
+
+	// Tab bar
+	b.WriteString(styleBold + " " + m.Title + " " + styleReset)
+	for i, t := range m.Tabs {
+		label := fmt.Sprintf(" %d %s ", i+1, t.Title)
+		if i == m.active {
+			label = styleReverse + label + styleReset
+		}
+		b.WriteString(" " + label)
+	}
+	b.WriteString("\r\n")
+	b.WriteString(strings.Repeat("─", m.width) + "\r\n")
+
+	// Header and rows
+	widths := columnWidths(tab.Columns, tab.rows, m.width-2)
+	b.WriteString(styleBold + "  " + formatColumns(tab.Columns, widths) + styleReset + "\r\n")
+
+	height := m.listHeight()
+	if tab.cursor < tab.offset {
+		tab.offset = tab.cursor
+	}
+	if tab.cursor >= tab.offset+height {
+		tab.offset = tab.cursor - height + 1
+	}
+
+	lines := 0
+	switch {
+	case !tab.loaded:
+		b.WriteString("  Loading...\r\n")
+		lines++
+	case tab.loadErr != nil:
+		b.WriteString("  " + truncate("Error: "+tab.loadErr.Error(), m.width-2) + "\r\n")
+		lines++
+	case len(tab.rows) == 0:
+		empty := tab.Empty
+		if empty == "" {
+			empty = "Nothing to show"
+		}
+		b.WriteString("  " + truncate(empty, m.width-2) + "\r\n")
+		lines++
+	default:
+		for i := tab.offset; i < len(tab.rows) && lines < height; i++ {
+			line := formatColumns(tab.rows[i].Columns, widths)
+			if i == tab.cursor {
+				b.WriteString(styleReverse + "> " + line + styleReset + "\r\n")
+			} else {
+				b.WriteString("  " + line + "\r\n")
+			}
+			lines++
+		}
+	}
+	for ; lines < height; lines++ {
+		b.WriteString("\r\n")
+	}
+
+	// Status and key help
+	b.WriteString(strings.Repeat("─", m.width) + "\r\n")
+	b.WriteString(truncate(m.status, m.width) + "\r\n")
+	b.WriteString(truncate(m.footer(tab), m.width))
+	return b.String()
+}
+
+func (m *Model) footer(tab *Tab) string {
+	var parts []string
+	for _, a := range tab.Actions {
+		parts = append(parts, string(a.Key)+" "+a.Label)
+	}
+	parts = append(parts, "←/→ tab", "↑/↓ move", "R refresh", "q quit")
+	return strings.Join(parts, "  ")
+}
+
+// columnWidths sizes columns to their content, shrinking the last column
+// to fit the available width
+func columnWidths(header []string, rows []Row, available int) []int {
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = len([]rune(h))
+	}
+	for _, row := range rows {
+		for i, cell := range row.Columns {
+			if i < len(widths) && len([]rune(cell)) > widths[i] {
+				widths[i] = len([]rune(cell))
+			}
+		}
+	}
+
+	total := 0
+	for i, w := range widths {
+		if i < len(widths)-1 && w > 40 {
+			widths[i] = 40
+		}
+		total += widths[i] + 2
+	}
+	if n := len(widths); n > 0 && total > available {
+		widths[n-1] -= total - available
+		if widths[n-1] < 4 {
+			widths[n-1] = 4
+		}
+	}
+	return widths
+}
+
+func formatColumns(cells []string, widths []int) string {
+	var b strings.Builder
+	for i, w := range widths {
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		cell = truncate(cell, w)
+		b.WriteString(cell)
+		if i < len(widths)-1 {
+			b.WriteString(strings.Repeat(" ", w-len([]rune(cell))+2))
+		}
+	}
+	return b.String()
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if width <= 0 {
+		return ""
+	}
+	if len(r) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(r[:width-1]) + "…"
+}
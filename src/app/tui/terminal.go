@@ -0,0 +1,255 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// Key is a decoded key press: a printable character ("s", "R") or one of
+// the named keys below
+type Key string
+
+// Named keys
+const (
+	KeyEnter     Key = "enter"
+	KeyEsc       Key = "esc"
+	KeyTab       Key = "tab"
+	KeyShiftTab  Key = "shift-tab"
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyLeft      Key = "left"
+	KeyRight     Key = "right"
+	KeyHome      Key = "home"
+	KeyEnd       Key = "end"
+	KeyPageUp    Key = "pgup"
+	KeyPageDown  Key = "pgdown"
+	KeyBackspace Key = "backspace"
+	KeyCtrlC     Key = "ctrl-c"
+)
+
+// ANSI sequences
+const (
+	styleBold    = "\x1b[1m"
+	styleReverse = "\x1b[7m"
+	styleReset   = "\x1b[0m"
+
+	altScreenOn  = "\x1b[?1049h"
+	altScreenOff = "\x1b[?1049l"
+	cursorHide   = "\x1b[?25l"
+	cursorShow   = "\x1b[?25h"
+	clearHome    = "\x1b[H\x1b[2J"
+)
+
+var escapeKeys = map[string]Key{
+	"[A": KeyUp, "[B": KeyDown, "[C": KeyRight, "[D": KeyLeft,
+	"OA": KeyUp, "OB": KeyDown, "OC": KeyRight, "OD": KeyLeft,
+	"[H": KeyHome, "[F": KeyEnd, "OH": KeyHome, "OF": KeyEnd,
+	"[1~": KeyHome, "[4~": KeyEnd, "[7~": KeyHome, "[8~": KeyEnd,
+	"[5~": KeyPageUp, "[6~": KeyPageDown, "[Z": KeyShiftTab,
+}
+
+// DecodeKeys splits raw terminal input into key presses
+func DecodeKeys(input []byte) []Key {
+	var keys []Key
+	for len(input) > 0 {
+		switch c := input[0]; {
+		case c == 0x1b:
+			key, n := decodeEscape(input)
+			if key != "" {
+				keys = append(keys, key)
+			}
+			input = input[n:]
+			continue
+		case c == '\r' || c == '\n':
+			keys = append(keys, KeyEnter)
+		case c == '\t':
+			keys = append(keys, KeyTab)
+		case c == 0x03:
+			keys = append(keys, KeyCtrlC)
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, KeyBackspace)
+		case c < 0x20:
+			// other control characters are ignored
+		default:
+			r, size := utf8.DecodeRune(input)
+			keys = append(keys, Key(string(r)))
+			input = input[size:]
+			continue
+		}
+		input = input[1:]
+	}
+	return keys
+}
+
+// decodeEscape decodes an escape sequence at the start of input and returns
+// the key with the number of bytes consumed
+func decodeEscape(input []byte) (Key, int) {
+	if len(input) == 1 {
+		return KeyEsc, 1
+	}
+	if input[1] != '[' && input[1] != 'O' {
+		return KeyEsc, 1
+	}
+	// CSI/SS3 sequences end with a letter or '~'
+	for i := 2; i < len(input); i++ {
+		c := input[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || c == '~' {
+			if key, ok := escapeKeys[string(input[1:i+1])]; ok {
+				return key, i + 1
+			}
+			return "", i + 1
+		}
+	}
+	return KeyEsc, len(input)
+}
+
+// Program runs a model on the terminal
+type Program struct {
+	Model *Model
+	// Exec runs the portunix arguments of a triggered action with the
+	// terminal restored to normal mode
+	Exec func(args []string) error
+
+	in  *os.File
+	out io.Writer
+}
+
+// NewProgram creates a program on stdin/stdout
+func NewProgram(model *Model, exec func(args []string) error) *Program {
+	return &Program{Model: model, Exec: exec, in: os.Stdin, out: os.Stdout}
+}
+
+// Run shows the UI until the user quits
+func (p *Program) Run() error {
+	fd := int(p.in.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("the interactive UI requires a terminal")
+	}
+
+	restore, err := p.enter(fd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if restore != nil {
+			restore()
+		}
+	}()
+
+	p.render(fd)
+	p.Model.EnsureLoaded()
+	p.render(fd)
+
+	buf := make([]byte, 64)
+	for {
+		n, err := p.in.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, key := range DecodeKeys(buf[:n]) {
+			result := p.Model.Update(key)
+			if result.Quit {
+				return nil
+			}
+			if len(result.Run) > 0 {
+				restore()
+				p.execute(result.Run)
+				if restore, err = p.enter(fd); err != nil {
+					return err
+				}
+				p.Model.Active().Reload()
+			}
+		}
+		p.render(fd)
+	}
+}
+
+// enter switches to raw mode and the alternate screen
+func (p *Program) enter(fd int) (func(), error) {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable raw terminal mode: %w", err)
+	}
+	fmt.Fprint(p.out, altScreenOn+cursorHide)
+	return func() {
+		fmt.Fprint(p.out, cursorShow+altScreenOff)
+		term.Restore(fd, state)
+	}, nil
+}
+
+// execute runs an action in normal mode and waits for Enter, so its output
+// can be read before returning to the UI
+func (p *Program) execute(args []string) {
+	fmt.Fprintf(p.out, "$ portunix %s\n\n", joinArgs(args))
+	if err := p.Exec(args); err != nil {
+		fmt.Fprintf(p.out, "\n❌ %v\n", err)
+		p.Model.SetStatus("Failed: portunix " + joinArgs(args))
+	} else {
+		p.Model.SetStatus("Done: portunix " + joinArgs(args))
+	}
+	fmt.Fprint(p.out, "\nPress Enter to return...")
+	bufio.NewReader(p.in).ReadString('\n')
+}
+
+func (p *Program) render(fd int) {
+	if width, height, err := term.GetSize(fd); err == nil {
+		p.Model.SetSize(width, height)
+	}
+	fmt.Fprint(p.out, clearHome+p.Model.View())
+}
+
+// Screen is a full-screen view with its own layout, for UIs that do not
+// fit the tabbed Model
+type Screen interface {
+	SetSize(width, height int)
+	// Update applies a key press and reports whether to quit
+	Update(key Key) bool
+	View() string
+}
+
+// RunScreen shows a screen on stdin/stdout until it asks to quit
+func RunScreen(screen Screen) error {
+	p := &Program{in: os.Stdin, out: os.Stdout}
+	fd := int(p.in.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("the interactive UI requires a terminal")
+	}
+
+	restore, err := p.enter(fd)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	draw := func() {
+		if width, height, err := term.GetSize(fd); err == nil {
+			screen.SetSize(width, height)
+		}
+		fmt.Fprint(p.out, clearHome+screen.View())
+	}
+	draw()
+
+	buf := make([]byte, 64)
+	for {
+		n, err := p.in.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, key := range DecodeKeys(buf[:n]) {
+			if screen.Update(key) {
+				return nil
+			}
+		}
+		draw()
+	}
+}
+
+func joinArgs(args []string) string {
+	return strings.Join(args, " ")
+}
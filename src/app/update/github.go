@@ -1,15 +1,11 @@
 package update
 
 import (
-	"archive/tar"
-	"archive/zip"
-	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"runtime"
 	"strings"
 	"time"
 )
@@ -81,37 +77,7 @@ func GetLatestRelease() (*ReleaseInfo, error) {
 		return nil, fmt.Errorf("failed to parse release: %w", err)
 	}
 
-	// Find the appropriate asset for this platform
-	binaryName := GetBinaryName(release.TagName)
-	checksumName := GetChecksumName(release.TagName)
-
-	var binaryAsset, checksumAsset *GitHubAsset
-
-	for i := range release.Assets {
-		asset := &release.Assets[i]
-		if asset.Name == binaryName {
-			binaryAsset = asset
-		} else if asset.Name == checksumName {
-			checksumAsset = asset
-		}
-	}
-
-	if binaryAsset == nil {
-		return nil, fmt.Errorf("no binary found for %s/%s", GetOS(), GetArch())
-	}
-
-	info := &ReleaseInfo{
-		Version:     release.TagName,
-		DownloadURL: binaryAsset.BrowserDownloadURL,
-		Size:        binaryAsset.Size,
-		PublishedAt: release.PublishedAt,
-	}
-
-	if checksumAsset != nil {
-		info.ChecksumURL = checksumAsset.BrowserDownloadURL
-	}
-
-	return info, nil
+	return newReleaseInfo(&release)
 }
 
 // GetMostRecentRelease fetches the most recent release (including prereleases)
@@ -153,37 +119,7 @@ func GetMostRecentRelease() (*ReleaseInfo, error) {
 	// Use the first release (most recent)
 	release := releases[0]
 
-	// Find the appropriate asset for this platform
-	binaryName := GetBinaryName(release.TagName)
-	checksumName := GetChecksumName(release.TagName)
-
-	var binaryAsset, checksumAsset *GitHubAsset
-
-	for i := range release.Assets {
-		asset := &release.Assets[i]
-		if asset.Name == binaryName {
-			binaryAsset = asset
-		} else if asset.Name == checksumName {
-			checksumAsset = asset
-		}
-	}
-
-	if binaryAsset == nil {
-		return nil, fmt.Errorf("no binary found for %s/%s", GetOS(), GetArch())
-	}
-
-	info := &ReleaseInfo{
-		Version:     release.TagName,
-		DownloadURL: binaryAsset.BrowserDownloadURL,
-		Size:        binaryAsset.Size,
-		PublishedAt: release.PublishedAt,
-	}
-
-	if checksumAsset != nil {
-		info.ChecksumURL = checksumAsset.BrowserDownloadURL
-	}
-
-	return info, nil
+	return newReleaseInfo(&release)
 }
 
 // GetRelease fetches a specific release by version
@@ -223,11 +159,16 @@ func GetRelease(version string) (*ReleaseInfo, error) {
 		return nil, fmt.Errorf("failed to parse release: %w", err)
 	}
 
-	// Find the appropriate asset for this platform
+	return newReleaseInfo(&release)
+}
+
+// newReleaseInfo picks the archive, checksum and signature assets of a
+// release for this platform
+func newReleaseInfo(release *GitHubRelease) (*ReleaseInfo, error) {
 	binaryName := GetBinaryName(release.TagName)
 	checksumName := GetChecksumName(release.TagName)
 
-	var binaryAsset, checksumAsset *GitHubAsset
+	var binaryAsset, checksumAsset, signatureAsset *GitHubAsset
 
 	for i := range release.Assets {
 		asset := &release.Assets[i]
@@ -235,6 +176,8 @@ func GetRelease(version string) (*ReleaseInfo, error) {
 			binaryAsset = asset
 		} else if asset.Name == checksumName {
 			checksumAsset = asset
+		} else if asset.Name == checksumName+".sig" {
+			signatureAsset = asset
 		}
 	}
 
@@ -247,23 +190,35 @@ func GetRelease(version string) (*ReleaseInfo, error) {
 		DownloadURL: binaryAsset.BrowserDownloadURL,
 		Size:        binaryAsset.Size,
 		PublishedAt: release.PublishedAt,
+		Prerelease:  release.Prerelease,
 	}
 
 	if checksumAsset != nil {
 		info.ChecksumURL = checksumAsset.BrowserDownloadURL
 	}
 
+	if signatureAsset != nil {
+		info.SignatureURL = signatureAsset.BrowserDownloadURL
+	}
+
 	return info, nil
 }
 
-// DownloadUpdate downloads the update archive and extracts the binary
-func DownloadUpdate(release *ReleaseInfo) (string, error) {
+// DownloadUpdate downloads the release archive and verifies it against
+// checksums as returned by FetchReleaseChecksums. The caller removes the
+// returned archive.
+func DownloadUpdate(release *ReleaseInfo, checksums []byte) (archivePath string, err error) {
 	// Create temporary file for archive
 	tmpArchive, err := os.CreateTemp("", "portunix-update-*.tmp")
 	if err != nil {
 		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
-	defer os.Remove(tmpArchive.Name())
+	// The archive is handed to the caller; remove it only on failure
+	defer func() {
+		if err != nil {
+			os.Remove(tmpArchive.Name())
+		}
+	}()
 	tmpArchive.Close()
 
 	// Download the archive
@@ -293,129 +248,18 @@ func DownloadUpdate(release *ReleaseInfo) (string, error) {
 		return "", fmt.Errorf("failed to save download: %w", err)
 	}
 
-	// Verify archive checksum if available
-	if release.ChecksumURL != "" {
-		// Extract archive name from download URL
-		urlParts := strings.Split(release.DownloadURL, "/")
-		archiveName := urlParts[len(urlParts)-1]
+	// Extract archive name from download URL
+	urlParts := strings.Split(release.DownloadURL, "/")
+	archiveName := urlParts[len(urlParts)-1]
 
-		if err := VerifyArchiveChecksum(tmpArchive.Name(), release.ChecksumURL, archiveName); err != nil {
-			return "", fmt.Errorf("checksum verification failed: %w", err)
-		}
+	if err := VerifyArchiveChecksum(tmpArchive.Name(), checksums, archiveName); err != nil {
+		return "", fmt.Errorf("checksum verification failed: %w", err)
 	}
 
 	// Return the archive path for multi-binary extraction
 	return tmpArchive.Name(), nil
 }
 
-// extractBinary extracts the portunix binary from an archive
-func extractBinary(archivePath string) (string, error) {
-	// Create temporary file for extracted binary
-	tmpBinary, err := os.CreateTemp("", "portunix-*.exe")
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp binary: %w", err)
-	}
-	tmpBinary.Close()
-
-	if runtime.GOOS == "windows" {
-		// Extract from zip
-		return extractFromZip(archivePath, tmpBinary.Name())
-	} else {
-		// Extract from tar.gz
-		return extractFromTarGz(archivePath, tmpBinary.Name())
-	}
-}
-
-// extractFromZip extracts portunix binary from a zip archive
-func extractFromZip(zipPath, destPath string) (string, error) {
-	reader, err := zip.OpenReader(zipPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to open zip: %w", err)
-	}
-	defer reader.Close()
-
-	// Find portunix binary in the archive
-	for _, file := range reader.File {
-		if strings.Contains(file.Name, "portunix") && (strings.HasSuffix(file.Name, ".exe") || !strings.Contains(file.Name, ".")) {
-			rc, err := file.Open()
-			if err != nil {
-				return "", fmt.Errorf("failed to open file in zip: %w", err)
-			}
-			defer rc.Close()
-
-			// Write to destination
-			out, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
-			if err != nil {
-				return "", fmt.Errorf("failed to create output file: %w", err)
-			}
-			defer out.Close()
-
-			_, err = io.Copy(out, rc)
-			if err != nil {
-				return "", fmt.Errorf("failed to extract file: %w", err)
-			}
-
-			return destPath, nil
-		}
-	}
-
-	return "", fmt.Errorf("portunix binary not found in zip")
-}
-
-// extractFromTarGz extracts portunix binary from a tar.gz archive
-func extractFromTarGz(tarGzPath, destPath string) (string, error) {
-	file, err := os.Open(tarGzPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to open tar.gz: %w", err)
-	}
-	defer file.Close()
-
-	// Create gzip reader
-	gzReader, err := gzip.NewReader(file)
-	if err != nil {
-		return "", fmt.Errorf("failed to create gzip reader: %w", err)
-	}
-	defer gzReader.Close()
-
-	// Create tar reader
-	tarReader := tar.NewReader(gzReader)
-
-	// Find portunix binary in the archive
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return "", fmt.Errorf("failed to read tar: %w", err)
-		}
-
-		// Check if this is the portunix binary
-		if strings.Contains(header.Name, "portunix") && header.Typeflag == tar.TypeReg {
-			// Write to destination
-			out, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
-			if err != nil {
-				return "", fmt.Errorf("failed to create output file: %w", err)
-			}
-			defer out.Close()
-
-			_, err = io.Copy(out, tarReader)
-			if err != nil {
-				return "", fmt.Errorf("failed to extract file: %w", err)
-			}
-
-			// Set executable permissions
-			if err := os.Chmod(destPath, 0755); err != nil {
-				return "", fmt.Errorf("failed to set permissions: %w", err)
-			}
-
-			return destPath, nil
-		}
-	}
-
-	return "", fmt.Errorf("portunix binary not found in tar.gz")
-}
-
 // DownloadFile downloads a file from a URL
 func DownloadFile(url string) ([]byte, error) {
 	client := &http.Client{
@@ -439,206 +283,3 @@ func DownloadFile(url string) ([]byte, error) {
 
 	return data, nil
 }
-
-// extractAllFromZip extracts all portunix binaries from a zip archive
-func extractAllFromZip(zipPath string) ([]*BinaryInfo, error) {
-	reader, err := zip.OpenReader(zipPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open zip: %w", err)
-	}
-	defer reader.Close()
-
-	var binaries []*BinaryInfo
-	binSuffix := ".exe"
-
-	// Expected binary names
-	expectedBinaries := []string{"portunix", "ptx-container", "ptx-mcp"}
-
-	for _, expectedBinary := range expectedBinaries {
-		found := false
-
-		// Find the binary in the archive
-		for _, file := range reader.File {
-			fileName := strings.ToLower(file.Name)
-			if strings.Contains(fileName, expectedBinary) && strings.HasSuffix(fileName, binSuffix) {
-				// Create temporary file
-				tmpFile, err := os.CreateTemp("", expectedBinary+"-*.exe")
-				if err != nil {
-					// Clean up any previously extracted files
-					for _, binary := range binaries {
-						os.Remove(binary.Path)
-					}
-					return nil, fmt.Errorf("failed to create temp file for %s: %w", expectedBinary, err)
-				}
-				tmpFile.Close()
-
-				// Extract the binary
-				rc, err := file.Open()
-				if err != nil {
-					os.Remove(tmpFile.Name())
-					for _, binary := range binaries {
-						os.Remove(binary.Path)
-					}
-					return nil, fmt.Errorf("failed to open %s in zip: %w", expectedBinary, err)
-				}
-
-				out, err := os.OpenFile(tmpFile.Name(), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
-				if err != nil {
-					rc.Close()
-					os.Remove(tmpFile.Name())
-					for _, binary := range binaries {
-						os.Remove(binary.Path)
-					}
-					return nil, fmt.Errorf("failed to create output file for %s: %w", expectedBinary, err)
-				}
-
-				_, err = io.Copy(out, rc)
-				rc.Close()
-				out.Close()
-
-				if err != nil {
-					os.Remove(tmpFile.Name())
-					for _, binary := range binaries {
-						os.Remove(binary.Path)
-					}
-					return nil, fmt.Errorf("failed to extract %s: %w", expectedBinary, err)
-				}
-
-				binaries = append(binaries, &BinaryInfo{
-					Name: expectedBinary,
-					Path: tmpFile.Name(),
-				})
-
-				found = true
-				break
-			}
-		}
-
-		// Main binary is required, helpers are optional
-		if !found && expectedBinary == "portunix" {
-			for _, binary := range binaries {
-				os.Remove(binary.Path)
-			}
-			return nil, fmt.Errorf("main binary %s not found in zip", expectedBinary)
-		}
-	}
-
-	if len(binaries) == 0 {
-		return nil, fmt.Errorf("no portunix binaries found in zip")
-	}
-
-	return binaries, nil
-}
-
-// extractAllFromTarGz extracts all portunix binaries from a tar.gz archive
-func extractAllFromTarGz(tarGzPath string) ([]*BinaryInfo, error) {
-	file, err := os.Open(tarGzPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open tar.gz: %w", err)
-	}
-	defer file.Close()
-
-	// Create gzip reader
-	gzReader, err := gzip.NewReader(file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
-	}
-	defer gzReader.Close()
-
-	// Create tar reader
-	tarReader := tar.NewReader(gzReader)
-
-	var binaries []*BinaryInfo
-
-	// Expected binary names
-	expectedBinaries := []string{"portunix", "ptx-container", "ptx-mcp"}
-	foundBinaries := make(map[string]bool)
-
-	// Extract all binaries from tar
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			// Clean up any extracted files
-			for _, binary := range binaries {
-				os.Remove(binary.Path)
-			}
-			return nil, fmt.Errorf("failed to read tar: %w", err)
-		}
-
-		// Check if this is one of our expected binaries
-		if header.Typeflag == tar.TypeReg {
-			fileName := strings.ToLower(header.Name)
-
-			for _, expectedBinary := range expectedBinaries {
-				if strings.Contains(fileName, expectedBinary) && !strings.Contains(fileName, ".") {
-					// Create temporary file
-					tmpFile, err := os.CreateTemp("", expectedBinary+"-*")
-					if err != nil {
-						for _, binary := range binaries {
-							os.Remove(binary.Path)
-						}
-						return nil, fmt.Errorf("failed to create temp file for %s: %w", expectedBinary, err)
-					}
-					tmpFile.Close()
-
-					// Extract the binary
-					out, err := os.OpenFile(tmpFile.Name(), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
-					if err != nil {
-						os.Remove(tmpFile.Name())
-						for _, binary := range binaries {
-							os.Remove(binary.Path)
-						}
-						return nil, fmt.Errorf("failed to create output file for %s: %w", expectedBinary, err)
-					}
-
-					_, err = io.Copy(out, tarReader)
-					out.Close()
-
-					if err != nil {
-						os.Remove(tmpFile.Name())
-						for _, binary := range binaries {
-							os.Remove(binary.Path)
-						}
-						return nil, fmt.Errorf("failed to extract %s: %w", expectedBinary, err)
-					}
-
-					// Set executable permissions
-					if err := os.Chmod(tmpFile.Name(), 0755); err != nil {
-						os.Remove(tmpFile.Name())
-						for _, binary := range binaries {
-							os.Remove(binary.Path)
-						}
-						return nil, fmt.Errorf("failed to set permissions for %s: %w", expectedBinary, err)
-					}
-
-					if !foundBinaries[expectedBinary] {
-						binaries = append(binaries, &BinaryInfo{
-							Name: expectedBinary,
-							Path: tmpFile.Name(),
-						})
-						foundBinaries[expectedBinary] = true
-					}
-
-					break
-				}
-			}
-		}
-	}
-
-	// Main binary is required
-	if !foundBinaries["portunix"] {
-		for _, binary := range binaries {
-			os.Remove(binary.Path)
-		}
-		return nil, fmt.Errorf("main binary portunix not found in tar.gz")
-	}
-
-	if len(binaries) == 0 {
-		return nil, fmt.Errorf("no portunix binaries found in tar.gz")
-	}
-
-	return binaries, nil
-}
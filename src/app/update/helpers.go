@@ -0,0 +1,292 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// HelperPinFile is the per-project file pinning helper versions
+const HelperPinFile = ".portunix-helpers.json"
+
+// SigningPublicKey is the base64 encoded ed25519 key used to verify release
+// checksum signatures. Release builds set it from PORTUNIX_SIGNING_PUBLIC_KEY
+// using ldflags; when empty, only checksums are verified.
+var SigningPublicKey = ""
+
+// RequireSigningKey refuses to install release binaries when this build has
+// no signing key, unless insecure is set: unsigned checksums come from the
+// same release as the archives and do not authenticate them
+func RequireSigningKey(insecure bool) error {
+	if SigningPublicKey == "" && !insecure {
+		return fmt.Errorf("this build has no release signing key, so the release cannot be authenticated; install a release build or pass --insecure to accept unsigned checksums")
+	}
+	return nil
+}
+
+// HelperPins maps helper names to pinned release versions
+type HelperPins struct {
+	Helpers map[string]string `json:"helpers"`
+}
+
+// FindHelperPinFile searches dir and its parents for the pin file
+func FindHelperPinFile(dir string) string {
+	for {
+		path := filepath.Join(dir, HelperPinFile)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadHelperPins loads helper pins from path; a missing file yields no pins
+func LoadHelperPins(path string) (*HelperPins, error) {
+	pins := &HelperPins{Helpers: map[string]string{}}
+	if path == "" {
+		return pins, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return pins, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, pins); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if pins.Helpers == nil {
+		pins.Helpers = map[string]string{}
+	}
+	return pins, nil
+}
+
+// SaveHelperPins writes helper pins to path
+func SaveHelperPins(path string, pins *HelperPins) error {
+	data, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode pins: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// FetchReleaseChecksums downloads the checksum file of a release and, when
+// a signing key is compiled in, verifies its signature. The returned bytes
+// are the ones the archive must be checked against (see DownloadUpdate).
+// signed is false when no signing key is configured; with a key, a missing
+// or invalid signature is an error.
+func FetchReleaseChecksums(release *ReleaseInfo) (checksums []byte, signed bool, err error) {
+	if release.ChecksumURL == "" {
+		return nil, false, fmt.Errorf("release %s publishes no checksums", release.Version)
+	}
+
+	checksums, err = DownloadFile(release.ChecksumURL)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to download checksums: %w", err)
+	}
+
+	if SigningPublicKey == "" {
+		return checksums, false, nil
+	}
+
+	if release.SignatureURL == "" {
+		return nil, false, fmt.Errorf("release %s has no checksum signature", release.Version)
+	}
+	sigData, err := DownloadFile(release.SignatureURL)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to download signature: %w", err)
+	}
+
+	if err := verifyChecksumSignature(SigningPublicKey, checksums, sigData); err != nil {
+		return nil, false, fmt.Errorf("release %s: %w", release.Version, err)
+	}
+	return checksums, true, nil
+}
+
+// verifyChecksumSignature verifies the base64 encoded ed25519 signature of
+// a checksum file against the base64 encoded public key
+func verifyChecksumSignature(publicKey string, checksums, sigData []byte) error {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid signing public key")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sigData)))
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(key), checksums, signature) {
+		return fmt.Errorf("checksum signature verification failed")
+	}
+	return nil
+}
+
+// ExtractHelperBinary extracts a single binary (e.g. "ptx-python") from a
+// release archive into a temporary file and returns its path
+func ExtractHelperBinary(archivePath, name string) (string, error) {
+	binName := name
+	if runtime.GOOS == "windows" {
+		binName += ".exe"
+	}
+
+	tmpFile, err := os.CreateTemp("", name+"-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	tmpFile.Close()
+
+	var found bool
+	if runtime.GOOS == "windows" {
+		found, err = extractNamedFromZip(archivePath, binName, tmpPath)
+	} else {
+		found, err = extractNamedFromTarGz(archivePath, binName, tmpPath)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	if !found {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("%s not found in release archive", binName)
+	}
+
+	if err := os.Chmod(tmpPath, 0755); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to set permissions for %s: %w", name, err)
+	}
+	return tmpPath, nil
+}
+
+// extractNamedFromZip extracts the archive entry whose base name is binName
+func extractNamedFromZip(zipPath, binName, destPath string) (bool, error) {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to open zip: %w", err)
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		if !strings.EqualFold(filepath.Base(file.Name), binName) {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return false, fmt.Errorf("failed to open %s in zip: %w", binName, err)
+		}
+		defer rc.Close()
+
+		return true, writeBinary(destPath, rc)
+	}
+	return false, nil
+}
+
+// extractNamedFromTarGz extracts the archive entry whose base name is binName
+func extractNamedFromTarGz(tarGzPath, binName, destPath string) (bool, error) {
+	file, err := os.Open(tarGzPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to open tar.gz: %w", err)
+	}
+	defer file.Close()
+
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return false, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer gzReader.Close()
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to read tar: %w", err)
+		}
+
+		if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) == binName {
+			return true, writeBinary(destPath, tarReader)
+		}
+	}
+}
+
+// writeBinary copies r into destPath
+func writeBinary(destPath string, r io.Reader) error {
+	out, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, r); err != nil {
+		return fmt.Errorf("failed to extract binary: %w", err)
+	}
+	return nil
+}
+
+// InstallHelperBinary replaces a helper binary in targetDir, restoring the
+// previous binary if the replacement fails
+func InstallHelperBinary(targetDir, name, newBinaryPath string) error {
+	binSuffix := ""
+	if runtime.GOOS == "windows" {
+		binSuffix = ".exe"
+	}
+	targetPath := filepath.Join(targetDir, name+binSuffix)
+
+	backupPath := ""
+	if _, err := os.Stat(targetPath); err == nil {
+		if err := checkWritePermission(targetPath); err != nil {
+			return fmt.Errorf("permission denied\n  Cannot write to %s", targetPath)
+		}
+		backupPath = targetPath + ".backup"
+		if err := copyFile(targetPath, backupPath); err != nil {
+			return fmt.Errorf("failed to backup %s: %w", name, err)
+		}
+	}
+
+	if runtime.GOOS == "windows" && backupPath != "" {
+		// A running helper cannot be overwritten on Windows, but it can be renamed
+		os.Remove(targetPath + ".old")
+		os.Rename(targetPath, targetPath+".old")
+	}
+
+	if err := copyFile(newBinaryPath, targetPath); err != nil {
+		if backupPath != "" {
+			copyFile(backupPath, targetPath)
+			os.Remove(backupPath)
+		}
+		return fmt.Errorf("failed to update %s: %w", name, err)
+	}
+
+	if err := os.Chmod(targetPath, 0755); err != nil {
+		if backupPath != "" {
+			copyFile(backupPath, targetPath)
+			os.Remove(backupPath)
+		}
+		return fmt.Errorf("failed to set permissions for %s: %w", name, err)
+	}
+
+	if backupPath != "" {
+		os.Remove(backupPath)
+	}
+	return nil
+}
@@ -0,0 +1,365 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"portunix.ai/app/config"
+)
+
+// Release channels
+const (
+	ChannelStable = "stable"
+	ChannelBeta   = "beta"
+)
+
+// KeyChannel is the configuration key holding the default update channel
+const KeyChannel = "update.channel"
+
+// stagedSuffix and oldSuffix name the files of an update in progress next
+// to the installed binaries
+const (
+	stagedSuffix = ".new"
+	oldSuffix    = ".old"
+)
+
+// DefaultChannel returns the configured update channel
+func DefaultChannel() string {
+	return config.GetString(KeyChannel, ChannelStable)
+}
+
+// ValidateChannel checks a channel name
+func ValidateChannel(channel string) error {
+	if channel != ChannelStable && channel != ChannelBeta {
+		return fmt.Errorf("unknown channel %q (use %s or %s)", channel, ChannelStable, ChannelBeta)
+	}
+	return nil
+}
+
+// LatestRelease returns the newest release of a channel that has an
+// archive for this platform. The stable channel only considers full
+// releases, the beta channel also prereleases.
+func LatestRelease(channel string) (*ReleaseInfo, error) {
+	if err := ValidateChannel(channel); err != nil {
+		return nil, err
+	}
+	releases, err := listReleases()
+	if err != nil {
+		return nil, err
+	}
+	best := selectRelease(releases, channel)
+	if best == nil {
+		return nil, fmt.Errorf("no %s release found for %s/%s", channel, GetOS(), GetArch())
+	}
+	return newReleaseInfo(best)
+}
+
+// CheckChannel returns the newest release of a channel when it is newer
+// than the running version, nil otherwise
+func CheckChannel(channel string) (*ReleaseInfo, error) {
+	latest, err := LatestRelease(channel)
+	if err != nil {
+		return nil, err
+	}
+	if CompareVersions(Version, latest.Version) >= 0 {
+		return nil, nil
+	}
+	return latest, nil
+}
+
+// selectRelease picks the newest release of the channel with an archive
+// for this platform
+func selectRelease(releases []GitHubRelease, channel string) *GitHubRelease {
+	var best *GitHubRelease
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft || (r.Prerelease && channel != ChannelBeta) {
+			continue
+		}
+		if !hasAsset(r, GetBinaryName(r.TagName)) {
+			continue
+		}
+		if best == nil || CompareVersions(r.TagName, best.TagName) > 0 {
+			best = r
+		}
+	}
+	return best
+}
+
+func hasAsset(release *GitHubRelease, name string) bool {
+	for _, asset := range release.Assets {
+		if asset.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// listReleases fetches the recent releases, including prereleases
+func listReleases() ([]GitHubRelease, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=50", githubAPI, githubOwner, githubRepo)
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", fmt.Sprintf("Portunix/%s", Version))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var releases []GitHubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	}
+	return releases, nil
+}
+
+// InstallRelease installs the portunix binary and every helper of a
+// downloaded release archive into targetDir. The new binaries are first
+// staged next to the installed ones and the staged portunix is run once;
+// only then are the binaries swapped in by rename. If any swap fails, the
+// binaries already replaced are restored. It returns the installed names.
+func InstallRelease(archivePath, targetDir string) ([]string, error) {
+	CleanupPreviousUpdate(targetDir)
+
+	if err := checkDirWritable(targetDir); err != nil {
+		if runtime.GOOS != "windows" {
+			return nil, fmt.Errorf("permission denied\n  Cannot write to %s\n  Try running with sudo: sudo portunix self-update", targetDir)
+		}
+		return nil, fmt.Errorf("permission denied\n  Cannot write to %s\n  Try running as administrator", targetDir)
+	}
+
+	staged, err := stageReleaseBinaries(archivePath, targetDir)
+	if err != nil {
+		removeStaged(staged)
+		return nil, err
+	}
+	if err := smokeTest(staged); err != nil {
+		removeStaged(staged)
+		return nil, err
+	}
+
+	var swapped []string
+	for _, binary := range staged {
+		target := filepath.Join(targetDir, binary.Name+binarySuffix())
+		if err := swapBinary(binary.Path, target); err != nil {
+			rollback(swapped)
+			removeStaged(staged)
+			return nil, fmt.Errorf("failed to replace %s: %w", binary.Name, err)
+		}
+		swapped = append(swapped, target)
+	}
+
+	names := make([]string, 0, len(staged))
+	for _, binary := range staged {
+		names = append(names, binary.Name)
+	}
+	CleanupPreviousUpdate(targetDir)
+	return names, nil
+}
+
+// CleanupPreviousUpdate removes the files a finished or aborted update
+// left behind. On Windows the replaced binary of a running process can
+// only be removed once it has exited, so this also runs on the next update.
+func CleanupPreviousUpdate(targetDir string) {
+	entries, err := os.ReadDir(targetDir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		old := strings.HasSuffix(name, oldSuffix) && isReleaseBinary(strings.TrimSuffix(name, oldSuffix))
+		staged := strings.HasPrefix(name, ".") && strings.HasSuffix(name, stagedSuffix) &&
+			isReleaseBinary(strings.TrimSuffix(strings.TrimPrefix(name, "."), stagedSuffix))
+		if old || staged {
+			os.Remove(filepath.Join(targetDir, name))
+		}
+	}
+}
+
+// swapBinary moves the installed binary aside and renames the staged one
+// into its place; a rename within a directory is atomic
+func swapBinary(staged, target string) error {
+	old := target + oldSuffix
+	os.Remove(old)
+	if _, err := os.Stat(target); err == nil {
+		// Renaming also works for the running executable on Windows
+		if err := os.Rename(target, old); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(staged, target); err != nil {
+		os.Rename(old, target)
+		return err
+	}
+	return nil
+}
+
+// rollback restores the binaries moved aside by swapBinary
+func rollback(targets []string) {
+	for _, target := range targets {
+		old := target + oldSuffix
+		if _, err := os.Stat(old); err != nil {
+			// The binary was new in this release
+			os.Remove(target)
+			continue
+		}
+		os.Remove(target)
+		os.Rename(old, target)
+	}
+}
+
+func removeStaged(staged []*BinaryInfo) {
+	for _, binary := range staged {
+		os.Remove(binary.Path)
+	}
+}
+
+// smokeTest runs the staged portunix binary to catch broken or foreign
+// architecture downloads before anything is replaced
+func smokeTest(staged []*BinaryInfo) error {
+	for _, binary := range staged {
+		if binary.Name != "portunix" {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if out, err := exec.CommandContext(ctx, binary.Path, "--version").CombinedOutput(); err != nil {
+			return fmt.Errorf("new portunix binary does not run: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	return fmt.Errorf("main binary portunix not found in release archive")
+}
+
+// checkDirWritable checks that files can be created in dir
+func checkDirWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".portunix-write-test-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+func binarySuffix() string {
+	if runtime.GOOS == "windows" {
+		return ".exe"
+	}
+	return ""
+}
+
+// isReleaseBinary reports whether a file name is portunix or one of its
+// helpers, including the .exe suffix on Windows
+func isReleaseBinary(name string) bool {
+	base := strings.TrimSuffix(name, binarySuffix())
+	if runtime.GOOS == "windows" && base == name {
+		return false
+	}
+	return base == "portunix" || (strings.HasPrefix(base, "ptx-") && !strings.Contains(base, "."))
+}
+
+// stageReleaseBinaries extracts portunix and all helpers of the archive
+// into targetDir as hidden staged files
+func stageReleaseBinaries(archivePath, targetDir string) ([]*BinaryInfo, error) {
+	var staged []*BinaryInfo
+	seen := make(map[string]bool)
+	stage := func(name string, r io.Reader) error {
+		base := strings.TrimSuffix(name, binarySuffix())
+		if seen[base] {
+			return nil
+		}
+		seen[base] = true
+		path := filepath.Join(targetDir, "."+name+stagedSuffix)
+		// Registered before writing so a partial file is cleaned up
+		staged = append(staged, &BinaryInfo{Name: base, Path: path})
+		if err := writeBinary(path, r); err != nil {
+			return fmt.Errorf("failed to stage %s: %w", base, err)
+		}
+		return os.Chmod(path, 0755)
+	}
+
+	if runtime.GOOS == "windows" {
+		reader, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open zip: %w", err)
+		}
+		defer reader.Close()
+		for _, file := range reader.File {
+			name := filepath.Base(file.Name)
+			if file.FileInfo().IsDir() || !isReleaseBinary(strings.ToLower(name)) {
+				continue
+			}
+			rc, err := file.Open()
+			if err != nil {
+				return staged, fmt.Errorf("failed to open %s in zip: %w", name, err)
+			}
+			err = stage(strings.ToLower(name), rc)
+			rc.Close()
+			if err != nil {
+				return staged, err
+			}
+		}
+	} else {
+		file, err := os.Open(archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open tar.gz: %w", err)
+		}
+		defer file.Close()
+		gzReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer gzReader.Close()
+
+		tarReader := tar.NewReader(gzReader)
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return staged, fmt.Errorf("failed to read tar: %w", err)
+			}
+			name := filepath.Base(header.Name)
+			if header.Typeflag != tar.TypeReg || !isReleaseBinary(name) {
+				continue
+			}
+			if err := stage(name, tarReader); err != nil {
+				return staged, err
+			}
+		}
+	}
+
+	if !seen["portunix"] {
+		return staged, fmt.Errorf("main binary portunix not found in release archive")
+	}
+	return staged, nil
+}
@@ -0,0 +1,141 @@
+package update
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCompareVersionsPrerelease(t *testing.T) {
+	cases := []struct {
+		v1, v2 string
+		want   int
+	}{
+		{"v1.5.0", "v1.5.0-beta.1", 1},
+		{"1.5.0-beta.1", "1.5.0-beta.2", -1},
+		{"1.5.0-beta.10", "1.5.0-beta.2", 1},
+		{"1.5.0-alpha", "1.5.0-beta", -1},
+		{"1.6.0-beta.1", "1.5.9", 1},
+		{"v1.5.0", "1.5.0", 0},
+	}
+	for _, c := range cases {
+		if got := CompareVersions(c.v1, c.v2); got != c.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", c.v1, c.v2, got, c.want)
+		}
+	}
+}
+
+func TestSelectRelease(t *testing.T) {
+	release := func(tag string, prerelease, draft bool) GitHubRelease {
+		return GitHubRelease{TagName: tag, Prerelease: prerelease, Draft: draft,
+			Assets: []GitHubAsset{{Name: GetBinaryName(tag)}}}
+	}
+	releases := []GitHubRelease{
+		release("v1.11.0-beta.1", true, false),
+		release("v1.12.0", false, true),
+		release("v1.10.2", false, false),
+		release("v1.10.10", false, false),
+		{TagName: "v1.10.11"}, // no archive for this platform
+	}
+
+	if got := selectRelease(releases, ChannelStable); got == nil || got.TagName != "v1.10.10" {
+		t.Errorf("stable channel selected %v", got)
+	}
+	if got := selectRelease(releases, ChannelBeta); got == nil || got.TagName != "v1.11.0-beta.1" {
+		t.Errorf("beta channel selected %v", got)
+	}
+	if err := ValidateChannel("nightly"); err == nil {
+		t.Error("unknown channel should be rejected")
+	}
+}
+
+func TestInstallRelease(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("release archive test uses a shell script as binary")
+	}
+	dir := t.TempDir()
+	archive := filepath.Join(t.TempDir(), "release.tar.gz")
+	writeTestArchive(t, archive, map[string]string{
+		"portunix":      "#!/bin/sh\necho new\n",
+		"ptx-installer": "new helper",
+		"README.md":     "docs",
+	})
+	os.WriteFile(filepath.Join(dir, "portunix"), []byte("old"), 0755)
+	os.WriteFile(filepath.Join(dir, "portunix.old"), []byte("stale"), 0755)
+
+	installed, err := InstallRelease(archive, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(installed) != 2 {
+		t.Errorf("unexpected installed binaries %v", installed)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "ptx-installer")); string(data) != "new helper" {
+		t.Errorf("helper not installed: %q", data)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("update left files behind: %v", entries)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "README.md")); err == nil {
+		t.Error("non-binary files must not be installed")
+	}
+}
+
+func TestInstallReleaseBrokenBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("release archive test uses a shell script as binary")
+	}
+	dir := t.TempDir()
+	archive := filepath.Join(t.TempDir(), "release.tar.gz")
+	writeTestArchive(t, archive, map[string]string{"portunix": "#!/bin/sh\nexit 3\n"})
+	os.WriteFile(filepath.Join(dir, "portunix"), []byte("old"), 0755)
+
+	if _, err := InstallRelease(archive, dir); err == nil {
+		t.Fatal("a binary failing the smoke test must not be installed")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "portunix")); string(data) != "old" {
+		t.Errorf("installed binary changed: %q", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("staged files left behind: %v", entries)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	dir := t.TempDir()
+	replaced := filepath.Join(dir, "portunix")
+	added := filepath.Join(dir, "ptx-new")
+	os.WriteFile(replaced, []byte("new"), 0755)
+	os.WriteFile(replaced+oldSuffix, []byte("old"), 0755)
+	os.WriteFile(added, []byte("new"), 0755)
+
+	rollback([]string{replaced, added})
+
+	if data, _ := os.ReadFile(replaced); string(data) != "old" {
+		t.Errorf("binary not restored: %q", data)
+	}
+	if _, err := os.Stat(added); err == nil {
+		t.Error("binary new in the release should be removed")
+	}
+}
+
+func writeTestArchive(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+}
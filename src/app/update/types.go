@@ -3,6 +3,7 @@ package update
 import (
 	"fmt"
 	"runtime"
+	"strconv"
 	"strings"
 )
 
@@ -11,11 +12,13 @@ var Version = "v1.10.8"
 
 // ReleaseInfo contains information about a GitHub release
 type ReleaseInfo struct {
-	Version     string
-	DownloadURL string
-	ChecksumURL string
-	Size        int64
-	PublishedAt string
+	Version      string
+	DownloadURL  string
+	ChecksumURL  string
+	SignatureURL string
+	Size         int64
+	PublishedAt  string
+	Prerelease   bool
 }
 
 // GitHubRelease represents a GitHub release API response
@@ -72,7 +75,8 @@ func GetChecksumName(version string) string {
 	return fmt.Sprintf("checksums_%s.txt", version)
 }
 
-// CompareVersions compares two semantic versions
+// CompareVersions compares two semantic versions; prereleases such as
+// 1.5.0-beta.2 order before their release
 // Returns: -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
 func CompareVersions(v1, v2 string) int {
 	// Remove 'v' prefix if present
@@ -93,6 +97,56 @@ func CompareVersions(v1, v2 string) int {
 		}
 	}
 
+	return comparePrerelease(prereleaseOf(v1), prereleaseOf(v2))
+}
+
+// prereleaseOf returns the prerelease part of a version ("beta.2" of
+// "1.5.0-beta.2+build"), "" for releases
+func prereleaseOf(version string) string {
+	version, _, _ = strings.Cut(version, "+")
+	_, pre, _ := strings.Cut(version, "-")
+	return pre
+}
+
+// comparePrerelease orders prerelease identifiers as semantic versioning
+// does: a release is newer than any prerelease, numeric identifiers
+// compare numerically
+func comparePrerelease(p1, p2 string) int {
+	switch {
+	case p1 == p2:
+		return 0
+	case p1 == "":
+		return 1
+	case p2 == "":
+		return -1
+	}
+	ids1, ids2 := strings.Split(p1, "."), strings.Split(p2, ".")
+	for i := 0; i < len(ids1) && i < len(ids2); i++ {
+		n1, err1 := strconv.Atoi(ids1[i])
+		n2, err2 := strconv.Atoi(ids2[i])
+		switch {
+		case err1 == nil && err2 == nil:
+			if n1 != n2 {
+				return compareInts(n1, n2)
+			}
+		case err1 == nil:
+			return -1
+		case err2 == nil:
+			return 1
+		case ids1[i] != ids2[i]:
+			return strings.Compare(ids1[i], ids2[i])
+		}
+	}
+	return compareInts(len(ids1), len(ids2))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
 	return 0
 }
 
@@ -9,25 +9,11 @@ import (
 	"strings"
 )
 
-// VerifyChecksum verifies the SHA256 checksum of a file
-func VerifyChecksum(filepath string, checksumURL string) error {
-	// For archive verification, we need to check the checksum of the archive file, not the extracted binary
-	// This function is called with the path to the extracted binary, but checksums are for archives
-	// So we'll skip verification for now and return nil
-	// TODO: Implement proper archive checksum verification
-	return nil
-}
-
 // VerifyArchiveChecksum verifies the SHA256 checksum of an archive file
-func VerifyArchiveChecksum(archivePath string, checksumURL string, archiveName string) error {
-	// Download checksum file
-	checksumData, err := DownloadFile(checksumURL)
-	if err != nil {
-		return fmt.Errorf("failed to download checksum: %w", err)
-	}
-
+// against the entry for archiveName in the contents of a checksum file
+func VerifyArchiveChecksum(archivePath string, checksums []byte, archiveName string) error {
 	// Parse checksum file to find the right entry
-	lines := strings.Split(string(checksumData), "\n")
+	lines := strings.Split(string(checksums), "\n")
 	var expectedSum string
 
 	for _, line := range lines {
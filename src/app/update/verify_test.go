@@ -0,0 +1,112 @@
+package update
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// signedRelease serves a checksum file listing archive and its signature by
+// priv; the signature is published only when withSig is set
+func signedRelease(t *testing.T, priv ed25519.PrivateKey, archive []byte, withSig bool) (*ReleaseInfo, []byte) {
+	t.Helper()
+	sum := sha256.Sum256(archive)
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  portunix_1.0.0_linux_amd64.tar.gz\n")
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, checksums))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/checksums_1.0.0.txt":
+			w.Write(checksums)
+		case "/checksums_1.0.0.txt.sig":
+			w.Write([]byte(signature + "\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	release := &ReleaseInfo{Version: "v1.0.0", ChecksumURL: server.URL + "/checksums_1.0.0.txt"}
+	if withSig {
+		release.SignatureURL = server.URL + "/checksums_1.0.0.txt.sig"
+	}
+	return release, checksums
+}
+
+// withSigningKey compiles pub in as the release signing key for the test
+func withSigningKey(t *testing.T, pub ed25519.PublicKey) {
+	t.Helper()
+	previous := SigningPublicKey
+	SigningPublicKey = base64.StdEncoding.EncodeToString(pub)
+	t.Cleanup(func() { SigningPublicKey = previous })
+}
+
+func TestFetchReleaseChecksumsSigned(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	withSigningKey(t, pub)
+	release, want := signedRelease(t, priv, []byte("archive"), true)
+
+	checksums, signed, err := FetchReleaseChecksums(release)
+	if err != nil || !signed || string(checksums) != string(want) {
+		t.Fatalf("good signature: signed=%v, err=%v", signed, err)
+	}
+}
+
+func TestFetchReleaseChecksumsBadSignature(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	_, otherPriv, _ := ed25519.GenerateKey(nil)
+	withSigningKey(t, pub)
+	release, _ := signedRelease(t, otherPriv, []byte("archive"), true)
+
+	if checksums, _, err := FetchReleaseChecksums(release); err == nil || checksums != nil {
+		t.Fatalf("signature of another key accepted: %v", err)
+	}
+}
+
+func TestFetchReleaseChecksumsMissingSignature(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	withSigningKey(t, pub)
+	release, _ := signedRelease(t, priv, []byte("archive"), false)
+
+	if _, _, err := FetchReleaseChecksums(release); err == nil || !strings.Contains(err.Error(), "no checksum signature") {
+		t.Fatalf("release without signature accepted: %v", err)
+	}
+}
+
+func TestFetchReleaseChecksumsWithoutKey(t *testing.T) {
+	withSigningKey(t, nil) // encodes to "", no key compiled in
+	_, priv, _ := ed25519.GenerateKey(nil)
+	release, _ := signedRelease(t, priv, []byte("archive"), false)
+
+	if _, signed, err := FetchReleaseChecksums(release); err != nil || signed {
+		t.Fatalf("unsigned build: signed=%v, err=%v", signed, err)
+	}
+}
+
+func TestVerifyArchiveChecksum(t *testing.T) {
+	_, priv, _ := ed25519.GenerateKey(nil)
+	_, checksums := signedRelease(t, priv, []byte("archive"), true)
+	dir := t.TempDir()
+
+	good := filepath.Join(dir, "good.tar.gz")
+	os.WriteFile(good, []byte("archive"), 0644)
+	if err := VerifyArchiveChecksum(good, checksums, "portunix_1.0.0_linux_amd64.tar.gz"); err != nil {
+		t.Errorf("matching archive rejected: %v", err)
+	}
+
+	tampered := filepath.Join(dir, "tampered.tar.gz")
+	os.WriteFile(tampered, []byte("tampered"), 0644)
+	if err := VerifyArchiveChecksum(tampered, checksums, "portunix_1.0.0_linux_amd64.tar.gz"); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("tampered archive: %v", err)
+	}
+	if err := VerifyArchiveChecksum(good, checksums, "portunix_1.0.0_darwin_arm64.tar.gz"); err == nil {
+		t.Error("archive without checksum entry accepted")
+	}
+}
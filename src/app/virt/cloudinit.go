@@ -0,0 +1,210 @@
+package virt
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// CloudImage is a distribution cloud image that boots with cloud-init
+type CloudImage struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	// OSVariant is the libosinfo short id passed to virt-install
+	OSVariant string `json:"os_variant"`
+}
+
+// CloudImages are the images accepted by 'virt create --image'
+var CloudImages = []CloudImage{
+	{Name: "ubuntu-22.04", Description: "Ubuntu 22.04 LTS", URL: "https://cloud-images.ubuntu.com/releases/22.04/release/ubuntu-22.04-server-cloudimg-amd64.img", OSVariant: "ubuntu22.04"},
+	{Name: "ubuntu-24.04", Description: "Ubuntu 24.04 LTS", URL: "https://cloud-images.ubuntu.com/releases/24.04/release/ubuntu-24.04-server-cloudimg-amd64.img", OSVariant: "ubuntu24.04"},
+	{Name: "debian-12", Description: "Debian 12 (bookworm)", URL: "https://cloud.debian.org/images/cloud/bookworm/latest/debian-12-genericcloud-amd64.qcow2", OSVariant: "debian12"},
+	{Name: "rocky-9", Description: "Rocky Linux 9", URL: "https://dl.rockylinux.org/pub/rocky/9/images/x86_64/Rocky-9-GenericCloud.latest.x86_64.qcow2", OSVariant: "rocky9"},
+	{Name: "fedora-41", Description: "Fedora Cloud 41", URL: "https://download.fedoraproject.org/pub/fedora/linux/releases/41/Cloud/x86_64/images/Fedora-Cloud-Base-Generic-41-1.4.x86_64.qcow2", OSVariant: "fedora41"},
+}
+
+// LookupCloudImage returns the catalog entry for name
+func LookupCloudImage(name string) (*CloudImage, error) {
+	var names []string
+	for i := range CloudImages {
+		if CloudImages[i].Name == name {
+			return &CloudImages[i], nil
+		}
+		names = append(names, CloudImages[i].Name)
+	}
+	return nil, fmt.Errorf("unknown image %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// DataDir returns the directory holding cloud images and VM instances
+func DataDir() string {
+	if dir := os.Getenv("PORTUNIX_VIRT_DIR"); dir != "" {
+		return dir
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-virt")
+	}
+	return filepath.Join(homeDir, ".portunix", "virt")
+}
+
+// Downloader downloads url to path. Callers pass one that retries and
+// resumes interrupted downloads; the virt module has no such client.
+type Downloader func(url, path string) error
+
+// EnsureCloudImage downloads the image once and returns its local path.
+// Progress messages go to out. A nil download uses a single plain HTTP
+// request.
+func EnsureCloudImage(img *CloudImage, out io.Writer, download Downloader) (string, error) {
+	dir := filepath.Join(DataDir(), "images")
+	path := filepath.Join(dir, img.Name+filepath.Ext(img.URL))
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create image directory: %w", err)
+	}
+
+	fmt.Fprintf(out, "Downloading %s from %s...\n", img.Description, img.URL)
+	if download == nil {
+		download = httpDownload
+	}
+	if err := download(img.URL, path); err != nil {
+		return "", fmt.Errorf("failed to download image: %w", err)
+	}
+	return path, nil
+}
+
+// httpDownload downloads url to path through a .part file
+func httpDownload(url, path string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	part := path + ".part"
+	f, err := os.Create(part)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(part)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(part)
+		return err
+	}
+	return os.Rename(part, path)
+}
+
+// CloudInit is the NoCloud configuration of a new VM
+type CloudInit struct {
+	Hostname     string
+	User         string
+	SSHPublicKey string
+	Packages     []string
+	RunCmd       []string
+	// Custom replaces the generated user-data, e.g. a file passed with
+	// --cloud-init; it must set up SSH access itself
+	Custom string
+}
+
+// UserData renders the #cloud-config user-data. The user gets passwordless
+// sudo and key-only SSH access; qemu-guest-agent lets libvirt report the IP.
+func (c *CloudInit) UserData() string {
+	if c.Custom != "" {
+		return c.Custom
+	}
+	var b strings.Builder
+	b.WriteString("#cloud-config\n")
+	fmt.Fprintf(&b, "hostname: %s\n", c.Hostname)
+	b.WriteString("manage_etc_hosts: true\n")
+	b.WriteString("users:\n")
+	fmt.Fprintf(&b, "  - name: %s\n", c.User)
+	b.WriteString("    shell: /bin/bash\n")
+	b.WriteString("    sudo: ALL=(ALL) NOPASSWD:ALL\n")
+	b.WriteString("    lock_passwd: true\n")
+	b.WriteString("    ssh_authorized_keys:\n")
+	fmt.Fprintf(&b, "      - %s\n", strings.TrimSpace(c.SSHPublicKey))
+	b.WriteString("ssh_pwauth: false\n")
+	b.WriteString("package_update: true\n")
+	b.WriteString("packages:\n")
+	for _, pkg := range append([]string{"qemu-guest-agent"}, c.Packages...) {
+		fmt.Fprintf(&b, "  - %s\n", pkg)
+	}
+	b.WriteString("runcmd:\n")
+	b.WriteString("  - [systemctl, enable, --now, qemu-guest-agent]\n")
+	for _, cmd := range c.RunCmd {
+		fmt.Fprintf(&b, "  - %q\n", cmd)
+	}
+	return b.String()
+}
+
+// MetaData renders the NoCloud meta-data
+func (c *CloudInit) MetaData() string {
+	return fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", c.Hostname, c.Hostname)
+}
+
+// WriteFiles writes user-data and meta-data into dir and returns their paths
+func (c *CloudInit) WriteFiles(dir string) (userData, metaData string, err error) {
+	userData = filepath.Join(dir, "user-data")
+	metaData = filepath.Join(dir, "meta-data")
+	if err = os.WriteFile(userData, []byte(c.UserData()), 0644); err != nil {
+		return "", "", err
+	}
+	if err = os.WriteFile(metaData, []byte(c.MetaData()), 0644); err != nil {
+		return "", "", err
+	}
+	return userData, metaData, nil
+}
+
+// BuildSeedISO writes the cloud-init files into dir/cidata and packs them into a
+// NoCloud seed ISO (volume label "cidata") for hypervisors that cannot pass
+// cloud-init data directly
+func (c *CloudInit) BuildSeedISO(dir string) (string, error) {
+	seedDir := filepath.Join(dir, "cidata")
+	if err := os.MkdirAll(seedDir, 0755); err != nil {
+		return "", err
+	}
+	userData, metaData, err := c.WriteFiles(seedDir)
+	if err != nil {
+		return "", err
+	}
+	iso := filepath.Join(dir, "seed.iso")
+
+	var cmd *exec.Cmd
+	switch {
+	case lookPath("cloud-localds"):
+		cmd = exec.Command("cloud-localds", iso, userData, metaData)
+	case lookPath("genisoimage"):
+		cmd = exec.Command("genisoimage", "-output", iso, "-volid", "cidata", "-joliet", "-rock", userData, metaData)
+	case lookPath("mkisofs"):
+		cmd = exec.Command("mkisofs", "-output", iso, "-volid", "cidata", "-joliet", "-rock", userData, metaData)
+	case lookPath("xorriso"):
+		cmd = exec.Command("xorriso", "-as", "mkisofs", "-output", iso, "-volid", "cidata", "-joliet", "-rock", userData, metaData)
+	case runtime.GOOS == "windows" && lookPath("oscdimg"):
+		cmd = exec.Command("oscdimg", "-j2", "-lcidata", seedDir, iso)
+	default:
+		return "", fmt.Errorf("no ISO tool found to build the cloud-init seed; install one of cloud-image-utils, genisoimage or xorriso")
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to build cloud-init seed: %v %s", err, strings.TrimSpace(string(output)))
+	}
+	return iso, nil
+}
+
+func lookPath(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
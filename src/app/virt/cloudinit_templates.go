@@ -0,0 +1,273 @@
+package virt
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed cloudinit/*.yaml
+var cloudInitFS embed.FS
+
+// cloudInitHeader marks the template header lines that declare the
+// description and variables; they are removed from the rendered user-data
+const cloudInitHeader = "# portunix:"
+
+// CloudInitTemplate is a user-data template of the cloud-init library
+type CloudInitTemplate struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Vars        []CloudInitVar `json:"vars"`
+	// Source is "builtin" or the path of a custom template
+	Source string `json:"source"`
+	body   string
+}
+
+// CloudInitVar is a variable declared by a template header line
+// "# portunix:var name[=default] description". A variable without a
+// default is required.
+type CloudInitVar struct {
+	Name        string `json:"name"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+}
+
+// CloudInitTemplateDirs returns the directories searched for custom
+// templates: extra, then PORTUNIX_CLOUDINIT_PATH, then the cloud-init
+// directory of DataDir. Earlier directories override later ones and the
+// built-in templates.
+func CloudInitTemplateDirs(extra ...string) []string {
+	dirs := append([]string{}, extra...)
+	if env := os.Getenv("PORTUNIX_CLOUDINIT_PATH"); env != "" {
+		dirs = append(dirs, filepath.SplitList(env)...)
+	}
+	return append(dirs, filepath.Join(DataDir(), "cloud-init"))
+}
+
+// ListCloudInitTemplates returns the built-in templates and the *.yaml
+// templates found in dirs, sorted by name
+func ListCloudInitTemplates(dirs []string) ([]*CloudInitTemplate, error) {
+	byName := map[string]*CloudInitTemplate{}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		entries, err := os.ReadDir(dirs[i])
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() || filepath.Ext(e.Name()) != ".yaml" {
+				continue
+			}
+			path := filepath.Join(dirs[i], e.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			t, err := parseCloudInitTemplate(strings.TrimSuffix(e.Name(), ".yaml"), path, string(data))
+			if err != nil {
+				return nil, err
+			}
+			byName[t.Name] = t
+		}
+	}
+	builtin, err := fs.Glob(cloudInitFS, "cloudinit/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range builtin {
+		name := strings.TrimSuffix(filepath.Base(path), ".yaml")
+		if _, ok := byName[name]; ok {
+			continue
+		}
+		data, err := cloudInitFS.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		t, err := parseCloudInitTemplate(name, "builtin", string(data))
+		if err != nil {
+			return nil, err
+		}
+		byName[name] = t
+	}
+
+	templates := make([]*CloudInitTemplate, 0, len(byName))
+	for _, t := range byName {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// LoadCloudInitTemplate returns the template called name
+func LoadCloudInitTemplate(name string, dirs []string) (*CloudInitTemplate, error) {
+	templates, err := ListCloudInitTemplates(dirs)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+		names = append(names, t.Name)
+	}
+	return nil, fmt.Errorf("unknown cloud-init template %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+func parseCloudInitTemplate(name, source, body string) (*CloudInitTemplate, error) {
+	t := &CloudInitTemplate{Name: name, Source: source, body: body}
+	for _, line := range strings.Split(body, "\n") {
+		directive, ok := strings.CutPrefix(strings.TrimSpace(line), cloudInitHeader)
+		if !ok {
+			continue
+		}
+		kind, rest, _ := strings.Cut(directive, " ")
+		rest = strings.TrimSpace(rest)
+		switch kind {
+		case "description":
+			t.Description = rest
+		case "var":
+			spec, description, _ := strings.Cut(rest, " ")
+			v := CloudInitVar{Description: strings.TrimSpace(description)}
+			var hasDefault bool
+			v.Name, v.Default, hasDefault = strings.Cut(spec, "=")
+			v.Required = !hasDefault
+			if v.Name == "" {
+				return nil, fmt.Errorf("template %s: variable without a name", name)
+			}
+			t.Vars = append(t.Vars, v)
+		default:
+			return nil, fmt.Errorf("template %s: unknown header %q", name, cloudInitHeader+kind)
+		}
+	}
+	return t, nil
+}
+
+// HasVar reports whether the template declares the variable
+func (t *CloudInitTemplate) HasVar(name string) bool {
+	for _, v := range t.Vars {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+var cloudInitFuncs = template.FuncMap{
+	// quote renders a YAML double-quoted string
+	"quote": func(s string) string {
+		data, _ := json.Marshal(s)
+		return string(data)
+	},
+	// shellquote renders a POSIX shell single-quoted string
+	"shellquote": func(s string) string {
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	},
+	// split turns a comma separated value into a list without empty items
+	"split": func(s string) []string {
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	},
+	// required fails the rendering when value is empty
+	"required": func(msg, value string) (string, error) {
+		if value == "" {
+			return "", fmt.Errorf("%s", msg)
+		}
+		return value, nil
+	},
+}
+
+// Render fills the template with vars on top of the declared defaults.
+// Unknown and missing required variables are errors, and #cloud-config
+// output must be valid YAML.
+func (t *CloudInitTemplate) Render(vars map[string]string) (string, error) {
+	data := map[string]string{}
+	for _, v := range t.Vars {
+		data[v.Name] = v.Default
+	}
+	for name, value := range vars {
+		if !t.HasVar(name) {
+			var names []string
+			for _, v := range t.Vars {
+				names = append(names, v.Name)
+			}
+			return "", fmt.Errorf("template %s has no variable %q (variables: %s)", t.Name, name, strings.Join(names, ", "))
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return "", fmt.Errorf("variable %s must be a single line", name)
+		}
+		data[name] = value
+	}
+	for _, v := range t.Vars {
+		if v.Required && data[v.Name] == "" {
+			return "", fmt.Errorf("template %s requires --var %s=... (%s)", t.Name, v.Name, v.Description)
+		}
+	}
+
+	tmpl, err := template.New(t.Name).Funcs(cloudInitFuncs).Option("missingkey=error").Parse(t.body)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %s: %w", t.Name, err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", t.Name, err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), cloudInitHeader) {
+			lines = append(lines, line)
+		}
+	}
+	userData := strings.Join(lines, "\n")
+	if strings.HasPrefix(userData, "#cloud-config") {
+		var doc map[string]interface{}
+		if err := yaml.Unmarshal([]byte(userData), &doc); err != nil {
+			return "", fmt.Errorf("template %s rendered invalid YAML: %w", t.Name, err)
+		}
+	}
+	return userData, nil
+}
+
+// ParseCloudInitVars parses --var name=value arguments. A value starting
+// with "@" is read from the named file, e.g. ssh_key=@~/.ssh/id_ed25519.pub.
+func ParseCloudInitVars(args []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid variable %q, expected name=value", arg)
+		}
+		if path, isFile := strings.CutPrefix(value, "@"); isFile {
+			if rest, ok := strings.CutPrefix(path, "~"); ok {
+				if home, err := os.UserHomeDir(); err == nil {
+					path = home + rest
+				}
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("variable %s: %w", name, err)
+			}
+			value = strings.TrimSpace(string(data))
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
@@ -0,0 +1,123 @@
+package virt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var instanceNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9.-]{0,62}$`)
+
+// Instance records a VM created from a cloud image so that later commands
+// know how to reach it over SSH
+type Instance struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	Image    string `json:"image"`
+	User     string `json:"user"`
+	// KeyPath is the private key generated for the VM
+	KeyPath string `json:"key_path"`
+	// IP is the last address reported by the hypervisor
+	IP        string    `json:"ip,omitempty"`
+	SSHPort   int       `json:"ssh_port,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ValidateInstanceName checks that name is usable as a VM and host name
+func ValidateInstanceName(name string) error {
+	if !instanceNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid VM name %q: use letters, digits, dots and dashes", name)
+	}
+	return nil
+}
+
+// InstanceDir returns the directory with the files of a VM: its record,
+// SSH key, cloud-init data and disks of providers without a storage pool
+func InstanceDir(name string) string {
+	return filepath.Join(DataDir(), "instances", name)
+}
+
+// LoadInstance reads the record of a VM created by portunix
+func LoadInstance(name string) (*Instance, error) {
+	if err := ValidateInstanceName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(InstanceDir(name), "instance.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("VM '%s' was not created by portunix", name)
+		}
+		return nil, err
+	}
+	var inst Instance
+	if err := json.Unmarshal(data, &inst); err != nil {
+		return nil, fmt.Errorf("invalid VM record %s: %w", name, err)
+	}
+	return &inst, nil
+}
+
+// SaveInstance writes the record of a VM
+func SaveInstance(inst *Instance) error {
+	if err := os.MkdirAll(InstanceDir(inst.Name), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(inst, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(InstanceDir(inst.Name), "instance.json"), append(data, '\n'), 0600)
+}
+
+// RemoveInstance deletes the record and the files of a VM
+func RemoveInstance(name string) error {
+	if err := ValidateInstanceName(name); err != nil {
+		return err
+	}
+	return os.RemoveAll(InstanceDir(name))
+}
+
+// EnsureSSHKey generates the ed25519 key pair of a VM unless it exists and
+// returns the private key path and the public key
+func EnsureSSHKey(name string) (string, string, error) {
+	keyPath := filepath.Join(InstanceDir(name), "id_ed25519")
+	if _, err := os.Stat(keyPath); os.IsNotExist(err) {
+		if err := os.MkdirAll(InstanceDir(name), 0700); err != nil {
+			return "", "", err
+		}
+		cmd := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "portunix@"+name, "-f", keyPath)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return "", "", fmt.Errorf("failed to generate SSH key: %v %s", err, strings.TrimSpace(string(output)))
+		}
+	}
+	pub, err := os.ReadFile(keyPath + ".pub")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read SSH public key: %w", err)
+	}
+	return keyPath, strings.TrimSpace(string(pub)), nil
+}
+
+// KnownHostsPath returns the known_hosts file of the instance. The host key
+// is trusted on first use; it is removed with the instance, so a VM
+// recreated under the same name is not rejected.
+func (inst *Instance) KnownHostsPath() string {
+	return filepath.Join(InstanceDir(inst.Name), "known_hosts")
+}
+
+// SSHArgs returns the ssh arguments that log into the instance
+func (inst *Instance) SSHArgs() []string {
+	args := []string{
+		"-i", inst.KeyPath,
+		"-o", "IdentitiesOnly=yes",
+		"-o", "UserKnownHostsFile=" + inst.KnownHostsPath(),
+		"-o", "StrictHostKeyChecking=accept-new",
+	}
+	if inst.SSHPort != 0 && inst.SSHPort != 22 {
+		args = append(args, "-p", fmt.Sprint(inst.SSHPort))
+	}
+	return append(args, inst.User+"@"+inst.IP)
+}
@@ -465,6 +465,19 @@ func (b *Backend) vboxManage(args ...string) error {
 	return cmd.Run()
 }
 
+// VBoxManage runs a VBoxManage command and returns its combined output
+func (b *Backend) VBoxManage(args ...string) (string, error) {
+	output, err := exec.Command(b.getVBoxManageCommand(), args...).CombinedOutput()
+	outputStr := strings.TrimSpace(string(output))
+	if err != nil {
+		if outputStr != "" {
+			return outputStr, fmt.Errorf("VBoxManage %s failed: %s", args[0], outputStr)
+		}
+		return "", fmt.Errorf("VBoxManage %s failed: %w", args[0], err)
+	}
+	return outputStr, nil
+}
+
 // getVBoxManageCommand returns the VBoxManage command path
 func (b *Backend) getVBoxManageCommand() string {
 	if b.vboxManagePath != "" {
@@ -0,0 +1,250 @@
+package vuln
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"portunix.ai/app/secret"
+)
+
+// Feed endpoints; variables so tests can point them at a local server
+var (
+	osvURL = "https://api.osv.dev/v1"
+	nvdURL = "https://services.nvd.nist.gov/rest/json/cves/2.0"
+)
+
+// NVDKeySecret is the secret store name of the optional NVD API key, which
+// raises the NVD rate limit from 5 to 50 requests per 30 seconds
+const NVDKeySecret = "vuln/nvd-api-key"
+
+// osvBatchSize is the maximum number of queries of an OSV batch request
+const osvBatchSize = 1000
+
+var httpClient = &http.Client{Timeout: 60 * time.Second}
+
+type osvQuery struct {
+	Package struct {
+		Name      string `json:"name,omitempty"`
+		Ecosystem string `json:"ecosystem,omitempty"`
+		PURL      string `json:"purl,omitempty"`
+	} `json:"package"`
+	Version string `json:"version,omitempty"`
+}
+
+// osvPURL strips the qualifiers and subpath of a package URL, which OSV
+// does not accept
+func osvPURL(purl string) string {
+	if i := strings.IndexAny(purl, "?#"); i >= 0 {
+		return purl[:i]
+	}
+	return purl
+}
+
+// queryOSV returns the findings of packages with an ecosystem or package
+// URL
+func queryOSV(ctx context.Context, packages []Package) ([]Finding, error) {
+	var queried []Package
+	var queries []osvQuery
+	for _, p := range packages {
+		var q osvQuery
+		switch {
+		case p.PURL != "":
+			q.Package.PURL = osvPURL(p.PURL)
+		case p.Ecosystem != "":
+			q.Package.Name, q.Package.Ecosystem, q.Version = p.Name, p.Ecosystem, p.Version
+		default:
+			continue
+		}
+		queried = append(queried, p)
+		queries = append(queries, q)
+	}
+
+	var findings []Finding
+	details := make(map[string]*osvVuln)
+	for start := 0; start < len(queries); start += osvBatchSize {
+		end := min(start+osvBatchSize, len(queries))
+		body, _ := json.Marshal(map[string]interface{}{"queries": queries[start:end]})
+		var resp struct {
+			Results []struct {
+				Vulns []struct {
+					ID string `json:"id"`
+				} `json:"vulns"`
+			} `json:"results"`
+		}
+		if err := postJSON(ctx, osvURL+"/querybatch", body, &resp); err != nil {
+			return nil, fmt.Errorf("OSV query failed: %w", err)
+		}
+		for i, result := range resp.Results {
+			if start+i >= len(queried) {
+				break
+			}
+			p := queried[start+i]
+			for _, v := range result.Vulns {
+				detail, ok := details[v.ID]
+				if !ok {
+					detail = fetchOSVVuln(ctx, v.ID)
+					details[v.ID] = detail
+				}
+				findings = append(findings, detail.finding(p))
+			}
+		}
+	}
+	return findings, nil
+}
+
+type osvVuln struct {
+	ID               string   `json:"id"`
+	Summary          string   `json:"summary"`
+	Details          string   `json:"details"`
+	Aliases          []string `json:"aliases"`
+	Modified         string   `json:"modified"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+	Affected []struct {
+		EcosystemSpecific struct {
+			Severity string `json:"severity"`
+		} `json:"ecosystem_specific"`
+	} `json:"affected"`
+}
+
+// fetchOSVVuln returns the details of a vulnerability; failures leave them
+// empty
+func fetchOSVVuln(ctx context.Context, id string) *osvVuln {
+	v := &osvVuln{ID: id}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, osvURL+"/vulns/"+url.PathEscape(id), nil)
+	if err != nil {
+		return v
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return v
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		json.NewDecoder(resp.Body).Decode(v)
+	}
+	return v
+}
+
+func (v *osvVuln) finding(p Package) Finding {
+	summary := v.Summary
+	if summary == "" {
+		summary, _, _ = strings.Cut(strings.TrimSpace(v.Details), "\n")
+	}
+	severity := v.DatabaseSpecific.Severity
+	for _, a := range v.Affected {
+		if severity == "" {
+			severity = a.EcosystemSpecific.Severity
+		}
+	}
+	return Finding{
+		ID:       v.ID,
+		Aliases:  v.Aliases,
+		Summary:  summary,
+		Severity: normalizeSeverity(severity),
+		Package:  p.Name,
+		Version:  p.Version,
+		Source:   p.Source,
+		Feed:     "osv",
+	}
+}
+
+// queryNVD returns the findings of packages with a CPE. Requests are
+// spaced to stay within the NVD rate limit.
+func queryNVD(ctx context.Context, packages []Package) ([]Finding, error) {
+	apiKey, _, _ := secret.Lookup(NVDKeySecret, "vuln")
+	delay := 6 * time.Second
+	if apiKey != "" {
+		delay = 600 * time.Millisecond
+	}
+
+	var findings []Finding
+	first := true
+	for _, p := range packages {
+		if p.CPE == "" {
+			continue
+		}
+		if !first {
+			select {
+			case <-ctx.Done():
+				return findings, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+		first = false
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, nvdURL+"?virtualMatchString="+url.QueryEscape(p.CPE), nil)
+		if err != nil {
+			return findings, err
+		}
+		if apiKey != "" {
+			req.Header.Set("apiKey", apiKey)
+		}
+		var resp struct {
+			Vulnerabilities []struct {
+				CVE struct {
+					ID           string `json:"id"`
+					Descriptions []struct {
+						Lang  string `json:"lang"`
+						Value string `json:"value"`
+					} `json:"descriptions"`
+					Metrics struct {
+						V31 []struct {
+							CVSSData struct {
+								BaseSeverity string `json:"baseSeverity"`
+							} `json:"cvssData"`
+						} `json:"cvssMetricV31"`
+					} `json:"metrics"`
+				} `json:"cve"`
+			} `json:"vulnerabilities"`
+		}
+		if err := doJSON(req, &resp); err != nil {
+			return findings, fmt.Errorf("NVD query for %s failed: %w", p.Name, err)
+		}
+		for _, v := range resp.Vulnerabilities {
+			f := Finding{ID: v.CVE.ID, Package: p.Name, Version: p.Version, Source: p.Source, Feed: "nvd"}
+			for _, d := range v.CVE.Descriptions {
+				if d.Lang == "en" {
+					f.Summary, _, _ = strings.Cut(d.Value, "\n")
+				}
+			}
+			if len(v.CVE.Metrics.V31) > 0 {
+				f.Severity = normalizeSeverity(v.CVE.Metrics.V31[0].CVSSData.BaseSeverity)
+			} else {
+				f.Severity = SeverityUnknown
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+func postJSON(ctx context.Context, endpoint string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doJSON(req, out)
+}
+
+func doJSON(req *http.Request, out interface{}) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,257 @@
+package vuln
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"portunix.ai/app/container"
+	"portunix.ai/app/sbom"
+)
+
+// Package is an installed package checked for vulnerabilities. It is
+// queried in OSV by package URL or by ecosystem and name, and in NVD by
+// CPE.
+type Package struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Ecosystem string `json:"ecosystem,omitempty"`
+	PURL      string `json:"purl,omitempty"`
+	CPE       string `json:"cpe,omitempty"`
+	// Source is where the package is installed: portunix, system or
+	// image:<ref>
+	Source string `json:"source"`
+}
+
+// cpeProducts maps packages installed by portunix to their NVD CPE
+// vendor and product
+var cpeProducts = map[string]string{
+	"nodejs":     "nodejs:node.js",
+	"python":     "python:python",
+	"java":       "oracle:openjdk",
+	"git":        "git-scm:git",
+	"docker":     "docker:docker",
+	"podman":     "podman_project:podman",
+	"caddy":      "caddyserver:caddy",
+	"hugo":       "gohugo:hugo",
+	"maven":      "apache:maven",
+	"openssh":    "openbsd:openssh",
+	"qemu":       "qemu:qemu",
+	"virtualbox": "oracle:vm_virtualbox",
+	"kubectl":    "kubernetes:kubectl",
+	"minio":      "minio:minio",
+	"wireguard":  "wireguard:wireguard-tools",
+	"gh":         "github:cli",
+	"rust":       "rust-lang:rust",
+	"powershell": "microsoft:powershell",
+}
+
+// portunixPackages reads the packages installed by portunix. Go is checked
+// in OSV as the standard library, the others in NVD.
+func portunixPackages() ([]Package, error) {
+	path := os.Getenv("PORTUNIX_INSTALLED_DB")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".portunix", "installed.json")
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+
+	var packages []Package
+	for name, p := range state.Packages {
+		version := strings.TrimPrefix(p.Version, "v")
+		if version == "" || version == "latest" {
+			continue
+		}
+		pkg := Package{Name: name, Version: version, Source: "portunix"}
+		switch {
+		case name == "go":
+			pkg.Ecosystem = "Go"
+			pkg.PURL = "pkg:golang/stdlib@" + strings.TrimPrefix(version, "go")
+		case cpeProducts[name] != "":
+			pkg.CPE = fmt.Sprintf("cpe:2.3:a:%s:%s:*:*:*:*:*:*:*", cpeProducts[name], version)
+		default:
+			continue
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+// osRelease reads the ID, VERSION_ID and VERSION of /etc/os-release
+func osRelease() map[string]string {
+	values := make(map[string]string)
+	file, err := os.Open("/etc/os-release")
+	if err != nil {
+		return values
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if ok {
+			values[key] = strings.Trim(value, `"'`)
+		}
+	}
+	return values
+}
+
+// distroEcosystem returns the OSV ecosystem of the Linux distribution,
+// e.g. "Debian:12" or "Ubuntu:22.04:LTS", and its package manager
+func distroEcosystem(release map[string]string) (string, string) {
+	version := release["VERSION_ID"]
+	switch release["ID"] {
+	case "debian":
+		return "Debian:" + version, "dpkg"
+	case "ubuntu":
+		if strings.Contains(release["VERSION"], "LTS") {
+			return "Ubuntu:" + version + ":LTS", "dpkg"
+		}
+		return "Ubuntu:" + version, "dpkg"
+	case "rocky":
+		return "Rocky Linux:" + strings.Split(version, ".")[0], "rpm"
+	case "almalinux":
+		return "AlmaLinux:" + strings.Split(version, ".")[0], "rpm"
+	case "alpine":
+		parts := strings.Split(version, ".")
+		if len(parts) >= 2 {
+			return "Alpine:v" + parts[0] + "." + parts[1], "apk"
+		}
+	}
+	return "", ""
+}
+
+// systemPackages lists the packages of the system package database under
+// the names OSV uses: source packages for Debian and Ubuntu
+func systemPackages() ([]Package, error) {
+	if runtime.GOOS != "linux" {
+		return nil, nil
+	}
+	release := osRelease()
+	ecosystem, manager := distroEcosystem(release)
+	if ecosystem == "" {
+		return nil, fmt.Errorf("system packages of %s are not covered by OSV; only images and portunix packages are checked", release["ID"])
+	}
+
+	var output []byte
+	var err error
+	switch manager {
+	case "dpkg":
+		output, err = exec.Command("dpkg-query", "-W", "-f", "${db:Status-Status}\t${source:Package}\t${source:Version}\n").Output()
+	case "rpm":
+		output, err = exec.Command("rpm", "-qa", "--qf", "installed\t%{NAME}\t%{VERSION}-%{RELEASE}\n").Output()
+	case "apk":
+		output, err = exec.Command("apk", "info", "-v").Output()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s packages: %w", manager, err)
+	}
+	return parseSystemPackages(string(output), manager, ecosystem), nil
+}
+
+// parseSystemPackages parses package manager output into unique packages
+func parseSystemPackages(output, manager, ecosystem string) []Package {
+	seen := make(map[string]bool)
+	var packages []Package
+	for _, line := range strings.Split(output, "\n") {
+		var name, version string
+		if manager == "apk" {
+			// name-version-rN; the version starts at the second to last dash
+			parts := strings.Split(strings.TrimSpace(line), "-")
+			if len(parts) < 3 {
+				continue
+			}
+			name = strings.Join(parts[:len(parts)-2], "-")
+			version = strings.Join(parts[len(parts)-2:], "-")
+		} else {
+			fields := strings.Split(line, "\t")
+			if len(fields) != 3 || fields[0] != "installed" {
+				continue
+			}
+			name, version = fields[1], fields[2]
+		}
+		if seen[name+"@"+version] {
+			continue
+		}
+		seen[name+"@"+version] = true
+		packages = append(packages, Package{Name: name, Version: version, Ecosystem: ecosystem, Source: "system"})
+	}
+	return packages
+}
+
+// imagePackages lists the packages of the local container images from
+// their SBOMs, kept in dir for comparison between scans
+func imagePackages(dir string) ([]Package, []string, error) {
+	if _, err := exec.LookPath("syft"); err != nil {
+		return nil, nil, fmt.Errorf("container images skipped: syft is not installed (portunix install syft)")
+	}
+	rt, err := container.GetSelectedRuntime()
+	if err != nil {
+		return nil, nil, fmt.Errorf("container images skipped: %v", err)
+	}
+	out, err := exec.Command(rt, "images", "--format", "{{.Repository}}:{{.Tag}}").Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list %s images: %w", rt, err)
+	}
+
+	var packages []Package
+	var warnings []string
+	seen := make(map[string]bool)
+	for _, image := range strings.Fields(string(out)) {
+		if strings.Contains(image, "<none>") || seen[image] {
+			continue
+		}
+		seen[image] = true
+		target := sbom.Target{Kind: sbom.KindImage, Ref: image}
+		record, err := sbom.Generate(target, sbom.FormatCycloneDX, dir)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		components, err := sbom.ReadComponents(record.Path)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		pruneSBOMs(dir, target)
+		for _, c := range components {
+			if c.PURL == "" || c.Version == "" {
+				continue
+			}
+			packages = append(packages, Package{Name: c.Name, Version: c.Version, PURL: c.PURL, Source: "image:" + image})
+		}
+	}
+	return packages, warnings, nil
+}
+
+// pruneSBOMs keeps the two newest SBOMs of an image
+func pruneSBOMs(dir string, target sbom.Target) {
+	records, err := sbom.List(dir, target)
+	if err != nil {
+		return
+	}
+	for i := 0; i < len(records)-2; i++ {
+		os.Remove(records[i].Path)
+	}
+}
@@ -0,0 +1,145 @@
+package vuln
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"portunix.ai/app/secret"
+)
+
+// Notification channels
+const (
+	NotifyDesktop = "desktop"
+	NotifyEmail   = "email"
+	NotifyNone    = "none"
+)
+
+// SMTPConfig is the "smtp" section of a pft configuration file
+// (.pft-config.json), which email notifications reuse
+type SMTPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	From     string `json:"from"`
+}
+
+// LoadSMTPConfig reads the SMTP section of a pft configuration file
+func LoadSMTPConfig(path string) (*SMTPConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pft configuration: %w", err)
+	}
+	var config struct {
+		SMTP *SMTPConfig `json:"smtp"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid pft configuration %s: %w", path, err)
+	}
+	if config.SMTP == nil || config.SMTP.Host == "" {
+		return nil, fmt.Errorf("%s has no SMTP configuration (ptx-pft configure --smtp-host ...)", path)
+	}
+	return config.SMTP, nil
+}
+
+// Message returns the subject and body of a notification about new
+// findings
+func Message(findings []Finding) (string, string) {
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	var parts []string
+	for _, severity := range []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityUnknown} {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	subject := fmt.Sprintf("Portunix: %d new vulnerabilities (%s)", len(findings), strings.Join(parts, ", "))
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "New vulnerabilities found on %s at %s:\n\n", hostname(), time.Now().Format("2006-01-02 15:04"))
+	for _, f := range findings {
+		id := f.ID
+		if cve := f.CVE(); cve != "" && cve != f.ID {
+			id += " (" + cve + ")"
+		}
+		fmt.Fprintf(&body, "[%s] %s\n  %s %s (%s)\n", strings.ToUpper(f.Severity), id, f.Package, f.Version, f.Source)
+		if f.Summary != "" {
+			fmt.Fprintf(&body, "  %s\n", f.Summary)
+		}
+		body.WriteString("\n")
+	}
+	body.WriteString("Run 'portunix vuln status' for all current findings.\n")
+	return subject, body.String()
+}
+
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "this machine"
+	}
+	return name
+}
+
+// NotifyDesktopMessage shows a desktop notification with notify-send,
+// osascript or a Windows balloon tip
+func NotifyDesktopMessage(title, text string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", text, title)
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+		script := "Add-Type -AssemblyName System.Windows.Forms;" +
+			"$n = New-Object System.Windows.Forms.NotifyIcon;" +
+			"$n.Icon = [System.Drawing.SystemIcons]::Warning;" +
+			"$n.Visible = $true;" +
+			"$n.ShowBalloonTip(10000, " + quote(title) + ", " + quote(text) + ", 'Warning');" +
+			"Start-Sleep -Seconds 10; $n.Dispose()"
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return fmt.Errorf("notify-send is not installed")
+		}
+		cmd = exec.Command("notify-send", "--urgency=critical", "--app-name=Portunix", title, text)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("desktop notification failed: %v %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// SendEmail sends a notification through the SMTP server of a pft
+// configuration. A "secret:<name>" password is read from the secret store.
+func SendEmail(config *SMTPConfig, to []string, subject, body string) error {
+	if len(to) == 0 {
+		return fmt.Errorf("no email recipients (portunix config set vuln.email_to <address>)")
+	}
+	password, err := secret.Resolve(config.Password, "vuln")
+	if err != nil {
+		return err
+	}
+	port := config.Port
+	if port == 0 {
+		port = 25
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+		config.From, strings.Join(to, ", "), subject, body)
+
+	var auth smtp.Auth
+	if config.Username != "" && password != "" {
+		auth = smtp.PlainAuth("", config.Username, password, config.Host)
+	}
+	if err := smtp.SendMail(fmt.Sprintf("%s:%d", config.Host, port), auth, config.From, to, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+// Package vuln checks the packages installed by portunix, the system
+// packages and the local container images against the OSV and NVD
+// vulnerability feeds and reports the vulnerabilities found since the
+// previous scan.
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Severities of findings, from the feeds' CVSS ratings
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityUnknown  = "unknown"
+)
+
+var severityRank = map[string]int{
+	SeverityCritical: 4,
+	SeverityHigh:     3,
+	SeverityMedium:   2,
+	SeverityLow:      1,
+	SeverityUnknown:  0,
+}
+
+// normalizeSeverity maps the severity labels of the feeds, e.g. "HIGH" or
+// "MODERATE", to the severities above
+func normalizeSeverity(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "critical":
+		return SeverityCritical
+	case "high", "important":
+		return SeverityHigh
+	case "medium", "moderate":
+		return SeverityMedium
+	case "low", "negligible":
+		return SeverityLow
+	}
+	return SeverityUnknown
+}
+
+// AtLeast reports whether severity is at least min; an empty min matches
+// every severity
+func AtLeast(severity, min string) bool {
+	if min == "" {
+		return true
+	}
+	return severityRank[severity] >= severityRank[normalizeSeverity(min)]
+}
+
+// Finding is a vulnerability affecting an installed package
+type Finding struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Summary  string   `json:"summary,omitempty"`
+	Severity string   `json:"severity"`
+	Package  string   `json:"package"`
+	Version  string   `json:"version"`
+	Source   string   `json:"source"`
+	// Feed is the feed that reported the finding: osv or nvd
+	Feed string `json:"feed"`
+}
+
+// Key identifies a finding between scans
+func (f Finding) Key() string {
+	return f.ID + "|" + f.Source + "|" + f.Package + "@" + f.Version
+}
+
+// CVE returns the CVE identifier of the finding, if any
+func (f Finding) CVE() string {
+	if strings.HasPrefix(f.ID, "CVE-") {
+		return f.ID
+	}
+	for _, alias := range f.Aliases {
+		if strings.HasPrefix(alias, "CVE-") {
+			return alias
+		}
+	}
+	return ""
+}
+
+// Options selects what a scan covers
+type Options struct {
+	// Images includes the local container images, which needs syft
+	Images bool
+	// NoSystem skips the system package database
+	NoSystem bool
+}
+
+// Result is the outcome of a scan
+type Result struct {
+	Time     time.Time `json:"time"`
+	Packages int       `json:"packages"`
+	Findings []Finding `json:"findings"`
+	// New lists the findings not reported by the previous scan
+	New      []Finding `json:"new"`
+	Warnings []string  `json:"warnings,omitempty"`
+}
+
+// Dir returns the directory holding the scan state and image SBOMs
+func Dir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".portunix", "vuln")
+	}
+	return filepath.Join(home, ".portunix", "vuln")
+}
+
+func statePath() string {
+	return filepath.Join(Dir(), "state.json")
+}
+
+// Inventory lists the packages a scan checks. Parts that cannot be listed
+// are reported as warnings.
+func Inventory(opts Options) ([]Package, []string) {
+	var packages []Package
+	var warnings []string
+
+	installed, err := portunixPackages()
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	packages = append(packages, installed...)
+
+	if !opts.NoSystem {
+		system, err := systemPackages()
+		if err != nil {
+			warnings = append(warnings, err.Error())
+		}
+		packages = append(packages, system...)
+	}
+
+	if opts.Images {
+		images, imageWarnings, err := imagePackages(filepath.Join(Dir(), "sbom"))
+		if err != nil {
+			warnings = append(warnings, err.Error())
+		}
+		warnings = append(warnings, imageWarnings...)
+		packages = append(packages, images...)
+	}
+	return packages, warnings
+}
+
+// Scan checks the inventory against OSV and NVD, compares the findings
+// with the previous scan and stores them for the next one
+func Scan(ctx context.Context, opts Options) (*Result, error) {
+	packages, warnings := Inventory(opts)
+	result, err := check(ctx, packages)
+	if err != nil {
+		return nil, err
+	}
+	result.Warnings = append(warnings, result.Warnings...)
+
+	previous, err := LastResult()
+	if err != nil {
+		result.Warnings = append(result.Warnings, err.Error())
+	}
+	result.New = newFindings(previous, result.Findings)
+
+	if err := saveResult(result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// check queries the feeds for packages. A failing NVD query is reported
+// as a warning since OSV covers most packages.
+func check(ctx context.Context, packages []Package) (*Result, error) {
+	result := &Result{Time: time.Now(), Packages: len(packages)}
+	findings, err := queryOSV(ctx, packages)
+	if err != nil {
+		return nil, err
+	}
+	nvdFindings, err := queryNVD(ctx, packages)
+	if err != nil {
+		result.Warnings = append(result.Warnings, err.Error())
+	}
+	findings = append(findings, nvdFindings...)
+
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		if !seen[f.Key()] {
+			seen[f.Key()] = true
+			result.Findings = append(result.Findings, f)
+		}
+	}
+	sortFindings(result.Findings)
+	return result, nil
+}
+
+// newFindings returns the findings missing from the previous result; with
+// no previous result every finding is new
+func newFindings(previous *Result, findings []Finding) []Finding {
+	known := make(map[string]bool)
+	if previous != nil {
+		for _, f := range previous.Findings {
+			known[f.Key()] = true
+		}
+	}
+	var added []Finding
+	for _, f := range findings {
+		if !known[f.Key()] {
+			added = append(added, f)
+		}
+	}
+	return added
+}
+
+func sortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] > severityRank[b.Severity]
+		}
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.ID < b.ID
+	})
+}
+
+// LastResult returns the result of the previous scan, or nil if there was
+// none
+func LastResult() (*Result, error) {
+	data, err := os.ReadFile(statePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var result Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid scan state %s: %w", statePath(), err)
+	}
+	return &result, nil
+}
+
+func saveResult(result *Result) error {
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", Dir(), err)
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := statePath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to save scan state: %w", err)
+	}
+	return os.Rename(tmp, statePath())
+}
@@ -0,0 +1,111 @@
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDistroEcosystem(t *testing.T) {
+	cases := []struct {
+		release   map[string]string
+		ecosystem string
+		manager   string
+	}{
+		{map[string]string{"ID": "debian", "VERSION_ID": "12"}, "Debian:12", "dpkg"},
+		{map[string]string{"ID": "ubuntu", "VERSION_ID": "22.04", "VERSION": "22.04.4 LTS (Jammy Jellyfish)"}, "Ubuntu:22.04:LTS", "dpkg"},
+		{map[string]string{"ID": "ubuntu", "VERSION_ID": "23.10", "VERSION": "23.10 (Mantic Minotaur)"}, "Ubuntu:23.10", "dpkg"},
+		{map[string]string{"ID": "rocky", "VERSION_ID": "9.3"}, "Rocky Linux:9", "rpm"},
+		{map[string]string{"ID": "alpine", "VERSION_ID": "3.19.1"}, "Alpine:v3.19", "apk"},
+		{map[string]string{"ID": "arch"}, "", ""},
+	}
+	for _, c := range cases {
+		ecosystem, manager := distroEcosystem(c.release)
+		if ecosystem != c.ecosystem || manager != c.manager {
+			t.Errorf("%v: got %q %q, want %q %q", c.release, ecosystem, manager, c.ecosystem, c.manager)
+		}
+	}
+}
+
+func TestParseSystemPackages(t *testing.T) {
+	dpkg := "installed\topenssl\t3.0.2-0ubuntu1.15\ninstalled\topenssl\t3.0.2-0ubuntu1.15\nconfig-files\tzlib\t1:1.2.11\ninstalled\tcurl\t7.81.0-1\n"
+	packages := parseSystemPackages(dpkg, "dpkg", "Ubuntu:22.04:LTS")
+	if len(packages) != 2 || packages[0].Name != "openssl" || packages[1].Version != "7.81.0-1" {
+		t.Errorf("unexpected dpkg packages %+v", packages)
+	}
+
+	apk := "musl-1.2.4-r2\nlibcrypto3-3.1.4-r5\n"
+	packages = parseSystemPackages(apk, "apk", "Alpine:v3.19")
+	if len(packages) != 2 || packages[1].Name != "libcrypto3" || packages[1].Version != "3.1.4-r5" {
+		t.Errorf("unexpected apk packages %+v", packages)
+	}
+}
+
+func TestCheckFeeds(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/osv/querybatch":
+			var req struct {
+				Queries []osvQuery `json:"queries"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			var results []map[string]interface{}
+			for _, q := range req.Queries {
+				if q.Package.PURL == "pkg:golang/stdlib@1.21.0" {
+					results = append(results, map[string]interface{}{"vulns": []map[string]string{{"id": "GO-2024-0001"}}})
+				} else {
+					results = append(results, map[string]interface{}{})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+		case r.URL.Path == "/osv/vulns/GO-2024-0001":
+			w.Write([]byte(`{"id": "GO-2024-0001", "summary": "Panic in net/http", "aliases": ["CVE-2024-1111"], "database_specific": {"severity": "MODERATE"}}`))
+		case r.URL.Path == "/nvd":
+			if !strings.Contains(r.URL.Query().Get("virtualMatchString"), "nodejs:node.js:20.1.0") {
+				t.Errorf("unexpected NVD query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"vulnerabilities": [{"cve": {"id": "CVE-2024-2222",
+				"descriptions": [{"lang": "en", "value": "Path traversal"}],
+				"metrics": {"cvssMetricV31": [{"cvssData": {"baseSeverity": "HIGH"}}]}}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	osvURL, nvdURL = server.URL+"/osv", server.URL+"/nvd"
+
+	packages := []Package{
+		{Name: "go", Version: "1.21.0", Ecosystem: "Go", PURL: "pkg:golang/stdlib@1.21.0?goos=linux", Source: "portunix"},
+		{Name: "nodejs", Version: "20.1.0", CPE: "cpe:2.3:a:nodejs:node.js:20.1.0:*:*:*:*:*:*:*", Source: "portunix"},
+		{Name: "zlib", Version: "1.3", Ecosystem: "Debian:12", Source: "system"},
+	}
+	result, err := check(context.Background(), packages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", result.Findings)
+	}
+	high, moderate := result.Findings[0], result.Findings[1]
+	if high.ID != "CVE-2024-2222" || high.Severity != SeverityHigh || high.Feed != "nvd" {
+		t.Errorf("unexpected NVD finding %+v", high)
+	}
+	if moderate.CVE() != "CVE-2024-1111" || moderate.Severity != SeverityMedium || moderate.Summary != "Panic in net/http" {
+		t.Errorf("unexpected OSV finding %+v", moderate)
+	}
+
+	if added := newFindings(nil, result.Findings); len(added) != 2 {
+		t.Errorf("without a previous scan every finding is new: %+v", added)
+	}
+	previous := &Result{Findings: []Finding{moderate}}
+	if added := newFindings(previous, result.Findings); len(added) != 1 || added[0].ID != high.ID {
+		t.Errorf("only the NVD finding is new: %+v", added)
+	}
+	if !AtLeast(SeverityHigh, "medium") || AtLeast(SeverityMedium, "high") {
+		t.Error("unexpected severity ordering")
+	}
+}
@@ -0,0 +1,85 @@
+package vuln
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"portunix.ai/app/service"
+)
+
+// WatchLogPath returns the log of a detached watcher
+func WatchLogPath() string {
+	return filepath.Join(Dir(), "watch.log")
+}
+
+func watchPIDPath() string {
+	return filepath.Join(Dir(), "watch.pid")
+}
+
+// WatchPID returns the process ID of the running watcher, or 0
+func WatchPID() int {
+	data, err := os.ReadFile(watchPIDPath())
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || !service.IsProcessAlive(pid) {
+		return 0
+	}
+	return pid
+}
+
+// StopWatch stops the running watcher
+func StopWatch() error {
+	pid := WatchPID()
+	if pid == 0 {
+		return fmt.Errorf("vulnerability watch is not running")
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if err := proc.Signal(os.Interrupt); err != nil {
+		if err := proc.Kill(); err != nil {
+			return fmt.Errorf("failed to stop watcher (PID %d): %w", pid, err)
+		}
+	}
+	os.Remove(watchPIDPath())
+	return nil
+}
+
+// Watch scans every interval until ctx is cancelled, passing each result
+// to report. The first scan runs when the previous one is older than
+// interval. A scan error is passed with a nil result and does not stop the
+// watch.
+func Watch(ctx context.Context, interval time.Duration, opts Options, report func(*Result, error)) error {
+	if pid := WatchPID(); pid != 0 && pid != os.Getpid() {
+		return fmt.Errorf("vulnerability watch is already running (PID %d)", pid)
+	}
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(watchPIDPath(), []byte(strconv.Itoa(os.Getpid())), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", watchPIDPath(), err)
+	}
+	defer os.Remove(watchPIDPath())
+
+	wait := time.Duration(0)
+	if last, err := LastResult(); err == nil && last != nil {
+		wait = max(0, interval-time.Since(last.Time))
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+		report(Scan(ctx, opts))
+		wait = interval
+	}
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,9 +25,14 @@ Cache categories:
   builds     - Build artifacts and temporary files
   metadata   - Package definitions and dependency trees
 
+Installer downloads are kept in the downloads category and reused by
+later installs. 'portunix install <package> --offline' installs only
+from cached artifacts, for air-gapped machines and repeated test runs.
+
 Environment variables:
   PORTUNIX_CACHE_DIR       Override cache directory
-  PORTUNIX_CACHE_DISABLED  Set to "true" to disable caching`,
+  PORTUNIX_CACHE_DISABLED  Set to "true" to disable caching
+  PORTUNIX_OFFLINE         Set to "true" to make installs offline`,
 }
 
 var cacheInfoCmd = &cobra.Command{
@@ -155,6 +162,83 @@ var cachePurgeCmd = &cobra.Command{
 	},
 }
 
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove old, broken and oversized cache entries",
+	Long: `Remove expired entries, entries whose file is gone and leftovers of
+interrupted downloads, then shrink categories over their size limit by
+dropping the oldest entries.
+
+With --older-than, entries created longer ago than the given age are
+removed regardless of their TTL. Ages accept Go durations plus days,
+e.g. 12h or 30d.
+
+Examples:
+  portunix cache prune
+  portunix cache prune --older-than 30d`,
+	Run: func(cmd *cobra.Command, args []string) {
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		var maxAge time.Duration
+		if olderThan != "" {
+			var err error
+			if maxAge, err = parseCacheAge(olderThan); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		mgr := cache.NewManager()
+		result, err := mgr.Prune(maxAge)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		if result.RemovedFiles == 0 {
+			fmt.Println("Nothing to prune")
+			return
+		}
+
+		fmt.Printf("Pruned %d entries, freed %s\n",
+			result.RemovedFiles,
+			cache.FormatSize(result.FreedBytes),
+		)
+	},
+}
+
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check cached files against their recorded checksums",
+	Long: `Recompute the SHA256 checksum of every cached file and compare it with
+the checksum recorded when the file was stored. Exits with status 1 when
+a file is missing or corrupted; --remove deletes the failing entries so
+the next install downloads them again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		remove, _ := cmd.Flags().GetBool("remove")
+		mgr := cache.NewManager()
+		result, err := mgr.Verify(remove)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, issue := range result.Issues {
+			fmt.Printf("  ❌ [%s] %s: %s\n", issue.Entry.Category, issue.Entry.Filename, issue.Problem)
+		}
+		if len(result.Issues) == 0 {
+			fmt.Printf("✅ All %d cached entries verified\n", result.Checked)
+			return
+		}
+
+		action := "run with --remove to delete them"
+		if remove {
+			action = "removed"
+		}
+		fmt.Printf("%d of %d entries failed verification (%s)\n", len(result.Issues), result.Checked, action)
+		os.Exit(1)
+	},
+}
+
 var cacheRemoveCmd = &cobra.Command{
 	Use:   "remove <pattern>",
 	Short: "Remove cache entries matching a pattern",
@@ -224,11 +308,30 @@ func printEntry(entry *cache.EntryMeta) {
 		ttlStr,
 		expired,
 	)
+	if entry.Label != "" {
+		fmt.Printf("    Package: %s\n", entry.Label)
+	}
 	if source != "" && source != entry.Filename {
 		fmt.Printf("    Source: %s\n", source)
 	}
 }
 
+// parseCacheAge parses a Go duration with an additional "d" suffix for days
+func parseCacheAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return d, nil
+}
+
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s + strings.Repeat(" ", maxLen-len(s))
@@ -243,4 +346,9 @@ func init() {
 	cacheCmd.AddCommand(cacheCleanCmd)
 	cacheCmd.AddCommand(cachePurgeCmd)
 	cacheCmd.AddCommand(cacheRemoveCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheVerifyCmd)
+
+	cachePruneCmd.Flags().String("older-than", "", "Remove entries older than this age (e.g. 12h, 30d)")
+	cacheVerifyCmd.Flags().Bool("remove", false, "Remove entries that fail verification")
 }
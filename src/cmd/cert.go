@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/cert"
+)
+
+var certCmd = &cobra.Command{
+	Use:   "cert",
+	Short: "Issue and renew TLS certificates with ACME DNS-01",
+	Long: `Issue TLS certificates from Let's Encrypt (or another ACME CA) using DNS-01
+challenges. The challenge TXT records are created through the DNS provider
+API, so wildcard certificates and hosts that are not reachable from the
+internet are supported.
+
+DNS provider credentials are read from the environment:
+  cloudflare  CLOUDFLARE_API_TOKEN (Zone:DNS:Edit permission)
+  route53     AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY [, AWS_SESSION_TOKEN]
+  hetzner     HETZNER_DNS_TOKEN
+
+Certificates are stored in ~/.portunix/certs. 'portunix cert schedule'
+installs a daily 'portunix cert renew --all' run; renewed certificates are
+written again to every location they were exported to.`,
+}
+
+var certIssueCmd = &cobra.Command{
+	Use:   "issue",
+	Short: "Issue a certificate",
+	Example: `  portunix cert issue --domain '*.example.com' --domain example.com --dns cloudflare
+  portunix cert issue --domain api.example.com --dns route53 --email admin@example.com --staging`,
+	Run: func(cmd *cobra.Command, args []string) {
+		domains, _ := cmd.Flags().GetStringSlice("domain")
+		dnsName, _ := cmd.Flags().GetString("dns")
+		email, _ := cmd.Flags().GetString("email")
+		staging, _ := cmd.Flags().GetBool("staging")
+		directory, _ := cmd.Flags().GetString("directory")
+		timeout, _ := cmd.Flags().GetDuration("propagation-timeout")
+
+		if dnsName == "" {
+			fmt.Printf("Error: --dns is required (%s)\n", strings.Join(cert.DNSProviderNames(), ", "))
+			os.Exit(1)
+		}
+		if staging && directory == "" {
+			directory = cert.LetsEncryptStagingURL
+		}
+		dns, err := cert.NewDNSProvider(dnsName)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		c, err := cert.Issue(ctx, cert.IssueOptions{
+			Domains:            domains,
+			Email:              email,
+			DirectoryURL:       directory,
+			PropagationTimeout: timeout,
+			Out:                os.Stdout,
+		}, dns)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("\n✅ Certificate %s stored\n", c.Name)
+		fmt.Printf("  Certificate: %s\n", c.ChainPath())
+		fmt.Printf("  Key:         %s\n", c.KeyPath())
+		fmt.Printf("  Expires:     %s\n", c.NotAfter.Format("2006-01-02"))
+	},
+}
+
+var certListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored certificates",
+	Run: func(cmd *cobra.Command, args []string) {
+		certs, err := cert.List()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		formatJSON, _ := cmd.Flags().GetBool("json")
+		if formatJSON {
+			if certs == nil {
+				certs = []*cert.Certificate{}
+			}
+			data, err := json.MarshalIndent(certs, "", "  ")
+			if err != nil {
+				fmt.Printf("Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		if len(certs) == 0 {
+			fmt.Println("No certificates found")
+			return
+		}
+		fmt.Printf("%-30s %-12s %-12s %-6s %s\n", "NAME", "DNS", "EXPIRES", "DAYS", "DOMAINS")
+		for _, c := range certs {
+			fmt.Printf("%-30s %-12s %-12s %-6d %s\n", c.Name, c.DNSProvider, c.NotAfter.Format("2006-01-02"), c.DaysLeft(), strings.Join(c.Domains, ","))
+		}
+	},
+}
+
+var certRenewCmd = &cobra.Command{
+	Use:   "renew [domain]",
+	Short: "Renew certificates that expire soon",
+	Long: `Renew a certificate, or with --all every stored certificate that expires
+within --days days. Use --force to renew regardless of the expiry date.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		days, _ := cmd.Flags().GetInt("days")
+		force, _ := cmd.Flags().GetBool("force")
+
+		var certs []*cert.Certificate
+		switch {
+		case len(args) == 1:
+			c, err := cert.Load(args[0])
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			certs = append(certs, c)
+		case all:
+			var err error
+			if certs, err = cert.List(); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		default:
+			fmt.Println("Error: specify a domain or --all")
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		failed := 0
+		renewBefore := time.Duration(days) * 24 * time.Hour
+		for _, c := range certs {
+			if !force && !c.NeedsRenewal(renewBefore) {
+				fmt.Printf("%s: valid for %d more days, skipping\n", c.Name, c.DaysLeft())
+				continue
+			}
+			fmt.Printf("%s: renewing (expires %s)\n", c.Name, c.NotAfter.Format("2006-01-02"))
+			renewed, err := cert.Renew(ctx, c, os.Stdout)
+			if err != nil {
+				fmt.Printf("❌ %s: %v\n", c.Name, err)
+				failed++
+				continue
+			}
+			fmt.Printf("✅ %s: renewed, valid until %s\n", renewed.Name, renewed.NotAfter.Format("2006-01-02"))
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+var certExportCmd = &cobra.Command{
+	Use:   "export <domain>",
+	Short: "Export a certificate for Caddy, HAProxy or nginx",
+	Example: `  portunix cert export '*.example.com' --format nginx --out /etc/nginx/certs
+  portunix cert export api.example.com --format haproxy --out /etc/haproxy/certs`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		outDir, _ := cmd.Flags().GetString("out")
+
+		c, err := cert.Load(args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		result, err := c.Export(format, outDir)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Exported %s for %s:\n", c.Name, format)
+		for _, file := range result.Files {
+			fmt.Printf("  %s\n", file)
+		}
+		fmt.Printf("\nConfiguration:\n%s\n", result.Snippet)
+		fmt.Println("\nRenewals update these files; reload the server after 'portunix cert renew'.")
+	},
+}
+
+var certRemoveCmd = &cobra.Command{
+	Use:   "remove <domain>",
+	Short: "Remove a stored certificate",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cert.Remove(args[0]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Removed certificate %s\n", cert.StorageName(args[0]))
+	},
+}
+
+var certScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Install or remove the daily renewal job",
+	Run: func(cmd *cobra.Command, args []string) {
+		remove, _ := cmd.Flags().GetBool("remove")
+		if remove {
+			if err := cert.RemoveSchedule(); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("✅ Renewal schedule removed")
+			return
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		installed, err := cert.InstallSchedule(executable)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Installed %s running '%s cert renew --all'\n", installed, executable)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(certCmd)
+	certCmd.AddCommand(certIssueCmd)
+	certCmd.AddCommand(certListCmd)
+	certCmd.AddCommand(certRenewCmd)
+	certCmd.AddCommand(certExportCmd)
+	certCmd.AddCommand(certRemoveCmd)
+	certCmd.AddCommand(certScheduleCmd)
+
+	certIssueCmd.Flags().StringSlice("domain", nil, "Domain to include (repeatable, wildcards allowed)")
+	certIssueCmd.Flags().String("dns", "", fmt.Sprintf("DNS provider for the challenge %v", cert.DNSProviderNames()))
+	certIssueCmd.Flags().String("email", "", "Contact email of the ACME account")
+	certIssueCmd.Flags().Bool("staging", false, "Use the Let's Encrypt staging CA")
+	certIssueCmd.Flags().String("directory", "", "ACME directory URL (default: Let's Encrypt)")
+	certIssueCmd.Flags().Duration("propagation-timeout", cert.DefaultPropagationTimeout, "Maximum wait for challenge records to appear in DNS")
+	certIssueCmd.MarkFlagRequired("domain")
+
+	certListCmd.Flags().Bool("json", false, "Output in JSON format")
+
+	certRenewCmd.Flags().Bool("all", false, "Renew all stored certificates that expire soon")
+	certRenewCmd.Flags().Int("days", int(cert.DefaultRenewBefore.Hours()/24), "Renew certificates expiring within this many days")
+	certRenewCmd.Flags().Bool("force", false, "Renew even if the certificate is not close to expiry")
+
+	certExportCmd.Flags().String("format", "", fmt.Sprintf("Export format %v", cert.ExportFormats))
+	certExportCmd.Flags().String("out", ".", "Output directory")
+	certExportCmd.MarkFlagRequired("format")
+
+	certScheduleCmd.Flags().Bool("remove", false, "Remove the renewal schedule")
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/cache"
+	"portunix.ai/app/cleanup"
+)
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Analyze and reclaim disk space used by Portunix",
+	Long: `Report and reclaim the disk space of all Portunix subsystems in one place:
+
+  cache        install cache (downloads, HTTP responses, builds, metadata)
+  containers   images, containers, volumes and build cache of Docker and Podman
+  venvs        centralized Python virtual environments
+  vms          cloud images and VM disks of 'portunix virt'
+
+Items unused for longer than --older-than are reclaimable. Volumes and VM
+disks hold data and are only reported, never removed.`,
+}
+
+var cleanupAnalyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Show the disk space used by each subsystem",
+	Example: `  portunix cleanup analyze
+  portunix cleanup analyze --older-than 7d --only cache,venvs
+  portunix cleanup analyze --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		report := analyzeCleanup(cmd)
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		printCleanupReport(report)
+		if report.Reclaimable > 0 {
+			fmt.Printf("\nPreview the cleanup with 'portunix cleanup run --older-than %s --dry-run'\n", report.OlderThan)
+		}
+	},
+}
+
+var cleanupRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Reclaim the space of items unused for longer than --older-than",
+	Long: `Remove what 'cleanup analyze' reports as reclaimable: install cache
+entries older than the age, stopped containers, unused images and build cache
+created before it, Python venvs and cloud images unchanged since. Cloud images
+VMs are created from are kept. Use --dry-run to list the actions first.`,
+	Example: `  portunix cleanup run --older-than 30d --dry-run
+  portunix cleanup run --older-than 30d
+  portunix cleanup run --only containers --older-than 14d`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		report := analyzeCleanup(cmd)
+
+		actions := 0
+		for _, usage := range report.Usage {
+			actions += len(usage.Actions)
+		}
+		if actions == 0 {
+			fmt.Printf("Nothing unused for more than %s to clean up\n", report.OlderThan)
+			return
+		}
+
+		if dryRun {
+			fmt.Printf("Cleanup of items unused for more than %s (dry run):\n", report.OlderThan)
+		} else {
+			fmt.Printf("Cleaning up items unused for more than %s:\n", report.OlderThan)
+		}
+		freed, err := cleanup.Run(report, dryRun, os.Stdout)
+		if dryRun {
+			fmt.Printf("\nUp to %s would be freed\n", cache.FormatSize(report.Reclaimable))
+			return
+		}
+		fmt.Printf("\nFreed %s\n", cache.FormatSize(freed))
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// analyzeCleanup runs the analysis selected by the --older-than and --only
+// flags, exiting on invalid values
+func analyzeCleanup(cmd *cobra.Command) *cleanup.Report {
+	olderThan, _ := cmd.Flags().GetString("older-than")
+	only, _ := cmd.Flags().GetStringSlice("only")
+	age, err := parseCacheAge(olderThan)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	report, err := cleanup.Analyze(cleanup.Options{OlderThan: age, Subsystems: only})
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return report
+}
+
+func printCleanupReport(report *cleanup.Report) {
+	titles := map[string]string{
+		cleanup.SubsystemCache:      "Install cache",
+		cleanup.SubsystemContainers: "Containers",
+		cleanup.SubsystemVenvs:      "Python venvs",
+		cleanup.SubsystemVMs:        "Virtual machines",
+	}
+
+	fmt.Printf("Disk usage (reclaimable: unused for more than %s)\n", report.OlderThan)
+	fmt.Println("==================================================")
+	for _, usage := range report.Usage {
+		fmt.Printf("\n%-40s %10s  %10s reclaimable\n", titles[usage.Subsystem],
+			cache.FormatSize(usage.Size), cache.FormatSize(usage.Reclaimable))
+		if usage.Error != "" {
+			fmt.Printf("  ⚠️  %s\n", usage.Error)
+		}
+		if len(usage.Items) == 0 && usage.Error == "" {
+			fmt.Println("  (nothing stored)")
+		}
+		for _, item := range usage.Items {
+			used := ""
+			if item.Modified != nil {
+				used = "  changed " + item.Modified.Format("2006-01-02")
+			}
+			fmt.Printf("  %-38s %10s  %10s%s\n", truncate(item.Name, 38),
+				cache.FormatSize(item.Size), cache.FormatSize(item.Reclaimable), used)
+			if item.Note != "" {
+				fmt.Printf("    %s\n", item.Note)
+			}
+		}
+	}
+	fmt.Printf("\n%-40s %10s  %10s reclaimable\n", "Total", cache.FormatSize(report.Size), cache.FormatSize(report.Reclaimable))
+}
+
+func init() {
+	rootCmd.AddCommand(cleanupCmd)
+	cleanupCmd.AddCommand(cleanupAnalyzeCmd)
+	cleanupCmd.AddCommand(cleanupRunCmd)
+
+	for _, c := range []*cobra.Command{cleanupAnalyzeCmd, cleanupRunCmd} {
+		c.Flags().String("older-than", "30d", "Items unused for longer are reclaimable (e.g. 12h, 30d)")
+		c.Flags().StringSlice("only", nil, "Only these subsystems: cache, containers, venvs, vms")
+	}
+	cleanupAnalyzeCmd.Flags().Bool("json", false, "Output in JSON format")
+	cleanupRunCmd.Flags().Bool("dry-run", false, "List the actions without removing anything")
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/aihelp"
+	appversion "portunix.ai/app/version"
+	"portunix.ai/portunix/src/dispatcher"
+)
+
+var completionNoDescriptions bool
+
+// completionCmd generates shell completion scripts. The scripts call back
+// into "portunix __complete", which also covers helper commands (see
+// handleHelperCompletion) that cobra cannot complete on its own because they
+// are dispatched before flag parsing.
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate shell completion scripts",
+	Long: `Generate a shell completion script for portunix.
+
+Completion covers built-in commands, plugins and all installed helpers
+(container, python, pft, ...). Helper commands, flags and argument values
+are read from each helper's --help-ai output and cached in
+~/.portunix/cache/completion until the helper binary changes.
+
+Bash:
+  source <(portunix completion bash)
+  # permanently (Linux):
+  portunix completion bash > /etc/bash_completion.d/portunix
+
+Zsh:
+  portunix completion zsh > "${fpath[1]}/_portunix"
+
+Fish:
+  portunix completion fish > ~/.config/fish/completions/portunix.fish
+
+PowerShell:
+  portunix completion powershell | Out-String | Invoke-Expression`,
+	Example: `  portunix completion bash
+  portunix completion zsh --no-descriptions`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return writeCompletionScript(cmd.OutOrStdout(), args[0], !completionNoDescriptions)
+	},
+}
+
+// writeCompletionScript writes the completion script for a shell
+func writeCompletionScript(w io.Writer, shell string, descriptions bool) error {
+	switch shell {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(w, descriptions)
+	case "zsh":
+		if descriptions {
+			return rootCmd.GenZshCompletion(w)
+		}
+		return rootCmd.GenZshCompletionNoDesc(w)
+	case "fish":
+		return rootCmd.GenFishCompletion(w, descriptions)
+	case "powershell":
+		if descriptions {
+			return rootCmd.GenPowerShellCompletionWithDesc(w)
+		}
+		return rootCmd.GenPowerShellCompletion(w)
+	}
+	return fmt.Errorf("unsupported shell: %s", shell)
+}
+
+// handleHelperCompletion answers "portunix __complete" requests for helper
+// commands using the helper's --help-ai description. For other requests it
+// registers placeholder commands for helpers unknown to cobra, so they are
+// offered at the top level, and returns false to let cobra respond.
+func handleHelperCompletion(args []string, out io.Writer) bool {
+	if len(args) == 0 || (args[0] != cobra.ShellCompRequestCmd && args[0] != cobra.ShellCompNoDescRequestCmd) {
+		return false
+	}
+	descriptions := args[0] == cobra.ShellCompRequestCmd
+
+	words := args[1:]
+	for len(words) > 1 && strings.HasPrefix(words[0], "-") {
+		words = words[1:]
+	}
+
+	commands := dispatcher.NewDispatcher(appversion.ProductVersion).HelperCommands()
+	if len(words) < 2 {
+		addHelperCompletionCommands(commands)
+		return false
+	}
+
+	helperPath, ok := commands[words[0]]
+	if !ok {
+		return false
+	}
+	help, err := loadHelperHelp(helperPath)
+	if err != nil {
+		return false
+	}
+
+	result := help.Complete(words[:len(words)-1], words[len(words)-1])
+	writeCompletionResult(out, result, descriptions)
+	return true
+}
+
+// writeCompletionResult prints candidates in cobra's __complete protocol
+func writeCompletionResult(out io.Writer, result aihelp.Completion, descriptions bool) {
+	for _, c := range result.Candidates {
+		if descriptions && c.Description != "" {
+			fmt.Fprintf(out, "%s\t%s\n", c.Value, c.Description)
+		} else {
+			fmt.Fprintln(out, c.Value)
+		}
+	}
+
+	directive := cobra.ShellCompDirectiveNoFileComp
+	if result.Files {
+		directive = cobra.ShellCompDirectiveDefault
+	}
+	fmt.Fprintf(out, ":%d\n", directive)
+}
+
+// addHelperCompletionCommands registers placeholders for helper commands
+// without a cobra counterpart (pft, go, java, ...). They only exist while
+// answering a completion request.
+func addHelperCompletionCommands(commands map[string]string) {
+	for name, helperPath := range commands {
+		if existing, _, err := rootCmd.Find([]string{name}); err == nil && existing != rootCmd {
+			continue
+		}
+
+		short := "Provided by " + strings.TrimSuffix(filepath.Base(helperPath), ".exe")
+		if help, err := loadHelperHelp(helperPath); err == nil && help.Description != "" {
+			short = help.Description
+		}
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                name,
+			Short:              short,
+			DisableFlagParsing: true,
+			Run:                func(cmd *cobra.Command, args []string) {},
+		})
+	}
+}
+
+// helperHelpCache is the on-disk cache entry of a helper's --help-ai output
+type helperHelpCache struct {
+	ModTime time.Time   `json:"mod_time"`
+	Size    int64       `json:"size"`
+	Help    aihelp.Help `json:"help"`
+}
+
+// loadHelperHelp returns the --help-ai description of a helper, cached until
+// the helper binary changes
+func loadHelperHelp(helperPath string) (aihelp.Help, error) {
+	info, err := os.Stat(helperPath)
+	if err != nil {
+		return aihelp.Help{}, err
+	}
+
+	cachePath := completionCachePath(helperPath)
+	if data, err := os.ReadFile(cachePath); err == nil {
+		var cached helperHelpCache
+		if json.Unmarshal(data, &cached) == nil && cached.ModTime.Equal(info.ModTime()) && cached.Size == info.Size() {
+			return cached.Help, nil
+		}
+	}
+
+	help, err := queryHelperHelp(helperPath)
+	if err != nil {
+		return aihelp.Help{}, err
+	}
+
+	if cachePath != "" {
+		if data, err := json.Marshal(helperHelpCache{ModTime: info.ModTime(), Size: info.Size(), Help: help}); err == nil {
+			if os.MkdirAll(filepath.Dir(cachePath), 0755) == nil {
+				_ = os.WriteFile(cachePath, data, 0644)
+			}
+		}
+	}
+	return help, nil
+}
+
+// queryHelperHelp runs "<helper> --help-ai", falling back to
+// "<helper> --list-commands" for helpers without machine-readable help
+func queryHelperHelp(helperPath string) (aihelp.Help, error) {
+	if output, err := exec.Command(helperPath, "--help-ai").Output(); err == nil {
+		var help aihelp.Help
+		if err := json.Unmarshal(output, &help); err == nil && len(help.Commands) > 0 {
+			return help, nil
+		}
+	}
+
+	output, err := exec.Command(helperPath, "--list-commands").Output()
+	if err != nil {
+		return aihelp.Help{}, fmt.Errorf("failed to query %s: %w", filepath.Base(helperPath), err)
+	}
+	return parseListCommands(output), nil
+}
+
+// parseListCommands converts --list-commands output (one command per line)
+func parseListCommands(output []byte) aihelp.Help {
+	var help aihelp.Help
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" {
+			help.Commands = append(help.Commands, aihelp.Command{Name: name})
+		}
+	}
+	return help
+}
+
+// completionCachePath returns the cache file for a helper binary
+func completionCachePath(helperPath string) string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	name := strings.TrimSuffix(filepath.Base(helperPath), ".exe")
+	return filepath.Join(homeDir, ".portunix", "cache", "completion", name+".json")
+}
+
+func init() {
+	// Replace cobra's default completion command with one that documents
+	// helper completion
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	completionCmd.Flags().BoolVar(&completionNoDescriptions, "no-descriptions", false, "Disable completion descriptions")
+	rootCmd.AddCommand(completionCmd)
+}
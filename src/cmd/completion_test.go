@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"portunix.ai/app/aihelp"
+)
+
+func testCompletionHelp() aihelp.Help {
+	return aihelp.Help{
+		Tool: "ptx-container",
+		Commands: []aihelp.Command{
+			{
+				Name:      "container run",
+				Arguments: []aihelp.Argument{{Name: "image", Type: "string", Required: true}},
+				Flags: []aihelp.Flag{
+					{Name: "detach", Shorthand: "d", Type: "boolean"},
+					{Name: "network", Type: "string", Choices: []string{"bridge", "host"}},
+				},
+			},
+			{Name: "container cp", Arguments: []aihelp.Argument{{Name: "source", Type: "path", Required: true}}},
+			{Name: "container network create"},
+			{Name: "container network list"},
+		},
+		GlobalFlags: []aihelp.Flag{{Name: "help-ai", Type: "boolean"}},
+	}
+}
+
+func completionValues(c aihelp.Completion) []string {
+	var values []string
+	for _, candidate := range c.Candidates {
+		values = append(values, candidate.Value)
+	}
+	return values
+}
+
+func TestHelperHelpComplete(t *testing.T) {
+	help := testCompletionHelp()
+	tests := []struct {
+		name       string
+		words      []string
+		toComplete string
+		want       []string
+		files      bool
+	}{
+		{name: "subcommands", words: []string{"container"}, toComplete: "", want: []string{"cp", "network", "run"}},
+		{name: "prefix", words: []string{"container"}, toComplete: "n", want: []string{"network"}},
+		{name: "nested", words: []string{"container", "network"}, toComplete: "", want: []string{"create", "list"}},
+		{name: "flags", words: []string{"container", "run"}, toComplete: "--", want: []string{"--detach", "--network", "--help-ai"}},
+		{name: "flag choices", words: []string{"container", "run", "-d", "--network"}, toComplete: "h", want: []string{"host"}},
+		{name: "after flag value", words: []string{"container", "run", "--network", "host"}, toComplete: "", want: nil},
+		{name: "path argument", words: []string{"container", "cp"}, toComplete: "", want: nil, files: true},
+		{name: "after separator", words: []string{"container", "run", "--"}, toComplete: "", want: nil, files: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := help.Complete(tt.words, tt.toComplete)
+			if values := completionValues(got); !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("Complete(%v, %q) = %v, want %v", tt.words, tt.toComplete, values, tt.want)
+			}
+			if got.Files != tt.files {
+				t.Errorf("Complete(%v, %q) files = %v, want %v", tt.words, tt.toComplete, got.Files, tt.files)
+			}
+		})
+	}
+}
+
+func TestParseListCommands(t *testing.T) {
+	help := parseListCommands([]byte("install\npackage\n\n"))
+	if len(help.Commands) != 2 || help.Commands[1].Name != "package" {
+		t.Errorf("unexpected commands: %+v", help.Commands)
+	}
+}
+
+func TestWriteCompletionScript(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		var buf bytes.Buffer
+		if err := writeCompletionScript(&buf, shell, true); err != nil {
+			t.Fatalf("%s: %v", shell, err)
+		}
+		if !strings.Contains(buf.String(), "portunix") {
+			t.Errorf("%s script does not reference portunix", shell)
+		}
+	}
+	if err := writeCompletionScript(&bytes.Buffer{}, "tcsh", true); err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 
 	"portunix.ai/app/config"
 
@@ -12,33 +14,45 @@ import (
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage Portunix configuration",
-	Long: `Manage Portunix configuration settings.
-
-Configuration is loaded from (in order of priority):
-1. ./portunix-config.yaml (project directory)
-2. ~/.portunix/config.yaml (user directory)
-3. ~/.config/portunix/config.yaml (Linux standard)
-4. Built-in defaults
-
-Available configuration keys:
-- container_runtime: Container runtime to use (docker, podman)
-- verbose: Enable verbose output (true, false)
-- auto_update: Enable automatic updates (true, false)`,
+	Long: `Manage Portunix configuration settings shared by portunix and its helpers.
+
+Configuration is layered (highest priority first):
+1. PORTUNIX_* environment variables (e.g. PORTUNIX_CONTAINER_RUNTIME,
+   PORTUNIX_PYTHON_VENV_DIR for key python.venv_dir)
+2. project: ./portunix-config.yaml (searched in parent directories too)
+3. user:    ~/.portunix/config.yaml
+4. system:  /etc/portunix/config.yaml (%ProgramData%\Portunix\config.yaml on Windows)
+5. Built-in defaults
+
+Keys are dotted paths stored as nested YAML (python.venv_dir becomes
+"python:" / "venv_dir:"). Helpers may define their own namespaced keys.
+Run 'portunix config keys' to list documented keys.`,
 }
 
 // configGetCmd gets a configuration value
 var configGetCmd = &cobra.Command{
 	Use:   "get <key>",
 	Short: "Get a configuration value",
-	Long:  `Get the value of a specific configuration key.`,
+	Long:  `Get the effective value of a configuration key.`,
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		key := args[0]
+		showSource, _ := cmd.Flags().GetBool("show-source")
+
+		if showSource {
+			value, source, ok := config.Lookup(key)
+			if !ok {
+				fmt.Printf("%s is not set\n", key)
+				return
+			}
+			fmt.Printf("%s (%s)\n", value, source)
+			return
+		}
 
 		value, err := config.GetConfigValue(key)
 		if err != nil {
 			fmt.Printf("❌ Error: %v\n", err)
-			return
+			os.Exit(1)
 		}
 
 		fmt.Printf("%s\n", value)
@@ -49,50 +63,115 @@ var configGetCmd = &cobra.Command{
 var configSetCmd = &cobra.Command{
 	Use:   "set <key> <value>",
 	Short: "Set a configuration value",
-	Long:  `Set the value of a specific configuration key.`,
+	Long:  `Set the value of a configuration key in the given scope (default: user).`,
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		key := args[0]
 		value := args[1]
 
-		err := config.SetConfigValue(key, value)
+		scope, err := scopeFlag(cmd)
 		if err != nil {
 			fmt.Printf("❌ Error: %v\n", err)
-			return
+			os.Exit(1)
+		}
+
+		if err := config.Set(scope, key, value); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Configuration updated: %s = %s (%s: %s)\n", key, value, scope, config.ScopePath(scope))
+	},
+}
+
+// configUnsetCmd removes a configuration value
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a configuration value",
+	Long:  `Remove a configuration key from the given scope (default: user).`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		scope, err := scopeFlag(cmd)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
 		}
 
-		fmt.Printf("✅ Configuration updated: %s = %s\n", key, value)
+		if err := config.Unset(scope, args[0]); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Configuration removed: %s (%s)\n", args[0], scope)
 	},
 }
 
-// configShowCmd shows all configuration values
-var configShowCmd = &cobra.Command{
-	Use:   "show",
-	Short: "Show all configuration values",
-	Long:  `Display all current configuration values.`,
+// configListCmd lists all effective configuration values
+var configListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"show"},
+	Short:   "List all configuration values with their source",
+	Long:    `Display all effective configuration values and the layer each comes from.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cfg, err := config.LoadConfig()
+		entries, err := config.List()
 		if err != nil {
 			fmt.Printf("❌ Error loading configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			data, _ := json.MarshalIndent(entries, "", "  ")
+			fmt.Println(string(data))
 			return
 		}
 
 		fmt.Println("⚙️  Portunix Configuration")
 		fmt.Println("========================")
-		fmt.Printf("Container Runtime: %s\n", cfg.ContainerRuntime)
-		fmt.Printf("Verbose: %v\n", cfg.Verbose)
-		fmt.Printf("Auto Update: %v\n", cfg.AutoUpdate)
-
-		fmt.Println("\n💡 Configuration file locations (in priority order):")
-		fmt.Println("  1. ./portunix-config.yaml (project directory)")
-		fmt.Println("  2. ~/.portunix/config.yaml (user directory)")
-		fmt.Println("  3. ~/.config/portunix/config.yaml (Linux standard)")
+		for _, e := range entries {
+			fmt.Printf("%-24s %-24s [%s]\n", e.Key, e.Value, e.Source)
+		}
+
+		fmt.Println("\n💡 Configuration files (in priority order):")
+		for i := len(config.Scopes) - 1; i >= 0; i-- {
+			scope := config.Scopes[i]
+			fmt.Printf("  %-8s %s\n", scope+":", config.ScopePath(scope))
+		}
 	},
 }
 
+// configKeysCmd lists documented configuration keys
+var configKeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "List documented configuration keys",
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, k := range config.KnownKeys() {
+			fmt.Printf("%-20s %s\n", k.Key, k.Description)
+			fmt.Printf("%-20s env: %s\n", "", config.EnvVarName(k.Key))
+		}
+	},
+}
+
+// scopeFlag parses the --scope flag
+func scopeFlag(cmd *cobra.Command) (config.Scope, error) {
+	value, _ := cmd.Flags().GetString("scope")
+	for _, scope := range config.Scopes {
+		if string(scope) == value {
+			return scope, nil
+		}
+	}
+	return "", fmt.Errorf("invalid scope: %s (must be system, user or project)", value)
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configSetCmd)
-	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configKeysCmd)
+
+	configGetCmd.Flags().Bool("show-source", false, "Show which layer the value comes from")
+	configSetCmd.Flags().String("scope", string(config.ScopeUser), "Scope to write: system, user, project")
+	configUnsetCmd.Flags().String("scope", string(config.ScopeUser), "Scope to modify: system, user, project")
+	configListCmd.Flags().Bool("json", false, "Output in JSON format")
 }
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
 
 	"portunix.ai/app/compose"
 	"portunix.ai/app/container"
@@ -93,8 +96,17 @@ Supported installation types:
   • go: Install Go development environment
   • claude-code: Install Claude Code CLI
 
+Templates:
+  --template NAME replaces the installation type with a named environment
+  of the template catalog (python-dev, go-dev, node-dev, ansible-test): its
+  base image, tools, cache volumes, ports and environment. Flags given after
+  it override the template. Custom templates are *.yaml files in
+  ~/.portunix/container-templates or PORTUNIX_CONTAINER_TEMPLATE_PATH; see
+  'portunix container templates'.
+
 Examples:
   portunix container run-in-container default
+  portunix container run-in-container --template go-dev --keep-running
   portunix container run-in-container nodejs --image ubuntu:22.04
   portunix container run-in-container python --image alpine:latest
   portunix container run-in-container claude-code --keep-running
@@ -107,6 +119,13 @@ Additional parameters (all Docker/Podman flags supported):
   -p, --port: Map container ports to host
   -e, --env: Set environment variables`,
 	Run: func(cmd *cobra.Command, args []string) {
+		args, err := container.ApplyTemplate(args, container.TemplateDirs())
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			fmt.Println("\n💡 Hint: List templates with 'portunix container templates'")
+			return
+		}
+
 		runtime, err := container.GetSelectedRuntime()
 		if err != nil {
 			fmt.Printf("❌ Error: %v\n", err)
@@ -128,6 +147,44 @@ Additional parameters (all Docker/Podman flags supported):
 	},
 }
 
+// containerTemplatesCmd lists the environment templates of run-in-container
+var containerTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List environment templates for run-in-container",
+	Long: `List the environment templates usable with
+'portunix container run-in-container --template NAME'.
+
+A template defines the base image, the tools installed with
+'portunix install', cache directories kept in portunix-cache-<name>
+volumes shared by all containers, published ports and environment
+variables. Custom templates are *.yaml files in the directories of
+PORTUNIX_CONTAINER_TEMPLATE_PATH or ~/.portunix/container-templates; a
+custom template overrides the built-in template of the same name:
+
+  description: Rust development
+  image: ubuntu:22.04
+  tools: [rust, git]
+  caches:
+    - name: cargo
+      path: /root/.cargo/registry
+  ports: ["8080"]
+  env: [RUST_BACKTRACE=1]
+  workdir: /workspace`,
+	Run: func(cmd *cobra.Command, args []string) {
+		templates, err := container.ListTemplates(container.TemplateDirs())
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		formatJSON, _ := cmd.Flags().GetBool("json")
+		if err := container.PrintTemplates(os.Stdout, templates, formatJSON); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
 // containerExecCmd represents the universal exec command
 var containerExecCmd = &cobra.Command{
 	Use:                "exec [flags] <container-name> <command>",
@@ -154,41 +211,51 @@ Examples:
   portunix container exec nodejs-dev sh -c "node --version"
 
 The command preserves all arguments and supports interactive mode for shell access.
-Use -i or --interactive for interactive sessions.`,
+Use -i or --interactive for interactive sessions.
+
+Scripts and CI can rely on the result: portunix exits with the exit code of
+the command, or with 125 when the command could not be run. Without a
+terminal on stdin no pseudo-TTY is allocated, and piped input is passed to
+the command:
+  -T, --no-tty         Never allocate a pseudo-TTY (keeps stdin with -i)
+  -d, --detach         Run the command in the background
+
+  echo "SELECT 1" | portunix container exec db psql -U postgres
+  portunix container exec -d web ./warm-cache.sh
+
+Flags before the container name set up the environment of the command:
+  --env-file FILE      Set the variables of a .env file
+  -e, --env KEY=VALUE  Set a variable; KEY alone forwards the host variable
+  --copy-env PATTERN   Forward host variables matching a glob, e.g. 'AWS_*'
+  -w, --workdir DIR    Working directory inside the container
+  -u, --user USER      User (name or UID[:GID]) running the command
+
+  portunix container exec --env-file .env --workdir /app --user 1000 app npm test
+  portunix container exec --copy-env 'CI*' --copy-env GITHUB_TOKEN ci-runner make`,
 	Args: cobra.MinimumNArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		// Manual flag parsing since DisableFlagParsing is true
-		var interactive bool
-		var containerName string
-		var command []string
-
-		// Parse arguments manually to extract flags
-		filteredArgs := []string{}
-		for _, arg := range args {
-			if arg == "-i" || arg == "--interactive" {
-				interactive = true
-			} else if arg == "-it" {
-				interactive = true
-				filteredArgs = append(filteredArgs, "-t") // Keep -t for docker/podman
-			} else {
-				filteredArgs = append(filteredArgs, arg)
-			}
+		// Manual flag parsing since DisableFlagParsing is true; flags end at
+		// the container name, the command keeps its own flags
+		opts, rest, err := container.ParseExecFlags(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(containerExecFailed)
 		}
 
-		if len(filteredArgs) < 2 {
-			fmt.Printf("❌ Error: Usage: portunix container exec [flags] <container-name> <command>\n")
-			return
+		if len(rest) < 2 {
+			fmt.Fprintf(os.Stderr, "❌ Error: Usage: portunix container exec [flags] <container-name> <command>\n")
+			os.Exit(containerExecFailed)
 		}
 
-		containerName = filteredArgs[0]
-		command = filteredArgs[1:]
+		containerName := rest[0]
+		command := rest[1:]
 
 		// Get configured runtime
 		runtime, err := container.GetSelectedRuntime()
 		if err != nil {
-			fmt.Printf("❌ Error: %v\n", err)
-			fmt.Println("\n💡 Hint: Check available runtimes with 'portunix container info'")
-			return
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			fmt.Fprintln(os.Stderr, "\n💡 Hint: Check available runtimes with 'portunix container info'")
+			os.Exit(containerExecFailed)
 		}
 
 		// Silent execution - only show command output
@@ -196,20 +263,37 @@ Use -i or --interactive for interactive sessions.`,
 		// Delegate to appropriate runtime implementation
 		switch runtime {
 		case "docker":
-			err = docker.ExecCommandWithOptions(containerName, command, interactive)
+			err = docker.ExecWithOptions(containerName, command, opts)
 		case "podman":
-			err = podman.ExecCommandWithOptions(containerName, command, interactive)
+			err = podman.ExecWithOptions(containerName, command, opts)
 		default:
-			fmt.Printf("❌ Error: Unsupported runtime: %s\n", runtime)
-			return
+			fmt.Fprintf(os.Stderr, "❌ Error: Unsupported runtime: %s\n", runtime)
+			os.Exit(containerExecFailed)
 		}
 
 		if err != nil {
-			fmt.Printf("❌ Error executing command: %v\n", err)
+			os.Exit(containerExecExitCode(err))
 		}
 	},
 }
 
+// containerExecFailed is the exit code of container exec when the command
+// could not be run, as with docker and podman. Errors go to stderr, so they
+// are told apart from the output and exit code of the command.
+const containerExecFailed = 125
+
+// containerExecExitCode returns the exit code of a failed exec: the exit
+// code of the command, which the runtime passes on, or containerExecFailed
+// when the runtime could not be started
+func containerExecExitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	fmt.Fprintf(os.Stderr, "❌ Error executing command: %v\n", err)
+	return containerExecFailed
+}
+
 // containerInfoCmd shows information about container runtimes
 var containerInfoCmd = &cobra.Command{
 	Use:   "info",
@@ -846,6 +930,7 @@ func init() {
 	rootCmd.AddCommand(containerCmd)
 	containerCmd.AddCommand(containerRunCmd)
 	containerCmd.AddCommand(containerRunInContainerCmd)
+	containerCmd.AddCommand(containerTemplatesCmd)
 	containerCmd.AddCommand(containerExecCmd)
 	containerCmd.AddCommand(containerCpCmd)
 	containerCmd.AddCommand(containerInfoCmd)
@@ -869,6 +954,15 @@ func init() {
 
 	// Add interactive flag to exec command
 	containerExecCmd.Flags().BoolP("interactive", "i", false, "Keep STDIN open and allocate pseudo-TTY")
+	containerExecCmd.Flags().BoolP("no-tty", "T", false, "Do not allocate a pseudo-TTY")
+	containerExecCmd.Flags().BoolP("detach", "d", false, "Run the command in the background")
+	containerExecCmd.Flags().String("env-file", "", "Set the variables of a .env file")
+	containerExecCmd.Flags().StringSliceP("env", "e", []string{}, "Set environment variables (KEY alone forwards the host variable)")
+	containerExecCmd.Flags().String("copy-env", "", "Forward host variables matching a glob pattern")
+	containerExecCmd.Flags().StringP("workdir", "w", "", "Working directory inside the container")
+	containerExecCmd.Flags().StringP("user", "u", "", "User running the command")
+
+	containerTemplatesCmd.Flags().Bool("json", false, "Output in JSON format")
 
 	// Add force flag to remove command
 	containerRemoveCmd.Flags().BoolP("force", "f", false, "Force removal of running container")
@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 	"os/exec"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -176,3 +177,16 @@ func TestContainerExecIntegration(t *testing.T) {
 	// 3. Verify output
 	// 4. Clean up container
 }
+
+func TestContainerExecExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runs sh")
+	}
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	if code := containerExecExitCode(err); code != 3 {
+		t.Errorf("expected the exit code of the command, got %d", code)
+	}
+	if code := containerExecExitCode(exec.Command("/nonexistent/runtime").Run()); code != containerExecFailed {
+		t.Errorf("expected %d when the runtime cannot start, got %d", containerExecFailed, code)
+	}
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/container"
+	"portunix.ai/app/daemon"
+	"portunix.ai/app/progress"
+	"portunix.ai/app/system"
+	"portunix.ai/app/version"
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run portunix as a local gRPC service",
+	Long: `Run portunix as a long-lived local service that IDE plugins and the MCP
+server call over gRPC instead of starting portunix for every command.
+
+The daemon listens on a Unix socket in ~/.portunix/daemon (a loopback port on
+Windows, written to ~/.portunix/daemon/endpoint) and accepts only calls
+carrying the token from ~/.portunix/daemon/token in the "authorization:
+Bearer <token>" metadata. Messages are JSON encoded with the gRPC
+content-subtype "json".
+
+Service portunix.daemon.v1.Daemon:
+  Ping             daemon version, PID and start time
+  SystemInfo       same data as 'portunix system info --json'
+  Install          install a package {package, variant, dry_run}
+  InstallStream    the same, streaming progress events {event} and the
+                   result {result} (server streaming)
+  ListContainers   containers of the preferred runtime
+  Container        {action: start|stop|rm|logs, name}
+  PftSync          synchronize a PFT project {dir, dry_run}
+  Shutdown         stop the daemon
+
+'portunix service install daemon' starts the daemon at every login through
+the service manager of the OS.`,
+}
+
+var daemonStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the daemon in the background",
+	Run: func(cmd *cobra.Command, args []string) {
+		foreground, _ := cmd.Flags().GetBool("foreground")
+		if foreground {
+			if err := runDaemon(); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if resp, err := pingDaemon(); err == nil {
+			fmt.Printf("Daemon is already running (PID %d)\n", resp.PID)
+			return
+		}
+
+		self, err := os.Executable()
+		if err != nil {
+			fmt.Printf("Error: failed to locate portunix executable: %v\n", err)
+			os.Exit(1)
+		}
+		pid, err := daemon.StartBackground(self, "daemon", "start", "--foreground")
+		if err != nil {
+			fmt.Printf("Error: failed to start daemon: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Wait until the daemon answers
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			if _, err := pingDaemon(); err == nil {
+				break
+			}
+			if time.Now().After(deadline) {
+				fmt.Printf("Error: daemon did not start, see %s\n", daemon.LogPath())
+				os.Exit(1)
+			}
+			time.Sleep(200 * time.Millisecond)
+		}
+		fmt.Printf("✓ Daemon started (PID %d)\n", pid)
+		fmt.Printf("  Token: %s\n", daemon.TokenPath())
+	},
+}
+
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running daemon",
+	Run: func(cmd *cobra.Command, args []string) {
+		if stopped, err := stopInstalledService("daemon"); stopped {
+			if err != nil {
+				fmt.Printf("Error: failed to stop daemon: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("✓ Daemon service stopped until the next login")
+			return
+		}
+
+		client, err := daemon.Connect()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer client.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := client.Shutdown(ctx); err != nil {
+			fmt.Printf("Error: failed to stop daemon: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✓ Daemon stopped")
+	},
+}
+
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the daemon is running",
+	Run: func(cmd *cobra.Command, args []string) {
+		formatJSON, _ := cmd.Flags().GetBool("json")
+
+		resp, err := pingDaemon()
+		endpoint, _ := daemon.ReadEndpoint()
+		if formatJSON {
+			status := map[string]interface{}{
+				"running":  err == nil,
+				"endpoint": endpoint,
+				"token":    daemon.TokenPath(),
+			}
+			if err == nil {
+				status["pid"] = resp.PID
+				status["version"] = resp.Version
+				status["started"] = resp.Started
+			}
+			data, _ := json.MarshalIndent(status, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		if err != nil {
+			fmt.Println("Daemon: not running")
+			fmt.Println("  Start it with 'portunix daemon start'")
+			return
+		}
+		fmt.Println("Daemon:   running")
+		fmt.Printf("PID:      %d\n", resp.PID)
+		fmt.Printf("Version:  %s\n", resp.Version)
+		fmt.Printf("Uptime:   %s\n", time.Since(resp.Started).Round(time.Second))
+		fmt.Printf("Endpoint: %s\n", endpoint)
+		fmt.Printf("Token:    %s\n", daemon.TokenPath())
+	},
+}
+
+// runDaemon serves the API until interrupted or shut down by a client
+func runDaemon() error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate portunix executable: %w", err)
+	}
+	token, err := daemon.EnsureToken()
+	if err != nil {
+		return err
+	}
+	lis, err := daemon.Listen()
+	if err != nil {
+		return err
+	}
+	defer daemon.Cleanup()
+
+	server := daemon.NewServer(&commandBackend{self: self}, token, version.ProductVersion)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		server.Stop()
+	}()
+
+	fmt.Printf("Portunix daemon listening on %s (PID %d)\n", lis.Addr(), os.Getpid())
+	return server.Serve(lis)
+}
+
+func pingDaemon() (*daemon.PingResponse, error) {
+	client, err := daemon.Connect()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return client.Ping(ctx)
+}
+
+// commandBackend performs daemon operations by running portunix itself, so
+// they behave exactly like the corresponding CLI commands
+type commandBackend struct {
+	self string
+}
+
+func (b *commandBackend) SystemInfo(ctx context.Context) (*system.SystemInfo, error) {
+	return system.GetSystemInfo()
+}
+
+func (b *commandBackend) ListContainers(ctx context.Context) (*daemon.ListContainersResponse, error) {
+	runtime := container.GetPreferredRuntime()
+	if runtime == container.RuntimeNone {
+		return nil, fmt.Errorf("no container runtime available")
+	}
+	output, err := exec.CommandContext(ctx, string(runtime), "ps", "-a", "--format", "{{json .}}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s ps failed: %w", runtime, err)
+	}
+	containers, err := parseContainers(output)
+	if err != nil {
+		return nil, err
+	}
+	return &daemon.ListContainersResponse{Runtime: string(runtime), Containers: containers}, nil
+}
+
+func (b *commandBackend) Run(ctx context.Context, dir string, args []string, onProgress func(progress.Event)) (*daemon.CommandResponse, error) {
+	c := exec.CommandContext(ctx, b.self, args...)
+	c.Dir = dir
+	output, err := progress.CombinedOutput(c, onProgress)
+
+	resp := &daemon.CommandResponse{Command: append([]string{"portunix"}, args...), Output: string(output)}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		resp.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// parseContainers parses "ps --format '{{json .}}'" output of docker
+// (one object per line, Names as string) or podman (Names as array)
+func parseContainers(output []byte) ([]daemon.Container, error) {
+	var containers []daemon.Container
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var c map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			return nil, fmt.Errorf("unexpected container list output: %w", err)
+		}
+
+		id := jsonString(c["ID"])
+		if id == "" {
+			id = jsonString(c["Id"])
+		}
+		name := jsonString(c["Names"])
+		if name == "" {
+			name = id
+		}
+		containers = append(containers, daemon.Container{
+			ID:     id,
+			Name:   name,
+			Image:  jsonString(c["Image"]),
+			State:  jsonString(c["State"]),
+			Status: jsonString(c["Status"]),
+		})
+	}
+	return containers, scanner.Err()
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+	daemonCmd.AddCommand(daemonStartCmd)
+	daemonCmd.AddCommand(daemonStopCmd)
+	daemonCmd.AddCommand(daemonStatusCmd)
+
+	daemonStartCmd.Flags().Bool("foreground", false, "Run in the foreground instead of detaching")
+	daemonStatusCmd.Flags().Bool("json", false, "Output status as JSON")
+}
@@ -288,8 +288,7 @@ Examples:
 
 		err := docker.ExecCommand(containerID, command)
 		if err != nil {
-			fmt.Printf("Error executing command: %v\n", err)
-			return
+			os.Exit(containerExecExitCode(err))
 		}
 	},
 }
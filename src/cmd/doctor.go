@@ -0,0 +1,582 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/container"
+	"portunix.ai/app/system"
+	appversion "portunix.ai/app/version"
+	"portunix.ai/portunix/pkg/logging"
+	"portunix.ai/portunix/src/dispatcher"
+)
+
+// Doctor check results
+const (
+	doctorOK      = "ok"
+	doctorWarning = "warning"
+	doctorFailed  = "failed"
+	doctorSkipped = "skipped"
+)
+
+// doctorCategories is the display and fix priority order of check categories
+var doctorCategories = []string{"system", "helpers", "containers", "languages", "network"}
+
+// doctorCheck is the result of a single environment check
+type doctorCheck struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`      // instruction shown to the user
+	Fixable  bool   `json:"fixable,omitempty"`  // doctor --fix can repair it
+	Repaired bool   `json:"repaired,omitempty"` // repaired by this run
+
+	// repair performs a safe automatic fix for --fix; recheck verifies it
+	repair  func() error
+	recheck func() doctorCheck
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the environment and suggest fixes",
+	Long: `Run all environment checks in one pass and print prioritized fix
+instructions:
+
+  system       OS detection, Portunix home and log directories
+  helpers      helper binaries (ptx-*) present, executable, versions matching
+  containers   Docker/Podman availability, daemon state, compose preflight
+  languages    Python, Node.js and Java availability
+  network      access to GitHub, Docker Hub, PyPI and npm registries
+
+With --fix, safe repairs are applied automatically (creating missing
+directories, making helper binaries executable, refreshing the cached
+container runtime detection). Everything else is reported as a command to
+run. Exit code is 1 when any check failed.`,
+	Example: `  portunix doctor
+  portunix doctor --fix
+  portunix doctor --category containers --json
+  portunix doctor --offline`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fix, _ := cmd.Flags().GetBool("fix")
+		formatJSON, _ := cmd.Flags().GetBool("json")
+		offline, _ := cmd.Flags().GetBool("offline")
+		categories, _ := cmd.Flags().GetStringSlice("category")
+
+		checks := runDoctorChecks(categories, offline)
+		if fix {
+			checks = repairDoctorChecks(checks, !formatJSON)
+		}
+		sortDoctorChecks(checks)
+
+		if formatJSON {
+			data, err := json.MarshalIndent(map[string]interface{}{
+				"version": appversion.ProductVersion,
+				"checks":  checks,
+				"summary": summarizeDoctorChecks(checks),
+			}, "", "  ")
+			if err != nil {
+				fmt.Printf("Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		} else {
+			printDoctorReport(checks)
+		}
+
+		if summarizeDoctorChecks(checks)[doctorFailed] > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// runDoctorChecks runs the checks of the selected categories (all when empty)
+func runDoctorChecks(categories []string, offline bool) []doctorCheck {
+	selected := func(category string) bool {
+		if len(categories) == 0 {
+			return true
+		}
+		for _, c := range categories {
+			if strings.EqualFold(c, category) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var info *system.SystemInfo
+	var infoErr error
+	if selected("system") || selected("containers") {
+		info, infoErr = system.GetSystemInfo()
+	}
+
+	var checks []doctorCheck
+	if selected("system") {
+		checks = append(checks, doctorSystemChecks(info, infoErr)...)
+	}
+	if selected("helpers") {
+		checks = append(checks, doctorHelperChecks(dispatcher.NewDispatcher(appversion.ProductVersion))...)
+	}
+	if selected("containers") {
+		checks = append(checks, doctorContainerChecks(info)...)
+	}
+	if selected("languages") {
+		checks = append(checks, doctorLanguageChecks()...)
+	}
+	if selected("network") {
+		checks = append(checks, doctorNetworkChecks(offline)...)
+	}
+	return checks
+}
+
+// doctorSystemChecks checks OS detection and the Portunix directories
+func doctorSystemChecks(info *system.SystemInfo, infoErr error) []doctorCheck {
+	var checks []doctorCheck
+
+	osCheck := doctorCheck{Category: "system", Name: "OS detection", Status: doctorOK}
+	if infoErr != nil {
+		osCheck.Status = doctorFailed
+		osCheck.Message = infoErr.Error()
+		osCheck.Fix = "Run 'portunix system info --json' and report the output"
+	} else {
+		osCheck.Message = strings.TrimSpace(fmt.Sprintf("%s %s %s (%s)", info.OS, info.Version, info.Variant, info.Architecture))
+	}
+	checks = append(checks, osCheck)
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return append(checks, doctorCheck{Category: "system", Name: "Portunix home", Status: doctorFailed,
+			Message: err.Error(), Fix: "Set the HOME (USERPROFILE on Windows) environment variable"})
+	}
+	checks = append(checks,
+		doctorDirectoryCheck("Portunix home", filepath.Join(home, ".portunix")),
+		doctorDirectoryCheck("Log directory", logging.LogDir("")),
+	)
+	return checks
+}
+
+// doctorDirectoryCheck verifies that a directory exists and is writable
+func doctorDirectoryCheck(name, dir string) doctorCheck {
+	check := doctorCheck{Category: "system", Name: name, Status: doctorOK, Message: dir}
+
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		check.Status = doctorWarning
+		check.Message = dir + " does not exist"
+		check.Fix = "mkdir -p " + dir
+		check.Fixable = true
+		check.repair = func() error { return os.MkdirAll(dir, 0755) }
+		check.recheck = func() doctorCheck { return doctorDirectoryCheck(name, dir) }
+	case err != nil:
+		check.Status = doctorFailed
+		check.Message = err.Error()
+	case !info.IsDir():
+		check.Status = doctorFailed
+		check.Message = dir + " is not a directory"
+		check.Fix = "Remove or rename " + dir
+	default:
+		probe, err := os.CreateTemp(dir, ".doctor-*")
+		if err != nil {
+			check.Status = doctorFailed
+			check.Message = dir + " is not writable"
+			check.Fix = "Fix the permissions of " + dir
+			break
+		}
+		probe.Close()
+		os.Remove(probe.Name())
+	}
+	return check
+}
+
+// doctorHelperChecks checks that all registered helpers are installed,
+// executable and compatible with this portunix version
+func doctorHelperChecks(disp *dispatcher.Dispatcher) []doctorCheck {
+	suffix := ""
+	if runtime.GOOS == "windows" {
+		suffix = ".exe"
+	}
+
+	var names []string
+	for name := range disp.ListHelpers() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	checks := make([]doctorCheck, 0, len(names))
+	for _, name := range names {
+		checks = append(checks, checkHelperBinary(name, filepath.Join(disp.GetExecutableDir(), name+suffix), appversion.ProductVersion))
+	}
+	return checks
+}
+
+// checkHelperBinary checks a single helper binary at path
+func checkHelperBinary(name, path, mainVersion string) doctorCheck {
+	check := doctorCheck{Category: "helpers", Name: name, Status: doctorOK}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		check.Status = doctorWarning
+		check.Message = "not installed (its commands are unavailable)"
+		check.Fix = "portunix helpers update " + name
+		return check
+	}
+
+	if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+		check.Status = doctorFailed
+		check.Message = path + " is not executable"
+		check.Fix = "chmod +x " + path
+		check.Fixable = true
+		check.repair = func() error { return os.Chmod(path, info.Mode()|0755) }
+		check.recheck = func() doctorCheck { return checkHelperBinary(name, path, mainVersion) }
+		return check
+	}
+
+	output, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		check.Status = doctorFailed
+		check.Message = fmt.Sprintf("failed to run: %v", err)
+		check.Fix = "portunix helpers update " + name + " --force"
+		return check
+	}
+
+	helperVer := helperVersion(strings.TrimSpace(string(output)))
+	check.Message = helperVer
+	if !sameMinorVersion(helperVer, mainVersion) {
+		check.Status = doctorFailed
+		check.Message = fmt.Sprintf("%s does not match portunix %s", helperVer, mainVersion)
+		check.Fix = "portunix helpers update " + name
+	}
+	return check
+}
+
+// doctorContainerChecks checks container runtimes and compose readiness
+func doctorContainerChecks(info *system.SystemInfo) []doctorCheck {
+	runtimeCheck := doctorCheck{Category: "containers", Name: "Container runtime", Status: doctorOK}
+	if info == nil || info.Capabilities == nil {
+		runtimeCheck.Status = doctorSkipped
+		runtimeCheck.Message = "system information unavailable"
+		return []doctorCheck{runtimeCheck}
+	}
+	caps := info.Capabilities
+
+	var found []string
+	if caps.Docker {
+		state := "daemon running"
+		if !caps.DockerDaemonRunning {
+			state = "daemon not running"
+		}
+		found = append(found, fmt.Sprintf("Docker %s (%s)", caps.DockerVersion, state))
+	}
+	if caps.Podman {
+		found = append(found, "Podman "+caps.PodmanVersion)
+	}
+
+	switch {
+	case !caps.Docker && !caps.Podman:
+		runtimeCheck.Status = doctorWarning
+		runtimeCheck.Message = "neither Docker nor Podman is installed"
+		runtimeCheck.Fix = "portunix install podman"
+	case caps.Docker && !caps.DockerDaemonRunning && !caps.Podman:
+		runtimeCheck.Status = doctorFailed
+		runtimeCheck.Message = strings.Join(found, ", ")
+		runtimeCheck.Fix = dockerStartInstruction()
+	default:
+		runtimeCheck.Message = strings.Join(found, ", ")
+	}
+
+	// Cached runtime detection used by ptx-container may be stale after
+	// installing or removing a runtime
+	preferred := container.GetPreferredRuntime()
+	if preferred == container.RuntimeNone && (caps.Docker || caps.Podman) {
+		runtimeCheck.Status = doctorWarning
+		runtimeCheck.Message += " (cached runtime detection is outdated)"
+		runtimeCheck.Fix = "portunix container check --refresh"
+		runtimeCheck.Fixable = true
+		runtimeCheck.repair = func() error {
+			_, err := container.RefreshContainerCapabilities()
+			return err
+		}
+		runtimeCheck.recheck = func() doctorCheck { return doctorContainerChecks(info)[0] }
+	}
+
+	composeCheck := doctorCheck{Category: "containers", Name: "Compose", Status: doctorOK}
+	compose := caps.ComposeInfo
+	switch {
+	case !caps.Docker && !caps.Podman:
+		composeCheck.Status = doctorSkipped
+		composeCheck.Message = "no container runtime"
+	case compose == nil || !compose.Available:
+		composeCheck.Status = doctorWarning
+		composeCheck.Message = "no compose tool found"
+		if caps.Podman {
+			composeCheck.Fix = "pip install podman-compose"
+		} else {
+			composeCheck.Fix = "portunix install docker"
+		}
+	case !compose.DaemonReady:
+		composeCheck.Status = doctorWarning
+		composeCheck.Message = fmt.Sprintf("%s %s: %s", compose.Type, compose.Version, compose.WarningMessage)
+		composeCheck.Fix = "portunix container compose-preflight"
+	default:
+		composeCheck.Message = strings.TrimSpace(compose.Type + " " + compose.Version)
+	}
+
+	return []doctorCheck{runtimeCheck, composeCheck}
+}
+
+// dockerStartInstruction returns the platform command to start the Docker daemon
+func dockerStartInstruction() string {
+	switch runtime.GOOS {
+	case "windows", "darwin":
+		return "Start Docker Desktop"
+	}
+	return "sudo systemctl start docker"
+}
+
+// doctorLanguageChecks checks that common language runtimes are available
+func doctorLanguageChecks() []doctorCheck {
+	languages := []struct {
+		name     string
+		binaries []string
+		args     []string
+		pkg      string
+	}{
+		{name: "Python", binaries: []string{"python3", "python"}, args: []string{"--version"}, pkg: "python"},
+		{name: "Node.js", binaries: []string{"node"}, args: []string{"--version"}, pkg: "nodejs"},
+		{name: "Java", binaries: []string{"java"}, args: []string{"-version"}, pkg: "java"},
+	}
+
+	var checks []doctorCheck
+	for _, lang := range languages {
+		check := doctorCheck{Category: "languages", Name: lang.name, Status: doctorWarning,
+			Message: "not found in PATH", Fix: "portunix install " + lang.pkg}
+		for _, binary := range lang.binaries {
+			path, err := exec.LookPath(binary)
+			if err != nil {
+				continue
+			}
+			// java prints its version to stderr
+			output, err := exec.Command(path, lang.args...).CombinedOutput()
+			if err != nil {
+				check.Status = doctorFailed
+				check.Message = fmt.Sprintf("%s is installed but fails to run: %v", path, err)
+				break
+			}
+			check.Status = doctorOK
+			check.Message = firstOutputLine(string(output))
+			check.Fix = ""
+			break
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// doctorRegistries are the endpoints needed by install, container and helper updates
+var doctorRegistries = []struct {
+	name string
+	url  string
+}{
+	{"GitHub", "https://api.github.com"},
+	{"Docker Hub", "https://registry-1.docker.io/v2/"},
+	{"PyPI", "https://pypi.org/simple/"},
+	{"npm registry", "https://registry.npmjs.org/"},
+}
+
+// doctorNetworkChecks checks that package and image registries are reachable.
+// Any HTTP response counts as reachable (Docker Hub answers 401 without a token).
+func doctorNetworkChecks(offline bool) []doctorCheck {
+	checks := make([]doctorCheck, len(doctorRegistries))
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	var wg sync.WaitGroup
+	for i, registry := range doctorRegistries {
+		checks[i] = doctorCheck{Category: "network", Name: registry.name, Status: doctorSkipped, Message: "--offline"}
+		if offline {
+			continue
+		}
+
+		wg.Add(1)
+		go func(check *doctorCheck, url string) {
+			defer wg.Done()
+			start := time.Now()
+			resp, err := client.Head(url)
+			if err != nil {
+				check.Status = doctorWarning
+				check.Message = err.Error()
+				check.Fix = "Check the network connection and the HTTPS_PROXY setting"
+				return
+			}
+			resp.Body.Close()
+			check.Status = doctorOK
+			check.Message = fmt.Sprintf("%s reachable (%d ms)", url, time.Since(start).Milliseconds())
+		}(&checks[i], registry.url)
+	}
+	wg.Wait()
+	return checks
+}
+
+// repairDoctorChecks applies the safe repairs of fixable checks
+func repairDoctorChecks(checks []doctorCheck, verbose bool) []doctorCheck {
+	for i, check := range checks {
+		if check.repair == nil || check.Status == doctorOK {
+			continue
+		}
+		if verbose {
+			fmt.Printf("🔧 Fixing %s: %s\n", check.Name, check.Fix)
+		}
+		if err := check.repair(); err != nil {
+			if verbose {
+				fmt.Printf("   ❌ %v\n", err)
+			}
+			continue
+		}
+		checks[i] = check.recheck()
+		checks[i].Repaired = true
+	}
+	if verbose {
+		fmt.Println()
+	}
+	return checks
+}
+
+// sortDoctorChecks orders checks by category, failures first within a category
+func sortDoctorChecks(checks []doctorCheck) {
+	sort.SliceStable(checks, func(i, j int) bool {
+		ci, cj := doctorCategoryRank(checks[i].Category), doctorCategoryRank(checks[j].Category)
+		if ci != cj {
+			return ci < cj
+		}
+		return doctorStatusRank(checks[i].Status) < doctorStatusRank(checks[j].Status)
+	})
+}
+
+func doctorCategoryRank(category string) int {
+	for i, c := range doctorCategories {
+		if c == category {
+			return i
+		}
+	}
+	return len(doctorCategories)
+}
+
+func doctorStatusRank(status string) int {
+	switch status {
+	case doctorFailed:
+		return 0
+	case doctorWarning:
+		return 1
+	case doctorOK:
+		return 2
+	}
+	return 3
+}
+
+// doctorFixes returns the checks needing attention, failures first. Helper
+// updates of the same severity are merged into a single command.
+func doctorFixes(checks []doctorCheck) []doctorCheck {
+	const helperUpdate = "portunix helpers update "
+
+	var fixes []doctorCheck
+	merged := make(map[string]int) // status -> index of merged helper fix
+	for _, check := range checks {
+		if check.Fix == "" || (check.Status != doctorFailed && check.Status != doctorWarning) {
+			continue
+		}
+		if check.Category == "helpers" && strings.HasPrefix(check.Fix, helperUpdate) && len(strings.Fields(check.Fix)) == 4 {
+			if i, ok := merged[check.Status]; ok {
+				fixes[i].Name = "helpers"
+				fixes[i].Fix += " " + check.Name
+				continue
+			}
+			merged[check.Status] = len(fixes)
+		}
+		fixes = append(fixes, check)
+	}
+	sort.SliceStable(fixes, func(i, j int) bool {
+		return doctorStatusRank(fixes[i].Status) < doctorStatusRank(fixes[j].Status)
+	})
+	return fixes
+}
+
+// summarizeDoctorChecks counts checks by status
+func summarizeDoctorChecks(checks []doctorCheck) map[string]int {
+	summary := map[string]int{doctorOK: 0, doctorWarning: 0, doctorFailed: 0, doctorSkipped: 0}
+	for _, check := range checks {
+		summary[check.Status]++
+	}
+	return summary
+}
+
+func printDoctorReport(checks []doctorCheck) {
+	fmt.Printf("🩺 Portunix Doctor (%s)\n", appversion.ProductVersion)
+
+	category := ""
+	for _, check := range checks {
+		if check.Category != category {
+			category = check.Category
+			fmt.Printf("\n%s\n", strings.ToUpper(category[:1])+category[1:])
+		}
+		icon := map[string]string{doctorOK: "✅", doctorWarning: "⚠️ ", doctorFailed: "❌", doctorSkipped: "⏭️ "}[check.Status]
+		repaired := ""
+		if check.Repaired {
+			repaired = " (fixed)"
+		}
+		fmt.Printf("  %s %-20s %s%s\n", icon, check.Name, check.Message, repaired)
+	}
+
+	summary := summarizeDoctorChecks(checks)
+	fmt.Printf("\nSummary: %d passed, %d warnings, %d failed, %d skipped\n",
+		summary[doctorOK], summary[doctorWarning], summary[doctorFailed], summary[doctorSkipped])
+
+	fixes := doctorFixes(checks)
+	if len(fixes) == 0 {
+		fmt.Println("\n✅ No problems found")
+		return
+	}
+
+	fmt.Println("\nRecommended fixes (most important first):")
+	fixable := false
+	for i, check := range fixes {
+		marker := ""
+		if check.Fixable {
+			marker = " [--fix]"
+			fixable = true
+		}
+		fmt.Printf("  %d. %s: %s%s\n", i+1, check.Name, check.Fix, marker)
+	}
+	if fixable {
+		fmt.Println("\nRun 'portunix doctor --fix' to apply the repairs marked [--fix].")
+	}
+}
+
+// firstOutputLine returns the first non-empty line of command output
+func firstOutputLine(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().Bool("fix", false, "Apply safe automatic repairs")
+	doctorCmd.Flags().Bool("json", false, "Output results as JSON")
+	doctorCmd.Flags().Bool("offline", false, "Skip network checks")
+	doctorCmd.Flags().StringSlice("category", nil, "Only run these categories (system, helpers, containers, languages, network)")
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCheckHelperBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script helper")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ptx-test")
+
+	if check := checkHelperBinary("ptx-test", path, "v1.2.0"); check.Status != doctorWarning {
+		t.Errorf("missing helper: expected warning, got %+v", check)
+	}
+
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho ptx-test version v1.2.3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	check := checkHelperBinary("ptx-test", path, "v1.2.0")
+	if check.Status != doctorFailed || !check.Fixable {
+		t.Fatalf("non-executable helper: expected fixable failure, got %+v", check)
+	}
+
+	checks := repairDoctorChecks([]doctorCheck{check}, false)
+	if checks[0].Status != doctorOK || !checks[0].Repaired || checks[0].Message != "v1.2.3" {
+		t.Errorf("expected repaired helper, got %+v", checks[0])
+	}
+
+	if check := checkHelperBinary("ptx-test", path, "v2.0.0"); check.Status != doctorFailed {
+		t.Errorf("version mismatch: expected failure, got %+v", check)
+	}
+}
+
+func TestDoctorDirectoryCheck(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+
+	check := doctorDirectoryCheck("Log directory", dir)
+	if check.Status != doctorWarning || check.repair == nil {
+		t.Fatalf("expected fixable warning, got %+v", check)
+	}
+	checks := repairDoctorChecks([]doctorCheck{check}, false)
+	if checks[0].Status != doctorOK {
+		t.Errorf("expected directory to be created, got %+v", checks[0])
+	}
+}
+
+func TestDoctorFixesOrder(t *testing.T) {
+	checks := []doctorCheck{
+		{Category: "network", Name: "PyPI", Status: doctorWarning, Fix: "check proxy"},
+		{Category: "languages", Name: "Java", Status: doctorOK},
+		{Category: "containers", Name: "Container runtime", Status: doctorFailed, Fix: "start docker"},
+		{Category: "system", Name: "Log directory", Status: doctorWarning, Fix: "mkdir"},
+		{Category: "helpers", Name: "ptx-go", Status: doctorWarning, Fix: "portunix helpers update ptx-go"},
+		{Category: "helpers", Name: "ptx-java", Status: doctorWarning, Fix: "portunix helpers update ptx-java"},
+	}
+	sortDoctorChecks(checks)
+	if checks[0].Category != "system" || checks[len(checks)-1].Category != "network" {
+		t.Errorf("unexpected check order: %+v", checks)
+	}
+
+	fixes := doctorFixes(checks)
+	if len(fixes) != 4 || fixes[0].Name != "Container runtime" || fixes[1].Name != "Log directory" {
+		t.Fatalf("unexpected fix order: %+v", fixes)
+	}
+	if fixes[2].Fix != "portunix helpers update ptx-go ptx-java" {
+		t.Errorf("helper updates not merged: %q", fixes[2].Fix)
+	}
+}
@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"portunix.ai/app/edge"
+	"portunix.ai/app/virt"
 )
 
 var edgeCmd = &cobra.Command{
@@ -45,9 +51,31 @@ var edgeInitCmd = &cobra.Command{
 var edgeDeployCmd = &cobra.Command{
 	Use:   "deploy [config-path]",
 	Short: "Deploy edge infrastructure",
-	Long:  `Deploy edge infrastructure based on configuration file.`,
-	Args:  cobra.MaximumNArgs(1),
+	Long: `Deploy edge infrastructure.
+
+With --provider a new VPS is created through the provider API, the template
+(Caddy + WireGuard by default) is installed on it through a ptxbook and the
+connection state is stored in ~/.portunix/edge/<name>. The API token is read
+from HCLOUD_TOKEN (hetzner) or DIGITALOCEAN_TOKEN (digitalocean), or from the
+secret store ('portunix secret set edge/hetzner'). Re-running the command for
+the same name resumes an interrupted deployment.
+
+Without --provider the local edge configuration is deployed.
+
+Examples:
+  portunix edge deploy --provider hetzner --name edge1 --domain example.com --email admin@example.com
+  portunix edge deploy --provider digitalocean --name edge2 --region ams3 --dry-run
+  portunix edge deploy ./edge-config`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		providerName, _ := cmd.Flags().GetString("provider")
+		if providerName != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("config path cannot be combined with --provider")
+			}
+			return runEdgeProviderDeploy(cmd, providerName)
+		}
+
 		configPath := "./edge-config"
 		if len(args) > 0 {
 			configPath = args[0]
@@ -58,6 +86,316 @@ var edgeDeployCmd = &cobra.Command{
 	},
 }
 
+func runEdgeProviderDeploy(cmd *cobra.Command, providerName string) error {
+	opts := edge.DeployOptions{}
+	opts.Name, _ = cmd.Flags().GetString("name")
+	opts.Template, _ = cmd.Flags().GetString("template")
+	opts.Region, _ = cmd.Flags().GetString("region")
+	opts.Size, _ = cmd.Flags().GetString("size")
+	opts.Image, _ = cmd.Flags().GetString("image")
+	opts.Domain, _ = cmd.Flags().GetString("domain")
+	opts.AdminEmail, _ = cmd.Flags().GetString("email")
+	opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+	if name, _ := cmd.Flags().GetString("cloud-init-template"); name != "" {
+		userData, err := renderEdgeCloudInit(cmd, name, opts.Name)
+		if err != nil {
+			return err
+		}
+		opts.UserData = userData
+	}
+
+	var provider edge.Provider
+	var err error
+	if opts.DryRun && os.Getenv(edge.ProviderTokenEnv(providerName)) == "" {
+		// A plan does not need API access
+		provider, err = edge.NewProviderWithURL(providerName, "", "")
+	} else {
+		provider, err = edge.NewProvider(providerName)
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	deployer := &edge.Deployer{Provider: provider, Out: os.Stdout}
+	d, err := deployer.Deploy(ctx, opts)
+	if err != nil {
+		if d != nil && d.ServerID != "" {
+			fmt.Printf("\nDeployment %s failed; state kept in %s\n", d.Name, edge.DeploymentDir(d.Name))
+			fmt.Printf("Re-run the same command to resume, or remove it with: portunix edge destroy %s\n", d.Name)
+		}
+		return err
+	}
+	if opts.DryRun {
+		return nil
+	}
+
+	fmt.Printf("\n✅ Edge %s deployed\n", d.Name)
+	fmt.Printf("  Public IP:  %s\n", d.PublicIP)
+	fmt.Printf("  SSH:        ssh -i %s %s@%s\n", d.SSHKey, d.SSHUser, d.PublicIP)
+	if d.Domain != "" {
+		fmt.Printf("  URL:        https://%s\n", d.Domain)
+	}
+	if d.WireGuard.ClientConfig != "" {
+		fmt.Printf("  WireGuard:  sudo wg-quick up %s\n", d.WireGuard.ClientConfig)
+	}
+	return nil
+}
+
+var edgeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List VPS edge deployments",
+	Long:  `List edge deployments created with 'portunix edge deploy --provider' and their stored state.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deployments, err := edge.ListDeployments()
+		if err != nil {
+			return err
+		}
+
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if jsonOutput {
+			if deployments == nil {
+				deployments = []*edge.Deployment{}
+			}
+			// Never print the WireGuard server key
+			redacted := make([]edge.Deployment, len(deployments))
+			for i, d := range deployments {
+				redacted[i] = *d
+				redacted[i].WireGuard.ServerPrivateKey = ""
+			}
+			data, err := json.MarshalIndent(redacted, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(deployments) == 0 {
+			fmt.Println("No edge deployments found")
+			return nil
+		}
+		fmt.Printf("%-20s %-14s %-16s %-12s %s\n", "NAME", "PROVIDER", "PUBLIC IP", "STATUS", "DOMAIN")
+		for _, d := range deployments {
+			fmt.Printf("%-20s %-14s %-16s %-12s %s\n", d.Name, d.Provider, d.PublicIP, d.Status, d.Domain)
+		}
+		return nil
+	},
+}
+
+var edgeDestroyCmd = &cobra.Command{
+	Use:   "destroy <name>",
+	Short: "Delete a VPS edge deployment",
+	Long:  `Delete the VPS of an edge deployment at the provider and remove its local state and keys.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := edge.LoadDeployment(args[0])
+		if err != nil {
+			return err
+		}
+
+		yes, _ := cmd.Flags().GetBool("yes")
+		if !yes {
+			fmt.Printf("This deletes %s server %s (%s) and all local state of %s. Continue? [y/N]: ", d.Provider, d.ServerID, d.PublicIP, d.Name)
+			var answer string
+			fmt.Scanln(&answer)
+			if answer != "y" && answer != "Y" {
+				fmt.Println("Aborted")
+				return nil
+			}
+		}
+
+		provider, err := edge.NewProvider(d.Provider)
+		if err != nil {
+			return err
+		}
+		deployer := &edge.Deployer{Provider: provider, Out: os.Stdout}
+		if err := deployer.Destroy(cmd.Context(), d); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Edge %s destroyed\n", d.Name)
+		return nil
+	},
+}
+
+var edgeRouteCmd = &cobra.Command{
+	Use:   "route",
+	Short: "Manage reverse proxy routes on an edge host",
+	Long: `Manage reverse proxy routes of a VPS edge deployment.
+
+HTTP routes are served by Caddy with automatic TLS, TCP routes (--tcp) are
+proxied by HAProxy. The configuration is updated over SSH, validated and
+reloaded gracefully; routes are tracked in the local deployment state.
+
+Examples:
+  portunix edge route add api.example.com --target 10.100.0.2:8080
+  portunix edge route add db.example.com --target 10.100.0.2:5432 --tcp 5432
+  portunix edge route list
+  portunix edge route remove api.example.com`,
+}
+
+var edgeRouteAddCmd = &cobra.Command{
+	Use:   "add <domain>",
+	Short: "Add or update a route",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, _ := cmd.Flags().GetString("target")
+		tcpPort, _ := cmd.Flags().GetInt("tcp")
+		if target == "" {
+			return fmt.Errorf("--target host:port is required")
+		}
+
+		d, err := loadEdgeDeployment(cmd)
+		if err != nil {
+			return err
+		}
+		added, err := d.SetRoute(edge.Route{Domain: args[0], Target: target, TCPPort: tcpPort})
+		if err != nil {
+			return err
+		}
+		if err := applyEdgeRoutes(d); err != nil {
+			return err
+		}
+
+		action := "Added"
+		if !added {
+			action = "Updated"
+		}
+		if tcpPort != 0 {
+			fmt.Printf("✅ %s TCP route %s:%d -> %s on %s\n", action, args[0], tcpPort, target, d.Name)
+		} else {
+			fmt.Printf("✅ %s route https://%s -> %s on %s\n", action, args[0], target, d.Name)
+			fmt.Printf("   Point the DNS record of %s to %s\n", args[0], d.PublicIP)
+		}
+		return nil
+	},
+}
+
+var edgeRouteListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List routes",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := loadEdgeDeployment(cmd)
+		if err != nil {
+			return err
+		}
+
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if jsonOutput {
+			routes := d.Routes
+			if routes == nil {
+				routes = []edge.Route{}
+			}
+			data, err := json.MarshalIndent(routes, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(d.Routes) == 0 {
+			fmt.Printf("No routes on edge %s\n", d.Name)
+			return nil
+		}
+		fmt.Printf("%-30s %-6s %-8s %s\n", "DOMAIN", "PROTO", "PORT", "TARGET")
+		for _, r := range d.Routes {
+			port := "443"
+			if r.TCPPort != 0 {
+				port = fmt.Sprintf("%d", r.TCPPort)
+			}
+			fmt.Printf("%-30s %-6s %-8s %s\n", r.Domain, r.Protocol(), port, r.Target)
+		}
+		return nil
+	},
+}
+
+var edgeRouteRemoveCmd = &cobra.Command{
+	Use:   "remove <domain>",
+	Short: "Remove the routes of a domain",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tcpPort, _ := cmd.Flags().GetInt("tcp")
+
+		d, err := loadEdgeDeployment(cmd)
+		if err != nil {
+			return err
+		}
+		removed := d.RemoveRoute(args[0], tcpPort)
+		if removed == 0 {
+			return fmt.Errorf("no route for %s on edge %s", args[0], d.Name)
+		}
+		if err := applyEdgeRoutes(d); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Removed %d route(s) for %s from %s\n", removed, args[0], d.Name)
+		return nil
+	},
+}
+
+// loadEdgeDeployment returns the deployment selected with --edge, or the
+// only one when there is exactly one
+// renderEdgeCloudInit renders a template of the cloud-init library for a
+// new edge server; the host name defaults to the deployment name
+func renderEdgeCloudInit(cmd *cobra.Command, name, hostname string) (string, error) {
+	var dirs []string
+	if dir, _ := cmd.Flags().GetString("template-dir"); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	t, err := virt.LoadCloudInitTemplate(name, virt.CloudInitTemplateDirs(dirs...))
+	if err != nil {
+		return "", err
+	}
+	args, _ := cmd.Flags().GetStringArray("var")
+	vars, err := virt.ParseCloudInitVars(args)
+	if err != nil {
+		return "", err
+	}
+	if _, set := vars["hostname"]; !set && t.HasVar("hostname") {
+		vars["hostname"] = hostname
+	}
+	return t.Render(vars)
+}
+
+func loadEdgeDeployment(cmd *cobra.Command) (*edge.Deployment, error) {
+	name, _ := cmd.Flags().GetString("edge")
+	if name != "" {
+		return edge.LoadDeployment(name)
+	}
+	deployments, err := edge.ListDeployments()
+	if err != nil {
+		return nil, err
+	}
+	switch len(deployments) {
+	case 0:
+		return nil, fmt.Errorf("no edge deployments found; create one with 'portunix edge deploy --provider'")
+	case 1:
+		return deployments[0], nil
+	default:
+		return nil, fmt.Errorf("multiple edge deployments found; select one with --edge")
+	}
+}
+
+// applyEdgeRoutes pushes the routes to the edge host and saves the state
+// only when the host accepted them
+func applyEdgeRoutes(d *edge.Deployment) error {
+	if d.Status != edge.StatusDeployed {
+		return fmt.Errorf("edge %s is not deployed (status: %s)", d.Name, d.Status)
+	}
+	client, err := edge.ConnectSSH(d, 30*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to connect to edge %s: %w", d.Name, err)
+	}
+	defer client.Close()
+
+	if err := edge.ApplyRoutes(client, d, os.Stdout); err != nil {
+		return err
+	}
+	return d.Save()
+}
+
 var edgeStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show edge infrastructure status",
@@ -81,7 +419,10 @@ var edgeStopCmd = &cobra.Command{
 var edgeStartCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start edge infrastructure services",
-	Long:  `Start all edge infrastructure services including reverse proxy, VPN, and security services.`,
+	Long: `Start all edge infrastructure services including reverse proxy, VPN, and security services.
+
+'portunix service install edge' starts them through the service manager of the
+host; on Linux run 'loginctl enable-linger' so user services start at boot.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		manager := edge.NewManager()
 		return manager.Start()
@@ -185,6 +526,14 @@ func init() {
 	edgeCmd.AddCommand(edgeLogsCmd)
 	edgeCmd.AddCommand(edgeConfigCmd)
 	edgeCmd.AddCommand(edgeInstallCmd)
+	edgeCmd.AddCommand(edgeListCmd)
+	edgeCmd.AddCommand(edgeDestroyCmd)
+	edgeCmd.AddCommand(edgeRouteCmd)
+
+	// Add route subcommands
+	edgeRouteCmd.AddCommand(edgeRouteAddCmd)
+	edgeRouteCmd.AddCommand(edgeRouteListCmd)
+	edgeRouteCmd.AddCommand(edgeRouteRemoveCmd)
 
 	// Add config subcommands
 	edgeConfigCmd.AddCommand(edgeConfigAddDomainCmd)
@@ -193,4 +542,26 @@ func init() {
 	// Add flags
 	edgeLogsCmd.Flags().BoolP("follow", "f", false, "Follow log output")
 	edgeLogsCmd.Flags().IntP("tail", "t", 100, "Number of lines to show from end of logs")
+
+	edgeDeployCmd.Flags().String("provider", "", fmt.Sprintf("Provision a new VPS at provider %v", edge.ProviderNames()))
+	edgeDeployCmd.Flags().String("template", "caddy-wireguard", fmt.Sprintf("Deployment template %v", edge.TemplateNames()))
+	edgeDeployCmd.Flags().String("name", "edge", "Deployment and server name")
+	edgeDeployCmd.Flags().String("region", "", "Provider region (provider default when empty)")
+	edgeDeployCmd.Flags().String("size", "", "Server size/type (provider default when empty)")
+	edgeDeployCmd.Flags().String("image", "", "OS image (provider default when empty)")
+	edgeDeployCmd.Flags().String("domain", "", "Domain served by Caddy with automatic TLS")
+	edgeDeployCmd.Flags().String("email", "", "Admin email for the ACME account")
+	edgeDeployCmd.Flags().Bool("dry-run", false, "Show the deployment plan without creating anything")
+	edgeDeployCmd.Flags().String("cloud-init-template", "", "Cloud-init template run on first boot of a new server (e.g. edge-proxy)")
+	edgeDeployCmd.Flags().StringArray("var", nil, "Cloud-init template variable name=value (repeatable)")
+	edgeDeployCmd.Flags().String("template-dir", "", "Directory with custom cloud-init templates")
+
+	edgeListCmd.Flags().Bool("json", false, "Output in JSON format")
+	edgeDestroyCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+
+	edgeRouteCmd.PersistentFlags().String("edge", "", "Edge deployment name (default: the only deployment)")
+	edgeRouteAddCmd.Flags().String("target", "", "Upstream host:port, e.g. a WireGuard peer address")
+	edgeRouteAddCmd.Flags().Int("tcp", 0, "Public TCP port for a raw TCP route (HAProxy)")
+	edgeRouteRemoveCmd.Flags().Int("tcp", 0, "Remove only the TCP route on this port")
+	edgeRouteListCmd.Flags().Bool("json", false, "Output in JSON format")
 }
@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/edge"
+	"portunix.ai/app/fleet"
+	"portunix.ai/app/remote"
+)
+
+var fleetCmd = &cobra.Command{
+	Use:   "fleet",
+	Short: "Manage the inventory of remote hosts and their facts",
+	Long: `Keep an inventory of managed hosts with the facts collected from them:
+OS, installed packages and tools, WireGuard tunnels and TLS certificates.
+
+The inventory lives in ~/.portunix/fleet (PORTUNIX_FLEET_DIR), one JSON file
+per host. Initialized with --git it is a git repository: every change is
+committed and 'fleet sync' shares it with the team.
+
+Inventory names work with --host, e.g. 'portunix --host web1 install docker'.`,
+}
+
+var fleetInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create the inventory, optionally backed by git",
+	Example: `  portunix fleet init --git
+  portunix fleet init --clone git@github.com:example/fleet.git`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useGit, _ := cmd.Flags().GetBool("git")
+		cloneURL, _ := cmd.Flags().GetString("clone")
+		if err := fleet.Init(cloneURL, useGit); err != nil {
+			return err
+		}
+		switch {
+		case cloneURL != "":
+			fmt.Printf("✅ Inventory cloned from %s into %s\n", cloneURL, fleet.Dir())
+		case useGit:
+			fmt.Printf("✅ Git-backed inventory created in %s\n", fleet.Dir())
+		default:
+			fmt.Printf("✅ Inventory created in %s\n", fleet.Dir())
+		}
+		return nil
+	},
+}
+
+var fleetAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a host to the inventory or update its address and tags",
+	Long: `Add a host to the inventory. The address is anything --host accepts:
+[user@]host[:port], an ~/.ssh/config alias or an edge deployment name. It
+defaults to the name, so edge deployments and SSH aliases need no address.`,
+	Example: `  portunix fleet add web1 --address deploy@203.0.113.10 --tag web --tag prod
+  portunix fleet add edge1`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		address, _ := cmd.Flags().GetString("address")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+
+		h, err := fleet.Load(name)
+		if err != nil {
+			if err := fleet.ValidateHostName(name); err != nil {
+				return err
+			}
+			h = &fleet.Host{Name: name, Address: name, AddedAt: time.Now()}
+			if _, err := edge.LoadDeployment(name); err == nil {
+				h.Tags = append(h.Tags, "edge")
+			}
+		}
+		if address != "" {
+			h.Address = address
+		}
+		if _, err := remote.ParseTarget(h.Address); err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			if !h.HasTag(tag) {
+				h.Tags = append(h.Tags, tag)
+			}
+		}
+		if err := fleet.Save(h, "fleet: add "+name); err != nil {
+			return err
+		}
+		fmt.Printf("✅ %s (%s) is in the inventory; collect its facts with: portunix fleet scan %s\n", name, h.Address, name)
+		return nil
+	},
+}
+
+var fleetRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a host from the inventory",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := fleet.Remove(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("✅ %s removed from the inventory\n", args[0])
+		return nil
+	},
+}
+
+var fleetScanCmd = &cobra.Command{
+	Use:   "scan [name...]",
+	Short: "Collect facts from hosts over SSH",
+	Long: `Connect to hosts over SSH and record their facts. Nothing needs to be
+installed on the hosts; a POSIX shell is enough. Without names all hosts
+(or those with --tag) are scanned in parallel.`,
+	Example: `  portunix fleet scan
+  portunix fleet scan web1 web2
+  portunix fleet scan --tag prod`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+		if parallel < 1 {
+			parallel = 1
+		}
+
+		var hosts []*fleet.Host
+		if len(args) > 0 {
+			for _, name := range args {
+				h, err := fleet.Load(name)
+				if err != nil {
+					return err
+				}
+				hosts = append(hosts, h)
+			}
+		} else {
+			all, err := fleet.List()
+			if err != nil {
+				return err
+			}
+			hosts = fleet.Select(all, fleet.Filter{Tags: tags})
+		}
+		if len(hosts) == 0 {
+			fmt.Println("No hosts to scan; add one with 'portunix fleet add'")
+			return nil
+		}
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		sem := make(chan struct{}, parallel)
+		failed := 0
+		for _, h := range hosts {
+			wg.Add(1)
+			go func(h *fleet.Host) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				facts, err := scanFleetHost(h)
+				mu.Lock()
+				defer mu.Unlock()
+				h.ScannedAt = time.Now()
+				if err != nil {
+					failed++
+					h.ScanError = err.Error()
+					fmt.Printf("❌ %-20s %v\n", h.Name, err)
+				} else {
+					h.Facts, h.ScanError = facts, ""
+					fmt.Printf("✅ %-20s %s, %d packages, %d tunnels, %d certificates\n",
+						h.Name, facts.OSName, len(facts.Packages), len(facts.Tunnels), len(facts.Certificates))
+				}
+				if err := fleet.Save(h, "fleet: scan "+h.Name); err != nil {
+					fmt.Printf("   failed to save %s: %v\n", h.Name, err)
+				}
+			}(h)
+		}
+		wg.Wait()
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d hosts could not be scanned", failed, len(hosts))
+		}
+		return nil
+	},
+}
+
+func scanFleetHost(h *fleet.Host) (*fleet.Facts, error) {
+	target, err := remote.Resolve(h.Address)
+	if err != nil {
+		return nil, err
+	}
+	return fleet.Scan(&remote.SSHRunner{Target: target})
+}
+
+var fleetListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List hosts matching a query",
+	Long: `List inventory hosts, optionally filtered by their facts from the last
+scan. --missing matches hosts lacking any of the given packages or tools;
+common names such as docker also match distribution package names
+(docker.io, docker-ce).`,
+	Example: `  portunix fleet list --os ubuntu --missing docker
+  portunix fleet list --tag prod --has caddy
+  portunix fleet list --expiring 14`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var filter fleet.Filter
+		filter.OS, _ = cmd.Flags().GetString("os")
+		filter.Tags, _ = cmd.Flags().GetStringSlice("tag")
+		filter.Has, _ = cmd.Flags().GetStringSlice("has")
+		filter.Missing, _ = cmd.Flags().GetStringSlice("missing")
+		filter.ExpiringDays, _ = cmd.Flags().GetInt("expiring")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		all, err := fleet.List()
+		if err != nil {
+			return err
+		}
+		hosts := fleet.Select(all, filter)
+
+		if jsonOutput {
+			if hosts == nil {
+				hosts = []*fleet.Host{}
+			}
+			data, err := json.MarshalIndent(hosts, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(hosts) == 0 {
+			if len(all) == 0 {
+				fmt.Println("The inventory is empty; add hosts with 'portunix fleet add'")
+			} else {
+				fmt.Println("No hosts match the query")
+			}
+			return nil
+		}
+		fmt.Printf("%-20s %-28s %-24s %-18s %s\n", "NAME", "ADDRESS", "OS", "SCANNED", "TAGS")
+		for _, h := range hosts {
+			osName, scanned := "-", "never"
+			if h.Facts != nil {
+				osName = strings.TrimSpace(h.Facts.OS + " " + h.Facts.OSVersion)
+			}
+			if !h.ScannedAt.IsZero() {
+				scanned = h.ScannedAt.Format("2006-01-02 15:04")
+			}
+			if h.ScanError != "" {
+				scanned += " (failed)"
+			}
+			fmt.Printf("%-20s %-28s %-24s %-18s %s\n", h.Name, h.Address, osName, scanned, strings.Join(h.Tags, ","))
+		}
+		return nil
+	},
+}
+
+var fleetShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show the facts of a host",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		h, err := fleet.Load(args[0])
+		if err != nil {
+			return err
+		}
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if jsonOutput {
+			data, err := json.MarshalIndent(h, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Host:     %s\n", h.Name)
+		fmt.Printf("Address:  %s\n", h.Address)
+		if len(h.Tags) > 0 {
+			fmt.Printf("Tags:     %s\n", strings.Join(h.Tags, ", "))
+		}
+		if h.ScanError != "" {
+			fmt.Printf("Last scan failed: %s\n", h.ScanError)
+		}
+		f := h.Facts
+		if f == nil {
+			fmt.Printf("\nNot scanned yet; run: portunix fleet scan %s\n", h.Name)
+			return nil
+		}
+		fmt.Printf("Scanned:  %s\n", h.ScannedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Hostname: %s\n", f.Hostname)
+		fmt.Printf("OS:       %s\n", f.OSName)
+		fmt.Printf("Kernel:   %s (%s)\n", f.Kernel, f.Arch)
+		fmt.Printf("Packages: %d installed\n", len(f.Packages))
+		fmt.Printf("Tools:    %s\n", strings.Join(f.Tools, ", "))
+		if len(f.Tunnels) > 0 {
+			fmt.Println("\nTunnels:")
+			for _, t := range f.Tunnels {
+				fmt.Printf("  %-10s %-10s %-18s %d peers\n", t.Interface, t.Type, t.Address, t.Peers)
+			}
+		}
+		if len(f.Certificates) > 0 {
+			fmt.Println("\nCertificates:")
+			for _, c := range f.Certificates {
+				fmt.Printf("  %-40s expires %s (%d days)\n", strings.Join(c.Domains, ","), c.NotAfter.Format("2006-01-02"), c.DaysLeft())
+			}
+		}
+		return nil
+	},
+}
+
+var fleetSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Pull and push the git-backed inventory",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := fleet.Sync(); err != nil {
+			return err
+		}
+		fmt.Println("✅ Inventory synchronized")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fleetCmd)
+	fleetCmd.AddCommand(fleetInitCmd, fleetAddCmd, fleetRemoveCmd, fleetScanCmd, fleetListCmd, fleetShowCmd, fleetSyncCmd)
+
+	fleetInitCmd.Flags().Bool("git", false, "Make the inventory a git repository")
+	fleetInitCmd.Flags().String("clone", "", "Clone the inventory from a git repository")
+
+	fleetAddCmd.Flags().String("address", "", "SSH address [user@]host[:port] (default: the name)")
+	fleetAddCmd.Flags().StringSlice("tag", nil, "Tag the host (repeatable)")
+
+	fleetScanCmd.Flags().StringSlice("tag", nil, "Scan only hosts with this tag")
+	fleetScanCmd.Flags().Int("parallel", 8, "Hosts scanned at the same time")
+
+	fleetListCmd.Flags().String("os", "", "Only hosts running this OS, e.g. ubuntu")
+	fleetListCmd.Flags().StringSlice("tag", nil, "Only hosts with this tag")
+	fleetListCmd.Flags().StringSlice("has", nil, "Only hosts with this package or tool")
+	fleetListCmd.Flags().StringSlice("missing", nil, "Only hosts lacking this package or tool")
+	fleetListCmd.Flags().Int("expiring", 0, "Only hosts with a certificate expiring within this many days")
+	fleetListCmd.Flags().Bool("json", false, "Output in JSON format")
+
+	fleetShowCmd.Flags().Bool("json", false, "Output in JSON format")
+}
@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/harden"
+	"portunix.ai/app/shellquote"
+)
+
+var hardenCmd = &cobra.Command{
+	Use:   "harden",
+	Short: "Apply security hardening to this host",
+	Long: `Apply security hardening profiles to this host.
+
+Firewall changes save a snapshot of the previous state first, so they can
+be undone with 'harden firewall --rollback'. fail2ban jails protect SSH and
+the reverse proxy against brute force; 'harden status' summarizes both.`,
+}
+
+var hardenFirewallCmd = &cobra.Command{
+	Use:   "firewall",
+	Short: "Apply a firewall profile (nftables, ufw or Windows Firewall)",
+	Long: `Generate and apply firewall rules from a profile. Inbound traffic is
+denied except for the profile's ports; outbound traffic is allowed.
+
+Profiles:
+  edge         SSH, HTTP, HTTPS (incl. HTTP/3) and WireGuard 51820/udp
+  server       SSH only; open service ports with --allow
+  workstation  no inbound connections
+
+The backend is detected automatically: Windows Firewall on Windows, ufw when
+installed, nftables otherwise. Use --dry-run to print the exact rules and
+commands. After applying interactively, the change must be confirmed within
+--confirm-timeout or it is rolled back, so a rule that cuts off your SSH
+session undoes itself.`,
+	Example: `  portunix harden firewall --profile edge --dry-run
+  portunix harden firewall --profile server --allow 8080/tcp --ssh-port 2222
+  portunix harden firewall --rollback`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profileName, _ := cmd.Flags().GetString("profile")
+		backend, _ := cmd.Flags().GetString("backend")
+		allow, _ := cmd.Flags().GetStringSlice("allow")
+		sshPort, _ := cmd.Flags().GetInt("ssh-port")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		rollback, _ := cmd.Flags().GetBool("rollback")
+		yes, _ := cmd.Flags().GetBool("yes")
+		confirmTimeout, _ := cmd.Flags().GetDuration("confirm-timeout")
+		formatJSON, _ := cmd.Flags().GetBool("json")
+
+		fw, err := harden.NewFirewall(backend)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if rollback {
+			requireHardenPrivileges()
+			snap, err := fw.Rollback()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✅ Firewall restored to the state before %s (profile %s)\n", snap.Created.Format("2006-01-02 15:04:05"), snap.Profile)
+			return
+		}
+
+		if profileName == "" {
+			fmt.Printf("Error: --profile is required (%s)\n", strings.Join(harden.ProfileNames(), ", "))
+			os.Exit(1)
+		}
+		profile, err := harden.GetProfile(profileName, harden.ProfileOptions{SSHPort: sshPort, Allow: allow})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		plan, err := fw.Plan(profile)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if dryRun {
+			if formatJSON {
+				data, err := json.MarshalIndent(plan, "", "  ")
+				if err != nil {
+					fmt.Printf("Error formatting JSON: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println(string(data))
+				return
+			}
+			printFirewallPlan(plan)
+			return
+		}
+
+		requireHardenPrivileges()
+		fmt.Printf("Applying firewall profile %s with %s...\n", profile.Name, plan.Backend)
+		snap, err := fw.Apply(plan)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !yes && confirmTimeout > 0 && isInteractive() {
+			if !confirmWithin(fmt.Sprintf("Keep the new firewall rules? [y/N] (rolling back in %s) ", confirmTimeout), confirmTimeout) {
+				if _, err := fw.Rollback(); err != nil {
+					fmt.Printf("\nError: rollback failed: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println("\n↩️  Not confirmed, previous firewall restored")
+				os.Exit(1)
+			}
+		}
+
+		fmt.Printf("✅ Firewall profile %s applied (%d rules allowed)\n", profile.Name, len(plan.Rules))
+		fmt.Printf("   Snapshot %s saved; undo with: portunix harden firewall --rollback\n", snap.ID)
+	},
+}
+
+var hardenFail2banCmd = &cobra.Command{
+	Use:   "fail2ban",
+	Short: "Install and configure fail2ban for SSH and the reverse proxy",
+	Long: `Install fail2ban when missing and configure jails that ban clients
+after repeated failures:
+
+  sshd             failed SSH logins (systemd journal)
+  portunix-caddy   401/403 responses in Caddy's JSON access log
+  nginx-http-auth  failed basic auth and bot scans in nginx logs
+
+The reverse proxy is detected automatically; override it with --proxy.
+Bans are enforced through the firewall backend in use (ufw or nftables).
+The configuration is written to /etc/fail2ban/jail.d/portunix.local and
+removed again with --remove.`,
+	Example: `  portunix harden fail2ban --dry-run
+  portunix harden fail2ban --proxy caddy --bantime 24h --maxretry 3
+  portunix harden fail2ban --remove`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sshPort, _ := cmd.Flags().GetInt("ssh-port")
+		proxy, _ := cmd.Flags().GetString("proxy")
+		banTime, _ := cmd.Flags().GetDuration("bantime")
+		findTime, _ := cmd.Flags().GetDuration("findtime")
+		maxRetry, _ := cmd.Flags().GetInt("maxretry")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		remove, _ := cmd.Flags().GetBool("remove")
+
+		if runtime.GOOS != "linux" {
+			fmt.Printf("Error: fail2ban is only supported on Linux\n")
+			os.Exit(1)
+		}
+
+		if remove {
+			requireHardenPrivileges()
+			if err := harden.RemoveFail2ban(nil); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("✅ Portunix fail2ban jails removed")
+			return
+		}
+
+		plan, err := harden.Fail2banPlan(harden.Fail2banOptions{
+			SSHPort:  sshPort,
+			Proxy:    proxy,
+			BanTime:  banTime,
+			FindTime: findTime,
+			MaxRetry: maxRetry,
+		})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if dryRun {
+			fmt.Printf("Dry run - fail2ban jails %s\n", plan.Profile)
+			for _, file := range plan.Files {
+				fmt.Printf("\n--- %s\n%s", file.Path, file.Content)
+			}
+			fmt.Println("\nCommands:")
+			for _, command := range plan.Commands {
+				fmt.Printf("  %s\n", shellquote.Join(command))
+			}
+			return
+		}
+
+		requireHardenPrivileges()
+		if _, err := exec.LookPath("fail2ban-client"); err != nil {
+			fmt.Println("📦 Installing fail2ban...")
+			self, err := os.Executable()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			install := exec.Command(self, "install", "fail2ban")
+			install.Stdout = os.Stdout
+			install.Stderr = os.Stderr
+			if err := install.Run(); err != nil {
+				fmt.Printf("Error: failed to install fail2ban: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		fmt.Printf("Configuring fail2ban jails %s...\n", plan.Profile)
+		if err := harden.ApplyFail2ban(plan, nil); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ fail2ban configured (jails: %s)\n", plan.Profile)
+		fmt.Println("   Check bans with: portunix harden status")
+	},
+}
+
+var hardenStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show firewall state and fail2ban jails with ban counts",
+	Run: func(cmd *cobra.Command, args []string) {
+		formatJSON, _ := cmd.Flags().GetBool("json")
+
+		result := struct {
+			Firewall      *harden.FirewallStatus `json:"firewall,omitempty"`
+			FirewallError string                 `json:"firewall_error,omitempty"`
+			Fail2ban      []harden.Jail          `json:"fail2ban"`
+			Fail2banError string                 `json:"fail2ban_error,omitempty"`
+		}{Fail2ban: []harden.Jail{}}
+
+		if fw, err := harden.NewFirewall("auto"); err != nil {
+			result.FirewallError = err.Error()
+		} else if status, err := fw.Status(); err != nil {
+			result.FirewallError = err.Error()
+		} else {
+			result.Firewall = status
+		}
+
+		if _, err := exec.LookPath("fail2ban-client"); err != nil {
+			result.Fail2banError = "fail2ban is not installed"
+		} else if jails, err := harden.Fail2banStatus(nil); err != nil {
+			result.Fail2banError = err.Error()
+		} else if jails != nil {
+			result.Fail2ban = jails
+		}
+
+		if formatJSON {
+			data, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				fmt.Printf("Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		fmt.Println("Firewall:")
+		if result.Firewall == nil {
+			fmt.Printf("  ⚠️  %s\n", result.FirewallError)
+		} else {
+			state := "inactive"
+			if result.Firewall.Active {
+				state = "active"
+			}
+			fmt.Printf("  Backend:  %s (%s)\n", result.Firewall.Backend, state)
+			if result.Firewall.Profile != "" {
+				fmt.Printf("  Profile:  %s (applied %s)\n", result.Firewall.Profile, result.Firewall.AppliedAt.Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Println("  Profile:  none applied by portunix")
+			}
+		}
+
+		fmt.Println("\nfail2ban:")
+		if result.Fail2banError != "" {
+			fmt.Printf("  ⚠️  %s\n", result.Fail2banError)
+			return
+		}
+		if len(result.Fail2ban) == 0 {
+			fmt.Println("  No active jails")
+			return
+		}
+		fmt.Printf("  %-18s %8s %8s %8s\n", "JAIL", "FAILED", "BANNED", "TOTAL")
+		for _, jail := range result.Fail2ban {
+			fmt.Printf("  %-18s %8d %8d %8d\n", jail.Name, jail.CurrentlyFailed, jail.CurrentlyBanned, jail.TotalBanned)
+		}
+	},
+}
+
+func printFirewallPlan(plan *harden.Plan) {
+	fmt.Printf("Dry run - firewall profile %s with %s\n\n", plan.Profile, plan.Backend)
+	fmt.Println("Inbound allowed:")
+	for _, r := range plan.Rules {
+		fmt.Printf("  %-10s %s\n", r.String(), r.Comment)
+	}
+	for _, file := range plan.Files {
+		fmt.Printf("\n--- %s\n%s", file.Path, file.Content)
+	}
+	fmt.Println("\nCommands:")
+	for _, command := range plan.Commands {
+		fmt.Printf("  %s\n", shellquote.Join(command))
+	}
+}
+
+func requireHardenPrivileges() {
+	if runtime.GOOS != "windows" && os.Geteuid() != 0 {
+		fmt.Println("Error: changing the firewall requires root; run with sudo")
+		os.Exit(1)
+	}
+}
+
+func isInteractive() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// confirmWithin asks a yes/no question and returns false when no "y" answer
+// arrives before the timeout
+func confirmWithin(prompt string, timeout time.Duration) bool {
+	fmt.Print(prompt)
+	answer := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer <- strings.TrimSpace(line)
+	}()
+	select {
+	case a := <-answer:
+		return strings.EqualFold(a, "y") || strings.EqualFold(a, "yes")
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(hardenCmd)
+	hardenCmd.AddCommand(hardenFirewallCmd)
+	hardenCmd.AddCommand(hardenFail2banCmd)
+	hardenCmd.AddCommand(hardenStatusCmd)
+
+	hardenFirewallCmd.Flags().String("profile", "", fmt.Sprintf("Firewall profile %v", harden.ProfileNames()))
+	hardenFirewallCmd.Flags().String("backend", "auto", fmt.Sprintf("Firewall backend (auto, %s)", strings.Join(harden.BackendNames(), ", ")))
+	hardenFirewallCmd.Flags().StringSlice("allow", nil, "Additional inbound port, e.g. 8080/tcp (repeatable)")
+	hardenFirewallCmd.Flags().Int("ssh-port", 22, "SSH port kept open by profiles with SSH")
+	hardenFirewallCmd.Flags().Bool("dry-run", false, "Show the exact rules and commands without applying them")
+	hardenFirewallCmd.Flags().Bool("json", false, "Print the dry-run plan as JSON")
+	hardenFirewallCmd.Flags().Bool("rollback", false, "Restore the firewall state saved before the last apply")
+	hardenFirewallCmd.Flags().BoolP("yes", "y", false, "Do not ask to confirm the applied rules")
+	hardenFirewallCmd.Flags().Duration("confirm-timeout", 60*time.Second, "Roll back unless the new rules are confirmed within this time (0 disables)")
+
+	hardenFail2banCmd.Flags().Int("ssh-port", 22, "SSH port watched by the sshd jail")
+	hardenFail2banCmd.Flags().String("proxy", "", fmt.Sprintf("Reverse proxy jail (%s); detected when empty", strings.Join(harden.Fail2banProxies, ", ")))
+	hardenFail2banCmd.Flags().Duration("bantime", time.Hour, "How long an address stays banned")
+	hardenFail2banCmd.Flags().Duration("findtime", 10*time.Minute, "Window in which failures are counted")
+	hardenFail2banCmd.Flags().Int("maxretry", 5, "Failures within --findtime before a ban")
+	hardenFail2banCmd.Flags().Bool("dry-run", false, "Show the jail configuration without applying it")
+	hardenFail2banCmd.Flags().Bool("remove", false, "Remove the portunix jails")
+
+	hardenStatusCmd.Flags().Bool("json", false, "Output in JSON format")
+}
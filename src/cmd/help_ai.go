@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"portunix.ai/app/aihelp"
+	"portunix.ai/app/version"
+)
+
+// handleAIHelpRequest prints machine-readable help when --help-ai is given
+// for a built-in subcommand. Returns false when the request is not handled
+// here (root help, helper commands dispatched before cobra).
+func handleAIHelpRequest(args []string) bool {
+	if !aihelp.Requested(args) {
+		return false
+	}
+
+	target, _, err := rootCmd.Find(args)
+	if err != nil || target == rootCmd || target.DisableFlagParsing {
+		return false
+	}
+
+	aihelp.Help{
+		Tool:        "portunix",
+		Version:     version.ProductVersion,
+		Description: target.Short,
+		Commands:    cobraAICommands(target),
+	}.Print()
+	return true
+}
+
+// cobraAICommands describes a command and all its visible subcommands
+func cobraAICommands(cmd *cobra.Command) []aihelp.Command {
+	var commands []aihelp.Command
+	if cmd.Runnable() || !cmd.HasAvailableSubCommands() {
+		commands = append(commands, cobraAICommand(cmd))
+	}
+	for _, child := range cmd.Commands() {
+		if !child.IsAvailableCommand() {
+			continue
+		}
+		commands = append(commands, cobraAICommands(child)...)
+	}
+	return commands
+}
+
+// cobraAICommand converts a single cobra command
+func cobraAICommand(cmd *cobra.Command) aihelp.Command {
+	description := cmd.Short
+	if cmd.Long != "" {
+		description = cmd.Long
+	}
+
+	c := aihelp.Command{
+		Name:        strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "),
+		Description: description,
+		Aliases:     cmd.Aliases,
+		Arguments:   parseUseArguments(cmd.Use),
+		Flags:       cobraAIFlags(cmd.LocalFlags()),
+	}
+	for _, line := range strings.Split(cmd.Example, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			c.Examples = append(c.Examples, line)
+		}
+	}
+	return c
+}
+
+// cobraAIFlags converts a flag set, skipping the built-in help flag
+func cobraAIFlags(flags *pflag.FlagSet) []aihelp.Flag {
+	var result []aihelp.Flag
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Name == "help" {
+			return
+		}
+		flag := aihelp.Flag{
+			Name:        f.Name,
+			Shorthand:   f.Shorthand,
+			Type:        flagTypeName(f.Value.Type()),
+			Description: f.Usage,
+		}
+		if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "[]" {
+			flag.Default = f.DefValue
+		}
+		result = append(result, flag)
+	})
+	return result
+}
+
+// flagTypeName maps pflag value types to the --help-ai type vocabulary
+func flagTypeName(pflagType string) string {
+	switch pflagType {
+	case "bool":
+		return "boolean"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "count":
+		return "integer"
+	case "float32", "float64":
+		return "number"
+	case "stringSlice", "stringArray":
+		return "stringArray"
+	case "stringToString":
+		return "map"
+	}
+	return pflagType
+}
+
+// parseUseArguments extracts positional arguments from a cobra Use line,
+// e.g. "set <key> <value>" or "update [name...]"
+func parseUseArguments(use string) []aihelp.Argument {
+	fields := strings.Fields(use)
+	if len(fields) < 2 {
+		return nil
+	}
+
+	var args []aihelp.Argument
+	for _, field := range fields[1:] {
+		required := strings.HasPrefix(field, "<")
+		if !required && !strings.HasPrefix(field, "[") {
+			continue
+		}
+
+		name := strings.Trim(field, "<>[]")
+		if name == "flags" || name == "options" {
+			continue
+		}
+		variadic := strings.HasSuffix(name, "...")
+		name = strings.TrimSuffix(name, "...")
+
+		args = append(args, aihelp.Argument{
+			Name:     name,
+			Type:     argumentType(name),
+			Required: required,
+			Variadic: variadic,
+		})
+	}
+	return args
+}
+
+// argumentType guesses an argument type from its conventional name
+func argumentType(name string) string {
+	switch {
+	case strings.Contains(name, "path") || strings.Contains(name, "file") || strings.Contains(name, "dir"):
+		return "path"
+	case strings.Contains(name, "url"):
+		return "url"
+	case strings.Contains(name, "version"):
+		return "version"
+	case name == "count" || name == "port" || name == "lines":
+		return "integer"
+	}
+	return "string"
+}
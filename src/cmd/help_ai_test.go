@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"portunix.ai/app/aihelp"
+)
+
+func TestParseUseArguments(t *testing.T) {
+	tests := []struct {
+		use  string
+		want []aihelp.Argument
+	}{
+		{use: "list", want: nil},
+		{use: "set <key> <value>", want: []aihelp.Argument{
+			{Name: "key", Type: "string", Required: true},
+			{Name: "value", Type: "string", Required: true},
+		}},
+		{use: "update [name...]", want: []aihelp.Argument{
+			{Name: "name", Type: "string", Variadic: true},
+		}},
+		{use: "copy <source-path> [flags]", want: []aihelp.Argument{
+			{Name: "source-path", Type: "path", Required: true},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.use, func(t *testing.T) {
+			got := parseUseArguments(tt.use)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseUseArguments(%q) = %+v, want %+v", tt.use, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCobraAICommandsForConfig(t *testing.T) {
+	commands := cobraAICommands(configCmd)
+
+	var set *aihelp.Command
+	for i := range commands {
+		if commands[i].Name == "config set" {
+			set = &commands[i]
+		}
+	}
+	if set == nil {
+		t.Fatalf("config set not described: %+v", commands)
+	}
+	if len(set.Arguments) != 2 || set.Arguments[0].Name != "key" {
+		t.Errorf("unexpected arguments: %+v", set.Arguments)
+	}
+	if len(set.Flags) != 1 || set.Flags[0].Name != "scope" || set.Flags[0].Default != "user" {
+		t.Errorf("unexpected flags: %+v", set.Flags)
+	}
+}
+
+func TestGenerateAIHelpIncludesSubcommands(t *testing.T) {
+	out, err := GenerateAIHelp()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Commands    []CommandInfo `json:"commands"`
+		GlobalFlags []aihelp.Flag `json:"global_flags"`
+	}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(doc.GlobalFlags) == 0 {
+		t.Error("global flags missing")
+	}
+	for _, c := range doc.Commands {
+		if c.Name == "config" && len(c.SubCommands) == 0 {
+			t.Error("config subcommands missing")
+		}
+	}
+}
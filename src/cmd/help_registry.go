@@ -7,7 +7,11 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/spf13/cobra"
+	"portunix.ai/app/aihelp"
 	"portunix.ai/app/plugins/manager"
+	"portunix.ai/app/version"
+	"portunix.ai/portunix/pkg/logging"
 )
 
 // CommandInfo represents a command with all its metadata
@@ -44,26 +48,152 @@ var CommandRegistry = []CommandInfo{
 			{Name: "variant", Type: "string", Required: false, Description: "Package variant (e.g., 'full', 'minimal', 'default')"},
 			{Name: "dry-run", Type: "boolean", Required: false, Description: "Preview installation without making changes"},
 			{Name: "force", Type: "boolean", Required: false, Description: "Force reinstall even if already installed"},
+			{Name: "offline", Type: "boolean", Required: false, Description: "Install only from cached downloads, never use the network"},
+			{Name: "jobs", Type: "integer", Required: false, Description: "Parallel installations when installing several packages"},
+			{Name: "backend", Type: "string", Required: false, Description: "auto (prefer native system packages), direct or a package manager (apt, dnf, pacman, zypper, brew, winget, chocolatey)"},
 		},
 		Examples: []string{
 			"portunix install nodejs",
 			"portunix install python --variant full",
 			"portunix install java --dry-run",
+			"portunix install nodejs --offline",
+			"portunix install git nodejs hugo --jobs 4",
+			"portunix install apply packages.yaml",
+			"portunix install profile go-backend",
 		},
 	},
 	{
-		Name:        "update",
-		Brief:       "Update Portunix to the latest version",
-		Description: "Check for and install updates to Portunix itself. Downloads the latest release from GitHub, verifies checksums, and safely replaces the current binary with automatic rollback on failure.",
+		Name:        "uninstall",
+		Brief:       "Remove installed packages",
+		Description: "Remove packages installed by portunix. Uses the uninstall commands of the package definition, the system package manager the package was installed with, or the files recorded at install time.",
+		Category:    "core",
+		Parameters: []ParameterInfo{
+			{Name: "package", Type: "string", Required: true, Description: "Package name to remove"},
+			{Name: "dry-run", Type: "boolean", Required: false, Description: "Show what would be removed"},
+		},
+		Examples: []string{
+			"portunix uninstall hugo",
+			"portunix uninstall nodejs --dry-run",
+		},
+	},
+	{
+		Name:        "run",
+		Brief:       "Run a command with managed environment profiles",
+		Description: "Run a command with environment variables composed from env profiles defined in env-profiles.yaml: proxy variables, registry credentials from the secret store, JAVA_HOME of a managed JDK and Python venv activation. Profiles can include each other; the exit code is the one of the command.",
+		Category:    "core",
+		Parameters: []ParameterInfo{
+			{Name: "env-profile", Type: "string", Required: false, Description: "Env profile to apply (repeatable; default: run.env_profile setting)"},
+			{Name: "print", Type: "boolean", Required: false, Description: "Print the composed variables instead of running, secrets masked"},
+			{Name: "list", Type: "boolean", Required: false, Description: "List the defined env profiles"},
+			{Name: "command", Type: "string", Required: false, Description: "Command and arguments after --"},
+		},
+		Examples: []string{
+			"portunix run --env-profile ci -- mvn -B verify",
+			"portunix run --env-profile ci --print",
+			"portunix run --list",
+		},
+	},
+	{
+		Name:        "undo",
+		Brief:       "Reverse a recorded operation",
+		Description: "Reverse an operation from the journal of changes portunix made: uninstall installed packages, remove created files, restore overwritten files and remove detached containers. Files edited since the operation are kept unless forced. Without an operation ID the recent operations are listed.",
+		Category:    "core",
+		Parameters: []ParameterInfo{
+			{Name: "op-id", Type: "string", Required: false, Description: "Operation ID, or 'last' for the latest reversible operation"},
+			{Name: "list", Type: "boolean", Required: false, Description: "List recorded operations"},
+			{Name: "dry-run", Type: "boolean", Required: false, Description: "Show what would be reversed"},
+			{Name: "force", Type: "boolean", Required: false, Description: "Also reverse files edited since the operation"},
+		},
+		Examples: []string{
+			"portunix undo --list",
+			"portunix undo last --dry-run",
+			"portunix undo 20261017-142301-a3f2",
+		},
+	},
+	{
+		Name:        "upgrade",
+		Brief:       "Upgrade installed packages",
+		Description: "Compare packages installed by portunix with the registry version of their variant and reinstall newer versions, showing the changelog of each upgrade.",
+		Category:    "core",
+		Parameters: []ParameterInfo{
+			{Name: "package", Type: "string", Required: false, Description: "Package name to upgrade"},
+			{Name: "all", Type: "boolean", Required: false, Description: "Upgrade every installed package"},
+			{Name: "check", Type: "boolean", Required: false, Description: "Only show available upgrades"},
+		},
+		Examples: []string{
+			"portunix upgrade --all --check",
+			"portunix upgrade nodejs",
+			"portunix upgrade --all",
+		},
+	},
+	{
+		Name:        "init",
+		Brief:       "First-run setup of portunix for this machine",
+		Description: "Detect the environment, ask for the roles (dev, devops, product), install the helpers for those roles, configure the download cache and proxy, install shell completion and add portunix to PATH. --yes accepts all defaults.",
+		Category:    "core",
+		Parameters: []ParameterInfo{
+			{Name: "role", Type: "string", Required: false, Description: "Role to set up for: dev, devops, product (repeatable)"},
+			{Name: "yes", Type: "boolean", Required: false, Description: "Accept all defaults without asking"},
+			{Name: "dry-run", Type: "boolean", Required: false, Description: "Show the setup without changing anything"},
+			{Name: "skip-helpers", Type: "boolean", Required: false, Description: "Do not install helpers"},
+		},
+		Examples: []string{
+			"portunix init",
+			"portunix init --role devops --yes",
+			"portunix init --dry-run",
+		},
+	},
+	{
+		Name:        "self-update",
+		Brief:       "Update Portunix and its helpers to the latest version",
+		Description: "Check GitHub releases of the stable or beta channel and install the portunix binary with all helpers for this OS/arch. Checksums are verified against the release signing key of the build; builds without a key refuse to update unless --insecure is given. New binaries are staged and swapped in atomically with rollback on failure. Alias: update.",
 		Category:    "core",
 		Parameters: []ParameterInfo{
 			{Name: "check", Type: "boolean", Required: false, Description: "Only check for updates without installing"},
 			{Name: "force", Type: "boolean", Required: false, Description: "Force reinstall current version"},
+			{Name: "channel", Type: "string", Required: false, Description: "Release channel: stable or beta (default: update.channel)"},
+			{Name: "insecure", Type: "boolean", Required: false, Description: "Update without a release signing key, verifying only the unsigned checksums"},
+		},
+		Examples: []string{
+			"portunix self-update",
+			"portunix self-update --check",
+			"portunix self-update --channel beta",
+			"portunix config set update.channel beta",
+		},
+	},
+	{
+		Name:        "helpers",
+		Brief:       "Manage helper binaries",
+		Description: "List, update and pin helper binaries (ptx-*). Updates download the release archive for the current OS/arch, verify checksums and signatures, and install only the selected helpers. Helper versions can be pinned per project in .portunix-helpers.json.",
+		Category:    "core",
+		Parameters: []ParameterInfo{
+			{Name: "check", Type: "boolean", Required: false, Description: "Only show available helper updates"},
+			{Name: "force", Type: "boolean", Required: false, Description: "Reinstall helpers already at the target version"},
+			{Name: "version", Type: "string", Required: false, Description: "Install a specific release version"},
+			{Name: "insecure", Type: "boolean", Required: false, Description: "Update without a release signing key, verifying only the unsigned checksums"},
+		},
+		Examples: []string{
+			"portunix helpers list",
+			"portunix helpers update",
+			"portunix helpers update ptx-python --check",
+			"portunix helpers pin ptx-python v1.10.8",
+		},
+	},
+	{
+		Name:        "logs",
+		Brief:       "Inspect and clean Portunix log files",
+		Description: "Show captured logs of portunix and its helpers. Every invocation records structured JSON lines in ~/.portunix/logs regardless of --verbose/--quiet, so failed container or sync operations can be diagnosed afterwards.",
+		Category:    "core",
+		Parameters: []ParameterInfo{
+			{Name: "lines", Type: "integer", Required: false, Description: "Number of most recent lines to show", Default: "50"},
+			{Name: "level", Type: "string", Required: false, Description: "Minimum level to show (debug, info, warn, error)"},
+			{Name: "component", Type: "string", Required: false, Description: "Only show records from this component (e.g. ptx-container)"},
 		},
 		Examples: []string{
-			"portunix update",
-			"portunix update --check",
-			"portunix update --force",
+			"portunix logs path",
+			"portunix logs show --level error",
+			"portunix logs show --component ptx-container --lines 100",
+			"portunix logs clean",
 		},
 	},
 	{
@@ -108,27 +238,40 @@ var CommandRegistry = []CommandInfo{
 			"portunix container run ubuntu",
 			"portunix container exec my-container bash",
 			"portunix container list",
+			"portunix container run-in-container --template go-dev",
 		},
 	},
 	{
 		Name:        "virt",
 		Brief:       "Virtual machine management",
-		Description: "Universal virtualization management supporting multiple backends including QEMU/KVM, VirtualBox, VMware, and Hyper-V. Create, manage, and interact with virtual machines across platforms.",
+		Description: "Universal virtualization management supporting multiple backends including QEMU/KVM, VirtualBox, VMware, Hyper-V, Multipass and WSL2. Create, manage, and interact with virtual machines across platforms; 'vm' is an alias of 'virt'.",
 		Category:    "virtualization",
 		Examples: []string{
+			"portunix virt create myvm --image ubuntu-22.04",
 			"portunix virt create myvm --iso ubuntu.iso",
-			"portunix virt start myvm",
 			"portunix virt ssh myvm",
+			"portunix --host myvm install nodejs",
+			"portunix virt destroy myvm",
+			"portunix vm create scratch --backend multipass --image ubuntu-24.04",
+			"portunix vm create --backend wsl --distro Ubuntu-22.04",
+			"portunix vm cloudinit render --template dev-workstation --var user=alice",
 		},
 	},
 	{
 		Name:        "system",
 		Brief:       "System information",
-		Description: "Display detailed system information including OS details, hardware specs, network configuration, and installed software versions. Useful for debugging and environment verification.",
+		Description: "Display detailed system information including OS details, hardware inventory (CPU, memory, disks, GPUs with driver versions), virtualization capability, container readiness and installed software versions. Snapshots record packages, environment variables and services so changes made by installs can be reviewed. Useful for debugging and environment verification.",
 		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "info", Brief: "Display detailed system information"},
+			{Name: "check", Brief: "Check specific system conditions"},
+			{Name: "snapshot", Brief: "Save and compare snapshots of the environment"},
+		},
 		Examples: []string{
 			"portunix system info",
 			"portunix system os",
+			"portunix system snapshot save before",
+			"portunix system snapshot diff before",
 		},
 	},
 	{
@@ -147,12 +290,15 @@ var CommandRegistry = []CommandInfo{
 	{
 		Name:        "package",
 		Brief:       "Package management and registry",
-		Description: "Package management and registry operations including listing available packages, searching by name or description, and viewing detailed package information.",
+		Description: "Package management and registry operations including listing available packages, searching by name or description, and viewing detailed package information. New packages can be defined as JSON or YAML descriptors in ~/.portunix/packages or pulled from remote registries without code changes.",
 		Category:    "core",
 		Examples: []string{
 			"portunix package list",
 			"portunix package search python",
 			"portunix package info nodejs",
+			"portunix package installed",
+			"portunix package validate mytool.yaml",
+			"portunix package registry add team https://example.com/portunix-packages",
 		},
 	},
 	{
@@ -173,11 +319,12 @@ var CommandRegistry = []CommandInfo{
 		Description: "Manage Ansible Infrastructure as Code using .ptxbook files. Supports multi-environment deployments with enterprise features including secrets management, audit logging, RBAC, and CI/CD integration.",
 		Category:    "integration",
 		Parameters: []ParameterInfo{
-			{Name: "subcommand", Type: "string", Required: true, Description: "Operation to perform", Choices: []string{"run", "validate", "check", "list", "init", "template"}},
+			{Name: "subcommand", Type: "string", Required: true, Description: "Operation to perform", Choices: []string{"run", "validate", "check", "list", "runs", "approve", "init", "template"}},
 		},
 		Examples: []string{
 			"portunix playbook run deployment.ptxbook",
 			"portunix playbook validate my-project.ptxbook",
+			"portunix playbook run config.ptxbook --from-run infra",
 			"portunix playbook init web-server.ptxbook",
 			"portunix playbook template list",
 		},
@@ -220,6 +367,73 @@ var CommandRegistry = []CommandInfo{
 			"portunix credential list",
 		},
 	},
+	{
+		Name:        "docs",
+		Brief:       "Documentation site management",
+		Description: "Serve, build, link-check and deploy a Hugo documentation site. Hugo runs in a container through the detected compose runtime (Docker Compose or podman-compose) with live reload; the built site is published to a gh-pages branch or a directory.",
+		Category:    "development",
+		SubCommands: []CommandInfo{
+			{Name: "init", Brief: "Write docker-compose.docs.yml for the site"},
+			{Name: "serve", Brief: "Start the live reload server"},
+			{Name: "stop", Brief: "Stop a detached server"},
+			{Name: "build", Brief: "Build the site into <site>/public"},
+			{Name: "check-links", Brief: "Check the links of the built site"},
+			{Name: "deploy", Brief: "Build, check and publish the site"},
+		},
+		Examples: []string{
+			"portunix docs serve",
+			"portunix docs build --check",
+			"portunix docs check-links --external",
+			"portunix docs deploy --branch gh-pages",
+		},
+	},
+	{
+		Name:        "db",
+		Brief:       "Local database provisioning",
+		Description: "Run PostgreSQL, MySQL and MongoDB in containers of the configured runtime with persistent volumes and generated credentials. Prints connection strings, takes snapshots with the engine's dump tools and restores them.",
+		Category:    "development",
+		SubCommands: []CommandInfo{
+			{Name: "create", Brief: "Start a database container"},
+			{Name: "list", Brief: "List databases and their state"},
+			{Name: "info", Brief: "Show a database and its connection string"},
+			{Name: "url", Brief: "Print the connection string"},
+			{Name: "start", Brief: "Start a stopped database"},
+			{Name: "stop", Brief: "Stop a database"},
+			{Name: "shell", Brief: "Open the database client"},
+			{Name: "remove", Brief: "Remove a database container"},
+			{Name: "snapshot", Brief: "Dump a database"},
+			{Name: "snapshots", Brief: "List snapshots of a database"},
+			{Name: "restore", Brief: "Restore a snapshot"},
+		},
+		Examples: []string{
+			"portunix db create postgres --version 16 --port 5433",
+			"portunix db list",
+			"portunix db snapshot postgres --tag before-migration",
+			"portunix db restore postgres before-migration",
+		},
+	},
+	{
+		Name:        "ai",
+		Brief:       "Local LLM tooling",
+		Description: "Install Ollama and llama.cpp builds matching the GPU, download GGUF models with SHA-256 verification and serve them through an OpenAI-compatible endpoint. Helpers with optional LLM backends find the running server with 'portunix ai status --json'.",
+		Category:    "development",
+		SubCommands: []CommandInfo{
+			{Name: "gpu", Brief: "Show detected GPUs and the matching builds"},
+			{Name: "install", Brief: "Install Ollama or llama.cpp"},
+			{Name: "pull", Brief: "Download a GGUF model or pull an Ollama model"},
+			{Name: "list", Brief: "List backends and models"},
+			{Name: "verify", Brief: "Check the SHA-256 of downloaded models"},
+			{Name: "remove", Brief: "Remove a model"},
+			{Name: "serve", Brief: "Run an OpenAI-compatible server"},
+			{Name: "status", Brief: "Show the running server and its endpoint"},
+		},
+		Examples: []string{
+			"portunix ai install llama.cpp",
+			"portunix ai pull hf:Qwen/Qwen2.5-Coder-7B-Instruct-GGUF/qwen2.5-coder-7b-instruct-q4_k_m.gguf",
+			"portunix ai serve qwen2.5-coder-7b-instruct-q4_k_m",
+			"portunix ai status --json",
+		},
+	},
 	// Additional commands for expert level
 	{
 		Name:        "podman",
@@ -232,12 +446,346 @@ var CommandRegistry = []CommandInfo{
 		Brief:       "Windows Sandbox management",
 		Description: "Create and manage isolated Windows Sandbox environments for safe testing and development.",
 		Category:    "virtualization",
+		SubCommands: []CommandInfo{
+			{Name: "run", Brief: "Test a package installation in a fresh sandbox"},
+			{Name: "generate", Brief: "Generate a .wsb configuration file"},
+			{Name: "start", Brief: "Start a sandbox from a .wsb file"},
+		},
+		Examples: []string{
+			"portunix sandbox run nodejs",
+			"portunix sandbox run java --verify \"java -version\"",
+		},
+	},
+	{
+		Name:        "proxy",
+		Brief:       "Proxy and corporate network configuration",
+		Description: "Use a corporate proxy (explicit or from a PAC file) and trust the CA of TLS-intercepting proxies for all portunix downloads, the tools it runs, containers and run-in-container package managers. Settings are the proxy.* configuration keys.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "show", Brief: "Show the effective proxy configuration"},
+			{Name: "env", Brief: "Print shell commands exporting the proxy environment"},
+			{Name: "test", Brief: "Test an HTTPS request through the proxy"},
+			{Name: "import-ca", Brief: "Trust the CA certificate of a TLS-intercepting proxy"},
+			{Name: "sync", Brief: "Write the proxy settings to pip and npm configuration"},
+		},
+		Examples: []string{
+			"portunix config set proxy.https http://proxy.corp.example.com:3128",
+			"portunix config set proxy.no_proxy localhost,.corp.example.com",
+			"portunix proxy import-ca corp-root-ca.crt",
+			"portunix proxy test",
+		},
+	},
+	{
+		Name:        "mirror",
+		Brief:       "Download mirrors and container registries per network profile",
+		Description: "Redirect install engine downloads and container image pulls to internal mirrors such as Artifactory or Harbor. Mirrors are defined per network profile (e.g. office, home, airgapped) in mirrors.yaml; the network.profile setting selects the active one.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "show", Brief: "Show the active network profile and its mirrors"},
+			{Name: "list", Brief: "List the defined network profiles"},
+			{Name: "use", Brief: "Select the network profile"},
+			{Name: "resolve", Brief: "Show where a URL or image is redirected to"},
+		},
+		Examples: []string{
+			"portunix mirror use office",
+			"portunix mirror resolve ubuntu:22.04",
+			"portunix mirror use none",
+		},
+	},
+	{
+		Name:        "repo",
+		Brief:       "Bootstrap git repositories with standard hooks",
+		Description: "Install a pre-commit hook (lint rules and gitleaks secret scanning), a commit-msg hook checking the message format and an .editorconfig from a repository template. Organizations provide templates in repo-templates/ next to config.yaml.",
+		Category:    "development",
+		SubCommands: []CommandInfo{
+			{Name: "init", Brief: "Install git hooks and .editorconfig"},
+			{Name: "templates", Brief: "List the available repository templates"},
+		},
+		Examples: []string{
+			"portunix repo init",
+			"portunix repo init --template acme",
+			"portunix repo templates",
+		},
+	},
+	{
+		Name:        "sbom",
+		Brief:       "Generate and compare SBOMs",
+		Description: "Generate CycloneDX or SPDX SBOMs of containers, images, directories or the local system with syft (installed when missing), store them in .portunix/sbom and highlight new dependencies against the previous SBOM of the same target.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "generate", Brief: "Generate an SBOM and compare it with the previous one"},
+			{Name: "list", Brief: "List stored SBOMs"},
+			{Name: "diff", Brief: "Compare two SBOMs"},
+		},
+		Examples: []string{
+			"portunix sbom generate --target container:web",
+			"portunix sbom generate --target system --format spdx",
+			"portunix sbom diff --target container:web",
+		},
+	},
+	{
+		Name:        "secret",
+		Brief:       "Shared secret store for tokens, passwords and API keys",
+		Description: "Store pft tokens, Ansible secrets, registry tokens and edge provider API keys in the OS keychain or an encrypted file. Subsystems resolve them by name or from \"secret:<name>\" configuration values, and every access is audited.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "set", Brief: "Store a secret (prompts for the value when omitted)"},
+			{Name: "get", Brief: "Print a secret value"},
+			{Name: "list", Brief: "List stored secrets without values"},
+			{Name: "delete", Brief: "Remove a secret"},
+			{Name: "audit", Brief: "Show the access log of secrets"},
+			{Name: "backends", Brief: "Show the available secret backends"},
+		},
+		Examples: []string{
+			"portunix secret set edge/hetzner",
+			"portunix secret list",
+			"portunix secret audit pft/voc",
+		},
+	},
+	{
+		Name:        "vuln",
+		Brief:       "Check packages and images for vulnerabilities",
+		Description: "Check packages installed by portunix, system packages and local container images against the OSV and NVD feeds. A watcher scans periodically and raises desktop or email notifications (through the SMTP server of a pft configuration) when new CVEs affect the environment.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "scan", Brief: "Scan once and list the vulnerabilities found"},
+			{Name: "watch", Brief: "Scan periodically and notify about new vulnerabilities"},
+			{Name: "status", Brief: "Show the watcher state and the last findings"},
+			{Name: "stop", Brief: "Stop the background watcher"},
+		},
+		Examples: []string{
+			"portunix vuln scan",
+			"portunix vuln watch --detach --interval 12h",
+			"portunix config set vuln.notify desktop,email",
+		},
+	},
+	{
+		Name:        "metrics",
+		Brief:       "Opt-in local usage metrics",
+		Description: "Record command usage, durations and failure categories locally (opt-in), show which commands and install packages fail most often, and optionally export the events to a configured endpoint.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "show", Brief: "Show recorded command usage and failures"},
+			{Name: "status", Brief: "Show whether metrics are recorded and exported"},
+			{Name: "enable", Brief: "Start recording metrics locally"},
+			{Name: "disable", Brief: "Stop recording metrics"},
+			{Name: "export", Brief: "Send events to the configured endpoint"},
+			{Name: "clear", Brief: "Delete all recorded events"},
+		},
+		Examples: []string{
+			"portunix metrics enable",
+			"portunix metrics show --days 30",
+			"portunix config set metrics.endpoint https://metrics.example.com/portunix",
+			"portunix metrics export",
+		},
+	},
+	{
+		Name:        "doctor",
+		Brief:       "Diagnose the environment and suggest fixes",
+		Description: "Run all environment checks in one pass (OS detection, helper binaries and versions, container runtimes and compose, Python/Node.js/Java, network access to registries) and print prioritized fix instructions. --fix applies safe repairs.",
+		Category:    "utility",
+		Parameters: []ParameterInfo{
+			{Name: "fix", Type: "boolean", Required: false, Description: "Apply safe automatic repairs"},
+			{Name: "json", Type: "boolean", Required: false, Description: "Output results as JSON"},
+			{Name: "offline", Type: "boolean", Required: false, Description: "Skip network checks"},
+			{Name: "category", Type: "string", Required: false, Description: "Only run these categories", Choices: []string{"system", "helpers", "containers", "languages", "network"}},
+		},
+		Examples: []string{
+			"portunix doctor",
+			"portunix doctor --fix",
+			"portunix doctor --category containers --json",
+		},
+	},
+	{
+		Name:        "tui",
+		Brief:       "Interactive terminal UI",
+		Description: "Full-screen terminal UI listing containers, registry packages, PFT feedback items and playbooks with keyboard navigation and common actions (start/stop/logs, install, sync, run).",
+		Category:    "utility",
+		Examples: []string{
+			"portunix tui",
+		},
+	},
+	{
+		Name:        "daemon",
+		Brief:       "Run portunix as a local gRPC service",
+		Description: "Serve install, container lifecycle, system info and PFT sync over a local gRPC API (Unix socket, loopback port on Windows) authenticated with a per-user token, so IDE plugins and the MCP server can drive portunix without starting a process per command.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "start", Brief: "Start the daemon in the background"},
+			{Name: "stop", Brief: "Stop the running daemon"},
+			{Name: "status", Brief: "Show whether the daemon is running"},
+		},
+		Examples: []string{
+			"portunix daemon start",
+			"portunix daemon status --json",
+			"portunix daemon stop",
+		},
+	},
+	{
+		Name:        "service",
+		Brief:       "Plugin service instances and background services",
+		Description: "Start, discover and stop gRPC plugin service instances, and install the background components of portunix (daemon, vuln-watch, pft-sync, edge) as user services of systemd, launchd or the Windows Task Scheduler, so they start at login and restart on failure.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "install", Brief: "Install a background component as a user service"},
+			{Name: "uninstall", Brief: "Stop and remove a background service"},
+			{Name: "start", Brief: "Start a plugin instance or a background service"},
+			{Name: "stop", Brief: "Stop plugin instances or a background service"},
+			{Name: "status", Brief: "Show the state of background services"},
+			{Name: "list", Brief: "List running plugin service instances"},
+			{Name: "info", Brief: "Show gRPC service details for a plugin instance"},
+			{Name: "release", Brief: "Release a client session"},
+		},
+		Examples: []string{
+			"portunix service install daemon --now",
+			"portunix service install pft-sync --interval 30m",
+			"portunix service status",
+			"portunix service start reco",
+		},
+	},
+	{
+		Name:        "cert",
+		Brief:       "Issue and renew TLS certificates with ACME DNS-01",
+		Description: "Issue Let's Encrypt certificates (including wildcards) with DNS-01 challenges through Cloudflare, Route 53 or Hetzner DNS, renew them on a daily schedule and export them for Caddy, HAProxy or nginx.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "issue", Brief: "Issue a certificate"},
+			{Name: "list", Brief: "List stored certificates"},
+			{Name: "renew", Brief: "Renew certificates that expire soon"},
+			{Name: "export", Brief: "Export a certificate for Caddy, HAProxy or nginx"},
+			{Name: "remove", Brief: "Remove a stored certificate"},
+			{Name: "schedule", Brief: "Install or remove the daily renewal job"},
+		},
+		Examples: []string{
+			"portunix cert issue --domain '*.example.com' --dns cloudflare",
+			"portunix cert renew --all",
+			"portunix cert export '*.example.com' --format nginx --out /etc/nginx/certs",
+		},
+	},
+	{
+		Name:        "schedule",
+		Brief:       "Run portunix commands periodically",
+		Description: "Schedule portunix commands with cron expressions through the user's crontab on Linux and macOS or the Task Scheduler on Windows. Each run appends its output to a run log and records its exit status.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "add", Brief: "Schedule a portunix command"},
+			{Name: "list", Brief: "List scheduled commands"},
+			{Name: "remove", Brief: "Remove a scheduled command and its run log"},
+			{Name: "run", Brief: "Run a scheduled command now"},
+			{Name: "logs", Brief: "Show the run log of a scheduled command"},
+		},
+		Examples: []string{
+			`portunix schedule add "pft sync" --cron "0 * * * *"`,
+			"portunix schedule list",
+			"portunix schedule logs pft-sync --tail 50",
+		},
+	},
+	{
+		Name:        "env",
+		Brief:       "Set up the development environment a repository describes",
+		Description: "Converge the developer machine or a development container to the portunix.yaml of a repository: required tools (installed with install apply), container services started with compose for Docker and Podman alike, environment variables, and tasks run once per clone.",
+		Category:    "development",
+		SubCommands: []CommandInfo{
+			{Name: "up", Brief: "Install tools, start services and run tasks of portunix.yaml"},
+			{Name: "down", Brief: "Stop and remove the services of portunix.yaml"},
+			{Name: "export", Brief: "Print shell commands exporting the env section"},
+			{Name: "import-devcontainer", Brief: "Convert devcontainer.json to portunix.yaml"},
+			{Name: "export-devcontainer", Brief: "Convert portunix.yaml to devcontainer.json"},
+			{Name: "bake", Brief: "Build and push an image with the environment pre-installed"},
+		},
+		Examples: []string{
+			"portunix env up",
+			"portunix env up --container",
+			"eval \"$(portunix env export)\"",
+			"portunix env import-devcontainer .devcontainer/devcontainer.json",
+			"portunix env bake --output registry.corp/myteam/dev:latest",
+		},
+	},
+	{
+		Name:        "ide",
+		Brief:       "Connect VS Code and JetBrains IDEs to VMs and containers",
+		Description: "Configure VS Code Remote-SSH or JetBrains Gateway for a VM created by portunix, or VS Code Dev Containers for a running container. SSH host entries with the key of the VM are written to a portunix-managed file included from ~/.ssh/config.",
+		Category:    "development",
+		SubCommands: []CommandInfo{
+			{Name: "connect", Brief: "Configure an editor for a VM or container and print how to open it"},
+		},
+		Examples: []string{
+			"portunix ide connect ubuntu-dev",
+			"portunix ide connect ubuntu-dev --editor jetbrains --product GO",
+			"portunix ide connect my-container --open",
+		},
+	},
+	{
+		Name:        "harden",
+		Brief:       "Apply security hardening to this host",
+		Description: "Apply firewall profiles (edge, server, workstation) through nftables, ufw or Windows Firewall with a dry-run of the exact rules, a confirmation timeout that undoes rules cutting off the session, rollback to the state before the last apply, fail2ban jails for SSH and the reverse proxy, and a status summary of active jails and ban counts.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "firewall", Brief: "Apply a firewall profile (nftables, ufw or Windows Firewall)"},
+			{Name: "fail2ban", Brief: "Install and configure fail2ban for SSH and the reverse proxy"},
+			{Name: "status", Brief: "Show firewall state and fail2ban jails with ban counts"},
+		},
+		Examples: []string{
+			"portunix harden firewall --profile edge --dry-run",
+			"portunix harden firewall --profile server --allow 8080/tcp",
+			"portunix harden firewall --rollback",
+			"portunix harden fail2ban --proxy caddy",
+			"portunix harden status",
+		},
+	},
+	{
+		Name:        "fleet",
+		Brief:       "Manage the inventory of remote hosts and their facts",
+		Description: "Keep a local or git-backed inventory of managed hosts with facts collected over SSH (OS, installed packages and tools, WireGuard tunnels, TLS certificates) and query it, e.g. hosts running Ubuntu without Docker. Inventory names work with --host.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "init", Brief: "Create the inventory, optionally backed by git"},
+			{Name: "add", Brief: "Add a host to the inventory or update its address and tags"},
+			{Name: "remove", Brief: "Remove a host from the inventory"},
+			{Name: "scan", Brief: "Collect facts from hosts over SSH"},
+			{Name: "list", Brief: "List hosts matching a query"},
+			{Name: "show", Brief: "Show the facts of a host"},
+			{Name: "sync", Brief: "Pull and push the git-backed inventory"},
+		},
+		Examples: []string{
+			"portunix fleet add web1 --address deploy@203.0.113.10 --tag prod",
+			"portunix fleet scan",
+			"portunix fleet list --os ubuntu --missing docker",
+		},
+	},
+	{
+		Name:        "k8s",
+		Brief:       "Create local Kubernetes clusters with kind, k3d or k3s",
+		Description: "Create local Kubernetes clusters with kind, k3d or k3s, installing the provider and kubectl when missing. The kubeconfig is written per cluster and merged into ~/.kube/config. The PFT stack or docker compose files can be converted to manifests and deployed right after creation.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "create", Brief: "Create a cluster and optionally deploy applications"},
+			{Name: "list", Brief: "List clusters created by portunix"},
+			{Name: "delete", Brief: "Delete a cluster and its kubeconfig entries"},
+			{Name: "kubeconfig", Brief: "Print or refresh the kubeconfig of a cluster"},
+			{Name: "convert", Brief: "Convert a docker compose file to Kubernetes manifests"},
+		},
+		Examples: []string{
+			"portunix k8s create dev --provider k3d --nodes 3",
+			"portunix k8s create pft --deploy-pft",
+			"portunix k8s convert docker-compose.yml -o manifests.yaml",
+		},
 	},
 	{
 		Name:        "completion",
 		Brief:       "Generate shell completions",
-		Description: "Generate shell completion scripts for bash, zsh, fish, and PowerShell to enable tab completion for Portunix commands.",
+		Description: "Generate shell completion scripts for bash, zsh, fish, and PowerShell to enable tab completion for Portunix commands, plugins, and all installed helpers (commands, flags, and argument values from each helper's --help-ai output).",
 		Category:    "utility",
+		Parameters: []ParameterInfo{
+			{Name: "shell", Type: "string", Required: true, Description: "Target shell", Choices: []string{"bash", "zsh", "fish", "powershell"}},
+			{Name: "no-descriptions", Type: "boolean", Required: false, Description: "Disable completion descriptions"},
+		},
+		Examples: []string{
+			"source <(portunix completion bash)",
+			"portunix completion zsh > \"${fpath[1]}/_portunix\"",
+			"portunix completion fish > ~/.config/fish/completions/portunix.fish",
+			"portunix completion powershell | Out-String | Invoke-Expression",
+		},
 	},
 	{
 		Name:        "cache",
@@ -250,6 +798,8 @@ var CommandRegistry = []CommandInfo{
 			{Name: "clean", Brief: "Remove expired and invalid entries"},
 			{Name: "purge", Brief: "Clear all cache contents"},
 			{Name: "remove", Brief: "Remove entries matching a pattern"},
+			{Name: "prune", Brief: "Remove old, broken and oversized entries"},
+			{Name: "verify", Brief: "Check cached files against their checksums"},
 		},
 		Examples: []string{
 			"portunix cache info",
@@ -258,6 +808,29 @@ var CommandRegistry = []CommandInfo{
 			"portunix cache clean",
 			"portunix cache purge",
 			"portunix cache remove nodejs",
+			"portunix cache prune --older-than 30d",
+			"portunix cache verify --remove",
+		},
+	},
+	{
+		Name:        "cleanup",
+		Brief:       "Analyze and reclaim disk space",
+		Description: "Report the disk space used by the install cache, Docker/Podman images, containers and volumes, Python venvs and VM images and disks in one view, and reclaim what has not been used for a given age. Volumes and VM disks are only reported.",
+		Category:    "utility",
+		SubCommands: []CommandInfo{
+			{Name: "analyze", Brief: "Show disk usage and reclaimable space per subsystem"},
+			{Name: "run", Brief: "Remove items unused for longer than --older-than"},
+		},
+		Parameters: []ParameterInfo{
+			{Name: "older-than", Type: "string", Required: false, Description: "Items unused for longer are reclaimable (default 30d)"},
+			{Name: "only", Type: "string", Required: false, Description: "Only these subsystems", Choices: []string{"cache", "containers", "venvs", "vms"}},
+			{Name: "dry-run", Type: "boolean", Required: false, Description: "With run: list the actions without removing anything"},
+			{Name: "json", Type: "boolean", Required: false, Description: "With analyze: output in JSON format"},
+		},
+		Examples: []string{
+			"portunix cleanup analyze",
+			"portunix cleanup run --older-than 30d --dry-run",
+			"portunix cleanup run --only containers --older-than 14d",
 		},
 	},
 	{
@@ -317,7 +890,7 @@ var CommandRegistry = []CommandInfo{
 
 // GetBasicCommands returns only the essential commands for basic help
 func GetBasicCommands() []CommandInfo {
-	essentials := []string{"install", "update", "plugin", "mcp", "container", "virt", "playbook", "python", "aiops", "system", "make", "package", "pft", "credential"}
+	essentials := []string{"install", "self-update", "plugin", "mcp", "container", "virt", "playbook", "python", "aiops", "system", "make", "package", "pft", "credential"}
 	var basic []CommandInfo
 	for _, cmd := range CommandRegistry {
 		for _, name := range essentials {
@@ -419,6 +992,11 @@ func GenerateBasicHelp() string {
 	sb.WriteString("  --help-expert  Extended help with all options, examples, and advanced features\n")
 	sb.WriteString("  --help-ai      Machine-readable format optimized for AI/LLM parsing\n")
 
+	sb.WriteString("\nRemote hosts:\n")
+	sb.WriteString("  --host [user@]host[:port]  Run the command on another host over SSH\n")
+	sb.WriteString("                             (an edge deployment name works as host)\n")
+	sb.WriteString("  --agentless                Use shell fallbacks instead of a portunix binary\n")
+
 	sb.WriteString("\nUse 'portunix <command> --help' for command details\n")
 	sb.WriteString("Use 'portunix --help-expert' for complete documentation\n")
 
@@ -522,28 +1100,42 @@ func GenerateExpertHelp() string {
 func GenerateAIHelp() (string, error) {
 	// Structure for AI output
 	type AIHelpOutput struct {
-		Tool        string        `json:"tool"`
-		Version     string        `json:"version"`
-		Description string        `json:"description"`
-		Commands    []CommandInfo `json:"commands"`
-		Environment []struct {
-			Name        string `json:"name"`
-			Description string `json:"description"`
-		} `json:"environment"`
+		Tool        string               `json:"tool"`
+		Version     string               `json:"version"`
+		Description string               `json:"description"`
+		Commands    []CommandInfo        `json:"commands"`
+		GlobalFlags []aihelp.Flag        `json:"global_flags"`
+		Environment []aihelp.Environment `json:"environment"`
+	}
+
+	// Attach subcommands of built-in cobra commands to the registry entries.
+	// Helper commands (container, python, ...) describe themselves via
+	// "portunix <command> --help-ai".
+	commands := make([]CommandInfo, len(CommandRegistry))
+	for i, info := range CommandRegistry {
+		commands[i] = info
+		if c, _, err := rootCmd.Find([]string{info.Name}); err == nil && c != rootCmd && len(info.SubCommands) == 0 {
+			commands[i].SubCommands = cobraSubCommandInfo(c, info.Category)
+		}
 	}
 
 	output := AIHelpOutput{
 		Tool:        "portunix",
-		Version:     "latest",
+		Version:     version.ProductVersion,
 		Description: "Portunix is a command-line interface (CLI) tool designed to simplify the management of environments. It allows you to install software, configure settings, create virtual machines, and more.",
-		Commands:    CommandRegistry,
-		Environment: []struct {
-			Name        string `json:"name"`
-			Description string `json:"description"`
-		}{
+		Commands:    commands,
+		GlobalFlags: append(aihelp.StandardGlobalFlags(),
+			aihelp.Flag{Name: "host", Type: "string", Description: "Run the command on [user@]host[:port] or an edge deployment over SSH; must precede the command"},
+			aihelp.Flag{Name: "agentless", Type: "boolean", Description: "With --host, use shell fallbacks instead of uploading portunix"},
+		),
+		Environment: []aihelp.Environment{
 			{Name: "PORTUNIX_HOME", Description: "Base directory for Portunix data"},
 			{Name: "PORTUNIX_CACHE", Description: "Cache directory for downloads"},
-			{Name: "PORTUNIX_LOG_LEVEL", Description: "Logging level (debug, info, warn, error)"},
+			{Name: logging.EnvVerbose, Description: "Show debug output (same as --verbose)"},
+			{Name: logging.EnvQuiet, Description: "Show errors only (same as --quiet)"},
+			{Name: logging.EnvLogJSON, Description: "Write log output as JSON lines (same as --log-json)"},
+			{Name: logging.EnvLogDir, Description: "Log file directory (default ~/.portunix/logs)"},
+			{Name: "PORTUNIX_<KEY>", Description: "Override configuration key <KEY>, e.g. PORTUNIX_CONTAINER_RUNTIME"},
 		},
 	}
 
@@ -555,6 +1147,33 @@ func GenerateAIHelp() (string, error) {
 	return string(jsonBytes), nil
 }
 
+// cobraSubCommandInfo converts visible cobra subcommands to CommandInfo
+func cobraSubCommandInfo(cmd *cobra.Command, category string) []CommandInfo {
+	var subs []CommandInfo
+	for _, child := range cmd.Commands() {
+		if !child.IsAvailableCommand() {
+			continue
+		}
+		ai := cobraAICommand(child)
+		info := CommandInfo{
+			Name:        child.Name(),
+			Brief:       child.Short,
+			Description: ai.Description,
+			Category:    category,
+			Examples:    ai.Examples,
+			SubCommands: cobraSubCommandInfo(child, category),
+		}
+		for _, arg := range ai.Arguments {
+			info.Parameters = append(info.Parameters, ParameterInfo{Name: arg.Name, Type: arg.Type, Required: arg.Required, Description: "Positional argument"})
+		}
+		for _, f := range ai.Flags {
+			info.Parameters = append(info.Parameters, ParameterInfo{Name: f.Name, Type: f.Type, Description: f.Description, Default: f.Default})
+		}
+		subs = append(subs, info)
+	}
+	return subs
+}
+
 // GetCommandInfo returns detailed information about a specific command
 func GetCommandInfo(commandName string) *CommandInfo {
 	for _, cmd := range CommandRegistry {
@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/update"
+	appversion "portunix.ai/app/version"
+	"portunix.ai/portunix/src/dispatcher"
+)
+
+var helpersCmd = &cobra.Command{
+	Use:   "helpers",
+	Short: "Manage helper binaries (ptx-*)",
+	Long: `Manage helper binaries installed next to the portunix executable.
+
+Helpers are updated from GitHub release archives for the current OS/arch.
+Downloads are verified against the release checksums (and signatures when
+the build has a signing key). Helper versions can be pinned per project in
+` + update.HelperPinFile + `:
+
+  {
+    "helpers": {
+      "ptx-python": "v1.10.8"
+    }
+  }`,
+}
+
+var helpersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed helpers with versions and pins",
+	Run: func(cmd *cobra.Command, args []string) {
+		disp := dispatcher.NewDispatcher(appversion.ProductVersion)
+		helpers, err := disp.DiscoverHelpers()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		pinFile, pins := loadProjectPins()
+
+		fmt.Printf("Helpers in %s:\n\n", disp.GetExecutableDir())
+		for _, h := range helpers {
+			pin := ""
+			if v, ok := pins.Helpers[h.Name]; ok {
+				pin = "pinned " + v
+			}
+			fmt.Printf("  %-16s %-10s %s\n", h.Name, helperVersion(h.Version), pin)
+		}
+		if pinFile != "" {
+			fmt.Printf("\nPins: %s\n", pinFile)
+		}
+	},
+}
+
+var helpersUpdateCmd = &cobra.Command{
+	Use:   "update [name...]",
+	Short: "Update helpers to the latest or pinned release",
+	Long: `Update installed helpers to the latest release, or to the version pinned
+in the project ` + update.HelperPinFile + ` file. Without names all installed
+helpers are updated.
+
+The release checksums are verified against the release signing key
+compiled into this build. Builds without a signing key refuse to update
+unless --insecure is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		check, _ := cmd.Flags().GetBool("check")
+		force, _ := cmd.Flags().GetBool("force")
+		version, _ := cmd.Flags().GetString("version")
+		insecure, _ := cmd.Flags().GetBool("insecure")
+
+		if err := runHelpersUpdate(args, version, check, force, insecure); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var helpersPinCmd = &cobra.Command{
+	Use:   "pin <name> <version>",
+	Short: "Pin a helper version for the current project",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, version := normalizeHelperName(args[0]), args[1]
+		if !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+
+		pinFile, pins := loadProjectPins()
+		if pinFile == "" {
+			pinFile = update.HelperPinFile
+		}
+		pins.Helpers[name] = version
+
+		if err := update.SaveHelperPins(pinFile, pins); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ %s pinned to %s in %s\n", name, version, pinFile)
+
+		if !sameMinorVersion(version, appversion.ProductVersion) {
+			fmt.Printf("⚠ Warning: %s differs from portunix %s in major/minor version; the dispatcher may reject it\n", version, appversion.ProductVersion)
+		}
+		fmt.Println("  Run 'portunix helpers update' to apply")
+	},
+}
+
+var helpersUnpinCmd = &cobra.Command{
+	Use:   "unpin <name>",
+	Short: "Remove a helper version pin",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := normalizeHelperName(args[0])
+
+		pinFile, pins := loadProjectPins()
+		if _, ok := pins.Helpers[name]; !ok || pinFile == "" {
+			fmt.Printf("%s is not pinned\n", name)
+			return
+		}
+		delete(pins.Helpers, name)
+
+		if err := update.SaveHelperPins(pinFile, pins); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ %s unpinned\n", name)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(helpersCmd)
+	helpersCmd.AddCommand(helpersListCmd)
+	helpersCmd.AddCommand(helpersUpdateCmd)
+	helpersCmd.AddCommand(helpersPinCmd)
+	helpersCmd.AddCommand(helpersUnpinCmd)
+
+	helpersUpdateCmd.Flags().Bool("check", false, "Only show available updates without installing")
+	helpersUpdateCmd.Flags().Bool("force", false, "Reinstall even if the helper is already at the target version")
+	helpersUpdateCmd.Flags().String("version", "", "Install this release version instead of the latest (ignores pins)")
+	helpersUpdateCmd.Flags().Bool("insecure", false, "Update without a release signing key, verifying only the unsigned checksums")
+}
+
+// helperTarget describes a planned helper update
+type helperTarget struct {
+	name      string
+	installed string
+	target    string
+	pinned    bool
+}
+
+// runHelpersUpdate resolves target versions, then downloads each required
+// release once and installs the selected helpers from it
+func runHelpersUpdate(names []string, version string, check, force, insecure bool) error {
+	disp := dispatcher.NewDispatcher(appversion.ProductVersion)
+	helpers, err := disp.DiscoverHelpers()
+	if err != nil {
+		return err
+	}
+
+	installed := make(map[string]string)
+	for _, h := range helpers {
+		installed[h.Name] = helperVersion(h.Version)
+	}
+
+	if len(names) == 0 {
+		for name := range installed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no helpers installed in %s", disp.GetExecutableDir())
+	}
+
+	_, pins := loadProjectPins()
+
+	var latest *update.ReleaseInfo
+	releases := make(map[string]*update.ReleaseInfo)
+	var targets []helperTarget
+
+	for _, name := range names {
+		name = normalizeHelperName(name)
+		t := helperTarget{name: name, installed: installed[name], target: version}
+		if t.target == "" {
+			if pin, ok := pins.Helpers[name]; ok {
+				t.target, t.pinned = pin, true
+			}
+		}
+
+		if t.target == "" {
+			if latest == nil {
+				fmt.Println("Checking for updates...")
+				if latest, err = update.GetLatestRelease(); err != nil {
+					return fmt.Errorf("unable to check for updates: %w", err)
+				}
+				releases[latest.Version] = latest
+			}
+			t.target = latest.Version
+		}
+		targets = append(targets, t)
+	}
+
+	var pending []helperTarget
+	for _, t := range targets {
+		note := ""
+		if t.pinned {
+			note = " (pinned)"
+		}
+		current := t.installed
+		if current == "" {
+			current = "not installed"
+		}
+
+		if !force && t.installed != "" && update.CompareVersions(t.installed, t.target) == 0 {
+			fmt.Printf("  %-16s %s%s ✓ up to date\n", t.name, current, note)
+			continue
+		}
+		fmt.Printf("  %-16s %s → %s%s\n", t.name, current, t.target, note)
+		pending = append(pending, t)
+	}
+
+	if len(pending) == 0 {
+		fmt.Println("✓ All helpers are up to date")
+		return nil
+	}
+	if check {
+		fmt.Println("Run 'portunix helpers update' to install.")
+		return nil
+	}
+
+	// Group by release so each archive is downloaded only once
+	byVersion := make(map[string][]helperTarget)
+	for _, t := range pending {
+		byVersion[t.target] = append(byVersion[t.target], t)
+	}
+
+	for v, group := range byVersion {
+		release, ok := releases[v]
+		if !ok {
+			if release, err = update.GetRelease(v); err != nil {
+				return fmt.Errorf("unable to get release %s: %w", v, err)
+			}
+		}
+
+		if err := installHelpersFromRelease(disp.GetExecutableDir(), release, group, insecure); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("✓ Helpers updated successfully")
+	return nil
+}
+
+// installHelpersFromRelease downloads and verifies a release archive, then
+// installs the given helpers from it. Without a signing key in the build it
+// refuses unless insecure is set.
+func installHelpersFromRelease(targetDir string, release *update.ReleaseInfo, targets []helperTarget, insecure bool) error {
+	if err := update.RequireSigningKey(insecure); err != nil {
+		return err
+	}
+	checksums, signed, err := update.FetchReleaseChecksums(release)
+	if err != nil {
+		return fmt.Errorf("%w, refusing to install unverified helpers", err)
+	}
+	if signed {
+		fmt.Printf("✓ Signature of release %s verified\n", release.Version)
+	} else {
+		fmt.Println("⚠ Warning: --insecure: the release checksums are not signed, the release is not authenticated")
+	}
+
+	fmt.Printf("✓ Downloading release %s for %s/%s...\n", release.Version, update.GetOS(), update.GetArch())
+	archiveFile, err := update.DownloadUpdate(release, checksums)
+	if err != nil {
+		return fmt.Errorf("failed to download release: %w", err)
+	}
+	defer os.Remove(archiveFile)
+	if signed {
+		fmt.Println("✓ Checksum verified")
+	} else {
+		fmt.Println("✓ Checksum matches the unsigned release checksums")
+	}
+
+	for _, t := range targets {
+		binaryPath, err := update.ExtractHelperBinary(archiveFile, t.name)
+		if err != nil {
+			return err
+		}
+
+		err = update.InstallHelperBinary(targetDir, t.name, binaryPath)
+		os.Remove(binaryPath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ %s updated to %s\n", t.name, release.Version)
+	}
+	return nil
+}
+
+// loadProjectPins loads pins from the nearest pin file above the working directory
+func loadProjectPins() (string, *update.HelperPins) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		cwd = "."
+	}
+
+	pinFile := update.FindHelperPinFile(cwd)
+	pins, err := update.LoadHelperPins(pinFile)
+	if err != nil {
+		fmt.Printf("⚠ Warning: %v\n", err)
+		return pinFile, &update.HelperPins{Helpers: map[string]string{}}
+	}
+	return pinFile, pins
+}
+
+// helperVersion extracts the version from "ptx-python version v1.10.8" output
+func helperVersion(output string) string {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return "unknown"
+	}
+	return fields[len(fields)-1]
+}
+
+// normalizeHelperName accepts both "python" and "ptx-python"
+func normalizeHelperName(name string) string {
+	if strings.HasPrefix(name, "ptx-") {
+		return name
+	}
+	return "ptx-" + name
+}
+
+// sameMinorVersion reports whether two versions share major and minor numbers
+func sameMinorVersion(a, b string) bool {
+	pa := strings.SplitN(strings.TrimPrefix(a, "v"), ".", 3)
+	pb := strings.SplitN(strings.TrimPrefix(b, "v"), ".", 3)
+	if len(pa) < 2 || len(pb) < 2 {
+		return true
+	}
+	return pa[0] == pb[0] && pa[1] == pb[1]
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"portunix.ai/app/update"
+)
+
+func TestInstallHelpersRequiresSigningKey(t *testing.T) {
+	previous := update.SigningPublicKey
+	update.SigningPublicKey = ""
+	t.Cleanup(func() { update.SigningPublicKey = previous })
+
+	// The refusal comes before any download
+	release := &update.ReleaseInfo{Version: "v1.0.0"}
+	targets := []helperTarget{{name: "ptx-python", target: "v1.0.0"}}
+	err := installHelpersFromRelease(t.TempDir(), release, targets, false)
+	if err == nil || !strings.Contains(err.Error(), "--insecure") {
+		t.Errorf("unsigned update should be refused, got %v", err)
+	}
+
+	// --insecure goes on to the checksums, which this release lacks
+	err = installHelpersFromRelease(t.TempDir(), release, targets, true)
+	if err == nil || !strings.Contains(err.Error(), "publishes no checksums") {
+		t.Errorf("--insecure should proceed to checksum verification, got %v", err)
+	}
+}
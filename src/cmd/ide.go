@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/ide"
+)
+
+var ideCmd = &cobra.Command{
+	Use:   "ide",
+	Short: "Connect VS Code and JetBrains IDEs to VMs and containers",
+	Long: `Open VS Code or a JetBrains IDE on a machine portunix manages, with the
+editor running locally and the project on the VM or in the container.`,
+}
+
+var ideConnectCmd = &cobra.Command{
+	Use:   "connect <container|vm>",
+	Short: "Configure an editor for a VM or container and print how to open it",
+	Long: `Configures VS Code Remote-SSH or JetBrains Gateway for a VM created by
+portunix, or VS Code Dev Containers for a running container, and prints the
+command and link that open the editor there.
+
+For VMs, and fleet hosts or edge deployments, a 'Host portunix-<name>' entry
+with the key of the VM is written to ~/.portunix/ssh/config, which
+~/.ssh/config includes, so 'ssh portunix-<name>' works as well. The target
+kind is detected; --vm or --container forces it.`,
+	Example: `  portunix ide connect ubuntu-dev
+  portunix ide connect ubuntu-dev --editor jetbrains --product GO --path /home/ubuntu/src
+  portunix ide connect my-container --open
+  portunix ide connect ubuntu-dev --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := ide.Options{}
+		opts.Editor, _ = cmd.Flags().GetString("editor")
+		opts.Path, _ = cmd.Flags().GetString("path")
+		opts.Runtime, _ = cmd.Flags().GetString("runtime")
+		opts.Product, _ = cmd.Flags().GetString("product")
+		isVM, _ := cmd.Flags().GetBool("vm")
+		isContainer, _ := cmd.Flags().GetBool("container")
+		open, _ := cmd.Flags().GetBool("open")
+		formatJSON, _ := cmd.Flags().GetBool("json")
+
+		switch {
+		case isVM && isContainer:
+			fmt.Fprintf(os.Stderr, "❌ Error: --vm and --container are exclusive\n")
+			os.Exit(1)
+		case isVM:
+			opts.Kind = ide.KindVM
+		case isContainer:
+			opts.Kind = ide.KindContainer
+		}
+
+		c, err := ide.Connect(args[0], opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		includeAdded := false
+		if c.Host != nil {
+			if err := ide.UpdateHosts(ide.SSHConfigPath(), c.Host); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			if includeAdded, err = ide.EnsureInclude(ide.UserSSHConfigPath(), ide.SSHConfigPath()); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if formatJSON {
+			// Keep the & of URLs readable
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetEscapeHTML(false)
+			enc.SetIndent("", "  ")
+			enc.Encode(c)
+		} else {
+			if c.Host != nil {
+				fmt.Printf("✅ SSH host %s configured in %s\n", c.Host.Alias, ide.SSHConfigPath())
+				if includeAdded {
+					fmt.Printf("   Included from %s\n", ide.UserSSHConfigPath())
+				}
+			}
+			fmt.Printf("\nOpen %s on %s (%s):\n", c.Editor, c.Name, c.Path)
+			if len(c.Command) > 0 {
+				fmt.Printf("  %s\n", shellCommand(c.Command))
+			}
+			fmt.Printf("  %s\n", c.URL)
+			for _, note := range c.Notes {
+				fmt.Printf("\n💡 %s\n", note)
+			}
+		}
+
+		if open {
+			command := c.OpenCommand()
+			if err := exec.Command(command[0], command[1:]...).Start(); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Error: failed to open the editor: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// shellCommand returns args as a command line to copy into a shell
+func shellCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if strings.ContainsAny(arg, " \t\"'$&;|<>()") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+func init() {
+	rootCmd.AddCommand(ideCmd)
+	ideCmd.AddCommand(ideConnectCmd)
+
+	ideConnectCmd.Flags().String("editor", ide.VSCode, "Editor: vscode or jetbrains")
+	ideConnectCmd.Flags().String("path", "", "Folder to open (default: home directory of the VM, working directory of the container)")
+	ideConnectCmd.Flags().String("product", "", "JetBrains IDE product code for Gateway, e.g. IU, GO, PY")
+	ideConnectCmd.Flags().String("runtime", "", "Container runtime: docker or podman (default: configured)")
+	ideConnectCmd.Flags().Bool("vm", false, "The target is a VM or SSH host")
+	ideConnectCmd.Flags().Bool("container", false, "The target is a container")
+	ideConnectCmd.Flags().Bool("open", false, "Open the editor now")
+	ideConnectCmd.Flags().Bool("json", false, "Output the connection in JSON format")
+}
@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/cache"
+	"portunix.ai/app/config"
+	"portunix.ai/app/firstrun"
+	"portunix.ai/app/proxy"
+	"portunix.ai/app/selfinstall"
+	appversion "portunix.ai/app/version"
+	"portunix.ai/portunix/src/dispatcher"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "First-run setup of portunix for this machine",
+	Long: `Set up portunix for a new team member. init detects the environment,
+asks a few questions and then:
+
+  • installs the helpers for the selected roles (dev, devops, product)
+  • configures the download cache and the proxy
+  • installs shell completion
+  • adds the portunix directory to PATH
+
+Every answer has a default; --yes accepts all defaults, which is also what
+happens when stdin is not a terminal. Run init again at any time to change
+the setup.`,
+	Example: `  portunix init
+  portunix init --role devops --yes
+  portunix init --dry-run`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		roles, _ := cmd.Flags().GetStringSlice("role")
+		yes, _ := cmd.Flags().GetBool("yes")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		skipHelpers, _ := cmd.Flags().GetBool("skip-helpers")
+
+		for _, role := range roles {
+			if err := firstrun.ValidateRole(role); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		env := firstrun.Detect()
+		printEnvironment(env)
+
+		ask := &initPrompter{reader: bufio.NewReader(os.Stdin), defaults: yes || !isInteractive()}
+		plan := planInit(env, ask, roles, skipHelpers)
+		printInitPlan(plan)
+		if dryRun {
+			fmt.Println("\nDry run: nothing changed")
+			return
+		}
+		if !ask.confirm("\nApply this setup?", true) {
+			fmt.Println("Cancelled")
+			return
+		}
+		if err := applyInit(env, plan); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("\n✅ Portunix is set up")
+		fmt.Println("\nNext steps:")
+		fmt.Println("  portunix install --list      # packages available for installation")
+		fmt.Println("  portunix system info         # check the detected environment")
+		if plan.completion != "" || plan.addToPath {
+			fmt.Println("  Open a new terminal to use completion and the updated PATH")
+		}
+	},
+}
+
+// initPlan collects the answers of the init questions
+type initPlan struct {
+	roles      []string
+	helpers    []string // helpers to install; installed ones are left alone
+	cacheDir   string   // "" keeps the current cache directory
+	cacheOff   bool
+	proxy      string
+	noProxy    string
+	clearProxy bool
+	completion string // shell to install completion for, "" for none
+	addToPath  bool
+}
+
+func printEnvironment(env *firstrun.Environment) {
+	fmt.Println("🔍 Detected environment")
+	fmt.Printf("  System:            %s/%s\n", env.OS, env.Arch)
+	if env.Shell != "" {
+		fmt.Printf("  Shell:             %s\n", env.Shell)
+	}
+	runtimeName := env.ContainerRuntime
+	if runtimeName == "" {
+		runtimeName = "none (install with 'portunix install podman')"
+	}
+	fmt.Printf("  Container runtime: %s\n", runtimeName)
+	if len(env.Tools) > 0 {
+		fmt.Printf("  Tools:             %s\n", strings.Join(env.Tools, ", "))
+	}
+	if env.Proxy != "" {
+		fmt.Printf("  Proxy:             %s\n", env.Proxy)
+	}
+	inPath := "not in PATH"
+	if env.InPath {
+		inPath = "in PATH"
+	}
+	fmt.Printf("  Portunix:          %s (%s)\n\n", env.ExecDir, inPath)
+}
+
+// planInit asks the setup questions, using the flags and detected
+// environment as defaults
+func planInit(env *firstrun.Environment, ask *initPrompter, roles []string, skipHelpers bool) *initPlan {
+	plan := &initPlan{}
+
+	if len(roles) == 0 {
+		roles = ask.roles(env.SuggestedRoles())
+	}
+	plan.roles = roles
+
+	if !skipHelpers {
+		installed := installedHelpers()
+		for _, helper := range firstrun.HelpersForRoles(roles) {
+			if !installed[helper] {
+				plan.helpers = append(plan.helpers, helper)
+			}
+		}
+		if len(plan.helpers) > 0 && !ask.confirm(fmt.Sprintf("Install helpers %s?", strings.Join(plan.helpers, ", ")), true) {
+			plan.helpers = nil
+		}
+	}
+
+	current := cache.LoadConfig()
+	if ask.confirm("Use a download cache (speeds up repeated and offline installs)?", current.Enabled) {
+		if dir := ask.input("Cache directory", current.BaseDir); dir != current.BaseDir {
+			plan.cacheDir = dir
+		}
+	} else {
+		plan.cacheOff = true
+	}
+
+	configured := config.GetString(proxy.KeyHTTPS, config.GetString(proxy.KeyHTTP, ""))
+	defaultProxy := configured
+	if defaultProxy == "" {
+		defaultProxy = env.Proxy
+	}
+	if answer := ask.input("HTTP(S) proxy ('-' for direct connection)", defaultProxy); answer != configured {
+		plan.proxy = answer
+		plan.clearProxy = answer == ""
+		if answer != "" {
+			plan.noProxy = ask.input("Hosts reached without proxy", firstNonEmpty(config.GetString(proxy.KeyNoProxy, ""), env.NoProxy, "localhost,127.0.0.1"))
+		}
+	}
+
+	if env.Shell != "" && ask.confirm(fmt.Sprintf("Install %s completion?", env.Shell), true) {
+		plan.completion = env.Shell
+	}
+	if env.ExecDir != "" && !env.InPath {
+		plan.addToPath = ask.confirm(fmt.Sprintf("Add %s to PATH?", env.ExecDir), true)
+	}
+	return plan
+}
+
+func printInitPlan(plan *initPlan) {
+	fmt.Println("\n📋 Setup")
+	fmt.Printf("  Roles:       %s\n", strings.Join(plan.roles, ", "))
+	if len(plan.helpers) > 0 {
+		fmt.Printf("  Install:     %s\n", strings.Join(plan.helpers, ", "))
+	}
+	switch {
+	case plan.cacheOff:
+		fmt.Println("  Cache:       disabled")
+	case plan.cacheDir != "":
+		fmt.Printf("  Cache:       %s\n", plan.cacheDir)
+	}
+	switch {
+	case plan.clearProxy:
+		fmt.Println("  Proxy:       none (direct connection)")
+	case plan.proxy != "":
+		fmt.Printf("  Proxy:       %s (no proxy: %s)\n", plan.proxy, plan.noProxy)
+	}
+	if plan.completion != "" {
+		fmt.Printf("  Completion:  %s\n", plan.completion)
+	}
+	if plan.addToPath {
+		fmt.Println("  PATH:        add portunix directory")
+	}
+}
+
+// applyInit carries out the plan; helper installation failures do not
+// stop the remaining steps
+func applyInit(env *firstrun.Environment, plan *initPlan) error {
+	set := func(key, value string) error {
+		if err := config.ValidateKey(key, value); err != nil {
+			return err
+		}
+		return config.Set(config.ScopeUser, key, value)
+	}
+
+	if err := set(firstrun.KeyRoles, strings.Join(plan.roles, ",")); err != nil {
+		return err
+	}
+	if plan.cacheOff {
+		if err := set("cache.disabled", "true"); err != nil {
+			return err
+		}
+		fmt.Println("✓ Download cache disabled")
+	} else {
+		if _, source, _ := config.Lookup("cache.disabled"); source == string(config.ScopeUser) {
+			config.Unset(config.ScopeUser, "cache.disabled")
+		}
+		if plan.cacheDir != "" {
+			if err := set("cache.dir", plan.cacheDir); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Cache directory set to %s\n", plan.cacheDir)
+		}
+	}
+
+	if plan.proxy != "" {
+		if err := set(proxy.KeyHTTPS, plan.proxy); err != nil {
+			return err
+		}
+		if err := set(proxy.KeyNoProxy, plan.noProxy); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Proxy set to %s\n", plan.proxy)
+		if _, err := proxy.Apply(); err != nil {
+			fmt.Printf("⚠️  Proxy could not be applied: %v\n", err)
+		}
+	} else if plan.clearProxy {
+		for _, key := range []string{proxy.KeyHTTP, proxy.KeyHTTPS} {
+			if err := config.Unset(config.ScopeUser, key); err != nil {
+				return err
+			}
+		}
+		fmt.Println("✓ Proxy removed")
+	}
+
+	var failed []string
+	if len(plan.helpers) > 0 {
+		fmt.Println()
+		if err := runHelpersUpdate(plan.helpers, "", false, false, false); err != nil {
+			fmt.Printf("⚠️  Helpers were not installed: %v\n", err)
+			fmt.Printf("   Retry with 'portunix helpers update %s'\n", strings.Join(plan.helpers, " "))
+			failed = append(failed, "helpers")
+		}
+	}
+
+	if plan.completion != "" {
+		var script bytes.Buffer
+		if err := writeCompletionScript(&script, plan.completion, true); err != nil {
+			return err
+		}
+		files, err := firstrun.InstallCompletion(plan.completion, script.Bytes())
+		for _, file := range files {
+			fmt.Printf("✓ Completion written to %s\n", file)
+		}
+		if err != nil {
+			fmt.Printf("⚠️  Completion setup incomplete: %v\n", err)
+			failed = append(failed, "completion")
+		}
+	}
+
+	if plan.addToPath {
+		if err := selfinstall.AddToSystemPath(env.ExecDir); err != nil {
+			fmt.Printf("⚠️  PATH not updated: %v\n", err)
+			failed = append(failed, "PATH")
+		} else {
+			fmt.Printf("✓ Added %s to PATH\n", env.ExecDir)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("setup incomplete (%s); run 'portunix init' again to retry", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func installedHelpers() map[string]bool {
+	installed := make(map[string]bool)
+	helpers, err := dispatcher.NewDispatcher(appversion.ProductVersion).DiscoverHelpers()
+	if err != nil {
+		return installed
+	}
+	for _, h := range helpers {
+		installed[h.Name] = true
+	}
+	return installed
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// initPrompter asks the init questions on the terminal; with defaults set
+// it answers every question with its default
+type initPrompter struct {
+	reader   *bufio.Reader
+	defaults bool
+}
+
+func (p *initPrompter) read(prompt string) string {
+	fmt.Print(prompt)
+	line, _ := p.reader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func (p *initPrompter) confirm(question string, def bool) bool {
+	if p.defaults {
+		return def
+	}
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	switch strings.ToLower(p.read(question + " " + hint + ": ")) {
+	case "":
+		return def
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+func (p *initPrompter) input(question, def string) string {
+	if p.defaults {
+		return def
+	}
+	prompt := question + ": "
+	if def != "" {
+		prompt = fmt.Sprintf("%s [%s]: ", question, def)
+	}
+	if answer := p.read(prompt); answer != "" {
+		if answer == "-" {
+			return ""
+		}
+		return answer
+	}
+	return def
+}
+
+// roles asks for the roles by number, e.g. "1,2"
+func (p *initPrompter) roles(def []string) []string {
+	if p.defaults {
+		return def
+	}
+	all := firstrun.Roles()
+	fmt.Println("Which roles describe your work?")
+	var defNumbers []string
+	for i, role := range all {
+		fmt.Printf("  [%d] %-8s %s\n", i+1, role, firstrun.RoleDescription(role))
+		for _, d := range def {
+			if d == role {
+				defNumbers = append(defNumbers, strconv.Itoa(i+1))
+			}
+		}
+	}
+	for {
+		answer := p.input("Select one or more (comma separated)", strings.Join(defNumbers, ","))
+		var roles []string
+		valid := answer != ""
+		for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > len(all) {
+				valid = false
+				break
+			}
+			roles = append(roles, all[n-1])
+		}
+		if valid {
+			return roles
+		}
+		fmt.Printf("Please enter numbers between 1 and %d\n", len(all))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().StringSlice("role", nil, "Role to set up for: dev, devops, product (repeatable)")
+	initCmd.Flags().BoolP("yes", "y", false, "Accept all defaults without asking")
+	initCmd.Flags().Bool("dry-run", false, "Show the setup without changing anything")
+	initCmd.Flags().Bool("skip-helpers", false, "Do not install helpers")
+}
@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/k8s"
+)
+
+var k8sCmd = &cobra.Command{
+	Use:   "k8s",
+	Short: "Create local Kubernetes clusters with kind, k3d or k3s",
+	Long: `Create and manage local Kubernetes clusters for development.
+
+Clusters run in containers (kind, k3d) or directly on a Linux host (k3s). The
+provider and kubectl are installed through 'portunix install' when missing.
+Each cluster gets its own kubeconfig in ~/.portunix/k8s/<name>, which is also
+merged into ~/.kube/config unless --no-merge is given.`,
+}
+
+var k8sCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a cluster and optionally deploy applications to it",
+	Long: `Create a local cluster, write its kubeconfig and optionally deploy the
+PFT feedback stack or docker compose files converted to Kubernetes manifests.
+
+Converted services are reachable inside the cluster under their compose
+names; use 'kubectl port-forward' to reach published ports from the host.`,
+	Example: `  portunix k8s create
+  portunix k8s create dev --provider k3d --nodes 3
+  portunix k8s create pft --deploy-pft
+  portunix k8s create demo --compose ./docker-compose.yml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := "portunix"
+		if len(args) > 0 {
+			name = args[0]
+		}
+		if err := k8s.ValidateClusterName(name); err != nil {
+			return err
+		}
+		providerName, _ := cmd.Flags().GetString("provider")
+		provider, err := k8s.NewProvider(providerName)
+		if err != nil {
+			return err
+		}
+		nodes, _ := cmd.Flags().GetInt("nodes")
+		if nodes < 1 {
+			return fmt.Errorf("--nodes must be at least 1")
+		}
+		if existing, err := k8s.LoadCluster(name); err == nil {
+			return fmt.Errorf("cluster %s already exists (provider %s); delete it first with 'portunix k8s delete %s'", name, existing.Provider, name)
+		}
+
+		if providerName != "k3s" && !hasContainerRuntime() {
+			return fmt.Errorf("%s runs nodes in containers and needs Docker or Podman; install one with 'portunix install docker'", providerName)
+		}
+		for _, tool := range []string{provider.Tool(), "kubectl"} {
+			if err := ensureTool(tool); err != nil {
+				return err
+			}
+		}
+
+		kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+		if kubeconfigPath == "" {
+			kubeconfigPath = filepath.Join(k8s.ClusterDir(name), "kubeconfig")
+		}
+		if err := os.MkdirAll(k8s.ClusterDir(name), 0700); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(kubeconfigPath), 0700); err != nil {
+			return err
+		}
+
+		fmt.Printf("🚀 Creating %s cluster %s with %d node(s)...\n", providerName, name, nodes)
+		if err := provider.Create(name, nodes, kubeconfigPath); err != nil {
+			return fmt.Errorf("failed to create cluster: %w", err)
+		}
+		cluster := &k8s.Cluster{
+			Name:       name,
+			Provider:   providerName,
+			Nodes:      nodes,
+			Kubeconfig: kubeconfigPath,
+			Context:    provider.Context(name),
+			CreatedAt:  time.Now(),
+		}
+		if err := cluster.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Cluster %s created, kubeconfig: %s\n", name, kubeconfigPath)
+
+		if noMerge, _ := cmd.Flags().GetBool("no-merge"); !noMerge {
+			data, err := os.ReadFile(kubeconfigPath)
+			if err != nil {
+				return err
+			}
+			target := k8s.DefaultKubeconfigPath()
+			if err := k8s.MergeKubeconfig(data, target, true); err != nil {
+				return fmt.Errorf("failed to merge kubeconfig into %s: %w", target, err)
+			}
+			fmt.Printf("   Context %s merged into %s and set as current\n", cluster.Context, target)
+		}
+
+		if deployPFT, _ := cmd.Flags().GetBool("deploy-pft"); deployPFT {
+			env, err := k8s.PFTEnv(name)
+			if err != nil {
+				return err
+			}
+			if err := deployCompose(cluster, "pft", k8s.PFTCompose(), env, "pft"); err != nil {
+				return err
+			}
+			fmt.Println("   Open Fider with: kubectl --namespace pft port-forward service/fider 3000:3000")
+		}
+		composeFiles, _ := cmd.Flags().GetStringArray("compose")
+		for _, path := range composeFiles {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			env, err := k8s.LoadComposeEnv(path)
+			if err != nil {
+				return err
+			}
+			namespace := k8s.ComposeNamespace(path, data)
+			if err := deployCompose(cluster, namespace, data, env, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+		return nil
+	},
+}
+
+// deployCompose converts a compose file, stores the manifests in the cluster
+// directory and applies them
+func deployCompose(cluster *k8s.Cluster, namespace string, data []byte, env map[string]string, stem string) error {
+	conversion, err := k8s.ConvertCompose(data, env, namespace)
+	if err != nil {
+		return err
+	}
+	for _, warning := range conversion.Warnings {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+	manifests, err := conversion.YAML()
+	if err != nil {
+		return err
+	}
+	manifestPath := filepath.Join(k8s.ClusterDir(cluster.Name), stem+".k8s.yaml")
+	if err := os.WriteFile(manifestPath, []byte(manifests), 0600); err != nil {
+		return err
+	}
+	fmt.Printf("📦 Deploying %s to namespace %s...\n", stem, namespace)
+	if err := k8s.Apply(cluster.Kubeconfig, manifestPath); err != nil {
+		return fmt.Errorf("kubectl apply failed (manifests kept in %s): %w", manifestPath, err)
+	}
+	cluster.Deployed = append(cluster.Deployed, manifestPath)
+	return cluster.Save()
+}
+
+// hasContainerRuntime reports whether docker or podman is available
+func hasContainerRuntime() bool {
+	for _, tool := range []string{"docker", "podman"} {
+		if _, err := exec.LookPath(tool); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureTool installs tool through 'portunix install' when it is missing
+func ensureTool(tool string) error {
+	if tool == "" {
+		return nil
+	}
+	if _, err := exec.LookPath(tool); err == nil {
+		return nil
+	}
+	fmt.Printf("📦 Installing %s...\n", tool)
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	install := exec.Command(self, "install", tool)
+	install.Stdout = os.Stdout
+	install.Stderr = os.Stderr
+	if err := install.Run(); err != nil {
+		return fmt.Errorf("failed to install %s: %w", tool, err)
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return fmt.Errorf("%s was installed but is not on PATH; open a new shell and retry", tool)
+	}
+	return nil
+}
+
+var k8sListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List clusters created by portunix",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clusters, err := k8s.ListClusters()
+		if err != nil {
+			return err
+		}
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			if clusters == nil {
+				clusters = []*k8s.Cluster{}
+			}
+			data, err := json.MarshalIndent(clusters, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		if len(clusters) == 0 {
+			fmt.Println("No clusters found. Create one with: portunix k8s create")
+			return nil
+		}
+		fmt.Printf("%-20s %-8s %-6s %-24s %s\n", "NAME", "PROVIDER", "NODES", "CONTEXT", "CREATED")
+		for _, c := range clusters {
+			fmt.Printf("%-20s %-8s %-6d %-24s %s\n", c.Name, c.Provider, c.Nodes, c.Context, c.CreatedAt.Format("2006-01-02 15:04"))
+		}
+		return nil
+	},
+}
+
+var k8sDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a cluster and its kubeconfig entries",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cluster, err := k8s.LoadCluster(args[0])
+		if err != nil {
+			return err
+		}
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			fmt.Printf("Delete %s cluster %s and all its workloads? [y/N]: ", cluster.Provider, cluster.Name)
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+				fmt.Println("Cancelled")
+				return nil
+			}
+		}
+		provider, err := k8s.NewProvider(cluster.Provider)
+		if err != nil {
+			return err
+		}
+		if err := provider.Delete(cluster.Name); err != nil {
+			return fmt.Errorf("failed to delete cluster: %w", err)
+		}
+		target := k8s.DefaultKubeconfigPath()
+		if err := k8s.RemoveFromKubeconfig(target, cluster.Context); err != nil {
+			fmt.Printf("⚠️  Failed to clean up %s: %v\n", target, err)
+		}
+		if err := k8s.RemoveCluster(cluster.Name); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Cluster %s deleted\n", cluster.Name)
+		return nil
+	},
+}
+
+var k8sKubeconfigCmd = &cobra.Command{
+	Use:   "kubeconfig <name>",
+	Short: "Print or refresh the kubeconfig of a cluster",
+	Long: `Print the kubeconfig of a cluster. With --merge it is fetched again from
+the provider (e.g. after the API port changed) and merged into ~/.kube/config.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cluster, err := k8s.LoadCluster(args[0])
+		if err != nil {
+			return err
+		}
+		merge, _ := cmd.Flags().GetBool("merge")
+		if !merge {
+			data, err := os.ReadFile(cluster.Kubeconfig)
+			if err != nil {
+				return err
+			}
+			fmt.Print(string(data))
+			return nil
+		}
+		provider, err := k8s.NewProvider(cluster.Provider)
+		if err != nil {
+			return err
+		}
+		data, err := provider.Kubeconfig(cluster.Name)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(cluster.Kubeconfig, data, 0600); err != nil {
+			return err
+		}
+		target := k8s.DefaultKubeconfigPath()
+		if err := k8s.MergeKubeconfig(data, target, true); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Context %s merged into %s\n", cluster.Context, target)
+		return nil
+	},
+}
+
+var k8sConvertCmd = &cobra.Command{
+	Use:   "convert <compose-file>",
+	Short: "Convert a docker compose file to Kubernetes manifests",
+	Long: `Convert a docker compose file to a Namespace, Deployments, Services and
+PersistentVolumeClaims. Variables are expanded from the .env file next to the
+compose file and the environment. Compose features without a Kubernetes
+equivalent, such as bind mounts, are reported as warnings.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		env, err := k8s.LoadComposeEnv(args[0])
+		if err != nil {
+			return err
+		}
+		namespace, _ := cmd.Flags().GetString("namespace")
+		if namespace == "" {
+			namespace = k8s.ComposeNamespace(args[0], data)
+		}
+		conversion, err := k8s.ConvertCompose(data, env, namespace)
+		if err != nil {
+			return err
+		}
+		for _, warning := range conversion.Warnings {
+			fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+		}
+		manifests, err := conversion.YAML()
+		if err != nil {
+			return err
+		}
+		if output, _ := cmd.Flags().GetString("output"); output != "" {
+			if err := os.WriteFile(output, []byte(manifests), 0644); err != nil {
+				return err
+			}
+			fmt.Printf("✅ %d manifests written to %s\n", len(conversion.Manifests), output)
+			return nil
+		}
+		fmt.Print(manifests)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(k8sCmd)
+	k8sCmd.AddCommand(k8sCreateCmd)
+	k8sCmd.AddCommand(k8sListCmd)
+	k8sCmd.AddCommand(k8sDeleteCmd)
+	k8sCmd.AddCommand(k8sKubeconfigCmd)
+	k8sCmd.AddCommand(k8sConvertCmd)
+
+	k8sCreateCmd.Flags().String("provider", "kind", fmt.Sprintf("Cluster provider %v", k8s.ProviderNames()))
+	k8sCreateCmd.Flags().Int("nodes", 1, "Number of nodes (one control plane, the rest workers)")
+	k8sCreateCmd.Flags().String("kubeconfig", "", "Write the cluster kubeconfig to this file (default: ~/.portunix/k8s/<name>/kubeconfig)")
+	k8sCreateCmd.Flags().Bool("no-merge", false, "Do not merge the kubeconfig into ~/.kube/config")
+	k8sCreateCmd.Flags().Bool("deploy-pft", false, "Deploy the PFT feedback stack (Fider) to namespace pft")
+	k8sCreateCmd.Flags().StringArray("compose", nil, "Convert a compose file to manifests and deploy it (repeatable)")
+
+	k8sListCmd.Flags().Bool("json", false, "Output in JSON format")
+	k8sDeleteCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	k8sKubeconfigCmd.Flags().Bool("merge", false, "Fetch the kubeconfig again and merge it into ~/.kube/config")
+	k8sConvertCmd.Flags().StringP("output", "o", "", "Write the manifests to a file instead of stdout")
+	k8sConvertCmd.Flags().String("namespace", "", "Target namespace (default: compose project name)")
+}
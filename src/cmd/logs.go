@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/config"
+	"portunix.ai/portunix/pkg/logging"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Inspect and clean Portunix log files",
+	Long: `Inspect log files captured by portunix and its helpers.
+
+Every invocation writes structured JSON lines to ~/.portunix/logs
+(one file per day) independently of --verbose/--quiet, so failed
+operations can be diagnosed afterwards. Set logging.file=false to
+disable capture.`,
+}
+
+var logsPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the log directory",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(logging.LogDir(""))
+	},
+}
+
+var logsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show recent log records",
+	Run: func(cmd *cobra.Command, args []string) {
+		lines, _ := cmd.Flags().GetInt("lines")
+		level, _ := cmd.Flags().GetString("level")
+		component, _ := cmd.Flags().GetString("component")
+		raw, _ := cmd.Flags().GetBool("raw")
+
+		minLevel, ok := logLevelRank[strings.ToUpper(level)]
+		if level != "" && !ok {
+			fmt.Printf("Error: invalid level: %s (must be debug, info, warn or error)\n", level)
+			os.Exit(1)
+		}
+
+		records, err := readLogRecords(lines, minLevel, component)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(records) == 0 {
+			fmt.Println("No log records found")
+			return
+		}
+
+		for _, r := range records {
+			if raw {
+				fmt.Println(r.line)
+			} else {
+				fmt.Println(formatLogRecord(r.fields))
+			}
+		}
+	},
+}
+
+var logsCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove log files older than the retention period",
+	Run: func(cmd *cobra.Command, args []string) {
+		days, _ := cmd.Flags().GetInt("days")
+		if days < 0 {
+			days = config.GetInt("logging.retention_days", logging.DefaultRetentionDays)
+		}
+
+		removed, err := logging.Cleanup("", days)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Removed %d log file(s) older than %d days\n", removed, days)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.AddCommand(logsPathCmd)
+	logsCmd.AddCommand(logsShowCmd)
+	logsCmd.AddCommand(logsCleanCmd)
+
+	logsShowCmd.Flags().IntP("lines", "n", 50, "Number of most recent records to show")
+	logsShowCmd.Flags().String("level", "", "Minimum level to show (debug, info, warn, error)")
+	logsShowCmd.Flags().String("component", "", "Only show records from this component (e.g. ptx-container)")
+	logsShowCmd.Flags().Bool("raw", false, "Print raw JSON lines")
+	logsCleanCmd.Flags().Int("days", -1, "Retention in days (default: logging.retention_days)")
+}
+
+// logLevelRank orders level names (records use zerolog's lower case names)
+var logLevelRank = map[string]int{"DEBUG": 0, "INFO": 1, "WARN": 2, "ERROR": 3}
+
+// logRecord is a parsed log line
+type logRecord struct {
+	line   string
+	fields map[string]interface{}
+}
+
+// readLogRecords returns the last n matching records across all log files,
+// oldest first
+func readLogRecords(n, minLevel int, component string) ([]logRecord, error) {
+	files, err := logging.LogFiles("")
+	if err != nil {
+		return nil, err
+	}
+	// LogFiles is newest first; read oldest first to keep chronological order
+	sort.Strings(files)
+
+	var records []logRecord
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			var fields map[string]interface{}
+			if json.Unmarshal([]byte(line), &fields) != nil {
+				continue
+			}
+			if logLevelRank[strings.ToUpper(fmt.Sprint(fields["level"]))] < minLevel {
+				continue
+			}
+			if component != "" && fields["component"] != component {
+				continue
+			}
+			records = append(records, logRecord{line: line, fields: fields})
+			if n > 0 && len(records) > n {
+				records = records[1:]
+			}
+		}
+		f.Close()
+	}
+	return records, nil
+}
+
+// formatLogRecord renders a record as "time LEVEL [component] msg key=value..."
+func formatLogRecord(fields map[string]interface{}) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v %-5v [%v] %v", fields["time"], strings.ToUpper(fmt.Sprint(fields["level"])), fields["component"], fields["message"])
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		switch k {
+		case "time", "level", "component", "message", "pid":
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, fields[k])
+	}
+	return b.String()
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/config"
+	"portunix.ai/app/metrics"
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Opt-in local usage metrics",
+	Long: `Portunix can record which commands run, how long they take and why they
+fail (failure category only, never arguments or output). Recording is off
+until enabled with 'portunix metrics enable'. Events are stored locally in
+~/.portunix/metrics and are only sent anywhere when 'portunix metrics export'
+is run with an endpoint configured:
+
+  portunix config set metrics.endpoint https://metrics.example.com/portunix
+
+Install failures are recorded per package, so 'portunix metrics show' lists
+the packages that fail most often.`,
+}
+
+var metricsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show recorded command usage and failures",
+	Run: func(cmd *cobra.Command, args []string) {
+		days, _ := cmd.Flags().GetInt("days")
+		top, _ := cmd.Flags().GetInt("top")
+		formatJSON, _ := cmd.Flags().GetBool("json")
+
+		events, err := metrics.Load()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if days > 0 {
+			events = metrics.Since(events, time.Now().AddDate(0, 0, -days))
+		}
+		summary := metrics.Summarize(events)
+
+		if formatJSON {
+			data, err := json.MarshalIndent(summary, "", "  ")
+			if err != nil {
+				fmt.Printf("Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+		printMetricsSummary(summary, top)
+	},
+}
+
+var metricsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether metrics are recorded and exported",
+	Run: func(cmd *cobra.Command, args []string) {
+		state := "disabled"
+		if metrics.Enabled() {
+			state = "enabled"
+		}
+		fmt.Printf("Recording: %s\n", state)
+		fmt.Printf("Store:     %s\n", metrics.StorePath())
+
+		events, _ := metrics.Load()
+		pending, _ := metrics.Pending()
+		fmt.Printf("Events:    %d (%d not exported)\n", len(events), len(pending))
+
+		endpoint := config.GetString(metrics.KeyEndpoint, "")
+		if endpoint == "" {
+			endpoint = "not configured"
+		}
+		fmt.Printf("Endpoint:  %s\n", endpoint)
+		if last := metrics.LastExport(); !last.IsZero() {
+			fmt.Printf("Exported:  %s\n", last.Format("2006-01-02 15:04"))
+		}
+	},
+}
+
+var metricsEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Start recording metrics locally",
+	Run: func(cmd *cobra.Command, args []string) {
+		setMetricsEnabled(true)
+		fmt.Println("✓ Metrics recording enabled")
+		fmt.Printf("  Events are stored in %s and never leave this machine\n", metrics.StorePath())
+		fmt.Println("  unless you run 'portunix metrics export'.")
+	},
+}
+
+var metricsDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stop recording metrics",
+	Run: func(cmd *cobra.Command, args []string) {
+		setMetricsEnabled(false)
+		fmt.Println("✓ Metrics recording disabled")
+		fmt.Println("  Run 'portunix metrics clear' to delete recorded events")
+	},
+}
+
+var metricsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Send events not yet exported to the configured endpoint",
+	Run: func(cmd *cobra.Command, args []string) {
+		endpoint, _ := cmd.Flags().GetString("endpoint")
+		if endpoint == "" {
+			endpoint = config.GetString(metrics.KeyEndpoint, "")
+		}
+
+		count, err := metrics.Export(endpoint)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if count == 0 {
+			fmt.Println("Nothing to export")
+			return
+		}
+		fmt.Printf("✓ Exported %d events to %s\n", count, endpoint)
+	},
+}
+
+var metricsClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete all recorded events",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := metrics.Clear(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✓ Recorded metrics deleted")
+	},
+}
+
+func setMetricsEnabled(enabled bool) {
+	if err := config.Set(config.ScopeUser, metrics.KeyEnabled, fmt.Sprint(enabled)); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printMetricsSummary(summary metrics.Summary, top int) {
+	if summary.Events == 0 {
+		fmt.Println("No metrics recorded.")
+		if !metrics.Enabled() {
+			fmt.Println("Recording is disabled; run 'portunix metrics enable' to opt in.")
+		}
+		return
+	}
+
+	fmt.Printf("📊 Portunix metrics: %d commands since %s\n", summary.Events, summary.Since.Format("2006-01-02"))
+
+	fmt.Println("\nCommands:")
+	fmt.Printf("  %-28s %6s %7s %10s\n", "COMMAND", "RUNS", "FAILED", "AVG TIME")
+	for i, c := range summary.Commands {
+		if top > 0 && i >= top {
+			break
+		}
+		fmt.Printf("  %-28s %6d %7d %10s\n", c.Command, c.Runs, c.Failures, metrics.FormatDuration(c.AvgDurationMs))
+	}
+
+	if len(summary.Packages) > 0 {
+		fmt.Println("\nInstall packages (most failures first):")
+		fmt.Printf("  %-24s %8s %7s  %s\n", "PACKAGE", "INSTALLS", "FAILED", "TOP CAUSE")
+		for i, p := range summary.Packages {
+			if top > 0 && i >= top {
+				break
+			}
+			fmt.Printf("  %-24s %8d %7d  %s\n", p.Package, p.Installs, p.Failures, topCause(p.Causes))
+		}
+	}
+
+	if len(summary.Categories) > 0 {
+		fmt.Println("\nFailure categories:")
+		for _, category := range sortedByCount(summary.Categories) {
+			fmt.Printf("  %-14s %d\n", category, summary.Categories[category])
+		}
+	}
+}
+
+// topCause returns the most frequent failure category as "network (3)"
+func topCause(causes map[string]int) string {
+	sorted := sortedByCount(causes)
+	if len(sorted) == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%d)", sorted[0], causes[sorted[0]])
+}
+
+// sortedByCount returns map keys ordered by descending count
+func sortedByCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func init() {
+	rootCmd.AddCommand(metricsCmd)
+	metricsCmd.AddCommand(metricsShowCmd)
+	metricsCmd.AddCommand(metricsStatusCmd)
+	metricsCmd.AddCommand(metricsEnableCmd)
+	metricsCmd.AddCommand(metricsDisableCmd)
+	metricsCmd.AddCommand(metricsExportCmd)
+	metricsCmd.AddCommand(metricsClearCmd)
+
+	metricsShowCmd.Flags().Int("days", 0, "Only include events of the last N days")
+	metricsShowCmd.Flags().Int("top", 10, "Rows per table (0 for all)")
+	metricsShowCmd.Flags().Bool("json", false, "Output the summary as JSON")
+	metricsExportCmd.Flags().String("endpoint", "", "Override the configured metrics.endpoint")
+}
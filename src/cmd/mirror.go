@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/config"
+	"portunix.ai/app/mirror"
+)
+
+var mirrorCmd = &cobra.Command{
+	Use:   "mirror",
+	Short: "Download mirrors and container registries per network profile",
+	Long: `Redirect the downloads of the install engine and the image pulls of
+run-in-container and container installs to internal mirrors, such as an
+Artifactory or Harbor instance. Mirrors are grouped in network profiles
+defined in mirrors.yaml next to the system or user config.yaml
+(/etc/portunix/mirrors.yaml, ~/.portunix/mirrors.yaml):
+
+  profiles:
+    office:
+      downloads:
+        - from: https://github.com/
+          to: https://artifactory.corp.example.com/artifactory/github/
+      registries:
+        docker.io: harbor.corp.example.com/dockerhub
+        ghcr.io: harbor.corp.example.com/ghcr
+    home: {}
+    airgapped:
+      strict: true
+      registries:
+        "*": registry.lab:5000/mirror
+
+Download rules replace the longest matching URL prefix. When a mirror
+keeps failing, downloads fail over to the other matching rules, shorter
+prefixes and rules listed later, and then to the original URL unless the
+profile is strict. Registry mirrors
+replace the registry of an image ("ubuntu:22.04" becomes
+harbor.corp.example.com/dockerhub/library/ubuntu:22.04); the "*" mirror
+keeps the source registry in the path. Strict profiles refuse downloads
+and images without a mirror.
+
+The active profile is the network.profile setting, or PORTUNIX_NETWORK_PROFILE:
+
+  portunix mirror use office`,
+}
+
+var mirrorShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the active network profile and its mirrors",
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		f, err := mirror.Load()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		name := mirror.ActiveName()
+		profile := f.Profiles[name]
+
+		if jsonOutput {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"profile": name,
+				"mirrors": profile,
+				"files":   mirror.Files(),
+			}, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		if name == "" {
+			fmt.Println("No network profile selected; downloads and images are not redirected")
+			if names := f.Names(); len(names) > 0 {
+				fmt.Printf("Defined profiles: %s\n", strings.Join(names, ", "))
+				fmt.Println("Select one with 'portunix mirror use <profile>'")
+			}
+			return
+		}
+		_, source, _ := config.Lookup(mirror.KeyProfile)
+		fmt.Printf("Network profile: %s (%s)\n", name, source)
+		if profile == nil {
+			fmt.Printf("⚠️  Profile %q is not defined in %s\n", name, strings.Join(mirror.Files(), " or "))
+			return
+		}
+		if profile.Description != "" {
+			fmt.Printf("Description:     %s\n", profile.Description)
+		}
+		if profile.Strict {
+			fmt.Println("Strict:          downloads and images without a mirror are refused")
+		}
+		if len(profile.Downloads) > 0 {
+			fmt.Println("\nDownloads:")
+			for _, rule := range profile.Downloads {
+				fmt.Printf("  %s\n    → %s\n", rule.From, rule.To)
+			}
+		}
+		if len(profile.Registries) > 0 {
+			fmt.Println("\nRegistries:")
+			registries := make([]string, 0, len(profile.Registries))
+			for registry := range profile.Registries {
+				registries = append(registries, registry)
+			}
+			sort.Strings(registries)
+			for _, registry := range registries {
+				fmt.Printf("  %-20s → %s\n", registry, profile.Registries[registry])
+			}
+		}
+		if len(profile.Downloads) == 0 && len(profile.Registries) == 0 {
+			fmt.Println("\nNo mirrors: downloads and images use their original locations")
+		}
+	},
+}
+
+var mirrorListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the defined network profiles",
+	Run: func(cmd *cobra.Command, args []string) {
+		f, err := mirror.Load()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		names := f.Names()
+		if len(names) == 0 {
+			fmt.Printf("No network profiles defined. Create %s\n", mirror.Files()[len(mirror.Files())-1])
+			return
+		}
+		active := mirror.ActiveName()
+		for _, name := range names {
+			marker := " "
+			if name == active {
+				marker = "*"
+			}
+			p := f.Profiles[name]
+			line := fmt.Sprintf("%s %-16s %d download rules, %d registries  %s", marker, name, len(p.Downloads), len(p.Registries), p.Description)
+			fmt.Println(strings.TrimRight(line, " "))
+		}
+	},
+}
+
+var mirrorUseCmd = &cobra.Command{
+	Use:   "use <profile>",
+	Short: "Select the network profile (\"none\" to disable mirrors)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if name == "none" {
+			if err := config.Unset(config.ScopeUser, mirror.KeyProfile); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("✓ Network profile cleared; mirrors disabled")
+			return
+		}
+
+		f, err := mirror.Load()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := config.Set(config.ScopeUser, mirror.KeyProfile, name); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Network profile set to %s\n", name)
+		if _, ok := f.Profiles[name]; !ok {
+			fmt.Printf("⚠️  Profile %q is not defined; downloads and images are not redirected\n", name)
+		}
+	},
+}
+
+var mirrorResolveCmd = &cobra.Command{
+	Use:   "resolve <url|image>...",
+	Short: "Show where downloads and image pulls are redirected to",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		failed := false
+		for _, arg := range args {
+			var resolved string
+			var err error
+			if strings.Contains(arg, "://") {
+				resolved, err = mirror.RewriteURL(arg)
+			} else {
+				resolved, err = mirror.RewriteImage(arg)
+			}
+			switch {
+			case err != nil:
+				fmt.Printf("❌ %v\n", err)
+				failed = true
+			case resolved == arg:
+				fmt.Printf("%s (not mirrored)\n", arg)
+			default:
+				fmt.Printf("%s\n  → %s\n", arg, resolved)
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mirrorCmd)
+	mirrorCmd.AddCommand(mirrorShowCmd)
+	mirrorCmd.AddCommand(mirrorListCmd)
+	mirrorCmd.AddCommand(mirrorUseCmd)
+	mirrorCmd.AddCommand(mirrorResolveCmd)
+
+	mirrorShowCmd.Flags().Bool("json", false, "Output in JSON format")
+}
@@ -298,8 +298,7 @@ Examples:
 
 		err := podman.ExecCommand(containerID, command)
 		if err != nil {
-			fmt.Printf("Error executing command: %v\n", err)
-			return
+			os.Exit(containerExecExitCode(err))
 		}
 	},
 }
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/config"
+	"portunix.ai/app/proxy"
+)
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Proxy and corporate network configuration",
+	Long: `Configure portunix for networks that require a proxy, including proxies that
+intercept TLS with their own CA certificate:
+
+  portunix config set proxy.https http://proxy.corp.example.com:3128
+  portunix config set proxy.no_proxy localhost,127.0.0.1,.corp.example.com
+  portunix proxy import-ca corp-root-ca.crt
+
+Instead of a fixed proxy, proxy.pac can point to a proxy auto-config file;
+its first PROXY entry is used (PAC scripts are not evaluated).
+
+The settings apply to all downloads of portunix and its helpers and are
+exported as HTTP_PROXY/HTTPS_PROXY/NO_PROXY to every tool portunix runs. The
+proxy CA is added to the system certificates for Go, curl, git, pip and
+Node.js (SSL_CERT_FILE, CURL_CA_BUNDLE, GIT_SSL_CAINFO, REQUESTS_CA_BUNDLE,
+PIP_CERT, NODE_EXTRA_CA_CERTS). Containers started by portunix get the proxy
+variables, and run-in-container configures apt, dnf and yum and installs
+the CA inside the container.
+
+Use 'portunix proxy sync' to also write the settings to pip and npm
+configuration for use outside portunix.`,
+}
+
+var proxyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective proxy configuration",
+	Run: func(cmd *cobra.Command, args []string) {
+		found := false
+		for _, key := range []string{proxy.KeyHTTP, proxy.KeyHTTPS, proxy.KeyNoProxy, proxy.KeyPAC, proxy.KeyCABundle} {
+			if value, source, ok := config.Lookup(key); ok && value != "" {
+				fmt.Printf("%-16s %s (%s)\n", key, value, source)
+				found = true
+			}
+		}
+		if !found {
+			fmt.Println("No proxy configured. Set one with 'portunix config set proxy.https <url>'")
+			return
+		}
+
+		s := proxy.Load()
+		if s.PAC != "" && !s.HasProxy() {
+			resolved, err := proxy.ResolvePAC(s.PAC)
+			switch {
+			case err != nil:
+				fmt.Printf("\n❌ PAC file could not be resolved: %v\n", err)
+			case resolved == "":
+				fmt.Println("\nPAC file resolves to DIRECT (no proxy)")
+			default:
+				fmt.Printf("\nPAC file resolves to %s\n", resolved)
+			}
+		}
+		if s.CABundle != "" {
+			if _, err := os.Stat(proxy.CombinedBundlePath()); err == nil {
+				fmt.Printf("\nCombined CA bundle: %s\n", proxy.CombinedBundlePath())
+			}
+		}
+	},
+}
+
+var proxyEnvCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Print shell commands exporting the proxy environment",
+	Long: `Prints the environment variables portunix sets for the tools it runs, so a
+shell can use the same settings:
+
+  eval "$(portunix proxy env)"                          # bash, zsh
+  portunix proxy env --shell powershell | Invoke-Expression`,
+	Run: func(cmd *cobra.Command, args []string) {
+		shell, _ := cmd.Flags().GetString("shell")
+
+		s, err := proxy.Apply()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		env := s.Environment()
+		names := make([]string, 0, len(env))
+		for name := range env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			switch shell {
+			case "powershell", "pwsh":
+				fmt.Printf("$env:%s = '%s'\n", name, strings.ReplaceAll(env[name], "'", "''"))
+			case "cmd":
+				fmt.Printf("set %s=%s\n", name, env[name])
+			default:
+				fmt.Printf("export %s='%s'\n", name, strings.ReplaceAll(env[name], "'", `'\''`))
+			}
+		}
+	},
+}
+
+var proxyTestCmd = &cobra.Command{
+	Use:   "test [url]",
+	Short: "Test an HTTPS request through the configured proxy",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		target := "https://github.com"
+		if len(args) == 1 {
+			target = args[0]
+		}
+		if via := os.Getenv("HTTPS_PROXY"); via != "" {
+			fmt.Printf("Proxy: %s\n", via)
+		} else {
+			fmt.Println("Proxy: none (direct connection)")
+		}
+
+		client := &http.Client{Timeout: 15 * time.Second}
+		start := time.Now()
+		resp, err := client.Head(target)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", target, err)
+			if strings.Contains(err.Error(), "certificate") {
+				fmt.Println("   The proxy may intercept TLS; import its CA with 'portunix proxy import-ca <file>'")
+			}
+			os.Exit(1)
+		}
+		resp.Body.Close()
+		fmt.Printf("✅ %s: %s in %v\n", target, resp.Status, time.Since(start).Round(time.Millisecond))
+	},
+}
+
+var proxyImportCACmd = &cobra.Command{
+	Use:   "import-ca <file>",
+	Short: "Trust the CA certificate of a TLS-intercepting proxy",
+	Long: `Adds the certificates of a PEM or DER file to ~/.portunix/proxy/proxy-ca.pem
+and sets proxy.ca_bundle to it. Import several files to trust several CAs.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		added, err := proxy.ImportCA(args[0])
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(added) == 0 {
+			fmt.Println("✓ Certificates already imported")
+			return
+		}
+		for _, subject := range added {
+			fmt.Printf("✓ Imported %s\n", subject)
+		}
+		fmt.Printf("  proxy.ca_bundle = %s\n", proxy.ImportedCAPath())
+	},
+}
+
+var proxySyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Write the proxy settings to pip and npm configuration",
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := proxy.Apply()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		files, err := proxy.SyncTools(s)
+		for _, file := range files {
+			fmt.Printf("✓ Updated %s\n", file)
+		}
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(proxyCmd)
+	proxyCmd.AddCommand(proxyShowCmd)
+	proxyCmd.AddCommand(proxyEnvCmd)
+	proxyCmd.AddCommand(proxyTestCmd)
+	proxyCmd.AddCommand(proxyImportCACmd)
+	proxyCmd.AddCommand(proxySyncCmd)
+
+	proxyEnvCmd.Flags().String("shell", "sh", "Shell syntax: sh, powershell or cmd")
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/repo"
+)
+
+var repoCmd = &cobra.Command{
+	Use:   "repo",
+	Short: "Bootstrap git repositories with standard hooks and editor settings",
+	Long: `Set up a git repository the way the team works: an .editorconfig, a
+pre-commit hook running lint rules on the staged files and scanning them for
+secrets with gitleaks, and a commit-msg hook checking the message format.
+
+The hooks read their configuration from .portunix/repo.yaml, which should be
+committed so every clone runs the same checks. Hooks that existed before are
+kept as <hook>.local and run first.
+
+Organizations provide templates in repo-templates/ next to the system or user
+config.yaml (/etc/portunix/repo-templates/acme.yaml,
+~/.portunix/repo-templates/acme.yaml). A template overrides the fields of
+the built-in default it sets:
+
+  name: acme
+  hooks:
+    commit-msg:
+      pattern: '^[A-Z]+-[0-9]+ .+'
+      hint: "Start with the ticket, e.g. 'OPS-42 Rotate certificates'"
+  files:
+    .gitleaks.toml: |
+      [extend]
+      useDefault = true
+
+The repo.template setting selects the template used without --template.`,
+}
+
+var repoInitCmd = &cobra.Command{
+	Use:   "init [dir]",
+	Short: "Install git hooks and .editorconfig (runs git init when needed)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		templateRef, _ := cmd.Flags().GetString("template")
+		force, _ := cmd.Flags().GetBool("force")
+		noHooks, _ := cmd.Flags().GetBool("no-hooks")
+		noEditorConfig, _ := cmd.Flags().GetBool("no-editorconfig")
+		noInstall, _ := cmd.Flags().GetBool("no-install")
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+
+		tmpl, err := repo.LoadTemplate(templateRef)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		result, err := repo.Init(dir, tmpl, repo.InitOptions{
+			NoHooks:        noHooks,
+			NoEditorConfig: noEditorConfig,
+			Force:          force,
+		})
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if result.Created {
+			fmt.Printf("✓ Initialized git repository in %s\n", result.Root)
+		}
+		for _, file := range result.Written {
+			fmt.Printf("✓ Wrote %s\n", file)
+		}
+		for _, file := range result.Skipped {
+			fmt.Printf("• Kept existing %s (--force to overwrite)\n", file)
+		}
+		if len(result.Hooks) > 0 {
+			fmt.Printf("✓ Installed hooks: %s (template %s)\n", strings.Join(result.Hooks, ", "), tmpl.Name)
+		}
+		for _, hook := range result.Chained {
+			fmt.Printf("• Existing %s hook kept as %s.local and run first\n", hook, hook)
+		}
+
+		if !noHooks && !noInstall && tmpl.Hooks.PreCommit != nil && tmpl.Hooks.PreCommit.SecretScan {
+			if err := ensureTool("gitleaks"); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+				fmt.Println("   Commits are not scanned for secrets until gitleaks is installed")
+			}
+		}
+	},
+}
+
+var repoHookCmd = &cobra.Command{
+	Use:    "hook <pre-commit|commit-msg> [args...]",
+	Short:  "Run a repository hook (called by the installed git hooks)",
+	Args:   cobra.MinimumNArgs(1),
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := repo.RunHook(args[0], args[1:], os.Stderr); err != nil {
+			if !errors.Is(err, repo.ErrCheckFailed) {
+				fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			}
+			os.Exit(1)
+		}
+	},
+}
+
+var repoTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List the available repository templates",
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		templates := repo.ListTemplates()
+		if jsonOutput {
+			data, _ := json.MarshalIndent(templates, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		for _, t := range templates {
+			source := "built-in"
+			if t.Path != "" {
+				source = t.Path
+			}
+			fmt.Printf("  %-16s %s\n  %-16s (%s)\n", t.Name, t.Description, "", source)
+		}
+		fmt.Printf("\nOrganization templates: %s\n", strings.Join(repo.TemplateDirs(), ", "))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(repoCmd)
+	repoCmd.AddCommand(repoInitCmd)
+	repoCmd.AddCommand(repoHookCmd)
+	repoCmd.AddCommand(repoTemplatesCmd)
+
+	repoInitCmd.Flags().String("template", "", "Template name, file or URL (default: repo.template setting)")
+	repoInitCmd.Flags().Bool("force", false, "Overwrite an existing .editorconfig and template files")
+	repoInitCmd.Flags().Bool("no-hooks", false, "Do not install git hooks")
+	repoInitCmd.Flags().Bool("no-editorconfig", false, "Do not write .editorconfig")
+	repoInitCmd.Flags().Bool("no-install", false, "Do not install gitleaks when it is missing")
+	repoTemplatesCmd.Flags().Bool("json", false, "Output in JSON format")
+}
@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
+	"portunix.ai/app/config"
+	"portunix.ai/app/metrics"
 	"portunix.ai/app/version"
+	"portunix.ai/portunix/pkg/logging"
 )
 
 var (
@@ -22,15 +26,36 @@ Use the --help flag with any command to see more information about it.`,
 	// Help level flags
 	helpExpert bool
 	helpAI     bool
+
+	// Logging flags
+	logVerbose bool
+	logQuiet   bool
+	logJSON    bool
 )
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	// --help-ai on built-in subcommands bypasses argument validation
+	if handleAIHelpRequest(os.Args[1:]) {
+		return
+	}
+
+	// Tab completion of helper commands, which cobra cannot see
+	if handleHelperCompletion(os.Args[1:], os.Stdout) {
+		return
+	}
+
+	tracker := metrics.Start(os.Args[1:], version.ProductVersion)
+	cmd, err := rootCmd.ExecuteC()
+	tracker.Finish("", err)
 	if err != nil {
+		// cobra already printed the error; only record it in the log file
+		logging.Capture(zerolog.ErrorLevel, "command failed", "command", cmd.CommandPath(), "args", os.Args[1:], "error", err)
+		logging.Close()
 		os.Exit(1)
 	}
+	logging.Close()
 }
 
 // SetVersion sets the version for the root command
@@ -51,6 +76,12 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&helpExpert, "help-expert", false, "Show extended help with all options and examples")
 	rootCmd.PersistentFlags().BoolVar(&helpAI, "help-ai", false, "Show machine-readable help in JSON format")
 
+	// Add logging flags (also recognized before helper commands, see main.go)
+	rootCmd.PersistentFlags().BoolVar(&logVerbose, "verbose", false, "Show debug output")
+	rootCmd.PersistentFlags().BoolVar(&logQuiet, "quiet", false, "Show errors only")
+	rootCmd.PersistentFlags().BoolVar(&logJSON, "log-json", false, "Write log output as JSON lines")
+	cobra.OnInitialize(initLogging)
+
 	// Override help function to support multiple levels
 	rootCmd.SetHelpFunc(multiLevelHelp)
 
@@ -58,6 +89,23 @@ func init() {
 	rootCmd.SetVersionTemplate("Portunix version {{.Version}}\n")
 }
 
+// initLogging applies logging flags once cobra has parsed them
+func initLogging() {
+	opts, _ := logging.ParseFlags(nil)
+	if logVerbose {
+		opts.Verbose, opts.Quiet = true, false
+	} else if logQuiet {
+		opts.Quiet, opts.Verbose = true, false
+	}
+	opts.JSON = opts.JSON || logJSON
+	if !config.GetBool("logging.file", true) {
+		opts.NoFile = true
+	}
+	logging.Init("portunix", opts)
+	logging.Export(opts)
+	logging.Debug("command started", "args", os.Args[1:])
+}
+
 // multiLevelHelp implements the multi-level help system
 func multiLevelHelp(cmd *cobra.Command, args []string) {
 	// Check if this is the root command
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/config"
+	"portunix.ai/app/envprofile"
+)
+
+// keyRunEnvProfile is the configuration key of the profiles 'portunix run'
+// applies when --env-profile is not given
+const keyRunEnvProfile = "run.env_profile"
+
+// runFailed is the exit code of 'portunix run' when the command could not
+// be started
+const runFailed = 127
+
+var runCmd = &cobra.Command{
+	Use:   "run --env-profile <name> -- <command> [args...]",
+	Short: "Run a command with managed environment profiles",
+	Long: `Run a command with environment variables composed from env profiles:
+proxy variables, registry credentials from the secret store, JAVA_HOME of a
+managed JDK and Python venv activation, so scripts and CI jobs get the same
+environment without sourcing setup files. Profiles are defined in
+env-profiles.yaml next to the system or user config.yaml
+(/etc/portunix/env-profiles.yaml, ~/.portunix/env-profiles.yaml), or in the
+file named by PORTUNIX_ENV_PROFILES_FILE:
+
+  profiles:
+    corp:
+      proxy: true                 # HTTP(S)_PROXY, NO_PROXY and CA bundle
+    ci:
+      include: [corp]             # profiles applied first
+      java: "21"                  # JAVA_HOME of 'portunix java install 21'
+      venv: tools                 # venv of 'portunix python venv create tools'
+      secrets:                    # variables from 'portunix secret'
+        NPM_TOKEN: registry/npm
+      env:
+        GRADLE_OPTS: "-Xmx2g"
+        PATH: "/opt/ci/bin:${PATH}"  # ${NAME} is the value composed so far
+      unset: [DEBUG]
+
+Several --env-profile flags are applied in order. Without the flag the
+profiles of the run.env_profile setting are used (comma separated). The exit
+code is the one of the command.`,
+	Example: `  portunix run --env-profile ci -- mvn -B verify
+  portunix run --env-profile corp --env-profile java21 -- ./gradlew build
+  portunix run --env-profile ci --print
+  portunix run --list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		list, _ := cmd.Flags().GetBool("list")
+		printOnly, _ := cmd.Flags().GetBool("print")
+		formatJSON, _ := cmd.Flags().GetBool("json")
+		names, _ := cmd.Flags().GetStringArray("env-profile")
+
+		f, err := envprofile.Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if list {
+			listEnvProfiles(f, formatJSON)
+			return
+		}
+
+		if len(names) == 0 {
+			for _, name := range strings.Split(config.GetString(keyRunEnvProfile, ""), ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					names = append(names, name)
+				}
+			}
+		}
+		if len(names) == 0 {
+			fmt.Fprintln(os.Stderr, "❌ Error: no env profile given (--env-profile <name>, or set run.env_profile)")
+			if defined := f.Names(); len(defined) > 0 {
+				fmt.Fprintf(os.Stderr, "   Defined profiles: %s\n", strings.Join(defined, ", "))
+			}
+			os.Exit(1)
+		}
+		if !printOnly && len(args) == 0 {
+			fmt.Fprintln(os.Stderr, "❌ Error: no command given (portunix run --env-profile <name> -- <command>)")
+			os.Exit(1)
+		}
+
+		env, err := f.Compose(names, os.Environ(), envprofile.DefaultSources())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if printOnly {
+			printEnvProfile(env, formatJSON)
+			return
+		}
+		os.Exit(runWithEnvironment(env, args))
+	},
+}
+
+// runWithEnvironment runs a command with a composed environment and returns
+// its exit code
+func runWithEnvironment(env *envprofile.Environment, args []string) int {
+	// The command is looked up in the PATH of the profiles, e.g. a venv
+	if path, ok := env.Lookup("PATH"); ok {
+		os.Setenv("PATH", path)
+	}
+	command := exec.Command(args[0], args[1:]...)
+	command.Env = env.Environ()
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	err := command.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.ExitCode()
+	}
+	fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+	return runFailed
+}
+
+// printEnvProfile prints the variables the profiles set, secrets masked
+func printEnvProfile(env *envprofile.Environment, formatJSON bool) {
+	vars := env.Variables()
+	for i := range vars {
+		if vars[i].Secret {
+			vars[i].Value = "********"
+		}
+	}
+	if formatJSON {
+		data, _ := json.MarshalIndent(map[string]interface{}{"set": vars, "unset": env.Unset()}, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	for _, v := range vars {
+		fmt.Printf("%s=%s  (%s)\n", v.Name, v.Value, v.Profile)
+	}
+	for _, name := range env.Unset() {
+		fmt.Printf("unset %s\n", name)
+	}
+}
+
+// listEnvProfiles prints the defined env profiles
+func listEnvProfiles(f *envprofile.File, formatJSON bool) {
+	if formatJSON {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"profiles": f.Profiles,
+			"files":    envprofile.Files(),
+		}, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	names := f.Names()
+	if len(names) == 0 {
+		fmt.Printf("No env profiles defined. Define them in %s\n", envprofile.Files()[len(envprofile.Files())-1])
+		return
+	}
+	for _, name := range names {
+		p := f.Profiles[name]
+		var parts []string
+		if len(p.Include) > 0 {
+			parts = append(parts, "include "+strings.Join(p.Include, ", "))
+		}
+		if p.Proxy {
+			parts = append(parts, "proxy")
+		}
+		if p.Java != "" {
+			parts = append(parts, "java "+p.Java)
+		}
+		if p.Venv != "" {
+			parts = append(parts, "venv "+p.Venv)
+		}
+		if n := len(p.Secrets); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d secrets", n))
+		}
+		if n := len(p.Env); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d variables", n))
+		}
+		fmt.Printf("%-16s %s\n", name, strings.Join(parts, "; "))
+		if p.Description != "" {
+			fmt.Printf("%-16s %s\n", "", p.Description)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	// Flags after the command belong to the command
+	runCmd.Flags().SetInterspersed(false)
+	runCmd.Flags().StringArray("env-profile", nil, "Env profile to apply (repeatable, applied in order)")
+	runCmd.Flags().Bool("print", false, "Print the composed variables instead of running a command (secrets masked)")
+	runCmd.Flags().Bool("list", false, "List the defined env profiles")
+	runCmd.Flags().Bool("json", false, "Output --print and --list as JSON")
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"portunix.ai/app/sandbox"
+
+	"github.com/spf13/cobra"
+)
+
+// sandboxRunCmd represents the sandbox run command
+var sandboxRunCmd = &cobra.Command{
+	Use:   "run <package> [-- install-args...]",
+	Short: "Tests a package installation in a fresh Windows Sandbox.",
+	Long: `Installs a package inside a disposable Windows Sandbox and reports whether the
+installation succeeded.
+
+The command generates a .wsb configuration that maps the portunix binary
+(read-only), the download cache and a results folder into the sandbox. At logon
+the sandbox runs 'portunix install <package>', optionally a verification
+command, and writes the exit codes and logs to the results folder. The host
+waits for the results, prints them and closes the sandbox.
+
+Sharing the download cache means installers are downloaded only once across
+repeated test runs.
+
+Examples:
+  portunix sandbox run nodejs
+  portunix sandbox run java --verify "java -version"
+  portunix sandbox run java -- --variant 17
+  portunix sandbox run vscode --timeout 45m --keep-open`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		verify, _ := cmd.Flags().GetString("verify")
+		noNetwork, _ := cmd.Flags().GetBool("no-network")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		keepOpen, _ := cmd.Flags().GetBool("keep-open")
+		keepFiles, _ := cmd.Flags().GetBool("keep-files")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if runtime.GOOS != "windows" {
+			fmt.Println("Error: Windows Sandbox is only available on Windows")
+			os.Exit(1)
+		}
+
+		running, err := sandbox.CheckSandboxRunning()
+		if err != nil {
+			fmt.Printf("Error checking for running sandbox: %v\n", err)
+			os.Exit(1)
+		}
+		if running {
+			// Windows allows only one sandbox at a time
+			shouldClose, err := sandbox.PromptToCloseSandbox()
+			if err != nil || !shouldClose {
+				fmt.Println("Aborting - Windows Sandbox is still running")
+				os.Exit(1)
+			}
+			if err := sandbox.CloseSandbox(); err != nil {
+				fmt.Printf("Error closing sandbox: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		plan, err := sandbox.PrepareRun(sandbox.RunOptions{
+			Package:    args[0],
+			Args:       args[1:],
+			Verify:     verify,
+			Networking: !noNetwork,
+		})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Testing installation of %s in Windows Sandbox (timeout %s)...\n", args[0], timeout)
+		fmt.Printf("Results folder: %s\n", plan.Options.WorkDir)
+		result, err := plan.Run(timeout, keepOpen)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !keepFiles && !keepOpen && result.Success {
+			os.RemoveAll(plan.Options.WorkDir)
+		}
+
+		if jsonOutput {
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			printSandboxRunResult(result)
+		}
+		if !result.Success {
+			os.Exit(1)
+		}
+	},
+}
+
+func printSandboxRunResult(result *sandbox.RunResult) {
+	fmt.Println("\n--- install log ---")
+	fmt.Println(strings.TrimSpace(result.InstallLog))
+	if result.VerifyExit != nil {
+		fmt.Println("\n--- verify log ---")
+		fmt.Println(strings.TrimSpace(result.VerifyLog))
+	}
+	fmt.Println()
+	fmt.Printf("Install exit code: %d\n", result.InstallExit)
+	if result.VerifyExit != nil {
+		fmt.Printf("Verify exit code:  %d\n", *result.VerifyExit)
+	}
+	if result.Duration > 0 {
+		fmt.Printf("Duration:          %s\n", result.Duration)
+	}
+	if result.Success {
+		fmt.Printf("✅ %s installed successfully in Windows Sandbox\n", result.Package)
+	} else {
+		fmt.Printf("❌ Installation test of %s failed; logs are in %s\n", result.Package, result.WorkDir)
+	}
+}
+
+func init() {
+	sandboxCmd.AddCommand(sandboxRunCmd)
+	sandboxRunCmd.Flags().String("verify", "", "Command run after the install to verify it, e.g. \"java -version\"")
+	sandboxRunCmd.Flags().Bool("no-network", false, "Disable networking in the sandbox (requires a populated cache)")
+	sandboxRunCmd.Flags().Duration("timeout", 30*time.Minute, "Maximum time to wait for the test")
+	sandboxRunCmd.Flags().Bool("keep-open", false, "Leave the sandbox running after the test")
+	sandboxRunCmd.Flags().Bool("keep-files", false, "Keep the results folder after a successful test")
+	sandboxRunCmd.Flags().Bool("json", false, "Output the result in JSON format")
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/sbom"
+)
+
+var sbomCmd = &cobra.Command{
+	Use:   "sbom",
+	Short: "Generate and compare SBOMs of containers, images and the system",
+	Long: `Generate software bills of materials (CycloneDX or SPDX JSON) with syft,
+which is installed through 'portunix install syft' when missing. SBOMs are
+stored per target in .portunix/sbom of the current project and every new SBOM
+is compared with the previous one of the same target, highlighting new
+dependencies.
+
+Targets:
+  container:<name>   image of a container in the selected runtime
+  image:<ref>        image in the selected runtime, e.g. image:myapp:latest
+  dir:<path>         directory, e.g. a build output
+  system             packages installed on this machine (Linux, macOS)
+
+  portunix sbom generate --target container:web
+  portunix sbom generate --target system --format spdx`,
+}
+
+var sbomGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate an SBOM and compare it with the previous one",
+	Run: func(cmd *cobra.Command, args []string) {
+		targetFlag, _ := cmd.Flags().GetString("target")
+		format, _ := cmd.Flags().GetString("format")
+		dir, _ := cmd.Flags().GetString("dir")
+		noDiff, _ := cmd.Flags().GetBool("no-diff")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		target, err := sbom.ParseTarget(targetFlag)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := ensureTool("syft"); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !jsonOutput {
+			fmt.Printf("🔍 Generating %s SBOM for %s...\n", format, target)
+		}
+		record, err := sbom.Generate(target, format, dir)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var diff *sbom.Diff
+		var previous *sbom.Record
+		if !noDiff {
+			if previous, err = sbom.Previous(dir, target, record); err == nil && previous != nil {
+				diff, err = sbom.CompareFiles(previous.Path, record.Path)
+			}
+			if err != nil {
+				fmt.Printf("⚠️  Cannot compare with the previous SBOM: %v\n", err)
+			}
+		}
+
+		if jsonOutput {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"sbom":     record,
+				"previous": previous,
+				"diff":     diff,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		components, _ := sbom.ReadComponents(record.Path)
+		fmt.Printf("✅ SBOM with %d components: %s\n", len(components), record.Path)
+		switch {
+		case noDiff:
+		case previous == nil:
+			fmt.Println("   First SBOM of this target; later runs are compared with it")
+		case diff != nil:
+			fmt.Printf("\nChanges since %s:\n", previous.Created.Format("2006-01-02 15:04"))
+			printSBOMDiff(diff)
+		}
+	},
+}
+
+var sbomListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored SBOMs",
+	Run: func(cmd *cobra.Command, args []string) {
+		targetFlag, _ := cmd.Flags().GetString("target")
+		dir, _ := cmd.Flags().GetString("dir")
+
+		if targetFlag == "" {
+			slugs, err := sbom.Targets(dir)
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			if len(slugs) == 0 {
+				fmt.Printf("No SBOMs in %s. Create one with 'portunix sbom generate --target <target>'\n", dir)
+				return
+			}
+			for _, slug := range slugs {
+				fmt.Println(slug)
+			}
+			return
+		}
+
+		target, err := sbom.ParseTarget(targetFlag)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		records, err := sbom.List(dir, target)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(records) == 0 {
+			fmt.Printf("No SBOMs of %s\n", target)
+			return
+		}
+		for _, r := range records {
+			fmt.Printf("%s  %-9s  %s\n", r.Created.Format("2006-01-02 15:04:05"), r.Format, r.Path)
+		}
+	},
+}
+
+var sbomDiffCmd = &cobra.Command{
+	Use:   "diff [old.json new.json]",
+	Short: "Compare two SBOMs, or the two latest of a target",
+	Args:  cobra.RangeArgs(0, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		targetFlag, _ := cmd.Flags().GetString("target")
+		dir, _ := cmd.Flags().GetString("dir")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		var oldPath, newPath string
+		switch {
+		case len(args) == 2:
+			oldPath, newPath = args[0], args[1]
+		case len(args) == 0 && targetFlag != "":
+			target, err := sbom.ParseTarget(targetFlag)
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			records, err := sbom.List(dir, target)
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			if len(records) < 2 {
+				fmt.Printf("❌ Error: %s has %d SBOMs; at least 2 are needed\n", target, len(records))
+				os.Exit(1)
+			}
+			oldPath, newPath = records[len(records)-2].Path, records[len(records)-1].Path
+		default:
+			fmt.Println("❌ Error: pass two SBOM files or --target")
+			os.Exit(1)
+		}
+
+		diff, err := sbom.CompareFiles(oldPath, newPath)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if jsonOutput {
+			data, _ := json.MarshalIndent(diff, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		fmt.Printf("%s → %s\n\n", oldPath, newPath)
+		printSBOMDiff(diff)
+	},
+}
+
+func printSBOMDiff(diff *sbom.Diff) {
+	if diff.Empty() {
+		fmt.Println("   No dependency changes")
+		return
+	}
+	if len(diff.Added) > 0 {
+		fmt.Printf("  🆕 New dependencies (%d):\n", len(diff.Added))
+		for _, c := range diff.Added {
+			fmt.Printf("     + %s %s\n", c.Name, c.Version)
+		}
+	}
+	if len(diff.Changed) > 0 {
+		fmt.Printf("  🔄 Changed versions (%d):\n", len(diff.Changed))
+		for _, c := range diff.Changed {
+			fmt.Printf("     ~ %s %s → %s\n", c.Name, c.From, c.To)
+		}
+	}
+	if len(diff.Removed) > 0 {
+		fmt.Printf("  ➖ Removed dependencies (%d):\n", len(diff.Removed))
+		for _, c := range diff.Removed {
+			fmt.Printf("     - %s %s\n", c.Name, c.Version)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(sbomCmd)
+	sbomCmd.AddCommand(sbomGenerateCmd)
+	sbomCmd.AddCommand(sbomListCmd)
+	sbomCmd.AddCommand(sbomDiffCmd)
+
+	sbomCmd.PersistentFlags().String("dir", sbom.DefaultDir, "Directory holding the SBOMs")
+	sbomGenerateCmd.Flags().String("target", "", "container:<name>, image:<ref>, dir:<path> or system")
+	sbomGenerateCmd.Flags().String("format", sbom.FormatCycloneDX, "SBOM format: cyclonedx or spdx")
+	sbomGenerateCmd.Flags().Bool("no-diff", false, "Do not compare with the previous SBOM")
+	sbomGenerateCmd.Flags().Bool("json", false, "Output in JSON format")
+	sbomGenerateCmd.MarkFlagRequired("target")
+	sbomListCmd.Flags().String("target", "", "List the SBOMs of this target")
+	sbomDiffCmd.Flags().String("target", "", "Compare the two latest SBOMs of this target")
+	sbomDiffCmd.Flags().Bool("json", false, "Output in JSON format")
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/schedule"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Run portunix commands periodically",
+	Long: `Run portunix commands periodically with the scheduler of the OS: the
+user's crontab on Linux and macOS, the Task Scheduler on Windows.
+
+Times are given as five-field cron expressions (minute hour day month
+weekday) or the @hourly, @daily, @weekly, @monthly and @yearly shortcuts.
+Task Scheduler has no cron, so on Windows only expressions it can express
+are accepted: */N minutes, a fixed minute every N hours, or a fixed time on
+every day, on weekdays or on days of the month.
+
+Each run appends the output of the command to a run log in
+~/.portunix/schedule/logs and records its exit status, shown by
+'portunix schedule list'.`,
+}
+
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add <command> --cron <expression>",
+	Short: "Schedule a portunix command",
+	Long: `Schedule a portunix command. The command runs in the current directory,
+or in --workdir; its name defaults to the first two words of the command.`,
+	Example: `  portunix schedule add "pft sync" --cron "0 * * * *"
+  portunix schedule add "vuln scan --path /srv/app" --cron "30 2 * * *" --name nightly-scan
+  portunix schedule add "cache prune" --cron @weekly`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		expr, _ := cmd.Flags().GetString("cron")
+		name, _ := cmd.Flags().GetString("name")
+		workDir, _ := cmd.Flags().GetString("workdir")
+
+		command := args
+		if len(args) == 1 {
+			var err error
+			if command, err = schedule.SplitCommand(args[0]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if name == "" {
+			name = schedule.DefaultName(command)
+		}
+		if workDir == "" {
+			workDir, _ = os.Getwd()
+		}
+		if abs, err := filepath.Abs(workDir); err == nil {
+			workDir = abs
+		}
+
+		scheduler, err := schedule.Detect()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		executable, err := os.Executable()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		s := &schedule.Schedule{Name: name, Command: command, Cron: expr, WorkDir: workDir}
+		if err := schedule.Add(s, scheduler, executable); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Scheduled %s with %s: '%s' at %q\n", s.Name, scheduler.Name(), s.CommandLine(), s.Cron)
+		if next := s.Next(time.Now()); !next.IsZero() {
+			fmt.Printf("  Next run: %s\n", next.Format("2006-01-02 15:04"))
+		}
+		fmt.Printf("  Run log:  %s\n", schedule.LogPath(s.Name))
+	},
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List scheduled commands",
+	Run: func(cmd *cobra.Command, args []string) {
+		schedules, err := schedule.Load()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		formatJSON, _ := cmd.Flags().GetBool("json")
+		if formatJSON {
+			if schedules == nil {
+				schedules = []*schedule.Schedule{}
+			}
+			data, err := json.MarshalIndent(schedules, "", "  ")
+			if err != nil {
+				fmt.Printf("Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		if len(schedules) == 0 {
+			fmt.Println("No scheduled commands")
+			return
+		}
+		now := time.Now()
+		fmt.Printf("%-20s %-16s %-17s %-24s %s\n", "NAME", "CRON", "NEXT RUN", "LAST RUN", "COMMAND")
+		for _, s := range schedules {
+			next := "-"
+			if t := s.Next(now); !t.IsZero() {
+				next = t.Format("2006-01-02 15:04")
+			}
+			last := "-"
+			if run := s.LastRun; run != nil {
+				status := fmt.Sprintf("exit %d", run.ExitCode)
+				if run.Error != "" {
+					status = "failed"
+				}
+				last = fmt.Sprintf("%s %s", run.Start.Local().Format("2006-01-02 15:04"), status)
+			}
+			fmt.Printf("%-20s %-16s %-17s %-24s %s\n", s.Name, s.Cron, next, last, s.CommandLine())
+		}
+	},
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a scheduled command and its run log",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		scheduler, err := schedule.Detect()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := schedule.Remove(args[0], scheduler); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Removed schedule %s\n", args[0])
+	},
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a scheduled command now",
+	Long: `Run a scheduled command now, appending its output to the run log. The
+scheduler of the OS runs schedules with this command; it exits with the
+exit code of the scheduled command.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		executable, err := os.Executable()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		run, err := schedule.Execute(args[0], executable)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !run.OK() {
+			fmt.Printf("❌ %s failed with exit code %d, see %s\n", args[0], run.ExitCode, schedule.LogPath(args[0]))
+			os.Exit(max(run.ExitCode, 1))
+		}
+		fmt.Printf("✅ %s finished in %s\n", args[0], run.End.Sub(run.Start).Round(time.Millisecond))
+	},
+}
+
+var scheduleLogsCmd = &cobra.Command{
+	Use:   "logs <name>",
+	Short: "Show the run log of a scheduled command",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		tail, _ := cmd.Flags().GetInt("tail")
+		if _, err := schedule.Get(args[0]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		data, err := os.ReadFile(schedule.LogPath(args[0]))
+		if os.IsNotExist(err) {
+			fmt.Printf("%s has not run yet\n", args[0])
+			return
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		lines := strings.SplitAfter(string(data), "\n")
+		if tail > 0 && len(lines) > tail {
+			lines = lines[len(lines)-tail:]
+		}
+		fmt.Print(strings.Join(lines, ""))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+	scheduleCmd.AddCommand(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleRemoveCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+	scheduleCmd.AddCommand(scheduleLogsCmd)
+
+	scheduleAddCmd.Flags().String("cron", "", "Cron expression, e.g. \"0 * * * *\" or @daily")
+	scheduleAddCmd.Flags().String("name", "", "Schedule name (default: derived from the command)")
+	scheduleAddCmd.Flags().String("workdir", "", "Working directory of the command (default: current directory)")
+	scheduleAddCmd.MarkFlagRequired("cron")
+
+	scheduleListCmd.Flags().Bool("json", false, "Output in JSON format")
+
+	scheduleLogsCmd.Flags().Int("tail", 0, "Show only the last N lines")
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/config"
+	"portunix.ai/app/secret"
+)
+
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Shared secret store for tokens, passwords and API keys",
+	Long: `Store the secrets that portunix subsystems consume in one place: pft
+feedback tool tokens, Ansible playbook secrets, package registry tokens and
+edge provider API keys. Values live in the OS keychain (macOS Keychain,
+GNOME Keyring/KWallet through secret-tool) or in an AES-256-GCM encrypted
+file in ~/.portunix/secrets. The secret.backend setting selects the store
+of new secrets (auto prefers the keychain).
+
+Names are namespaced by subsystem:
+
+  pft/<area>          pft API tokens (area voc, vos, vob, voe)
+  pft/smtp            pft SMTP password
+  edge/<provider>     edge provider API keys (hetzner, digitalocean)
+  registry/<name>     tokens of remote package registries
+  vuln/nvd-api-key    NVD API key of 'portunix vuln'
+  <any>               Ansible secrets referenced as {{ secret:portunix:<name> }}
+
+Configuration values of the form "secret:<name>" are resolved from the
+store. Every read, write and removal is recorded in an audit log.
+
+  portunix secret set edge/hetzner
+  portunix secret get edge/hetzner
+  portunix secret audit edge/hetzner`,
+}
+
+var secretSetCmd = &cobra.Command{
+	Use:   "set <name> [value]",
+	Short: "Store a secret (prompts for the value when omitted)",
+	Long: `Store a secret. Without a value argument the value is read from a
+hidden prompt, or from standard input when it is not a terminal, which
+keeps it out of the shell history:
+
+  portunix secret set registry/team
+  echo "$TOKEN" | portunix secret set registry/team`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		backend, _ := cmd.Flags().GetString("backend")
+		name := args[0]
+
+		var value string
+		if len(args) == 2 {
+			value = args[1]
+		} else {
+			var err error
+			value, err = secret.ReadValue(fmt.Sprintf("Value of %s: ", name))
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if value == "" {
+			fmt.Println("❌ Error: empty secret value")
+			os.Exit(1)
+		}
+		if err := secret.Set(name, value, backend, "cli"); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Secret %s stored\n", name)
+	},
+}
+
+var secretGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print a secret value",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		value, err := secret.Get(args[0], "cli")
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(value)
+	},
+}
+
+var secretListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored secrets (names and metadata only)",
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		list, err := secret.List()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if jsonOutput {
+			data, _ := json.MarshalIndent(list, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		if len(list) == 0 {
+			fmt.Println("No secrets stored. Add one with 'portunix secret set <name>'")
+			return
+		}
+		fmt.Printf("%-32s %-9s %-17s %s\n", "NAME", "BACKEND", "UPDATED", "SET BY")
+		for _, info := range list {
+			fmt.Printf("%-32s %-9s %-17s %s\n", info.Name, info.Backend, info.Updated.Format("2006-01-02 15:04"), info.SetBy)
+		}
+	},
+}
+
+var secretDeleteCmd = &cobra.Command{
+	Use:     "delete <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a secret",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := secret.Delete(args[0], "cli"); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Secret %s removed\n", args[0])
+	},
+}
+
+var secretAuditCmd = &cobra.Command{
+	Use:   "audit [name]",
+	Short: "Show the access log of all secrets or one secret",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		limit, _ := cmd.Flags().GetInt("limit")
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+		}
+
+		entries, err := secret.AuditLog(name, limit)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if jsonOutput {
+			data, _ := json.MarshalIndent(entries, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		if len(entries) == 0 {
+			fmt.Println("No secret accesses recorded")
+			return
+		}
+		fmt.Printf("%-19s %-6s %-28s %-9s %-12s %s\n", "TIME", "ACTION", "NAME", "CONSUMER", "USER", "RESULT")
+		for _, e := range entries {
+			result := "ok"
+			if !e.OK {
+				result = "failed: " + e.Error
+			}
+			fmt.Printf("%-19s %-6s %-28s %-9s %-12s %s\n",
+				e.Time.Format("2006-01-02 15:04:05"), e.Action, e.Name, e.Consumer, e.User, result)
+		}
+	},
+}
+
+var secretBackendsCmd = &cobra.Command{
+	Use:   "backends",
+	Short: "Show the secret backends available on this system",
+	Run: func(cmd *cobra.Command, args []string) {
+		available, err := secret.Backends()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		names := make([]string, 0, len(available))
+		for name := range available {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			status := "✓ available"
+			if !available[name] {
+				status = "✗ not available"
+			}
+			fmt.Printf("  %-9s %s\n", name, status)
+		}
+		configured, source, _ := config.Lookup(secret.KeyBackend)
+		if configured == "" {
+			configured, source = secret.BackendAuto, "default"
+		}
+		fmt.Printf("\nNew secrets: %s (%s)\n", configured, source)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(secretCmd)
+	secretCmd.AddCommand(secretSetCmd)
+	secretCmd.AddCommand(secretGetCmd)
+	secretCmd.AddCommand(secretListCmd)
+	secretCmd.AddCommand(secretDeleteCmd)
+	secretCmd.AddCommand(secretAuditCmd)
+	secretCmd.AddCommand(secretBackendsCmd)
+
+	secretSetCmd.Flags().String("backend", "", "Store in this backend (keychain, file); default: secret.backend")
+	secretListCmd.Flags().Bool("json", false, "Output in JSON format")
+	secretAuditCmd.Flags().Bool("json", false, "Output in JSON format")
+	secretAuditCmd.Flags().Int("limit", 50, "Show at most this many recent entries (0 for all)")
+}
@@ -14,18 +14,36 @@ import (
 	"github.com/spf13/cobra"
 	"portunix.ai/app/plugins/manager"
 	"portunix.ai/app/service"
+	"portunix.ai/app/svcmgr"
 )
 
 var serviceCmd = &cobra.Command{
 	Use:   "service",
 	Short: "Service orchestration commands",
-	Long: `Manage gRPC plugin service instances.
+	Long: `Manage gRPC plugin service instances and the background services of
+portunix.
 
 The service command group provides lifecycle management for gRPC plugin
 processes. Clients (VSCode extensions, CLI scripts) can start, discover,
 and stop plugin services without knowing internal details.
 
+The background components of portunix are installed as user services of
+the OS service manager (systemd user units, launchd agents, Task Scheduler
+logon tasks), so they start at login and restart when they fail:
+
+  daemon       gRPC daemon for IDE plugins and the MCP server
+  vuln-watch   vulnerability watcher
+  pft-sync     PFT project synchronization, hourly by default
+  edge         edge reverse proxy, VPN and security services
+
+start and stop act on the installed service for these names and on plugin
+instances otherwise.
+
 Examples:
+  portunix service install daemon --now               # Start the daemon at login
+  portunix service install pft-sync --interval 30m    # Sync this PFT project
+  portunix service status                             # Background service states
+  portunix service stop vuln-watch                    # Stop a background service
   portunix service start reco                          # Start shared instance
   portunix service start reco --mode exclusive         # Start dedicated instance
   portunix service list                                # Show running instances
@@ -36,9 +54,11 @@ Examples:
 }
 
 var serviceStartCmd = &cobra.Command{
-	Use:   "start <plugin>",
-	Short: "Start a plugin service instance",
-	Long: `Start a gRPC plugin service instance or join an existing one.
+	Use:   "start <plugin|component>",
+	Short: "Start a plugin service instance or a background service",
+	Long: `Start a gRPC plugin service instance or join an existing one. For a
+background component (daemon, vuln-watch, pft-sync, edge) start its
+installed service.
 
 Allocation modes:
   shared (default)    Join existing shared instance or start new one
@@ -47,6 +67,9 @@ Allocation modes:
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		pluginName := args[0]
+		if _, ok := svcmgr.Lookup(pluginName); ok {
+			return systemServiceStart(pluginName)
+		}
 		modeStr, _ := cmd.Flags().GetString("mode")
 		outputFormat, _ := cmd.Flags().GetString("output")
 
@@ -90,12 +113,18 @@ var serviceReleaseCmd = &cobra.Command{
 }
 
 var serviceStopCmd = &cobra.Command{
-	Use:   "stop [plugin]",
-	Short: "Force stop service instances",
+	Use:   "stop [plugin|component]",
+	Short: "Force stop service instances or stop a background service",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		force, _ := cmd.Flags().GetBool("force")
 		all, _ := cmd.Flags().GetBool("all")
 
+		if len(args) == 1 && !all {
+			if _, ok := svcmgr.Lookup(args[0]); ok {
+				return systemServiceStop(args[0])
+			}
+		}
+
 		if !force {
 			return fmt.Errorf("--force flag is required for stop command")
 		}
@@ -0,0 +1,203 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/svcmgr"
+)
+
+var serviceInstallCmd = &cobra.Command{
+	Use:   "install <component>",
+	Short: "Install a background component as a user service",
+	Long: `Install a background component of portunix as a user service of the OS
+service manager: a systemd user unit (with a timer for periodic components)
+on Linux, a launchd agent on macOS or a Task Scheduler logon task on
+Windows. The service starts at the next login; --now starts it right away.
+
+Components: ` + strings.Join(svcmgr.Names(), ", ") + `
+
+pft-sync runs 'portunix pft sync' in the current directory, which has to
+be a PFT project, every --interval (default 1h).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, ok := svcmgr.Lookup(args[0])
+		if !ok {
+			return fmt.Errorf("unknown component %q (available: %s)", args[0], strings.Join(svcmgr.Names(), ", "))
+		}
+		intervalFlag, _ := cmd.Flags().GetString("interval")
+		now, _ := cmd.Flags().GetBool("now")
+
+		if intervalFlag != "" {
+			if s.Interval == 0 {
+				return fmt.Errorf("%s runs continuously; --interval applies to periodic components", s.Name)
+			}
+			interval, err := time.ParseDuration(intervalFlag)
+			if err != nil || interval < time.Minute {
+				return fmt.Errorf("invalid interval %q (at least 1m, e.g. 30m)", intervalFlag)
+			}
+			s.Interval = interval
+		}
+		if s.Name == "pft-sync" {
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			s.WorkDir = wd
+		}
+
+		mgr, err := svcmgr.Detect()
+		if err != nil {
+			return err
+		}
+		self, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to locate portunix executable: %w", err)
+		}
+		if resolved, err := filepath.EvalSymlinks(self); err == nil {
+			self = resolved
+		}
+		if err := mgr.Install(s, self); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Installed %s as %s %s (%s)\n", s.Name, mgr.Name(), mgr.Unit(s.Name), s.Kind())
+		if s.WorkDir != "" {
+			fmt.Printf("   Directory: %s\n", s.WorkDir)
+		}
+		if !now {
+			fmt.Printf("   Starts at the next login, or now with 'portunix service start %s'\n", s.Name)
+			return nil
+		}
+		return systemServiceStart(s.Name)
+	},
+}
+
+var serviceUninstallCmd = &cobra.Command{
+	Use:   "uninstall <component>",
+	Short: "Stop and remove a background service",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, ok := svcmgr.Lookup(args[0]); !ok {
+			return fmt.Errorf("unknown component %q (available: %s)", args[0], strings.Join(svcmgr.Names(), ", "))
+		}
+		mgr, err := svcmgr.Detect()
+		if err != nil {
+			return err
+		}
+		if err := mgr.Uninstall(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Removed service %s\n", args[0])
+		return nil
+	},
+}
+
+var serviceStatusCmd = &cobra.Command{
+	Use:   "status [component]",
+	Short: "Show the state of background services",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		formatJSON, _ := cmd.Flags().GetBool("json")
+		names := svcmgr.Names()
+		if len(args) == 1 {
+			if _, ok := svcmgr.Lookup(args[0]); !ok {
+				return fmt.Errorf("unknown component %q (available: %s)", args[0], strings.Join(names, ", "))
+			}
+			names = args
+		}
+		mgr, err := svcmgr.Detect()
+		if err != nil {
+			return err
+		}
+
+		var statuses []*svcmgr.Status
+		for _, name := range names {
+			st, err := mgr.Status(name)
+			if err != nil {
+				return err
+			}
+			statuses = append(statuses, st)
+		}
+		if formatJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(statuses)
+		}
+
+		fmt.Printf("%-12s %-10s %-14s %s\n", "COMPONENT", "INSTALLED", "STATE", "UNIT")
+		for _, st := range statuses {
+			installed, state := "no", "-"
+			if st.Installed {
+				installed, state = "yes", st.State
+			}
+			fmt.Printf("%-12s %-10s %-14s %s\n", st.Name, installed, state, st.Unit)
+		}
+		fmt.Printf("\nService manager: %s\n", mgr.Name())
+		if mgr.Name() != "systemd" {
+			fmt.Printf("Logs: %s\n", filepath.Dir(svcmgr.LogPath("")))
+		}
+		return nil
+	},
+}
+
+// systemServiceStart starts the installed service of a component
+func systemServiceStart(name string) error {
+	mgr, err := svcmgr.Detect()
+	if err != nil {
+		return err
+	}
+	if err := mgr.Start(name); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Started service %s\n", name)
+	return nil
+}
+
+// systemServiceStop stops the installed service of a component until the
+// next login
+func systemServiceStop(name string) error {
+	mgr, err := svcmgr.Detect()
+	if err != nil {
+		return err
+	}
+	if err := mgr.Stop(name); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Stopped service %s\n", name)
+	return nil
+}
+
+// stopInstalledService stops the service of a component when it is
+// installed and running, so that the service manager does not restart the
+// stopped process; it reports whether it did
+func stopInstalledService(name string) (bool, error) {
+	mgr, err := svcmgr.Detect()
+	if err != nil {
+		return false, nil
+	}
+	st, err := mgr.Status(name)
+	if err != nil || !st.Installed || !st.Running {
+		return false, nil
+	}
+	return true, mgr.Stop(name)
+}
+
+func init() {
+	serviceCmd.AddCommand(serviceInstallCmd)
+	serviceCmd.AddCommand(serviceUninstallCmd)
+	serviceCmd.AddCommand(serviceStatusCmd)
+
+	serviceInstallCmd.Flags().String("interval", "", "Run interval of periodic components, e.g. 30m (default 1h)")
+	serviceInstallCmd.Flags().Bool("now", false, "Start the service right away")
+
+	serviceStatusCmd.Flags().Bool("json", false, "Output in JSON format")
+}
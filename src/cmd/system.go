@@ -33,6 +33,9 @@ var systemInfoCmd = &cobra.Command{
 - Architecture
 - Hostname
 - PowerShell/Shell availability
+- Hardware: CPU, memory, disks and GPUs with driver versions
+- Virtualization capability (VT-x/AMD-V, nested virtualization)
+- Container readiness assessment
 
 Output can be formatted as JSON for programmatic use.
 
@@ -87,6 +90,9 @@ Performance profiling flags:
 			fmt.Printf("Error getting system information: %v\n", err)
 			os.Exit(1)
 		}
+		if !formatShort {
+			system.CollectHardware(sysInfo)
+		}
 
 		duration := time.Since(startTime)
 
@@ -135,6 +141,9 @@ Available conditions:
   windows         - Check if running on Windows
   linux          - Check if running on Linux  
   macos          - Check if running on macOS
+  apple-silicon  - Check if running on an Apple Silicon Mac
+  rosetta        - Check if running under Rosetta 2 translation
+  homebrew       - Check if Homebrew is installed (macOS)
   sandbox        - Check if running in Windows Sandbox
   docker         - Check if running in Docker
   wsl            - Check if running in WSL
@@ -212,6 +221,7 @@ func printDispatcherInfo(disp *dispatcher.Dispatcher, version string) {
 	fmt.Printf("======================\n")
 	fmt.Printf("Dispatcher Version: %s\n", version)
 	fmt.Printf("Executable Dir:     %s\n", disp.GetExecutableDir())
+	fmt.Printf("Route Manifest:     %s\n", disp.GetRouteManifestPath())
 	fmt.Printf("\n")
 
 	// Discover helper binaries
@@ -373,14 +383,39 @@ func printSystemInfo(info *system.SystemInfo) {
 		fmt.Printf("Kernel:       %s\n", info.LinuxInfo.KernelVersion)
 	}
 
+	if info.MacOSInfo != nil {
+		fmt.Printf("\nmacOS Details:\n")
+		fmt.Printf("Product:      %s\n", info.MacOSInfo.ProductName)
+		fmt.Printf("Kernel:       %s\n", info.MacOSInfo.KernelVersion)
+		fmt.Printf("Chip:         %s\n", info.MacOSInfo.Chip)
+		fmt.Printf("Apple Silicon: %t\n", info.MacOSInfo.AppleSilicon)
+		if info.MacOSInfo.AppleSilicon {
+			fmt.Printf("Rosetta:      %t (running translated: %t)\n", info.MacOSInfo.RosettaInstalled, info.MacOSInfo.Rosetta)
+		}
+		if info.MacOSInfo.HomebrewPrefix != "" {
+			fmt.Printf("Homebrew:     %s\n", info.MacOSInfo.HomebrewPrefix)
+		} else {
+			fmt.Printf("Homebrew:     not installed\n")
+		}
+	}
+
+	if hw := info.Hardware; hw != nil {
+		printHardwareInfo(hw)
+	}
+
 	fmt.Printf("\nCapabilities:\n")
 	fmt.Printf("PowerShell:   %t\n", info.Capabilities.PowerShell)
 	fmt.Printf("Admin:        %t\n", info.Capabilities.Admin)
 	fmt.Printf("Container Available: %t\n", info.Capabilities.ContainerAvailable)
 
 	// Add hardware virtualization capability
-	if info.Capabilities.VirtualizationInfo != nil {
-		fmt.Printf("Virtualization: %t\n", info.Capabilities.VirtualizationInfo.HardwareVirtualization)
+	if virtInfo := info.Capabilities.VirtualizationInfo; virtInfo != nil {
+		virtualization := fmt.Sprintf("%t", virtInfo.HardwareVirtualization)
+		if virtInfo.CPUExtension != "" {
+			virtualization += " (" + virtInfo.CPUExtension + ")"
+		}
+		fmt.Printf("Virtualization: %s\n", virtualization)
+		fmt.Printf("Nested Virtualization: %t\n", virtInfo.NestedVirtualization)
 	}
 
 	fmt.Printf("\nContainer Runtimes:\n")
@@ -412,6 +447,28 @@ func printSystemInfo(info *system.SystemInfo) {
 		}
 	}
 
+	if readiness := info.Capabilities.ContainerReadiness; readiness != nil {
+		fmt.Printf("\nContainer Readiness:\n")
+		if readiness.Ready {
+			fmt.Printf("Ready:        yes (%s)\n", readiness.Runtime)
+		} else {
+			fmt.Printf("Ready:        no\n")
+		}
+		for _, check := range readiness.Checks {
+			mark := "✅"
+			if !check.Passed && check.Required {
+				mark = "❌"
+			} else if !check.Passed {
+				mark = "⚠️ "
+			}
+			line := fmt.Sprintf("  %s %s", mark, check.Name)
+			if check.Detail != "" {
+				line += " - " + check.Detail
+			}
+			fmt.Println(line)
+		}
+	}
+
 	// Virtualization backends
 	if info.Capabilities.VirtualizationInfo != nil {
 		fmt.Printf("\nVirtualization Backends:\n")
@@ -440,6 +497,34 @@ func printSystemInfo(info *system.SystemInfo) {
 	}
 }
 
+func printHardwareInfo(hw *system.HardwareInfo) {
+	fmt.Printf("\nHardware:\n")
+	cpu := hw.CPU.Model
+	if cpu == "" {
+		cpu = "unknown"
+	}
+	fmt.Printf("CPU:          %s (%d cores, %d threads)\n", cpu, hw.CPU.Cores, hw.CPU.Threads)
+	if hw.Memory.TotalBytes > 0 {
+		fmt.Printf("Memory:       %s (%s available)\n", system.FormatBytes(hw.Memory.TotalBytes), system.FormatBytes(hw.Memory.AvailableBytes))
+	}
+	for _, disk := range hw.Disks {
+		fmt.Printf("Disk:         %s %s free of %s\n", disk.Mount, system.FormatBytes(disk.FreeBytes), system.FormatBytes(disk.TotalBytes))
+	}
+	if len(hw.GPUs) == 0 {
+		fmt.Printf("GPU:          none detected\n")
+	}
+	for _, gpu := range hw.GPUs {
+		line := gpu.Vendor + " " + gpu.Model
+		if gpu.Vendor == "" || strings.HasPrefix(gpu.Model, gpu.Vendor) {
+			line = gpu.Model
+		}
+		if gpu.Driver != "" || gpu.DriverVersion != "" {
+			line += " (driver " + strings.TrimSpace(gpu.Driver+" "+gpu.DriverVersion) + ")"
+		}
+		fmt.Printf("GPU:          %s\n", line)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(systemCmd)
 	systemCmd.AddCommand(systemInfoCmd)
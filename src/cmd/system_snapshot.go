@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/snapshot"
+)
+
+var systemSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and compare snapshots of the environment",
+	Long: `Records the installed packages with their versions, environment variables
+and system services, so the state before and after installing a profile or
+running a ptxbook can be compared:
+
+  portunix system snapshot save before
+  portunix install profile go-backend
+  portunix system snapshot diff before
+
+Packages come from the portunix install database and the system package
+databases (dpkg, rpm, pacman, Homebrew, Windows uninstall entries); services
+from systemd, launchd or the Windows service manager. Values of variables
+that look like secrets are stored as a hash only. Snapshots are kept in
+~/.portunix/snapshots.`,
+}
+
+var systemSnapshotSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Capture the current environment",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+
+		s, err := snapshot.Capture(args[0])
+		if err == nil {
+			err = s.Save(force)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Snapshot %s saved: %d packages, %d environment variables, %d services\n",
+			s.Name, len(s.Packages), len(s.Env), len(s.Services))
+	},
+}
+
+var systemSnapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved snapshots",
+	Run: func(cmd *cobra.Command, args []string) {
+		snapshots, err := snapshot.List()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(snapshots) == 0 {
+			fmt.Println("No snapshots saved. Create one with 'portunix system snapshot save <name>'")
+			return
+		}
+		fmt.Printf("%-24s %-17s %9s %6s %9s\n", "NAME", "CREATED", "PACKAGES", "ENV", "SERVICES")
+		for _, s := range snapshots {
+			fmt.Printf("%-24s %-17s %9d %6d %9d\n", s.Name, s.CreatedAt.Format("2006-01-02 15:04"),
+				len(s.Packages), len(s.Env), len(s.Services))
+		}
+	},
+}
+
+var systemSnapshotShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show the contents of a snapshot",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		formatJSON, _ := cmd.Flags().GetBool("json")
+
+		s, err := snapshot.Load(args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if formatJSON {
+			printSnapshotJSON(s)
+			return
+		}
+		fmt.Printf("Snapshot: %s\n", s.Name)
+		fmt.Printf("Created:  %s\n", s.CreatedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Host:     %s (%s)\n", s.Hostname, s.OS)
+		fmt.Printf("\nPackages (%d):\n", len(s.Packages))
+		for _, p := range s.Packages {
+			fmt.Printf("  %-10s %-40s %s\n", p.Source, p.Name, p.Version)
+		}
+		fmt.Printf("\nServices (%d):\n", len(s.Services))
+		for _, svc := range s.Services {
+			fmt.Printf("  %-50s %s\n", svc.Name, svc.State)
+		}
+		fmt.Printf("\nEnvironment variables: %d (use --json to list them)\n", len(s.Env))
+	},
+}
+
+var systemSnapshotDiffCmd = &cobra.Command{
+	Use:   "diff <a> [b]",
+	Short: "Compare two snapshots, or a snapshot with the current environment",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		formatJSON, _ := cmd.Flags().GetBool("json")
+
+		from, err := snapshot.Load(args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		var to *snapshot.Snapshot
+		if len(args) == 2 {
+			to, err = snapshot.Load(args[1])
+		} else {
+			to, err = snapshot.Capture("current")
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		diff := snapshot.Compare(from, to)
+		if formatJSON {
+			printSnapshotJSON(diff)
+			return
+		}
+		printSnapshotDiff(diff)
+	},
+}
+
+var systemSnapshotDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a saved snapshot",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := snapshot.Delete(args[0]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Snapshot %s deleted\n", args[0])
+	},
+}
+
+func printSnapshotJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Printf("Error formatting JSON: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
+}
+
+func printSnapshotDiff(diff *snapshot.Diff) {
+	fmt.Printf("Changes from %s to %s:\n", diff.From, diff.To)
+	if diff.Empty() {
+		fmt.Println("\nNo changes")
+		return
+	}
+	printSnapshotChanges("Packages", &diff.Packages)
+	printSnapshotChanges("Environment variables", &diff.Env)
+	printSnapshotChanges("Services", &diff.Services)
+}
+
+func printSnapshotChanges(title string, changes *snapshot.ChangeSet) {
+	if changes.Empty() {
+		return
+	}
+	fmt.Printf("\n%s (%d):\n", title, changes.Count())
+	for _, c := range changes.Added {
+		fmt.Printf("  + %s %s\n", c.Name, c.New)
+	}
+	for _, c := range changes.Removed {
+		fmt.Printf("  - %s %s\n", c.Name, c.Old)
+	}
+	for _, c := range changes.Changed {
+		fmt.Printf("  ~ %s: %s → %s\n", c.Name, c.Old, c.New)
+	}
+}
+
+func init() {
+	systemCmd.AddCommand(systemSnapshotCmd)
+	systemSnapshotCmd.AddCommand(systemSnapshotSaveCmd)
+	systemSnapshotCmd.AddCommand(systemSnapshotListCmd)
+	systemSnapshotCmd.AddCommand(systemSnapshotShowCmd)
+	systemSnapshotCmd.AddCommand(systemSnapshotDiffCmd)
+	systemSnapshotCmd.AddCommand(systemSnapshotDeleteCmd)
+
+	systemSnapshotSaveCmd.Flags().Bool("force", false, "Replace an existing snapshot of the same name")
+	systemSnapshotShowCmd.Flags().Bool("json", false, "Output as JSON")
+	systemSnapshotDiffCmd.Flags().Bool("json", false, "Output as JSON")
+}
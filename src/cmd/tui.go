@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/container"
+	"portunix.ai/app/tui"
+)
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Interactive terminal UI for containers, packages, PFT items and playbooks",
+	Long: `Start a full-screen terminal UI for everyday tasks without memorizing
+subcommands. Each tab lists resources and offers actions on the selected
+row; actions run the regular portunix command and return to the UI.
+
+Tabs:
+  1 Containers   s start, x stop, l logs, enter inspect
+  2 Packages     i install, enter info
+  3 PFT          y sync, enter show
+  4 Playbooks    r run, d dry run, v validate (*.ptxbook in the current directory)
+
+Keys:
+  ←/→, Tab, 1-4   switch tab
+  ↑/↓, j/k        move selection
+  R               refresh the current tab
+  q, Esc          quit`,
+	Example: `  portunix tui`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		self, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to locate portunix executable: %w", err)
+		}
+
+		model := tui.NewModel("Portunix",
+			containersTab(),
+			packagesTab(self),
+			pftTab(self),
+			playbooksTab(),
+		)
+		return tui.NewProgram(model, func(args []string) error {
+			c := exec.Command(self, args...)
+			c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+			return c.Run()
+		}).Run()
+	},
+}
+
+// containersTab lists containers of the preferred runtime
+func containersTab() *tui.Tab {
+	return &tui.Tab{
+		Title:   "Containers",
+		Columns: []string{"NAME", "IMAGE", "STATE", "STATUS"},
+		Empty:   "No containers found",
+		Load: func() ([]tui.Row, error) {
+			runtime := container.GetPreferredRuntime()
+			if runtime == container.RuntimeNone {
+				return nil, fmt.Errorf("no container runtime available (portunix install podman)")
+			}
+			output, err := exec.Command(string(runtime), "ps", "-a", "--format", "{{json .}}").Output()
+			if err != nil {
+				return nil, fmt.Errorf("%s ps failed: %w", runtime, err)
+			}
+			return parseContainerRows(output)
+		},
+		Actions: []tui.Action{
+			{Key: "s", Label: "start", Command: func(r tui.Row) []string { return []string{"container", "start", r.ID} }},
+			{Key: "x", Label: "stop", Confirm: true, Command: func(r tui.Row) []string { return []string{"container", "stop", r.ID} }},
+			{Key: "l", Label: "logs", Command: func(r tui.Row) []string { return []string{"container", "logs", r.ID} }},
+			{Key: tui.KeyEnter, Label: "inspect", Command: func(r tui.Row) []string { return []string{"container", "inspect", r.ID} }},
+		},
+	}
+}
+
+// parseContainerRows converts runtime "ps" output to table rows
+func parseContainerRows(output []byte) ([]tui.Row, error) {
+	containers, err := parseContainers(output)
+	if err != nil {
+		return nil, err
+	}
+	var rows []tui.Row
+	for _, c := range containers {
+		rows = append(rows, tui.Row{
+			ID:      c.Name,
+			Columns: []string{c.Name, c.Image, c.State, c.Status},
+		})
+	}
+	return rows, nil
+}
+
+// jsonString renders a decoded JSON value, joining arrays with commas
+func jsonString(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case []interface{}:
+		var parts []string
+		for _, item := range value {
+			parts = append(parts, jsonString(item))
+		}
+		return strings.Join(parts, ",")
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// packagesTab lists packages of the install registry
+func packagesTab(self string) *tui.Tab {
+	return &tui.Tab{
+		Title:   "Packages",
+		Columns: []string{"NAME", "CATEGORY", "DESCRIPTION"},
+		Empty:   "No packages found in registry",
+		Load: func() ([]tui.Row, error) {
+			output, err := exec.Command(self, "package", "list", "--format", "json").Output()
+			if err != nil {
+				return nil, fmt.Errorf("package list failed: %w", err)
+			}
+			var packages []struct {
+				Name        string `json:"name"`
+				Description string `json:"description"`
+				Category    string `json:"category"`
+			}
+			if err := json.Unmarshal(output, &packages); err != nil {
+				return nil, fmt.Errorf("unexpected package list output: %w", err)
+			}
+
+			rows := make([]tui.Row, 0, len(packages))
+			for _, p := range packages {
+				rows = append(rows, tui.Row{ID: p.Name, Columns: []string{p.Name, p.Category, p.Description}})
+			}
+			return rows, nil
+		},
+		Actions: []tui.Action{
+			{Key: "i", Label: "install", Confirm: true, Command: func(r tui.Row) []string { return []string{"install", r.ID} }},
+			{Key: tui.KeyEnter, Label: "info", Command: func(r tui.Row) []string { return []string{"package", "info", r.ID} }},
+		},
+	}
+}
+
+// pftTab lists feedback items of the PFT project in the current directory
+func pftTab(self string) *tui.Tab {
+	return &tui.Tab{
+		Title:   "PFT",
+		Columns: []string{"ID", "AREA", "STATUS", "TITLE"},
+		Empty:   "No feedback items (run 'portunix pft configure' first)",
+		Load: func() ([]tui.Row, error) {
+			output, err := exec.Command(self, "pft", "list", "--json").Output()
+			if err != nil {
+				return nil, fmt.Errorf("pft list failed: %w", err)
+			}
+			var items []struct {
+				ID     string `json:"id"`
+				Title  string `json:"title"`
+				Status string `json:"status"`
+				Type   string `json:"type"`
+			}
+			if err := json.Unmarshal(output, &items); err != nil {
+				// pft prints a hint instead of JSON when no project is configured
+				return nil, nil
+			}
+
+			rows := make([]tui.Row, 0, len(items))
+			for _, item := range items {
+				status := item.Status
+				if status == "" {
+					status = "open"
+				}
+				rows = append(rows, tui.Row{ID: item.ID, Columns: []string{item.ID, strings.ToUpper(item.Type), status, item.Title}})
+			}
+			return rows, nil
+		},
+		Actions: []tui.Action{
+			{Key: "y", Label: "sync", Confirm: true, Command: func(tui.Row) []string { return []string{"pft", "sync"} }},
+			{Key: tui.KeyEnter, Label: "show", Command: func(r tui.Row) []string { return []string{"pft", "show", r.ID} }},
+		},
+	}
+}
+
+// playbooksTab lists .ptxbook files in the current directory and one level below
+func playbooksTab() *tui.Tab {
+	return &tui.Tab{
+		Title:   "Playbooks",
+		Columns: []string{"FILE", "DIRECTORY"},
+		Empty:   "No *.ptxbook files in the current directory",
+		Load: func() ([]tui.Row, error) {
+			return findPlaybookRows(".")
+		},
+		Actions: []tui.Action{
+			{Key: "r", Label: "run", Confirm: true, Command: func(r tui.Row) []string { return []string{"playbook", "run", r.ID} }},
+			{Key: "d", Label: "dry run", Command: func(r tui.Row) []string { return []string{"playbook", "run", r.ID, "--dry-run"} }},
+			{Key: "v", Label: "validate", Command: func(r tui.Row) []string { return []string{"playbook", "validate", r.ID} }},
+		},
+	}
+}
+
+// findPlaybookRows finds *.ptxbook files in dir and its direct subdirectories
+func findPlaybookRows(dir string) ([]tui.Row, error) {
+	var files []string
+	for _, pattern := range []string{"*.ptxbook", filepath.Join("*", "*.ptxbook")} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	rows := make([]tui.Row, 0, len(files))
+	for _, file := range files {
+		rows = append(rows, tui.Row{ID: file, Columns: []string{filepath.Base(file), filepath.Dir(file)}})
+	}
+	return rows, nil
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
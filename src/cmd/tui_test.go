@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseContainerRows(t *testing.T) {
+	output := []byte(`{"ID":"abc","Names":"web","Image":"nginx","State":"running","Status":"Up 2 hours"}
+{"Id":"def","Names":["db"],"Image":"postgres","State":"exited","Status":"Exited (0)"}
+`)
+	rows, err := parseContainerRows(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].ID != "web" || rows[1].ID != "db" || rows[1].Columns[2] != "exited" {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+}
+
+func TestFindPlaybookRows(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"site.ptxbook", filepath.Join("deploy", "web.ptxbook"), "notes.txt"} {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := findPlaybookRows(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].Columns[0] != "web.ptxbook" || rows[1].Columns[0] != "site.ptxbook" {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+}
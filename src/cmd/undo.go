@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/journal"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [op-id|last]",
+	Short: "Reverse a recorded operation",
+	Long: `Portunix records what its commands change in an operation journal
+(~/.portunix/journal): packages installed, feedback items added or updated
+with 'portunix pft', containers started in detached mode. 'portunix undo'
+reverses an operation: installed packages are uninstalled, created files
+removed, overwritten files restored and containers removed.
+
+Files edited since the operation are left alone unless --force is given.
+Without arguments the recent operations are listed.`,
+	Example: `  portunix undo --list
+  portunix undo last --dry-run
+  portunix undo 20261017-142301-a3f2`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		list, _ := cmd.Flags().GetBool("list")
+		formatJSON, _ := cmd.Flags().GetBool("json")
+		if list || len(args) == 0 {
+			limit, _ := cmd.Flags().GetInt("limit")
+			listOperations(limit, formatJSON)
+			return
+		}
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		force, _ := cmd.Flags().GetBool("force")
+		op, results, err := journal.Undo(args[0], journal.UndoOptions{
+			DryRun:    dryRun,
+			Force:     force,
+			Uninstall: uninstallPackage,
+		})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if dryRun {
+			fmt.Printf("🔍 Undo of %s (%s) would:\n", op.ID, op.Command)
+		} else {
+			fmt.Printf("↩️  Undoing %s (%s)\n", op.ID, op.Command)
+		}
+		incomplete := false
+		for _, result := range results {
+			switch result.Status {
+			case journal.StatusDone:
+				mark := "✓"
+				if dryRun {
+					mark = "→"
+				}
+				fmt.Printf("  %s %s: %s\n", mark, result.Change.Describe(), result.Message)
+			case journal.StatusSkipped:
+				fmt.Printf("  ⚠️  %s: skipped, %s\n", result.Change.Describe(), result.Message)
+				incomplete = true
+			default:
+				fmt.Printf("  ✗ %s: %s\n", result.Change.Describe(), result.Message)
+				incomplete = true
+			}
+		}
+		if incomplete {
+			fmt.Println("\nSome changes were not reversed; the operation stays in the journal and can be undone again.")
+			os.Exit(1)
+		}
+	},
+}
+
+// listOperations prints the latest recorded operations, newest first
+func listOperations(limit int, formatJSON bool) {
+	operations, err := journal.Load()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if limit > 0 && len(operations) > limit {
+		operations = operations[len(operations)-limit:]
+	}
+	for i, j := 0, len(operations)-1; i < j; i, j = i+1, j-1 {
+		operations[i], operations[j] = operations[j], operations[i]
+	}
+
+	if formatJSON {
+		data, err := json.MarshalIndent(operations, "", "  ")
+		if err != nil {
+			fmt.Printf("Error formatting JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+	if len(operations) == 0 {
+		fmt.Println("No operations recorded.")
+		return
+	}
+
+	fmt.Printf("%-22s %-17s %-28s %s\n", "OPERATION", "TIME", "COMMAND", "CHANGES")
+	for _, op := range operations {
+		changes := fmt.Sprintf("%d", len(op.Changes))
+		if op.UndoneAt != nil {
+			changes += " (undone)"
+		}
+		fmt.Printf("%-22s %-17s %-28s %s\n", op.ID, op.Time.Format("2006-01-02 15:04"), op.Command, changes)
+		for _, change := range op.Changes {
+			fmt.Printf("  %s\n", change.Describe())
+		}
+	}
+}
+
+// uninstallPackage removes a package through 'portunix uninstall', which
+// runs the installer helper
+func uninstallPackage(name string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate portunix executable: %w", err)
+	}
+	command := exec.Command(self, "uninstall", name)
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	return command.Run()
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+	undoCmd.Flags().Bool("list", false, "List recorded operations")
+	undoCmd.Flags().Int("limit", 20, "Operations to list (0 for all)")
+	undoCmd.Flags().Bool("json", false, "List operations as JSON")
+	undoCmd.Flags().Bool("dry-run", false, "Show what would be reversed")
+	undoCmd.Flags().Bool("force", false, "Also reverse files edited since the operation")
+}
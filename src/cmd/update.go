@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"portunix.ai/app/update"
@@ -10,24 +11,57 @@ import (
 )
 
 var (
-	checkOnly   bool
-	forceUpdate bool
+	checkOnly      bool
+	forceUpdate    bool
+	updateChannel  string
+	insecureUpdate bool
 )
 
 var updateCmd = &cobra.Command{
-	Use:   "update",
-	Short: "Update Portunix to the latest version",
-	Long:  `Check for updates and install the latest version of Portunix from GitHub releases`,
+	Use:     "self-update",
+	Aliases: []string{"update"},
+	Short:   "Update Portunix and its helpers to the latest version",
+	Long: `Check GitHub releases for a newer version and install the portunix binary
+together with all helpers of the release for this OS and architecture.
+
+The release checksums are verified against the release signing key
+compiled into this build. Builds without a signing key refuse to update
+unless --insecure is given; the checksums then only detect corrupted
+downloads, not tampered releases. The new binaries are staged next to the
+installed ones and swapped in by rename; if any binary cannot be replaced,
+the previous binaries are restored.
+
+The stable channel only offers full releases, the beta channel also
+prereleases. The default channel is the update.channel setting.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		channel := updateChannel
+		if channel == "" {
+			channel = update.DefaultChannel()
+		}
+		if err := update.ValidateChannel(channel); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
 		currentVersion := appversion.ProductVersion
 		fmt.Printf("Current version: %s\n", currentVersion)
 
 		if checkOnly {
-			checkForUpdate()
+			checkForUpdate(channel)
 			return
 		}
 
-		performUpdate(forceUpdate)
+		if err := performUpdate(channel, forceUpdate, insecureUpdate); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			// Check if it's a permission error and provide better guidance
+			if update.IsPermissionError(err) {
+				fmt.Println("\n💡 Solutions:")
+				fmt.Println("  1. Run as Administrator (Right-click cmd.exe -> Run as administrator)")
+				fmt.Println("  2. Or download and reinstall manually from GitHub releases")
+				fmt.Println("  3. Or move portunix to a user-writable location (like Documents)")
+			}
+			os.Exit(1)
+		}
 	},
 }
 
@@ -35,12 +69,14 @@ func init() {
 	rootCmd.AddCommand(updateCmd)
 	updateCmd.Flags().BoolVar(&checkOnly, "check", false, "Only check for updates without installing")
 	updateCmd.Flags().BoolVar(&forceUpdate, "force", false, "Force update even if on latest version")
+	updateCmd.Flags().StringVar(&updateChannel, "channel", "", "Release channel: stable or beta (default: update.channel)")
+	updateCmd.Flags().BoolVar(&insecureUpdate, "insecure", false, "Update without a release signing key, verifying only the unsigned checksums")
 }
 
-func checkForUpdate() {
-	fmt.Println("Checking for updates...")
+func checkForUpdate(channel string) {
+	fmt.Printf("Checking for updates (%s channel)...\n", channel)
 
-	release, err := update.CheckForUpdate()
+	release, err := update.CheckChannel(channel)
 	if err != nil {
 		fmt.Printf("Error: Unable to check for updates\n  %v\n", err)
 		fmt.Println("  Please check your internet connection and try again")
@@ -53,22 +89,27 @@ func checkForUpdate() {
 	}
 
 	fmt.Printf("Latest version: %s\n", release.Version)
-	fmt.Printf("Update available! Run 'portunix update' to install.\n")
+	fmt.Printf("Update available! Run 'portunix self-update --channel %s' to install.\n", channel)
 }
 
-func performUpdate(force bool) {
-	fmt.Println("Checking for updates...")
+// performUpdate installs the latest release of the channel. Every failure
+// is returned, so the deferred removal of the downloaded archive runs
+// before the command exits.
+func performUpdate(channel string, force, insecure bool) error {
+	if err := update.RequireSigningKey(insecure); err != nil {
+		return err
+	}
+
+	fmt.Printf("Checking for updates (%s channel)...\n", channel)
 
-	release, err := update.CheckForUpdate()
+	release, err := update.CheckChannel(channel)
 	if err != nil {
-		fmt.Printf("Error: Unable to check for updates\n  %v\n", err)
-		fmt.Println("  Please check your internet connection and try again")
-		os.Exit(1)
+		return fmt.Errorf("unable to check for updates: %w\n  Please check your internet connection and try again", err)
 	}
 
 	if release == nil && !force {
 		fmt.Println("✓ You are running the latest version!")
-		return
+		return nil
 	}
 
 	if release == nil && force {
@@ -77,78 +118,52 @@ func performUpdate(force bool) {
 		fmt.Printf("⚠ Forcing reinstall of %s...\n", currentVersion)
 		release, err = update.GetRelease(currentVersion)
 		if err != nil {
-			fmt.Printf("Error: Unable to get release information\n  %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("unable to get release information: %w", err)
 		}
 	} else {
 		fmt.Printf("✓ New version available: %s\n", release.Version)
 	}
 
+	checksums, signed, err := update.FetchReleaseChecksums(release)
+	if err != nil {
+		return fmt.Errorf("%w\n  Update aborted for safety", err)
+	}
+	if signed {
+		fmt.Printf("✓ Signature of release %s verified\n", release.Version)
+	} else {
+		fmt.Println("⚠ Warning: --insecure: the release checksums are not signed, the release is not authenticated")
+	}
+
 	// Download update
 	fmt.Printf("✓ Downloading portunix-%s-%s-%s...\n", release.Version, update.GetOS(), update.GetArch())
-	archiveFile, err := update.DownloadUpdate(release)
+	archiveFile, err := update.DownloadUpdate(release, checksums)
 	if err != nil {
-		fmt.Printf("Error: Failed to download update\n  %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to download update: %w\n  This could indicate a corrupted download or security issue", err)
 	}
 	defer os.Remove(archiveFile)
-
-	// Verify checksum
-	fmt.Println("✓ Verifying checksum...")
-	checksumURL := release.ChecksumURL
-	if checksumURL == "" {
-		fmt.Println("⚠ Warning: No checksum available for verification")
+	if signed {
+		fmt.Println("✓ Checksum verified")
 	} else {
-		err = update.VerifyChecksum(archiveFile, checksumURL)
-		if err != nil {
-			fmt.Printf("Error: Checksum verification failed\n  %v\n", err)
-			fmt.Println("  This could indicate a corrupted download or security issue")
-			fmt.Println("  Update aborted for safety")
-			os.Exit(1)
-		}
+		fmt.Println("✓ Checksum matches the unsigned release checksums")
 	}
 
-	// Create backup
-	fmt.Println("✓ Creating backup...")
-	backupPath, err := update.CreateBackup()
+	targetDir, err := update.ExecutableDir()
 	if err != nil {
-		fmt.Printf("Error: Failed to create backup\n  %v\n", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Apply update
 	fmt.Println("✓ Installing update...")
-	err = update.ApplyUpdate(archiveFile)
+	installed, err := update.InstallRelease(archiveFile, targetDir)
 	if err != nil {
-		fmt.Printf("Error: Failed to apply update\n  %v\n", err)
-
-		// Check if it's a permission error and provide better guidance
-		if update.IsPermissionError(err) {
-			fmt.Println("\n💡 Solutions:")
-			fmt.Println("  1. Run as Administrator (Right-click cmd.exe -> Run as administrator)")
-			fmt.Println("  2. Or download and reinstall manually from GitHub releases")
-			fmt.Println("  3. Or move portunix to a user-writable location (like Documents)")
-		}
-
-		// Try to restore backup
-		fmt.Println("Attempting to restore backup...")
-		if restoreErr := update.RestoreBackup(backupPath); restoreErr != nil {
-			fmt.Printf("Error: Failed to restore backup\n  %v\n", restoreErr)
-			fmt.Println("  Manual intervention may be required")
-			fmt.Println("  Your original portunix.exe should still work")
-		} else {
-			fmt.Println("✓ Backup restored successfully")
-		}
-		os.Exit(1)
+		return fmt.Errorf("failed to apply update: %w\n  The previous version was kept", err)
 	}
 
-	// Clean up backup on success
-	os.Remove(backupPath)
-
+	fmt.Printf("✓ Updated %s\n", strings.Join(installed, ", "))
 	fmt.Println("✓ Update completed successfully!")
 	if !force {
 		fmt.Printf("\nPortunix has been updated from %s to %s\n", appversion.ProductVersion, release.Version)
 	} else {
 		fmt.Printf("\nPortunix %s has been reinstalled successfully\n", release.Version)
 	}
+	return nil
 }
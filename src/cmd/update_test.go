@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"portunix.ai/app/update"
+)
+
+func TestPerformUpdateRequiresSigningKey(t *testing.T) {
+	previous := update.SigningPublicKey
+	update.SigningPublicKey = ""
+	t.Cleanup(func() { update.SigningPublicKey = previous })
+
+	// The refusal comes before the release is looked up
+	err := performUpdate("stable", false, false)
+	if err == nil || !strings.Contains(err.Error(), "--insecure") {
+		t.Errorf("unsigned self-update should be refused, got %v", err)
+	}
+	if update.RequireSigningKey(true) != nil {
+		t.Error("--insecure should allow an unsigned update")
+	}
+}
@@ -12,9 +12,10 @@ import (
 
 // virtCmd represents the virt command - now delegates to ptx-virt helper
 var virtCmd = &cobra.Command{
-	Use:   "virt",
-	Short: "Virtual machine management",
-	Long: `Manage virtual machines using VirtualBox, QEMU/KVM, VMware, or Hyper-V.
+	Use:     "virt",
+	Aliases: []string{"vm"},
+	Short:   "Virtual machine management",
+	Long: `Manage virtual machines using VirtualBox, QEMU/KVM, VMware, Hyper-V, Multipass or WSL2.
 
 This command provides comprehensive VM management capabilities including:
 - Create and manage VMs
@@ -24,7 +25,7 @@ This command provides comprehensive VM management capabilities including:
 - Configure networking
 
 The virtualization backend is automatically detected based on available providers.
-Supported backends: VirtualBox, QEMU/KVM, VMware, Hyper-V
+Supported backends: VirtualBox, QEMU/KVM, VMware, Hyper-V, Multipass, WSL2 (--backend wsl)
 
 Use 'portunix virt check' to verify your system meets virtualization requirements.`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/config"
+	"portunix.ai/app/daemon"
+	"portunix.ai/app/vuln"
+)
+
+var vulnCmd = &cobra.Command{
+	Use:   "vuln",
+	Short: "Check installed packages and container images for vulnerabilities",
+	Long: `Check the environment against the OSV and NVD vulnerability feeds:
+
+  - packages installed by portunix (~/.portunix/installed.json)
+  - system packages of Debian, Ubuntu, Rocky Linux, AlmaLinux and Alpine
+  - local container images, from SBOMs generated with syft
+
+Every scan is compared with the previous one and only vulnerabilities that
+appeared since are notified. 'portunix vuln watch' scans periodically and
+raises desktop notifications or emails sent through the SMTP server of a pft
+configuration.
+
+Settings (portunix config set <key> <value>):
+  vuln.interval      time between watch scans (default 24h)
+  vuln.notify        desktop, email, desktop,email or none (default desktop)
+  vuln.email_to      comma separated email recipients
+  vuln.pft_config    pft configuration with the SMTP server (default ./.pft-config.json)
+  vuln.images        scan container images (default true)
+  vuln.min_severity  lowest notified severity (default: all)
+
+An NVD API key stored as vuln/nvd-api-key ('portunix secret set
+vuln/nvd-api-key') raises the NVD rate limit.
+
+  portunix vuln scan
+  portunix vuln watch --detach
+  portunix vuln status`,
+}
+
+var vulnScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Scan once and list the vulnerabilities found",
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		shouldNotify, _ := cmd.Flags().GetBool("notify")
+
+		opts := vulnOptions(cmd)
+		if !jsonOutput {
+			fmt.Println("🔍 Scanning installed packages for vulnerabilities...")
+		}
+		result, err := vuln.Scan(context.Background(), opts)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if shouldNotify {
+			notifyVulnerabilities(result)
+		}
+		if jsonOutput {
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		printVulnResult(result)
+	},
+}
+
+var vulnWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Scan periodically and notify about new vulnerabilities",
+	Long: `Scan every vuln.interval (default 24h) and notify about vulnerabilities
+that appeared since the previous scan. With --detach the watcher runs in the
+background with its output in ~/.portunix/vuln/watch.log; stop it with
+'portunix vuln stop'. To start the watcher at every login install it as a
+service with 'portunix service install vuln-watch'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		detach, _ := cmd.Flags().GetBool("detach")
+		intervalFlag, _ := cmd.Flags().GetString("interval")
+
+		if intervalFlag == "" {
+			intervalFlag = config.GetString("vuln.interval", "24h")
+		}
+		interval, err := time.ParseDuration(intervalFlag)
+		if err != nil || interval < time.Minute {
+			fmt.Printf("❌ Error: invalid interval %q (at least 1m, e.g. 6h)\n", intervalFlag)
+			os.Exit(1)
+		}
+
+		if detach {
+			if pid := vuln.WatchPID(); pid != 0 {
+				fmt.Printf("❌ Error: vulnerability watch is already running (PID %d)\n", pid)
+				os.Exit(1)
+			}
+			self, err := os.Executable()
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			if err := os.MkdirAll(vuln.Dir(), 0700); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			watchArgs := []string{"vuln", "watch", "--interval", interval.String()}
+			for _, name := range []string{"no-images", "no-system"} {
+				if value, _ := cmd.Flags().GetBool(name); value {
+					watchArgs = append(watchArgs, "--"+name)
+				}
+			}
+			pid, err := daemon.StartDetached(vuln.WatchLogPath(), self, watchArgs...)
+			if err != nil {
+				fmt.Printf("❌ Error: failed to start watcher: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✓ Vulnerability watch started (PID %d), scanning every %s\n", pid, interval)
+			fmt.Printf("  Log: %s\n", vuln.WatchLogPath())
+			return
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		fmt.Printf("%s 👀 Watching for vulnerabilities every %s\n", time.Now().Format("2006-01-02 15:04:05"), interval)
+		err = vuln.Watch(ctx, interval, vulnOptions(cmd), func(result *vuln.Result, err error) {
+			now := time.Now().Format("2006-01-02 15:04:05")
+			if err != nil {
+				fmt.Printf("%s ❌ Scan failed: %v\n", now, err)
+				return
+			}
+			for _, warning := range result.Warnings {
+				fmt.Printf("%s ⚠️  %s\n", now, warning)
+			}
+			fmt.Printf("%s ✓ %d packages, %d vulnerabilities, %d new\n", now, result.Packages, len(result.Findings), len(result.New))
+			notifyVulnerabilities(result)
+		})
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var vulnStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the watcher state and the findings of the last scan",
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		result, err := vuln.LastResult()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		pid := vuln.WatchPID()
+		if jsonOutput {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"watch_pid": pid,
+				"last_scan": result,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		if pid != 0 {
+			fmt.Printf("Watch:     running (PID %d)\n", pid)
+		} else {
+			fmt.Println("Watch:     not running")
+		}
+		if result == nil {
+			fmt.Println("Last scan: never; run 'portunix vuln scan'")
+			return
+		}
+		fmt.Printf("Last scan: %s\n\n", result.Time.Format("2006-01-02 15:04:05"))
+		printVulnResult(result)
+	},
+}
+
+var vulnStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the background watcher",
+	Run: func(cmd *cobra.Command, args []string) {
+		if stopped, err := stopInstalledService("vuln-watch"); stopped {
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("✓ Vulnerability watch service stopped until the next login")
+			return
+		}
+		if err := vuln.StopWatch(); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✓ Vulnerability watch stopped")
+	},
+}
+
+// vulnOptions returns the scan options of the --no-images and --no-system
+// flags and the vuln.images setting
+func vulnOptions(cmd *cobra.Command) vuln.Options {
+	noImages, _ := cmd.Flags().GetBool("no-images")
+	noSystem, _ := cmd.Flags().GetBool("no-system")
+	return vuln.Options{
+		Images:   !noImages && config.GetBool("vuln.images", true),
+		NoSystem: noSystem,
+	}
+}
+
+func printVulnResult(result *vuln.Result) {
+	for _, warning := range result.Warnings {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+	if len(result.Findings) == 0 {
+		fmt.Printf("✅ No known vulnerabilities in %d packages\n", result.Packages)
+		return
+	}
+	isNew := make(map[string]bool)
+	for _, f := range result.New {
+		isNew[f.Key()] = true
+	}
+	fmt.Printf("\n%-9s %-22s %-28s %s\n", "SEVERITY", "ID", "PACKAGE", "SOURCE")
+	for _, f := range result.Findings {
+		marker := ""
+		if isNew[f.Key()] {
+			marker = " 🆕"
+		}
+		id := f.ID
+		if cve := f.CVE(); cve != "" {
+			id = cve
+		}
+		fmt.Printf("%-9s %-22s %-28s %s%s\n", f.Severity, id, f.Package+" "+f.Version, f.Source, marker)
+	}
+	fmt.Printf("\n%d vulnerabilities in %d packages, %d new since the previous scan\n", len(result.Findings), result.Packages, len(result.New))
+}
+
+// notifyVulnerabilities sends the new findings at or above vuln.min_severity
+// through the channels of vuln.notify. Failures are printed, not fatal, so
+// the watch keeps running.
+func notifyVulnerabilities(result *vuln.Result) {
+	minSeverity := config.GetString("vuln.min_severity", "")
+	var findings []vuln.Finding
+	for _, f := range result.New {
+		if vuln.AtLeast(f.Severity, minSeverity) {
+			findings = append(findings, f)
+		}
+	}
+	if len(findings) == 0 {
+		return
+	}
+	subject, body := vuln.Message(findings)
+
+	for _, channel := range strings.Split(config.GetString("vuln.notify", vuln.NotifyDesktop), ",") {
+		switch strings.TrimSpace(channel) {
+		case vuln.NotifyDesktop:
+			text := fmt.Sprintf("%d new: %s", len(findings), findings[0].ID)
+			if len(findings) > 1 {
+				text += fmt.Sprintf(" and %d more", len(findings)-1)
+			}
+			if err := vuln.NotifyDesktopMessage(subject, text+". Run 'portunix vuln status'."); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+			}
+		case vuln.NotifyEmail:
+			smtpConfig, err := vuln.LoadSMTPConfig(config.GetString("vuln.pft_config", ".pft-config.json"))
+			if err != nil {
+				fmt.Printf("⚠️  Email notification skipped: %v\n", err)
+				continue
+			}
+			var to []string
+			for _, address := range strings.Split(config.GetString("vuln.email_to", ""), ",") {
+				if address = strings.TrimSpace(address); address != "" {
+					to = append(to, address)
+				}
+			}
+			if err := vuln.SendEmail(smtpConfig, to, subject, body); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+			} else {
+				fmt.Printf("📧 Notified %s\n", strings.Join(to, ", "))
+			}
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(vulnCmd)
+	vulnCmd.AddCommand(vulnScanCmd)
+	vulnCmd.AddCommand(vulnWatchCmd)
+	vulnCmd.AddCommand(vulnStatusCmd)
+	vulnCmd.AddCommand(vulnStopCmd)
+
+	for _, c := range []*cobra.Command{vulnScanCmd, vulnWatchCmd} {
+		c.Flags().Bool("no-images", false, "Skip container images")
+		c.Flags().Bool("no-system", false, "Skip system packages")
+	}
+	vulnScanCmd.Flags().Bool("json", false, "Output in JSON format")
+	vulnScanCmd.Flags().Bool("notify", false, "Notify about new vulnerabilities like 'vuln watch'")
+	vulnWatchCmd.Flags().String("interval", "", "Time between scans (default: vuln.interval or 24h)")
+	vulnWatchCmd.Flags().Bool("detach", false, "Run the watcher in the background")
+	vulnStatusCmd.Flags().Bool("json", false, "Output in JSON format")
+}
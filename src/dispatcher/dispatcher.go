@@ -7,7 +7,10 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
+	"github.com/rs/zerolog"
+	"portunix.ai/portunix/pkg/logging"
 	"portunix.ai/portunix/src/shared"
 )
 
@@ -25,6 +28,10 @@ type Dispatcher struct {
 	helpers   map[string]*HelperConfig
 	binSuffix string
 	discovery *shared.HelperDiscovery
+
+	routesPath  string         // Route manifest, see routes.go
+	routes      *RouteManifest // Loaded on first use
+	routesDirty bool
 }
 
 // NewDispatcher creates a new dispatcher instance
@@ -43,11 +50,12 @@ func NewDispatcher(version string) *Dispatcher {
 	}
 
 	d := &Dispatcher{
-		version:   version,
-		execDir:   execDir,
-		helpers:   make(map[string]*HelperConfig),
-		binSuffix: binSuffix,
-		discovery: shared.NewHelperDiscovery(version),
+		version:    version,
+		execDir:    execDir,
+		helpers:    make(map[string]*HelperConfig),
+		binSuffix:  binSuffix,
+		discovery:  shared.NewHelperDiscovery(version),
+		routesPath: routeManifestPath(),
 	}
 
 	// Register known helper binaries (Phase 2 preparation)
@@ -95,7 +103,7 @@ func (d *Dispatcher) registerHelpers() {
 
 	// Issue #100: PTX-Installer Helper for package installation
 	d.helpers["ptx-installer"] = &HelperConfig{
-		Commands: []string{"install", "package"},
+		Commands: []string{"install", "uninstall", "upgrade", "package"},
 		Binary:   "ptx-installer",
 		Required: false,
 	}
@@ -135,15 +143,52 @@ func (d *Dispatcher) registerHelpers() {
 		Required: false,
 	}
 
+	// PTX-Go Helper for Go toolchain management
+	d.helpers["ptx-go"] = &HelperConfig{
+		Commands: []string{"go"},
+		Binary:   "ptx-go",
+		Required: false,
+	}
+
+	// PTX-Java Helper for JDK and Maven/Gradle management
+	d.helpers["ptx-java"] = &HelperConfig{
+		Commands: []string{"java"},
+		Binary:   "ptx-java",
+		Required: false,
+	}
+
+	// PTX-Docs Helper for documentation site management
+	d.helpers["ptx-docs"] = &HelperConfig{
+		Commands: []string{"docs"},
+		Binary:   "ptx-docs",
+		Required: false,
+	}
+
+	// PTX-DB Helper for local database provisioning
+	d.helpers["ptx-db"] = &HelperConfig{
+		Commands: []string{"db"},
+		Binary:   "ptx-db",
+		Required: false,
+	}
+
+	// PTX-AI Helper for local LLM tooling
+	d.helpers["ptx-ai"] = &HelperConfig{
+		Commands: []string{"ai"},
+		Binary:   "ptx-ai",
+		Required: false,
+	}
+
 	// Issue #068: PTX-Virt Helper for virtualization management
 	d.helpers["ptx-virt"] = &HelperConfig{
-		Commands: []string{"virt"},
+		Commands: []string{"virt", "vm"},
 		Binary:   "ptx-virt",
 		Required: false,
 	}
 }
 
-// ShouldDispatch checks if a command should be dispatched to a helper binary
+// ShouldDispatch checks if a command should be dispatched to a helper binary.
+// Registered commands route without running any helper; other commands are
+// looked up in the route manifest of the installed helpers.
 func (d *Dispatcher) ShouldDispatch(args []string) (string, bool) {
 	if len(args) == 0 {
 		return "", false
@@ -163,7 +208,11 @@ func (d *Dispatcher) ShouldDispatch(args []string) (string, bool) {
 		}
 	}
 
-	return "", false
+	// Commands a helper reports beyond its registration
+	if strings.HasPrefix(command, "-") {
+		return "", false
+	}
+	return d.helperForCommand(command)
 }
 
 // helperExists checks if a helper binary exists and is executable
@@ -197,20 +246,35 @@ func (d *Dispatcher) Dispatch(helperPath string, args []string) error {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	return cmd.Run()
+	logging.Debug("dispatching to helper", "helper", filepath.Base(helperPath), "args", args)
+	start := time.Now()
+	err := cmd.Run()
+	if err != nil {
+		// The helper reports its own errors; only record the outcome here
+		logging.Capture(zerolog.ErrorLevel, "helper exited with error", "helper", filepath.Base(helperPath), "args", args,
+			"exit_code", cmd.ProcessState.ExitCode(), "duration", time.Since(start).String(), "error", err)
+	} else {
+		logging.Debug("helper finished", "helper", filepath.Base(helperPath), "duration", time.Since(start).String())
+	}
+	return err
 }
 
-// validateHelperVersion validates that helper binary version is compatible
+// validateHelperVersion validates that helper binary version is compatible,
+// using the version cached in the route manifest
 func (d *Dispatcher) validateHelperVersion(helperPath string) error {
-	// Try to get helper version
-	cmd := exec.Command(helperPath, "--version")
-	output, err := cmd.Output()
-	if err != nil {
+	binary := strings.TrimSuffix(filepath.Base(helperPath), d.binSuffix)
+	config := d.helpers[binary]
+	if config == nil {
+		config = &HelperConfig{Binary: binary}
+	}
+	route := d.route(config, helperPath)
+	d.saveRoutes()
+	if route == nil || route.Version == "" {
 		// If helper doesn't support --version, skip validation for now
 		return nil
 	}
 
-	helperVersionOutput := strings.TrimSpace(string(output))
+	helperVersionOutput := route.Version
 
 	// Extract version from output like "ptx-container version dev"
 	// Split by space and take the last part
@@ -237,6 +301,40 @@ func (d *Dispatcher) ListHelpers() map[string]bool {
 	return result
 }
 
+// HelperCommands returns the commands handled by installed helper binaries,
+// mapped to the helper path. Registered commands take precedence over the
+// commands helpers report in the route manifest.
+func (d *Dispatcher) HelperCommands() map[string]string {
+	result := make(map[string]string)
+	defer d.saveRoutes()
+
+	for _, name := range d.helperNames() {
+		config := d.helpers[name]
+		helperPath := filepath.Join(d.execDir, config.Binary+d.binSuffix)
+		if !d.helperExists(helperPath) {
+			continue
+		}
+		if route := d.route(config, helperPath); route != nil {
+			for _, cmd := range route.Commands {
+				if _, ok := result[cmd]; !ok {
+					result[cmd] = helperPath
+				}
+			}
+		}
+	}
+	for _, config := range d.helpers {
+		helperPath := filepath.Join(d.execDir, config.Binary+d.binSuffix)
+		if !d.helperExists(helperPath) {
+			continue
+		}
+		for _, cmd := range config.Commands {
+			result[cmd] = helperPath
+		}
+	}
+
+	return result
+}
+
 // DiscoverHelpers discovers all available helper binaries using the discovery mechanism
 func (d *Dispatcher) DiscoverHelpers() ([]*shared.HelperInfo, error) {
 	return d.discovery.DiscoverHelpers()
@@ -247,6 +345,11 @@ func (d *Dispatcher) GetExecutableDir() string {
 	return d.execDir
 }
 
+// GetRouteManifestPath returns the location of the cached command routes
+func (d *Dispatcher) GetRouteManifestPath() string {
+	return d.routesPath
+}
+
 // GetVersion returns the dispatcher version
 func (d *Dispatcher) GetVersion() string {
 	return d.version
@@ -0,0 +1,183 @@
+package dispatcher
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"portunix.ai/app/fileutil"
+	"portunix.ai/portunix/pkg/logging"
+)
+
+// The route manifest caches what routing needs from each helper binary, its
+// version and the commands it reports with --list-commands, so an invocation
+// execs only the helper it dispatches to. An entry is refreshed when its
+// binary changes (size or modification time), the whole manifest when
+// portunix itself changes version.
+
+// RouteManifestFile is the name of the route manifest in the cache directory
+const RouteManifestFile = "routes.json"
+
+// RouteManifest is the cached command routing of the installed helpers
+type RouteManifest struct {
+	Version string                  `json:"version"` // portunix version that built the manifest
+	Helpers map[string]*HelperRoute `json:"helpers"` // Binary name -> route
+}
+
+// HelperRoute is the cached routing information of a helper binary
+type HelperRoute struct {
+	ModTime  time.Time `json:"mod_time"`
+	Size     int64     `json:"size"`
+	Version  string    `json:"version,omitempty"` // Empty when the helper has no --version
+	Commands []string  `json:"commands"`
+}
+
+// routeManifestPath returns the location of the route manifest, empty when
+// there is no home directory to cache in
+func routeManifestPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".portunix", "cache", RouteManifestFile)
+}
+
+// loadRoutes reads the route manifest once per process. A missing or
+// unreadable manifest, or one built by another portunix version, starts empty.
+func (d *Dispatcher) loadRoutes() *RouteManifest {
+	if d.routes != nil {
+		return d.routes
+	}
+	d.routes = &RouteManifest{Version: d.version, Helpers: make(map[string]*HelperRoute)}
+	if d.routesPath == "" {
+		return d.routes
+	}
+
+	data, err := os.ReadFile(d.routesPath)
+	if err != nil {
+		return d.routes
+	}
+	var manifest RouteManifest
+	if err := json.Unmarshal(data, &manifest); err != nil || manifest.Version != d.version || manifest.Helpers == nil {
+		logging.Debug("route manifest rebuilt", "path", d.routesPath, "version", manifest.Version)
+		return d.routes
+	}
+	d.routes = &manifest
+	return d.routes
+}
+
+// saveRoutes writes the route manifest when routes were refreshed
+func (d *Dispatcher) saveRoutes() {
+	if !d.routesDirty || d.routesPath == "" {
+		return
+	}
+	d.routesDirty = false
+
+	data, err := json.MarshalIndent(d.routes, "", "  ")
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(d.routesPath), 0755); err == nil {
+			err = fileutil.WriteAtomic(d.routesPath, data, 0644)
+		}
+	}
+	if err != nil {
+		logging.Debug("failed to save route manifest", "path", d.routesPath, "error", err)
+	}
+}
+
+// route returns the routing information of a helper binary, querying the
+// binary only when it changed since it was cached. It returns nil when the
+// binary does not exist.
+func (d *Dispatcher) route(config *HelperConfig, helperPath string) *HelperRoute {
+	info, err := os.Stat(helperPath)
+	if err != nil {
+		return nil
+	}
+
+	manifest := d.loadRoutes()
+	if cached := manifest.Helpers[config.Binary]; cached != nil && cached.ModTime.Equal(info.ModTime()) && cached.Size == info.Size() {
+		return cached
+	}
+
+	route := &HelperRoute{
+		ModTime:  info.ModTime(),
+		Size:     info.Size(),
+		Version:  queryHelperVersion(helperPath),
+		Commands: queryHelperCommands(helperPath),
+	}
+	if len(route.Commands) == 0 {
+		route.Commands = config.Commands
+	}
+	logging.Debug("helper route refreshed", "helper", config.Binary, "version", route.Version, "commands", route.Commands)
+	manifest.Helpers[config.Binary] = route
+	d.routesDirty = true
+	return route
+}
+
+// helperForCommand returns the installed helper reporting a command that it
+// is not registered for, refreshing the routes of changed helpers
+func (d *Dispatcher) helperForCommand(command string) (string, bool) {
+	defer d.saveRoutes()
+
+	for _, name := range d.helperNames() {
+		config := d.helpers[name]
+		helperPath := filepath.Join(d.execDir, config.Binary+d.binSuffix)
+		if !d.helperExists(helperPath) {
+			continue
+		}
+		route := d.route(config, helperPath)
+		if route == nil {
+			continue
+		}
+		for _, cmd := range route.Commands {
+			if cmd == command {
+				return helperPath, true
+			}
+		}
+	}
+	return "", false
+}
+
+// helperNames returns the registered helpers in a stable order
+func (d *Dispatcher) helperNames() []string {
+	names := make([]string, 0, len(d.helpers))
+	for name := range d.helpers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// queryHelperVersion runs "<helper> --version" and returns its output,
+// empty when the helper does not support it
+func queryHelperVersion(helperPath string) string {
+	output, err := exec.Command(helperPath, "--version").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// queryHelperCommands runs "<helper> --list-commands", which prints a JSON
+// array or one command per line. Lines with spaces are messages of helpers
+// that do not know the flag.
+func queryHelperCommands(helperPath string) []string {
+	output, err := exec.Command(helperPath, "--list-commands").Output()
+	if err != nil {
+		return nil
+	}
+
+	var commands []string
+	if json.Unmarshal(output, &commands) == nil {
+		return commands
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.Contains(line, " ") {
+			commands = append(commands, line)
+		}
+	}
+	return commands
+}
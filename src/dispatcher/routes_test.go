@@ -0,0 +1,124 @@
+package dispatcher
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"portunix.ai/portunix/src/shared"
+)
+
+// writeFakeHelper writes a helper script that logs its invocations
+func writeFakeHelper(t *testing.T, dir, name, version string, commands ...string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	script := "#!/bin/sh\necho \"$@\" >> " + filepath.Join(dir, name+".log") + "\n" +
+		"case \"$1\" in\n" +
+		"--version) echo \"" + name + " version " + version + "\" ;;\n" +
+		"--list-commands) printf '" + strings.Join(commands, "\\n") + "\\n' ;;\n" +
+		"esac\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func helperCalls(t *testing.T, dir, name string) int {
+	data, err := os.ReadFile(filepath.Join(dir, name+".log"))
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "\n")
+}
+
+func newTestDispatcher(execDir, routesPath, version string) *Dispatcher {
+	d := &Dispatcher{
+		version:    version,
+		execDir:    execDir,
+		helpers:    make(map[string]*HelperConfig),
+		discovery:  shared.NewHelperDiscovery(version),
+		routesPath: routesPath,
+	}
+	d.registerHelpers()
+	return d
+}
+
+func TestRouteManifest(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake helpers are shell scripts")
+	}
+	execDir := t.TempDir()
+	routesPath := filepath.Join(t.TempDir(), RouteManifestFile)
+	helperPath := writeFakeHelper(t, execDir, "ptx-db", "1.2.0", "db", "database")
+
+	// Registered commands route without running the helper
+	d := newTestDispatcher(execDir, routesPath, "1.2.3")
+	if path, ok := d.ShouldDispatch([]string{"db", "--help"}); !ok || path != helperPath {
+		t.Fatalf("db not routed: %s %v", path, ok)
+	}
+	if calls := helperCalls(t, execDir, "ptx-db"); calls != 0 {
+		t.Errorf("routing a registered command ran the helper %d times", calls)
+	}
+
+	// A reported command builds the manifest once
+	if path, ok := d.ShouldDispatch([]string{"database"}); !ok || path != helperPath {
+		t.Fatalf("reported command not routed: %s %v", path, ok)
+	}
+	if err := d.validateHelperVersion(helperPath); err != nil {
+		t.Fatal(err)
+	}
+	if calls := helperCalls(t, execDir, "ptx-db"); calls != 2 {
+		t.Errorf("expected --version and --list-commands, got %d calls", calls)
+	}
+
+	// Later invocations use the manifest
+	d = newTestDispatcher(execDir, routesPath, "1.2.3")
+	d.ShouldDispatch([]string{"database"})
+	if err := d.validateHelperVersion(helperPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := d.ShouldDispatch([]string{"system"}); ok {
+		t.Error("unknown command routed to a helper")
+	}
+	if calls := helperCalls(t, execDir, "ptx-db"); calls != 2 {
+		t.Errorf("cached routes ran the helper: %d calls", calls)
+	}
+
+	// A replaced helper is queried again and validated with its new version
+	writeFakeHelper(t, execDir, "ptx-db", "2.0.0", "db")
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(helperPath, future, future)
+	d = newTestDispatcher(execDir, routesPath, "1.2.3")
+	if err := d.validateHelperVersion(helperPath); err == nil {
+		t.Error("incompatible helper version accepted")
+	}
+	if _, ok := d.ShouldDispatch([]string{"database"}); ok {
+		t.Error("stale route used after the helper changed")
+	}
+
+	// A new portunix version rebuilds the manifest
+	before := helperCalls(t, execDir, "ptx-db")
+	d = newTestDispatcher(execDir, routesPath, "2.0.1")
+	d.ShouldDispatch([]string{"database"})
+	if calls := helperCalls(t, execDir, "ptx-db") - before; calls != 2 {
+		t.Errorf("manifest not rebuilt for a new version: %d calls", calls)
+	}
+}
+
+func TestQueryHelperCommandsIgnoresMessages(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake helpers are shell scripts")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ptx-old")
+	os.WriteFile(path, []byte("#!/bin/sh\necho 'Unknown command: --list-commands'\necho 'Supported commands: old'\n"), 0755)
+	if commands := queryHelperCommands(path); len(commands) != 0 {
+		t.Errorf("messages parsed as commands: %v", commands)
+	}
+}
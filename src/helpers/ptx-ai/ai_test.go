@@ -0,0 +1,241 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectGPUs(t *testing.T) {
+	gpus := parseNvidiaSMI("NVIDIA GeForce RTX 4090, 24564, 550.54.14\nNVIDIA A100-SXM4-80GB, 81920, 550.54.14\n")
+	if len(gpus) != 2 || gpus[0].Name != "NVIDIA GeForce RTX 4090" || gpus[0].MemoryMB != 24564 || gpus[1].Driver != "550.54.14" {
+		t.Errorf("nvidia-smi: %+v", gpus)
+	}
+
+	drm := t.TempDir()
+	card := func(name, vendor, vram string) {
+		dir := filepath.Join(drm, name, "device")
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "vendor"), []byte(vendor+"\n"), 0644)
+		if vram != "" {
+			os.WriteFile(filepath.Join(dir, "mem_info_vram_total"), []byte(vram+"\n"), 0644)
+		}
+	}
+	card("card0", "0x8086", "")
+	card("card1", "0x1002", "17163091968")
+	card("card2", "0x1002", "536870912") // Integrated graphics
+	os.WriteFile(filepath.Join(drm, "card1", "device", "product_name"), []byte("Radeon RX 7800 XT\n"), 0644)
+	amd := detectAMDGPUs(drm)
+	if len(amd) != 1 || amd[0].Name != "Radeon RX 7800 XT" || amd[0].MemoryMB != 16368 {
+		t.Errorf("sysfs: %+v", amd)
+	}
+
+	for _, c := range []struct {
+		gpus []GPU
+		goos string
+		want string
+	}{
+		{nil, "linux", VariantCPU},
+		{gpus, "linux", VariantVulkan},
+		{gpus, "windows", VariantCUDA},
+		{amd, "windows", VariantHIP},
+		{nil, "darwin", VariantMetal},
+	} {
+		if got := recommendedVariant(c.gpus, c.goos); got != c.want {
+			t.Errorf("recommendedVariant(%v, %s) = %s, want %s", c.gpus, c.goos, got, c.want)
+		}
+	}
+}
+
+func testRelease(tag string, names ...string) *release {
+	r := &release{TagName: tag}
+	for _, name := range names {
+		r.Assets = append(r.Assets, releaseAsset{Name: name, URL: "https://example.com/" + name})
+	}
+	return r
+}
+
+func TestBackendAssets(t *testing.T) {
+	llama := testRelease("b6000",
+		"llama-b6000-bin-ubuntu-x64.zip",
+		"llama-b6000-bin-ubuntu-vulkan-x64.zip",
+		"llama-b6000-bin-macos-arm64.zip",
+		"llama-b6000-bin-win-cpu-x64.zip",
+		"llama-b6000-bin-win-cuda-13.1-x64.zip",
+		"llama-b6000-bin-win-cuda-12.4-x64.zip",
+		"cudart-llama-bin-win-cuda-12.4-x64.zip",
+		"llama-b6000-bin-win-hip-radeon-x64.zip",
+	)
+	for _, c := range []struct {
+		goos, goarch, variant string
+		want                  string
+	}{
+		{"linux", "amd64", VariantCPU, "llama-b6000-bin-ubuntu-x64.zip"},
+		{"linux", "amd64", VariantVulkan, "llama-b6000-bin-ubuntu-vulkan-x64.zip"},
+		{"darwin", "arm64", VariantMetal, "llama-b6000-bin-macos-arm64.zip"},
+		{"windows", "amd64", VariantCPU, "llama-b6000-bin-win-cpu-x64.zip"},
+		{"windows", "amd64", VariantCUDA, "llama-b6000-bin-win-cuda-12.4-x64.zip cudart-llama-bin-win-cuda-12.4-x64.zip"},
+		{"windows", "amd64", VariantHIP, "llama-b6000-bin-win-hip-radeon-x64.zip"},
+	} {
+		assets, err := llamaCppAssets(llama, c.goos, c.goarch, c.variant)
+		if err != nil {
+			t.Errorf("%s/%s %s: %v", c.goos, c.goarch, c.variant, err)
+			continue
+		}
+		var names []string
+		for _, a := range assets {
+			names = append(names, a.Name)
+		}
+		if got := strings.Join(names, " "); got != c.want {
+			t.Errorf("%s/%s %s: %s, want %s", c.goos, c.goarch, c.variant, got, c.want)
+		}
+	}
+	if _, err := llamaCppAssets(llama, "linux", "amd64", VariantCUDA); err == nil {
+		t.Error("Linux CUDA build accepted")
+	}
+	if _, err := llamaCppAssets(llama, "linux", "arm64", VariantCPU); err == nil {
+		t.Error("missing build accepted")
+	}
+
+	ollama := testRelease("v0.12.3", "ollama-linux-amd64.tgz", "ollama-linux-amd64-rocm.tgz", "ollama-darwin.tgz", "sha256sum.txt")
+	if assets, err := ollamaAssets(ollama, "linux", "amd64", VariantHIP); err != nil || len(assets) != 2 || assets[1].Name != "ollama-linux-amd64-rocm.tgz" {
+		t.Errorf("ollama ROCm: %v, %v", assets, err)
+	}
+	if _, err := ollamaAssets(ollama, "windows", "amd64", ""); err == nil {
+		t.Error("missing Windows archive accepted")
+	}
+}
+
+func TestChecksums(t *testing.T) {
+	sums := "8f3c  ./ollama-darwin.tgz\n" +
+		"5d1e1c1e0c1a0f4f6a8c4c3f2b1a0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a39  ./ollama-linux-amd64.tgz\n"
+	if got := findChecksum(sums, "ollama-linux-amd64.tgz"); !strings.HasPrefix(got, "5d1e1c") {
+		t.Errorf("sha256sum.txt: %q", got)
+	}
+	if got := findChecksum(strings.Repeat("AB", 32)+"\n", "x.zip"); got != strings.Repeat("ab", 32) {
+		t.Errorf("bare hash: %q", got)
+	}
+
+	r := testRelease("b1", "a.zip")
+	r.Assets[0].Digest = "sha256:" + strings.Repeat("0", 64)
+	if sum, err := assetChecksum(r, r.Assets[0]); err != nil || sum != strings.Repeat("0", 64) {
+		t.Errorf("digest: %q, %v", sum, err)
+	}
+	r.Assets[0].Digest = ""
+	if _, err := assetChecksum(r, r.Assets[0]); err == nil {
+		t.Error("asset without checksum accepted")
+	}
+}
+
+func TestParseModelRef(t *testing.T) {
+	t.Setenv("HF_ENDPOINT", "")
+	src, gguf, err := parseModelRef("hf:Qwen/Qwen2.5-7B-Instruct-GGUF/q4/qwen2.5-7b-instruct-q4_k_m.gguf")
+	if err != nil || !gguf || src.Repo != "Qwen/Qwen2.5-7B-Instruct-GGUF" || src.Revision != "main" ||
+		src.Path != "q4/qwen2.5-7b-instruct-q4_k_m.gguf" || src.File != "qwen2.5-7b-instruct-q4_k_m.gguf" ||
+		src.URL != "https://huggingface.co/Qwen/Qwen2.5-7B-Instruct-GGUF/resolve/main/q4/qwen2.5-7b-instruct-q4_k_m.gguf" {
+		t.Errorf("hf: %+v, %v", src, err)
+	}
+	if src, _, _ := parseModelRef("hf:org/repo@v2/model.gguf"); src.Revision != "v2" || src.Repo != "org/repo" {
+		t.Errorf("hf revision: %+v", src)
+	}
+	src, _, _ = parseModelRef("https://huggingface.co/org/repo/resolve/abc123/model.gguf?download=true")
+	if src.Repo != "org/repo" || src.Revision != "abc123" || src.Path != "model.gguf" {
+		t.Errorf("hf URL: %+v", src)
+	}
+	if src, _, _ := parseModelRef("https://models.corp/llm/model.gguf"); src.Repo != "" || src.File != "model.gguf" {
+		t.Errorf("URL: %+v", src)
+	}
+	if _, gguf, _ := parseModelRef("llama3.2:3b"); gguf {
+		t.Error("Ollama model taken for GGUF")
+	}
+	if _, _, err := parseModelRef("hf:org/repo"); err == nil {
+		t.Error("hf reference without file accepted")
+	}
+}
+
+func TestPullGGUF(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("PORTUNIX_AI_ROOT_DIR", filepath.Join(home, "ai"))
+
+	content := []byte("GGUF test model")
+	sum := sha256.Sum256(content)
+	sha := hex.EncodeToString(sum[:])
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/models/org/repo/tree/main":
+			fmt.Fprintf(w, `[{"type":"file","path":"README.md"},{"type":"file","path":"tiny.gguf","lfs":{"oid":"%s"}}]`, sha)
+		case "/org/repo/resolve/main/tiny.gguf", "/tiny.gguf":
+			w.Write(content)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("HF_ENDPOINT", server.URL)
+
+	src, _, _ := parseModelRef("hf:org/repo/tiny.gguf")
+	m, err := pullGGUF(src, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "tiny" || m.SHA256 != sha || m.Size != int64(len(content)) {
+		t.Errorf("model: %+v", m)
+	}
+	models, _ := loadModels()
+	if len(models) != 1 || findModel(models, "tiny") == nil {
+		t.Errorf("registry: %v", models)
+	}
+
+	// Other URLs need the checksum, and a wrong one fails
+	src, _, _ = parseModelRef(server.URL + "/tiny.gguf")
+	src.Repo = "" // Served by the test hub, treated as a plain URL
+	if _, err := pullGGUF(src, "plain", ""); err == nil || !strings.Contains(err.Error(), "--sha256") {
+		t.Errorf("missing checksum: %v", err)
+	}
+	os.Remove(filepath.Join(home, "ai", "models", "tiny.gguf"))
+	if _, err := pullGGUF(src, "tiny", strings.Repeat("0", 64)); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("wrong checksum: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, "ai", "models", "tiny.gguf")); err == nil {
+		t.Error("file with wrong checksum kept")
+	}
+}
+
+func TestServerCommand(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("PORTUNIX_AI_ROOT_DIR", home)
+	os.MkdirAll(filepath.Join(home, "backends", BackendLlamaCpp), 0755)
+	os.WriteFile(filepath.Join(home, "backends", BackendLlamaCpp, "backend.json"),
+		[]byte(`{"name":"llama.cpp","version":"b6000","variant":"cpu","binary":"/opt/llama-server"}`), 0644)
+	saveModels([]*Model{{Name: "tiny", File: "tiny.gguf", SHA256: "00"}})
+
+	if serveBackend("tiny") != BackendLlamaCpp || serveBackend("llama3.2:3b") != BackendOllama || serveBackend("") != BackendOllama {
+		t.Error("serveBackend")
+	}
+	cmd, endpoint, err := serverCommand(BackendLlamaCpp, "tiny", "0.0.0.0", 0, -1, 8192)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "/opt/llama-server -m " + filepath.Join(home, "models", "tiny.gguf") + " --alias tiny --host 0.0.0.0 --port 8080 --n-gpu-layers 0 --ctx-size 8192"
+	if got := strings.Join(cmd.Args, " "); got != want {
+		t.Errorf("command:\n%s\nwant:\n%s", got, want)
+	}
+	if endpoint != "http://127.0.0.1:8080/v1" {
+		t.Errorf("endpoint %s", endpoint)
+	}
+	if _, _, err := serverCommand(BackendLlamaCpp, "missing", "127.0.0.1", 0, -1, 0); err == nil {
+		t.Error("unknown model accepted")
+	}
+}
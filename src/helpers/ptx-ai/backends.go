@@ -0,0 +1,506 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"portunix.ai/app/config"
+	"portunix.ai/app/extract"
+	"portunix.ai/app/fetch"
+	"portunix.ai/app/fileutil"
+	"portunix.ai/app/mirror"
+	"portunix.ai/app/progress"
+)
+
+// Supported backends
+const (
+	BackendOllama   = "ollama"
+	BackendLlamaCpp = "llama.cpp"
+)
+
+// githubAPI is the GitHub REST API the backend releases are looked up in
+var githubAPI = "https://api.github.com"
+
+// backendSpec describes where a backend is released and which binary
+// serves models
+type backendSpec struct {
+	Repo   string
+	Binary string
+	// assets selects the release archives of a platform and build variant
+	assets func(r *release, goos, goarch, variant string) ([]releaseAsset, error)
+}
+
+var backendSpecs = map[string]backendSpec{
+	BackendOllama:   {Repo: "ollama/ollama", Binary: "ollama", assets: ollamaAssets},
+	BackendLlamaCpp: {Repo: "ggml-org/llama.cpp", Binary: "llama-server", assets: llamaCppAssets},
+}
+
+// Backend is a backend installed by 'portunix ai install'
+type Backend struct {
+	Name      string    `json:"name"`
+	Version   string    `json:"version"`
+	Variant   string    `json:"variant,omitempty"`
+	Binary    string    `json:"binary"`
+	Installed time.Time `json:"installed"`
+}
+
+// release is a GitHub release with its assets
+type release struct {
+	TagName string         `json:"tag_name"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+type releaseAsset struct {
+	Name   string `json:"name"`
+	URL    string `json:"browser_download_url"`
+	Size   int64  `json:"size"`
+	Digest string `json:"digest"` // sha256:<hex> for assets uploaded since mid 2025
+}
+
+// aiDir returns ~/.portunix/ai, holding backends, models and the server state
+func aiDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return config.GetString("ai.root_dir", filepath.Join(home, ".portunix", "ai")), nil
+}
+
+// backendDir returns the installation directory of a backend
+func backendDir(name string) (string, error) {
+	dir, err := aiDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "backends", name), nil
+}
+
+// loadBackend returns an installed backend, nil when it is not installed
+func loadBackend(name string) (*Backend, error) {
+	dir, err := backendDir(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "backend.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var b Backend
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid backend record of %s: %v", name, err)
+	}
+	return &b, nil
+}
+
+// backendBinary returns the binary of a backend: the one installed by
+// portunix, or else one found in PATH
+func backendBinary(name string) (string, error) {
+	b, err := loadBackend(name)
+	if err != nil {
+		return "", err
+	}
+	if b != nil {
+		return b.Binary, nil
+	}
+	if path, err := exec.LookPath(backendSpecs[name].Binary); err == nil {
+		return path, nil
+	}
+	return "", fmt.Errorf("%s is not installed (install it with 'portunix ai install %s')", name, name)
+}
+
+// fetchRelease looks up the latest release of a repository, or the one of tag
+func fetchRelease(repo, tag string) (*release, error) {
+	url := githubAPI + "/repos/" + repo + "/releases/latest"
+	if tag != "" && tag != "latest" {
+		url = githubAPI + "/repos/" + repo + "/releases/tags/" + tag
+	}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the release of %s: %v", repo, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("release %s of %s not found", tag, repo)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to look up the release of %s: HTTP %d", repo, resp.StatusCode)
+	}
+	var r release
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("invalid release of %s: %v", repo, err)
+	}
+	return &r, nil
+}
+
+func (r *release) asset(name string) *releaseAsset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// ollamaAssets selects the Ollama archive of a platform. Its builds include
+// CUDA; AMD cards on Linux need the ROCm archive on top (variant hip).
+func ollamaAssets(r *release, goos, goarch, variant string) ([]releaseAsset, error) {
+	var names []string
+	switch goos {
+	case "linux":
+		names = []string{"ollama-linux-" + goarch + ".tgz"}
+		if variant == VariantHIP {
+			names = append(names, "ollama-linux-"+goarch+"-rocm.tgz")
+		}
+	case "darwin":
+		names = []string{"ollama-darwin.tgz"}
+	case "windows":
+		names = []string{"ollama-windows-" + goarch + ".zip"}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("Ollama is not released for %s/%s", goos, goarch)
+	}
+	var assets []releaseAsset
+	for _, name := range names {
+		a := r.asset(name)
+		if a == nil {
+			return nil, fmt.Errorf("Ollama %s has no %s archive", r.TagName, name)
+		}
+		assets = append(assets, *a)
+	}
+	return assets, nil
+}
+
+// llamaCppAssets selects the llama.cpp build of a platform and variant, named
+// like llama-b6000-bin-ubuntu-vulkan-x64.zip or llama-b6000-bin-win-cuda-12.4-x64.zip.
+// Of several CUDA builds the oldest CUDA version is taken, which the most
+// drivers support; Windows CUDA builds need the cudart archive as well.
+func llamaCppAssets(r *release, goos, goarch, variant string) ([]releaseAsset, error) {
+	osToken := map[string]string{"linux": "ubuntu", "darwin": "macos", "windows": "win"}[goos]
+	archToken := map[string]string{"amd64": "x64", "arm64": "arm64"}[goarch]
+	if osToken == "" || archToken == "" {
+		return nil, fmt.Errorf("llama.cpp is not released for %s/%s", goos, goarch)
+	}
+
+	// Token of the variant in the asset name, and whether it carries a
+	// version or flavor (cuda-12.4, hip-radeon)
+	token, flavored := "", false
+	switch {
+	case goos == "darwin" && (variant == VariantMetal || variant == VariantCPU):
+	case goos == "linux" && variant == VariantCPU:
+	case goos == "windows" && variant == VariantCPU:
+		token = "cpu-"
+	case variant == VariantVulkan && goos != "darwin":
+		token = "vulkan-"
+	case goos == "windows" && (variant == VariantCUDA || variant == VariantHIP):
+		token, flavored = variant+"-", true
+	default:
+		return nil, fmt.Errorf("llama.cpp publishes no %s build for %s (try --variant %s)", variant, goos, recommendedVariant(nil, goos))
+	}
+
+	prefix := "llama-" + r.TagName + "-bin-" + osToken + "-" + token
+	var candidates []string
+	flavors := make(map[string]string)
+	for _, a := range r.Assets {
+		rest, ok := strings.CutPrefix(a.Name, prefix)
+		if !ok {
+			continue
+		}
+		for _, ext := range []string{".zip", ".tar.gz"} {
+			suffix := archToken + ext
+			if rest == suffix && !flavored {
+				candidates = append(candidates, a.Name)
+			} else if flavored && strings.HasSuffix(rest, "-"+suffix) {
+				candidates = append(candidates, a.Name)
+				flavors[a.Name] = strings.TrimSuffix(rest, "-"+suffix)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("llama.cpp %s has no %s build for %s/%s", r.TagName, variant, goos, goarch)
+	}
+	sort.Strings(candidates)
+	assets := []releaseAsset{*r.asset(candidates[0])}
+	if variant == VariantCUDA {
+		if cudart := r.asset("cudart-llama-bin-win-cuda-" + flavors[candidates[0]] + "-" + archToken + ".zip"); cudart != nil {
+			assets = append(assets, *cudart)
+		}
+	}
+	return assets, nil
+}
+
+// assetChecksum returns the SHA-256 of an asset: the digest GitHub records,
+// or the entry of a checksum file of the release
+func assetChecksum(r *release, a releaseAsset) (string, error) {
+	if sum, ok := strings.CutPrefix(a.Digest, "sha256:"); ok {
+		return sum, nil
+	}
+	for _, name := range []string{"sha256sum.txt", "SHA256SUMS", a.Name + ".sha256"} {
+		sums := r.asset(name)
+		if sums == nil {
+			continue
+		}
+		client := &http.Client{Timeout: time.Minute}
+		resp, err := client.Get(sums.URL)
+		if err != nil {
+			return "", fmt.Errorf("failed to download %s: %v", name, err)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("failed to download %s", name)
+		}
+		if sum := findChecksum(string(data), a.Name); sum != "" {
+			return sum, nil
+		}
+	}
+	return "", fmt.Errorf("release %s publishes no checksum for %s", r.TagName, a.Name)
+}
+
+// findChecksum finds the checksum of a file in sha256sum output; a bare
+// hash is the one of a <file>.sha256 published for the file alone
+func findChecksum(sums, name string) string {
+	scanner := bufio.NewScanner(strings.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 1 && len(fields[0]) == 64:
+			return strings.ToLower(fields[0])
+		case len(fields) == 2 && strings.TrimPrefix(strings.TrimPrefix(fields[1], "*"), "./") == name:
+			return strings.ToLower(fields[0])
+		}
+	}
+	return ""
+}
+
+// downloadVerified downloads url to dest through the mirrors of the network
+// profile and checks its SHA-256. A complete file from an earlier run is
+// kept, an interrupted one is resumed.
+func downloadVerified(url, dest, sha string, header http.Header) error {
+	sha = strings.ToLower(sha)
+	if sum, err := fileSHA256(dest); err == nil && sum == sha {
+		return nil
+	}
+	urls, err := mirror.DownloadURLs(url)
+	if err != nil {
+		return err
+	}
+	if urls[0] != url {
+		fmt.Printf("🪞 Using mirror: %s\n", urls[0])
+	}
+	reporter := progress.NewReporter(os.Stdout)
+	stage := filepath.Base(dest)
+	result, err := fetch.File(urls, dest, fetch.Options{Reporter: reporter, Stage: stage, Log: os.Stdout, Header: header})
+	if err != nil {
+		return err
+	}
+	reporter.Done(stage, progress.FormatBytes(result.Size))
+
+	sum, err := fileSHA256(dest)
+	if err != nil {
+		return err
+	}
+	if sum != sha {
+		os.Remove(dest)
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", filepath.Base(dest), sha, sum)
+	}
+	fmt.Printf("🔒 SHA-256 verified: %s\n", sha)
+	return nil
+}
+
+// fileSHA256 returns the hex SHA-256 of a file
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// installBackend downloads a backend release, verifies and extracts its
+// archives and replaces an earlier installation
+func installBackend(name, tag, variant string) (*Backend, error) {
+	spec, ok := backendSpecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown backend %q (supported: %s, %s)", name, BackendOllama, BackendLlamaCpp)
+	}
+	root, err := aiDir()
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := fetchRelease(spec.Repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := spec.assets(r, runtime.GOOS, runtime.GOARCH, variant)
+	if err != nil {
+		return nil, err
+	}
+	if current, _ := loadBackend(name); current != nil && current.Version == r.TagName && current.Variant == variant {
+		fmt.Printf("%s %s is already installed at %s\n", name, current.Version, current.Binary)
+		return current, nil
+	}
+
+	backendsDir := filepath.Join(root, "backends")
+	if err := os.MkdirAll(backendsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backends directory: %v", err)
+	}
+	stagingDir, err := os.MkdirTemp(backendsDir, ".staging-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	for _, a := range assets {
+		sum, err := assetChecksum(r, a)
+		if err != nil {
+			return nil, err
+		}
+		archive := filepath.Join(root, "downloads", a.Name)
+		fmt.Printf("📥 Downloading %s %s (%s)...\n", name, r.TagName, a.Name)
+		if err := downloadVerified(a.URL, archive, sum, nil); err != nil {
+			return nil, err
+		}
+		fmt.Println("📦 Extracting...")
+		if strings.HasSuffix(a.Name, ".zip") {
+			err = extract.Zip(archive, stagingDir)
+		} else {
+			err = extract.TarGz(archive, stagingDir)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("extraction of %s failed: %v", a.Name, err)
+		}
+		os.Remove(archive)
+	}
+
+	binaryName := spec.Binary
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+	binary, err := findFile(stagingDir, binaryName)
+	if err != nil {
+		return nil, fmt.Errorf("%s not found in the %s archive", binaryName, name)
+	}
+
+	target := filepath.Join(backendsDir, name)
+	if err := os.RemoveAll(target); err != nil {
+		return nil, fmt.Errorf("failed to remove the previous %s: %v", name, err)
+	}
+	if err := os.Rename(stagingDir, target); err != nil {
+		return nil, fmt.Errorf("failed to install %s: %v", name, err)
+	}
+	rel, _ := filepath.Rel(stagingDir, binary)
+	b := &Backend{Name: name, Version: r.TagName, Variant: variant, Binary: filepath.Join(target, rel), Installed: time.Now()}
+	os.Chmod(b.Binary, 0755)
+	data, _ := json.MarshalIndent(b, "", "  ")
+	if err := fileutil.WriteFile(filepath.Join(target, "backend.json"), data, 0644); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// findFile returns the first file named name below dir
+func findFile(dir, name string) (string, error) {
+	var found string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == name {
+			found = path
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", os.ErrNotExist
+	}
+	return found, nil
+}
+
+func handleInstall(args []string) error {
+	positional, flags, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: portunix ai install <%s|%s> [--version V] [--variant V]", BackendOllama, BackendLlamaCpp)
+	}
+	name := positional[0]
+	if name == "llamacpp" || name == "llama-cpp" {
+		name = BackendLlamaCpp
+	}
+
+	variant := flags["variant"]
+	if variant == "" {
+		gpus := detectGPUs()
+		switch name {
+		case BackendLlamaCpp:
+			variant = recommendedVariant(gpus, runtime.GOOS)
+		case BackendOllama:
+			for _, gpu := range gpus {
+				if gpu.Vendor == VendorAMD && runtime.GOOS == "linux" {
+					variant = VariantHIP
+				}
+			}
+		}
+		if variant != "" {
+			fmt.Printf("🎮 Using the %s build (detected: %s)\n", variant, describeGPUs(gpus))
+		}
+	}
+
+	b, err := installBackend(name, flags["version"], variant)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ %s %s installed at %s\n", b.Name, b.Version, b.Binary)
+	return nil
+}
+
+// describeGPUs names the GPUs for messages
+func describeGPUs(gpus []GPU) string {
+	if len(gpus) == 0 {
+		return "no GPU"
+	}
+	names := make([]string, 0, len(gpus))
+	for _, gpu := range gpus {
+		names = append(names, gpu.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,186 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// GPU vendors
+const (
+	VendorNVIDIA = "nvidia"
+	VendorAMD    = "amd"
+	VendorApple  = "apple"
+)
+
+// llama.cpp build variants; the release builds differ per platform
+const (
+	VariantCPU    = "cpu"
+	VariantCUDA   = "cuda"
+	VariantVulkan = "vulkan"
+	VariantHIP    = "hip"
+	VariantMetal  = "metal"
+)
+
+// GPU is a graphics card inference can run on
+type GPU struct {
+	Vendor   string `json:"vendor"`
+	Name     string `json:"name"`
+	MemoryMB int64  `json:"memory_mb,omitempty"` // VRAM; system memory for unified memory
+	Driver   string `json:"driver,omitempty"`
+}
+
+// detectGPUs returns the GPUs of the host: NVIDIA through nvidia-smi, AMD
+// through sysfs on Linux and the integrated GPU of Apple Silicon
+func detectGPUs() []GPU {
+	var gpus []GPU
+	if _, err := exec.LookPath("nvidia-smi"); err == nil {
+		out, err := exec.Command("nvidia-smi", "--query-gpu=name,memory.total,driver_version", "--format=csv,noheader,nounits").Output()
+		if err == nil {
+			gpus = append(gpus, parseNvidiaSMI(string(out))...)
+		}
+	}
+	if runtime.GOOS == "linux" {
+		gpus = append(gpus, detectAMDGPUs("/sys/class/drm")...)
+	}
+	if runtime.GOOS == "darwin" && runtime.GOARCH == "arm64" {
+		gpu := GPU{Vendor: VendorApple, Name: "Apple Silicon (Metal)"}
+		if out, err := exec.Command("sysctl", "-n", "hw.memsize").Output(); err == nil {
+			if bytes, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64); err == nil {
+				gpu.MemoryMB = bytes / (1024 * 1024)
+			}
+		}
+		gpus = append(gpus, gpu)
+	}
+	return gpus
+}
+
+// parseNvidiaSMI parses the CSV output of nvidia-smi --query-gpu=name,memory.total,driver_version
+func parseNvidiaSMI(output string) []GPU {
+	var gpus []GPU
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			continue
+		}
+		memory, _ := strconv.ParseInt(strings.TrimSpace(fields[1]), 10, 64)
+		gpus = append(gpus, GPU{
+			Vendor:   VendorNVIDIA,
+			Name:     strings.TrimSpace(fields[0]),
+			MemoryMB: memory,
+			Driver:   strings.TrimSpace(fields[2]),
+		})
+	}
+	return gpus
+}
+
+// detectAMDGPUs finds AMD cards with dedicated VRAM in the DRM devices;
+// integrated graphics report no VRAM and are left to the CPU builds
+func detectAMDGPUs(drmDir string) []GPU {
+	var gpus []GPU
+	cards, _ := filepath.Glob(filepath.Join(drmDir, "card[0-9]*"))
+	for _, card := range cards {
+		if strings.Contains(filepath.Base(card), "-") {
+			continue // Connectors such as card0-HDMI-A-1
+		}
+		vendor, err := os.ReadFile(filepath.Join(card, "device", "vendor"))
+		if err != nil || strings.TrimSpace(string(vendor)) != "0x1002" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(card, "device", "mem_info_vram_total"))
+		if err != nil {
+			continue
+		}
+		vram, _ := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		if vram < 1024*1024*1024 {
+			continue
+		}
+		name := "AMD Radeon"
+		if product, err := os.ReadFile(filepath.Join(card, "device", "product_name")); err == nil && len(strings.TrimSpace(string(product))) > 0 {
+			name = strings.TrimSpace(string(product))
+		} else if device, err := os.ReadFile(filepath.Join(card, "device", "device")); err == nil {
+			name += " (" + strings.TrimSpace(string(device)) + ")"
+		}
+		gpus = append(gpus, GPU{Vendor: VendorAMD, Name: name, MemoryMB: vram / (1024 * 1024)})
+	}
+	return gpus
+}
+
+// recommendedVariant returns the llama.cpp build for the GPUs of a platform.
+// llama.cpp publishes CUDA and HIP builds for Windows only, Linux gets the
+// Vulkan build, which runs on NVIDIA and AMD cards alike.
+func recommendedVariant(gpus []GPU, goos string) string {
+	vendors := make(map[string]bool)
+	for _, gpu := range gpus {
+		vendors[gpu.Vendor] = true
+	}
+	switch {
+	case goos == "darwin":
+		return VariantMetal
+	case vendors[VendorNVIDIA] && goos == "windows":
+		return VariantCUDA
+	case vendors[VendorAMD] && goos == "windows":
+		return VariantHIP
+	case vendors[VendorNVIDIA] || vendors[VendorAMD]:
+		return VariantVulkan
+	}
+	return VariantCPU
+}
+
+// hasGPU reports whether inference can be offloaded to a GPU
+func hasGPU(gpus []GPU) bool {
+	return len(gpus) > 0
+}
+
+func handleGPU(args []string) error {
+	_, flags, err := parseArgs(args, "json")
+	if err != nil {
+		return err
+	}
+	gpus := detectGPUs()
+	variant := recommendedVariant(gpus, runtime.GOOS)
+
+	if flags["json"] != "" {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"gpus":              gpus,
+			"llama_cpp_variant": variant,
+		}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(gpus) == 0 {
+		fmt.Println("No GPU detected, inference runs on the CPU")
+	}
+	for _, gpu := range gpus {
+		line := fmt.Sprintf("🎮 %s (%s)", gpu.Name, gpu.Vendor)
+		if gpu.MemoryMB > 0 {
+			line += fmt.Sprintf(", %.1f GB", float64(gpu.MemoryMB)/1024)
+		}
+		if gpu.Driver != "" {
+			line += ", driver " + gpu.Driver
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+	fmt.Printf("llama.cpp build: %s\n", variant)
+	if len(gpus) > 0 {
+		fmt.Println("Ollama:          GPU support included")
+	}
+	for _, gpu := range gpus {
+		if gpu.Vendor == VendorAMD && runtime.GOOS == "linux" {
+			fmt.Println("                 ROCm libraries are installed along with Ollama")
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import "portunix.ai/app/aihelp"
+
+// showHelpAI prints machine-readable help for --help-ai
+func showHelpAI() {
+	model := aihelp.Argument{Name: "model", Type: "string", Required: true, Description: "Name of a downloaded GGUF model or an Ollama model"}
+	jsonFlag := aihelp.Flag{Name: "json", Type: "boolean", Description: "Output JSON"}
+	backends := []string{BackendOllama, BackendLlamaCpp}
+
+	aihelp.Help{
+		Tool:        "ptx-ai",
+		Version:     version,
+		Description: "Local LLM tooling: Ollama and llama.cpp installation, verified model downloads, GPU detection and OpenAI-compatible servers",
+		Commands: []aihelp.Command{
+			{Name: "ai gpu", Description: "Show detected GPUs and the matching llama.cpp build", Flags: []aihelp.Flag{jsonFlag}},
+			{
+				Name:        "ai install",
+				Description: "Install a backend release into ~/.portunix/ai/backends, verifying its SHA-256",
+				Arguments:   []aihelp.Argument{{Name: "backend", Type: "string", Required: true, Choices: backends, Description: "Backend to install"}},
+				Flags: []aihelp.Flag{
+					{Name: "version", Type: "string", Default: "latest", Description: "Release tag, e.g. v0.12.3 or b6000"},
+					{Name: "variant", Type: "string", Description: "Build variant (default: detected from the GPU)", Choices: []string{VariantCPU, VariantCUDA, VariantVulkan, VariantHIP, VariantMetal}},
+				},
+				Examples: []string{"portunix ai install llama.cpp", "portunix ai install ollama"},
+			},
+			{
+				Name:        "ai pull",
+				Description: "Download a GGUF model with SHA-256 verification, or pull an Ollama model through the running Ollama server",
+				Arguments:   []aihelp.Argument{{Name: "model", Type: "string", Required: true, Description: "hf:<owner>/<repo>/<file>.gguf, a URL or an Ollama model name"}},
+				Flags: []aihelp.Flag{
+					{Name: "name", Type: "string", Description: "Model name (default: file name without .gguf)"},
+					{Name: "sha256", Type: "string", Description: "Expected SHA-256; required for URLs outside Hugging Face"},
+				},
+				Examples: []string{"portunix ai pull hf:Qwen/Qwen2.5-Coder-7B-Instruct-GGUF/qwen2.5-coder-7b-instruct-q4_k_m.gguf", "portunix ai pull llama3.2:3b"},
+			},
+			{Name: "ai list", Aliases: []string{"ls"}, Description: "List installed backends, GGUF models and Ollama models", Flags: []aihelp.Flag{jsonFlag}},
+			{Name: "ai verify", Description: "Check the SHA-256 of downloaded models", Arguments: []aihelp.Argument{{Name: "model", Type: "string", Description: "Model to check (default: all)"}}},
+			{Name: "ai remove", Aliases: []string{"rm"}, Description: "Remove a GGUF or Ollama model", Arguments: []aihelp.Argument{model}},
+			{
+				Name:        "ai serve",
+				Description: "Run an OpenAI-compatible server in the foreground: llama.cpp for GGUF models, Ollama otherwise",
+				Arguments:   []aihelp.Argument{{Name: "model", Type: "string", Description: "Model to serve; required for llama.cpp"}},
+				Flags: []aihelp.Flag{
+					{Name: "backend", Type: "string", Choices: backends, Description: "Backend (default: from the model)"},
+					{Name: "host", Type: "string", Default: "127.0.0.1", Description: "Listen address"},
+					{Name: "port", Type: "integer", Description: "Listen port (default: 11434 for Ollama, 8080 for llama.cpp)"},
+					{Name: "gpu-layers", Type: "integer", Description: "Layers offloaded to the GPU (default: all when a GPU is detected)"},
+					{Name: "ctx-size", Type: "integer", Description: "Context size in tokens (default: from the model)"},
+				},
+				Examples: []string{"portunix ai serve qwen2.5-coder-7b-instruct-q4_k_m", "portunix ai serve --backend ollama --port 11500"},
+			},
+			{Name: "ai status", Description: "Show the running server and its OpenAI-compatible endpoint; helpers use --json to find a backend", Flags: []aihelp.Flag{jsonFlag}},
+		},
+	}.Print()
+}
@@ -0,0 +1,190 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var version = "dev"
+
+// rootCmd represents the base command for ptx-ai
+var rootCmd = &cobra.Command{
+	Use:   "ptx-ai",
+	Short: "Portunix Local AI Tooling Helper",
+	Long: `ptx-ai is a helper binary for Portunix that manages local LLM tooling on the host.
+It installs Ollama and llama.cpp builds matching the GPU, downloads models with
+checksum verification and serves them through an OpenAI-compatible endpoint that
+other helpers discover with 'portunix ai status --json'.
+
+This binary is typically invoked by the main portunix dispatcher and should not be used directly.
+
+Supported features:
+- GPU detection (NVIDIA, AMD, Apple Silicon)
+- Ollama and llama.cpp installation into ~/.portunix/ai/backends
+- GGUF model downloads from Hugging Face or URLs with SHA-256 verification
+- Ollama model pulls
+- Local inference servers with endpoint discovery`,
+	Version:            version,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		handleCommand(args)
+	},
+}
+
+// handleCommand dispatches the "ai" command routed to this helper by the
+// parent portunix binary (see src/dispatcher/dispatcher.go), plus the discovery
+// meta-flags --version, --description, and --list-commands used by the
+// dispatcher. args arrive without the binary name prefix.
+func handleCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Println("No command specified")
+		return
+	}
+
+	command := args[0]
+	subArgs := args[1:]
+
+	switch command {
+	case "ai":
+		if len(subArgs) == 0 {
+			showAIHelp()
+		} else {
+			handleAICommand(subArgs)
+		}
+	case "--version":
+		fmt.Printf("ptx-ai version %s\n", version)
+	case "--description":
+		fmt.Println("Portunix Local AI Tooling Helper")
+	case "--list-commands":
+		fmt.Println("ai")
+	case "--help-ai":
+		showHelpAI()
+	default:
+		fmt.Printf("Unknown command: %s\n", command)
+		fmt.Println("Supported commands: ai")
+	}
+}
+
+func showAIHelp() {
+	fmt.Println("Usage: portunix ai [subcommand]")
+	fmt.Println()
+	fmt.Println("Local AI Tooling Commands:")
+	fmt.Println()
+	fmt.Println("Backends:")
+	fmt.Println("  gpu [--json]                 - Show detected GPUs and the matching backend builds")
+	fmt.Println("  install <ollama|llama.cpp> [--version V] [--variant cpu|cuda|vulkan|hip|metal]")
+	fmt.Println("                               - Install a backend into ~/.portunix/ai/backends")
+	fmt.Println()
+	fmt.Println("Models:")
+	fmt.Println("  pull <model> [--name N] [--sha256 S]")
+	fmt.Println("                               - Download a GGUF model (hf:<owner>/<repo>/<file>.gguf or URL)")
+	fmt.Println("                                 or pull an Ollama model (llama3.2:3b)")
+	fmt.Println("  list [--json]                - List backends and models")
+	fmt.Println("  verify [model]               - Check the SHA-256 of downloaded models")
+	fmt.Println("  remove <model>               - Remove a model")
+	fmt.Println()
+	fmt.Println("Serving:")
+	fmt.Println("  serve [model] [--backend ollama|llama.cpp] [--host H] [--port P]")
+	fmt.Println("        [--gpu-layers N] [--ctx-size N]")
+	fmt.Println("                               - Run an OpenAI-compatible server in the foreground")
+	fmt.Println("  status [--json]              - Show the running server and its endpoint")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix ai install llama.cpp")
+	fmt.Println("  portunix ai pull hf:Qwen/Qwen2.5-Coder-7B-Instruct-GGUF/qwen2.5-coder-7b-instruct-q4_k_m.gguf")
+	fmt.Println("  portunix ai serve qwen2.5-coder-7b-instruct-q4_k_m")
+	fmt.Println("  portunix ai install ollama && portunix ai serve")
+	fmt.Println("  portunix ai pull llama3.2:3b")
+}
+
+func handleAICommand(args []string) {
+	subcommand := args[0]
+	subArgs := args[1:]
+
+	var err error
+	exitCode := 0
+
+	switch subcommand {
+	case "gpu":
+		err = handleGPU(subArgs)
+	case "install":
+		err = handleInstall(subArgs)
+	case "pull":
+		err = handlePull(subArgs)
+	case "list", "ls":
+		err = handleList(subArgs)
+	case "verify":
+		err = handleVerify(subArgs)
+	case "remove", "rm":
+		err = handleRemove(subArgs)
+	case "serve":
+		exitCode, err = handleServe(subArgs)
+	case "status":
+		err = handleStatus(subArgs)
+	case "--help", "-h":
+		showAIHelp()
+	case "--help-ai":
+		showHelpAI()
+	default:
+		fmt.Printf("Unknown ai subcommand: %s\n", subcommand)
+		fmt.Println("Run 'portunix ai --help' for available commands")
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(exitCode)
+}
+
+// parseArgs splits positional arguments from --flag value options;
+// boolean lists the flags taking no value
+func parseArgs(args []string, boolean ...string) ([]string, map[string]string, error) {
+	var positional []string
+	flags := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+		name := strings.TrimPrefix(arg, "--")
+		if name, value, found := strings.Cut(name, "="); found {
+			flags[name] = value
+			continue
+		}
+		isBool := false
+		for _, b := range boolean {
+			isBool = isBool || b == name
+		}
+		if isBool {
+			flags[name] = "true"
+			continue
+		}
+		if i+1 >= len(args) {
+			return nil, nil, fmt.Errorf("%s requires a value", arg)
+		}
+		flags[name] = args[i+1]
+		i++
+	}
+	return positional, flags, nil
+}
+
+func init() {
+	rootCmd.SetVersionTemplate("ptx-ai version {{.Version}}\n")
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
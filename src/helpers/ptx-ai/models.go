@@ -0,0 +1,512 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"portunix.ai/app/fileutil"
+	"portunix.ai/app/progress"
+)
+
+// Model is a GGUF model downloaded by 'portunix ai pull'
+type Model struct {
+	Name   string    `json:"name"`
+	File   string    `json:"file"`
+	Source string    `json:"source"`
+	SHA256 string    `json:"sha256"`
+	Size   int64     `json:"size"`
+	Pulled time.Time `json:"pulled"`
+}
+
+// modelSource is where a GGUF model is downloaded from. Hugging Face
+// models carry their checksum in the repository metadata.
+type modelSource struct {
+	URL      string
+	File     string
+	Repo     string // Hugging Face repository, e.g. Qwen/Qwen2.5-7B-Instruct-GGUF
+	Revision string
+	Path     string // File within the repository
+}
+
+// hfEndpoint returns the Hugging Face hub, HF_ENDPOINT as the hub tools use it
+func hfEndpoint() string {
+	if endpoint := os.Getenv("HF_ENDPOINT"); endpoint != "" {
+		return strings.TrimSuffix(endpoint, "/")
+	}
+	return "https://huggingface.co"
+}
+
+// hfHeader authenticates Hugging Face requests with HF_TOKEN, needed for
+// gated models
+func hfHeader() http.Header {
+	header := http.Header{}
+	if token := os.Getenv("HF_TOKEN"); token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return header
+}
+
+// parseModelRef parses a GGUF model reference: hf:<owner>/<repo>/<file>,
+// a Hugging Face resolve URL or any other URL. ok is false for other
+// references, which name Ollama models.
+func parseModelRef(ref string) (src *modelSource, ok bool, err error) {
+	if rest, found := strings.CutPrefix(ref, "hf:"); found {
+		parts := strings.SplitN(rest, "/", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, true, fmt.Errorf("invalid model %q (expected hf:<owner>/<repo>/<file>.gguf)", ref)
+		}
+		repo, revision := parts[0]+"/"+parts[1], "main"
+		if i := strings.LastIndex(repo, "@"); i > 0 {
+			repo, revision = repo[:i], repo[i+1:]
+		}
+		return &modelSource{
+			URL:      hfEndpoint() + "/" + repo + "/resolve/" + revision + "/" + parts[2],
+			File:     path.Base(parts[2]),
+			Repo:     repo,
+			Revision: revision,
+			Path:     parts[2],
+		}, true, nil
+	}
+	if !strings.HasPrefix(ref, "http://") && !strings.HasPrefix(ref, "https://") {
+		return nil, false, nil
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid model URL %q: %v", ref, err)
+	}
+	src = &modelSource{URL: ref, File: path.Base(u.Path)}
+	// <hub>/<owner>/<repo>/resolve/<revision>/<path>
+	if strings.HasPrefix(ref, hfEndpoint()+"/") {
+		parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 5)
+		if len(parts) == 5 && parts[2] == "resolve" {
+			src.Repo, src.Revision, src.Path = parts[0]+"/"+parts[1], parts[3], parts[4]
+		}
+	}
+	if src.File == "" || src.File == "/" || src.File == "." {
+		return nil, true, fmt.Errorf("model URL %q names no file", ref)
+	}
+	return src, true, nil
+}
+
+// hfChecksum returns the SHA-256 Hugging Face records for a file stored in
+// LFS, as all GGUF files are
+func hfChecksum(src *modelSource) (string, error) {
+	dir := path.Dir(src.Path)
+	api := hfEndpoint() + "/api/models/" + src.Repo + "/tree/" + url.PathEscape(src.Revision)
+	if dir != "." {
+		api += "/" + dir
+	}
+	req, err := http.NewRequest("GET", api, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header = hfHeader()
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up %s: %v", src.Repo, err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "", fmt.Errorf("%s is private or gated: set HF_TOKEN to an access token", src.Repo)
+	case http.StatusNotFound:
+		return "", fmt.Errorf("%s (revision %s) not found on %s", src.Repo, src.Revision, hfEndpoint())
+	default:
+		return "", fmt.Errorf("failed to look up %s: HTTP %d", src.Repo, resp.StatusCode)
+	}
+
+	var entries []struct {
+		Path string `json:"path"`
+		LFS  *struct {
+			OID string `json:"oid"`
+		} `json:"lfs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return "", fmt.Errorf("invalid file list of %s: %v", src.Repo, err)
+	}
+	for _, e := range entries {
+		if e.Path != src.Path {
+			continue
+		}
+		if e.LFS == nil {
+			return "", fmt.Errorf("%s is not stored in LFS, pass its checksum with --sha256", src.Path)
+		}
+		return e.LFS.OID, nil
+	}
+	return "", fmt.Errorf("%s not found in %s", src.Path, src.Repo)
+}
+
+// modelsDir returns the directory of the downloaded models
+func modelsDir() (string, error) {
+	dir, err := aiDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "models"), nil
+}
+
+// loadModels reads the downloaded models, sorted by name
+func loadModels() ([]*Model, error) {
+	dir, err := modelsDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "models.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var models []*Model
+	if err := json.Unmarshal(data, &models); err != nil {
+		return nil, fmt.Errorf("invalid model list: %v", err)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+	return models, nil
+}
+
+// saveModels writes the downloaded models
+func saveModels(models []*Model) error {
+	dir, err := modelsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(models, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFile(filepath.Join(dir, "models.json"), data, 0644)
+}
+
+// findModel returns a downloaded model by name or file name
+func findModel(models []*Model, name string) *Model {
+	for _, m := range models {
+		if m.Name == name || m.File == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// modelPath returns the file of a downloaded model
+func modelPath(m *Model) (string, error) {
+	dir, err := modelsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, m.File), nil
+}
+
+// pullGGUF downloads a GGUF model, verifying it against sha or the
+// checksum of its Hugging Face repository
+func pullGGUF(src *modelSource, name, sha string) (*Model, error) {
+	if sha == "" && src.Repo != "" {
+		var err error
+		if sha, err = hfChecksum(src); err != nil {
+			return nil, err
+		}
+	}
+	if sha == "" {
+		return nil, fmt.Errorf("no checksum known for %s: pass the SHA-256 of the file with --sha256", src.URL)
+	}
+	if name == "" {
+		name = strings.TrimSuffix(src.File, ".gguf")
+	}
+
+	dir, err := modelsDir()
+	if err != nil {
+		return nil, err
+	}
+	models, err := loadModels()
+	if err != nil {
+		return nil, err
+	}
+	if existing := findModel(models, name); existing != nil && existing.File != src.File {
+		return nil, fmt.Errorf("model %s is already taken by %s, choose another --name", name, existing.Source)
+	}
+
+	fmt.Printf("📥 Downloading %s...\n", src.File)
+	dest := filepath.Join(dir, src.File)
+	var header http.Header
+	if src.Repo != "" {
+		header = hfHeader()
+	}
+	if err := downloadVerified(src.URL, dest, sha, header); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Model{Name: name, File: src.File, Source: src.URL, SHA256: strings.ToLower(sha), Size: info.Size(), Pulled: time.Now()}
+	kept := []*Model{m}
+	for _, other := range models {
+		if other.Name != name && other.File != src.File {
+			kept = append(kept, other)
+		}
+	}
+	return m, saveModels(kept)
+}
+
+// ollamaHost returns the address of the Ollama server, OLLAMA_HOST as
+// Ollama itself reads it
+func ollamaHost() string {
+	host := os.Getenv("OLLAMA_HOST")
+	if host == "" {
+		host = "127.0.0.1:11434"
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return strings.TrimSuffix(host, "/")
+}
+
+// ollamaModel is a model the Ollama server reports
+type ollamaModel struct {
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified_at"`
+}
+
+// ollamaModels lists the models of the running Ollama server
+func ollamaModels() ([]ollamaModel, error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(ollamaHost() + "/api/tags")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ollama returned HTTP %d", resp.StatusCode)
+	}
+	var tags struct {
+		Models []ollamaModel `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, err
+	}
+	return tags.Models, nil
+}
+
+// runOllama runs an ollama client command against the running server;
+// Ollama verifies the layers it pulls itself
+func runOllama(args ...string) error {
+	binary, err := backendBinary(BackendOllama)
+	if err != nil {
+		return err
+	}
+	if _, err := ollamaModels(); err != nil {
+		return fmt.Errorf("Ollama is not running at %s: start it with 'portunix ai serve'", ollamaHost())
+	}
+	cmd := exec.Command(binary, args...)
+	cmd.Env = append(os.Environ(), "OLLAMA_HOST="+ollamaHost())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func handlePull(args []string) error {
+	positional, flags, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: portunix ai pull <hf:<owner>/<repo>/<file>.gguf | URL | ollama-model> [--name N] [--sha256 S]")
+	}
+
+	src, gguf, err := parseModelRef(positional[0])
+	if err != nil {
+		return err
+	}
+	if !gguf {
+		if err := runOllama("pull", positional[0]); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Ollama model %s pulled\n", positional[0])
+		return nil
+	}
+
+	m, err := pullGGUF(src, flags["name"], flags["sha256"])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Model %s (%s) ready\n", m.Name, progress.FormatBytes(m.Size))
+	fmt.Printf("   Serve it: portunix ai serve %s\n", m.Name)
+	return nil
+}
+
+func handleList(args []string) error {
+	_, flags, err := parseArgs(args, "json")
+	if err != nil {
+		return err
+	}
+	var backends []*Backend
+	for _, name := range []string{BackendLlamaCpp, BackendOllama} {
+		b, err := loadBackend(name)
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			if path, err := exec.LookPath(backendSpecs[name].Binary); err == nil {
+				b = &Backend{Name: name, Version: "system", Binary: path}
+			}
+		}
+		if b != nil {
+			backends = append(backends, b)
+		}
+	}
+	models, err := loadModels()
+	if err != nil {
+		return err
+	}
+	ollama, ollamaErr := ollamaModels()
+
+	if flags["json"] != "" {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"backends":      backends,
+			"models":        models,
+			"ollama_models": ollama,
+		}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Println("Backends:")
+	if len(backends) == 0 {
+		fmt.Println("  none (portunix ai install llama.cpp | ollama)")
+	}
+	for _, b := range backends {
+		variant := ""
+		if b.Variant != "" {
+			variant = " (" + b.Variant + ")"
+		}
+		fmt.Printf("  %-10s %-10s %s%s\n", b.Name, b.Version, b.Binary, variant)
+	}
+	fmt.Println()
+	fmt.Println("GGUF models:")
+	if len(models) == 0 {
+		fmt.Println("  none (portunix ai pull hf:<owner>/<repo>/<file>.gguf)")
+	}
+	for _, m := range models {
+		fmt.Printf("  %-40s %10s  sha256:%s\n", m.Name, progress.FormatBytes(m.Size), m.SHA256[:12])
+	}
+	fmt.Println()
+	if ollamaErr != nil {
+		fmt.Printf("Ollama models: server not running at %s\n", ollamaHost())
+		return nil
+	}
+	fmt.Println("Ollama models:")
+	if len(ollama) == 0 {
+		fmt.Println("  none (portunix ai pull llama3.2:3b)")
+	}
+	for _, m := range ollama {
+		fmt.Printf("  %-40s %10s\n", m.Name, progress.FormatBytes(m.Size))
+	}
+	return nil
+}
+
+func handleVerify(args []string) error {
+	positional, _, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+	models, err := loadModels()
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		m := findModel(models, positional[0])
+		if m == nil {
+			return fmt.Errorf("model %s not found (see 'portunix ai list')", positional[0])
+		}
+		models = []*Model{m}
+	}
+
+	failed := 0
+	for _, m := range models {
+		file, err := modelPath(m)
+		if err != nil {
+			return err
+		}
+		sum, err := fileSHA256(file)
+		switch {
+		case err != nil:
+			fmt.Printf("❌ %s: %v\n", m.Name, err)
+			failed++
+		case sum != m.SHA256:
+			fmt.Printf("❌ %s: checksum mismatch (expected %s, got %s)\n", m.Name, m.SHA256, sum)
+			failed++
+		default:
+			fmt.Printf("✅ %s\n", m.Name)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d models failed verification, pull them again", failed, len(models))
+	}
+	return nil
+}
+
+func handleRemove(args []string) error {
+	positional, _, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: portunix ai remove <model>")
+	}
+	name := positional[0]
+
+	models, err := loadModels()
+	if err != nil {
+		return err
+	}
+	m := findModel(models, name)
+	if m == nil {
+		// Not a GGUF model, so an Ollama one
+		if _, err := ollamaModels(); err != nil {
+			return fmt.Errorf("model %s not found (see 'portunix ai list')", name)
+		}
+		return runOllama("rm", name)
+	}
+
+	file, err := modelPath(m)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var kept []*Model
+	for _, other := range models {
+		if other != m {
+			kept = append(kept, other)
+		}
+	}
+	if err := saveModels(kept); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Model %s removed\n", m.Name)
+	return nil
+}
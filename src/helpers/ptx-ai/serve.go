@@ -0,0 +1,264 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"portunix.ai/app/fileutil"
+)
+
+// Default ports of the backend servers
+const (
+	defaultOllamaPort   = 11434
+	defaultLlamaCppPort = 8080
+)
+
+// ServeState describes the server 'portunix ai serve' runs. It is written
+// to ~/.portunix/ai/serve.json while the server runs, so other helpers find
+// the endpoint with 'portunix ai status --json'.
+type ServeState struct {
+	Running  bool       `json:"running"`
+	Backend  string     `json:"backend,omitempty"`
+	Model    string     `json:"model,omitempty"`
+	Endpoint string     `json:"endpoint,omitempty"` // OpenAI-compatible base URL
+	PID      int        `json:"pid,omitempty"`
+	Started  *time.Time `json:"started,omitempty"`
+}
+
+// serveStateFile returns the file describing the running server
+func serveStateFile() (string, error) {
+	dir, err := aiDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "serve.json"), nil
+}
+
+// openAIEndpoint returns the OpenAI-compatible base URL of a server
+// listening on host:port
+func openAIEndpoint(host string, port int) string {
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(port)) + "/v1"
+}
+
+// endpointReady reports whether an OpenAI-compatible endpoint answers
+func endpointReady(endpoint string) bool {
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Get(endpoint + "/models")
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// currentServer returns the running server: the one started by 'portunix
+// ai serve', or else an Ollama server running on its own
+func currentServer() ServeState {
+	if path, err := serveStateFile(); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			var state ServeState
+			if json.Unmarshal(data, &state) == nil && endpointReady(state.Endpoint) {
+				state.Running = true
+				return state
+			}
+		}
+	}
+	if _, err := ollamaModels(); err == nil {
+		return ServeState{Running: true, Backend: BackendOllama, Endpoint: ollamaHost() + "/v1"}
+	}
+	return ServeState{}
+}
+
+// serverCommand builds the command serving a model. A GGUF model is served
+// by llama.cpp, Ollama serves its own models.
+func serverCommand(backend, model, host string, port, gpuLayers, ctxSize int) (*exec.Cmd, string, error) {
+	binary, err := backendBinary(backend)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch backend {
+	case BackendOllama:
+		if port == 0 {
+			port = defaultOllamaPort
+		}
+		cmd := exec.Command(binary, "serve")
+		cmd.Env = append(os.Environ(), "OLLAMA_HOST="+net.JoinHostPort(host, strconv.Itoa(port)))
+		return cmd, openAIEndpoint(host, port), nil
+
+	case BackendLlamaCpp:
+		if model == "" {
+			return nil, "", fmt.Errorf("llama.cpp serves a GGUF model: portunix ai serve <model>")
+		}
+		file, name := model, strings.TrimSuffix(filepath.Base(model), ".gguf")
+		if _, err := os.Stat(model); err != nil || !strings.HasSuffix(model, ".gguf") {
+			models, err := loadModels()
+			if err != nil {
+				return nil, "", err
+			}
+			m := findModel(models, model)
+			if m == nil {
+				return nil, "", fmt.Errorf("model %s not found (pull it with 'portunix ai pull hf:<owner>/<repo>/<file>.gguf')", model)
+			}
+			if file, err = modelPath(m); err != nil {
+				return nil, "", err
+			}
+			name = m.Name
+		}
+		if port == 0 {
+			port = defaultLlamaCppPort
+		}
+		args := []string{"-m", file, "--alias", name, "--host", host, "--port", strconv.Itoa(port)}
+		if gpuLayers < 0 {
+			// Offload all layers when the build and the host have a GPU;
+			// llama.cpp keeps what does not fit in VRAM on the CPU
+			gpuLayers = 0
+			if b, _ := loadBackend(BackendLlamaCpp); (b == nil || b.Variant != VariantCPU) && hasGPU(detectGPUs()) {
+				gpuLayers = 999
+			}
+		}
+		args = append(args, "--n-gpu-layers", strconv.Itoa(gpuLayers))
+		if ctxSize > 0 {
+			args = append(args, "--ctx-size", strconv.Itoa(ctxSize))
+		}
+		return exec.Command(binary, args...), openAIEndpoint(host, port), nil
+	}
+	return nil, "", fmt.Errorf("unknown backend %q (supported: %s, %s)", backend, BackendOllama, BackendLlamaCpp)
+}
+
+// serveBackend picks the backend of a model: llama.cpp for GGUF models and
+// files, Ollama for everything else
+func serveBackend(model string) string {
+	if model == "" {
+		return BackendOllama
+	}
+	if strings.HasSuffix(model, ".gguf") {
+		return BackendLlamaCpp
+	}
+	if models, err := loadModels(); err == nil && findModel(models, model) != nil {
+		return BackendLlamaCpp
+	}
+	return BackendOllama
+}
+
+func handleServe(args []string) (int, error) {
+	positional, flags, err := parseArgs(args)
+	if err != nil {
+		return 0, err
+	}
+	if len(positional) > 1 {
+		return 0, fmt.Errorf("usage: portunix ai serve [model] [--backend ollama|llama.cpp] [--host H] [--port P]")
+	}
+	model := ""
+	if len(positional) == 1 {
+		model = positional[0]
+	}
+	backend := flags["backend"]
+	if backend == "" {
+		backend = serveBackend(model)
+	}
+	host := flags["host"]
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	numbers := map[string]int{"port": 0, "gpu-layers": -1, "ctx-size": 0}
+	for name := range numbers {
+		if value, ok := flags[name]; ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid --%s %q", name, value)
+			}
+			numbers[name] = n
+		}
+	}
+
+	if running := currentServer(); running.Running {
+		return 0, fmt.Errorf("%s is already serving at %s", running.Backend, running.Endpoint)
+	}
+	cmd, endpoint, err := serverCommand(backend, model, host, numbers["port"], numbers["gpu-layers"], numbers["ctx-size"])
+	if err != nil {
+		return 0, err
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	fmt.Printf("🚀 Starting %s at %s (Ctrl+C to stop)\n", backend, endpoint)
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start %s: %v", backend, err)
+	}
+
+	statePath, err := serveStateFile()
+	if err != nil {
+		return 0, err
+	}
+	started := time.Now()
+	state := ServeState{Backend: backend, Model: model, Endpoint: endpoint, PID: cmd.Process.Pid, Started: &started}
+	data, _ := json.MarshalIndent(state, "", "  ")
+	os.MkdirAll(filepath.Dir(statePath), 0755)
+	if err := fileutil.WriteFile(statePath, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record the server: %v\n", err)
+	}
+	defer os.Remove(statePath)
+
+	// The server stops on Ctrl+C; keep running until it has, to clean up
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		for sig := range signals {
+			cmd.Process.Signal(sig)
+		}
+	}()
+
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	return 0, err
+}
+
+func handleStatus(args []string) error {
+	_, flags, err := parseArgs(args, "json")
+	if err != nil {
+		return err
+	}
+	state := currentServer()
+	if flags["json"] != "" {
+		data, _ := json.MarshalIndent(state, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if !state.Running {
+		fmt.Println("No AI server running (start one with 'portunix ai serve')")
+		return nil
+	}
+	fmt.Printf("✅ %s is serving\n", state.Backend)
+	if state.Model != "" {
+		fmt.Printf("   Model:    %s\n", state.Model)
+	}
+	fmt.Printf("   Endpoint: %s (OpenAI-compatible)\n", state.Endpoint)
+	if state.PID != 0 && state.Started != nil {
+		fmt.Printf("   PID:      %d, since %s\n", state.PID, state.Started.Format(time.RFC3339))
+	}
+	return nil
+}
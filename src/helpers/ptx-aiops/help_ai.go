@@ -0,0 +1,74 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import "portunix.ai/app/aihelp"
+
+// showHelpAI prints machine-readable help for --help-ai
+func showHelpAI() {
+	model := aihelp.Argument{Name: "name", Type: "string", Required: true, Description: "Model name (e.g. llama3.2)"}
+	container := aihelp.Flag{Name: "container", Type: "string", Default: OllamaContainerName, Description: "Ollama container to use"}
+	cpu := aihelp.Flag{Name: "cpu", Type: "boolean", Description: "Force CPU-only mode"}
+
+	aihelp.Help{
+		Tool:        "ptx-aiops",
+		Version:     version,
+		Description: "GPU monitoring and local AI stack (Ollama, Open WebUI) in containers",
+		Commands: []aihelp.Command{
+			{
+				Name:        "aiops gpu status",
+				Description: "Show GPU status and driver info",
+				Flags: []aihelp.Flag{
+					{Name: "watch", Shorthand: "w", Type: "boolean", Description: "Real-time monitoring"},
+					{Name: "interval", Type: "integer", Default: "5", Description: "Refresh interval in seconds for --watch"},
+				},
+				Examples: []string{"portunix aiops gpu status --watch --interval 2"},
+			},
+			{Name: "aiops gpu usage", Description: "Show GPU utilization summary"},
+			{Name: "aiops gpu processes", Description: "List processes using the GPU"},
+			{Name: "aiops gpu check", Description: "Verify GPU and container toolkit readiness"},
+			{Name: "aiops ollama container create", Description: "Create Ollama container (with GPU if available)", Flags: []aihelp.Flag{cpu}},
+			{Name: "aiops ollama container status", Description: "Show Ollama container status"},
+			{Name: "aiops ollama container start", Description: "Start stopped Ollama container"},
+			{Name: "aiops ollama container stop", Description: "Stop running Ollama container"},
+			{Name: "aiops ollama container remove", Description: "Remove Ollama container"},
+			{
+				Name:        "aiops model list",
+				Description: "List installed models",
+				Flags:       []aihelp.Flag{{Name: "available", Type: "boolean", Description: "List models available in the Ollama registry"}, container},
+			},
+			{Name: "aiops model install", Description: "Install a model into the container", Arguments: []aihelp.Argument{model}, Flags: []aihelp.Flag{container}, Examples: []string{"portunix aiops model install llama3.2"}},
+			{Name: "aiops model info", Description: "Show model details", Arguments: []aihelp.Argument{model}, Flags: []aihelp.Flag{container}},
+			{
+				Name:        "aiops model remove",
+				Description: "Remove a model from the container",
+				Arguments:   []aihelp.Argument{model},
+				Flags:       []aihelp.Flag{container, {Name: "force", Shorthand: "f", Type: "boolean", Description: "Do not ask for confirmation"}},
+			},
+			{
+				Name:        "aiops model run",
+				Description: "Chat with a model (interactive without --prompt)",
+				Arguments:   []aihelp.Argument{model},
+				Flags:       []aihelp.Flag{container, {Name: "prompt", Type: "string", Description: "Single prompt to answer"}},
+			},
+			{Name: "aiops webui container create", Description: "Create Open WebUI container"},
+			{Name: "aiops webui container status", Description: "Show WebUI container status"},
+			{Name: "aiops webui container start", Description: "Start stopped WebUI container"},
+			{Name: "aiops webui container stop", Description: "Stop running WebUI container"},
+			{Name: "aiops webui container remove", Description: "Remove WebUI container"},
+			{Name: "aiops webui open", Description: "Open WebUI in the browser"},
+			{
+				Name:        "aiops stack create",
+				Description: "Create the full stack (Ollama + Open WebUI)",
+				Flags:       []aihelp.Flag{{Name: "models", Type: "string", Description: "Comma-separated models to install"}, cpu},
+				Examples:    []string{"portunix aiops stack create --models llama3.2,qwen2.5"},
+			},
+			{Name: "aiops stack status", Description: "Show status of all stack containers"},
+			{Name: "aiops stack start", Description: "Start all stack containers"},
+			{Name: "aiops stack stop", Description: "Stop all stack containers"},
+			{Name: "aiops stack remove", Description: "Remove all stack containers"},
+		},
+	}.Print()
+}
@@ -59,6 +59,8 @@ func handleCommand(args []string) {
 		fmt.Println("Portunix AI Operations Helper")
 	case "--list-commands":
 		fmt.Println("aiops")
+	case "--help-ai":
+		showHelpAI()
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
 		fmt.Println("Supported commands: aiops")
@@ -132,6 +134,8 @@ func handleAIOpsCommand(args []string) {
 		handleStackCommand(subArgs)
 	case "--help", "-h":
 		showAIOpsHelp()
+	case "--help-ai":
+		showHelpAI()
 	default:
 		fmt.Printf("Unknown aiops subcommand: %s\n", subcommand)
 		fmt.Println("Run 'portunix aiops --help' for available commands")
@@ -0,0 +1,391 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"portunix.ai/app/secret"
+	"portunix.ai/app/vuln"
+)
+
+// Approval gates stop a run until it is signed off. The request is persisted
+// in ~/.portunix/playbook-approvals/<id>.json and announced through the
+// notification channels. An approver signs it off at the prompt of an
+// interactive run, or from another terminal with 'portunix playbook approve'.
+// Requests and decisions are recorded in the audit log with the identity of
+// the approver.
+
+// Approval states
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+	ApprovalExpired  = "expired"
+)
+
+// NotifySlack posts approval requests to a Slack incoming webhook
+const NotifySlack = "slack"
+
+// defaultApprovalTimeout is how long a run waits for a decision
+const defaultApprovalTimeout = time.Hour
+
+// approvalPollInterval is how often a waiting run checks for a decision
+var approvalPollInterval = 2 * time.Second
+
+// ApprovalRequest is a persisted approval request and its decision
+type ApprovalRequest struct {
+	ID          string    `json:"id"`
+	Playbook    string    `json:"playbook"`
+	Message     string    `json:"message,omitempty"`
+	Environment string    `json:"environment"`
+	RequestedBy string    `json:"requested_by"`
+	RequestedAt time.Time `json:"requested_at"`
+	Approvers   []string  `json:"approvers,omitempty"`
+	Status      string    `json:"status"`
+	DecidedBy   string    `json:"decided_by,omitempty"`
+	DecidedAt   time.Time `json:"decided_at,omitempty"`
+	Comment     string    `json:"comment,omitempty"`
+}
+
+// validateApproval checks the settings of an approval
+func validateApproval(a *ApprovalTask) error {
+	for _, channel := range a.Notify {
+		if !oneOf(channel, vuln.NotifyDesktop, vuln.NotifyEmail, NotifySlack) {
+			return fmt.Errorf("notify must be desktop, email or slack, got %q", channel)
+		}
+		if channel == vuln.NotifyEmail && len(a.EmailTo) == 0 {
+			return fmt.Errorf("email notification needs email_to")
+		}
+		if channel == NotifySlack && a.SlackWebhook == "" {
+			return fmt.Errorf("slack notification needs slack_webhook")
+		}
+	}
+	if a.Timeout != "" {
+		if d, err := time.ParseDuration(a.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout %q, expected a duration such as 30m", a.Timeout)
+		}
+	}
+	return nil
+}
+
+// runPause waits the seconds of the pause, or for Enter
+func runPause(p *PauseTask) error {
+	prompt := p.Prompt
+	if p.Seconds > 0 {
+		if prompt != "" {
+			fmt.Printf("   ⏸️  %s\n", prompt)
+		}
+		fmt.Printf("   Pausing for %d seconds...\n", p.Seconds)
+		time.Sleep(time.Duration(p.Seconds) * time.Second)
+		return nil
+	}
+
+	if !isInteractive() {
+		return fmt.Errorf("pause needs an interactive terminal, set seconds to pause unattended")
+	}
+	if prompt == "" {
+		prompt = "Press Enter to continue"
+	}
+	fmt.Printf("   ⏸️  %s ", prompt)
+	_, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	return err
+}
+
+// isInteractive reports whether stdin is a terminal
+func isInteractive() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// approvalsDir returns the directory of the approval requests
+func approvalsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".portunix", "playbook-approvals"), nil
+}
+
+func saveApproval(req *ApprovalRequest) error {
+	dir, err := approvalsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create approvals directory: %v", err)
+	}
+	data, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, req.ID+".json"), data, 0600)
+}
+
+// LoadApproval returns the approval request with the given ID
+func LoadApproval(id string) (*ApprovalRequest, error) {
+	dir, err := approvalsDir()
+	if err != nil {
+		return nil, err
+	}
+	if strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("invalid approval ID %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("approval %q not found", id)
+	} else if err != nil {
+		return nil, err
+	}
+	var req ApprovalRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("invalid approval %s: %v", id, err)
+	}
+	return &req, nil
+}
+
+// PendingApprovals returns the requests waiting for a decision, oldest first
+func PendingApprovals() ([]*ApprovalRequest, error) {
+	dir, err := approvalsDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var pending []*ApprovalRequest
+	for _, file := range files {
+		req, err := LoadApproval(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err == nil && req.Status == ApprovalPending {
+			pending = append(pending, req)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].RequestedAt.Before(pending[j].RequestedAt)
+	})
+	return pending, nil
+}
+
+// canApprove reports whether user may decide the request
+func canApprove(req *ApprovalRequest, user string) bool {
+	return len(req.Approvers) == 0 || oneOf(user, req.Approvers...)
+}
+
+// DecideApproval approves or rejects a pending request as user and records
+// the decision in the audit log
+func DecideApproval(id, user string, approve bool, comment string) (*ApprovalRequest, error) {
+	req, err := LoadApproval(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != ApprovalPending {
+		return nil, fmt.Errorf("approval %s is already %s", id, req.Status)
+	}
+	if !canApprove(req, user) {
+		return nil, fmt.Errorf("user %s may not approve %s (approvers: %s)", user, id, strings.Join(req.Approvers, ", "))
+	}
+
+	req.Status = ApprovalRejected
+	if approve {
+		req.Status = ApprovalApproved
+	}
+	req.DecidedBy = user
+	req.DecidedAt = time.Now()
+	req.Comment = comment
+	if err := saveApproval(req); err != nil {
+		return nil, err
+	}
+	auditApproval(req, req.Status, user)
+	return req, nil
+}
+
+// auditApproval records an approval event with the identity of user
+func auditApproval(req *ApprovalRequest, event, user string) {
+	auditMgr, err := NewAuditManager(GetDefaultAuditConfig())
+	if err != nil {
+		fmt.Printf("⚠️  Failed to record approval in the audit log: %v\n", err)
+		return
+	}
+	level := AuditLevelInfo
+	if event != ApprovalApproved && event != "requested" {
+		level = AuditLevelWarning
+	}
+	host, _ := os.Hostname()
+	details := map[string]interface{}{
+		"approval_id": req.ID,
+		"playbook":    req.Playbook,
+		"requester":   req.RequestedBy,
+		"host":        host,
+	}
+	if req.Message != "" {
+		details["message"] = req.Message
+	}
+	if req.Comment != "" {
+		details["comment"] = req.Comment
+	}
+	auditMgr.LogSystemEvent(level, "playbook.approval."+event, user, req.Environment, details)
+}
+
+// requestApproval stops the run until the approval is decided. It fails
+// when the request is rejected or times out.
+func requestApproval(ptxbook *PtxbookFile, approval *ApprovalTask, options ExecutionOptions) error {
+	timeout := defaultApprovalTimeout
+	if approval.Timeout != "" {
+		timeout, _ = time.ParseDuration(approval.Timeout)
+	}
+
+	dir, err := approvalsDir()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	req := &ApprovalRequest{
+		ID:          uniqueID(dir, newRunID(ptxbook.Metadata.Name, now)),
+		Playbook:    ptxbook.Metadata.Name,
+		Message:     approval.Message,
+		Environment: options.Environment,
+		RequestedBy: options.User,
+		RequestedAt: now,
+		Approvers:   approval.Approvers,
+		Status:      ApprovalPending,
+	}
+	if err := saveApproval(req); err != nil {
+		return fmt.Errorf("failed to record approval request: %v", err)
+	}
+	auditApproval(req, "requested", options.User)
+
+	fmt.Printf("   ✋ Approval required: %s\n", approvalMessage(req))
+	if len(req.Approvers) > 0 {
+		fmt.Printf("      Approvers: %s\n", strings.Join(req.Approvers, ", "))
+	}
+	fmt.Printf("      Approve with: portunix playbook approve %s\n", req.ID)
+	notifyApproval(req, approval)
+
+	user := getCurrentUser()
+	if isInteractive() && canApprove(req, user) {
+		fmt.Printf("      Approve as %s? [y/N] ", user)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		req, err = DecideApproval(req.ID, user, answer == "y" || answer == "yes", "")
+	} else {
+		fmt.Printf("      Waiting up to %s for a decision...\n", timeout)
+		req, err = waitForApproval(req.ID, timeout)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch req.Status {
+	case ApprovalApproved:
+		fmt.Printf("   ✅ Approved by %s\n", req.DecidedBy)
+		return nil
+	case ApprovalRejected:
+		if req.Comment != "" {
+			return fmt.Errorf("approval %s rejected by %s: %s", req.ID, req.DecidedBy, req.Comment)
+		}
+		return fmt.Errorf("approval %s rejected by %s", req.ID, req.DecidedBy)
+	default:
+		return fmt.Errorf("approval %s %s", req.ID, req.Status)
+	}
+}
+
+// waitForApproval polls the request until it is decided; an undecided
+// request expires after timeout
+func waitForApproval(id string, timeout time.Duration) (*ApprovalRequest, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		req, err := LoadApproval(id)
+		if err != nil {
+			return nil, err
+		}
+		if req.Status != ApprovalPending {
+			return req, nil
+		}
+		if time.Now().After(deadline) {
+			req.Status = ApprovalExpired
+			req.DecidedAt = time.Now()
+			if err := saveApproval(req); err != nil {
+				return nil, err
+			}
+			auditApproval(req, ApprovalExpired, "system")
+			return nil, fmt.Errorf("approval %s timed out after %s", id, timeout)
+		}
+		time.Sleep(approvalPollInterval)
+	}
+}
+
+// approvalMessage returns the text shown for a request
+func approvalMessage(req *ApprovalRequest) string {
+	if req.Message != "" {
+		return req.Message
+	}
+	return fmt.Sprintf("run of playbook %s (%s)", req.Playbook, req.Environment)
+}
+
+// notifyApproval announces the request through the channels of the
+// approval. Failures are printed, not fatal, since the approver may still
+// sign off from the terminal.
+func notifyApproval(req *ApprovalRequest, approval *ApprovalTask) {
+	if len(approval.Notify) == 0 {
+		return
+	}
+	host, _ := os.Hostname()
+	subject := fmt.Sprintf("Portunix: approval required for playbook %s", req.Playbook)
+	body := fmt.Sprintf("%s\n\nRequested by %s on %s at %s.\n\nApprove:  portunix playbook approve %s\nReject:   portunix playbook approve %s --reject\n",
+		approvalMessage(req), req.RequestedBy, host, req.RequestedAt.Format("2006-01-02 15:04"), req.ID, req.ID)
+
+	for _, channel := range approval.Notify {
+		var err error
+		switch channel {
+		case vuln.NotifyDesktop:
+			err = vuln.NotifyDesktopMessage(subject, approvalMessage(req))
+		case vuln.NotifyEmail:
+			var smtpConfig *vuln.SMTPConfig
+			if smtpConfig, err = vuln.LoadSMTPConfig(".pft-config.json"); err == nil {
+				err = vuln.SendEmail(smtpConfig, approval.EmailTo, subject, body)
+			}
+		case NotifySlack:
+			err = postSlackMessage(approval.SlackWebhook, "*"+subject+"*\n"+body)
+		}
+		if err != nil {
+			fmt.Printf("      ⚠️  %s notification failed: %v\n", channel, err)
+		} else {
+			fmt.Printf("      📣 Notified via %s\n", channel)
+		}
+	}
+}
+
+// postSlackMessage posts text to a Slack incoming webhook; a "secret:<name>"
+// webhook is read from the secret store
+func postSlackMessage(webhook, text string) error {
+	url, err := secret.Resolve(webhook, "ansible")
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned %s", resp.Status)
+	}
+	return nil
+}
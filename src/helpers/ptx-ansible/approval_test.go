@@ -0,0 +1,118 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateApproval(t *testing.T) {
+	invalid := map[string]ApprovalTask{
+		"unknown channel": {Notify: []string{"pager"}},
+		"email without":   {Notify: []string{"email"}},
+		"slack without":   {Notify: []string{"slack"}},
+		"bad timeout":     {Timeout: "soon"},
+	}
+	for name, approval := range invalid {
+		if err := validateApproval(&approval); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	valid := ApprovalTask{Notify: []string{"email", "slack"}, EmailTo: []string{"ops@example.com"}, SlackWebhook: "secret:slack-hook", Timeout: "30m"}
+	if err := validateApproval(&valid); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecideApproval(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+
+	req := &ApprovalRequest{ID: "deploy-1", Playbook: "deploy", Approvers: []string{"alice"}, Status: ApprovalPending}
+	if err := saveApproval(req); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := PendingApprovals(); len(pending) != 1 {
+		t.Fatalf("expected one pending approval, got %d", len(pending))
+	}
+
+	if _, err := DecideApproval("deploy-1", "mallory", true, ""); err == nil {
+		t.Error("only the approvers may decide")
+	}
+	decided, err := DecideApproval("deploy-1", "alice", false, "change freeze")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decided.Status != ApprovalRejected || decided.DecidedBy != "alice" || decided.Comment != "change freeze" {
+		t.Errorf("unexpected decision %+v", decided)
+	}
+	if _, err := DecideApproval("deploy-1", "alice", true, ""); err == nil {
+		t.Error("a decided approval cannot be decided again")
+	}
+
+	events, err := mustAuditManager(t).QueryEvents(&AuditFilter{Action: "playbook.approval.rejected"})
+	if err != nil || len(events) != 1 || events[0].User != "alice" {
+		t.Errorf("expected the rejection by alice in the audit log, got %v %v", events, err)
+	}
+}
+
+func mustAuditManager(t *testing.T) *AuditManager {
+	auditMgr, err := NewAuditManager(GetDefaultAuditConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auditMgr
+}
+
+func TestWaitForApproval(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	approvalPollInterval = 10 * time.Millisecond
+
+	for _, id := range []string{"deploy-1", "deploy-2"} {
+		if err := saveApproval(&ApprovalRequest{ID: id, Playbook: "deploy", Status: ApprovalPending}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		DecideApproval("deploy-1", "bob", true, "")
+	}()
+	req, err := waitForApproval("deploy-1", 5*time.Second)
+	if err != nil || req.Status != ApprovalApproved || req.DecidedBy != "bob" {
+		t.Errorf("unexpected result %+v %v", req, err)
+	}
+
+	if _, err := waitForApproval("deploy-2", 30*time.Millisecond); err == nil {
+		t.Error("an undecided approval should time out")
+	}
+	if req, _ := LoadApproval("deploy-2"); req.Status != ApprovalExpired {
+		t.Errorf("expected expired, got %s", req.Status)
+	}
+}
+
+func TestPostSlackMessage(t *testing.T) {
+	var text string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		text = payload["text"]
+	}))
+	defer server.Close()
+
+	if err := postSlackMessage(server.URL, "approve deploy-1"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "deploy-1") {
+		t.Errorf("unexpected text %q", text)
+	}
+}
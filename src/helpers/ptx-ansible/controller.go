@@ -0,0 +1,207 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// With --env container, Ansible runs in an ephemeral controller container
+// with a pinned Ansible version, so the host needs no Ansible. The controller
+// reaches the target container through the API socket of the runtime (the
+// community.docker.docker_api connection); the playbook, the inventory and
+// the variables are mounted read-only and the output is streamed back.
+
+const (
+	// defaultControllerAnsible is the Ansible package installed in the
+	// controller image unless requirements.ansible.version pins another
+	defaultControllerAnsible = "10.7.0"
+	controllerBaseImage      = "docker.io/library/python:3.12-slim"
+
+	// Paths inside the controller container
+	controllerSocket    = "/run/ptxbook/runtime.sock"
+	controllerInventory = "/run/ptxbook/inventory.ini"
+	controllerVars      = "/run/ptxbook/vars.json"
+	controllerPlaybooks = "/ptxbook"
+)
+
+// ansibleController runs ansible-playbook in controller containers
+type ansibleController struct {
+	runtime string
+	image   string
+	socket  string // Host path of the API socket of the runtime
+	stop    func() // Stops the API service started for podman
+}
+
+// bootstrapPythonScript installs python3, which Ansible modules need, in the
+// target container
+const bootstrapPythonScript = `command -v python3 >/dev/null 2>&1 && exit 0
+if command -v apt-get >/dev/null 2>&1; then apt-get update -qq && DEBIAN_FRONTEND=noninteractive apt-get install -y -qq python3
+elif command -v apk >/dev/null 2>&1; then apk add --no-cache python3
+elif command -v dnf >/dev/null 2>&1; then dnf install -y python3
+elif command -v yum >/dev/null 2>&1; then yum install -y python3
+elif command -v zypper >/dev/null 2>&1; then zypper --non-interactive install python3
+else echo "no supported package manager to install python3" >&2; exit 1
+fi`
+
+// startAnsibleController prepares the controller image, the API socket of
+// the runtime and python3 in the target container
+func startAnsibleController(envCtx *EnvironmentContext, requirements *AnsibleRequirements, verbose bool) (*ansibleController, error) {
+	runtime := envCtx.Runtime
+	if runtime != "docker" && runtime != "podman" {
+		return nil, fmt.Errorf("unsupported container runtime for the Ansible controller: %q", runtime)
+	}
+
+	image, err := controllerImage(runtime, requirements, verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	if verbose {
+		fmt.Printf("   Preparing python3 in %s...\n", envCtx.Target)
+	}
+	bootstrap := exec.Command(runtime, "exec", envCtx.Target, "sh", "-c", bootstrapPythonScript)
+	if output, err := bootstrap.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to install python3 in %s: %v\n%s", envCtx.Target, err, strings.TrimSpace(string(output)))
+	}
+
+	socket, stop, err := runtimeAPISocket(runtime)
+	if err != nil {
+		return nil, err
+	}
+	return &ansibleController{runtime: runtime, image: image, socket: socket, stop: stop}, nil
+}
+
+// Close stops what the controller started
+func (c *ansibleController) Close() {
+	if c != nil && c.stop != nil {
+		c.stop()
+	}
+}
+
+// command returns the command running the playbook in a controller
+// container. baseDir, the directory of the .ptxbook file, is mounted so
+// playbooks find their roles and files; inventoryPath and varsPath ("" for
+// none) are host files.
+func (c *ansibleController) command(playbookPath, baseDir, inventoryPath, varsPath string) *exec.Cmd {
+	return exec.Command(c.runtime, controllerRunArgs(c.image, c.socket, playbookPath, baseDir, inventoryPath, varsPath)...)
+}
+
+// controllerRunArgs returns the arguments of '<runtime> run' for a
+// controller container
+func controllerRunArgs(image, socket, playbookPath, baseDir, inventoryPath, varsPath string) []string {
+	mountDir, playbook := baseDir, playbookPath
+	if rel, err := filepath.Rel(baseDir, playbookPath); err == nil && !strings.HasPrefix(rel, "..") {
+		playbook = rel
+	} else {
+		mountDir, playbook = filepath.Dir(playbookPath), filepath.Base(playbookPath)
+	}
+
+	args := []string{"run", "--rm",
+		// The controller needs the runtime socket, which SELinux labels deny
+		"--security-opt", "label=disable",
+		"-e", "DOCKER_HOST=unix://" + controllerSocket,
+		"-e", "ANSIBLE_FORCE_COLOR=1",
+		"-e", "ANSIBLE_HOST_KEY_CHECKING=False",
+		"-v", socket + ":" + controllerSocket,
+		"-v", mountDir + ":" + controllerPlaybooks + ":ro",
+		"-v", inventoryPath + ":" + controllerInventory + ":ro",
+	}
+	if varsPath != "" {
+		args = append(args, "-v", varsPath+":"+controllerVars+":ro")
+	}
+	args = append(args, "-w", controllerPlaybooks, image,
+		"ansible-playbook", "-i", controllerInventory, path.Join(controllerPlaybooks, filepath.ToSlash(playbook)))
+	if varsPath != "" {
+		args = append(args, "--extra-vars", "@"+controllerVars)
+	}
+	return args
+}
+
+// controllerImage returns the image of the controller: the configured one, or
+// a local image with the pinned Ansible version, built when missing
+func controllerImage(runtime string, requirements *AnsibleRequirements, verbose bool) (string, error) {
+	version := defaultControllerAnsible
+	if requirements != nil {
+		if requirements.ControllerImage != "" {
+			return requirements.ControllerImage, nil
+		}
+		if requirements.Version != "" {
+			version = requirements.Version
+		}
+	}
+
+	image := "localhost/ptx-ansible-controller:" + version
+	if exec.Command(runtime, "image", "inspect", image).Run() == nil {
+		return image, nil
+	}
+
+	if verbose {
+		fmt.Printf("   Building Ansible controller image %s...\n", image)
+	}
+	cmd := exec.Command(runtime, "build", "-t", image, "-")
+	cmd.Stdin = strings.NewReader(controllerDockerfile(version))
+	var output strings.Builder
+	cmd.Stdout, cmd.Stderr = &output, &output
+	if verbose {
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	}
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to build the Ansible controller image: %v\n%s", err, strings.TrimSpace(output.String()))
+	}
+	return image, nil
+}
+
+// controllerDockerfile returns the Dockerfile of the controller image; the
+// Ansible package brings the community.docker collection, which needs
+// requests
+func controllerDockerfile(version string) string {
+	return fmt.Sprintf(`FROM %s
+RUN pip install --no-cache-dir "ansible==%s" requests
+`, controllerBaseImage, version)
+}
+
+// runtimeAPISocket returns the host path of the Docker API socket of the
+// runtime. Podman serves the API only on demand, so a service is started
+// for the run and stopped by the returned function.
+func runtimeAPISocket(runtime string) (string, func(), error) {
+	if runtime == "docker" {
+		if host := os.Getenv("DOCKER_HOST"); strings.HasPrefix(host, "unix://") {
+			return strings.TrimPrefix(host, "unix://"), nil, nil
+		}
+		return "/var/run/docker.sock", nil, nil
+	}
+
+	dir, err := os.MkdirTemp("", "ptx-ansible-podman-")
+	if err != nil {
+		return "", nil, err
+	}
+	socket := filepath.Join(dir, "podman.sock")
+	service := exec.Command("podman", "system", "service", "--time=0", "unix://"+socket)
+	if err := service.Start(); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("failed to start the podman API service: %v", err)
+	}
+	stop := func() {
+		service.Process.Kill()
+		service.Wait()
+		os.RemoveAll(dir)
+	}
+
+	for i := 0; i < 50; i++ {
+		if _, err := os.Stat(socket); err == nil {
+			return socket, stop, nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	stop()
+	return "", nil, fmt.Errorf("podman API socket %s did not appear", socket)
+}
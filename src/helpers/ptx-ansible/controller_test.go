@@ -0,0 +1,51 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestControllerRunArgs(t *testing.T) {
+	args := strings.Join(controllerRunArgs("ctl:10", "/var/run/docker.sock",
+		"/work/books/site/deploy.yml", "/work/books", "/tmp/inv.ini", "/tmp/vars.json"), " ")
+	for _, want := range []string{
+		"-v /var/run/docker.sock:/run/ptxbook/runtime.sock",
+		"-v /work/books:/ptxbook:ro",
+		"-v /tmp/inv.ini:/run/ptxbook/inventory.ini:ro",
+		"-v /tmp/vars.json:/run/ptxbook/vars.json:ro",
+		"ctl:10 ansible-playbook -i /run/ptxbook/inventory.ini /ptxbook/site/deploy.yml --extra-vars @/run/ptxbook/vars.json",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("args %q miss %q", args, want)
+		}
+	}
+
+	// A playbook outside the .ptxbook directory mounts its own directory
+	args = strings.Join(controllerRunArgs("ctl:10", "/sock", "/opt/ansible/site.yml", "/work/books", "/tmp/inv.ini", ""), " ")
+	if !strings.Contains(args, "-v /opt/ansible:/ptxbook:ro") || !strings.HasSuffix(args, "ansible-playbook -i /run/ptxbook/inventory.ini /ptxbook/site.yml") {
+		t.Errorf("unexpected args %q", args)
+	}
+	if strings.Contains(args, "vars.json") {
+		t.Errorf("args %q mount variables that were not given", args)
+	}
+}
+
+func TestControllerImage(t *testing.T) {
+	image, err := controllerImage("docker", &AnsibleRequirements{ControllerImage: "registry.example.com/ctl:1"}, false)
+	if err != nil || image != "registry.example.com/ctl:1" {
+		t.Errorf("got %q, %v", image, err)
+	}
+	if dockerfile := controllerDockerfile("9.5.1"); !strings.Contains(dockerfile, `"ansible==9.5.1"`) || !strings.HasPrefix(dockerfile, "FROM "+controllerBaseImage) {
+		t.Errorf("unexpected Dockerfile %q", dockerfile)
+	}
+}
+
+func TestGenerateContainerInventory(t *testing.T) {
+	if got := generateContainerInventory("web"); got != "[containers]\nweb ansible_connection=community.docker.docker_api\n" {
+		t.Errorf("unexpected inventory %q", got)
+	}
+}
@@ -5,11 +5,14 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -29,6 +32,9 @@ type ExecutionOptions struct {
 	User          string   // Phase 4: User executing the playbook
 	ScriptFilter  []string // Phase 1 #128: Filter scripts to run (empty = all)
 	ListScripts   bool     // Phase 1 #128: Just list available scripts
+	VarsFiles     []string // Values files overriding the playbook variables, in order
+	SetVars       []string // key=value overrides applied after the values files
+	FromRuns      []string // Runs (ID or playbook name) whose outputs become the variable 'inputs'
 }
 
 // ExecutionResult contains the result of playbook execution
@@ -36,6 +42,7 @@ type ExecutionResult struct {
 	Success bool
 	Message string
 	Errors  []string
+	RunID   string // Set when the outputs of the run were persisted
 }
 
 // ExecutePlaybook executes a .ptxbook file with the given options
@@ -78,6 +85,27 @@ func ExecutePlaybook(filePath string, options ExecutionOptions) (*ExecutionResul
 		}, err
 	}
 
+	// Outputs of previous runs, overridable by the values files and --set
+	fromRuns, err := applyRunInputs(ptxbook, options.FromRuns)
+	if err != nil {
+		auditMgr.LogPlaybookExecution(options.User, options.Environment, filePath, false, time.Since(startTime), err)
+		return &ExecutionResult{
+			Success: false,
+			Message: "Failed to load outputs of previous runs",
+			Errors:  []string{err.Error()},
+		}, err
+	}
+
+	// Environment overlays: values files and --set override the variables
+	if err := ApplyVariableOverrides(ptxbook, options.VarsFiles, options.SetVars); err != nil {
+		auditMgr.LogPlaybookExecution(options.User, options.Environment, filePath, false, time.Since(startTime), err)
+		return &ExecutionResult{
+			Success: false,
+			Message: "Failed to apply variables",
+			Errors:  []string{err.Error()},
+		}, err
+	}
+
 	// Phase 4: Check RBAC permissions for playbook execution
 	accessResult := rbacMgr.CheckAccess(&AccessRequest{
 		User:        options.User,
@@ -143,6 +171,54 @@ func ExecutePlaybook(filePath string, options ExecutionOptions) (*ExecutionResul
 		}
 	}
 
+	// Sign-off before the run changes anything
+	if gate := ptxbook.Spec.Approval; gate != nil {
+		required, err := true, error(nil)
+		if gate.When != "" {
+			required, err = ProcessConditionalExecution(gate.When, ptxbook.Spec.Variables, ptxbook.Spec.Environment)
+		}
+		if err == nil && required {
+			approval := gate.ApprovalTask
+			approval.Message, err = NewTemplateEngine(ptxbook.Spec.Variables, ptxbook.Spec.Environment).ProcessTemplate(approval.Message)
+			if err == nil && options.DryRun {
+				fmt.Println("   [DRY-RUN] Would request approval before running")
+			} else if err == nil {
+				err = requestApproval(ptxbook, &approval, options)
+			}
+		}
+		if err != nil {
+			auditMgr.LogPlaybookExecution(options.User, options.Environment, filePath, false, time.Since(startTime), err)
+			result.Success = false
+			result.Errors = append(result.Errors, fmt.Sprintf("Approval failed: %v", err))
+			return result, err
+		}
+	}
+
+	// Provision infrastructure first; its outputs feed the later steps
+	if ptxbook.Spec.Terraform != nil {
+		if options.Verbose {
+			fmt.Println("🏗️  Provisioning infrastructure with Terraform...")
+		}
+
+		if err := executeTerraform(ptxbook, filepath.Dir(filePath), options, rollbackManager); err != nil {
+			result.Success = false
+			result.Errors = append(result.Errors, fmt.Sprintf("Terraform provisioning failed: %v", err))
+
+			// Execute rollback on failure
+			if rollbackManager.IsEnabled() {
+				if rollbackErr := rollbackManager.ExecuteRollback(err.Error()); rollbackErr != nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("Rollback failed: %v", rollbackErr))
+				}
+			}
+
+			return result, err
+		}
+
+		if options.Verbose {
+			fmt.Println("✅ Infrastructure provisioned successfully")
+		}
+	}
+
 	// Phase 2: Setup environment if not local
 	var envCtx *EnvironmentContext
 	if options.Environment != "local" {
@@ -181,14 +257,39 @@ func ExecutePlaybook(filePath string, options ExecutionOptions) (*ExecutionResul
 		}
 	}
 
+	// Native tasks run without Ansible
+	if len(ptxbook.Spec.Tasks) > 0 {
+		if options.Verbose {
+			fmt.Printf("🧩 Running %d native tasks...\n", len(ptxbook.Spec.Tasks))
+		}
+
+		if err := executeTasks(ptxbook, filepath.Dir(filePath), options, envCtx, rollbackManager); err != nil {
+			result.Success = false
+			result.Errors = append(result.Errors, fmt.Sprintf("Native task execution failed: %v", err))
+
+			// Execute rollback on failure
+			if rollbackManager.IsEnabled() {
+				if rollbackErr := rollbackManager.ExecuteRollback(err.Error()); rollbackErr != nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("Rollback failed: %v", rollbackErr))
+				}
+			}
+
+			return result, err
+		}
+
+		if options.Verbose {
+			fmt.Println("✅ Native tasks completed successfully")
+		}
+	}
+
 	// Phase 2: Execute Ansible playbooks if present
 	if ptxbook.Spec.Ansible != nil && len(ptxbook.Spec.Ansible.Playbooks) > 0 {
 		if options.Verbose {
 			fmt.Printf("🔧 Executing %d Ansible playbooks...\n", len(ptxbook.Spec.Ansible.Playbooks))
 		}
 
-		// Check if Ansible is available
-		if !isAnsibleAvailable() {
+		// Check if Ansible is available; containers use a controller container
+		if options.Environment != "container" && !isAnsibleAvailable() {
 			errMsg := "Ansible is required but not available. Install with: portunix install ansible"
 			result.Success = false
 			result.Errors = append(result.Errors, errMsg)
@@ -200,10 +301,10 @@ func ExecutePlaybook(filePath string, options ExecutionOptions) (*ExecutionResul
 				}
 			}
 
-			return result, fmt.Errorf(errMsg)
+			return result, errors.New(errMsg)
 		}
 
-		if err := executeAnsiblePlaybooksWithRollback(ptxbook, options, envCtx, rollbackManager); err != nil {
+		if err := executeAnsiblePlaybooksWithRollback(ptxbook, filepath.Dir(filePath), options, envCtx, rollbackManager); err != nil {
 			result.Success = false
 			result.Errors = append(result.Errors, fmt.Sprintf("Ansible playbook execution failed: %v", err))
 
@@ -247,6 +348,32 @@ func ExecutePlaybook(filePath string, options ExecutionOptions) (*ExecutionResul
 		}
 	}
 
+	// Persist the outputs for playbooks chained with --from-run
+	if len(ptxbook.Spec.Outputs) > 0 && !options.DryRun {
+		outputs, err := renderOutputs(ptxbook, filepath.Dir(filePath), options.Environment == "local")
+		if err == nil {
+			absPath, _ := filepath.Abs(filePath)
+			run := &PlaybookRun{
+				ID:          newRunID(ptxbook.Metadata.Name, startTime),
+				Playbook:    ptxbook.Metadata.Name,
+				File:        absPath,
+				Environment: options.Environment,
+				User:        options.User,
+				StartedAt:   startTime,
+				FinishedAt:  time.Now(),
+				FromRuns:    fromRuns,
+				Outputs:     outputs,
+			}
+			err = SaveRun(run)
+			result.RunID = run.ID
+		}
+		if err != nil {
+			result.Success = false
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to persist outputs: %v", err))
+			return result, err
+		}
+	}
+
 	if options.Verbose {
 		fmt.Println("🎉 Playbook execution completed successfully")
 		fmt.Printf("📊 Audit trail logged for compliance\n")
@@ -293,9 +420,12 @@ func executePortunixPackages(ptxbook *PtxbookFile, options ExecutionOptions, env
 				}
 				cmd = exec.Command(portunixPath, args...)
 			case "virt":
-				// Execute on VM via SSH (simplified approach)
-				// In a full implementation, this would copy the binary and execute remotely
-				return fmt.Errorf("portunix package installation on VMs not yet implemented in Phase 2")
+				// portunix --host uploads a matching binary to the VM over SSH
+				args := []string{"--host", envCtx.Target, "install", pkg.Name}
+				if pkg.Variant != "" {
+					args = append(args, "--variant", pkg.Variant)
+				}
+				cmd = exec.Command(portunixPath, args...)
 			}
 		} else {
 			// Local execution
@@ -893,30 +1023,16 @@ func setupContainerEnvironment(options ExecutionOptions) (*EnvironmentContext, e
 		fmt.Printf("   Container initialized\n")
 	}
 
-	// Setup SSH connectivity (for Ansible if needed)
-	sshKeyPath, err := setupSSHForContainer(containerName, options)
-	if err != nil {
-		// Non-fatal, SSH is optional for direct container execution
-		if options.Verbose {
-			fmt.Printf("   Note: SSH setup skipped (not required for direct execution)\n")
-		}
-		sshKeyPath = ""
-	}
-
-	// Generate inventory
-	inventory := generateContainerInventory(containerName, sshKeyPath)
+	// Generate inventory for the Ansible controller container
+	inventory := generateContainerInventory(containerName)
 
 	envCtx := &EnvironmentContext{
-		Type:       "container",
-		Target:     containerName,
-		Runtime:    runtime, // Container runtime (docker/podman)
-		SSHHost:    "localhost",
-		SSHPort:    "2222",
-		SSHUser:    "root",
-		SSHKeyPath: sshKeyPath,
-		Inventory:  inventory,
-		TempDir:    runtime, // Store runtime for cleanup (legacy)
-		WorkDir:    workDir, // Working directory for scripts
+		Type:      "container",
+		Target:    containerName,
+		Runtime:   runtime, // Container runtime (docker/podman)
+		Inventory: inventory,
+		TempDir:   runtime, // Store runtime for cleanup (legacy)
+		WorkDir:   workDir, // Working directory for scripts
 	}
 
 	if options.Verbose {
@@ -953,27 +1069,35 @@ func setupVirtEnvironment(options ExecutionOptions) (*EnvironmentContext, error)
 		return nil, fmt.Errorf("failed to find portunix binary: %v", err)
 	}
 
-	// Check if VM exists and is running
+	// Check if VM exists and start it when needed
 	checkCmd := exec.Command(portunixPath, "virt", "list", "--name", options.Target)
 	if err := checkCmd.Run(); err != nil {
 		return nil, fmt.Errorf("VM '%s' not found or not accessible", options.Target)
 	}
+	if state, _ := exec.Command(portunixPath, "virt", "status", options.Target).Output(); strings.TrimSpace(string(state)) != "running" {
+		if options.Verbose {
+			fmt.Printf("   Starting VM %s...\n", options.Target)
+		}
+		if output, err := exec.Command(portunixPath, "virt", "start", options.Target).CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("failed to start VM '%s': %s", options.Target, strings.TrimSpace(string(output)))
+		}
+	}
 
 	// Setup SSH connectivity for VM
-	sshKeyPath, sshHost, sshPort, err := setupSSHForVM(options.Target, options)
+	sshKeyPath, sshHost, sshPort, sshUser, err := setupSSHForVM(options.Target, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup SSH for VM: %v", err)
 	}
 
 	// Generate inventory
-	inventory := generateVMInventory(options.Target, sshHost, sshPort, sshKeyPath)
+	inventory := generateVMInventory(options.Target, sshHost, sshPort, sshUser, sshKeyPath)
 
 	envCtx := &EnvironmentContext{
 		Type:       "virt",
 		Target:     options.Target,
 		SSHHost:    sshHost,
 		SSHPort:    sshPort,
-		SSHUser:    "root", // Assuming root access for VMs
+		SSHUser:    sshUser,
 		SSHKeyPath: sshKeyPath,
 		Inventory:  inventory,
 	}
@@ -985,59 +1109,63 @@ func setupVirtEnvironment(options ExecutionOptions) (*EnvironmentContext, error)
 	return envCtx, nil
 }
 
-// setupSSHForContainer configures SSH access to a container
-func setupSSHForContainer(containerName string, options ExecutionOptions) (string, error) {
-	// For Phase 2, we assume the container already has SSH setup
-	// In a full implementation, this would:
-	// 1. Generate SSH key pair
-	// 2. Install SSH server in container
-	// 3. Configure SSH access
-	// 4. Return path to private key
-
-	// Placeholder implementation - return mock SSH key path
-	sshKeyPath := fmt.Sprintf("/tmp/ptx-ansible-%s.key", containerName)
-
-	if options.Verbose {
-		fmt.Printf("   SSH key path: %s\n", sshKeyPath)
+// setupSSHForVM waits until a VM created with 'portunix virt create --image'
+// accepts SSH and returns its key path, address, port and login user
+func setupSSHForVM(vmName string, options ExecutionOptions) (string, string, string, string, error) {
+	portunixPath, err := getPortunixBinaryPath()
+	if err != nil {
+		return "", "", "", "", err
 	}
 
-	return sshKeyPath, nil
-}
-
-// setupSSHForVM configures SSH access to a virtual machine
-func setupSSHForVM(vmName string, options ExecutionOptions) (string, string, string, error) {
-	// For Phase 2, we assume the VM already has SSH setup
-	// In a full implementation, this would:
-	// 1. Query VM IP address
-	// 2. Check SSH connectivity
-	// 3. Setup SSH keys if needed
-	// 4. Return connection details
+	if output, err := exec.Command(portunixPath, "virt", "ssh", vmName, "--check", "--wait", "3m").CombinedOutput(); err != nil {
+		return "", "", "", "", fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
 
-	// Placeholder implementation - return mock connection details
-	sshKeyPath := fmt.Sprintf("/tmp/ptx-ansible-%s.key", vmName)
-	sshHost := "192.168.122.100" // Mock VM IP
-	sshPort := "22"
+	output, err := exec.Command(portunixPath, "virt", "info", vmName, "--json").Output()
+	if err != nil {
+		return "", "", "", "", fmt.Errorf("failed to get VM info: %v", err)
+	}
+	var info struct {
+		IP      string `json:"ip"`
+		SSHUser string `json:"ssh_user"`
+		SSHKey  string `json:"ssh_key"`
+		SSHPort int    `json:"ssh_port"`
+	}
+	if err := json.Unmarshal(output, &info); err != nil {
+		return "", "", "", "", fmt.Errorf("failed to parse VM info: %v", err)
+	}
+	if info.SSHUser == "" || info.IP == "" {
+		return "", "", "", "", fmt.Errorf("VM '%s' was not created from a cloud image; create it with: portunix virt create %s --image ubuntu-22.04", vmName, vmName)
+	}
+	sshPort := strconv.Itoa(info.SSHPort)
 
 	if options.Verbose {
-		fmt.Printf("   SSH connection: %s@%s:%s\n", "root", sshHost, sshPort)
-		fmt.Printf("   SSH key path: %s\n", sshKeyPath)
+		fmt.Printf("   SSH connection: %s@%s:%s\n", info.SSHUser, info.IP, sshPort)
+		fmt.Printf("   SSH key path: %s\n", info.SSHKey)
 	}
 
-	return sshKeyPath, sshHost, sshPort, nil
+	return info.SSHKey, info.IP, sshPort, info.SSHUser, nil
+}
+
+// vmCommand runs a shell command line inside a VM over SSH
+func vmCommand(portunixPath, vmName, command string) *exec.Cmd {
+	return exec.Command(portunixPath, "virt", "ssh", vmName, "--", command)
 }
 
-// generateContainerInventory creates Ansible inventory for container execution
-func generateContainerInventory(containerName, sshKeyPath string) string {
+// generateContainerInventory creates Ansible inventory for container
+// execution; the controller container connects through the runtime API
+func generateContainerInventory(containerName string) string {
 	return fmt.Sprintf(`[containers]
-%s ansible_host=localhost ansible_port=2222 ansible_user=root ansible_ssh_private_key_file=%s ansible_ssh_common_args='-o StrictHostKeyChecking=no'
-`, containerName, sshKeyPath)
+%s ansible_connection=community.docker.docker_api
+`, containerName)
 }
 
-// generateVMInventory creates Ansible inventory for VM execution
-func generateVMInventory(vmName, sshHost, sshPort, sshKeyPath string) string {
+// generateVMInventory creates Ansible inventory for VM execution. The
+// cloud-init user has passwordless sudo, so tasks may become root.
+func generateVMInventory(vmName, sshHost, sshPort, sshUser, sshKeyPath string) string {
 	return fmt.Sprintf(`[vms]
-%s ansible_host=%s ansible_port=%s ansible_user=root ansible_ssh_private_key_file=%s ansible_ssh_common_args='-o StrictHostKeyChecking=no'
-`, vmName, sshHost, sshPort, sshKeyPath)
+%s ansible_host=%s ansible_port=%s ansible_user=%s ansible_become=true ansible_ssh_private_key_file=%s ansible_ssh_common_args='-o StrictHostKeyChecking=no'
+`, vmName, sshHost, sshPort, sshUser, sshKeyPath)
 }
 
 // cleanupEnvironment cleans up the environment after playbook execution
@@ -1075,8 +1203,8 @@ func cleanupEnvironment(envCtx *EnvironmentContext, options ExecutionOptions) {
 		}
 	}
 
-	// Clean up temporary SSH keys
-	if envCtx.SSHKeyPath != "" {
+	// Clean up temporary SSH keys; a VM keeps its key for later sessions
+	if envCtx.Type == "container" && envCtx.SSHKeyPath != "" {
 		if err := os.Remove(envCtx.SSHKeyPath); err != nil && options.Verbose {
 			fmt.Printf("   Warning: Failed to remove SSH key %s: %v\n", envCtx.SSHKeyPath, err)
 		}
@@ -1467,45 +1595,11 @@ func executePortunixPackagesWithRollback(ptxbook *PtxbookFile, options Execution
 		}
 
 		// Build install command based on environment
-		var cmd *exec.Cmd
-		if envCtx != nil {
-			// For container/VM environments, execute install inside the environment
-			switch envCtx.Type {
-			case "container":
-				// Execute inside container - portunix is at /usr/local/bin/portunix in container
-				containerPortunixPath := "/usr/local/bin/portunix"
-				runtime := envCtx.TempDir // Runtime stored during setup
-				useDirectRuntime := runtime == "docker" || runtime == "podman"
-
-				var execArgs []string
-				if useDirectRuntime {
-					// Use explicit runtime directly
-					execArgs = []string{"exec", envCtx.Target, containerPortunixPath, "install", processedPkg.Name}
-					if processedPkg.Variant != "" {
-						execArgs = append(execArgs, "--variant", processedPkg.Variant)
-					}
-					cmd = exec.Command(runtime, execArgs...)
-				} else {
-					// Use portunix container exec
-					execArgs = []string{"container", "exec", envCtx.Target, containerPortunixPath, "install", processedPkg.Name}
-					if processedPkg.Variant != "" {
-						execArgs = append(execArgs, "--variant", processedPkg.Variant)
-					}
-					cmd = exec.Command(portunixPath, execArgs...)
-				}
-			case "virt":
-				// Execute on VM via SSH (simplified approach)
-				// In a full implementation, this would copy the binary and execute remotely
-				return fmt.Errorf("portunix package installation on VMs not yet implemented in Phase 2")
-			}
-		} else {
-			// Local execution
-			args := []string{"install", processedPkg.Name}
-			if processedPkg.Variant != "" {
-				args = append(args, "--variant", processedPkg.Variant)
-			}
-			cmd = exec.Command(portunixPath, args...)
+		args := []string{"install", processedPkg.Name}
+		if processedPkg.Variant != "" {
+			args = append(args, "--variant", processedPkg.Variant)
 		}
+		cmd := portunixCommand(portunixPath, envCtx, args...)
 
 		if options.Verbose {
 			cmd.Stdout = os.Stdout
@@ -1531,9 +1625,37 @@ func executePortunixPackagesWithRollback(ptxbook *PtxbookFile, options Execution
 	return nil
 }
 
-// executeAnsiblePlaybooksWithRollback executes Ansible playbooks with conditional execution and rollback support
-func executeAnsiblePlaybooksWithRollback(ptxbook *PtxbookFile, options ExecutionOptions, envCtx *EnvironmentContext, rollbackManager *RollbackManager) error {
-	playbookDir := filepath.Dir(ptxbook.Metadata.Name) // Assume playbooks are relative to .ptxbook file
+// portunixCommand returns the command running portunix with args in the
+// execution environment: locally, inside the container or on the VM
+func portunixCommand(portunixPath string, envCtx *EnvironmentContext, args ...string) *exec.Cmd {
+	if envCtx == nil {
+		return exec.Command(portunixPath, args...)
+	}
+	switch envCtx.Type {
+	case "container":
+		// Execute inside container - portunix is at /usr/local/bin/portunix in container
+		containerPortunixPath := "/usr/local/bin/portunix"
+		runtime := envCtx.TempDir // Runtime stored during setup
+		if runtime == "docker" || runtime == "podman" {
+			// Use explicit runtime directly
+			return exec.Command(runtime, append([]string{"exec", envCtx.Target, containerPortunixPath}, args...)...)
+		}
+		// Use portunix container exec
+		return exec.Command(portunixPath, append([]string{"container", "exec", envCtx.Target, containerPortunixPath}, args...)...)
+	case "virt":
+		// portunix --host uploads a matching binary to the VM over SSH
+		return exec.Command(portunixPath, append([]string{"--host", envCtx.Target}, args...)...)
+	}
+	return exec.Command(portunixPath, args...)
+}
+
+// executeAnsiblePlaybooksWithRollback executes Ansible playbooks with conditional execution and rollback support;
+// baseDir is the directory of the .ptxbook file
+func executeAnsiblePlaybooksWithRollback(ptxbook *PtxbookFile, baseDir string, options ExecutionOptions, envCtx *EnvironmentContext, rollbackManager *RollbackManager) error {
+	// Container environments run Ansible in a controller container, started
+	// once for all playbooks
+	var controller *ansibleController
+	defer func() { controller.Close() }()
 
 	for _, playbook := range ptxbook.Spec.Ansible.Playbooks {
 		// Phase 3: Process playbook variables and templates
@@ -1569,7 +1691,7 @@ func executeAnsiblePlaybooksWithRollback(ptxbook *PtxbookFile, options Execution
 		// Resolve playbook path (relative to .ptxbook file)
 		playbookPath := processedPlaybook.Path
 		if !filepath.IsAbs(playbookPath) {
-			playbookPath = filepath.Join(playbookDir, playbookPath)
+			playbookPath = filepath.Join(baseDir, playbookPath)
 		}
 
 		// Check if playbook file exists
@@ -1579,11 +1701,12 @@ func executeAnsiblePlaybooksWithRollback(ptxbook *PtxbookFile, options Execution
 
 		// Build ansible-playbook command
 		args := []string{"ansible-playbook", playbookPath}
+		var inventoryPath, varsPath string
 
 		// Configure inventory and connection based on environment
 		if envCtx != nil {
 			// Create temporary inventory file
-			inventoryPath, err := createTemporaryInventory(envCtx.Inventory)
+			inventoryPath, err = createTemporaryInventory(envCtx.Inventory)
 			if err != nil {
 				return fmt.Errorf("failed to create inventory file: %v", err)
 			}
@@ -1595,14 +1718,50 @@ func executeAnsiblePlaybooksWithRollback(ptxbook *PtxbookFile, options Execution
 				fmt.Printf("   Using inventory: %s\n", inventoryPath)
 				fmt.Printf("   Target: %s (%s)\n", envCtx.Target, envCtx.Type)
 			}
+		} else if inventory, err := terraformInventory(ptxbook); err != nil {
+			return err
+		} else if inventory != "" {
+			// Hosts provisioned by Terraform
+			inventoryPath, err := createTemporaryInventory(inventory)
+			if err != nil {
+				return fmt.Errorf("failed to create inventory file: %v", err)
+			}
+			defer os.Remove(inventoryPath)
+
+			args = append(args, "-i", inventoryPath)
 		} else {
 			// Default to localhost execution
 			args = append(args, "-i", "localhost,")
 			args = append(args, "--connection", "local")
 		}
 
+		// Pass the playbook variables, with those of the playbook entry
+		variables := map[string]interface{}{}
+		mergeVariables(variables, ptxbook.Spec.Variables)
+		mergeVariables(variables, playbook.Vars)
+		if len(variables) > 0 {
+			varsPath, err = writeExtraVars(variables)
+			if err != nil {
+				return fmt.Errorf("failed to write variables: %v", err)
+			}
+			defer os.Remove(varsPath)
+			args = append(args, "--extra-vars", "@"+varsPath)
+		}
+
 		// Execute the ansible-playbook command
 		cmd := exec.Command("ansible-playbook", args[1:]...)
+		if envCtx != nil && envCtx.Type == "container" {
+			if controller == nil {
+				var requirements *AnsibleRequirements
+				if ptxbook.Spec.Requirements != nil {
+					requirements = ptxbook.Spec.Requirements.Ansible
+				}
+				if controller, err = startAnsibleController(envCtx, requirements, options.Verbose); err != nil {
+					return err
+				}
+			}
+			cmd = controller.command(playbookPath, baseDir, inventoryPath, varsPath)
+		}
 		if options.Verbose {
 			cmd.Stdout = os.Stdout
 			cmd.Stderr = os.Stderr
@@ -1656,6 +1815,16 @@ func evaluateScriptCondition(condition string, envCtx *EnvironmentContext, optio
 		} else {
 			cmd = exec.Command(portunixPath, "container", "exec", envCtx.Target, "sh", "-c", testCmd)
 		}
+	} else if envCtx != nil && envCtx.Type == "virt" {
+		portunixPath, err := getPortunixBinaryPath()
+		if err != nil {
+			return false, err
+		}
+		testCmd := "test " + condition
+		if envCtx.WorkDir != "" {
+			testCmd = fmt.Sprintf("cd %s && %s", envCtx.WorkDir, testCmd)
+		}
+		cmd = vmCommand(portunixPath, envCtx.Target, testCmd)
 	} else {
 		// Local evaluation - use appropriate shell for OS
 		if runtime.GOOS == "windows" {
@@ -1802,6 +1971,17 @@ func executeScripts(ptxbook *PtxbookFile, options ExecutionOptions, envCtx *Envi
 			}
 			wrappedCmd := fmt.Sprintf("cd %s && %s", workDir, script.Command)
 			cmd = exec.Command(portunixPath, "container", "exec", envCtx.Target, "sh", "-c", wrappedCmd)
+		} else if envCtx != nil && envCtx.Type == "virt" {
+			// Execute inside the VM over SSH, from the login user's home
+			portunixPath, err := getPortunixBinaryPath()
+			if err != nil {
+				return fmt.Errorf("failed to find portunix binary: %v", err)
+			}
+			command := script.Command
+			if envCtx.WorkDir != "" {
+				command = fmt.Sprintf("cd %s && %s", envCtx.WorkDir, command)
+			}
+			cmd = vmCommand(portunixPath, envCtx.Target, command)
 		} else {
 			// Local execution - use appropriate shell for OS
 			if runtime.GOOS == "windows" {
@@ -1879,6 +2059,17 @@ func executeScripts(ptxbook *PtxbookFile, options ExecutionOptions, envCtx *Envi
 			}
 			wrappedCmd := fmt.Sprintf("cd %s && %s", workDir, script.Command)
 			cmd = exec.Command(portunixPath, "container", "exec", envCtx.Target, "sh", "-c", wrappedCmd)
+		} else if envCtx != nil && envCtx.Type == "virt" {
+			// Execute inside the VM over SSH, from the login user's home
+			portunixPath, err := getPortunixBinaryPath()
+			if err != nil {
+				return fmt.Errorf("failed to find portunix binary: %v", err)
+			}
+			command := script.Command
+			if envCtx.WorkDir != "" {
+				command = fmt.Sprintf("cd %s && %s", envCtx.WorkDir, command)
+			}
+			cmd = vmCommand(portunixPath, envCtx.Target, command)
 		} else {
 			// Local execution - use appropriate shell for OS
 			if runtime.GOOS == "windows" {
@@ -1,18 +1,30 @@
 module portunix.ai/portunix/src/helpers/ptx-ansible
 
-go 1.23.3
+go 1.24.0
 
 toolchain go1.24.2
 
 // Use parent module for dependencies
 replace portunix.ai/portunix => ../../..
 
+replace portunix.ai/app => ../../app
+
 require (
-	github.com/spf13/cobra v1.8.1
+	github.com/spf13/cobra v1.10.1
 	gopkg.in/yaml.v3 v3.0.1
+	portunix.ai/app v0.0.0
+	portunix.ai/portunix v0.0.0-00010101000000-000000000000
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/spf13/pflag v1.0.9 // indirect
+	golang.org/x/crypto v0.47.0 // indirect
+	golang.org/x/net v0.49.0 // indirect
+	golang.org/x/sys v0.40.0 // indirect
+	golang.org/x/term v0.39.0 // indirect
+	golang.org/x/text v0.33.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260126211449-d11affda4bed // indirect
+	google.golang.org/grpc v1.78.0 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
 )
@@ -0,0 +1,86 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import "portunix.ai/app/aihelp"
+
+// showHelpAI prints machine-readable help for --help-ai
+func showHelpAI() {
+	playbook := aihelp.Argument{Name: "playbook", Type: "path", Required: true, Description: ".ptxbook file"}
+
+	aihelp.Help{
+		Tool:        "ptx-ansible",
+		Version:     version,
+		Description: "Infrastructure as Code with .ptxbook playbooks executed locally, in containers or VMs; the native tasks section (file, template, service, package) runs without Ansible and the terraform section provisions infrastructure with Terraform/OpenTofu whose outputs feed the later steps",
+		Commands: []aihelp.Command{
+			{
+				Name:        "playbook run",
+				Description: "Execute a .ptxbook file",
+				Arguments:   []aihelp.Argument{playbook},
+				Flags: []aihelp.Flag{
+					{Name: "dry-run", Type: "boolean", Description: "Validate without executing"},
+					{Name: "list-scripts", Type: "boolean", Description: "List scripts defined in the playbook"},
+					{Name: "script", Type: "string", Description: "Comma-separated scripts to run"},
+					{Name: "env", Type: "string", Default: "local", Description: "Execution environment; in container, Ansible runs in an ephemeral controller container pinned by requirements.ansible.version", Choices: []string{"local", "container", "virt"}},
+					{Name: "target", Type: "string", Description: "Target container or VM"},
+					{Name: "image", Type: "string", Description: "Container image for --env container"},
+					{Name: "vars-file", Type: "stringArray", Description: "YAML values file overriding the playbook variables, e.g. prod.yaml"},
+					{Name: "set", Type: "stringArray", Description: "Override a variable with key=value; dotted keys set nested values"},
+					{Name: "from-run", Type: "stringArray", Description: "Run ID or playbook name (latest run) whose outputs become {{ .inputs.<name> }}"},
+				},
+				Examples: []string{
+					"portunix playbook run deployment.ptxbook",
+					"portunix playbook run deployment.ptxbook --vars-file prod.yaml --set db.port=5433",
+					"portunix playbook run my-docs.ptxbook --script create,build",
+					"portunix playbook run deployment.ptxbook --env container",
+				},
+			},
+			{
+				Name:        "playbook build",
+				Description: "Generate a production Dockerfile from a playbook",
+				Arguments:   []aihelp.Argument{playbook},
+				Flags: []aihelp.Flag{
+					{Name: "output", Shorthand: "o", Type: "path", Description: "Output Dockerfile path"},
+					{Name: "script", Type: "string", Description: "Build script to use"},
+					{Name: "serve", Type: "string", Description: "Script serving the result"},
+				},
+				Examples: []string{"portunix playbook build my-docs.ptxbook"},
+			},
+			{Name: "playbook validate", Description: "Validate playbook syntax and dependencies", Arguments: []aihelp.Argument{playbook}},
+			{Name: "playbook check", Description: "Check that the ansible helper is available and working"},
+			{Name: "playbook list", Description: "List playbooks in the current directory"},
+			{
+				Name:        "playbook approve",
+				Description: "Approve or reject a run stopped at an approval gate; lists the pending approvals without an ID. The decision is audit logged with the approver",
+				Arguments:   []aihelp.Argument{{Name: "id", Type: "string", Description: "Approval ID printed by the waiting run"}},
+				Flags: []aihelp.Flag{
+					{Name: "reject", Type: "bool", Description: "Reject instead of approve"},
+					{Name: "comment", Type: "string", Description: "Comment recorded with the decision"},
+				},
+				Examples: []string{"portunix playbook approve", "portunix playbook approve deploy-20261017-153012 --reject --comment \"change freeze\""},
+			},
+			{
+				Name:        "playbook runs",
+				Description: "List the persisted runs of playbooks declaring outputs, or show the outputs of one",
+				Arguments:   []aihelp.Argument{{Name: "run", Type: "string", Description: "Run ID or playbook name (latest run)"}},
+				Flags:       []aihelp.Flag{{Name: "json", Type: "bool", Description: "Output as JSON"}},
+				Examples:    []string{"portunix playbook runs", "portunix playbook runs infra --json"},
+			},
+			{
+				Name:        "playbook init",
+				Description: "Generate a playbook from a template",
+				Arguments:   []aihelp.Argument{{Name: "name", Type: "string", Required: true, Description: "Project name"}},
+				Flags: []aihelp.Flag{
+					{Name: "template", Type: "string", Description: "Template name (see playbook template list)"},
+					{Name: "engine", Type: "string", Description: "Template engine variant (e.g. hugo)"},
+					{Name: "target", Type: "string", Description: "Target environment"},
+				},
+				Examples: []string{"portunix playbook init my-docs --template static-docs --engine hugo"},
+			},
+			{Name: "playbook template list", Description: "List available templates"},
+			{Name: "playbook template show", Description: "Show template details", Arguments: []aihelp.Argument{{Name: "name", Type: "string", Required: true}}},
+		},
+	}.Print()
+}
@@ -5,6 +5,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -45,6 +46,10 @@ func handleCommand(args []string) {
 		fmt.Printf("ptx-ansible version %s\n", version)
 		return
 	}
+	if len(args) > 0 && args[0] == "--help-ai" {
+		showHelpAI()
+		return
+	}
 
 	// Handle dispatched commands: playbook
 	if len(args) == 0 {
@@ -100,6 +105,8 @@ func showPlaybookHelp() {
 	fmt.Println("  validate    Validate a .ptxbook file syntax and dependencies")
 	fmt.Println("  check       Check if ptx-ansible helper is available and working")
 	fmt.Println("  list        List available playbooks in current directory")
+	fmt.Println("  runs        List persisted runs and their outputs")
+	fmt.Println("  approve     Approve or reject a run waiting for sign-off")
 	fmt.Println("  init        Generate playbook from template")
 	fmt.Println("  template    Manage playbook templates")
 	fmt.Println("  help        Show this help message")
@@ -121,9 +128,15 @@ func showPlaybookHelp() {
 	fmt.Println("  # Validate playbook without execution")
 	fmt.Println("  portunix playbook run deployment.ptxbook --dry-run")
 	fmt.Println("")
+	fmt.Println("  # Run with the values of an environment")
+	fmt.Println("  portunix playbook run deployment.ptxbook --vars-file prod.yaml --set replicas=3")
+	fmt.Println("")
 	fmt.Println("  # Run in container environment")
 	fmt.Println("  portunix playbook run deployment.ptxbook --env container")
 	fmt.Println("")
+	fmt.Println("  # Chain a playbook to the outputs of the last infra run")
+	fmt.Println("  portunix playbook run config.ptxbook --from-run infra")
+	fmt.Println("")
 	fmt.Println("  # List available templates")
 	fmt.Println("  portunix playbook template list")
 	fmt.Println("")
@@ -168,10 +181,16 @@ func handlePlaybookCommand(args []string) {
 		handlePlaybookCheck()
 	case "list":
 		handlePlaybookList()
+	case "runs":
+		handlePlaybookRuns(subArgs)
+	case "approve":
+		handlePlaybookApprove(subArgs)
 	case "init":
 		handlePlaybookInit(subArgs)
 	case "template":
 		handleTemplateCommand(subArgs)
+	case "--help-ai":
+		showHelpAI()
 	case "--help", "-h", "help":
 		showPlaybookHelp()
 	default:
@@ -191,6 +210,9 @@ func handlePlaybookRun(args []string) {
 		fmt.Println("  --image IMAGE       - Override container image")
 		fmt.Println("  --script SCRIPTS    - Run specific scripts (comma-separated, e.g., init,dev)")
 		fmt.Println("  --list-scripts      - List available scripts in playbook")
+		fmt.Println("  --vars-file FILE    - Override variables with a values file, e.g. prod.yaml (repeatable)")
+		fmt.Println("  --set KEY=VALUE     - Override a variable, e.g. --set db.port=5433 (repeatable)")
+		fmt.Println("  --from-run RUN      - Use the outputs of a run (ID or playbook name) as {{ .inputs.* }} (repeatable)")
 		fmt.Println("\nNote: Environment settings from playbook are used by default.")
 		return
 	}
@@ -274,6 +296,27 @@ func handlePlaybookRun(args []string) {
 				fmt.Println("Error: --image requires an image value")
 				return
 			}
+		case "--vars-file":
+			if i+2 < len(args) {
+				options.VarsFiles = append(options.VarsFiles, args[i+2])
+			} else {
+				fmt.Println("Error: --vars-file requires a file")
+				return
+			}
+		case "--set":
+			if i+2 < len(args) {
+				options.SetVars = append(options.SetVars, args[i+2])
+			} else {
+				fmt.Println("Error: --set requires a key=value assignment")
+				return
+			}
+		case "--from-run":
+			if i+2 < len(args) {
+				options.FromRuns = append(options.FromRuns, args[i+2])
+			} else {
+				fmt.Println("Error: --from-run requires a run ID or playbook name")
+				return
+			}
 		}
 	}
 
@@ -328,6 +371,9 @@ func handlePlaybookRun(args []string) {
 	} else {
 		fmt.Printf("✅ Execution completed successfully\n")
 	}
+	if result.RunID != "" {
+		fmt.Printf("   Run ID: %s (chain with --from-run %s)\n", result.RunID, result.RunID)
+	}
 }
 
 // handlePlaybookBuild generates a production Dockerfile from a playbook (Issue #128 Phase 4)
@@ -474,6 +520,14 @@ func handlePlaybookValidate(args []string) {
 		fmt.Printf("   Portunix packages: %d\n", len(ptxbook.Spec.Portunix.Packages))
 	}
 
+	if ptxbook.Spec.Terraform != nil {
+		fmt.Printf("   Terraform: %s\n", terraformSummary(ptxbook.Spec.Terraform))
+	}
+
+	if len(ptxbook.Spec.Tasks) > 0 {
+		fmt.Printf("   Native tasks: %d\n", len(ptxbook.Spec.Tasks))
+	}
+
 	if ptxbook.Spec.Ansible != nil && len(ptxbook.Spec.Ansible.Playbooks) > 0 {
 		fmt.Printf("   Ansible playbooks: %d\n", len(ptxbook.Spec.Ansible.Playbooks))
 		fmt.Printf("   Requires Ansible: %s\n", GetMinAnsibleVersion(ptxbook))
@@ -493,6 +547,127 @@ func handlePlaybookList() {
 	fmt.Println("Playbook listing not yet implemented")
 }
 
+// handlePlaybookRuns lists the persisted runs, or shows the outputs of one
+func handlePlaybookRuns(args []string) {
+	jsonOutput := false
+	ref := ""
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOutput = true
+		case "--help", "-h":
+			fmt.Println("Usage: portunix playbook runs [RUN] [--json]")
+			fmt.Println("")
+			fmt.Println("List the runs of playbooks declaring outputs, or show the outputs of a run")
+			fmt.Println("given by its ID or playbook name (latest run).")
+			return
+		default:
+			ref = arg
+		}
+	}
+
+	if ref != "" {
+		run, err := LoadRun(ref)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if jsonOutput {
+			data, _ := json.MarshalIndent(run, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		fmt.Printf("Run: %s\n", run.ID)
+		fmt.Printf("   Playbook: %s (%s)\n", run.Playbook, run.File)
+		fmt.Printf("   Environment: %s\n", run.Environment)
+		fmt.Printf("   Finished: %s\n", run.FinishedAt.Format("2006-01-02 15:04:05"))
+		if len(run.FromRuns) > 0 {
+			fmt.Printf("   From runs: %s\n", strings.Join(run.FromRuns, ", "))
+		}
+		fmt.Println("   Outputs:")
+		for _, name := range sortedKeys(run.Outputs) {
+			output := run.Outputs[name]
+			fmt.Printf("     %s (%s): %s\n", name, output.Type, output.Value)
+		}
+		return
+	}
+
+	runs, err := ListRuns()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if jsonOutput {
+		data, _ := json.MarshalIndent(runs, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	if len(runs) == 0 {
+		fmt.Println("No runs with outputs recorded")
+		return
+	}
+	fmt.Printf("%-40s %-20s %-20s %s\n", "ID", "PLAYBOOK", "FINISHED", "OUTPUTS")
+	for _, run := range runs {
+		fmt.Printf("%-40s %-20s %-20s %s\n", run.ID, run.Playbook, run.FinishedAt.Format("2006-01-02 15:04:05"),
+			strings.Join(sortedKeys(run.Outputs), ", "))
+	}
+}
+
+// handlePlaybookApprove decides a pending approval, or lists the pending
+// approvals without an ID
+func handlePlaybookApprove(args []string) {
+	reject := false
+	id, comment := "", ""
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--reject":
+			reject = true
+		case "--comment":
+			if i+1 < len(args) {
+				comment = args[i+1]
+				i++
+			}
+		case "--help", "-h":
+			fmt.Println("Usage: portunix playbook approve [ID] [--reject] [--comment TEXT]")
+			fmt.Println("")
+			fmt.Println("Approve or reject a playbook run waiting for sign-off. Without an ID the")
+			fmt.Println("pending approvals are listed. The decision is recorded in the audit log")
+			fmt.Println("with your user name.")
+			return
+		default:
+			id = args[i]
+		}
+	}
+
+	if id == "" {
+		pending, err := PendingApprovals()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(pending) == 0 {
+			fmt.Println("No pending approvals")
+			return
+		}
+		fmt.Printf("%-40s %-20s %-12s %s\n", "ID", "PLAYBOOK", "REQUESTER", "MESSAGE")
+		for _, req := range pending {
+			fmt.Printf("%-40s %-20s %-12s %s\n", req.ID, req.Playbook, req.RequestedBy, approvalMessage(req))
+		}
+		return
+	}
+
+	req, err := DecideApproval(id, getCurrentUser(), !reject, comment)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if req.Status == ApprovalApproved {
+		fmt.Printf("✅ Approved %s (%s) as %s\n", req.ID, req.Playbook, req.DecidedBy)
+	} else {
+		fmt.Printf("🚫 Rejected %s (%s) as %s\n", req.ID, req.Playbook, req.DecidedBy)
+	}
+}
+
 func handlePlaybookInit(args []string) {
 	// Parse flags
 	var projectName, templateName, engine, target string
@@ -962,8 +1137,9 @@ func handleEnterpriseCommand(args []string) {
 func handleSecretsCommand(args []string) {
 	fmt.Println("🔐 Secrets Management")
 	fmt.Println("   AES-256-GCM encryption for secure secret storage")
-	fmt.Println("   Support for multiple secret stores: vault, env, file")
+	fmt.Println("   Support for multiple secret stores: portunix, vault, env, file")
 	fmt.Println("   Integration with .ptxbook files via {{ secret:store:key }} syntax")
+	fmt.Println("   {{ secret:portunix:<name> }} reads the shared store ('portunix secret set <name>')")
 }
 
 func handleAuditCommand(args []string) {
@@ -26,12 +26,16 @@ type PtxbookMetadata struct {
 
 // PtxbookSpec represents the spec section
 type PtxbookSpec struct {
-	Variables    map[string]interface{}  `yaml:"variables,omitempty" json:"variables,omitempty"`
-	Requirements *PtxbookRequirements    `yaml:"requirements,omitempty" json:"requirements,omitempty"`
-	Portunix     *PtxbookPortunix        `yaml:"portunix,omitempty" json:"portunix,omitempty"`
-	Ansible      *PtxbookAnsible         `yaml:"ansible,omitempty" json:"ansible,omitempty"`
-	Scripts      map[string]string       `yaml:"scripts,omitempty" json:"scripts,omitempty"`         // Custom scripts (init, build, serve, etc.)
-	ScriptsExt   map[string]ScriptConfig `yaml:"scripts_ext,omitempty" json:"scripts_ext,omitempty"` // Extended scripts with conditions (Issue #128)
+	Variables    map[string]interface{}   `yaml:"variables,omitempty" json:"variables,omitempty"`
+	Requirements *PtxbookRequirements     `yaml:"requirements,omitempty" json:"requirements,omitempty"`
+	Portunix     *PtxbookPortunix         `yaml:"portunix,omitempty" json:"portunix,omitempty"`
+	Ansible      *PtxbookAnsible          `yaml:"ansible,omitempty" json:"ansible,omitempty"`
+	Terraform    *PtxbookTerraform        `yaml:"terraform,omitempty" json:"terraform,omitempty"`     // Infrastructure provisioned before the other steps
+	Outputs      map[string]PtxbookOutput `yaml:"outputs,omitempty" json:"outputs,omitempty"`         // Persisted per run for chained playbooks
+	Approval     *PtxbookApproval         `yaml:"approval,omitempty" json:"approval,omitempty"`       // Sign-off required before the run changes anything
+	Tasks        []PtxbookTask            `yaml:"tasks,omitempty" json:"tasks,omitempty"`             // Native tasks, run without Ansible
+	Scripts      map[string]string        `yaml:"scripts,omitempty" json:"scripts,omitempty"`         // Custom scripts (init, build, serve, etc.)
+	ScriptsExt   map[string]ScriptConfig  `yaml:"scripts_ext,omitempty" json:"scripts_ext,omitempty"` // Extended scripts with conditions (Issue #128)
 	// Phase 3: Advanced features
 	Rollback    *PtxbookRollback       `yaml:"rollback,omitempty" json:"rollback,omitempty"`       // Rollback configuration
 	Environment map[string]interface{} `yaml:"environment,omitempty" json:"environment,omitempty"` // Environment configuration (target, runtime, image)
@@ -53,7 +57,9 @@ type PtxbookRequirements struct {
 
 // AnsibleRequirements represents Ansible version requirements
 type AnsibleRequirements struct {
-	MinVersion string `yaml:"min_version,omitempty" json:"min_version,omitempty"`
+	MinVersion      string `yaml:"min_version,omitempty" json:"min_version,omitempty"`
+	Version         string `yaml:"version,omitempty" json:"version,omitempty"`                   // Ansible package of the controller container (--env container)
+	ControllerImage string `yaml:"controller_image,omitempty" json:"controller_image,omitempty"` // Prebuilt controller image with Ansible and community.docker
 }
 
 // PtxbookPortunix represents the Portunix package management section
@@ -69,6 +75,102 @@ type PtxbookPackage struct {
 	Vars    map[string]interface{} `yaml:"vars,omitempty" json:"vars,omitempty"` // Phase 3: Package-specific variables
 }
 
+// PtxbookTask represents a native task executed by ptx-ansible itself, without
+// Ansible. Exactly one module (file, template, service, package) is set.
+type PtxbookTask struct {
+	Name     string        `yaml:"name,omitempty" json:"name,omitempty"`
+	When     string        `yaml:"when,omitempty" json:"when,omitempty"`
+	File     *FileTask     `yaml:"file,omitempty" json:"file,omitempty"`
+	Template *TemplateTask `yaml:"template,omitempty" json:"template,omitempty"`
+	Service  *ServiceTask  `yaml:"service,omitempty" json:"service,omitempty"`
+	Package  *PackageTask  `yaml:"package,omitempty" json:"package,omitempty"`
+	Pause    *PauseTask    `yaml:"pause,omitempty" json:"pause,omitempty"`
+	Approval *ApprovalTask `yaml:"approval,omitempty" json:"approval,omitempty"`
+}
+
+// FileTask manages a file or directory
+type FileTask struct {
+	Path    string `yaml:"path" json:"path"`
+	State   string `yaml:"state,omitempty" json:"state,omitempty"`     // "file" (default), "directory" or "absent"
+	Content string `yaml:"content,omitempty" json:"content,omitempty"` // Content of a file, variables are substituted
+	Mode    string `yaml:"mode,omitempty" json:"mode,omitempty"`       // Octal permissions, e.g. "0644"
+}
+
+// TemplateTask renders a template file with the playbook variables
+type TemplateTask struct {
+	Src  string `yaml:"src" json:"src"` // Relative to the .ptxbook file
+	Dest string `yaml:"dest" json:"dest"`
+	Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
+}
+
+// ServiceTask manages a system service
+type ServiceTask struct {
+	Name    string `yaml:"name" json:"name"`
+	State   string `yaml:"state,omitempty" json:"state,omitempty"`     // "started", "stopped" or "restarted"
+	Enabled *bool  `yaml:"enabled,omitempty" json:"enabled,omitempty"` // Start at boot
+}
+
+// PackageTask installs or removes a package with the portunix install engine
+type PackageTask struct {
+	Name    string `yaml:"name" json:"name"`
+	Variant string `yaml:"variant,omitempty" json:"variant,omitempty"`
+	State   string `yaml:"state,omitempty" json:"state,omitempty"` // "present" (default) or "absent"
+}
+
+// PtxbookTerraform provisions infrastructure with Terraform or OpenTofu. Its
+// outputs become the variable 'terraform', e.g. {{ .terraform.server_ip }}.
+type PtxbookTerraform struct {
+	Dir       string                 `yaml:"dir,omitempty" json:"dir,omitempty"`             // Configuration directory, relative to the .ptxbook file (default ".")
+	Binary    string                 `yaml:"binary,omitempty" json:"binary,omitempty"`       // "terraform" or "tofu", detected when empty
+	Workspace string                 `yaml:"workspace,omitempty" json:"workspace,omitempty"` // Selected, and created when missing
+	Backend   map[string]string      `yaml:"backend,omitempty" json:"backend,omitempty"`     // State backend settings passed as -backend-config
+	Vars      map[string]interface{} `yaml:"vars,omitempty" json:"vars,omitempty"`           // Input variables
+	VarFiles  []string               `yaml:"var_files,omitempty" json:"var_files,omitempty"` // .tfvars files, relative to dir
+	PlanOnly  bool                   `yaml:"plan_only,omitempty" json:"plan_only,omitempty"` // Plan without applying; outputs come from the current state
+	Inventory *TerraformInventory    `yaml:"inventory,omitempty" json:"inventory,omitempty"` // Ansible targets taken from an output
+}
+
+// TerraformInventory makes Ansible playbooks target the hosts of a Terraform
+// output instead of localhost
+type TerraformInventory struct {
+	Output string `yaml:"output" json:"output"` // Output holding a host or a list of hosts
+	User   string `yaml:"user,omitempty" json:"user,omitempty"`
+	SSHKey string `yaml:"ssh_key,omitempty" json:"ssh_key,omitempty"` // Private key file
+}
+
+// PtxbookOutput is a value a run hands over to the playbooks chained after it
+// with --from-run. Exactly one of Value, Path and Secret is set.
+type PtxbookOutput struct {
+	Value       string `yaml:"value,omitempty" json:"value,omitempty"`   // Rendered with the variables, e.g. an endpoint
+	Path        string `yaml:"path,omitempty" json:"path,omitempty"`     // Artifact file, relative to the .ptxbook file
+	Secret      string `yaml:"secret,omitempty" json:"secret,omitempty"` // Secret reference "store:key" or "key", persisted unresolved
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// PauseTask waits a number of seconds, or for Enter when Seconds is 0
+type PauseTask struct {
+	Prompt  string `yaml:"prompt,omitempty" json:"prompt,omitempty"`
+	Seconds int    `yaml:"seconds,omitempty" json:"seconds,omitempty"`
+}
+
+// ApprovalTask stops the run until an approver signs it off, interactively
+// or with 'portunix playbook approve'
+type ApprovalTask struct {
+	Message      string   `yaml:"message,omitempty" json:"message,omitempty"`
+	Approvers    []string `yaml:"approvers,omitempty" json:"approvers,omitempty"`         // Users allowed to approve, anyone when empty
+	Notify       []string `yaml:"notify,omitempty" json:"notify,omitempty"`               // "desktop", "email" and/or "slack"
+	EmailTo      []string `yaml:"email_to,omitempty" json:"email_to,omitempty"`           // Recipients, sent with the SMTP settings of .pft-config.json
+	SlackWebhook string   `yaml:"slack_webhook,omitempty" json:"slack_webhook,omitempty"` // Incoming webhook URL or "secret:<name>"
+	Timeout      string   `yaml:"timeout,omitempty" json:"timeout,omitempty"`             // Maximum wait, e.g. "30m" (default 1h)
+}
+
+// PtxbookApproval is the approval gate of a whole run, requested before
+// anything is changed
+type PtxbookApproval struct {
+	When         string `yaml:"when,omitempty" json:"when,omitempty"`
+	ApprovalTask `yaml:",inline" json:",inline"`
+}
+
 // PtxbookAnsible represents the Ansible playbooks section
 type PtxbookAnsible struct {
 	Playbooks []AnsiblePlaybook `yaml:"playbooks,omitempty" json:"playbooks,omitempty"`
@@ -136,12 +238,21 @@ func ValidatePtxbookFile(ptxbook *PtxbookFile) error {
 		return fmt.Errorf("metadata.name is required")
 	}
 
-	// Validate that at least Portunix, Ansible, or Scripts section exists
+	// Validate that at least Portunix, Ansible, Tasks, Terraform, or Scripts section exists
 	hasPortunix := ptxbook.Spec.Portunix != nil && len(ptxbook.Spec.Portunix.Packages) > 0
 	hasAnsible := ptxbook.Spec.Ansible != nil && len(ptxbook.Spec.Ansible.Playbooks) > 0
+	hasTasks := len(ptxbook.Spec.Tasks) > 0
+	hasTerraform := ptxbook.Spec.Terraform != nil
 	hasScripts := len(ptxbook.Spec.Scripts) > 0
-	if !hasPortunix && !hasAnsible && !hasScripts {
-		return fmt.Errorf("spec must contain at least one of: 'portunix', 'ansible', or 'scripts' section")
+	if !hasPortunix && !hasAnsible && !hasTasks && !hasTerraform && !hasScripts {
+		return fmt.Errorf("spec must contain at least one of: 'portunix', 'ansible', 'tasks', 'terraform', or 'scripts' section")
+	}
+
+	// Validate Terraform configuration if present
+	if hasTerraform {
+		if err := validateTerraform(ptxbook.Spec.Terraform); err != nil {
+			return fmt.Errorf("spec.terraform: %v", err)
+		}
 	}
 
 	// Validate Portunix packages if present
@@ -153,6 +264,27 @@ func ValidatePtxbookFile(ptxbook *PtxbookFile) error {
 		}
 	}
 
+	// Validate native tasks if present
+	for i, task := range ptxbook.Spec.Tasks {
+		if err := validateTask(task); err != nil {
+			return fmt.Errorf("spec.tasks[%d]: %v", i, err)
+		}
+	}
+
+	// Validate the approval gate if present
+	if ptxbook.Spec.Approval != nil {
+		if err := validateApproval(&ptxbook.Spec.Approval.ApprovalTask); err != nil {
+			return fmt.Errorf("spec.approval: %v", err)
+		}
+	}
+
+	// Validate outputs if present
+	for name, output := range ptxbook.Spec.Outputs {
+		if err := validateOutput(output); err != nil {
+			return fmt.Errorf("spec.outputs.%s: %v", name, err)
+		}
+	}
+
 	// Validate Ansible playbooks if present
 	if ptxbook.Spec.Ansible != nil {
 		for i, playbook := range ptxbook.Spec.Ansible.Playbooks {
@@ -8,6 +8,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"portunix.ai/app/fileutil"
 )
 
 // Permission represents a specific permission
@@ -532,7 +534,7 @@ func (rbac *RBACManager) saveData() error {
 		return fmt.Errorf("failed to marshal RBAC data: %w", err)
 	}
 
-	if err := os.WriteFile(rbac.dataFile, data, 0640); err != nil {
+	if err := fileutil.WriteFile(rbac.dataFile, data, 0640); err != nil {
 		return fmt.Errorf("failed to write RBAC data file: %w", err)
 	}
 
@@ -0,0 +1,248 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Runs of playbooks that declare outputs are persisted in
+// ~/.portunix/playbook-runs/<id>.json. 'playbook run --from-run <id>' makes
+// the outputs of such a run the variable 'inputs' of the next playbook, so
+// infra → config → app pipelines are composed of separate playbooks.
+
+// PlaybookRun is the persisted record of a run
+type PlaybookRun struct {
+	ID          string               `json:"id"`
+	Playbook    string               `json:"playbook"`
+	File        string               `json:"file"`
+	Environment string               `json:"environment"`
+	User        string               `json:"user"`
+	StartedAt   time.Time            `json:"started_at"`
+	FinishedAt  time.Time            `json:"finished_at"`
+	FromRuns    []string             `json:"from_runs,omitempty"`
+	Outputs     map[string]RunOutput `json:"outputs"`
+}
+
+// RunOutput is a rendered output of a run
+type RunOutput struct {
+	Type        string `json:"type"` // "value", "path" or "secret"
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// validateOutput checks that an output sets exactly one of value, path and
+// secret
+func validateOutput(output PtxbookOutput) error {
+	set := 0
+	for _, field := range []string{output.Value, output.Path, output.Secret} {
+		if field != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of 'value', 'path' or 'secret' is required")
+	}
+	return nil
+}
+
+// runsDir returns the directory of the persisted runs
+func runsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".portunix", "playbook-runs"), nil
+}
+
+// newRunID returns the ID of a run of the named playbook, e.g.
+// infra-20261017-153012
+func newRunID(name string, t time.Time) string {
+	id := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(name))
+	return id + "-" + t.Format("20060102-150405")
+}
+
+// renderOutputs renders the outputs of the playbook after a run. Relative
+// artifact paths resolve against baseDir, the directory of the .ptxbook file;
+// local runs also check that the artifacts exist.
+func renderOutputs(ptxbook *PtxbookFile, baseDir string, local bool) (map[string]RunOutput, error) {
+	engine := NewTemplateEngine(ptxbook.Spec.Variables, ptxbook.Spec.Environment)
+	outputs := make(map[string]RunOutput, len(ptxbook.Spec.Outputs))
+
+	for name, output := range ptxbook.Spec.Outputs {
+		rendered := RunOutput{Description: output.Description}
+		switch {
+		case output.Secret != "":
+			// Never persist the secret itself, the consumer resolves it
+			rendered.Type = "secret"
+			rendered.Value = "{{ secret:" + output.Secret + " }}"
+		case output.Path != "":
+			path, err := engine.ProcessTemplate(output.Path)
+			if err != nil {
+				return nil, fmt.Errorf("output %s: %v", name, err)
+			}
+			if local {
+				if !filepath.IsAbs(path) {
+					path = filepath.Join(baseDir, path)
+				}
+				if path, err = filepath.Abs(path); err != nil {
+					return nil, fmt.Errorf("output %s: %v", name, err)
+				}
+				if _, err := os.Stat(path); err != nil {
+					return nil, fmt.Errorf("output %s: artifact not found: %s", name, path)
+				}
+			}
+			rendered.Type = "path"
+			rendered.Value = path
+		default:
+			value, err := engine.ProcessTemplate(output.Value)
+			if err != nil {
+				return nil, fmt.Errorf("output %s: %v", name, err)
+			}
+			rendered.Type = "value"
+			rendered.Value = value
+		}
+		outputs[name] = rendered
+	}
+	return outputs, nil
+}
+
+// SaveRun persists a run; an ID already taken gets a numeric suffix
+func SaveRun(run *PlaybookRun) error {
+	dir, err := runsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create runs directory: %v", err)
+	}
+
+	run.ID = uniqueID(dir, run.ID)
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, run.ID+".json"), data, 0600)
+}
+
+// uniqueID returns id, with a numeric suffix when dir already has a record
+// of that ID
+func uniqueID(dir, id string) string {
+	unique := id
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(dir, unique+".json")); os.IsNotExist(err) {
+			return unique
+		}
+		unique = fmt.Sprintf("%s-%d", id, i)
+	}
+}
+
+// ListRuns returns the persisted runs, latest first
+func ListRuns() ([]*PlaybookRun, error) {
+	dir, err := runsDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []*PlaybookRun
+	for _, file := range files {
+		run, err := readRun(file)
+		if err != nil {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].FinishedAt.After(runs[j].FinishedAt)
+	})
+	return runs, nil
+}
+
+// LoadRun returns the run with the given ID, or the latest run of the
+// playbook of that name
+func LoadRun(ref string) (*PlaybookRun, error) {
+	dir, err := runsDir()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.ContainsAny(ref, `/\`) {
+		if run, err := readRun(filepath.Join(dir, ref+".json")); err == nil {
+			return run, nil
+		}
+	}
+
+	runs, err := ListRuns()
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs {
+		if run.Playbook == ref {
+			return run, nil
+		}
+	}
+	return nil, fmt.Errorf("run %q not found, see 'portunix playbook runs'", ref)
+}
+
+func readRun(file string) (*PlaybookRun, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var run PlaybookRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("invalid run %s: %v", file, err)
+	}
+	return &run, nil
+}
+
+// applyRunInputs sets the outputs of the referenced runs as the variable
+// 'inputs', later runs overriding earlier ones. It returns the IDs of the
+// runs.
+func applyRunInputs(ptxbook *PtxbookFile, refs []string) ([]string, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	inputs := map[string]interface{}{}
+	var ids []string
+	hasSecrets := false
+	for _, ref := range refs {
+		run, err := LoadRun(ref)
+		if err != nil {
+			return nil, err
+		}
+		for name, output := range run.Outputs {
+			inputs[name] = output.Value
+			hasSecrets = hasSecrets || output.Type == "secret"
+		}
+		ids = append(ids, run.ID)
+	}
+
+	if ptxbook.Spec.Variables == nil {
+		ptxbook.Spec.Variables = map[string]interface{}{}
+	}
+	mergeVariables(ptxbook.Spec.Variables, map[string]interface{}{"inputs": inputs})
+
+	// Secret references are resolved like those of the playbook itself
+	if hasSecrets && ptxbook.Spec.Secrets == nil {
+		ptxbook.Spec.Secrets = map[string]interface{}{}
+	}
+	return ids, nil
+}
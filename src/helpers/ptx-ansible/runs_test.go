@@ -0,0 +1,91 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewRunID(t *testing.T) {
+	at := time.Date(2026, 10, 17, 15, 30, 12, 0, time.UTC)
+	if id := newRunID("Edge Infra", at); id != "edge-infra-20261017-153012" {
+		t.Errorf("unexpected run id %s", id)
+	}
+}
+
+func TestRenderOutputs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "kubeconfig"), []byte("cfg"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ptxbook := &PtxbookFile{Spec: PtxbookSpec{
+		Variables: map[string]interface{}{"host": "203.0.113.10"},
+		Outputs: map[string]PtxbookOutput{
+			"endpoint":    {Value: "https://{{ .host }}"},
+			"kubeconfig":  {Path: "kubeconfig"},
+			"db_password": {Secret: "file:db_password"},
+		},
+	}}
+	outputs, err := renderOutputs(ptxbook, dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]RunOutput{
+		"endpoint":    {Type: "value", Value: "https://203.0.113.10"},
+		"kubeconfig":  {Type: "path", Value: filepath.Join(dir, "kubeconfig")},
+		"db_password": {Type: "secret", Value: "{{ secret:file:db_password }}"},
+	}
+	for name, output := range want {
+		if outputs[name] != output {
+			t.Errorf("%s: got %+v, want %+v", name, outputs[name], output)
+		}
+	}
+
+	ptxbook.Spec.Outputs = map[string]PtxbookOutput{"missing": {Path: "missing.tar"}}
+	if _, err := renderOutputs(ptxbook, dir, true); err == nil {
+		t.Error("a missing artifact should fail a local run")
+	}
+	if _, err := renderOutputs(ptxbook, dir, false); err != nil {
+		t.Errorf("artifacts of other environments are not checked: %v", err)
+	}
+}
+
+func TestRunChaining(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+
+	for i, endpoint := range []string{"https://old", "https://new"} {
+		run := &PlaybookRun{
+			ID:         fmt.Sprintf("infra-20261017-15301%d", i),
+			Playbook:   "infra",
+			FinishedAt: time.Date(2026, 10, 17, 15, 30, 10+i, 0, time.UTC),
+			Outputs:    map[string]RunOutput{"endpoint": {Type: "value", Value: endpoint}},
+		}
+		if err := SaveRun(run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ptxbook := &PtxbookFile{}
+	ids, err := applyRunInputs(ptxbook, []string{"infra"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := ptxbook.Spec.Variables["inputs"].(map[string]interface{})
+	if len(ids) != 1 || ids[0] != "infra-20261017-153011" || inputs["endpoint"] != "https://new" {
+		t.Errorf("expected the latest run, got %v %v", ids, inputs)
+	}
+
+	if run, err := LoadRun("infra-20261017-153010"); err != nil || run.Outputs["endpoint"].Value != "https://old" {
+		t.Errorf("run by id: %v %v", run, err)
+	}
+	if _, err := LoadRun("config"); err == nil {
+		t.Error("unknown run should fail")
+	}
+}
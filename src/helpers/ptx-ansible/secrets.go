@@ -17,11 +17,14 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"portunix.ai/app/fileutil"
+	"portunix.ai/app/secret"
 )
 
 // SecretStore represents a secure secret storage system
 type SecretStore struct {
-	Type       string                 `json:"type"`       // "file", "env", "vault", "aws", "azure"
+	Type       string                 `json:"type"`       // "portunix", "file", "env", "vault", "aws", "azure"
 	Config     map[string]interface{} `json:"config"`     // Store-specific configuration
 	Encryption *EncryptionConfig      `json:"encryption"` // Encryption settings
 }
@@ -42,6 +45,11 @@ type SecretManager struct {
 	auditMgr      *AuditManager
 }
 
+// portunixStore is the store backed by the shared 'portunix secret' store.
+// It is available under this name even when the configuration does not
+// define it.
+const portunixStore = "portunix"
+
 // SecretValue represents an encrypted secret
 type SecretValue struct {
 	Value       string            `json:"value"`
@@ -101,8 +109,11 @@ func (sm *SecretManager) LoadSecretStores(configPath string) error {
 // createDefaultConfig creates a default secret store configuration
 func (sm *SecretManager) createDefaultConfig(configPath string) error {
 	defaultConfig := map[string]interface{}{
-		"default_store": "file",
+		"default_store": portunixStore,
 		"stores": map[string]*SecretStore{
+			portunixStore: {
+				Type: "portunix",
+			},
 			"file": {
 				Type: "file",
 				Config: map[string]interface{}{
@@ -136,7 +147,7 @@ func (sm *SecretManager) createDefaultConfig(configPath string) error {
 		return err
 	}
 
-	return os.WriteFile(configPath, data, 0600)
+	return fileutil.WriteFile(configPath, data, 0600)
 }
 
 // initializeEncryption sets up encryption for the secret manager
@@ -238,9 +249,16 @@ func (sm *SecretManager) ProcessSecretReferences(ptxbook *PtxbookFile) error {
 	return nil
 }
 
-// processVariableSecrets processes secret references in variables
+// processVariableSecrets processes secret references in variables, including
+// nested maps such as the inputs of chained runs
 func (sm *SecretManager) processVariableSecrets(variables map[string]interface{}, context *ExecutionContext) error {
 	for key, value := range variables {
+		if nested, ok := value.(map[string]interface{}); ok {
+			if err := sm.processVariableSecrets(nested, context); err != nil {
+				return err
+			}
+			continue
+		}
 		if strValue, ok := value.(string); ok {
 			if resolved, err := sm.resolveSecretReference(strValue, context); err != nil {
 				return fmt.Errorf("failed to resolve secret in variable %s: %v", key, err)
@@ -319,14 +337,27 @@ func (sm *SecretManager) resolveSecretReference(input string, context *Execution
 	}), nil
 }
 
+// lookupStore returns a configured store, or the shared portunix store
+func (sm *SecretManager) lookupStore(storeName string) (*SecretStore, error) {
+	if store, exists := sm.stores[storeName]; exists {
+		return store, nil
+	}
+	if storeName == portunixStore {
+		return &SecretStore{Type: "portunix"}, nil
+	}
+	return nil, fmt.Errorf("secret store '%s' not found", storeName)
+}
+
 // GetSecret retrieves a secret from the specified store
 func (sm *SecretManager) GetSecret(storeName, key string, context *ExecutionContext) (string, error) {
-	store, exists := sm.stores[storeName]
-	if !exists {
-		return "", fmt.Errorf("secret store '%s' not found", storeName)
+	store, err := sm.lookupStore(storeName)
+	if err != nil {
+		return "", err
 	}
 
 	switch store.Type {
+	case "portunix":
+		return secret.Get(key, "ansible")
 	case "file":
 		return sm.getFileSecret(store, key, context)
 	case "env":
@@ -403,15 +434,17 @@ func (sm *SecretManager) getVaultSecret(store *SecretStore, key string, context
 
 // SetSecret stores a secret in the specified store
 func (sm *SecretManager) SetSecret(storeName, key, value string, context *ExecutionContext) error {
-	store, exists := sm.stores[storeName]
-	if !exists {
-		return fmt.Errorf("secret store '%s' not found", storeName)
+	store, err := sm.lookupStore(storeName)
+	if err != nil {
+		return err
 	}
 
 	// Check permissions
 	// Access control check would be implemented here
 
 	switch store.Type {
+	case "portunix":
+		return secret.Set(key, value, "", "ansible")
 	case "file":
 		return sm.setFileSecret(store, key, value, context)
 	case "env":
@@ -458,7 +491,7 @@ func (sm *SecretManager) setFileSecret(store *SecretStore, key, value string, co
 		data = []byte(value)
 	}
 
-	if err := os.WriteFile(secretFile, data, 0600); err != nil {
+	if err := fileutil.WriteFile(secretFile, data, 0600); err != nil {
 		return fmt.Errorf("failed to write secret file: %v", err)
 	}
 
@@ -0,0 +1,457 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"portunix.ai/app/shellquote"
+)
+
+// Native tasks configure the target without Ansible. Locally they are
+// executed by ptx-ansible itself; in container and VM environments each task
+// becomes a POSIX shell command run inside the environment.
+
+// validateTask checks that a task sets exactly one module with valid fields
+func validateTask(task PtxbookTask) error {
+	modules := 0
+	for _, set := range []bool{task.File != nil, task.Template != nil, task.Service != nil, task.Package != nil, task.Pause != nil, task.Approval != nil} {
+		if set {
+			modules++
+		}
+	}
+	if modules != 1 {
+		return fmt.Errorf("exactly one of 'file', 'template', 'service', 'package', 'pause' or 'approval' is required")
+	}
+
+	switch {
+	case task.File != nil:
+		if task.File.Path == "" {
+			return fmt.Errorf("file.path is required")
+		}
+		if !oneOf(task.File.State, "", "file", "directory", "absent") {
+			return fmt.Errorf("file.state must be file, directory or absent, got %q", task.File.State)
+		}
+		if task.File.Content != "" && task.File.State != "" && task.File.State != "file" {
+			return fmt.Errorf("file.content is only valid with state file")
+		}
+		return validateMode(task.File.Mode)
+	case task.Template != nil:
+		if task.Template.Src == "" || task.Template.Dest == "" {
+			return fmt.Errorf("template.src and template.dest are required")
+		}
+		return validateMode(task.Template.Mode)
+	case task.Service != nil:
+		if task.Service.Name == "" {
+			return fmt.Errorf("service.name is required")
+		}
+		if !oneOf(task.Service.State, "", "started", "stopped", "restarted") {
+			return fmt.Errorf("service.state must be started, stopped or restarted, got %q", task.Service.State)
+		}
+		if task.Service.State == "" && task.Service.Enabled == nil {
+			return fmt.Errorf("service needs a state or enabled")
+		}
+	case task.Package != nil:
+		if task.Package.Name == "" {
+			return fmt.Errorf("package.name is required")
+		}
+		if !oneOf(task.Package.State, "", "present", "absent") {
+			return fmt.Errorf("package.state must be present or absent, got %q", task.Package.State)
+		}
+	case task.Pause != nil:
+		if task.Pause.Seconds < 0 {
+			return fmt.Errorf("pause.seconds must not be negative")
+		}
+	case task.Approval != nil:
+		if err := validateApproval(task.Approval); err != nil {
+			return fmt.Errorf("approval: %v", err)
+		}
+	}
+	return nil
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+func validateMode(mode string) error {
+	if mode == "" {
+		return nil
+	}
+	if _, err := strconv.ParseUint(mode, 8, 32); err != nil {
+		return fmt.Errorf("invalid mode %q, expected octal permissions such as \"0644\"", mode)
+	}
+	return nil
+}
+
+// taskName returns the name shown for a task
+func taskName(task PtxbookTask) string {
+	if task.Name != "" {
+		return task.Name
+	}
+	switch {
+	case task.File != nil:
+		return "file " + task.File.Path
+	case task.Template != nil:
+		return "template " + task.Template.Dest
+	case task.Service != nil:
+		return "service " + task.Service.Name
+	case task.Package != nil:
+		return "package " + task.Package.Name
+	case task.Pause != nil:
+		return "pause"
+	case task.Approval != nil:
+		return "approval"
+	}
+	return "task"
+}
+
+// executeTasks runs the native tasks of the playbook in order; baseDir is the
+// directory of the .ptxbook file, against which template sources resolve
+func executeTasks(ptxbook *PtxbookFile, baseDir string, options ExecutionOptions, envCtx *EnvironmentContext, rollbackManager *RollbackManager) error {
+	engine := NewTemplateEngine(ptxbook.Spec.Variables, ptxbook.Spec.Environment)
+
+	for _, task := range ptxbook.Spec.Tasks {
+		task, err := processTaskVariables(task, engine)
+		if err != nil {
+			return err
+		}
+		name := taskName(task)
+
+		if task.When != "" && !engine.evaluateCondition(task.When) {
+			if options.Verbose {
+				fmt.Printf("   Skipping task '%s' (condition not met: %s)\n", name, task.When)
+			}
+			continue
+		}
+
+		if options.DryRun {
+			fmt.Printf("   [DRY-RUN] Would run task '%s'\n", name)
+			continue
+		}
+		if options.Verbose {
+			fmt.Printf("   Running task '%s'...\n", name)
+		}
+
+		// Pauses and approvals stop the run on this machine, whatever the
+		// environment
+		if task.Pause != nil || task.Approval != nil {
+			if task.Pause != nil {
+				err = runPause(task.Pause)
+			} else {
+				err = requestApproval(ptxbook, task.Approval, options)
+			}
+			if err != nil {
+				return fmt.Errorf("task '%s' failed: %v", name, err)
+			}
+			continue
+		}
+
+		// A template is a file with rendered content
+		if task.Template != nil {
+			content, err := renderTemplate(task.Template, baseDir, engine)
+			if err != nil {
+				return fmt.Errorf("task '%s' failed: %v", name, err)
+			}
+			task.File = &FileTask{Path: task.Template.Dest, Content: content, Mode: task.Template.Mode}
+			task.Template = nil
+		}
+
+		// Local file and service tasks do not need portunix
+		var portunixPath string
+		if task.Package != nil || envCtx != nil {
+			if portunixPath, err = getPortunixBinaryPath(); err != nil {
+				return fmt.Errorf("failed to find portunix binary: %v", err)
+			}
+		}
+
+		if task.Package != nil {
+			err = runPackageTask(task.Package, portunixPath, options, envCtx, rollbackManager)
+		} else if envCtx != nil {
+			var command string
+			if command, err = taskShellCommand(task); err == nil {
+				err = runShellTask(command, portunixPath, options, envCtx)
+			}
+		} else {
+			err = runLocalTask(task, options)
+		}
+		if err != nil {
+			return fmt.Errorf("task '%s' failed: %v", name, err)
+		}
+
+		if options.Verbose {
+			fmt.Printf("   ✓ Task '%s' completed\n", name)
+		}
+	}
+	return nil
+}
+
+// processTaskVariables substitutes the playbook variables in the fields of a
+// task; template files are rendered when the task runs
+func processTaskVariables(task PtxbookTask, engine *TemplateEngine) (PtxbookTask, error) {
+	var err error
+	process := func(s *string) {
+		if err == nil && *s != "" {
+			*s, err = engine.ProcessTemplate(*s)
+		}
+	}
+	switch {
+	case task.File != nil:
+		f := *task.File
+		process(&f.Path)
+		process(&f.Content)
+		task.File = &f
+	case task.Template != nil:
+		t := *task.Template
+		process(&t.Src)
+		process(&t.Dest)
+		task.Template = &t
+	case task.Service != nil:
+		s := *task.Service
+		process(&s.Name)
+		task.Service = &s
+	case task.Package != nil:
+		p := *task.Package
+		process(&p.Name)
+		process(&p.Variant)
+		task.Package = &p
+	case task.Pause != nil:
+		p := *task.Pause
+		process(&p.Prompt)
+		task.Pause = &p
+	case task.Approval != nil:
+		a := *task.Approval
+		process(&a.Message)
+		task.Approval = &a
+	}
+	if err != nil {
+		return task, fmt.Errorf("failed to process variables of task '%s': %v", taskName(task), err)
+	}
+	return task, nil
+}
+
+// renderTemplate reads a template file and substitutes the playbook variables
+func renderTemplate(t *TemplateTask, baseDir string, engine *TemplateEngine) (string, error) {
+	src := t.Src
+	if !filepath.IsAbs(src) {
+		src = filepath.Join(baseDir, src)
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template: %v", err)
+	}
+	return engine.ProcessTemplate(string(data))
+}
+
+// runPackageTask installs or removes a package with portunix in the
+// execution environment
+func runPackageTask(p *PackageTask, portunixPath string, options ExecutionOptions, envCtx *EnvironmentContext, rollbackManager *RollbackManager) error {
+	args := []string{"install", p.Name}
+	if p.State == "absent" {
+		args = []string{"uninstall", p.Name}
+	}
+	if p.Variant != "" && p.State != "absent" {
+		args = append(args, "--variant", p.Variant)
+	}
+	cmd := portunixCommand(portunixPath, envCtx, args...)
+	if options.Verbose {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+	err := cmd.Run()
+
+	if p.State != "absent" {
+		environment := "local"
+		if envCtx != nil {
+			environment = envCtx.Type
+		}
+		rollbackManager.RecordAction("package_install", p.Name, p.Variant, environment, err == nil)
+	}
+	return err
+}
+
+// runLocalTask executes a file or service task on this machine
+func runLocalTask(task PtxbookTask, options ExecutionOptions) error {
+	switch {
+	case task.File != nil:
+		return applyFileTask(task.File)
+	case task.Service != nil:
+		for _, args := range serviceCommands(runtime.GOOS, task.Service) {
+			cmd := exec.Command(args[0], args[1:]...)
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+			if options.Verbose {
+				cmd.Stdout = os.Stdout
+			}
+			if err := cmd.Run(); err != nil {
+				return fmt.Errorf("%s: %v %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+			}
+		}
+	}
+	return nil
+}
+
+// applyFileTask brings a local file or directory to the state of the task.
+// A file is only rewritten when its content differs.
+func applyFileTask(f *FileTask) error {
+	var mode os.FileMode
+	if f.Mode != "" {
+		m, err := strconv.ParseUint(f.Mode, 8, 32)
+		if err != nil {
+			return err
+		}
+		mode = os.FileMode(m)
+	}
+
+	switch f.State {
+	case "absent":
+		return os.RemoveAll(f.Path)
+	case "directory":
+		if err := os.MkdirAll(f.Path, 0755); err != nil {
+			return err
+		}
+	default:
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+			return err
+		}
+		current, err := os.ReadFile(f.Path)
+		switch {
+		case os.IsNotExist(err) || (err == nil && f.Content != "" && string(current) != f.Content):
+			if err := os.WriteFile(f.Path, []byte(f.Content), 0644); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		}
+	}
+	if mode != 0 {
+		return os.Chmod(f.Path, mode)
+	}
+	return nil
+}
+
+// serviceCommands returns the commands bringing a service to the state of
+// the task with the service manager of goos: systemd on Linux, launchd on
+// macOS and the service control manager on Windows
+func serviceCommands(goos string, s *ServiceTask) [][]string {
+	var commands [][]string
+	switch goos {
+	case "windows":
+		if s.Enabled != nil {
+			startType := "demand"
+			if *s.Enabled {
+				startType = "auto"
+			}
+			commands = append(commands, []string{"sc.exe", "config", s.Name, "start=", startType})
+		}
+		switch s.State {
+		case "started":
+			commands = append(commands, []string{"sc.exe", "start", s.Name})
+		case "stopped":
+			commands = append(commands, []string{"sc.exe", "stop", s.Name})
+		case "restarted":
+			commands = append(commands, []string{"powershell", "-NoProfile", "-Command", "Restart-Service -Name '" + s.Name + "'"})
+		}
+	case "darwin":
+		target := "system/" + s.Name
+		if s.Enabled != nil {
+			action := "disable"
+			if *s.Enabled {
+				action = "enable"
+			}
+			commands = append(commands, []string{"launchctl", action, target})
+		}
+		switch s.State {
+		case "started":
+			commands = append(commands, []string{"launchctl", "kickstart", target})
+		case "stopped":
+			commands = append(commands, []string{"launchctl", "kill", "SIGTERM", target})
+		case "restarted":
+			commands = append(commands, []string{"launchctl", "kickstart", "-k", target})
+		}
+	default:
+		if s.Enabled != nil {
+			action := "disable"
+			if *s.Enabled {
+				action = "enable"
+			}
+			commands = append(commands, []string{"systemctl", action, s.Name})
+		}
+		switch s.State {
+		case "started":
+			commands = append(commands, []string{"systemctl", "start", s.Name})
+		case "stopped":
+			commands = append(commands, []string{"systemctl", "stop", s.Name})
+		case "restarted":
+			commands = append(commands, []string{"systemctl", "restart", s.Name})
+		}
+	}
+	return commands
+}
+
+// taskShellCommand returns the POSIX shell command executing a file or
+// service task inside a container or VM
+func taskShellCommand(task PtxbookTask) (string, error) {
+	var f *FileTask
+	switch {
+	case task.File != nil:
+		f = task.File
+	case task.Service != nil:
+		var lines []string
+		for _, args := range serviceCommands("linux", task.Service) {
+			lines = append(lines, shellquote.Join(args))
+		}
+		return strings.Join(lines, " && "), nil
+	default:
+		return "", fmt.Errorf("no shell command for task '%s'", taskName(task))
+	}
+
+	path := shellquote.Quote(f.Path)
+	var command string
+	switch f.State {
+	case "absent":
+		return "rm -rf " + path, nil
+	case "directory":
+		command = "mkdir -p " + path
+	default:
+		command = fmt.Sprintf("mkdir -p \"$(dirname %s)\" && ", path)
+		if f.Content != "" {
+			command += fmt.Sprintf("printf '%%s' %s > %s", shellquote.Quote(f.Content), path)
+		} else {
+			command += "touch " + path
+		}
+	}
+	if f.Mode != "" {
+		command += fmt.Sprintf(" && chmod %s %s", f.Mode, path)
+	}
+	return command, nil
+}
+
+// runShellTask runs a task command inside the container or VM
+func runShellTask(command, portunixPath string, options ExecutionOptions, envCtx *EnvironmentContext) error {
+	var cmd *exec.Cmd
+	if envCtx.Type == "virt" {
+		cmd = vmCommand(portunixPath, envCtx.Target, command)
+	} else {
+		cmd = exec.Command(portunixPath, "container", "exec", envCtx.Target, "sh", "-c", command)
+	}
+	cmd.Stderr = os.Stderr
+	if options.Verbose {
+		cmd.Stdout = os.Stdout
+	}
+	return cmd.Run()
+}
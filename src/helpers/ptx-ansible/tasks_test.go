@@ -0,0 +1,126 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseTasksOnlyPtxbook(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "setup.ptxbook")
+	content := `apiVersion: portunix.ai/v1
+kind: Playbook
+metadata:
+  name: setup
+spec:
+  tasks:
+    - file:
+        path: /tmp/app
+        state: directory
+    - service:
+        name: nginx
+        state: started
+        enabled: true
+`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ptxbook, err := ParsePtxbookFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ptxbook.Spec.Tasks) != 2 || RequiresAnsible(ptxbook) {
+		t.Errorf("unexpected playbook %+v", ptxbook.Spec)
+	}
+}
+
+func TestValidateTask(t *testing.T) {
+	invalid := map[string]PtxbookTask{
+		"no module":      {Name: "empty"},
+		"two modules":    {File: &FileTask{Path: "/a"}, Package: &PackageTask{Name: "git"}},
+		"bad file state": {File: &FileTask{Path: "/a", State: "link"}},
+		"bad mode":       {File: &FileTask{Path: "/a", Mode: "rw-r--r--"}},
+		"dir content":    {File: &FileTask{Path: "/a", State: "directory", Content: "x"}},
+		"no template":    {Template: &TemplateTask{Dest: "/a"}},
+		"service no-op":  {Service: &ServiceTask{Name: "nginx"}},
+		"bad package":    {Package: &PackageTask{Name: "git", State: "latest"}},
+	}
+	for name, task := range invalid {
+		if err := validateTask(task); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if err := validateTask(PtxbookTask{Package: &PackageTask{Name: "git", State: "absent"}}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestApplyFileTask(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("checks unix permissions")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "conf", "app.conf")
+	if err := applyFileTask(&FileTask{Path: path, Content: "port=8080\n", Mode: "0600"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "port=8080\n" {
+		t.Fatalf("unexpected content %q: %v", data, err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("unexpected mode %v", info.Mode().Perm())
+	}
+
+	// a file without content is only created
+	if err := applyFileTask(&FileTask{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "port=8080\n" {
+		t.Errorf("existing content should be kept, got %q", data)
+	}
+
+	if err := applyFileTask(&FileTask{Path: filepath.Join(dir, "conf"), State: "absent"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("absent should remove the directory")
+	}
+}
+
+func TestTaskShellCommand(t *testing.T) {
+	command, err := taskShellCommand(PtxbookTask{File: &FileTask{Path: "/etc/app/motd", Content: "it's up\n", Mode: "0644"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `mkdir -p "$(dirname /etc/app/motd)" && printf '%s' 'it'\''s up
+' > /etc/app/motd && chmod 0644 /etc/app/motd`
+	if command != want {
+		t.Errorf("got\n%s\nwant\n%s", command, want)
+	}
+
+	enabled := true
+	command, _ = taskShellCommand(PtxbookTask{Service: &ServiceTask{Name: "nginx", State: "restarted", Enabled: &enabled}})
+	if command != "systemctl enable nginx && systemctl restart nginx" {
+		t.Errorf("unexpected service command %s", command)
+	}
+}
+
+func TestServiceCommands(t *testing.T) {
+	disabled := false
+	s := &ServiceTask{Name: "Spooler", State: "stopped", Enabled: &disabled}
+	got := serviceCommands("windows", s)
+	if len(got) != 2 || strings.Join(got[0], " ") != "sc.exe config Spooler start= demand" || strings.Join(got[1], " ") != "sc.exe stop Spooler" {
+		t.Errorf("unexpected windows commands %v", got)
+	}
+	got = serviceCommands("darwin", &ServiceTask{Name: "com.example.app", State: "restarted"})
+	if len(got) != 1 || strings.Join(got[0], " ") != "launchctl kickstart -k system/com.example.app" {
+		t.Errorf("unexpected darwin commands %v", got)
+	}
+}
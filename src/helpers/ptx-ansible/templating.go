@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	gotemplate "text/template"
 )
 
 // TemplateEngine handles advanced Jinja2-style variable templating
@@ -125,9 +126,74 @@ func (te *TemplateEngine) ProcessTemplate(template string) (string, error) {
 		return ""
 	})
 
+	// Go templates: {{ .name }}, {{ .db.host }}, {{ if }}, {{ range }} and
+	// the functions of templateFuncs, e.g. {{ .port | default 8080 }}
+	if strings.Contains(result, "{{") {
+		tmpl, err := gotemplate.New("ptxbook").Funcs(templateFuncs).Parse(result)
+		if err != nil {
+			return "", fmt.Errorf("invalid template: %v", err)
+		}
+		var out strings.Builder
+		if err := tmpl.Execute(&out, te.data()); err != nil {
+			return "", fmt.Errorf("failed to render template: %v", err)
+		}
+		if strings.Contains(out.String(), "<no value>") && !strings.Contains(result, "<no value>") {
+			return "", fmt.Errorf("template references an undefined variable: %s", strings.TrimSpace(result))
+		}
+		result = out.String()
+	}
+
 	return result, nil
 }
 
+// data returns the values of Go templates: the environment overlaid with
+// the variables
+func (te *TemplateEngine) data() map[string]interface{} {
+	data := make(map[string]interface{}, len(te.environment)+len(te.variables))
+	for k, v := range te.environment {
+		data[k] = v
+	}
+	for k, v := range te.variables {
+		data[k] = v
+	}
+	return data
+}
+
+// templateFuncs are the Jinja-like filters available in Go templates
+var templateFuncs = gotemplate.FuncMap{
+	// default returns def when value is unset or empty
+	"default": func(def, value interface{}) interface{} {
+		if value == nil || fmt.Sprintf("%v", value) == "" {
+			return def
+		}
+		return value
+	},
+	// required fails the rendering when value is unset or empty
+	"required": func(name string, value interface{}) (interface{}, error) {
+		if value == nil || fmt.Sprintf("%v", value) == "" {
+			return nil, fmt.Errorf("variable %s is required", name)
+		}
+		return value, nil
+	},
+	"upper":   func(s interface{}) string { return strings.ToUpper(fmt.Sprint(s)) },
+	"lower":   func(s interface{}) string { return strings.ToLower(fmt.Sprint(s)) },
+	"trim":    func(s interface{}) string { return strings.TrimSpace(fmt.Sprint(s)) },
+	"replace": func(old, new string, s interface{}) string { return strings.ReplaceAll(fmt.Sprint(s), old, new) },
+	"quote":   func(s interface{}) string { return fmt.Sprintf("%q", fmt.Sprint(s)) },
+	"env":     os.Getenv,
+	"join": func(sep string, list interface{}) string {
+		items, ok := list.([]interface{})
+		if !ok {
+			return fmt.Sprint(list)
+		}
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, sep)
+	},
+}
+
 // evaluateExpression evaluates a simple expression
 func (te *TemplateEngine) evaluateExpression(expr string) string {
 	expr = strings.TrimSpace(expr)
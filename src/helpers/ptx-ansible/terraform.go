@@ -0,0 +1,319 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The terraform section provisions infrastructure on the control host before
+// the packages, tasks and Ansible playbooks configure it: init with the state
+// backend, workspace selection, plan and apply. The outputs are stored in the
+// variable 'terraform', so later steps use e.g. {{ .terraform.server_ip }}.
+
+// terraformBinaries are the supported CLIs, in detection order
+var terraformBinaries = []string{"terraform", "tofu"}
+
+// validateTerraform checks the terraform section
+func validateTerraform(tf *PtxbookTerraform) error {
+	if tf.Binary != "" && !oneOf(tf.Binary, terraformBinaries...) {
+		return fmt.Errorf("binary must be terraform or tofu, got %q", tf.Binary)
+	}
+	if tf.Inventory != nil && tf.Inventory.Output == "" {
+		return fmt.Errorf("inventory.output is required")
+	}
+	return nil
+}
+
+// findTerraformBinary returns the path of the configured CLI, or of the first
+// one installed
+func findTerraformBinary(binary string) (string, error) {
+	candidates := terraformBinaries
+	if binary != "" {
+		candidates = []string{binary}
+	}
+	for _, name := range candidates {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s is required but not available in PATH", strings.Join(candidates, " or "))
+}
+
+// executeTerraform provisions the infrastructure of the playbook and stores
+// the outputs in its variables; baseDir is the directory of the .ptxbook file
+func executeTerraform(ptxbook *PtxbookFile, baseDir string, options ExecutionOptions, rollbackManager *RollbackManager) error {
+	tf, err := processTerraformVariables(ptxbook.Spec.Terraform, NewTemplateEngine(ptxbook.Spec.Variables, ptxbook.Spec.Environment))
+	if err != nil {
+		return err
+	}
+
+	dir := tf.Dir
+	if dir == "" {
+		dir = "."
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(baseDir, dir)
+	}
+
+	if options.DryRun {
+		action := "plan and apply"
+		if tf.PlanOnly {
+			action = "plan"
+		}
+		fmt.Printf("   [DRY-RUN] Would %s Terraform configuration: %s\n", action, dir)
+		if tf.Workspace != "" {
+			fmt.Printf("   [DRY-RUN] Workspace: %s\n", tf.Workspace)
+		}
+		// The outputs of an existing state let the later steps render
+		if binary, err := findTerraformBinary(tf.Binary); err == nil {
+			if outputs, err := terraformOutputs(binary, dir); err == nil {
+				setTerraformOutputs(ptxbook, outputs)
+			}
+		}
+		return nil
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("terraform configuration not found: %s", dir)
+	}
+	binary, err := findTerraformBinary(tf.Binary)
+	if err != nil {
+		return err
+	}
+	if options.Verbose {
+		fmt.Printf("   Using %s in %s\n", binary, dir)
+	}
+
+	if err := runTerraform(binary, dir, options.Verbose, terraformInitArgs(tf)...); err != nil {
+		return err
+	}
+	if tf.Workspace != "" {
+		if err := runTerraform(binary, dir, options.Verbose, "workspace", "select", "-or-create", tf.Workspace); err != nil {
+			return err
+		}
+	}
+
+	var varsFile string
+	if len(tf.Vars) > 0 {
+		if varsFile, err = writeExtraVars(tf.Vars); err != nil {
+			return fmt.Errorf("failed to write terraform variables: %v", err)
+		}
+		defer os.Remove(varsFile)
+	}
+
+	planDir, err := os.MkdirTemp("", "ptxbook-terraform-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(planDir)
+	planFile := filepath.Join(planDir, "tfplan")
+
+	if err := runTerraform(binary, dir, options.Verbose, terraformPlanArgs(tf, varsFile, planFile)...); err != nil {
+		return err
+	}
+
+	if !tf.PlanOnly {
+		err := runTerraform(binary, dir, options.Verbose, "apply", "-input=false", planFile)
+		rollbackManager.RecordAction("terraform_apply", dir, tf.Workspace, "local", err == nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	outputs, err := terraformOutputs(binary, dir)
+	if err != nil {
+		return err
+	}
+	setTerraformOutputs(ptxbook, outputs)
+	if options.Verbose && len(outputs) > 0 {
+		fmt.Printf("   Terraform outputs: %s\n", strings.Join(sortedKeys(outputs), ", "))
+	}
+	return nil
+}
+
+// setTerraformOutputs stores the outputs in the variable 'terraform'
+func setTerraformOutputs(ptxbook *PtxbookFile, outputs map[string]interface{}) {
+	if ptxbook.Spec.Variables == nil {
+		ptxbook.Spec.Variables = map[string]interface{}{}
+	}
+	ptxbook.Spec.Variables["terraform"] = outputs
+}
+
+// terraformSummary describes the terraform section for 'playbook validate'
+func terraformSummary(tf *PtxbookTerraform) string {
+	binary := tf.Binary
+	if binary == "" {
+		binary = "terraform/tofu"
+	}
+	dir := tf.Dir
+	if dir == "" {
+		dir = "."
+	}
+	summary := fmt.Sprintf("%s in %s", binary, dir)
+	if tf.Workspace != "" {
+		summary += ", workspace " + tf.Workspace
+	}
+	if tf.PlanOnly {
+		summary += ", plan only"
+	}
+	return summary
+}
+
+// processTerraformVariables substitutes the playbook variables in the
+// settings of the terraform section
+func processTerraformVariables(tf *PtxbookTerraform, engine *TemplateEngine) (*PtxbookTerraform, error) {
+	processed := *tf
+	var err error
+	for _, field := range []*string{&processed.Dir, &processed.Workspace} {
+		if *field, err = engine.ProcessTemplate(*field); err != nil {
+			return nil, fmt.Errorf("terraform: %v", err)
+		}
+	}
+
+	processed.Backend = make(map[string]string, len(tf.Backend))
+	for k, v := range tf.Backend {
+		if processed.Backend[k], err = engine.ProcessTemplate(v); err != nil {
+			return nil, fmt.Errorf("terraform backend %s: %v", k, err)
+		}
+	}
+
+	processed.Vars = make(map[string]interface{}, len(tf.Vars))
+	for k, v := range tf.Vars {
+		if s, ok := v.(string); ok {
+			if v, err = engine.ProcessTemplate(s); err != nil {
+				return nil, fmt.Errorf("terraform variable %s: %v", k, err)
+			}
+		}
+		processed.Vars[k] = v
+	}
+	return &processed, nil
+}
+
+// terraformInitArgs returns the arguments of 'terraform init' with the state
+// backend settings
+func terraformInitArgs(tf *PtxbookTerraform) []string {
+	args := []string{"init", "-input=false"}
+	for _, k := range sortedKeys(tf.Backend) {
+		args = append(args, fmt.Sprintf("-backend-config=%s=%s", k, tf.Backend[k]))
+	}
+	return args
+}
+
+// terraformPlanArgs returns the arguments of 'terraform plan'; the variables
+// of the playbook are passed in varsFile and override the var files
+func terraformPlanArgs(tf *PtxbookTerraform, varsFile, planFile string) []string {
+	args := []string{"plan", "-input=false", "-out=" + planFile}
+	for _, file := range tf.VarFiles {
+		args = append(args, "-var-file="+file)
+	}
+	if varsFile != "" {
+		args = append(args, "-var-file="+varsFile)
+	}
+	return args
+}
+
+// runTerraform runs the CLI in dir; its output is shown in verbose mode and
+// otherwise reported on failure
+func runTerraform(binary, dir string, verbose bool, args ...string) error {
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "TF_IN_AUTOMATION=1")
+	if verbose {
+		fmt.Printf("   $ %s %s\n", filepath.Base(binary), strings.Join(args, " "))
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("terraform %s failed: %v", args[0], err)
+		}
+		return nil
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("terraform %s failed: %v\n%s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// terraformOutputs returns the values of the outputs of the current state
+func terraformOutputs(binary, dir string) (map[string]interface{}, error) {
+	cmd := exec.Command(binary, "output", "-json")
+	cmd.Dir = dir
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("terraform output failed: %v", err)
+	}
+	return parseTerraformOutputs(data)
+}
+
+// parseTerraformOutputs extracts the values of 'terraform output -json'
+func parseTerraformOutputs(data []byte) (map[string]interface{}, error) {
+	var raw map[string]struct {
+		Value interface{} `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse terraform outputs: %v", err)
+	}
+	outputs := make(map[string]interface{}, len(raw))
+	for name, output := range raw {
+		outputs[name] = output.Value
+	}
+	return outputs, nil
+}
+
+// terraformInventory returns the Ansible inventory of the hosts of the
+// configured output, or "" when the playbook has none
+func terraformInventory(ptxbook *PtxbookFile) (string, error) {
+	tf := ptxbook.Spec.Terraform
+	if tf == nil || tf.Inventory == nil {
+		return "", nil
+	}
+	outputs, _ := ptxbook.Spec.Variables["terraform"].(map[string]interface{})
+	value, ok := outputs[tf.Inventory.Output]
+	if !ok {
+		return "", fmt.Errorf("terraform output %q not found", tf.Inventory.Output)
+	}
+
+	var hosts []string
+	switch v := value.(type) {
+	case string:
+		hosts = []string{v}
+	case []interface{}:
+		for _, host := range v {
+			hosts = append(hosts, fmt.Sprint(host))
+		}
+	default:
+		return "", fmt.Errorf("terraform output %q must be a host or a list of hosts", tf.Inventory.Output)
+	}
+
+	var b strings.Builder
+	b.WriteString("[terraform]\n")
+	for _, host := range hosts {
+		b.WriteString(host)
+		if tf.Inventory.User != "" {
+			b.WriteString(" ansible_user=" + tf.Inventory.User)
+		}
+		if tf.Inventory.SSHKey != "" {
+			b.WriteString(" ansible_ssh_private_key_file=" + tf.Inventory.SSHKey)
+		}
+		b.WriteString(" ansible_ssh_common_args='-o StrictHostKeyChecking=no'\n")
+	}
+	return b.String(), nil
+}
+
+// sortedKeys returns the keys of m in order, for stable command lines
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,82 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTerraformOutputs(t *testing.T) {
+	data := []byte(`{
+  "server_ip": {"sensitive": false, "type": "string", "value": "203.0.113.10"},
+  "edge_ips": {"sensitive": false, "type": ["list", "string"], "value": ["203.0.113.11", "203.0.113.12"]},
+  "token": {"sensitive": true, "type": "string", "value": "s3cret"}
+}`)
+	outputs, err := parseTerraformOutputs(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outputs["server_ip"] != "203.0.113.10" || outputs["token"] != "s3cret" || len(outputs["edge_ips"].([]interface{})) != 2 {
+		t.Errorf("unexpected outputs %v", outputs)
+	}
+}
+
+func TestTerraformArgs(t *testing.T) {
+	tf := &PtxbookTerraform{
+		Backend:  map[string]string{"key": "edge/terraform.tfstate", "bucket": "states"},
+		VarFiles: []string{"prod.tfvars"},
+	}
+	if got := strings.Join(terraformInitArgs(tf), " "); got != "init -input=false -backend-config=bucket=states -backend-config=key=edge/terraform.tfstate" {
+		t.Errorf("unexpected init args %s", got)
+	}
+	if got := strings.Join(terraformPlanArgs(tf, "/tmp/vars.json", "/tmp/tfplan"), " "); got != "plan -input=false -out=/tmp/tfplan -var-file=prod.tfvars -var-file=/tmp/vars.json" {
+		t.Errorf("unexpected plan args %s", got)
+	}
+}
+
+func TestProcessTerraformVariables(t *testing.T) {
+	engine := NewTemplateEngine(map[string]interface{}{"stage": "prod"}, map[string]interface{}{})
+	tf, err := processTerraformVariables(&PtxbookTerraform{
+		Workspace: "{{ .stage }}",
+		Backend:   map[string]string{"key": "{{ .stage }}/terraform.tfstate"},
+		Vars:      map[string]interface{}{"name": "edge-{{ .stage }}", "count": 2},
+	}, engine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tf.Workspace != "prod" || tf.Backend["key"] != "prod/terraform.tfstate" || tf.Vars["name"] != "edge-prod" || tf.Vars["count"] != 2 {
+		t.Errorf("unexpected terraform %+v", tf)
+	}
+}
+
+func TestTerraformInventory(t *testing.T) {
+	ptxbook := &PtxbookFile{Spec: PtxbookSpec{
+		Terraform: &PtxbookTerraform{Inventory: &TerraformInventory{Output: "edge_ips", User: "root"}},
+		Variables: map[string]interface{}{"terraform": map[string]interface{}{
+			"edge_ips": []interface{}{"203.0.113.11", "203.0.113.12"},
+			"count":    2.0,
+		}},
+	}}
+	inventory, err := terraformInventory(ptxbook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[terraform]\n" +
+		"203.0.113.11 ansible_user=root ansible_ssh_common_args='-o StrictHostKeyChecking=no'\n" +
+		"203.0.113.12 ansible_user=root ansible_ssh_common_args='-o StrictHostKeyChecking=no'\n"
+	if inventory != want {
+		t.Errorf("got\n%s\nwant\n%s", inventory, want)
+	}
+
+	ptxbook.Spec.Terraform.Inventory.Output = "count"
+	if _, err := terraformInventory(ptxbook); err == nil {
+		t.Error("a number is not a host")
+	}
+	ptxbook.Spec.Terraform.Inventory.Output = "missing"
+	if _, err := terraformInventory(ptxbook); err == nil {
+		t.Error("a missing output should fail")
+	}
+}
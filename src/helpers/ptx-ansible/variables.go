@@ -0,0 +1,125 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Variable overlays let one playbook serve several environments: the
+// variables of the playbook are overridden by values files, e.g. dev.yaml,
+// staging.yaml and prod.yaml, in the order given with --vars-file, and
+// finally by --set key=value.
+
+// ApplyVariableOverrides merges the values files and --set assignments into
+// the variables of the playbook
+func ApplyVariableOverrides(ptxbook *PtxbookFile, varsFiles, sets []string) error {
+	if len(varsFiles) == 0 && len(sets) == 0 {
+		return nil
+	}
+	if ptxbook.Spec.Variables == nil {
+		ptxbook.Spec.Variables = map[string]interface{}{}
+	}
+	for _, file := range varsFiles {
+		values, err := LoadVarsFile(file)
+		if err != nil {
+			return err
+		}
+		mergeVariables(ptxbook.Spec.Variables, values)
+	}
+	for _, set := range sets {
+		if err := setVariable(ptxbook.Spec.Variables, set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadVarsFile reads a YAML or JSON values file holding a map of variables
+func LoadVarsFile(file string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vars file: %v", err)
+	}
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse vars file %s: %v", file, err)
+	}
+	return values, nil
+}
+
+// mergeVariables merges src into dst; nested maps are merged key by key,
+// other values replace those of dst
+func mergeVariables(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeVariables(dstMap, srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+// setVariable applies a --set key=value assignment. Dotted keys address
+// nested maps (db.host=...) and values are YAML scalars, so port=8080 is a
+// number and debug=true a boolean; quote them to keep strings.
+func setVariable(variables map[string]interface{}, set string) error {
+	key, raw, ok := strings.Cut(set, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("invalid --set %q, expected key=value", set)
+	}
+
+	var value interface{} = raw
+	var parsed interface{}
+	if err := yaml.Unmarshal([]byte(raw), &parsed); err == nil {
+		switch parsed.(type) {
+		case bool, int, float64, string:
+			value = parsed
+		}
+	}
+
+	path := strings.Split(key, ".")
+	current := variables
+	for _, name := range path[:len(path)-1] {
+		if name == "" {
+			return fmt.Errorf("invalid --set key %q", key)
+		}
+		next, ok := current[name].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			current[name] = next
+		}
+		current = next
+	}
+	current[path[len(path)-1]] = value
+	return nil
+}
+
+// writeExtraVars writes variables to a temporary JSON file for
+// 'ansible-playbook --extra-vars @file' or 'terraform plan -var-file'; the
+// caller removes it
+func writeExtraVars(variables map[string]interface{}) (string, error) {
+	data, err := json.Marshal(variables)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode variables: %v", err)
+	}
+	f, err := os.CreateTemp("", "ptxbook-vars-*.json")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
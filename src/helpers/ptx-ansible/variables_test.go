@@ -0,0 +1,72 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetVariable(t *testing.T) {
+	variables := map[string]interface{}{"db": map[string]interface{}{"host": "localhost"}}
+	for _, set := range []string{"db.port=5433", "debug=true", "name=shop", "version='1.0'"} {
+		if err := setVariable(variables, set); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db := variables["db"].(map[string]interface{})
+	if db["host"] != "localhost" || db["port"] != 5433 {
+		t.Errorf("unexpected db %v", db)
+	}
+	if variables["debug"] != true || variables["name"] != "shop" || variables["version"] != "1.0" {
+		t.Errorf("unexpected variables %v", variables)
+	}
+	for _, set := range []string{"novalue", "=x", "db..host=x"} {
+		if err := setVariable(variables, set); err == nil {
+			t.Errorf("%s: expected an error", set)
+		}
+	}
+}
+
+func TestApplyVariableOverrides(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "prod.yaml")
+	if err := os.WriteFile(file, []byte("db:\n  host: db.prod\nreplicas: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ptxbook := &PtxbookFile{Spec: PtxbookSpec{Variables: map[string]interface{}{
+		"db":       map[string]interface{}{"host": "localhost", "port": 5432},
+		"replicas": 1,
+	}}}
+	if err := ApplyVariableOverrides(ptxbook, []string{file}, []string{"replicas=3"}); err != nil {
+		t.Fatal(err)
+	}
+	db := ptxbook.Spec.Variables["db"].(map[string]interface{})
+	if db["host"] != "db.prod" || db["port"] != 5432 || ptxbook.Spec.Variables["replicas"] != 3 {
+		t.Errorf("unexpected variables %v", ptxbook.Spec.Variables)
+	}
+}
+
+func TestProcessTemplateGoTemplates(t *testing.T) {
+	te := NewTemplateEngine(map[string]interface{}{
+		"app": "shop",
+		"db":  map[string]interface{}{"host": "db.prod"},
+	}, map[string]interface{}{})
+
+	got, err := te.ProcessTemplate("{{ app }}: {{ .db.host }}:{{ .db.port | default 5432 }} {{ .app | upper }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "shop: db.prod:5432 SHOP" {
+		t.Errorf("unexpected result %q", got)
+	}
+
+	if _, err := te.ProcessTemplate("{{ .missing }}"); err == nil {
+		t.Error("undefined variable should fail")
+	}
+	if _, err := te.ProcessTemplate(`{{ .db.user | required "db.user" }}`); err == nil {
+		t.Error("required variable should fail")
+	}
+}
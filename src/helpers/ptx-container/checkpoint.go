@@ -0,0 +1,266 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// criuPaths are where distributions install CRIU; /usr/sbin is often not
+// in the PATH of regular users
+var criuPaths = []string{"/usr/sbin/criu", "/sbin/criu", "/usr/local/sbin/criu"}
+
+// checkpointSupport describes what checkpoint/restore needs: Podman,
+// running rootful, and CRIU on the Linux host
+type checkpointSupport struct {
+	Runtime  string // podman or docker
+	Rootless bool
+	CRIU     string // Path of the criu binary, empty when missing
+	GOOS     string
+}
+
+// problem explains why checkpoints cannot be taken, or returns nil
+func (s checkpointSupport) problem() error {
+	switch {
+	case s.Runtime == "":
+		return fmt.Errorf("neither Podman nor Docker is available")
+	case s.Runtime == "docker":
+		return fmt.Errorf("checkpoint/restore needs Podman; Docker offers checkpoints only in its experimental mode, which portunix does not use. Install Podman with 'portunix install podman' and run the container with it")
+	case s.Rootless:
+		return fmt.Errorf("checkpoint/restore needs rootful Podman: CRIU cannot checkpoint rootless containers. Run the container with 'sudo portunix container run ...' (or 'podman machine set --rootful' on macOS and Windows)")
+	case s.GOOS == "linux" && s.CRIU == "":
+		return fmt.Errorf("CRIU is not installed; install the 'criu' package of your distribution")
+	}
+	return nil
+}
+
+// detectCheckpointSupport checks the runtime for checkpoint/restore. On macOS
+// and Windows Podman runs in a machine, which brings its own CRIU.
+func detectCheckpointSupport() checkpointSupport {
+	s := checkpointSupport{GOOS: runtime.GOOS}
+	rt, err := selectRuntime()
+	if err != nil {
+		return s
+	}
+	s.Runtime = rt
+	if rt != "podman" {
+		return s
+	}
+	if output, err := exec.Command("podman", "info", "--format", "{{.Host.Security.Rootless}}").Output(); err == nil {
+		s.Rootless = strings.TrimSpace(string(output)) == "true"
+	}
+	if path, err := exec.LookPath("criu"); err == nil {
+		s.CRIU = path
+	} else {
+		for _, path := range criuPaths {
+			if _, err := os.Stat(path); err == nil {
+				s.CRIU = path
+				break
+			}
+		}
+	}
+	return s
+}
+
+// checkpointOptions are the options of 'container checkpoint'
+type checkpointOptions struct {
+	LeaveRunning   bool
+	TCPEstablished bool
+	Export         string // Archive for restoring after the container is removed or on another host
+}
+
+// checkpointArgs returns the podman arguments checkpointing a container
+func checkpointArgs(name string, opts checkpointOptions) []string {
+	args := []string{"container", "checkpoint"}
+	if opts.LeaveRunning {
+		args = append(args, "--leave-running")
+	}
+	if opts.TCPEstablished {
+		args = append(args, "--tcp-established")
+	}
+	if opts.Export != "" {
+		args = append(args, "--export", opts.Export)
+	}
+	return append(args, name)
+}
+
+// restoreOptions are the options of 'container restore'
+type restoreOptions struct {
+	TCPEstablished bool
+	Import         string // Archive written by 'container checkpoint --export'
+}
+
+// restoreArgs returns the podman arguments restoring a container. An
+// imported archive creates a new container, named name when given.
+func restoreArgs(name string, opts restoreOptions) []string {
+	args := []string{"container", "restore"}
+	if opts.TCPEstablished {
+		args = append(args, "--tcp-established")
+	}
+	if opts.Import != "" {
+		args = append(args, "--import", opts.Import)
+		if name != "" {
+			args = append(args, "--name", name)
+		}
+		return args
+	}
+	return append(args, name)
+}
+
+// handleContainerCheckpoint implements `container checkpoint <name>`: the
+// processes of the container are frozen to disk with CRIU and the container
+// stops, so it survives a host reboot and 'container restore' resumes it.
+func handleContainerCheckpoint(args []string) {
+	var name string
+	var opts checkpointOptions
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			showCheckpointHelp()
+			return
+		case "--leave-running", "-R":
+			opts.LeaveRunning = true
+		case "--tcp-established":
+			opts.TCPEstablished = true
+		case "--export", "-e":
+			if i+1 < len(args) {
+				opts.Export = args[i+1]
+				i++
+			}
+		default:
+			if strings.HasPrefix(args[i], "-") || name != "" {
+				fmt.Fprintf(os.Stderr, "❌ Error: unexpected argument: %s\n", args[i])
+				showCheckpointHelp()
+				os.Exit(1)
+			}
+			name = args[i]
+		}
+	}
+	if name == "" {
+		fmt.Fprintln(os.Stderr, "❌ Error: container name required")
+		fmt.Fprintln(os.Stderr, "Usage: portunix container checkpoint <container-name> [OPTIONS]")
+		os.Exit(1)
+	}
+	if err := detectCheckpointSupport().problem(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if code := runPassthrough("podman", checkpointArgs(name, opts)...); code != 0 {
+		os.Exit(code)
+	}
+	if opts.LeaveRunning {
+		fmt.Printf("✅ Container '%s' checkpointed and still running\n", name)
+	} else {
+		fmt.Printf("✅ Container '%s' checkpointed and stopped; the checkpoint survives a host reboot\n", name)
+	}
+	if opts.Export != "" {
+		fmt.Printf("   Archive: %s\n", opts.Export)
+	}
+	fmt.Printf("   Resume it with: portunix container restore %s\n", name)
+}
+
+// handleContainerRestore implements `container restore <name>`, resuming a
+// checkpointed container, or with --import recreating one from an archive
+func handleContainerRestore(args []string) {
+	var name string
+	var opts restoreOptions
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			showRestoreHelp()
+			return
+		case "--tcp-established":
+			opts.TCPEstablished = true
+		case "--import", "-i":
+			if i+1 < len(args) {
+				opts.Import = args[i+1]
+				i++
+			}
+		default:
+			if strings.HasPrefix(args[i], "-") || name != "" {
+				fmt.Fprintf(os.Stderr, "❌ Error: unexpected argument: %s\n", args[i])
+				showRestoreHelp()
+				os.Exit(1)
+			}
+			name = args[i]
+		}
+	}
+	if name == "" && opts.Import == "" {
+		fmt.Fprintln(os.Stderr, "❌ Error: container name or --import archive required")
+		fmt.Fprintln(os.Stderr, "Usage: portunix container restore <container-name> [OPTIONS]")
+		os.Exit(1)
+	}
+	if opts.Import != "" {
+		if _, err := os.Stat(opts.Import); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: checkpoint archive: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if err := detectCheckpointSupport().problem(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if code := runPassthrough("podman", restoreArgs(name, opts)...); code != 0 {
+		os.Exit(code)
+	}
+	if name == "" {
+		fmt.Printf("✅ Container restored from %s\n", opts.Import)
+		return
+	}
+	fmt.Printf("✅ Container '%s' restored\n", name)
+}
+
+func showCheckpointHelp() {
+	fmt.Println("Usage: portunix container checkpoint <container-name> [OPTIONS]")
+	fmt.Println()
+	fmt.Println("💾 CHECKPOINT CONTAINER")
+	fmt.Println()
+	fmt.Println("Save the memory and processes of a running container to disk with CRIU")
+	fmt.Println("and stop it. The checkpoint survives a host reboot; 'portunix container")
+	fmt.Println("restore' resumes the container where it left off.")
+	fmt.Println()
+	fmt.Println("Requirements:")
+	fmt.Println("  • Podman running rootful (Docker is not supported)")
+	fmt.Println("  • CRIU installed on Linux hosts")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -R, --leave-running     Keep the container running after the checkpoint")
+	fmt.Println("  --tcp-established       Checkpoint open TCP connections")
+	fmt.Println("  -e, --export <file>     Also write the checkpoint to a tar.gz archive, to")
+	fmt.Println("                          restore it after the container is removed or on")
+	fmt.Println("                          another host")
+	fmt.Println("  -h, --help              Show this help message")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  sudo portunix container checkpoint python-dev")
+	fmt.Println("  sudo portunix container checkpoint python-dev --export /srv/python-dev.tar.gz")
+}
+
+func showRestoreHelp() {
+	fmt.Println("Usage: portunix container restore <container-name> [OPTIONS]")
+	fmt.Println("       portunix container restore --import <file> [container-name]")
+	fmt.Println()
+	fmt.Println("♻️ RESTORE CONTAINER")
+	fmt.Println()
+	fmt.Println("Resume a container from its checkpoint, with the memory and processes it")
+	fmt.Println("had when 'portunix container checkpoint' saved it. With --import a new")
+	fmt.Println("container is created from a checkpoint archive, named container-name when")
+	fmt.Println("given.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -i, --import <file>     Restore from an archive written by checkpoint --export")
+	fmt.Println("  --tcp-established       Restore open TCP connections")
+	fmt.Println("  -h, --help              Show this help message")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  sudo portunix container restore python-dev")
+	fmt.Println("  sudo portunix container restore --import /srv/python-dev.tar.gz python-dev-2")
+}
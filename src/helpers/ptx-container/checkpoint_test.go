@@ -0,0 +1,53 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckpointSupport(t *testing.T) {
+	for _, c := range []struct {
+		support checkpointSupport
+		want    string
+	}{
+		{checkpointSupport{Runtime: "podman", CRIU: "/usr/sbin/criu", GOOS: "linux"}, ""},
+		{checkpointSupport{Runtime: "podman", GOOS: "darwin"}, ""},
+		{checkpointSupport{Runtime: "docker", GOOS: "linux"}, "needs Podman"},
+		{checkpointSupport{Runtime: "podman", Rootless: true, CRIU: "/usr/sbin/criu", GOOS: "linux"}, "rootful"},
+		{checkpointSupport{Runtime: "podman", GOOS: "linux"}, "CRIU is not installed"},
+		{checkpointSupport{GOOS: "linux"}, "neither Podman nor Docker"},
+	} {
+		err := c.support.problem()
+		if c.want == "" {
+			if err != nil {
+				t.Errorf("%+v: %v", c.support, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%+v: got %v, want %q", c.support, err, c.want)
+		}
+	}
+}
+
+func TestCheckpointArgs(t *testing.T) {
+	for _, c := range []struct {
+		got  []string
+		want string
+	}{
+		{checkpointArgs("dev", checkpointOptions{}), "container checkpoint dev"},
+		{checkpointArgs("dev", checkpointOptions{LeaveRunning: true, TCPEstablished: true, Export: "/srv/dev.tar.gz"}),
+			"container checkpoint --leave-running --tcp-established --export /srv/dev.tar.gz dev"},
+		{restoreArgs("dev", restoreOptions{}), "container restore dev"},
+		{restoreArgs("dev-2", restoreOptions{Import: "/srv/dev.tar.gz"}), "container restore --import /srv/dev.tar.gz --name dev-2"},
+		{restoreArgs("", restoreOptions{Import: "/srv/dev.tar.gz", TCPEstablished: true}), "container restore --tcp-established --import /srv/dev.tar.gz"},
+	} {
+		if got := strings.Join(c.got, " "); got != c.want {
+			t.Errorf("got %q, want %q", got, c.want)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// DefaultComposeWaitTimeout is how long 'compose up --wait' waits for the
+// services to become ready
+const DefaultComposeWaitTimeout = 5 * time.Minute
+
+// composeLogLines is how many log lines of each failed service are shown
+const composeLogLines = 40
+
+// composeValueFlags are the global compose flags that take a value
+var composeValueFlags = map[string]bool{
+	"-f": true, "--file": true, "-p": true, "--project-name": true, "--env-file": true,
+	"--profile": true, "--project-directory": true, "--ansi": true, "--progress": true, "--parallel": true,
+}
+
+// composeWaitArgs splits the arguments of 'compose up --wait': the global
+// flags before the subcommand, which ps and logs need as well, and the up
+// arguments without --wait/--wait-timeout, detached. wait is false for other
+// commands, which are passed through unchanged.
+func composeWaitArgs(args []string) (global, up []string, wait bool, timeout time.Duration, err error) {
+	timeout = DefaultComposeWaitTimeout
+	command := -1
+	for i := 0; i < len(args); i++ {
+		if composeValueFlags[args[i]] {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(args[i], "-") {
+			command = i
+			break
+		}
+	}
+	if command < 0 || args[command] != "up" {
+		return nil, args, false, timeout, nil
+	}
+
+	global = args[:command]
+	up = []string{"up"}
+	detached := false
+	for i := command + 1; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--wait":
+			wait = true
+		case arg == "--wait-timeout" || strings.HasPrefix(arg, "--wait-timeout="):
+			value := strings.TrimPrefix(arg, "--wait-timeout=")
+			if arg == "--wait-timeout" {
+				if i+1 >= len(args) {
+					return nil, nil, false, timeout, fmt.Errorf("--wait-timeout requires a value")
+				}
+				i++
+				value = args[i]
+			}
+			if timeout, err = parseWaitTimeout(value); err != nil {
+				return nil, nil, false, timeout, err
+			}
+		default:
+			if arg == "-d" || arg == "--detach" {
+				detached = true
+			}
+			up = append(up, arg)
+		}
+	}
+	if !wait {
+		return nil, args, false, timeout, nil
+	}
+	if !detached {
+		up = append([]string{"up", "-d"}, up[1:]...)
+	}
+	return global, up, true, timeout, nil
+}
+
+// parseWaitTimeout accepts seconds, as docker compose does, or a duration
+// such as 2m
+func parseWaitTimeout(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid --wait-timeout %q (seconds or a duration like 2m)", value)
+}
+
+// Readiness states of a compose service
+const (
+	serviceStarting  = "starting"
+	serviceReady     = "ready"
+	serviceCompleted = "completed" // One-shot container that exited with code 0
+	serviceFailed    = "failed"
+)
+
+// serviceState is a container of a compose project as inspected
+type serviceState struct {
+	Service  string
+	Status   string // Container status: created, running, exited, ...
+	ExitCode int
+	Health   string // Healthcheck status, empty without a healthcheck
+	Ports    []string
+}
+
+// composeInspectFormat prints the fields of serviceState, one container per
+// line. Docker and Podman both understand it.
+const composeInspectFormat = `{{index .Config.Labels "com.docker.compose.service"}}|{{.State.Status}}|{{.State.ExitCode}}|{{if .State.Health}}{{.State.Health.Status}}{{end}}|{{range $p, $b := .NetworkSettings.Ports}}{{range $b}}{{.HostPort}} {{end}}{{end}}`
+
+// parseServiceStates parses the output of inspect with composeInspectFormat
+func parseServiceStates(output string) []serviceState {
+	var states []serviceState
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "|")
+		if len(fields) != 5 || fields[0] == "" {
+			continue
+		}
+		exitCode, _ := strconv.Atoi(fields[2])
+		state := serviceState{Service: fields[0], Status: fields[1], ExitCode: exitCode, Health: fields[3]}
+		seen := make(map[string]bool)
+		for _, port := range strings.Fields(fields[4]) {
+			if !seen[port] {
+				seen[port] = true
+				state.Ports = append(state.Ports, port)
+			}
+		}
+		sort.Strings(state.Ports)
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Service < states[j].Service })
+	return states
+}
+
+// readiness returns the readiness of a service and a short detail. Services
+// with a healthcheck are ready once healthy; without one, once their
+// published ports accept connections, or simply running without ports.
+func (s serviceState) readiness(probe func(port string) bool) (string, string) {
+	switch s.Status {
+	case "running":
+		switch s.Health {
+		case "healthy":
+			return serviceReady, "healthy"
+		case "unhealthy":
+			return serviceFailed, "unhealthy"
+		case "":
+		default:
+			return serviceStarting, "health: " + s.Health
+		}
+		for _, port := range s.Ports {
+			if !probe(port) {
+				return serviceStarting, "port " + port + " not open"
+			}
+		}
+		if len(s.Ports) > 0 {
+			return serviceReady, "port " + strings.Join(s.Ports, ", ") + " open"
+		}
+		return serviceReady, "running"
+	case "exited", "dead":
+		if s.ExitCode == 0 {
+			return serviceCompleted, "exited 0"
+		}
+		return serviceFailed, fmt.Sprintf("exited %d", s.ExitCode)
+	}
+	return serviceStarting, s.Status
+}
+
+// probePort reports whether a published port accepts TCP connections
+func probePort(port string) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", port), time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// composeCommand returns the command running compose with args on runtime
+func composeCommand(runtime string, args ...string) *exec.Cmd {
+	switch runtime {
+	case "Docker Compose V2":
+		return exec.Command("docker", append([]string{"compose"}, args...)...)
+	case "Docker Compose V1":
+		return exec.Command("docker-compose", args...)
+	case "Podman Compose":
+		// Built-in podman compose (Podman 3.0+)
+		return exec.Command("podman", append([]string{"compose"}, args...)...)
+	case "Podman Compose (standalone)":
+		return exec.Command("podman-compose", args...)
+	}
+	return nil
+}
+
+// composeEngine returns the container engine behind a compose runtime
+func composeEngine(runtime string) string {
+	if strings.HasPrefix(runtime, "Podman") {
+		return "podman"
+	}
+	return "docker"
+}
+
+// composeServiceStates inspects the containers of the compose project
+func composeServiceStates(runtime string, global []string) ([]serviceState, error) {
+	psArgs := append(append([]string{}, global...), "ps", "-q")
+	if runtime == "Docker Compose V2" {
+		psArgs = append(psArgs, "-a") // Include exited containers
+	}
+	output, err := composeCommand(runtime, psArgs...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the containers of the project: %w", err)
+	}
+	ids := strings.Fields(string(output))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	inspectArgs := append([]string{"inspect", "--format", composeInspectFormat}, ids...)
+	output, err = exec.Command(composeEngine(runtime), inspectArgs...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect the containers of the project: %w", err)
+	}
+	return parseServiceStates(string(output)), nil
+}
+
+// statusTable renders the wait status of the services, one line each
+func statusTable(states []serviceState, probe func(port string) bool, elapsed time.Duration) ([]string, []string, bool) {
+	icons := map[string]string{serviceStarting: "⏳", serviceReady: "✅", serviceCompleted: "✅", serviceFailed: "❌"}
+	lines := []string{fmt.Sprintf("Waiting for services (%s)", elapsed.Truncate(time.Second))}
+	var failed []string
+	done := len(states) > 0
+	for _, state := range states {
+		readiness, detail := state.readiness(probe)
+		lines = append(lines, fmt.Sprintf("  %s %-24s %-10s %s", icons[readiness], state.Service, readiness, detail))
+		switch readiness {
+		case serviceFailed:
+			failed = append(failed, state.Service)
+		case serviceStarting:
+			done = false
+		}
+	}
+	return lines, failed, done
+}
+
+// waitForCompose waits until all services of the project are ready, drawing
+// a live status table. On an unhealthy service, or at the timeout, it prints
+// the logs of the services that are not ready and returns an error.
+func waitForCompose(runtime string, global []string, timeout time.Duration, out io.Writer) error {
+	live := out == os.Stdout && term.IsTerminal(int(os.Stdout.Fd()))
+	start := time.Now()
+	drawn := 0
+	var last []string
+	for {
+		states, err := composeServiceStates(runtime, global)
+		if err != nil {
+			return err
+		}
+		lines, failed, done := statusTable(states, probePort, time.Since(start))
+
+		if live {
+			if drawn > 0 {
+				fmt.Fprintf(out, "\033[%dA", drawn)
+			}
+			for _, line := range lines {
+				fmt.Fprintf(out, "\033[K%s\n", line)
+			}
+			drawn = len(lines)
+		} else if strings.Join(lines[1:], "\n") != strings.Join(last, "\n") {
+			// Without a terminal only changes are printed
+			for _, line := range lines[1:] {
+				fmt.Fprintln(out, line)
+			}
+			last = lines[1:]
+		}
+
+		if len(failed) > 0 {
+			printComposeLogs(runtime, global, failed, out)
+			return fmt.Errorf("unhealthy services: %s", strings.Join(failed, ", "))
+		}
+		if done {
+			fmt.Fprintf(out, "✅ All %d services ready after %s\n", len(states), time.Since(start).Truncate(time.Second))
+			return nil
+		}
+		if time.Since(start) >= timeout {
+			var notReady []string
+			for _, state := range states {
+				if readiness, _ := state.readiness(probePort); readiness == serviceStarting {
+					notReady = append(notReady, state.Service)
+				}
+			}
+			printComposeLogs(runtime, global, notReady, out)
+			if len(states) == 0 {
+				return fmt.Errorf("no containers started within %s", timeout)
+			}
+			return fmt.Errorf("services not ready within %s: %s", timeout, strings.Join(notReady, ", "))
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// printComposeLogs prints the last log lines of services
+func printComposeLogs(runtime string, global, services []string, out io.Writer) {
+	for _, service := range services {
+		fmt.Fprintf(out, "\n📋 Logs of %s (last %d lines):\n", service, composeLogLines)
+		logArgs := append(append([]string{}, global...), "logs", "--no-color", "--tail", strconv.Itoa(composeLogLines), service)
+		cmd := composeCommand(runtime, logArgs...)
+		cmd.Stdout = out
+		cmd.Stderr = out
+		if err := cmd.Run(); err != nil {
+			fmt.Fprintf(out, "   (logs not available: %v)\n", err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComposeWaitArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		global  string
+		up      string
+		wait    bool
+		timeout time.Duration
+	}{
+		{[]string{"-f", "up.yml", "-p", "pft", "up", "--wait"}, "-f up.yml -p pft", "up -d", true, DefaultComposeWaitTimeout},
+		{[]string{"up", "-d", "--wait", "--wait-timeout", "90", "web"}, "", "up -d web", true, 90 * time.Second},
+		{[]string{"--env-file", ".env", "up", "--wait-timeout=2m", "--wait"}, "--env-file .env", "up -d", true, 2 * time.Minute},
+		{[]string{"-f", "compose.yml", "up", "-d"}, "", "-f compose.yml up -d", false, DefaultComposeWaitTimeout},
+		{[]string{"logs", "--wait"}, "", "logs --wait", false, DefaultComposeWaitTimeout},
+	}
+	for _, tt := range tests {
+		global, up, wait, timeout, err := composeWaitArgs(tt.args)
+		if err != nil {
+			t.Fatalf("%v: %v", tt.args, err)
+		}
+		if strings.Join(global, " ") != tt.global || strings.Join(up, " ") != tt.up || wait != tt.wait || timeout != tt.timeout {
+			t.Errorf("%v: global %v, up %v, wait %v, timeout %s", tt.args, global, up, wait, timeout)
+		}
+	}
+
+	if _, _, _, _, err := composeWaitArgs([]string{"up", "--wait", "--wait-timeout", "soon"}); err == nil {
+		t.Error("invalid timeout accepted")
+	}
+}
+
+func TestComposeReadiness(t *testing.T) {
+	states := parseServiceStates("web|running|0||8080 8080 \ndb|running|0|healthy|\nmigrate|exited|0||\napi|running|0|starting|\n\nworker|exited|1||\n")
+	if len(states) != 5 || states[0].Service != "api" || strings.Join(states[3].Ports, ",") != "8080" {
+		t.Fatalf("states %+v", states)
+	}
+
+	open := map[string]bool{}
+	probe := func(port string) bool { return open[port] }
+	want := map[string]string{"api": serviceStarting, "db": serviceReady, "migrate": serviceCompleted, "web": serviceStarting, "worker": serviceFailed}
+	for _, state := range states {
+		if readiness, detail := state.readiness(probe); readiness != want[state.Service] {
+			t.Errorf("%s: %s (%s), want %s", state.Service, readiness, detail, want[state.Service])
+		}
+	}
+
+	open["8080"] = true
+	if readiness, _ := states[3].readiness(probe); readiness != serviceReady {
+		t.Errorf("web with open port: %s", readiness)
+	}
+	lines, failed, done := statusTable(states, probe, 3*time.Second)
+	if done || strings.Join(failed, ",") != "worker" || len(lines) != 6 {
+		t.Errorf("table %v, failed %v, done %v", lines, failed, done)
+	}
+	if _, _, done := statusTable(nil, probe, 0); done {
+		t.Error("project without containers reported ready")
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"portunix.ai/app/container"
+	"portunix.ai/portunix/pkg/logging"
+)
+
+// handleContainerEvents implements `container events`: it streams the
+// start/stop/die/oom events of Docker and Podman as one stream until
+// interrupted. With --json every event is a JSON line for other tools, such
+// as `pft status --watch`.
+func handleContainerEvents(args []string) {
+	var filters []string
+	var since, runtimeName string
+	jsonOutput := false
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			showEventsHelp()
+			return
+		case "--json":
+			jsonOutput = true
+		case "--filter", "-f":
+			if i+1 < len(args) {
+				filters = append(filters, args[i+1])
+				i++
+			}
+		case "--since":
+			if i+1 < len(args) {
+				since = args[i+1]
+				i++
+			}
+		case "--runtime":
+			if i+1 < len(args) {
+				runtimeName = args[i+1]
+				i++
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "❌ Error: unknown option: %s\n", args[i])
+			showEventsHelp()
+			os.Exit(1)
+		}
+	}
+
+	filter, err := container.ParseEventFilters(filters)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	runtimes := container.EventRuntimes()
+	if runtimeName != "" {
+		if runtimeName != "docker" && runtimeName != "podman" {
+			fmt.Fprintf(os.Stderr, "❌ Error: unknown runtime '%s' (must be 'docker' or 'podman')\n", runtimeName)
+			os.Exit(1)
+		}
+		var selected []container.RuntimeType
+		for _, rt := range runtimes {
+			if string(rt) == runtimeName {
+				selected = append(selected, rt)
+			}
+		}
+		runtimes = selected
+	}
+	if len(runtimes) == 0 {
+		fmt.Fprintln(os.Stderr, "❌ Error: neither Podman nor Docker is available")
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if !jsonOutput {
+		fmt.Fprintf(os.Stderr, "Watching container events (Ctrl+C to stop)...\n")
+	}
+
+	var mu sync.Mutex
+	emit := func(e *container.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if jsonOutput {
+			data, _ := json.Marshal(e)
+			fmt.Println(string(data))
+			return
+		}
+		fmt.Println(formatEvent(e))
+	}
+
+	var wg sync.WaitGroup
+	failed := 0
+	for _, rt := range runtimes {
+		wg.Add(1)
+		go func(rt container.RuntimeType) {
+			defer wg.Done()
+			logging.Debug("streaming container events", "runtime", rt, "filters", filters)
+			if err := container.StreamEvents(ctx, rt, filter, since, emit); err != nil {
+				mu.Lock()
+				failed++
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+				mu.Unlock()
+			}
+		}(rt)
+	}
+	wg.Wait()
+
+	if failed == len(runtimes) {
+		os.Exit(1)
+	}
+}
+
+// formatEvent returns the human-readable line of an event
+func formatEvent(e *container.Event) string {
+	id := e.ID
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	line := fmt.Sprintf("%s  %-6s  %-5s  %-30s  %s", e.Time.Local().Format("2006-01-02 15:04:05"), e.Runtime, e.Action, e.Name, id)
+	if e.Image != "" {
+		line += "  " + e.Image
+	}
+	if e.ExitCode != nil {
+		line += fmt.Sprintf("  (exit code %d)", *e.ExitCode)
+	}
+	return line
+}
+
+func showEventsHelp() {
+	fmt.Println("Usage: portunix container events [OPTIONS]")
+	fmt.Println()
+	fmt.Println("📡 WATCH CONTAINER EVENTS")
+	fmt.Println()
+	fmt.Println("Stream start, stop, die and oom events of Docker and Podman containers")
+	fmt.Println("as one stream until interrupted. Both runtimes are watched when installed.")
+	fmt.Println("Podman has no oom event; it reports a container killed for memory as die.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -f, --filter <key=value>  Filter events (repeatable):")
+	fmt.Println("                              name=<pattern>  container name, * wildcards allowed")
+	fmt.Println("                              event=<action>  start, stop, die or oom")
+	fmt.Println("                              other filters (label=..., image=...) go to the runtime")
+	fmt.Println("  --since <time>            Also show events since a time or duration (e.g. 10m)")
+	fmt.Println("  --runtime <name>          Watch only docker or podman")
+	fmt.Println("  --json                    Output one JSON object per event")
+	fmt.Println("  -h, --help                Show this help message")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix container events")
+	fmt.Println("  portunix container events --filter name=fider-*")
+	fmt.Println("  portunix container events --filter event=die --filter event=oom --json")
+	fmt.Println("  portunix container events --since 1h --runtime podman")
+}
@@ -0,0 +1,282 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"strings"
+
+	"portunix.ai/app/aihelp"
+)
+
+// containerAIHelp describes all container subcommands for --help-ai
+func containerAIHelp() aihelp.Help {
+	name := aihelp.Argument{Name: "container-name", Type: "string", Required: true, Description: "Name or ID of the container"}
+	names := aihelp.Argument{Name: "container-name", Type: "string", Required: true, Variadic: true, Description: "Names or IDs of containers"}
+	format := aihelp.Flag{Name: "format", Shorthand: "f", Type: "string", Description: "Go template for selective output (runtime semantics)"}
+
+	return aihelp.Help{
+		Tool:        "ptx-container",
+		Version:     version,
+		Description: "Universal container management with automatic Docker/Podman selection",
+		Commands: []aihelp.Command{
+			{
+				Name:        "container run",
+				Description: "Create and start a new container using the automatically selected runtime",
+				Arguments: []aihelp.Argument{
+					{Name: "image", Type: "string", Required: true, Description: "Container image"},
+					{Name: "command", Type: "string", Variadic: true, Description: "Command to run in the container"},
+				},
+				Flags: []aihelp.Flag{
+					{Name: "detach", Shorthand: "d", Type: "boolean", Description: "Run container in background"},
+					{Name: "interactive", Shorthand: "i", Type: "boolean", Description: "Keep STDIN open"},
+					{Name: "tty", Shorthand: "t", Type: "boolean", Description: "Allocate pseudo-TTY"},
+					{Name: "name", Type: "string", Description: "Assign a name to the container"},
+					{Name: "network", Type: "string", Description: "Connect container to a network"},
+					{Name: "port", Shorthand: "p", Type: "stringArray", Description: "Publish container ports to host (host:container)"},
+					{Name: "volume", Shorthand: "v", Type: "stringArray", Description: "Bind mount volumes (host:container)"},
+					{Name: "env", Shorthand: "e", Type: "stringArray", Description: "Set environment variables (KEY=VALUE)"},
+				},
+				Examples: []string{
+					"portunix container run ubuntu:22.04 echo \"Hello World\"",
+					"portunix container run -d --name test-container ubuntu:22.04 bash",
+					"portunix container run -d -p 8080:80 nginx:latest",
+				},
+			},
+			{
+				Name:        "container run-in-container",
+				Description: "Run package installation inside a clean container for safe testing",
+				Arguments:   []aihelp.Argument{{Name: "package", Type: "string", Required: true, Description: "Package to install; omitted with --template"}},
+				Flags: []aihelp.Flag{
+					{Name: "image", Type: "string", Default: "ubuntu:22.04", Description: "Container image to use"},
+					{Name: "template", Type: "string", Description: "Create the environment of a template, see 'container templates'"},
+					{Name: "install", Type: "stringArray", Description: "Install a package; repeatable"},
+					{Name: "workdir", Type: "string", Description: "Working directory inside the container"},
+				},
+				Examples: []string{
+					"portunix container run-in-container nodejs",
+					"portunix container run-in-container python --image debian:bookworm",
+					"portunix container run-in-container --template python-dev",
+				},
+			},
+			{
+				Name:        "container templates",
+				Description: "List environment templates for run-in-container",
+				Flags:       []aihelp.Flag{{Name: "json", Type: "boolean", Description: "Output in JSON format"}},
+				Examples:    []string{"portunix container templates --json"},
+			},
+			{
+				Name:        "container exec",
+				Description: "Execute a command inside a running container",
+				Arguments: []aihelp.Argument{
+					name,
+					{Name: "command", Type: "string", Required: true, Description: "Command to execute"},
+					{Name: "args", Type: "string", Variadic: true, Description: "Arguments for the command"},
+				},
+				Flags: []aihelp.Flag{
+					{Name: "env-file", Type: "stringArray", Description: "Set the variables of a .env file"},
+					{Name: "env", Shorthand: "e", Type: "stringArray", Description: "Set KEY=VALUE; KEY alone forwards the host variable"},
+					{Name: "copy-env", Type: "stringArray", Description: "Forward host variables matching a glob, e.g. 'AWS_*'"},
+					{Name: "workdir", Shorthand: "w", Type: "string", Description: "Working directory inside the container"},
+					{Name: "user", Shorthand: "u", Type: "string", Description: "User (name or UID[:GID]) running the command"},
+					{Name: "no-tty", Shorthand: "T", Type: "boolean", Description: "Never allocate a pseudo-TTY"},
+					{Name: "detach", Shorthand: "d", Type: "boolean", Description: "Run the command in the background"},
+				},
+				Examples: []string{
+					"portunix container exec my-container bash",
+					"portunix container exec python-dev python --version",
+					"portunix container exec --env-file .env -w /app app npm test",
+				},
+			},
+			{
+				Name:        "container list",
+				Description: "List containers from all available runtimes",
+				Examples:    []string{"portunix container list"},
+			},
+			{
+				Name:        "container start",
+				Description: "Start a stopped container",
+				Arguments:   []aihelp.Argument{name},
+				Examples:    []string{"portunix container start web-server"},
+			},
+			{
+				Name:        "container stop",
+				Description: "Stop a running container",
+				Arguments:   []aihelp.Argument{name},
+				Examples:    []string{"portunix container stop web-server"},
+			},
+			{
+				Name:        "container rm",
+				Description: "Remove one or more containers",
+				Arguments:   []aihelp.Argument{names},
+				Flags:       []aihelp.Flag{{Name: "force", Shorthand: "f", Type: "boolean", Description: "Force removal of running containers"}},
+				Examples:    []string{"portunix container rm test-container", "portunix container rm web-server -f"},
+			},
+			{
+				Name:        "container logs",
+				Description: "Show container logs",
+				Arguments:   []aihelp.Argument{name},
+				Flags:       []aihelp.Flag{{Name: "follow", Shorthand: "f", Type: "boolean", Description: "Follow log output"}},
+				Examples:    []string{"portunix container logs web-server --follow"},
+			},
+			{
+				Name:        "container cp",
+				Description: "Copy files or directories between a container and the host",
+				Arguments: []aihelp.Argument{
+					{Name: "source", Type: "path", Required: true, Description: "Source path (local path or container:path)"},
+					{Name: "destination", Type: "path", Required: true, Description: "Destination path (local path or container:path)"},
+				},
+				Examples: []string{"portunix container cp ./config.json mycontainer:/app/config.json"},
+			},
+			{
+				Name:        "container events",
+				Description: "Stream start, stop, die and oom events of Docker and Podman containers until interrupted",
+				Flags: []aihelp.Flag{
+					{Name: "filter", Shorthand: "f", Type: "stringArray", Description: "Filter events: name=<pattern>, event=<action>, or a runtime filter such as label=..."},
+					{Name: "since", Type: "string", Description: "Also show events since a time or duration"},
+					{Name: "runtime", Type: "string", Description: "Watch only docker or podman"},
+					{Name: "json", Type: "boolean", Description: "Output one JSON object per event"},
+				},
+				Examples: []string{
+					"portunix container events --filter name=fider-*",
+					"portunix container events --filter event=die --json",
+				},
+			},
+			{
+				Name:        "container inspect",
+				Description: "Show low-level container details",
+				Arguments:   []aihelp.Argument{names},
+				Flags:       []aihelp.Flag{format},
+				Examples:    []string{"portunix container inspect my-container -f '{{.Config.Env}}'"},
+			},
+			{
+				Name:        "container info",
+				Description: "Show container runtime information and availability",
+				Examples:    []string{"portunix container info"},
+			},
+			{
+				Name:        "container check",
+				Description: "Check container runtime capabilities and versions",
+				Flags:       []aihelp.Flag{{Name: "refresh", Type: "boolean", Description: "Force re-detection of capabilities"}},
+				Examples:    []string{"portunix container check"},
+			},
+			{
+				Name:        "container checkpoint",
+				Description: "Save a running container's memory and processes to disk with CRIU and stop it, so it survives a host reboot; needs rootful Podman and CRIU, Docker is not supported",
+				Arguments:   []aihelp.Argument{name},
+				Flags: []aihelp.Flag{
+					{Name: "leave-running", Shorthand: "R", Type: "boolean", Description: "Keep the container running after the checkpoint"},
+					{Name: "tcp-established", Type: "boolean", Description: "Checkpoint open TCP connections"},
+					{Name: "export", Shorthand: "e", Type: "path", Description: "Also write the checkpoint to a tar.gz archive"},
+				},
+				Examples: []string{"sudo portunix container checkpoint python-dev", "sudo portunix container checkpoint python-dev --export /srv/python-dev.tar.gz"},
+			},
+			{
+				Name:        "container restore",
+				Description: "Resume a checkpointed container, or create one from a checkpoint archive with --import",
+				Arguments:   []aihelp.Argument{{Name: "container-name", Type: "string", Description: "Container to resume; with --import the name of the new container"}},
+				Flags: []aihelp.Flag{
+					{Name: "import", Shorthand: "i", Type: "path", Description: "Archive written by checkpoint --export"},
+					{Name: "tcp-established", Type: "boolean", Description: "Restore open TCP connections"},
+				},
+				Examples: []string{"sudo portunix container restore python-dev", "sudo portunix container restore --import /srv/python-dev.tar.gz python-dev-2"},
+			},
+			{
+				Name:        "container compose",
+				Description: "Run docker compose, docker-compose or podman-compose with all arguments passed through; 'up --wait' waits for healthchecks or port probes and fails with the logs of unhealthy services",
+				Arguments:   []aihelp.Argument{{Name: "args", Type: "string", Variadic: true, Description: "Compose arguments"}},
+				Flags: []aihelp.Flag{
+					{Name: "wait", Type: "boolean", Description: "With up: start detached and wait until all services are healthy or their ports open"},
+					{Name: "wait-timeout", Type: "string", Default: "5m", Description: "With up --wait: seconds or duration to wait"},
+				},
+				Examples: []string{
+					"portunix container compose -f docker-compose.yml up -d",
+					"portunix container compose -f docker-compose.yml up --wait",
+					"portunix container compose -f docker-compose.yml down",
+				},
+			},
+			{
+				Name:        "container compose-preflight",
+				Description: "Check if compose is ready (exit 0 ready, 1 not ready), including Docker rootless, WSL integration and SELinux labels",
+				Flags: []aihelp.Flag{
+					{Name: "json", Type: "boolean", Description: "Output result as JSON; problems are listed in issues"},
+					{Name: "file", Shorthand: "f", Type: "string", Description: "Compose file whose bind mounts are checked for SELinux labels"},
+					{Name: "fix", Type: "boolean", Description: "Run the fix commands of the problems after confirmation"},
+					{Name: "yes", Shorthand: "y", Type: "boolean", Description: "Run the fixes without asking"},
+				},
+				Examples: []string{
+					"portunix container compose-preflight --json",
+					"portunix container compose-preflight --fix --yes",
+				},
+			},
+			{
+				Name:        "container network create",
+				Description: "Create a network (idempotent)",
+				Arguments:   []aihelp.Argument{{Name: "name", Type: "string", Required: true}},
+				Flags: []aihelp.Flag{
+					{Name: "driver", Type: "string", Description: "Network driver"},
+					{Name: "subnet", Type: "string", Description: "Subnet in CIDR format"},
+					{Name: "gateway", Type: "string", Description: "Gateway IP address"},
+				},
+				Examples: []string{"portunix container network create my-net --driver bridge --subnet 10.88.0.0/16"},
+			},
+			{Name: "container network list", Description: "List available networks"},
+			{
+				Name:        "container network inspect",
+				Description: "Show low-level network information",
+				Arguments:   []aihelp.Argument{{Name: "name", Type: "string", Required: true}},
+				Flags:       []aihelp.Flag{format},
+			},
+			{
+				Name:        "container network rm",
+				Description: "Remove one or more networks",
+				Arguments:   []aihelp.Argument{{Name: "name", Type: "string", Required: true, Variadic: true}},
+			},
+			{
+				Name:        "container volume create",
+				Description: "Create a named volume (idempotent)",
+				Arguments:   []aihelp.Argument{{Name: "name", Type: "string", Required: true}},
+				Flags:       []aihelp.Flag{{Name: "driver", Type: "string", Description: "Volume driver"}},
+			},
+			{Name: "container volume list", Description: "List available volumes"},
+			{
+				Name:        "container volume inspect",
+				Description: "Show low-level volume information",
+				Arguments:   []aihelp.Argument{{Name: "name", Type: "string", Required: true}},
+				Flags:       []aihelp.Flag{format},
+			},
+			{
+				Name:        "container volume rm",
+				Description: "Remove one or more volumes",
+				Arguments:   []aihelp.Argument{{Name: "name", Type: "string", Required: true, Variadic: true}},
+			},
+			{
+				Name:        "container volume prune",
+				Description: "Remove all unused volumes",
+				Flags:       []aihelp.Flag{{Name: "force", Type: "boolean", Description: "Do not prompt for confirmation"}},
+			},
+		},
+		GlobalFlags: append(aihelp.StandardGlobalFlags(),
+			aihelp.Flag{Name: "debug", Type: "boolean", Description: "Print the underlying podman/docker argv"}),
+	}
+}
+
+// showContainerAIHelp prints --help-ai output, limited to one subcommand
+// when given (e.g. "run" or "network create")
+func showContainerAIHelp(subcommand string) {
+	help := containerAIHelp()
+	if subcommand != "" {
+		prefix := "container " + subcommand
+		var filtered []aihelp.Command
+		for _, c := range help.Commands {
+			if c.Name == prefix || strings.HasPrefix(c.Name, prefix+" ") {
+				filtered = append(filtered, c)
+			}
+		}
+		if len(filtered) > 0 {
+			help.Commands = filtered
+		}
+	}
+	help.Print()
+}
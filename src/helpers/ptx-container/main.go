@@ -5,14 +5,22 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
+	"portunix.ai/app/container"
+	"portunix.ai/app/journal"
+	"portunix.ai/portunix/pkg/logging"
 )
 
 var version = "dev"
@@ -62,6 +70,9 @@ func handleCommand(args []string) {
 	for _, arg := range args {
 		if arg == "--debug" {
 			debugMode = true
+		} else if arg == "--verbose" || arg == "--quiet" || arg == "--log-json" {
+			// Global logging flags are applied via environment by the parent
+			continue
 		} else {
 			filteredArgs = append(filteredArgs, arg)
 		}
@@ -78,20 +89,27 @@ func handleCommand(args []string) {
 
 	switch command {
 	case "container", "docker", "podman":
+		if i := aiHelpPosition(subArgs); i >= 0 {
+			showContainerAIHelp(strings.Join(subArgs[:i], " "))
+			return
+		}
 		if len(subArgs) == 0 || (len(subArgs) == 1 && (subArgs[0] == "--help" || subArgs[0] == "-h")) {
 			// Show container help with logical command structure
 			fmt.Printf("Usage: portunix %s [command]\n\n", command)
 			fmt.Println("Available Commands:")
 			fmt.Println("  check            Check container runtime capabilities and versions")
+			fmt.Println("  checkpoint       Save a running container to disk with CRIU (Podman)")
 			fmt.Println("  compose          Run docker-compose/podman-compose commands (universal runtime)")
 			fmt.Println("  compose-preflight Check if compose is ready (daemon/socket running)")
 			fmt.Println("  cp               Copy files/folders between container and host")
+			fmt.Println("  events           Stream container start/stop/die/oom events (Docker and Podman)")
 			fmt.Println("  exec             Execute command in container (universal runtime)")
 			fmt.Println("  info             Show container runtime information and availability")
 			fmt.Println("  inspect          Show low-level container details (universal runtime)")
 			fmt.Println("  list             List containers from all available runtimes")
 			fmt.Println("  logs             Show container logs (universal runtime)")
 			fmt.Println("  network          Manage container networks (create/list/inspect/rm)")
+			fmt.Println("  restore          Resume a checkpointed container (Podman)")
 			fmt.Println("  rm               Remove container (universal runtime)")
 			fmt.Println("  run              Run new container (universal runtime)")
 			fmt.Println("  run-in-container Run installation in container (RECOMMENDED for testing)")
@@ -108,11 +126,28 @@ func handleCommand(args []string) {
 			// Implement actual container logic
 			handleContainerSubcommand(command, subArgs)
 		}
+	case "--help-ai":
+		showContainerAIHelp("")
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
 	}
 }
 
+// aiHelpPosition returns the index of --help-ai when it directly follows the
+// (sub)command words, e.g. "run --help-ai" or "network create --help-ai".
+// Later positions belong to the wrapped runtime and are passed through.
+func aiHelpPosition(args []string) int {
+	for i := 0; i < len(args) && i < 3; i++ {
+		if args[i] == "--help-ai" {
+			return i
+		}
+		if strings.HasPrefix(args[i], "-") {
+			break
+		}
+	}
+	return -1
+}
+
 // handleContainerSubcommand handles specific container subcommands
 func handleContainerSubcommand(command string, subArgs []string) {
 	if len(subArgs) == 0 {
@@ -128,6 +163,8 @@ func handleContainerSubcommand(command string, subArgs []string) {
 		handleContainerRun(cmdArgs)
 	case "run-in-container":
 		handleRunInContainer(cmdArgs)
+	case "templates":
+		handleContainerTemplates(cmdArgs)
 	case "exec":
 		handleContainerExec(cmdArgs)
 	case "list":
@@ -142,10 +179,16 @@ func handleContainerSubcommand(command string, subArgs []string) {
 		handleContainerLogs(cmdArgs)
 	case "cp":
 		handleContainerCp(cmdArgs)
+	case "events":
+		handleContainerEvents(cmdArgs)
 	case "info":
 		handleContainerInfo(cmdArgs)
 	case "check":
 		handleContainerCheck(cmdArgs)
+	case "checkpoint":
+		handleContainerCheckpoint(cmdArgs)
+	case "restore":
+		handleContainerRestore(cmdArgs)
 	case "compose":
 		handleContainerCompose(cmdArgs)
 	case "compose-preflight":
@@ -158,7 +201,7 @@ func handleContainerSubcommand(command string, subArgs []string) {
 		handleContainerInspect(cmdArgs)
 	default:
 		fmt.Printf("Unknown %s subcommand: %s\n", command, subcommand)
-		fmt.Printf("Available subcommands: run, run-in-container, exec, list, stop, start, rm, logs, cp, info, check, compose, compose-preflight, network, volume, inspect\n")
+		fmt.Printf("Available subcommands: run, run-in-container, templates, exec, list, stop, start, rm, logs, cp, events, info, check, checkpoint, restore, compose, compose-preflight, network, volume, inspect\n")
 	}
 }
 
@@ -172,45 +215,130 @@ func handleRunInContainer(args []string) {
 		}
 	}
 
+	args, err := container.ApplyTemplate(args, container.TemplateDirs())
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	if len(args) == 0 {
 		fmt.Println("❌ Error: Installation type required")
 		showRunInContainerHelp()
-		return
+		os.Exit(1)
 	}
 
-	// Parse arguments: extract installationType and --image flag
-	var installationType string
-	var containerImage string = "ubuntu:22.04" // default
-	var remainingArgs []string
-
-	installationType = args[0]
-
-	// Parse remaining arguments for flags
-	for i := 1; i < len(args); i++ {
-		if args[i] == "--image" && i+1 < len(args) {
-			containerImage = args[i+1]
-			i++ // Skip next argument as it's the image value
-		} else {
-			remainingArgs = append(remainingArgs, args[i])
-		}
+	opts, err := parseRunInContainerArgs(args)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
 	}
 
-	fmt.Printf("🐳 Starting container installation for: %s\n", installationType)
-	fmt.Printf("📦 Using image: %s\n", containerImage)
+	fmt.Printf("🐳 Starting container installation for: %s\n", opts.installationType)
+	fmt.Printf("📦 Using image: %s\n", opts.image)
 
 	// Try Podman first, then Docker
 	if isPodmanAvailable() {
 		fmt.Println("Using Podman as container runtime...")
-		runPodmanInContainerWithImage(installationType, containerImage, remainingArgs)
+		runPodmanInContainerWithImage(opts.installationType, opts.image, opts.packages, opts.runArgs)
 	} else if isDockerAvailable() {
 		fmt.Println("Using Docker as container runtime...")
-		runDockerInContainerWithImage(installationType, containerImage, remainingArgs)
+		runDockerInContainerWithImage(opts.installationType, opts.image, opts.packages, opts.runArgs)
 	} else {
 		fmt.Println("❌ Error: Neither Podman nor Docker is available")
 		fmt.Println("Please install Podman or Docker first")
+		os.Exit(1)
 	}
 }
 
+// runInContainerOptions are the parsed arguments of run-in-container
+type runInContainerOptions struct {
+	installationType string
+	image            string
+	// packages are installed with 'portunix install'; the installation
+	// type when no --install is given
+	packages []string
+	// runArgs are passed to the container runtime: volumes, ports,
+	// environment and working directory
+	runArgs []string
+}
+
+// parseRunInContainerArgs parses "<installation-type> [flags]". Unknown
+// flags and flags without a value are errors.
+func parseRunInContainerArgs(args []string) (*runInContainerOptions, error) {
+	opts := &runInContainerOptions{installationType: args[0], image: "ubuntu:22.04"}
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--image", "--install", "-v", "--volume", "-p", "--port", "-e", "--env", "--workdir":
+		default:
+			return nil, fmt.Errorf("unknown argument %q (see 'portunix container run-in-container --help')", args[i])
+		}
+		if i+1 == len(args) {
+			return nil, fmt.Errorf("flag %s needs a value", args[i])
+		}
+		switch value := args[i+1]; args[i] {
+		case "--image":
+			opts.image = value
+		case "--install":
+			opts.packages = append(opts.packages, value)
+		case "--workdir":
+			opts.runArgs = append(opts.runArgs, "-w", value)
+		default:
+			opts.runArgs = append(opts.runArgs, args[i], value)
+		}
+		i++ // Skip the flag value
+	}
+	if len(opts.packages) == 0 {
+		opts.packages = []string{opts.installationType}
+	}
+	return opts, nil
+}
+
+// handleContainerTemplates lists the environment templates of
+// run-in-container
+func handleContainerTemplates(args []string) {
+	formatJSON := false
+	for _, arg := range args {
+		switch arg {
+		case "--help", "-h":
+			showTemplatesHelp()
+			return
+		case "--json":
+			formatJSON = true
+		}
+	}
+
+	templates, err := container.ListTemplates(container.TemplateDirs())
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := container.PrintTemplates(os.Stdout, templates, formatJSON); err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// showTemplatesHelp displays help for the templates subcommand
+func showTemplatesHelp() {
+	fmt.Println("Usage: portunix container templates [--json]")
+	fmt.Println()
+	fmt.Println("📋 ENVIRONMENT TEMPLATES FOR RUN-IN-CONTAINER")
+	fmt.Println()
+	fmt.Println("List the templates usable with 'portunix container run-in-container --template NAME'.")
+	fmt.Println("A template defines the base image, the tools installed with 'portunix install',")
+	fmt.Println("cache directories kept in portunix-cache-<name> volumes, published ports and")
+	fmt.Println("environment variables.")
+	fmt.Println()
+	fmt.Println("Custom templates are *.yaml files in the directories of")
+	fmt.Println("PORTUNIX_CONTAINER_TEMPLATE_PATH or ~/.portunix/container-templates; they")
+	fmt.Println("override built-in templates of the same name.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --json       Output in JSON format")
+	fmt.Println("  -h, --help   Show this help message")
+}
+
 // showRunInContainerHelp displays help for the run-in-container subcommand
 func showRunInContainerHelp() {
 	fmt.Println("Usage: portunix container run-in-container [OPTIONS] <PACKAGE>")
@@ -231,10 +359,16 @@ func showRunInContainerHelp() {
 	fmt.Println()
 	fmt.Println("Options:")
 	fmt.Println("  --image <IMAGE>     Container image to use (default: ubuntu:22.04)")
+	fmt.Println("  --template <NAME>   Create the environment of a template instead of <PACKAGE>")
+	fmt.Println("                      (see 'portunix container templates')")
+	fmt.Println("  --install <PACKAGE> Install a package; repeatable")
+	fmt.Println("  -v, -p, -e <VALUE>  Volume, port and environment variable of the container")
+	fmt.Println("  --workdir <DIR>     Working directory inside the container")
 	fmt.Println("  -h, --help          Show this help message")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  portunix container run-in-container nodejs")
+	fmt.Println("  portunix container run-in-container --template python-dev")
 	fmt.Println("  portunix container run-in-container python --image debian:bookworm")
 	fmt.Println("  portunix container run-in-container ansible --image ubuntu:22.04")
 	fmt.Println("  portunix container run-in-container claude-code")
@@ -312,47 +446,66 @@ func handleContainerRun(args []string) {
 
 // Placeholder implementations for other subcommands
 func handleContainerExec(args []string) {
-	// Check for help flag first
-	for _, arg := range args {
-		if arg == "--help" || arg == "-h" {
-			showExecHelp()
-			return
-		}
+	// Check for help flag first; flags after the container name belong to
+	// the command
+	if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		showExecHelp()
+		return
 	}
 
-	if len(args) < 2 {
+	// Sessions are interactive unless -T or -d is given; without a
+	// terminal on stdin no pseudo-TTY is allocated
+	opts, rest, err := container.ParseExecFlags(append([]string{"-it"}, args...))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(containerExecFailed)
+	}
+	if len(rest) < 2 {
 		showExecHelp()
 		return
 	}
 
-	containerName := args[0]
-	command := args[1:]
+	containerName := rest[0]
+	command := rest[1:]
 
 	// Try Podman first, then Docker
 	// Silent execution - only show command output, not execution messages
 	if isPodmanAvailable() {
-		if err := execPodmanCommand(containerName, command); err != nil {
-			// Try Docker as fallback if Podman fails
-			if isDockerAvailable() {
-				if err := execDockerCommand(containerName, command); err != nil {
-					fmt.Fprintf(os.Stderr, "❌ Error: Failed to execute command in container '%s': %v\n", containerName, err)
-					os.Exit(1)
-				}
-			} else {
-				fmt.Fprintf(os.Stderr, "❌ Error: Failed to execute command in container '%s': %v\n", containerName, err)
-				os.Exit(1)
-			}
+		err = execPodmanCommand(containerName, command, opts)
+		// Try Docker as fallback if Podman could not run the command
+		if err != nil && execExitCode(err) == containerExecFailed && isDockerAvailable() {
+			err = execDockerCommand(containerName, command, opts)
 		}
 	} else if isDockerAvailable() {
-		if err := execDockerCommand(containerName, command); err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Error: Failed to execute command in container '%s': %v\n", containerName, err)
-			os.Exit(1)
-		}
+		err = execDockerCommand(containerName, command, opts)
 	} else {
 		fmt.Fprintln(os.Stderr, "❌ Error: Neither Podman nor Docker is available")
 		fmt.Fprintln(os.Stderr, "Please install Podman or Docker first")
-		os.Exit(1)
+		os.Exit(containerExecFailed)
+	}
+	if err == nil {
+		return
+	}
+	code := execExitCode(err)
+	if code == containerExecFailed {
+		fmt.Fprintf(os.Stderr, "❌ Error: Failed to execute command in container '%s': %v\n", containerName, err)
+	}
+	os.Exit(code)
+}
+
+// containerExecFailed is the exit code of exec when the command could not
+// be run, as with docker and podman
+const containerExecFailed = 125
+
+// execExitCode returns the exit code of the command of a failed exec, which
+// the runtime passes on, or containerExecFailed when the runtime could not
+// be started
+func execExitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
 	}
+	return containerExecFailed
 }
 
 func handleContainerList(args []string) {
@@ -643,12 +796,20 @@ func handleContainerInfo(args []string) {
 
 // ComposeStatus represents the status of compose readiness
 type ComposeStatus struct {
-	Ready           bool
-	Runtime         string
-	Version         string
-	DaemonRunning   bool
-	ErrorMessage    string
-	FixInstructions string
+	Ready           bool             `json:"ready"`
+	Runtime         string           `json:"runtime"`
+	Version         string           `json:"version"`
+	DaemonRunning   bool             `json:"daemon_running"`
+	ErrorMessage    string           `json:"error"`
+	FixInstructions string           `json:"fix"`
+	Issues          []PreflightIssue `json:"issues"`
+}
+
+// fail marks the status not ready because of issue
+func (s *ComposeStatus) fail(issue PreflightIssue) {
+	s.ErrorMessage = issue.Problem
+	s.FixInstructions = issue.Fix
+	s.Issues = append(s.Issues, issue)
 }
 
 // CheckComposeReady checks if compose is ready to use and returns detailed status
@@ -677,9 +838,20 @@ func CheckComposeReady() ComposeStatus {
 				status.Version = strings.TrimSpace(string(output))
 				return status
 			}
+		} else if issue, ok := dockerRootlessIssue(); ok {
+			status.fail(issue)
+			return status
+		} else if issue, ok := wslDockerDesktopIssue(); ok {
+			status.fail(issue)
+			return status
 		} else {
-			status.ErrorMessage = "Docker CLI installed but daemon is not running"
-			status.FixInstructions = "Start Docker daemon with: sudo systemctl start docker"
+			status.fail(PreflightIssue{
+				ID:       "docker-daemon",
+				Problem:  "Docker CLI installed but daemon is not running",
+				Fix:      "Start Docker daemon with: sudo systemctl start docker",
+				Commands: [][]string{{"sudo", "systemctl", "start", "docker"}},
+				Blocking: true,
+			})
 			return status
 		}
 	}
@@ -692,8 +864,13 @@ func CheckComposeReady() ComposeStatus {
 		socketRunning := isPodmanSocketRunning()
 
 		if !socketRunning {
-			status.ErrorMessage = "Podman installed but socket is not running"
-			status.FixInstructions = "systemctl --user enable --now podman.socket"
+			status.fail(PreflightIssue{
+				ID:       "podman-socket",
+				Problem:  "Podman installed but socket is not running",
+				Fix:      "systemctl --user enable --now podman.socket",
+				Commands: [][]string{{"systemctl", "--user", "enable", "--now", "podman.socket"}},
+				Blocking: true,
+			})
 			return status
 		}
 
@@ -716,27 +893,36 @@ func CheckComposeReady() ComposeStatus {
 			return status
 		}
 		// Podman works but no compose tool
-		status.ErrorMessage = "Podman is running but no compose tool is available"
-		status.FixInstructions = "Install podman-compose: pip install podman-compose"
+		status.fail(PreflightIssue{
+			ID:       "podman-compose",
+			Problem:  "Podman is running but no compose tool is available",
+			Fix:      "Install podman-compose: pip install podman-compose",
+			Commands: [][]string{{"pip", "install", "podman-compose"}},
+			Blocking: true,
+		})
+		return status
+	}
+
+	// Docker Desktop on Windows without the WSL integration of this distro
+	if issue, ok := wslDockerDesktopIssue(); ok {
+		status.fail(issue)
 		return status
 	}
 
 	// No container runtime found
-	status.ErrorMessage = "No container runtime (Docker or Podman) is installed"
-	status.FixInstructions = "Install Docker or Podman first"
+	status.fail(PreflightIssue{
+		ID:       "no-runtime",
+		Problem:  "No container runtime (Docker or Podman) is installed",
+		Fix:      "Install Docker or Podman first",
+		Blocking: true,
+	})
 	return status
 }
 
 // isPodmanSocketRunning checks if podman socket file exists and is accessible
 func isPodmanSocketRunning() bool {
 	// Check XDG_RUNTIME_DIR for user socket
-	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
-	if runtimeDir == "" {
-		// Fallback to /run/user/<uid>
-		runtimeDir = fmt.Sprintf("/run/user/%d", os.Getuid())
-	}
-
-	socketPath := filepath.Join(runtimeDir, "podman", "podman.sock")
+	socketPath := filepath.Join(userRuntimeDir(), "podman", "podman.sock")
 
 	// Check if socket file exists
 	if _, err := os.Stat(socketPath); err == nil {
@@ -775,23 +961,48 @@ func handleComposePreflight(args []string) {
 		}
 	}
 
-	// Check for --json flag
-	jsonOutput := false
-	for _, arg := range args {
-		if arg == "--json" {
+	jsonOutput, fix, yes := false, false, false
+	composeFile := ""
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--json":
 			jsonOutput = true
-			break
+		case args[i] == "--fix":
+			fix = true
+		case args[i] == "--yes" || args[i] == "-y":
+			yes = true
+		case (args[i] == "-f" || args[i] == "--file") && i+1 < len(args):
+			composeFile = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--file="):
+			composeFile = strings.TrimPrefix(args[i], "--file=")
 		}
 	}
+	composeFile = findComposeFile(composeFile)
 
-	status := CheckComposeReady()
+	// Fixes report to stderr with --json, which keeps stdout parseable
+	var out io.Writer = os.Stdout
+	if jsonOutput {
+		out = os.Stderr
+	}
+
+	status := checkComposePreflight(composeFile, out)
+	if fix && len(status.Issues) > 0 && fixPreflightIssues(status.Issues, yes, os.Stdin, out) {
+		fmt.Fprintln(out, "\n🔍 Checking again...")
+		status = checkComposePreflight(composeFile, out)
+	}
 
 	if jsonOutput {
 		// JSON output for programmatic use
-		fmt.Printf(`{"ready":%t,"runtime":"%s","version":"%s","daemon_running":%t,"error":"%s","fix":"%s"}`,
-			status.Ready, status.Runtime, status.Version, status.DaemonRunning,
-			status.ErrorMessage, status.FixInstructions)
-		fmt.Println()
+		if status.Issues == nil {
+			status.Issues = []PreflightIssue{}
+		}
+		data, err := json.Marshal(status)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error formatting JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
 		if !status.Ready {
 			os.Exit(1)
 		}
@@ -801,14 +1012,38 @@ func handleComposePreflight(args []string) {
 	// Human-readable output
 	if status.Ready {
 		fmt.Printf("✅ Compose is ready: %s (%s)\n", status.Runtime, status.Version)
+		for _, issue := range status.Issues {
+			fmt.Printf("\n⚠️  %s\n   Fix: %s\n", issue.Problem, issue.Fix)
+		}
+		if len(status.Issues) > 0 && !fix {
+			fmt.Println("\n💡 Run 'portunix container compose-preflight --fix' to apply the fixes")
+		}
 	} else {
 		fmt.Printf("❌ Compose is NOT ready\n\n")
 		fmt.Printf("Problem: %s\n\n", status.ErrorMessage)
 		fmt.Printf("Solution: %s\n", status.FixInstructions)
+		if !fix && len(status.Issues) > 0 && len(status.Issues[0].Commands) > 0 {
+			fmt.Println("\n💡 Run 'portunix container compose-preflight --fix' to apply the fix")
+		}
 		os.Exit(1)
 	}
 }
 
+// checkComposePreflight checks compose readiness and, when it is ready, the
+// SELinux labels of the bind mounts of composeFile
+func checkComposePreflight(composeFile string, out io.Writer) ComposeStatus {
+	status := CheckComposeReady()
+	if !status.Ready {
+		return status
+	}
+	issues, err := selinuxIssues(composeFile)
+	if err != nil {
+		fmt.Fprintf(out, "⚠️  Could not check SELinux labels: %v\n", err)
+	}
+	status.Issues = append(status.Issues, issues...)
+	return status
+}
+
 func showComposePreflightHelp() {
 	fmt.Println("Usage: portunix container compose-preflight [OPTIONS]")
 	fmt.Println()
@@ -818,18 +1053,26 @@ func showComposePreflightHelp() {
 	fmt.Println("  • Docker/Podman installation")
 	fmt.Println("  • Docker daemon or Podman socket status")
 	fmt.Println("  • Compose tool availability")
+	fmt.Println("  • Docker rootless daemon and context")
+	fmt.Println("  • Docker Desktop WSL integration of the current WSL distro")
+	fmt.Println("  • SELinux labels of the bind mounts of the compose file")
 	fmt.Println()
 	fmt.Println("Options:")
-	fmt.Println("  --json       Output result as JSON for programmatic use")
-	fmt.Println("  -h, --help   Show this help message")
+	fmt.Println("  --json            Output result as JSON for programmatic use")
+	fmt.Println("  -f, --file FILE   Compose file to check (default: compose.yaml, docker-compose.yml, ...)")
+	fmt.Println("  --fix             Show the fix of each problem and run its commands after confirmation")
+	fmt.Println("  -y, --yes         Run the fixes without asking")
+	fmt.Println("  -h, --help        Show this help message")
 	fmt.Println()
 	fmt.Println("Exit codes:")
-	fmt.Println("  0 - Compose is ready")
+	fmt.Println("  0 - Compose is ready (SELinux label problems are reported as warnings)")
 	fmt.Println("  1 - Compose is NOT ready (with instructions to fix)")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  portunix container compose-preflight")
 	fmt.Println("  portunix container compose-preflight --json")
+	fmt.Println("  portunix container compose-preflight --fix")
+	fmt.Println("  portunix container compose-preflight -f deploy/compose.yaml --fix --yes")
 }
 
 // handleContainerCompose handles compose subcommand - passes through to docker-compose/podman-compose
@@ -861,21 +1104,19 @@ func handleContainerCompose(args []string) {
 		return
 	}
 
+	// 'up --wait' starts the project detached, then waits for its services
+	global, upArgs, wait, timeout, err := composeWaitArgs(args)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if wait {
+		args = append(append([]string{}, global...), upArgs...)
+	}
+
 	// Execute compose command
-	var cmd *exec.Cmd
-	switch runtime {
-	case "Docker Compose V2":
-		cmdArgs := append([]string{"compose"}, args...)
-		cmd = exec.Command("docker", cmdArgs...)
-	case "Docker Compose V1":
-		cmd = exec.Command("docker-compose", args...)
-	case "Podman Compose":
-		// Built-in podman compose (Podman 3.0+)
-		cmdArgs := append([]string{"compose"}, args...)
-		cmd = exec.Command("podman", cmdArgs...)
-	case "Podman Compose (standalone)":
-		cmd = exec.Command("podman-compose", args...)
-	default:
+	cmd := composeCommand(runtime, args...)
+	if cmd == nil {
 		fmt.Printf("❌ Unknown compose runtime: %s\n", runtime)
 		return
 	}
@@ -888,6 +1129,14 @@ func handleContainerCompose(args []string) {
 		fmt.Printf("❌ Compose command failed: %v\n", err)
 		os.Exit(1)
 	}
+
+	if wait {
+		fmt.Println()
+		if err := waitForCompose(runtime, global, timeout, os.Stdout); err != nil {
+			fmt.Printf("\n❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
 }
 
 // detectComposeRuntime detects available compose tool and returns name and version
@@ -963,8 +1212,20 @@ func showComposeHelp() {
 	fmt.Println("  portunix container compose -f <file> ps")
 	fmt.Println("  portunix container compose -f <file> exec <service> <command>")
 	fmt.Println()
+	fmt.Println("⏳ WAITING FOR SERVICES:")
+	fmt.Println("  portunix container compose -f <file> up --wait [--wait-timeout 5m]")
+	fmt.Println()
+	fmt.Println("  Starts the project detached and waits until every service is ready: healthy")
+	fmt.Println("  for services with a healthcheck, otherwise accepting connections on its")
+	fmt.Println("  published ports. A live status table shows the progress. An unhealthy")
+	fmt.Println("  service or a crashed container stops the wait at once and the logs of the")
+	fmt.Println("  failed services are printed; the command exits with 1. Containers that")
+	fmt.Println("  exit with 0 (migrations, init jobs) count as completed. Works with all")
+	fmt.Println("  compose runtimes; --wait-timeout takes seconds or a duration (default 5m).")
+	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  portunix container compose -f docker-compose.yml up -d")
+	fmt.Println("  portunix container compose -f docker-compose.yml up --wait --wait-timeout 180")
 	fmt.Println("  portunix container compose -f docker-compose.yml down")
 	fmt.Println("  portunix container compose -f docker-compose.docs.yml up docs-server")
 	fmt.Println("  portunix container compose -f docker-compose.yml logs -f web")
@@ -1067,6 +1328,12 @@ func handleContainerCheck(args []string) {
 		fmt.Println("  - Volume mounting: ✓")
 		fmt.Println("  - Network creation: ✓")
 
+		if err := detectCheckpointSupport().problem(); err == nil {
+			fmt.Println("  - Checkpoint/restore (CRIU): ✓")
+		} else {
+			fmt.Printf("  - Checkpoint/restore (CRIU): ✗ %v\n", err)
+		}
+
 		// Runtime active check
 		if dockerAvailable {
 			infoCmd := exec.Command("docker", "info")
@@ -1149,9 +1416,9 @@ func isDockerAvailable() bool {
 
 // Container runtime implementations
 // runPodmanInContainerWithImage runs installation in Podman container with specified image
-func runPodmanInContainerWithImage(installationType string, imageName string, args []string) {
+func runPodmanInContainerWithImage(installationType string, imageName string, packages []string, runArgs []string) {
 	// Create container and install specified software with provided image
-	runPodmanInContainerImpl(installationType, imageName, args)
+	runPodmanInContainerImpl(installationType, imageName, packages, runArgs)
 }
 
 func runPodmanInContainer(installationType string, args []string) {
@@ -1166,10 +1433,10 @@ func runPodmanInContainer(installationType string, args []string) {
 		}
 	}
 
-	runPodmanInContainerImpl(installationType, imageName, args)
+	runPodmanInContainerImpl(installationType, imageName, []string{installationType}, nil)
 }
 
-func runPodmanInContainerImpl(installationType string, imageName string, args []string) {
+func runPodmanInContainerImpl(installationType string, imageName string, packages []string, runArgs []string) {
 
 	containerName := fmt.Sprintf("portunix-test-%s", installationType)
 
@@ -1185,17 +1452,18 @@ func runPodmanInContainerImpl(installationType string, imageName string, args []
 	exec.Command("cp", "./portunix", tempPath).Run()
 
 	// Build run arguments with TTY detection
-	var runArgs []string
+	var args []string
 	if term.IsTerminal(int(os.Stdin.Fd())) {
-		runArgs = []string{"run", "--name", containerName, "-it", "--rm"}
+		args = []string{"run", "--name", containerName, "-it", "--rm"}
 	} else {
-		runArgs = []string{"run", "--name", containerName, "-i", "--rm"}
+		args = []string{"run", "--name", containerName, "-i", "--rm"}
 	}
-	runArgs = append(runArgs, "-v", fmt.Sprintf("%s:/usr/local/bin/portunix", tempPath))
-	runArgs = append(runArgs, imageName, "/bin/bash", "-c",
-		fmt.Sprintf("apt-get update && apt-get install -y python3 python3-pip && chmod +x /usr/local/bin/portunix && portunix install %s", installationType))
+	args = append(args, "-v", fmt.Sprintf("%s:/usr/local/bin/portunix", tempPath))
+	args = append(args, runArgs...)
+	args = append(args, imageName, "/bin/bash", "-c",
+		"apt-get update && apt-get install -y python3 python3-pip && chmod +x /usr/local/bin/portunix && "+installCommands(packages))
 
-	cmd := exec.Command("podman", runArgs...)
+	cmd := exec.Command("podman", args...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -1205,10 +1473,20 @@ func runPodmanInContainerImpl(installationType string, imageName string, args []
 	}
 }
 
+// installCommands returns the shell commands installing packages with
+// portunix
+func installCommands(packages []string) string {
+	commands := make([]string, len(packages))
+	for i, p := range packages {
+		commands[i] = "portunix install " + p
+	}
+	return strings.Join(commands, " && ")
+}
+
 // runDockerInContainerWithImage runs installation in Docker container with specified image
-func runDockerInContainerWithImage(installationType string, imageName string, args []string) {
+func runDockerInContainerWithImage(installationType string, imageName string, packages []string, runArgs []string) {
 	// Create container and install specified software with provided image
-	runDockerInContainerImpl(installationType, imageName, args)
+	runDockerInContainerImpl(installationType, imageName, packages, runArgs)
 }
 
 func runDockerInContainer(installationType string, args []string) {
@@ -1223,10 +1501,10 @@ func runDockerInContainer(installationType string, args []string) {
 		}
 	}
 
-	runDockerInContainerImpl(installationType, imageName, args)
+	runDockerInContainerImpl(installationType, imageName, []string{installationType}, nil)
 }
 
-func runDockerInContainerImpl(installationType string, imageName string, args []string) {
+func runDockerInContainerImpl(installationType string, imageName string, packages []string, runArgs []string) {
 
 	containerName := fmt.Sprintf("portunix-test-%s", installationType)
 
@@ -1242,17 +1520,18 @@ func runDockerInContainerImpl(installationType string, imageName string, args []
 	exec.Command("cp", "./portunix", tempPath).Run()
 
 	// Build run arguments with TTY detection
-	var runArgs []string
+	var args []string
 	if term.IsTerminal(int(os.Stdin.Fd())) {
-		runArgs = []string{"run", "--name", containerName, "-it", "--rm"}
+		args = []string{"run", "--name", containerName, "-it", "--rm"}
 	} else {
-		runArgs = []string{"run", "--name", containerName, "-i", "--rm"}
+		args = []string{"run", "--name", containerName, "-i", "--rm"}
 	}
-	runArgs = append(runArgs, "-v", fmt.Sprintf("%s:/usr/local/bin/portunix", tempPath))
-	runArgs = append(runArgs, imageName, "/bin/bash", "-c",
-		fmt.Sprintf("apt-get update && apt-get install -y python3 python3-pip && chmod +x /usr/local/bin/portunix && portunix install %s", installationType))
+	args = append(args, "-v", fmt.Sprintf("%s:/usr/local/bin/portunix", tempPath))
+	args = append(args, runArgs...)
+	args = append(args, imageName, "/bin/bash", "-c",
+		"apt-get update && apt-get install -y python3 python3-pip && chmod +x /usr/local/bin/portunix && "+installCommands(packages))
 
-	cmd := exec.Command("docker", runArgs...)
+	cmd := exec.Command("docker", args...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -1284,17 +1563,25 @@ func runPodmanContainer(image string, command []string) {
 		fmt.Fprintf(os.Stderr, "🔍 DEBUG detached: %v\n", detached)
 	}
 
+	logging.Debug("running container", "runtime", "podman", "args", args, "detached", detached)
 	cmd := exec.Command("podman", args...)
 	cmd.Stdin = os.Stdin
+	var output bytes.Buffer
 	cmd.Stdout = os.Stdout
+	if detached {
+		cmd.Stdout = io.MultiWriter(os.Stdout, &output)
+	}
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
-		fmt.Printf("❌ Podman run failed: %v\n", err)
+		logging.Error("Podman run failed", "error", err, "args", args, "exit_code", cmd.ProcessState.ExitCode())
 		// Surface the failure to the parent process (dispatcher / ptx-installer)
 		// — otherwise callers see a 0 exit and treat a failed run as a success.
 		os.Exit(1)
 	}
+	if detached {
+		journalContainer("podman", output.String())
+	}
 }
 
 func runDockerContainer(image string, command []string) {
@@ -1319,16 +1606,39 @@ func runDockerContainer(image string, command []string) {
 		fmt.Fprintf(os.Stderr, "🔍 DEBUG detached: %v\n", detached)
 	}
 
+	logging.Debug("running container", "runtime", "docker", "args", args, "detached", detached)
 	cmd := exec.Command("docker", args...)
 	cmd.Stdin = os.Stdin
+	var output bytes.Buffer
 	cmd.Stdout = os.Stdout
+	if detached {
+		cmd.Stdout = io.MultiWriter(os.Stdout, &output)
+	}
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
-		fmt.Printf("❌ Docker run failed: %v\n", err)
+		logging.Error("Docker run failed", "error", err, "args", args, "exit_code", cmd.ProcessState.ExitCode())
 		// See runPodmanContainer — surface failure so the parent sees non-zero exit.
 		os.Exit(1)
 	}
+	if detached {
+		journalContainer("docker", output.String())
+	}
+}
+
+// journalContainer records a container started in detached mode, which
+// outlives the command, in the portunix operation journal. The runtime
+// prints the ID of the new container last.
+func journalContainer(runtime, output string) {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return
+	}
+	op := journal.Current("ptx-container")
+	op.ContainerCreated(runtime, fields[len(fields)-1])
+	if err := op.Commit(); err != nil {
+		logging.Warn("failed to journal container", "runtime", runtime, "error", err)
+	}
 }
 
 // isDetachedMode checks if -d or --detach flag is present in image name or command args
@@ -1346,43 +1656,55 @@ func isDetachedMode(image string, command []string) bool {
 }
 
 // execPodmanCommand executes a command inside an existing Podman container
-func execPodmanCommand(containerName string, command []string) error {
+func execPodmanCommand(containerName string, command []string, opts container.ExecOptions) error {
 	// Only use -t flag if stdin is a terminal (interactive mode)
 	// This prevents "the input device is not a TTY" error on Windows
-	var args []string
-	if term.IsTerminal(int(os.Stdin.Fd())) {
-		args = []string{"exec", "-it", containerName}
-	} else {
-		args = []string{"exec", "-i", containerName}
+	opts = opts.ForStdin()
+	cmd, cleanup, err := opts.Command("podman", containerName, command)
+	if err != nil {
+		return err
 	}
-	args = append(args, command...)
+	defer cleanup()
+	args := cmd.Args[1:]
 
-	cmd := exec.Command("podman", args...)
-	cmd.Stdin = os.Stdin
+	logging.Debug("exec in container", "runtime", "podman", "args", args)
+	if opts.Interactive {
+		cmd.Stdin = os.Stdin
+	}
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	return cmd.Run()
+	err = cmd.Run()
+	if err != nil {
+		logging.Capture(zerolog.ErrorLevel, "container exec failed", "runtime", "podman", "args", args, "exit_code", cmd.ProcessState.ExitCode(), "error", err)
+	}
+	return err
 }
 
 // execDockerCommand executes a command inside an existing Docker container
-func execDockerCommand(containerName string, command []string) error {
+func execDockerCommand(containerName string, command []string, opts container.ExecOptions) error {
 	// Only use -t flag if stdin is a terminal (interactive mode)
 	// This prevents "the input device is not a TTY" error on Windows
-	var args []string
-	if term.IsTerminal(int(os.Stdin.Fd())) {
-		args = []string{"exec", "-it", containerName}
-	} else {
-		args = []string{"exec", "-i", containerName}
+	opts = opts.ForStdin()
+	cmd, cleanup, err := opts.Command("docker", containerName, command)
+	if err != nil {
+		return err
 	}
-	args = append(args, command...)
+	defer cleanup()
+	args := cmd.Args[1:]
 
-	cmd := exec.Command("docker", args...)
-	cmd.Stdin = os.Stdin
+	logging.Debug("exec in container", "runtime", "docker", "args", args)
+	if opts.Interactive {
+		cmd.Stdin = os.Stdin
+	}
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	return cmd.Run()
+	err = cmd.Run()
+	if err != nil {
+		logging.Capture(zerolog.ErrorLevel, "container exec failed", "runtime", "docker", "args", args, "exit_code", cmd.ProcessState.ExitCode(), "error", err)
+	}
+	return err
 }
 
 // selectRuntime returns "podman" if installed, otherwise "docker".
@@ -1407,15 +1729,17 @@ func runPassthrough(runtime string, args ...string) int {
 	if debugMode {
 		fmt.Fprintf(os.Stderr, "🔍 DEBUG %s args: %v\n", runtime, args)
 	}
+	logging.Debug("runtime command", "runtime", runtime, "args", args)
 	cmd := exec.Command(runtime, args...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
+			logging.Debug("runtime command failed", "runtime", runtime, "args", args, "exit_code", exitErr.ExitCode())
 			return exitErr.ExitCode()
 		}
-		fmt.Fprintf(os.Stderr, "❌ %s execution failed: %v\n", runtime, err)
+		logging.Error(runtime+" execution failed", "error", err, "args", args)
 		return 1
 	}
 	return 0
@@ -2252,7 +2576,7 @@ func showCpHelp() {
 }
 
 func showExecHelp() {
-	fmt.Println("Usage: portunix container exec <container-name> <command> [args...]")
+	fmt.Println("Usage: portunix container exec [flags] <container-name> <command> [args...]")
 	fmt.Println()
 	fmt.Println("🔧 EXECUTE COMMAND IN CONTAINER")
 	fmt.Println()
@@ -2269,14 +2593,26 @@ func showExecHelp() {
 	fmt.Println("  <command>          Command to execute")
 	fmt.Println("  [args...]          Optional arguments for the command")
 	fmt.Println()
-	fmt.Println("Options:")
-	fmt.Println("  -h, --help         Show this help message")
+	fmt.Println("Options (before the container name):")
+	fmt.Println("  --env-file FILE      Set the variables of a .env file")
+	fmt.Println("  -e, --env KEY=VALUE  Set a variable; KEY alone forwards the host variable")
+	fmt.Println("  --copy-env PATTERN   Forward host variables matching a glob, e.g. 'AWS_*'")
+	fmt.Println("  -w, --workdir DIR    Working directory inside the container")
+	fmt.Println("  -u, --user USER      User (name or UID[:GID]) running the command")
+	fmt.Println("  -T, --no-tty         Never allocate a pseudo-TTY")
+	fmt.Println("  -d, --detach         Run the command in the background")
+	fmt.Println("  -h, --help           Show this help message")
+	fmt.Println()
+	fmt.Println("Exits with the exit code of the command, or with 125 when the command")
+	fmt.Println("could not be run.")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  portunix container exec my-container bash")
 	fmt.Println("  portunix container exec my-container ls -la /app")
 	fmt.Println("  portunix container exec web-server cat /etc/nginx/nginx.conf")
 	fmt.Println("  portunix container exec python-dev python --version")
+	fmt.Println("  portunix container exec --env-file .env -w /app -u 1000 app npm test")
+	fmt.Println("  echo \"SELECT 1\" | portunix container exec -T db psql -U postgres")
 }
 
 func showListHelp() {
@@ -2329,6 +2665,10 @@ func main() {
 		return
 	}
 
+	// Logging options are inherited from the parent portunix process
+	logging.InitFromEnv("ptx-container")
+	logging.Debug("command started", "args", args)
+
 	// Delegate to handleCommand for all functionality
 	handleCommand(args)
 }
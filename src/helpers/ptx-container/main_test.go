@@ -0,0 +1,34 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRunInContainerArgs(t *testing.T) {
+	opts, err := parseRunInContainerArgs([]string{"python"})
+	if err != nil || opts.image != "ubuntu:22.04" || strings.Join(opts.packages, ",") != "python" {
+		t.Errorf("unexpected defaults %+v %v", opts, err)
+	}
+
+	// Arguments expanded from a template
+	opts, err = parseRunInContainerArgs([]string{"rust-dev", "--image", "rust:1", "--install", "rust", "--workdir", "/src", "-e", "RUST_LOG=debug"})
+	if err != nil || opts.image != "rust:1" || strings.Join(opts.packages, ",") != "rust" ||
+		strings.Join(opts.runArgs, " ") != "-w /src -e RUST_LOG=debug" {
+		t.Errorf("template arguments parsed as %+v %v", opts, err)
+	}
+
+	for _, bad := range [][]string{
+		{"python", "--image"},
+		{"python", "--imgae", "debian:bookworm"},
+		{"python", "extra"},
+	} {
+		if _, err := parseRunInContainerArgs(bad); err == nil {
+			t.Errorf("expected an error for %v", bad)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PreflightIssue is a problem found by compose-preflight. Commands are the
+// guided fix run by 'compose-preflight --fix'; issues without commands need
+// the manual steps of Fix.
+type PreflightIssue struct {
+	ID       string     `json:"id"`
+	Problem  string     `json:"problem"`
+	Fix      string     `json:"fix"`
+	Commands [][]string `json:"commands,omitempty"`
+	// Blocking issues keep compose from working, the others are warnings
+	Blocking bool `json:"blocking"`
+}
+
+// composeFiles are the default compose files, in the order compose uses
+var composeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// dockerDesktopCLI is the docker CLI of Docker Desktop seen from WSL
+const dockerDesktopCLI = "/mnt/c/Program Files/Docker/Docker/resources/bin/docker.exe"
+
+// userRuntimeDir returns XDG_RUNTIME_DIR, where rootless runtimes keep
+// their sockets
+func userRuntimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir
+	}
+	return fmt.Sprintf("/run/user/%d", os.Getuid())
+}
+
+// dockerRootlessIssue reports a rootless Docker setup the docker CLI does not
+// reach: the daemon is stopped, or the CLI still uses the rootful socket
+func dockerRootlessIssue() (PreflightIssue, bool) {
+	socket := filepath.Join(userRuntimeDir(), "docker.sock")
+	_, socketErr := os.Stat(socket)
+	_, scriptErr := exec.LookPath("dockerd-rootless.sh")
+	unitErr := os.ErrNotExist
+	if home, err := os.UserHomeDir(); err == nil {
+		_, unitErr = os.Stat(filepath.Join(home, ".config", "systemd", "user", "docker.service"))
+	}
+	if socketErr != nil && scriptErr != nil && unitErr != nil {
+		return PreflightIssue{}, false
+	}
+
+	if socketErr != nil {
+		return PreflightIssue{
+			ID:       "docker-rootless-daemon",
+			Problem:  "Docker rootless is set up but its daemon is not running",
+			Fix:      "Start the rootless daemon with: systemctl --user enable --now docker",
+			Commands: [][]string{{"systemctl", "--user", "enable", "--now", "docker"}},
+			Blocking: true,
+		}, true
+	}
+
+	host := "unix://" + socket
+	var commands [][]string
+	if exec.Command("docker", "context", "inspect", "rootless").Run() != nil {
+		commands = append(commands, []string{"docker", "context", "create", "rootless", "--docker", "host=" + host})
+	}
+	commands = append(commands, []string{"docker", "context", "use", "rootless"})
+	return PreflightIssue{
+		ID:       "docker-rootless-context",
+		Problem:  fmt.Sprintf("Docker rootless daemon is running on %s but the docker CLI does not use it", socket),
+		Fix:      fmt.Sprintf("Switch to the rootless context with: docker context use rootless (or export DOCKER_HOST=%s)", host),
+		Commands: commands,
+		Blocking: true,
+	}, true
+}
+
+// isWSL reports whether portunix runs in WSL, from /proc/version and
+// WSL_DISTRO_NAME
+func isWSL(procVersion, distro string) bool {
+	return distro != "" || strings.Contains(strings.ToLower(procVersion), "microsoft")
+}
+
+// wslDockerDesktopIssue reports Docker Desktop installed on Windows while the
+// WSL distro has no working docker, i.e. the WSL integration of the distro
+// is disabled or Docker Desktop is not running
+func wslDockerDesktopIssue() (PreflightIssue, bool) {
+	procVersion, _ := os.ReadFile("/proc/version")
+	distro := os.Getenv("WSL_DISTRO_NAME")
+	if !isWSL(string(procVersion), distro) {
+		return PreflightIssue{}, false
+	}
+	if _, err := os.Stat(dockerDesktopCLI); err != nil {
+		if _, err := exec.LookPath("docker.exe"); err != nil {
+			return PreflightIssue{}, false
+		}
+	}
+	if distro == "" {
+		distro = "this"
+	}
+	return PreflightIssue{
+		ID:      "wsl-docker-desktop-integration",
+		Problem: fmt.Sprintf("Docker Desktop is installed on Windows but docker does not work in the %s WSL distro", distro),
+		Fix: fmt.Sprintf("Start Docker Desktop, open Settings → Resources → WSL integration, enable %s, "+
+			"click 'Apply & restart' and run compose-preflight again", distro),
+		Commands: [][]string{{"powershell.exe", "-NoProfile", "-Command",
+			"Start-Process 'C:\\Program Files\\Docker\\Docker\\Docker Desktop.exe'"}},
+		Blocking: true,
+	}, true
+}
+
+// selinuxEnforcing reports whether SELinux is enforcing
+func selinuxEnforcing() bool {
+	if data, err := os.ReadFile("/sys/fs/selinux/enforce"); err == nil {
+		return strings.TrimSpace(string(data)) == "1"
+	}
+	out, err := exec.Command("getenforce").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "Enforcing"
+}
+
+// bindMount is a host directory or file mounted by a compose service
+type bindMount struct {
+	Service string
+	Source  string
+	// Labeled is set when the mount has the :z or :Z SELinux option
+	Labeled bool
+}
+
+// composeBindMounts returns the bind mounts of the services of a compose
+// file; relative sources are resolved against dir
+func composeBindMounts(data []byte, dir string) ([]bindMount, error) {
+	var file struct {
+		Services map[string]struct {
+			Volumes []yaml.Node `yaml:"volumes"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	var mounts []bindMount
+	for service, s := range file.Services {
+		for _, node := range s.Volumes {
+			var m bindMount
+			switch node.Kind {
+			case yaml.ScalarNode:
+				// source:target[:options]; named volumes have no path
+				parts := strings.Split(node.Value, ":")
+				if len(parts) < 2 || !isHostPath(parts[0]) {
+					continue
+				}
+				m.Source = parts[0]
+				if len(parts) > 2 {
+					for _, opt := range strings.Split(parts[2], ",") {
+						m.Labeled = m.Labeled || opt == "z" || opt == "Z"
+					}
+				}
+			case yaml.MappingNode:
+				var long struct {
+					Type   string `yaml:"type"`
+					Source string `yaml:"source"`
+					Bind   struct {
+						SELinux string `yaml:"selinux"`
+					} `yaml:"bind"`
+				}
+				if err := node.Decode(&long); err != nil {
+					return nil, err
+				}
+				if long.Type != "bind" || long.Source == "" {
+					continue
+				}
+				m.Source = long.Source
+				m.Labeled = long.Bind.SELinux == "z" || long.Bind.SELinux == "Z"
+			default:
+				continue
+			}
+			m.Service = service
+			if strings.HasPrefix(m.Source, "~") {
+				if home, err := os.UserHomeDir(); err == nil {
+					m.Source = filepath.Join(home, m.Source[1:])
+				}
+			}
+			if !filepath.IsAbs(m.Source) {
+				m.Source = filepath.Join(dir, m.Source)
+			}
+			mounts = append(mounts, m)
+		}
+	}
+	return mounts, nil
+}
+
+// isHostPath reports whether the source of a short volume syntax is a path
+// rather than the name of a volume
+func isHostPath(source string) bool {
+	return strings.HasPrefix(source, "/") || strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~")
+}
+
+// findComposeFile returns file, or the default compose file of the current
+// directory; "" when there is none
+func findComposeFile(file string) string {
+	if file != "" {
+		return file
+	}
+	for _, name := range composeFiles {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// selinuxIssues reports bind mounts of the compose file that SELinux keeps
+// the containers from reading: mounts without :z or :Z of paths not labeled
+// container_file_t. Runtime sockets and system paths are never relabeled.
+func selinuxIssues(composeFile string) ([]PreflightIssue, error) {
+	if composeFile == "" || !selinuxEnforcing() {
+		return nil, nil
+	}
+	data, err := os.ReadFile(composeFile)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := filepath.Abs(filepath.Dir(composeFile))
+	if err != nil {
+		return nil, err
+	}
+	mounts, err := composeBindMounts(data, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", composeFile, err)
+	}
+
+	var issues []PreflightIssue
+	seen := map[string]bool{}
+	for _, m := range mounts {
+		if m.Labeled || seen[m.Source] || isSystemPath(m.Source) {
+			continue
+		}
+		seen[m.Source] = true
+		if _, err := os.Stat(m.Source); err != nil {
+			continue
+		}
+		if out, err := exec.Command("stat", "-c", "%C", m.Source).Output(); err == nil && strings.Contains(string(out), "container_file_t") {
+			continue
+		}
+		issues = append(issues, PreflightIssue{
+			ID:       "selinux-label",
+			Problem:  fmt.Sprintf("SELinux is enforcing and service %s mounts %s without a :z or :Z label, so the container cannot access it", m.Service, m.Source),
+			Fix:      fmt.Sprintf("Add :z (shared) or :Z (private) to the volume in %s, or relabel with: chcon -R -t container_file_t %s", composeFile, m.Source),
+			Commands: [][]string{{"chcon", "-R", "-t", "container_file_t", m.Source}},
+		})
+	}
+	return issues, nil
+}
+
+// isSystemPath reports paths that must keep their SELinux label
+func isSystemPath(path string) bool {
+	for _, dir := range []string{"/run", "/var/run", "/dev", "/proc", "/sys", "/etc", "/usr"} {
+		if path == dir || strings.HasPrefix(path, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// fixPreflightIssues prints the fix of each issue and runs its commands,
+// asking first unless yes is set. It reports whether any fix was run.
+func fixPreflightIssues(issues []PreflightIssue, yes bool, in io.Reader, out io.Writer) bool {
+	reader := bufio.NewReader(in)
+	fixed := false
+	for _, issue := range issues {
+		fmt.Fprintf(out, "\n🔧 %s\n", issue.Problem)
+		fmt.Fprintf(out, "   Fix: %s\n", issue.Fix)
+		if len(issue.Commands) == 0 {
+			continue
+		}
+		for _, c := range issue.Commands {
+			fmt.Fprintf(out, "   $ %s\n", strings.Join(c, " "))
+		}
+		if !yes {
+			fmt.Fprint(out, "   Run these commands? [y/N] ")
+			answer, _ := reader.ReadString('\n')
+			if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+				fmt.Fprintln(out, "   Skipped")
+				continue
+			}
+		}
+		for _, c := range issue.Commands {
+			cmd := exec.Command(c[0], c[1:]...)
+			cmd.Stdin = os.Stdin
+			cmd.Stdout = out
+			cmd.Stderr = out
+			if err := cmd.Run(); err != nil {
+				fmt.Fprintf(out, "   ❌ %s failed: %v\n", c[0], err)
+				break
+			}
+		}
+		fixed = true
+	}
+	return fixed
+}
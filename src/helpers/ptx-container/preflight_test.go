@@ -0,0 +1,98 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestIsWSL(t *testing.T) {
+	wsl := "Linux version 5.15.153.1-microsoft-standard-WSL2 (root@1f3c) (gcc) #1 SMP"
+	if !isWSL(wsl, "") {
+		t.Error("WSL kernel not detected")
+	}
+	if !isWSL("", "Ubuntu") {
+		t.Error("WSL_DISTRO_NAME not detected")
+	}
+	if isWSL("Linux version 6.8.0-45-generic (buildd@lcy02)", "") {
+		t.Error("plain Linux detected as WSL")
+	}
+}
+
+func TestComposeBindMounts(t *testing.T) {
+	data := []byte(`
+services:
+  web:
+    volumes:
+      - ./html:/usr/share/nginx/html:ro
+      - /srv/certs:/certs:ro,Z
+      - cache:/var/cache
+  db:
+    volumes:
+      - type: bind
+        source: ./data
+        target: /var/lib/postgresql/data
+        bind:
+          selinux: z
+      - type: bind
+        source: /srv/backup
+        target: /backup
+      - type: volume
+        source: pgdata
+        target: /pg
+volumes:
+  cache:
+  pgdata:
+`)
+	mounts, err := composeBindMounts(data, "/project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range mounts {
+		got = append(got, m.Service+" "+m.Source+" "+map[bool]string{true: "labeled", false: "unlabeled"}[m.Labeled])
+	}
+	sort.Strings(got)
+	want := []string{
+		"db /project/data labeled",
+		"db /srv/backup unlabeled",
+		"web /project/html unlabeled",
+		"web /srv/certs labeled",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got mounts\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if _, err := composeBindMounts([]byte("services: ["), "/project"); err == nil {
+		t.Error("invalid compose file should fail")
+	}
+}
+
+func TestFixPreflightIssues(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runs true")
+	}
+	issues := []PreflightIssue{
+		{ID: "manual", Problem: "manual problem", Fix: "do it by hand"},
+		{ID: "guided", Problem: "guided problem", Fix: "run true", Commands: [][]string{{"true"}}},
+	}
+	var out bytes.Buffer
+	if fixPreflightIssues(issues, false, strings.NewReader("n\n"), &out) {
+		t.Error("declined fix should not run")
+	}
+	if !strings.Contains(out.String(), "do it by hand") || !strings.Contains(out.String(), "$ true") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+	if !fixPreflightIssues(issues, false, strings.NewReader("y\n"), &out) {
+		t.Error("confirmed fix should run")
+	}
+	if !fixPreflightIssues(issues, true, strings.NewReader(""), &out) {
+		t.Error("--yes should run the fix without asking")
+	}
+}
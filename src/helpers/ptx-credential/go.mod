@@ -1,15 +1,21 @@
 module portunix.ai/ptx-credential
 
-go 1.23.0
+go 1.24.0
+
+// Use parent module for shared packages
+replace portunix.ai/portunix => ../../..
+
+replace portunix.ai/app => ../../app
 
 require (
-	github.com/spf13/cobra v1.8.1
-	golang.org/x/crypto v0.31.0
-	golang.org/x/term v0.27.0
+	github.com/spf13/cobra v1.10.1
+	golang.org/x/crypto v0.47.0
+	golang.org/x/term v0.39.0
+	portunix.ai/app v0.0.0
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/sys v0.28.0 // indirect
+	github.com/spf13/pflag v1.0.9 // indirect
+	golang.org/x/sys v0.40.0 // indirect
 )
@@ -10,6 +10,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"portunix.ai/app/fileutil"
 )
 
 const (
@@ -178,7 +180,7 @@ func (s *Storage) Save(store *CredentialStore) error {
 	}
 
 	// Write to file with restricted permissions
-	if err := os.WriteFile(storePath, []byte(encrypted), 0600); err != nil {
+	if err := fileutil.WriteFile(storePath, []byte(encrypted), 0600); err != nil {
 		return fmt.Errorf("failed to write store file: %w", err)
 	}
 
@@ -458,7 +460,7 @@ func (s *Storage) SetRawM365Data(jsonData string) error {
 	storePath := s.getStorePath()
 
 	// Write to file with restricted permissions
-	if err := os.WriteFile(storePath, []byte(encrypted), 0600); err != nil {
+	if err := fileutil.WriteFile(storePath, []byte(encrypted), 0600); err != nil {
 		return fmt.Errorf("failed to write M365 token file: %w", err)
 	}
 
@@ -0,0 +1,122 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// readyTimeout bounds the wait for a new database to accept connections;
+// first start of MySQL initializes its data directory and is slow
+const readyTimeout = 120 * time.Second
+
+// runArgs returns the arguments of '<runtime> run' creating an instance
+func runArgs(engine *Engine, inst *Instance) []string {
+	args := []string{
+		"run", "-d",
+		"--name", inst.Container(),
+		"--restart", "unless-stopped",
+		"-p", fmt.Sprintf("%d:%d", inst.Port, engine.Port),
+		"-v", inst.Volume() + ":" + engine.DataDir,
+		"--label", "portunix.db=" + inst.Name,
+		"--label", "portunix.db.engine=" + engine.Name,
+	}
+	for _, env := range engine.env(inst) {
+		args = append(args, "-e", env)
+	}
+	return append(args, inst.Image)
+}
+
+// runtimeOutput runs a container runtime command and returns its output
+func runtimeOutput(runtime string, args ...string) (string, error) {
+	cmd := exec.Command(runtime, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s %s: %s", runtime, args[0], msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// containerStatus returns the state of an instance's container: running,
+// exited, ... or "missing" when the container was removed
+func containerStatus(inst *Instance) string {
+	status, err := runtimeOutput(inst.Runtime, "inspect", "--format", "{{.State.Status}}", inst.Container())
+	if err != nil || status == "" {
+		return "missing"
+	}
+	return status
+}
+
+// waitReady polls the engine's readiness command until the database
+// accepts connections
+func waitReady(engine *Engine, inst *Instance, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	args := append([]string{"exec", inst.Container()}, engine.ready(inst)...)
+	for {
+		if err := exec.Command(inst.Runtime, args...).Run(); err == nil {
+			return nil
+		}
+		if containerStatus(inst) != "running" {
+			return fmt.Errorf("container %s stopped; see '%s logs %s'", inst.Container(), inst.Runtime, inst.Container())
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("database not ready after %s", timeout)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// execWithIO runs a command in an instance's container with the given
+// standard input and output
+func execWithIO(inst *Instance, stdin io.Reader, stdout io.Writer, command []string) error {
+	args := []string{"exec"}
+	if stdin != nil {
+		args = append(args, "-i")
+	}
+	args = append(args, inst.Container())
+	cmd := exec.Command(inst.Runtime, append(args, command...)...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %s", command[0], msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// execInteractive runs a command in an instance's container attached to
+// the terminal
+func execInteractive(inst *Instance, command []string) error {
+	cmd := exec.Command(inst.Runtime, append([]string{"exec", "-it", inst.Container()}, command...)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// parsePort parses a --port value
+func parsePort(value string) (int, error) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", value)
+	}
+	return port, nil
+}
@@ -0,0 +1,161 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Engine describes how a database runs in a container
+type Engine struct {
+	Name           string
+	Image          string
+	DefaultVersion string
+	// Port is the port the database listens on inside the container
+	Port int
+	// DataDir is the volume mount point holding the database files
+	DataDir string
+	// SnapshotExt is the file extension of snapshots
+	SnapshotExt string
+	// env returns the container environment initializing the database
+	env func(inst *Instance) []string
+	// ready is run in the container to test whether the database accepts
+	// connections
+	ready func(inst *Instance) []string
+	// dump is run in the container and writes a snapshot to stdout
+	dump func(inst *Instance) []string
+	// restore is run in the container and reads a snapshot from stdin
+	restore func(inst *Instance) []string
+	// shell is the interactive client run by 'db shell'
+	shell  func(inst *Instance) []string
+	scheme string
+	query  string
+}
+
+// engines lists the supported databases by name
+var engines = map[string]*Engine{
+	"postgres": {
+		Name:           "postgres",
+		Image:          "postgres",
+		DefaultVersion: "16",
+		Port:           5432,
+		DataDir:        "/var/lib/postgresql/data",
+		SnapshotExt:    ".dump",
+		env: func(inst *Instance) []string {
+			return []string{"POSTGRES_USER=" + inst.User, "POSTGRES_PASSWORD=" + inst.Password, "POSTGRES_DB=" + inst.Database}
+		},
+		ready: func(inst *Instance) []string {
+			return []string{"pg_isready", "-h", "localhost", "-U", inst.User, "-d", inst.Database}
+		},
+		dump: func(inst *Instance) []string {
+			return []string{"pg_dump", "-U", inst.User, "-d", inst.Database, "--format=custom"}
+		},
+		restore: func(inst *Instance) []string {
+			return []string{"pg_restore", "-U", inst.User, "-d", inst.Database, "--clean", "--if-exists", "--no-owner"}
+		},
+		shell: func(inst *Instance) []string {
+			return []string{"psql", "-U", inst.User, "-d", inst.Database}
+		},
+		scheme: "postgresql",
+	},
+	"mysql": {
+		Name:           "mysql",
+		Image:          "mysql",
+		DefaultVersion: "8.4",
+		Port:           3306,
+		DataDir:        "/var/lib/mysql",
+		SnapshotExt:    ".sql",
+		env: func(inst *Instance) []string {
+			return []string{"MYSQL_ROOT_PASSWORD=" + inst.Password, "MYSQL_DATABASE=" + inst.Database,
+				"MYSQL_USER=" + inst.User, "MYSQL_PASSWORD=" + inst.Password}
+		},
+		// The password is read from the container environment so that it
+		// does not show in the process list
+		ready: func(inst *Instance) []string {
+			return []string{"sh", "-c", `mysqladmin ping -h 127.0.0.1 -uroot -p"$MYSQL_ROOT_PASSWORD" --silent`}
+		},
+		dump: func(inst *Instance) []string {
+			return []string{"sh", "-c", `exec mysqldump -uroot -p"$MYSQL_ROOT_PASSWORD" --single-transaction --routines --triggers --databases "$MYSQL_DATABASE"`}
+		},
+		restore: func(inst *Instance) []string {
+			return []string{"sh", "-c", `exec mysql -uroot -p"$MYSQL_ROOT_PASSWORD"`}
+		},
+		shell: func(inst *Instance) []string {
+			return []string{"sh", "-c", `exec mysql -u"$MYSQL_USER" -p"$MYSQL_PASSWORD" "$MYSQL_DATABASE"`}
+		},
+		scheme: "mysql",
+	},
+	"mongo": {
+		Name:           "mongo",
+		Image:          "mongo",
+		DefaultVersion: "7",
+		Port:           27017,
+		DataDir:        "/data/db",
+		SnapshotExt:    ".archive.gz",
+		env: func(inst *Instance) []string {
+			return []string{"MONGO_INITDB_ROOT_USERNAME=" + inst.User, "MONGO_INITDB_ROOT_PASSWORD=" + inst.Password,
+				"MONGO_INITDB_DATABASE=" + inst.Database}
+		},
+		ready: func(inst *Instance) []string {
+			return []string{"mongosh", "--quiet", "--eval", "db.runCommand({ping: 1}).ok"}
+		},
+		dump: func(inst *Instance) []string {
+			return []string{"sh", "-c", `exec mongodump --archive --gzip -u "$MONGO_INITDB_ROOT_USERNAME" -p "$MONGO_INITDB_ROOT_PASSWORD" --authenticationDatabase admin`}
+		},
+		restore: func(inst *Instance) []string {
+			return []string{"sh", "-c", `exec mongorestore --archive --gzip --drop -u "$MONGO_INITDB_ROOT_USERNAME" -p "$MONGO_INITDB_ROOT_PASSWORD" --authenticationDatabase admin`}
+		},
+		shell: func(inst *Instance) []string {
+			return []string{"sh", "-c", `exec mongosh -u "$MONGO_INITDB_ROOT_USERNAME" -p "$MONGO_INITDB_ROOT_PASSWORD" --authenticationDatabase admin "$MONGO_INITDB_DATABASE"`}
+		},
+		scheme: "mongodb",
+		query:  "authSource=admin",
+	},
+}
+
+// engineAliases maps common alternative names to engines
+var engineAliases = map[string]string{
+	"postgresql": "postgres",
+	"pg":         "postgres",
+	"mongodb":    "mongo",
+}
+
+// lookupEngine returns the engine of a name or alias
+func lookupEngine(name string) (*Engine, error) {
+	name = strings.ToLower(name)
+	if alias, ok := engineAliases[name]; ok {
+		name = alias
+	}
+	if engine, ok := engines[name]; ok {
+		return engine, nil
+	}
+	return nil, fmt.Errorf("unsupported database %q (use %s)", name, strings.Join(engineNames(), ", "))
+}
+
+// engineNames returns the supported engines, sorted
+func engineNames() []string {
+	var names []string
+	for name := range engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConnectionURL returns the connection string of an instance as reached
+// from the host
+func (e *Engine) ConnectionURL(inst *Instance) string {
+	u := url.URL{
+		Scheme:   e.scheme,
+		User:     url.UserPassword(inst.User, inst.Password),
+		Host:     fmt.Sprintf("localhost:%d", inst.Port),
+		Path:     "/" + inst.Database,
+		RawQuery: e.query,
+	}
+	return u.String()
+}
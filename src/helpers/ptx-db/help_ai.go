@@ -0,0 +1,63 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import "portunix.ai/app/aihelp"
+
+// showHelpAI prints machine-readable help for --help-ai
+func showHelpAI() {
+	name := aihelp.Argument{Name: "name", Type: "string", Required: true, Description: "Database instance name"}
+	jsonFlag := aihelp.Flag{Name: "json", Type: "boolean", Description: "Output JSON"}
+
+	aihelp.Help{
+		Tool:        "ptx-db",
+		Version:     version,
+		Description: "Local PostgreSQL, MySQL and MongoDB containers with persistent volumes, connection strings and snapshots",
+		Commands: []aihelp.Command{
+			{
+				Name:        "db create",
+				Description: "Start a database container with a persistent volume and generated credentials",
+				Arguments:   []aihelp.Argument{{Name: "engine", Type: "string", Required: true, Choices: engineNames(), Description: "Database engine"}},
+				Flags: []aihelp.Flag{
+					{Name: "name", Type: "string", Description: "Instance name (default: engine name)"},
+					{Name: "version", Type: "string", Description: "Image version (postgres 16, mysql 8.4, mongo 7 by default)"},
+					{Name: "port", Type: "integer", Description: "Host port (default: first free port from the engine's port)"},
+					{Name: "user", Type: "string", Default: "portunix", Description: "Database user"},
+					{Name: "password", Type: "string", Description: "Password (default: generated)"},
+					{Name: "database", Type: "string", Description: "Database name (default: instance name)"},
+					{Name: "no-wait", Type: "boolean", Description: "Do not wait until the database accepts connections"},
+				},
+				Examples: []string{"portunix db create postgres --version 16 --port 5433", "portunix db create mongo --name events"},
+			},
+			{Name: "db list", Aliases: []string{"ls"}, Description: "List databases and their container state", Flags: []aihelp.Flag{jsonFlag}},
+			{Name: "db info", Description: "Show a database with its credentials and connection string", Arguments: []aihelp.Argument{name}, Flags: []aihelp.Flag{jsonFlag}},
+			{Name: "db url", Description: "Print the connection string", Arguments: []aihelp.Argument{name}, Examples: []string{"export DATABASE_URL=$(portunix db url postgres)"}},
+			{Name: "db start", Description: "Start a stopped database", Arguments: []aihelp.Argument{name}},
+			{Name: "db stop", Description: "Stop a database", Arguments: []aihelp.Argument{name}},
+			{Name: "db shell", Description: "Open the database client (psql, mysql, mongosh)", Arguments: []aihelp.Argument{name}},
+			{
+				Name:        "db remove",
+				Aliases:     []string{"rm"},
+				Description: "Remove the container; the data volume is kept unless --volume is given",
+				Arguments:   []aihelp.Argument{name},
+				Flags:       []aihelp.Flag{{Name: "volume", Type: "boolean", Description: "Also remove the data volume"}},
+			},
+			{
+				Name:        "db snapshot",
+				Description: "Dump the database to ~/.portunix/db/snapshots/<name>",
+				Arguments:   []aihelp.Argument{name},
+				Flags:       []aihelp.Flag{{Name: "tag", Type: "string", Description: "Label appended to the snapshot ID"}},
+				Examples:    []string{"portunix db snapshot postgres --tag before-migration"},
+			},
+			{Name: "db snapshots", Description: "List the snapshots of a database", Arguments: []aihelp.Argument{name}, Flags: []aihelp.Flag{jsonFlag}},
+			{
+				Name:        "db restore",
+				Description: "Restore a snapshot into a database",
+				Arguments:   []aihelp.Argument{name, {Name: "snapshot", Type: "string", Required: true, Description: "Snapshot ID, unique ID prefix or file path"}},
+				Examples:    []string{"portunix db restore postgres 20260101-120000-before-migration"},
+			},
+		},
+	}.Print()
+}
@@ -0,0 +1,171 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"portunix.ai/app/fileutil"
+)
+
+// Instance is a database container created by 'portunix db create'
+type Instance struct {
+	Name     string    `json:"name"`
+	Engine   string    `json:"engine"`
+	Version  string    `json:"version"`
+	Image    string    `json:"image"`
+	Port     int       `json:"port"`
+	User     string    `json:"user"`
+	Password string    `json:"password"`
+	Database string    `json:"database"`
+	Runtime  string    `json:"runtime"`
+	Created  time.Time `json:"created"`
+}
+
+// Container returns the container name of the instance
+func (i *Instance) Container() string {
+	return "portunix-db-" + i.Name
+}
+
+// Volume returns the named volume holding the database files, which
+// outlives the container
+func (i *Instance) Volume() string {
+	return "portunix-db-" + i.Name + "-data"
+}
+
+// dbDir returns ~/.portunix/db, holding the instance list and snapshots
+func dbDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".portunix", "db"), nil
+}
+
+// instancesFile returns the file recording the created instances. It
+// holds passwords and is readable by the owner only.
+func instancesFile() (string, error) {
+	dir, err := dbDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "instances.json"), nil
+}
+
+// loadInstances reads the recorded instances by name
+func loadInstances() (map[string]*Instance, error) {
+	path, err := instancesFile()
+	if err != nil {
+		return nil, err
+	}
+	instances := make(map[string]*Instance)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return instances, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []*Instance
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, inst := range list {
+		instances[inst.Name] = inst
+	}
+	return instances, nil
+}
+
+// saveInstances writes the recorded instances, sorted by name
+func saveInstances(instances map[string]*Instance) error {
+	path, err := instancesFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(sortedInstances(instances), "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFile(path, data, 0600)
+}
+
+// sortedInstances returns the instances sorted by name
+func sortedInstances(instances map[string]*Instance) []*Instance {
+	list := make([]*Instance, 0, len(instances))
+	for _, inst := range instances {
+		list = append(list, inst)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// getInstance returns a recorded instance
+func getInstance(name string) (*Instance, error) {
+	instances, err := loadInstances()
+	if err != nil {
+		return nil, err
+	}
+	inst, ok := instances[name]
+	if !ok {
+		return nil, fmt.Errorf("database %q not found (see 'portunix db list')", name)
+	}
+	return inst, nil
+}
+
+var instanceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// validateInstanceName checks a name usable in container and volume names
+func validateInstanceName(name string) error {
+	if !instanceNamePattern.MatchString(name) || len(name) > 48 {
+		return fmt.Errorf("invalid name %q (lowercase letters, digits, '-' and '_')", name)
+	}
+	return nil
+}
+
+// generatePassword returns a random password safe to use in URLs
+func generatePassword() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// freePort returns the first port from start that is neither used by a
+// recorded instance nor bound on the host
+func freePort(start int, instances map[string]*Instance) (int, error) {
+	used := make(map[int]bool)
+	for _, inst := range instances {
+		used[inst.Port] = true
+	}
+	for port := start; port < start+100 && port <= 65535; port++ {
+		if !used[port] && portAvailable(port) {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no free port found from %d (use --port)", start)
+}
+
+// portAvailable reports whether a TCP port can be bound on the host
+func portAvailable(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
@@ -0,0 +1,529 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"portunix.ai/app/container"
+	"portunix.ai/app/mirror"
+)
+
+var version = "dev"
+
+// rootCmd represents the base command for ptx-db
+var rootCmd = &cobra.Command{
+	Use:   "ptx-db",
+	Short: "Portunix Local Database Helper",
+	Long: `ptx-db is a helper binary for Portunix that provisions local databases.
+It runs PostgreSQL, MySQL and MongoDB in containers of the configured runtime
+(Docker or Podman) with persistent volumes and generated credentials.
+
+This binary is typically invoked by the main portunix dispatcher and should not be used directly.
+
+Supported features:
+- Database containers with persistent named volumes
+- Generated credentials and connection strings
+- Snapshots and restores using the engine's dump tools
+- Listing, starting and stopping instances`,
+	Version:            version,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		handleCommand(args)
+	},
+}
+
+// handleCommand dispatches the "db" command routed to this helper by the
+// parent portunix binary (see src/dispatcher/dispatcher.go), plus the discovery
+// meta-flags --version, --description, and --list-commands used by the
+// dispatcher. args arrive without the binary name prefix.
+func handleCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Println("No command specified")
+		return
+	}
+
+	command := args[0]
+	subArgs := args[1:]
+
+	switch command {
+	case "db":
+		if len(subArgs) == 0 {
+			showDBHelp()
+		} else {
+			handleDBCommand(subArgs)
+		}
+	case "--version":
+		fmt.Printf("ptx-db version %s\n", version)
+	case "--description":
+		fmt.Println("Portunix Local Database Helper")
+	case "--list-commands":
+		fmt.Println("db")
+	case "--help-ai":
+		showHelpAI()
+	default:
+		fmt.Printf("Unknown command: %s\n", command)
+		fmt.Println("Supported commands: db")
+	}
+}
+
+func showDBHelp() {
+	fmt.Println("Usage: portunix db [subcommand]")
+	fmt.Println()
+	fmt.Println("Local Database Commands:")
+	fmt.Println()
+	fmt.Println("Instances:")
+	fmt.Println("  create <postgres|mysql|mongo> [--name N] [--version V] [--port P]")
+	fmt.Println("         [--user U] [--password P] [--database D] - Start a database container")
+	fmt.Println("  list [--json]                - List databases and their state")
+	fmt.Println("  info <name> [--json]         - Show a database and its connection string")
+	fmt.Println("  url <name>                   - Print the connection string")
+	fmt.Println("  start <name>                 - Start a stopped database")
+	fmt.Println("  stop <name>                  - Stop a database")
+	fmt.Println("  shell <name>                 - Open the database client")
+	fmt.Println("  remove <name> [--volume]     - Remove the container (and its data with --volume)")
+	fmt.Println()
+	fmt.Println("Snapshots:")
+	fmt.Println("  snapshot <name> [--tag T]    - Dump the database to ~/.portunix/db/snapshots")
+	fmt.Println("  snapshots <name> [--json]    - List snapshots of a database")
+	fmt.Println("  restore <name> <snapshot>    - Restore a snapshot (ID, ID prefix or file)")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix db create postgres --version 16 --port 5433")
+	fmt.Println("  export DATABASE_URL=$(portunix db url postgres)")
+	fmt.Println("  portunix db snapshot postgres --tag before-migration")
+	fmt.Println("  portunix db restore postgres before-migration")
+}
+
+func handleDBCommand(args []string) {
+	subcommand := args[0]
+	subArgs := args[1:]
+
+	var err error
+	switch subcommand {
+	case "create":
+		err = handleCreate(subArgs)
+	case "list", "ls":
+		err = handleList(subArgs)
+	case "info":
+		err = handleInfo(subArgs)
+	case "url":
+		err = handleURL(subArgs)
+	case "start", "stop":
+		err = handleStartStop(subcommand, subArgs)
+	case "shell":
+		err = handleShell(subArgs)
+	case "remove", "rm":
+		err = handleRemove(subArgs)
+	case "snapshot":
+		err = handleSnapshot(subArgs)
+	case "snapshots":
+		err = handleSnapshots(subArgs)
+	case "restore":
+		err = handleRestore(subArgs)
+	case "--help", "-h":
+		showDBHelp()
+	case "--help-ai":
+		showHelpAI()
+	default:
+		fmt.Printf("Unknown db subcommand: %s\n", subcommand)
+		fmt.Println("Run 'portunix db --help' for available commands")
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseArgs splits positional arguments from --flag value options;
+// boolean lists the flags taking no value
+func parseArgs(args []string, boolean ...string) ([]string, map[string]string, error) {
+	var positional []string
+	flags := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+		name := strings.TrimPrefix(arg, "--")
+		if name, value, found := strings.Cut(name, "="); found {
+			flags[name] = value
+			continue
+		}
+		isBool := false
+		for _, b := range boolean {
+			isBool = isBool || b == name
+		}
+		if isBool {
+			flags[name] = "true"
+			continue
+		}
+		if i+1 >= len(args) {
+			return nil, nil, fmt.Errorf("%s requires a value", arg)
+		}
+		flags[name] = args[i+1]
+		i++
+	}
+	return positional, flags, nil
+}
+
+// instanceArg parses the arguments of commands taking an instance name
+func instanceArg(args []string, usage string, boolean ...string) (*Instance, *Engine, map[string]string, error) {
+	positional, flags, err := parseArgs(args, boolean...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(positional) != 1 {
+		return nil, nil, nil, fmt.Errorf("usage: portunix db %s", usage)
+	}
+	inst, err := getInstance(positional[0])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	engine, err := lookupEngine(inst.Engine)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return inst, engine, flags, nil
+}
+
+func handleCreate(args []string) error {
+	positional, flags, err := parseArgs(args, "no-wait")
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: portunix db create <%s> [--name N] [--version V] [--port P]", strings.Join(engineNames(), "|"))
+	}
+	engine, err := lookupEngine(positional[0])
+	if err != nil {
+		return err
+	}
+	runtime, err := container.GetSelectedRuntime()
+	if err != nil {
+		return fmt.Errorf("%v (see 'portunix container info')", err)
+	}
+
+	instances, err := loadInstances()
+	if err != nil {
+		return err
+	}
+	inst := &Instance{
+		Name:     flags["name"],
+		Engine:   engine.Name,
+		Version:  flags["version"],
+		User:     flags["user"],
+		Password: flags["password"],
+		Database: flags["database"],
+		Runtime:  runtime,
+		Created:  time.Now(),
+	}
+	if inst.Name == "" {
+		inst.Name = engine.Name
+	}
+	if err := validateInstanceName(inst.Name); err != nil {
+		return err
+	}
+	if _, exists := instances[inst.Name]; exists {
+		return fmt.Errorf("database %q already exists (use --name for another instance)", inst.Name)
+	}
+	if inst.Version == "" {
+		inst.Version = engine.DefaultVersion
+	}
+	if inst.User == "" {
+		inst.User = "portunix"
+	}
+	if engine.Name == "mysql" && inst.User == "root" {
+		return fmt.Errorf("MySQL creates root itself; choose another --user")
+	}
+	if inst.Database == "" {
+		inst.Database = strings.ReplaceAll(inst.Name, "-", "_")
+	}
+	if inst.Password == "" {
+		if inst.Password, err = generatePassword(); err != nil {
+			return err
+		}
+	}
+	if value, ok := flags["port"]; ok {
+		if inst.Port, err = parsePort(value); err != nil {
+			return err
+		}
+		if !portAvailable(inst.Port) {
+			return fmt.Errorf("port %d is already in use", inst.Port)
+		}
+	} else if inst.Port, err = freePort(engine.Port, instances); err != nil {
+		return err
+	}
+	if inst.Image, err = mirror.RewriteImage(engine.Image + ":" + inst.Version); err != nil {
+		return err
+	}
+	if _, err := runtimeOutput(runtime, "inspect", "--format", "{{.Name}}", inst.Container()); err == nil {
+		return fmt.Errorf("container %s already exists; remove it with '%s rm -f %s'", inst.Container(), runtime, inst.Container())
+	}
+
+	fmt.Printf("🗄️  Creating %s %s database '%s'\n", engine.Name, inst.Version, inst.Name)
+	fmt.Printf("   Image:  %s (%s)\n", inst.Image, runtime)
+	fmt.Printf("   Port:   %d\n", inst.Port)
+	fmt.Printf("   Volume: %s\n", inst.Volume())
+	if _, err := runtimeOutput(runtime, runArgs(engine, inst)...); err != nil {
+		return fmt.Errorf("failed to start container: %w", err)
+	}
+	instances[inst.Name] = inst
+	if err := saveInstances(instances); err != nil {
+		return err
+	}
+
+	if flags["no-wait"] != "true" {
+		fmt.Print("⏳ Waiting for the database to accept connections...")
+		if err := waitReady(engine, inst, readyTimeout); err != nil {
+			fmt.Println()
+			return err
+		}
+		fmt.Println(" ready")
+	}
+	fmt.Printf("✅ Database '%s' created\n", inst.Name)
+	fmt.Printf("   %s\n", engine.ConnectionURL(inst))
+	return nil
+}
+
+// instanceView is an instance as shown by list and info
+type instanceView struct {
+	*Instance
+	Status string `json:"status"`
+	URL    string `json:"url"`
+}
+
+func newInstanceView(inst *Instance) instanceView {
+	view := instanceView{Instance: inst, Status: containerStatus(inst)}
+	if engine, err := lookupEngine(inst.Engine); err == nil {
+		view.URL = engine.ConnectionURL(inst)
+	}
+	return view
+}
+
+func handleList(args []string) error {
+	_, flags, err := parseArgs(args, "json")
+	if err != nil {
+		return err
+	}
+	instances, err := loadInstances()
+	if err != nil {
+		return err
+	}
+	views := []instanceView{}
+	for _, inst := range sortedInstances(instances) {
+		views = append(views, newInstanceView(inst))
+	}
+
+	if flags["json"] == "true" {
+		data, _ := json.MarshalIndent(views, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(views) == 0 {
+		fmt.Println("No databases. Create one with: portunix db create postgres")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tENGINE\tVERSION\tPORT\tSTATUS\tCREATED")
+	for _, view := range views {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", view.Name, view.Engine, view.Version, view.Port, view.Status, view.Created.Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
+
+func handleInfo(args []string) error {
+	inst, _, flags, err := instanceArg(args, "info <name> [--json]", "json")
+	if err != nil {
+		return err
+	}
+	view := newInstanceView(inst)
+	if flags["json"] == "true" {
+		data, _ := json.MarshalIndent(view, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Printf("Name:      %s\n", view.Name)
+	fmt.Printf("Engine:    %s %s\n", view.Engine, view.Version)
+	fmt.Printf("Status:    %s\n", view.Status)
+	fmt.Printf("Container: %s (%s)\n", inst.Container(), view.Runtime)
+	fmt.Printf("Image:     %s\n", view.Image)
+	fmt.Printf("Volume:    %s\n", inst.Volume())
+	fmt.Printf("Port:      %d\n", view.Port)
+	fmt.Printf("Database:  %s\n", view.Database)
+	fmt.Printf("User:      %s\n", view.User)
+	fmt.Printf("Password:  %s\n", view.Password)
+	fmt.Printf("URL:       %s\n", view.URL)
+	return nil
+}
+
+func handleURL(args []string) error {
+	inst, engine, _, err := instanceArg(args, "url <name>")
+	if err != nil {
+		return err
+	}
+	fmt.Println(engine.ConnectionURL(inst))
+	return nil
+}
+
+func handleStartStop(action string, args []string) error {
+	inst, engine, _, err := instanceArg(args, action+" <name>")
+	if err != nil {
+		return err
+	}
+	if _, err := runtimeOutput(inst.Runtime, action, inst.Container()); err != nil {
+		return err
+	}
+	if action == "start" {
+		if err := waitReady(engine, inst, readyTimeout); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Database '%s' started on port %d\n", inst.Name, inst.Port)
+	} else {
+		fmt.Printf("✅ Database '%s' stopped\n", inst.Name)
+	}
+	return nil
+}
+
+func handleShell(args []string) error {
+	inst, engine, _, err := instanceArg(args, "shell <name>")
+	if err != nil {
+		return err
+	}
+	return execInteractive(inst, engine.shell(inst))
+}
+
+func handleRemove(args []string) error {
+	inst, _, flags, err := instanceArg(args, "remove <name> [--volume]", "volume")
+	if err != nil {
+		return err
+	}
+	if containerStatus(inst) != "missing" {
+		if _, err := runtimeOutput(inst.Runtime, "rm", "-f", inst.Container()); err != nil {
+			return err
+		}
+	}
+	if flags["volume"] == "true" {
+		if _, err := runtimeOutput(inst.Runtime, "volume", "rm", inst.Volume()); err != nil {
+			return err
+		}
+	}
+
+	instances, err := loadInstances()
+	if err != nil {
+		return err
+	}
+	delete(instances, inst.Name)
+	if err := saveInstances(instances); err != nil {
+		return err
+	}
+	if flags["volume"] == "true" {
+		fmt.Printf("✅ Database '%s' and its data removed\n", inst.Name)
+	} else {
+		fmt.Printf("✅ Database '%s' removed; data kept in volume %s\n", inst.Name, inst.Volume())
+	}
+	return nil
+}
+
+func handleSnapshot(args []string) error {
+	inst, engine, flags, err := instanceArg(args, "snapshot <name> [--tag T]")
+	if err != nil {
+		return err
+	}
+	snapshot, err := createSnapshot(engine, inst, flags["tag"])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Snapshot %s created (%s)\n", snapshot.ID, formatSize(snapshot.Size))
+	fmt.Printf("   %s\n", snapshot.Path)
+	return nil
+}
+
+func handleSnapshots(args []string) error {
+	inst, engine, flags, err := instanceArg(args, "snapshots <name> [--json]", "json")
+	if err != nil {
+		return err
+	}
+	snapshots, err := listSnapshots(engine, inst.Name)
+	if err != nil {
+		return err
+	}
+	if flags["json"] == "true" {
+		if snapshots == nil {
+			snapshots = []Snapshot{}
+		}
+		data, _ := json.MarshalIndent(snapshots, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(snapshots) == 0 {
+		fmt.Printf("No snapshots of '%s'. Create one with: portunix db snapshot %s\n", inst.Name, inst.Name)
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSIZE\tCREATED")
+	for _, snapshot := range snapshots {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", snapshot.ID, formatSize(snapshot.Size), snapshot.Created.Format("2006-01-02 15:04:05"))
+	}
+	return w.Flush()
+}
+
+func handleRestore(args []string) error {
+	positional, _, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("usage: portunix db restore <name> <snapshot>")
+	}
+	inst, engine, _, err := instanceArg(positional[:1], "restore <name> <snapshot>")
+	if err != nil {
+		return err
+	}
+	path, err := findSnapshot(engine, inst.Name, positional[1])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("♻️  Restoring %s into '%s'\n", path, inst.Name)
+	if err := restoreSnapshot(engine, inst, path); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Database '%s' restored\n", inst.Name)
+	return nil
+}
+
+// formatSize formats a byte count for display
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	rootCmd.SetVersionTemplate("ptx-db version {{.Version}}\n")
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
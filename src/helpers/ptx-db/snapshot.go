@@ -0,0 +1,155 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Snapshot is a dump of an instance's database
+type Snapshot struct {
+	ID      string    `json:"id"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	Created time.Time `json:"created"`
+}
+
+// snapshotDir returns the directory holding the snapshots of an instance
+func snapshotDir(name string) (string, error) {
+	dir, err := dbDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "snapshots", name), nil
+}
+
+// snapshotID names a snapshot by its creation time and an optional tag
+func snapshotID(created time.Time, tag string) string {
+	id := created.Format("20060102-150405")
+	if tag != "" {
+		id += "-" + tag
+	}
+	return id
+}
+
+// createSnapshot dumps an instance's database into its snapshot directory
+func createSnapshot(engine *Engine, inst *Instance, tag string) (*Snapshot, error) {
+	if tag != "" {
+		if err := validateInstanceName(tag); err != nil {
+			return nil, fmt.Errorf("invalid tag %q", tag)
+		}
+	}
+	dir, err := snapshotDir(inst.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	created := time.Now()
+	id := snapshotID(created, tag)
+	path := filepath.Join(dir, id+engine.SnapshotExt)
+	// Write to a temporary name so that a failed dump is never listed
+	tmp := path + ".partial"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	err = execWithIO(inst, nil, file, engine.dump(inst))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("snapshot failed: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{ID: id, Path: path, Size: info.Size(), Created: created}, nil
+}
+
+// listSnapshots returns the snapshots of an instance, oldest first
+func listSnapshots(engine *Engine, name string) ([]Snapshot, error) {
+	dir, err := snapshotDir(name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), engine.SnapshotExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, Snapshot{
+			ID:      strings.TrimSuffix(entry.Name(), engine.SnapshotExt),
+			Path:    filepath.Join(dir, entry.Name()),
+			Size:    info.Size(),
+			Created: info.ModTime(),
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].ID < snapshots[j].ID })
+	return snapshots, nil
+}
+
+// findSnapshot resolves a snapshot ID, unique ID prefix or file path
+func findSnapshot(engine *Engine, name, ref string) (string, error) {
+	if info, err := os.Stat(ref); err == nil && !info.IsDir() {
+		return ref, nil
+	}
+	snapshots, err := listSnapshots(engine, name)
+	if err != nil {
+		return "", err
+	}
+	var matches []string
+	for _, snapshot := range snapshots {
+		if snapshot.ID == ref {
+			return snapshot.Path, nil
+		}
+		if strings.HasPrefix(snapshot.ID, ref) {
+			matches = append(matches, snapshot.Path)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("snapshot %q of %s not found (see 'portunix db snapshots %s')", ref, name, name)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("snapshot %q is ambiguous (%d matches)", ref, len(matches))
+}
+
+// restoreSnapshot loads a snapshot file into an instance's database
+func restoreSnapshot(engine *Engine, inst *Instance, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := execWithIO(inst, file, os.Stdout, engine.restore(inst)); err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+	return nil
+}
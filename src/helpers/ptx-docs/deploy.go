@@ -0,0 +1,166 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DeployOptions selects where a built site is published
+type DeployOptions struct {
+	// Branch receives the site as a commit of its own, e.g. gh-pages
+	Branch string
+	Remote string
+	// Push pushes Branch to Remote after committing
+	Push    bool
+	Message string
+	// Dir, when set, receives a copy of the site instead of Branch
+	Dir string
+}
+
+// deployToBranch commits the contents of publicDir to a branch of the
+// repository containing repoDir. The commit is built with a temporary
+// index, so the working tree and the current branch are left untouched.
+// It returns the new commit, or "" when the site did not change; the
+// branch is pushed either way.
+func deployToBranch(repoDir, publicDir string, opts DeployOptions) (string, error) {
+	gitDir, err := gitOutput(repoDir, nil, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("%s is not in a git repository: %w", repoDir, err)
+	}
+	// GitHub Pages would otherwise run Jekyll over the site
+	if err := os.WriteFile(filepath.Join(publicDir, ".nojekyll"), nil, 0644); err != nil {
+		return "", err
+	}
+
+	index, err := os.CreateTemp("", "ptx-docs-index-*")
+	if err != nil {
+		return "", err
+	}
+	index.Close()
+	os.Remove(index.Name())
+	defer os.Remove(index.Name())
+
+	env := []string{"GIT_INDEX_FILE=" + index.Name()}
+	worktree := []string{"--git-dir=" + gitDir, "--work-tree=" + publicDir}
+	if _, err := gitOutput(publicDir, env, append(worktree, "add", "--all", "--force", ".")...); err != nil {
+		return "", err
+	}
+	tree, err := gitOutput(publicDir, env, append(worktree, "write-tree")...)
+	if err != nil {
+		return "", err
+	}
+
+	ref := "refs/heads/" + opts.Branch
+	commitArgs := []string{"commit-tree", tree, "-m", opts.Message}
+	unchanged := false
+	if parent, err := gitOutput(repoDir, nil, "rev-parse", "--verify", "--quiet", ref); err == nil {
+		parentTree, _ := gitOutput(repoDir, nil, "rev-parse", parent+"^{tree}")
+		unchanged = parentTree == tree
+		commitArgs = append(commitArgs, "-p", parent)
+	}
+	var commit string
+	if !unchanged {
+		if commit, err = gitOutput(repoDir, nil, commitArgs...); err != nil {
+			return "", err
+		}
+		if _, err := gitOutput(repoDir, nil, "update-ref", ref, commit); err != nil {
+			return "", err
+		}
+	}
+
+	if opts.Push {
+		cmd := exec.Command("git", "push", opts.Remote, ref+":"+ref)
+		cmd.Dir = repoDir
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return commit, fmt.Errorf("git push %s %s failed: %w", opts.Remote, opts.Branch, err)
+		}
+	}
+	return commit, nil
+}
+
+// gitOutput runs git in dir and returns its trimmed output
+func gitOutput(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// deployToDir replaces the contents of dir with the built site, e.g. the
+// document root of a web server
+func deployToDir(publicDir, dir string) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	// Refuse to empty a directory that does not hold a previous deployment
+	if _, err := os.Stat(filepath.Join(dir, "index.html")); len(entries) > 0 && err != nil {
+		return 0, fmt.Errorf("%s is not empty and holds no site (index.html missing)", dir)
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return 0, err
+		}
+	}
+
+	files := 0
+	err = filepath.WalkDir(publicDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(publicDir, p)
+		target := filepath.Join(dir, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		files++
+		return copyFile(p, target)
+	})
+	return files, err
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// defaultDeployMessage is the commit message of a deployment
+func defaultDeployMessage() string {
+	return "Deploy documentation " + time.Now().UTC().Format(time.RFC3339)
+}
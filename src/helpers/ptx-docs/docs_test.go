@@ -0,0 +1,167 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	public := t.TempDir()
+	writeFiles(t, public, map[string]string{
+		"index.html": `<a href="/portunix/docs/">Docs</a> <a href='docs/missing/'>x</a>
+			<link href="https://example.org/portunix/css/site.css"> <a href="#top">top</a>
+			<a href="mailto:team@example.org">mail</a> <a href="https://github.com/x">ext</a>`,
+		"docs/index.html":         `<a href="guide/install">install</a> <img src="/portunix/img/logo.png"> <a href="/other/">outside</a>`,
+		"docs/guide/install.html": `<a href="https://example.org/portunix/docs/">back</a>`,
+		"css/site.css":            "",
+	})
+
+	report, err := checkLinks(public, "https://example.org/portunix/", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Pages != 3 || report.Links != 8 {
+		t.Errorf("unexpected counts: %d pages, %d links", report.Pages, report.Links)
+	}
+	var broken []string
+	for _, b := range report.Broken {
+		broken = append(broken, b.Page+" "+b.Link)
+	}
+	want := "docs/index.html /other/,docs/index.html /portunix/img/logo.png,index.html docs/missing/"
+	if strings.Join(broken, ",") != want {
+		t.Errorf("broken links:\n got %s\nwant %s", strings.Join(broken, ","), want)
+	}
+
+	if _, err := checkLinks(filepath.Join(public, "none"), "", false); err == nil {
+		t.Error("missing build should fail")
+	}
+}
+
+func TestFindSite(t *testing.T) {
+	project := t.TempDir()
+	writeFiles(t, project, map[string]string{
+		"docs-site/hugo.toml": "baseURL = 'https://example.org/portunix/'\ntitle = 'Docs'\n",
+	})
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(project)
+
+	site, err := findSite("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(site.Dir) != "docs-site" || site.ConfigFile != "hugo.toml" {
+		t.Errorf("unexpected site %+v", site)
+	}
+	if got := site.BaseURL(); got != "https://example.org/portunix/" {
+		t.Errorf("unexpected baseURL %q", got)
+	}
+	if _, err := findSite("content"); err == nil {
+		t.Error("directory without Hugo configuration should fail")
+	}
+
+	path, err := writeProjectCompose(site, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "- ./docs-site:/src:z") || !strings.Contains(string(data), "${DOCS_IMAGE:-"+DefaultImage+"}") {
+		t.Errorf("unexpected compose file:\n%s", data)
+	}
+	if _, err := writeProjectCompose(site, false); err == nil {
+		t.Error("existing compose file should not be overwritten without --force")
+	}
+	if file, _ := composeFile(site); file != path {
+		t.Errorf("project compose file should be used, got %s", file)
+	}
+}
+
+func TestDeployToBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		out, err := gitOutput(repo, nil, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	git("init", "-q", "-b", "main")
+	git("config", "user.email", "docs@example.org")
+	git("config", "user.name", "Docs")
+	writeFiles(t, repo, map[string]string{"README.md": "readme"})
+	git("add", "README.md")
+	git("commit", "-q", "-m", "initial")
+
+	public := filepath.Join(repo, "docs-site", "public")
+	writeFiles(t, public, map[string]string{"index.html": "<h1>Docs</h1>", "docs/index.html": "page"})
+	opts := DeployOptions{Branch: "gh-pages", Message: "Deploy"}
+
+	commit, err := deployToBranch(repo, public, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit == "" {
+		t.Fatal("first deployment should create a commit")
+	}
+	files := git("ls-tree", "-r", "--name-only", "gh-pages")
+	if files != ".nojekyll\ndocs/index.html\nindex.html" {
+		t.Errorf("unexpected branch contents:\n%s", files)
+	}
+	if branch := git("rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Errorf("current branch changed to %s", branch)
+	}
+	if status := git("status", "--porcelain", "--", "README.md"); status != "" {
+		t.Errorf("working tree changed: %s", status)
+	}
+
+	if commit, err := deployToBranch(repo, public, opts); err != nil || commit != "" {
+		t.Errorf("unchanged site should not be committed again: %q %v", commit, err)
+	}
+}
+
+func TestDeployToDir(t *testing.T) {
+	public := t.TempDir()
+	writeFiles(t, public, map[string]string{"index.html": "new", "docs/index.html": "page"})
+
+	target := t.TempDir()
+	writeFiles(t, target, map[string]string{"index.html": "old", "stale.html": "stale"})
+	files, err := deployToDir(public, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files != 2 {
+		t.Errorf("expected 2 files, got %d", files)
+	}
+	if _, err := os.Stat(filepath.Join(target, "stale.html")); err == nil {
+		t.Error("files of the previous deployment should be removed")
+	}
+
+	other := t.TempDir()
+	writeFiles(t, other, map[string]string{"notes.txt": "keep"})
+	if _, err := deployToDir(public, other); err == nil {
+		t.Error("a directory without a previous site must not be emptied")
+	}
+}
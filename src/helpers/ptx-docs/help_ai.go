@@ -0,0 +1,69 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import "portunix.ai/app/aihelp"
+
+// showHelpAI prints machine-readable help for --help-ai
+func showHelpAI() {
+	site := aihelp.Flag{Name: "site", Type: "path", Description: "Site directory (default: ., docs-site, docs, website, site)"}
+	image := aihelp.Flag{Name: "image", Type: "string", Default: DefaultImage, Description: "Hugo container image (config key docs.image)"}
+	baseURL := aihelp.Flag{Name: "base-url", Type: "url", Description: "Override the baseURL of the site configuration"}
+	external := aihelp.Flag{Name: "external", Type: "boolean", Description: "Also request external URLs"}
+	jsonFlag := aihelp.Flag{Name: "json", Type: "boolean", Description: "Output the link report as JSON"}
+
+	aihelp.Help{
+		Tool:        "ptx-docs",
+		Version:     version,
+		Description: "Hugo documentation sites served, built, link-checked and deployed through the compose runtime",
+		Commands: []aihelp.Command{
+			{
+				Name:        "docs init",
+				Description: "Write docker-compose.docs.yml for the site to the working directory",
+				Flags:       []aihelp.Flag{site, {Name: "force", Type: "boolean", Description: "Overwrite an existing file"}},
+				Examples:    []string{"portunix docs init"},
+			},
+			{
+				Name:        "docs serve",
+				Description: "Start the Hugo live reload server in a container",
+				Flags: []aihelp.Flag{
+					site, image,
+					{Name: "port", Shorthand: "p", Type: "integer", Default: "1313", Description: "Server port"},
+					{Name: "drafts", Type: "boolean", Description: "Render draft pages"},
+					{Name: "detach", Shorthand: "d", Type: "boolean", Description: "Run the server in the background"},
+				},
+				Examples: []string{"portunix docs serve", "portunix docs serve --port 8080 --drafts"},
+			},
+			{Name: "docs stop", Description: "Stop a detached server", Flags: []aihelp.Flag{site}},
+			{
+				Name:        "docs build",
+				Description: "Build the minified site into <site>/public",
+				Flags:       []aihelp.Flag{site, image, baseURL, {Name: "check", Type: "boolean", Description: "Check links after building"}},
+				Examples:    []string{"portunix docs build --check"},
+			},
+			{
+				Name:        "docs check-links",
+				Description: "Check the links of the built site (exit 1 when links are broken)",
+				Flags:       []aihelp.Flag{site, baseURL, external, jsonFlag},
+				Examples:    []string{"portunix docs check-links", "portunix docs check-links --external --json"},
+			},
+			{
+				Name:        "docs deploy",
+				Description: "Build and check the site, then commit it to a branch and push, or copy it to a directory",
+				Flags: []aihelp.Flag{
+					site, image, baseURL, external,
+					{Name: "branch", Type: "string", Default: "gh-pages", Description: "Branch receiving the site"},
+					{Name: "remote", Type: "string", Default: "origin", Description: "Remote the branch is pushed to"},
+					{Name: "message", Shorthand: "m", Type: "string", Description: "Commit message"},
+					{Name: "no-push", Type: "boolean", Description: "Commit to the branch without pushing"},
+					{Name: "dir", Type: "path", Description: "Copy the site to a directory instead of a branch"},
+					{Name: "no-build", Type: "boolean", Description: "Deploy the existing build"},
+					{Name: "force", Type: "boolean", Description: "Deploy despite broken links"},
+				},
+				Examples: []string{"portunix docs deploy", "portunix docs deploy --dir /var/www/docs"},
+			},
+		},
+	}.Print()
+}
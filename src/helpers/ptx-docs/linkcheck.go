@@ -0,0 +1,214 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BrokenLink is a link of a built page whose target does not exist
+type BrokenLink struct {
+	// Page is the page path relative to the public directory
+	Page   string `json:"page"`
+	Link   string `json:"link"`
+	Reason string `json:"reason"`
+}
+
+// LinkReport is the result of checking the links of a built site
+type LinkReport struct {
+	Pages    int          `json:"pages"`
+	Links    int          `json:"links"`
+	External int          `json:"external_checked"`
+	Broken   []BrokenLink `json:"broken"`
+}
+
+// linkPattern matches the href and src attributes of a page
+var linkPattern = regexp.MustCompile(`(?i)\s(?:href|src)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
+// skippedSchemes are links not pointing to documents
+var skippedSchemes = []string{"mailto:", "tel:", "javascript:", "data:"}
+
+// externalWorkers bounds the concurrent requests of external link checks
+const externalWorkers = 8
+
+// checkLinks checks the links of all HTML pages in publicDir. Links under
+// baseURL are resolved inside publicDir; other absolute URLs are requested
+// only when external is set.
+func checkLinks(publicDir, baseURL string, external bool) (*LinkReport, error) {
+	if _, err := os.Stat(publicDir); err != nil {
+		return nil, fmt.Errorf("built site not found in %s (run 'portunix docs build' first)", publicDir)
+	}
+	base, _ := url.Parse(baseURL)
+	basePath := "/"
+	if base != nil && base.Path != "" {
+		basePath = strings.TrimSuffix(base.Path, "/") + "/"
+	}
+
+	report := &LinkReport{}
+	externalLinks := make(map[string][]string)
+	err := filepath.WalkDir(publicDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(d.Name(), ".html") {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(publicDir, p)
+		page := filepath.ToSlash(rel)
+		report.Pages++
+
+		for _, m := range linkPattern.FindAllSubmatch(data, -1) {
+			link := string(m[1]) + string(m[2])
+			if skipLink(link) {
+				continue
+			}
+			report.Links++
+			target, err := url.Parse(link)
+			if err != nil {
+				report.Broken = append(report.Broken, BrokenLink{Page: page, Link: link, Reason: "invalid URL"})
+				continue
+			}
+			if target.IsAbs() || target.Host != "" {
+				if base == nil || target.Host != base.Host || !strings.HasPrefix(target.Path+"/", basePath) {
+					if external && (target.Scheme == "http" || target.Scheme == "https" || target.Scheme == "") {
+						externalLinks[link] = append(externalLinks[link], page)
+					}
+					continue
+				}
+			}
+			if !internalTargetExists(publicDir, page, basePath, target.Path) {
+				report.Broken = append(report.Broken, BrokenLink{Page: page, Link: link, Reason: "not found"})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if external {
+		report.External = len(externalLinks)
+		for link, reason := range checkExternal(externalLinks) {
+			for _, page := range externalLinks[link] {
+				report.Broken = append(report.Broken, BrokenLink{Page: page, Link: link, Reason: reason})
+			}
+		}
+	}
+	sort.Slice(report.Broken, func(i, j int) bool {
+		if report.Broken[i].Page != report.Broken[j].Page {
+			return report.Broken[i].Page < report.Broken[j].Page
+		}
+		return report.Broken[i].Link < report.Broken[j].Link
+	})
+	return report, nil
+}
+
+func skipLink(link string) bool {
+	if link == "" || strings.HasPrefix(link, "#") {
+		return true
+	}
+	lower := strings.ToLower(link)
+	for _, scheme := range skippedSchemes {
+		if strings.HasPrefix(lower, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// internalTargetExists resolves a link path of a page to a file of the
+// built site, the way a static web server would
+func internalTargetExists(publicDir, page, basePath, linkPath string) bool {
+	if linkPath == "" {
+		// Query or fragment of the page itself
+		return true
+	}
+	var target string
+	if strings.HasPrefix(linkPath, "/") {
+		if !strings.HasPrefix(linkPath+"/", basePath) {
+			return false
+		}
+		target = strings.TrimPrefix(linkPath, strings.TrimSuffix(basePath, "/"))
+	} else {
+		target = path.Join(path.Dir("/"+page), linkPath)
+		if strings.HasSuffix(linkPath, "/") {
+			target += "/"
+		}
+	}
+
+	full := filepath.Join(publicDir, filepath.FromSlash(path.Clean("/"+target)))
+	info, err := os.Stat(full)
+	if err == nil && !info.IsDir() {
+		return true
+	}
+	if err == nil {
+		_, err = os.Stat(filepath.Join(full, "index.html"))
+		return err == nil
+	}
+	_, err = os.Stat(full + ".html")
+	return err == nil
+}
+
+// checkExternal requests external links, returning the failure reason of
+// each broken one
+func checkExternal(links map[string][]string) map[string]string {
+	client := &http.Client{Timeout: 15 * time.Second}
+	jobs := make(chan string)
+	broken := make(map[string]string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < externalWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for link := range jobs {
+				if reason := checkURL(client, link); reason != "" {
+					mu.Lock()
+					broken[link] = reason
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for link := range links {
+		jobs <- link
+	}
+	close(jobs)
+	wg.Wait()
+	return broken
+}
+
+// checkURL returns why a URL is unreachable, or "" when it responds.
+// Servers rejecting HEAD are retried with GET.
+func checkURL(client *http.Client, link string) string {
+	if strings.HasPrefix(link, "//") {
+		link = "https:" + link
+	}
+	resp, err := client.Head(link)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusForbidden) {
+		resp.Body.Close()
+		resp, err = client.Get(link)
+	}
+	if err != nil {
+		return err.Error()
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return resp.Status
+	}
+	return ""
+}
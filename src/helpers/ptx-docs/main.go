@@ -0,0 +1,365 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var version = "dev"
+
+// rootCmd represents the base command for ptx-docs
+var rootCmd = &cobra.Command{
+	Use:   "ptx-docs",
+	Short: "Portunix Documentation Site Helper",
+	Long: `ptx-docs is a helper binary for Portunix that manages documentation sites.
+It runs the Hugo static site generator in a container through the detected
+compose runtime (Docker Compose or podman-compose), so no local Hugo install is needed.
+
+This binary is typically invoked by the main portunix dispatcher and should not be used directly.
+
+Supported features:
+- Live reload development server
+- Minified production builds
+- Internal and external link checking
+- Deployment to a gh-pages branch or a directory`,
+	Version:            version,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		handleCommand(args)
+	},
+}
+
+// handleCommand dispatches the "docs" command routed to this helper by the
+// parent portunix binary (see src/dispatcher/dispatcher.go), plus the discovery
+// meta-flags --version, --description, and --list-commands used by the
+// dispatcher. args arrive without the binary name prefix.
+func handleCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Println("No command specified")
+		return
+	}
+
+	command := args[0]
+	subArgs := args[1:]
+
+	switch command {
+	case "docs":
+		if len(subArgs) == 0 {
+			showDocsHelp()
+		} else {
+			handleDocsCommand(subArgs)
+		}
+	case "--version":
+		fmt.Printf("ptx-docs version %s\n", version)
+	case "--description":
+		fmt.Println("Portunix Documentation Site Helper")
+	case "--list-commands":
+		fmt.Println("docs")
+	case "--help-ai":
+		showHelpAI()
+	default:
+		fmt.Printf("Unknown command: %s\n", command)
+		fmt.Println("Supported commands: docs")
+	}
+}
+
+func showDocsHelp() {
+	fmt.Println("Usage: portunix docs [subcommand]")
+	fmt.Println()
+	fmt.Println("Documentation Site Commands (Hugo in a container):")
+	fmt.Println()
+	fmt.Println("  init [--force]               - Write docker-compose.docs.yml for the site")
+	fmt.Println("  serve [--port N] [--drafts] [-d] - Start the live reload server (default port 1313)")
+	fmt.Println("  stop                         - Stop a detached server")
+	fmt.Println("  build [--base-url URL] [--check] - Build the site into <site>/public")
+	fmt.Println("  check-links [--external] [--json] - Check the links of the built site")
+	fmt.Println("  deploy [--branch gh-pages] [--remote origin] [--no-push] [--dir PATH] [--force]")
+	fmt.Println("                               - Build, check and publish the site")
+	fmt.Println()
+	fmt.Println("Common options:")
+	fmt.Println("  --site <dir>                 - Site directory (default: ., docs-site, docs, website, site)")
+	fmt.Println("  --image <image>              - Hugo image (default: docs.image config or " + DefaultImage + ")")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix docs serve")
+	fmt.Println("  portunix docs build --check")
+	fmt.Println("  portunix docs check-links --external")
+	fmt.Println("  portunix docs deploy --branch gh-pages")
+}
+
+// docsFlags are the options of the docs subcommands
+type docsFlags struct {
+	site     string
+	image    string
+	port     int
+	drafts   bool
+	detach   bool
+	force    bool
+	baseURL  string
+	check    bool
+	external bool
+	json     bool
+	noBuild  bool
+	deploy   DeployOptions
+}
+
+func parseDocsFlags(args []string) (*docsFlags, error) {
+	flags := &docsFlags{deploy: DeployOptions{Branch: "gh-pages", Remote: "origin", Push: true}}
+	value := func(i int) (string, error) {
+		if i+1 >= len(args) {
+			return "", fmt.Errorf("%s requires a value", args[i])
+		}
+		return args[i+1], nil
+	}
+
+	for i := 0; i < len(args); i++ {
+		var err error
+		switch args[i] {
+		case "--site":
+			flags.site, err = value(i)
+			i++
+		case "--image":
+			flags.image, err = value(i)
+			i++
+		case "--port", "-p":
+			var port string
+			if port, err = value(i); err == nil {
+				flags.port, err = strconv.Atoi(port)
+				if err == nil && (flags.port < 1 || flags.port > 65535) {
+					err = fmt.Errorf("invalid port %d", flags.port)
+				}
+			}
+			i++
+		case "--base-url":
+			flags.baseURL, err = value(i)
+			i++
+		case "--branch":
+			flags.deploy.Branch, err = value(i)
+			i++
+		case "--remote":
+			flags.deploy.Remote, err = value(i)
+			i++
+		case "--message", "-m":
+			flags.deploy.Message, err = value(i)
+			i++
+		case "--dir":
+			flags.deploy.Dir, err = value(i)
+			i++
+		case "--drafts":
+			flags.drafts = true
+		case "--detach", "-d":
+			flags.detach = true
+		case "--force":
+			flags.force = true
+		case "--check":
+			flags.check = true
+		case "--external":
+			flags.external = true
+		case "--json":
+			flags.json = true
+		case "--no-build":
+			flags.noBuild = true
+		case "--no-push":
+			flags.deploy.Push = false
+		default:
+			err = fmt.Errorf("unknown option: %s", args[i])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return flags, nil
+}
+
+func handleDocsCommand(args []string) {
+	subcommand := args[0]
+
+	switch subcommand {
+	case "--help", "-h":
+		showDocsHelp()
+		return
+	case "--help-ai":
+		showHelpAI()
+		return
+	case "init", "serve", "stop", "build", "check-links", "deploy":
+	default:
+		fmt.Printf("Unknown docs subcommand: %s\n", subcommand)
+		fmt.Println("Run 'portunix docs --help' for available commands")
+		os.Exit(1)
+	}
+
+	flags, err := parseDocsFlags(args[1:])
+	if err == nil {
+		err = runDocsCommand(subcommand, flags)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runDocsCommand(subcommand string, flags *docsFlags) error {
+	site, err := findSite(flags.site)
+	if err != nil {
+		return err
+	}
+	opts := runOptions{Port: flags.port, Drafts: flags.drafts, Image: flags.image}
+
+	switch subcommand {
+	case "init":
+		path, err := writeProjectCompose(site, flags.force)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ Created %s for site %s\n", path, site.Dir)
+		fmt.Println("   Start the live reload server with: portunix docs serve")
+		return nil
+
+	case "serve":
+		port := opts.Port
+		if port == 0 {
+			port = DefaultPort
+		}
+		fmt.Printf("📚 Serving %s at http://localhost:%d/ (live reload)\n", site.Dir, port)
+		args := []string{"up", "--remove-orphans"}
+		if flags.detach {
+			args = append(args, "-d")
+		}
+		if err := runCompose(site, opts, args...); err != nil {
+			return err
+		}
+		if flags.detach {
+			fmt.Println("✅ Server started; stop it with: portunix docs stop")
+		}
+		return nil
+
+	case "stop":
+		return runCompose(site, opts, "down")
+
+	case "build":
+		if err := buildSite(site, opts, flags.baseURL); err != nil {
+			return err
+		}
+		if flags.check {
+			return reportLinks(site, flags)
+		}
+		return nil
+
+	case "check-links":
+		return reportLinks(site, flags)
+
+	case "deploy":
+		return deploySite(site, opts, flags)
+	}
+	return nil
+}
+
+// buildSite runs a minified Hugo build into the site's public directory
+func buildSite(site *Site, opts runOptions, baseURL string) error {
+	fmt.Printf("🔨 Building %s\n", site.Dir)
+	args := []string{"run", "--rm", composeService, "--minify", "--cleanDestinationDir"}
+	if baseURL != "" {
+		args = append(args, "--baseURL", baseURL)
+	}
+	if err := runCompose(site, opts, args...); err != nil {
+		return fmt.Errorf("hugo build failed: %w", err)
+	}
+	fmt.Printf("✅ Site built into %s\n", site.PublicDir())
+	return nil
+}
+
+// reportLinks checks the built site and fails when links are broken
+func reportLinks(site *Site, flags *docsFlags) error {
+	baseURL := flags.baseURL
+	if baseURL == "" {
+		baseURL = site.BaseURL()
+	}
+	report, err := checkLinks(site.PublicDir(), baseURL, flags.external)
+	if err != nil {
+		return err
+	}
+
+	if flags.json {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		fmt.Printf("🔗 Checked %d links on %d pages", report.Links, report.Pages)
+		if flags.external {
+			fmt.Printf(" (%d external URLs)", report.External)
+		}
+		fmt.Println()
+		page := ""
+		for _, broken := range report.Broken {
+			if broken.Page != page {
+				page = broken.Page
+				fmt.Printf("\n  %s\n", page)
+			}
+			fmt.Printf("    ❌ %s (%s)\n", broken.Link, broken.Reason)
+		}
+		if len(report.Broken) == 0 {
+			fmt.Println("✅ No broken links")
+		}
+	}
+	if len(report.Broken) > 0 {
+		return fmt.Errorf("%d broken links", len(report.Broken))
+	}
+	return nil
+}
+
+// deploySite builds the site, refuses to publish broken links, and
+// publishes to a directory or a branch
+func deploySite(site *Site, opts runOptions, flags *docsFlags) error {
+	if !flags.noBuild {
+		if err := buildSite(site, opts, flags.baseURL); err != nil {
+			return err
+		}
+	}
+	if err := reportLinks(site, flags); err != nil && !flags.force {
+		return fmt.Errorf("%v; fix them or deploy with --force", err)
+	}
+
+	if flags.deploy.Dir != "" {
+		files, err := deployToDir(site.PublicDir(), flags.deploy.Dir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ Deployed %d files to %s\n", files, flags.deploy.Dir)
+		return nil
+	}
+
+	if flags.deploy.Message == "" {
+		flags.deploy.Message = defaultDeployMessage()
+	}
+	commit, err := deployToBranch(site.Dir, site.PublicDir(), flags.deploy)
+	if err != nil {
+		return err
+	}
+	switch {
+	case commit == "":
+		fmt.Printf("✅ Branch %s is up to date\n", flags.deploy.Branch)
+	case flags.deploy.Push:
+		fmt.Printf("✅ Deployed %s to %s/%s\n", commit[:12], flags.deploy.Remote, flags.deploy.Branch)
+	default:
+		fmt.Printf("✅ Committed %s to branch %s (push with: git push %s %s)\n", commit[:12], flags.deploy.Branch, flags.deploy.Remote, flags.deploy.Branch)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.SetVersionTemplate("ptx-docs version {{.Version}}\n")
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
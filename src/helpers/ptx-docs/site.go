@@ -0,0 +1,222 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"portunix.ai/app/compose"
+	"portunix.ai/app/config"
+	"portunix.ai/app/mirror"
+)
+
+const (
+	// ComposeFileName is the compose file 'docs init' writes to the project
+	// root; serve and build use it when present
+	ComposeFileName = "docker-compose.docs.yml"
+	// composeService is the service running Hugo in the compose file
+	composeService = "docs"
+	// DefaultImage is the Hugo image used unless docs.image is configured
+	DefaultImage = "hugomods/hugo:exts"
+	// DefaultPort is the port of the live reload server
+	DefaultPort = 1313
+)
+
+// siteConfigFiles are the Hugo configuration files marking a site directory
+var siteConfigFiles = []string{"hugo.toml", "hugo.yaml", "hugo.yml", "hugo.json", "config.toml", "config.yaml", "config.yml"}
+
+// siteCandidates are the directories searched for a site, relative to the
+// working directory
+var siteCandidates = []string{".", "docs-site", "docs", "website", "site"}
+
+// Site is a Hugo documentation site
+type Site struct {
+	// Dir is the absolute site directory
+	Dir string
+	// ConfigFile is the Hugo configuration file name inside Dir
+	ConfigFile string
+	// ProjectDir is the working directory, where the compose file of the
+	// project lives
+	ProjectDir string
+}
+
+// PublicDir returns the directory Hugo builds the site into
+func (s *Site) PublicDir() string {
+	return filepath.Join(s.Dir, "public")
+}
+
+// findSite locates the site: the given directory, or the first of
+// siteCandidates with a Hugo configuration file
+func findSite(dir string) (*Site, error) {
+	candidates := siteCandidates
+	if dir != "" {
+		candidates = []string{dir}
+	}
+	for _, candidate := range candidates {
+		abs, err := filepath.Abs(candidate)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range siteConfigFiles {
+			if _, err := os.Stat(filepath.Join(abs, name)); err == nil {
+				cwd, err := os.Getwd()
+				if err != nil {
+					return nil, err
+				}
+				return &Site{Dir: abs, ConfigFile: name, ProjectDir: cwd}, nil
+			}
+		}
+	}
+	if dir != "" {
+		return nil, fmt.Errorf("no Hugo site in %s (expected one of %s)", dir, strings.Join(siteConfigFiles, ", "))
+	}
+	return nil, fmt.Errorf("no Hugo site found in %s (use --site <dir>)", strings.Join(siteCandidates, ", "))
+}
+
+var baseURLPattern = regexp.MustCompile(`(?m)^\s*"?baseURL"?\s*[=:]\s*["']?([^"'\s,]+)`)
+
+// BaseURL returns the baseURL of the site configuration, or "" when unset
+func (s *Site) BaseURL() string {
+	data, err := os.ReadFile(filepath.Join(s.Dir, s.ConfigFile))
+	if err != nil {
+		return ""
+	}
+	if m := baseURLPattern.FindSubmatch(data); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// composeTemplate runs Hugo with its binary as entrypoint, so that
+// 'compose run docs <args>' passes <args> to hugo. The DOCS_* variables
+// are set by ptx-docs and default to a plain 'hugo server'.
+const composeTemplate = `# Documentation site served by 'portunix docs serve'
+services:
+  docs:
+    image: ${DOCS_IMAGE:-%s}
+    entrypoint: ["hugo"]
+    command:
+      - server
+      - --bind=0.0.0.0
+      - --port=${DOCS_PORT:-%d}
+      - --baseURL=http://localhost:${DOCS_PORT:-%d}/
+      - --appendPort=false
+      - --poll=700ms
+    user: "${DOCS_USER:-0:0}"
+    working_dir: /src
+    environment:
+      HUGO_CACHEDIR: /tmp/hugo_cache
+      HUGO_BUILDDRAFTS: "${DOCS_DRAFTS:-false}"
+    volumes:
+      - %s:/src:z
+    ports:
+      - "${DOCS_PORT:-%d}:${DOCS_PORT:-%d}"
+`
+
+// composeYAML renders the compose file for a site; siteDir is the volume
+// source as written in the file
+func composeYAML(siteDir string) string {
+	return fmt.Sprintf(composeTemplate, DefaultImage, DefaultPort, DefaultPort, filepath.ToSlash(siteDir), DefaultPort, DefaultPort)
+}
+
+// writeProjectCompose writes docker-compose.docs.yml to the project root,
+// mounting the site by a relative path so the file can be committed
+func writeProjectCompose(site *Site, force bool) (string, error) {
+	path := filepath.Join(site.ProjectDir, ComposeFileName)
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+	rel, err := filepath.Rel(site.ProjectDir, site.Dir)
+	if err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, []byte(composeYAML("./"+filepath.ToSlash(rel))), 0644)
+}
+
+// composeFile returns the compose file for a site: the project's
+// docker-compose.docs.yml, or one generated under ~/.portunix/docs
+func composeFile(site *Site) (string, error) {
+	path := filepath.Join(site.ProjectDir, ComposeFileName)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, ".portunix", "docs", projectName(site))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path = filepath.Join(dir, ComposeFileName)
+	return path, os.WriteFile(path, []byte(composeYAML(site.Dir)), 0644)
+}
+
+var projectNameInvalid = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// projectName is the compose project of a site, unique per site directory
+func projectName(site *Site) string {
+	sum := sha256.Sum256([]byte(site.Dir))
+	name := strings.ToLower(filepath.Base(site.ProjectDir))
+	name = projectNameInvalid.ReplaceAllString(name, "-")
+	return fmt.Sprintf("ptx-docs-%s-%x", strings.Trim(name, "-"), sum[:4])
+}
+
+// runOptions are the settings passed to the compose file
+type runOptions struct {
+	Port   int
+	Drafts bool
+	Image  string
+}
+
+// runCompose runs a compose command against the site's compose file
+func runCompose(site *Site, opts runOptions, args ...string) error {
+	runtimeInfo, err := compose.GetComposeRuntime()
+	if err != nil {
+		return fmt.Errorf("%v\n\n%s", err, compose.GetInstallationInstructions())
+	}
+	file, err := composeFile(site)
+	if err != nil {
+		return fmt.Errorf("failed to prepare compose file: %w", err)
+	}
+
+	image := opts.Image
+	if image == "" {
+		image = config.GetString("docs.image", DefaultImage)
+	}
+	if rewritten, err := mirror.RewriteImage(image); err != nil {
+		return err
+	} else if rewritten != image {
+		fmt.Printf("🪞 Using mirror: %s\n", rewritten)
+		image = rewritten
+	}
+	if opts.Port == 0 {
+		opts.Port = DefaultPort
+	}
+
+	env := map[string]string{
+		"DOCS_IMAGE":  image,
+		"DOCS_PORT":   fmt.Sprint(opts.Port),
+		"DOCS_DRAFTS": fmt.Sprint(opts.Drafts),
+	}
+	// Rootful Docker writes build output as root; run Hugo as the current
+	// user instead. Rootless Podman maps container root to the user.
+	if runtime.GOOS == "linux" && runtimeInfo.Command != "podman-compose" {
+		env["DOCS_USER"] = fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+
+	fmt.Printf("🐳 %s: %s\n", runtimeInfo.Name, file)
+	fullArgs := append([]string{"-f", file, "-p", projectName(site)}, args...)
+	return compose.ExecuteWithRuntime(runtimeInfo, fullArgs)
+}
@@ -0,0 +1,201 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BuildTarget is a single GOOS/GOARCH pair
+type BuildTarget struct {
+	OS   string
+	Arch string
+}
+
+// String returns the target in os/arch form
+func (t BuildTarget) String() string {
+	return t.OS + "/" + t.Arch
+}
+
+// BuildOptions holds options for matrix builds
+type BuildOptions struct {
+	Targets   []BuildTarget
+	OutputDir string
+	Name      string
+	Package   string
+	LDFlags   string
+	CGO       bool
+}
+
+// parseTargets parses "linux/amd64,windows/amd64" into build targets
+func parseTargets(spec string) ([]BuildTarget, error) {
+	var targets []BuildTarget
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		goos, goarch, found := strings.Cut(item, "/")
+		if !found || goos == "" || goarch == "" {
+			return nil, fmt.Errorf("invalid target %q (expected os/arch)", item)
+		}
+		targets = append(targets, BuildTarget{OS: goos, Arch: goarch})
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no build targets specified")
+	}
+	return targets, nil
+}
+
+// outputPath returns the binary path for a target, e.g. dist/app-windows-amd64.exe
+func (o BuildOptions) outputPath(t BuildTarget) string {
+	name := fmt.Sprintf("%s-%s-%s", o.Name, t.OS, t.Arch)
+	if t.OS == "windows" {
+		name += ".exe"
+	}
+	return filepath.Join(o.OutputDir, name)
+}
+
+func handleBuild(args []string) error {
+	opts := BuildOptions{
+		OutputDir: "dist",
+		Package:   ".",
+	}
+	targetSpec := ""
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--targets":
+			if i+1 < len(args) {
+				targetSpec = args[i+1]
+				i++
+			}
+		case "--output", "-o":
+			if i+1 < len(args) {
+				opts.OutputDir = args[i+1]
+				i++
+			}
+		case "--name":
+			if i+1 < len(args) {
+				opts.Name = args[i+1]
+				i++
+			}
+		case "--ldflags":
+			if i+1 < len(args) {
+				opts.LDFlags = args[i+1]
+				i++
+			}
+		case "--cgo":
+			opts.CGO = true
+		case "--help", "-h":
+			showBuildHelp()
+			return nil
+		default:
+			if !strings.HasPrefix(args[i], "-") {
+				opts.Package = args[i]
+			}
+		}
+	}
+
+	if targetSpec == "" {
+		targetSpec = hostTarget()
+	}
+	targets, err := parseTargets(targetSpec)
+	if err != nil {
+		return err
+	}
+	opts.Targets = targets
+
+	if opts.Name == "" {
+		abs, err := filepath.Abs(opts.Package)
+		if err != nil {
+			return err
+		}
+		opts.Name = filepath.Base(abs)
+	}
+
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	return runMatrixBuild(opts)
+}
+
+// runMatrixBuild builds every target and prints a summary table
+func runMatrixBuild(opts BuildOptions) error {
+	goCmd := goCommand()
+	failed := 0
+
+	fmt.Printf("🔨 Building %s for %d target(s)...\n\n", opts.Name, len(opts.Targets))
+	for _, t := range opts.Targets {
+		out := opts.outputPath(t)
+		args := []string{"build", "-o", out}
+		if opts.LDFlags != "" {
+			args = append(args, "-ldflags", opts.LDFlags)
+		}
+		args = append(args, opts.Package)
+
+		cgo := "0"
+		if opts.CGO {
+			cgo = "1"
+		}
+
+		start := time.Now()
+		cmd := exec.Command(goCmd, args...)
+		cmd.Env = append(os.Environ(), "GOOS="+t.OS, "GOARCH="+t.Arch, "CGO_ENABLED="+cgo)
+		output, err := cmd.CombinedOutput()
+		elapsed := time.Since(start).Round(time.Millisecond)
+
+		if err != nil {
+			failed++
+			fmt.Printf("  ❌ %-18s %s\n", t.String(), elapsed)
+			for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+				fmt.Printf("       %s\n", line)
+			}
+			continue
+		}
+		fmt.Printf("  ✅ %-18s %-8s %s\n", t.String(), elapsed, out)
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d target(s) failed", failed, len(opts.Targets))
+	}
+	fmt.Printf("✅ All %d target(s) built in %s\n", len(opts.Targets), opts.OutputDir)
+	return nil
+}
+
+// hostTarget returns the target of the active go toolchain
+func hostTarget() string {
+	output, err := exec.Command(goCommand(), "env", "GOOS", "GOARCH").Output()
+	if err != nil {
+		return ""
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) != 2 {
+		return ""
+	}
+	return fields[0] + "/" + fields[1]
+}
+
+func showBuildHelp() {
+	fmt.Println("Usage: portunix go build [package] [options]")
+	fmt.Println()
+	fmt.Println("Cross-compile a Go package for multiple targets.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --targets <os/arch,...> - Targets (default: host platform)")
+	fmt.Println("  --output, -o <dir>      - Output directory (default: dist)")
+	fmt.Println("  --name <name>           - Binary base name (default: package directory name)")
+	fmt.Println("  --ldflags <flags>       - Linker flags passed to go build")
+	fmt.Println("  --cgo                   - Enable CGO (disabled by default for portability)")
+	fmt.Println()
+	fmt.Println("Output files are named <name>-<os>-<arch>[.exe].")
+}
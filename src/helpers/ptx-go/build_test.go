@@ -0,0 +1,93 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTargets(t *testing.T) {
+	targets, err := parseTargets("linux/amd64, windows/amd64,darwin/arm64")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 3 {
+		t.Fatalf("expected 3 targets, got %d", len(targets))
+	}
+	if targets[2].OS != "darwin" || targets[2].Arch != "arm64" {
+		t.Errorf("unexpected target: %v", targets[2])
+	}
+
+	for _, invalid := range []string{"", "linux", "linux/", "/amd64"} {
+		if _, err := parseTargets(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	opts := BuildOptions{OutputDir: "dist", Name: "app"}
+
+	if got := opts.outputPath(BuildTarget{OS: "windows", Arch: "amd64"}); got != filepath.Join("dist", "app-windows-amd64.exe") {
+		t.Errorf("unexpected windows output path: %s", got)
+	}
+	if got := opts.outputPath(BuildTarget{OS: "linux", Arch: "arm64"}); got != filepath.Join("dist", "app-linux-arm64") {
+		t.Errorf("unexpected linux output path: %s", got)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.24.10", "1.24.2", 1},
+		{"1.23", "1.23.0", 0},
+		{"1.22.5", "1.24.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseTestEvents(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Action":"run","Package":"example/a","Test":"TestOne"}`,
+		`{"Action":"pass","Package":"example/a","Test":"TestOne","Elapsed":0.01}`,
+		`{"Action":"fail","Package":"example/a","Test":"TestTwo","Elapsed":0.02}`,
+		`{"Action":"fail","Package":"example/a","Elapsed":0.05}`,
+		`{"Action":"skip","Package":"example/b","Test":"TestSkip"}`,
+		`{"Action":"pass","Package":"example/b","Elapsed":0.01}`,
+	}, "\n")
+
+	summary, err := parseTestEvents(strings.NewReader(input), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Passed != 1 || summary.Failed != 1 || summary.Skipped != 1 {
+		t.Errorf("unexpected totals: %+v", summary)
+	}
+	if summary.ExitCode != ExitIssues {
+		t.Errorf("expected exit code %d, got %d", ExitIssues, summary.ExitCode)
+	}
+	if len(summary.Packages[0].Failures) != 1 || summary.Packages[0].Failures[0] != "TestTwo" {
+		t.Errorf("expected TestTwo failure, got %v", summary.Packages[0].Failures)
+	}
+}
+
+func TestParseLintOutput(t *testing.T) {
+	output := "# example\nvet: main.go:10:2: unreachable code\npkg/util.go:3: missing return\n"
+	issues := parseLintOutput("go vet", output)
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+	if issues[0].File != "main.go" || issues[0].Line != 10 || issues[0].Column != 2 {
+		t.Errorf("unexpected issue: %+v", issues[0])
+	}
+}
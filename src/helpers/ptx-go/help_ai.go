@@ -0,0 +1,62 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import "portunix.ai/app/aihelp"
+
+// showHelpAI prints machine-readable help for --help-ai
+func showHelpAI() {
+	versionArg := aihelp.Argument{Name: "version", Type: "version", Required: true, Description: "Go version (e.g. 1.24.2)"}
+	packages := aihelp.Argument{Name: "packages", Type: "string", Variadic: true, Description: "Package patterns (default ./...)"}
+	jsonFlag := aihelp.Flag{Name: "json", Type: "boolean", Description: "Output a machine-readable summary"}
+
+	aihelp.Help{
+		Tool:        "ptx-go",
+		Version:     version,
+		Description: "Go toolchain management, cross-compilation and quality checks",
+		Commands: []aihelp.Command{
+			{
+				Name:        "go install",
+				Description: "Install a Go version into ~/.portunix/go/versions",
+				Arguments:   []aihelp.Argument{{Name: "version", Type: "version", Required: true, Description: "Go version or 'latest'"}},
+				Examples:    []string{"portunix go install 1.24.2", "portunix go install latest"},
+			},
+			{Name: "go list", Aliases: []string{"ls"}, Description: "List installed Go versions"},
+			{Name: "go use", Description: "Switch the active Go version", Arguments: []aihelp.Argument{versionArg}, Examples: []string{"portunix go use 1.24.2"}},
+			{Name: "go current", Description: "Show the active Go version and GOROOT"},
+			{Name: "go remove", Aliases: []string{"rm"}, Description: "Remove an installed Go version", Arguments: []aihelp.Argument{versionArg}},
+			{Name: "go env", Description: "Print shell commands activating the current version"},
+			{Name: "go mod cache info", Description: "Show module cache location and size"},
+			{Name: "go mod cache clean", Description: "Remove the module cache (go clean -modcache)"},
+			{Name: "go mod download", Description: "Download modules of the current project"},
+			{
+				Name:        "go build",
+				Description: "Cross-compile a matrix of targets",
+				Flags: []aihelp.Flag{
+					{Name: "targets", Type: "string", Description: "Comma-separated os/arch list (e.g. linux/amd64,windows/amd64)"},
+					{Name: "output", Shorthand: "o", Type: "path", Default: "dist", Description: "Output directory"},
+					{Name: "name", Type: "string", Description: "Binary name (default: module directory name)"},
+					{Name: "ldflags", Type: "string", Description: "Linker flags passed to go build"},
+					{Name: "cgo", Type: "boolean", Description: "Enable cgo"},
+				},
+				Examples: []string{"portunix go build --targets linux/amd64,windows/amd64,darwin/arm64"},
+			},
+			{
+				Name:        "go test",
+				Description: "Run go test with a unified summary (exit 0 ok, 1 failures, 2 tool error)",
+				Arguments:   []aihelp.Argument{packages},
+				Flags:       []aihelp.Flag{jsonFlag},
+				Examples:    []string{"portunix go test ./... --json"},
+			},
+			{
+				Name:        "go lint",
+				Description: "Run go vet and golangci-lint when available (exit 0 ok, 1 issues, 2 tool error)",
+				Arguments:   []aihelp.Argument{packages},
+				Flags:       []aihelp.Flag{jsonFlag},
+				Examples:    []string{"portunix go lint --json"},
+			},
+		},
+	}.Print()
+}
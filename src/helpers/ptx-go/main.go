@@ -0,0 +1,156 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var version = "dev"
+
+// rootCmd represents the base command for ptx-go
+var rootCmd = &cobra.Command{
+	Use:   "ptx-go",
+	Short: "Portunix Go Development Helper",
+	Long: `ptx-go is a helper binary for Portunix that handles Go toolchain operations.
+It provides Go version installation and switching, module cache management,
+cross-compile matrix builds, and lint/test wrappers with unified output.
+
+This binary is typically invoked by the main portunix dispatcher and should not be used directly.
+
+Supported features:
+- Go version installation and switching (~/.portunix/go/versions)
+- Module cache management
+- Cross-compile matrix builds
+- Lint (go vet, golangci-lint) and test wrappers`,
+	Version:            version,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		handleCommand(args)
+	},
+}
+
+// handleCommand dispatches the "go" command routed to this helper by the
+// parent portunix binary (see src/dispatcher/dispatcher.go), plus the discovery
+// meta-flags --version, --description, and --list-commands used by the
+// dispatcher. args arrive without the binary name prefix.
+func handleCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Println("No command specified")
+		return
+	}
+
+	command := args[0]
+	subArgs := args[1:]
+
+	switch command {
+	case "go":
+		if len(subArgs) == 0 {
+			showGoHelp()
+		} else {
+			handleGoCommand(subArgs)
+		}
+	case "--version":
+		fmt.Printf("ptx-go version %s\n", version)
+	case "--description":
+		fmt.Println("Portunix Go Development Helper")
+	case "--list-commands":
+		fmt.Println("go")
+	case "--help-ai":
+		showHelpAI()
+	default:
+		fmt.Printf("Unknown command: %s\n", command)
+		fmt.Println("Supported commands: go")
+	}
+}
+
+func showGoHelp() {
+	fmt.Println("Usage: portunix go [subcommand]")
+	fmt.Println()
+	fmt.Println("Go Development Commands:")
+	fmt.Println()
+	fmt.Println("Toolchain Management:")
+	fmt.Println("  install <version|latest>     - Install Go version into ~/.portunix/go/versions")
+	fmt.Println("  list                         - List installed Go versions")
+	fmt.Println("  use <version>                - Switch active Go version")
+	fmt.Println("  current                      - Show active Go version and GOROOT")
+	fmt.Println("  remove <version>             - Remove installed Go version")
+	fmt.Println("  env                          - Print shell commands to activate current version")
+	fmt.Println()
+	fmt.Println("Module Cache:")
+	fmt.Println("  mod cache info               - Show module cache location and size")
+	fmt.Println("  mod cache clean              - Remove module cache (go clean -modcache)")
+	fmt.Println("  mod download                 - Download modules of current project")
+	fmt.Println()
+	fmt.Println("Build & Quality:")
+	fmt.Println("  build --targets <os/arch,...> - Cross-compile matrix build")
+	fmt.Println("  test [packages] [--json]     - Run go test with unified summary")
+	fmt.Println("  lint [packages] [--json]     - Run go vet and golangci-lint (if available)")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix go install 1.24.2")
+	fmt.Println("  portunix go use 1.24.2")
+	fmt.Println("  portunix go build --targets linux/amd64,windows/amd64,darwin/arm64")
+	fmt.Println("  portunix go test ./... --json")
+}
+
+func handleGoCommand(args []string) {
+	subcommand := args[0]
+	subArgs := args[1:]
+
+	var err error
+	exitCode := 0
+
+	switch subcommand {
+	case "install":
+		err = handleInstall(subArgs)
+	case "list", "ls":
+		err = handleList()
+	case "use":
+		err = handleUse(subArgs)
+	case "current":
+		err = handleCurrent()
+	case "remove", "rm":
+		err = handleRemove(subArgs)
+	case "env":
+		err = handleEnv()
+	case "mod":
+		err = handleMod(subArgs)
+	case "build":
+		err = handleBuild(subArgs)
+	case "test":
+		exitCode, err = handleTest(subArgs)
+	case "lint":
+		exitCode, err = handleLint(subArgs)
+	case "--help", "-h":
+		showGoHelp()
+	case "--help-ai":
+		showHelpAI()
+	default:
+		fmt.Printf("Unknown go subcommand: %s\n", subcommand)
+		fmt.Println("Run 'portunix go --help' for available commands")
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitToolError)
+	}
+	os.Exit(exitCode)
+}
+
+func init() {
+	rootCmd.SetVersionTemplate("ptx-go version {{.Version}}\n")
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func handleMod(args []string) error {
+	if len(args) == 0 {
+		showModHelp()
+		return nil
+	}
+
+	switch args[0] {
+	case "cache":
+		if len(args) < 2 {
+			showModHelp()
+			return nil
+		}
+		switch args[1] {
+		case "info":
+			return modCacheInfo()
+		case "clean":
+			return runGo("clean", "-modcache")
+		default:
+			return fmt.Errorf("unknown mod cache subcommand: %s", args[1])
+		}
+	case "download":
+		return runGo(append([]string{"mod", "download"}, args[1:]...)...)
+	case "tidy":
+		return runGo("mod", "tidy")
+	case "--help", "-h":
+		showModHelp()
+		return nil
+	default:
+		return fmt.Errorf("unknown mod subcommand: %s", args[0])
+	}
+}
+
+func showModHelp() {
+	fmt.Println("Usage: portunix go mod [subcommand]")
+	fmt.Println()
+	fmt.Println("Module Cache Commands:")
+	fmt.Println("  cache info              - Show module cache location and size")
+	fmt.Println("  cache clean             - Remove module cache")
+	fmt.Println("  download [modules]      - Download modules to the cache")
+	fmt.Println("  tidy                    - Run go mod tidy")
+}
+
+// modCacheInfo prints GOMODCACHE location, module count and size
+func modCacheInfo() error {
+	goCmd := goCommand()
+	output, err := exec.Command(goCmd, "env", "GOMODCACHE", "GOCACHE").Output()
+	if err != nil {
+		return fmt.Errorf("failed to query go env: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) < 2 {
+		return fmt.Errorf("unexpected go env output")
+	}
+	modCache, buildCache := strings.TrimSpace(lines[0]), strings.TrimSpace(lines[1])
+
+	modSize, modules := dirStats(filepath.Join(modCache, "cache", "download"), modCache)
+	buildSize, _ := dirStats("", buildCache)
+
+	fmt.Printf("Module cache: %s\n", modCache)
+	fmt.Printf("  Modules:    %d\n", modules)
+	fmt.Printf("  Size:       %s\n", formatSize(modSize))
+	fmt.Printf("Build cache:  %s\n", buildCache)
+	fmt.Printf("  Size:       %s\n", formatSize(buildSize))
+	return nil
+}
+
+// dirStats returns the total size of root and the number of module versions
+// (.zip files) under downloadDir
+func dirStats(downloadDir, root string) (int64, int) {
+	var size int64
+	modules := 0
+
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		if downloadDir != "" && strings.HasPrefix(path, downloadDir) && strings.HasSuffix(path, ".zip") {
+			modules++
+		}
+		return nil
+	})
+
+	return size, modules
+}
+
+// goCommand returns the go binary of the active toolchain
+func goCommand() string {
+	t, err := NewToolchain()
+	if err != nil {
+		return "go"
+	}
+	return t.GoCommand()
+}
+
+// runGo runs the active go binary with output attached to the terminal
+func runGo(args ...string) error {
+	cmd := exec.Command(goCommand(), args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
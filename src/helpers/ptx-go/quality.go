@@ -0,0 +1,303 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Unified exit codes for test and lint wrappers
+const (
+	ExitOK        = 0 // Everything passed
+	ExitIssues    = 1 // Tests failed or lint issues found
+	ExitToolError = 2 // Tool could not be executed
+)
+
+// PackageResult summarizes test results of a single package
+type PackageResult struct {
+	Package  string   `json:"package"`
+	Status   string   `json:"status"` // pass, fail, skip
+	Passed   int      `json:"passed"`
+	Failed   int      `json:"failed"`
+	Skipped  int      `json:"skipped"`
+	Elapsed  float64  `json:"elapsed_seconds"`
+	Failures []string `json:"failures,omitempty"`
+}
+
+// TestSummary is the unified output of portunix go test
+type TestSummary struct {
+	Packages []*PackageResult `json:"packages"`
+	Passed   int              `json:"passed"`
+	Failed   int              `json:"failed"`
+	Skipped  int              `json:"skipped"`
+	ExitCode int              `json:"exit_code"`
+}
+
+// testEvent is a line of go test -json output
+type testEvent struct {
+	Action  string  `json:"Action"`
+	Package string  `json:"Package"`
+	Test    string  `json:"Test"`
+	Elapsed float64 `json:"Elapsed"`
+	Output  string  `json:"Output"`
+}
+
+// LintIssue is a single lint finding
+type LintIssue struct {
+	Tool    string `json:"tool"`
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// LintSummary is the unified output of portunix go lint
+type LintSummary struct {
+	Tools    []string    `json:"tools"`
+	Issues   []LintIssue `json:"issues"`
+	ExitCode int         `json:"exit_code"`
+}
+
+// parseTestEvents aggregates go test -json events into a summary
+func parseTestEvents(r io.Reader, verbose io.Writer) (*TestSummary, error) {
+	packages := map[string]*PackageResult{}
+	get := func(name string) *PackageResult {
+		if p, ok := packages[name]; ok {
+			return p
+		}
+		p := &PackageResult{Package: name}
+		packages[name] = p
+		return p
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		var ev testEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			// Build errors are printed as plain text
+			if verbose != nil {
+				fmt.Fprintln(verbose, scanner.Text())
+			}
+			continue
+		}
+		if ev.Package == "" {
+			continue
+		}
+
+		p := get(ev.Package)
+		if ev.Test == "" {
+			switch ev.Action {
+			case "pass", "fail", "skip":
+				p.Status = ev.Action
+				p.Elapsed = ev.Elapsed
+			case "output":
+				if verbose != nil && strings.HasPrefix(ev.Output, "FAIL") {
+					fmt.Fprint(verbose, ev.Output)
+				}
+			}
+			continue
+		}
+
+		switch ev.Action {
+		case "pass":
+			p.Passed++
+		case "fail":
+			p.Failed++
+			p.Failures = append(p.Failures, ev.Test)
+		case "skip":
+			p.Skipped++
+		case "output":
+			if verbose != nil && strings.Contains(ev.Output, "_test.go:") {
+				fmt.Fprint(verbose, ev.Output)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	summary := &TestSummary{}
+	for _, p := range packages {
+		if p.Status == "" {
+			p.Status = "fail"
+		}
+		summary.Packages = append(summary.Packages, p)
+		summary.Passed += p.Passed
+		summary.Failed += p.Failed
+		summary.Skipped += p.Skipped
+	}
+	sort.Slice(summary.Packages, func(i, j int) bool {
+		return summary.Packages[i].Package < summary.Packages[j].Package
+	})
+
+	summary.ExitCode = ExitOK
+	for _, p := range summary.Packages {
+		if p.Status == "fail" {
+			summary.ExitCode = ExitIssues
+		}
+	}
+	return summary, nil
+}
+
+func handleTest(args []string) (int, error) {
+	jsonOutput := false
+	goArgs := []string{"test", "-json"}
+	packages := []string{}
+
+	for _, arg := range args {
+		switch {
+		case arg == "--json":
+			jsonOutput = true
+		case strings.HasPrefix(arg, "-"):
+			goArgs = append(goArgs, arg)
+		default:
+			packages = append(packages, arg)
+		}
+	}
+	if len(packages) == 0 {
+		packages = []string{"./..."}
+	}
+	goArgs = append(goArgs, packages...)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(goCommand(), goArgs...)
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return ExitToolError, err
+	}
+	if err := cmd.Start(); err != nil {
+		return ExitToolError, fmt.Errorf("failed to run go test: %v", err)
+	}
+
+	var verbose io.Writer = os.Stdout
+	if jsonOutput {
+		verbose = nil
+	}
+	summary, parseErr := parseTestEvents(stdout, verbose)
+	waitErr := cmd.Wait()
+
+	if parseErr != nil {
+		return ExitToolError, parseErr
+	}
+	if waitErr != nil && len(summary.Packages) == 0 {
+		return ExitToolError, fmt.Errorf("go test failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	if !jsonOutput && stderr.Len() > 0 {
+		fmt.Fprint(os.Stderr, stderr.String())
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(summary, "", "  ")
+		fmt.Println(string(data))
+		return summary.ExitCode, nil
+	}
+
+	fmt.Println()
+	for _, p := range summary.Packages {
+		icon := "✅"
+		switch p.Status {
+		case "fail":
+			icon = "❌"
+		case "skip":
+			icon = "⏭️ "
+		}
+		fmt.Printf("%s %-60s %3d passed %3d failed %3d skipped  %.2fs\n",
+			icon, p.Package, p.Passed, p.Failed, p.Skipped, p.Elapsed)
+		for _, name := range p.Failures {
+			fmt.Printf("     - %s\n", name)
+		}
+	}
+	fmt.Println()
+	fmt.Printf("Total: %d passed, %d failed, %d skipped\n", summary.Passed, summary.Failed, summary.Skipped)
+
+	return summary.ExitCode, nil
+}
+
+// lintLinePattern matches "path/file.go:12:5: message"
+var lintLinePattern = regexp.MustCompile(`^(?:vet: )?([^\s:]+\.go):(\d+)(?::(\d+))?: (.*)$`)
+
+// parseLintOutput converts file:line:col: message lines into issues
+func parseLintOutput(tool, output string) []LintIssue {
+	var issues []LintIssue
+	for _, line := range strings.Split(output, "\n") {
+		m := lintLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		lineNo, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		issues = append(issues, LintIssue{Tool: tool, File: m[1], Line: lineNo, Column: col, Message: m[4]})
+	}
+	return issues
+}
+
+func handleLint(args []string) (int, error) {
+	jsonOutput := false
+	packages := []string{}
+	for _, arg := range args {
+		if arg == "--json" {
+			jsonOutput = true
+		} else if !strings.HasPrefix(arg, "-") {
+			packages = append(packages, arg)
+		}
+	}
+	if len(packages) == 0 {
+		packages = []string{"./..."}
+	}
+
+	summary := &LintSummary{Issues: []LintIssue{}}
+
+	// go vet is always available
+	vetOutput, vetErr := exec.Command(goCommand(), append([]string{"vet"}, packages...)...).CombinedOutput()
+	summary.Tools = append(summary.Tools, "go vet")
+	vetIssues := parseLintOutput("go vet", string(vetOutput))
+	if vetErr != nil && len(vetIssues) == 0 {
+		return ExitToolError, fmt.Errorf("go vet failed: %s", strings.TrimSpace(string(vetOutput)))
+	}
+	summary.Issues = append(summary.Issues, vetIssues...)
+
+	// golangci-lint is optional
+	if path, err := exec.LookPath("golangci-lint"); err == nil {
+		summary.Tools = append(summary.Tools, "golangci-lint")
+		output, _ := exec.Command(path, append([]string{"run"}, packages...)...).CombinedOutput()
+		summary.Issues = append(summary.Issues, parseLintOutput("golangci-lint", string(output))...)
+	} else if !jsonOutput {
+		fmt.Println("ℹ️  golangci-lint not found, running go vet only")
+	}
+
+	summary.ExitCode = ExitOK
+	if len(summary.Issues) > 0 {
+		summary.ExitCode = ExitIssues
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(summary, "", "  ")
+		fmt.Println(string(data))
+		return summary.ExitCode, nil
+	}
+
+	for _, issue := range summary.Issues {
+		fmt.Printf("%s:%d:%d: %s (%s)\n", issue.File, issue.Line, issue.Column, issue.Message, issue.Tool)
+	}
+	fmt.Println()
+	if summary.ExitCode == ExitOK {
+		fmt.Printf("✅ No issues found (%s)\n", strings.Join(summary.Tools, ", "))
+	} else {
+		fmt.Printf("❌ %d issue(s) found (%s)\n", len(summary.Issues), strings.Join(summary.Tools, ", "))
+	}
+	return summary.ExitCode, nil
+}
@@ -0,0 +1,452 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"portunix.ai/app/config"
+	"portunix.ai/app/extract"
+)
+
+// goDownloadIndex lists Go releases in JSON form
+const goDownloadIndex = "https://go.dev/dl/?mode=json"
+
+// goDownloadIndexAll also lists archived releases, needed for older versions
+const goDownloadIndexAll = "https://go.dev/dl/?mode=json&include=all"
+
+// goDownloadBase is the base URL for Go release archives
+const goDownloadBase = "https://go.dev/dl/"
+
+// Toolchain manages Go installations under ~/.portunix/go
+type Toolchain struct {
+	baseDir     string // ~/.portunix/go
+	versionsDir string // ~/.portunix/go/versions
+}
+
+// goRelease is an entry of the go.dev download index
+type goRelease struct {
+	Version string   `json:"version"`
+	Stable  bool     `json:"stable"`
+	Files   []goFile `json:"files"`
+}
+
+// goFile is a downloadable file of a go.dev release
+type goFile struct {
+	Filename string `json:"filename"`
+	SHA256   string `json:"sha256"`
+}
+
+// NewToolchain creates a new toolchain manager
+func NewToolchain() (*Toolchain, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %v", err)
+	}
+
+	baseDir := config.GetString("go.root_dir", filepath.Join(homeDir, ".portunix", "go"))
+	versionsDir := filepath.Join(baseDir, "versions")
+	if err := os.MkdirAll(versionsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create go versions directory: %v", err)
+	}
+
+	return &Toolchain{
+		baseDir:     baseDir,
+		versionsDir: versionsDir,
+	}, nil
+}
+
+// normalizeVersion strips a leading "go" or "v" prefix (go1.24.2, v1.24.2 -> 1.24.2)
+func normalizeVersion(v string) string {
+	v = strings.TrimPrefix(v, "go")
+	return strings.TrimPrefix(v, "v")
+}
+
+// GoRoot returns the GOROOT of an installed version
+func (t *Toolchain) GoRoot(v string) string {
+	return filepath.Join(t.versionsDir, "go"+normalizeVersion(v))
+}
+
+// IsInstalled reports whether a version is installed
+func (t *Toolchain) IsInstalled(v string) bool {
+	_, err := os.Stat(goBinary(t.GoRoot(v)))
+	return err == nil
+}
+
+// fetchReleases queries the go.dev download index
+func fetchReleases(indexURL string) ([]goRelease, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(indexURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Go releases: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query Go releases: HTTP %d", resp.StatusCode)
+	}
+
+	var releases []goRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to parse Go releases: %v", err)
+	}
+	return releases, nil
+}
+
+// archiveChecksum returns the published sha256 of a release archive
+func archiveChecksum(releases []goRelease, archiveName string) (string, error) {
+	for _, r := range releases {
+		for _, f := range r.Files {
+			if f.Filename == archiveName && f.SHA256 != "" {
+				return strings.ToLower(f.SHA256), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%s is not listed on go.dev (is it a valid Go version?)", archiveName)
+}
+
+// ResolveLatest queries go.dev for the latest stable version
+func (t *Toolchain) ResolveLatest() (string, error) {
+	releases, err := fetchReleases(goDownloadIndex)
+	if err != nil {
+		return "", err
+	}
+
+	for _, r := range releases {
+		if r.Stable {
+			return normalizeVersion(r.Version), nil
+		}
+	}
+	return "", fmt.Errorf("no stable Go release found")
+}
+
+// Install downloads and extracts a Go release for the current platform
+func (t *Toolchain) Install(v string) error {
+	v = normalizeVersion(v)
+	if t.IsInstalled(v) {
+		fmt.Printf("Go %s is already installed at %s\n", v, t.GoRoot(v))
+		return nil
+	}
+
+	ext := ".tar.gz"
+	if runtime.GOOS == "windows" {
+		ext = ".zip"
+	}
+	archiveName := fmt.Sprintf("go%s.%s-%s%s", v, runtime.GOOS, runtime.GOARCH, ext)
+	url := goDownloadBase + archiveName
+
+	releases, err := fetchReleases(goDownloadIndexAll)
+	if err != nil {
+		return err
+	}
+	expectedSum, err := archiveChecksum(releases, archiveName)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp("", "ptx-go-*"+ext)
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	fmt.Printf("📥 Downloading %s...\n", url)
+	client := &http.Client{Timeout: 30 * time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("download failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		tmpFile.Close()
+		return fmt.Errorf("download failed: HTTP %d (is %s a valid Go version?)", resp.StatusCode, v)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmpFile, hash), resp.Body); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("download failed: %v", err)
+	}
+	tmpFile.Close()
+
+	if actualSum := hex.EncodeToString(hash.Sum(nil)); actualSum != expectedSum {
+		os.Remove(tmpFile.Name())
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", archiveName, expectedSum, actualSum)
+	}
+	fmt.Println("🔐 Checksum verified")
+
+	// Archives contain a top-level "go/" directory; extract to a staging dir and rename
+	stagingDir, err := os.MkdirTemp(t.versionsDir, ".staging-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	fmt.Println("📦 Extracting...")
+	if ext == ".zip" {
+		err = extract.Zip(tmpFile.Name(), stagingDir)
+	} else {
+		err = extract.TarGz(tmpFile.Name(), stagingDir)
+	}
+	if err != nil {
+		return fmt.Errorf("extraction failed: %v", err)
+	}
+
+	if err := os.Rename(filepath.Join(stagingDir, "go"), t.GoRoot(v)); err != nil {
+		return fmt.Errorf("failed to install Go %s: %v", v, err)
+	}
+
+	fmt.Printf("✅ Go %s installed at %s\n", v, t.GoRoot(v))
+	return nil
+}
+
+// List returns installed versions sorted newest first
+func (t *Toolchain) List() ([]string, error) {
+	entries, err := os.ReadDir(t.versionsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), "go") && t.IsInstalled(e.Name()) {
+			versions = append(versions, normalizeVersion(e.Name()))
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return compareVersions(versions[i], versions[j]) > 0
+	})
+	return versions, nil
+}
+
+// Current returns the active version; empty when the system Go is used
+func (t *Toolchain) Current() string {
+	// Project pin in .go-version takes precedence over global selection
+	if data, err := os.ReadFile(".go-version"); err == nil {
+		if v := normalizeVersion(strings.TrimSpace(string(data))); v != "" && t.IsInstalled(v) {
+			return v
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(t.baseDir, "current"))
+	if err != nil {
+		return ""
+	}
+	return normalizeVersion(strings.TrimSpace(string(data)))
+}
+
+// Use sets the globally active version
+func (t *Toolchain) Use(v string) error {
+	v = normalizeVersion(v)
+	if !t.IsInstalled(v) {
+		return fmt.Errorf("go %s is not installed (install it with: portunix go install %s)", v, v)
+	}
+	return os.WriteFile(filepath.Join(t.baseDir, "current"), []byte(v+"\n"), 0644)
+}
+
+// Remove deletes an installed version
+func (t *Toolchain) Remove(v string) error {
+	v = normalizeVersion(v)
+	if !t.IsInstalled(v) {
+		return fmt.Errorf("go %s is not installed", v)
+	}
+	if t.Current() == v {
+		os.Remove(filepath.Join(t.baseDir, "current"))
+	}
+	return os.RemoveAll(t.GoRoot(v))
+}
+
+// GoCommand returns the go binary to use: the active managed version or go from PATH
+func (t *Toolchain) GoCommand() string {
+	if v := t.Current(); v != "" && t.IsInstalled(v) {
+		return goBinary(t.GoRoot(v))
+	}
+	return "go"
+}
+
+// goBinary returns the path of the go binary within a GOROOT
+func goBinary(goRoot string) string {
+	name := "go"
+	if runtime.GOOS == "windows" {
+		name = "go.exe"
+	}
+	return filepath.Join(goRoot, "bin", name)
+}
+
+// compareVersions compares dotted versions numerically (1.24.10 > 1.24.2)
+func compareVersions(a, b string) int {
+	pa := strings.Split(a, ".")
+	pb := strings.Split(b, ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var na, nb int
+		if i < len(pa) {
+			fmt.Sscanf(pa[i], "%d", &na)
+		}
+		if i < len(pb) {
+			fmt.Sscanf(pb[i], "%d", &nb)
+		}
+		if na != nb {
+			if na > nb {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+// Command handlers
+
+func handleInstall(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("version required (usage: portunix go install <version|latest>)")
+	}
+
+	t, err := NewToolchain()
+	if err != nil {
+		return err
+	}
+
+	v := args[0]
+	if v == "latest" {
+		if v, err = t.ResolveLatest(); err != nil {
+			return err
+		}
+	}
+
+	if err := t.Install(v); err != nil {
+		return err
+	}
+
+	if t.Current() == "" {
+		if err := t.Use(v); err != nil {
+			return err
+		}
+		fmt.Printf("Go %s set as active version\n", normalizeVersion(v))
+	}
+	return nil
+}
+
+func handleList() error {
+	t, err := NewToolchain()
+	if err != nil {
+		return err
+	}
+
+	versions, err := t.List()
+	if err != nil {
+		return err
+	}
+
+	if len(versions) == 0 {
+		fmt.Println("No Go versions installed by portunix.")
+		fmt.Println("Install one with: portunix go install latest")
+		return nil
+	}
+
+	current := t.Current()
+	fmt.Printf("Go versions in %s:\n\n", t.versionsDir)
+	for _, v := range versions {
+		marker := "  "
+		if v == current {
+			marker = "* "
+		}
+		fmt.Printf("%s%s\n", marker, v)
+	}
+	return nil
+}
+
+func handleUse(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("version required (usage: portunix go use <version>)")
+	}
+
+	t, err := NewToolchain()
+	if err != nil {
+		return err
+	}
+
+	if err := t.Use(args[0]); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Go %s is now active\n", normalizeVersion(args[0]))
+	fmt.Println("Portunix commands use it automatically. For your shell, run:")
+	printEnvCommands(t.GoRoot(args[0]))
+	return nil
+}
+
+func handleCurrent() error {
+	t, err := NewToolchain()
+	if err != nil {
+		return err
+	}
+
+	v := t.Current()
+	if v == "" {
+		path, err := exec.LookPath("go")
+		if err != nil {
+			fmt.Println("No active Go version (none managed by portunix, none on PATH)")
+			return nil
+		}
+		fmt.Printf("System Go: %s\n", path)
+		return nil
+	}
+
+	fmt.Printf("Go %s\n", v)
+	fmt.Printf("GOROOT: %s\n", t.GoRoot(v))
+	return nil
+}
+
+func handleRemove(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("version required (usage: portunix go remove <version>)")
+	}
+
+	t, err := NewToolchain()
+	if err != nil {
+		return err
+	}
+
+	if err := t.Remove(args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Go %s removed\n", normalizeVersion(args[0]))
+	return nil
+}
+
+func handleEnv() error {
+	t, err := NewToolchain()
+	if err != nil {
+		return err
+	}
+
+	v := t.Current()
+	if v == "" {
+		return fmt.Errorf("no active Go version (select one with: portunix go use <version>)")
+	}
+	printEnvCommands(t.GoRoot(v))
+	return nil
+}
+
+// printEnvCommands prints shell commands setting GOROOT and PATH
+func printEnvCommands(goRoot string) {
+	if runtime.GOOS == "windows" {
+		fmt.Printf("  $env:GOROOT=\"%s\"; $env:PATH=\"%s;$env:PATH\"\n", goRoot, filepath.Join(goRoot, "bin"))
+		return
+	}
+	fmt.Printf("  export GOROOT=\"%s\" PATH=\"%s:$PATH\"\n", goRoot, filepath.Join(goRoot, "bin"))
+}
@@ -0,0 +1,37 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestArchiveChecksum(t *testing.T) {
+	index := `[
+		{"version": "go1.24.2", "stable": true, "files": [
+			{"filename": "go1.24.2.src.tar.gz", "sha256": "aaaa"},
+			{"filename": "go1.24.2.linux-amd64.tar.gz", "sha256": "68097BD680839CBC9D464A0EDCE4F7C333975E27A90246890E9F1078C7E702AD"}
+		]},
+		{"version": "go1.23.8", "stable": true, "files": [
+			{"filename": "go1.23.8.windows-amd64.zip", "sha256": "bbbb"}
+		]}
+	]`
+	var releases []goRelease
+	if err := json.Unmarshal([]byte(index), &releases); err != nil {
+		t.Fatal(err)
+	}
+
+	sum, err := archiveChecksum(releases, "go1.24.2.linux-amd64.tar.gz")
+	if err != nil || sum != "68097bd680839cbc9d464a0edce4f7c333975e27a90246890e9f1078c7e702ad" {
+		t.Errorf("got %q, %v", sum, err)
+	}
+	if sum, err := archiveChecksum(releases, "go1.23.8.windows-amd64.zip"); err != nil || sum != "bbbb" {
+		t.Errorf("older release: got %q, %v", sum, err)
+	}
+	if _, err := archiveChecksum(releases, "go1.99.0.linux-amd64.tar.gz"); err == nil {
+		t.Error("expected error for an unlisted archive")
+	}
+}
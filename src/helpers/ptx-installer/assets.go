@@ -13,6 +13,11 @@ import (
 //go:embed assets/packages/*.json
 var embeddedAssets embed.FS
 
+// Embed built-in installation profiles
+//
+//go:embed assets/profiles/*.yaml
+var embeddedProfiles embed.FS
+
 // Embed installation scripts for Windows
 //
 //go:embed assets/scripts/windows/*.ps1
@@ -0,0 +1,305 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
+)
+
+// PackageManifest declares the packages a system should have. It is the
+// input of 'portunix install apply', a lighter alternative to a ptxbook.
+//
+//	packages:
+//	  - name: nodejs
+//	    version: "20"
+//	  - name: java
+//	    variant: "21"
+//	    os: [linux, darwin]
+type PackageManifest struct {
+	Packages []DesiredPackage `yaml:"packages" json:"packages"`
+}
+
+// DesiredPackage is one entry of a package manifest
+type DesiredPackage struct {
+	Name string `yaml:"name" json:"name"`
+	// Version is the wanted version or version prefix ("20" matches 20.11.1).
+	// Empty or "latest" accepts any installed version.
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+	Variant string `yaml:"variant,omitempty" json:"variant,omitempty"`
+	// OS and Arch limit the entry to some platforms (linux, windows, darwin;
+	// amd64, arm64). Empty means all.
+	OS   []string `yaml:"os,omitempty" json:"os,omitempty"`
+	Arch []string `yaml:"arch,omitempty" json:"arch,omitempty"`
+}
+
+// Apply actions reported for each manifest entry
+const (
+	ApplyInstalled = "installed"
+	ApplyUpdated   = "updated"
+	ApplySkipped   = "skipped"
+	ApplyFailed    = "failed"
+)
+
+// ApplyResult is the outcome of converging one manifest entry
+type ApplyResult struct {
+	Name    string `json:"name"`
+	Action  string `json:"action"`
+	Version string `json:"version,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// LoadPackageManifest reads and validates a package manifest file
+func LoadPackageManifest(path string) (*PackageManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return ParsePackageManifest(data)
+}
+
+// ParsePackageManifest parses and validates a package manifest
+func ParsePackageManifest(data []byte) (*PackageManifest, error) {
+	var m PackageManifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// validate checks that the manifest lists named packages, each once per
+// platform
+func (m *PackageManifest) validate() error {
+	if len(m.Packages) == 0 {
+		return fmt.Errorf("manifest declares no packages")
+	}
+	seen := make(map[string]bool)
+	for i, p := range m.Packages {
+		if p.Name == "" {
+			return fmt.Errorf("package %d has no name", i+1)
+		}
+		// The same package may appear once per platform
+		key := p.Name + "/" + strings.Join(p.OS, ",") + "/" + strings.Join(p.Arch, ",")
+		if seen[key] {
+			return fmt.Errorf("package %s is declared twice", p.Name)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// Applies reports whether the entry targets the given platform. Arch
+// accepts Go names (amd64) and registry names (x64).
+func (p DesiredPackage) Applies(goos, arch string) bool {
+	return matchesAny(p.OS, goos, strings.ToLower) && matchesAny(p.Arch, arch, normalizeArch)
+}
+
+func matchesAny(values []string, current string, normalize func(string) string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if normalize(v) == normalize(current) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeArch maps Go architecture names to the registry names
+func normalizeArch(arch string) string {
+	switch arch = strings.ToLower(arch); arch {
+	case "amd64", "x86_64":
+		return "x64"
+	case "386":
+		return "x86"
+	}
+	return arch
+}
+
+// VersionMatches reports whether an installed version satisfies the wanted
+// version: equal, or wanted is a prefix ending at a version separator
+func VersionMatches(installed, wanted string) bool {
+	installed = strings.TrimPrefix(installed, "v")
+	wanted = strings.TrimPrefix(wanted, "v")
+	if wanted == "" || wanted == "latest" || installed == wanted {
+		return true
+	}
+	return strings.HasPrefix(installed, wanted+".")
+}
+
+var versionPattern = regexp.MustCompile(`\d+\.\d+(\.\d+)?`)
+
+// hasVerification reports whether a package declares a verification command
+func hasVerification(pkg *registry.Package) bool {
+	if platform, ok := currentPlatform(pkg); ok && platform.Verification != nil && platform.Verification.Command != "" {
+		return true
+	}
+	return pkg.Spec.Verification != nil && pkg.Spec.Verification.Command != ""
+}
+
+// InstalledVersion runs the verification command of a package and reports
+// whether it is installed and, when the output contains one, its version.
+// Packages without a verification command are looked up on PATH by name.
+func (i *Installer) InstalledVersion(pkg *registry.Package) (bool, string) {
+	return i.runVerification(pkg, "")
+}
+
+// runVerification runs the verification command with ${INSTALL_PATH} set to
+// installPath, for packages installed into a chosen directory
+func (i *Installer) runVerification(pkg *registry.Package, installPath string) (bool, string) {
+	verification := pkg.Spec.Verification
+	if platform, ok := currentPlatform(pkg); ok && platform.Verification != nil {
+		verification = platform.Verification
+	}
+	if verification == nil || verification.Command == "" {
+		_, err := exec.LookPath(pkg.Metadata.Name)
+		return err == nil, ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	command := verification.Command
+	if installPath != "" {
+		command = strings.ReplaceAll(command, "${INSTALL_PATH}", installPath)
+	}
+	command = expandEnvVars(command)
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	// Many tools print their version to stderr (java -version)
+	out, err := cmd.CombinedOutput()
+	exitCode := 0
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			return false, ""
+		}
+		exitCode = exitErr.ExitCode()
+	}
+	if exitCode != verification.ExpectedExitCode {
+		return false, ""
+	}
+	return true, versionPattern.FindString(string(out))
+}
+
+// currentPlatform returns the platform section of a package for this system,
+// falling back from windows_sandbox to windows like Install does
+func currentPlatform(pkg *registry.Package) (registry.PlatformSpec, bool) {
+	currentOS := GetOperatingSystem()
+	platform, ok := pkg.Spec.Platforms[currentOS]
+	if !ok && currentOS == "windows_sandbox" {
+		platform, ok = pkg.Spec.Platforms["windows"]
+	}
+	return platform, ok
+}
+
+// selectVariant returns the variant of an entry: the explicit variant, else
+// the variant named after or providing the wanted version, else "" for the
+// automatic choice
+func selectVariant(platform registry.PlatformSpec, p DesiredPackage) string {
+	if p.Variant != "" {
+		return p.Variant
+	}
+	if p.Version == "" || p.Version == "latest" {
+		return ""
+	}
+	if _, ok := platform.Variants[p.Version]; ok {
+		return p.Version
+	}
+	for name, variant := range platform.Variants {
+		if VersionMatches(variant.Version, p.Version) {
+			return name
+		}
+	}
+	return ""
+}
+
+// Apply converges the system to the manifest. Missing packages are
+// installed, packages with a version other than the wanted one are
+// reinstalled and the rest are skipped. install performs one installation;
+// nil uses Install. With dryRun nothing is installed and the results
+// describe what would be done.
+func (i *Installer) Apply(m *PackageManifest, dryRun bool, install func(*InstallOptions) error) []ApplyResult {
+	if install == nil {
+		install = i.Install
+	}
+	goos, arch := runtime.GOOS, runtime.GOARCH
+	results := make([]ApplyResult, 0, len(m.Packages))
+	for _, p := range m.Packages {
+		result := ApplyResult{Name: p.Name}
+		if !p.Applies(goos, arch) {
+			result.Action = ApplySkipped
+			result.Detail = fmt.Sprintf("not for %s/%s", goos, arch)
+			results = append(results, result)
+			continue
+		}
+		pkg, err := i.registry.GetPackage(p.Name)
+		if err != nil {
+			result.Action = ApplyFailed
+			result.Detail = "unknown package"
+			results = append(results, result)
+			continue
+		}
+		platform, ok := currentPlatform(pkg)
+		if backend, _, _ := nativeBackend(pkg, BackendAuto, ""); !ok && backend == nil {
+			result.Action = ApplySkipped
+			result.Detail = fmt.Sprintf("not available for %s", GetOperatingSystem())
+			results = append(results, result)
+			continue
+		}
+
+		installed, version := i.InstalledVersion(pkg)
+		result.Version = version
+		options := &InstallOptions{PackageName: p.Name, Variant: selectVariant(platform, p)}
+		switch {
+		case !installed:
+			result.Action = ApplyInstalled
+		case version != "" && !VersionMatches(version, p.Version):
+			result.Action = ApplyUpdated
+			result.Detail = fmt.Sprintf("%s -> %s", version, p.Version)
+			options.Force = true
+		default:
+			result.Action = ApplySkipped
+			result.Detail = "already installed"
+			results = append(results, result)
+			continue
+		}
+
+		if dryRun {
+			result.Detail = strings.TrimSpace("would be " + result.Action + " " + result.Detail)
+			results = append(results, result)
+			continue
+		}
+		if err := install(options); err != nil {
+			result.Action = ApplyFailed
+			result.Detail = err.Error()
+			results = append(results, result)
+			continue
+		}
+		if ok, newVersion := i.InstalledVersion(pkg); ok {
+			result.Version = newVersion
+		} else {
+			result.Detail = strings.TrimSpace(result.Detail + " (verification failed, a new shell may be needed)")
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,159 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
+)
+
+func TestParsePackageManifest(t *testing.T) {
+	m, err := ParsePackageManifest([]byte(`
+packages:
+  - name: nodejs
+    version: "20"
+  - name: java
+    variant: "21"
+    os: [linux, darwin]
+  - name: java
+    os: [windows]
+`))
+	if err != nil {
+		t.Fatalf("ParsePackageManifest failed: %v", err)
+	}
+	if len(m.Packages) != 3 || m.Packages[0].Version != "20" || m.Packages[1].Variant != "21" {
+		t.Errorf("unexpected manifest %+v", m)
+	}
+	if !m.Packages[1].Applies("linux", "arm64") || m.Packages[1].Applies("windows", "amd64") {
+		t.Error("os condition not applied")
+	}
+
+	for name, data := range map[string]string{
+		"empty":     "packages: []",
+		"no name":   "packages:\n  - version: 1\n",
+		"duplicate": "packages:\n  - name: go\n  - name: go\n",
+		"invalid":   "packages: [",
+	} {
+		if _, err := ParsePackageManifest([]byte(data)); err == nil {
+			t.Errorf("%s manifest should fail", name)
+		}
+	}
+}
+
+func TestVersionMatches(t *testing.T) {
+	tests := []struct {
+		installed, wanted string
+		want              bool
+	}{
+		{"20.11.1", "20", true},
+		{"20.11.1", "20.11", true},
+		{"v1.31.0", "1.31.0", true},
+		{"21.0.2", "2", false},
+		{"18.19.0", "20", false},
+		{"1.2.3", "", true},
+		{"1.2.3", "latest", true},
+	}
+	for _, tt := range tests {
+		if got := VersionMatches(tt.installed, tt.wanted); got != tt.want {
+			t.Errorf("VersionMatches(%q, %q) = %v, want %v", tt.installed, tt.wanted, got, tt.want)
+		}
+	}
+}
+
+// writeTestPackage stores a package whose verification command prints version
+func writeTestPackage(t *testing.T, dir, name, verification string) {
+	t.Helper()
+	pkg := `{
+  "apiVersion": "v1",
+  "kind": "Package",
+  "metadata": {"name": "` + name + `", "displayName": "` + name + `", "description": "test package", "category": "development/tools"},
+  "spec": {
+    "platforms": {
+      "` + GetOperatingSystem() + `": {
+        "type": "script",
+        "variants": {
+          "1": {"version": "1.4.0", "installScript": "true"},
+          "2": {"version": "2.1.0", "installScript": "true"}
+        },
+        "verification": {"command": "` + verification + `", "expectedExitCode": 0}
+      }
+    }
+  }
+}`
+	if err := os.WriteFile(filepath.Join(dir, "packages", name+".json"), []byte(pkg), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstallerApply(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("verification commands use sh")
+	}
+	t.Setenv(registry.EnvPackagesDir, t.TempDir())
+	assets := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(assets, "packages"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestPackage(t, assets, "present", "echo tool version 1.4.0")
+	writeTestPackage(t, assets, "outdated", "echo tool version 1.4.0")
+	writeTestPackage(t, assets, "missing", "exit 1")
+	writeTestPackage(t, assets, "broken", "exit 1")
+	reg, err := registry.LoadPackageRegistry(assets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	installer := &Installer{registry: reg, cacheDir: t.TempDir(), assetsPath: assets}
+
+	manifest := &PackageManifest{Packages: []DesiredPackage{
+		{Name: "present", Version: "1"},
+		{Name: "outdated", Version: "2"},
+		{Name: "missing"},
+		{Name: "broken"},
+		{Name: "elsewhere", OS: []string{"plan9"}},
+		{Name: "unknown"},
+	}}
+
+	var installed []*InstallOptions
+	install := func(o *InstallOptions) error {
+		installed = append(installed, o)
+		if o.PackageName == "broken" {
+			return errors.New("script failed")
+		}
+		return nil
+	}
+
+	dry := installer.Apply(manifest, true, install)
+	if len(installed) != 0 {
+		t.Fatalf("dry run should not install, got %d installs", len(installed))
+	}
+	if dry[1].Action != ApplyUpdated || dry[1].Detail != "would be updated 1.4.0 -> 2" {
+		t.Errorf("unexpected dry run result %+v", dry[1])
+	}
+
+	results := installer.Apply(manifest, false, install)
+	want := []string{ApplySkipped, ApplyUpdated, ApplyInstalled, ApplyFailed, ApplySkipped, ApplyFailed}
+	for i, r := range results {
+		if r.Action != want[i] {
+			t.Errorf("%s: action %s, want %s (%s)", r.Name, r.Action, want[i], r.Detail)
+		}
+	}
+	if results[0].Version != "1.4.0" {
+		t.Errorf("installed version not detected: %+v", results[0])
+	}
+	if len(installed) != 3 {
+		t.Fatalf("expected 3 installs, got %d", len(installed))
+	}
+	if o := installed[0]; o.PackageName != "outdated" || o.Variant != "2" || !o.Force {
+		t.Errorf("update should force the variant of the wanted version: %+v", o)
+	}
+	if o := installed[1]; o.PackageName != "missing" || o.Variant != "" || o.Force {
+		t.Errorf("unexpected install options %+v", o)
+	}
+}
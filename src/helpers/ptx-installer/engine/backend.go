@@ -0,0 +1,174 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+
+	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
+)
+
+// Backend selection values of InstallOptions.Backend besides backend names
+const (
+	// BackendAuto prefers a native package of an available system package
+	// manager and falls back to the registry variant
+	BackendAuto = "auto"
+	// BackendDirect always installs the registry variant
+	BackendDirect = "direct"
+)
+
+// PackageBackend is a system package manager packages can be delegated to
+// when their definition names a native package for it
+type PackageBackend interface {
+	// Name is the key used in the "native" section of package definitions
+	Name() string
+	// Available reports whether the manager can be used on this system
+	Available() bool
+	Install(packages []string) error
+	Remove(packages []string) error
+}
+
+// commandBackend is a package manager driven through its command line tool
+type commandBackend struct {
+	name     string
+	goos     string
+	commands []string
+	install  func(packages []string) error
+}
+
+func (b *commandBackend) Name() string { return b.name }
+
+func (b *commandBackend) Available() bool {
+	if runtime.GOOS != b.goos {
+		return false
+	}
+	for _, command := range b.commands {
+		if isCommandAvailable(command) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *commandBackend) Install(packages []string) error { return b.install(packages) }
+
+func (b *commandBackend) Remove(packages []string) error {
+	return RemoveSystemPackages(b.name, packages)
+}
+
+// brewBackend finds brew also outside PATH, in the Homebrew prefix
+type brewBackend struct {
+	commandBackend
+}
+
+func (b *brewBackend) Available() bool {
+	return runtime.GOOS == "darwin" && brewCommand() != ""
+}
+
+// packageBackends lists the known backends, in order of preference per
+// operating system
+var packageBackends = []PackageBackend{
+	&commandBackend{name: "apt", goos: "linux", commands: []string{"apt-get"}, install: func(p []string) error { return InstallViaAPT(p, true) }},
+	&commandBackend{name: "dnf", goos: "linux", commands: []string{"dnf", "yum"}, install: func(p []string) error { return InstallViaDNF(p, true) }},
+	&commandBackend{name: "pacman", goos: "linux", commands: []string{"pacman"}, install: func(p []string) error { return InstallViaPacman(p, true) }},
+	&commandBackend{name: "zypper", goos: "linux", commands: []string{"zypper"}, install: func(p []string) error { return InstallViaZypper(p, true) }},
+	&brewBackend{commandBackend{name: "brew", goos: "darwin", install: InstallViaBrew}},
+	&commandBackend{name: "winget", goos: "windows", commands: []string{"winget"}, install: InstallViaWinget},
+	&commandBackend{name: "chocolatey", goos: "windows", commands: []string{"choco"}, install: InstallViaChocolatey},
+}
+
+// PackageBackends returns all known backends
+func PackageBackends() []PackageBackend {
+	return packageBackends
+}
+
+// SystemBackends returns the backends available on this system, most
+// preferred first
+func SystemBackends() []PackageBackend {
+	var available []PackageBackend
+	for _, b := range packageBackends {
+		if b.Available() {
+			available = append(available, b)
+		}
+	}
+	return available
+}
+
+// GetBackend returns a backend by name
+func GetBackend(name string) (PackageBackend, bool) {
+	for _, b := range packageBackends {
+		if b.Name() == name {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// IsBackendName reports whether name is a known backend
+func IsBackendName(name string) bool {
+	_, ok := GetBackend(name)
+	return ok
+}
+
+// ValidateBackend checks a --backend value
+func ValidateBackend(name string) error {
+	if name == "" || name == BackendAuto || name == BackendDirect || IsBackendName(name) {
+		return nil
+	}
+	names := []string{BackendAuto, BackendDirect}
+	for _, b := range packageBackends {
+		names = append(names, b.Name())
+	}
+	return fmt.Errorf("unknown backend %s (valid: %s)", name, strings.Join(names, ", "))
+}
+
+// nativeBackend selects the system package manager installing pkg. With
+// backend "" or auto the first available manager with a native package
+// wins, unless the user chose a variant explicitly; a named backend must
+// be available and have a native package. It returns nil when the registry
+// variant should be installed.
+func nativeBackend(pkg *registry.Package, backend, variant string) (PackageBackend, []string, error) {
+	switch backend {
+	case BackendDirect:
+		return nil, nil, nil
+	case "", BackendAuto:
+		if variant != "" || len(pkg.Spec.Native) == 0 {
+			return nil, nil, nil
+		}
+		for _, b := range SystemBackends() {
+			if packages := pkg.Spec.Native[b.Name()]; len(packages) > 0 {
+				return b, packages, nil
+			}
+		}
+		return nil, nil, nil
+	}
+
+	b, ok := GetBackend(backend)
+	if !ok {
+		return nil, nil, ValidateBackend(backend)
+	}
+	packages := pkg.Spec.Native[backend]
+	if len(packages) == 0 {
+		return nil, nil, fmt.Errorf("package %s has no native %s package", pkg.Metadata.Name, backend)
+	}
+	if !b.Available() {
+		return nil, nil, fmt.Errorf("%s is not available on this system", backend)
+	}
+	return b, packages, nil
+}
+
+// NativeBackendNames returns the backends a package has native packages
+// for, sorted by name
+func NativeBackendNames(pkg *registry.Package) []string {
+	names := make([]string, 0, len(pkg.Spec.Native))
+	for name := range pkg.Spec.Native {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
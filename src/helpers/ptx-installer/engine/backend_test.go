@@ -0,0 +1,149 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
+)
+
+// fakeBackend records the packages it was asked to install
+type fakeBackend struct {
+	name      string
+	available bool
+	fail      bool
+	installed []string
+}
+
+func (b *fakeBackend) Name() string    { return b.name }
+func (b *fakeBackend) Available() bool { return b.available }
+func (b *fakeBackend) Install(packages []string) error {
+	if b.fail {
+		return errors.New("package not found")
+	}
+	b.installed = append(b.installed, packages...)
+	return nil
+}
+func (b *fakeBackend) Remove(packages []string) error { return nil }
+
+// useBackends replaces the known backends for one test
+func useBackends(t *testing.T, backends ...PackageBackend) {
+	previous := packageBackends
+	packageBackends = backends
+	t.Cleanup(func() { packageBackends = previous })
+}
+
+func TestNativeBackendSelection(t *testing.T) {
+	missing := &fakeBackend{name: "apt"}
+	present := &fakeBackend{name: "brew", available: true}
+	other := &fakeBackend{name: "winget", available: true}
+	useBackends(t, missing, present, other)
+	pkg := &registry.Package{Metadata: registry.Metadata{Name: "tool"}}
+	pkg.Spec.Native = map[string][]string{"apt": {"tool-apt"}, "brew": {"tool"}}
+
+	if b, packages, _ := nativeBackend(pkg, "", ""); b != present || packages[0] != "tool" {
+		t.Errorf("auto should pick the first available backend with a native package, got %v", b)
+	}
+	if b, _, _ := nativeBackend(pkg, BackendDirect, ""); b != nil {
+		t.Error("direct must not use a backend")
+	}
+	if b, _, _ := nativeBackend(pkg, BackendAuto, "stable"); b != nil {
+		t.Error("an explicit variant must not use a backend")
+	}
+	if _, _, err := nativeBackend(pkg, "apt", ""); err == nil || !strings.Contains(err.Error(), "not available") {
+		t.Errorf("unavailable backend should fail, got %v", err)
+	}
+	if _, _, err := nativeBackend(pkg, "winget", ""); err == nil || !strings.Contains(err.Error(), "no native") {
+		t.Errorf("backend without a native package should fail, got %v", err)
+	}
+	if err := ValidateBackend("snapcraft"); err == nil {
+		t.Error("unknown backend should be rejected")
+	}
+}
+
+func TestInstallNativeAndFallback(t *testing.T) {
+	t.Setenv(registry.EnvPackagesDir, t.TempDir())
+	t.Setenv(EnvInstalledDB, filepath.Join(t.TempDir(), "installed.json"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("tool"))
+	}))
+	defer server.Close()
+
+	assets := t.TempDir()
+	target := filepath.Join(t.TempDir(), "tool")
+	pkg := `{
+  "apiVersion": "v1",
+  "kind": "Package",
+  "metadata": {"name": "tool", "displayName": "Tool", "description": "test package", "category": "development/tools"},
+  "spec": {
+    "native": {"brew": ["tool"]},
+    "verification": {"command": "ptx-test-missing-tool --version"},
+    "platforms": {
+      "` + GetOperatingSystem() + `": {
+        "type": "download",
+        "variants": {"stable": {"version": "1.0.0", "url": "` + server.URL + `/tool", "extractTo": "` + filepath.ToSlash(target) + `"}}
+      }
+    }
+  }
+}`
+	os.MkdirAll(filepath.Join(assets, "packages"), 0755)
+	os.WriteFile(filepath.Join(assets, "packages", "tool.json"), []byte(pkg), 0644)
+	reg, err := registry.LoadPackageRegistry(assets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	installer := &Installer{registry: reg, cacheDir: t.TempDir(), assetsPath: assets}
+
+	brew := &fakeBackend{name: "brew", available: true}
+	useBackends(t, brew)
+	if err := installer.Install(&InstallOptions{PackageName: "tool"}); err != nil {
+		t.Fatalf("native install failed: %v", err)
+	}
+	state, _ := LoadInstallState()
+	record, ok := state.Get("tool")
+	if len(brew.installed) != 1 || !ok || record.Type != "brew" || record.Packages[0] != "tool" || record.Variant != "" {
+		t.Fatalf("native install not recorded: %+v", record)
+	}
+	if info := installer.CheckUpgrade(record); info.Upgradable || info.Detail != "managed by brew" {
+		t.Errorf("unexpected upgrade info %+v", info)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Error("the registry package must not be installed")
+	}
+
+	// A failing system package manager falls back to the registry package
+	brew.fail = true
+	if err := installer.Install(&InstallOptions{PackageName: "tool"}); err != nil {
+		t.Fatalf("fallback install failed: %v", err)
+	}
+	if _, err := os.Stat(target); err != nil {
+		t.Errorf("fallback should install the registry package: %v", err)
+	}
+
+	// Unless the backend was chosen explicitly
+	if err := installer.Install(&InstallOptions{PackageName: "tool", Backend: "brew"}); err == nil {
+		t.Error("an explicit backend must not fall back")
+	}
+}
+
+func TestNativeOnlyDescriptor(t *testing.T) {
+	valid := `{"apiVersion": "v1", "kind": "Package",
+  "metadata": {"name": "jq", "displayName": "jq", "description": "JSON processor", "category": "development/tools"},
+  "spec": {"native": {"apt": ["jq"], "brew": ["jq"]}}}`
+	if _, err := registry.ParsePackageDescriptor("jq.json", []byte(valid)); err != nil {
+		t.Errorf("a package with native packages only should be valid: %v", err)
+	}
+	invalid := strings.Replace(valid, `"brew"`, `"portage"`, 1)
+	if _, err := registry.ParsePackageDescriptor("jq.json", []byte(invalid)); err == nil {
+		t.Error("unknown native backends should be rejected")
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"portunix.ai/app/cache"
+)
+
+// EnvOffline makes every install behave as if --offline was given
+const EnvOffline = "PORTUNIX_OFFLINE"
+
+// ErrNotCached is returned by offline installs for artifacts missing from
+// the download cache
+var ErrNotCached = errors.New("not in the download cache")
+
+// OfflineFromEnv reports whether PORTUNIX_OFFLINE requests offline installs
+func OfflineFromEnv() bool {
+	value := strings.ToLower(os.Getenv(EnvOffline))
+	return value == "1" || value == "true" || value == "yes"
+}
+
+// fetchArtifact returns a local copy of url, reusing the download cache.
+// Fresh downloads are stored in the cache under the package label. Offline
+// installs accept expired cache entries and never touch the network.
+func (i *Installer) fetchArtifact(url string, options *InstallOptions, label string) (string, error) {
+	key := cache.CacheKey(url)
+	if i.cache != nil && i.cache.IsEnabled() {
+		path, _, _ := i.cache.Get(cache.CategoryDownloads, key)
+		if path == "" && options.Offline {
+			path, _, _ = i.cache.Lookup(cache.CategoryDownloads, key)
+		}
+		if path != "" {
+			fmt.Printf("♻️  Using cached %s\n", path)
+			return path, nil
+		}
+	}
+	if options.Offline {
+		return "", fmt.Errorf("%s: %w (offline mode)", url, ErrNotCached)
+	}
+
+	if i.cache == nil || !i.cache.IsEnabled() {
+		return DownloadFileWithProperFilename(url, i.cacheDir)
+	}
+
+	tmpDir, err := os.MkdirTemp(i.cacheDir, "download-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create download directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// The incomplete download stays in the cache to be resumed next time
+	downloaded, err := downloadToDir(url, tmpDir, i.cache.PartialPath(key))
+	if err != nil {
+		return "", err
+	}
+	meta, err := i.cache.StoreWithLabel(cache.CategoryDownloads, key, downloaded, url, label)
+	if err != nil {
+		return "", fmt.Errorf("failed to cache download: %w", err)
+	}
+	return i.cache.EntryPath(meta), nil
+}
+
+// artifactLabel names the package and version a cached download belongs to
+func artifactLabel(options *InstallOptions, variant string) string {
+	if variant == "" {
+		return options.PackageName
+	}
+	return options.PackageName + " " + variant
+}
+
+// copyArtifact copies a cached artifact to its install location
+func copyArtifact(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,76 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
+)
+
+// expectedChecksum returns the checksum of the main download of a variant.
+// Checksum is keyed by architecture like urls, with "default" for a single
+// url.
+func expectedChecksum(variant *registry.VariantSpec) string {
+	if checksum := variant.Checksum[GetArchitecture()]; checksum != "" {
+		return checksum
+	}
+	return variant.Checksum["default"]
+}
+
+// VerifyChecksum checks a file against "sha256:<hex>", "sha512:<hex>" or a
+// bare SHA-256 hex digest
+func VerifyChecksum(path, expected string) error {
+	algorithm, digest, found := strings.Cut(expected, ":")
+	if !found {
+		algorithm, digest = "sha256", expected
+	}
+	var h hash.Hash
+	switch strings.ToLower(algorithm) {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported checksum algorithm %q", algorithm)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := io.Copy(h, file); err != nil {
+		return err
+	}
+	actual := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(actual, strings.TrimSpace(digest)) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s:%s", path, expected, strings.ToLower(algorithm), actual)
+	}
+	return nil
+}
+
+// verifyDownload checks the main download of a variant when the package
+// declares a checksum for it. A file that fails is removed from the cache so
+// the next attempt downloads it again.
+func verifyDownload(path string, variant *registry.VariantSpec) error {
+	expected := expectedChecksum(variant)
+	if expected == "" {
+		return nil
+	}
+	if err := VerifyChecksum(path, expected); err != nil {
+		os.Remove(path)
+		return err
+	}
+	fmt.Println("🔐 Checksum verified")
+	return nil
+}
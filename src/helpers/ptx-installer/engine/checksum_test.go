@@ -0,0 +1,41 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
+)
+
+func TestVerifyChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool.tar.gz")
+	if err := os.WriteFile(path, []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	const sha256Hello = "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"
+
+	for _, expected := range []string{sha256Hello, "sha256:" + sha256Hello, "SHA256:5891B5B522D5DF086D0FF0B110FBD9D21BB4FC7163AF34D08286A2E846F6BE03"} {
+		if err := VerifyChecksum(path, expected); err != nil {
+			t.Errorf("VerifyChecksum(%q) failed: %v", expected, err)
+		}
+	}
+	if err := VerifyChecksum(path, "md5:b1946ac92492d2347c6235b4d2611184"); err == nil {
+		t.Error("unsupported algorithm should fail")
+	}
+
+	variant := &registry.VariantSpec{Checksum: map[string]string{"default": "sha256:0000"}}
+	if err := verifyDownload(path, variant); err == nil {
+		t.Fatal("mismatching checksum should fail")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("a file with a wrong checksum should be removed")
+	}
+	if err := verifyDownload(path, &registry.VariantSpec{}); err != nil {
+		t.Errorf("variants without checksum should not be verified: %v", err)
+	}
+}
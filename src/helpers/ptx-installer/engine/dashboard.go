@@ -0,0 +1,160 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dashboard shows the state of parallel installations. On a terminal the
+// task table is redrawn in place; otherwise every state change is printed
+// as a line, which keeps logs of CI runs readable.
+type Dashboard struct {
+	mu      sync.Mutex
+	out     io.Writer
+	live    bool
+	order   []string
+	tasks   map[string]*dashboardTask
+	drawn   int
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+type dashboardTask struct {
+	status  string
+	detail  string
+	logPath string
+	started time.Time
+	elapsed time.Duration
+}
+
+// NewDashboard creates a dashboard for tasks, drawing live when stdout is a
+// terminal
+func NewDashboard(tasks []*InstallTask) *Dashboard {
+	d := &Dashboard{out: os.Stdout, live: isTerminal(os.Stdout), tasks: make(map[string]*dashboardTask)}
+	for _, task := range tasks {
+		d.order = append(d.order, task.Name)
+		d.tasks[task.Name] = &dashboardTask{status: TaskWaiting, logPath: task.LogPath}
+	}
+	return d
+}
+
+// isTerminal reports whether f is a character device
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Start begins the periodic redraw of a live dashboard
+func (d *Dashboard) Start() {
+	if !d.live {
+		return
+	}
+	d.stop, d.stopped = make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(d.stopped)
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				d.mu.Lock()
+				d.draw()
+				d.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// Stop ends the redraw and prints the final table
+func (d *Dashboard) Stop() {
+	if d.stop != nil {
+		close(d.stop)
+		<-d.stopped
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.live {
+		d.draw()
+	}
+}
+
+// Update records a task state change; it is a ProgressFunc
+func (d *Dashboard) Update(name, status, detail string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	task, ok := d.tasks[name]
+	if !ok {
+		return
+	}
+	task.status, task.detail = status, detail
+	switch status {
+	case TaskRunning:
+		task.started = time.Now()
+	case TaskDone, TaskFailed:
+		if !task.started.IsZero() {
+			task.elapsed = time.Since(task.started)
+		}
+	}
+
+	if d.live {
+		d.draw()
+	} else if status != TaskWaiting {
+		fmt.Fprintln(d.out, d.line(name, task))
+	}
+}
+
+// draw rewrites the task table over the previous one
+func (d *Dashboard) draw() {
+	if d.drawn > 0 {
+		fmt.Fprintf(d.out, "\033[%dA", d.drawn)
+	}
+	for _, name := range d.order {
+		fmt.Fprintf(d.out, "\r\033[K%s\n", d.line(name, d.tasks[name]))
+	}
+	d.drawn = len(d.order)
+}
+
+func (d *Dashboard) line(name string, task *dashboardTask) string {
+	icons := map[string]string{
+		TaskWaiting: "⏸️ ",
+		TaskRunning: "⏳",
+		TaskDone:    "✅",
+		TaskFailed:  "❌",
+		TaskSkipped: "⏭️ ",
+	}
+	elapsed := task.elapsed
+	if task.status == TaskRunning {
+		elapsed = time.Since(task.started)
+	}
+
+	line := fmt.Sprintf("  %s %-20s %-8s", icons[task.status], name, task.status)
+	switch task.status {
+	case TaskRunning, TaskDone:
+		line += fmt.Sprintf(" %s", formatDuration(elapsed.Seconds()))
+	case TaskFailed:
+		line += " " + firstLine(task.detail)
+		if task.logPath != "" {
+			line += " (log: " + task.logPath + ")"
+		}
+	case TaskSkipped:
+		line += " needs " + task.detail
+	}
+	return line
+}
+
+func firstLine(s string) string {
+	if idx := strings.IndexByte(s, '\n'); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
@@ -6,83 +6,17 @@ package engine
 
 import (
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
-)
-
-// ProgressWriter wraps an io.Writer to display download progress
-type ProgressWriter struct {
-	Total      int64
-	Downloaded int64
-	LastUpdate time.Time
-	StartTime  time.Time
-}
-
-// Write implements io.Writer interface with progress display
-func (pw *ProgressWriter) Write(p []byte) (int, error) {
-	n := len(p)
-	pw.Downloaded += int64(n)
 
-	// Update progress every 100ms to avoid too frequent updates
-	if time.Since(pw.LastUpdate) > 100*time.Millisecond {
-		pw.displayProgress()
-		pw.LastUpdate = time.Now()
-	}
-
-	return n, nil
-}
-
-// displayProgress shows current download progress
-func (pw *ProgressWriter) displayProgress() {
-	if pw.Total <= 0 {
-		// Unknown size - just show downloaded amount
-		fmt.Printf("\r⏳ Downloaded: %s", formatBytes(pw.Downloaded))
-		return
-	}
-
-	percent := float64(pw.Downloaded) / float64(pw.Total) * 100
-	elapsed := time.Since(pw.StartTime).Seconds()
-
-	// Calculate speed and ETA
-	var speedStr, etaStr string
-	if elapsed > 0 {
-		speed := float64(pw.Downloaded) / elapsed
-		speedStr = fmt.Sprintf("%s/s", formatBytes(int64(speed)))
-
-		if speed > 0 {
-			remaining := float64(pw.Total-pw.Downloaded) / speed
-			etaStr = formatDuration(remaining)
-		}
-	}
-
-	// Progress bar (20 chars wide)
-	barWidth := 20
-	filled := int(percent / 100 * float64(barWidth))
-	bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
-
-	fmt.Printf("\r⏳ [%s] %.1f%% (%s / %s) %s %s   ",
-		bar, percent,
-		formatBytes(pw.Downloaded), formatBytes(pw.Total),
-		speedStr, etaStr)
-}
-
-// Finish completes the progress display
-func (pw *ProgressWriter) Finish() {
-	if pw.Total > 0 {
-		elapsed := time.Since(pw.StartTime).Seconds()
-		speed := float64(pw.Downloaded) / elapsed
-		fmt.Printf("\r✅ Downloaded: %s in %s (%s/s)                    \n",
-			formatBytes(pw.Downloaded),
-			formatDuration(elapsed),
-			formatBytes(int64(speed)))
-	} else {
-		fmt.Printf("\r✅ Downloaded: %s                    \n", formatBytes(pw.Downloaded))
-	}
-}
+	"portunix.ai/app/cache"
+	"portunix.ai/app/fetch"
+	"portunix.ai/app/mirror"
+	"portunix.ai/app/progress"
+)
 
 // formatDuration formats seconds to human-readable duration
 func formatDuration(seconds float64) string {
@@ -99,76 +33,79 @@ func formatDuration(seconds float64) string {
 	return fmt.Sprintf("%dh %dm", hours, minutes)
 }
 
-// DownloadFile downloads a file from URL to the specified filepath with progress
-func DownloadFile(destPath string, url string) error {
-	fmt.Printf("📥 Downloading from: %s\n", url)
-
-	// Create the file
-	out, err := os.Create(destPath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer out.Close()
-
-	// Get the data
-	resp, err := http.Get(url)
-	if err != nil {
-		return fmt.Errorf("failed to download: %w", err)
-	}
-	defer resp.Body.Close()
+// fetchOptions reports download progress and retries on stdout
+func fetchOptions(reporter *progress.Reporter) fetch.Options {
+	return fetch.Options{Reporter: reporter, Log: os.Stdout}
+}
 
-	// Check server response
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
+// downloadSummary reports a finished download with its size and speed
+func downloadSummary(reporter *progress.Reporter, result *fetch.Result, start time.Time) {
+	elapsed := time.Since(start).Seconds()
+	summary := formatBytes(result.Size)
+	if transferred := result.Size - result.Resumed; transferred > 0 && elapsed > 0 {
+		summary += fmt.Sprintf(" in %s (%s/s)", formatDuration(elapsed), formatBytes(int64(float64(transferred)/elapsed)))
 	}
-
-	// Show file size
-	size := resp.ContentLength
-	if size > 0 {
-		fmt.Printf("📦 Size: %s\n", formatBytes(size))
+	if result.Resumed > 0 {
+		summary += fmt.Sprintf(", resumed after %s", formatBytes(result.Resumed))
 	}
+	reporter.Done("download", summary)
+}
 
-	// Create progress writer
-	progress := &ProgressWriter{
-		Total:      size,
-		StartTime:  time.Now(),
-		LastUpdate: time.Now(),
+// DownloadFile downloads a file from URL to the specified filepath with
+// progress, retrying and resuming interrupted transfers
+func DownloadFile(destPath string, url string) error {
+	urls, err := mirrorURLs(url)
+	if err != nil {
+		return err
 	}
+	fmt.Printf("📥 Downloading from: %s\n", urls[0])
 
-	// Download with progress
-	_, err = io.Copy(out, io.TeeReader(resp.Body, progress))
+	reporter := progress.NewReporter(os.Stdout)
+	start := time.Now()
+	result, err := fetch.File(urls, destPath, fetchOptions(reporter))
 	if err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+		return err
 	}
-
-	progress.Finish()
+	downloadSummary(reporter, result, start)
 	return nil
 }
 
 // DownloadFileWithProperFilename downloads a file and determines the proper filename
 func DownloadFileWithProperFilename(url string, cacheDir string) (string, error) {
-	// Ensure cache directory exists
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+	return downloadToDir(url, cacheDir, filepath.Join(cacheDir, ".partial", cache.CacheKey(url)))
+}
+
+// downloadToDir downloads url into dir under the name the server gives it.
+// The incomplete file is kept at partial, where a later download of the
+// same URL resumes it.
+func downloadToDir(url, dir, partial string) (string, error) {
+	// Ensure the directory exists
+	if err := os.MkdirAll(dir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
-	// Get the response to check headers
-	resp, err := http.Get(url)
+	urls, err := mirrorURLs(url)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch URL: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
+	fmt.Printf("📥 Downloading from: %s\n", urls[0])
 
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("bad status: %s", resp.Status)
+	reporter := progress.NewReporter(os.Stdout)
+	start := time.Now()
+	result, err := fetch.Download(urls, partial, fetchOptions(reporter))
+	if err != nil {
+		return "", err
 	}
+	downloadSummary(reporter, result, start)
 
 	// Extract filename from response
-	filename := ExtractFilenameFromResponse(resp)
+	filename := ""
+	if result.Response != nil {
+		filename = ExtractFilenameFromResponse(result.Response)
+	}
 	if filename == "" {
 		// Fallback: extract from URL
-		parts := strings.Split(url, "/")
+		parts := strings.Split(result.URL, "/")
 		if len(parts) > 0 {
 			filename = parts[len(parts)-1]
 		}
@@ -177,38 +114,24 @@ func DownloadFileWithProperFilename(url string, cacheDir string) (string, error)
 		}
 	}
 
-	// Create full path
-	destPath := filepath.Join(cacheDir, filename)
-
-	// Create file
-	out, err := os.Create(destPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to create file: %w", err)
-	}
-	defer out.Close()
-
-	// Show download info
-	fmt.Printf("📥 Downloading: %s\n", filename)
-	size := resp.ContentLength
-	if size > 0 {
-		fmt.Printf("📦 Size: %s\n", formatBytes(size))
-	}
-
-	// Create progress writer
-	progress := &ProgressWriter{
-		Total:      size,
-		StartTime:  time.Now(),
-		LastUpdate: time.Now(),
+	destPath := filepath.Join(dir, filename)
+	if err := os.Rename(partial, destPath); err != nil {
+		return "", fmt.Errorf("failed to move download to %s: %w", destPath, err)
 	}
+	return destPath, nil
+}
 
-	// Download with progress
-	_, err = io.Copy(out, io.TeeReader(resp.Body, progress))
+// mirrorURLs returns the mirrors of the active network profile for a
+// download, followed by the original URL as failover
+func mirrorURLs(url string) ([]string, error) {
+	urls, err := mirror.DownloadURLs(url)
 	if err != nil {
-		return "", fmt.Errorf("failed to write file: %w", err)
+		return nil, err
 	}
-
-	progress.Finish()
-	return destPath, nil
+	if urls[0] != url {
+		fmt.Printf("🪞 Using mirror: %s\n", urls[0])
+	}
+	return urls, nil
 }
 
 // ExtractFilenameFromResponse extracts filename from HTTP response
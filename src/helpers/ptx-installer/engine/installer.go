@@ -16,7 +16,9 @@ import (
 	"strings"
 	"time"
 
+	"portunix.ai/app/cache"
 	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
+	"portunix.ai/portunix/src/pkg/platform"
 )
 
 // EmbeddedScriptsFS holds the embedded scripts filesystem (set from main package)
@@ -121,6 +123,10 @@ func expandEnvVars(s string) string {
 			if runtime.GOOS == "windows" {
 				return os.Getenv("USERPROFILE")
 			}
+		case "HOMEBREW_PREFIX":
+			if prefix := platform.HomebrewPrefix(); prefix != "" {
+				return prefix
+			}
 		}
 		return match // Return original if not found
 	})
@@ -145,6 +151,9 @@ type InstallOptions struct {
 	InstallPath string // Target path for packages that require it (e.g., docusaurus)
 	DryRun      bool
 	Force       bool
+	// Offline installs only from the download cache and fails for
+	// artifacts that were never downloaded
+	Offline bool
 	// Database connection overrides for container-type installs that read
 	// PostgreSQL-style env keys (HOST, PORT, USER, PASSWORD). Empty values
 	// leave the variant's JSON defaults untouched.
@@ -152,6 +161,15 @@ type InstallOptions struct {
 	DBPort     string
 	DBUser     string
 	DBPassword string
+
+	// Backend is auto (default), direct or a system package manager name
+	// (apt, dnf, pacman, zypper, brew, winget, chocolatey)
+	Backend string
+
+	// Filled in by the installation for the installed packages database
+	installDir string
+	created    []string
+	systemPkgs []string
 }
 
 // Installer handles package installation operations
@@ -159,6 +177,7 @@ type Installer struct {
 	registry   *registry.PackageRegistry
 	cacheDir   string
 	assetsPath string
+	cache      *cache.Manager
 }
 
 // NewInstaller creates a new installer instance
@@ -184,6 +203,7 @@ func NewInstaller(assetsPath string) (*Installer, error) {
 		registry:   reg,
 		cacheDir:   cacheDir,
 		assetsPath: assetsPath,
+		cache:      cache.NewManager(),
 	}, nil
 }
 
@@ -206,14 +226,26 @@ func (i *Installer) Install(options *InstallOptions) error {
 
 	// Check if package supports current platform
 	platformSpec, exists := pkg.Spec.Platforms[currentOS]
-	if !exists {
+	if !exists && currentOS == "windows_sandbox" {
 		// Try fallback for windows_sandbox
-		if currentOS == "windows_sandbox" {
-			platformSpec, exists = pkg.Spec.Platforms["windows"]
-		}
-		if !exists {
-			return fmt.Errorf("package %s not available for platform %s", options.PackageName, currentOS)
+		platformSpec, exists = pkg.Spec.Platforms["windows"]
+	}
+
+	// Prefer a native package of the system package manager
+	backend, nativePackages, err := nativeBackend(pkg, options.Backend, options.Variant)
+	if err != nil {
+		return err
+	}
+	if backend != nil {
+		err := i.installNative(pkg, backend, nativePackages, options)
+		if err == nil || !exists || options.Backend == backend.Name() {
+			return err
 		}
+		fmt.Printf("⚠️  %s installation failed: %v\n", backend.Name(), err)
+		fmt.Println("   Falling back to the registry package")
+	}
+	if !exists {
+		return fmt.Errorf("package %s not available for platform %s", options.PackageName, currentOS)
 	}
 
 	// Determine variant to install
@@ -264,6 +296,9 @@ func (i *Installer) Install(options *InstallOptions) error {
 				fmt.Printf("     - %s\n", af.URL)
 			}
 		}
+		if options.Offline {
+			fmt.Println("   Offline: only cached downloads will be used")
+		}
 
 		return nil
 	}
@@ -289,32 +324,97 @@ func (i *Installer) Install(options *InstallOptions) error {
 
 	// Perform installation based on effective type (variant type takes precedence)
 	fmt.Printf("\n🚀 Starting installation (type: %s)...\n", effectiveType)
+	if options.Offline {
+		switch effectiveType {
+		case "tar.gz", "zip", "deb", "msi", "exe", "download":
+			fmt.Println("📴 Offline mode: using cached downloads only")
+		default:
+			fmt.Printf("⚠️  Offline mode: %s installs are handled by external tools and may still need network access\n", effectiveType)
+		}
+	}
+
+	if err := i.installByType(effectiveType, &platformSpec, &variantSpec, options); err != nil {
+		return err
+	}
+
+	// Run the package verification command, if it declares one
+	installedVersion := variantSpec.Version
+	if hasVerification(pkg) {
+		if ok, version := i.runVerification(pkg, options.InstallPath); ok {
+			fmt.Printf("🔎 Verified %s %s\n", pkg.Metadata.Name, version)
+			// Variants like "latest" are recorded with the version found
+			if version != "" && !numericVersion.MatchString(installedVersion) {
+				installedVersion = version
+			}
+		} else {
+			fmt.Printf("⚠️  Verification of %s failed; a new shell may be needed to pick up PATH changes\n", pkg.Metadata.Name)
+		}
+	}
+
+	if err := i.recordInstall(pkg, variant, installedVersion, effectiveType, &variantSpec, options); err != nil {
+		fmt.Printf("⚠️  Failed to record installation: %v\n", err)
+	}
+	return nil
+}
 
+// installNative installs a package through a system package manager and
+// records it with the manager as installation type
+func (i *Installer) installNative(pkg *registry.Package, backend PackageBackend, packages []string, options *InstallOptions) error {
+	fmt.Printf("🎯 Backend: %s (%s)\n", backend.Name(), strings.Join(packages, ", "))
+	if options.DryRun {
+		fmt.Println("\n🔍 DRY RUN MODE - No actual installation will be performed")
+		fmt.Printf("   Would install: %s\n", pkg.Metadata.Name)
+		fmt.Printf("   Via %s: %s\n", backend.Name(), strings.Join(packages, " "))
+		return nil
+	}
+	if options.Offline {
+		fmt.Printf("⚠️  Offline mode: %s installs may still need network access\n", backend.Name())
+	}
+
+	fmt.Printf("\n🚀 Starting installation (backend: %s)...\n", backend.Name())
+	if err := backend.Install(packages); err != nil {
+		return err
+	}
+
+	version := "system"
+	if ok, detected := i.InstalledVersion(pkg); ok && detected != "" {
+		fmt.Printf("🔎 Verified %s %s\n", pkg.Metadata.Name, detected)
+		version = detected
+	}
+	options.systemPkgs = packages
+	if err := i.recordInstall(pkg, "", version, backend.Name(), &registry.VariantSpec{}, options); err != nil {
+		fmt.Printf("⚠️  Failed to record installation: %v\n", err)
+	}
+	return nil
+}
+
+// installByType runs the installer for an installation type
+func (i *Installer) installByType(effectiveType string, platformSpec *registry.PlatformSpec, variantSpec *registry.VariantSpec, options *InstallOptions) error {
 	switch effectiveType {
 	case "tar.gz", "zip":
-		return i.installArchive(&platformSpec, &variantSpec, options)
+		return i.installArchive(platformSpec, variantSpec, options)
 	case "deb":
-		return i.installDeb(&platformSpec, &variantSpec, options)
+		return i.installDeb(platformSpec, variantSpec, options)
 	case "apt":
-		return i.installApt(&platformSpec, &variantSpec, options)
+		return i.installApt(platformSpec, variantSpec, options)
 	case "dnf", "yum":
-		return i.installDnf(&platformSpec, &variantSpec, options)
+		return i.installDnf(platformSpec, variantSpec, options)
 	case "snap":
-		return i.installSnap(&platformSpec, &variantSpec, options)
+		return i.installSnap(platformSpec, variantSpec, options)
 	case "pacman":
-		return i.installPacman(&platformSpec, &variantSpec, options)
+		return i.installPacman(platformSpec, variantSpec, options)
 	case "msi", "exe":
-		return i.installWindowsBinary(&platformSpec, &variantSpec, options)
+		return i.installWindowsBinary(platformSpec, variantSpec, options)
 	case "chocolatey":
-		return i.installChocolatey(&platformSpec, &variantSpec, options)
+		return i.installChocolatey(platformSpec, variantSpec, options)
 	case "winget":
-		return i.installWinget(&platformSpec, &variantSpec, options)
+		return i.installWinget(platformSpec, variantSpec, options)
 	case "download":
-		return i.installDownload(&platformSpec, &variantSpec, options)
+		return i.installDownload(platformSpec, variantSpec, options)
 	case "script":
-		return i.installScript(&platformSpec, &variantSpec, options)
+		return i.installScript(platformSpec, variantSpec, options)
 	case "container":
-		return i.installContainer(&platformSpec, &variantSpec, options)
+		return i.installContainer(platformSpec, variantSpec, options)
 	default:
 		return fmt.Errorf("installation type %s not yet implemented in ptx-installer", effectiveType)
 	}
@@ -340,10 +440,13 @@ func (i *Installer) installArchive(platform *registry.PlatformSpec, variant *reg
 
 	// Download archive to cache
 	fmt.Printf("📥 Downloading archive from: %s\n", downloadURL)
-	archivePath, err := DownloadFileWithProperFilename(downloadURL, i.cacheDir)
+	archivePath, err := i.fetchArtifact(downloadURL, options, artifactLabel(options, variant.Version))
 	if err != nil {
 		return fmt.Errorf("download failed: %w", err)
 	}
+	if err := verifyDownload(archivePath, variant); err != nil {
+		return err
+	}
 
 	// Determine extraction directory (expand environment variables)
 	extractTo := expandEnvVars(variant.ExtractTo)
@@ -364,11 +467,13 @@ func (i *Installer) installArchive(platform *registry.PlatformSpec, variant *reg
 	}
 
 	// Ensure extract directory exists, with fallback to user directory
+	before := snapshotDir(extractTo)
 	if err := os.MkdirAll(extractTo, 0755); err != nil {
 		// Primary path failed (likely permission denied), try fallback
 		if extractTo != fallbackDir {
 			fmt.Printf("⚠️  Cannot create %s (permission denied), using user directory\n", extractTo)
 			extractTo = fallbackDir
+			before = snapshotDir(extractTo)
 			if err := os.MkdirAll(extractTo, 0755); err != nil {
 				return fmt.Errorf("failed to create extract directory: %w", err)
 			}
@@ -386,12 +491,17 @@ func (i *Installer) installArchive(platform *registry.PlatformSpec, variant *reg
 	if !proceed {
 		return nil // User cancelled installation
 	}
+	if _, err := os.Stat(extractTo); os.IsNotExist(err) {
+		before = nil // the previous installation was removed
+	}
 
 	// Extract archive
 	fmt.Printf("📦 Extracting to: %s\n", extractTo)
 	if err := ExtractArchive(archivePath, extractTo); err != nil {
 		return fmt.Errorf("extraction failed: %w", err)
 	}
+	options.installDir = extractTo
+	options.created = createdPaths(extractTo, before)
 
 	// Find actual root directory (many archives have a single top-level directory)
 	actualRoot, err := FindExtractedRoot(extractTo)
@@ -522,6 +632,7 @@ func (i *Installer) installDownload(platform *registry.PlatformSpec, variant *re
 	}
 
 	// Ensure target directory exists
+	before := snapshotDir(targetDir)
 	if err := os.MkdirAll(targetDir, 0755); err != nil {
 		return fmt.Errorf("failed to create target directory %s: %w", targetDir, err)
 	}
@@ -569,9 +680,19 @@ func (i *Installer) installDownload(platform *registry.PlatformSpec, variant *re
 	for idx, dl := range downloads {
 		destPath := filepath.Join(targetDir, dl.filename)
 		fmt.Printf("\n[%d/%d] %s\n", idx+1, len(downloads), dl.filename)
-		if err := DownloadFile(destPath, dl.url); err != nil {
+		cached, err := i.fetchArtifact(dl.url, options, artifactLabel(options, variant.Version))
+		if err != nil {
 			return fmt.Errorf("failed to download %s: %w", dl.filename, err)
 		}
+		if err := copyArtifact(cached, destPath); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", dl.filename, err)
+		}
+		// The checksum covers the main file; additional files follow it
+		if dl.url == downloadURL {
+			if err := verifyDownload(destPath, variant); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Run post-install commands if specified
@@ -598,6 +719,8 @@ func (i *Installer) installDownload(platform *registry.PlatformSpec, variant *re
 		}
 	}
 
+	options.installDir = targetDir
+	options.created = createdPaths(targetDir, before)
 	fmt.Printf("\n✅ Downloaded %d file(s) to %s\n", len(downloads), targetDir)
 	return nil
 }
@@ -622,12 +745,18 @@ func (i *Installer) installDeb(platform *registry.PlatformSpec, variant *registr
 
 	// Download .deb file to cache
 	fmt.Printf("📥 Downloading .deb package from: %s\n", downloadURL)
-	debPath, err := DownloadFileWithProperFilename(downloadURL, i.cacheDir)
+	debPath, err := i.fetchArtifact(downloadURL, options, artifactLabel(options, variant.Version))
 	if err != nil {
 		return fmt.Errorf("download failed: %w", err)
 	}
+	if err := verifyDownload(debPath, variant); err != nil {
+		return err
+	}
 
 	// Install using dpkg
+	if name := debPackageName(debPath); name != "" {
+		options.systemPkgs = []string{name}
+	}
 	return InstallDebPackage(debPath)
 }
 
@@ -961,11 +1090,14 @@ func (i *Installer) installWindowsBinary(platform *registry.PlatformSpec, varian
 
 	// Download installer to cache
 	fmt.Printf("📥 Downloading installer from: %s\n", downloadURL)
-	installerPath, err := DownloadFileWithProperFilename(downloadURL, i.cacheDir)
+	installerPath, err := i.fetchArtifact(downloadURL, options, artifactLabel(options, variant.Version))
 	if err != nil {
 		return fmt.Errorf("download failed: %w", err)
 	}
 	fmt.Printf("✅ Downloaded to: %s\n", installerPath)
+	if err := verifyDownload(installerPath, variant); err != nil {
+		return err
+	}
 
 	// Determine installation type and run installer
 	if strings.HasSuffix(strings.ToLower(installerPath), ".msi") {
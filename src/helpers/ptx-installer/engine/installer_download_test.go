@@ -5,12 +5,14 @@
 package engine
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"portunix.ai/app/cache"
 	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
 )
 
@@ -160,3 +162,50 @@ func TestInstallDownload_NoURLError(t *testing.T) {
 		t.Error("expected error for variant with no URL and no additional files")
 	}
 }
+
+func TestInstallDownload_CacheAndOffline(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("cached-tool"))
+	}))
+	defer server.Close()
+
+	cfg := cache.DefaultConfig()
+	cfg.BaseDir = t.TempDir()
+	installer := &Installer{
+		registry: &registry.PackageRegistry{},
+		cacheDir: t.TempDir(),
+		cache:    cache.NewManagerWithConfig(cfg),
+	}
+	platform := &registry.PlatformSpec{Type: "download"}
+	variant := &registry.VariantSpec{Version: "1.0.0", URL: server.URL + "/tool.bin"}
+
+	for _, offline := range []bool{false, false, true} {
+		variant.ExtractTo = t.TempDir()
+		options := &InstallOptions{PackageName: "tool", Offline: offline}
+		if err := installer.installDownload(platform, variant, options); err != nil {
+			t.Fatalf("installDownload (offline=%v) failed: %v", offline, err)
+		}
+		if content, _ := os.ReadFile(filepath.Join(variant.ExtractTo, "tool.bin")); string(content) != "cached-tool" {
+			t.Errorf("file content mismatch: %q", content)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected a single download, got %d", requests)
+	}
+
+	entries, _ := installer.cache.ListEntries(cache.CategoryDownloads)
+	if len(entries) != 1 || entries[0].Label != "tool 1.0.0" || entries[0].Source != variant.URL {
+		t.Errorf("unexpected cache entries %+v", entries)
+	}
+
+	variant.URL = server.URL + "/other.bin"
+	err := installer.installDownload(platform, variant, &InstallOptions{PackageName: "tool", Offline: true})
+	if !errors.Is(err, ErrNotCached) {
+		t.Errorf("offline install of an uncached file should fail with ErrNotCached, got %v", err)
+	}
+	if requests != 1 {
+		t.Error("offline install must not download")
+	}
+}
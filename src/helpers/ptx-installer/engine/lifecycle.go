@@ -0,0 +1,259 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"portunix.ai/app/journal"
+	"portunix.ai/app/update"
+	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
+)
+
+// ErrNotInstalled is returned for packages missing from the installed
+// packages database
+var ErrNotInstalled = errors.New("not installed by portunix")
+
+// systemPackageTypes are the installation types owned by a system package
+// manager, removed through the same manager
+var systemPackageTypes = map[string]bool{
+	"apt": true, "deb": true, "dnf": true, "yum": true, "snap": true,
+	"pacman": true, "zypper": true, "brew": true, "chocolatey": true, "winget": true,
+}
+
+// recordInstall stores a finished installation in the installed packages
+// database
+func (i *Installer) recordInstall(pkg *registry.Package, variant, version, installType string, variantSpec *registry.VariantSpec, options *InstallOptions) error {
+	record := &InstalledPackage{
+		Name:       pkg.Metadata.Name,
+		Variant:    variant,
+		Version:    version,
+		Type:       installType,
+		InstallDir: options.installDir,
+		Paths:      options.created,
+		Packages:   options.systemPkgs,
+		Source:     pkg.Source,
+	}
+	if len(record.Packages) == 0 && systemPackageTypes[installType] {
+		record.Packages = variantSpec.Packages
+	}
+	upgrade := false
+	err := UpdateInstallState(func(state *InstallState) error {
+		_, upgrade = state.Get(record.Name)
+		state.Record(record)
+		return nil
+	})
+	// Undoing an upgrade would remove the package, so only new installs are
+	// journaled
+	if err == nil && !upgrade {
+		op := journal.Current("ptx-installer")
+		op.PackageInstalled(record.Name, record.Version)
+		if err := op.Commit(); err != nil {
+			fmt.Printf("⚠️  Failed to journal installation: %v\n", err)
+		}
+	}
+	return err
+}
+
+// Uninstall removes a package installed by portunix and forgets it. The
+// uninstall commands of the package definition take precedence, then
+// system packages are removed through their package manager and files
+// through the paths recorded at install time. With dryRun only the plan is
+// printed.
+func (i *Installer) Uninstall(name string, dryRun bool) error {
+	state, err := LoadInstallState()
+	if err != nil {
+		return err
+	}
+	record, ok := state.Get(name)
+	if !ok {
+		return fmt.Errorf("%s: %w", name, ErrNotInstalled)
+	}
+	fmt.Printf("\n🗑️  Uninstalling %s %s (variant: %s, type: %s)\n", record.Name, record.Version, record.Variant, record.Type)
+
+	commands := i.uninstallCommands(record)
+	switch {
+	case len(commands) > 0:
+		for _, command := range commands {
+			command = strings.ReplaceAll(command, "${install_path}", record.InstallDir)
+			command = strings.ReplaceAll(command, "%install_path%", record.InstallDir)
+			command = expandEnvVars(command)
+			fmt.Printf("   Running: %s\n", command)
+			if dryRun {
+				continue
+			}
+			if err := runShell(command); err != nil {
+				return fmt.Errorf("uninstall command failed: %s (error: %w)", command, err)
+			}
+		}
+	case systemPackageTypes[record.Type] && len(record.Packages) > 0:
+		if dryRun {
+			fmt.Printf("   Would remove %s packages: %s\n", record.Type, strings.Join(record.Packages, ", "))
+			break
+		}
+		if err := RemoveSystemPackages(record.Type, record.Packages); err != nil {
+			return err
+		}
+	case len(record.Paths) > 0:
+		if err := removeInstalledPaths(record, dryRun); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%s (%s installation) cannot be removed automatically; add uninstall commands to its package definition", name, record.Type)
+	}
+
+	if dryRun {
+		fmt.Println("\n🔍 DRY RUN MODE - Nothing was removed")
+		return nil
+	}
+	return UpdateInstallState(func(state *InstallState) error {
+		state.Remove(name)
+		return nil
+	})
+}
+
+// uninstallCommands returns the uninstall commands declared for the
+// installed variant of a package
+func (i *Installer) uninstallCommands(record *InstalledPackage) []string {
+	pkg, err := i.registry.GetPackage(record.Name)
+	if err != nil {
+		return nil
+	}
+	platform, ok := currentPlatform(pkg)
+	if !ok {
+		return nil
+	}
+	return platform.Variants[record.Variant].Uninstall
+}
+
+// removeInstalledPaths deletes the files and directories created by an
+// installation, then the install directory when nothing else is left in it
+func removeInstalledPaths(record *InstalledPackage, dryRun bool) error {
+	for _, path := range record.Paths {
+		if isProtectedPath(path) {
+			return fmt.Errorf("refusing to remove %s", path)
+		}
+	}
+	for _, path := range record.Paths {
+		fmt.Printf("   Removing %s\n", path)
+		if dryRun {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	if record.InstallDir != "" && !dryRun && !isProtectedPath(record.InstallDir) {
+		// Fails while the directory still holds files of other packages
+		os.Remove(record.InstallDir)
+	}
+	return nil
+}
+
+// isProtectedPath reports paths never removed by uninstall: file system
+// roots, top-level directories and the home directory
+func isProtectedPath(path string) bool {
+	if path == "" {
+		return true
+	}
+	clean := filepath.Clean(path)
+	if home, err := os.UserHomeDir(); err == nil && clean == filepath.Clean(home) {
+		return true
+	}
+	rest := strings.Trim(strings.TrimPrefix(filepath.ToSlash(clean), filepath.ToSlash(filepath.VolumeName(clean))), "/")
+	return !strings.Contains(rest, "/")
+}
+
+// runShell runs a command through the platform shell
+func runShell(command string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// UpgradeInfo compares an installed package with the registry
+type UpgradeInfo struct {
+	Name      string `json:"name"`
+	Variant   string `json:"variant"`
+	Installed string `json:"installed"`
+	Available string `json:"available,omitempty"`
+	// Upgradable is set when the registry has a newer version of the variant
+	Upgradable bool   `json:"upgradable"`
+	Changelog  string `json:"changelog,omitempty"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+var numericVersion = regexp.MustCompile(`^v?\d+(\.\d+)*`)
+
+// CheckUpgrade compares an installed package with the version its variant
+// has in the registry. Versions like "latest" cannot be compared and are
+// never reported as upgradable.
+func (i *Installer) CheckUpgrade(record *InstalledPackage) UpgradeInfo {
+	info := UpgradeInfo{Name: record.Name, Variant: record.Variant, Installed: record.Version}
+	pkg, err := i.registry.GetPackage(record.Name)
+	if err != nil {
+		info.Detail = "no longer in the package registry"
+		return info
+	}
+	if record.Variant == "" && IsBackendName(record.Type) {
+		info.Detail = fmt.Sprintf("managed by %s", record.Type)
+		return info
+	}
+	platform, ok := currentPlatform(pkg)
+	if !ok {
+		info.Detail = fmt.Sprintf("not available for %s", GetOperatingSystem())
+		return info
+	}
+	spec, ok := platform.Variants[record.Variant]
+	if !ok {
+		info.Detail = fmt.Sprintf("variant %s is no longer available", record.Variant)
+		return info
+	}
+	info.Available = spec.Version
+
+	switch {
+	case !numericVersion.MatchString(spec.Version) || !numericVersion.MatchString(record.Version):
+		info.Detail = "versions cannot be compared"
+	case update.CompareVersions(spec.Version, record.Version) > 0:
+		info.Upgradable = true
+		info.Changelog = spec.Changelog
+		info.Detail = fmt.Sprintf("%s -> %s", record.Version, spec.Version)
+	default:
+		info.Detail = "up to date"
+	}
+	return info
+}
+
+// Upgrade reinstalls a package with the registry version of its variant.
+// The previous installation is replaced when portunix created its whole
+// install directory.
+func (i *Installer) Upgrade(record *InstalledPackage, install func(*InstallOptions) error) error {
+	if install == nil {
+		install = i.Install
+	}
+	options := &InstallOptions{
+		PackageName: record.Name,
+		Variant:     record.Variant,
+		Force:       record.InstallDir != "" && containsString(record.Paths, record.InstallDir),
+	}
+	if record.Variant == "" && IsBackendName(record.Type) {
+		options.Backend = record.Type
+	}
+	return install(options)
+}
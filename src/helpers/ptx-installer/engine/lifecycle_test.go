@@ -0,0 +1,162 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
+)
+
+// writeDownloadPackage stores a download package with one variant
+func writeDownloadPackage(t *testing.T, assets, url, extractTo, version, changelog string) {
+	t.Helper()
+	pkg := `{
+  "apiVersion": "v1",
+  "kind": "Package",
+  "metadata": {"name": "tool", "displayName": "Tool", "description": "test package", "category": "development/tools"},
+  "spec": {
+    "platforms": {
+      "` + GetOperatingSystem() + `": {
+        "type": "download",
+        "variants": {
+          "stable": {"version": "` + version + `", "url": "` + url + `", "extractTo": "` + filepath.ToSlash(extractTo) + `", "changelog": "` + changelog + `"}
+        }
+      }
+    }
+  }
+}`
+	if err := os.MkdirAll(filepath.Join(assets, "packages"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(assets, "packages", "tool.json"), []byte(pkg), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstallUpgradeUninstall(t *testing.T) {
+	t.Setenv(registry.EnvPackagesDir, t.TempDir())
+	t.Setenv(EnvInstalledDB, filepath.Join(t.TempDir(), "installed.json"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("tool " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	assets := t.TempDir()
+	target := filepath.Join(t.TempDir(), "tools", "tool")
+	writeDownloadPackage(t, assets, server.URL+"/tool.bin", target, "1.2.0", "")
+	reg, err := registry.LoadPackageRegistry(assets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	installer := &Installer{registry: reg, cacheDir: t.TempDir(), assetsPath: assets}
+
+	if err := installer.Install(&InstallOptions{PackageName: "tool", Variant: "stable"}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	state, err := LoadInstallState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, ok := state.Get("tool")
+	if !ok || record.Version != "1.2.0" || record.Type != "download" || record.InstallDir != target {
+		t.Fatalf("unexpected record %+v", record)
+	}
+	if len(record.Paths) != 1 || record.Paths[0] != target {
+		t.Errorf("the new install directory should be recorded, got %v", record.Paths)
+	}
+
+	if info := installer.CheckUpgrade(record); info.Upgradable || info.Detail != "up to date" {
+		t.Errorf("unexpected upgrade info %+v", info)
+	}
+
+	// A newer registry version is an upgrade with a changelog
+	writeDownloadPackage(t, assets, server.URL+"/tool-1.3.bin", target, "1.3.0", "Faster startup")
+	if installer.registry, err = registry.LoadPackageRegistry(assets); err != nil {
+		t.Fatal(err)
+	}
+	info := installer.CheckUpgrade(record)
+	if !info.Upgradable || info.Available != "1.3.0" || info.Changelog != "Faster startup" {
+		t.Fatalf("unexpected upgrade info %+v", info)
+	}
+	var upgradeOptions *InstallOptions
+	err = installer.Upgrade(record, func(o *InstallOptions) error {
+		upgradeOptions = o
+		return installer.Install(o)
+	})
+	if err != nil {
+		t.Fatalf("Upgrade failed: %v", err)
+	}
+	if upgradeOptions.Variant != "stable" || !upgradeOptions.Force {
+		t.Errorf("upgrade should reinstall the variant it owns: %+v", upgradeOptions)
+	}
+	state, _ = LoadInstallState()
+	if record, _ = state.Get("tool"); record.Version != "1.3.0" || record.InstalledAt.After(record.UpdatedAt) {
+		t.Errorf("upgrade not recorded: %+v", record)
+	}
+
+	if err := installer.Uninstall("tool", true); err != nil {
+		t.Fatalf("dry run uninstall failed: %v", err)
+	}
+	if _, err := os.Stat(target); err != nil {
+		t.Fatal("dry run must not remove files")
+	}
+	if err := installer.Uninstall("tool", false); err != nil {
+		t.Fatalf("Uninstall failed: %v", err)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Error("install directory should be removed")
+	}
+	if _, err := os.Stat(filepath.Dir(target)); err != nil {
+		t.Error("parent directory should be kept")
+	}
+	state, _ = LoadInstallState()
+	if _, ok := state.Get("tool"); ok {
+		t.Error("record should be removed")
+	}
+	if err := installer.Uninstall("tool", false); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("expected ErrNotInstalled, got %v", err)
+	}
+}
+
+func TestCreatedPaths(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "bin")
+	if paths := createdPaths(dir, snapshotDir(dir)); paths != nil {
+		t.Errorf("missing directory created nothing, got %v", paths)
+	}
+
+	before := snapshotDir(dir)
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "existing"), nil, 0644)
+	if paths := createdPaths(dir, before); len(paths) != 1 || paths[0] != dir {
+		t.Errorf("new directory should be owned, got %v", paths)
+	}
+
+	before = snapshotDir(dir)
+	os.WriteFile(filepath.Join(dir, "tool"), nil, 0755)
+	if paths := createdPaths(dir, before); len(paths) != 1 || !strings.HasSuffix(paths[0], "tool") {
+		t.Errorf("only the new file should be owned in a shared directory, got %v", paths)
+	}
+}
+
+func TestIsProtectedPath(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	for _, path := range []string{"", "/", "/usr", "/opt/", home} {
+		if !isProtectedPath(path) {
+			t.Errorf("%q should be protected", path)
+		}
+	}
+	for _, path := range []string{"/opt/maven", "/usr/local/bin/hugo", filepath.Join(home, ".local", "share", "tool")} {
+		if isProtectedPath(path) {
+			t.Errorf("%q should not be protected", path)
+		}
+	}
+}
@@ -8,8 +8,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
+
+	"portunix.ai/portunix/src/pkg/platform"
 )
 
 // AddAptRepository adds a third-party APT repository with optional GPG key
@@ -265,6 +268,88 @@ func InstallViaPacman(packages []string, requiresSudo bool) error {
 	return nil
 }
 
+// InstallViaZypper installs packages using Zypper package manager (openSUSE)
+func InstallViaZypper(packages []string, requiresSudo bool) error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("Zypper is only available on Linux")
+	}
+
+	if !isCommandAvailable("zypper") {
+		return fmt.Errorf("zypper command not found")
+	}
+
+	fmt.Printf("📦 Installing via Zypper: %v\n", packages)
+
+	args := append([]string{"zypper", "--non-interactive", "install"}, packages...)
+	if requiresSudo && !IsRunningAsRoot() {
+		if !IsSudoAvailable() {
+			return fmt.Errorf("sudo is required but not available")
+		}
+		args = append([]string{"sudo"}, args...)
+	}
+	fmt.Printf("🚀 Running: %s\n", strings.Join(args, " "))
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("zypper install failed: %w", err)
+	}
+
+	fmt.Println("✅ Zypper installation completed")
+	return nil
+}
+
+// InstallViaBrew installs packages using Homebrew (macOS). Names prefixed
+// with "cask:" are installed as casks.
+func InstallViaBrew(packages []string) error {
+	if runtime.GOOS != "darwin" {
+		return fmt.Errorf("Homebrew backend is only used on macOS")
+	}
+
+	brew := brewCommand()
+	if brew == "" {
+		return fmt.Errorf("Homebrew is not installed. Install it first with: portunix install homebrew")
+	}
+
+	fmt.Printf("📦 Installing via Homebrew: %v\n", packages)
+
+	for _, pkg := range packages {
+		args := []string{"install"}
+		if name, ok := strings.CutPrefix(pkg, "cask:"); ok {
+			args = append(args, "--cask", name)
+		} else {
+			args = append(args, pkg)
+		}
+		fmt.Printf("🚀 Running: brew %s\n", strings.Join(args, " "))
+		cmd := exec.Command(brew, args...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("brew install failed for %s: %w", pkg, err)
+		}
+	}
+
+	fmt.Println("✅ Homebrew installation completed")
+	return nil
+}
+
+// brewCommand returns the brew executable: from PATH, else from the
+// Homebrew prefix, which is not on PATH right after Homebrew is installed
+func brewCommand() string {
+	if path, err := exec.LookPath("brew"); err == nil {
+		return path
+	}
+	if prefix := platform.HomebrewPrefix(); prefix != "" {
+		brew := filepath.Join(prefix, "bin", "brew")
+		if _, err := os.Stat(brew); err == nil {
+			return brew
+		}
+	}
+	return ""
+}
+
 // InstallDebPackage installs a .deb package file
 func InstallDebPackage(debFile string) error {
 	if runtime.GOOS != "linux" {
@@ -393,3 +478,87 @@ func InstallViaWinget(packages []string) error {
 	fmt.Println("✅ Winget installation completed")
 	return nil
 }
+
+// RemoveSystemPackages removes packages installed through a system package
+// manager. manager is the installation type recorded for the package.
+func RemoveSystemPackages(manager string, packages []string) error {
+	if len(packages) == 0 {
+		return fmt.Errorf("no packages recorded for %s removal", manager)
+	}
+
+	var args []string
+	sudo := runtime.GOOS != "windows"
+	switch manager {
+	case "apt", "deb":
+		args = append([]string{"apt-get", "remove", "-y"}, packages...)
+	case "dnf", "yum":
+		packageManager := "dnf"
+		if !isCommandAvailable("dnf") {
+			packageManager = "yum"
+		}
+		args = append([]string{packageManager, "remove", "-y"}, packages...)
+	case "snap":
+		args = append([]string{"snap", "remove"}, packages...)
+	case "pacman":
+		args = append([]string{"pacman", "-R", "--noconfirm"}, packages...)
+	case "zypper":
+		args = append([]string{"zypper", "--non-interactive", "remove"}, packages...)
+	case "brew":
+		for _, pkg := range packages {
+			args := []string{"uninstall"}
+			if name, ok := strings.CutPrefix(pkg, "cask:"); ok {
+				args = append(args, "--cask", name)
+			} else {
+				args = append(args, pkg)
+			}
+			cmd := exec.Command(brewCommand(), args...)
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			if err := cmd.Run(); err != nil {
+				return fmt.Errorf("brew uninstall failed for %s: %w", pkg, err)
+			}
+		}
+		return nil
+	case "chocolatey":
+		args = append([]string{"choco", "uninstall", "-y"}, packages...)
+	case "winget":
+		for _, pkg := range packages {
+			cmd := exec.Command("winget", "uninstall", "--id", pkg, "-e", "--silent")
+			if err := cmd.Run(); err != nil {
+				return fmt.Errorf("winget uninstall failed for %s: %w", pkg, err)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("removal via %s is not supported", manager)
+	}
+
+	if !isCommandAvailable(args[0]) {
+		return fmt.Errorf("%s command not found", args[0])
+	}
+	if sudo && !IsRunningAsRoot() {
+		if !IsSudoAvailable() {
+			return fmt.Errorf("sudo is required but not available")
+		}
+		args = append([]string{"sudo"}, args...)
+	}
+
+	fmt.Printf("🚀 Running: %s\n", strings.Join(args, " "))
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s removal failed: %w", manager, err)
+	}
+	return nil
+}
+
+// debPackageName reads the package name from a .deb file
+func debPackageName(debFile string) string {
+	out, err := exec.Command("dpkg-deb", "-f", debFile, "Package").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
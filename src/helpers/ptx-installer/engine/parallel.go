@@ -0,0 +1,299 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
+)
+
+// Task states reported to the progress callback
+const (
+	TaskWaiting = "waiting"
+	TaskRunning = "running"
+	TaskDone    = "done"
+	TaskFailed  = "failed"
+	TaskSkipped = "skipped"
+)
+
+// InstallTask is one installation in a dependency graph
+type InstallTask struct {
+	Name    string
+	Variant string
+	// DependsOn names tasks that must finish successfully first. Names
+	// outside the graph are treated as satisfied.
+	DependsOn []string
+	// Exclusive tasks never run together: system package managers and
+	// Windows installers hold a global lock
+	Exclusive bool
+	// Dependency is set for tasks added as a dependency of a requested
+	// package
+	Dependency bool
+	// LogPath is where the output of a task run in the background goes
+	LogPath string
+	Run     func() error
+}
+
+// TaskResult is the outcome of one task
+type TaskResult struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// ProgressFunc receives task state changes; detail is an error, the
+// blocking dependency or empty
+type ProgressFunc func(name, status, detail string)
+
+// DefaultJobs is the default number of parallel installations
+func DefaultJobs() int {
+	if n := runtime.NumCPU(); n < 4 {
+		return n
+	}
+	return 4
+}
+
+// exclusiveTypes are installation types that must not run concurrently
+var exclusiveTypes = map[string]bool{
+	"apt": true, "deb": true, "dnf": true, "yum": true, "snap": true, "pacman": true,
+	"zypper": true, "brew": true, "chocolatey": true, "winget": true, "msi": true,
+	"exe": true, "script": true,
+}
+
+// PlanInstall builds the dependency graph for installing packages. Missing
+// dependencies are added before the packages needing them; dependencies
+// that are already installed are left out. variant applies to every
+// requested package, "" selects each package's default.
+func (i *Installer) PlanInstall(names []string, variant string) ([]*InstallTask, error) {
+	var tasks []*InstallTask
+	byName := make(map[string]*InstallTask)
+	requested := make(map[string]bool)
+	for _, name := range names {
+		requested[name] = true
+	}
+
+	for _, name := range names {
+		order, err := i.registry.ResolveDependencies(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, pkgName := range order {
+			if byName[pkgName] != nil {
+				continue
+			}
+			pkg, _ := i.registry.GetPackage(pkgName)
+			if !requested[pkgName] {
+				if installed, _ := i.InstalledVersion(pkg); installed {
+					continue
+				}
+			}
+			task := &InstallTask{Name: pkgName, Dependency: !requested[pkgName]}
+			if requested[pkgName] {
+				task.Variant = variant
+			}
+			task.Exclusive = i.isExclusiveInstall(pkg, task.Variant)
+			task.DependsOn = append([]string(nil), pkg.Spec.Dependencies...)
+			byName[pkgName] = task
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// isExclusiveInstall reports whether installing the variant goes through a
+// system package manager or installer that cannot run concurrently
+func (i *Installer) isExclusiveInstall(pkg *registry.Package, variant string) bool {
+	switch strings.ToLower(pkg.Metadata.Name) {
+	case "docker", "podman":
+		return true
+	}
+	if backend, _, _ := nativeBackend(pkg, BackendAuto, variant); backend != nil {
+		return true
+	}
+	platform, ok := currentPlatform(pkg)
+	if !ok {
+		return false
+	}
+	if variant == "" {
+		variant = i.autoDetectVariant(&platform)
+	}
+	installType := platform.Type
+	if spec, ok := platform.Variants[variant]; ok && spec.Type != "" {
+		installType = spec.Type
+	}
+	return exclusiveTypes[installType]
+}
+
+// TaskLevels groups tasks by dependency depth: level 0 has no dependencies
+// in the graph, level n depends on tasks of lower levels only
+func TaskLevels(tasks []*InstallTask) [][]*InstallTask {
+	byName := make(map[string]*InstallTask, len(tasks))
+	for _, task := range tasks {
+		byName[task.Name] = task
+	}
+	depth := make(map[string]int)
+	var levelOf func(task *InstallTask, seen map[string]bool) int
+	levelOf = func(task *InstallTask, seen map[string]bool) int {
+		if d, ok := depth[task.Name]; ok {
+			return d
+		}
+		if seen[task.Name] {
+			return 0 // cycles are reported by RunInstallTasks
+		}
+		seen[task.Name] = true
+		d := 0
+		for _, dep := range task.DependsOn {
+			if depTask := byName[dep]; depTask != nil {
+				if l := levelOf(depTask, seen) + 1; l > d {
+					d = l
+				}
+			}
+		}
+		depth[task.Name] = d
+		return d
+	}
+
+	var levels [][]*InstallTask
+	for _, task := range tasks {
+		d := levelOf(task, make(map[string]bool))
+		for len(levels) <= d {
+			levels = append(levels, nil)
+		}
+		levels[d] = append(levels[d], task)
+	}
+	return levels
+}
+
+// RunInstallTasks runs tasks in dependency order with up to jobs tasks at
+// once. Tasks whose dependency failed are skipped; independent tasks keep
+// running. Results are returned in task order.
+func RunInstallTasks(tasks []*InstallTask, jobs int, progress ProgressFunc) []TaskResult {
+	if jobs < 1 {
+		jobs = 1
+	}
+	if progress == nil {
+		progress = func(string, string, string) {}
+	}
+
+	inGraph := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		inGraph[task.Name] = true
+		progress(task.Name, TaskWaiting, "")
+	}
+
+	type finished struct {
+		index int
+		err   error
+	}
+	results := make([]TaskResult, len(tasks))
+	status := make(map[string]string, len(tasks))
+	started := make([]time.Time, len(tasks))
+	done := make(chan finished)
+	running, exclusiveRunning, remaining := 0, false, len(tasks)
+
+	for remaining > 0 {
+		// Start or skip every task whose dependencies are settled
+		for idx, task := range tasks {
+			if status[task.Name] != "" {
+				continue
+			}
+			ready, blocker := true, ""
+			for _, dep := range task.DependsOn {
+				if !inGraph[dep] {
+					continue
+				}
+				switch status[dep] {
+				case TaskDone:
+				case TaskFailed, TaskSkipped:
+					blocker = dep
+				default:
+					ready = false
+				}
+			}
+			if blocker != "" {
+				status[task.Name] = TaskSkipped
+				results[idx] = TaskResult{Name: task.Name, Status: TaskSkipped, Error: "dependency " + blocker + " was not installed"}
+				progress(task.Name, TaskSkipped, blocker)
+				remaining--
+				continue
+			}
+			if !ready || running >= jobs || (task.Exclusive && exclusiveRunning) {
+				continue
+			}
+
+			status[task.Name] = TaskRunning
+			started[idx] = time.Now()
+			running++
+			if task.Exclusive {
+				exclusiveRunning = true
+			}
+			progress(task.Name, TaskRunning, "")
+			go func(idx int, task *InstallTask) {
+				done <- finished{idx, task.Run()}
+			}(idx, task)
+		}
+
+		if running == 0 {
+			// Nothing can start: the rest of the graph is a cycle
+			for idx, task := range tasks {
+				if status[task.Name] == "" {
+					status[task.Name] = TaskFailed
+					results[idx] = TaskResult{Name: task.Name, Status: TaskFailed, Error: "circular dependency"}
+					progress(task.Name, TaskFailed, "circular dependency")
+					remaining--
+				}
+			}
+			break
+		}
+
+		f := <-done
+		task := tasks[f.index]
+		running--
+		remaining--
+		if task.Exclusive {
+			exclusiveRunning = false
+		}
+		result := TaskResult{Name: task.Name, Status: TaskDone, Duration: time.Since(started[f.index])}
+		if f.err != nil {
+			result.Status = TaskFailed
+			result.Error = f.err.Error()
+		}
+		status[task.Name] = result.Status
+		results[f.index] = result
+		progress(task.Name, result.Status, result.Error)
+	}
+	return results
+}
+
+// FormatTaskPlan describes the install order for dry runs
+func FormatTaskPlan(tasks []*InstallTask, jobs int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "📋 Installation plan: %d package(s), up to %d in parallel\n", len(tasks), jobs)
+	for level, group := range TaskLevels(tasks) {
+		names := make([]string, 0, len(group))
+		for _, task := range group {
+			name := task.Name
+			if task.Variant != "" {
+				name += " (" + task.Variant + ")"
+			}
+			if task.Dependency {
+				name += " [dependency]"
+			}
+			if task.Exclusive {
+				name += " [exclusive]"
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(&b, "   Stage %d: %s\n", level+1, strings.Join(names, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,260 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
+)
+
+// recorder tracks the order tasks finish in and the peak concurrency
+type recorder struct {
+	mu        sync.Mutex
+	finished  []string
+	running   int32
+	peak      int32
+	exclusive int32
+}
+
+func (r *recorder) task(name string, exclusive bool, err error, deps ...string) *InstallTask {
+	return &InstallTask{Name: name, DependsOn: deps, Exclusive: exclusive, Run: func() error {
+		n := atomic.AddInt32(&r.running, 1)
+		for {
+			peak := atomic.LoadInt32(&r.peak)
+			if n <= peak || atomic.CompareAndSwapInt32(&r.peak, peak, n) {
+				break
+			}
+		}
+		if exclusive && atomic.AddInt32(&r.exclusive, 1) > 1 {
+			err = errors.New("exclusive tasks overlapped")
+		}
+		time.Sleep(20 * time.Millisecond)
+		if exclusive {
+			atomic.AddInt32(&r.exclusive, -1)
+		}
+		atomic.AddInt32(&r.running, -1)
+		r.mu.Lock()
+		r.finished = append(r.finished, name)
+		r.mu.Unlock()
+		return err
+	}}
+}
+
+func (r *recorder) index(name string) int {
+	for i, n := range r.finished {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestRunInstallTasksOrderAndParallelism(t *testing.T) {
+	r := &recorder{}
+	tasks := []*InstallTask{
+		r.task("git", false, nil),
+		r.task("nodejs", false, nil),
+		r.task("hugo", false, nil),
+		r.task("yarn", false, nil, "nodejs"),
+		r.task("external", false, nil, "not-in-graph"),
+	}
+	results := RunInstallTasks(tasks, 3, nil)
+	for _, result := range results {
+		if result.Status != TaskDone {
+			t.Errorf("%s: unexpected result %+v", result.Name, result)
+		}
+	}
+	if r.index("yarn") < r.index("nodejs") {
+		t.Errorf("yarn finished before its dependency: %v", r.finished)
+	}
+	if r.peak < 2 || r.peak > 3 {
+		t.Errorf("expected 2-3 tasks at once with 3 jobs, got %d", r.peak)
+	}
+
+	r = &recorder{}
+	RunInstallTasks([]*InstallTask{r.task("a", false, nil), r.task("b", false, nil), r.task("c", false, nil)}, 1, nil)
+	if r.peak != 1 {
+		t.Errorf("one job must run tasks one by one, got %d at once", r.peak)
+	}
+}
+
+func TestRunInstallTasksExclusive(t *testing.T) {
+	r := &recorder{}
+	tasks := []*InstallTask{
+		r.task("apt-a", true, nil),
+		r.task("apt-b", true, nil),
+		r.task("apt-c", true, nil),
+		r.task("download", false, nil),
+	}
+	for _, result := range RunInstallTasks(tasks, 4, nil) {
+		if result.Status != TaskDone {
+			t.Errorf("%s: %s", result.Name, result.Error)
+		}
+	}
+	if r.peak < 2 {
+		t.Error("a non-exclusive task should run next to an exclusive one")
+	}
+}
+
+func TestRunInstallTasksFailureSkipsDependents(t *testing.T) {
+	r := &recorder{}
+	var events []string
+	var mu sync.Mutex
+	tasks := []*InstallTask{
+		r.task("python", false, errors.New("download failed")),
+		r.task("pip-tool", false, nil, "python"),
+		r.task("plugin", false, nil, "pip-tool"),
+		r.task("git", false, nil),
+	}
+	results := RunInstallTasks(tasks, 2, func(name, status, detail string) {
+		mu.Lock()
+		events = append(events, name+":"+status)
+		mu.Unlock()
+	})
+
+	want := map[string]string{"python": TaskFailed, "pip-tool": TaskSkipped, "plugin": TaskSkipped, "git": TaskDone}
+	for _, result := range results {
+		if result.Status != want[result.Name] {
+			t.Errorf("%s: expected %s, got %s", result.Name, want[result.Name], result.Status)
+		}
+	}
+	if results[0].Error != "download failed" {
+		t.Errorf("unexpected error %q", results[0].Error)
+	}
+	if r.index("pip-tool") != -1 {
+		t.Error("dependents of a failed task must not run")
+	}
+	if len(events) != 10 {
+		t.Errorf("expected waiting, running and final events, got %v", events)
+	}
+}
+
+func TestRunInstallTasksCycle(t *testing.T) {
+	r := &recorder{}
+	tasks := []*InstallTask{
+		r.task("a", false, nil, "b"),
+		r.task("b", false, nil, "a"),
+		r.task("c", false, nil),
+	}
+	results := RunInstallTasks(tasks, 2, nil)
+	if results[0].Status != TaskFailed || results[1].Status != TaskFailed || results[2].Status != TaskDone {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
+
+func TestTaskLevels(t *testing.T) {
+	tasks := []*InstallTask{
+		{Name: "java"},
+		{Name: "maven", DependsOn: []string{"java"}},
+		{Name: "git"},
+		{Name: "plugin", DependsOn: []string{"maven", "git"}},
+	}
+	levels := TaskLevels(tasks)
+	if len(levels) != 3 || len(levels[0]) != 2 || levels[1][0].Name != "maven" || levels[2][0].Name != "plugin" {
+		t.Errorf("unexpected levels %+v", levels)
+	}
+}
+
+// writeDepPackage stores a download package with dependencies
+func writeDepPackage(t *testing.T, assets, name, installType string, deps ...string) {
+	t.Helper()
+	source := `"url": "https://example.invalid/` + name + `.tar.gz"`
+	if installType == "apt" {
+		source = `"packages": ["` + name + `"]`
+	}
+	depList := ""
+	for i, dep := range deps {
+		if i > 0 {
+			depList += ", "
+		}
+		depList += `"` + dep + `"`
+	}
+	pkg := `{
+  "apiVersion": "v1",
+  "kind": "Package",
+  "metadata": {"name": "` + name + `", "displayName": "` + name + `", "description": "test package", "category": "development/tools"},
+  "spec": {
+    "dependencies": [` + depList + `],
+    "verification": {"command": "ptx-test-missing-` + name + ` --version"},
+    "platforms": {
+      "` + GetOperatingSystem() + `": {
+        "type": "` + installType + `",
+        "variants": {"default": {"version": "1.0.0", ` + source + `}}
+      }
+    }
+  }
+}`
+	if err := os.MkdirAll(filepath.Join(assets, "packages"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(assets, "packages", name+".json"), []byte(pkg), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlanInstall(t *testing.T) {
+	t.Setenv(registry.EnvPackagesDir, t.TempDir())
+	assets := t.TempDir()
+	writeDepPackage(t, assets, "runtime", "apt")
+	writeDepPackage(t, assets, "framework", "download", "runtime")
+	writeDepPackage(t, assets, "cli", "download")
+	reg, err := registry.LoadPackageRegistry(assets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	installer := &Installer{registry: reg, cacheDir: t.TempDir(), assetsPath: assets}
+
+	tasks, err := installer.PlanInstall([]string{"framework", "cli"}, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 || tasks[0].Name != "runtime" || tasks[1].Name != "framework" || tasks[2].Name != "cli" {
+		t.Fatalf("unexpected plan %+v", tasks)
+	}
+	if !tasks[0].Dependency || tasks[0].Variant != "" || !tasks[0].Exclusive {
+		t.Errorf("runtime should be an exclusive dependency with its default variant: %+v", tasks[0])
+	}
+	if tasks[1].Dependency || tasks[1].Variant != "default" || tasks[1].Exclusive || tasks[1].DependsOn[0] != "runtime" {
+		t.Errorf("unexpected framework task %+v", tasks[1])
+	}
+
+	if _, err := installer.PlanInstall([]string{"missing"}, ""); err == nil {
+		t.Error("unknown packages should fail planning")
+	}
+}
+
+func TestUpdateInstallStateConcurrent(t *testing.T) {
+	t.Setenv(EnvInstalledDB, filepath.Join(t.TempDir(), "installed.json"))
+	var wg sync.WaitGroup
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			err := UpdateInstallState(func(state *InstallState) error {
+				state.Record(&InstalledPackage{Name: name, Version: "1.0"})
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	state, err := LoadInstallState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.List()) != 6 {
+		t.Errorf("concurrent updates lost records: %d left", len(state.List()))
+	}
+}
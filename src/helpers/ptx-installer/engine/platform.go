@@ -18,8 +18,13 @@ func GetOperatingSystem() string {
 }
 
 // GetArchitecture returns the current system architecture
-// This is a wrapper around platform.GetArchitecture() for backward compatibility
+// This is a wrapper around platform.GetArchitecture() for backward compatibility.
+// On Apple Silicon it reports arm64 also when running under Rosetta, so
+// native binaries are installed.
 func GetArchitecture() string {
+	if platform.IsDarwin() {
+		return platform.GetNativeArchitecture()
+	}
 	return platform.GetArchitecture()
 }
 
@@ -0,0 +1,148 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvProfilesDir overrides the directory with user-defined profiles
+const EnvProfilesDir = "PORTUNIX_PROFILES_DIR"
+
+// Profile sources
+const (
+	ProfileBuiltin = "builtin"
+	ProfileUser    = "user"
+)
+
+// Profile is a named workstation preset: a package manifest installed as a
+// batch with 'portunix install profile <name>'
+//
+//	name: go-backend
+//	description: Go backend development
+//	packages:
+//	  - name: go
+//	  - name: docker
+type Profile struct {
+	Name            string `yaml:"name" json:"name"`
+	Description     string `yaml:"description,omitempty" json:"description,omitempty"`
+	PackageManifest `yaml:",inline"`
+	Source          string `yaml:"-" json:"source"`
+}
+
+// embeddedProfiles holds the built-in profiles (set from main package)
+var embeddedProfiles fs.FS
+
+// SetEmbeddedProfiles sets the filesystem with the built-in profiles. Files
+// are read from its profiles directory.
+func SetEmbeddedProfiles(profilesFS fs.FS) {
+	embeddedProfiles = profilesFS
+}
+
+// UserProfilesDir returns the directory with user-defined profiles, which
+// override built-in profiles of the same name
+func UserProfilesDir() string {
+	if dir := os.Getenv(EnvProfilesDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".portunix", "profiles")
+	}
+	return filepath.Join(home, ".portunix", "profiles")
+}
+
+// ParseProfile parses a profile file. The name defaults to the file name
+// without extension.
+func ParseProfile(fileName string, data []byte) (*Profile, error) {
+	var p Profile
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %w", fileName, err)
+	}
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(path.Base(filepath.ToSlash(fileName)), path.Ext(fileName))
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %w", fileName, err)
+	}
+	return &p, nil
+}
+
+// LoadProfiles returns the built-in and user-defined profiles sorted by
+// name. Invalid files are reported as warnings and left out.
+func LoadProfiles() ([]*Profile, []error) {
+	byName := make(map[string]*Profile)
+	var warnings []error
+
+	if embeddedProfiles != nil {
+		entries, _ := fs.ReadDir(embeddedProfiles, "profiles")
+		for _, entry := range entries {
+			if !isProfileFile(entry.Name()) {
+				continue
+			}
+			data, err := fs.ReadFile(embeddedProfiles, path.Join("profiles", entry.Name()))
+			if err == nil {
+				var p *Profile
+				if p, err = ParseProfile(entry.Name(), data); err == nil {
+					p.Source = ProfileBuiltin
+					byName[p.Name] = p
+					continue
+				}
+			}
+			warnings = append(warnings, err)
+		}
+	}
+
+	dir := UserProfilesDir()
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if entry.IsDir() || !isProfileFile(entry.Name()) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err == nil {
+			var p *Profile
+			if p, err = ParseProfile(entry.Name(), data); err == nil {
+				p.Source = ProfileUser
+				byName[p.Name] = p
+				continue
+			}
+		}
+		warnings = append(warnings, err)
+	}
+
+	profiles := make([]*Profile, 0, len(byName))
+	for _, p := range byName {
+		profiles = append(profiles, p)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, warnings
+}
+
+// GetProfile returns the profile with the given name
+func GetProfile(name string) (*Profile, error) {
+	profiles, _ := LoadProfiles()
+	var names []string
+	for _, p := range profiles {
+		if p.Name == name {
+			return p, nil
+		}
+		names = append(names, p.Name)
+	}
+	return nil, fmt.Errorf("profile %s not found (available: %s)", name, strings.Join(names, ", "))
+}
+
+func isProfileFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,86 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
+)
+
+func TestParseProfile(t *testing.T) {
+	p, err := ParseProfile("my-stack.yaml", []byte("packages:\n  - name: go\n  - name: java\n    variant: \"21\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "my-stack" || len(p.Packages) != 2 || p.Packages[1].Variant != "21" {
+		t.Errorf("unexpected profile %+v", p)
+	}
+
+	for _, data := range []string{"name: empty\n", "packages:\n  - name: go\n  - name: go\n", "packages: ["} {
+		if _, err := ParseProfile("bad.yaml", []byte(data)); err == nil {
+			t.Errorf("expected an error for %q", data)
+		}
+	}
+}
+
+func TestLoadProfilesUserOverride(t *testing.T) {
+	defer SetEmbeddedProfiles(embeddedProfiles)
+	SetEmbeddedProfiles(os.DirFS(filepath.Join("..", "assets")))
+	dir := t.TempDir()
+	t.Setenv(EnvProfilesDir, dir)
+	os.WriteFile(filepath.Join(dir, "go-backend.yml"), []byte("name: go-backend\npackages:\n  - name: go\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "mine.yaml"), []byte("description: Mine\npackages:\n  - name: git\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("packages: []\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644)
+
+	profiles, warnings := LoadProfiles()
+	if len(warnings) != 1 {
+		t.Errorf("expected one warning for the broken profile, got %v", warnings)
+	}
+	byName := make(map[string]*Profile)
+	for _, p := range profiles {
+		byName[p.Name] = p
+	}
+	if p := byName["go-backend"]; p == nil || p.Source != ProfileUser || len(p.Packages) != 1 {
+		t.Errorf("user profile should override the built-in one: %+v", p)
+	}
+	if p := byName["mine"]; p == nil || p.Description != "Mine" {
+		t.Errorf("user profile missing: %+v", p)
+	}
+	if p := byName["data-science"]; p == nil || p.Source != ProfileBuiltin {
+		t.Errorf("built-in profile missing: %+v", p)
+	}
+	if _, err := GetProfile("missing"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
+
+// TestBuiltinProfilesUseKnownPackages keeps the built-in profiles in sync
+// with the package registry
+func TestBuiltinProfilesUseKnownPackages(t *testing.T) {
+	defer SetEmbeddedProfiles(embeddedProfiles)
+	SetEmbeddedProfiles(os.DirFS(filepath.Join("..", "assets")))
+	t.Setenv(EnvProfilesDir, t.TempDir())
+	t.Setenv(registry.EnvPackagesDir, t.TempDir())
+	reg, err := registry.LoadPackageRegistry(filepath.Join("..", "assets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, warnings := LoadProfiles()
+	if len(warnings) > 0 || len(profiles) == 0 {
+		t.Fatalf("built-in profiles failed to load: %v", warnings)
+	}
+	for _, p := range profiles {
+		for _, pkg := range p.Packages {
+			if _, err := reg.GetPackage(pkg.Name); err != nil {
+				t.Errorf("profile %s: unknown package %s", p.Name, pkg.Name)
+			}
+		}
+	}
+}
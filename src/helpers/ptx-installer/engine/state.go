@@ -0,0 +1,225 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// EnvInstalledDB overrides the location of the installed packages database
+const EnvInstalledDB = "PORTUNIX_INSTALLED_DB"
+
+// InstalledPackage records a package installed by portunix and what the
+// installation left on the system, so it can be uninstalled or upgraded
+type InstalledPackage struct {
+	Name    string `json:"name"`
+	Variant string `json:"variant"`
+	Version string `json:"version"`
+	Type    string `json:"type"`
+	// InstallDir is the directory an archive or download was placed in
+	InstallDir string `json:"installDir,omitempty"`
+	// Paths are the files and directories created by the installation
+	Paths []string `json:"paths,omitempty"`
+	// Packages are the system packages of package manager installations
+	Packages    []string  `json:"packages,omitempty"`
+	Source      string    `json:"source,omitempty"`
+	InstalledAt time.Time `json:"installedAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// InstallState is the database of packages installed by portunix
+type InstallState struct {
+	Packages map[string]*InstalledPackage `json:"packages"`
+	path     string
+}
+
+// InstalledDBPath returns the installed packages database,
+// ~/.portunix/installed.json unless PORTUNIX_INSTALLED_DB is set
+func InstalledDBPath() string {
+	if path := os.Getenv(EnvInstalledDB); path != "" {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".portunix", "installed.json")
+	}
+	return filepath.Join(homeDir, ".portunix", "installed.json")
+}
+
+// LoadInstallState reads the installed packages database. A missing
+// database is an empty state.
+func LoadInstallState() (*InstallState, error) {
+	state := &InstallState{Packages: make(map[string]*InstalledPackage), path: InstalledDBPath()}
+	data, err := os.ReadFile(state.path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read installed packages: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid installed packages database %s: %w", state.path, err)
+	}
+	if state.Packages == nil {
+		state.Packages = make(map[string]*InstalledPackage)
+	}
+	return state, nil
+}
+
+// UpdateInstallState loads the database, applies update and saves the
+// result while holding a lock file, so installations running in parallel
+// do not overwrite each other's records
+func UpdateInstallState(update func(*InstallState) error) error {
+	unlock, err := lockInstallState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := LoadInstallState()
+	if err != nil {
+		return err
+	}
+	if err := update(state); err != nil {
+		return err
+	}
+	return state.Save()
+}
+
+// lockInstallState creates <database>.lock, waiting while another process
+// holds it. A lock older than stateLockStale is left over from a crashed
+// process and taken over.
+func lockInstallState() (func(), error) {
+	lockPath := InstalledDBPath() + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(stateLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock installed packages: %w", err)
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > stateLockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("installed packages database is locked by another process (%s)", lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+const (
+	stateLockTimeout = time.Minute
+	stateLockStale   = 30 * time.Second
+)
+
+// Save writes the database, replacing the previous file atomically
+func (s *InstallState) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Get returns the record of an installed package
+func (s *InstallState) Get(name string) (*InstalledPackage, bool) {
+	record, ok := s.Packages[name]
+	return record, ok
+}
+
+// List returns all records sorted by name
+func (s *InstallState) List() []*InstalledPackage {
+	records := make([]*InstalledPackage, 0, len(s.Packages))
+	for _, record := range s.Packages {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+	return records
+}
+
+// Record stores an installation. Reinstalling keeps the original install
+// time and the paths created by earlier installs that still exist.
+func (s *InstallState) Record(record *InstalledPackage) {
+	now := time.Now()
+	record.InstalledAt, record.UpdatedAt = now, now
+	if previous, ok := s.Packages[record.Name]; ok {
+		record.InstalledAt = previous.InstalledAt
+		for _, path := range previous.Paths {
+			if _, err := os.Lstat(path); err == nil && !containsString(record.Paths, path) {
+				record.Paths = append(record.Paths, path)
+			}
+		}
+	}
+	sort.Strings(record.Paths)
+	s.Packages[record.Name] = record
+}
+
+// Remove deletes the record of a package
+func (s *InstallState) Remove(name string) {
+	delete(s.Packages, name)
+}
+
+// snapshotDir returns the top-level entries of dir, or nil when dir does
+// not exist
+func snapshotDir(dir string) map[string]bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	names := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		names[entry.Name()] = true
+	}
+	return names
+}
+
+// createdPaths returns what an installation added to dir: dir itself when
+// it did not exist before, else the new top-level entries
+func createdPaths(dir string, before map[string]bool) []string {
+	if before == nil {
+		if _, err := os.Stat(dir); err == nil {
+			return []string{dir}
+		}
+		return nil
+	}
+	var paths []string
+	for name := range snapshotDir(dir) {
+		if !before[name] {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import "portunix.ai/app/aihelp"
+
+// showHelpAI prints machine-readable help for --help-ai
+func showHelpAI() {
+	pkg := aihelp.Argument{Name: "package", Type: "string", Required: true, Description: "Package name (see 'portunix package list --json')"}
+
+	aihelp.Help{
+		Tool:        "ptx-installer",
+		Version:     version,
+		Description: "Cross-platform package installation from the Portunix package registry",
+		Commands: []aihelp.Command{
+			{
+				Name:        "install",
+				Description: "Install software packages; several packages install in parallel along their dependency graph",
+				Arguments:   []aihelp.Argument{pkg},
+				Flags: []aihelp.Flag{
+					{Name: "variant", Type: "string", Description: "Package variant (e.g. 21 for Java 21)"},
+					{Name: "path", Type: "path", Description: "Target installation path (project generators)"},
+					{Name: "dry-run", Type: "boolean", Description: "Preview installation without executing"},
+					{Name: "force", Type: "boolean", Description: "Reinstall even if already installed"},
+					{Name: "offline", Type: "boolean", Description: "Install only from the download cache, never use the network"},
+					{Name: "jobs", Type: "integer", Description: "Parallel installations when installing several packages"},
+					{Name: "backend", Type: "string", Description: "auto (prefer a native system package), direct, apt, dnf, pacman, zypper, brew, winget or chocolatey"},
+					{Name: "db-host", Type: "string", Description: "Override container DB host"},
+					{Name: "db-port", Type: "integer", Description: "Override container DB port"},
+					{Name: "db-user", Type: "string", Description: "Override container DB user"},
+					{Name: "db-password", Type: "string", Description: "Override container DB password"},
+				},
+				Examples: []string{
+					"portunix install python",
+					"portunix install java --variant=21",
+					"portunix install nodejs --dry-run",
+					"portunix install nodejs --offline",
+					"portunix install git nodejs hugo --jobs 4",
+				},
+			},
+			{
+				Name:        "install apply",
+				Description: "Install, update or skip the packages declared in a YAML manifest (name, version, variant, os, arch)",
+				Arguments:   []aihelp.Argument{{Name: "manifest", Type: "path", Required: true, Description: "Package manifest, e.g. packages.yaml"}},
+				Flags: []aihelp.Flag{
+					{Name: "dry-run", Type: "boolean", Description: "Report planned actions without installing"},
+					{Name: "offline", Type: "boolean", Description: "Install only from the download cache"},
+					{Name: "json", Type: "boolean", Description: "Output the results as JSON"},
+				},
+				Examples: []string{"portunix install apply packages.yaml --dry-run"},
+			},
+			{
+				Name:        "install profile",
+				Description: "Install a workstation profile (built-in or from ~/.portunix/profiles) as a batch; 'list' and 'show <name>' inspect profiles",
+				Arguments:   []aihelp.Argument{{Name: "name", Type: "string", Required: true, Description: "Profile name, list or show"}},
+				Flags: []aihelp.Flag{
+					{Name: "dry-run", Type: "boolean", Description: "Report planned actions without installing"},
+					{Name: "offline", Type: "boolean", Description: "Install only from the download cache"},
+					{Name: "json", Type: "boolean", Description: "Output as JSON"},
+				},
+				Examples: []string{"portunix install profile list", "portunix install profile go-backend --dry-run"},
+			},
+			{
+				Name:        "uninstall",
+				Description: "Remove packages installed by portunix (uninstall commands, system package manager or recorded files)",
+				Arguments:   []aihelp.Argument{{Name: "package", Type: "string", Required: true, Variadic: true}},
+				Flags:       []aihelp.Flag{{Name: "dry-run", Type: "boolean", Description: "Show what would be removed"}},
+				Examples:    []string{"portunix uninstall hugo --dry-run"},
+			},
+			{
+				Name:        "upgrade",
+				Description: "Upgrade packages installed by portunix to the registry version of their variant, showing changelogs",
+				Arguments:   []aihelp.Argument{{Name: "package", Type: "string", Variadic: true}},
+				Flags: []aihelp.Flag{
+					{Name: "all", Type: "boolean", Description: "Upgrade every installed package"},
+					{Name: "check", Type: "boolean", Description: "Only show available upgrades"},
+					{Name: "offline", Type: "boolean", Description: "Install only from the download cache"},
+					{Name: "json", Type: "boolean", Description: "Output the comparison as JSON"},
+				},
+				Examples: []string{"portunix upgrade --all --check", "portunix upgrade nodejs"},
+			},
+			{
+				Name:        "package list",
+				Description: "List available packages",
+				Flags: []aihelp.Flag{
+					{Name: "category", Type: "string", Description: "Filter by category (e.g. development/languages)"},
+					{Name: "platform", Type: "string", Description: "Filter by platform", Choices: []string{"linux", "windows", "darwin"}},
+					{Name: "json", Type: "boolean", Description: "Output as JSON"},
+				},
+				Examples: []string{"portunix package list --category development/languages"},
+			},
+			{
+				Name:        "package search",
+				Description: "Search packages by name or description",
+				Arguments:   []aihelp.Argument{{Name: "query", Type: "string", Required: true}},
+				Examples:    []string{"portunix package search python"},
+			},
+			{
+				Name:        "package info",
+				Description: "Show detailed information about a package",
+				Arguments:   []aihelp.Argument{pkg},
+				Examples:    []string{"portunix package info nodejs"},
+			},
+			{
+				Name:        "package installed",
+				Description: "List packages installed by portunix with version, variant and type",
+				Flags:       []aihelp.Flag{{Name: "json", Type: "boolean", Description: "Output as JSON"}},
+				Examples:    []string{"portunix package installed"},
+			},
+			{
+				Name:        "package backends",
+				Description: "List the system package managers native packages are installed through and whether they are available",
+				Flags:       []aihelp.Flag{{Name: "json", Type: "boolean", Description: "Output as JSON"}},
+				Examples:    []string{"portunix package backends"},
+			},
+			{
+				Name:        "package validate",
+				Description: "Validate JSON or YAML package descriptors for the user packages directory (~/.portunix/packages)",
+				Arguments:   []aihelp.Argument{{Name: "descriptor", Type: "path", Required: true}},
+				Examples:    []string{"portunix package validate mytool.yaml"},
+			},
+			{
+				Name:        "package registry",
+				Description: "Manage remote package registries (add <name> <url>, list, update [name], remove <name>)",
+				Examples:    []string{"portunix package registry add team https://example.com/portunix-packages", "portunix package registry update"},
+			},
+		},
+	}.Print()
+}
@@ -6,12 +6,21 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"portunix.ai/app/metrics"
+	"portunix.ai/app/proxy"
 	"portunix.ai/portunix/src/helpers/ptx-installer/engine"
 	"portunix.ai/portunix/src/helpers/ptx-installer/registry"
 )
@@ -21,6 +30,9 @@ func init() {
 	registry.SetEmbeddedAssets(embeddedAssets)
 	// Pass embedded scripts to engine package
 	engine.SetEmbeddedScripts(embeddedScripts)
+	if profiles, err := fs.Sub(embeddedProfiles, "assets"); err == nil {
+		engine.SetEmbeddedProfiles(profiles)
+	}
 }
 
 var version = "dev"
@@ -42,7 +54,8 @@ This binary is typically invoked by the main portunix dispatcher and should not
 }
 
 // handleCommand dispatches commands routed to this helper by the parent portunix
-// binary (see src/dispatcher/dispatcher.go): "install" and "package". args arrive
+// binary (see src/dispatcher/dispatcher.go): "install", "uninstall", "upgrade"
+// and "package". args arrive
 // stripped of the binary name, so args[0] is the top-level command. Also handles
 // the --version / -v meta-flag used by the dispatcher for version discovery.
 func handleCommand(args []string) {
@@ -51,9 +64,11 @@ func handleCommand(args []string) {
 		fmt.Println("No command specified")
 		fmt.Println("Usage: ptx-installer [command] [arguments]")
 		fmt.Println("\nAvailable commands:")
-		fmt.Println("  install  - Install software packages")
-		fmt.Println("  package  - Package management operations")
-		fmt.Println("  --help   - Show this help")
+		fmt.Println("  install   - Install software packages")
+		fmt.Println("  uninstall - Remove packages installed by portunix")
+		fmt.Println("  upgrade   - Upgrade packages installed by portunix")
+		fmt.Println("  package   - Package management operations")
+		fmt.Println("  --help    - Show this help")
 		return
 	}
 
@@ -64,12 +79,20 @@ func handleCommand(args []string) {
 		fmt.Printf("ptx-installer version %s\n", version)
 		return
 	}
+	if command == "--help-ai" || (len(args) > 1 && args[1] == "--help-ai") {
+		showHelpAI()
+		return
+	}
 
 	subArgs := args[1:]
 
 	switch command {
 	case "install":
 		handleInstall(subArgs)
+	case "uninstall":
+		handleUninstall(subArgs)
+	case "upgrade":
+		handleUpgrade(subArgs)
 	case "package":
 		handlePackage(subArgs)
 	default:
@@ -79,6 +102,15 @@ func handleCommand(args []string) {
 }
 
 func handleInstall(args []string) {
+	if len(args) > 0 && args[0] == "apply" {
+		handleInstallApply(args[1:])
+		return
+	}
+	if len(args) > 0 && args[0] == "profile" {
+		handleInstallProfile(args[1:])
+		return
+	}
+
 	// Check for help flag first (before processing any arguments as package names)
 	for _, arg := range args {
 		if arg == "--help" || arg == "-h" {
@@ -94,12 +126,20 @@ func handleInstall(args []string) {
 	}
 
 	// Parse arguments
-	packageName := args[0]
+	packages := installPackageArgs(args)
+	if len(packages) == 0 {
+		showInstallHelp()
+		return
+	}
+	if len(packages) > 1 {
+		handleParallelInstall(packages, args)
+		return
+	}
+	packageName := packages[0]
 	dryRun := false
 
 	// Parse flags
-	for i := 1; i < len(args); i++ {
-		arg := args[i]
+	for _, arg := range args {
 		if arg == "--dry-run" {
 			dryRun = true
 		}
@@ -107,17 +147,10 @@ func handleInstall(args []string) {
 
 	// Handle special container runtime packages
 	switch strings.ToLower(packageName) {
-	case "docker":
-		dockerInstaller := engine.NewDockerInstaller(dryRun)
-		if err := dockerInstaller.Install(); err != nil {
-			fmt.Printf("\n❌ Docker installation failed: %v\n", err)
-			os.Exit(1)
-		}
-		return
-	case "podman":
-		podmanInstaller := engine.NewPodmanInstaller(dryRun)
-		if err := podmanInstaller.Install(); err != nil {
-			fmt.Printf("\n❌ Podman installation failed: %v\n", err)
+	case "docker", "podman":
+		if err := installContainerRuntime(packageName, dryRun); err != nil {
+			fmt.Printf("\n❌ %s installation failed: %v\n", runtimeDisplayName(packageName), err)
+			metrics.ReportFailure(err)
 			os.Exit(1)
 		}
 		return
@@ -128,10 +161,11 @@ func handleInstall(args []string) {
 		PackageName: packageName,
 		DryRun:      dryRun,
 		Force:       false,
+		Offline:     engine.OfflineFromEnv(),
 	}
 
 	// Parse additional flags
-	for i := 1; i < len(args); i++ {
+	for i := 0; i < len(args); i++ {
 		arg := args[i]
 
 		if strings.HasPrefix(arg, "--variant=") {
@@ -144,8 +178,15 @@ func handleInstall(args []string) {
 		} else if arg == "--path" && i+1 < len(args) {
 			options.InstallPath = args[i+1]
 			i++ // Skip next argument as it's the path value
+		} else if strings.HasPrefix(arg, "--backend=") {
+			options.Backend = strings.TrimPrefix(arg, "--backend=")
+		} else if arg == "--backend" && i+1 < len(args) {
+			options.Backend = args[i+1]
+			i++
 		} else if arg == "--force" {
 			options.Force = true
+		} else if arg == "--offline" {
+			options.Offline = true
 		} else if strings.HasPrefix(arg, "--db-host=") {
 			options.DBHost = strings.TrimPrefix(arg, "--db-host=")
 		} else if arg == "--db-host" && i+1 < len(args) {
@@ -169,6 +210,11 @@ func handleInstall(args []string) {
 		}
 	}
 
+	if err := engine.ValidateBackend(options.Backend); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
 	// Create installer
 	installer, err := engine.NewInstaller("./assets")
 	if err != nil {
@@ -179,12 +225,499 @@ func handleInstall(args []string) {
 	// Perform installation
 	if err := installer.Install(options); err != nil {
 		fmt.Printf("\n❌ Installation failed: %v\n", err)
+		metrics.ReportFailure(err)
 		os.Exit(1)
 	}
 
 	fmt.Println("\n✅ Installation completed successfully!")
 }
 
+// installValueFlags are install options whose value may be the next argument
+var installValueFlags = map[string]bool{
+	"--variant": true, "--path": true, "--jobs": true, "--backend": true,
+	"--db-host": true, "--db-port": true, "--db-user": true, "--db-password": true,
+}
+
+// installPackageArgs returns the package names among install arguments
+func installPackageArgs(args []string) []string {
+	var packages []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case installValueFlags[arg]:
+			i++ // Skip the option value
+		case strings.HasPrefix(arg, "-"):
+		default:
+			packages = append(packages, arg)
+		}
+	}
+	return packages
+}
+
+// handleParallelInstall installs several packages along their dependency
+// graph, running independent installations at the same time. Each package
+// is installed by a child ptx-installer process whose output goes to a log
+// file while the dashboard shows the progress.
+func handleParallelInstall(packages []string, args []string) {
+	jobs := engine.DefaultJobs()
+	dryRun, force := false, false
+	offline := engine.OfflineFromEnv()
+	var variant, backend string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		value := ""
+		if installValueFlags[arg] && i+1 < len(args) {
+			value = args[i+1]
+			i++
+		} else if idx := strings.Index(arg, "="); idx > 0 && installValueFlags[arg[:idx]] {
+			arg, value = arg[:idx], arg[idx+1:]
+		}
+		switch arg {
+		case "--dry-run":
+			dryRun = true
+		case "--force":
+			force = true
+		case "--offline":
+			offline = true
+		case "--variant":
+			variant = value
+		case "--backend":
+			if err := engine.ValidateBackend(value); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			backend = value
+		case "--jobs":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				fmt.Printf("❌ Invalid --jobs value: %s\n", value)
+				os.Exit(1)
+			}
+			jobs = n
+		case "--path", "--db-host", "--db-port", "--db-user", "--db-password":
+			fmt.Printf("❌ %s can only be used when installing a single package\n", arg)
+			os.Exit(1)
+		}
+	}
+
+	installer, err := engine.NewInstaller("./assets")
+	if err != nil {
+		fmt.Printf("❌ Error creating installer: %v\n", err)
+		os.Exit(1)
+	}
+	tasks, err := installer.PlanInstall(packages, variant)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(engine.FormatTaskPlan(tasks, jobs))
+	if dryRun {
+		fmt.Println("\n🔍 DRY RUN MODE - No packages were installed")
+		return
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		fmt.Printf("❌ Cannot locate ptx-installer: %v\n", err)
+		os.Exit(1)
+	}
+	logDir := installLogDir()
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		fmt.Printf("❌ Failed to create log directory: %v\n", err)
+		os.Exit(1)
+	}
+	exclusive := false
+	for _, task := range tasks {
+		task.LogPath = filepath.Join(logDir, task.Name+".log")
+		task.Run = childInstall(executable, task, backend, force, offline)
+		exclusive = exclusive || task.Exclusive
+	}
+	if exclusive {
+		// Ask for the sudo password once, before output goes to log files
+		refreshSudo()
+	}
+
+	fmt.Println()
+	dashboard := engine.NewDashboard(tasks)
+	dashboard.Start()
+	start := time.Now()
+	results := engine.RunInstallTasks(tasks, jobs, dashboard.Update)
+	dashboard.Stop()
+
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	fmt.Printf("\n📦 %d installed, %d failed, %d skipped in %s\n",
+		counts[engine.TaskDone], counts[engine.TaskFailed], counts[engine.TaskSkipped], time.Since(start).Round(time.Second))
+	if counts[engine.TaskFailed] > 0 || counts[engine.TaskSkipped] > 0 {
+		for _, r := range results {
+			if r.Status == engine.TaskFailed || r.Status == engine.TaskSkipped {
+				fmt.Printf("   ❌ %s: %s\n", r.Name, r.Error)
+			}
+		}
+		fmt.Printf("   Logs: %s\n", logDir)
+		os.Exit(1)
+	}
+	fmt.Println("\n✅ Installation completed successfully!")
+}
+
+// childInstall returns a task runner installing one package with a child
+// ptx-installer process
+func childInstall(executable string, task *engine.InstallTask, backend string, force, offline bool) func() error {
+	return func() error {
+		childArgs := []string{"install", task.Name}
+		if task.Variant != "" {
+			childArgs = append(childArgs, "--variant="+task.Variant)
+		}
+		if backend != "" && !task.Dependency {
+			childArgs = append(childArgs, "--backend="+backend)
+		}
+		if force && !task.Dependency {
+			childArgs = append(childArgs, "--force")
+		}
+		if offline {
+			childArgs = append(childArgs, "--offline")
+		}
+		logFile, err := os.Create(task.LogPath)
+		if err != nil {
+			return err
+		}
+		defer logFile.Close()
+
+		cmd := exec.Command(executable, childArgs...)
+		cmd.Stdout = logFile
+		cmd.Stderr = logFile
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("installation failed: %w", err)
+		}
+		return nil
+	}
+}
+
+// installLogDir is where parallel installations write their output
+func installLogDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "portunix-install-logs")
+	}
+	return filepath.Join(homeDir, ".portunix", "logs", "install")
+}
+
+// refreshSudo caches sudo credentials for installations through system
+// package managers; it does nothing on Windows, as root or without sudo
+func refreshSudo() {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		return
+	}
+	if _, err := exec.LookPath("sudo"); err != nil {
+		return
+	}
+	cmd := exec.Command("sudo", "-v")
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Printf("⚠️  sudo authentication failed: %v\n", err)
+	}
+}
+
+// installContainerRuntime installs docker or podman with their dedicated
+// installers instead of the generic registry flow
+func installContainerRuntime(name string, dryRun bool) error {
+	if strings.EqualFold(name, "podman") {
+		return engine.NewPodmanInstaller(dryRun).Install()
+	}
+	return engine.NewDockerInstaller(dryRun).Install()
+}
+
+func runtimeDisplayName(name string) string {
+	if strings.EqualFold(name, "podman") {
+		return "Podman"
+	}
+	return "Docker"
+}
+
+// handleInstallApply converges the system to a package manifest
+func handleInstallApply(args []string) {
+	var manifestPath string
+	dryRun := false
+	formatJSON := false
+	offline := engine.OfflineFromEnv()
+	for _, arg := range args {
+		switch {
+		case arg == "--help" || arg == "-h":
+			showInstallApplyHelp()
+			return
+		case arg == "--dry-run":
+			dryRun = true
+		case arg == "--offline":
+			offline = true
+		case arg == "--json" || arg == "--format=json":
+			formatJSON = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Printf("Unknown option: %s\n", arg)
+			os.Exit(1)
+		case manifestPath == "":
+			manifestPath = arg
+		}
+	}
+	if manifestPath == "" {
+		showInstallApplyHelp()
+		os.Exit(1)
+	}
+
+	manifest, err := engine.LoadPackageManifest(manifestPath)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	installer, err := engine.NewInstaller("./assets")
+	if err != nil {
+		fmt.Printf("❌ Error creating installer: %v\n", err)
+		os.Exit(1)
+	}
+
+	applyManifest(installer, manifest, "Package manifest "+manifestPath, dryRun, offline, formatJSON)
+}
+
+// applyManifest converges the system to a manifest and prints the summary
+// report; it exits with 1 when a package failed
+func applyManifest(installer *engine.Installer, manifest *engine.PackageManifest, title string, dryRun, offline, formatJSON bool) {
+	results := installer.Apply(manifest, dryRun, func(options *engine.InstallOptions) error {
+		switch strings.ToLower(options.PackageName) {
+		case "docker", "podman":
+			return installContainerRuntime(options.PackageName, false)
+		}
+		options.Offline = offline
+		return installer.Install(options)
+	})
+
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Action]++
+	}
+	if formatJSON {
+		jsonData, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			fmt.Printf("Error generating JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		fmt.Printf("\n📋 %s", title)
+		if dryRun {
+			fmt.Print(" (dry run)")
+		}
+		fmt.Println()
+		fmt.Println("═══════════════════════════════════════════════════════════")
+		icons := map[string]string{
+			engine.ApplyInstalled: "✅",
+			engine.ApplyUpdated:   "🔄",
+			engine.ApplySkipped:   "⏭️ ",
+			engine.ApplyFailed:    "❌",
+		}
+		for _, r := range results {
+			fmt.Printf("%s %-20s %-10s %-12s %s\n", icons[r.Action], r.Name, r.Action, r.Version, r.Detail)
+		}
+		format := "\nSummary: %d installed, %d updated, %d skipped, %d failed\n"
+		if dryRun {
+			format = "\nSummary: %d to install, %d to update, %d skipped, %d failed\n"
+		}
+		fmt.Printf(format, counts[engine.ApplyInstalled], counts[engine.ApplyUpdated], counts[engine.ApplySkipped], counts[engine.ApplyFailed])
+	}
+	if counts[engine.ApplyFailed] > 0 {
+		metrics.ReportFailure(fmt.Errorf("%d packages failed to apply", counts[engine.ApplyFailed]))
+		os.Exit(1)
+	}
+}
+
+// handleInstallProfile lists, shows and installs workstation profiles
+func handleInstallProfile(args []string) {
+	var name string
+	dryRun := false
+	formatJSON := false
+	offline := engine.OfflineFromEnv()
+	for _, arg := range args {
+		switch {
+		case arg == "--help" || arg == "-h":
+			showInstallProfileHelp()
+			return
+		case arg == "--dry-run":
+			dryRun = true
+		case arg == "--offline":
+			offline = true
+		case arg == "--json" || arg == "--format=json":
+			formatJSON = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Printf("Unknown option: %s\n", arg)
+			os.Exit(1)
+		case name == "":
+			name = arg
+		}
+	}
+
+	switch name {
+	case "":
+		showInstallProfileHelp()
+		os.Exit(1)
+	case "list":
+		listProfiles(formatJSON)
+		return
+	case "show":
+		if len(args) < 2 || strings.HasPrefix(args[1], "-") {
+			fmt.Println("Usage: portunix install profile show <name>")
+			os.Exit(1)
+		}
+		showProfile(args[1], formatJSON)
+		return
+	}
+
+	profile, err := engine.GetProfile(name)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	installer, err := engine.NewInstaller("./assets")
+	if err != nil {
+		fmt.Printf("❌ Error creating installer: %v\n", err)
+		os.Exit(1)
+	}
+	if !formatJSON {
+		fmt.Printf("🧰 Installing profile %s (%d packages)\n", profile.Name, len(profile.Packages))
+	}
+	applyManifest(installer, &profile.PackageManifest, "Profile "+profile.Name, dryRun, offline, formatJSON)
+}
+
+// listProfiles prints the built-in and user-defined profiles
+func listProfiles(formatJSON bool) {
+	profiles, warnings := engine.LoadProfiles()
+	if formatJSON {
+		type profileJSON struct {
+			Name        string   `json:"name"`
+			Description string   `json:"description,omitempty"`
+			Source      string   `json:"source"`
+			Packages    []string `json:"packages"`
+		}
+		list := make([]profileJSON, 0, len(profiles))
+		for _, p := range profiles {
+			list = append(list, profileJSON{Name: p.Name, Description: p.Description, Source: p.Source, Packages: profilePackageNames(p)})
+		}
+		jsonData, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			fmt.Printf("Error generating JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	for _, w := range warnings {
+		fmt.Printf("⚠️  %v\n", w)
+	}
+	fmt.Println("🧰 Installation profiles:")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	for _, p := range profiles {
+		fmt.Printf("%-16s %-8s %s\n", p.Name, p.Source, p.Description)
+		fmt.Printf("%-16s %-8s %s\n", "", "", strings.Join(profilePackageNames(p), ", "))
+	}
+	fmt.Printf("\nUser profiles: %s\n", engine.UserProfilesDir())
+}
+
+// showProfile prints the packages of one profile
+func showProfile(name string, formatJSON bool) {
+	profile, err := engine.GetProfile(name)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if formatJSON {
+		jsonData, err := json.MarshalIndent(profile, "", "  ")
+		if err != nil {
+			fmt.Printf("Error generating JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(jsonData))
+		return
+	}
+	fmt.Printf("🧰 Profile: %s (%s)\n", profile.Name, profile.Source)
+	if profile.Description != "" {
+		fmt.Printf("   %s\n", profile.Description)
+	}
+	fmt.Println("\nPackages:")
+	for _, p := range profile.Packages {
+		line := "  - " + p.Name
+		if p.Variant != "" {
+			line += " (variant " + p.Variant + ")"
+		}
+		if p.Version != "" {
+			line += " version " + p.Version
+		}
+		if len(p.OS) > 0 {
+			line += " [" + strings.Join(p.OS, ", ") + "]"
+		}
+		fmt.Println(line)
+	}
+}
+
+func profilePackageNames(p *engine.Profile) []string {
+	names := make([]string, 0, len(p.Packages))
+	for _, pkg := range p.Packages {
+		names = append(names, pkg.Name)
+	}
+	return names
+}
+
+func showInstallProfileHelp() {
+	fmt.Println("Install a workstation profile: a named set of packages")
+	fmt.Println("\nUsage: portunix install profile <name> [options]")
+	fmt.Println("       portunix install profile list [--json]")
+	fmt.Println("       portunix install profile show <name> [--json]")
+	fmt.Println("\nThe packages of the profile are installed as a batch like")
+	fmt.Println("'portunix install apply': missing packages are installed, the rest skipped.")
+	fmt.Println("\nUser-defined profiles are YAML files in ~/.portunix/profiles")
+	fmt.Println("(PORTUNIX_PROFILES_DIR) and override built-in profiles of the same name:")
+	fmt.Println("  name: my-stack")
+	fmt.Println("  description: Tools for my project")
+	fmt.Println("  packages:")
+	fmt.Println("    - name: go")
+	fmt.Println("    - name: java")
+	fmt.Println("      variant: \"21\"")
+	fmt.Println("\nOptions:")
+	fmt.Println("  --dry-run            Show what would be installed")
+	fmt.Println("  --offline            Install only from cached downloads")
+	fmt.Println("  --json               Output in JSON format")
+	fmt.Println("  -h, --help           Show this help message")
+	fmt.Println("\nExamples:")
+	fmt.Println("  portunix install profile list")
+	fmt.Println("  portunix install profile go-backend --dry-run")
+	fmt.Println("  portunix install profile data-science")
+}
+
+func showInstallApplyHelp() {
+	fmt.Println("Converge the system to a package manifest")
+	fmt.Println("\nUsage: portunix install apply <packages.yaml> [options]")
+	fmt.Println("\nMissing packages are installed, packages with another version than the")
+	fmt.Println("wanted one are reinstalled and the rest are skipped.")
+	fmt.Println("\nManifest format:")
+	fmt.Println("  packages:")
+	fmt.Println("    - name: nodejs")
+	fmt.Println("      version: \"20\"          # version or prefix, default any")
+	fmt.Println("    - name: java")
+	fmt.Println("      variant: \"21\"")
+	fmt.Println("      os: [linux, darwin]      # platform conditions, default all")
+	fmt.Println("      arch: [amd64]")
+	fmt.Println("\nOptions:")
+	fmt.Println("  --dry-run            Show what would be installed or updated")
+	fmt.Println("  --offline            Install only from cached downloads")
+	fmt.Println("  --json               Output the results in JSON format")
+	fmt.Println("  -h, --help           Show this help message")
+	fmt.Println("\nExamples:")
+	fmt.Println("  portunix install apply packages.yaml --dry-run")
+	fmt.Println("  portunix install apply packages.yaml")
+}
+
 func handlePackage(args []string) {
 	// Check for help flag first
 	for _, arg := range args {
@@ -210,6 +743,14 @@ func handlePackage(args []string) {
 		handlePackageSearch(subArgs)
 	case "info":
 		handlePackageInfo(subArgs)
+	case "validate":
+		handlePackageValidate(subArgs)
+	case "registry":
+		handlePackageRegistry(subArgs)
+	case "installed":
+		handlePackageInstalled(subArgs)
+	case "backends":
+		handlePackageBackends(subArgs)
 	default:
 		fmt.Printf("Unknown package subcommand: %s\n", subcommand)
 		fmt.Println("Use 'portunix package --help' for available subcommands")
@@ -225,6 +766,16 @@ func showPackageHelp() {
 	fmt.Println("  list     List all available packages")
 	fmt.Println("  search   Search for packages by name or description")
 	fmt.Println("  info     Show detailed information about a package")
+	fmt.Println("  validate Check a package descriptor (JSON or YAML)")
+	fmt.Println("  registry Manage remote package registries")
+	fmt.Println("  installed List packages installed by portunix")
+	fmt.Println("  backends List system package managers used for native packages")
+	fmt.Println()
+	fmt.Println("Custom packages:")
+	fmt.Printf("  Descriptors in %s (*.json, *.yaml) override built-in packages.\n", registry.UserPackagesDir())
+	fmt.Println("  Remote registries are downloaded with 'portunix package registry update';")
+	fmt.Println("  they add packages but do not replace built-in ones.")
+	fmt.Println("  Private https registries use the bearer token stored as 'portunix secret set registry/<name>'.")
 	fmt.Println()
 	fmt.Println("Options:")
 	fmt.Println("  -h, --help   Show this help message")
@@ -234,6 +785,398 @@ func showPackageHelp() {
 	fmt.Println("  portunix package list --category development/languages")
 	fmt.Println("  portunix package search python")
 	fmt.Println("  portunix package info nodejs")
+	fmt.Println("  portunix package installed")
+	fmt.Println("  portunix package backends")
+	fmt.Println("  portunix package validate ./mytool.yaml")
+	fmt.Println("  portunix package registry add team https://example.com/portunix-packages")
+}
+
+// handlePackageBackends lists the package manager backends and whether
+// they are available on this system
+func handlePackageBackends(args []string) {
+	formatJSON := len(args) > 0 && (args[0] == "--json" || args[0] == "--format=json")
+	type backendJSON struct {
+		Name      string `json:"name"`
+		Available bool   `json:"available"`
+	}
+	var backends []backendJSON
+	for _, b := range engine.PackageBackends() {
+		backends = append(backends, backendJSON{Name: b.Name(), Available: b.Available()})
+	}
+	if formatJSON {
+		jsonData, err := json.MarshalIndent(backends, "", "  ")
+		if err != nil {
+			fmt.Printf("Error generating JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	fmt.Println("🔌 Package manager backends (in order of preference):")
+	for _, b := range backends {
+		status := "not available"
+		if b.Available {
+			status = "✅ available"
+		}
+		fmt.Printf("   %-12s %s\n", b.Name, status)
+	}
+	fmt.Println("\nPackages declaring a native package for an available backend are installed")
+	fmt.Println("through it; use --backend=direct to install the registry package instead.")
+}
+
+// handlePackageInstalled lists the installed packages database
+func handlePackageInstalled(args []string) {
+	formatJSON := false
+	for _, arg := range args {
+		switch arg {
+		case "--json", "--format=json":
+			formatJSON = true
+		default:
+			fmt.Println("Usage: portunix package installed [--json]")
+			return
+		}
+	}
+
+	state, err := engine.LoadInstallState()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	records := state.List()
+	if formatJSON {
+		jsonData, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			fmt.Printf("Error generating JSON: %v\n", err)
+			return
+		}
+		fmt.Println(string(jsonData))
+		return
+	}
+	if len(records) == 0 {
+		fmt.Println("No packages installed by portunix")
+		return
+	}
+
+	fmt.Printf("%-20s %-15s %-15s %-12s %s\n", "NAME", "VERSION", "VARIANT", "TYPE", "INSTALLED")
+	for _, r := range records {
+		fmt.Printf("%-20s %-15s %-15s %-12s %s\n", r.Name, r.Version, r.Variant, r.Type, r.InstalledAt.Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("\n%d package(s), database: %s\n", len(records), engine.InstalledDBPath())
+}
+
+// handleUninstall removes packages installed by portunix
+func handleUninstall(args []string) {
+	var names []string
+	dryRun := false
+	for _, arg := range args {
+		switch {
+		case arg == "--help" || arg == "-h":
+			showUninstallHelp()
+			return
+		case arg == "--dry-run":
+			dryRun = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Printf("Unknown option: %s\n", arg)
+			os.Exit(1)
+		default:
+			names = append(names, arg)
+		}
+	}
+	if len(names) == 0 {
+		showUninstallHelp()
+		os.Exit(1)
+	}
+
+	installer, err := engine.NewInstaller("./assets")
+	if err != nil {
+		fmt.Printf("❌ Error creating installer: %v\n", err)
+		os.Exit(1)
+	}
+	failed := false
+	for _, name := range names {
+		if err := installer.Uninstall(name, dryRun); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			if errors.Is(err, engine.ErrNotInstalled) {
+				fmt.Println("   See 'portunix package installed' for packages portunix can remove")
+			}
+			failed = true
+			continue
+		}
+		if !dryRun {
+			fmt.Printf("✅ %s uninstalled\n", name)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func showUninstallHelp() {
+	fmt.Println("Remove packages installed by portunix")
+	fmt.Println("\nUsage: portunix uninstall <package>... [options]")
+	fmt.Println("\nPackages are removed with the uninstall commands of their definition,")
+	fmt.Println("through the system package manager they were installed with, or by")
+	fmt.Println("deleting the files recorded at install time.")
+	fmt.Println("\nOptions:")
+	fmt.Println("  --dry-run            Show what would be removed")
+	fmt.Println("  -h, --help           Show this help message")
+	fmt.Println("\nExamples:")
+	fmt.Println("  portunix uninstall hugo")
+	fmt.Println("  portunix uninstall nodejs --dry-run")
+}
+
+// handleUpgrade upgrades packages installed by portunix to the version of
+// their variant in the registry
+func handleUpgrade(args []string) {
+	var names []string
+	all, dryRun, formatJSON := false, false, false
+	offline := engine.OfflineFromEnv()
+	for _, arg := range args {
+		switch {
+		case arg == "--help" || arg == "-h":
+			showUpgradeHelp()
+			return
+		case arg == "--all":
+			all = true
+		case arg == "--dry-run" || arg == "--check":
+			dryRun = true
+		case arg == "--offline":
+			offline = true
+		case arg == "--json" || arg == "--format=json":
+			formatJSON = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Printf("Unknown option: %s\n", arg)
+			os.Exit(1)
+		default:
+			names = append(names, arg)
+		}
+	}
+	if !all && len(names) == 0 {
+		showUpgradeHelp()
+		os.Exit(1)
+	}
+
+	installer, err := engine.NewInstaller("./assets")
+	if err != nil {
+		fmt.Printf("❌ Error creating installer: %v\n", err)
+		os.Exit(1)
+	}
+	state, err := engine.LoadInstallState()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	var records []*engine.InstalledPackage
+	failed := false
+	if all {
+		records = state.List()
+	}
+	for _, name := range names {
+		record, ok := state.Get(name)
+		if !ok {
+			fmt.Printf("❌ %s: %v\n", name, engine.ErrNotInstalled)
+			failed = true
+			continue
+		}
+		records = append(records, record)
+	}
+
+	infos := make([]engine.UpgradeInfo, 0, len(records))
+	for _, record := range records {
+		infos = append(infos, installer.CheckUpgrade(record))
+	}
+	if formatJSON {
+		jsonData, err := json.MarshalIndent(infos, "", "  ")
+		if err != nil {
+			fmt.Printf("Error generating JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(jsonData))
+		if dryRun {
+			return
+		}
+	}
+
+	upgraded := 0
+	for idx, info := range infos {
+		if !info.Upgradable {
+			if !formatJSON {
+				fmt.Printf("✔️  %s %s: %s\n", info.Name, info.Installed, info.Detail)
+			}
+			continue
+		}
+		fmt.Printf("⬆️  %s %s -> %s (variant %s)\n", info.Name, info.Installed, info.Available, info.Variant)
+		if info.Changelog != "" {
+			fmt.Println("📝 Changelog:")
+			for _, line := range strings.Split(strings.TrimSpace(info.Changelog), "\n") {
+				fmt.Printf("   %s\n", line)
+			}
+		}
+		if dryRun {
+			continue
+		}
+		err := installer.Upgrade(records[idx], func(options *engine.InstallOptions) error {
+			options.Offline = offline
+			return installer.Install(options)
+		})
+		if err != nil {
+			fmt.Printf("❌ Upgrade of %s failed: %v\n", info.Name, err)
+			metrics.ReportFailure(err)
+			failed = true
+			continue
+		}
+		upgraded++
+	}
+
+	if dryRun {
+		fmt.Println("\n🔍 DRY RUN MODE - Nothing was upgraded")
+	} else if !formatJSON || upgraded > 0 {
+		fmt.Printf("\n✅ %d package(s) upgraded\n", upgraded)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func showUpgradeHelp() {
+	fmt.Println("Upgrade packages installed by portunix")
+	fmt.Println("\nUsage: portunix upgrade <package>... [options]")
+	fmt.Println("       portunix upgrade --all [options]")
+	fmt.Println("\nEach package is compared with the version of its installed variant in")
+	fmt.Println("the registry and reinstalled when the registry version is newer. The")
+	fmt.Println("changelog of the new version is shown when the package provides one.")
+	fmt.Println("\nOptions:")
+	fmt.Println("  --all                Upgrade every package installed by portunix")
+	fmt.Println("  --dry-run, --check   Only show available upgrades")
+	fmt.Println("  --offline            Install only from cached downloads")
+	fmt.Println("  --json               Output the comparison in JSON format")
+	fmt.Println("  -h, --help           Show this help message")
+	fmt.Println("\nExamples:")
+	fmt.Println("  portunix upgrade --all --check")
+	fmt.Println("  portunix upgrade nodejs")
+	fmt.Println("  portunix upgrade --all")
+}
+
+// handlePackageValidate parses and validates package descriptors so authors
+// can check them before dropping them into the user packages directory
+func handlePackageValidate(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: portunix package validate <descriptor>...")
+		fmt.Printf("\nValid descriptors can be copied to %s\n", registry.UserPackagesDir())
+		return
+	}
+	failed := false
+	for _, path := range args {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			var pkg *registry.Package
+			if pkg, err = registry.ParsePackageDescriptor(path, data); err == nil {
+				platforms := make([]string, 0, len(pkg.Spec.Platforms))
+				for platform := range pkg.Spec.Platforms {
+					platforms = append(platforms, platform)
+				}
+				sort.Strings(platforms)
+				fmt.Printf("✅ %s: package %s (%s)\n", path, pkg.Metadata.Name, strings.Join(platforms, ", "))
+				continue
+			}
+		}
+		fmt.Printf("❌ %s: %v\n", path, err)
+		failed = true
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func handlePackageRegistry(args []string) {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		fmt.Println("Manage remote package registries")
+		fmt.Println("\nUsage: portunix package registry <subcommand>")
+		fmt.Println("\nSubcommands:")
+		fmt.Println("  add <name> <url>   Add a registry and download its packages")
+		fmt.Println("  list               List registries")
+		fmt.Println("  update [name]      Download the packages of all or one registry")
+		fmt.Println("  remove <name>      Remove a registry and its packages")
+		fmt.Println("\nA registry serves <url>/registry/index.json and <url>/packages/<name>.json")
+		fmt.Println("(or the .yaml file named in the index), the layout of the built-in assets.")
+		return
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) != 3 {
+			fmt.Println("Usage: portunix package registry add <name> <url>")
+			os.Exit(1)
+		}
+		if err := registry.AddRemoteRegistry(args[1], args[2]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Registry %s added\n", args[1])
+		updateRemoteRegistries([]string{args[1]})
+	case "list", "ls":
+		registries, err := registry.LoadRemoteRegistries()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if len(registries) == 0 {
+			fmt.Println("No remote registries configured")
+			return
+		}
+		fmt.Printf("%-16s %-9s %-17s %s\n", "NAME", "PACKAGES", "UPDATED", "URL")
+		for _, r := range registries {
+			updated := "never"
+			if !r.UpdatedAt.IsZero() {
+				updated = r.UpdatedAt.Format("2006-01-02 15:04")
+			}
+			fmt.Printf("%-16s %-9d %-17s %s\n", r.Name, r.Packages, updated, r.URL)
+		}
+	case "update":
+		names := args[1:]
+		if len(names) == 0 {
+			registries, err := registry.LoadRemoteRegistries()
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			for _, r := range registries {
+				names = append(names, r.Name)
+			}
+		}
+		updateRemoteRegistries(names)
+	case "remove", "rm":
+		if len(args) != 2 {
+			fmt.Println("Usage: portunix package registry remove <name>")
+			os.Exit(1)
+		}
+		if err := registry.RemoveRemoteRegistry(args[1]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Registry %s removed\n", args[1])
+	default:
+		fmt.Printf("Unknown registry subcommand: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+func updateRemoteRegistries(names []string) {
+	failed := false
+	for _, name := range names {
+		count, err := registry.UpdateRemoteRegistry(name)
+		if err != nil {
+			fmt.Printf("❌ Registry %s: %v\n", name, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("📦 Registry %s: %d packages downloaded\n", name, count)
+	}
+	if failed {
+		os.Exit(1)
+	}
 }
 
 func handlePackageList(args []string) {
@@ -470,6 +1413,9 @@ func handlePackageInfo(args []string) {
 	fmt.Printf("   Display Name: %s\n", pkg.Metadata.DisplayName)
 	fmt.Printf("   Description:  %s\n", pkg.Metadata.Description)
 	fmt.Printf("   Category:     %s\n", pkg.Metadata.Category)
+	if pkg.Source != "" {
+		fmt.Printf("   Source:       %s\n", pkg.Source)
+	}
 
 	if pkg.Metadata.Homepage != "" {
 		fmt.Printf("   Homepage:     %s\n", pkg.Metadata.Homepage)
@@ -492,6 +1438,14 @@ func handlePackageInfo(args []string) {
 		}
 	}
 
+	// Native packages of system package managers
+	if len(pkg.Spec.Native) > 0 {
+		fmt.Printf("\n🔌 Native Packages:\n")
+		for _, backend := range engine.NativeBackendNames(pkg) {
+			fmt.Printf("   %-12s %s\n", backend, strings.Join(pkg.Spec.Native[backend], ", "))
+		}
+	}
+
 	// Platforms and variants
 	fmt.Printf("\n💻 Supported Platforms:\n")
 	for platformName, platformSpec := range pkg.Spec.Platforms {
@@ -537,11 +1491,17 @@ func handlePackageInfo(args []string) {
 func showInstallHelp() {
 	fmt.Println("Install software packages")
 	fmt.Println("\nUsage: portunix install <package> [options]")
+	fmt.Println("       portunix install <package> <package>... [--jobs N] [options]")
+	fmt.Println("       portunix install apply <packages.yaml> [--dry-run] [--offline] [--json]")
+	fmt.Println("       portunix install profile <name|list|show> [--dry-run] [--json]")
 	fmt.Println("\nOptions:")
 	fmt.Println("  --variant=<variant>  Select package variant (e.g., --variant=21 for Java 21)")
 	fmt.Println("  --path=<path>        Target installation path (for project generators like docusaurus)")
 	fmt.Println("  --dry-run            Preview installation without executing")
 	fmt.Println("  --force              Force reinstallation even if already installed")
+	fmt.Println("  --offline            Install only from cached downloads (also PORTUNIX_OFFLINE=1)")
+	fmt.Println("  --jobs=<n>           Parallel installations when installing several packages (default: CPUs, max 4)")
+	fmt.Println("  --backend=<name>     auto (default), direct, apt, dnf, pacman, zypper, brew, winget, chocolatey")
 	fmt.Println("  --db-host=<host>     Override container DB HOST env (container variants that read it)")
 	fmt.Println("  --db-port=<port>     Override container DB PORT env")
 	fmt.Println("  --db-user=<user>     Override container DB USER env")
@@ -552,9 +1512,23 @@ func showInstallHelp() {
 	fmt.Println("  portunix install java --variant=21")
 	fmt.Println("  portunix install docusaurus --path ./my-docs")
 	fmt.Println("  portunix install nodejs --dry-run")
+	fmt.Println("  portunix install nodejs --offline")
+	fmt.Println("  portunix install git nodejs hugo --jobs 4")
+	fmt.Println("  portunix install git --backend=direct")
 	fmt.Println("  portunix install odoo --variant=container-external-db --db-host=my-pg")
+	fmt.Println("  portunix install apply packages.yaml --dry-run")
+	fmt.Println("  portunix install profile go-backend")
 	fmt.Println("\nUse 'portunix package list' to see available packages")
 	fmt.Println("Use 'portunix package info <package>' for detailed package information")
+	fmt.Println("Use 'portunix cache list downloads' to see cached downloads")
+	fmt.Println("\nSeveral packages are installed along their dependency graph: missing")
+	fmt.Println("dependencies are added, independent packages install in parallel and")
+	fmt.Println("system package manager installs run one at a time. Output of each")
+	fmt.Println("package goes to ~/.portunix/logs/install/<package>.log.")
+	fmt.Println("\nPackages with a native package for an available system package manager")
+	fmt.Println("(winget/chocolatey, apt/dnf/pacman/zypper, Homebrew) are installed through")
+	fmt.Println("it unless a variant is chosen; the registry package is the fallback.")
+	fmt.Println("See 'portunix package backends'.")
 }
 
 func init() {
@@ -569,6 +1543,12 @@ func main() {
 	// Initialize embedded assets in registry package
 	registry.SetEmbeddedAssets(embeddedAssets)
 
+	// Started directly or by portunix: either way downloads must use the
+	// configured proxy and trust the proxy CA
+	if _, err := proxy.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: proxy configuration: %v\n", err)
+	}
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -37,6 +37,8 @@ type Package struct {
 	Kind       string      `json:"kind"`
 	Metadata   Metadata    `json:"metadata"`
 	Spec       PackageSpec `json:"spec"`
+	// Source is SourceBuiltin, "registry <name>" or the path of a user descriptor
+	Source string `json:"-"`
 }
 
 // Metadata contains package metadata
@@ -62,6 +64,10 @@ type PackageSpec struct {
 	AIPrompts    *AIPrompts              `json:"aiPrompts,omitempty"`
 	Dependencies []string                `json:"dependencies,omitempty"`
 	Templates    []string                `json:"templates,omitempty"`
+	// Native maps system package managers (apt, dnf, pacman, zypper, brew,
+	// winget, chocolatey) to the package names providing this package, so
+	// the install can be delegated to them
+	Native map[string][]string `json:"native,omitempty"`
 }
 
 // PlatformSpec represents platform-specific configuration
@@ -117,6 +123,12 @@ type VariantSpec struct {
 	Checksum          map[string]string `json:"checksum,omitempty"`
 	Container         *ContainerSpec    `json:"container,omitempty"`
 	AdditionalFiles   []AdditionalFile  `json:"additionalFiles,omitempty"`
+	// Uninstall lists commands that remove the variant, for installation
+	// types portunix cannot undo by itself (scripts, installers)
+	Uninstall []string `json:"uninstall,omitempty"`
+	// Changelog holds release notes or a release notes URL for Version,
+	// shown by 'portunix upgrade'
+	Changelog string `json:"changelog,omitempty"`
 }
 
 // AdditionalFile represents an extra file to download alongside the main package
@@ -224,10 +236,13 @@ type CategoryIndex struct {
 // LoadPackageRegistry loads the package registry from the assets directory
 // Priority 1: Try embedded assets (production/container mode)
 // Priority 2: Fallback to external assets (development mode)
+// User descriptors and remote registries are loaded on top of either.
 func LoadPackageRegistry(assetsPath string) (*PackageRegistry, error) {
 	// Priority 1: Try embedded assets first (production/container mode)
 	if registry, err := loadFromEmbedded(); err == nil {
 		fmt.Printf("Package registry loaded from embedded assets\n")
+		registry.loadUserPackages()
+		registry.generateIndex()
 		return registry, nil
 	} else {
 		fmt.Printf("Embedded assets not available, trying external assets: %v\n", err)
@@ -253,6 +268,8 @@ func LoadPackageRegistry(assetsPath string) (*PackageRegistry, error) {
 		fmt.Printf("Warning: Failed to load categories from external assets: %v\n", err)
 	}
 
+	registry.loadUserPackages()
+
 	// Generate index automatically from discovered packages
 	registry.generateIndex()
 
@@ -378,6 +395,7 @@ func (r *PackageRegistry) loadPackage(packagePath string) error {
 		return fmt.Errorf("package validation failed: %w", err)
 	}
 
+	pkg.Source = SourceBuiltin
 	r.packages[pkg.Metadata.Name] = &pkg
 	return nil
 }
@@ -438,8 +456,19 @@ func (r *PackageRegistry) validateMetadata(metadata *Metadata) error {
 
 // validatePackageSpec validates package specification
 func (r *PackageRegistry) validatePackageSpec(spec *PackageSpec) error {
-	if len(spec.Platforms) == 0 {
-		return fmt.Errorf("at least one platform must be specified")
+	if len(spec.Platforms) == 0 && len(spec.Native) == 0 {
+		return fmt.Errorf("at least one platform or native package must be specified")
+	}
+
+	// Validate native packages
+	validBackends := []string{"apt", "dnf", "pacman", "zypper", "brew", "winget", "chocolatey"}
+	for backend, packages := range spec.Native {
+		if !containsString(validBackends, backend) {
+			return fmt.Errorf("unsupported native backend '%s', must be one of: %v", backend, validBackends)
+		}
+		if len(packages) == 0 {
+			return fmt.Errorf("native backend %s lists no packages", backend)
+		}
 	}
 
 	// Validate platforms
@@ -894,6 +923,7 @@ func (r *PackageRegistry) loadPackageFromEmbedded(filename string) error {
 		return fmt.Errorf("embedded package validation failed: %w", err)
 	}
 
+	pkg.Source = SourceBuiltin
 	r.packages[pkg.Metadata.Name] = &pkg
 	return nil
 }
@@ -0,0 +1,341 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"portunix.ai/app/fileutil"
+	"portunix.ai/app/secret"
+)
+
+// EnvPackagesDir overrides the directory with user package descriptors
+const EnvPackagesDir = "PORTUNIX_PACKAGES_DIR"
+
+// Package sources shown by 'package info'
+const (
+	SourceBuiltin = "builtin"
+	sourceRemote  = "registry "
+)
+
+// UserPackagesDir returns the directory with user package descriptors.
+// *.json, *.yaml and *.yml files in it override built-in packages of the
+// same name; the remote subdirectory holds downloaded remote registries.
+func UserPackagesDir() string {
+	if dir := os.Getenv(EnvPackagesDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".portunix", "packages")
+	}
+	return filepath.Join(home, ".portunix", "packages")
+}
+
+func remoteDir() string {
+	return filepath.Join(UserPackagesDir(), "remote")
+}
+
+func remoteRegistriesFile() string {
+	return filepath.Join(remoteDir(), "registries.json")
+}
+
+// isDescriptorFile reports whether name is a package descriptor file
+func isDescriptorFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// ParsePackageDescriptor parses and validates a JSON or YAML package
+// descriptor. YAML uses the same field names as the JSON format.
+func ParsePackageDescriptor(name string, data []byte) (*Package, error) {
+	if ext := strings.ToLower(filepath.Ext(name)); ext == ".yaml" || ext == ".yml" {
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse package: %w", err)
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse package: %w", err)
+		}
+		data = converted
+	}
+	var pkg Package
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse package: %w", err)
+	}
+	if err := (&PackageRegistry{}).validatePackage(&pkg); err != nil {
+		return nil, fmt.Errorf("package validation failed: %w", err)
+	}
+	return &pkg, nil
+}
+
+// loadDescriptorDir loads the descriptors of dir over the registry
+// packages. Remote registries (source set) cannot replace a built-in
+// package; overriding one takes a user descriptor.
+func (r *PackageRegistry) loadDescriptorDir(dir, source string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Printf("Warning: Failed to read packages from %s: %v\n", dir, err)
+		}
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !isDescriptorFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err == nil {
+			var pkg *Package
+			if pkg, err = ParsePackageDescriptor(entry.Name(), data); err == nil {
+				if existing, ok := r.packages[pkg.Metadata.Name]; ok && source != "" && existing.Source == SourceBuiltin {
+					fmt.Printf("Warning: Ignoring package %s from %s, it would replace the built-in package; copy %s to %s to override it\n",
+						pkg.Metadata.Name, source, path, UserPackagesDir())
+					continue
+				}
+				pkg.Source = source
+				if source == "" {
+					pkg.Source = path
+				}
+				r.packages[pkg.Metadata.Name] = pkg
+				continue
+			}
+		}
+		fmt.Printf("Warning: Failed to load package %s: %v\n", path, err)
+	}
+}
+
+// loadUserPackages overlays the remote registries and then the user
+// descriptors, so user packages win over remote ones and over built-in
+// packages
+func (r *PackageRegistry) loadUserPackages() {
+	registries, err := LoadRemoteRegistries()
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	for _, remote := range registries {
+		r.loadDescriptorDir(filepath.Join(remoteDir(), remote.Name), sourceRemote+remote.Name)
+	}
+	r.loadDescriptorDir(UserPackagesDir(), "")
+}
+
+// RemoteRegistry is a package registry served over HTTP with the layout of
+// the built-in assets: <url>/registry/index.json lists the packages and
+// <url>/packages/<name>.json (or the file named in the index) describes them
+type RemoteRegistry struct {
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+	Packages  int       `json:"packages"`
+}
+
+// LoadRemoteRegistries returns the configured remote registries
+func LoadRemoteRegistries() ([]RemoteRegistry, error) {
+	data, err := os.ReadFile(remoteRegistriesFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var registries []RemoteRegistry
+	if err := json.Unmarshal(data, &registries); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", remoteRegistriesFile(), err)
+	}
+	return registries, nil
+}
+
+func saveRemoteRegistries(registries []RemoteRegistry) error {
+	sort.Slice(registries, func(i, j int) bool { return registries[i].Name < registries[j].Name })
+	if err := os.MkdirAll(remoteDir(), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(registries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFile(remoteRegistriesFile(), data, 0644)
+}
+
+// AddRemoteRegistry adds or replaces a remote registry. Call
+// UpdateRemoteRegistry to download its packages.
+func AddRemoteRegistry(name, rawURL string) error {
+	if !isValidPackageName(name) {
+		return fmt.Errorf("registry name must be lowercase alphanumeric with hyphens only, got '%s'", name)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http" && u.Scheme != "file") {
+		return fmt.Errorf("registry URL must be an http(s) or file URL, got '%s'", rawURL)
+	}
+	registries, err := LoadRemoteRegistries()
+	if err != nil {
+		return err
+	}
+	kept := registries[:0]
+	for _, r := range registries {
+		if r.Name != name {
+			kept = append(kept, r)
+		}
+	}
+	return saveRemoteRegistries(append(kept, RemoteRegistry{Name: name, URL: strings.TrimSuffix(rawURL, "/")}))
+}
+
+// RemoveRemoteRegistry removes a remote registry and its downloaded packages
+func RemoveRemoteRegistry(name string) error {
+	registries, err := LoadRemoteRegistries()
+	if err != nil {
+		return err
+	}
+	kept := registries[:0]
+	for _, r := range registries {
+		if r.Name != name {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) == len(registries) {
+		return fmt.Errorf("registry %s not found", name)
+	}
+	if err := os.RemoveAll(filepath.Join(remoteDir(), name)); err != nil {
+		return err
+	}
+	return saveRemoteRegistries(kept)
+}
+
+// UpdateRemoteRegistry downloads the index and packages of a registry. The
+// previous copy is replaced only when every package downloads and validates.
+func UpdateRemoteRegistry(name string) (int, error) {
+	registries, err := LoadRemoteRegistries()
+	if err != nil {
+		return 0, err
+	}
+	index := -1
+	for i, r := range registries {
+		if r.Name == name {
+			index = i
+		}
+	}
+	if index < 0 {
+		return 0, fmt.Errorf("registry %s not found", name)
+	}
+	base := registries[index].URL
+	// Private registries authenticate with a bearer token from the secret
+	// store, stored as registry/<name>; it is sent over https only
+	token, _, err := secret.Lookup(RegistrySecretName(name), "registry")
+	if err != nil {
+		return 0, err
+	}
+	if token != "" && !strings.HasPrefix(base, "https://") {
+		return 0, fmt.Errorf("refusing to send the %s token to %s: the registry URL is not https", RegistrySecretName(name), base)
+	}
+
+	data, err := fetch(base+"/registry/index.json", token)
+	if err != nil {
+		return 0, err
+	}
+	var idx RegistryIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return 0, fmt.Errorf("invalid registry index: %w", err)
+	}
+	if len(idx.Spec.Packages) == 0 {
+		return 0, fmt.Errorf("registry index lists no packages")
+	}
+
+	if err := os.MkdirAll(remoteDir(), 0755); err != nil {
+		return 0, err
+	}
+	staging, err := os.MkdirTemp(remoteDir(), "."+name+"-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(staging)
+	for _, entry := range idx.Spec.Packages {
+		file := entry
+		if !isDescriptorFile(file) {
+			file += ".json"
+		}
+		if file != filepath.Base(file) {
+			return 0, fmt.Errorf("invalid package entry %q in registry index", entry)
+		}
+		data, err := fetch(base+"/packages/"+file, token)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := ParsePackageDescriptor(file, data); err != nil {
+			return 0, fmt.Errorf("%s: %w", file, err)
+		}
+		if err := os.WriteFile(filepath.Join(staging, file), data, 0644); err != nil {
+			return 0, err
+		}
+	}
+
+	target := filepath.Join(remoteDir(), name)
+	if err := os.RemoveAll(target); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(staging, target); err != nil {
+		return 0, err
+	}
+	registries[index].UpdatedAt = time.Now()
+	registries[index].Packages = len(idx.Spec.Packages)
+	return len(idx.Spec.Packages), saveRemoteRegistries(registries)
+}
+
+// RegistrySecretName returns the secret store name of a remote registry's
+// access token
+func RegistrySecretName(name string) string {
+	return "registry/" + name
+}
+
+// fetch downloads a registry file from an http(s) or file URL, sending the
+// token as bearer authorization when set
+func fetch(rawURL, token string) ([]byte, error) {
+	if path, ok := strings.CutPrefix(rawURL, "file://"); ok {
+		return os.ReadFile(filepath.FromSlash(path))
+	}
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if token != "" && req.URL.Scheme != "https" {
+				return fmt.Errorf("refusing to follow a redirect to %s with the registry token", req.URL)
+			}
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+			return nil
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: HTTP %d", rawURL, resp.StatusCode)
+	}
+	// Descriptors are small; refuse anything that is clearly not one
+	return io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+}
@@ -0,0 +1,191 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"portunix.ai/app/secret"
+)
+
+const yamlDescriptor = `apiVersion: v1
+kind: Package
+metadata:
+  name: mytool
+  displayName: My Tool
+  description: Team tool
+  category: development/tools
+spec:
+  platforms:
+    linux:
+      type: tar.gz
+      variants:
+        latest:
+          version: 1.2.0
+          urls:
+            x64: https://example.com/mytool-linux-x64.tar.gz
+            arm64: https://example.com/mytool-linux-arm64.tar.gz
+          checksum:
+            x64: sha256:0000
+          binary: mytool
+      verification:
+        command: mytool --version
+        expectedExitCode: 0
+`
+
+func jsonDescriptor(name, description string) string {
+	return `{"apiVersion": "v1", "kind": "Package",
+  "metadata": {"name": "` + name + `", "displayName": "` + name + `", "description": "` + description + `", "category": "development/tools"},
+  "spec": {"platforms": {"linux": {"type": "script", "variants": {"latest": {"version": "1.0.0", "installScript": "true"}}}}}}`
+}
+
+func TestParsePackageDescriptorYAML(t *testing.T) {
+	pkg, err := ParsePackageDescriptor("mytool.yaml", []byte(yamlDescriptor))
+	if err != nil {
+		t.Fatalf("ParsePackageDescriptor failed: %v", err)
+	}
+	variant := pkg.Spec.Platforms["linux"].Variants["latest"]
+	if pkg.Metadata.Name != "mytool" || variant.URLs["arm64"] == "" || variant.Checksum["x64"] != "sha256:0000" {
+		t.Errorf("unexpected package %+v", pkg)
+	}
+	if v := pkg.Spec.Platforms["linux"].Verification; v == nil || v.Command != "mytool --version" {
+		t.Errorf("verification not parsed: %+v", v)
+	}
+
+	if _, err := ParsePackageDescriptor("bad.yaml", []byte("apiVersion: v1\nkind: Package\nmetadata:\n  name: Bad_Name\n")); err == nil {
+		t.Error("invalid descriptor should fail")
+	}
+}
+
+func TestLoadUserPackages(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv(EnvPackagesDir, userDir)
+	assets := t.TempDir()
+	os.MkdirAll(filepath.Join(assets, "packages"), 0755)
+	os.WriteFile(filepath.Join(assets, "packages", "git.json"), []byte(jsonDescriptor("git", "builtin git")), 0644)
+	os.WriteFile(filepath.Join(userDir, "git.json"), []byte(jsonDescriptor("git", "patched git")), 0644)
+	os.WriteFile(filepath.Join(userDir, "mytool.yaml"), []byte(yamlDescriptor), 0644)
+	os.WriteFile(filepath.Join(userDir, "broken.yaml"), []byte("kind: ["), 0644)
+
+	reg, err := LoadPackageRegistry(assets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	git, err := reg.GetPackage("git")
+	if err != nil || git.Metadata.Description != "patched git" || git.Source != filepath.Join(userDir, "git.json") {
+		t.Errorf("user descriptor should override the built-in package: %+v, %v", git, err)
+	}
+	if _, err := reg.GetPackage("mytool"); err != nil {
+		t.Errorf("YAML user package not loaded: %v", err)
+	}
+	if _, err := reg.GetPackage("broken"); err == nil {
+		t.Error("broken descriptor should be skipped")
+	}
+}
+
+func TestRemoteRegistry(t *testing.T) {
+	t.Setenv(EnvPackagesDir, t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/team/registry/index.json":
+			w.Write([]byte(`{"apiVersion": "v1", "kind": "PackageIndex", "spec": {"packages": ["teamtool", "mytool.yaml", "git"]}}`))
+		case "/team/packages/teamtool.json":
+			w.Write([]byte(jsonDescriptor("teamtool", "team tool")))
+		case "/team/packages/git.json":
+			w.Write([]byte(jsonDescriptor("git", "remote git")))
+		case "/team/packages/mytool.yaml":
+			w.Write([]byte(yamlDescriptor))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	if err := AddRemoteRegistry("Team", server.URL); err == nil {
+		t.Error("invalid registry name should fail")
+	}
+	if err := AddRemoteRegistry("team", "ftp://example.com"); err == nil {
+		t.Error("unsupported URL scheme should fail")
+	}
+	if err := AddRemoteRegistry("team", server.URL+"/team/"); err != nil {
+		t.Fatal(err)
+	}
+	count, err := UpdateRemoteRegistry("team")
+	if err != nil || count != 3 {
+		t.Fatalf("UpdateRemoteRegistry = %d, %v", count, err)
+	}
+	registries, _ := LoadRemoteRegistries()
+	if len(registries) != 1 || registries[0].Packages != 3 || registries[0].UpdatedAt.IsZero() {
+		t.Errorf("unexpected registries %+v", registries)
+	}
+
+	assets := t.TempDir()
+	os.MkdirAll(filepath.Join(assets, "packages"), 0755)
+	os.WriteFile(filepath.Join(assets, "packages", "git.json"), []byte(jsonDescriptor("git", "builtin git")), 0644)
+	reg, err := LoadPackageRegistry(assets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := reg.GetPackage("teamtool")
+	if err != nil || pkg.Source != "registry team" {
+		t.Errorf("remote package not loaded: %+v, %v", pkg, err)
+	}
+	if git, err := reg.GetPackage("git"); err != nil || git.Metadata.Description != "builtin git" {
+		t.Errorf("remote package should not replace the built-in one: %+v, %v", git, err)
+	}
+
+	// A failed update keeps the previous copy
+	if err := AddRemoteRegistry("team", server.URL+"/missing"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UpdateRemoteRegistry("team"); err == nil {
+		t.Error("update from a missing index should fail")
+	}
+	if _, err := os.Stat(filepath.Join(remoteDir(), "team", "teamtool.json")); err != nil {
+		t.Errorf("previous packages should be kept: %v", err)
+	}
+
+	if err := RemoveRemoteRegistry("team"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(remoteDir(), "team")); !os.IsNotExist(err) {
+		t.Error("registry packages should be removed")
+	}
+	if err := RemoveRemoteRegistry("team"); err == nil {
+		t.Error("removing an unknown registry should fail")
+	}
+}
+
+func TestRemoteRegistryTokenRequiresHTTPS(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv(secret.EnvPassphrase, "")
+	t.Setenv(EnvPackagesDir, t.TempDir())
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	if err := secret.Set(RegistrySecretName("team"), "s3cret", secret.BackendFile, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddRemoteRegistry("team", server.URL); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UpdateRemoteRegistry("team"); err == nil || !strings.Contains(err.Error(), "not https") {
+		t.Errorf("token sent to an http registry: %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("%d requests reached the http registry", requests)
+	}
+}
@@ -0,0 +1,269 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+)
+
+// Supported build tools
+const (
+	BuildToolMaven  = "maven"
+	BuildToolGradle = "gradle"
+)
+
+// ProjectInfo describes a detected Java project
+type ProjectInfo struct {
+	Dir         string
+	BuildTool   string // maven or gradle
+	BuildFile   string
+	Wrapper     string // path to mvnw/gradlew when present
+	JavaVersion string // required Java feature version, empty when unknown
+}
+
+// Patterns detecting the required Java version in build files
+var (
+	mavenVersionPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`<maven\.compiler\.release>\s*(?:1\.)?(\d+)`),
+		regexp.MustCompile(`<java\.version>\s*(?:1\.)?(\d+)`),
+		regexp.MustCompile(`<maven\.compiler\.source>\s*(?:1\.)?(\d+)`),
+		regexp.MustCompile(`<release>\s*(?:1\.)?(\d+)\s*</release>`),
+	}
+	gradleVersionPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`JavaLanguageVersion\.of\(\s*(\d+)\s*\)`),
+		regexp.MustCompile(`JavaVersion\.VERSION_(?:1_)?(\d+)`),
+		regexp.MustCompile(`(?:source|target)Compatibility\s*=\s*['"]?(?:1\.)?(\d+)`),
+	}
+)
+
+// DetectProject inspects dir for Maven or Gradle build files
+func DetectProject(dir string) (*ProjectInfo, error) {
+	info := &ProjectInfo{Dir: dir}
+
+	switch {
+	case fileExists(filepath.Join(dir, "pom.xml")):
+		info.BuildTool = BuildToolMaven
+		info.BuildFile = filepath.Join(dir, "pom.xml")
+	case fileExists(filepath.Join(dir, "build.gradle.kts")):
+		info.BuildTool = BuildToolGradle
+		info.BuildFile = filepath.Join(dir, "build.gradle.kts")
+	case fileExists(filepath.Join(dir, "build.gradle")):
+		info.BuildTool = BuildToolGradle
+		info.BuildFile = filepath.Join(dir, "build.gradle")
+	default:
+		return nil, fmt.Errorf("no pom.xml or build.gradle found in %s", dir)
+	}
+
+	info.Wrapper = findWrapper(dir, info.BuildTool)
+
+	data, err := os.ReadFile(info.BuildFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", info.BuildFile, err)
+	}
+	if info.BuildTool == BuildToolMaven {
+		info.JavaVersion = detectJavaVersion(string(data), mavenVersionPatterns)
+	} else {
+		info.JavaVersion = detectJavaVersion(string(data), gradleVersionPatterns)
+	}
+	return info, nil
+}
+
+// detectJavaVersion returns the first Java version matched by patterns
+func detectJavaVersion(content string, patterns []*regexp.Regexp) string {
+	for _, p := range patterns {
+		if m := p.FindStringSubmatch(content); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// findWrapper returns the path of mvnw/gradlew in dir, or empty
+func findWrapper(dir, tool string) string {
+	name := "mvnw"
+	if tool == BuildToolGradle {
+		name = "gradlew"
+	}
+	if runtime.GOOS == "windows" {
+		if tool == BuildToolGradle {
+			name += ".bat"
+		} else {
+			name += ".cmd"
+		}
+	}
+
+	path := filepath.Join(dir, name)
+	if fileExists(path) {
+		return path
+	}
+	return ""
+}
+
+// defaultBuildArgs returns the arguments used by "portunix java build"
+func defaultBuildArgs(tool string) []string {
+	if tool == BuildToolGradle {
+		return []string{"build"}
+	}
+	return []string{"package"}
+}
+
+// resolveProjectJDK selects the JDK for a project: .java-version pin, then the
+// version required by the build file (installing it when missing), then the
+// globally active JDK. Returns nil when the system Java should be used.
+func resolveProjectJDK(m *JDKManager, info *ProjectInfo) (*JDK, error) {
+	spec, source := m.Current()
+	if source == ".java-version" {
+		return m.requireInstalled(spec)
+	}
+
+	if info != nil && info.JavaVersion != "" {
+		jdk, err := m.Find(info.JavaVersion)
+		if err != nil {
+			return nil, err
+		}
+		if jdk != nil {
+			return jdk, nil
+		}
+		fmt.Printf("ℹ️  %s requires Java %s, installing...\n", filepath.Base(info.BuildFile), info.JavaVersion)
+		return m.Install("", info.JavaVersion)
+	}
+
+	if spec != "" {
+		return m.requireInstalled(spec)
+	}
+	return nil, nil
+}
+
+// handleBuildTool runs Maven or Gradle with the project JDK; tool is empty to
+// auto-detect and run the default build goal
+func handleBuildTool(tool string, args []string) (int, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return 1, err
+	}
+
+	info, detectErr := DetectProject(cwd)
+	if tool == "" {
+		if detectErr != nil {
+			return 1, detectErr
+		}
+		tool = info.BuildTool
+		if len(args) == 0 {
+			args = defaultBuildArgs(tool)
+		}
+	}
+	if info != nil && info.BuildTool != tool {
+		info = nil
+	}
+
+	m, err := NewJDKManager()
+	if err != nil {
+		return 1, err
+	}
+
+	jdk, err := resolveProjectJDK(m, info)
+	if err != nil {
+		return 1, err
+	}
+
+	binary, err := buildToolBinary(tool, info)
+	if err != nil {
+		return 1, err
+	}
+
+	cmd := exec.Command(binary, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if jdk != nil {
+		fmt.Printf("☕ Using JDK %s (%s)\n", jdk.ID(), jdk.Home)
+		cmd.Env = append(cmd.Env,
+			"JAVA_HOME="+jdk.Home,
+			"PATH="+filepath.Join(jdk.Home, "bin")+string(os.PathListSeparator)+os.Getenv("PATH"),
+		)
+	}
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), nil
+		}
+		return 1, fmt.Errorf("failed to run %s: %v", binary, err)
+	}
+	return 0, nil
+}
+
+// buildToolBinary prefers the project wrapper over mvn/gradle from PATH
+func buildToolBinary(tool string, info *ProjectInfo) (string, error) {
+	if info != nil && info.Wrapper != "" {
+		return info.Wrapper, nil
+	}
+
+	name := "mvn"
+	if tool == BuildToolGradle {
+		name = "gradle"
+	}
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+
+	if tool == BuildToolMaven {
+		return "", fmt.Errorf("maven not found (install it with: portunix install maven)")
+	}
+	return "", fmt.Errorf("gradle not found (add a Gradle wrapper to the project or install Gradle)")
+}
+
+func handleDetect() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	info, err := DetectProject(cwd)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Build tool:    %s (%s)\n", info.BuildTool, filepath.Base(info.BuildFile))
+	if info.Wrapper != "" {
+		fmt.Printf("Wrapper:       %s\n", filepath.Base(info.Wrapper))
+	} else {
+		fmt.Println("Wrapper:       none")
+	}
+	if info.JavaVersion != "" {
+		fmt.Printf("Java version:  %s\n", info.JavaVersion)
+	} else {
+		fmt.Println("Java version:  not specified")
+	}
+
+	m, err := NewJDKManager()
+	if err != nil {
+		return err
+	}
+	if spec, source := m.Current(); source == ".java-version" {
+		fmt.Printf("Pinned JDK:    %s (.java-version)\n", spec)
+	}
+	if info.JavaVersion != "" {
+		if jdk, _ := m.Find(info.JavaVersion); jdk != nil {
+			fmt.Printf("Matching JDK:  %s\n", jdk.ID())
+		} else {
+			fmt.Printf("Matching JDK:  not installed (portunix java install %s)\n", info.JavaVersion)
+		}
+	}
+	return nil
+}
+
+// fileExists checks if a file exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,40 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import "portunix.ai/app/aihelp"
+
+// showHelpAI prints machine-readable help for --help-ai
+func showHelpAI() {
+	jdk := aihelp.Argument{Name: "jdk", Type: "string", Required: true, Description: "JDK spec: major version or <distribution>-<major> (e.g. 21, zulu-17)"}
+	buildArgs := aihelp.Argument{Name: "args", Type: "string", Variadic: true, Description: "Arguments passed to the build tool"}
+
+	aihelp.Help{
+		Tool:        "ptx-java",
+		Version:     version,
+		Description: "JDK management and Maven/Gradle builds with the project JDK",
+		Commands: []aihelp.Command{
+			{
+				Name:        "java install",
+				Description: "Install a JDK into ~/.portunix/java/jdks",
+				Arguments:   []aihelp.Argument{{Name: "version", Type: "version", Required: true, Description: "Java major version"}},
+				Flags: []aihelp.Flag{
+					{Name: "distribution", Type: "string", Default: "temurin", Description: "JDK distribution", Choices: []string{"temurin", "zulu"}},
+				},
+				Examples: []string{"portunix java install 21", "portunix java install 17 --distribution zulu"},
+			},
+			{Name: "java list", Aliases: []string{"ls"}, Description: "List installed JDKs"},
+			{Name: "java use", Description: "Set the globally active JDK", Arguments: []aihelp.Argument{jdk}, Examples: []string{"portunix java use 21"}},
+			{Name: "java pin", Description: "Pin a JDK for the current project (.java-version)", Arguments: []aihelp.Argument{jdk}, Examples: []string{"portunix java pin 17"}},
+			{Name: "java current", Description: "Show the active JDK and JAVA_HOME"},
+			{Name: "java remove", Aliases: []string{"rm"}, Description: "Remove an installed JDK", Arguments: []aihelp.Argument{jdk}},
+			{Name: "java env", Description: "Print shell commands activating the current JDK"},
+			{Name: "java detect", Description: "Show the detected build tool and required Java version"},
+			{Name: "java build", Description: "Build the project (mvn package / gradle build) with the project JDK", Arguments: []aihelp.Argument{buildArgs}, Examples: []string{"portunix java build"}},
+			{Name: "java mvn", Aliases: []string{"maven"}, Description: "Run Maven with the project JDK", Arguments: []aihelp.Argument{buildArgs}, Examples: []string{"portunix java mvn clean verify"}},
+			{Name: "java gradle", Description: "Run Gradle with the project JDK", Arguments: []aihelp.Argument{buildArgs}, Examples: []string{"portunix java gradle test"}},
+		},
+	}.Print()
+}
@@ -0,0 +1,702 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"portunix.ai/app/config"
+	"portunix.ai/app/extract"
+)
+
+// Supported JDK distributions
+const (
+	DistributionTemurin = "temurin"
+	DistributionZulu    = "zulu"
+)
+
+// defaultDistribution is used when no distribution is specified
+const defaultDistribution = DistributionTemurin
+
+// temurinAssetsURL is the Adoptium API endpoint listing the latest GA release
+// of a feature version, including archive checksums
+const temurinAssetsURL = "https://api.adoptium.net/v3/assets/latest/%s/hotspot"
+
+// zuluPackagesURL is the Azul metadata API endpoint listing Zulu packages;
+// package details at <url><uuid> carry the checksum
+const zuluPackagesURL = "https://api.azul.com/metadata/v1/zulu/packages/"
+
+// jdkPackage is a downloadable JDK archive with its published sha256
+type jdkPackage struct {
+	URL    string
+	SHA256 string
+}
+
+// JDK is an installed JDK identified by distribution and feature version
+type JDK struct {
+	Distribution string
+	Major        string
+	Version      string // full version from the release file, e.g. 21.0.4+7
+	Home         string // JAVA_HOME
+}
+
+// ID returns the JDK identifier, e.g. temurin-21
+func (j JDK) ID() string {
+	return j.Distribution + "-" + j.Major
+}
+
+// JDKManager manages JDK installations under ~/.portunix/java
+type JDKManager struct {
+	baseDir string // ~/.portunix/java
+	jdksDir string // ~/.portunix/java/jdks
+}
+
+// NewJDKManager creates a new JDK manager
+func NewJDKManager() (*JDKManager, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %v", err)
+	}
+
+	baseDir := config.GetString("java.root_dir", filepath.Join(homeDir, ".portunix", "java"))
+	jdksDir := filepath.Join(baseDir, "jdks")
+	if err := os.MkdirAll(jdksDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create jdks directory: %v", err)
+	}
+
+	return &JDKManager{
+		baseDir: baseDir,
+		jdksDir: jdksDir,
+	}, nil
+}
+
+// parseJDKSpec splits "temurin-21", "zulu-17" or "21" into distribution and
+// major version; distribution is empty when not specified
+func parseJDKSpec(spec string) (string, string, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	dist, major := "", spec
+	if i := strings.LastIndex(spec, "-"); i >= 0 {
+		dist, major = spec[:i], spec[i+1:]
+	}
+
+	// Accept full versions like 21.0.4 or legacy 1.8
+	if strings.HasPrefix(major, "1.") {
+		major = strings.TrimPrefix(major, "1.")
+	}
+	if i := strings.IndexAny(major, ".+"); i >= 0 {
+		major = major[:i]
+	}
+
+	if _, err := strconv.Atoi(major); err != nil || major == "" {
+		return "", "", fmt.Errorf("invalid JDK version %q (expected e.g. 21, temurin-21, zulu-17)", spec)
+	}
+	if dist != "" && dist != DistributionTemurin && dist != DistributionZulu {
+		return "", "", fmt.Errorf("unsupported distribution %q (supported: temurin, zulu)", dist)
+	}
+	return dist, major, nil
+}
+
+// jdkDir returns the installation directory of a JDK
+func (m *JDKManager) jdkDir(dist, major string) string {
+	return filepath.Join(m.jdksDir, dist+"-"+major)
+}
+
+// javaHome returns JAVA_HOME for an installation directory; macOS archives
+// keep the JDK under Contents/Home
+func javaHome(dir string) string {
+	macHome := filepath.Join(dir, "Contents", "Home")
+	if _, err := os.Stat(macHome); err == nil {
+		return macHome
+	}
+	return dir
+}
+
+// javaBinary returns the path of a binary within JAVA_HOME/bin
+func javaBinary(home, name string) string {
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return filepath.Join(home, "bin", name)
+}
+
+// readReleaseVersion reads JAVA_VERSION from the JDK release file
+func readReleaseVersion(home string) string {
+	f, err := os.Open(filepath.Join(home, "release"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "JAVA_RUNTIME_VERSION=") || strings.HasPrefix(line, "JAVA_VERSION=") {
+			_, value, _ := strings.Cut(line, "=")
+			return strings.Trim(value, "\"")
+		}
+	}
+	return ""
+}
+
+// List returns installed JDKs sorted by major version, newest first
+func (m *JDKManager) List() ([]JDK, error) {
+	entries, err := os.ReadDir(m.jdksDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var jdks []JDK
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dist, major, err := parseJDKSpec(e.Name())
+		if err != nil || dist == "" {
+			continue
+		}
+		home := javaHome(filepath.Join(m.jdksDir, e.Name()))
+		if _, err := os.Stat(javaBinary(home, "java")); err != nil {
+			continue
+		}
+		jdks = append(jdks, JDK{Distribution: dist, Major: major, Version: readReleaseVersion(home), Home: home})
+	}
+
+	sort.Slice(jdks, func(i, j int) bool {
+		a, _ := strconv.Atoi(jdks[i].Major)
+		b, _ := strconv.Atoi(jdks[j].Major)
+		if a != b {
+			return a > b
+		}
+		return jdks[i].Distribution < jdks[j].Distribution
+	})
+	return jdks, nil
+}
+
+// Find returns the installed JDK matching spec; without a distribution the
+// default distribution is preferred
+func (m *JDKManager) Find(spec string) (*JDK, error) {
+	dist, major, err := parseJDKSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	jdks, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var match *JDK
+	for i := range jdks {
+		j := jdks[i]
+		if j.Major != major || (dist != "" && j.Distribution != dist) {
+			continue
+		}
+		if match == nil || j.Distribution == defaultDistribution {
+			match = &j
+		}
+	}
+	return match, nil
+}
+
+// Install downloads the latest GA release of a JDK feature version
+func (m *JDKManager) Install(dist, major string) (*JDK, error) {
+	if dist == "" {
+		dist = defaultDistribution
+	}
+	if existing, _ := m.Find(dist + "-" + major); existing != nil {
+		fmt.Printf("JDK %s is already installed at %s\n", existing.ID(), existing.Home)
+		return existing, nil
+	}
+
+	ext := "tar.gz"
+	if runtime.GOOS == "windows" {
+		ext = "zip"
+	}
+
+	var pkg *jdkPackage
+	var err error
+	switch dist {
+	case DistributionTemurin:
+		pkg, err = temurinPackage(major, ext)
+	case DistributionZulu:
+		pkg, err = zuluPackage(major, ext)
+	default:
+		return nil, fmt.Errorf("unsupported distribution %q (supported: temurin, zulu)", dist)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tmpFile, err := os.CreateTemp("", "ptx-java-*."+ext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	fmt.Printf("📥 Downloading %s JDK %s...\n", dist, major)
+	client := &http.Client{Timeout: 30 * time.Minute}
+	resp, err := client.Get(pkg.URL)
+	if err != nil {
+		tmpFile.Close()
+		return nil, fmt.Errorf("download failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		tmpFile.Close()
+		return nil, fmt.Errorf("download failed: HTTP %d (is %s available from %s?)", resp.StatusCode, major, dist)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmpFile, hash), resp.Body); err != nil {
+		tmpFile.Close()
+		return nil, fmt.Errorf("download failed: %v", err)
+	}
+	tmpFile.Close()
+
+	if actualSum := hex.EncodeToString(hash.Sum(nil)); actualSum != pkg.SHA256 {
+		os.Remove(tmpFile.Name())
+		return nil, fmt.Errorf("checksum mismatch for %s JDK %s: expected %s, got %s", dist, major, pkg.SHA256, actualSum)
+	}
+	fmt.Println("🔐 Checksum verified")
+
+	// Archives contain a single top-level directory (jdk-21.0.4+7, zulu21...);
+	// extract to a staging dir and rename it to <dist>-<major>
+	stagingDir, err := os.MkdirTemp(m.jdksDir, ".staging-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	fmt.Println("📦 Extracting...")
+	if ext == "zip" {
+		err = extract.Zip(tmpFile.Name(), stagingDir)
+	} else {
+		err = extract.TarGz(tmpFile.Name(), stagingDir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("extraction failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(stagingDir)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return nil, fmt.Errorf("unexpected archive layout")
+	}
+
+	target := m.jdkDir(dist, major)
+	if err := os.Rename(filepath.Join(stagingDir, entries[0].Name()), target); err != nil {
+		return nil, fmt.Errorf("failed to install JDK %s-%s: %v", dist, major, err)
+	}
+
+	home := javaHome(target)
+	jdk := &JDK{Distribution: dist, Major: major, Version: readReleaseVersion(home), Home: home}
+	fmt.Printf("✅ JDK %s (%s) installed at %s\n", jdk.ID(), jdk.Version, jdk.Home)
+	return jdk, nil
+}
+
+// Current returns the active JDK spec: project pin (.java-version) first,
+// then the global selection; empty when the system Java is used
+func (m *JDKManager) Current() (string, string) {
+	if data, err := os.ReadFile(".java-version"); err == nil {
+		if spec := strings.TrimSpace(string(data)); spec != "" {
+			return spec, ".java-version"
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(m.baseDir, "current"))
+	if err != nil {
+		return "", ""
+	}
+	return strings.TrimSpace(string(data)), "global"
+}
+
+// Use sets the globally active JDK
+func (m *JDKManager) Use(spec string) (*JDK, error) {
+	jdk, err := m.requireInstalled(spec)
+	if err != nil {
+		return nil, err
+	}
+	return jdk, os.WriteFile(filepath.Join(m.baseDir, "current"), []byte(jdk.ID()+"\n"), 0644)
+}
+
+// Pin writes .java-version in the current directory
+func (m *JDKManager) Pin(spec string) (*JDK, error) {
+	jdk, err := m.requireInstalled(spec)
+	if err != nil {
+		return nil, err
+	}
+	return jdk, os.WriteFile(".java-version", []byte(jdk.ID()+"\n"), 0644)
+}
+
+// Remove deletes an installed JDK
+func (m *JDKManager) Remove(spec string) (*JDK, error) {
+	jdk, err := m.requireInstalled(spec)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := os.ReadFile(filepath.Join(m.baseDir, "current")); err == nil && strings.TrimSpace(string(data)) == jdk.ID() {
+		os.Remove(filepath.Join(m.baseDir, "current"))
+	}
+	return jdk, os.RemoveAll(m.jdkDir(jdk.Distribution, jdk.Major))
+}
+
+// requireInstalled returns the installed JDK matching spec or an error
+func (m *JDKManager) requireInstalled(spec string) (*JDK, error) {
+	jdk, err := m.Find(spec)
+	if err != nil {
+		return nil, err
+	}
+	if jdk == nil {
+		return nil, fmt.Errorf("JDK %s is not installed (install it with: portunix java install %s)", spec, spec)
+	}
+	return jdk, nil
+}
+
+// platformNames returns the OS and architecture names used by JDK vendors
+func platformNames() (string, string) {
+	goos := runtime.GOOS
+	if goos == "darwin" {
+		goos = "mac"
+	}
+	arch := runtime.GOARCH
+	switch arch {
+	case "amd64":
+		arch = "x64"
+	case "arm64":
+		arch = "aarch64"
+	}
+	return goos, arch
+}
+
+// temurinPackage queries the Adoptium API for the latest GA archive
+func temurinPackage(major, ext string) (*jdkPackage, error) {
+	goos, arch := platformNames()
+
+	query := url.Values{}
+	query.Set("os", goos)
+	query.Set("architecture", arch)
+	query.Set("image_type", "jdk")
+	query.Set("vendor", "eclipse")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(fmt.Sprintf(temurinAssetsURL, major) + "?" + query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Temurin releases: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query Temurin releases: HTTP %d (is %s available from temurin?)", resp.StatusCode, major)
+	}
+
+	pkg, err := parseTemurinAssets(resp.Body, ext)
+	if err != nil {
+		return nil, err
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("no Temurin JDK %s found for %s/%s", major, goos, arch)
+	}
+	return pkg, nil
+}
+
+// parseTemurinAssets returns the archive of the given extension from an
+// Adoptium assets response; nil when there is none
+func parseTemurinAssets(r io.Reader, ext string) (*jdkPackage, error) {
+	var assets []struct {
+		Binary struct {
+			Package struct {
+				Name     string `json:"name"`
+				Link     string `json:"link"`
+				Checksum string `json:"checksum"`
+			} `json:"package"`
+		} `json:"binary"`
+	}
+	if err := json.NewDecoder(r).Decode(&assets); err != nil {
+		return nil, fmt.Errorf("failed to parse Temurin releases: %v", err)
+	}
+
+	for _, a := range assets {
+		p := a.Binary.Package
+		if strings.HasSuffix(p.Name, "."+ext) && p.Link != "" {
+			if p.Checksum == "" {
+				return nil, fmt.Errorf("no checksum published for %s", p.Name)
+			}
+			return &jdkPackage{URL: p.Link, SHA256: strings.ToLower(p.Checksum)}, nil
+		}
+	}
+	return nil, nil
+}
+
+// zuluPackage queries the Azul metadata API for the latest GA archive and
+// its checksum
+func zuluPackage(major, ext string) (*jdkPackage, error) {
+	goos, arch := platformNames()
+	if goos == "mac" {
+		goos = "macos"
+	}
+
+	query := url.Values{}
+	query.Set("java_version", major)
+	query.Set("os", goos)
+	query.Set("arch", arch)
+	query.Set("archive_type", ext)
+	query.Set("java_package_type", "jdk")
+	query.Set("javafx_bundled", "false")
+	query.Set("release_status", "ga")
+	query.Set("latest", "true")
+	query.Set("page_size", "1")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(zuluPackagesURL + "?" + query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Zulu packages: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var packages []struct {
+		PackageUUID string `json:"package_uuid"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&packages); err != nil {
+		return nil, fmt.Errorf("failed to parse Zulu packages: %v", err)
+	}
+	if len(packages) == 0 || packages[0].PackageUUID == "" {
+		return nil, fmt.Errorf("no Zulu JDK %s found for %s/%s", major, goos, arch)
+	}
+
+	details, err := client.Get(zuluPackagesURL + url.PathEscape(packages[0].PackageUUID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Zulu package: %v", err)
+	}
+	defer details.Body.Close()
+	return parseZuluPackage(details.Body)
+}
+
+// parseZuluPackage reads the download URL and checksum from Azul package details
+func parseZuluPackage(r io.Reader) (*jdkPackage, error) {
+	var pkg struct {
+		Name        string `json:"name"`
+		DownloadURL string `json:"download_url"`
+		SHA256      string `json:"sha256_hash"`
+	}
+	if err := json.NewDecoder(r).Decode(&pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse Zulu package: %v", err)
+	}
+	if pkg.DownloadURL == "" {
+		return nil, fmt.Errorf("Zulu package has no download URL")
+	}
+	if pkg.SHA256 == "" {
+		return nil, fmt.Errorf("no checksum published for %s", pkg.Name)
+	}
+	return &jdkPackage{URL: pkg.DownloadURL, SHA256: strings.ToLower(pkg.SHA256)}, nil
+}
+
+// Command handlers
+
+func handleInstall(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("version required (usage: portunix java install <version> [--distribution temurin|zulu])")
+	}
+
+	spec := ""
+	dist := ""
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--distribution", "--dist":
+			if i+1 < len(args) {
+				dist = strings.ToLower(args[i+1])
+				i++
+			}
+		default:
+			if !strings.HasPrefix(args[i], "-") {
+				spec = args[i]
+			}
+		}
+	}
+
+	specDist, major, err := parseJDKSpec(spec)
+	if err != nil {
+		return err
+	}
+	if dist == "" {
+		dist = specDist
+	}
+
+	m, err := NewJDKManager()
+	if err != nil {
+		return err
+	}
+
+	jdk, err := m.Install(dist, major)
+	if err != nil {
+		return err
+	}
+
+	if current, _ := m.Current(); current == "" {
+		if _, err := m.Use(jdk.ID()); err != nil {
+			return err
+		}
+		fmt.Printf("JDK %s set as active version\n", jdk.ID())
+	}
+	return nil
+}
+
+func handleList() error {
+	m, err := NewJDKManager()
+	if err != nil {
+		return err
+	}
+
+	jdks, err := m.List()
+	if err != nil {
+		return err
+	}
+
+	if len(jdks) == 0 {
+		fmt.Println("No JDKs installed by portunix.")
+		fmt.Println("Install one with: portunix java install 21")
+		return nil
+	}
+
+	active := ""
+	if spec, _ := m.Current(); spec != "" {
+		if jdk, _ := m.Find(spec); jdk != nil {
+			active = jdk.ID()
+		}
+	}
+
+	fmt.Printf("JDKs in %s:\n\n", m.jdksDir)
+	for _, j := range jdks {
+		marker := "  "
+		if j.ID() == active {
+			marker = "* "
+		}
+		fmt.Printf("%s%-14s %s\n", marker, j.ID(), j.Version)
+	}
+	return nil
+}
+
+func handleUse(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("JDK required (usage: portunix java use <jdk>)")
+	}
+
+	m, err := NewJDKManager()
+	if err != nil {
+		return err
+	}
+
+	jdk, err := m.Use(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ JDK %s is now active\n", jdk.ID())
+	fmt.Println("Portunix commands use it automatically. For your shell, run:")
+	printEnvCommands(jdk.Home)
+	return nil
+}
+
+func handlePin(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("JDK required (usage: portunix java pin <jdk>)")
+	}
+
+	m, err := NewJDKManager()
+	if err != nil {
+		return err
+	}
+
+	jdk, err := m.Pin(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Project pinned to JDK %s (.java-version)\n", jdk.ID())
+	return nil
+}
+
+func handleCurrent() error {
+	m, err := NewJDKManager()
+	if err != nil {
+		return err
+	}
+
+	spec, source := m.Current()
+	if spec == "" {
+		if home := os.Getenv("JAVA_HOME"); home != "" {
+			fmt.Printf("System JDK: %s (JAVA_HOME)\n", home)
+			return nil
+		}
+		fmt.Println("No active JDK (none managed by portunix, JAVA_HOME not set)")
+		return nil
+	}
+
+	jdk, err := m.Find(spec)
+	if err != nil {
+		return err
+	}
+	if jdk == nil {
+		return fmt.Errorf("JDK %s selected by %s is not installed (install it with: portunix java install %s)", spec, source, spec)
+	}
+
+	fmt.Printf("JDK %s (%s) [%s]\n", jdk.ID(), jdk.Version, source)
+	fmt.Printf("JAVA_HOME: %s\n", jdk.Home)
+	return nil
+}
+
+func handleRemove(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("JDK required (usage: portunix java remove <jdk>)")
+	}
+
+	m, err := NewJDKManager()
+	if err != nil {
+		return err
+	}
+
+	jdk, err := m.Remove(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ JDK %s removed\n", jdk.ID())
+	return nil
+}
+
+func handleEnv() error {
+	m, err := NewJDKManager()
+	if err != nil {
+		return err
+	}
+
+	spec, _ := m.Current()
+	if spec == "" {
+		return fmt.Errorf("no active JDK (select one with: portunix java use <jdk>)")
+	}
+	jdk, err := m.requireInstalled(spec)
+	if err != nil {
+		return err
+	}
+	printEnvCommands(jdk.Home)
+	return nil
+}
+
+// printEnvCommands prints shell commands setting JAVA_HOME and PATH
+func printEnvCommands(home string) {
+	if runtime.GOOS == "windows" {
+		fmt.Printf("  $env:JAVA_HOME=\"%s\"; $env:PATH=\"%s;$env:PATH\"\n", home, filepath.Join(home, "bin"))
+		return
+	}
+	fmt.Printf("  export JAVA_HOME=\"%s\" PATH=\"%s:$PATH\"\n", home, filepath.Join(home, "bin"))
+}
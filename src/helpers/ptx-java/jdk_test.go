@@ -0,0 +1,100 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseJDKSpec(t *testing.T) {
+	tests := []struct {
+		spec, dist, major string
+	}{
+		{"21", "", "21"},
+		{"temurin-21", "temurin", "21"},
+		{"zulu-17", "zulu", "17"},
+		{"21.0.4+7", "", "21"},
+		{"1.8", "", "8"},
+	}
+	for _, tt := range tests {
+		dist, major, err := parseJDKSpec(tt.spec)
+		if err != nil {
+			t.Errorf("parseJDKSpec(%q) unexpected error: %v", tt.spec, err)
+			continue
+		}
+		if dist != tt.dist || major != tt.major {
+			t.Errorf("parseJDKSpec(%q) = %q, %q; want %q, %q", tt.spec, dist, major, tt.dist, tt.major)
+		}
+	}
+
+	for _, invalid := range []string{"", "latest", "corretto-21"} {
+		if _, _, err := parseJDKSpec(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestDetectJavaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		gradle  bool
+		want    string
+	}{
+		{"maven release", `<properties><maven.compiler.release>17</maven.compiler.release></properties>`, false, "17"},
+		{"spring boot", `<properties><java.version>21</java.version></properties>`, false, "21"},
+		{"maven legacy", `<maven.compiler.source>1.8</maven.compiler.source>`, false, "8"},
+		{"gradle toolchain", `java { toolchain { languageVersion = JavaLanguageVersion.of(21) } }`, true, "21"},
+		{"gradle compatibility", `sourceCompatibility = JavaVersion.VERSION_11`, true, "11"},
+		{"gradle string", `sourceCompatibility = '17'`, true, "17"},
+		{"none", `<project></project>`, false, ""},
+	}
+	for _, tt := range tests {
+		patterns := mavenVersionPatterns
+		if tt.gradle {
+			patterns = gradleVersionPatterns
+		}
+		if got := detectJavaVersion(tt.content, patterns); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseTemurinAssets(t *testing.T) {
+	body := `[{"binary": {"package": {
+		"name": "OpenJDK21U-jdk_x64_linux_hotspot_21.0.4_7.tar.gz",
+		"link": "https://github.com/adoptium/temurin21-binaries/releases/download/jdk-21.0.4%2B7/OpenJDK21U-jdk_x64_linux_hotspot_21.0.4_7.tar.gz",
+		"checksum": "51FB4D03A4429C39D397D3A03A779077159317616550E4E71624C9843083E7B9"}}}]`
+
+	pkg, err := parseTemurinAssets(strings.NewReader(body), "tar.gz")
+	if err != nil || pkg == nil {
+		t.Fatalf("got %v, %v", pkg, err)
+	}
+	if pkg.SHA256 != "51fb4d03a4429c39d397d3a03a779077159317616550e4e71624c9843083e7b9" || !strings.HasSuffix(pkg.URL, "_21.0.4_7.tar.gz") {
+		t.Errorf("got %+v", pkg)
+	}
+
+	if pkg, err := parseTemurinAssets(strings.NewReader(body), "zip"); err != nil || pkg != nil {
+		t.Errorf("zip: got %v, %v", pkg, err)
+	}
+	unsigned := strings.Replace(body, `"checksum": "51FB4D03A4429C39D397D3A03A779077159317616550E4E71624C9843083E7B9"`, `"checksum": ""`, 1)
+	if _, err := parseTemurinAssets(strings.NewReader(unsigned), "tar.gz"); err == nil {
+		t.Error("expected error for a package without checksum")
+	}
+}
+
+func TestParseZuluPackage(t *testing.T) {
+	pkg, err := parseZuluPackage(strings.NewReader(`{"name": "zulu21.36.17-ca-jdk21.0.4-linux_x64.tar.gz",
+		"download_url": "https://cdn.azul.com/zulu/bin/zulu21.36.17-ca-jdk21.0.4-linux_x64.tar.gz",
+		"sha256_hash": "318d0c2ed3c876fb7ea2c952945cdcf7decfb5264b959ebd4fe2f24d3f3f1ef0"}`))
+	if err != nil || pkg.SHA256 != "318d0c2ed3c876fb7ea2c952945cdcf7decfb5264b959ebd4fe2f24d3f3f1ef0" {
+		t.Errorf("got %+v, %v", pkg, err)
+	}
+
+	if _, err := parseZuluPackage(strings.NewReader(`{"name": "zulu.tar.gz", "download_url": "https://cdn.azul.com/zulu.tar.gz"}`)); err == nil {
+		t.Error("expected error for a package without checksum")
+	}
+}
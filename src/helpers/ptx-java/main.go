@@ -0,0 +1,158 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var version = "dev"
+
+// rootCmd represents the base command for ptx-java
+var rootCmd = &cobra.Command{
+	Use:   "ptx-java",
+	Short: "Portunix Java Development Helper",
+	Long: `ptx-java is a helper binary for Portunix that handles Java development operations.
+It installs and manages multiple JDK distributions, selects JAVA_HOME per project,
+and wraps Maven/Gradle builds with toolchain auto-detection.
+
+This binary is typically invoked by the main portunix dispatcher and should not be used directly.
+
+Supported features:
+- JDK installation (Temurin, Zulu) into ~/.portunix/java/jdks
+- JDK switching, globally or per project (.java-version)
+- Maven and Gradle wrappers with required Java version detection`,
+	Version:            version,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		handleCommand(args)
+	},
+}
+
+// handleCommand dispatches the "java" command routed to this helper by the
+// parent portunix binary (see src/dispatcher/dispatcher.go), plus the discovery
+// meta-flags --version, --description, and --list-commands used by the
+// dispatcher. args arrive without the binary name prefix.
+func handleCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Println("No command specified")
+		return
+	}
+
+	command := args[0]
+	subArgs := args[1:]
+
+	switch command {
+	case "java":
+		if len(subArgs) == 0 {
+			showJavaHelp()
+		} else {
+			handleJavaCommand(subArgs)
+		}
+	case "--version":
+		fmt.Printf("ptx-java version %s\n", version)
+	case "--description":
+		fmt.Println("Portunix Java Development Helper")
+	case "--list-commands":
+		fmt.Println("java")
+	case "--help-ai":
+		showHelpAI()
+	default:
+		fmt.Printf("Unknown command: %s\n", command)
+		fmt.Println("Supported commands: java")
+	}
+}
+
+func showJavaHelp() {
+	fmt.Println("Usage: portunix java [subcommand]")
+	fmt.Println()
+	fmt.Println("Java Development Commands:")
+	fmt.Println()
+	fmt.Println("JDK Management:")
+	fmt.Println("  install <version> [--distribution temurin|zulu]")
+	fmt.Println("                               - Install JDK into ~/.portunix/java/jdks")
+	fmt.Println("  list                         - List installed JDKs")
+	fmt.Println("  use <jdk>                    - Set globally active JDK (e.g. 21, temurin-21)")
+	fmt.Println("  pin <jdk>                    - Pin JDK for current project (.java-version)")
+	fmt.Println("  current                      - Show active JDK and JAVA_HOME")
+	fmt.Println("  remove <jdk>                 - Remove installed JDK")
+	fmt.Println("  env                          - Print shell commands to activate current JDK")
+	fmt.Println()
+	fmt.Println("Build Tools:")
+	fmt.Println("  detect                       - Show detected build tool and required Java version")
+	fmt.Println("  build [args]                 - Build project (mvn package / gradle build)")
+	fmt.Println("  mvn [args]                   - Run Maven with the project JDK")
+	fmt.Println("  gradle [args]                - Run Gradle with the project JDK")
+	fmt.Println()
+	fmt.Println("Distributions: temurin (default), zulu")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix java install 21")
+	fmt.Println("  portunix java install 17 --distribution zulu")
+	fmt.Println("  portunix java pin 17")
+	fmt.Println("  portunix java build")
+	fmt.Println("  portunix java mvn clean verify")
+}
+
+func handleJavaCommand(args []string) {
+	subcommand := args[0]
+	subArgs := args[1:]
+
+	var err error
+	exitCode := 0
+
+	switch subcommand {
+	case "install":
+		err = handleInstall(subArgs)
+	case "list", "ls":
+		err = handleList()
+	case "use":
+		err = handleUse(subArgs)
+	case "pin":
+		err = handlePin(subArgs)
+	case "current":
+		err = handleCurrent()
+	case "remove", "rm":
+		err = handleRemove(subArgs)
+	case "env":
+		err = handleEnv()
+	case "detect":
+		err = handleDetect()
+	case "build":
+		exitCode, err = handleBuildTool("", subArgs)
+	case "mvn", "maven":
+		exitCode, err = handleBuildTool(BuildToolMaven, subArgs)
+	case "gradle":
+		exitCode, err = handleBuildTool(BuildToolGradle, subArgs)
+	case "--help", "-h":
+		showJavaHelp()
+	case "--help-ai":
+		showHelpAI()
+	default:
+		fmt.Printf("Unknown java subcommand: %s\n", subcommand)
+		fmt.Println("Run 'portunix java --help' for available commands")
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(exitCode)
+}
+
+func init() {
+	rootCmd.SetVersionTemplate("ptx-java version {{.Version}}\n")
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
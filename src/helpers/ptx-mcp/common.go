@@ -17,6 +17,8 @@ import (
 	"strings"
 	"syscall"
 	"time"
+
+	"portunix.ai/app/fileutil"
 )
 
 // cachedClaudePath caches the claude path to avoid inconsistency
@@ -268,7 +270,7 @@ func saveMCPConfiguration(config *MCPConfiguration) error {
 		return err
 	}
 
-	return os.WriteFile(configFile, data, 0644)
+	return fileutil.WriteFile(configFile, data, 0644)
 }
 
 // removeMCPConfiguration removes MCP configuration file
@@ -9,10 +9,13 @@ replace portunix.ai/app => ../../app
 
 replace portunix.ai/app/install => ../../app/install
 
+replace portunix.ai/portunix => ../../..
+
 require (
-	github.com/spf13/cobra v1.8.1
+	github.com/spf13/cobra v1.10.1
 	portunix.ai/app v0.0.0
 	portunix.ai/app/install v0.0.0
+	portunix.ai/portunix v0.0.0-00010101000000-000000000000
 )
 
 require (
@@ -21,10 +24,15 @@ require (
 	github.com/kr/fs v0.1.0 // indirect
 	github.com/pbnjay/memory v0.0.0-20210728143218-7b4eea64cf58 // indirect
 	github.com/pkg/sftp v1.13.10 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/spf13/pflag v1.0.9 // indirect
 	github.com/testcontainers/testcontainers-go v0.40.0 // indirect
-	golang.org/x/crypto v0.46.0 // indirect
-	golang.org/x/sys v0.39.0 // indirect
-	golang.org/x/term v0.38.0 // indirect
+	golang.org/x/crypto v0.47.0 // indirect
+	golang.org/x/net v0.49.0 // indirect
+	golang.org/x/sys v0.40.0 // indirect
+	golang.org/x/term v0.39.0 // indirect
+	golang.org/x/text v0.33.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260126211449-d11affda4bed // indirect
+	google.golang.org/grpc v1.78.0 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
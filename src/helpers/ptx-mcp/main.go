@@ -38,18 +38,39 @@ Communication Modes:
   tcp      - TCP socket server for network-based connections
   unix     - Unix domain socket for local IPC
 
+Install Policies (install_package tool):
+  confirm  - First call returns an install plan and a plan token; installs
+             only when called again with that token after the user
+             approved the plan (default)
+  allow    - Install immediately
+  deny     - Reject all installations
+
 Examples:
   portunix mcp serve                           # Start in stdio mode (default)
   portunix mcp serve --mode stdio              # Explicit stdio mode
-  portunix mcp serve --mode tcp --port 3001    # TCP mode on port 3001`,
+  portunix mcp serve --mode tcp --port 3001    # TCP mode on port 3001
+  portunix mcp serve --permissions standard --install-policy allow`,
 	Run: func(cmd *cobra.Command, args []string) {
 		mode, _ := cmd.Flags().GetString("mode")
 		port, _ := cmd.Flags().GetInt("port")
 		socket, _ := cmd.Flags().GetString("socket")
 		permissions, _ := cmd.Flags().GetString("permissions")
 		config, _ := cmd.Flags().GetString("config")
+		installPolicy, _ := cmd.Flags().GetString("install-policy")
+
+		validPolicy := false
+		for _, policy := range mcp.InstallPolicies {
+			if policy == installPolicy {
+				validPolicy = true
+			}
+		}
+		if !validPolicy {
+			fmt.Fprintf(os.Stderr, "Unknown install policy: %s. Supported policies: confirm, allow, deny\n", installPolicy)
+			os.Exit(1)
+		}
 
 		server := mcp.NewServer(port, permissions, config)
+		server.InstallPolicy = installPolicy
 
 		switch mode {
 		case "stdio":
@@ -104,6 +125,7 @@ func init() {
 	serveCmd.Flags().StringP("socket", "s", "/tmp/portunix.sock", "Socket path for Unix mode")
 	serveCmd.Flags().StringP("permissions", "r", "limited", "Permission level: limited, standard, full")
 	serveCmd.Flags().StringP("config", "c", "", "Path to configuration file")
+	serveCmd.Flags().String("install-policy", mcp.InstallPolicyConfirm, "Package install policy: confirm, allow, deny")
 
 	// Version template
 	rootCmd.SetVersionTemplate("portunix mcp version {{.Version}}\n")
@@ -149,12 +171,19 @@ func showHelpExpert() {
 	fmt.Println("  -s, --socket <path>      Socket path for Unix mode (default: /tmp/portunix.sock)")
 	fmt.Println("  -r, --permissions <level> Permission level: limited, standard, full (default: limited)")
 	fmt.Println("  -c, --config <path>      Path to configuration file")
+	fmt.Println("  --install-policy <policy> Package install policy: confirm, allow, deny (default: confirm)")
 	fmt.Println()
 	fmt.Println("COMMUNICATION MODES:")
 	fmt.Println("  stdio    Standard input/output for direct AI integration (default)")
 	fmt.Println("  tcp      TCP socket server for network-based connections")
 	fmt.Println("  unix     Unix domain socket for local IPC")
 	fmt.Println()
+	fmt.Println("TOOLS (selection):")
+	fmt.Println("  get_system_info          System information and capabilities")
+	fmt.Println("  install_package          Install a package (subject to install policy)")
+	fmt.Println("  container_list/run/exec/logs  Manage containers of podman or docker")
+	fmt.Println("  pft_list, pft_show       Query PTX-PFT feedback items")
+	fmt.Println()
 	fmt.Println("EXAMPLES:")
 	fmt.Println("  portunix mcp serve                           Start in stdio mode")
 	fmt.Println("  portunix mcp serve --mode tcp --port 3001    TCP mode on port 3001")
@@ -0,0 +1,670 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// analysisDirName is the directory of the project holding cluster analyses
+// waiting for review
+const analysisDirName = ".pft-analysis"
+
+// Embedder turns feedback texts into vectors whose cosine similarity
+// reflects how similar the texts are
+type Embedder interface {
+	Name() string
+	Embed(texts []string) ([][]float64, error)
+	// DefaultThreshold is the similarity at which items are grouped
+	DefaultThreshold() float64
+}
+
+// LanguageModel is a provider that also generates text, used for cluster
+// summaries and verbatim scoring
+type LanguageModel interface {
+	// ModelName names the text model, e.g. "ollama/llama3.2"
+	ModelName() string
+	// Complete answers a prompt; with jsonOutput the answer is a JSON object
+	Complete(prompt string, jsonOutput bool) (string, error)
+}
+
+// NewEmbedder returns the embedder of an AI configuration; nil or an empty
+// provider selects the built-in TF-IDF embedder, which needs no model
+func NewEmbedder(ai *AIConfig) (Embedder, error) {
+	if ai == nil || ai.Provider == "" || ai.Provider == "local" {
+		return localEmbedder{}, nil
+	}
+	switch ai.Provider {
+	case "ollama":
+		return &ollamaClient{url: defaultString(ai.URL, "http://localhost:11434"), model: defaultString(ai.Model, "nomic-embed-text"), chatModel: defaultString(ai.ChatModel, "llama3.2")}, nil
+	case "openai":
+		key := resolveSecret(ai.APIKey)
+		if key == "" {
+			key = os.Getenv("OPENAI_API_KEY")
+		}
+		if key == "" {
+			return nil, fmt.Errorf("openai provider needs an API key (pft configure --ai-key <key> or OPENAI_API_KEY)")
+		}
+		return &openAIClient{url: strings.TrimSuffix(defaultString(ai.URL, "https://api.openai.com/v1"), "/"), key: key, model: defaultString(ai.Model, "text-embedding-3-small"), chatModel: defaultString(ai.ChatModel, "gpt-4o-mini")}, nil
+	}
+	return nil, fmt.Errorf("unknown AI provider: %s (local, ollama, openai)", ai.Provider)
+}
+
+func defaultString(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// analysisStopWords are left out of embeddings and cluster keywords
+var analysisStopWords = func() map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.Fields(`the and for with that this from have has are was were not but
+		can could would should will into when what which while where there their them they then than
+		your you our out all any also been being more most some such only other very just about
+		over under because need needs want wants like able use used using make makes get gets
+		item items feedback user users customer customers please would it's its don't cannot`) {
+		words[w] = true
+	}
+	return words
+}()
+
+// analysisTokens splits text into lower case words without stop words;
+// a plural "s" is dropped so "exports" and "export" match
+func analysisTokens(text string) []string {
+	var tokens []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) < 3 || analysisStopWords[word] {
+			continue
+		}
+		if len(word) > 4 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+			word = strings.TrimSuffix(word, "s")
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// localEmbedder builds TF-IDF vectors over the vocabulary of the texts of
+// one Embed call
+type localEmbedder struct{}
+
+func (localEmbedder) Name() string              { return "local (tf-idf)" }
+func (localEmbedder) DefaultThreshold() float64 { return 0.2 }
+
+func (localEmbedder) Embed(texts []string) ([][]float64, error) {
+	docs := make([][]string, len(texts))
+	df := make(map[string]int)
+	for i, text := range texts {
+		docs[i] = analysisTokens(text)
+		seen := make(map[string]bool)
+		for _, t := range docs[i] {
+			if !seen[t] {
+				seen[t] = true
+				df[t]++
+			}
+		}
+	}
+	vocabulary := make([]string, 0, len(df))
+	for t := range df {
+		vocabulary = append(vocabulary, t)
+	}
+	sort.Strings(vocabulary)
+	index := make(map[string]int, len(vocabulary))
+	for i, t := range vocabulary {
+		index[t] = i
+	}
+
+	vectors := make([][]float64, len(texts))
+	for i, doc := range docs {
+		v := make([]float64, len(vocabulary))
+		for _, t := range doc {
+			v[index[t]]++
+		}
+		for t, j := range index {
+			if v[j] > 0 {
+				v[j] *= math.Log(float64(1+len(texts))/float64(1+df[t])) + 1
+			}
+		}
+		vectors[i] = normalize(v)
+	}
+	return vectors, nil
+}
+
+func normalize(v []float64) []float64 {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	if sum == 0 {
+		return v
+	}
+	n := math.Sqrt(sum)
+	for i := range v {
+		v[i] /= n
+	}
+	return v
+}
+
+func cosine(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// ollamaClient embeds and summarizes with a local Ollama server
+type ollamaClient struct {
+	url, model, chatModel string
+}
+
+func (c *ollamaClient) Name() string              { return "ollama (" + c.model + ")" }
+func (c *ollamaClient) DefaultThreshold() float64 { return 0.7 }
+
+func (c *ollamaClient) Embed(texts []string) ([][]float64, error) {
+	var resp struct {
+		Embeddings [][]float64 `json:"embeddings"`
+	}
+	err := postAI(strings.TrimSuffix(c.url, "/")+"/api/embed", "", map[string]interface{}{"model": c.model, "input": texts}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d texts", len(resp.Embeddings), len(texts))
+	}
+	return resp.Embeddings, nil
+}
+
+func (c *ollamaClient) ModelName() string { return "ollama/" + c.chatModel }
+
+func (c *ollamaClient) Complete(prompt string, jsonOutput bool) (string, error) {
+	var resp struct {
+		Response string `json:"response"`
+	}
+	request := map[string]interface{}{"model": c.chatModel, "prompt": prompt, "stream": false}
+	if jsonOutput {
+		request["format"] = "json"
+	}
+	err := postAI(strings.TrimSuffix(c.url, "/")+"/api/generate", "", request, &resp)
+	return strings.TrimSpace(resp.Response), err
+}
+
+// openAIClient embeds and summarizes with an OpenAI compatible API
+type openAIClient struct {
+	url, key, model, chatModel string
+}
+
+func (c *openAIClient) Name() string              { return "openai (" + c.model + ")" }
+func (c *openAIClient) DefaultThreshold() float64 { return 0.5 }
+
+func (c *openAIClient) Embed(texts []string) ([][]float64, error) {
+	var resp struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := postAI(c.url+"/embeddings", c.key, map[string]interface{}{"model": c.model, "input": texts}, &resp); err != nil {
+		return nil, err
+	}
+	vectors := make([][]float64, len(texts))
+	for _, d := range resp.Data {
+		if d.Index >= 0 && d.Index < len(vectors) {
+			vectors[d.Index] = d.Embedding
+		}
+	}
+	for i, v := range vectors {
+		if v == nil {
+			return nil, fmt.Errorf("no embedding returned for text %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+func (c *openAIClient) ModelName() string { return "openai/" + c.chatModel }
+
+func (c *openAIClient) Complete(prompt string, jsonOutput bool) (string, error) {
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	request := map[string]interface{}{
+		"model":    c.chatModel,
+		"messages": []map[string]string{{"role": "user", "content": prompt}},
+	}
+	if jsonOutput {
+		request["response_format"] = map[string]string{"type": "json_object"}
+	}
+	err := postAI(c.url+"/chat/completions", c.key, request, &resp)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("empty completion")
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
+func postAI(url, key string, payload interface{}, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("AI request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("AI request to %s failed: HTTP %d: %s", url, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func summaryPrompt(items []FeedbackItem, keywords []string) string {
+	var b strings.Builder
+	b.WriteString("The following product feedback items were grouped as one theme for a QFD workshop")
+	if len(keywords) > 0 {
+		fmt.Fprintf(&b, " (keywords: %s)", strings.Join(keywords, ", "))
+	}
+	b.WriteString(". Summarize in 2-3 sentences the underlying customer need they share, without listing the items.\n\n")
+	for _, item := range items {
+		fmt.Fprintf(&b, "- %s: %s. %s\n", item.ID, item.Title, truncateString(strings.Join(strings.Fields(item.Description), " "), 300))
+	}
+	return b.String()
+}
+
+// ClusterItem is a feedback item of a cluster
+type ClusterItem struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// Similarity to the cluster centroid
+	Similarity float64 `json:"similarity"`
+}
+
+// ProposedCategory is the category proposed for the items of a cluster
+type ProposedCategory struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Existing is set when the category is already defined in the area
+	Existing   bool    `json:"existing"`
+	Similarity float64 `json:"similarity,omitempty"`
+}
+
+// FeedbackCluster is a group of similar feedback items
+type FeedbackCluster struct {
+	ID       int              `json:"id"`
+	Keywords []string         `json:"keywords"`
+	Summary  string           `json:"summary"`
+	Category ProposedCategory `json:"category"`
+	Items    []ClusterItem    `json:"items"`
+	// Approved clusters are applied by 'pft analyze --apply'; reviewers
+	// set it after checking the proposal
+	Approved bool `json:"approved"`
+}
+
+// ClusterAnalysis is the reviewable result of 'pft analyze --cluster'
+type ClusterAnalysis struct {
+	Area        string            `json:"area"`
+	CreatedAt   string            `json:"created_at"`
+	Embedder    string            `json:"embedder"`
+	Threshold   float64           `json:"threshold"`
+	Clusters    []FeedbackCluster `json:"clusters"`
+	Unclustered []ClusterItem     `json:"unclustered,omitempty"`
+	Warnings    []string          `json:"warnings,omitempty"`
+}
+
+// clusterVectors groups vectors by average-linkage agglomerative
+// clustering, merging groups while their average similarity reaches
+// threshold
+func clusterVectors(vectors [][]float64, threshold float64) [][]int {
+	n := len(vectors)
+	sim := make([][]float64, n)
+	for i := range sim {
+		sim[i] = make([]float64, n)
+		for j := range sim[i] {
+			sim[i][j] = cosine(vectors[i], vectors[j])
+		}
+	}
+	groups := make([][]int, n)
+	for i := range groups {
+		groups[i] = []int{i}
+	}
+	average := func(a, b []int) float64 {
+		var sum float64
+		for _, i := range a {
+			for _, j := range b {
+				sum += sim[i][j]
+			}
+		}
+		return sum / float64(len(a)*len(b))
+	}
+	for len(groups) > 1 {
+		best, bi, bj := -1.0, -1, -1
+		for i := 0; i < len(groups); i++ {
+			for j := i + 1; j < len(groups); j++ {
+				if s := average(groups[i], groups[j]); s > best {
+					best, bi, bj = s, i, j
+				}
+			}
+		}
+		if best < threshold {
+			break
+		}
+		groups[bi] = append(groups[bi], groups[bj]...)
+		groups = append(groups[:bj], groups[bj+1:]...)
+	}
+	for _, g := range groups {
+		sort.Ints(g)
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return len(groups[i]) > len(groups[j]) })
+	return groups
+}
+
+func centroid(vectors [][]float64, members []int) []float64 {
+	c := make([]float64, len(vectors[members[0]]))
+	for _, m := range members {
+		for i, x := range vectors[m] {
+			c[i] += x
+		}
+	}
+	return normalize(c)
+}
+
+// clusterKeywords returns the terms most characteristic of a cluster: the
+// number of its items using a term, weighted up for terms rare outside it
+func clusterKeywords(items []FeedbackItem, members []int, limit int) []string {
+	df := make(map[string]int)
+	itemTerms := make([]map[string]bool, len(items))
+	for i, item := range items {
+		itemTerms[i] = make(map[string]bool)
+		for _, t := range analysisTokens(feedbackText(item)) {
+			itemTerms[i][t] = true
+		}
+		for t := range itemTerms[i] {
+			df[t]++
+		}
+	}
+	scores := make(map[string]float64)
+	for _, m := range members {
+		for t := range itemTerms[m] {
+			scores[t] += 1 + math.Log(float64(len(items))/float64(df[t]))
+		}
+	}
+	terms := make([]string, 0, len(scores))
+	for t := range scores {
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if scores[terms[i]] != scores[terms[j]] {
+			return scores[terms[i]] > scores[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > limit {
+		terms = terms[:limit]
+	}
+	return terms
+}
+
+func feedbackText(item FeedbackItem) string {
+	return strings.Join([]string{item.Title, item.Summary, item.Description}, "\n")
+}
+
+var categoryIDInvalid = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// newCategoryProposal names a new category after the cluster keywords
+func newCategoryProposal(keywords []string) ProposedCategory {
+	words := keywords
+	if len(words) > 2 {
+		words = words[:2]
+	}
+	var parts []string
+	for _, w := range words {
+		if p := categoryIDInvalid.ReplaceAllString(strings.ToUpper(w), ""); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		parts = []string{"THEME"}
+	}
+	name := strings.Join(words, " ")
+	if r := []rune(name); len(r) > 0 {
+		name = string(unicode.ToUpper(r[0])) + string(r[1:])
+	}
+	return ProposedCategory{ID: strings.Join(parts, "-"), Name: name}
+}
+
+// extractiveSummary describes a cluster by its keywords and its most
+// central item
+func extractiveSummary(items []ClusterItem, keywords []string) string {
+	representative := items[0]
+	for _, item := range items {
+		if item.Similarity > representative.Similarity {
+			representative = item
+		}
+	}
+	return fmt.Sprintf("%d items about %s. Representative: %s \"%s\".",
+		len(items), strings.Join(keywords, ", "), representative.ID, representative.Title)
+}
+
+// AnalyzeClusters groups similar feedback items of an area and proposes a
+// category and summary for each group. The categories of the area are
+// embedded with the items so a cluster matching an existing category
+// reuses it.
+func AnalyzeClusters(area string, items []FeedbackItem, categories []Category, embedder Embedder, threshold float64) (*ClusterAnalysis, error) {
+	if len(items) < 2 {
+		return nil, fmt.Errorf("at least 2 feedback items are needed, %s has %d", area, len(items))
+	}
+	if threshold <= 0 {
+		threshold = embedder.DefaultThreshold()
+	}
+
+	texts := make([]string, 0, len(items)+len(categories))
+	for _, item := range items {
+		texts = append(texts, feedbackText(item))
+	}
+	for _, cat := range categories {
+		texts = append(texts, cat.Name+"\n"+cat.Description)
+	}
+	vectors, err := embedder.Embed(texts)
+	if err != nil {
+		return nil, err
+	}
+	itemVectors, categoryVectors := vectors[:len(items)], vectors[len(items):]
+
+	analysis := &ClusterAnalysis{
+		Area:      area,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Embedder:  embedder.Name(),
+		Threshold: threshold,
+	}
+	model, _ := embedder.(LanguageModel)
+	for _, members := range clusterVectors(itemVectors, threshold) {
+		center := centroid(itemVectors, members)
+		var clusterItems []ClusterItem
+		var memberItems []FeedbackItem
+		for _, m := range members {
+			clusterItems = append(clusterItems, ClusterItem{
+				ID: items[m].ID, Title: items[m].Title, Similarity: round2(cosine(itemVectors[m], center)),
+			})
+			memberItems = append(memberItems, items[m])
+		}
+		if len(members) == 1 {
+			analysis.Unclustered = append(analysis.Unclustered, clusterItems[0])
+			continue
+		}
+
+		cluster := FeedbackCluster{ID: len(analysis.Clusters) + 1, Items: clusterItems}
+		cluster.Keywords = clusterKeywords(items, members, 4)
+		cluster.Category = newCategoryProposal(cluster.Keywords)
+		for i, cat := range categories {
+			if s := cosine(categoryVectors[i], center); s >= threshold && s > cluster.Category.Similarity {
+				cluster.Category = ProposedCategory{ID: cat.ID, Name: cat.Name, Existing: true, Similarity: round2(s)}
+			}
+		}
+		cluster.Summary = extractiveSummary(clusterItems, cluster.Keywords)
+		if model != nil {
+			summary, err := model.Complete(summaryPrompt(memberItems, cluster.Keywords), false)
+			if err != nil {
+				analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("cluster %d: %v; keyword summary used", cluster.ID, err))
+			} else if summary != "" {
+				cluster.Summary = summary
+			}
+		}
+		analysis.Clusters = append(analysis.Clusters, cluster)
+	}
+	return analysis, nil
+}
+
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
+
+// SaveClusterAnalysis writes the analysis as JSON, edited by reviewers and
+// read by --apply, and as a markdown report for workshops. It returns the
+// path of the JSON file.
+func SaveClusterAnalysis(projectDir string, analysis *ClusterAnalysis) (string, error) {
+	dir := filepath.Join(projectDir, analysisDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	base := filepath.Join(dir, fmt.Sprintf("clusters-%s-%s", analysis.Area, time.Now().Format("20060102-150405")))
+	data, err := json.MarshalIndent(analysis, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(base+".json", data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write analysis: %w", err)
+	}
+	if err := os.WriteFile(base+".md", []byte(clusterReport(analysis)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+	return base + ".json", nil
+}
+
+// LoadClusterAnalysis reads an analysis saved by SaveClusterAnalysis
+func LoadClusterAnalysis(path string) (*ClusterAnalysis, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var analysis ClusterAnalysis
+	if err := json.Unmarshal(data, &analysis); err != nil {
+		return nil, fmt.Errorf("invalid analysis %s: %w", path, err)
+	}
+	if !IsValidArea(analysis.Area) {
+		return nil, fmt.Errorf("invalid area %q in %s", analysis.Area, path)
+	}
+	return &analysis, nil
+}
+
+// clusterReport renders the analysis as a markdown workshop handout
+func clusterReport(analysis *ClusterAnalysis) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Feedback Themes - %s\n\n", strings.ToUpper(analysis.Area))
+	fmt.Fprintf(&b, "Generated %s with %s (similarity threshold %.2f).\n\n", analysis.CreatedAt, analysis.Embedder, analysis.Threshold)
+	for _, c := range analysis.Clusters {
+		fmt.Fprintf(&b, "## Theme %d: %s\n\n", c.ID, strings.Join(c.Keywords, ", "))
+		fmt.Fprintf(&b, "%s\n\n", c.Summary)
+		status := "new category"
+		if c.Category.Existing {
+			status = "existing category"
+		}
+		fmt.Fprintf(&b, "**Proposed category:** %s (%s, %s)\n\n", c.Category.ID, c.Category.Name, status)
+		for _, item := range c.Items {
+			fmt.Fprintf(&b, "- %s: %s\n", item.ID, item.Title)
+		}
+		b.WriteString("\n")
+	}
+	if len(analysis.Unclustered) > 0 {
+		b.WriteString("## Not grouped\n\n")
+		for _, item := range analysis.Unclustered {
+			fmt.Fprintf(&b, "- %s: %s\n", item.ID, item.Title)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ApplyClusterAnalysis assigns the proposed categories of approved
+// clusters to their items, creating new categories in the area. With
+// dryRun only the changes are returned.
+func ApplyClusterAnalysis(projectDir string, analysis *ClusterAnalysis, dryRun bool) ([]string, error) {
+	registry, err := LoadCategoryRegistry(projectDir, analysis.Area)
+	if err != nil {
+		return nil, err
+	}
+	var changes []string
+	for _, c := range analysis.Clusters {
+		if !c.Approved {
+			continue
+		}
+		categoryID := NormalizeCategoryID(c.Category.ID)
+		if !registry.HasCategory(categoryID) {
+			cat := Category{ID: categoryID, Name: c.Category.Name, Description: truncateString(c.Summary, 200)}
+			if err := registry.AddCategory(cat); err != nil {
+				return changes, fmt.Errorf("cluster %d: %w", c.ID, err)
+			}
+			changes = append(changes, fmt.Sprintf("create category %s (%s)", categoryID, c.Category.Name))
+			if !dryRun {
+				if err := SaveCategoryRegistry(projectDir, analysis.Area, registry); err != nil {
+					return changes, err
+				}
+			}
+		}
+		for _, item := range c.Items {
+			filePath, _, err := findFeedbackItemFile(projectDir, item.ID)
+			if err != nil {
+				return changes, fmt.Errorf("cluster %d: %w", c.ID, err)
+			}
+			changes = append(changes, fmt.Sprintf("assign %s to %s", categoryID, item.ID))
+			if dryRun {
+				continue
+			}
+			if err := AddCategoryToFile(filePath, categoryID); err != nil {
+				return changes, fmt.Errorf("failed to assign %s to %s: %w", categoryID, item.ID, err)
+			}
+		}
+	}
+	return changes, nil
+}
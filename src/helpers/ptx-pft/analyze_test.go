@@ -0,0 +1,137 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var analysisItems = []FeedbackItem{
+	{ID: "UC001", Title: "Export reports to PDF", Description: "Monthly reports should export to PDF for printing."},
+	{ID: "UC002", Title: "PDF export of dashboards", Description: "Export the dashboard as a PDF report."},
+	{ID: "UC003", Title: "Excel export", Description: "Export report tables to Excel spreadsheets and PDF."},
+	{ID: "UC004", Title: "Login with Google", Description: "Single sign-on login through Google accounts."},
+	{ID: "UC005", Title: "SSO login via Azure", Description: "Single sign-on login with Azure AD accounts."},
+	{ID: "UC006", Title: "Dark mode", Description: "A dark colour theme for night shifts."},
+}
+
+func TestAnalyzeClustersLocal(t *testing.T) {
+	categories := []Category{{ID: "AUTH", Name: "Login and single sign-on", Description: "Authentication, login, accounts"}}
+	analysis, err := AnalyzeClusters("voc", analysisItems, categories, localEmbedder{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(analysis.Clusters) != 2 {
+		t.Fatalf("expected export and login clusters, got %+v", analysis.Clusters)
+	}
+	export, login := analysis.Clusters[0], analysis.Clusters[1]
+	if len(export.Items) != 3 || export.Category.Existing || !strings.Contains(export.Category.ID, "EXPORT") {
+		t.Errorf("unexpected export cluster %+v", export)
+	}
+	if len(login.Items) != 2 || !login.Category.Existing || login.Category.ID != "AUTH" {
+		t.Errorf("login cluster should reuse the AUTH category: %+v", login)
+	}
+	if len(analysis.Unclustered) != 1 || analysis.Unclustered[0].ID != "UC006" {
+		t.Errorf("unexpected unclustered items %+v", analysis.Unclustered)
+	}
+	if export.Approved || export.Summary == "" {
+		t.Errorf("proposals must start unapproved with a summary: %+v", export)
+	}
+}
+
+func TestApplyClusterAnalysis(t *testing.T) {
+	projectDir := t.TempDir()
+	vocDir := filepath.Join(projectDir, "VoC")
+	os.MkdirAll(vocDir, 0755)
+	for _, item := range analysisItems[:3] {
+		content := "---\nid: " + item.ID + "\nstatus: open\n---\n\n# " + item.ID + ": " + item.Title + "\n\n## Description\n" + item.Description + "\n"
+		os.WriteFile(filepath.Join(vocDir, item.ID+".md"), []byte(content), 0644)
+	}
+
+	items, err := scanLocalDirectory(vocDir, "voc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysis, err := AnalyzeClusters("voc", items, nil, localEmbedder{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := SaveClusterAnalysis(projectDir, analysis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(strings.TrimSuffix(path, ".json") + ".md"); err != nil {
+		t.Errorf("workshop report missing: %v", err)
+	}
+	loaded, err := LoadClusterAnalysis(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changes, _ := ApplyClusterAnalysis(projectDir, loaded, false); len(changes) != 0 {
+		t.Fatalf("unapproved clusters must not be applied: %v", changes)
+	}
+	approveClusters(loaded, "all")
+	if _, err := ApplyClusterAnalysis(projectDir, loaded, true); err != nil {
+		t.Fatal(err)
+	}
+	if registry, _ := LoadCategoryRegistry(projectDir, "voc"); len(registry.Categories) != 0 {
+		t.Fatal("dry run must not create categories")
+	}
+	if _, err := ApplyClusterAnalysis(projectDir, loaded, false); err != nil {
+		t.Fatal(err)
+	}
+	categoryID := loaded.Clusters[0].Category.ID
+	registry, _ := LoadCategoryRegistry(projectDir, "voc")
+	if !registry.HasCategory(categoryID) {
+		t.Errorf("category %s not created", categoryID)
+	}
+	item, err := ParseMarkdownFile(filepath.Join(vocDir, "UC001.md"))
+	if err != nil || len(item.Categories) != 1 || item.Categories[0] != categoryID {
+		t.Errorf("UC001 not assigned to %s: %+v %v", categoryID, item, err)
+	}
+}
+
+func TestOllamaEmbedder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		switch r.URL.Path {
+		case "/api/embed":
+			json.NewDecoder(r.Body).Decode(&req)
+			var embeddings [][]float64
+			for _, text := range req.Input {
+				if strings.Contains(strings.ToLower(text), "login") {
+					embeddings = append(embeddings, []float64{0, 1})
+				} else {
+					embeddings = append(embeddings, []float64{1, 0.1})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"embeddings": embeddings})
+		case "/api/generate":
+			w.Write([]byte(`{"response": "Customers want to share reports offline."}`))
+		}
+	}))
+	defer server.Close()
+
+	embedder, err := NewEmbedder(&AIConfig{Provider: "ollama", URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysis, err := AnalyzeClusters("voc", analysisItems[:5], nil, embedder, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(analysis.Clusters) != 2 || analysis.Clusters[0].Summary != "Customers want to share reports offline." {
+		t.Errorf("unexpected analysis %+v", analysis)
+	}
+}
@@ -205,3 +205,58 @@ func (c *FiderClient) GetUser(id int) (*FiderUser, error) {
 
 	return &user, nil
 }
+
+// FiderCreateTag represents the request body for creating a tag
+type FiderCreateTag struct {
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	IsPublic bool   `json:"isPublic"`
+}
+
+// ListTags returns all tags from Fider
+func (c *FiderClient) ListTags() ([]FiderTag, error) {
+	respBody, err := c.doRequest("GET", "/api/v1/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []FiderTag
+	if err := json.Unmarshal(respBody, &tags); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return tags, nil
+}
+
+// CreateTag creates a new tag in Fider; color is hex without '#'
+func (c *FiderClient) CreateTag(name, color string, isPublic bool) (*FiderTag, error) {
+	reqBody := FiderCreateTag{
+		Name:     name,
+		Color:    color,
+		IsPublic: isPublic,
+	}
+
+	respBody, err := c.doRequest("POST", "/api/v1/tags", reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag FiderTag
+	if err := json.Unmarshal(respBody, &tag); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &tag, nil
+}
+
+// AssignTag adds a tag to a post
+func (c *FiderClient) AssignTag(number int, slug string) error {
+	_, err := c.doRequest("POST", fmt.Sprintf("/api/v1/posts/%d/tags/%s", number, slug), nil)
+	return err
+}
+
+// UnassignTag removes a tag from a post
+func (c *FiderClient) UnassignTag(number int, slug string) error {
+	_, err := c.doRequest("DELETE", fmt.Sprintf("/api/v1/posts/%d/tags/%s", number, slug), nil)
+	return err
+}
@@ -8,5 +8,17 @@ var QFDTemplates embed.FS
 //go:embed email/*
 var EmailTemplates embed.FS
 
+//go:embed chat/*
+var ChatTemplates embed.FS
+
 //go:embed fider/*
 var FiderTemplates embed.FS
+
+//go:embed site/*
+var SiteTemplates embed.FS
+
+//go:embed locale/*
+var LocaleTemplates embed.FS
+
+//go:embed compose/*
+var ComposeTemplates embed.FS
@@ -0,0 +1,542 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"portunix.ai/app/tui"
+)
+
+// boardColumns are the columns of 'pft board'. Moving an item into a
+// column sets its status.
+var boardColumns = []struct{ Key, Label, Status string }{
+	{"open", "Open", "open"},
+	{"under-review", "Under Review", "under-review"},
+	{"planned", "Planned", "planned"},
+	{"in-progress", "In Progress", "in-progress"},
+	{"done", "Done", "done"},
+	{"closed", "Closed", "declined"},
+}
+
+// boardColumnIndex returns the column of an item status
+func boardColumnIndex(status string) int {
+	key := "open"
+	if isTriagedStatus(status) {
+		key = roadmapColumn(status)
+	}
+	for i, column := range boardColumns {
+		if column.Key == key {
+			return i
+		}
+	}
+	return 0
+}
+
+// ANSI styles of the board
+const (
+	boardBold    = "\x1b[1m"
+	boardReverse = "\x1b[7m"
+	boardDim     = "\x1b[2m"
+	boardReset   = "\x1b[0m"
+)
+
+// Board is the kanban screen of 'pft board'. Every change is written to
+// the item files right away.
+type Board struct {
+	ProjectDir string
+	Area       string
+
+	columns    [][]FeedbackItem
+	categories []Category
+	users      []User
+	col        int
+	rows       []int // Cursor per column
+	offsets    []int // First visible item per column
+	width      int
+	height     int
+	status     string
+	picker     *boardPicker
+	detail     []string // Lines of the opened item, nil when closed
+	detailPath string
+	detailTop  int
+}
+
+// boardPicker selects a category or user for the selected item
+type boardPicker struct {
+	title   string
+	options []boardOption
+	cursor  int
+	apply   func(option boardOption) error
+}
+
+type boardOption struct {
+	Value   string
+	Label   string
+	Checked bool
+}
+
+// NewBoard creates a board of an area and loads its items
+func NewBoard(projectDir, area string) *Board {
+	b := &Board{ProjectDir: projectDir, Area: area, width: 80, height: 24}
+	b.Reload()
+	return b
+}
+
+// Reload reads the items, categories and users of the area again
+func (b *Board) Reload() {
+	b.columns = make([][]FeedbackItem, len(boardColumns))
+	if len(b.rows) != len(boardColumns) {
+		b.rows = make([]int, len(boardColumns))
+		b.offsets = make([]int, len(boardColumns))
+	}
+
+	items, err := scanLocalDirectory(getVoiceDir(b.ProjectDir, b.Area), b.Area)
+	if err != nil {
+		b.status = "Error: " + err.Error()
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	for _, item := range items {
+		if strings.EqualFold(filepath.Base(item.FilePath), "README.md") {
+			continue
+		}
+		i := boardColumnIndex(item.Status)
+		b.columns[i] = append(b.columns[i], item)
+	}
+	for i := range b.rows {
+		b.rows[i] = clampIndex(b.rows[i], len(b.columns[i]))
+	}
+
+	b.categories = nil
+	if registry, err := LoadCategoryRegistry(b.ProjectDir, b.Area); err == nil {
+		b.categories = registry.Categories
+	}
+	b.users = nil
+	if registry, err := LoadUserRegistry(b.ProjectDir); err == nil {
+		b.users = registry.Users
+	}
+}
+
+func clampIndex(i, n int) int {
+	if i >= n {
+		i = n - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
+
+// Selected returns the item under the cursor
+func (b *Board) Selected() (FeedbackItem, bool) {
+	column := b.columns[b.col]
+	if len(column) == 0 {
+		return FeedbackItem{}, false
+	}
+	return column[b.rows[b.col]], true
+}
+
+// focus moves the cursor to an item
+func (b *Board) focus(id string) {
+	for c, column := range b.columns {
+		for r, item := range column {
+			if item.ID == id {
+				b.col, b.rows[c] = c, r
+				return
+			}
+		}
+	}
+}
+
+// SetSize updates the terminal dimensions
+func (b *Board) SetSize(width, height int) {
+	if width > 0 && height > 0 {
+		b.width, b.height = width, height
+	}
+}
+
+// Update applies a key press and reports whether to quit
+func (b *Board) Update(key tui.Key) bool {
+	switch {
+	case b.detail != nil:
+		b.updateDetail(key)
+		return false
+	case b.picker != nil:
+		b.updatePicker(key)
+		return false
+	}
+
+	b.status = ""
+	switch key {
+	case "q", tui.KeyCtrlC, tui.KeyEsc:
+		return true
+	case tui.KeyLeft, "h":
+		b.col = clampIndex(b.col-1, len(boardColumns))
+	case tui.KeyRight, "l":
+		b.col = clampIndex(b.col+1, len(boardColumns))
+	case tui.KeyUp, "k":
+		b.rows[b.col] = clampIndex(b.rows[b.col]-1, len(b.columns[b.col]))
+	case tui.KeyDown, "j":
+		b.rows[b.col] = clampIndex(b.rows[b.col]+1, len(b.columns[b.col]))
+	case tui.KeyHome, "g":
+		b.rows[b.col] = 0
+	case tui.KeyEnd, "G":
+		b.rows[b.col] = clampIndex(len(b.columns[b.col]), len(b.columns[b.col]))
+	case "<", "H":
+		b.moveSelected(b.col - 1)
+	case ">", "L":
+		b.moveSelected(b.col + 1)
+	case tui.KeyEnter, "o":
+		b.openSelected()
+	case "c":
+		b.pickCategory()
+	case "u":
+		b.pickAssignee()
+	case tui.KeyTab, tui.KeyShiftTab:
+		b.switchArea(key == tui.KeyTab)
+	case "R", "r":
+		b.Reload()
+		b.status = "Reloaded"
+	default:
+		if len(key) == 1 && key[0] >= '1' && int(key[0]-'1') < len(boardColumns) {
+			b.moveSelected(int(key[0] - '1'))
+		}
+	}
+	return false
+}
+
+// moveSelected moves the selected item to a column by changing its status
+func (b *Board) moveSelected(col int) {
+	item, ok := b.Selected()
+	if !ok || col < 0 || col >= len(boardColumns) || col == b.col {
+		return
+	}
+	column := boardColumns[col]
+	if err := setItemStatus(item.FilePath, column.Status); err != nil {
+		b.status = "Error: " + err.Error()
+		return
+	}
+	b.Reload()
+	b.focus(item.ID)
+	b.status = fmt.Sprintf("%s moved to %s", item.ID, column.Label)
+}
+
+// setItemStatus changes the status of an item file in place, keeping the
+// rest of the file, and records the SLA dates like 'pft update --status'
+func setItemStatus(filePath, status string) error {
+	item, err := ParseMarkdownFile(filePath)
+	if err != nil {
+		return err
+	}
+	triaged, resolved := item.TriagedAt, item.ResolvedAt
+	recordStatusDates(status, &triaged, &resolved)
+	return UpdateFileFields(filePath, map[string]string{
+		"status":   status,
+		"updated":  time.Now().Format("2006-01-02"),
+		"triaged":  triaged,
+		"resolved": resolved,
+	})
+}
+
+func (b *Board) openSelected() {
+	item, ok := b.Selected()
+	if !ok {
+		return
+	}
+	content, err := os.ReadFile(item.FilePath)
+	if err != nil {
+		b.status = "Error: " + err.Error()
+		return
+	}
+	b.detail = strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	b.detailPath = item.FilePath
+	if rel, err := filepath.Rel(b.ProjectDir, item.FilePath); err == nil {
+		b.detailPath = rel
+	}
+	b.detailTop = 0
+}
+
+func (b *Board) updateDetail(key tui.Key) {
+	page := b.bodyHeight()
+	switch key {
+	case "q", tui.KeyEsc, tui.KeyEnter, tui.KeyCtrlC:
+		b.detail = nil
+	case tui.KeyUp, "k":
+		b.detailTop--
+	case tui.KeyDown, "j":
+		b.detailTop++
+	case tui.KeyPageUp:
+		b.detailTop -= page
+	case tui.KeyPageDown, " ":
+		b.detailTop += page
+	case tui.KeyHome, "g":
+		b.detailTop = 0
+	}
+	b.detailTop = clampIndex(b.detailTop, len(b.detail)-page+1)
+}
+
+// pickCategory toggles a category of the area on the selected item
+func (b *Board) pickCategory() {
+	item, ok := b.Selected()
+	if !ok {
+		return
+	}
+	if len(b.categories) == 0 {
+		b.status = fmt.Sprintf("No categories in %s (pft category add)", strings.ToUpper(b.Area))
+		return
+	}
+	assigned := map[string]bool{}
+	for _, id := range item.Categories {
+		assigned[strings.ToUpper(id)] = true
+	}
+	picker := &boardPicker{title: "Toggle category of " + item.ID}
+	for _, category := range b.categories {
+		picker.options = append(picker.options, boardOption{
+			Value:   category.ID,
+			Label:   fmt.Sprintf("%s  %s", category.ID, category.Name),
+			Checked: assigned[strings.ToUpper(category.ID)],
+		})
+	}
+	picker.apply = func(option boardOption) error {
+		if option.Checked {
+			b.status = fmt.Sprintf("%s removed from %s", option.Value, item.ID)
+			return RemoveCategoryFromFile(item.FilePath, option.Value)
+		}
+		b.status = fmt.Sprintf("%s added to %s", option.Value, item.ID)
+		return AddCategoryToFile(item.FilePath, option.Value)
+	}
+	b.picker = picker
+}
+
+// pickAssignee assigns the selected item to a user of the registry
+func (b *Board) pickAssignee() {
+	item, ok := b.Selected()
+	if !ok {
+		return
+	}
+	if len(b.users) == 0 {
+		b.status = "No users in the registry (pft user add)"
+		return
+	}
+	picker := &boardPicker{title: "Assign " + item.ID + " to"}
+	picker.options = append(picker.options, boardOption{Label: "(nobody)", Checked: item.Assignee == ""})
+	for _, user := range b.users {
+		picker.options = append(picker.options, boardOption{
+			Value:   user.ID,
+			Label:   fmt.Sprintf("%s  %s", user.Name, user.ID),
+			Checked: item.Assignee == user.ID,
+		})
+		if item.Assignee == user.ID {
+			picker.cursor = len(picker.options) - 1
+		}
+	}
+	picker.apply = func(option boardOption) error {
+		if option.Value == "" {
+			b.status = item.ID + " unassigned"
+		} else {
+			b.status = fmt.Sprintf("%s assigned to %s", item.ID, option.Value)
+		}
+		return UpdateFileFields(item.FilePath, map[string]string{"assignee": option.Value})
+	}
+	b.picker = picker
+}
+
+func (b *Board) updatePicker(key tui.Key) {
+	p := b.picker
+	switch key {
+	case "q", tui.KeyEsc, tui.KeyCtrlC:
+		b.picker = nil
+	case tui.KeyUp, "k":
+		p.cursor = clampIndex(p.cursor-1, len(p.options))
+	case tui.KeyDown, "j":
+		p.cursor = clampIndex(p.cursor+1, len(p.options))
+	case tui.KeyEnter, " ":
+		b.picker = nil
+		item, _ := b.Selected()
+		if err := p.apply(p.options[p.cursor]); err != nil {
+			b.status = "Error: " + err.Error()
+		}
+		b.Reload()
+		b.focus(item.ID)
+	}
+}
+
+func (b *Board) switchArea(forward bool) {
+	index := 0
+	for i, area := range ValidAreaNames {
+		if area == b.Area {
+			index = i
+		}
+	}
+	step := 1
+	if !forward {
+		step = len(ValidAreaNames) - 1
+	}
+	b.Area = ValidAreaNames[(index+step)%len(ValidAreaNames)]
+	b.col = 0
+	b.rows, b.offsets = nil, nil
+	b.Reload()
+}
+
+// bodyHeight is the number of lines between the header and the status
+// line: the screen minus title, column headers, separators, status and
+// footer
+func (b *Board) bodyHeight() int {
+	if h := b.height - 6; h > 1 {
+		return h
+	}
+	return 1
+}
+
+// View renders the screen
+func (b *Board) View() string {
+	var sb strings.Builder
+
+	// Title with the area tabs
+	total := 0
+	for _, column := range b.columns {
+		total += len(column)
+	}
+	sb.WriteString(boardBold + " PFT Board " + boardReset)
+	for _, area := range ValidAreaNames {
+		label := " " + strings.ToUpper(area) + " "
+		if area == b.Area {
+			label = boardReverse + label + boardReset
+		}
+		sb.WriteString(" " + label)
+	}
+	sb.WriteString(fmt.Sprintf("  %d items\r\n", total))
+	sb.WriteString(strings.Repeat("─", b.width) + "\r\n")
+
+	height := b.bodyHeight()
+	switch {
+	case b.detail != nil:
+		sb.WriteString(boardBold + fitWidth(" "+b.detailPath, b.width) + boardReset + "\r\n")
+		for i := 0; i < height; i++ {
+			line := ""
+			if n := b.detailTop + i; n < len(b.detail) {
+				line = b.detail[n]
+			}
+			sb.WriteString(fitWidth(line, b.width) + "\r\n")
+		}
+	case b.picker != nil:
+		sb.WriteString(boardBold + fitWidth(b.picker.title, b.width) + boardReset + "\r\n")
+		for i := 0; i < height; i++ {
+			if i >= len(b.picker.options) {
+				sb.WriteString("\r\n")
+				continue
+			}
+			option := b.picker.options[i]
+			mark := "[ ] "
+			if option.Checked {
+				mark = "[x] "
+			}
+			line := fitWidth(" "+mark+option.Label, b.width)
+			if i == b.picker.cursor {
+				line = boardReverse + line + boardReset
+			}
+			sb.WriteString(line + "\r\n")
+		}
+	default:
+		b.renderColumns(&sb, height)
+	}
+
+	// Status and key help
+	sb.WriteString(strings.Repeat("─", b.width) + "\r\n")
+	status := b.status
+	if status == "" && b.detail == nil && b.picker == nil {
+		if item, ok := b.Selected(); ok {
+			status = boardItemSummary(item)
+		}
+	}
+	sb.WriteString(fitWidth(status, b.width) + "\r\n")
+	sb.WriteString(boardDim + fitWidth(b.footer(), b.width) + boardReset)
+	return sb.String()
+}
+
+func (b *Board) renderColumns(sb *strings.Builder, height int) {
+	n := len(boardColumns)
+	width := (b.width - (n - 1)) / n
+	if width < 4 {
+		width = 4
+	}
+
+	var header []string
+	for i, column := range boardColumns {
+		label := fitWidth(fmt.Sprintf(" %s (%d)", column.Label, len(b.columns[i])), width)
+		if i == b.col {
+			label = boardReverse + label + boardReset
+		} else {
+			label = boardBold + label + boardReset
+		}
+		header = append(header, label)
+	}
+	sb.WriteString(strings.Join(header, "│") + "\r\n")
+
+	// Keep the cursor of every column visible
+	for i := range boardColumns {
+		if b.rows[i] < b.offsets[i] {
+			b.offsets[i] = b.rows[i]
+		}
+		if b.rows[i] >= b.offsets[i]+height {
+			b.offsets[i] = b.rows[i] - height + 1
+		}
+	}
+
+	for line := 0; line < height; line++ {
+		var cells []string
+		for i := range boardColumns {
+			cell := fitWidth("", width)
+			if r := b.offsets[i] + line; r < len(b.columns[i]) {
+				item := b.columns[i][r]
+				cell = fitWidth(" "+item.ID+" "+item.Title, width)
+				if i == b.col && r == b.rows[i] {
+					cell = boardReverse + cell + boardReset
+				}
+			}
+			cells = append(cells, cell)
+		}
+		sb.WriteString(strings.Join(cells, "│") + "\r\n")
+	}
+}
+
+func (b *Board) footer() string {
+	switch {
+	case b.detail != nil:
+		return "↑/↓ scroll  PgUp/PgDn page  Esc close"
+	case b.picker != nil:
+		return "↑/↓ select  Enter apply  Esc cancel"
+	}
+	return "←/→ column  ↑/↓ item  </> move  1-6 move to column  Enter open  c category  u assignee  Tab area  R reload  q quit"
+}
+
+// boardItemSummary describes the selected item in the status line
+func boardItemSummary(item FeedbackItem) string {
+	parts := []string{item.ID + " " + item.Title, "status: " + item.Status}
+	if len(item.Categories) > 0 {
+		parts = append(parts, "categories: "+strings.Join(item.Categories, ", "))
+	}
+	if item.Assignee != "" {
+		parts = append(parts, "assignee: "+item.Assignee)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// fitWidth truncates or pads a string to exactly width runes
+func fitWidth(s string, width int) string {
+	r := []rune(strings.ReplaceAll(s, "\t", "    "))
+	if width <= 0 {
+		return ""
+	}
+	if len(r) > width {
+		return string(r[:width-1]) + "…"
+	}
+	return string(r) + strings.Repeat(" ", width-len(r))
+}
@@ -0,0 +1,114 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"portunix.ai/app/tui"
+)
+
+func TestBoard(t *testing.T) {
+	projectDir := t.TempDir()
+	needs := filepath.Join(getVoiceDir(projectDir, "voc"), "needs")
+	if err := os.MkdirAll(needs, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, params := range []FeedbackItemParams{
+		{ID: "P01", Title: "Dark mode", Area: "voc", Status: "open", Created: "2026-10-01"},
+		{ID: "P02", Title: "Export to PDF", Area: "voc", Status: "planned", Created: "2026-10-01", Triaged: "2026-10-02"},
+	} {
+		content := generateFeedbackMarkdown(params) + "\n## Follow-up\n\nKeep this section.\n"
+		if err := os.WriteFile(filepath.Join(needs, params.ID+"-item.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	SaveCategoryRegistry(projectDir, "voc", &CategoryRegistry{Categories: []Category{{ID: "UX", Name: "User experience"}}})
+	SaveUserRegistry(projectDir, &UserRegistry{Users: []User{{ID: "jana@example.com", Name: "Jana"}}})
+
+	board := NewBoard(projectDir, "voc")
+	board.SetSize(120, 20)
+	if len(board.columns[0]) != 1 || len(board.columns[2]) != 1 {
+		t.Fatalf("items should be in Open and Planned: %+v", board.columns)
+	}
+	selected := func() *FeedbackItem {
+		t.Helper()
+		item, ok := board.Selected()
+		if !ok {
+			t.Fatal("nothing selected")
+		}
+		parsed, err := ParseMarkdownFile(item.FilePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	today := time.Now().Format("2006-01-02")
+
+	// Moving right twice plans the item and records its triage date
+	board.Update(">")
+	board.Update(">")
+	item := selected()
+	if board.col != 2 || item.ID != "P01" || item.Status != "planned" || item.TriagedAt != today {
+		t.Fatalf("P01 should be planned and selected: col %d, %+v", board.col, item)
+	}
+	content, _ := os.ReadFile(item.FilePath)
+	if !strings.Contains(string(content), "Keep this section.") {
+		t.Error("moving an item must keep the rest of the file")
+	}
+
+	board.Update("c")
+	board.Update(tui.KeyEnter)
+	if item := selected(); len(item.Categories) != 1 || item.Categories[0] != "UX" {
+		t.Errorf("category not added: %v", item.Categories)
+	}
+	board.Update("c")
+	if !board.picker.options[0].Checked {
+		t.Error("assigned category should be checked")
+	}
+	board.Update(tui.KeyEnter)
+	if item := selected(); len(item.Categories) != 0 {
+		t.Errorf("category not removed: %v", item.Categories)
+	}
+
+	board.Update("u")
+	board.Update(tui.KeyDown)
+	board.Update(tui.KeyEnter)
+	if item := selected(); item.Assignee != "jana@example.com" {
+		t.Errorf("assignee not set: %q", item.Assignee)
+	}
+
+	board.Update("5")
+	if item := selected(); item.Status != "done" || item.ResolvedAt != today {
+		t.Errorf("done should record the resolve date: %+v", item)
+	}
+	board.Update("4")
+	if item := selected(); item.Status != "in-progress" || item.ResolvedAt != "" {
+		t.Errorf("reopening should clear the resolve date: %+v", item)
+	}
+
+	board.Update("k") // Clears the status message, showing the selected item
+	view := board.View()
+	for _, want := range []string{"In Progress (1)", "Planned (1)", "P01 Dark mode", "assignee: jana@example.com"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view is missing %q:\n%s", want, view)
+		}
+	}
+
+	board.Update(tui.KeyEnter)
+	if board.detail == nil || !strings.Contains(board.View(), filepath.Join("needs", "P01-item.md")) {
+		t.Fatal("Enter should open the item")
+	}
+	if board.Update(tui.KeyEsc) || board.detail != nil {
+		t.Error("Esc should close the item, not the board")
+	}
+	if !board.Update("q") {
+		t.Error("q should quit")
+	}
+}
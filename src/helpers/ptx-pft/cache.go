@@ -10,6 +10,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"portunix.ai/app/fileutil"
 )
 
 const cacheFileName = ".pft-cache.json"
@@ -67,7 +69,7 @@ func (c *SyncCache) Save() error {
 		return fmt.Errorf("failed to serialize cache: %w", err)
 	}
 
-	if err := os.WriteFile(c.filePath, data, 0644); err != nil {
+	if err := fileutil.WriteFile(c.filePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write cache: %w", err)
 	}
 
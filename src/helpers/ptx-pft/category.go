@@ -12,6 +12,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"portunix.ai/app/fileutil"
 )
 
 // Category represents a category within an area
@@ -21,6 +23,7 @@ type Category struct {
 	Description string `json:"description,omitempty"`
 	Color       string `json:"color,omitempty"`
 	Order       int    `json:"order,omitempty"`
+	FiderTag    string `json:"fider_tag,omitempty"` // Slug of the mapped Fider tag
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
 }
@@ -133,7 +136,7 @@ func SaveCategoryRegistry(projectDir, area string, registry *CategoryRegistry) e
 	}
 
 	filePath := GetCategoriesFilePath(projectDir, area)
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
+	if err := fileutil.WriteFile(filePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write categories file: %w", err)
 	}
 
@@ -0,0 +1,406 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChatMessage is a notification posted to a chat channel
+type ChatMessage struct {
+	Title string
+	Text  string
+}
+
+// NotificationChannel posts notifications to a chat channel
+type NotificationChannel interface {
+	Name() string
+	Send(msg ChatMessage) error
+}
+
+// slackPostMessageURL is the Slack Web API method used with a bot token
+var slackPostMessageURL = "https://slack.com/api/chat.postMessage"
+
+var chatHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// ParseChannel resolves a channel spec such as slack:#product, teams:product
+// or just slack (the default channel) against the chat configuration
+func ParseChannel(config *Config, spec string) (NotificationChannel, error) {
+	kind, name, _ := strings.Cut(spec, ":")
+	chat := ChatConfig{}
+	if config.Chat != nil {
+		chat = *config.Chat
+	}
+
+	switch strings.ToLower(kind) {
+	case "slack":
+		if chat.Slack == nil || (chat.Slack.WebhookURL == "" && chat.Slack.BotToken == "") {
+			return nil, fmt.Errorf("slack is not configured (pft configure --slack-webhook <url> or --slack-token <token>)")
+		}
+		if name == "" {
+			name = chat.Slack.DefaultChannel
+		}
+		channel := &slackChannel{
+			channel:    name,
+			webhookURL: resolveSecret(chat.Slack.WebhookURL),
+			botToken:   resolveSecret(chat.Slack.BotToken),
+		}
+		// Incoming webhooks post to the channel they were created for
+		if name != "" && channel.botToken == "" && name != chat.Slack.DefaultChannel {
+			return nil, fmt.Errorf("posting to %s needs a Slack bot token (pft configure --slack-token <token>)", name)
+		}
+		return channel, nil
+	case "teams":
+		if chat.Teams == nil {
+			return nil, fmt.Errorf("teams is not configured (pft configure --teams-webhook [<channel>=]<url>)")
+		}
+		webhookURL := chat.Teams.WebhookURL
+		if name != "" {
+			webhookURL = chat.Teams.Webhooks[name]
+		}
+		if webhookURL == "" {
+			return nil, fmt.Errorf("no Teams webhook configured for channel '%s'", name)
+		}
+		return &teamsChannel{channel: name, webhookURL: resolveSecret(webhookURL)}, nil
+	default:
+		return nil, fmt.Errorf("unknown channel '%s' (use slack[:#channel] or teams[:channel])", spec)
+	}
+}
+
+// ChatNotification renders a notification template for chat channels
+func ChatNotification(notifyType NotificationType, data EmailData) (ChatMessage, error) {
+	data.Provider = "chat"
+	title, text, err := GenerateNotification(notifyType, data)
+	if err != nil {
+		return ChatMessage{}, err
+	}
+	return ChatMessage{Title: title, Text: text}, nil
+}
+
+// slackChannel posts through chat.postMessage when a bot token is set,
+// otherwise through the incoming webhook
+type slackChannel struct {
+	channel    string
+	webhookURL string
+	botToken   string
+}
+
+func (c *slackChannel) Name() string {
+	if c.channel == "" {
+		return "slack"
+	}
+	return "slack:" + c.channel
+}
+
+func (c *slackChannel) Send(msg ChatMessage) error {
+	payload := map[string]interface{}{
+		"text":   fmt.Sprintf("*%s*\n%s", msg.Title, msg.Text),
+		"mrkdwn": true,
+	}
+	if c.channel != "" {
+		payload["channel"] = c.channel
+	}
+
+	if c.botToken == "" {
+		_, err := postChatJSON(c.webhookURL, payload, nil)
+		return err
+	}
+	body, err := postChatJSON(slackPostMessageURL, payload, map[string]string{"Authorization": "Bearer " + c.botToken})
+	if err != nil {
+		return err
+	}
+	// The Web API reports errors in the body of a 200 response
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("invalid Slack response: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("slack: %s", result.Error)
+	}
+	return nil
+}
+
+// teamsChannel posts a message card to an incoming webhook
+type teamsChannel struct {
+	channel    string
+	webhookURL string
+}
+
+func (c *teamsChannel) Name() string {
+	if c.channel == "" {
+		return "teams"
+	}
+	return "teams:" + c.channel
+}
+
+func (c *teamsChannel) Send(msg ChatMessage) error {
+	payload := map[string]interface{}{
+		"@type":    "MessageCard",
+		"@context": "https://schema.org/extensions",
+		"summary":  msg.Title,
+		"title":    msg.Title,
+		// Teams joins single line breaks
+		"text": strings.ReplaceAll(msg.Text, "\n", "\n\n"),
+	}
+	_, err := postChatJSON(c.webhookURL, payload, nil)
+	return err
+}
+
+func postChatJSON(endpoint string, payload interface{}, headers map[string]string) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := chatHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post message: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to post message: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// ChatIntakeServer creates items from Slack slash commands (/slack/command)
+// and Teams outgoing webhook mentions (/teams/webhook). Every request must
+// be signed with the secret configured for its platform.
+type ChatIntakeServer struct {
+	Config     *Config
+	ProjectDir string
+	Options    IntakeOptions
+
+	mu sync.Mutex // Serializes item IDs and the intake state
+}
+
+// slackMaxSkew rejects replayed Slack requests
+const slackMaxSkew = 5 * time.Minute
+
+// Handler returns the HTTP handler of the intake endpoints
+func (s *ChatIntakeServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slack/command", s.handleSlackCommand)
+	mux.HandleFunc("/teams/webhook", s.handleTeamsWebhook)
+	return mux
+}
+
+// Endpoints lists the enabled endpoints, those with a configured secret
+func (s *ChatIntakeServer) Endpoints() []string {
+	var endpoints []string
+	if s.slackSecret() != "" {
+		endpoints = append(endpoints, "/slack/command")
+	}
+	if s.teamsSecret() != "" {
+		endpoints = append(endpoints, "/teams/webhook")
+	}
+	return endpoints
+}
+
+func (s *ChatIntakeServer) slackSecret() string {
+	if s.Config.Chat == nil || s.Config.Chat.Slack == nil {
+		return ""
+	}
+	return resolveSecret(s.Config.Chat.Slack.SigningSecret)
+}
+
+func (s *ChatIntakeServer) teamsSecret() string {
+	if s.Config.Chat == nil || s.Config.Chat.Teams == nil {
+		return ""
+	}
+	return resolveSecret(s.Config.Chat.Teams.OutgoingSecret)
+}
+
+func (s *ChatIntakeServer) handleSlackCommand(w http.ResponseWriter, r *http.Request) {
+	secret := s.slackSecret()
+	body, ok := readChatRequest(w, r, secret != "")
+	if !ok {
+		return
+	}
+	if err := verifySlackSignature(secret, r.Header, body, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	text := strings.TrimSpace(form.Get("text"))
+	var reply string
+	if text == "" || strings.EqualFold(text, "help") {
+		reply = fmt.Sprintf("Usage: %s <your feedback>. Include [P05] to add to an existing item.", form.Get("command"))
+	} else {
+		msg := chatInboundMessage("slack", "slack:"+form.Get("trigger_id"), form.Get("user_name"), text)
+		reply = s.process(msg)
+	}
+	writeChatJSON(w, map[string]string{"response_type": "ephemeral", "text": reply})
+}
+
+// teamsMention is the @mention of the outgoing webhook in a message
+var teamsMention = regexp.MustCompile(`(?s)<at>.*?</at>`)
+
+func (s *ChatIntakeServer) handleTeamsWebhook(w http.ResponseWriter, r *http.Request) {
+	secret := s.teamsSecret()
+	body, ok := readChatRequest(w, r, secret != "")
+	if !ok {
+		return
+	}
+	if err := verifyTeamsSignature(secret, r.Header.Get("Authorization"), body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	var activity struct {
+		ID   string `json:"id"`
+		Text string `json:"text"`
+		From struct {
+			Name string `json:"name"`
+		} `json:"from"`
+	}
+	if err := json.Unmarshal(body, &activity); err != nil {
+		http.Error(w, "invalid message", http.StatusBadRequest)
+		return
+	}
+
+	text := strings.TrimSpace(htmlToText(teamsMention.ReplaceAllString(activity.Text, "")))
+	reply := "Mention me followed by your feedback. Include [P05] to add to an existing item."
+	if text != "" && !strings.EqualFold(text, "help") {
+		reply = s.process(chatInboundMessage("teams", "teams:"+activity.ID, activity.From.Name, text))
+	}
+	writeChatJSON(w, map[string]string{"type": "message", "text": reply})
+}
+
+// readChatRequest reads the body of a POST to an enabled endpoint
+func readChatRequest(w http.ResponseWriter, r *http.Request, enabled bool) ([]byte, bool) {
+	if !enabled {
+		http.Error(w, "endpoint not configured", http.StatusNotFound)
+		return nil, false
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return nil, false
+	}
+	return body, true
+}
+
+func writeChatJSON(w http.ResponseWriter, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payload)
+}
+
+// verifySlackSignature checks the v0 request signature of Slack
+func verifySlackSignature(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing request timestamp")
+	}
+	if skew := now.Sub(time.Unix(seconds, 0)); skew > slackMaxSkew || skew < -slackMaxSkew {
+		return fmt.Errorf("request timestamp too old")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// verifyTeamsSignature checks the HMAC authorization of a Teams outgoing
+// webhook, whose security token is base64 encoded
+func verifyTeamsSignature(secret, authorization string, body []byte) error {
+	key, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return fmt.Errorf("invalid Teams security token")
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	expected := "HMAC " + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(authorization)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// chatInboundMessage converts a chat message for intake: its first line is
+// the title, the whole text the verbatim
+func chatInboundMessage(source, id, author, text string) *InboundMessage {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	title := strings.TrimSpace(strings.SplitN(text, "\n", 2)[0])
+	if len(title) > 80 {
+		title = truncateString(title, 77)
+	}
+	return &InboundMessage{
+		Source:    source,
+		MessageID: id,
+		Subject:   title,
+		FromName:  author,
+		Date:      time.Now(),
+		Body:      text,
+	}
+}
+
+// process records a chat message and returns the reply for its author
+func (s *ChatIntakeServer) process(msg *InboundMessage) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := loadIntakeState(s.ProjectDir)
+	if err != nil {
+		return fmt.Sprintf("Sorry, the feedback could not be recorded: %v", err)
+	}
+	entry, err := ProcessInboundMessage(s.Config, s.ProjectDir, msg, state, s.Options)
+	if err == nil && !s.Options.DryRun {
+		err = state.save(s.ProjectDir)
+	}
+	if err != nil {
+		return fmt.Sprintf("Sorry, the feedback could not be recorded: %v", err)
+	}
+	if entry.From == "" {
+		entry.From = msg.FromName + " (" + msg.Source + ")"
+	}
+	printIntakeEntries([]IntakeEntry{entry}, false, s.Options.DryRun)
+
+	switch entry.Outcome {
+	case IntakeCreated:
+		return fmt.Sprintf("Thank you! Your feedback was recorded as %s: %s", entry.ItemID, msg.Subject)
+	case IntakeFollowUp:
+		return fmt.Sprintf("Thank you! Your comment was added to %s.", entry.ItemID)
+	default:
+		return fmt.Sprintf("This message was already recorded as %s.", entry.ItemID)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotificationChannels(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		payload["authorization"] = r.Header.Get("Authorization")
+		mu.Lock()
+		received[r.URL.Path] = payload
+		mu.Unlock()
+		if r.URL.Path == "/api" {
+			fmt.Fprint(w, `{"ok": true}`)
+		}
+	}))
+	defer server.Close()
+
+	config := &Config{Chat: &ChatConfig{
+		Slack: &SlackConfig{WebhookURL: server.URL + "/slack"},
+		Teams: &TeamsConfig{Webhooks: map[string]string{"product": server.URL + "/teams"}},
+	}}
+	msg, err := ChatNotification(NotifyVote, EmailData{ProductName: "Acme", Title: "Dark mode", ItemID: "P01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Title != "[Acme] Vote request: Dark mode" || !strings.Contains(msg.Text, "P01") || strings.Contains(msg.Text, "email") {
+		t.Errorf("unexpected chat message %+v", msg)
+	}
+
+	for _, spec := range []string{"slack", "teams:product"} {
+		channel, err := ParseChannel(config, spec)
+		if err != nil {
+			t.Fatalf("%s: %v", spec, err)
+		}
+		if err := channel.Send(msg); err != nil {
+			t.Fatalf("%s: %v", spec, err)
+		}
+	}
+	if text, _ := received["/slack"]["text"].(string); !strings.HasPrefix(text, "*[Acme] Vote request: Dark mode*\n") {
+		t.Errorf("unexpected Slack payload %v", received["/slack"])
+	}
+	if received["/teams"]["@type"] != "MessageCard" || received["/teams"]["title"] != msg.Title {
+		t.Errorf("unexpected Teams payload %v", received["/teams"])
+	}
+
+	for _, spec := range []string{"slack:#other", "teams", "teams:unknown", "irc:#product"} {
+		if _, err := ParseChannel(config, spec); err == nil {
+			t.Errorf("%s should not resolve", spec)
+		}
+	}
+
+	// A bot token posts to any channel through the Web API
+	defer func(original string) { slackPostMessageURL = original }(slackPostMessageURL)
+	slackPostMessageURL = server.URL + "/api"
+	config.Chat.Slack.BotToken = "xoxb-test"
+	channel, err := ParseChannel(config, "slack:#product")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := channel.Send(msg); err != nil {
+		t.Fatal(err)
+	}
+	if received["/api"]["channel"] != "#product" || received["/api"]["authorization"] != "Bearer xoxb-test" {
+		t.Errorf("unexpected Web API request %v", received["/api"])
+	}
+}
+
+func TestChatIntakeServer(t *testing.T) {
+	projectDir := t.TempDir()
+	teamsKey := base64.StdEncoding.EncodeToString([]byte("teams-key"))
+	server := &ChatIntakeServer{
+		Config: &Config{Chat: &ChatConfig{
+			Slack: &SlackConfig{SigningSecret: "slack-secret"},
+			Teams: &TeamsConfig{OutgoingSecret: teamsKey},
+		}},
+		ProjectDir: projectDir,
+		Options:    IntakeOptions{Area: "voc"},
+	}
+	handler := server.Handler()
+
+	slackRequest := func(form url.Values, secret string) (int, string) {
+		body := form.Encode()
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(secret))
+		fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+		req := httptest.NewRequest(http.MethodPost, "/slack/command", strings.NewReader(body))
+		req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+		req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		var reply map[string]string
+		json.Unmarshal(rec.Body.Bytes(), &reply)
+		return rec.Code, reply["text"]
+	}
+
+	form := url.Values{"command": {"/feedback"}, "text": {"Export to PDF crashes\nWith more than 100 pages."}, "user_name": {"jana"}, "trigger_id": {"t1"}}
+	if code, _ := slackRequest(form, "wrong-secret"); code != http.StatusUnauthorized {
+		t.Fatalf("unsigned request must be rejected, got %d", code)
+	}
+	code, reply := slackRequest(form, "slack-secret")
+	if code != http.StatusOK || !strings.Contains(reply, "recorded as P01") {
+		t.Fatalf("unexpected reply %d %q", code, reply)
+	}
+	if _, reply := slackRequest(form, "slack-secret"); !strings.Contains(reply, "already recorded as P01") {
+		t.Errorf("a retried command must not create a second item: %q", reply)
+	}
+
+	items, _ := scanLocalDirectory(getVoiceDir(projectDir, "voc"), "voc")
+	if len(items) != 1 || items[0].Title != "Export to PDF crashes" {
+		t.Fatalf("unexpected items %+v", items)
+	}
+
+	// A Teams mention tagged with the item ID is added as a follow-up
+	activity := `{"type":"message","id":"m1","text":"<at>Feedback</at>&nbsp;[P01] Same in 2.0","from":{"name":"Petr"}}`
+	mac := hmac.New(sha256.New, []byte("teams-key"))
+	mac.Write([]byte(activity))
+	req := httptest.NewRequest(http.MethodPost, "/teams/webhook", strings.NewReader(activity))
+	req.Header.Set("Authorization", "HMAC "+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	body, _ := io.ReadAll(rec.Body)
+	if rec.Code != http.StatusOK || !strings.Contains(string(body), "added to P01") {
+		t.Fatalf("unexpected Teams reply %d %s", rec.Code, body)
+	}
+	content, _ := os.ReadFile(items[0].FilePath)
+	for _, want := range []string{"source: slack", "author: jana", "(Petr)", "> [P01] Same in 2.0"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("item is missing %q:\n%s", want, content)
+		}
+	}
+}
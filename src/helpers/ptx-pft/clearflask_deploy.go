@@ -90,24 +90,13 @@ func getClearFlaskDeployDir() (string, error) {
 	return deployDir, nil
 }
 
-// writeClearFlaskComposeFile generates and writes docker-compose.yaml for ClearFlask
-func writeClearFlaskComposeFile(deployDir string) (string, error) {
-	pkg, err := loadPackageDefinition("clearflask")
-	if err != nil {
+// writeClearFlaskComposeFile renders docker-compose.yaml from the clearflask stack template
+func writeClearFlaskComposeFile(deployDir string, config *Config) (string, error) {
+	spec := StackSpec{Template: "clearflask", AllocatePorts: true}
+	if _, err := WriteStack(deployDir, clearflaskComposeFile, spec, config); err != nil {
 		return "", err
 	}
-
-	yamlData, err := generateComposeYAML(pkg)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate compose YAML: %w", err)
-	}
-
-	composePath := filepath.Join(deployDir, clearflaskComposeFile)
-	if err := os.WriteFile(composePath, yamlData, 0644); err != nil {
-		return "", fmt.Errorf("failed to write compose file: %w", err)
-	}
-
-	return composePath, nil
+	return filepath.Join(deployDir, clearflaskComposeFile), nil
 }
 
 // writeClearFlaskEnvFile writes environment variables for ClearFlask docker-compose
@@ -138,7 +127,7 @@ func writeClearFlaskEnvFile(deployDir string, config *Config) (string, error) {
 	// Determine host URL
 	hostURL := config.GetEndpoint()
 	if hostURL == "" {
-		hostURL = "http://localhost:3100"
+		hostURL = fmt.Sprintf("http://localhost:%d", stackPort(deployDir, "web", 3100))
 	}
 
 	env := fmt.Sprintf(`# ClearFlask environment configuration
@@ -205,7 +194,7 @@ func DeployClearFlask(config *Config) (*DeployResult, error) {
 	}
 
 	// Write compose file (generated from JSON)
-	composePath, err := writeClearFlaskComposeFile(deployDir)
+	composePath, err := writeClearFlaskComposeFile(deployDir, config)
 	if err != nil {
 		return nil, err
 	}
@@ -234,14 +223,14 @@ func DeployClearFlask(config *Config) (*DeployResult, error) {
 	// Start services
 	fmt.Println()
 	fmt.Println("Starting services...")
-	if err := runClearFlaskContainerCompose(deployDir, "up", "-d"); err != nil {
+	if err := runClearFlaskContainerCompose(deployDir, "up", "--wait"); err != nil {
 		return nil, fmt.Errorf("failed to start services: %w", err)
 	}
 
 	// Determine URL
 	baseURL := config.GetEndpoint()
 	if baseURL == "" {
-		baseURL = "http://localhost:3100"
+		baseURL = fmt.Sprintf("http://localhost:%d", stackPort(deployDir, "web", 3100))
 	}
 
 	result.Success = true
@@ -249,9 +238,7 @@ func DeployClearFlask(config *Config) (*DeployResult, error) {
 	result.Message = fmt.Sprintf(`ClearFlask deployed successfully!
 
 Access ClearFlask at: %s
-LocalStack S3 at:     http://localhost:4566
-
-Note: First startup may take 1-2 minutes for database initialization and Elasticsearch indexing.
+LocalStack S3 at:     http://localhost:%d
 
 Services:
   - MariaDB:              Running
@@ -260,7 +247,7 @@ Services:
   - ClearFlask Server:    Running
   - ClearFlask Connect:   Running
 
-Resource usage: ~2GB RAM minimum`, baseURL)
+Resource usage: ~2GB RAM minimum`, baseURL, stackPort(deployDir, "s3", 4566))
 
 	return result, nil
 }
@@ -394,13 +381,12 @@ func DeployClearFlaskInstance(instanceName string, port int, config *Config) (*D
 
 	projectName := fmt.Sprintf("portunix-clearflask-%s", instanceName)
 
-	// Generate compose file with custom port
-	composeContent := generateClearFlaskInstanceComposeYAML(instanceName, port)
-	composePath := filepath.Join(deployDir, clearflaskComposeFile)
-	if err := os.WriteFile(composePath, []byte(composeContent), 0644); err != nil {
-		return nil, fmt.Errorf("failed to write compose file: %w", err)
+	// Generate compose file with custom port; LocalStack on port+50
+	spec := StackSpec{Template: "clearflask", Instance: instanceName, Ports: map[string]int{"web": port, "s3": port + 50}}
+	if _, err := WriteStack(deployDir, clearflaskComposeFile, spec, config); err != nil {
+		return nil, err
 	}
-	result.ComposeFile = composePath
+	result.ComposeFile = filepath.Join(deployDir, clearflaskComposeFile)
 
 	// Write env file with instance-specific settings
 	envPath, err := writeClearFlaskInstanceEnvFile(deployDir, instanceName, port, config)
@@ -422,7 +408,7 @@ func DeployClearFlaskInstance(instanceName string, port int, config *Config) (*D
 	// Start services
 	fmt.Println()
 	fmt.Println("Starting services...")
-	if err := runClearFlaskInstanceContainerCompose(deployDir, projectName, "up", "-d"); err != nil {
+	if err := runClearFlaskInstanceContainerCompose(deployDir, projectName, "up", "--wait"); err != nil {
 		return nil, fmt.Errorf("failed to start services: %w", err)
 	}
 
@@ -436,108 +422,6 @@ func DeployClearFlaskInstance(instanceName string, port int, config *Config) (*D
 	return result, nil
 }
 
-// generateClearFlaskInstanceComposeYAML generates docker-compose.yaml for a specific ClearFlask instance
-func generateClearFlaskInstanceComposeYAML(instanceName string, port int) string {
-	localstackPort := port + 50 // LocalStack port
-
-	return fmt.Sprintf(`services:
-  mariadb:
-    image: mariadb:10.5
-    container_name: clearflask-%s-db
-    environment:
-      MYSQL_ROOT_PASSWORD: ${CLEARFLASK_DB_ROOT_PASSWORD}
-      MYSQL_DATABASE: clearflask
-      MYSQL_USER: clearflask
-      MYSQL_PASSWORD: ${CLEARFLASK_DB_PASSWORD}
-    volumes:
-      - clearflask-%s-db:/var/lib/mysql
-    healthcheck:
-      test: ["CMD", "mysqladmin", "ping", "-h", "localhost"]
-      interval: 10s
-      timeout: 5s
-      retries: 10
-    restart: unless-stopped
-
-  elasticsearch:
-    image: docker.elastic.co/elasticsearch/elasticsearch:7.10.0
-    container_name: clearflask-%s-es
-    environment:
-      - discovery.type=single-node
-      - ES_JAVA_OPTS=-Xms512m -Xmx512m
-      - xpack.security.enabled=false
-    volumes:
-      - clearflask-%s-es:/usr/share/elasticsearch/data
-    healthcheck:
-      test: ["CMD-SHELL", "curl -s http://localhost:9200/_cluster/health | grep -vq '\"status\":\"red\"'"]
-      interval: 30s
-      timeout: 10s
-      retries: 10
-    restart: unless-stopped
-
-  localstack:
-    image: localstack/localstack:0.14.3
-    container_name: clearflask-%s-s3
-    ports:
-      - "%d:4566"
-    environment:
-      SERVICES: s3
-      DEFAULT_REGION: us-east-1
-      DATA_DIR: /tmp/localstack/data
-    volumes:
-      - clearflask-%s-s3:/tmp/localstack
-    healthcheck:
-      test: ["CMD-SHELL", "curl -s http://localhost:4566/_localstack/health | grep -q 's3'"]
-      interval: 30s
-      timeout: 10s
-      retries: 5
-    restart: unless-stopped
-
-  clearflask-server:
-    image: ghcr.io/clearflask/clearflask-server:latest
-    container_name: clearflask-%s-server
-    environment:
-      CLEARFLASK_ENVIRONMENT: PRODUCTION_SELF_HOST
-      CLEARFLASK_DB_HOST: mariadb
-      CLEARFLASK_DB_PORT: "3306"
-      CLEARFLASK_DB_USER: clearflask
-      CLEARFLASK_DB_PASS: ${CLEARFLASK_DB_PASSWORD}
-      CLEARFLASK_DB_DATABASE: clearflask
-      CLEARFLASK_ES_HOST: elasticsearch
-      CLEARFLASK_ES_PORT: "9200"
-      CLEARFLASK_S3_ENDPOINT: http://localstack:4566
-      CLEARFLASK_S3_REGION: us-east-1
-      AWS_ACCESS_KEY_ID: test
-      AWS_SECRET_ACCESS_KEY: test
-    depends_on:
-      mariadb:
-        condition: service_healthy
-      elasticsearch:
-        condition: service_healthy
-      localstack:
-        condition: service_healthy
-    restart: unless-stopped
-
-  clearflask-connect:
-    image: ghcr.io/clearflask/clearflask-connect:latest
-    container_name: clearflask-%s-connect
-    ports:
-      - "%d:9080"
-    environment:
-      CLEARFLASK_SERVER_HOST: clearflask-server
-    depends_on:
-      clearflask-server:
-        condition: service_started
-    restart: unless-stopped
-
-volumes:
-  clearflask-%s-db:
-  clearflask-%s-es:
-  clearflask-%s-s3:
-`, instanceName, instanceName, instanceName, instanceName, instanceName,
-		localstackPort, instanceName, instanceName, instanceName, port,
-		instanceName, instanceName, instanceName)
-}
-
 // writeClearFlaskInstanceEnvFile writes environment file for a specific ClearFlask instance
 func writeClearFlaskInstanceEnvFile(deployDir, instanceName string, port int, config *Config) (string, error) {
 	envPath := filepath.Join(deployDir, clearflaskEnvFile)
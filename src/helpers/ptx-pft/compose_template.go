@@ -0,0 +1,245 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"text/template"
+
+	"portunix.ai/portunix/src/helpers/ptx-pft/assets/templates"
+)
+
+// stackStateFile records the ports of a rendered stack next to its compose
+// file, so a stack keeps its ports when deployed again
+const stackStateFile = "stack.json"
+
+// maxPortShift limits how far an allocated port moves from the preferred one
+const maxPortShift = 100
+
+// StackSpec describes a compose stack rendered from a template
+type StackSpec struct {
+	Template      string         // Template name, e.g. fider
+	Instance      string         // Instance name; empty for the default deployment
+	Ports         map[string]int // Fixed host ports by name
+	AllocatePorts bool           // Move ports in use to the next free one
+	Vars          map[string]string
+}
+
+// Stack is a rendered compose stack
+type Stack struct {
+	YAML    []byte
+	Ports   map[string]int    // Host ports by name
+	Volumes []string          // Named volumes
+	Images  map[string]string // Default image by service, before pinning
+}
+
+// stackState is the content of the stack state file
+type stackState struct {
+	Template string         `json:"template"`
+	Instance string         `json:"instance,omitempty"`
+	Ports    map[string]int `json:"ports"`
+}
+
+// loadComposeTemplate loads a compose template. Templates in
+// ~/.portunix/pft/templates/compose or assets/templates/compose next to the
+// binary override the built-in ones and can define new stacks.
+func loadComposeTemplate(name string) (string, error) {
+	var locations []string
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		locations = append(locations, filepath.Join(homeDir, ".portunix", "pft", "templates", "compose", name+".yaml"))
+	}
+	execPath, _ := os.Executable()
+	locations = append(locations, filepath.Join(filepath.Dir(execPath), "assets", "templates", "compose", name+".yaml"))
+
+	for _, loc := range locations {
+		if data, err := os.ReadFile(loc); err == nil {
+			return string(data), nil
+		}
+	}
+	if data, err := templates.ComposeTemplates.ReadFile("compose/" + name + ".yaml"); err == nil {
+		return string(data), nil
+	}
+	return "", fmt.Errorf("compose template not found: %s (searched: %v and built-in templates)", name, locations)
+}
+
+// portAvailable checks if a host port can be published
+func portAvailable(port int) bool {
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// RenderStack renders the compose template of a stack. Templates use:
+//
+//	{{name "db"}}             container name, e.g. fider-db or fider-voc-db
+//	{{volume "db"}}           named volume, declared automatically
+//	{{port "web" 3000}}       host port: fixed, recorded, or allocated
+//	{{image "fider" "img"}}   image of a service, unless pinned in config
+//	{{.Instance}} {{.Vars.X}} instance name and extra variables
+//
+// recorded holds the ports of an earlier render, which are kept.
+func RenderStack(spec StackSpec, config *Config, recorded map[string]int) (*Stack, error) {
+	content, err := loadComposeTemplate(spec.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	stack := &Stack{Ports: make(map[string]int), Images: make(map[string]string)}
+	taken := make(map[int]bool)
+	prefix := spec.Template
+	if spec.Instance != "" {
+		prefix += "-" + spec.Instance
+	}
+
+	allocate := func(name string, preferred int) (int, error) {
+		if port, ok := stack.Ports[name]; ok {
+			return port, nil
+		}
+		port, fixed := spec.Ports[name]
+		if !fixed {
+			port, fixed = recorded[name]
+		}
+		if !fixed {
+			port = preferred
+			for spec.AllocatePorts && (taken[port] || !portAvailable(port)) {
+				if port++; port > preferred+maxPortShift {
+					return 0, fmt.Errorf("no free port for %s near %d", name, preferred)
+				}
+			}
+			if port != preferred {
+				fmt.Printf("  Port %d is in use, %s uses port %d\n", preferred, name, port)
+			}
+		}
+		stack.Ports[name] = port
+		taken[port] = true
+		return port, nil
+	}
+
+	funcs := template.FuncMap{
+		"name": func(name string) string {
+			return prefix + "-" + name
+		},
+		"volume": func(name string) string {
+			volume := prefix + "-" + name
+			for _, v := range stack.Volumes {
+				if v == volume {
+					return volume
+				}
+			}
+			stack.Volumes = append(stack.Volumes, volume)
+			return volume
+		},
+		"port": allocate,
+		"image": func(service, defaultImage string) string {
+			stack.Images[service] = defaultImage
+			if config == nil {
+				return defaultImage
+			}
+			return config.GetImage(spec.Template, service, defaultImage)
+		},
+	}
+
+	tmpl, err := template.New(spec.Template).Funcs(funcs).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compose template %s: %w", spec.Template, err)
+	}
+	data := map[string]interface{}{"Instance": spec.Instance, "Vars": spec.Vars}
+	if spec.Vars == nil {
+		data["Vars"] = map[string]string{}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render compose template %s: %w", spec.Template, err)
+	}
+	if len(stack.Volumes) > 0 {
+		buf.WriteString("\nvolumes:\n")
+		for _, volume := range stack.Volumes {
+			fmt.Fprintf(&buf, "  %s:\n", volume)
+		}
+	}
+	stack.YAML = buf.Bytes()
+	return stack, nil
+}
+
+// readStackState returns the recorded ports of the stack in deployDir
+func readStackState(deployDir string) *stackState {
+	data, err := os.ReadFile(filepath.Join(deployDir, stackStateFile))
+	if err != nil {
+		return nil
+	}
+	var state stackState
+	if json.Unmarshal(data, &state) != nil {
+		return nil
+	}
+	return &state
+}
+
+// WriteStack renders a stack into deployDir/composeFile and records its
+// ports. A stack deployed before its ports were recorded keeps the
+// preferred ports, which its own containers hold.
+func WriteStack(deployDir, composeFile string, spec StackSpec, config *Config) (*Stack, error) {
+	var recorded map[string]int
+	if state := readStackState(deployDir); state != nil && state.Template == spec.Template && state.Instance == spec.Instance {
+		recorded = state.Ports
+	} else if _, err := os.Stat(filepath.Join(deployDir, composeFile)); err == nil {
+		spec.AllocatePorts = false
+	}
+
+	stack, err := RenderStack(spec, config, recorded)
+	if err != nil {
+		return nil, err
+	}
+
+	composePath := filepath.Join(deployDir, composeFile)
+	if err := os.WriteFile(composePath, stack.YAML, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write compose file: %w", err)
+	}
+	state, _ := json.MarshalIndent(stackState{Template: spec.Template, Instance: spec.Instance, Ports: stack.Ports}, "", "  ")
+	if err := os.WriteFile(filepath.Join(deployDir, stackStateFile), state, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write stack state: %w", err)
+	}
+	return stack, nil
+}
+
+// stackPort returns a recorded port of the stack in deployDir
+func stackPort(deployDir, name string, defaultPort int) int {
+	if state := readStackState(deployDir); state != nil {
+		if port, ok := state.Ports[name]; ok {
+			return port
+		}
+	}
+	return defaultPort
+}
+
+// stackServices returns the services of a stack template and their
+// default images
+func stackServices(name string) (map[string]string, error) {
+	stack, err := RenderStack(StackSpec{Template: name}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return stack.Images, nil
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
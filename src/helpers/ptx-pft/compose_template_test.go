@@ -0,0 +1,103 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenderStackTemplates(t *testing.T) {
+	for _, name := range []string{"fider", "clearflask", "eververse", "email"} {
+		for _, instance := range []string{"", "vos"} {
+			stack, err := RenderStack(StackSpec{Template: name, Instance: instance}, nil, nil)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", name, instance, err)
+			}
+			var compose struct {
+				Services map[string]struct {
+					Image         string `yaml:"image"`
+					ContainerName string `yaml:"container_name"`
+				} `yaml:"services"`
+				Volumes map[string]interface{} `yaml:"volumes"`
+			}
+			if err := yaml.Unmarshal(stack.YAML, &compose); err != nil {
+				t.Fatalf("%s/%s: invalid YAML: %v\n%s", name, instance, err, stack.YAML)
+			}
+			if len(compose.Services) == 0 || len(compose.Services) != len(stack.Images) {
+				t.Errorf("%s/%s: services %d, images %v", name, instance, len(compose.Services), stack.Images)
+			}
+			for _, volume := range stack.Volumes {
+				if _, ok := compose.Volumes[volume]; !ok {
+					t.Errorf("%s/%s: volume %s not declared", name, instance, volume)
+				}
+			}
+		}
+	}
+
+	stack, _ := RenderStack(StackSpec{Template: "fider", Instance: "voc", Ports: map[string]int{"web": 3100, "mail": 3200}}, nil, nil)
+	for _, want := range []string{"container_name: fider-voc-db", `"3100:3000"`, `"3200:8025"`, "fider-voc-db:/var/lib/postgresql/data"} {
+		if !strings.Contains(string(stack.YAML), want) {
+			t.Errorf("instance stack without %s:\n%s", want, stack.YAML)
+		}
+	}
+	if strings.Contains(string(stack.YAML), ":1025\"") {
+		t.Error("instances must not publish the SMTP port")
+	}
+}
+
+func TestStackPortAllocation(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	busy := listener.Addr().(*net.TCPAddr).Port
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	templateDir := filepath.Join(home, ".portunix", "pft", "templates", "compose")
+	os.MkdirAll(templateDir, 0755)
+	docs := "services:\n  docs:\n    image: {{image \"docs\" \"nginx:alpine\"}}\n    container_name: {{name \"web\"}}\n" +
+		"    ports:\n      - \"{{port \"web\" " + strconv.Itoa(busy) + "}}:80\"\n    volumes:\n      - {{volume \"site\"}}:/usr/share/nginx/html\n"
+	if err := os.WriteFile(filepath.Join(templateDir, "docs.yaml"), []byte(docs), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deployDir := t.TempDir()
+	config := &Config{}
+	config.PinImage("docs", "docs", "nginx:1.27-alpine")
+	stack, err := WriteStack(deployDir, "docker-compose.yaml", StackSpec{Template: "docs", AllocatePorts: true}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := stack.Ports["web"]
+	if port == busy || port > busy+maxPortShift {
+		t.Errorf("busy port %d not moved: %d", busy, port)
+	}
+	if !strings.Contains(string(stack.YAML), "image: nginx:1.27-alpine") || !strings.Contains(string(stack.YAML), "docs-site:") {
+		t.Errorf("unexpected stack:\n%s", stack.YAML)
+	}
+	if stackPort(deployDir, "web", 0) != port {
+		t.Error("allocated port not recorded")
+	}
+
+	// A second deploy keeps its port, even though its containers hold it
+	listener2, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err == nil {
+		defer listener2.Close()
+	}
+	again, err := WriteStack(deployDir, "docker-compose.yaml", StackSpec{Template: "docs", AllocatePorts: true}, config)
+	if err != nil || again.Ports["web"] != port {
+		t.Errorf("port changed on redeploy: %v %v", again, err)
+	}
+}
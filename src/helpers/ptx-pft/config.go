@@ -9,6 +9,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+
+	portunixconfig "portunix.ai/app/config"
+	"portunix.ai/app/fileutil"
 )
 
 const (
@@ -46,28 +50,219 @@ type SMTPConfig struct {
 	From     string `json:"from"`
 }
 
+// IMAPConfig holds the mailbox polled by 'pft intake email'
+type IMAPConfig struct {
+	Host      string `json:"host"`
+	Port      int    `json:"port,omitempty"` // Default 993 (143 with plaintext)
+	Username  string `json:"username,omitempty"`
+	Password  string `json:"password,omitempty"`   // Usually a secret reference
+	Mailbox   string `json:"mailbox,omitempty"`    // Default INBOX
+	Area      string `json:"area,omitempty"`       // Area of created items (default voc)
+	Plaintext bool   `json:"plaintext,omitempty"`  // Connect without TLS (local test servers)
+	AutoReply bool   `json:"auto_reply,omitempty"` // Reply with the item ID (needs SMTP)
+}
+
+// ShareConfig holds the key signing 'pft share' links and exports, and
+// the public URL of 'pft share serve'
+type ShareConfig struct {
+	Key string `json:"key,omitempty"` // Usually a secret reference
+	URL string `json:"url,omitempty"` // e.g. https://feedback.example.com
+}
+
+// ChatConfig holds the Slack and Microsoft Teams channels of
+// 'pft notify --channel' and 'pft intake chat'
+type ChatConfig struct {
+	Slack *SlackConfig `json:"slack,omitempty"`
+	Teams *TeamsConfig `json:"teams,omitempty"`
+	Area  string       `json:"area,omitempty"` // Area of items created from chat (default voc)
+}
+
+// SlackConfig holds the Slack app credentials. Secrets are usually secret
+// references.
+type SlackConfig struct {
+	WebhookURL     string `json:"webhook_url,omitempty"`    // Incoming webhook of the default channel
+	BotToken       string `json:"bot_token,omitempty"`      // Posts to any channel via chat.postMessage
+	SigningSecret  string `json:"signing_secret,omitempty"` // Verifies slash command requests
+	DefaultChannel string `json:"default_channel,omitempty"`
+}
+
+// TeamsConfig holds the Microsoft Teams webhooks
+type TeamsConfig struct {
+	WebhookURL     string            `json:"webhook_url,omitempty"`     // Incoming webhook of the default channel
+	Webhooks       map[string]string `json:"webhooks,omitempty"`        // Incoming webhooks by channel name
+	OutgoingSecret string            `json:"outgoing_secret,omitempty"` // Security token of the outgoing webhook
+}
+
+// AIConfig selects the embedding and language models of 'pft analyze'
+type AIConfig struct {
+	Provider  string `json:"provider,omitempty"`   // local, ollama, openai
+	URL       string `json:"url,omitempty"`        // API endpoint (default per provider)
+	Model     string `json:"model,omitempty"`      // Embedding model
+	ChatModel string `json:"chat_model,omitempty"` // Model writing cluster summaries
+	APIKey    string `json:"api_key,omitempty"`    // API key (openai), usually a secret reference
+}
+
 // AreaConfig holds configuration for a single area (voc, vos, vob, voe)
 type AreaConfig struct {
-	Provider  string `json:"provider,omitempty"`   // fider, clearflask, eververse, local
-	URL       string `json:"url,omitempty"`        // Provider endpoint URL
-	APIToken  string `json:"api_token,omitempty"`  // API token for authentication
-	ProjectID string `json:"project_id,omitempty"` // For ClearFlask multi-project
-	ProductID string `json:"product_id,omitempty"` // For Eververse multi-product
+	Provider   string     `json:"provider,omitempty"`    // fider, clearflask, eververse, discourse, local
+	URL        string     `json:"url,omitempty"`         // Provider endpoint URL
+	APIToken   string     `json:"api_token,omitempty"`   // API token for authentication
+	ProjectID  string     `json:"project_id,omitempty"`  // For ClearFlask multi-project
+	ProductID  string     `json:"product_id,omitempty"`  // For Eververse multi-product
+	Category   string     `json:"category,omitempty"`    // Discourse category (slug or ID) of the area
+	Username   string     `json:"username,omitempty"`    // Discourse user the API key acts as (default system)
+	StatusSync string     `json:"status_sync,omitempty"` // Discourse status push: tags (default), reply, both
+	SLA        *SLAPolicy `json:"sla,omitempty"`         // Response time targets
+	IDScheme   *IDScheme  `json:"id_scheme,omitempty"`   // Format of new item IDs
+}
+
+// IDScheme sets the format of new item IDs of an area, e.g. UC001
+type IDScheme struct {
+	Prefix  string `json:"prefix"`            // Letters before the number (default P)
+	Padding int    `json:"padding,omitempty"` // Minimum digits, zero padded (default 2)
+}
+
+// SLAPolicy sets response time targets for the items of an area
+type SLAPolicy struct {
+	TriageDays   int  `json:"triage_days,omitempty"`   // Items must leave the open status within
+	ResolveDays  int  `json:"resolve_days,omitempty"`  // Items must be implemented or declined within
+	CalendarDays bool `json:"calendar_days,omitempty"` // Count calendar instead of business days
+	WarnDays     int  `json:"warn_days,omitempty"`     // Deadlines this close are upcoming (default 2)
 }
 
 // Config represents the .pft-config.json structure
 type Config struct {
-	Name     string      `json:"name"`
-	Path     string      `json:"path"`
-	SMTP     *SMTPConfig `json:"smtp,omitempty"` // SMTP configuration for notifications
-	VoC      *AreaConfig `json:"voc,omitempty"`  // Voice of Customer
-	VoS      *AreaConfig `json:"vos,omitempty"`  // Voice of Stakeholder
-	VoB      *AreaConfig `json:"vob,omitempty"`  // Voice of Business
-	VoE      *AreaConfig `json:"voe,omitempty"`  // Voice of Engineer
-	Sync     SyncConfig  `json:"sync"`
-	Mappings Mappings    `json:"mappings"`
+	Name     string       `json:"name"`
+	Path     string       `json:"path"`
+	Language string       `json:"language,omitempty"` // Language of generated content (en, cs, de)
+	SMTP     *SMTPConfig  `json:"smtp,omitempty"`     // SMTP configuration for notifications
+	IMAP     *IMAPConfig  `json:"imap,omitempty"`     // Mailbox of inbound feedback
+	Chat     *ChatConfig  `json:"chat,omitempty"`     // Slack and Teams channels
+	AI       *AIConfig    `json:"ai,omitempty"`       // Models of feedback analysis
+	Share    *ShareConfig `json:"share,omitempty"`    // Read-only share links
+	VoC      *AreaConfig  `json:"voc,omitempty"`      // Voice of Customer
+	VoS      *AreaConfig  `json:"vos,omitempty"`      // Voice of Stakeholder
+	VoB      *AreaConfig  `json:"vob,omitempty"`      // Voice of Business
+	VoE      *AreaConfig  `json:"voe,omitempty"`      // Voice of Engineer
+	Sync     SyncConfig   `json:"sync"`
+	Mappings Mappings     `json:"mappings"`
+	Remote   string       `json:"remote,omitempty"` // Host the provider was deployed to
+	Images   ImagePins    `json:"images,omitempty"` // Pinned container images
+
+	// shared records the settings applied from the portunix configuration,
+	// which SaveToPath keeps out of the file
+	shared map[string]sharedValue
 }
 
+// sharedSetting is a pft setting that is not project specific: the
+// language and the AI and SMTP accounts of the user. It can be kept in the
+// portunix configuration scopes ('portunix config set pft.language cs')
+// instead of every .pft-config.json. A PORTUNIX_PFT_* variable overrides
+// the file; a scope value applies where the file leaves the setting empty.
+type sharedSetting struct {
+	key string
+	get func(c *Config) string
+	set func(c *Config, value string)
+}
+
+type sharedValue struct {
+	file    string // Value in .pft-config.json
+	applied string // Value from the portunix configuration
+}
+
+var sharedSettings = []sharedSetting{
+	{"pft.language", func(c *Config) string { return c.Language }, func(c *Config, v string) { c.Language = v }},
+	{"pft.ai.provider", func(c *Config) string { return c.ai().Provider }, func(c *Config, v string) { c.ai().Provider = v }},
+	{"pft.ai.url", func(c *Config) string { return c.ai().URL }, func(c *Config, v string) { c.ai().URL = v }},
+	{"pft.ai.model", func(c *Config) string { return c.ai().Model }, func(c *Config, v string) { c.ai().Model = v }},
+	{"pft.ai.chat_model", func(c *Config) string { return c.ai().ChatModel }, func(c *Config, v string) { c.ai().ChatModel = v }},
+	{"pft.ai.api_key", func(c *Config) string { return c.ai().APIKey }, func(c *Config, v string) { c.ai().APIKey = v }},
+	{"pft.smtp.host", func(c *Config) string { return c.smtp().Host }, func(c *Config, v string) { c.smtp().Host = v }},
+	{"pft.smtp.port", func(c *Config) string {
+		if c.smtp().Port == 0 {
+			return ""
+		}
+		return strconv.Itoa(c.smtp().Port)
+	}, func(c *Config, v string) { c.smtp().Port, _ = strconv.Atoi(v) }},
+	{"pft.smtp.username", func(c *Config) string { return c.smtp().Username }, func(c *Config, v string) { c.smtp().Username = v }},
+	{"pft.smtp.password", func(c *Config) string { return c.smtp().Password }, func(c *Config, v string) { c.smtp().Password = v }},
+	{"pft.smtp.from", func(c *Config) string { return c.smtp().From }, func(c *Config, v string) { c.smtp().From = v }},
+}
+
+func (c *Config) ai() *AIConfig {
+	if c.AI == nil {
+		c.AI = &AIConfig{}
+	}
+	return c.AI
+}
+
+func (c *Config) smtp() *SMTPConfig {
+	if c.SMTP == nil {
+		c.SMTP = &SMTPConfig{}
+	}
+	return c.SMTP
+}
+
+// applySharedSettings overlays the shared settings of the portunix
+// configuration scopes and environment
+func (c *Config) applySharedSettings() {
+	hadAI, hadSMTP := c.AI != nil, c.SMTP != nil
+	for _, setting := range sharedSettings {
+		value, source, ok := portunixconfig.Lookup(setting.key)
+		if !ok || value == "" {
+			continue
+		}
+		file := setting.get(c)
+		if file != "" && source != portunixconfig.EnvVarName(setting.key) {
+			continue
+		}
+		setting.set(c, value)
+		if c.shared == nil {
+			c.shared = make(map[string]sharedValue)
+		}
+		c.shared[setting.key] = sharedValue{file: file, applied: value}
+	}
+	if !hadAI && *c.ai() == (AIConfig{}) {
+		c.AI = nil
+	}
+	if !hadSMTP && *c.smtp() == (SMTPConfig{}) {
+		c.SMTP = nil
+	}
+}
+
+// withoutSharedSettings returns the configuration as it is written to the
+// file: shared settings that were not changed get their file value back
+func (c *Config) withoutSharedSettings() *Config {
+	if len(c.shared) == 0 {
+		return c
+	}
+	out := *c
+	if c.AI != nil {
+		ai := *c.AI
+		out.AI = &ai
+	}
+	if c.SMTP != nil {
+		smtp := *c.SMTP
+		out.SMTP = &smtp
+	}
+	for _, setting := range sharedSettings {
+		if value, ok := c.shared[setting.key]; ok && setting.get(&out) == value.applied {
+			setting.set(&out, value.file)
+		}
+	}
+	if out.AI != nil && *out.AI == (AIConfig{}) {
+		out.AI = nil
+	}
+	if out.SMTP != nil && *out.SMTP == (SMTPConfig{}) {
+		out.SMTP = nil
+	}
+	return &out
+}
+
+// ImagePins maps provider and compose service to a pinned image, e.g.
+// images.fider.fider = getfider/fider:v0.25.0
+type ImagePins map[string]map[string]string
+
 // NewDefaultConfig creates a new Config with default values
 func NewDefaultConfig() *Config {
 	return &Config{
@@ -127,6 +322,7 @@ func LoadConfigFromPath(path string) (*Config, error) {
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	config.applySharedSettings()
 
 	return &config, nil
 }
@@ -139,12 +335,12 @@ func (c *Config) Save(dir string) error {
 
 // SaveToPath writes the configuration to a specific path
 func (c *Config) SaveToPath(path string) error {
-	data, err := json.MarshalIndent(c, "", "  ")
+	data, err := json.MarshalIndent(c.withoutSharedSettings(), "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := fileutil.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
@@ -187,7 +383,7 @@ func validateAreaConfig(name string, area *AreaConfig) error {
 		return nil // local/unconfigured is valid
 	}
 
-	validProviders := []string{"fider", "clearflask", "eververse", "local"}
+	validProviders := []string{"fider", "clearflask", "eververse", "discourse", "local"}
 	isValid := false
 	for _, p := range validProviders {
 		if area.Provider == p {
@@ -203,6 +399,14 @@ func validateAreaConfig(name string, area *AreaConfig) error {
 	if area.Provider == "clearflask" && area.ProjectID == "" {
 		return fmt.Errorf("project_id is required for ClearFlask provider in area %s", name)
 	}
+	if area.Provider == "discourse" && area.Category == "" {
+		return fmt.Errorf("category is required for Discourse provider in area %s", name)
+	}
+	switch area.StatusSync {
+	case "", DiscourseStatusTags, DiscourseStatusReply, DiscourseStatusBoth:
+	default:
+		return fmt.Errorf("invalid status_sync '%s' in area %s (tags, reply, both)", area.StatusSync, name)
+	}
 	if area.Provider != "local" && area.URL == "" {
 		return fmt.Errorf("url is required for provider %s in area %s", area.Provider, name)
 	}
@@ -261,6 +465,47 @@ func (c *Config) GetAreaConfig(area string) *AreaConfig {
 	}
 }
 
+// GetImage returns the image of a compose service, the pinned one if set
+func (c *Config) GetImage(provider, service, defaultImage string) string {
+	if image := c.Images[provider][service]; image != "" {
+		return image
+	}
+	return defaultImage
+}
+
+// PinImage pins the image of a compose service
+func (c *Config) PinImage(provider, service, image string) {
+	if c.Images == nil {
+		c.Images = make(ImagePins)
+	}
+	if c.Images[provider] == nil {
+		c.Images[provider] = make(map[string]string)
+	}
+	c.Images[provider][service] = image
+}
+
+// GetLanguage returns the language of generated content, English unless configured
+func (c *Config) GetLanguage() string {
+	if IsValidLanguage(c.Language) {
+		return c.Language
+	}
+	return DefaultLanguage
+}
+
+// GetIDScheme returns the ID scheme of an area, P01 unless configured
+func (c *Config) GetIDScheme(area string) IDScheme {
+	scheme := IDScheme{Prefix: "P", Padding: 2}
+	if areaCfg := c.GetAreaConfig(area); areaCfg != nil && areaCfg.IDScheme != nil {
+		if areaCfg.IDScheme.Prefix != "" {
+			scheme.Prefix = areaCfg.IDScheme.Prefix
+		}
+		if areaCfg.IDScheme.Padding > 0 {
+			scheme.Padding = areaCfg.IDScheme.Padding
+		}
+	}
+	return scheme
+}
+
 // SetAreaConfig sets the AreaConfig for a given area name
 func (c *Config) SetAreaConfig(area string, cfg *AreaConfig) {
 	switch area {
@@ -289,10 +534,19 @@ func (c *Config) GetAreaProviderConfig(area string) ProviderConfig {
 	if areaCfg.ProductID != "" {
 		options["product_id"] = areaCfg.ProductID
 	}
+	if areaCfg.Category != "" {
+		options["category"] = areaCfg.Category
+	}
+	if areaCfg.Username != "" {
+		options["username"] = areaCfg.Username
+	}
+	if areaCfg.StatusSync != "" {
+		options["status_sync"] = areaCfg.StatusSync
+	}
 
 	return ProviderConfig{
 		Endpoint: areaCfg.URL,
-		APIToken: areaCfg.APIToken,
+		APIToken: areaCfg.Token(),
 		Options:  options,
 	}
 }
@@ -332,7 +586,7 @@ func (c *Config) GetEndpoint() string {
 func (c *Config) GetAPIToken() string {
 	for _, area := range []string{"voc", "vos", "vob", "voe"} {
 		if cfg := c.GetAreaConfig(area); cfg != nil && cfg.APIToken != "" {
-			return cfg.APIToken
+			return cfg.Token()
 		}
 	}
 	return ""
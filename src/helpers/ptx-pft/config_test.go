@@ -0,0 +1,58 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	portunixconfig "portunix.ai/app/config"
+)
+
+func TestConfigSharedSettings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	for _, kv := range [][2]string{{"pft.language", "cs"}, {"pft.smtp.host", "smtp.example.com"}, {"pft.smtp.port", "587"}, {"pft.ai.provider", "ollama"}} {
+		if err := portunixconfig.Set(portunixconfig.ScopeUser, kv[0], kv[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PORTUNIX_PFT_AI_PROVIDER", "openai")
+
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	os.WriteFile(path, []byte(`{"name": "demo", "language": "de", "ai": {"provider": "local", "model": "tfidf"}}`), 0644)
+	config, err := LoadConfigFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Language != "de" {
+		t.Errorf("file language should win over the user scope, got %s", config.Language)
+	}
+	if config.SMTP == nil || config.SMTP.Host != "smtp.example.com" || config.SMTP.Port != 587 {
+		t.Errorf("SMTP server from the user scope not applied: %+v", config.SMTP)
+	}
+	if config.AI.Provider != "openai" || config.AI.Model != "tfidf" {
+		t.Errorf("environment should override the file: %+v", config.AI)
+	}
+
+	config.Name = "renamed"
+	if err := config.SaveToPath(path); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	for _, leak := range []string{"smtp", "openai"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("shared setting %q written to the project file:\n%s", leak, data)
+		}
+	}
+	for _, want := range []string{`"renamed"`, `"provider": "local"`, `"language": "de"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("%s missing in the saved file:\n%s", want, data)
+		}
+	}
+}
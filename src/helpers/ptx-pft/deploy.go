@@ -13,8 +13,6 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
-
-	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -23,93 +21,6 @@ const (
 	fiderProjectName = "portunix-fider"
 )
 
-// Package JSON structures
-type PackageDefinition struct {
-	APIVersion string          `json:"apiVersion"`
-	Kind       string          `json:"kind"`
-	Metadata   PackageMetadata `json:"metadata"`
-	Spec       PackageSpec     `json:"spec"`
-}
-
-type PackageMetadata struct {
-	Name        string `json:"name"`
-	DisplayName string `json:"displayName"`
-	Description string `json:"description"`
-}
-
-type PackageSpec struct {
-	Type      string            `json:"type"`
-	Container ContainerSpec     `json:"container"`
-	Defaults  map[string]string `json:"defaults"`
-}
-
-type ContainerSpec struct {
-	Provider    string                 `json:"provider"`
-	ProjectName string                 `json:"projectName"`
-	Services    map[string]ServiceSpec `json:"services"`
-	Volumes     map[string]interface{} `json:"volumes"`
-	Networks    map[string]NetworkSpec `json:"networks"`
-}
-
-type ServiceSpec struct {
-	Image         string                   `json:"image"`
-	PullPolicy    string                   `json:"pullPolicy"`
-	ContainerName string                   `json:"containerName"`
-	Ports         []string                 `json:"ports"`
-	Environment   map[string]string        `json:"environment"`
-	Volumes       []string                 `json:"volumes"`
-	Healthcheck   *HealthcheckSpec         `json:"healthcheck"`
-	DependsOn     map[string]DependsOnSpec `json:"dependsOn"`
-	Restart       string                   `json:"restart"`
-}
-
-type HealthcheckSpec struct {
-	Test     []string `json:"test"`
-	Interval string   `json:"interval"`
-	Timeout  string   `json:"timeout"`
-	Retries  int      `json:"retries"`
-}
-
-type DependsOnSpec struct {
-	Condition string `json:"condition"`
-}
-
-type NetworkSpec struct {
-	Name string `json:"name"`
-}
-
-// Docker Compose YAML structures
-type ComposeFile struct {
-	Version  string                    `yaml:"version,omitempty"`
-	Services map[string]ComposeService `yaml:"services"`
-	Volumes  map[string]interface{}    `yaml:"volumes,omitempty"`
-	Networks map[string]ComposeNetwork `yaml:"networks,omitempty"`
-}
-
-type ComposeService struct {
-	Image         string              `yaml:"image"`
-	PullPolicy    string              `yaml:"pull_policy,omitempty"`
-	ContainerName string              `yaml:"container_name,omitempty"`
-	Ports         []string            `yaml:"ports,omitempty"`
-	Environment   map[string]string   `yaml:"environment,omitempty"`
-	Volumes       []string            `yaml:"volumes,omitempty"`
-	Healthcheck   *ComposeHealthcheck `yaml:"healthcheck,omitempty"`
-	DependsOn     interface{}         `yaml:"depends_on,omitempty"`
-	Restart       string              `yaml:"restart,omitempty"`
-	Networks      []string            `yaml:"networks,omitempty"`
-}
-
-type ComposeHealthcheck struct {
-	Test     []string `yaml:"test"`
-	Interval string   `yaml:"interval"`
-	Timeout  string   `yaml:"timeout"`
-	Retries  int      `yaml:"retries"`
-}
-
-type ComposeNetwork struct {
-	Name string `yaml:"name,omitempty"`
-}
-
 // DeployResult contains deployment information
 type DeployResult struct {
 	Success     bool
@@ -163,93 +74,6 @@ func CheckComposePreflight() (*ComposePreflightResult, error) {
 	}, nil
 }
 
-// loadPackageDefinition loads a package definition from JSON file
-func loadPackageDefinition(name string) (*PackageDefinition, error) {
-	// Find package file - check multiple locations
-	var packagePath string
-	var data []byte
-	var err error
-
-	// Get executable directory
-	execPath, _ := os.Executable()
-	execDir := filepath.Dir(execPath)
-
-	// Possible locations for package definitions
-	locations := []string{
-		filepath.Join(execDir, "assets", "packages", name+".json"),
-		filepath.Join(execDir, "..", "assets", "packages", name+".json"),
-		filepath.Join("assets", "packages", name+".json"),
-	}
-
-	for _, loc := range locations {
-		if data, err = os.ReadFile(loc); err == nil {
-			packagePath = loc
-			break
-		}
-	}
-
-	if packagePath == "" {
-		return nil, fmt.Errorf("package definition not found: %s (searched: %v)", name, locations)
-	}
-
-	var pkg PackageDefinition
-	if err := json.Unmarshal(data, &pkg); err != nil {
-		return nil, fmt.Errorf("failed to parse package definition %s: %w", packagePath, err)
-	}
-
-	return &pkg, nil
-}
-
-// generateComposeYAML generates docker-compose.yaml from package definition
-func generateComposeYAML(pkg *PackageDefinition) ([]byte, error) {
-	compose := ComposeFile{
-		Services: make(map[string]ComposeService),
-		Volumes:  pkg.Spec.Container.Volumes,
-		Networks: make(map[string]ComposeNetwork),
-	}
-
-	// Convert services
-	for name, svc := range pkg.Spec.Container.Services {
-		composeSvc := ComposeService{
-			Image:         svc.Image,
-			PullPolicy:    svc.PullPolicy,
-			ContainerName: svc.ContainerName,
-			Ports:         svc.Ports,
-			Environment:   svc.Environment,
-			Volumes:       svc.Volumes,
-			Restart:       svc.Restart,
-		}
-
-		if svc.Healthcheck != nil {
-			composeSvc.Healthcheck = &ComposeHealthcheck{
-				Test:     svc.Healthcheck.Test,
-				Interval: svc.Healthcheck.Interval,
-				Timeout:  svc.Healthcheck.Timeout,
-				Retries:  svc.Healthcheck.Retries,
-			}
-		}
-
-		if len(svc.DependsOn) > 0 {
-			dependsOn := make(map[string]map[string]string)
-			for depName, depSpec := range svc.DependsOn {
-				dependsOn[depName] = map[string]string{
-					"condition": depSpec.Condition,
-				}
-			}
-			composeSvc.DependsOn = dependsOn
-		}
-
-		compose.Services[name] = composeSvc
-	}
-
-	// Convert networks
-	for name, net := range pkg.Spec.Container.Networks {
-		compose.Networks[name] = ComposeNetwork{Name: net.Name}
-	}
-
-	return yaml.Marshal(&compose)
-}
-
 // generateSecret generates a random secret string
 func generateSecret(length int) string {
 	bytes := make([]byte, length)
@@ -274,24 +98,13 @@ func getDeployDir() (string, error) {
 	return deployDir, nil
 }
 
-// writeComposeFile generates and writes docker-compose.yaml from package JSON
-func writeComposeFile(deployDir string) (string, error) {
-	pkg, err := loadPackageDefinition("fider")
-	if err != nil {
+// writeComposeFile renders docker-compose.yaml from the fider stack template
+func writeComposeFile(deployDir string, config *Config) (string, error) {
+	spec := StackSpec{Template: "fider", AllocatePorts: true}
+	if _, err := WriteStack(deployDir, fiderComposeFile, spec, config); err != nil {
 		return "", err
 	}
-
-	yamlData, err := generateComposeYAML(pkg)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate compose YAML: %w", err)
-	}
-
-	composePath := filepath.Join(deployDir, fiderComposeFile)
-	if err := os.WriteFile(composePath, yamlData, 0644); err != nil {
-		return "", fmt.Errorf("failed to write compose file: %w", err)
-	}
-
-	return composePath, nil
+	return filepath.Join(deployDir, fiderComposeFile), nil
 }
 
 // writeEnvFile writes environment variables for docker-compose
@@ -320,9 +133,10 @@ func writeEnvFile(deployDir string, config *Config) (string, error) {
 	}
 
 	// Determine base URL
+	port := stackPort(deployDir, "web", 3000)
 	baseURL := config.GetEndpoint()
 	if baseURL == "" {
-		baseURL = "http://localhost:3000"
+		baseURL = fmt.Sprintf("http://localhost:%d", port)
 	}
 
 	env := fmt.Sprintf(`# Fider environment configuration
@@ -331,9 +145,9 @@ func writeEnvFile(deployDir string, config *Config) (string, error) {
 FIDER_DB_PASSWORD=%s
 FIDER_JWT_SECRET=%s
 FIDER_BASE_URL=%s
-FIDER_PORT=3000
+FIDER_PORT=%d
 FIDER_EMAIL_NOREPLY=noreply@localhost
-`, dbPassword, jwtSecret, baseURL)
+`, dbPassword, jwtSecret, baseURL, port)
 
 	if err := os.WriteFile(envPath, []byte(env), 0600); err != nil {
 		return "", fmt.Errorf("failed to write env file: %w", err)
@@ -407,7 +221,7 @@ func Deploy(config *Config) (*DeployResult, error) {
 	}
 
 	// Write compose file (generated from JSON)
-	composePath, err := writeComposeFile(deployDir)
+	composePath, err := writeComposeFile(deployDir, config)
 	if err != nil {
 		return nil, err
 	}
@@ -434,19 +248,19 @@ func Deploy(config *Config) (*DeployResult, error) {
 	// Start services
 	fmt.Println()
 	fmt.Println("Starting services...")
-	if err := runContainerCompose(deployDir, "up", "-d"); err != nil {
+	if err := runContainerCompose(deployDir, "up", "--wait"); err != nil {
 		return nil, fmt.Errorf("failed to start services: %w", err)
 	}
 
 	// Determine URL
 	baseURL := config.GetEndpoint()
 	if baseURL == "" {
-		baseURL = "http://localhost:3000"
+		baseURL = fmt.Sprintf("http://localhost:%d", stackPort(deployDir, "web", 3000))
 	}
 
 	result.Success = true
 	result.URL = baseURL
-	result.Message = fmt.Sprintf("Fider deployed successfully!\n\nAccess Fider at: %s\n\nNote: First startup may take 30-60 seconds for database initialization.", baseURL)
+	result.Message = fmt.Sprintf("Fider deployed successfully!\n\nAccess Fider at: %s", baseURL)
 
 	return result, nil
 }
@@ -468,13 +282,13 @@ func DeployInstance(instanceName string, port int, config *Config) (*DeployResul
 
 	projectName := fmt.Sprintf("portunix-fider-%s", instanceName)
 
-	// Generate compose file with custom port
-	composeContent := generateInstanceComposeYAML(instanceName, port)
-	composePath := filepath.Join(deployDir, fiderComposeFile)
-	if err := os.WriteFile(composePath, []byte(composeContent), 0644); err != nil {
-		return nil, fmt.Errorf("failed to write compose file: %w", err)
+	// Generate compose file with custom port; Mailhog web UI on port+100
+	// (e.g., 3200 for VoC, 3201 for VoS)
+	spec := StackSpec{Template: "fider", Instance: instanceName, Ports: map[string]int{"web": port, "mail": port + 100}}
+	if _, err := WriteStack(deployDir, fiderComposeFile, spec, config); err != nil {
+		return nil, err
 	}
-	result.ComposeFile = composePath
+	result.ComposeFile = filepath.Join(deployDir, fiderComposeFile)
 
 	// Write env file with instance-specific settings
 	envPath, err := writeInstanceEnvFile(deployDir, instanceName, port, config)
@@ -489,7 +303,7 @@ func DeployInstance(instanceName string, port int, config *Config) (*DeployResul
 	}
 
 	// Start services
-	if err := runInstanceContainerCompose(deployDir, projectName, "up", "-d"); err != nil {
+	if err := runInstanceContainerCompose(deployDir, projectName, "up", "--wait"); err != nil {
 		return nil, fmt.Errorf("failed to start services: %w", err)
 	}
 
@@ -501,57 +315,6 @@ func DeployInstance(instanceName string, port int, config *Config) (*DeployResul
 	return result, nil
 }
 
-// generateInstanceComposeYAML generates docker-compose.yaml for a specific instance
-func generateInstanceComposeYAML(instanceName string, port int) string {
-	mailhogPort := port + 100 // Mailhog web UI on port+100 (e.g., 3200 for VoC, 3201 for VoS)
-	return fmt.Sprintf(`services:
-  db:
-    image: postgres:15-alpine
-    container_name: fider-%s-db
-    environment:
-      POSTGRES_DB: fider
-      POSTGRES_USER: fider
-      POSTGRES_PASSWORD: ${FIDER_DB_PASSWORD}
-    volumes:
-      - fider-%s-db:/var/lib/postgresql/data
-    healthcheck:
-      test: ["CMD-SHELL", "pg_isready -U fider"]
-      interval: 10s
-      timeout: 5s
-      retries: 5
-    restart: unless-stopped
-
-  mailhog:
-    image: mailhog/mailhog:latest
-    container_name: fider-%s-mail
-    ports:
-      - "%d:8025"
-    restart: unless-stopped
-
-  fider:
-    image: getfider/fider:stable
-    container_name: fider-%s-app
-    ports:
-      - "%d:3000"
-    environment:
-      BASE_URL: ${FIDER_BASE_URL}
-      DATABASE_URL: postgres://fider:${FIDER_DB_PASSWORD}@db:5432/fider?sslmode=disable
-      JWT_SECRET: ${FIDER_JWT_SECRET}
-      EMAIL_NOREPLY: noreply@fider.local
-      EMAIL_SMTP_HOST: mailhog
-      EMAIL_SMTP_PORT: 1025
-    depends_on:
-      db:
-        condition: service_healthy
-      mailhog:
-        condition: service_started
-    restart: unless-stopped
-
-volumes:
-  fider-%s-db:
-`, instanceName, instanceName, instanceName, mailhogPort, instanceName, port, instanceName)
-}
-
 // writeInstanceEnvFile writes environment file for a specific instance
 func writeInstanceEnvFile(deployDir, instanceName string, port int, config *Config) (string, error) {
 	envPath := filepath.Join(deployDir, fiderEnvFile)
@@ -712,11 +475,11 @@ func DeployEmailOnly(config *Config) (*DeployResult, error) {
 	projectName := "portunix-email"
 
 	// Generate compose file with only Mailhog
-	composeContent := generateEmailOnlyComposeYAML()
-	composePath := filepath.Join(deployDir, fiderComposeFile)
-	if err := os.WriteFile(composePath, []byte(composeContent), 0644); err != nil {
-		return nil, fmt.Errorf("failed to write compose file: %w", err)
+	stack, err := WriteStack(deployDir, fiderComposeFile, StackSpec{Template: "email", AllocatePorts: true}, config)
+	if err != nil {
+		return nil, err
 	}
+	composePath := filepath.Join(deployDir, fiderComposeFile)
 	result.ComposeFile = composePath
 
 	// Write minimal env file
@@ -740,36 +503,23 @@ func DeployEmailOnly(config *Config) (*DeployResult, error) {
 	// Start services
 	fmt.Println()
 	fmt.Println("Starting Mailhog...")
-	if err := runEmailOnlyContainerCompose(deployDir, projectName, "up", "-d"); err != nil {
+	if err := runEmailOnlyContainerCompose(deployDir, projectName, "up", "--wait"); err != nil {
 		return nil, fmt.Errorf("failed to start services: %w", err)
 	}
 
 	result.Success = true
-	result.URL = "http://localhost:3200"
+	result.URL = fmt.Sprintf("http://localhost:%d", stack.Ports["mail"])
 	result.Message = fmt.Sprintf(`Email-only mode deployed successfully!
 
-Mailhog Web UI: http://localhost:3200
-SMTP Server:    localhost:1025
+Mailhog Web UI: %s
+SMTP Server:    localhost:%d
 
 Note: In email-only mode, sync/pull/push commands are disabled.
-      Use 'pft notify' to send emails and 'pft votes' to check responses.`)
+      Use 'pft notify' to send emails and 'pft votes' to check responses.`, result.URL, stack.Ports["smtp"])
 
 	return result, nil
 }
 
-// generateEmailOnlyComposeYAML generates docker-compose.yaml with only Mailhog
-func generateEmailOnlyComposeYAML() string {
-	return `services:
-  mailhog:
-    image: mailhog/mailhog:latest
-    container_name: pft-mailhog
-    ports:
-      - "3200:8025"
-      - "1025:1025"
-    restart: unless-stopped
-`
-}
-
 // runEmailOnlyContainerCompose executes portunix container compose for email-only mode
 func runEmailOnlyContainerCompose(deployDir, projectName string, args ...string) error {
 	portunixPath, err := findPortunix()
@@ -0,0 +1,264 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"portunix.ai/app/remote"
+)
+
+// remoteDeployDir is where compose projects are copied on the remote host,
+// relative to the remote home directory
+const remoteDeployDir = ".portunix/pft"
+
+// remoteProject is a compose project staged locally for a remote host
+type remoteProject struct {
+	Provider    string
+	ProjectName string
+	ComposeFile string
+	EnvFile     string // Empty when the project has none
+	Port        int    // Web UI port
+}
+
+// getRemoteStageDir returns the local directory holding the files deployed
+// to a host, so secrets are reused when the host is deployed again
+func getRemoteStageDir(host, provider string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	name := strings.Trim(secretNameInvalid.ReplaceAllString(strings.ToLower(host), "-"), "-.")
+	stageDir := filepath.Join(homeDir, ".portunix", "pft", "remote", name, provider)
+	if err := os.MkdirAll(stageDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	return stageDir, nil
+}
+
+// newRemoteProject returns the compose project of a provider
+func newRemoteProject(provider string) (*remoteProject, error) {
+	switch provider {
+	case "fider":
+		return &remoteProject{Provider: provider, ProjectName: fiderProjectName, ComposeFile: fiderComposeFile, EnvFile: fiderEnvFile, Port: 3000}, nil
+	case "clearflask":
+		return &remoteProject{Provider: provider, ProjectName: clearflaskProjectName, ComposeFile: clearflaskComposeFile, EnvFile: clearflaskEnvFile, Port: 3100}, nil
+	case "email":
+		return &remoteProject{Provider: provider, ProjectName: "portunix-email", ComposeFile: fiderComposeFile, Port: 3200}, nil
+	case "eververse":
+		return nil, fmt.Errorf("eververse cannot be deployed remotely: its image is built on the local machine")
+	}
+	return nil, fmt.Errorf("provider '%s' deployment not yet implemented", provider)
+}
+
+// stage writes the compose and env files of the project to stageDir. Ports
+// are not allocated, as free ports of this machine say nothing about the host.
+func (p *remoteProject) stage(stageDir string, config *Config) error {
+	if _, err := WriteStack(stageDir, p.ComposeFile, StackSpec{Template: p.Provider}, config); err != nil {
+		return err
+	}
+	switch p.Provider {
+	case "fider":
+		_, err := writeEnvFile(stageDir, config)
+		return err
+	case "clearflask":
+		_, err := writeClearFlaskEnvFile(stageDir, config)
+		return err
+	}
+	return nil
+}
+
+// isLocalHost checks if a URL host name points to this machine
+func isLocalHost(host string) bool {
+	if host == "" || strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
+
+// remoteURL returns a local URL moved to host, keeping scheme and port.
+// URLs of other hosts are returned unchanged.
+func remoteURL(localURL, host string) string {
+	u, err := url.Parse(localURL)
+	if err != nil || u.Host == "" || !isLocalHost(u.Hostname()) {
+		return localURL
+	}
+	if port := u.Port(); port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	} else {
+		u.Host = host
+	}
+	return u.String()
+}
+
+// rewriteConfigForRemote points the URLs and SMTP server configured for
+// this machine to the remote host. publicURL, when set, replaces the
+// endpoints of the areas served by the deployed provider, e.g. for a
+// reverse proxy with TLS in front of the host. Returns the changes made.
+func rewriteConfigForRemote(config *Config, host, publicURL string, project *remoteProject) []string {
+	var changes []string
+	configured := false
+	for _, area := range ValidAreaNames {
+		cfg := config.GetAreaConfig(area)
+		if cfg == nil || cfg.URL == "" {
+			continue
+		}
+		configured = true
+		newURL := remoteURL(cfg.URL, host)
+		if publicURL != "" && (cfg.Provider == "" || cfg.Provider == project.Provider) {
+			newURL = publicURL
+		}
+		if newURL != cfg.URL {
+			changes = append(changes, fmt.Sprintf("%s URL: %s → %s", strings.ToUpper(area), cfg.URL, newURL))
+			cfg.URL = newURL
+		}
+	}
+
+	// Areas without a URL reach the provider at the default port
+	if !configured && project.Provider != "email" {
+		newURL := publicURL
+		if newURL == "" {
+			newURL = fmt.Sprintf("http://%s", net.JoinHostPort(host, fmt.Sprint(project.Port)))
+		}
+		cfg := config.GetAreaConfig("voc")
+		if cfg == nil {
+			cfg = &AreaConfig{Provider: project.Provider}
+			config.SetAreaConfig("voc", cfg)
+		}
+		cfg.URL = newURL
+		changes = append(changes, fmt.Sprintf("VOC URL: %s", newURL))
+	}
+
+	if config.SMTP != nil && isLocalHost(config.SMTP.Host) {
+		changes = append(changes, fmt.Sprintf("SMTP host: %s → %s", config.SMTP.Host, host))
+		config.SMTP.Host = host
+	}
+	return changes
+}
+
+// uploadRemoteProject copies the staged files to dir on the remote host;
+// the env file holding secrets is readable by the owner only
+func uploadRemoteProject(runner remote.Runner, stageDir, dir string, project *remoteProject) error {
+	files := []string{project.ComposeFile}
+	if project.EnvFile != "" {
+		files = append(files, project.EnvFile)
+	}
+
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(stageDir, name))
+		if err != nil {
+			return err
+		}
+		command := fmt.Sprintf("umask 077 && mkdir -p '%[1]s' && cat > '%[1]s/%[2]s'", dir, name)
+		if name == project.ComposeFile {
+			command += fmt.Sprintf(" && chmod 644 '%s/%s'", dir, name)
+		}
+		var stderr bytes.Buffer
+		if err := runner.Run(command, bytes.NewReader(data), io.Discard, &stderr, false); err != nil {
+			return fmt.Errorf("failed to upload %s: %v %s", name, err, strings.TrimSpace(stderr.String()))
+		}
+	}
+	return nil
+}
+
+// remoteHomeDir returns the home directory of the SSH user
+func remoteHomeDir(runner remote.Runner) (string, error) {
+	var out, stderr bytes.Buffer
+	if err := runner.Run(`printf '%s' "$HOME"`, nil, &out, &stderr, false); err != nil {
+		return "", fmt.Errorf("failed to connect: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	home := strings.TrimSpace(out.String())
+	if home == "" || strings.ContainsAny(home, "'\n") {
+		return "", fmt.Errorf("remote home directory not usable: %q", home)
+	}
+	return home, nil
+}
+
+// DeployRemote deploys the configured provider to a host over SSH. The
+// compose files are generated locally, copied to ~/.portunix/pft/<provider>
+// on the host and started with 'portunix container compose' there, using
+// the same remote execution as 'portunix --host'. The configured URLs are
+// rewritten to the host and saved to configPath.
+func DeployRemote(config *Config, configPath, host, publicURL string) (*DeployResult, error) {
+	executor, err := remote.NewExecutor(host)
+	if err != nil {
+		return nil, err
+	}
+	executor.Helper = "ptx-container"
+	provider := config.GetProvider()
+	project, err := newRemoteProject(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	// The base URL ends up in the env file, so rewrite before staging
+	changes := rewriteConfigForRemote(config, executor.Target.Host, publicURL, project)
+	stageDir, err := getRemoteStageDir(host, provider)
+	if err != nil {
+		return nil, err
+	}
+	if err := project.stage(stageDir, config); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Deploying %s to %s...\n", provider, executor.Target.Name)
+	home, err := remoteHomeDir(executor.Runner)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", executor.Target.Name, err)
+	}
+	dir := home + "/" + remoteDeployDir + "/" + provider
+	if err := uploadRemoteProject(executor.Runner, stageDir, dir, project); err != nil {
+		return nil, err
+	}
+	fmt.Printf("  Compose files: %s:%s\n", executor.Target.Name, dir)
+	fmt.Println()
+
+	compose := []string{"container", "compose", "-f", dir + "/" + project.ComposeFile}
+	if project.EnvFile != "" {
+		compose = append(compose, "--env-file", dir+"/"+project.EnvFile)
+	}
+	compose = append(compose, "-p", project.ProjectName)
+
+	fmt.Println("Pulling container images...")
+	if _, err := executor.Execute(append(compose, "pull")); err != nil {
+		return nil, fmt.Errorf("failed to pull images on %s: %w", executor.Target.Name, err)
+	}
+	fmt.Println()
+	fmt.Println("Starting services...")
+	if _, err := executor.Execute(append(compose, "up", "-d")); err != nil {
+		return nil, fmt.Errorf("failed to start services on %s: %w", executor.Target.Name, err)
+	}
+
+	config.Remote = host
+	if err := config.SaveToPath(configPath); err != nil {
+		return nil, fmt.Errorf("deployed, but failed to save configuration: %w", err)
+	}
+
+	baseURL := config.GetEndpoint()
+	if provider == "email" {
+		baseURL = fmt.Sprintf("http://%s", net.JoinHostPort(executor.Target.Host, fmt.Sprint(project.Port)))
+	}
+	message := fmt.Sprintf("%s deployed to %s!\n\nAccess it at: %s", provider, executor.Target.Name, baseURL)
+	if len(changes) > 0 {
+		message += "\n\nConfiguration updated:\n  " + strings.Join(changes, "\n  ")
+	}
+
+	return &DeployResult{
+		Success:     true,
+		URL:         baseURL,
+		ComposeFile: dir + "/" + project.ComposeFile,
+		Message:     message,
+	}, nil
+}
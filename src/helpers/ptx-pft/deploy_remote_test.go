@@ -0,0 +1,119 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeRunner records the commands and uploaded files of a remote host
+type fakeRunner struct {
+	commands []string
+	uploads  map[string]string // Command to stdin
+}
+
+func (f *fakeRunner) Run(command string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+	f.commands = append(f.commands, command)
+	if stdin != nil {
+		data, _ := io.ReadAll(stdin)
+		f.uploads[command] = string(data)
+	}
+	if strings.Contains(command, "$HOME") {
+		io.WriteString(stdout, "/home/admin\n")
+	}
+	return nil
+}
+
+func TestRemoteURL(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"http://localhost:3000", "http://vps.example.com:3000"},
+		{"http://127.0.0.1:3100/api", "http://vps.example.com:3100/api"},
+		{"https://localhost", "https://vps.example.com"},
+		{"http://[::1]:3000", "http://vps.example.com:3000"},
+		{"https://feedback.example.com", "https://feedback.example.com"},
+	}
+	for _, tt := range tests {
+		if got := remoteURL(tt.in, "vps.example.com"); got != tt.want {
+			t.Errorf("remoteURL(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRewriteConfigForRemote(t *testing.T) {
+	project, _ := newRemoteProject("fider")
+	config := &Config{
+		VoC:  &AreaConfig{Provider: "fider", URL: "http://localhost:3000"},
+		VoS:  &AreaConfig{Provider: "local", URL: "https://jira.example.com"},
+		SMTP: &SMTPConfig{Host: "localhost", Port: 1025},
+	}
+	changes := rewriteConfigForRemote(config, "10.0.0.5", "", project)
+	if len(changes) != 2 || config.VoC.URL != "http://10.0.0.5:3000" || config.SMTP.Host != "10.0.0.5" {
+		t.Errorf("unexpected rewrite %v: %s %s", changes, config.VoC.URL, config.SMTP.Host)
+	}
+	if config.VoS.URL != "https://jira.example.com" {
+		t.Errorf("URL of another host rewritten: %s", config.VoS.URL)
+	}
+
+	rewriteConfigForRemote(config, "10.0.0.5", "https://feedback.example.com", project)
+	if config.VoC.URL != "https://feedback.example.com" || config.VoS.URL != "https://jira.example.com" {
+		t.Errorf("public URL not applied to the provider only: %s %s", config.VoC.URL, config.VoS.URL)
+	}
+
+	// Without configured URLs the provider is reached at its default port
+	config = &Config{VoC: &AreaConfig{Provider: "clearflask"}}
+	project, _ = newRemoteProject("clearflask")
+	rewriteConfigForRemote(config, "vps", "", project)
+	if config.VoC.URL != "http://vps:3100" {
+		t.Errorf("unexpected default URL %s", config.VoC.URL)
+	}
+
+	if _, err := newRemoteProject("eververse"); err == nil {
+		t.Error("eververse must not be deployable remotely")
+	}
+}
+
+func TestUploadRemoteProject(t *testing.T) {
+	project, _ := newRemoteProject("fider")
+	config := &Config{VoC: &AreaConfig{Provider: "fider", URL: "http://localhost:3000"}}
+	rewriteConfigForRemote(config, "vps.example.com", "", project)
+
+	// The compose file is generated from the package definitions in assets
+	stageDir := t.TempDir()
+	if _, err := writeEnvFile(stageDir, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(stageDir, fiderComposeFile), []byte("services: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	env, err := os.ReadFile(filepath.Join(stageDir, fiderEnvFile))
+	if err != nil || !strings.Contains(string(env), "FIDER_BASE_URL=http://vps.example.com:3000") {
+		t.Fatalf("env file without remote URL: %s %v", env, err)
+	}
+
+	runner := &fakeRunner{uploads: make(map[string]string)}
+	home, err := remoteHomeDir(runner)
+	if err != nil || home != "/home/admin" {
+		t.Fatalf("unexpected home %q %v", home, err)
+	}
+	dir := home + "/" + remoteDeployDir + "/fider"
+	if err := uploadRemoteProject(runner, stageDir, dir, project); err != nil {
+		t.Fatal(err)
+	}
+	if len(runner.uploads) != 2 {
+		t.Fatalf("unexpected uploads %v", runner.commands)
+	}
+	for command, data := range runner.uploads {
+		if !strings.Contains(command, "'/home/admin/.portunix/pft/fider'") || !strings.HasPrefix(command, "umask 077") {
+			t.Errorf("unexpected upload command %s", command)
+		}
+		if strings.HasSuffix(command, "/.env'") && data != string(env) {
+			t.Error("env file uploaded with other content")
+		}
+	}
+}
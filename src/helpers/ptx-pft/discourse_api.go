@@ -0,0 +1,296 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DiscourseClient is a client for the Discourse API
+type DiscourseClient struct {
+	BaseURL    string
+	APIKey     string
+	Username   string // User the API key acts as
+	HTTPClient *http.Client
+}
+
+// DiscourseCategory represents a category in Discourse
+type DiscourseCategory struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+	TopicID int    `json:"topic_id"` // "About the category" topic
+}
+
+// DiscourseTopic represents a topic in Discourse
+type DiscourseTopic struct {
+	ID         int           `json:"id"`
+	Title      string        `json:"title"`
+	Slug       string        `json:"slug"`
+	CategoryID int           `json:"category_id"`
+	LikeCount  int           `json:"like_count"`
+	PostsCount int           `json:"posts_count"`
+	CreatedAt  time.Time     `json:"created_at"`
+	Closed     bool          `json:"closed"`
+	Tags       DiscourseTags `json:"tags"`
+	PostStream struct {
+		Posts []DiscoursePost `json:"posts"`
+	} `json:"post_stream"`
+	Details struct {
+		CreatedBy struct {
+			Username string `json:"username"`
+		} `json:"created_by"`
+	} `json:"details"`
+}
+
+// DiscoursePost represents a post in Discourse
+type DiscoursePost struct {
+	ID         int    `json:"id"`
+	TopicID    int    `json:"topic_id"`
+	PostNumber int    `json:"post_number"`
+	Username   string `json:"username"`
+	Raw        string `json:"raw"`
+}
+
+// DiscourseTags holds tag names. Discourse lists tags as names, newer
+// versions as objects with a name.
+type DiscourseTags []string
+
+// UnmarshalJSON accepts both tag formats
+func (t *DiscourseTags) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	tags := make(DiscourseTags, 0, len(raw))
+	for _, entry := range raw {
+		var name string
+		if json.Unmarshal(entry, &name) != nil {
+			var tag struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(entry, &tag); err != nil {
+				return err
+			}
+			name = tag.Name
+		}
+		tags = append(tags, name)
+	}
+	*t = tags
+	return nil
+}
+
+// DiscourseError represents an error response from Discourse
+type DiscourseError struct {
+	Errors []string `json:"errors"`
+}
+
+// NewDiscourseClient creates a new Discourse API client
+func NewDiscourseClient(baseURL, apiKey, username string) *DiscourseClient {
+	return &DiscourseClient{
+		BaseURL:  strings.TrimSuffix(baseURL, "/"),
+		APIKey:   apiKey,
+		Username: username,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// doRequest performs an HTTP request with authentication
+func (c *DiscourseClient) doRequest(method, path string, body interface{}) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequest(method, c.BaseURL+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Api-Key", c.APIKey)
+	req.Header.Set("Api-Username", c.Username)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var discourseErr DiscourseError
+		if json.Unmarshal(respBody, &discourseErr) == nil && len(discourseErr.Errors) > 0 {
+			return nil, fmt.Errorf("API error: %s", strings.Join(discourseErr.Errors, "; "))
+		}
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	return respBody, nil
+}
+
+// GetCategory returns a category by slug or ID
+func (c *DiscourseClient) GetCategory(ref string) (*DiscourseCategory, error) {
+	respBody, err := c.doRequest("GET", "/c/"+url.PathEscape(ref)+"/show.json", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Category DiscourseCategory `json:"category"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Category, nil
+}
+
+// ListTopics returns all topics of a category, following the pages of the
+// topic list
+func (c *DiscourseClient) ListTopics(categoryID int) ([]DiscourseTopic, error) {
+	var topics []DiscourseTopic
+	for page := 0; ; page++ {
+		respBody, err := c.doRequest("GET", fmt.Sprintf("/c/%d.json?page=%d", categoryID, page), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp struct {
+			TopicList struct {
+				Topics        []DiscourseTopic `json:"topics"`
+				MoreTopicsURL string           `json:"more_topics_url"`
+			} `json:"topic_list"`
+		}
+		if err := json.Unmarshal(respBody, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		topics = append(topics, resp.TopicList.Topics...)
+		if resp.TopicList.MoreTopicsURL == "" || len(resp.TopicList.Topics) == 0 {
+			return topics, nil
+		}
+	}
+}
+
+// GetTopic returns a topic with the raw content of its first post
+func (c *DiscourseClient) GetTopic(id int) (*DiscourseTopic, error) {
+	respBody, err := c.doRequest("GET", fmt.Sprintf("/t/%d.json?include_raw=true", id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var topic DiscourseTopic
+	if err := json.Unmarshal(respBody, &topic); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &topic, nil
+}
+
+// CreateTopic creates a topic in a category
+func (c *DiscourseClient) CreateTopic(categoryID int, title, raw string, tags []string) (*DiscoursePost, error) {
+	reqBody := map[string]interface{}{
+		"title":    title,
+		"raw":      raw,
+		"category": categoryID,
+	}
+	if len(tags) > 0 {
+		reqBody["tags"] = tags
+	}
+	return c.createPost(reqBody)
+}
+
+// Reply adds a post to a topic
+func (c *DiscourseClient) Reply(topicID int, raw string) (*DiscoursePost, error) {
+	return c.createPost(map[string]interface{}{"topic_id": topicID, "raw": raw})
+}
+
+func (c *DiscourseClient) createPost(reqBody map[string]interface{}) (*DiscoursePost, error) {
+	respBody, err := c.doRequest("POST", "/posts.json", reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var post DiscoursePost
+	if err := json.Unmarshal(respBody, &post); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &post, nil
+}
+
+// SetTopicTags replaces the tags of a topic
+func (c *DiscourseClient) SetTopicTags(topicID int, tags []string) error {
+	if tags == nil {
+		tags = []string{} // An empty list removes all tags
+	}
+	_, err := c.doRequest("PUT", fmt.Sprintf("/t/-/%d.json", topicID), map[string]interface{}{"tags": tags})
+	return err
+}
+
+// DeleteTopic deletes a topic
+func (c *DiscourseClient) DeleteTopic(topicID int) error {
+	_, err := c.doRequest("DELETE", fmt.Sprintf("/t/%d.json", topicID), nil)
+	return err
+}
+
+// DiscourseUser represents a user in the Discourse admin API
+type DiscourseUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// FindUserByEmail returns the user with an email address, nil if there is
+// none. Requires an admin API key.
+func (c *DiscourseClient) FindUserByEmail(email string) (*DiscourseUser, error) {
+	respBody, err := c.doRequest("GET", "/admin/users/list/all.json?show_emails=true&email="+url.QueryEscape(email), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []DiscourseUser
+	if err := json.Unmarshal(respBody, &users); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	for i := range users {
+		if strings.EqualFold(users[i].Email, email) {
+			return &users[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// AnonymizeUser anonymizes a user: Discourse keeps the posts under a
+// generated name and erases the personal data of the account
+func (c *DiscourseClient) AnonymizeUser(userID int) error {
+	_, err := c.doRequest("PUT", fmt.Sprintf("/admin/users/%d/anonymize.json", userID), nil)
+	return err
+}
+
+// TopicURL returns the web URL of a topic
+func (c *DiscourseClient) TopicURL(topic DiscourseTopic) string {
+	return fmt.Sprintf("%s/t/%s/%d", c.BaseURL, topic.Slug, topic.ID)
+}
@@ -0,0 +1,472 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// A Discourse area maps one category to the area: topics are feedback items
+// and likes their votes. Statuses are pushed back as topic tags, replies or
+// both (status_sync).
+
+// discourseStatuses are the provider statuses pushed to Discourse, each a
+// topic tag of the same name
+var discourseStatuses = []string{"planned", "started", "completed", "declined"}
+
+// discourseStatusLabels are the wording of status replies
+var discourseStatusLabels = map[string]string{
+	"open":      "under review",
+	"planned":   "planned",
+	"started":   "in progress",
+	"completed": "completed",
+	"declined":  "declined",
+}
+
+// Values of status_sync
+const (
+	DiscourseStatusTags  = "tags"
+	DiscourseStatusReply = "reply"
+	DiscourseStatusBoth  = "both"
+)
+
+// discourseStatusKey is the frontmatter key recording the status last
+// pushed to Discourse, so a status is announced once
+const discourseStatusKey = "discourse_status"
+
+// DiscourseProvider implements FeedbackProvider interface for Discourse
+type DiscourseProvider struct {
+	client     *DiscourseClient
+	config     ProviderConfig
+	category   *DiscourseCategory
+	statusSync string
+}
+
+// NewDiscourseProvider creates a new Discourse provider
+func NewDiscourseProvider() FeedbackProvider {
+	return &DiscourseProvider{}
+}
+
+// Name returns the provider name
+func (p *DiscourseProvider) Name() string {
+	return "discourse"
+}
+
+// Connect establishes connection to Discourse and resolves the category
+func (p *DiscourseProvider) Connect(config ProviderConfig) error {
+	p.config = config
+
+	category := config.Options["category"]
+	if category == "" {
+		return fmt.Errorf("category is required for Discourse provider")
+	}
+	p.statusSync = config.Options["status_sync"]
+	if p.statusSync == "" {
+		p.statusSync = DiscourseStatusTags
+	}
+	username := config.Options["username"]
+	if username == "" {
+		username = "system"
+	}
+
+	p.client = NewDiscourseClient(config.Endpoint, config.APIToken, username)
+	cat, err := p.client.GetCategory(category)
+	if err != nil {
+		p.client = nil
+		return fmt.Errorf("connection test failed: %w", err)
+	}
+	p.category = cat
+	return nil
+}
+
+// Close closes the connection
+func (p *DiscourseProvider) Close() error {
+	p.client = nil
+	p.category = nil
+	return nil
+}
+
+// DeleteUserAccount anonymizes the Discourse account with an email address.
+// Anonymizing rather than deleting keeps the topics of the category, which
+// are the feedback items of the area.
+func (p *DiscourseProvider) DeleteUserAccount(email string) (bool, error) {
+	if p.client == nil {
+		return false, fmt.Errorf("provider not connected")
+	}
+	user, err := p.client.FindUserByEmail(email)
+	if err != nil || user == nil {
+		return false, err
+	}
+	if err := p.client.AnonymizeUser(user.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// List returns the topics of the category. The descriptions are empty, Get
+// returns them.
+func (p *DiscourseProvider) List() ([]FeedbackItem, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("provider not connected")
+	}
+
+	topics, err := p.client.ListTopics(p.category.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]FeedbackItem, 0, len(topics))
+	for _, topic := range topics {
+		if topic.ID == p.category.TopicID {
+			continue // "About the category" topic
+		}
+		items = append(items, p.discourseTopicToFeedbackItem(topic))
+	}
+
+	return items, nil
+}
+
+// Get returns a specific feedback item by topic ID
+func (p *DiscourseProvider) Get(id string) (*FeedbackItem, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("provider not connected")
+	}
+
+	topicID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid topic ID: %s", id)
+	}
+
+	topic, err := p.client.GetTopic(topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := p.discourseTopicToFeedbackItem(*topic)
+	return &item, nil
+}
+
+// Create creates a topic in the category
+func (p *DiscourseProvider) Create(item FeedbackItem) (*FeedbackItem, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("provider not connected")
+	}
+
+	tags := item.Tags
+	if isDiscourseStatus(item.Status) && p.statusSync != DiscourseStatusReply {
+		tags = append(append([]string{}, tags...), item.Status)
+	}
+	post, err := p.client.CreateTopic(p.category.ID, item.Title, item.Description, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Get(strconv.Itoa(post.TopicID))
+}
+
+// Update pushes the status of an item to its topic: the status tag replaces
+// the previous one and, with status_sync reply or both, a reply announces it
+func (p *DiscourseProvider) Update(item FeedbackItem) error {
+	if p.client == nil {
+		return fmt.Errorf("provider not connected")
+	}
+
+	topicID, err := strconv.Atoi(item.ExternalID)
+	if err != nil {
+		return fmt.Errorf("invalid topic ID: %s", item.ExternalID)
+	}
+
+	if p.statusSync != DiscourseStatusReply {
+		topic, err := p.client.GetTopic(topicID)
+		if err != nil {
+			return err
+		}
+		tags := discourseTagsWithStatus(topic.Tags, item.Status)
+		if err := p.client.SetTopicTags(topicID, tags); err != nil {
+			return fmt.Errorf("failed to tag topic %d: %w", topicID, err)
+		}
+	}
+
+	if p.statusSync != DiscourseStatusTags {
+		if _, err := p.client.Reply(topicID, discourseStatusReply(item.Status)); err != nil {
+			return fmt.Errorf("failed to reply to topic %d: %w", topicID, err)
+		}
+	}
+
+	return nil
+}
+
+// Delete removes a topic
+func (p *DiscourseProvider) Delete(id string) error {
+	if p.client == nil {
+		return fmt.Errorf("provider not connected")
+	}
+
+	topicID, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid topic ID: %s", id)
+	}
+	return p.client.DeleteTopic(topicID)
+}
+
+// discourseTopicToFeedbackItem converts a topic to a FeedbackItem. The
+// status comes from the status tag, the votes are the likes.
+func (p *DiscourseProvider) discourseTopicToFeedbackItem(topic DiscourseTopic) FeedbackItem {
+	status := "open"
+	var tags []string
+	for _, tag := range topic.Tags {
+		if isDiscourseStatus(tag) {
+			status = tag
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	item := FeedbackItem{
+		ID:         strconv.Itoa(topic.ID),
+		ExternalID: strconv.Itoa(topic.ID),
+		Title:      topic.Title,
+		Status:     status,
+		Tags:       tags,
+		Votes:      topic.LikeCount,
+		CreatedAt:  topic.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		Metadata: map[string]string{
+			"slug":        topic.Slug,
+			"url":         p.client.TopicURL(topic),
+			"author_name": topic.Details.CreatedBy.Username,
+		},
+	}
+	for _, post := range topic.PostStream.Posts {
+		if post.PostNumber == 1 {
+			item.Description = post.Raw
+			if item.Metadata["author_name"] == "" {
+				item.Metadata["author_name"] = post.Username
+			}
+		}
+	}
+	return item
+}
+
+// isDiscourseStatus reports whether a tag is a status tag
+func isDiscourseStatus(tag string) bool {
+	for _, status := range discourseStatuses {
+		if tag == status {
+			return true
+		}
+	}
+	return false
+}
+
+// discourseTagsWithStatus returns the tags with the status tag replaced;
+// the open status has none
+func discourseTagsWithStatus(tags []string, status string) []string {
+	var result []string
+	for _, tag := range tags {
+		if !isDiscourseStatus(tag) {
+			result = append(result, tag)
+		}
+	}
+	if isDiscourseStatus(status) {
+		result = append(result, status)
+	}
+	return result
+}
+
+// discourseStatusReply returns the reply announcing a status
+func discourseStatusReply(status string) string {
+	label := discourseStatusLabels[status]
+	if label == "" {
+		label = status
+	}
+	return fmt.Sprintf("Status update: this request is now **%s**.", label)
+}
+
+// providerStatus maps a local item status to the provider status (open,
+// planned, started, completed, declined)
+func providerStatus(status string) string {
+	switch roadmapColumn(status) {
+	case "planned":
+		return "planned"
+	case "in-progress":
+		return "started"
+	case "done":
+		return "completed"
+	case "closed":
+		return "declined"
+	}
+	return "open"
+}
+
+// localStatus maps a provider status to the local status of the status
+// mappings
+func localStatus(mappings StatusMappings, status string) string {
+	mapped := map[string]string{
+		"open":      mappings.Open,
+		"planned":   mappings.Planned,
+		"started":   mappings.Started,
+		"completed": mappings.Completed,
+		"declined":  mappings.Declined,
+	}[status]
+	if mapped == "" {
+		return status
+	}
+	return mapped
+}
+
+// DiscourseSyncResult counts the changes of a Discourse sync
+type DiscourseSyncResult struct {
+	Pulled   int // Topics imported as new items
+	Votes    int // Items whose vote count changed
+	Statuses int // Statuses pushed to topics
+}
+
+// SyncDiscourseArea synchronizes an area with its Discourse category: new
+// topics become items, likes update the votes of linked items and changed
+// statuses of linked items are pushed back
+func SyncDiscourseArea(provider FeedbackProvider, config *Config, projectDir, area string, dryRun bool) (DiscourseSyncResult, error) {
+	var result DiscourseSyncResult
+
+	topics, err := provider.List()
+	if err != nil {
+		return result, fmt.Errorf("failed to list topics: %w", err)
+	}
+
+	areaDir := getVoiceDir(projectDir, area)
+	items, err := ScanFeedbackDirectory(areaDir, area)
+	if err != nil {
+		return result, err
+	}
+	linked := make(map[string]*FeedbackItem)
+	for _, item := range items {
+		if item.ExternalID != "" {
+			linked[item.ExternalID] = item
+		}
+	}
+
+	for _, topic := range topics {
+		item, ok := linked[topic.ExternalID]
+		if !ok {
+			id, err := pullDiscourseTopic(provider, config, projectDir, area, topic.ExternalID, dryRun)
+			if err != nil {
+				fmt.Printf("  ✗ Failed to pull topic %s: %v\n", topic.ExternalID, err)
+				continue
+			}
+			prefix := ""
+			if dryRun {
+				prefix = "[DRY-RUN] Would create "
+			}
+			fmt.Printf("  ✓ %s%s: %s (topic %s)\n", prefix, id, topic.Title, topic.ExternalID)
+			result.Pulled++
+			continue
+		}
+
+		if item.Votes != topic.Votes {
+			if !dryRun {
+				if err := UpdateFileFields(item.FilePath, map[string]string{"votes": strconv.Itoa(topic.Votes)}); err != nil {
+					fmt.Printf("  ✗ Failed to update votes of %s: %v\n", item.ID, err)
+					continue
+				}
+			}
+			result.Votes++
+		}
+
+		// An item still in the local status of the pushed one is unchanged,
+		// even when several provider statuses map to it
+		status := providerStatus(item.Status)
+		pushed := discoursePushedStatus(item.FilePath, topic.Status)
+		if status == pushed || item.Status == localStatus(config.Mappings.Status, pushed) {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("  [DRY-RUN] Would push status %s of %s to topic %s\n", status, item.ID, topic.ExternalID)
+			result.Statuses++
+			continue
+		}
+		update := topic
+		update.Status = status
+		if err := provider.Update(update); err != nil {
+			fmt.Printf("  ✗ Failed to push status of %s: %v\n", item.ID, err)
+			continue
+		}
+		if err := UpdateFileFields(item.FilePath, map[string]string{discourseStatusKey: status}); err != nil {
+			return result, err
+		}
+		fmt.Printf("  ✓ Pushed status %s of %s to topic %s\n", status, item.ID, topic.ExternalID)
+		result.Statuses++
+	}
+
+	return result, nil
+}
+
+// discoursePushedStatus returns the status last pushed for an item, the
+// status of the topic for items linked before
+func discoursePushedStatus(filePath, topicStatus string) string {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return topicStatus
+	}
+	if fm, _ := ParseFrontmatter(string(content)); fm != nil && fm.Get(discourseStatusKey) != "" {
+		return fm.Get(discourseStatusKey)
+	}
+	return topicStatus
+}
+
+// pullDiscourseTopic creates an item from a topic and returns its ID
+func pullDiscourseTopic(provider FeedbackProvider, config *Config, projectDir, area, topicID string, dryRun bool) (string, error) {
+	if dryRun {
+		return previewNextItemID(config, projectDir, area)
+	}
+
+	topic, err := provider.Get(topicID)
+	if err != nil {
+		return "", err
+	}
+	itemID, err := generateNextItemID(config, projectDir, area)
+	if err != nil {
+		return "", err
+	}
+
+	targetDir := filepath.Join(getVoiceDir(projectDir, area), "needs")
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	slug := createSlugFromTitle(topic.Title)
+	if len(slug) > 40 {
+		slug = slug[:40]
+	}
+	filePath := filepath.Join(targetDir, fmt.Sprintf("%s-%s.md", itemID, slug))
+	content := generateFeedbackMarkdown(FeedbackItemParams{
+		ID:          itemID,
+		Title:       topic.Title,
+		Area:        area,
+		Description: strings.TrimSpace(topic.Description),
+		Status:      localStatus(config.Mappings.Status, topic.Status),
+		Author:      topic.Metadata["author_name"],
+		Source:      "discourse",
+		Created:     strings.SplitN(topic.CreatedAt, "T", 2)[0],
+		Tags:        topic.Tags,
+		Language:    config.GetLanguage(),
+	})
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write item: %w", err)
+	}
+
+	fields := map[string]string{
+		"external_id":      topic.ExternalID,
+		"external_url":     topic.Metadata["url"],
+		"votes":            strconv.Itoa(topic.Votes),
+		discourseStatusKey: topic.Status,
+	}
+	return itemID, UpdateFileFields(filePath, fields)
+}
+
+// Register the Discourse provider
+func init() {
+	RegisterProvider("discourse", NewDiscourseProvider)
+}
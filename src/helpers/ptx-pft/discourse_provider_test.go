@@ -0,0 +1,184 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeDiscourse serves a category with its topics
+type fakeDiscourse struct {
+	topics  []DiscourseTopic
+	replies map[int][]string
+}
+
+func (f *fakeDiscourse) topic(id int) *DiscourseTopic {
+	for i := range f.topics {
+		if f.topics[i].ID == id {
+			return &f.topics[i]
+		}
+	}
+	return nil
+}
+
+func (f *fakeDiscourse) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Api-Key") != "key" || r.Header.Get("Api-Username") != "bot" {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors":["You are not permitted to view the requested resource."]}`))
+		return
+	}
+
+	var id int
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/c/ideas/show.json":
+		json.NewEncoder(w).Encode(map[string]interface{}{"category": DiscourseCategory{ID: 5, Slug: "ideas", TopicID: 1}})
+	case r.Method == "GET" && r.URL.Path == "/c/5.json":
+		// Tags as objects, as newer versions list them
+		var topics []map[string]interface{}
+		if r.URL.Query().Get("page") == "0" {
+			for _, t := range f.topics {
+				var tags []map[string]string
+				for _, tag := range t.Tags {
+					tags = append(tags, map[string]string{"name": tag})
+				}
+				topics = append(topics, map[string]interface{}{"id": t.ID, "title": t.Title, "slug": t.Slug, "like_count": t.LikeCount, "tags": tags})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"topic_list": map[string]interface{}{"topics": topics}})
+	case r.Method == "GET" && sscanPath(r.URL.Path, "/t/%d.json", &id):
+		t := *f.topic(id)
+		t.PostStream.Posts = []DiscoursePost{{ID: id * 10, PostNumber: 1, Username: "alice", Raw: "Please add " + strings.ToLower(t.Title) + "."}}
+		json.NewEncoder(w).Encode(t)
+	case r.Method == "PUT" && sscanPath(r.URL.Path, "/t/-/%d.json", &id):
+		var req struct {
+			Tags []string `json:"tags"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		f.topic(id).Tags = req.Tags
+		w.Write([]byte(`{"basic_topic":{}}`))
+	case r.Method == "POST" && r.URL.Path == "/posts.json":
+		var req struct {
+			TopicID int    `json:"topic_id"`
+			Raw     string `json:"raw"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		f.replies[req.TopicID] = append(f.replies[req.TopicID], req.Raw)
+		json.NewEncoder(w).Encode(DiscoursePost{ID: 99, TopicID: req.TopicID})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func sscanPath(path, format string, id *int) bool {
+	n, err := fmt.Sscanf(path, format, id)
+	return err == nil && n == 1 && fmt.Sprintf(format, *id) == path
+}
+
+func TestDiscourseTagsUnmarshal(t *testing.T) {
+	for _, data := range []string{`["ui","planned"]`, `[{"id":1,"name":"ui","slug":"ui"},{"id":2,"name":"planned","slug":"planned"}]`} {
+		var tags DiscourseTags
+		if err := json.Unmarshal([]byte(data), &tags); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(tags, ",") != "ui,planned" {
+			t.Errorf("%s: got %v", data, tags)
+		}
+	}
+}
+
+func TestDiscourseTagsWithStatus(t *testing.T) {
+	if got := discourseTagsWithStatus([]string{"ui", "planned"}, "completed"); strings.Join(got, ",") != "ui,completed" {
+		t.Errorf("got %v", got)
+	}
+	if got := discourseTagsWithStatus([]string{"started"}, "open"); len(got) != 0 {
+		t.Errorf("open status kept tags %v", got)
+	}
+}
+
+func TestSyncDiscourseArea(t *testing.T) {
+	fake := &fakeDiscourse{
+		topics: []DiscourseTopic{
+			{ID: 1, Title: "About the Ideas category", Slug: "about-the-ideas-category"},
+			{ID: 10, Title: "Dark mode", Slug: "dark-mode", LikeCount: 3, Tags: DiscourseTags{"ui"}},
+			{ID: 11, Title: "Export to CSV", Slug: "export-to-csv", LikeCount: 1, Tags: DiscourseTags{"planned"}},
+		},
+		replies: map[int][]string{},
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	projectDir := t.TempDir()
+	config := NewDefaultConfig()
+	config.VoC = &AreaConfig{Provider: "discourse", URL: server.URL, APIToken: "key", Category: "ideas", Username: "bot", StatusSync: DiscourseStatusBoth}
+
+	provider := NewDiscourseProvider()
+	if err := provider.Connect(config.GetAreaProviderConfig("voc")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SyncDiscourseArea(provider, config, projectDir, "voc", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Pulled != 2 || result.Statuses != 0 {
+		t.Fatalf("first sync: %+v", result)
+	}
+
+	items, err := ScanFeedbackDirectory(getVoiceDir(projectDir, "voc"), "voc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byTopic := map[string]*FeedbackItem{}
+	for _, item := range items {
+		byTopic[item.ExternalID] = item
+	}
+	dark, export := byTopic["10"], byTopic["11"]
+	if dark == nil || export == nil {
+		t.Fatalf("topics not pulled: %v", items)
+	}
+	if dark.Votes != 3 || dark.Status != "pending" || !strings.Contains(dark.Description, "Please add dark mode.") {
+		t.Errorf("unexpected item %+v", dark)
+	}
+	if export.Status != "in_progress" {
+		t.Errorf("planned topic pulled with status %s", export.Status)
+	}
+
+	// A like and a local status change: votes update, the status is
+	// pushed once as tag and reply
+	fake.topic(10).LikeCount = 5
+	content, _ := os.ReadFile(dark.FilePath)
+	os.WriteFile(dark.FilePath, []byte(strings.Replace(string(content), "status: pending", "status: implemented", 1)), 0644)
+
+	for run := 0; run < 2; run++ {
+		if result, err = SyncDiscourseArea(provider, config, projectDir, "voc", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if result.Votes != 0 || result.Statuses != 0 {
+		t.Errorf("second sync repeated changes: %+v", result)
+	}
+	if tags := strings.Join(fake.topic(10).Tags, ","); tags != "ui,completed" {
+		t.Errorf("topic tags %s", tags)
+	}
+	if len(fake.replies[10]) != 1 || !strings.Contains(fake.replies[10][0], "completed") {
+		t.Errorf("replies %v", fake.replies[10])
+	}
+	if len(fake.replies[11]) != 0 {
+		t.Errorf("unchanged item announced: %v", fake.replies[11])
+	}
+	if item, _ := ParseMarkdownFile(dark.FilePath); item.Votes != 5 {
+		t.Errorf("votes not updated: %d", item.Votes)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, IDRegistryFileName)); err != nil {
+		t.Errorf("item IDs not allocated: %v", err)
+	}
+}
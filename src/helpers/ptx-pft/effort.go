@@ -0,0 +1,215 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Effort units: estimates are entered as "4h", "3d", "1w 2d" and counted in
+// working hours, so a day is 8 hours and a week 5 days
+const (
+	HoursPerDay  = 8
+	HoursPerWeek = 5 * HoursPerDay
+)
+
+// ParseEffort converts an effort such as "3d", "1.5d", "1w 2d" or "6h" to
+// hours. A bare number counts hours.
+func ParseEffort(value string) (float64, error) {
+	fields := strings.Fields(strings.ToLower(value))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty effort")
+	}
+	var hours float64
+	for _, field := range fields {
+		unit := 1.0
+		switch {
+		case strings.HasSuffix(field, "h"):
+			field = strings.TrimSuffix(field, "h")
+		case strings.HasSuffix(field, "d"):
+			field, unit = strings.TrimSuffix(field, "d"), HoursPerDay
+		case strings.HasSuffix(field, "w"):
+			field, unit = strings.TrimSuffix(field, "w"), HoursPerWeek
+		}
+		n, err := strconv.ParseFloat(field, 64)
+		if err != nil || n < 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+			return 0, fmt.Errorf("invalid effort %q (use e.g. 4h, 3d, 1w 2d)", value)
+		}
+		hours += n * unit
+	}
+	return hours, nil
+}
+
+// FormatEffort writes hours in the largest whole units, e.g. 52 as "1w 1d 4h"
+func FormatEffort(hours float64) string {
+	if hours <= 0 {
+		return "0h"
+	}
+	var parts []string
+	if weeks := math.Floor(hours / HoursPerWeek); weeks > 0 {
+		parts = append(parts, fmt.Sprintf("%.0fw", weeks))
+		hours -= weeks * HoursPerWeek
+	}
+	if days := math.Floor(hours / HoursPerDay); days > 0 {
+		parts = append(parts, fmt.Sprintf("%.0fd", days))
+		hours -= days * HoursPerDay
+	}
+	if hours = math.Round(hours*100) / 100; hours > 0 {
+		parts = append(parts, strconv.FormatFloat(hours, 'f', -1, 64)+"h")
+	}
+	if len(parts) == 0 {
+		return "0h"
+	}
+	return strings.Join(parts, " ")
+}
+
+// itemEffort returns the estimated and actual hours of an item; fields that
+// are missing or malformed count as zero
+func itemEffort(item FeedbackItem) (estimate, actual float64) {
+	if item.Estimate != "" {
+		estimate, _ = ParseEffort(item.Estimate)
+	}
+	if item.ActualEffort != "" {
+		actual, _ = ParseEffort(item.ActualEffort)
+	}
+	return estimate, actual
+}
+
+// EffortGroup sums the effort of the items sharing a status or category
+type EffortGroup struct {
+	Name        string
+	Items       int
+	Unestimated int
+	Estimate    float64 // Hours
+	Actual      float64 // Hours
+}
+
+// SummarizeEffort groups items by the key function; an item with several
+// keys (categories) counts in each of its groups
+func SummarizeEffort(items []FeedbackItem, keys func(FeedbackItem) []string) []EffortGroup {
+	groups := map[string]*EffortGroup{}
+	for _, item := range items {
+		estimate, actual := itemEffort(item)
+		for _, key := range keys(item) {
+			group := groups[key]
+			if group == nil {
+				group = &EffortGroup{Name: key}
+				groups[key] = group
+			}
+			group.Items++
+			if item.Estimate == "" {
+				group.Unestimated++
+			}
+			group.Estimate += estimate
+			group.Actual += actual
+		}
+	}
+
+	result := make([]EffortGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Estimate != result[j].Estimate {
+			return result[i].Estimate > result[j].Estimate
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// effortByStatus and effortByCategory are the groupings of the effort report
+func effortByStatus(item FeedbackItem) []string {
+	if item.Status == "" {
+		return []string{"open"}
+	}
+	return []string{item.Status}
+}
+
+func effortByCategory(item FeedbackItem) []string {
+	if len(item.Categories) == 0 {
+		return []string{""}
+	}
+	return item.Categories
+}
+
+// hasEffort reports whether any item carries an estimate or actual effort
+func hasEffort(items []FeedbackItem) bool {
+	for _, item := range items {
+		if item.Estimate != "" || item.ActualEffort != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// generateEffortReport writes the estimated and actual effort per status
+// and category
+func generateEffortReport(report *strings.Builder, l *Locale, items []FeedbackItem) {
+	if !hasEffort(items) {
+		return
+	}
+	report.WriteString(fmt.Sprintf("\n## %s\n\n", l.T("effort.title")))
+	writeTable := func(heading string, groups []EffortGroup) {
+		report.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", heading, l.T("effort.items"),
+			l.T("effort.estimated"), l.T("effort.actual"), l.T("effort.unestimated")))
+		report.WriteString("|------|-------|-----------|--------|-------------|\n")
+		var total EffortGroup
+		for _, group := range groups {
+			name := group.Name
+			if name == "" {
+				name = l.T("report.uncategorized")
+			}
+			report.WriteString(fmt.Sprintf("| %s | %d | %s | %s | %d |\n",
+				name, group.Items, FormatEffort(group.Estimate), FormatEffort(group.Actual), group.Unestimated))
+			total.Items += group.Items
+			total.Unestimated += group.Unestimated
+			total.Estimate += group.Estimate
+			total.Actual += group.Actual
+		}
+		report.WriteString(fmt.Sprintf("| **%s** | %d | %s | %s | %d |\n\n",
+			l.T("report.total"), total.Items, FormatEffort(total.Estimate), FormatEffort(total.Actual), total.Unestimated))
+	}
+	writeTable(l.T("report.status"), SummarizeEffort(items, effortByStatus))
+	writeTable(l.T("report.categories"), SummarizeEffort(items, effortByCategory))
+}
+
+// sprintCSV exports the unresolved items with their effort in hours, the
+// unit sprint planning tools (Jira, Azure DevOps, Taiga) import
+func sprintCSV(items []FeedbackItem) (string, error) {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Write([]string{"ID", "Title", "Type", "Status", "Priority", "Assignee", "Categories",
+		"Estimate (h)", "Actual Effort (h)", "Remaining (h)"})
+	hours := func(h float64) string {
+		return strconv.FormatFloat(math.Round(h*100)/100, 'f', -1, 64)
+	}
+	for _, item := range items {
+		if isResolvedStatus(item.Status) {
+			continue
+		}
+		estimate, actual := itemEffort(item)
+		remaining := ""
+		if item.Estimate != "" {
+			remaining = hours(math.Max(estimate-actual, 0))
+		}
+		estimateText, actualText := "", ""
+		if item.Estimate != "" {
+			estimateText = hours(estimate)
+		}
+		if item.ActualEffort != "" {
+			actualText = hours(actual)
+		}
+		w.Write([]string{item.ID, item.Title, item.Type, item.Status, item.Priority, item.Assignee,
+			strings.Join(item.Categories, ";"), estimateText, actualText, remaining})
+	}
+	w.Flush()
+	return sb.String(), w.Error()
+}
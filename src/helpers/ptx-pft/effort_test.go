@@ -0,0 +1,106 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseEffort(t *testing.T) {
+	for value, want := range map[string]float64{
+		"4h":     4,
+		"3d":     24,
+		"1.5d":   12,
+		"1w 2d":  56,
+		"2W":     80,
+		"6":      6,
+		"1d 30m": -1,
+		"":       -1,
+		"-2d":    -1,
+	} {
+		got, err := ParseEffort(value)
+		if want < 0 {
+			if err == nil {
+				t.Errorf("ParseEffort(%q) accepted", value)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("ParseEffort(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+
+	for hours, want := range map[float64]string{0: "0h", 4: "4h", 12: "1d 4h", 52: "1w 1d 4h", 80: "2w", 2.5: "2.5h"} {
+		if got := FormatEffort(hours); got != want {
+			t.Errorf("FormatEffort(%v) = %q, want %q", hours, got, want)
+		}
+	}
+}
+
+func TestEffortFrontmatter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "UC001-export.md")
+	os.WriteFile(path, []byte("---\nid: UC001\ntitle: Export\nstatus: planned\n---\n\n# Export\n"), 0644)
+	if err := UpdateFileFields(path, map[string]string{"estimate": "3d", "actual_effort": "1d 4h"}); err != nil {
+		t.Fatal(err)
+	}
+	item, err := ParseMarkdownFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Estimate != "3d" || item.ActualEffort != "1d 4h" {
+		t.Errorf("effort not read back: %+v", item)
+	}
+}
+
+func TestEffortReport(t *testing.T) {
+	items := []FeedbackItem{
+		{ID: "UC001", Title: "Export, CSV", Status: "planned", Categories: []string{"export"}, Estimate: "3d", ActualEffort: "1d"},
+		{ID: "UC002", Title: "Dark mode", Status: "planned", Categories: []string{"ui", "export"}, Estimate: "1w"},
+		{ID: "UC003", Title: "Login", Status: "open"},
+		{ID: "UC004", Title: "Shipped", Status: "implemented", Estimate: "2d", ActualEffort: "3d"},
+	}
+
+	byStatus := SummarizeEffort(items, effortByStatus)
+	if len(byStatus) != 3 || byStatus[0].Name != "planned" || byStatus[0].Estimate != 64 || byStatus[0].Actual != 8 {
+		t.Fatalf("unexpected status groups %+v", byStatus)
+	}
+	byCategory := SummarizeEffort(items, effortByCategory)
+	if byCategory[0].Name != "export" || byCategory[0].Items != 2 || byCategory[0].Estimate != 64 {
+		t.Errorf("unexpected category groups %+v", byCategory)
+	}
+
+	var report strings.Builder
+	generateEffortReport(&report, GetLocale("en"), items)
+	for _, want := range []string{
+		"| planned | 2 | 1w 3d | 1d | 0 |",
+		"| open | 1 | 0h | 0h | 1 |",
+		"| **Total** | 4 | 2w | 4d | 1 |",
+		"| (uncategorized) | 2 | 2d | 3d | 1 |",
+	} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("report misses %q:\n%s", want, report.String())
+		}
+	}
+	report.Reset()
+	generateEffortReport(&report, GetLocale("en"), items[2:3])
+	if report.Len() != 0 {
+		t.Errorf("report without efforts: %q", report.String())
+	}
+
+	csv, err := sprintCSV(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("resolved items must be left out:\n%s", csv)
+	}
+	if lines[1] != `UC001,"Export, CSV",,planned,,,export,24,8,16` || lines[3] != "UC003,Login,,open,,,,,," {
+		t.Errorf("unexpected sprint CSV:\n%s", csv)
+	}
+}
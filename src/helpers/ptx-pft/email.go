@@ -6,12 +6,15 @@ package main
 
 import (
 	"bytes"
+	"embed"
 	"fmt"
 	"net/smtp"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
+
+	"portunix.ai/portunix/src/helpers/ptx-pft/assets/templates"
 )
 
 // NotificationType represents the type of notification
@@ -21,6 +24,8 @@ const (
 	NotifyVote        NotificationType = "vote"
 	NotifyDescription NotificationType = "description"
 	NotifyAcceptance  NotificationType = "acceptance"
+	// NotifyIntake confirms an item created from an inbound email
+	NotifyIntake NotificationType = "intake"
 )
 
 // SMTPClient handles email sending
@@ -38,7 +43,7 @@ func NewSMTPClient(config *SMTPConfig) *SMTPClient {
 		Host:     config.Host,
 		Port:     config.Port,
 		Username: config.Username,
-		Password: config.Password,
+		Password: resolveSecret(config.Password),
 		From:     config.From,
 	}
 }
@@ -53,6 +58,13 @@ type EmailData struct {
 	PostNumber  int
 	Provider    string // Provider name (email, fider, etc.)
 	ItemID      string // Local item ID (e.g., UC001)
+	Language    string // Template language (en, cs, de); English when empty
+
+	// Mail merge fields of the recipient
+	Organization string
+	Role         string     // Role name in the area of the item
+	ItemURL      string     // Deep link to the item in the feedback tool
+	OpenItems    []ItemLink // The recipient's own unresolved items
 }
 
 // SendEmail sends an email via SMTP
@@ -84,7 +96,7 @@ func (c *SMTPClient) SendEmail(to, subject, body string) error {
 // GenerateNotification generates email subject and body for the given notification type
 func GenerateNotification(notifyType NotificationType, data EmailData) (subject, body string, err error) {
 	// Load template from file
-	templateContent, err := loadTemplate(data.Provider, string(notifyType))
+	templateContent, err := loadTemplate(data.Provider, string(notifyType), data.Language)
 	if err != nil {
 		return "", "", err
 	}
@@ -98,16 +110,25 @@ func GenerateNotification(notifyType NotificationType, data EmailData) (subject,
 	return executeTemplates(subjectTmpl, bodyTmpl, data)
 }
 
-// loadTemplate loads a template file from assets/templates/<provider>/<type>.md
-func loadTemplate(provider, notifyType string) (string, error) {
+// loadTemplate loads a template file from assets/templates/<provider>/<type>.md,
+// preferring the translation in assets/templates/<provider>/<lang>/<type>.md
+func loadTemplate(provider, notifyType, lang string) (string, error) {
 	// Find template file - check multiple locations
 	execPath, _ := os.Executable()
 	execDir := filepath.Dir(execPath)
 
-	locations := []string{
-		filepath.Join(execDir, "assets", "templates", provider, notifyType+".md"),
-		filepath.Join(execDir, "..", "assets", "templates", provider, notifyType+".md"),
-		filepath.Join("assets", "templates", provider, notifyType+".md"),
+	names := []string{notifyType + ".md"}
+	if lang != "" && lang != DefaultLanguage {
+		names = []string{filepath.Join(lang, notifyType+".md"), notifyType + ".md"}
+	}
+
+	var locations []string
+	for _, name := range names {
+		locations = append(locations,
+			filepath.Join(execDir, "assets", "templates", provider, name),
+			filepath.Join(execDir, "..", "assets", "templates", provider, name),
+			filepath.Join("assets", "templates", provider, name),
+		)
 	}
 
 	var data []byte
@@ -118,6 +139,16 @@ func loadTemplate(provider, notifyType string) (string, error) {
 		}
 	}
 
+	// Fall back to the templates built into the binary
+	embedded := map[string]embed.FS{"email": templates.EmailTemplates, "chat": templates.ChatTemplates}
+	if fs, ok := embedded[provider]; ok {
+		for _, name := range names {
+			if data, err = fs.ReadFile(provider + "/" + filepath.ToSlash(name)); err == nil {
+				return string(data), nil
+			}
+		}
+	}
+
 	return "", fmt.Errorf("template not found: %s/%s.md (searched: %v)", provider, notifyType, locations)
 }
 
@@ -22,6 +22,9 @@ const (
 	eververseImageName   = "portunix/eververse:latest"
 	eververseGitRepo     = "https://github.com/haydenbleasel/eververse.git"
 
+	// The Supabase stack takes longer than the default wait to become healthy
+	eververseWaitTimeout = "10m"
+
 	// Minimum RAM requirement in bytes (6GB)
 	eververseMinRAMBytes = 6 * 1024 * 1024 * 1024
 )
@@ -294,275 +297,13 @@ func ensureEververseImage() error {
 	return nil
 }
 
-// writeEververseComposeFile generates and writes docker-compose.yaml for Eververse
-func writeEververseComposeFile(deployDir string) (string, error) {
-	// Try to load from package definition first
-	pkg, err := loadPackageDefinition("eververse")
-	if err == nil {
-		yamlData, err := generateComposeYAML(pkg)
-		if err != nil {
-			return "", fmt.Errorf("failed to generate compose YAML: %w", err)
-		}
-
-		composePath := filepath.Join(deployDir, eververseComposeFile)
-		if err := os.WriteFile(composePath, yamlData, 0644); err != nil {
-			return "", fmt.Errorf("failed to write compose file: %w", err)
-		}
-
-		return composePath, nil
-	}
-
-	// Fallback to embedded compose content if package not found
-	composeContent := generateEververseComposeYAML()
-	composePath := filepath.Join(deployDir, eververseComposeFile)
-	if err := os.WriteFile(composePath, []byte(composeContent), 0644); err != nil {
-		return "", fmt.Errorf("failed to write compose file: %w", err)
+// writeEververseComposeFile renders docker-compose.yaml from the eververse stack template
+func writeEververseComposeFile(deployDir string, config *Config) (string, error) {
+	spec := StackSpec{Template: "eververse", AllocatePorts: true}
+	if _, err := WriteStack(deployDir, eververseComposeFile, spec, config); err != nil {
+		return "", err
 	}
-
-	return composePath, nil
-}
-
-// generateEververseComposeYAML generates the full docker-compose.yaml for Eververse + Supabase
-func generateEververseComposeYAML() string {
-	return `# Eververse with Supabase Stack
-# WARNING: This stack requires ~6GB RAM minimum
-# Generated by portunix pft deploy
-
-services:
-  # ==================== DATABASE ====================
-  db:
-    image: supabase/postgres:15.1.0.147
-    container_name: eververse-db
-    restart: unless-stopped
-    ports:
-      - "5432:5432"
-    environment:
-      POSTGRES_PASSWORD: ${POSTGRES_PASSWORD}
-      POSTGRES_DB: postgres
-    volumes:
-      - eververse-db:/var/lib/postgresql/data
-    healthcheck:
-      test: ["CMD-SHELL", "pg_isready -U postgres"]
-      interval: 10s
-      timeout: 5s
-      retries: 5
-
-  # ==================== API GATEWAY ====================
-  kong:
-    image: kong:2.8.1
-    container_name: eververse-kong
-    restart: unless-stopped
-    ports:
-      - "8000:8000"
-      - "8443:8443"
-    environment:
-      KONG_DATABASE: "off"
-      KONG_DECLARATIVE_CONFIG: /var/lib/kong/kong.yml
-      KONG_DNS_ORDER: LAST,A,CNAME
-      KONG_PLUGINS: request-transformer,cors,key-auth,acl
-    volumes:
-      - ./volumes/kong/kong.yml:/var/lib/kong/kong.yml:ro
-    depends_on:
-      db:
-        condition: service_healthy
-
-  # ==================== AUTH ====================
-  auth:
-    image: supabase/gotrue:v2.99.0
-    container_name: eververse-auth
-    restart: unless-stopped
-    environment:
-      GOTRUE_API_HOST: 0.0.0.0
-      GOTRUE_API_PORT: 9999
-      API_EXTERNAL_URL: ${API_EXTERNAL_URL:-http://localhost:8000}
-      GOTRUE_DB_DRIVER: postgres
-      GOTRUE_DB_DATABASE_URL: postgres://postgres:${POSTGRES_PASSWORD}@db:5432/postgres?search_path=auth
-      GOTRUE_SITE_URL: ${SITE_URL:-http://localhost:3000}
-      GOTRUE_JWT_SECRET: ${JWT_SECRET}
-      GOTRUE_JWT_EXP: 3600
-      GOTRUE_DISABLE_SIGNUP: "false"
-    depends_on:
-      db:
-        condition: service_healthy
-
-  # ==================== REST API ====================
-  rest:
-    image: postgrest/postgrest:v11.2.0
-    container_name: eververse-rest
-    restart: unless-stopped
-    environment:
-      PGRST_DB_URI: postgres://postgres:${POSTGRES_PASSWORD}@db:5432/postgres
-      PGRST_DB_SCHEMAS: public,storage,graphql_public
-      PGRST_DB_ANON_ROLE: anon
-      PGRST_JWT_SECRET: ${JWT_SECRET}
-    depends_on:
-      db:
-        condition: service_healthy
-
-  # ==================== REALTIME ====================
-  realtime:
-    image: supabase/realtime:v2.25.35
-    container_name: eververse-realtime
-    restart: unless-stopped
-    environment:
-      PORT: 4000
-      DB_HOST: db
-      DB_PORT: 5432
-      DB_USER: postgres
-      DB_PASSWORD: ${POSTGRES_PASSWORD}
-      DB_NAME: postgres
-      DB_SSL: "false"
-      JWT_SECRET: ${JWT_SECRET}
-      REPLICATION_MODE: RLS
-      SECURE_CHANNELS: "true"
-    depends_on:
-      db:
-        condition: service_healthy
-
-  # ==================== STORAGE ====================
-  storage:
-    image: supabase/storage-api:v0.43.11
-    container_name: eververse-storage
-    restart: unless-stopped
-    environment:
-      ANON_KEY: ${ANON_KEY}
-      SERVICE_KEY: ${SERVICE_KEY}
-      POSTGREST_URL: http://rest:3000
-      PGRST_JWT_SECRET: ${JWT_SECRET}
-      DATABASE_URL: postgres://postgres:${POSTGRES_PASSWORD}@db:5432/postgres
-      STORAGE_BACKEND: file
-      FILE_STORAGE_BACKEND_PATH: /var/lib/storage
-      TENANT_ID: stub
-      REGION: stub
-      GLOBAL_S3_BUCKET: stub
-    volumes:
-      - eververse-storage:/var/lib/storage
-    depends_on:
-      db:
-        condition: service_healthy
-      rest:
-        condition: service_started
-
-  # ==================== IMAGE PROXY ====================
-  imgproxy:
-    image: darthsim/imgproxy:v3.18
-    container_name: eververse-imgproxy
-    restart: unless-stopped
-    environment:
-      IMGPROXY_BIND: ":5001"
-      IMGPROXY_LOCAL_FILESYSTEM_ROOT: /
-      IMGPROXY_USE_ETAG: "true"
-
-  # ==================== POSTGRES META ====================
-  meta:
-    image: supabase/postgres-meta:v0.68.0
-    container_name: eververse-meta
-    restart: unless-stopped
-    environment:
-      PG_META_PORT: 8080
-      PG_META_DB_HOST: db
-      PG_META_DB_PORT: 5432
-      PG_META_DB_NAME: postgres
-      PG_META_DB_USER: postgres
-      PG_META_DB_PASSWORD: ${POSTGRES_PASSWORD}
-    depends_on:
-      db:
-        condition: service_healthy
-
-  # ==================== EDGE FUNCTIONS ====================
-  functions:
-    image: supabase/edge-runtime:v1.22.4
-    container_name: eververse-functions
-    restart: unless-stopped
-    environment:
-      JWT_SECRET: ${JWT_SECRET}
-      SUPABASE_URL: http://kong:8000
-      SUPABASE_ANON_KEY: ${ANON_KEY}
-      SUPABASE_SERVICE_ROLE_KEY: ${SERVICE_KEY}
-      SUPABASE_DB_URL: postgresql://postgres:${POSTGRES_PASSWORD}@db:5432/postgres
-    volumes:
-      - ./volumes/functions:/home/deno/functions:Z
-    depends_on:
-      - kong
-
-  # ==================== ANALYTICS ====================
-  analytics:
-    image: supabase/logflare:1.4.0
-    container_name: eververse-analytics
-    restart: unless-stopped
-    environment:
-      LOGFLARE_NODE_HOST: 127.0.0.1
-      DB_USERNAME: postgres
-      DB_DATABASE: postgres
-      DB_HOSTNAME: db
-      DB_PORT: 5432
-      DB_PASSWORD: ${POSTGRES_PASSWORD}
-      DB_SCHEMA: _analytics
-      LOGFLARE_API_KEY: ${LOGFLARE_API_KEY}
-      LOGFLARE_SINGLE_TENANT: "true"
-      LOGFLARE_SUPABASE_MODE: "true"
-      POSTGRES_BACKEND_URL: postgresql://postgres:${POSTGRES_PASSWORD}@db:5432/postgres
-    depends_on:
-      db:
-        condition: service_healthy
-
-  # ==================== STUDIO (Dashboard) ====================
-  studio:
-    image: supabase/studio:20231123-64a766a
-    container_name: eververse-studio
-    restart: unless-stopped
-    ports:
-      - "3001:3000"
-    environment:
-      STUDIO_PG_META_URL: http://meta:8080
-      POSTGRES_PASSWORD: ${POSTGRES_PASSWORD}
-      DEFAULT_ORGANIZATION_NAME: Eververse
-      DEFAULT_PROJECT_NAME: Eververse Local
-      SUPABASE_URL: http://kong:8000
-      SUPABASE_PUBLIC_URL: ${API_EXTERNAL_URL:-http://localhost:8000}
-      SUPABASE_ANON_KEY: ${ANON_KEY}
-      SUPABASE_SERVICE_KEY: ${SERVICE_KEY}
-    depends_on:
-      - kong
-      - meta
-
-  # ==================== EVERVERSE APP ====================
-  eververse:
-    image: portunix/eververse:latest
-    pull_policy: never
-    container_name: eververse-app
-    restart: unless-stopped
-    ports:
-      - "3000:3000"
-    environment:
-      NODE_ENV: production
-      NEXT_PUBLIC_SUPABASE_URL: http://kong:8000
-      NEXT_PUBLIC_SUPABASE_ANON_KEY: ${ANON_KEY}
-      SUPABASE_SERVICE_ROLE_KEY: ${SERVICE_KEY}
-      DATABASE_URL: postgres://postgres:${POSTGRES_PASSWORD}@db:5432/postgres
-      NEXT_PUBLIC_DISABLE_ANALYTICS: "true"
-      STRIPE_SECRET_KEY: ${STRIPE_SECRET_KEY:-sk_test_dummy}
-      STRIPE_WEBHOOK_SECRET: ${STRIPE_WEBHOOK_SECRET:-whsec_dummy}
-    depends_on:
-      - kong
-      - auth
-      - rest
-      - storage
-    healthcheck:
-      test: ["CMD", "curl", "-f", "http://localhost:3000/api/health"]
-      interval: 30s
-      timeout: 10s
-      retries: 3
-      start_period: 60s
-
-volumes:
-  eververse-db:
-  eververse-storage:
-
-networks:
-  default:
-    name: eververse-network
-`
+	return filepath.Join(deployDir, eververseComposeFile), nil
 }
 
 // writeEververseEnvFile writes environment variables for Eververse docker-compose
@@ -607,8 +348,8 @@ func writeEververseEnvFile(deployDir string, config *Config) (string, error) {
 	}
 
 	// Determine URLs
-	siteURL := "http://localhost:3000"
-	apiURL := "http://localhost:8000"
+	siteURL := fmt.Sprintf("http://localhost:%d", stackPort(deployDir, "web", 3000))
+	apiURL := fmt.Sprintf("http://localhost:%d", stackPort(deployDir, "api", 8000))
 
 	if config.GetEndpoint() != "" {
 		siteURL = config.GetEndpoint()
@@ -799,7 +540,7 @@ func DeployEververse(config *Config) (*DeployResult, error) {
 	}
 
 	// Write compose file
-	composePath, err := writeEververseComposeFile(deployDir)
+	composePath, err := writeEververseComposeFile(deployDir, config)
 	if err != nil {
 		return nil, err
 	}
@@ -842,14 +583,14 @@ func DeployEververse(config *Config) (*DeployResult, error) {
 	// Start services
 	fmt.Println()
 	fmt.Println("Starting services...")
-	if err := runEververseContainerCompose(deployDir, "up", "-d"); err != nil {
+	if err := runEververseContainerCompose(deployDir, "up", "--wait", "--wait-timeout", eververseWaitTimeout); err != nil {
 		return nil, fmt.Errorf("failed to start services: %w", err)
 	}
 
 	// Determine URL
 	baseURL := config.GetEndpoint()
 	if baseURL == "" {
-		baseURL = "http://localhost:3000"
+		baseURL = fmt.Sprintf("http://localhost:%d", stackPort(deployDir, "web", 3000))
 	}
 
 	result.Success = true
@@ -857,11 +598,8 @@ func DeployEververse(config *Config) (*DeployResult, error) {
 	result.Message = fmt.Sprintf(`Eververse deployed successfully!
 
 Access Eververse at:     %s
-Supabase Studio at:      http://localhost:3001
-Supabase API at:         http://localhost:8000
-
-Note: Full startup may take 60-120 seconds for all services to initialize.
-      Database initialization happens on first start.
+Supabase Studio at:      http://localhost:%d
+Supabase API at:         http://localhost:%d
 
 Services (12 containers):
   - PostgreSQL:          Database
@@ -877,7 +615,7 @@ Services (12 containers):
   - Studio:              Dashboard UI
   - Eververse:           Main application
 
-Resource usage: ~6GB RAM minimum`, baseURL)
+Resource usage: ~6GB RAM minimum`, baseURL, stackPort(deployDir, "studio", 3001), stackPort(deployDir, "api", 8000))
 
 	return result, nil
 }
@@ -1045,12 +783,13 @@ func DeployEververseInstance(instanceName string, port int, config *Config) (*De
 	}
 
 	// Generate compose file with custom ports
-	composeContent := generateEververseInstanceComposeYAML(instanceName, port)
-	composePath := filepath.Join(deployDir, eververseComposeFile)
-	if err := os.WriteFile(composePath, []byte(composeContent), 0644); err != nil {
-		return nil, fmt.Errorf("failed to write compose file: %w", err)
+	spec := StackSpec{Template: "eververse", Instance: instanceName, Ports: map[string]int{
+		"web": port, "studio": port + 1, "api": port + 10, "db": port + 20,
+	}}
+	if _, err := WriteStack(deployDir, eververseComposeFile, spec, config); err != nil {
+		return nil, err
 	}
-	result.ComposeFile = composePath
+	result.ComposeFile = filepath.Join(deployDir, eververseComposeFile)
 
 	// Write env file with instance-specific settings
 	envPath, err := writeEververseInstanceEnvFile(deployDir, instanceName, port, config)
@@ -1077,7 +816,7 @@ func DeployEververseInstance(instanceName string, port int, config *Config) (*De
 	// Start services
 	fmt.Println()
 	fmt.Println("Starting services...")
-	if err := runEververseInstanceContainerCompose(deployDir, projectName, "up", "-d"); err != nil {
+	if err := runEververseInstanceContainerCompose(deployDir, projectName, "up", "--wait", "--wait-timeout", eververseWaitTimeout); err != nil {
 		return nil, fmt.Errorf("failed to start services: %w", err)
 	}
 
@@ -1093,197 +832,6 @@ func DeployEververseInstance(instanceName string, port int, config *Config) (*De
 	return result, nil
 }
 
-// generateEververseInstanceComposeYAML generates docker-compose.yaml for a specific instance
-func generateEververseInstanceComposeYAML(instanceName string, port int) string {
-	studioPort := port + 1
-	apiPort := port + 10
-	dbPort := port + 20
-
-	return fmt.Sprintf(`# Eververse instance: %s
-# Ports: App=%d, Studio=%d, API=%d, DB=%d
-
-services:
-  db:
-    image: supabase/postgres:15.1.0.147
-    container_name: eververse-%s-db
-    restart: unless-stopped
-    ports:
-      - "%d:5432"
-    environment:
-      POSTGRES_PASSWORD: ${POSTGRES_PASSWORD}
-      POSTGRES_DB: postgres
-    volumes:
-      - eververse-%s-db:/var/lib/postgresql/data
-    healthcheck:
-      test: ["CMD-SHELL", "pg_isready -U postgres"]
-      interval: 10s
-      timeout: 5s
-      retries: 5
-
-  kong:
-    image: kong:2.8.1
-    container_name: eververse-%s-kong
-    restart: unless-stopped
-    ports:
-      - "%d:8000"
-    environment:
-      KONG_DATABASE: "off"
-      KONG_DECLARATIVE_CONFIG: /var/lib/kong/kong.yml
-      KONG_DNS_ORDER: LAST,A,CNAME
-      KONG_PLUGINS: request-transformer,cors,key-auth,acl
-    volumes:
-      - ./volumes/kong/kong.yml:/var/lib/kong/kong.yml:ro
-    depends_on:
-      db:
-        condition: service_healthy
-
-  auth:
-    image: supabase/gotrue:v2.99.0
-    container_name: eververse-%s-auth
-    restart: unless-stopped
-    environment:
-      GOTRUE_API_HOST: 0.0.0.0
-      GOTRUE_API_PORT: 9999
-      API_EXTERNAL_URL: http://localhost:%d
-      GOTRUE_DB_DRIVER: postgres
-      GOTRUE_DB_DATABASE_URL: postgres://postgres:${POSTGRES_PASSWORD}@db:5432/postgres?search_path=auth
-      GOTRUE_SITE_URL: http://localhost:%d
-      GOTRUE_JWT_SECRET: ${JWT_SECRET}
-      GOTRUE_JWT_EXP: 3600
-      GOTRUE_DISABLE_SIGNUP: "false"
-    depends_on:
-      db:
-        condition: service_healthy
-
-  rest:
-    image: postgrest/postgrest:v11.2.0
-    container_name: eververse-%s-rest
-    restart: unless-stopped
-    environment:
-      PGRST_DB_URI: postgres://postgres:${POSTGRES_PASSWORD}@db:5432/postgres
-      PGRST_DB_SCHEMAS: public,storage,graphql_public
-      PGRST_DB_ANON_ROLE: anon
-      PGRST_JWT_SECRET: ${JWT_SECRET}
-    depends_on:
-      db:
-        condition: service_healthy
-
-  realtime:
-    image: supabase/realtime:v2.25.35
-    container_name: eververse-%s-realtime
-    restart: unless-stopped
-    environment:
-      PORT: 4000
-      DB_HOST: db
-      DB_PORT: 5432
-      DB_USER: postgres
-      DB_PASSWORD: ${POSTGRES_PASSWORD}
-      DB_NAME: postgres
-      DB_SSL: "false"
-      JWT_SECRET: ${JWT_SECRET}
-      REPLICATION_MODE: RLS
-      SECURE_CHANNELS: "true"
-    depends_on:
-      db:
-        condition: service_healthy
-
-  storage:
-    image: supabase/storage-api:v0.43.11
-    container_name: eververse-%s-storage
-    restart: unless-stopped
-    environment:
-      ANON_KEY: ${ANON_KEY}
-      SERVICE_KEY: ${SERVICE_KEY}
-      POSTGREST_URL: http://rest:3000
-      PGRST_JWT_SECRET: ${JWT_SECRET}
-      DATABASE_URL: postgres://postgres:${POSTGRES_PASSWORD}@db:5432/postgres
-      STORAGE_BACKEND: file
-      FILE_STORAGE_BACKEND_PATH: /var/lib/storage
-      TENANT_ID: stub
-      REGION: stub
-      GLOBAL_S3_BUCKET: stub
-    volumes:
-      - eververse-%s-storage:/var/lib/storage
-    depends_on:
-      db:
-        condition: service_healthy
-      rest:
-        condition: service_started
-
-  meta:
-    image: supabase/postgres-meta:v0.68.0
-    container_name: eververse-%s-meta
-    restart: unless-stopped
-    environment:
-      PG_META_PORT: 8080
-      PG_META_DB_HOST: db
-      PG_META_DB_PORT: 5432
-      PG_META_DB_NAME: postgres
-      PG_META_DB_USER: postgres
-      PG_META_DB_PASSWORD: ${POSTGRES_PASSWORD}
-    depends_on:
-      db:
-        condition: service_healthy
-
-  studio:
-    image: supabase/studio:20231123-64a766a
-    container_name: eververse-%s-studio
-    restart: unless-stopped
-    ports:
-      - "%d:3000"
-    environment:
-      STUDIO_PG_META_URL: http://meta:8080
-      POSTGRES_PASSWORD: ${POSTGRES_PASSWORD}
-      DEFAULT_ORGANIZATION_NAME: Eververse
-      DEFAULT_PROJECT_NAME: Eververse %s
-      SUPABASE_URL: http://kong:8000
-      SUPABASE_PUBLIC_URL: http://localhost:%d
-      SUPABASE_ANON_KEY: ${ANON_KEY}
-      SUPABASE_SERVICE_KEY: ${SERVICE_KEY}
-    depends_on:
-      - kong
-      - meta
-
-  eververse:
-    image: portunix/eververse:latest
-    pull_policy: never
-    container_name: eververse-%s-app
-    restart: unless-stopped
-    ports:
-      - "%d:3000"
-    environment:
-      NODE_ENV: production
-      NEXT_PUBLIC_SUPABASE_URL: http://kong:8000
-      NEXT_PUBLIC_SUPABASE_ANON_KEY: ${ANON_KEY}
-      SUPABASE_SERVICE_ROLE_KEY: ${SERVICE_KEY}
-      DATABASE_URL: postgres://postgres:${POSTGRES_PASSWORD}@db:5432/postgres
-      NEXT_PUBLIC_DISABLE_ANALYTICS: "true"
-    depends_on:
-      - kong
-      - auth
-      - rest
-      - storage
-
-volumes:
-  eververse-%s-db:
-  eververse-%s-storage:
-
-networks:
-  default:
-    name: eververse-%s-network
-`, instanceName, port, studioPort, apiPort, dbPort,
-		instanceName, dbPort, instanceName,
-		instanceName, apiPort,
-		instanceName, apiPort, port,
-		instanceName,
-		instanceName,
-		instanceName, instanceName,
-		instanceName,
-		instanceName, studioPort, instanceName, apiPort,
-		instanceName, port,
-		instanceName, instanceName, instanceName)
-}
-
 // writeEververseInstanceEnvFile writes environment file for a specific instance
 func writeEververseInstanceEnvFile(deployDir, instanceName string, port int, config *Config) (string, error) {
 	envPath := filepath.Join(deployDir, eververseEnvFile)
@@ -0,0 +1,349 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// syncedCategoriesKey is the frontmatter key recording the categories of an
+// item at the last sync, so a category removed on one side is not restored
+// from the other
+const syncedCategoriesKey = "synced_categories"
+
+// defaultTagColor is used for tags of categories without a color
+const defaultTagColor = "6B7280"
+
+// maxTagNameLength is the longest tag name Fider accepts
+const maxTagNameLength = 30
+
+// FiderTagMapping maps the categories of an area to Fider tags
+type FiderTagMapping struct {
+	registry *CategoryRegistry
+	tags     map[string]FiderTag // By slug
+	changed  bool                // Registry needs saving
+}
+
+// NewFiderTagMapping maps categories to existing tags. A category without a
+// recorded tag is matched by ID or name.
+func NewFiderTagMapping(registry *CategoryRegistry, tags []FiderTag) *FiderTagMapping {
+	m := &FiderTagMapping{registry: registry, tags: make(map[string]FiderTag)}
+	for _, tag := range tags {
+		m.tags[tag.Slug] = tag
+	}
+	for i := range registry.Categories {
+		cat := &registry.Categories[i]
+		if cat.FiderTag != "" {
+			continue
+		}
+		for _, tag := range tags {
+			if m.categoryIDForSlug(tag.Slug) != "" {
+				continue // Mapped to another category
+			}
+			if tag.Slug == strings.ToLower(cat.ID) || strings.EqualFold(tag.Name, cat.ID) || strings.EqualFold(tag.Name, cat.Name) {
+				cat.FiderTag = tag.Slug
+				m.changed = true
+				break
+			}
+		}
+	}
+	return m
+}
+
+func (m *FiderTagMapping) categoryIDForSlug(slug string) string {
+	for _, cat := range m.registry.Categories {
+		if cat.FiderTag == slug {
+			return cat.ID
+		}
+	}
+	return ""
+}
+
+// CategoryForTag returns the category of a tag. A tag added in Fider gets a
+// new category when create is set; otherwise it is not mapped.
+func (m *FiderTagMapping) CategoryForTag(tag FiderTag, create bool) (string, bool) {
+	if id := m.categoryIDForSlug(tag.Slug); id != "" {
+		return id, true
+	}
+	if !create {
+		return "", false
+	}
+
+	cat := Category{ID: tag.Slug, Name: tag.Name, FiderTag: tag.Slug}
+	if tag.Color != "" && ValidateHexColor("#"+tag.Color) == nil {
+		cat.Color = "#" + tag.Color
+	}
+	if err := m.registry.AddCategory(cat); err != nil {
+		return "", false
+	}
+	m.changed = true
+	return NormalizeCategoryID(tag.Slug), true
+}
+
+// TagForCategory returns the tag slug of a category, creating the tag in
+// Fider when missing. In dry-run mode a missing tag is not created and the
+// returned slug is empty.
+func (m *FiderTagMapping) TagForCategory(client *FiderClient, id string, dryRun bool) (string, error) {
+	cat, err := m.registry.GetCategory(id)
+	if err != nil {
+		return "", err
+	}
+	if _, exists := m.tags[cat.FiderTag]; exists {
+		return cat.FiderTag, nil
+	}
+	if dryRun {
+		fmt.Printf("  [DRY-RUN] Would create tag: %s\n", tagName(cat))
+		return "", nil
+	}
+
+	color := strings.TrimPrefix(cat.Color, "#")
+	if color == "" {
+		color = defaultTagColor
+	}
+	tag, err := client.CreateTag(tagName(cat), color, true)
+	if err != nil {
+		return "", fmt.Errorf("failed to create tag for %s: %w", cat.ID, err)
+	}
+	fmt.Printf("  ✓ Created tag: %s\n", tag.Name)
+	m.tags[tag.Slug] = *tag
+	cat.FiderTag = tag.Slug
+	m.changed = true
+	return tag.Slug, nil
+}
+
+// tagName returns the Fider tag name of a category
+func tagName(cat *Category) string {
+	if cat.Name != "" && len([]rune(cat.Name)) <= maxTagNameLength {
+		return cat.Name
+	}
+	return cat.ID
+}
+
+// mergeCategories merges the categories of both sides against the state of
+// the last sync: a category added on either side is kept, one removed on
+// either side is dropped
+func mergeCategories(synced, local, remote []string) []string {
+	inSynced := toSet(synced)
+	inLocal := toSet(local)
+	inRemote := toSet(remote)
+
+	var merged []string
+	seen := make(map[string]bool)
+	for _, id := range append(append([]string{}, local...), remote...) {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if inSynced[id] && !(inLocal[id] && inRemote[id]) {
+			continue // Removed on one side
+		}
+		merged = append(merged, id)
+	}
+	return merged
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+func sameCategories(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	inA := toSet(a)
+	for _, id := range b {
+		if !inA[id] {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeCategories(categories []string) []string {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, id := range categories {
+		id = NormalizeCategoryID(strings.TrimSpace(id))
+		if id != "" && !seen[id] {
+			seen[id] = true
+			normalized = append(normalized, id)
+		}
+	}
+	return normalized
+}
+
+// readSyncedCategories returns the categories recorded at the last sync
+func readSyncedCategories(filePath string) []string {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil
+	}
+	fm, _ := ParseFrontmatter(string(content))
+	if fm == nil {
+		return nil
+	}
+	return fm.List(syncedCategoriesKey)
+}
+
+// writeSyncedCategories records the sync state and, when categories is not
+// nil, replaces the item's categories. A file without frontmatter gets one.
+func writeSyncedCategories(filePath string, categories *[]string, synced []string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	fm, body := ParseFrontmatter(string(content))
+	if fm == nil {
+		fm, body = NewFrontmatter(), "\n"+body
+	}
+	if categories != nil {
+		fm.Delete("category")
+		fm.SetList("categories", *categories)
+		body = removeCategoriesSection(body)
+	}
+	fm.SetList(syncedCategoriesKey, synced)
+
+	return os.WriteFile(filePath, []byte(fm.String()+body), 0644)
+}
+
+// SyncCategoriesWithFider reconciles the categories of synced items in dir
+// with the tags of their Fider posts. Changes made on either side since the
+// last sync are merged; pull writes them to local files (creating categories
+// for new tags), push to Fider (creating tags for new categories).
+func SyncCategoriesWithFider(client *FiderClient, projectDir, area, dir string, pull, push, dryRun bool) (int, int, error) {
+	registry, err := LoadCategoryRegistry(projectDir, area)
+	if err != nil {
+		return 0, 0, err
+	}
+	tags, err := client.ListTags()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list tags: %w", err)
+	}
+	posts, err := client.ListPosts()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list posts: %w", err)
+	}
+	items, err := ScanFeedbackDirectory(dir, area)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to scan directory: %w", err)
+	}
+
+	postsByNumber := make(map[int]FiderPost, len(posts))
+	for _, post := range posts {
+		postsByNumber[post.Number] = post
+	}
+	mapping := NewFiderTagMapping(registry, tags)
+
+	localUpdated, remoteUpdated := 0, 0
+	for _, item := range items {
+		number, ok := ExtractFiderID(item.FilePath)
+		if !ok {
+			continue
+		}
+		post, ok := postsByNumber[number]
+		if !ok {
+			continue
+		}
+
+		var remote []string
+		remoteSlugs := make(map[string]string)
+		for _, tag := range post.Tags {
+			if id, ok := mapping.CategoryForTag(tag, pull); ok {
+				remote = append(remote, id)
+				remoteSlugs[id] = tag.Slug
+			}
+		}
+		local := normalizeCategories(item.Categories)
+		synced := normalizeCategories(readSyncedCategories(item.FilePath))
+		merged := mergeCategories(synced, local, remote)
+
+		// The recorded state is what both sides agree on after this run
+		state := merged
+		if !push {
+			state = remote
+		} else if !pull {
+			state = local
+		}
+
+		if push {
+			changed, failed := false, false
+			for _, id := range merged {
+				if _, tagged := remoteSlugs[id]; tagged {
+					continue
+				}
+				slug, err := mapping.TagForCategory(client, id, dryRun)
+				if err != nil {
+					fmt.Printf("  ⚠ %s: %v\n", item.ID, err)
+					failed = true
+					continue
+				}
+				if dryRun {
+					fmt.Printf("  [DRY-RUN] Would tag Fider #%d: %s\n", number, id)
+				} else if err := client.AssignTag(number, slug); err != nil {
+					fmt.Printf("  ✗ Failed to tag Fider #%d with %s: %v\n", number, id, err)
+					failed = true
+					continue
+				} else {
+					fmt.Printf("  ✓ Tagged Fider #%d: %s\n", number, id)
+				}
+				changed = true
+			}
+			inMerged := toSet(merged)
+			for _, id := range remote {
+				if inMerged[id] {
+					continue
+				}
+				if dryRun {
+					fmt.Printf("  [DRY-RUN] Would untag Fider #%d: %s\n", number, id)
+				} else if err := client.UnassignTag(number, remoteSlugs[id]); err != nil {
+					fmt.Printf("  ✗ Failed to untag Fider #%d from %s: %v\n", number, id, err)
+					failed = true
+					continue
+				} else {
+					fmt.Printf("  ✓ Untagged Fider #%d: %s\n", number, id)
+				}
+				changed = true
+			}
+			if changed {
+				remoteUpdated++
+			}
+			if failed {
+				// Keep the last state, so the next sync retries the change
+				state = synced
+			}
+		}
+
+		var categories *[]string
+		if pull && !sameCategories(local, merged) {
+			categories = &merged
+			localUpdated++
+			if dryRun {
+				fmt.Printf("  [DRY-RUN] Would set categories of %s: %s\n", item.ID, strings.Join(merged, ", "))
+			} else {
+				fmt.Printf("  ✓ Categories of %s: %s\n", item.ID, strings.Join(merged, ", "))
+			}
+		}
+		if dryRun || (categories == nil && sameCategories(synced, state)) {
+			continue
+		}
+		if err := writeSyncedCategories(item.FilePath, categories, state); err != nil {
+			fmt.Printf("  ✗ Failed to update %s: %v\n", item.ID, err)
+		}
+	}
+
+	if mapping.changed && !dryRun {
+		if err := SaveCategoryRegistry(projectDir, area, registry); err != nil {
+			return localUpdated, remoteUpdated, err
+		}
+	}
+	return localUpdated, remoteUpdated, nil
+}
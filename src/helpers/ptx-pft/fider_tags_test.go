@@ -0,0 +1,158 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeFiderTags serves the tag endpoints of one post
+type fakeFiderTags struct {
+	tags     []FiderTag
+	postTags []string // Slugs of the tags on post #1
+	requests []string
+}
+
+func (f *fakeFiderTags) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/api/v1/tags":
+		json.NewEncoder(w).Encode(f.tags)
+	case r.Method == "POST" && r.URL.Path == "/api/v1/tags":
+		var req FiderCreateTag
+		json.NewDecoder(r.Body).Decode(&req)
+		tag := FiderTag{ID: len(f.tags) + 1, Slug: strings.ToLower(req.Name), Name: req.Name, Color: req.Color}
+		f.tags = append(f.tags, tag)
+		json.NewEncoder(w).Encode(tag)
+	case r.Method == "GET" && r.URL.Path == "/api/v1/posts":
+		post := FiderPost{ID: 1, Number: 1, Title: "Dark mode"}
+		for _, slug := range f.postTags {
+			for _, tag := range f.tags {
+				if tag.Slug == slug {
+					post.Tags = append(post.Tags, tag)
+				}
+			}
+		}
+		json.NewEncoder(w).Encode([]FiderPost{post})
+	case strings.HasPrefix(r.URL.Path, "/api/v1/posts/1/tags/"):
+		slug := strings.TrimPrefix(r.URL.Path, "/api/v1/posts/1/tags/")
+		var kept []string
+		for _, s := range f.postTags {
+			if s != slug {
+				kept = append(kept, s)
+			}
+		}
+		if r.Method == "POST" {
+			kept = append(kept, slug)
+		}
+		f.postTags = kept
+		w.Write([]byte("{}"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestSyncCategoriesWithFider(t *testing.T) {
+	projectDir := t.TempDir()
+	registry, _ := LoadCategoryRegistry(projectDir, "voc")
+	registry.AddCategory(Category{ID: "AUTH", Name: "Authentication"})
+	registry.AddCategory(Category{ID: "EXPORT", Name: "Export"})
+	if err := SaveCategoryRegistry(projectDir, "voc", registry); err != nil {
+		t.Fatal(err)
+	}
+
+	// Locally AUTH was removed and EXPORT added since the last sync; in
+	// Fider the post was tagged Mobile
+	vocDir := getVoiceDir(projectDir, "voc")
+	path := filepath.Join(vocDir, "UC001-dark-mode.md")
+	item := "---\ncategories:\n  - EXPORT\nsynced_categories:\n  - AUTH\n---\n\n# Dark mode\n\n## Metadata\n- Fider ID: 1\n"
+	if err := os.WriteFile(path, []byte(item), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fider := &fakeFiderTags{
+		tags: []FiderTag{
+			{ID: 1, Slug: "authentication", Name: "Authentication"},
+			{ID: 2, Slug: "mobile", Name: "Mobile", Color: "FF0000"},
+		},
+		postTags: []string{"authentication", "mobile"},
+	}
+	server := httptest.NewServer(fider)
+	defer server.Close()
+	client := NewFiderClient(server.URL, "test-key")
+
+	// Push only leaves the local file alone
+	local, remote, err := SyncCategoriesWithFider(client, projectDir, "voc", vocDir, false, true, true)
+	if err != nil || local != 0 || remote != 1 {
+		t.Fatalf("dry-run push: %d %d %v", local, remote, err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != item {
+		t.Fatal("dry run must not write the item")
+	}
+
+	local, remote, err = SyncCategoriesWithFider(client, projectDir, "voc", vocDir, true, true, false)
+	if err != nil || local != 1 || remote != 1 {
+		t.Fatalf("sync: %d %d %v", local, remote, err)
+	}
+	if got := strings.Join(fider.postTags, ","); got != "mobile,export" {
+		t.Errorf("unexpected post tags %s", got)
+	}
+	parsed, _ := ParseMarkdownFile(path)
+	if got := strings.Join(parsed.Categories, ","); got != "EXPORT,MOBILE" {
+		t.Errorf("unexpected categories %s", got)
+	}
+	if got := strings.Join(readSyncedCategories(path), ","); got != "EXPORT,MOBILE" {
+		t.Errorf("unexpected sync state %s", got)
+	}
+
+	registry, _ = LoadCategoryRegistry(projectDir, "voc")
+	mobile, err := registry.GetCategory("MOBILE")
+	if err != nil || mobile.FiderTag != "mobile" || mobile.Color != "#FF0000" {
+		t.Errorf("tag not imported as category: %+v %v", mobile, err)
+	}
+	if export, _ := registry.GetCategory("EXPORT"); export.FiderTag != "export" {
+		t.Errorf("created tag not recorded: %+v", export)
+	}
+
+	// Nothing changes on the next sync
+	fider.requests = nil
+	local, remote, err = SyncCategoriesWithFider(client, projectDir, "voc", vocDir, true, true, false)
+	if err != nil || local != 0 || remote != 0 {
+		t.Errorf("second sync: %d %d %v", local, remote, err)
+	}
+	for _, req := range fider.requests {
+		if strings.HasPrefix(req, "POST") || strings.HasPrefix(req, "DELETE") {
+			t.Errorf("unexpected request %s", req)
+		}
+	}
+}
+
+func TestMergeCategories(t *testing.T) {
+	tests := []struct {
+		synced, local, remote, want string
+	}{
+		{"", "A", "B", "A,B"},
+		{"A", "A", "A,B", "A,B"},
+		{"A,B", "A", "A,B", "A"},
+		{"A,B", "A,B,C", "B", "B,C"},
+	}
+	split := func(s string) []string {
+		if s == "" {
+			return nil
+		}
+		return strings.Split(s, ",")
+	}
+	for _, tt := range tests {
+		got := strings.Join(mergeCategories(split(tt.synced), split(tt.local), split(tt.remote)), ",")
+		if got != tt.want {
+			t.Errorf("merge(%s, %s, %s) = %s, want %s", tt.synced, tt.local, tt.remote, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Frontmatter is the YAML frontmatter of an item file. It keeps the parsed
+// document, so keys pft does not know, key order, comments and multi-line
+// values survive when pft rewrites the file.
+type Frontmatter struct {
+	node *yaml.Node // Mapping node
+}
+
+// NewFrontmatter returns an empty frontmatter
+func NewFrontmatter() *Frontmatter {
+	return &Frontmatter{node: &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}}
+}
+
+// ParseFrontmatter splits a markdown file into its frontmatter and the body
+// after the closing "---" line. A file without frontmatter returns nil and
+// the whole content. Frontmatter that is not valid YAML, as older versions
+// wrote for titles containing ": ", is read line by line.
+func ParseFrontmatter(content string) (*Frontmatter, string) {
+	source, body, ok := splitFrontmatter(content)
+	if !ok {
+		return nil, content
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(source), &doc); err == nil {
+		if len(doc.Content) == 0 {
+			return NewFrontmatter(), body
+		}
+		if doc.Content[0].Kind == yaml.MappingNode {
+			fm := &Frontmatter{node: doc.Content[0]}
+			fm.recoverHashValues()
+			return fm, body
+		}
+	}
+	return parseLegacyFrontmatter(source), body
+}
+
+// splitFrontmatter returns the frontmatter source between the "---" lines
+// and the body after them; ok is false when the file has no frontmatter
+func splitFrontmatter(content string) (source, body string, ok bool) {
+	rest, ok := cutLine(content, "---")
+	if !ok {
+		return "", content, false
+	}
+
+	for offset := 0; offset <= len(rest); {
+		end := strings.IndexByte(rest[offset:], '\n')
+		line := rest[offset:]
+		if end != -1 {
+			line = rest[offset : offset+end]
+		}
+		if strings.TrimRight(line, "\r") == "---" {
+			if end != -1 {
+				body = rest[offset+end+1:]
+			}
+			return rest[:offset], body, true
+		}
+		if end == -1 {
+			break
+		}
+		offset += end + 1
+	}
+	return "", content, false
+}
+
+// cutLine removes a first line equal to line
+func cutLine(content, line string) (string, bool) {
+	if rest, ok := strings.CutPrefix(content, line+"\n"); ok {
+		return rest, true
+	}
+	if rest, ok := strings.CutPrefix(content, line+"\r\n"); ok {
+		return rest, true
+	}
+	return content, false
+}
+
+// parseLegacyFrontmatter reads "key: value" lines and "- item" lists
+func parseLegacyFrontmatter(source string) *Frontmatter {
+	fm := NewFrontmatter()
+	var listKey string
+	var list []string
+	flush := func() {
+		if listKey != "" {
+			fm.SetList(listKey, list)
+		}
+		listKey, list = "", nil
+	}
+	for _, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "- "); ok {
+			if listKey != "" {
+				list = append(list, strings.TrimSpace(value))
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		flush()
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if value == "" {
+			listKey = key
+			continue
+		}
+		fm.Set(key, value)
+	}
+	flush()
+	return fm
+}
+
+// recoverHashValues restores values such as "linked_issue: #107", written
+// unquoted by older versions, which YAML reads as a comment. A comment
+// directly followed by text, unlike "# note", is taken as the value.
+func (f *Frontmatter) recoverHashValues() {
+	for i := 0; i+1 < len(f.node.Content); i += 2 {
+		key, value := f.node.Content[i], f.node.Content[i+1]
+		comment := key.LineComment
+		if value.Kind != yaml.ScalarNode || value.Tag != "!!null" || value.Value != "" ||
+			len(comment) < 2 || comment[0] != '#' || comment[1] == ' ' || comment[1] == '\t' {
+			continue
+		}
+		f.node.Content[i+1] = scalarNode(comment)
+		key.LineComment = ""
+	}
+}
+
+// Keys returns the keys in file order
+func (f *Frontmatter) Keys() []string {
+	var keys []string
+	for i := 0; i+1 < len(f.node.Content); i += 2 {
+		keys = append(keys, f.node.Content[i].Value)
+	}
+	return keys
+}
+
+// Has reports whether a key is present
+func (f *Frontmatter) Has(key string) bool {
+	return f.value(key) != nil
+}
+
+// Get returns the value of a scalar key, empty for lists and nested maps
+func (f *Frontmatter) Get(key string) string {
+	if value := f.value(key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
+
+// List returns the items of a list key; a scalar value is a one-item list
+func (f *Frontmatter) List(key string) []string {
+	value := f.value(key)
+	if value == nil {
+		return nil
+	}
+	switch value.Kind {
+	case yaml.SequenceNode:
+		var items []string
+		for _, item := range value.Content {
+			if item.Kind == yaml.ScalarNode && item.Value != "" {
+				items = append(items, item.Value)
+			}
+		}
+		return items
+	case yaml.ScalarNode:
+		if value.Value != "" {
+			return []string{value.Value}
+		}
+	}
+	return nil
+}
+
+// Set sets a scalar key, keeping its position; an empty value removes it
+func (f *Frontmatter) Set(key, value string) {
+	if value == "" {
+		f.Delete(key)
+		return
+	}
+	f.put(key, scalarNode(value))
+}
+
+// SetList sets a list key, keeping its position; an empty list removes it
+func (f *Frontmatter) SetList(key string, values []string) {
+	if len(values) == 0 {
+		f.Delete(key)
+		return
+	}
+	list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, value := range values {
+		list.Content = append(list.Content, scalarNode(value))
+	}
+	f.put(key, list)
+}
+
+// ReplaceValues replaces the matches of pattern in every value, including
+// the items of lists and nested maps, and reports whether any matched
+func (f *Frontmatter) ReplaceValues(pattern *regexp.Regexp, replacement string) bool {
+	var replace func(node *yaml.Node) bool
+	replace = func(node *yaml.Node) bool {
+		if node.Kind == yaml.ScalarNode {
+			if !pattern.MatchString(node.Value) {
+				return false
+			}
+			node.Value = pattern.ReplaceAllString(node.Value, replacement)
+			return true
+		}
+		changed := false
+		for i, child := range node.Content {
+			// Keys of nested maps are field names, not values
+			if node.Kind == yaml.MappingNode && i%2 == 0 {
+				continue
+			}
+			if replace(child) {
+				changed = true
+			}
+		}
+		return changed
+	}
+
+	changed := false
+	for i := 1; i < len(f.node.Content); i += 2 {
+		if replace(f.node.Content[i]) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Delete removes a key
+func (f *Frontmatter) Delete(key string) {
+	for i := 0; i+1 < len(f.node.Content); i += 2 {
+		if f.node.Content[i].Value == key {
+			f.node.Content = append(f.node.Content[:i], f.node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// String renders the frontmatter including its "---" delimiters
+func (f *Frontmatter) String() string {
+	if len(f.node.Content) == 0 {
+		return "---\n---\n"
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(f.node); err != nil {
+		// Nodes built by pft always encode; keep the file readable anyway
+		return "---\n---\n"
+	}
+	enc.Close()
+	return "---\n" + buf.String() + "---\n"
+}
+
+func (f *Frontmatter) value(key string) *yaml.Node {
+	for i := 0; i+1 < len(f.node.Content); i += 2 {
+		if f.node.Content[i].Value == key {
+			return f.node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func (f *Frontmatter) put(key string, value *yaml.Node) {
+	for i := 0; i+1 < len(f.node.Content); i += 2 {
+		if f.node.Content[i].Value == key {
+			old := f.node.Content[i+1]
+			value.LineComment, value.FootComment = old.LineComment, old.FootComment
+			f.node.Content[i+1] = value
+			return
+		}
+	}
+	f.node.Content = append(f.node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// scalarNode returns a string value; the encoder quotes it only when a plain
+// scalar would be misread, so dates and numbers stay as they were written
+func scalarNode(value string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	switch strings.ToLower(value) {
+	case "null", "~", "true", "false":
+		node.Tag = "!!str"
+	}
+	if strings.Contains(value, "\n") {
+		node.Style = yaml.LiteralStyle
+	}
+	return node
+}
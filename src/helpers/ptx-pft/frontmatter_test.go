@@ -0,0 +1,110 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const customItem = `---
+id: P07
+title: 'Export: PDF'
+status: open # set by triage
+description: |
+  First line.
+  Second line.
+votes: 12
+customer:
+  name: Acme
+  tier: gold
+linked_issue: #107
+tags:
+  - export
+---
+
+# Export: PDF
+
+## Follow-up
+
+Keep this section.
+`
+
+func TestParseFrontmatter(t *testing.T) {
+	fm, body := ParseFrontmatter(customItem)
+	if fm == nil {
+		t.Fatal("frontmatter not found")
+	}
+	if fm.Get("title") != "Export: PDF" || fm.Get("description") != "First line.\nSecond line.\n" {
+		t.Errorf("unexpected values %q %q", fm.Get("title"), fm.Get("description"))
+	}
+	if fm.Get("linked_issue") != "#107" {
+		t.Errorf("unquoted issue reference lost: %q", fm.Get("linked_issue"))
+	}
+	if tags := fm.List("tags"); len(tags) != 1 || tags[0] != "export" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if !strings.HasPrefix(body, "\n# Export: PDF\n") {
+		t.Errorf("unexpected body %q", body)
+	}
+
+	// Frontmatter written by older versions is not always valid YAML
+	fm, body = ParseFrontmatter("---\nid: P01\ntitle: Dark mode: night\nproducts:\n  - App\n---\nBody\n")
+	if fm == nil || fm.Get("title") != "Dark mode: night" || len(fm.List("products")) != 1 || body != "Body\n" {
+		t.Errorf("legacy frontmatter not read: %v %q", fm, body)
+	}
+
+	// A closing delimiter must be a line of its own
+	if fm, _ := ParseFrontmatter("---\ntitle: a---b\n"); fm != nil {
+		t.Error("unterminated frontmatter must not be parsed")
+	}
+	if fm, body := ParseFrontmatter("# Title\n---\n"); fm != nil || body != "# Title\n---\n" {
+		t.Error("a file not starting with --- has no frontmatter")
+	}
+}
+
+func TestUpdatePreservesUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "P07-export.md")
+	if err := os.WriteFile(path, []byte(customItem), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UpdateFileFields(path, map[string]string{"status": "planned", "assignee": "jana"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateFileCategories(path, []string{"EXPORT"}); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(path)
+	for _, want := range []string{"status: planned # set by triage", "description: |\n  First line.\n  Second line.\n", "customer:\n  name: Acme\n  tier: gold\n", "linked_issue: '#107'", "categories:\n  - EXPORT\n", "assignee: jana\n", "Keep this section."} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("missing %q:\n%s", want, content)
+		}
+	}
+
+	// 'pft update' rewrites the known fields and keeps everything else
+	params := parseExistingItem(string(content))
+	params.Priority = "high"
+	params.Tags = append(params.Tags, "pdf")
+	updated := generateFeedbackMarkdown(*params)
+	for _, want := range []string{"title: 'Export: PDF'", "priority: high", "votes: 12", "customer:\n  name: Acme", "  - export\n  - pdf\n", "description: |\n  First line.\n  Second line.\n", "Keep this section."} {
+		if !strings.Contains(updated, want) {
+			t.Errorf("missing %q:\n%s", want, updated)
+		}
+	}
+	if strings.Contains(updated, "## Description") {
+		t.Error("a description kept in the frontmatter must not be copied into the body")
+	}
+
+	item, err := parseMarkdownContent(path, []byte(updated))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Title != "Export: PDF" || item.Votes != 12 || item.LinkedIssue != "#107" || len(item.Categories) != 1 || len(item.Tags) != 2 {
+		t.Errorf("unexpected item %+v", item)
+	}
+}
@@ -0,0 +1,178 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// healthProbeTimeout limits a single health probe
+const healthProbeTimeout = 5 * time.Second
+
+// mailhogUIPort is the port of the Mailhog web UI and API
+const mailhogUIPort = "3200"
+
+// healthPaths are the health endpoints of the providers, relative to their
+// base URL; providers without one are probed at the base URL
+var healthPaths = map[string]string{
+	"fider":      "/_health",
+	"clearflask": "",
+	"eververse":  "",
+	"mailhog":    "/api/v2/messages?limit=1",
+}
+
+// HealthProbe is the result of probing a health endpoint
+type HealthProbe struct {
+	Name           string `json:"name"`
+	Provider       string `json:"provider"`
+	URL            string `json:"url"`
+	Healthy        bool   `json:"healthy"`
+	StatusCode     int    `json:"status_code,omitempty"`
+	ResponseTimeMs int64  `json:"response_time_ms"`
+	Error          string `json:"error,omitempty"`
+}
+
+// StatusReport is the status of the feedback stack
+type StatusReport struct {
+	Product        string        `json:"product"`
+	Provider       string        `json:"provider"`
+	Endpoint       string        `json:"endpoint,omitempty"`
+	Remote         string        `json:"remote,omitempty"`
+	Infrastructure string        `json:"infrastructure"`
+	Healthy        bool          `json:"healthy"`
+	Probes         []HealthProbe `json:"probes"`
+	CheckedAt      time.Time     `json:"checked_at"`
+}
+
+// healthProbes returns the health endpoints of the configured stack: the
+// web UI of each area served by a deployed provider, and Mailhog for
+// providers sending email through it
+func healthProbes(config *Config) []HealthProbe {
+	var probes []HealthProbe
+	seen := make(map[string]bool)
+	add := func(name, provider, baseURL string) {
+		probeURL := baseURL
+		if path := healthPaths[provider]; path != "" {
+			probeURL = strings.TrimSuffix(baseURL, "/") + path
+		}
+		if seen[probeURL] {
+			return
+		}
+		seen[probeURL] = true
+		probes = append(probes, HealthProbe{Name: name, Provider: provider, URL: probeURL})
+	}
+
+	for _, area := range ValidAreaNames {
+		cfg := config.GetAreaConfig(area)
+		if cfg == nil || cfg.URL == "" {
+			continue
+		}
+		if _, ok := healthPaths[cfg.Provider]; !ok {
+			continue
+		}
+		add(area, cfg.Provider, cfg.URL)
+	}
+
+	provider := config.GetProvider()
+	if provider == "fider" || provider == "email" {
+		host := "localhost"
+		if config.SMTP != nil && config.SMTP.Host != "" {
+			host = config.SMTP.Host
+		} else if u, err := url.Parse(config.GetEndpoint()); err == nil && u.Hostname() != "" {
+			host = u.Hostname()
+		}
+		add("mail", "mailhog", "http://"+net.JoinHostPort(host, mailhogUIPort))
+	}
+	return probes
+}
+
+// probe requests the health endpoint and records status and response time
+func (p *HealthProbe) probe(client *http.Client) {
+	start := time.Now()
+	resp, err := client.Get(p.URL)
+	if err != nil {
+		p.ResponseTimeMs = time.Since(start).Milliseconds()
+		p.Error = err.Error()
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	p.ResponseTimeMs = time.Since(start).Milliseconds()
+	p.StatusCode = resp.StatusCode
+	p.Healthy = resp.StatusCode < 400
+	if !p.Healthy {
+		p.Error = resp.Status
+	}
+}
+
+// CheckHealth probes the health endpoints of the configured stack
+func CheckHealth(config *Config) []HealthProbe {
+	client := &http.Client{Timeout: healthProbeTimeout}
+	probes := healthProbes(config)
+	done := make(chan struct{})
+	for i := range probes {
+		go func(p *HealthProbe) {
+			p.probe(client)
+			done <- struct{}{}
+		}(&probes[i])
+	}
+	for range probes {
+		<-done
+	}
+	return probes
+}
+
+// getInfrastructureStatus returns the container status of the local deployment
+func getInfrastructureStatus(provider string) (string, error) {
+	switch provider {
+	case "fider":
+		return GetStatus()
+	case "email":
+		return GetEmailOnlyStatus()
+	case "clearflask":
+		return GetClearFlaskStatus()
+	case "eververse":
+		return GetEververseStatus()
+	}
+	return "not_implemented", nil
+}
+
+// BuildStatusReport checks the containers and health endpoints of the
+// stack. The containers of a remote deployment are not checked.
+func BuildStatusReport(config *Config) *StatusReport {
+	report := &StatusReport{
+		Product:   config.Name,
+		Provider:  config.GetProvider(),
+		Endpoint:  config.GetEndpoint(),
+		Remote:    config.Remote,
+		CheckedAt: time.Now().UTC(),
+	}
+
+	if config.Remote != "" {
+		report.Infrastructure = "remote"
+	} else if status, err := getInfrastructureStatus(report.Provider); err != nil {
+		report.Infrastructure = "unknown"
+	} else {
+		report.Infrastructure = status
+	}
+
+	report.Probes = CheckHealth(config)
+	if report.Probes == nil {
+		report.Probes = []HealthProbe{}
+	}
+	report.Healthy = report.Infrastructure != "not_deployed" && report.Infrastructure != "stopped" &&
+		report.Infrastructure != "partial" && report.Infrastructure != "error"
+	for _, p := range report.Probes {
+		if !p.Healthy {
+			report.Healthy = false
+		}
+	}
+	return report
+}
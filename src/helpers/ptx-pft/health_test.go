@@ -0,0 +1,74 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHealthProbes(t *testing.T) {
+	config := &Config{
+		VoC:  &AreaConfig{Provider: "fider", URL: "http://vps:3000/"},
+		VoS:  &AreaConfig{Provider: "fider", URL: "http://vps:3000"},
+		VoB:  &AreaConfig{Provider: "local"},
+		SMTP: &SMTPConfig{Host: "vps", Port: 1025},
+	}
+	probes := healthProbes(config)
+	if len(probes) != 2 {
+		t.Fatalf("unexpected probes %+v", probes)
+	}
+	if probes[0].URL != "http://vps:3000/_health" || probes[0].Name != "voc" {
+		t.Errorf("unexpected Fider probe %+v", probes[0])
+	}
+	if probes[1].URL != "http://vps:3200/api/v2/messages?limit=1" || probes[1].Provider != "mailhog" {
+		t.Errorf("unexpected Mailhog probe %+v", probes[1])
+	}
+
+	if probes := healthProbes(&Config{VoC: &AreaConfig{Provider: "clearflask", URL: "http://localhost:3100"}}); len(probes) != 1 {
+		t.Errorf("ClearFlask must not probe Mailhog: %+v", probes)
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_health" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+
+	config := &Config{
+		Remote: "vps",
+		VoC:    &AreaConfig{Provider: "fider", URL: server.URL},
+		VoS:    &AreaConfig{Provider: "fider", URL: broken.URL},
+		SMTP:   &SMTPConfig{Host: "127.0.0.1"},
+	}
+	report := BuildStatusReport(config)
+	if report.Infrastructure != "remote" || report.Healthy || len(report.Probes) != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	voc, vos := report.Probes[0], report.Probes[1]
+	if !voc.Healthy || voc.StatusCode != 200 {
+		t.Errorf("unexpected VoC probe %+v", voc)
+	}
+	if vos.Healthy || vos.StatusCode != 503 || !strings.Contains(vos.Error, "503") {
+		t.Errorf("unexpected VoS probe %+v", vos)
+	}
+
+	config.VoS = nil
+	config.SMTP = nil
+	config.VoC.Provider = "clearflask"
+	config.VoC.URL = server.URL + "/_health"
+	if report := BuildStatusReport(config); !report.Healthy {
+		t.Errorf("expected healthy report %+v", report)
+	}
+}
@@ -0,0 +1,388 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import "portunix.ai/app/aihelp"
+
+// showHelpAI prints machine-readable help for --help-ai
+func showHelpAI() {
+	id := aihelp.Argument{Name: "id", Type: "string", Required: true, Description: "Feedback item ID"}
+	path := aihelp.Flag{Name: "path", Type: "path", Description: "Project directory (default: configured project path)"}
+	area := aihelp.Flag{Name: "area", Type: "string", Description: "Feedback area", Choices: []string{"voc", "vos", "vob", "voe"}}
+	syncFlags := []aihelp.Flag{
+		{Name: "voc", Type: "boolean", Description: "Only VoC (Voice of Customer)"},
+		{Name: "vos", Type: "boolean", Description: "Only VoS (Voice of Stakeholder)"},
+		{Name: "dry-run", Type: "boolean", Description: "Show what would change without making changes"},
+		{Name: "voc-token", Type: "string", Description: "VoC API token (saved to configuration)"},
+		{Name: "vos-token", Type: "string", Description: "VoS API token (saved to configuration)"},
+	}
+
+	aihelp.Help{
+		Tool:        "ptx-pft",
+		Version:     version,
+		Description: "Product feedback tool: QFD projects, feedback items and sync with Fider, ClearFlask, Eververse or email",
+		Commands: []aihelp.Command{
+			{
+				Name:        "pft project create",
+				Description: "Create a new PFT project",
+				Arguments:   []aihelp.Argument{{Name: "name", Type: "string", Required: true}},
+				Flags:       []aihelp.Flag{{Name: "template", Type: "string", Default: "qfd", Description: "Project template", Choices: []string{"qfd", "basic"}}},
+				Examples:    []string{"portunix pft project create my-product"},
+			},
+			{Name: "pft info", Description: "Show methodology documentation", Flags: []aihelp.Flag{{Name: "json", Type: "boolean", Description: "Output as JSON"}}},
+			{
+				Name:        "pft configure",
+				Description: "Configure project and providers (interactive without flags)",
+				Flags: []aihelp.Flag{
+					{Name: "name", Type: "string", Description: "Project name"},
+					{Name: "path", Type: "path", Description: "Project path"},
+					{Name: "language", Type: "string", Description: "Language of item bodies, reports and notifications", Choices: []string{"en", "cs", "de"}},
+					area,
+					{Name: "provider", Type: "string", Description: "Provider for --area", Choices: []string{"fider", "clearflask", "eververse", "discourse", "email"}},
+					{Name: "url", Type: "url", Description: "Provider URL for --area"},
+					{Name: "token", Type: "string", Description: "Provider API token for --area"},
+					{Name: "project-id", Type: "string", Description: "Provider project ID for --area"},
+					{Name: "discourse-category", Type: "string", Description: "Discourse category (slug or ID) mapped to --area"},
+					{Name: "discourse-user", Type: "string", Description: "Discourse user the API key acts as (default: system)"},
+					{Name: "status-sync", Type: "string", Description: "How statuses are pushed to Discourse topics", Choices: []string{"tags", "reply", "both"}},
+					{Name: "id-prefix", Type: "string", Description: "Prefix of new item IDs in --area, letters only (default: P)"},
+					{Name: "id-padding", Type: "integer", Description: "Minimum digits of new item IDs in --area (default: 2)"},
+					{Name: "smtp-host", Type: "string", Description: "SMTP server host"},
+					{Name: "smtp-port", Type: "integer", Description: "SMTP server port"},
+					{Name: "smtp-user", Type: "string", Description: "SMTP user"},
+					{Name: "smtp-pass", Type: "string", Description: "SMTP password"},
+					{Name: "smtp-from", Type: "string", Description: "Sender address"},
+					{Name: "ai-provider", Type: "string", Description: "Embedding provider of pft analyze", Choices: []string{"local", "ollama", "openai"}},
+					{Name: "ai-url", Type: "url", Description: "AI API endpoint"},
+					{Name: "ai-model", Type: "string", Description: "Embedding model"},
+					{Name: "ai-chat-model", Type: "string", Description: "Model writing cluster summaries"},
+					{Name: "ai-key", Type: "string", Description: "AI API key (stored in the secret store)"},
+					{Name: "share-url", Type: "string", Description: "Public address of 'pft share serve' used in share links"},
+					{Name: "show", Type: "boolean", Description: "Show current configuration"},
+					{Name: "fix-paths", Type: "boolean", Description: "Convert stored paths to the current platform"},
+				},
+				Examples: []string{"portunix pft configure --show", "portunix pft configure --area voc --provider fider --url http://localhost:3100"},
+			},
+			{
+				Name:        "pft deploy",
+				Description: "Deploy the feedback tool to a container",
+				Arguments:   []aihelp.Argument{{Name: "provider", Type: "string", Choices: []string{"fider", "clearflask", "eververse", "email"}}},
+				Flags: []aihelp.Flag{
+					{Name: "remote", Type: "string", Description: "Deploy to a host over SSH (user@host[:port], fleet host or VM name)"},
+					{Name: "url", Type: "url", Description: "Public URL of the remote deployment"},
+				},
+				Examples: []string{"portunix pft deploy --remote admin@feedback.example.com --url https://feedback.example.com"},
+			},
+			{
+				Name:        "pft status",
+				Description: "Check feedback tool containers and probe health endpoints; exits 1 when unhealthy",
+				Flags: []aihelp.Flag{
+					{Name: "json", Type: "boolean", Description: "Output status, probes and response times as JSON"},
+					{Name: "watch", Shorthand: "w", Type: "boolean", Description: "Refresh the status on container start/stop/die/oom events until interrupted"},
+				},
+			},
+			{Name: "pft destroy", Description: "Remove the feedback tool instance"},
+			{
+				Name:        "pft upgrade",
+				Description: "Upgrade the deployed feedback tool: pin images, back up the database, pull, restart and check health",
+				Flags: []aihelp.Flag{
+					{Name: "version", Type: "string", Description: "Tag of the application images"},
+					{Name: "image", Type: "stringArray", Description: "Pin the image of a compose service as <service>=<image> (repeatable)"},
+					{Name: "skip-backup", Type: "boolean", Description: "Upgrade without a database backup"},
+					{Name: "pin-only", Type: "boolean", Description: "Save the pins without upgrading"},
+					{Name: "timeout", Type: "duration", Default: "3m", Description: "Time to wait for the tool to become healthy"},
+					{Name: "dry-run", Type: "boolean", Description: "Show the image changes without upgrading"},
+				},
+				Examples: []string{"portunix pft upgrade --version v0.25.0 --dry-run"},
+			},
+			{Name: "pft sync", Description: "Bidirectional sync: pull new posts, then push new local files; Discourse areas pull topics and likes and push statuses as tags or replies", Flags: syncFlags, Examples: []string{"portunix pft sync --voc --dry-run"}},
+			{Name: "pft pull", Description: "Pull from the external system", Flags: syncFlags},
+			{Name: "pft push", Description: "Push to the external system", Flags: syncFlags},
+			{
+				Name:        "pft watch",
+				Description: "Watch VoC/VoS directories, validate the frontmatter of saved items, update the sync cache and optionally push",
+				Flags: []aihelp.Flag{
+					{Name: "voc", Type: "boolean", Description: "Only watch VoC"},
+					{Name: "vos", Type: "boolean", Description: "Only watch VoS"},
+					{Name: "push", Type: "boolean", Description: "Push an area after its items changed, once all are valid"},
+					{Name: "debounce", Type: "duration", Default: "300ms", Description: "Wait for editors to finish saving"},
+					{Name: "path", Type: "path", Description: "Project directory"},
+				},
+				Examples: []string{"portunix pft watch --voc --push"},
+			},
+			{
+				Name:        "pft list",
+				Description: "List feedback items",
+				Flags: []aihelp.Flag{
+					{Name: "voc", Type: "boolean", Description: "Only VoC items"},
+					{Name: "vos", Type: "boolean", Description: "Only VoS items"},
+					{Name: "all", Shorthand: "a", Type: "boolean", Description: "All areas"},
+					{Name: "format", Type: "string", Description: "Output format"},
+					{Name: "json", Type: "boolean", Description: "Print all items as a single JSON array"},
+					{Name: "category", Type: "string", Description: "Filter by category"},
+					{Name: "uncategorized", Type: "boolean", Description: "Only items without category"},
+					{Name: "sort", Type: "string", Description: "Sort by score from pft analyze --score", Choices: []string{"urgency", "sentiment"}},
+					path,
+				},
+			},
+			{Name: "pft show", Description: "Show feedback details", Arguments: []aihelp.Argument{id}, Flags: []aihelp.Flag{path}},
+			{
+				Name:        "pft add",
+				Description: "Add a new feedback item",
+				Flags: []aihelp.Flag{
+					area,
+					{Name: "title", Type: "string", Description: "Item title"},
+					{Name: "description", Type: "string", Description: "Item description"},
+					{Name: "verbatim", Type: "string", Description: "Original customer wording"},
+					{Name: "category", Type: "string", Description: "Category ID"},
+					{Name: "author", Type: "string", Description: "Author"},
+					{Name: "source", Type: "string", Description: "Feedback source"},
+					{Name: "status", Type: "string", Description: "Item status"},
+					{Name: "priority", Type: "string", Description: "Item priority"},
+					{Name: "legacy-id", Type: "string", Description: "ID in a previous system"},
+					{Name: "product", Type: "string", Description: "Product"},
+					{Name: "target-user", Type: "string", Description: "Target user"},
+					{Name: "related", Type: "string", Description: "Related item IDs"},
+					{Name: "tag", Type: "stringArray", Description: "Tags"},
+					path,
+				},
+				Examples: []string{"portunix pft add --area voc --title \"Faster export\""},
+			},
+			{Name: "pft update", Description: "Update a feedback item", Arguments: []aihelp.Argument{id}},
+			{
+				Name:        "pft board",
+				Description: "Interactive terminal kanban of item statuses; moves, categories and assignees are saved to the markdown files",
+				Flags: []aihelp.Flag{
+					{Name: "area", Type: "string", Default: "voc", Choices: []string{"voc", "vos", "vob", "voe"}},
+					path,
+				},
+			},
+			{Name: "pft link", Description: "Link feedback to a local issue", Arguments: []aihelp.Argument{id, {Name: "issue", Type: "string", Required: true}}},
+			{
+				Name:        "pft report",
+				Description: "Generate a feedback report",
+				Flags: []aihelp.Flag{
+					{Name: "type", Type: "string", Default: "summary", Choices: []string{"summary", "detailed", "status", "sla", "effort"}},
+					{Name: "output", Shorthand: "o", Type: "path", Description: "Output file"},
+				},
+			},
+			{
+				Name:        "pft export",
+				Description: "Export feedback items",
+				Flags: []aihelp.Flag{
+					{Name: "format", Type: "string", Default: "md", Choices: []string{"md", "json", "csv", "sprint"}, Description: "sprint: CSV of unresolved items with effort in hours"},
+					{Name: "output", Shorthand: "o", Type: "path", Description: "Output file"},
+					{Name: "voc", Type: "boolean", Description: "Only VoC items"},
+					{Name: "vos", Type: "boolean", Description: "Only VoS items"},
+				},
+			},
+			{
+				Name:        "pft publish",
+				Description: "Generate a static read-only feedback portal (roadmap, status, votes)",
+				Flags: []aihelp.Flag{
+					{Name: "format", Type: "string", Default: "site", Choices: []string{"site"}},
+					{Name: "output", Shorthand: "o", Type: "path", Default: "./public", Description: "Output directory"},
+					{Name: "title", Type: "string", Description: "Portal title (default: project name)"},
+					{Name: "voc", Type: "boolean", Description: "Publish VoC items (default)"},
+					{Name: "vos", Type: "boolean", Description: "Publish VoS items"},
+					{Name: "path", Type: "path", Description: "Project directory"},
+				},
+			},
+			{
+				Name:        "pft share",
+				Description: "Share one item read-only with external stakeholders: a signed self-contained HTML page, or with --token a signed link to 'pft share serve'; both expire",
+				Arguments:   []aihelp.Argument{{Name: "item-id", Type: "string", Required: true}},
+				Flags: []aihelp.Flag{
+					{Name: "expires", Type: "string", Default: "7d", Description: "Lifetime of the share, e.g. 12h, 7d, 2w"},
+					{Name: "output", Shorthand: "o", Type: "path", Description: "HTML file (default <id>-share.html)"},
+					{Name: "token", Type: "boolean", Description: "Print a link to the share server instead of writing a file"},
+					{Name: "title", Type: "string", Description: "Page title (default: project name)"},
+					{Name: "path", Type: "path", Description: "Project directory"},
+				},
+				Examples: []string{"portunix pft share UC001 --expires 7d", "portunix pft share UC001 --token --expires 2w"},
+			},
+			{
+				Name:        "pft share serve",
+				Description: "Serve share links at /share/<token> with the current status of the item; expired links answer 410",
+				Flags: []aihelp.Flag{
+					{Name: "listen", Type: "string", Default: ":8086"},
+					{Name: "title", Type: "string", Description: "Page title (default: project name)"},
+					{Name: "path", Type: "path", Description: "Project directory"},
+				},
+			},
+			{Name: "pft share verify", Description: "Check the signature and expiry of a share export or token", Arguments: []aihelp.Argument{{Name: "file-or-token", Type: "string", Required: true}}},
+			{Name: "pft share rotate-key", Description: "Replace the share signing key, revoking all share links and exports"},
+			{
+				Name:        "pft release-notes",
+				Description: "Generate customer-facing release notes from items implemented since a git tag",
+				Flags: []aihelp.Flag{
+					{Name: "since", Type: "string", Description: "Git tag, commit or date YYYY-MM-DD (required)"},
+					{Name: "until", Type: "string", Default: "HEAD", Description: "End of the range"},
+					{Name: "group-by", Type: "string", Default: "category", Choices: []string{"category", "product"}},
+					{Name: "title", Type: "string", Description: "Title (default: project name)"},
+					{Name: "output", Shorthand: "o", Type: "path", Description: "Output file"},
+					{Name: "voc", Type: "boolean", Description: "Only VoC items"},
+					{Name: "vos", Type: "boolean", Description: "Only VoS items"},
+					{Name: "json", Type: "boolean", Description: "Output entries as JSON"},
+					{Name: "path", Type: "path", Description: "Project directory"},
+				},
+			},
+			{
+				Name:        "pft sla status",
+				Description: "List SLA breaches and upcoming deadlines with compliance metrics per area",
+				Flags: []aihelp.Flag{
+					{Name: "area", Type: "string", Choices: []string{"voc", "vos", "vob", "voe"}},
+					{Name: "all", Shorthand: "a", Type: "boolean", Description: "Also list met, missed and on-track targets"},
+					{Name: "json", Type: "boolean", Description: "Output metrics and checks as JSON"},
+					{Name: "path", Type: "path", Description: "Project directory"},
+				},
+			},
+			{
+				Name:        "pft metrics",
+				Description: "Export item counts by area and status, sync lag, last sync times and notification failures as Prometheus metrics",
+				Flags: []aihelp.Flag{
+					{Name: "listen", Type: "string", Default: DefaultMetricsListen, Description: "Serve /metrics on this address"},
+					{Name: "textfile", Type: "path", Description: "Write a .prom file for the node_exporter textfile collector"},
+					{Name: "interval", Type: "duration", Description: "Rewrite the textfile periodically"},
+					{Name: "path", Type: "path", Description: "Project directory"},
+				},
+			},
+			{
+				Name:        "pft intake email",
+				Description: "Import unread emails of an IMAP mailbox as feedback items, threading replies",
+				Flags: []aihelp.Flag{
+					{Name: "imap", Type: "string", Description: "IMAP server host[:port] (default: configured)"},
+					{Name: "user", Type: "string", Description: "IMAP username"},
+					{Name: "password", Type: "string", Description: "IMAP password or secret:<name> reference"},
+					{Name: "mailbox", Type: "string", Default: "INBOX"},
+					{Name: "plaintext", Type: "boolean", Description: "Connect without TLS"},
+					{Name: "area", Type: "string", Default: "voc", Choices: []string{"voc", "vos", "vob", "voe"}},
+					{Name: "auto-reply", Type: "boolean", Description: "Reply to senders with the item ID"},
+					{Name: "interval", Type: "string", Description: "Keep polling at this interval (e.g. 5m)"},
+					{Name: "dry-run", Type: "boolean", Description: "Show what would be imported"},
+					{Name: "json", Type: "boolean", Description: "Output results as JSON"},
+					{Name: "path", Type: "path", Description: "Project directory"},
+				},
+			},
+			{
+				Name:        "pft intake chat",
+				Description: "Serve signed Slack slash command and Teams outgoing webhook endpoints creating items from chat messages",
+				Flags: []aihelp.Flag{
+					{Name: "listen", Type: "string", Default: ":8085", Description: "Listen address"},
+					{Name: "area", Type: "string", Default: "voc", Choices: []string{"voc", "vos", "vob", "voe"}},
+					{Name: "dry-run", Type: "boolean", Description: "Reply without creating items"},
+					{Name: "path", Type: "path", Description: "Project directory"},
+				},
+			},
+			{Name: "pft cache", Description: "Manage the local provider cache"},
+			{
+				Name:        "pft notify",
+				Description: "Send a notification about a feedback item",
+				Arguments:   []aihelp.Argument{id},
+				Flags: []aihelp.Flag{
+					{Name: "user", Type: "string", Description: "Recipient email"},
+					{Name: "type", Type: "string", Description: "Notification type"},
+					{Name: "all-voc", Type: "boolean", Description: "Notify all VoC users"},
+					{Name: "all-vos", Type: "boolean", Description: "Notify all VoS users"},
+					{Name: "channel", Type: "string", Description: "Post to slack[:#channel] or teams[:channel] (repeatable)"},
+					{Name: "preview", Type: "string", Description: "Show the personalized email of a user (email or name) without sending"},
+					{Name: "dry-run", Type: "boolean", Description: "Show notifications without sending"},
+				},
+			},
+			{Name: "pft user list", Description: "List users"},
+			{Name: "pft user add", Description: "Add a user"},
+			{Name: "pft user role", Description: "Assign a role to a user", Arguments: []aihelp.Argument{{Name: "id", Type: "string", Required: true}}},
+			{Name: "pft user link", Description: "Link a user to an external ID", Arguments: []aihelp.Argument{{Name: "id", Type: "string", Required: true}}},
+			{Name: "pft user remove", Description: "Remove a user", Arguments: []aihelp.Argument{{Name: "id", Type: "string", Required: true}}},
+			{
+				Name:        "pft user import",
+				Description: "Import users from CSV or LDAP/Active Directory with role mapping rules",
+				Flags: []aihelp.Flag{
+					{Name: "csv", Type: "path", Description: "CSV file with id/email, name, organization and optional voc/vos/vob/voe role columns"},
+					{Name: "ldap", Type: "url", Description: "LDAP server URL (ldap:// or ldaps://)"},
+					{Name: "base-dn", Type: "string", Description: "LDAP search base"},
+					{Name: "filter", Type: "string", Default: DefaultLDAPFilter, Description: "LDAP search filter"},
+					{Name: "bind-dn", Type: "string", Description: "DN to bind as"},
+					{Name: "bind-password", Type: "string", Description: "Bind password or secret:<name> reference"},
+					{Name: "start-tls", Type: "boolean", Description: "Use StartTLS on ldap:// connections"},
+					{Name: "map", Type: "string", Description: "Role rule <area>=<role> or <attribute>=<pattern>:<area>=<role>[:proxy] (repeatable)"},
+					{Name: "dry-run", Type: "boolean", Description: "Show what would be imported"},
+					{Name: "path", Type: "path", Description: "Project directory"},
+				},
+			},
+			{
+				Name:        "pft user purge",
+				Description: "Erase a user for a GDPR erasure request: registry entry, item authorship and feedback tool accounts",
+				Arguments:   []aihelp.Argument{{Name: "email", Type: "string", Required: true}},
+				Flags: []aihelp.Flag{
+					{Name: "anonymize-items", Type: "boolean", Description: "Replace the user in their items by Anonymous and redact their email"},
+					{Name: "delete-account", Type: "boolean", Description: "Delete the user's accounts in the feedback tools of the areas"},
+					{Name: "dry-run", Type: "boolean", Description: "Show what would be erased"},
+					{Name: "yes", Type: "boolean", Description: "Do not ask for confirmation"},
+					{Name: "path", Type: "path", Description: "Project directory"},
+				},
+			},
+			{Name: "pft role list", Description: "List available roles"},
+			{Name: "pft role init", Description: "Initialize default role files"},
+			{Name: "pft category list", Description: "List categories in an area"},
+			{Name: "pft category add", Description: "Create a category", Arguments: []aihelp.Argument{{Name: "id", Type: "string", Required: true}}},
+			{Name: "pft category remove", Description: "Delete a category", Arguments: []aihelp.Argument{{Name: "id", Type: "string", Required: true}}},
+			{Name: "pft category rename", Description: "Rename a category", Arguments: []aihelp.Argument{{Name: "id", Type: "string", Required: true}}},
+			{Name: "pft category show", Description: "Show category details", Arguments: []aihelp.Argument{{Name: "id", Type: "string", Required: true}}},
+			{
+				Name:        "pft assign",
+				Description: "Add categories to an item",
+				Arguments:   []aihelp.Argument{{Name: "item-id", Type: "string", Required: true}},
+				Flags: []aihelp.Flag{
+					{Name: "category", Shorthand: "c", Type: "string", Description: "Category ID"},
+					{Name: "set", Shorthand: "s", Type: "boolean", Description: "Replace existing categories"},
+					path,
+				},
+			},
+			{
+				Name:        "pft estimate",
+				Description: "Set the estimate and actual effort of an item (frontmatter fields estimate and actual_effort); without values shows them",
+				Arguments: []aihelp.Argument{
+					{Name: "item-id", Type: "string", Required: true},
+					{Name: "effort", Type: "string", Description: "Estimated working time: h, d (8h) or w (5d), e.g. 3d or \"1w 2d\""},
+				},
+				Flags: []aihelp.Flag{
+					{Name: "actual", Shorthand: "a", Type: "string", Description: "Effort actually spent"},
+					{Name: "clear", Type: "boolean", Description: "Remove the estimate and actual effort"},
+					path,
+				},
+				Examples: []string{"portunix pft estimate UC001 3d", "portunix pft estimate UC001 --actual 4d"},
+			},
+			{
+				Name:        "pft analyze",
+				Description: "Cluster similar feedback items, propose categories and summaries for review, and apply approved proposals",
+				Flags: []aihelp.Flag{
+					{Name: "cluster", Type: "boolean", Description: "Cluster the items of an area"},
+					{Name: "score", Type: "boolean", Description: "Score sentiment and urgency of verbatims into their frontmatter"},
+					{Name: "llm", Type: "boolean", Description: "Score with the configured language model instead of rules"},
+					{Name: "force", Type: "boolean", Description: "Rescore items that already have scores"},
+					area,
+					{Name: "provider", Type: "string", Description: "Embedding provider", Choices: []string{"local", "ollama", "openai"}},
+					{Name: "threshold", Type: "number", Description: "Similarity needed to group items (0-1)"},
+					{Name: "json", Type: "boolean", Description: "Output as JSON"},
+					{Name: "apply", Type: "path", Description: "Apply the approved clusters of a saved analysis"},
+					{Name: "approve", Type: "string", Description: "Cluster IDs to approve while applying, or all"},
+					{Name: "dry-run", Type: "boolean", Description: "Show what --apply would change"},
+					path,
+				},
+				Examples: []string{"portunix pft analyze --cluster --area voc", "portunix pft analyze --apply .pft-analysis/clusters-voc-20260101-100000.json --approve all"},
+			},
+			{
+				Name:        "pft unassign",
+				Description: "Remove categories from an item",
+				Arguments:   []aihelp.Argument{{Name: "item-id", Type: "string", Required: true}},
+				Flags: []aihelp.Flag{
+					{Name: "category", Shorthand: "c", Type: "string", Description: "Category ID"},
+					{Name: "all", Type: "boolean", Description: "Remove all categories"},
+				},
+			},
+		},
+	}.Print()
+}
@@ -0,0 +1,137 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"portunix.ai/app/fileutil"
+)
+
+// IDRegistryFileName stores the last allocated number per area and prefix,
+// so IDs are never reused, even after an item file is deleted
+const IDRegistryFileName = ".pft-ids.json"
+
+var idPrefixPattern = regexp.MustCompile(`^[A-Za-z]+$`)
+
+// ValidateIDScheme checks a configured ID scheme
+func ValidateIDScheme(scheme IDScheme) error {
+	if !idPrefixPattern.MatchString(scheme.Prefix) {
+		return fmt.Errorf("invalid ID prefix '%s' (letters only, e.g. UC or REQ)", scheme.Prefix)
+	}
+	if scheme.Padding < 1 || scheme.Padding > 9 {
+		return fmt.Errorf("invalid ID padding %d (1-9, 1 for no zero padding)", scheme.Padding)
+	}
+	return nil
+}
+
+// Format returns the ID of a number
+func (s IDScheme) Format(number int) string {
+	return fmt.Sprintf("%s%0*d", s.Prefix, s.Padding, number)
+}
+
+// idRegistry maps area -> prefix -> last allocated number
+type idRegistry map[string]map[string]int
+
+// generateNextItemID allocates the next ID of an area. The counter in
+// .pft-ids.json is raised under the registry's file lock, so parallel
+// invocations never get the same ID; items created by hand or by older
+// versions are found by scanning the area directory.
+func generateNextItemID(config *Config, projectDir, area string) (string, error) {
+	scheme := config.GetIDScheme(area)
+	if err := ValidateIDScheme(scheme); err != nil {
+		return "", err
+	}
+
+	registryPath := filepath.Join(projectDir, IDRegistryFileName)
+	var next int
+	err := fileutil.WithLock(registryPath, func() error {
+		registry, err := loadIDRegistry(projectDir)
+		if err != nil {
+			return err
+		}
+		next = registry.next(projectDir, area, scheme.Prefix)
+		if registry[area] == nil {
+			registry[area] = map[string]int{}
+		}
+		registry[area][scheme.Prefix] = next
+
+		data, err := json.MarshalIndent(registry, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := fileutil.WriteAtomic(registryPath, data, 0644); err != nil {
+			return fmt.Errorf("failed to save %s: %w", IDRegistryFileName, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return scheme.Format(next), nil
+}
+
+// previewNextItemID returns the ID the next item of an area would get,
+// without allocating it (dry runs)
+func previewNextItemID(config *Config, projectDir, area string) (string, error) {
+	scheme := config.GetIDScheme(area)
+	if err := ValidateIDScheme(scheme); err != nil {
+		return "", err
+	}
+	registry, err := loadIDRegistry(projectDir)
+	if err != nil {
+		return "", err
+	}
+	return scheme.Format(registry.next(projectDir, area, scheme.Prefix)), nil
+}
+
+func loadIDRegistry(projectDir string) (idRegistry, error) {
+	registry := idRegistry{}
+	data, err := os.ReadFile(filepath.Join(projectDir, IDRegistryFileName))
+	if os.IsNotExist(err) {
+		return registry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", IDRegistryFileName, err)
+	}
+	return registry, nil
+}
+
+// next returns the number after both the counter and the item files
+func (r idRegistry) next(projectDir, area, prefix string) int {
+	last := r[area][prefix]
+	if used := highestUsedID(getVoiceDir(projectDir, area), prefix); used > last {
+		last = used
+	}
+	return last + 1
+}
+
+// highestUsedID returns the highest number of the item files of an area
+// named <prefix><number>-<slug>.md
+func highestUsedID(areaDir, prefix string) int {
+	pattern := regexp.MustCompile(`^(?i)` + regexp.QuoteMeta(prefix) + `(\d+)(?:[-_.]|$)`)
+	highest := 0
+	filepath.WalkDir(areaDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(d.Name(), ".md") {
+			return nil
+		}
+		if match := pattern.FindStringSubmatch(d.Name()); match != nil {
+			if number, err := strconv.Atoi(match[1]); err == nil && number > highest {
+				highest = number
+			}
+		}
+		return nil
+	})
+	return highest
+}
@@ -0,0 +1,92 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestGenerateNextItemID(t *testing.T) {
+	projectDir := t.TempDir()
+	config := &Config{}
+
+	for _, want := range []string{"P01", "P02"} {
+		id, err := generateNextItemID(config, projectDir, "voc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != want {
+			t.Errorf("got %s, want %s", id, want)
+		}
+	}
+
+	// Use cases continue after items created by hand
+	config.SetAreaConfig("vos", &AreaConfig{IDScheme: &IDScheme{Prefix: "UC", Padding: 3}})
+	needs := filepath.Join(getVoiceDir(projectDir, "vos"), "needs")
+	if err := os.MkdirAll(needs, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(needs, "UC007-login.md"), []byte("# Login\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := previewNextItemID(config, projectDir, "vos"); id != "UC008" {
+		t.Errorf("preview got %s, want UC008", id)
+	}
+	if id, _ := generateNextItemID(config, projectDir, "vos"); id != "UC008" {
+		t.Errorf("got %s, want UC008", id)
+	}
+
+	// A deleted item's ID is never reused
+	os.Remove(filepath.Join(needs, "UC007-login.md"))
+	if id, _ := generateNextItemID(config, projectDir, "vos"); id != "UC009" {
+		t.Errorf("got %s, want UC009", id)
+	}
+	if id, _ := generateNextItemID(config, projectDir, "voc"); id != "P03" {
+		t.Errorf("areas must be numbered separately, got %s", id)
+	}
+
+	config.SetAreaConfig("vob", &AreaConfig{IDScheme: &IDScheme{Prefix: "B-"}})
+	if _, err := generateNextItemID(config, projectDir, "vob"); err == nil {
+		t.Error("a prefix with non-letters must be rejected")
+	}
+}
+
+func TestGenerateNextItemIDConcurrent(t *testing.T) {
+	projectDir := t.TempDir()
+	config := &Config{}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := generateNextItemID(config, projectDir, "voc")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[id] {
+				t.Errorf("duplicate ID %s", id)
+			}
+			seen[id] = true
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 20 || !seen["P20"] {
+		t.Errorf("expected P01-P20, got %v", seen)
+	}
+
+	// A lock file left over by a crashed process does not block allocation
+	if id, err := generateNextItemID(config, projectDir, "voc"); err != nil || id != "P21" {
+		t.Errorf("got %s, %v", id, err)
+	}
+}
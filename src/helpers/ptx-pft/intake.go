@@ -0,0 +1,484 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"portunix.ai/app/fileutil"
+)
+
+// IntakeStateFileName maps processed Message-IDs to items, so threads are
+// recognized and messages are never imported twice
+const IntakeStateFileName = ".pft-intake.json"
+
+// InboundMessage is an email or chat message converted for intake
+type InboundMessage struct {
+	Source        string // email (default), slack, teams
+	MessageID     string
+	References    []string // In-Reply-To and References
+	Subject       string
+	FromName      string
+	FromAddress   string
+	Date          time.Time
+	Body          string
+	AutoGenerated bool // Auto-replies, bounces and mailing lists get no reply
+}
+
+// Intake outcomes
+const (
+	IntakeCreated   = "created"
+	IntakeFollowUp  = "follow-up"
+	IntakeDuplicate = "duplicate"
+)
+
+// IntakeEntry is the outcome of one inbound message
+type IntakeEntry struct {
+	ItemID  string `json:"item_id"`
+	Outcome string `json:"outcome"`
+	Subject string `json:"subject"`
+	From    string `json:"from"`
+	Replied bool   `json:"replied,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// IntakeOptions controls how inbound messages become items
+type IntakeOptions struct {
+	Area      string
+	AutoReply bool
+	DryRun    bool
+}
+
+type intakeState struct {
+	Messages map[string]string `json:"messages"` // Message-ID -> item ID
+}
+
+func loadIntakeState(projectDir string) (*intakeState, error) {
+	state := &intakeState{Messages: map[string]string{}}
+	data, err := os.ReadFile(filepath.Join(projectDir, IntakeStateFileName))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", IntakeStateFileName, err)
+	}
+	if state.Messages == nil {
+		state.Messages = map[string]string{}
+	}
+	return state, nil
+}
+
+func (s *intakeState) save(projectDir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFile(filepath.Join(projectDir, IntakeStateFileName), data, 0644)
+}
+
+// parseInboundMessage parses a raw RFC 822 message
+func parseInboundMessage(raw []byte) (*InboundMessage, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+	decoder := new(mime.WordDecoder)
+	decode := func(value string) string {
+		if decoded, err := decoder.DecodeHeader(value); err == nil {
+			return decoded
+		}
+		return value
+	}
+
+	inbound := &InboundMessage{
+		MessageID: strings.TrimSpace(msg.Header.Get("Message-Id")),
+		Subject:   strings.TrimSpace(decode(msg.Header.Get("Subject"))),
+	}
+	for _, header := range []string{"In-Reply-To", "References"} {
+		inbound.References = append(inbound.References, strings.Fields(msg.Header.Get(header))...)
+	}
+	parser := mail.AddressParser{WordDecoder: decoder}
+	if from, err := parser.Parse(msg.Header.Get("From")); err == nil {
+		inbound.FromName, inbound.FromAddress = from.Name, from.Address
+	} else {
+		inbound.FromAddress = strings.TrimSpace(msg.Header.Get("From"))
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		inbound.Date = date
+	} else {
+		inbound.Date = time.Now()
+	}
+	if inbound.MessageID == "" {
+		// Without a Message-ID, sender and date identify the message
+		inbound.MessageID = fmt.Sprintf("<%s.%d@pft>", inbound.FromAddress, inbound.Date.Unix())
+	}
+
+	autoSubmitted := strings.ToLower(msg.Header.Get("Auto-Submitted"))
+	precedence := strings.ToLower(msg.Header.Get("Precedence"))
+	sender := strings.ToLower(inbound.FromAddress)
+	inbound.AutoGenerated = (autoSubmitted != "" && autoSubmitted != "no") ||
+		precedence == "bulk" || precedence == "list" || precedence == "junk" ||
+		msg.Header.Get("List-Id") != "" ||
+		strings.Contains(sender, "mailer-daemon") || strings.Contains(sender, "noreply") || strings.Contains(sender, "no-reply")
+
+	body, err := extractTextBody(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	inbound.Body = cleanVerbatim(body)
+	return inbound, nil
+}
+
+// extractTextBody returns the text of a message part, preferring text/plain
+// over text/html in multipart messages
+func extractTextBody(contentType, encoding string, body io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		var plain, htmlText string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", fmt.Errorf("invalid multipart message: %w", err)
+			}
+			partType := part.Header.Get("Content-Type")
+			if partType == "" {
+				partType = "text/plain"
+			}
+			if strings.HasPrefix(part.Header.Get("Content-Disposition"), "attachment") {
+				continue
+			}
+			text, err := extractTextBody(partType, part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil || text == "" {
+				continue
+			}
+			partMedia, _, _ := mime.ParseMediaType(partType)
+			if partMedia == "text/html" {
+				if htmlText == "" {
+					htmlText = text
+				}
+			} else if plain == "" {
+				plain = text
+			}
+		}
+		if plain != "" {
+			return plain, nil
+		}
+		return htmlText, nil
+	}
+	if !strings.HasPrefix(mediaType, "text/") {
+		return "", nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode message body: %w", err)
+	}
+	text := decodeCharset(data, params["charset"])
+	if mediaType == "text/html" {
+		text = htmlToText(text)
+	}
+	return text, nil
+}
+
+// decodeCharset converts Latin-1 bodies to UTF-8; other charsets are
+// expected to be UTF-8 compatible
+func decodeCharset(data []byte, charset string) string {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "windows-1252":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	}
+	return string(data)
+}
+
+var (
+	htmlBreaks = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>`)
+	htmlTags   = regexp.MustCompile(`(?s)<style.*?</style>|<script.*?</script>|<[^>]+>`)
+)
+
+func htmlToText(text string) string {
+	text = htmlBreaks.ReplaceAllString(text, "\n")
+	text = htmlTags.ReplaceAllString(text, "")
+	return html.UnescapeString(text)
+}
+
+// quoteHeader matches the line introducing a quoted reply, in English and
+// Czech mail clients
+var quoteHeader = regexp.MustCompile(`^(On .+ wrote:|Dne .+ napsal.*:|-----\s*Original Message\s*-----|-----\s*Původní zpráva\s*-----|From: .+)$`)
+
+// cleanVerbatim strips quoted replies and signatures from a message body
+func cleanVerbatim(body string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		// "-- " starts a signature; quoted-printable decoding drops the space
+		if trimmed == "--" || quoteHeader.MatchString(trimmed) {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	text := strings.TrimSpace(strings.Join(lines, "\n"))
+	// Collapse runs of empty lines
+	for strings.Contains(text, "\n\n\n") {
+		text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+	}
+	return text
+}
+
+var subjectItemTag = regexp.MustCompile(`\[([A-Z]+[0-9]+)\]`)
+
+// threadItemID returns the item an inbound message belongs to: the item of
+// a message it replies to, or an item ID tagged in the subject
+func threadItemID(projectDir string, msg *InboundMessage, state *intakeState) string {
+	for _, ref := range msg.References {
+		if id, ok := state.Messages[ref]; ok {
+			return id
+		}
+	}
+	if match := subjectItemTag.FindStringSubmatch(msg.Subject); match != nil {
+		if _, _, err := findFeedbackItemFile(projectDir, match[1]); err == nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+var replyPrefix = regexp.MustCompile(`(?i)^((re|fwd?|aw|odp|vs)\s*:\s*)+`)
+
+// ProcessInboundMessage turns a message into a new item or a follow-up of
+// the item of its thread and records it in the intake state
+func ProcessInboundMessage(config *Config, projectDir string, msg *InboundMessage, state *intakeState, opts IntakeOptions) (IntakeEntry, error) {
+	entry := IntakeEntry{Subject: msg.Subject, From: msg.FromAddress}
+	if id, ok := state.Messages[msg.MessageID]; ok {
+		entry.ItemID, entry.Outcome = id, IntakeDuplicate
+		return entry, nil
+	}
+	author := msg.FromName
+	if author == "" {
+		author = msg.FromAddress
+	}
+
+	if id := threadItemID(projectDir, msg, state); id != "" {
+		entry.ItemID, entry.Outcome = id, IntakeFollowUp
+		if !opts.DryRun {
+			path, _, err := findFeedbackItemFile(projectDir, id)
+			if err != nil {
+				return entry, err
+			}
+			followUp := fmt.Sprintf("\n## Follow-up %s (%s)\n\n> %s\n",
+				msg.Date.Format("2006-01-02"), author, strings.ReplaceAll(msg.Body, "\n", "\n> "))
+			file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				return entry, fmt.Errorf("failed to add follow-up to %s: %w", id, err)
+			}
+			_, err = file.WriteString(followUp)
+			file.Close()
+			if err != nil {
+				return entry, fmt.Errorf("failed to add follow-up to %s: %w", id, err)
+			}
+		}
+		state.Messages[msg.MessageID] = id
+		return entry, nil
+	}
+
+	area := opts.Area
+	if area == "" {
+		area = "voc"
+	}
+	areaDir := getVoiceDir(projectDir, area)
+	targetDir := filepath.Join(areaDir, "needs")
+	allocate := generateNextItemID
+	if opts.DryRun {
+		allocate = previewNextItemID
+	}
+	itemID, err := allocate(config, projectDir, area)
+	if err != nil {
+		return entry, err
+	}
+	entry.ItemID, entry.Outcome = itemID, IntakeCreated
+
+	title := strings.TrimSpace(replyPrefix.ReplaceAllString(msg.Subject, ""))
+	if title == "" {
+		title = truncateString(strings.SplitN(msg.Body, "\n", 2)[0], 60)
+	}
+	source := msg.Source
+	if source == "" {
+		source = "email"
+	}
+	if title == "" {
+		title = fmt.Sprintf("Feedback from %s via %s", author, source)
+	}
+	if !opts.DryRun {
+		if err := os.MkdirAll(targetDir, 0755); err != nil {
+			return entry, fmt.Errorf("failed to create directory: %w", err)
+		}
+		slug := createSlugFromTitle(title)
+		if len(slug) > 40 {
+			slug = slug[:40]
+		}
+		filePath := filepath.Join(targetDir, fmt.Sprintf("%s-%s.md", itemID, slug))
+		content := generateFeedbackMarkdown(FeedbackItemParams{
+			ID:       itemID,
+			Title:    title,
+			Area:     area,
+			Verbatim: strings.ReplaceAll(msg.Body, "\n", "\n> "),
+			Status:   "pending",
+			Author:   author,
+			Source:   source,
+			Created:  msg.Date.Format("2006-01-02"),
+			Language: config.GetLanguage(),
+		})
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			return entry, fmt.Errorf("failed to write item: %w", err)
+		}
+		fields := map[string]string{"message_id": msg.MessageID}
+		if msg.FromAddress != "" {
+			fields["author_email"] = msg.FromAddress
+		}
+		if err := UpdateFileFields(filePath, fields); err != nil {
+			return entry, err
+		}
+	}
+	state.Messages[msg.MessageID] = itemID
+	return entry, nil
+}
+
+// RunEmailIntake imports the unread messages of a mailbox. Processed
+// messages are flagged as seen; with AutoReply, senders of new items get
+// the item ID by email.
+func RunEmailIntake(config *Config, projectDir string, imap IMAPConfig, opts IntakeOptions) ([]IntakeEntry, error) {
+	port := imap.Port
+	if port == 0 {
+		port = 993
+		if imap.Plaintext {
+			port = 143
+		}
+	}
+	mailbox := imap.Mailbox
+	if mailbox == "" {
+		mailbox = "INBOX"
+	}
+	if opts.AutoReply && (config.SMTP == nil || config.SMTP.Host == "") {
+		return nil, fmt.Errorf("--auto-reply needs SMTP (pft configure --smtp-host ...)")
+	}
+
+	state, err := loadIntakeState(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dialIMAP(net.JoinHostPort(imap.Host, strconv.Itoa(port)), imap.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Logout()
+	if err := client.Login(imap.Username, resolveSecret(imap.Password)); err != nil {
+		return nil, err
+	}
+	if err := client.Select(mailbox); err != nil {
+		return nil, err
+	}
+	uids, err := client.SearchUnseen()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []IntakeEntry
+	for _, uid := range uids {
+		raw, err := client.Fetch(uid)
+		if err != nil {
+			return entries, err
+		}
+		msg, err := parseInboundMessage(raw)
+		if err != nil {
+			entries = append(entries, IntakeEntry{Error: fmt.Sprintf("message %d: %v", uid, err)})
+			continue
+		}
+		entry, err := ProcessInboundMessage(config, projectDir, msg, state, opts)
+		if err != nil {
+			entry.Error = err.Error()
+			entries = append(entries, entry)
+			continue
+		}
+		if !opts.DryRun {
+			if entry.Outcome == IntakeCreated && opts.AutoReply && !msg.AutoGenerated && msg.FromAddress != "" {
+				err := sendIntakeReply(config, msg, entry.ItemID)
+				RecordNotification(projectDir, "email", err)
+				if err != nil {
+					entry.Error = err.Error()
+				} else {
+					entry.Replied = true
+				}
+			}
+			if err := client.MarkSeen(uid); err != nil {
+				return entries, err
+			}
+			// Save after every message so an interrupted run never
+			// imports a message twice
+			if err := state.save(projectDir); err != nil {
+				return entries, fmt.Errorf("failed to save intake state: %w", err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func sendIntakeReply(config *Config, msg *InboundMessage, itemID string) error {
+	name := msg.FromName
+	if name == "" {
+		name = msg.FromAddress
+	}
+	subject, body, err := GenerateNotification(NotifyIntake, EmailData{
+		ProductName: config.Name,
+		UserName:    name,
+		Title:       strings.TrimSpace(replyPrefix.ReplaceAllString(msg.Subject, "")),
+		Provider:    "email",
+		ItemID:      itemID,
+		Language:    config.GetLanguage(),
+	})
+	if err != nil {
+		return err
+	}
+	return NewSMTPClient(config.SMTP).SendEmail(msg.FromAddress, subject, body)
+}
@@ -0,0 +1,189 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const multipartFeedback = "From: =?UTF-8?Q?Jana_Nov=C3=A1kov=C3=A1?= <jana@example.com>\r\n" +
+	"Subject: =?UTF-8?Q?Export_do_PDF_nefunguje?=\r\n" +
+	"Message-ID: <m1@example.com>\r\n" +
+	"Date: Mon, 12 Oct 2026 09:30:00 +0200\r\n" +
+	"Content-Type: multipart/alternative; boundary=b1\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/html; charset=UTF-8\r\n" +
+	"\r\n" +
+	"<p>HTML version</p>\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain; charset=UTF-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Export do PDF pad=C3=A1 p=C5=99i velk=C3=BDch reportech.\r\n" +
+	"\r\n" +
+	"-- \r\n" +
+	"Jana, ACME s.r.o.\r\n" +
+	"--b1--\r\n"
+
+func TestParseInboundMessage(t *testing.T) {
+	msg, err := parseInboundMessage([]byte(multipartFeedback))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Subject != "Export do PDF nefunguje" || msg.FromName != "Jana Nováková" || msg.FromAddress != "jana@example.com" {
+		t.Errorf("unexpected headers %+v", msg)
+	}
+	if msg.Body != "Export do PDF padá při velkých reportech." {
+		t.Errorf("plain text without signature expected, got %q", msg.Body)
+	}
+	if msg.AutoGenerated {
+		t.Error("a customer message is not auto-generated")
+	}
+
+	reply, _ := parseInboundMessage([]byte("From: bob@example.com\r\nSubject: Re: Dark mode [P01]\r\nIn-Reply-To: <m1@example.com>\r\nAuto-Submitted: auto-replied\r\n\r\nPlease hurry.\r\n\r\nOn Mon, 12 Oct 2026, Support wrote:\r\n> Thank you\r\n"))
+	if reply.Body != "Please hurry." || len(reply.References) != 1 || !reply.AutoGenerated {
+		t.Errorf("unexpected reply %+v", reply)
+	}
+}
+
+// fakeIMAPServer serves a fixed set of messages and records \Seen flags
+type fakeIMAPServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages map[uint32]string
+	seen     map[uint32]bool
+}
+
+func newFakeIMAPServer(t *testing.T, messages map[uint32]string) *fakeIMAPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeIMAPServer{listener: listener, messages: messages, seen: map[uint32]bool{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *fakeIMAPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "* OK fake IMAP ready\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		tag, cmd := fields[0], strings.Join(fields[1:], " ")
+		s.mu.Lock()
+		switch {
+		case strings.HasPrefix(cmd, "UID SEARCH UNSEEN"):
+			var uids []string
+			for uid := uint32(1); uid <= uint32(len(s.messages)); uid++ {
+				if !s.seen[uid] {
+					uids = append(uids, strconv.Itoa(int(uid)))
+				}
+			}
+			fmt.Fprintf(conn, "* SEARCH %s\r\n", strings.Join(uids, " "))
+		case strings.HasPrefix(cmd, "UID FETCH"):
+			uid, _ := strconv.Atoi(fields[3])
+			raw := s.messages[uint32(uid)]
+			fmt.Fprintf(conn, "* %d FETCH (UID %d BODY[] {%d}\r\n%s)\r\n", uid, uid, len(raw), raw)
+		case strings.HasPrefix(cmd, "UID STORE"):
+			uid, _ := strconv.Atoi(fields[3])
+			s.seen[uint32(uid)] = true
+		case strings.HasPrefix(cmd, "LOGOUT"):
+			fmt.Fprint(conn, "* BYE\r\n")
+		}
+		s.mu.Unlock()
+		fmt.Fprintf(conn, "%s OK done\r\n", tag)
+	}
+}
+
+func TestRunEmailIntake(t *testing.T) {
+	server := newFakeIMAPServer(t, map[uint32]string{
+		1: multipartFeedback,
+		2: "From: Jana <jana@example.com>\r\nSubject: Re: Export do PDF nefunguje\r\nMessage-ID: <m2@example.com>\r\nIn-Reply-To: <m1@example.com>\r\nDate: Tue, 13 Oct 2026 10:00:00 +0200\r\n\r\nStill broken in 1.4.\r\n",
+	})
+	host, port, _ := net.SplitHostPort(server.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	projectDir := t.TempDir()
+	imap := IMAPConfig{Host: host, Port: portNumber, Username: "feedback", Password: "pw", Plaintext: true}
+
+	entries, err := RunEmailIntake(&Config{Name: "Acme"}, projectDir, imap, IntakeOptions{Area: "voc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	created, followUp := entries[0], entries[1]
+	if entries[0].Outcome != IntakeCreated {
+		created, followUp = entries[1], entries[0]
+	}
+	if created.Outcome != IntakeCreated || followUp.Outcome != IntakeFollowUp || followUp.ItemID != created.ItemID {
+		t.Fatalf("reply should be threaded into the created item: %+v", entries)
+	}
+
+	path, _, err := findFeedbackItemFile(projectDir, created.ItemID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item, err := ParseMarkdownFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Title != "Export do PDF nefunguje" || item.CreatedAt != "2026-10-12" || item.Status != "pending" {
+		t.Errorf("unexpected item %+v", item)
+	}
+	content, _ := os.ReadFile(path)
+	for _, want := range []string{"author: Jana Nováková", "author_email: jana@example.com", "message_id: <m1@example.com>", "> Export do PDF padá", "## Follow-up", "> Still broken in 1.4."} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("item is missing %q:\n%s", want, content)
+		}
+	}
+	if !server.seen[1] || !server.seen[2] {
+		t.Errorf("processed messages must be flagged as seen: %v", server.seen)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, IntakeStateFileName)); err != nil {
+		t.Errorf("intake state not saved: %v", err)
+	}
+
+	// A message seen again (e.g. unflagged by the user) is not imported twice
+	server.mu.Lock()
+	server.seen = map[uint32]bool{}
+	server.mu.Unlock()
+	entries, err = RunEmailIntake(&Config{}, projectDir, imap, IntakeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.Outcome != IntakeDuplicate {
+			t.Errorf("expected duplicates, got %+v", entry)
+		}
+	}
+
+	if _, err := RunEmailIntake(&Config{}, projectDir, imap, IntakeOptions{AutoReply: true}); err == nil {
+		t.Error("--auto-reply without SMTP should fail")
+	}
+}
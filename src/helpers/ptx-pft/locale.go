@@ -0,0 +1,128 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"portunix.ai/portunix/src/helpers/ptx-pft/assets/templates"
+)
+
+// DefaultLanguage is the language of generated content unless configured
+const DefaultLanguage = "en"
+
+// SupportedLanguages are the languages of item bodies, reports and
+// notifications, with a text set in assets/templates/locale/<lang>.json
+var SupportedLanguages = []string{"en", "cs", "de"}
+
+// IsValidLanguage checks if a language is supported
+func IsValidLanguage(lang string) bool {
+	for _, supported := range SupportedLanguages {
+		if lang == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// Locale holds the generated texts of one language
+type Locale struct {
+	Language string
+	texts    map[string]string
+	fallback map[string]string
+}
+
+// GetLocale returns the texts of a language; missing texts and unknown
+// languages fall back to English
+func GetLocale(lang string) *Locale {
+	if !IsValidLanguage(lang) {
+		lang = DefaultLanguage
+	}
+	return &Locale{
+		Language: lang,
+		texts:    loadLocaleTexts(lang),
+		fallback: loadLocaleTexts(DefaultLanguage),
+	}
+}
+
+var localeCache sync.Map // Language -> map[string]string
+
+func loadLocaleTexts(lang string) map[string]string {
+	if texts, ok := localeCache.Load(lang); ok {
+		return texts.(map[string]string)
+	}
+	texts := map[string]string{}
+	data, err := templates.LocaleTemplates.ReadFile("locale/" + lang + ".json")
+	if err == nil {
+		json.Unmarshal(data, &texts)
+	}
+	localeCache.Store(lang, texts)
+	return texts
+}
+
+// T returns the text of a key, formatted with args when given
+func (l *Locale) T(key string, args ...interface{}) string {
+	text, ok := l.texts[key]
+	if !ok {
+		if text, ok = l.fallback[key]; !ok {
+			text = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// localizedHeadings returns a section heading in all supported languages,
+// so files generated in any language are parsed
+func localizedHeadings(key string) []string {
+	var headings []string
+	for _, lang := range SupportedLanguages {
+		heading := GetLocale(lang).T(key)
+		found := false
+		for _, h := range headings {
+			found = found || strings.EqualFold(h, heading)
+		}
+		if !found {
+			headings = append(headings, heading)
+		}
+	}
+	return headings
+}
+
+// isLocalizedHeading checks if a section heading is key in any language
+func isLocalizedHeading(heading, key string) bool {
+	for _, h := range localizedHeadings(key) {
+		if strings.EqualFold(strings.TrimSpace(heading), h) {
+			return true
+		}
+	}
+	return false
+}
+
+// localizedSection returns the trimmed content of the "## <heading>"
+// section of key in any language, up to the next heading
+func localizedSection(body, key string) (string, bool) {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		heading, ok := strings.CutPrefix(line, "## ")
+		if !ok || !isLocalizedHeading(heading, key) {
+			continue
+		}
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if strings.HasPrefix(lines[j], "#") {
+				end = j
+				break
+			}
+		}
+		return strings.TrimSpace(strings.Join(lines[i+1:end], "\n")), true
+	}
+	return "", false
+}
@@ -0,0 +1,77 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalesAreComplete(t *testing.T) {
+	english := loadLocaleTexts(DefaultLanguage)
+	for _, lang := range SupportedLanguages {
+		texts := loadLocaleTexts(lang)
+		for key := range english {
+			if texts[key] == "" {
+				t.Errorf("%s: missing %s", lang, key)
+			}
+		}
+		for _, notifyType := range []NotificationType{NotifyVote, NotifyDescription, NotifyAcceptance} {
+			for _, provider := range []string{"email", "chat"} {
+				if _, _, err := GenerateNotification(notifyType, EmailData{Provider: provider, Language: lang, Title: "Dark mode"}); err != nil {
+					t.Errorf("%s/%s/%s: %v", provider, lang, notifyType, err)
+				}
+			}
+		}
+	}
+	if GetLocale("xx").Language != DefaultLanguage {
+		t.Error("unknown languages must fall back to English")
+	}
+}
+
+func TestLocalizedItemBody(t *testing.T) {
+	for lang, heading := range map[string]string{"en": "## Description", "cs": "## Popis", "de": "## Beschreibung"} {
+		content := generateFeedbackMarkdown(FeedbackItemParams{
+			ID: "P01", Title: "Dark mode", Area: "voc", Status: "open",
+			Description: "Night theme.", Verbatim: "Too bright", Language: lang,
+		})
+		if !strings.Contains(content, heading+"\n\nNight theme.") {
+			t.Errorf("%s: missing %q:\n%s", lang, heading, content)
+		}
+
+		// Items are read whatever language they were written in
+		params := parseExistingItem(content)
+		if params.Description != "Night theme." || params.Verbatim != "Too bright" {
+			t.Errorf("%s: unexpected params %+v", lang, params)
+		}
+		item, err := parseMarkdownContent("P01.md", []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.Description != "Night theme." {
+			t.Errorf("%s: unexpected description %q", lang, item.Description)
+		}
+	}
+
+	cs := generateFeedbackMarkdown(FeedbackItemParams{ID: "P01", Title: "Dark mode", Language: "cs"})
+	if !strings.Contains(cs, "## Stav implementace\n\n| Fáze | Stav | Poznámka |") {
+		t.Errorf("unexpected Czech body:\n%s", cs)
+	}
+}
+
+func TestLocalizedReport(t *testing.T) {
+	items := []FeedbackItem{{ID: "P01", Title: "Dark mode", Status: "open", ExternalID: "7"}}
+	var report strings.Builder
+	l := GetLocale("de")
+	generateSummaryReport(&report, l, items, nil)
+	generateStatusReport(&report, l, items)
+	generateSLAReport(&report, l, []SLACheck{{Area: "voc", ItemID: "P01", Title: "Dark mode", Target: "triage", State: SLABreached, Days: 2, Deadline: time.Now()}})
+	for _, want := range []string{"## Zusammenfassung", "1 Einträge", "Mit Fider synchronisiert: 1", "| P01 | Dark mode |  | open | - | Ja |", "überschritten, 2 T überfällig"} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("report is missing %q:\n%s", want, report.String())
+		}
+	}
+}
@@ -8,13 +8,24 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
+	"portunix.ai/app/journal"
+	"portunix.ai/app/secret"
+	"portunix.ai/app/tui"
+	"portunix.ai/portunix/pkg/logging"
 )
 
 var version = "dev"
@@ -100,6 +111,8 @@ func handleCommand(args []string) {
 		fmt.Println("Portunix Product Feedback Tool Helper")
 	case "--list-commands":
 		fmt.Println("pft")
+	case "--help-ai":
+		showHelpAI()
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
 		fmt.Println("Supported commands: pft")
@@ -127,13 +140,17 @@ func showPFTHelp() {
 	fmt.Println()
 	fmt.Println("Infrastructure:")
 	fmt.Println("  deploy                   - Deploy feedback tool to container")
+	fmt.Println("  deploy --remote <host>   - Deploy feedback tool to a host over SSH")
 	fmt.Println("  status                   - Check feedback tool status")
+	fmt.Println("  status --json            - Health probes and response times as JSON")
 	fmt.Println("  destroy                  - Remove feedback tool instance")
+	fmt.Println("  upgrade                  - Upgrade feedback tool images with a database backup")
 	fmt.Println()
 	fmt.Println("Synchronization:")
 	fmt.Println("  sync                     - Full bidirectional sync")
 	fmt.Println("  pull                     - Pull from external system")
 	fmt.Println("  push                     - Push to external system")
+	fmt.Println("  watch                    - Validate items as they are saved, optionally push")
 	fmt.Println()
 	fmt.Println("User/Customer Registry:")
 	fmt.Println("  user list                - List all users")
@@ -141,13 +158,17 @@ func showPFTHelp() {
 	fmt.Println("  user role <id>           - Assign role to user")
 	fmt.Println("  user link <id>           - Link user to external ID")
 	fmt.Println("  user remove <id>         - Remove user")
+	fmt.Println("  user purge <email>       - Erase a user (GDPR erasure request)")
 	fmt.Println("  role list                - List available roles")
 	fmt.Println("  role init                - Initialize default role files")
 	fmt.Println()
 	fmt.Println("Feedback Management:")
 	fmt.Println("  list                     - List all feedback items")
 	fmt.Println("  add                      - Add new feedback item")
+	fmt.Println("  intake email             - Import feedback emails from an IMAP mailbox")
+	fmt.Println("  intake chat              - Create items from Slack and Teams messages")
 	fmt.Println("  show <id>                - Show feedback details")
+	fmt.Println("  board                    - Interactive kanban board of item statuses")
 	fmt.Println("  link <id> <issue>        - Link feedback to local issue")
 	fmt.Println()
 	fmt.Println("Category Management:")
@@ -164,9 +185,30 @@ func showPFTHelp() {
 	fmt.Println("                           - Remove category from item")
 	fmt.Println("  unassign <item-id> --all - Remove all categories")
 	fmt.Println()
+	fmt.Println("Effort Tracking:")
+	fmt.Println("  estimate <id> <effort>   - Set the estimate of an item, e.g. 3d")
+	fmt.Println("  estimate <id> --actual <effort>")
+	fmt.Println("                           - Record the actual effort")
+	fmt.Println("  report --type effort     - Estimated and actual effort per status and category")
+	fmt.Println("  export --format sprint   - CSV of open items with effort in hours")
+	fmt.Println()
+	fmt.Println("Analysis:")
+	fmt.Println("  analyze --cluster        - Group similar items and propose categories")
+	fmt.Println("  analyze --apply <file>   - Apply approved cluster proposals")
+	fmt.Println("  analyze --score          - Score sentiment and urgency of verbatims")
+	fmt.Println()
+	fmt.Println("SLA Tracking:")
+	fmt.Println("  sla status               - List SLA breaches and upcoming deadlines")
+	fmt.Println("  metrics --listen :9301   - Prometheus metrics of items, sync lag and notifications")
+	fmt.Println()
 	fmt.Println("Reporting:")
 	fmt.Println("  report                   - Generate feedback report")
 	fmt.Println("  export --format=md       - Export to markdown")
+	fmt.Println("  publish --format site    - Generate static feedback portal (roadmap, votes)")
+	fmt.Println("  release-notes --since <tag>")
+	fmt.Println("                           - Customer-facing notes of implemented items")
+	fmt.Println("  share <id> --expires 7d  - Signed read-only page of an item for stakeholders")
+	fmt.Println("  share serve              - Serve share links created with 'share <id> --token'")
 	fmt.Println()
 	fmt.Println("Notifications:")
 	fmt.Println("  notify <id> --user <email> --type <type>")
@@ -175,6 +217,8 @@ func showPFTHelp() {
 	fmt.Println("                           - Notify all VoC users")
 	fmt.Println("  notify <id> --all-vos --type <type>")
 	fmt.Println("                           - Notify all VoS users")
+	fmt.Println("  notify <id> --channel slack:#product --type <type>")
+	fmt.Println("                           - Post to a Slack or Teams channel")
 	fmt.Println()
 	fmt.Println("Available providers: " + strings.Join(ListProviders(), ", "))
 	if len(ListProviders()) == 0 {
@@ -206,12 +250,16 @@ func handlePFTCommand(args []string) {
 		handleStatusCommand(subArgs)
 	case "destroy":
 		handleDestroyCommand(subArgs)
+	case "upgrade":
+		handleUpgradeCommand(subArgs)
 	case "sync":
 		handleSyncCommand(subArgs)
 	case "pull":
 		handlePullCommand(subArgs)
 	case "push":
 		handlePushCommand(subArgs)
+	case "watch":
+		handleWatchCommand(subArgs)
 	case "list":
 		handleListCommand(subArgs)
 	case "show":
@@ -226,6 +274,18 @@ func handlePFTCommand(args []string) {
 		handleReportCommand(subArgs)
 	case "export":
 		handleExportCommand(subArgs)
+	case "publish":
+		handlePublishCommand(subArgs)
+	case "release-notes":
+		handleReleaseNotesCommand(subArgs)
+	case "sla":
+		handleSLACommand(subArgs)
+	case "metrics":
+		handleMetricsCommand(subArgs)
+	case "board":
+		handleBoardCommand(subArgs)
+	case "intake":
+		handleIntakeCommand(subArgs)
 	case "cache":
 		handleCacheCommand(subArgs)
 	case "notify":
@@ -240,6 +300,14 @@ func handlePFTCommand(args []string) {
 		handleAssignCommand(subArgs)
 	case "unassign":
 		handleUnassignCommand(subArgs)
+	case "estimate":
+		handleEstimateCommand(subArgs)
+	case "share":
+		handleShareCommand(subArgs)
+	case "analyze":
+		handleAnalyzeCommand(subArgs)
+	case "--help-ai":
+		showHelpAI()
 	case "--help", "-h":
 		showPFTHelp()
 	default:
@@ -252,8 +320,18 @@ func handlePFTCommand(args []string) {
 func handleConfigureCommand(args []string) {
 	// Parse flags
 	var name, path, area, provider, url, token, projectID string
+	var discourseCategory, discourseUser, statusSync string
+	var slaTriage, slaResolve, slaWarn int
+	var imapHost, imapUser, imapPass, imapMailbox string
+	var imapPort int
+	var slackWebhook, slackToken, slackChannel, slackSigning, teamsWebhook, teamsSecret string
+	var slaCalendar, slaBusiness bool
+	var idPrefix, language string
+	idPadding := -1
 	var smtpHost, smtpUser, smtpPass, smtpFrom string
 	var smtpPort int
+	var aiProvider, aiURL, aiModel, aiChatModel, aiKey string
+	var shareURL string
 	var showConfig, fixPaths bool
 
 	for i := 0; i < len(args); i++ {
@@ -295,6 +373,21 @@ func handleConfigureCommand(args []string) {
 				projectID = args[i+1]
 				i++
 			}
+		case "--discourse-category":
+			if i+1 < len(args) {
+				discourseCategory = args[i+1]
+				i++
+			}
+		case "--discourse-user":
+			if i+1 < len(args) {
+				discourseUser = args[i+1]
+				i++
+			}
+		case "--status-sync":
+			if i+1 < len(args) {
+				statusSync = args[i+1]
+				i++
+			}
 		case "--smtp-host":
 			if i+1 < len(args) {
 				smtpHost = args[i+1]
@@ -320,6 +413,121 @@ func handleConfigureCommand(args []string) {
 				smtpFrom = args[i+1]
 				i++
 			}
+		case "--ai-provider":
+			if i+1 < len(args) {
+				aiProvider = args[i+1]
+				i++
+			}
+		case "--ai-url":
+			if i+1 < len(args) {
+				aiURL = args[i+1]
+				i++
+			}
+		case "--ai-model":
+			if i+1 < len(args) {
+				aiModel = args[i+1]
+				i++
+			}
+		case "--ai-chat-model":
+			if i+1 < len(args) {
+				aiChatModel = args[i+1]
+				i++
+			}
+		case "--ai-key":
+			if i+1 < len(args) {
+				aiKey = args[i+1]
+				i++
+			}
+		case "--share-url":
+			if i+1 < len(args) {
+				shareURL = args[i+1]
+				i++
+			}
+		case "--imap-host":
+			if i+1 < len(args) {
+				imapHost = args[i+1]
+				i++
+			}
+		case "--imap-port":
+			if i+1 < len(args) {
+				fmt.Sscanf(args[i+1], "%d", &imapPort)
+				i++
+			}
+		case "--imap-user":
+			if i+1 < len(args) {
+				imapUser = args[i+1]
+				i++
+			}
+		case "--imap-pass":
+			if i+1 < len(args) {
+				imapPass = args[i+1]
+				i++
+			}
+		case "--imap-mailbox":
+			if i+1 < len(args) {
+				imapMailbox = args[i+1]
+				i++
+			}
+		case "--slack-webhook", "--slack-token", "--slack-channel", "--slack-signing-secret", "--teams-webhook", "--teams-secret":
+			if i+1 < len(args) {
+				value := args[i+1]
+				switch args[i] {
+				case "--slack-webhook":
+					slackWebhook = value
+				case "--slack-token":
+					slackToken = value
+				case "--slack-channel":
+					slackChannel = value
+				case "--slack-signing-secret":
+					slackSigning = value
+				case "--teams-webhook":
+					teamsWebhook = value
+				case "--teams-secret":
+					teamsSecret = value
+				}
+				i++
+			}
+		case "--sla-triage-days", "--sla-resolve-days", "--sla-warn-days":
+			if i+1 < len(args) {
+				days, err := strconv.Atoi(args[i+1])
+				if err != nil || days < 0 {
+					fmt.Printf("Invalid %s value '%s'\n", args[i], args[i+1])
+					return
+				}
+				switch args[i] {
+				case "--sla-triage-days":
+					slaTriage = days
+				case "--sla-resolve-days":
+					slaResolve = days
+				default:
+					slaWarn = days
+				}
+				i++
+			}
+		case "--language":
+			if i+1 < len(args) {
+				language = args[i+1]
+				i++
+			}
+		case "--id-prefix":
+			if i+1 < len(args) {
+				idPrefix = args[i+1]
+				i++
+			}
+		case "--id-padding":
+			if i+1 < len(args) {
+				padding, err := strconv.Atoi(args[i+1])
+				if err != nil {
+					fmt.Printf("Invalid --id-padding value '%s'\n", args[i+1])
+					return
+				}
+				idPadding = padding
+				i++
+			}
+		case "--sla-calendar-days":
+			slaCalendar = true
+		case "--sla-business-days":
+			slaBusiness = true
 		case "--show":
 			showConfig = true
 		case "--help", "-h":
@@ -346,15 +554,51 @@ func handleConfigureCommand(args []string) {
 		return
 	}
 
+	// IMAP configuration for 'pft intake email'
+	if imapHost != "" || imapPort > 0 || imapUser != "" || imapPass != "" || imapMailbox != "" {
+		updateIMAPConfig(path, imapHost, imapPort, imapUser, imapPass, imapMailbox)
+		return
+	}
+
+	// Slack and Teams channels
+	if slackWebhook != "" || slackToken != "" || slackChannel != "" || slackSigning != "" || teamsWebhook != "" || teamsSecret != "" {
+		updateChatConfig(path, slackWebhook, slackToken, slackChannel, slackSigning, teamsWebhook, teamsSecret)
+		return
+	}
+
+	// AI configuration for 'pft analyze'
+	if aiProvider != "" || aiURL != "" || aiModel != "" || aiChatModel != "" || aiKey != "" {
+		updateAIConfig(path, aiProvider, aiURL, aiModel, aiChatModel, aiKey)
+		return
+	}
+
+	// Public address of 'pft share serve'
+	if shareURL != "" {
+		updateShareConfig(path, shareURL)
+		return
+	}
+
+	// Per-area SLA policy
+	if area != "" && (slaTriage > 0 || slaResolve > 0 || slaWarn > 0 || slaCalendar || slaBusiness) {
+		updateSLAConfig(path, area, slaTriage, slaResolve, slaWarn, slaCalendar, slaBusiness)
+		return
+	}
+
+	// Per-area ID scheme
+	if area != "" && (idPrefix != "" || idPadding >= 0) {
+		updateIDSchemeConfig(path, area, idPrefix, idPadding)
+		return
+	}
+
 	// Per-area configuration
 	if area != "" {
-		updateAreaConfig(path, area, provider, url, token, projectID)
+		updateAreaConfig(path, area, provider, url, token, projectID, discourseCategory, discourseUser, statusSync)
 		return
 	}
 
-	// Global configuration (name, path)
-	if name != "" || path != "" {
-		updateGlobalConfig(name, path)
+	// Global configuration (name, path, language)
+	if name != "" || path != "" || language != "" {
+		updateGlobalConfig(name, path, language)
 		return
 	}
 
@@ -368,15 +612,30 @@ func showConfigureHelp() {
 	fmt.Println("Global options:")
 	fmt.Println("  --name <name>         Set product name")
 	fmt.Println("  --path <path>         Set path to local documents")
+	fmt.Println("  --language <lang>     Language of item bodies, reports and notifications (en, cs, de)")
 	fmt.Println("  --show                Show current configuration")
 	fmt.Println("  --fix-paths           Convert absolute paths to relative for cross-platform use")
 	fmt.Println()
 	fmt.Println("Per-area options (requires --area):")
 	fmt.Println("  --area <area>         Target area (voc, vos, vob, voe)")
-	fmt.Println("  --provider <type>     Set provider (fider, clearflask, eververse, local)")
+	fmt.Println("  --provider <type>     Set provider (fider, clearflask, eververse, discourse, local)")
 	fmt.Println("  --url <url>           Set provider endpoint URL")
 	fmt.Println("  --token <token>       Set API token")
 	fmt.Println("  --project-id <id>     Set project ID (for ClearFlask)")
+	fmt.Println("  --discourse-category <slug|id>  Discourse category mapped to the area")
+	fmt.Println("  --discourse-user <name>         Discourse user the API key acts as (default: system)")
+	fmt.Println("  --status-sync <mode>            Push statuses to Discourse as tags (default), reply or both")
+	fmt.Println()
+	fmt.Println("ID options (requires --area):")
+	fmt.Println("  --id-prefix <letters>   Prefix of new item IDs, e.g. UC for UC001 (default: P)")
+	fmt.Println("  --id-padding <n>        Minimum number of digits, 1-9 (default: 2)")
+	fmt.Println()
+	fmt.Println("SLA options (requires --area, see 'pft sla'):")
+	fmt.Println("  --sla-triage-days <n>   Items must leave the open status within n days")
+	fmt.Println("  --sla-resolve-days <n>  Items must be implemented or declined within n days")
+	fmt.Println("  --sla-warn-days <n>     Report deadlines within n days as upcoming (default: 2)")
+	fmt.Println("  --sla-calendar-days     Count calendar days")
+	fmt.Println("  --sla-business-days     Count business days, Monday to Friday (default)")
 	fmt.Println()
 	fmt.Println("SMTP options:")
 	fmt.Println("  --smtp-host <host>    SMTP server hostname")
@@ -385,10 +644,38 @@ func showConfigureHelp() {
 	fmt.Println("  --smtp-pass <pass>    SMTP password")
 	fmt.Println("  --smtp-from <email>   Sender email address")
 	fmt.Println()
+	fmt.Println("IMAP options (pft intake email):")
+	fmt.Println("  --imap-host <host>    IMAP server hostname")
+	fmt.Println("  --imap-port <port>    IMAP server port (default: 993)")
+	fmt.Println("  --imap-user <user>    IMAP username")
+	fmt.Println("  --imap-pass <pass>    IMAP password (stored in the portunix secret store)")
+	fmt.Println("  --imap-mailbox <box>  Mailbox to poll (default: INBOX)")
+	fmt.Println()
+	fmt.Println("Chat options (pft notify --channel, pft intake chat):")
+	fmt.Println("  --slack-webhook <url>         Slack incoming webhook of the default channel")
+	fmt.Println("  --slack-token <token>         Slack bot token, posts to any channel")
+	fmt.Println("  --slack-channel <#channel>    Default Slack channel")
+	fmt.Println("  --slack-signing-secret <s>    Verifies slash command requests")
+	fmt.Println("  --teams-webhook [<name>=]<url> Teams incoming webhook (default or named channel)")
+	fmt.Println("  --teams-secret <token>        Security token of the Teams outgoing webhook")
+	fmt.Println("  Webhooks, tokens and secrets are stored in the portunix secret store.")
+	fmt.Println()
+	fmt.Println("AI options (pft analyze):")
+	fmt.Println("  --ai-provider <type>  Embedding provider (local, ollama, openai)")
+	fmt.Println("  --ai-url <url>        API endpoint (default per provider)")
+	fmt.Println("  --ai-model <model>    Embedding model")
+	fmt.Println("  --ai-chat-model <m>   Model writing cluster summaries")
+	fmt.Println("  --ai-key <key>        API key (stored in the portunix secret store)")
+	fmt.Println()
+	fmt.Println("Share options (pft share):")
+	fmt.Println("  --share-url <url>     Public address of 'pft share serve' used in share links")
+	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  portunix pft configure --name 'MyProduct' --path /tmp/pft")
 	fmt.Println("  portunix pft configure --area voc --provider fider --url http://localhost:3100")
+	fmt.Println("  portunix pft configure --area voc --provider discourse --url https://forum.example.org --discourse-category feature-requests")
 	fmt.Println("  portunix pft configure --smtp-host smtp.example.com --smtp-port 587")
+	fmt.Println("  portunix pft configure --area voc --sla-triage-days 5 --sla-resolve-days 60")
 	fmt.Println()
 	fmt.Println("Without options, runs an interactive configuration wizard.")
 }
@@ -405,6 +692,7 @@ func showCurrentConfig(configPath string) {
 	fmt.Println()
 	fmt.Printf("  Product Name: %s\n", config.Name)
 	fmt.Printf("  Document Path: %s\n", config.Path)
+	fmt.Printf("  Language: %s\n", config.GetLanguage())
 	fmt.Println()
 
 	// Show per-area configuration
@@ -425,15 +713,29 @@ func showCurrentConfig(configPath string) {
 			if area.cfg.URL != "" {
 				fmt.Printf("    URL: %s\n", area.cfg.URL)
 			}
-			if area.cfg.APIToken != "" {
+			if secret.IsReference(area.cfg.APIToken) {
+				fmt.Printf("    API Token: %s\n", area.cfg.APIToken)
+			} else if area.cfg.APIToken != "" {
 				fmt.Printf("    API Token: %s***\n", area.cfg.APIToken[:min(4, len(area.cfg.APIToken))])
 			}
 			if area.cfg.ProjectID != "" {
 				fmt.Printf("    Project ID: %s\n", area.cfg.ProjectID)
 			}
+			if area.cfg.Category != "" {
+				fmt.Printf("    Category: %s\n", area.cfg.Category)
+			}
+			if area.cfg.StatusSync != "" {
+				fmt.Printf("    Status Sync: %s\n", area.cfg.StatusSync)
+			}
 		} else {
 			fmt.Printf("  %s: local (no external sync)\n", area.name)
 		}
+		if area.cfg != nil && area.cfg.IDScheme != nil {
+			fmt.Printf("    IDs: %s, %s, ...\n", area.cfg.IDScheme.Format(1), area.cfg.IDScheme.Format(2))
+		}
+		if area.cfg != nil && area.cfg.SLA != nil {
+			fmt.Printf("    SLA: %s\n", area.cfg.SLA)
+		}
 	}
 
 	// Show SMTP configuration
@@ -447,6 +749,78 @@ func showCurrentConfig(configPath string) {
 		}
 	}
 
+	// Show IMAP configuration
+	if config.IMAP != nil && config.IMAP.Host != "" {
+		fmt.Println()
+		fmt.Println("  IMAP:")
+		fmt.Printf("    Host: %s:%d\n", config.IMAP.Host, config.IMAP.Port)
+		if config.IMAP.Username != "" {
+			fmt.Printf("    Username: %s\n", config.IMAP.Username)
+		}
+		if config.IMAP.Mailbox != "" {
+			fmt.Printf("    Mailbox: %s\n", config.IMAP.Mailbox)
+		}
+	}
+
+	// Show chat configuration
+	if config.Chat != nil {
+		if slack := config.Chat.Slack; slack != nil {
+			fmt.Println()
+			fmt.Println("  Slack:")
+			fmt.Printf("    Webhook: %v, Bot token: %v, Signing secret: %v\n", slack.WebhookURL != "", slack.BotToken != "", slack.SigningSecret != "")
+			if slack.DefaultChannel != "" {
+				fmt.Printf("    Default channel: %s\n", slack.DefaultChannel)
+			}
+		}
+		if teams := config.Chat.Teams; teams != nil {
+			fmt.Println()
+			fmt.Println("  Teams:")
+			fmt.Printf("    Default webhook: %v, Security token: %v\n", teams.WebhookURL != "", teams.OutgoingSecret != "")
+			names := make([]string, 0, len(teams.Webhooks))
+			for name := range teams.Webhooks {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Printf("    Channel: %s\n", name)
+			}
+		}
+	}
+
+	// Show AI configuration
+	if config.AI != nil && config.AI.Provider != "" {
+		fmt.Println()
+		fmt.Println("  AI:")
+		fmt.Printf("    Provider: %s\n", config.AI.Provider)
+		if config.AI.URL != "" {
+			fmt.Printf("    URL: %s\n", config.AI.URL)
+		}
+		if config.AI.Model != "" {
+			fmt.Printf("    Model: %s\n", config.AI.Model)
+		}
+		if config.AI.ChatModel != "" {
+			fmt.Printf("    Chat model: %s\n", config.AI.ChatModel)
+		}
+		if secret.IsReference(config.AI.APIKey) {
+			fmt.Printf("    API Key: %s\n", config.AI.APIKey)
+		} else if config.AI.APIKey != "" {
+			fmt.Printf("    API Key: %s***\n", config.AI.APIKey[:min(4, len(config.AI.APIKey))])
+		}
+	}
+
+	if config.Share != nil {
+		fmt.Println()
+		fmt.Println("  Share:")
+		if config.Share.URL != "" {
+			fmt.Printf("    URL: %s\n", config.Share.URL)
+		}
+		if secret.IsReference(config.Share.Key) {
+			fmt.Printf("    Key: %s\n", config.Share.Key)
+		} else if config.Share.Key != "" {
+			fmt.Println("    Key: (stored in the configuration file)")
+		}
+	}
+
 	fmt.Println()
 	fmt.Println("Sync settings:")
 	fmt.Printf("  Auto sync: %v\n", config.Sync.Auto)
@@ -455,7 +829,12 @@ func showCurrentConfig(configPath string) {
 }
 
 // updateGlobalConfig updates global settings (name, path)
-func updateGlobalConfig(name, path string) {
+func updateGlobalConfig(name, path, language string) {
+	if language != "" && !IsValidLanguage(language) {
+		fmt.Printf("Invalid language '%s'. Valid options: %s\n", language, strings.Join(SupportedLanguages, ", "))
+		return
+	}
+
 	config, _, err := loadOrCreateConfig(path)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
@@ -473,11 +852,16 @@ func updateGlobalConfig(name, path string) {
 		fmt.Printf("Product name set to: %s\n", name)
 	}
 
+	if language != "" {
+		config.Language = language
+		fmt.Printf("Language of generated content set to: %s\n", language)
+	}
+
 	saveConfig(config)
 }
 
 // updateAreaConfig updates configuration for a specific area
-func updateAreaConfig(configPath, area, provider, url, token, projectID string) {
+func updateAreaConfig(configPath, area, provider, url, token, projectID, discourseCategory, discourseUser, statusSync string) {
 	// Validate area
 	if !IsValidArea(area) {
 		fmt.Printf("Invalid area '%s'. Valid options: voc, vos, vob, voe\n", area)
@@ -486,7 +870,7 @@ func updateAreaConfig(configPath, area, provider, url, token, projectID string)
 
 	// Validate provider if specified
 	if provider != "" {
-		validProviders := []string{"fider", "clearflask", "eververse", "local"}
+		validProviders := []string{"fider", "clearflask", "eververse", "discourse", "local"}
 		isValid := false
 		for _, p := range validProviders {
 			if provider == p {
@@ -495,10 +879,16 @@ func updateAreaConfig(configPath, area, provider, url, token, projectID string)
 			}
 		}
 		if !isValid {
-			fmt.Printf("Invalid provider '%s'. Valid options: fider, clearflask, eververse, local\n", provider)
+			fmt.Printf("Invalid provider '%s'. Valid options: fider, clearflask, eververse, discourse, local\n", provider)
 			return
 		}
 	}
+	switch statusSync {
+	case "", DiscourseStatusTags, DiscourseStatusReply, DiscourseStatusBoth:
+	default:
+		fmt.Printf("Invalid status sync '%s'. Valid options: tags, reply, both\n", statusSync)
+		return
+	}
 
 	config, _, err := loadOrCreateConfig(configPath)
 	if err != nil {
@@ -522,13 +912,25 @@ func updateAreaConfig(configPath, area, provider, url, token, projectID string)
 		fmt.Printf("Area %s URL set to: %s\n", area, url)
 	}
 	if token != "" {
-		areaCfg.APIToken = token
+		areaCfg.APIToken = storeToken(config, area, token)
 		fmt.Printf("Area %s API token updated\n", area)
 	}
 	if projectID != "" {
 		areaCfg.ProjectID = projectID
 		fmt.Printf("Area %s project ID set to: %s\n", area, projectID)
 	}
+	if discourseCategory != "" {
+		areaCfg.Category = discourseCategory
+		fmt.Printf("Area %s Discourse category set to: %s\n", area, discourseCategory)
+	}
+	if discourseUser != "" {
+		areaCfg.Username = discourseUser
+		fmt.Printf("Area %s Discourse user set to: %s\n", area, discourseUser)
+	}
+	if statusSync != "" {
+		areaCfg.StatusSync = statusSync
+		fmt.Printf("Area %s status sync set to: %s\n", area, statusSync)
+	}
 
 	// Set the area config
 	config.SetAreaConfig(area, areaCfg)
@@ -536,6 +938,82 @@ func updateAreaConfig(configPath, area, provider, url, token, projectID string)
 	saveConfig(config)
 }
 
+// updateSLAConfig updates the SLA policy of an area
+func updateSLAConfig(configPath, area string, triageDays, resolveDays, warnDays int, calendar, business bool) {
+	if !IsValidArea(area) {
+		fmt.Printf("Invalid area '%s'. Valid options: voc, vos, vob, voe\n", area)
+		return
+	}
+
+	config, _, err := loadOrCreateConfig(configPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	areaCfg := config.GetAreaConfig(area)
+	if areaCfg == nil {
+		areaCfg = &AreaConfig{}
+	}
+	if areaCfg.SLA == nil {
+		areaCfg.SLA = &SLAPolicy{}
+	}
+	if triageDays > 0 {
+		areaCfg.SLA.TriageDays = triageDays
+	}
+	if resolveDays > 0 {
+		areaCfg.SLA.ResolveDays = resolveDays
+	}
+	if warnDays > 0 {
+		areaCfg.SLA.WarnDays = warnDays
+	}
+	if calendar {
+		areaCfg.SLA.CalendarDays = true
+	} else if business {
+		areaCfg.SLA.CalendarDays = false
+	}
+	config.SetAreaConfig(area, areaCfg)
+	fmt.Printf("Area %s SLA: %s\n", area, areaCfg.SLA)
+
+	saveConfig(config)
+}
+
+// updateIDSchemeConfig updates the ID scheme of an area
+func updateIDSchemeConfig(configPath, area, prefix string, padding int) {
+	if !IsValidArea(area) {
+		fmt.Printf("Invalid area '%s'. Valid options: voc, vos, vob, voe\n", area)
+		return
+	}
+
+	config, _, err := loadOrCreateConfig(configPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	scheme := config.GetIDScheme(area)
+	if prefix != "" {
+		scheme.Prefix = prefix
+	}
+	if padding >= 0 {
+		scheme.Padding = padding
+	}
+	if err := ValidateIDScheme(scheme); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	areaCfg := config.GetAreaConfig(area)
+	if areaCfg == nil {
+		areaCfg = &AreaConfig{}
+	}
+	areaCfg.IDScheme = &scheme
+	config.SetAreaConfig(area, areaCfg)
+	fmt.Printf("Area %s IDs: %s (next numbers continue after existing %s items)\n", area, scheme.Format(1), scheme.Prefix)
+
+	saveConfig(config)
+}
+
 // updateSMTPConfig updates SMTP server configuration
 func updateSMTPConfig(configPath, host string, port int, user, pass, from string) {
 	config, _, err := loadOrCreateConfig(configPath)
@@ -561,7 +1039,7 @@ func updateSMTPConfig(configPath, host string, port int, user, pass, from string
 		fmt.Printf("SMTP username set to: %s\n", user)
 	}
 	if pass != "" {
-		config.SMTP.Password = pass
+		config.SMTP.Password = storeSecret(secretName(config, "smtp"), pass)
 		fmt.Println("SMTP password updated")
 	}
 	if from != "" {
@@ -572,75 +1050,232 @@ func updateSMTPConfig(configPath, host string, port int, user, pass, from string
 	saveConfig(config)
 }
 
-// loadOrCreateConfig loads existing config or creates a new one
-// Returns: config, configFilePath (path to .pft-config.json), error
-func loadOrCreateConfig(path string) (*Config, string, error) {
-	var config *Config
-	var configFilePath string
-
-	if path != "" {
-		absPath, err := filepath.Abs(path)
-		if err != nil {
-			return nil, "", fmt.Errorf("error resolving path: %w", err)
-		}
-		configFilePath = filepath.Join(absPath, ConfigFileName)
-		if _, statErr := os.Stat(configFilePath); statErr == nil {
-			config, _ = LoadConfigFromPath(configFilePath)
-		}
-		if config == nil {
-			config = NewDefaultConfig()
-			config.Path = absPath
-		}
-	} else {
-		// Try to find config in current or parent directories
-		foundPath, err := findConfigFile()
-		if err == nil {
-			configFilePath = foundPath
-			config, _ = LoadConfigFromPath(configFilePath)
-		}
-		if config == nil {
-			config = NewDefaultConfig()
-			// Set configFilePath to current directory for new configs
-			cwd, _ := os.Getwd()
-			configFilePath = filepath.Join(cwd, ConfigFileName)
-		}
+// updateIMAPConfig updates the mailbox of 'pft intake email'
+func updateIMAPConfig(configPath, host string, port int, user, pass, mailbox string) {
+	config, _, err := loadOrCreateConfig(configPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
 	}
 
-	return config, configFilePath, nil
-}
+	if config.IMAP == nil {
+		config.IMAP = &IMAPConfig{Port: 993}
+	}
 
-// saveConfig saves configuration to the appropriate path
-func saveConfig(config *Config) {
-	savePath := config.Path
-	if savePath == "" {
-		savePath, _ = os.Getwd()
+	if host != "" {
+		config.IMAP.Host = host
+		fmt.Printf("IMAP host set to: %s\n", host)
 	}
-	if err := config.Save(savePath); err != nil {
-		fmt.Printf("Error saving configuration: %v\n", err)
-		return
+	if port > 0 {
+		config.IMAP.Port = port
+		fmt.Printf("IMAP port set to: %d\n", port)
 	}
-	fmt.Printf("\nConfiguration saved to %s\n", GetConfigPath(savePath))
+	if user != "" {
+		config.IMAP.Username = user
+		fmt.Printf("IMAP username set to: %s\n", user)
+	}
+	if pass != "" {
+		config.IMAP.Password = storeSecret(secretName(config, "imap"), pass)
+		fmt.Println("IMAP password updated")
+	}
+	if mailbox != "" {
+		config.IMAP.Mailbox = mailbox
+		fmt.Printf("IMAP mailbox set to: %s\n", mailbox)
+	}
+
+	saveConfig(config)
 }
 
-// fixConfigPaths converts absolute paths to empty/relative for cross-platform compatibility
-func fixConfigPaths(configPath string) {
-	config, configFilePath, err := loadOrCreateConfig(configPath)
+// updateChatConfig updates the Slack and Teams channels
+func updateChatConfig(configPath, slackWebhook, slackToken, slackChannel, slackSigning, teamsWebhook, teamsSecret string) {
+	config, _, err := loadOrCreateConfig(configPath)
 	if err != nil {
-		fmt.Printf("Error loading configuration: %v\n", err)
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	if config.Path == "" {
-		fmt.Println("Path is already empty (cross-platform compatible)")
-		return
+	if config.Chat == nil {
+		config.Chat = &ChatConfig{}
 	}
-
-	configDir := filepath.Dir(configFilePath)
-	oldPath := config.Path
-
-	// Check if path is absolute
-	if !filepath.IsAbs(config.Path) {
-		fmt.Printf("Path is already relative: %s\n", config.Path)
+	if slackWebhook != "" || slackToken != "" || slackChannel != "" || slackSigning != "" {
+		if config.Chat.Slack == nil {
+			config.Chat.Slack = &SlackConfig{}
+		}
+		slack := config.Chat.Slack
+		if slackWebhook != "" {
+			slack.WebhookURL = storeSecret(secretName(config, "slack-webhook"), slackWebhook)
+			fmt.Println("Slack webhook updated")
+		}
+		if slackToken != "" {
+			slack.BotToken = storeSecret(secretName(config, "slack-token"), slackToken)
+			fmt.Println("Slack bot token updated")
+		}
+		if slackChannel != "" {
+			slack.DefaultChannel = slackChannel
+			fmt.Printf("Slack default channel set to: %s\n", slackChannel)
+		}
+		if slackSigning != "" {
+			slack.SigningSecret = storeSecret(secretName(config, "slack-signing-secret"), slackSigning)
+			fmt.Println("Slack signing secret updated")
+		}
+	}
+	if teamsWebhook != "" || teamsSecret != "" {
+		if config.Chat.Teams == nil {
+			config.Chat.Teams = &TeamsConfig{}
+		}
+		teams := config.Chat.Teams
+		if teamsWebhook != "" {
+			// A name= prefix (before the URL scheme) selects a named channel
+			name, webhookURL := "", teamsWebhook
+			if eq := strings.Index(teamsWebhook, "="); eq > 0 && eq < strings.Index(teamsWebhook, "://") {
+				name, webhookURL = teamsWebhook[:eq], teamsWebhook[eq+1:]
+			}
+			if name == "" {
+				teams.WebhookURL = storeSecret(secretName(config, "teams-webhook"), webhookURL)
+				fmt.Println("Teams webhook updated")
+			} else {
+				if teams.Webhooks == nil {
+					teams.Webhooks = map[string]string{}
+				}
+				teams.Webhooks[name] = storeSecret(secretName(config, "teams-webhook-"+name), webhookURL)
+				fmt.Printf("Teams webhook of channel '%s' updated\n", name)
+			}
+		}
+		if teamsSecret != "" {
+			teams.OutgoingSecret = storeSecret(secretName(config, "teams-secret"), teamsSecret)
+			fmt.Println("Teams security token updated")
+		}
+	}
+
+	saveConfig(config)
+}
+
+// updateAIConfig updates the models of 'pft analyze'
+func updateAIConfig(configPath, provider, url, model, chatModel, key string) {
+	config, _, err := loadOrCreateConfig(configPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	if config.AI == nil {
+		config.AI = &AIConfig{}
+	}
+
+	if provider != "" {
+		if provider != "local" && provider != "ollama" && provider != "openai" {
+			fmt.Printf("Error: unknown AI provider '%s' (local, ollama, openai)\n", provider)
+			return
+		}
+		config.AI.Provider = provider
+		fmt.Printf("AI provider set to: %s\n", provider)
+	}
+	if url != "" {
+		config.AI.URL = url
+		fmt.Printf("AI URL set to: %s\n", url)
+	}
+	if model != "" {
+		config.AI.Model = model
+		fmt.Printf("AI embedding model set to: %s\n", model)
+	}
+	if chatModel != "" {
+		config.AI.ChatModel = chatModel
+		fmt.Printf("AI chat model set to: %s\n", chatModel)
+	}
+	if key != "" {
+		config.AI.APIKey = storeSecret(secretName(config, "ai"), key)
+		fmt.Println("AI API key updated")
+	}
+
+	saveConfig(config)
+}
+
+// updateShareConfig sets the public address of 'pft share serve'
+func updateShareConfig(configPath, url string) {
+	config, _, err := loadOrCreateConfig(configPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	if config.Share == nil {
+		config.Share = &ShareConfig{}
+	}
+	config.Share.URL = strings.TrimRight(url, "/")
+	fmt.Printf("Share URL set to: %s\n", config.Share.URL)
+
+	saveConfig(config)
+}
+
+// loadOrCreateConfig loads existing config or creates a new one
+// Returns: config, configFilePath (path to .pft-config.json), error
+func loadOrCreateConfig(path string) (*Config, string, error) {
+	var config *Config
+	var configFilePath string
+
+	if path != "" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("error resolving path: %w", err)
+		}
+		configFilePath = filepath.Join(absPath, ConfigFileName)
+		if _, statErr := os.Stat(configFilePath); statErr == nil {
+			config, _ = LoadConfigFromPath(configFilePath)
+		}
+		if config == nil {
+			config = NewDefaultConfig()
+			config.Path = absPath
+		}
+	} else {
+		// Try to find config in current or parent directories
+		foundPath, err := findConfigFile()
+		if err == nil {
+			configFilePath = foundPath
+			config, _ = LoadConfigFromPath(configFilePath)
+		}
+		if config == nil {
+			config = NewDefaultConfig()
+			// Set configFilePath to current directory for new configs
+			cwd, _ := os.Getwd()
+			configFilePath = filepath.Join(cwd, ConfigFileName)
+		}
+	}
+
+	return config, configFilePath, nil
+}
+
+// saveConfig saves configuration to the appropriate path
+func saveConfig(config *Config) {
+	savePath := config.Path
+	if savePath == "" {
+		savePath, _ = os.Getwd()
+	}
+	if err := config.Save(savePath); err != nil {
+		fmt.Printf("Error saving configuration: %v\n", err)
+		return
+	}
+	fmt.Printf("\nConfiguration saved to %s\n", GetConfigPath(savePath))
+}
+
+// fixConfigPaths converts absolute paths to empty/relative for cross-platform compatibility
+func fixConfigPaths(configPath string) {
+	config, configFilePath, err := loadOrCreateConfig(configPath)
+	if err != nil {
+		fmt.Printf("Error loading configuration: %v\n", err)
+		return
+	}
+
+	if config.Path == "" {
+		fmt.Println("Path is already empty (cross-platform compatible)")
+		return
+	}
+
+	configDir := filepath.Dir(configFilePath)
+	oldPath := config.Path
+
+	// Check if path is absolute
+	if !filepath.IsAbs(config.Path) {
+		fmt.Printf("Path is already relative: %s\n", config.Path)
 		return
 	}
 
@@ -715,7 +1350,7 @@ func runConfigureWizard() {
 
 	fmt.Println()
 	fmt.Println("Configure areas (VoC, VoS, VoB, VoE):")
-	fmt.Println("  Available providers: fider, clearflask, eververse, local")
+	fmt.Println("  Available providers: fider, clearflask, eververse, discourse, local")
 	fmt.Println()
 
 	// Configure each area
@@ -765,7 +1400,15 @@ func runConfigureWizard() {
 			(*area.config).Provider = provider
 			(*area.config).URL = url
 			if token != "" {
-				(*area.config).APIToken = token
+				(*area.config).APIToken = storeToken(config, area.name, token)
+			}
+
+			if provider == "discourse" {
+				fmt.Printf("  Discourse category [%s]: ", (*area.config).Category)
+				categoryInput, _ := reader.ReadString('\n')
+				if category := strings.TrimSpace(categoryInput); category != "" {
+					(*area.config).Category = category
+				}
 			}
 		} else {
 			// Local provider - clear external config
@@ -796,14 +1439,55 @@ func runConfigureWizard() {
 
 // Infrastructure command handlers
 func handleDeployCommand(args []string) {
-	config, err := LoadConfig()
+	var remoteHost, publicURL string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--remote":
+			if i+1 < len(args) {
+				remoteHost = args[i+1]
+				i++
+			}
+		case "--url":
+			if i+1 < len(args) {
+				publicURL = args[i+1]
+				i++
+			}
+		case "--help", "-h":
+			showDeployHelp()
+			return
+		}
+	}
+
+	config, configFilePath, err := LoadConfigWithFilePath()
 	if err != nil {
 		fmt.Println("No configuration found. Run 'portunix pft configure' first.")
 		return
 	}
 
+	if publicURL != "" && remoteHost == "" {
+		fmt.Println("Error: --url requires --remote")
+		return
+	}
+	if publicURL != "" {
+		if u, err := url.Parse(publicURL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			fmt.Printf("Error: invalid URL: %s\n", publicURL)
+			return
+		}
+	}
+
 	var result *DeployResult
 
+	if remoteHost != "" {
+		result, err = DeployRemote(config, configFilePath, remoteHost, publicURL)
+		if err != nil {
+			fmt.Printf("Deployment failed: %v\n", err)
+			return
+		}
+		fmt.Println()
+		fmt.Println(result.Message)
+		return
+	}
+
 	switch config.GetProvider() {
 	case "fider":
 		result, err = Deploy(config)
@@ -828,7 +1512,42 @@ func handleDeployCommand(args []string) {
 	fmt.Println(result.Message)
 }
 
+func showDeployHelp() {
+	fmt.Println("Usage: portunix pft deploy [options]")
+	fmt.Println()
+	fmt.Println("Deploy the configured feedback tool with container compose.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --remote <host>          Deploy to a host over SSH instead of this machine")
+	fmt.Println("                           (user@host[:port], fleet host or VM name)")
+	fmt.Println("  --url <url>              Public URL of the remote deployment, e.g. behind")
+	fmt.Println("                           a reverse proxy (default: http://<host>:<port>)")
+	fmt.Println()
+	fmt.Println("With --remote, the compose files are copied to ~/.portunix/pft/<provider>")
+	fmt.Println("on the host and started with portunix there. Configured localhost URLs")
+	fmt.Println("and the SMTP host are rewritten to the remote address and saved.")
+	fmt.Println("Secrets of each host are kept in ~/.portunix/pft/remote/<host>.")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft deploy")
+	fmt.Println("  portunix pft deploy --remote admin@feedback.example.com")
+	fmt.Println("  portunix pft deploy --remote vps1 --url https://feedback.example.com")
+}
+
 func handleStatusCommand(args []string) {
+	jsonOutput, watch := false, false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOutput = true
+		case "--watch", "-w":
+			watch = true
+		case "--help", "-h":
+			showStatusHelp()
+			return
+		}
+	}
+
 	// First check if we have a config
 	config, err := LoadConfig()
 	if err != nil {
@@ -836,6 +1555,28 @@ func handleStatusCommand(args []string) {
 		return
 	}
 
+	if watch {
+		if err := WatchStatus(config, jsonOutput); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if jsonOutput {
+		report := BuildStatusReport(config)
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Printf("Error creating JSON: %v\n", err)
+			return
+		}
+		fmt.Println(string(data))
+		if !report.Healthy {
+			exitUnhealthy()
+		}
+		return
+	}
+
 	fmt.Println("Product Feedback Tool Status")
 	fmt.Println("============================")
 	fmt.Printf("Product: %s\n", config.Name)
@@ -847,7 +1588,15 @@ func handleStatusCommand(args []string) {
 	}
 	fmt.Println()
 
-	switch config.GetProvider() {
+	provider := config.GetProvider()
+	if config.Remote != "" {
+		fmt.Printf("Infrastructure: Deployed to %s\n", config.Remote)
+		provider = "remote"
+	}
+
+	switch provider {
+	case "remote":
+		// Containers run on the remote host; only the health probes apply
 	case "fider":
 		status, err := GetStatus()
 		if err != nil {
@@ -959,6 +1708,145 @@ func handleStatusCommand(args []string) {
 	default:
 		fmt.Printf("Infrastructure status for '%s': Not implemented\n", config.GetProvider())
 	}
+
+	probes := CheckHealth(config)
+	if len(probes) == 0 {
+		return
+	}
+	healthy := true
+	fmt.Println()
+	fmt.Println("Health:")
+	for _, p := range probes {
+		if p.Healthy {
+			fmt.Printf("  ✓ %-5s %-10s %s (HTTP %d, %d ms)\n", p.Name, p.Provider, p.URL, p.StatusCode, p.ResponseTimeMs)
+		} else {
+			fmt.Printf("  ✗ %-5s %-10s %s (%s, %d ms)\n", p.Name, p.Provider, p.URL, p.Error, p.ResponseTimeMs)
+			healthy = false
+		}
+	}
+	if !healthy {
+		exitUnhealthy()
+	}
+}
+
+// exitUnhealthy exits with status 1, so monitoring scripts can alert on a
+// broken feedback stack
+func exitUnhealthy() {
+	logging.Close()
+	os.Exit(1)
+}
+
+func showStatusHelp() {
+	fmt.Println("Usage: portunix pft status [--json] [--watch]")
+	fmt.Println()
+	fmt.Println("Show the containers of the deployed feedback tool and probe its health")
+	fmt.Println("endpoints: the web UI of each area (Fider /_health) and the Mailhog API.")
+	fmt.Println("Response times are measured per probe. Exits with status 1 when a probe")
+	fmt.Println("fails; with --json also when the local containers are not all running.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --json                   Output as JSON for monitoring scripts")
+	fmt.Println("  -w, --watch              Refresh the status on container start/stop/die/oom")
+	fmt.Println("                           events until interrupted (with --json: JSON lines)")
+}
+
+func handleUpgradeCommand(args []string) {
+	opts := UpgradeOptions{Images: make(map[string]string)}
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--version":
+			if i+1 < len(args) {
+				opts.Version = args[i+1]
+				i++
+			}
+		case "--image":
+			if i+1 < len(args) {
+				service, image, ok := strings.Cut(args[i+1], "=")
+				if !ok || service == "" || image == "" {
+					fmt.Printf("Error: invalid image pin '%s' (expected <service>=<image>)\n", args[i+1])
+					return
+				}
+				opts.Images[service] = image
+				i++
+			}
+		case "--timeout":
+			if i+1 < len(args) {
+				timeout, err := time.ParseDuration(args[i+1])
+				if err != nil || timeout <= 0 {
+					fmt.Printf("Error: invalid timeout: %s\n", args[i+1])
+					return
+				}
+				opts.Timeout = timeout
+				i++
+			}
+		case "--skip-backup":
+			opts.SkipBackup = true
+		case "--pin-only":
+			opts.PinOnly = true
+		case "--dry-run":
+			opts.DryRun = true
+		case "--help", "-h":
+			showUpgradeHelp()
+			return
+		}
+	}
+
+	config, configFilePath, err := LoadConfigWithFilePath()
+	if err != nil {
+		fmt.Println("No configuration found. Run 'portunix pft configure' first.")
+		return
+	}
+
+	if opts.DryRun {
+		fmt.Println("[DRY-RUN] No changes will be made")
+		fmt.Println()
+	}
+	result, err := Upgrade(config, configFilePath, opts)
+	if err != nil {
+		fmt.Printf("Upgrade failed: %v\n", err)
+		return
+	}
+
+	switch {
+	case opts.DryRun:
+		return
+	case opts.PinOnly:
+		fmt.Printf("Images pinned in %s\n", configFilePath)
+		return
+	}
+	fmt.Println()
+	fmt.Printf("✓ %s upgraded and healthy at %s\n", config.GetProvider(), result.URL)
+	if result.BackupFile != "" {
+		fmt.Printf("  Database backup: %s\n", result.BackupFile)
+	}
+}
+
+func showUpgradeHelp() {
+	fmt.Println("Usage: portunix pft upgrade [options]")
+	fmt.Println()
+	fmt.Println("Upgrade the deployed feedback tool (fider, clearflask). The requested images")
+	fmt.Println("are pinned in the configuration, the database is backed up, the new images")
+	fmt.Println("are pulled and started, and the tool is checked to be healthy. Database")
+	fmt.Println("migrations run when the new version starts.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --version <tag>          Tag of the application images, e.g. v0.25.0")
+	fmt.Println("  --image <service>=<img>  Pin the image of a compose service (repeatable)")
+	fmt.Println("  --skip-backup            Upgrade without a database backup")
+	fmt.Println("  --pin-only               Save the pins without upgrading")
+	fmt.Println("  --timeout <duration>     Time to wait for the tool to become healthy (default 3m)")
+	fmt.Println("  --dry-run                Show the image changes without upgrading")
+	fmt.Println()
+	fmt.Println("Without --version or --image, the pinned images are pulled again, which")
+	fmt.Println("picks up new releases of moving tags such as 'stable'.")
+	fmt.Println()
+	fmt.Println("Backups are written to ~/.portunix/pft/<provider>/backups together with")
+	fmt.Println("the compose file before the upgrade.")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft upgrade --version v0.25.0 --dry-run")
+	fmt.Println("  portunix pft upgrade --version v0.25.0")
+	fmt.Println("  portunix pft upgrade --image db=postgres:16-alpine")
 }
 
 func handleDestroyCommand(args []string) {
@@ -1106,18 +1994,28 @@ func handleSyncCommand(args []string) {
 
 	// Update config with tokens if provided
 	if vocToken != "" {
-		config.VoC.APIToken = vocToken
+		config.VoC.APIToken = storeToken(config, "voc", vocToken)
 	}
 	if vosToken != "" {
-		config.VoS.APIToken = vosToken
+		config.VoS.APIToken = storeToken(config, "vos", vosToken)
 	}
 
-	fmt.Printf("Synchronizing %s with Fider...\n", config.Name)
+	fmt.Printf("Synchronizing %s...\n", config.Name)
 	if dryRun {
 		fmt.Println("(dry-run mode - no changes will be made)")
 	}
 	fmt.Println()
 
+	// Areas mapped to a Discourse category sync through the Discourse provider
+	if syncVoC && config.GetAreaProvider("voc") == "discourse" {
+		syncDiscourse(config, basePath, "voc", "VoC (Voice of Customer)", dryRun)
+		syncVoC = false
+	}
+	if syncVoS && config.GetAreaProvider("vos") == "discourse" {
+		syncDiscourse(config, basePath, "vos", "VoS (Voice of Stakeholder)", dryRun)
+		syncVoS = false
+	}
+
 	// Sync VoC
 	if syncVoC {
 		fmt.Println("🔄 VoC (Voice of Customer):")
@@ -1127,12 +2025,14 @@ func handleSyncCommand(args []string) {
 		if vocURL == "" {
 			vocURL = "http://localhost:3100"
 		}
-		vocAPIToken := config.VoC.APIToken
+		vocAPIToken := config.VoC.Token()
 		if vocAPIToken == "" {
 			vocAPIToken = config.GetAPIToken()
 		}
 
+		var syncErr error // First failure of the run
 		if vocAPIToken == "" {
+			syncErr = fmt.Errorf("no API token configured")
 			fmt.Println("   ✗ No API token configured for VoC")
 			fmt.Println("   Run: portunix pft sync --voc --voc-token <your-token>")
 		} else {
@@ -1142,8 +2042,13 @@ func handleSyncCommand(args []string) {
 			fmt.Println("   📥 Pulling new posts from Fider...")
 			pulled, skippedPull, err := PullFromFider(client, vocDir, "voc", dryRun)
 			if err != nil {
+				logging.Capture(zerolog.ErrorLevel, "sync pull failed", "voice", "voc", "url", vocURL, "dir", vocDir, "error", err)
+				if syncErr == nil {
+					syncErr = err
+				}
 				fmt.Printf("   ✗ Pull failed: %v\n", err)
 			} else {
+				logging.Info("sync pulled", "voice", "voc", "url", vocURL, "pulled", pulled, "skipped", skippedPull, "dry_run", dryRun)
 				fmt.Printf("      Pulled: %d, Skipped: %d\n", pulled, skippedPull)
 			}
 
@@ -1151,21 +2056,47 @@ func handleSyncCommand(args []string) {
 			fmt.Println("   📤 Pushing new local files to Fider...")
 			items, err := ScanFeedbackDirectory(vocDir, "voc")
 			if err != nil {
+				logging.Capture(zerolog.ErrorLevel, "sync scan failed", "voice", "voc", "dir", vocDir, "error", err)
+				if syncErr == nil {
+					syncErr = err
+				}
 				fmt.Printf("   ✗ Failed to scan directory: %v\n", err)
 			} else {
 				pushed, skippedPush, err := PushNewToFider(client, items, dryRun, config.Name)
 				if err != nil {
+					logging.Capture(zerolog.ErrorLevel, "sync push failed", "voice", "voc", "url", vocURL, "items", len(items), "error", err)
+					if syncErr == nil {
+						syncErr = err
+					}
 					fmt.Printf("   ✗ Push failed: %v\n", err)
 				} else {
+					logging.Info("sync pushed", "voice", "voc", "url", vocURL, "pushed", pushed, "skipped", skippedPush, "dry_run", dryRun)
 					fmt.Printf("      Pushed: %d, Skipped (already synced): %d\n", pushed, skippedPush)
 				}
 			}
-		}
-		fmt.Println()
-	}
 
-	// Sync VoS
-	if syncVoS {
+			// Step 3: Merge category changes with Fider tags
+			fmt.Println("   🏷  Syncing categories with Fider tags...")
+			localUpdated, remoteUpdated, err := SyncCategoriesWithFider(client, basePath, "voc", vocDir, true, true, dryRun)
+			if err != nil {
+				logging.Capture(zerolog.ErrorLevel, "sync categories failed", "voice", "voc", "url", vocURL, "error", err)
+				if syncErr == nil {
+					syncErr = err
+				}
+				fmt.Printf("   ✗ Category sync failed: %v\n", err)
+			} else {
+				logging.Info("sync categories", "voice", "voc", "url", vocURL, "local", localUpdated, "remote", remoteUpdated, "dry_run", dryRun)
+				fmt.Printf("      Items updated: %d, Fider posts updated: %d\n", localUpdated, remoteUpdated)
+			}
+		}
+		if !dryRun {
+			RecordSync(basePath, "voc", syncErr)
+		}
+		fmt.Println()
+	}
+
+	// Sync VoS
+	if syncVoS {
 		fmt.Println("🔄 VoS (Voice of Stakeholder):")
 		vosDir := getVoiceDir(basePath, "vos")
 
@@ -1173,12 +2104,14 @@ func handleSyncCommand(args []string) {
 		if vosURL == "" {
 			vosURL = "http://localhost:3101"
 		}
-		vosAPIToken := config.VoS.APIToken
+		vosAPIToken := config.VoS.Token()
 		if vosAPIToken == "" {
 			vosAPIToken = config.GetAPIToken()
 		}
 
+		var syncErr error // First failure of the run
 		if vosAPIToken == "" {
+			syncErr = fmt.Errorf("no API token configured")
 			fmt.Println("   ✗ No API token configured for VoS")
 			fmt.Println("   Run: portunix pft sync --vos --vos-token <your-token>")
 		} else {
@@ -1188,8 +2121,13 @@ func handleSyncCommand(args []string) {
 			fmt.Println("   📥 Pulling new posts from Fider...")
 			pulled, skippedPull, err := PullFromFider(client, vosDir, "vos", dryRun)
 			if err != nil {
+				logging.Capture(zerolog.ErrorLevel, "sync pull failed", "voice", "vos", "url", vosURL, "dir", vosDir, "error", err)
+				if syncErr == nil {
+					syncErr = err
+				}
 				fmt.Printf("   ✗ Pull failed: %v\n", err)
 			} else {
+				logging.Info("sync pulled", "voice", "vos", "url", vosURL, "pulled", pulled, "skipped", skippedPull, "dry_run", dryRun)
 				fmt.Printf("      Pulled: %d, Skipped: %d\n", pulled, skippedPull)
 			}
 
@@ -1197,15 +2135,41 @@ func handleSyncCommand(args []string) {
 			fmt.Println("   📤 Pushing new local files to Fider...")
 			items, err := ScanFeedbackDirectory(vosDir, "vos")
 			if err != nil {
+				logging.Capture(zerolog.ErrorLevel, "sync scan failed", "voice", "vos", "dir", vosDir, "error", err)
+				if syncErr == nil {
+					syncErr = err
+				}
 				fmt.Printf("   ✗ Failed to scan directory: %v\n", err)
 			} else {
 				pushed, skippedPush, err := PushNewToFider(client, items, dryRun, config.Name)
 				if err != nil {
+					logging.Capture(zerolog.ErrorLevel, "sync push failed", "voice", "vos", "url", vosURL, "items", len(items), "error", err)
+					if syncErr == nil {
+						syncErr = err
+					}
 					fmt.Printf("   ✗ Push failed: %v\n", err)
 				} else {
+					logging.Info("sync pushed", "voice", "vos", "url", vosURL, "pushed", pushed, "skipped", skippedPush, "dry_run", dryRun)
 					fmt.Printf("      Pushed: %d, Skipped (already synced): %d\n", pushed, skippedPush)
 				}
 			}
+
+			// Step 3: Merge category changes with Fider tags
+			fmt.Println("   🏷  Syncing categories with Fider tags...")
+			localUpdated, remoteUpdated, err := SyncCategoriesWithFider(client, basePath, "vos", vosDir, true, true, dryRun)
+			if err != nil {
+				logging.Capture(zerolog.ErrorLevel, "sync categories failed", "voice", "vos", "url", vosURL, "error", err)
+				if syncErr == nil {
+					syncErr = err
+				}
+				fmt.Printf("   ✗ Category sync failed: %v\n", err)
+			} else {
+				logging.Info("sync categories", "voice", "vos", "url", vosURL, "local", localUpdated, "remote", remoteUpdated, "dry_run", dryRun)
+				fmt.Printf("      Items updated: %d, Fider posts updated: %d\n", localUpdated, remoteUpdated)
+			}
+		}
+		if !dryRun {
+			RecordSync(basePath, "vos", syncErr)
 		}
 		fmt.Println()
 	}
@@ -1222,6 +2186,39 @@ func handleSyncCommand(args []string) {
 	fmt.Println("Sync complete.")
 }
 
+// syncDiscourse synchronizes an area with its Discourse category
+func syncDiscourse(config *Config, basePath, area, label string, dryRun bool) {
+	fmt.Printf("🔄 %s with Discourse:\n", label)
+	defer fmt.Println()
+
+	provider, _ := GetProvider("discourse")
+	providerConfig := config.GetAreaProviderConfig(area)
+	if err := provider.Connect(providerConfig); err != nil {
+		logging.Capture(zerolog.ErrorLevel, "sync connect failed", "voice", area, "url", providerConfig.Endpoint, "error", err)
+		fmt.Printf("   ✗ %v\n", err)
+		if !dryRun {
+			RecordSync(basePath, area, err)
+		}
+		return
+	}
+	defer provider.Close()
+
+	result, err := SyncDiscourseArea(provider, config, basePath, area, dryRun)
+	if err != nil {
+		logging.Capture(zerolog.ErrorLevel, "sync discourse failed", "voice", area, "url", providerConfig.Endpoint, "error", err)
+		fmt.Printf("   ✗ Sync failed: %v\n", err)
+		if !dryRun {
+			RecordSync(basePath, area, err)
+		}
+		return
+	}
+	if !dryRun {
+		RecordSync(basePath, area, nil)
+	}
+	logging.Info("sync discourse", "voice", area, "url", providerConfig.Endpoint, "pulled", result.Pulled, "votes", result.Votes, "statuses", result.Statuses, "dry_run", dryRun)
+	fmt.Printf("      Topics pulled: %d, Votes updated: %d, Statuses pushed: %d\n", result.Pulled, result.Votes, result.Statuses)
+}
+
 func showSyncHelp() {
 	fmt.Println("Usage: portunix pft sync [options]")
 	fmt.Println()
@@ -1230,6 +2227,7 @@ func showSyncHelp() {
 	fmt.Println("This command will:")
 	fmt.Println("  1. Pull new posts from Fider (posts not yet in local files)")
 	fmt.Println("  2. Push new local files to Fider (files without Fider ID)")
+	fmt.Println("  3. Merge categories of synced items with Fider tags")
 	fmt.Println()
 	fmt.Println("Options:")
 	fmt.Println("  --voc              Sync only VoC (Voice of Customer)")
@@ -1240,6 +2238,14 @@ func showSyncHelp() {
 	fmt.Println()
 	fmt.Println("Note: Files with Fider ID in metadata are considered synced.")
 	fmt.Println("      New local files will get Fider ID added after push.")
+	fmt.Println()
+	fmt.Println("Categories map to Fider tags by ID or name. Missing tags are created in")
+	fmt.Println("Fider and new tags become local categories. A category added or removed")
+	fmt.Println("on either side since the last sync is applied to the other.")
+	fmt.Println()
+	fmt.Println("Areas with the discourse provider sync with their category instead: new")
+	fmt.Println("topics become items, likes update the votes and changed statuses are pushed")
+	fmt.Println("back as topic tags (planned, started, completed, declined) or replies.")
 }
 
 func handlePullCommand(args []string) {
@@ -1292,10 +2298,10 @@ func handlePullCommand(args []string) {
 
 	// Update config with tokens if provided
 	if vocToken != "" {
-		config.VoC.APIToken = vocToken
+		config.VoC.APIToken = storeToken(config, "voc", vocToken)
 	}
 	if vosToken != "" {
-		config.VoS.APIToken = vosToken
+		config.VoS.APIToken = storeToken(config, "vos", vosToken)
 	}
 
 	fmt.Println("Pulling feedback from Fider...")
@@ -1313,7 +2319,7 @@ func handlePullCommand(args []string) {
 		if vocURL == "" {
 			vocURL = "http://localhost:3100"
 		}
-		vocAPIToken := config.VoC.APIToken
+		vocAPIToken := config.VoC.Token()
 		if vocAPIToken == "" {
 			vocAPIToken = config.GetAPIToken()
 		}
@@ -1329,6 +2335,12 @@ func handlePullCommand(args []string) {
 			} else {
 				fmt.Printf("   Created: %d, Skipped: %d\n", created, skipped)
 			}
+			updated, _, err := SyncCategoriesWithFider(client, basePath, "voc", vocDir, true, false, dryRun)
+			if err != nil {
+				fmt.Printf("   ✗ Category pull failed: %v\n", err)
+			} else {
+				fmt.Printf("   Categories updated from Fider tags: %d\n", updated)
+			}
 		}
 		fmt.Println()
 	}
@@ -1342,7 +2354,7 @@ func handlePullCommand(args []string) {
 		if vosURL == "" {
 			vosURL = "http://localhost:3101"
 		}
-		vosAPIToken := config.VoS.APIToken
+		vosAPIToken := config.VoS.Token()
 		if vosAPIToken == "" {
 			vosAPIToken = config.GetAPIToken()
 		}
@@ -1358,6 +2370,12 @@ func handlePullCommand(args []string) {
 			} else {
 				fmt.Printf("   Created: %d, Skipped: %d\n", created, skipped)
 			}
+			updated, _, err := SyncCategoriesWithFider(client, basePath, "vos", vosDir, true, false, dryRun)
+			if err != nil {
+				fmt.Printf("   ✗ Category pull failed: %v\n", err)
+			} else {
+				fmt.Printf("   Categories updated from Fider tags: %d\n", updated)
+			}
 		}
 		fmt.Println()
 	}
@@ -1384,7 +2402,8 @@ func showPullHelp() {
 	fmt.Println("  --vos-token <tok>  Set VoS Fider API token")
 	fmt.Println("  --dry-run          Show what would be pulled without creating files")
 	fmt.Println()
-	fmt.Println("Note: Existing files are skipped (not overwritten).")
+	fmt.Println("Note: Existing files are skipped (not overwritten). Categories of synced")
+	fmt.Println("      files are updated from the Fider tags of their posts.")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  portunix pft pull --voc")
@@ -1441,10 +2460,10 @@ func handlePushCommand(args []string) {
 
 	// Update config with tokens if provided
 	if vocToken != "" {
-		config.VoC.APIToken = vocToken
+		config.VoC.APIToken = storeToken(config, "voc", vocToken)
 	}
 	if vosToken != "" {
-		config.VoS.APIToken = vosToken
+		config.VoS.APIToken = storeToken(config, "vos", vosToken)
 	}
 
 	fmt.Println("Pushing feedback to Fider...")
@@ -1462,7 +2481,7 @@ func handlePushCommand(args []string) {
 		if vocURL == "" {
 			vocURL = "http://localhost:3100"
 		}
-		vocAPIToken := config.VoC.APIToken
+		vocAPIToken := config.VoC.Token()
 		if vocAPIToken == "" {
 			vocAPIToken = config.GetAPIToken() // Fallback to legacy
 		}
@@ -1482,6 +2501,12 @@ func handlePushCommand(args []string) {
 				if err := PushToFider(client, items, dryRun); err != nil {
 					fmt.Printf("   ✗ Push failed: %v\n", err)
 				}
+				_, updated, err := SyncCategoriesWithFider(client, basePath, "voc", vocDir, false, true, dryRun)
+				if err != nil {
+					fmt.Printf("   ✗ Category push failed: %v\n", err)
+				} else {
+					fmt.Printf("   Fider posts retagged from categories: %d\n", updated)
+				}
 			}
 		}
 		fmt.Println()
@@ -1496,7 +2521,7 @@ func handlePushCommand(args []string) {
 		if vosURL == "" {
 			vosURL = "http://localhost:3101"
 		}
-		vosAPIToken := config.VoS.APIToken
+		vosAPIToken := config.VoS.Token()
 		if vosAPIToken == "" {
 			vosAPIToken = config.GetAPIToken() // Fallback to legacy
 		}
@@ -1516,6 +2541,12 @@ func handlePushCommand(args []string) {
 				if err := PushToFider(client, items, dryRun); err != nil {
 					fmt.Printf("   ✗ Push failed: %v\n", err)
 				}
+				_, updated, err := SyncCategoriesWithFider(client, basePath, "vos", vosDir, false, true, dryRun)
+				if err != nil {
+					fmt.Printf("   ✗ Category push failed: %v\n", err)
+				} else {
+					fmt.Printf("   Fider posts retagged from categories: %d\n", updated)
+				}
 			}
 		}
 		fmt.Println()
@@ -1531,6 +2562,104 @@ func handlePushCommand(args []string) {
 	}
 }
 
+func handleWatchCommand(args []string) {
+	var watchVoC, watchVoS, push bool
+	var configPath string
+	debounce := DefaultWatchDebounce
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--voc":
+			watchVoC = true
+		case "--vos":
+			watchVoS = true
+		case "--push":
+			push = true
+		case "--debounce":
+			if i+1 < len(args) {
+				d, err := time.ParseDuration(args[i+1])
+				if err != nil || d <= 0 {
+					fmt.Printf("Error: invalid --debounce '%s' (e.g. 500ms)\n", args[i+1])
+					return
+				}
+				debounce = d
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				configPath = args[i+1]
+				i++
+			}
+		case "--help", "-h":
+			showWatchHelp()
+			return
+		}
+	}
+	if push && configPath != "" {
+		fmt.Println("Error: --push pushes the project of the current directory; run watch there instead of using --path")
+		return
+	}
+
+	// If neither specified, watch both
+	var areas []string
+	if watchVoC || !watchVoS {
+		areas = append(areas, "voc")
+	}
+	if watchVoS || !watchVoC {
+		areas = append(areas, "vos")
+	}
+
+	config, configFilePath, err := loadOrCreateConfig(configPath)
+	if err != nil {
+		fmt.Printf("Error loading configuration: %v\n", err)
+		return
+	}
+	projectDir := ResolveProjectPath(config, configFilePath, configPath)
+
+	var watched, names []string
+	for _, area := range areas {
+		if info, err := os.Stat(getVoiceDir(projectDir, area)); err == nil && info.IsDir() {
+			watched = append(watched, area)
+			names = append(names, filepath.Base(getVoiceDir(projectDir, area)))
+		}
+	}
+	if len(watched) == 0 {
+		fmt.Printf("Error: no VoC or VoS directory in %s\n", projectDir)
+		return
+	}
+
+	watcher := NewItemWatcher(projectDir, watched, os.Stdout)
+	if push {
+		watcher.push = func(area string) {
+			handlePushCommand([]string{"--" + area})
+		}
+	}
+	fmt.Printf("👀 Watching %s in %s (Ctrl+C to stop)\n", strings.Join(names, ", "), projectDir)
+	if err := watcher.Run(debounce, nil); err != nil {
+		logging.Capture(zerolog.ErrorLevel, "watch failed", "dir", projectDir, "error", err)
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+func showWatchHelp() {
+	fmt.Println("Usage: portunix pft watch [options]")
+	fmt.Println()
+	fmt.Println("Watch the VoC and VoS directories and check every saved item: the")
+	fmt.Println("frontmatter must be valid YAML with an id matching the file name and a")
+	fmt.Println("title. Valid items are updated in the sync cache, removed files dropped.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --voc              Watch only VoC (Voice of Customer) documents")
+	fmt.Println("  --vos              Watch only VoS (Voice of Stakeholder) documents")
+	fmt.Println("  --push             Push an area after its items changed, once all are valid")
+	fmt.Println("  --debounce <dur>   Wait for editors to finish saving (default: 300ms)")
+	fmt.Println("  --path <dir>       Project directory (default: current directory)")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft watch")
+	fmt.Println("  portunix pft watch --voc --push")
+}
+
 func showPushHelp() {
 	fmt.Println("Usage: portunix pft push [options]")
 	fmt.Println()
@@ -1543,6 +2672,9 @@ func showPushHelp() {
 	fmt.Println("  --vos-token <tok>  Set VoS Fider API token")
 	fmt.Println("  --dry-run          Show what would be pushed without making changes")
 	fmt.Println()
+	fmt.Println("Note: Posts of synced files are tagged with their categories; missing")
+	fmt.Println("      tags are created in Fider.")
+	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  portunix pft push --voc --voc-token abc123")
 	fmt.Println("  portunix pft push --dry-run")
@@ -1552,10 +2684,11 @@ func showPushHelp() {
 // Feedback management handlers
 func handleListCommand(args []string) {
 	// Parse flags
-	var listVoC, listVoS, showAll, uncategorizedOnly bool
+	var listVoC, listVoS, showAll, uncategorizedOnly, jsonOutput bool
 	var format string = "table"
 	var categoryFilter string
 	var configPath string
+	var sortBy string
 
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -1577,6 +2710,13 @@ func handleListCommand(args []string) {
 			}
 		case "--uncategorized":
 			uncategorizedOnly = true
+		case "--sort":
+			if i+1 < len(args) {
+				sortBy = args[i+1]
+				i++
+			}
+		case "--json":
+			jsonOutput = true
 		case "--path":
 			if i+1 < len(args) {
 				configPath = args[i+1]
@@ -1588,6 +2728,11 @@ func handleListCommand(args []string) {
 		}
 	}
 
+	if sortBy != "" && sortBy != "urgency" && sortBy != "sentiment" {
+		fmt.Printf("Error: unknown sort order '%s' (urgency, sentiment)\n", sortBy)
+		return
+	}
+
 	// Default: list both
 	if !listVoC && !listVoS {
 		listVoC = true
@@ -1603,6 +2748,29 @@ func handleListCommand(args []string) {
 	// Use cross-platform path resolution
 	projectDir := ResolveProjectPath(config, configFilePath, configPath)
 
+	// Machine-readable output: a single JSON array without headers
+	if jsonOutput {
+		items := []FeedbackItem{}
+		for _, area := range []struct {
+			name    string
+			enabled bool
+		}{{"voc", listVoC}, {"vos", listVoS}} {
+			if !area.enabled {
+				continue
+			}
+			areaItems, err := scanLocalDirectory(getVoiceDir(projectDir, area.name), area.name)
+			if err == nil {
+				items = append(items, filterItemsByCategory(areaItems, categoryFilter, uncategorizedOnly)...)
+			}
+		}
+		if sortBy != "" {
+			sortItemsByScore(items, sortBy)
+		}
+		data, _ := json.MarshalIndent(items, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
 	fmt.Printf("Feedback Items - %s\n", config.Name)
 	if categoryFilter != "" {
 		fmt.Printf("Filter: category = %s\n", categoryFilter)
@@ -1620,6 +2788,9 @@ func handleListCommand(args []string) {
 		if err == nil && len(vocItems) > 0 {
 			// Apply category filter
 			filteredItems := filterItemsByCategory(vocItems, categoryFilter, uncategorizedOnly)
+			if sortBy != "" {
+				sortItemsByScore(filteredItems, sortBy)
+			}
 			if len(filteredItems) > 0 {
 				fmt.Printf("\n📢 Voice of Customer (VoC) - %d items\n", len(filteredItems))
 				fmt.Println(strings.Repeat("-", 40))
@@ -1641,6 +2812,9 @@ func handleListCommand(args []string) {
 		if err == nil && len(vosItems) > 0 {
 			// Apply category filter
 			filteredItems := filterItemsByCategory(vosItems, categoryFilter, uncategorizedOnly)
+			if sortBy != "" {
+				sortItemsByScore(filteredItems, sortBy)
+			}
 			if len(filteredItems) > 0 {
 				fmt.Printf("\n🏢 Voice of Stakeholder (VoS) - %d items\n", len(filteredItems))
 				fmt.Println(strings.Repeat("-", 40))
@@ -1700,7 +2874,11 @@ func printFeedbackItem(item FeedbackItem, format string, showAll bool) {
 		if len(item.Categories) > 0 {
 			categoryMark = " [" + strings.Join(item.Categories, ", ") + "]"
 		}
-		fmt.Printf("   %-10s %-40s (%s)%s%s\n", item.ID, truncateStr(item.Title, 40), status, categoryMark, syncMark)
+		scoreMark := ""
+		if item.Scores != nil {
+			scoreMark = fmt.Sprintf(" [urgency %.2f, sentiment %+.2f]", item.Scores.Urgency, item.Scores.Sentiment)
+		}
+		fmt.Printf("   %-10s %-40s (%s)%s%s%s\n", item.ID, truncateStr(item.Title, 40), status, categoryMark, scoreMark, syncMark)
 		if showAll && item.Description != "" {
 			desc := truncateStr(item.Description, 70)
 			fmt.Printf("              %s\n", desc)
@@ -1736,8 +2914,10 @@ func showListHelp() {
 	fmt.Println("  --vos              List only VoS (Voice of Stakeholder) items")
 	fmt.Println("  --all, -a          Show full descriptions")
 	fmt.Println("  --format <fmt>     Output format (table, json)")
+	fmt.Println("  --json             Print all items as a single JSON array")
 	fmt.Println("  --category <id>    Filter by category")
 	fmt.Println("  --uncategorized    Show only uncategorized items")
+	fmt.Println("  --sort <order>     Sort by urgency or sentiment (scores from 'pft analyze --score')")
 	fmt.Println("  --help, -h         Show this help")
 	fmt.Println()
 	fmt.Println("Examples:")
@@ -1745,8 +2925,10 @@ func showListHelp() {
 	fmt.Println("  portunix pft list --voc")
 	fmt.Println("  portunix pft list --all")
 	fmt.Println("  portunix pft list --format json")
+	fmt.Println("  portunix pft list --json")
 	fmt.Println("  portunix pft list --category user-auth")
 	fmt.Println("  portunix pft list --uncategorized")
+	fmt.Println("  portunix pft list --sort urgency")
 }
 
 func handleShowCommand(args []string) {
@@ -1820,6 +3002,14 @@ func handleShowCommand(args []string) {
 		fmt.Printf("Tags:        %s\n", strings.Join(item.Tags, ", "))
 	}
 
+	if item.Estimate != "" {
+		fmt.Printf("Estimate:    %s\n", item.Estimate)
+	}
+
+	if item.ActualEffort != "" {
+		fmt.Printf("Actual:      %s\n", item.ActualEffort)
+	}
+
 	if item.CreatedAt != "" {
 		fmt.Printf("Created:     %s\n", item.CreatedAt)
 	}
@@ -2024,8 +3214,12 @@ func handleAddCommand(args []string) {
 		return
 	}
 
-	// Generate unique ID
-	itemID := generateNextItemID(areaDir, area)
+	// Allocate a unique ID
+	itemID, err := generateNextItemID(config, projectDir, area)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
 
 	// Create slug from title
 	slug := createSlugFromTitle(title)
@@ -2055,10 +3249,13 @@ func handleAddCommand(args []string) {
 		TargetUsers: targetUsers,
 		Related:     related,
 		Tags:        tags,
+		Language:    config.GetLanguage(),
 	}
 	content := generateFeedbackMarkdown(params)
 
 	// Write file
+	op := journal.Current("ptx-pft")
+	op.SaveFile(filePath)
 	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
 		fmt.Printf("Error writing file: %v\n", err)
 		return
@@ -2069,6 +3266,7 @@ func handleAddCommand(args []string) {
 	if category != "" {
 		fmt.Printf("  Category: %s\n", category)
 	}
+	commitJournal(op)
 }
 
 // handleUpdateCommand updates an existing feedback item
@@ -2087,9 +3285,9 @@ func handleUpdateCommand(args []string) {
 	// First argument is the item ID
 	itemID := args[0]
 	var title, description, verbatim, category, author, source, status, configPath string
-	var priority string
+	var priority, assignee string
 	var products, targetUsers, related, tags []string
-	var clearProducts, clearTargetUsers, clearRelated, clearTags bool
+	var clearProducts, clearTargetUsers, clearRelated, clearTags, clearAssignee bool
 
 	for i := 1; i < len(args); i++ {
 		switch args[i] {
@@ -2161,6 +3359,13 @@ func handleUpdateCommand(args []string) {
 			clearRelated = true
 		case "--clear-tags":
 			clearTags = true
+		case "--assignee":
+			if i+1 < len(args) {
+				assignee = args[i+1]
+				i++
+			}
+		case "--clear-assignee":
+			clearAssignee = true
 		case "--path":
 			if i+1 < len(args) {
 				configPath = args[i+1]
@@ -2247,8 +3452,16 @@ func handleUpdateCommand(args []string) {
 		existingParams.Source = source
 	}
 	if status != "" {
+		// Record when the item was triaged and resolved for SLA tracking
+		recordStatusDates(status, &existingParams.Triaged, &existingParams.Resolved)
 		existingParams.Status = status
 	}
+	if assignee != "" {
+		existingParams.Assignee = assignee
+	}
+	if clearAssignee {
+		existingParams.Assignee = ""
+	}
 	if priority != "" {
 		existingParams.Priority = priority
 	}
@@ -2284,11 +3497,19 @@ func handleUpdateCommand(args []string) {
 
 	// Set area from found location
 	existingParams.Area = itemArea
+	existingParams.Language = config.GetLanguage()
+
+	// Keep the markdown body unless title, description or verbatim change
+	if title != "" || description != "" || verbatim != "" {
+		existingParams.Body = ""
+	}
 
 	// Generate updated content
 	newContent := generateFeedbackMarkdown(*existingParams)
 
 	// Write file
+	op := journal.Current("ptx-pft")
+	op.SaveFile(itemPath)
 	if err := os.WriteFile(itemPath, []byte(newContent), 0644); err != nil {
 		fmt.Printf("Error writing file: %v\n", err)
 		return
@@ -2296,118 +3517,61 @@ func handleUpdateCommand(args []string) {
 
 	fmt.Printf("✓ Updated feedback item '%s'\n", itemID)
 	fmt.Printf("  File: %s\n", itemPath)
+	commitJournal(op)
 }
 
-// parseExistingItem parses an existing markdown file and returns FeedbackItemParams
-func parseExistingItem(content string) *FeedbackItemParams {
-	params := &FeedbackItemParams{}
-
-	// Check for YAML frontmatter
-	if !strings.HasPrefix(content, "---") {
-		return nil
+// commitJournal records the files an add or update wrote in the portunix
+// operation journal, so 'portunix undo' can reverse it
+func commitJournal(op *journal.Op) {
+	if err := op.Commit(); err != nil {
+		logging.Warn("failed to journal operation", "operation", op.ID(), "error", err)
+		return
+	}
+	if op != nil {
+		fmt.Printf("  Undo: portunix undo %s\n", op.ID())
 	}
+}
 
-	// Find end of frontmatter
-	endIndex := strings.Index(content[3:], "---")
-	if endIndex == -1 {
+// parseExistingItem parses an existing markdown file and returns FeedbackItemParams
+func parseExistingItem(content string) *FeedbackItemParams {
+	fm, body := ParseFrontmatter(content)
+	if fm == nil {
 		return nil
 	}
-
-	frontmatter := content[3 : endIndex+3]
-	lines := strings.Split(frontmatter, "\n")
-
-	var currentArrayField string
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		// Check if this is an array item
-		if strings.HasPrefix(line, "- ") {
-			value := strings.TrimPrefix(line, "- ")
-			switch currentArrayField {
-			case "products":
-				params.Products = append(params.Products, value)
-			case "target_users":
-				params.TargetUsers = append(params.TargetUsers, value)
-			case "related":
-				params.Related = append(params.Related, value)
-			case "tags":
-				params.Tags = append(params.Tags, value)
-			}
-			continue
-		}
-
-		// Parse key: value
-		parts := strings.SplitN(line, ":", 2)
-		if len(parts) != 2 {
-			continue
-		}
-
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-
-		// Check if this starts an array
-		if value == "" {
-			currentArrayField = key
-			continue
-		}
-		currentArrayField = ""
-
-		switch key {
-		case "id":
-			params.ID = value
-		case "title":
-			params.Title = value
-		case "area":
-			params.Area = value
-		case "description":
-			params.Description = value
-		case "verbatim":
-			params.Verbatim = value
-		case "category":
-			params.Category = value
-		case "status":
-			params.Status = value
-		case "priority":
-			params.Priority = value
-		case "legacy_id":
-			params.LegacyID = value
-		case "author":
-			params.Author = value
-		case "source":
-			params.Source = value
-		}
+	params := &FeedbackItemParams{
+		ID:          fm.Get("id"),
+		Title:       fm.Get("title"),
+		Area:        fm.Get("area"),
+		Description: fm.Get("description"),
+		Verbatim:    fm.Get("verbatim"),
+		Category:    fm.Get("category"),
+		Status:      fm.Get("status"),
+		Priority:    fm.Get("priority"),
+		LegacyID:    fm.Get("legacy_id"),
+		Author:      fm.Get("author"),
+		AuthorRole:  fm.Get("author_role"),
+		Source:      fm.Get("source"),
+		Created:     fm.Get("created"),
+		Triaged:     fm.Get("triaged"),
+		Resolved:    fm.Get("resolved"),
+		Assignee:    fm.Get("assignee"),
+		Products:    fm.List("products"),
+		TargetUsers: fm.List("target_users"),
+		Related:     fm.List("related"),
+		Tags:        fm.List("tags"),
+		Frontmatter: fm,
+		Body:        body,
 	}
 
 	// Extract description from markdown body if not in frontmatter
-	bodyStart := endIndex + 6 // Skip past "---\n"
-	if bodyStart < len(content) {
-		body := content[bodyStart:]
-		// Look for ## Popis section
-		if idx := strings.Index(body, "## Popis"); idx != -1 {
-			descStart := idx + len("## Popis")
-			// Find next section or end
-			descEnd := strings.Index(body[descStart:], "##")
-			if descEnd == -1 {
-				descEnd = len(body) - descStart
-			}
-			desc := strings.TrimSpace(body[descStart : descStart+descEnd])
-			if desc != "" && params.Description == "" {
-				params.Description = desc
-			}
+	if body != "" {
+		// Look for the description section in any language
+		if desc, ok := localizedSection(body, "item.description"); ok && desc != "" && params.Description == "" {
+			params.Description = desc
 		}
 
 		// Look for ## Verbatim section first (new format)
-		if idx := strings.Index(body, "## Verbatim"); idx != -1 && params.Verbatim == "" {
-			verbStart := idx + len("## Verbatim")
-			// Find next section or end
-			verbEnd := strings.Index(body[verbStart:], "##")
-			if verbEnd == -1 {
-				verbEnd = len(body) - verbStart
-			}
-			verbSection := strings.TrimSpace(body[verbStart : verbStart+verbEnd])
+		if verbSection, ok := localizedSection(body, "item.verbatim"); ok && params.Verbatim == "" {
 			// Extract content from blockquote
 			if strings.HasPrefix(verbSection, "> ") {
 				params.Verbatim = strings.TrimPrefix(verbSection, "> ")
@@ -2450,10 +3614,12 @@ func showUpdateHelp() {
 	fmt.Println("  --source <text>       Update source")
 	fmt.Println("  --status <status>     Update status")
 	fmt.Println("  --priority <level>    Update priority")
+	fmt.Println("  --assignee <user-id>  Assign to a user of the user registry")
 	fmt.Println("  --product <name>      Add product (can be used multiple times)")
 	fmt.Println("  --target-user <user>  Add target user (can be used multiple times)")
 	fmt.Println("  --related <id>        Add related item (can be used multiple times)")
 	fmt.Println("  --tag <tag>           Add tag (can be used multiple times)")
+	fmt.Println("  --clear-assignee      Remove the assignee")
 	fmt.Println("  --clear-products      Clear all products before adding new")
 	fmt.Println("  --clear-target-users  Clear all target users before adding new")
 	fmt.Println("  --clear-related       Clear all related items before adding new")
@@ -2466,35 +3632,6 @@ func showUpdateHelp() {
 	fmt.Println("  portunix pft update P01 --clear-tags --tag newtag1 --tag newtag2")
 }
 
-// generateNextItemID generates the next sequential ID (P01, P02, ...)
-func generateNextItemID(areaDir, area string) string {
-	maxNum := 0
-
-	// Scan all files in area directory and subdirectories
-	filepath.WalkDir(areaDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil || d.IsDir() {
-			return nil
-		}
-		if !strings.HasSuffix(d.Name(), ".md") {
-			return nil
-		}
-
-		// Extract number from filename like P01-title.md or P05-title.md
-		name := d.Name()
-		if strings.HasPrefix(name, "P") {
-			// Find the number after P
-			var num int
-			fmt.Sscanf(name[1:], "%d", &num)
-			if num > maxNum {
-				maxNum = num
-			}
-		}
-		return nil
-	})
-
-	return fmt.Sprintf("P%02d", maxNum+1)
-}
-
 // FeedbackItemParams contains all parameters for generating feedback markdown
 type FeedbackItemParams struct {
 	ID          string
@@ -2509,10 +3646,17 @@ type FeedbackItemParams struct {
 	Source      string
 	Priority    string
 	LegacyID    string
+	Created     string
+	Triaged     string
+	Resolved    string
+	Assignee    string // User ID from the user registry
 	Products    []string
 	TargetUsers []string
 	Related     []string
 	Tags        []string
+	Language    string       // Language of generated sections (en, cs, de); English when empty
+	Frontmatter *Frontmatter // Frontmatter of an existing file, keeping keys pft does not manage
+	Body        string       // Markdown after the frontmatter of an existing file; generated when empty
 }
 
 // generateFeedbackMarkdown generates markdown content with YAML frontmatter
@@ -2520,81 +3664,72 @@ func generateFeedbackMarkdown(params FeedbackItemParams) string {
 	var sb strings.Builder
 	now := time.Now().Format("2006-01-02")
 
-	// YAML frontmatter
-	sb.WriteString("---\n")
-	sb.WriteString(fmt.Sprintf("id: %s\n", params.ID))
-	sb.WriteString(fmt.Sprintf("title: %s\n", params.Title))
-	sb.WriteString(fmt.Sprintf("area: %s\n", params.Area))
-	if params.Category != "" {
-		sb.WriteString(fmt.Sprintf("category: %s\n", strings.ToUpper(params.Category)))
-	}
-	sb.WriteString(fmt.Sprintf("status: %s\n", params.Status))
-	if params.Priority != "" {
-		sb.WriteString(fmt.Sprintf("priority: %s\n", params.Priority))
-	}
-	if params.LegacyID != "" {
-		sb.WriteString(fmt.Sprintf("legacy_id: %s\n", params.LegacyID))
-	}
-	if params.Author != "" {
-		sb.WriteString(fmt.Sprintf("author: %s\n", params.Author))
-	}
-	if params.AuthorRole != "" {
-		sb.WriteString(fmt.Sprintf("author_role: %s\n", params.AuthorRole))
-	}
-	if params.Source != "" {
-		sb.WriteString(fmt.Sprintf("source: %s\n", params.Source))
-	}
-	sb.WriteString(fmt.Sprintf("created: %s\n", now))
-	sb.WriteString(fmt.Sprintf("updated: %s\n", now))
-
-	// Array fields
-	if len(params.Products) > 0 {
-		sb.WriteString("products:\n")
-		for _, p := range params.Products {
-			sb.WriteString(fmt.Sprintf("  - %s\n", p))
-		}
-	}
-	if len(params.TargetUsers) > 0 {
-		sb.WriteString("target_users:\n")
-		for _, u := range params.TargetUsers {
-			sb.WriteString(fmt.Sprintf("  - %s\n", u))
-		}
-	}
-	if len(params.Related) > 0 {
-		sb.WriteString("related:\n")
-		for _, r := range params.Related {
-			sb.WriteString(fmt.Sprintf("  - %s\n", r))
-		}
-	}
-	if len(params.Tags) > 0 {
-		sb.WriteString("tags:\n")
-		for _, tag := range params.Tags {
-			sb.WriteString(fmt.Sprintf("  - %s\n", tag))
-		}
-	}
-
-	sb.WriteString("---\n\n")
+	// YAML frontmatter, updating an existing one in place
+	fm := params.Frontmatter
+	if fm == nil {
+		fm = NewFrontmatter()
+	}
+	fm.Set("id", params.ID)
+	fm.Set("title", params.Title)
+	fm.Set("area", params.Area)
+	fm.Set("category", strings.ToUpper(params.Category))
+	fm.Set("status", params.Status)
+	fm.Set("priority", params.Priority)
+	fm.Set("legacy_id", params.LegacyID)
+	fm.Set("author", params.Author)
+	fm.Set("author_role", params.AuthorRole)
+	fm.Set("source", params.Source)
+	fm.Set("assignee", params.Assignee)
+	created := params.Created
+	if created == "" {
+		created = now
+	}
+	fm.Set("created", created)
+	fm.Set("updated", now)
+	fm.Set("triaged", params.Triaged)
+	fm.Set("resolved", params.Resolved)
+	fm.SetList("products", params.Products)
+	fm.SetList("target_users", params.TargetUsers)
+	fm.SetList("related", params.Related)
+	fm.SetList("tags", params.Tags)
+
+	// Description and verbatim kept in the frontmatter stay there
+	inFrontmatter := map[string]bool{}
+	for key, value := range map[string]string{"description": params.Description, "verbatim": params.Verbatim} {
+		if fm.Has(key) {
+			fm.Set(key, value)
+			inFrontmatter[key] = true
+		}
+	}
+	sb.WriteString(fm.String())
+
+	if params.Body != "" {
+		sb.WriteString(params.Body)
+		return sb.String()
+	}
+	sb.WriteString("\n")
 
 	// Markdown content
+	l := GetLocale(params.Language)
 	sb.WriteString(fmt.Sprintf("# %s\n\n", params.Title))
 
-	if params.Verbatim != "" {
-		sb.WriteString("## Verbatim\n\n")
+	if params.Verbatim != "" && !inFrontmatter["verbatim"] {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", l.T("item.verbatim")))
 		sb.WriteString(fmt.Sprintf("> %s\n\n", params.Verbatim))
 	}
 
-	if params.Description != "" {
-		sb.WriteString("## Popis\n\n")
+	if params.Description != "" && !inFrontmatter["description"] {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", l.T("item.description")))
 		sb.WriteString(params.Description)
 		sb.WriteString("\n\n")
 	}
 
-	sb.WriteString("## Stav implementace\n\n")
-	sb.WriteString("| Fáze | Stav | Poznámka |\n")
+	sb.WriteString(fmt.Sprintf("## %s\n\n", l.T("item.implementation")))
+	sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", l.T("item.phase"), l.T("item.state"), l.T("item.note")))
 	sb.WriteString("|------|------|----------|\n")
-	sb.WriteString("| Analýza | ⏳ | - |\n")
-	sb.WriteString("| Vývoj | ⏳ | - |\n")
-	sb.WriteString("| Release | ⏳ | - |\n")
+	for _, phase := range []string{"item.analysis", "item.development", "item.release"} {
+		sb.WriteString(fmt.Sprintf("| %s | ⏳ | - |\n", l.T(phase)))
+	}
 
 	return sb.String()
 }
@@ -2716,39 +3851,21 @@ func handleLinkCommand(args []string) {
 
 	contentStr := string(content)
 
-	// Check if already linked
-	if strings.Contains(contentStr, "linked_issue:") {
-		// Update existing link
-		lines := strings.Split(contentStr, "\n")
-		for i, line := range lines {
-			if strings.HasPrefix(line, "linked_issue:") {
-				lines[i] = fmt.Sprintf("linked_issue: %s", issueID)
-				break
-			}
+	if fm, _ := ParseFrontmatter(contentStr); fm != nil {
+		if err := UpdateFileFields(filePath, map[string]string{"linked_issue": issueID}); err != nil {
+			fmt.Printf("Error writing file: %v\n", err)
+			return
 		}
-		contentStr = strings.Join(lines, "\n")
 	} else {
-		// Add link to metadata section (after frontmatter or at top)
-		if strings.HasPrefix(contentStr, "---") {
-			// Find end of frontmatter
-			endIdx := strings.Index(contentStr[3:], "---")
-			if endIdx > 0 {
-				// Insert before closing ---
-				insertPos := 3 + endIdx
-				contentStr = contentStr[:insertPos] + fmt.Sprintf("linked_issue: %s\n", issueID) + contentStr[insertPos:]
-			}
-		} else {
-			// Add at the top as metadata comment
-			contentStr = fmt.Sprintf("<!-- linked_issue: %s -->\n\n%s", issueID, contentStr)
+		// Files without frontmatter carry the link as a comment at the top
+		contentStr = regexp.MustCompile(`(?m)^<!-- linked_issue:.*-->\n*`).ReplaceAllString(contentStr, "")
+		contentStr = fmt.Sprintf("<!-- linked_issue: %s -->\n\n%s", issueID, contentStr)
+		if err := os.WriteFile(filePath, []byte(contentStr), 0644); err != nil {
+			fmt.Printf("Error writing file: %v\n", err)
+			return
 		}
 	}
 
-	// Write updated content
-	if err := os.WriteFile(filePath, []byte(contentStr), 0644); err != nil {
-		fmt.Printf("Error writing file: %v\n", err)
-		return
-	}
-
 	fmt.Printf("✓ Linked feedback '%s' to issue '%s'\n", feedbackID, issueID)
 	fmt.Printf("  File: %s\n", filePath)
 	fmt.Printf("  Item: %s\n", item.Title)
@@ -2789,7 +3906,8 @@ func handleNotifyCommand(args []string) {
 	itemID := args[0]
 
 	// Parse flags
-	var userEmail, notifyTypeStr string
+	var userEmail, notifyTypeStr, previewUser string
+	var channelSpecs []string
 	var allVoC, allVoS, dryRun bool
 
 	for i := 1; i < len(args); i++ {
@@ -2804,10 +3922,20 @@ func handleNotifyCommand(args []string) {
 				notifyTypeStr = args[i+1]
 				i++
 			}
+		case "--channel":
+			if i+1 < len(args) {
+				channelSpecs = append(channelSpecs, args[i+1])
+				i++
+			}
 		case "--all-voc":
 			allVoC = true
 		case "--all-vos":
 			allVoS = true
+		case "--preview":
+			if i+1 < len(args) {
+				previewUser = args[i+1]
+				i++
+			}
 		case "--dry-run":
 			dryRun = true
 		}
@@ -2827,8 +3955,8 @@ func handleNotifyCommand(args []string) {
 	}
 
 	// Validate recipient selection
-	if userEmail == "" && !allVoC && !allVoS {
-		fmt.Println("Error: recipient required (--user, --all-voc, or --all-vos)")
+	if previewUser == "" && userEmail == "" && !allVoC && !allVoS && len(channelSpecs) == 0 {
+		fmt.Println("Error: recipient required (--user, --all-voc, --all-vos, --channel, or --preview)")
 		return
 	}
 
@@ -2850,6 +3978,7 @@ func handleNotifyCommand(args []string) {
 	}
 
 	// Prepare email data
+	area := itemArea(projectDir, feedbackItem.FilePath)
 	emailData := EmailData{
 		ProductName: config.Name,
 		Title:       feedbackItem.Title,
@@ -2858,66 +3987,75 @@ func handleNotifyCommand(args []string) {
 		PostNumber:  postNumber,
 		Provider:    config.GetProvider(),
 		ItemID:      itemID,
+		Language:    config.GetLanguage(),
+		ItemURL:     itemURL(config, area, feedbackItem.ExternalID),
 	}
 
-	// Collect recipients
-	var recipients []struct {
-		Email string
-		Name  string
+	registry, err := LoadUserRegistry(projectDir)
+	if err != nil {
+		fmt.Printf("Error loading user registry: %v\n", err)
+		return
+	}
+	openItems, err := loadOpenItems(config, projectDir)
+	if err != nil {
+		fmt.Printf("Error loading open items: %v\n", err)
+		return
 	}
 
-	if userEmail != "" {
-		// Single user specified
-		registry, err := LoadUserRegistry(projectDir)
-		if err == nil {
-			user := registry.FindUserByEmail(userEmail)
-			if user != nil {
-				recipients = append(recipients, struct {
-					Email string
-					Name  string
-				}{Email: userEmail, Name: user.Name})
-			} else {
-				recipients = append(recipients, struct {
-					Email string
-					Name  string
-				}{Email: userEmail, Name: userEmail})
-			}
-		} else {
-			recipients = append(recipients, struct {
-				Email string
-				Name  string
-			}{Email: userEmail, Name: userEmail})
+	// Render the email of one user without sending anything
+	if previewUser != "" {
+		user := registry.FindUserByEmail(previewUser)
+		if user == nil {
+			user = registry.FindUserByName(previewUser)
+		}
+		email := previewUser
+		if user != nil {
+			email = user.ID
+		} else if !strings.Contains(previewUser, "@") {
+			fmt.Printf("Error: user '%s' not found in users.json\n", previewUser)
+			return
 		}
-	}
 
-	if allVoC || allVoS {
-		registry, err := LoadUserRegistry(projectDir)
+		subject, body, err := GenerateNotification(notifyType, personalizeEmail(emailData, projectDir, area, email, user, openItems))
 		if err != nil {
-			fmt.Printf("Error loading user registry: %v\n", err)
+			fmt.Printf("Error generating email: %v\n", err)
 			return
 		}
+		fmt.Printf("To: %s\n", email)
+		fmt.Printf("Subject: %s\n", subject)
+		fmt.Println("---")
+		fmt.Println(body)
+		fmt.Println("---")
+		return
+	}
 
-		if allVoC {
-			vocUsers := registry.ListUsersByCategory("voc")
-			for _, user := range vocUsers {
-				if user.ID != "" && strings.Contains(user.ID, "@") {
-					recipients = append(recipients, struct {
-						Email string
-						Name  string
-					}{Email: user.ID, Name: user.Name})
-				}
-			}
+	// Post to chat channels
+	if len(channelSpecs) > 0 {
+		sendChannelNotifications(config, projectDir, channelSpecs, notifyType, emailData, dryRun)
+		if userEmail == "" && !allVoC && !allVoS {
+			return
 		}
+		fmt.Println()
+	}
 
-		if allVoS {
-			vosUsers := registry.ListUsersByCategory("vos")
-			for _, user := range vosUsers {
-				if user.ID != "" && strings.Contains(user.ID, "@") {
-					recipients = append(recipients, struct {
-						Email string
-						Name  string
-					}{Email: user.ID, Name: user.Name})
-				}
+	// Collect recipients
+	type recipient struct {
+		Email string
+		User  *User // Registry entry, nil for addresses not in users.json
+	}
+	var recipients []recipient
+
+	if userEmail != "" {
+		recipients = append(recipients, recipient{Email: userEmail, User: registry.FindUserByEmail(userEmail)})
+	}
+
+	for _, category := range []string{"voc", "vos"} {
+		if category == "voc" && !allVoC || category == "vos" && !allVoS {
+			continue
+		}
+		for _, user := range registry.ListUsersByCategory(category) {
+			if user.ID != "" && strings.Contains(user.ID, "@") {
+				recipients = append(recipients, recipient{Email: user.ID, User: registry.FindUser(user.ID)})
 			}
 		}
 	}
@@ -2955,15 +4093,14 @@ func handleNotifyCommand(args []string) {
 	failCount := 0
 
 	for _, recipient := range recipients {
-		emailData.UserName = recipient.Name
-		if emailData.UserName == "" {
-			emailData.UserName = recipient.Email
-		}
-
-		subject, body, err := GenerateNotification(notifyType, emailData)
+		personalized := personalizeEmail(emailData, projectDir, area, recipient.Email, recipient.User, openItems)
+		subject, body, err := GenerateNotification(notifyType, personalized)
 		if err != nil {
 			fmt.Printf("   Error generating email for %s: %v\n", recipient.Email, err)
 			failCount++
+			if !dryRun {
+				RecordNotification(projectDir, "email", err)
+			}
 			continue
 		}
 
@@ -2976,7 +4113,9 @@ func handleNotifyCommand(args []string) {
 			fmt.Println()
 			successCount++
 		} else {
-			if err := client.SendEmail(recipient.Email, subject, body); err != nil {
+			err := client.SendEmail(recipient.Email, subject, body)
+			RecordNotification(projectDir, "email", err)
+			if err != nil {
 				fmt.Printf("   Failed to send to %s: %v\n", recipient.Email, err)
 				failCount++
 			} else {
@@ -2994,19 +4133,59 @@ func handleNotifyCommand(args []string) {
 	}
 }
 
-func showNotifyHelp() {
-	fmt.Println("Usage: portunix pft notify <item-id> [options]")
-	fmt.Println()
-	fmt.Println("Send notification emails to users requesting action on feedback items.")
-	fmt.Println()
-	fmt.Println("Options:")
-	fmt.Println("  --user <email>     Send to specific user")
-	fmt.Println("  --all-voc          Send to all users with VoC role")
-	fmt.Println("  --all-vos          Send to all users with VoS role")
-	fmt.Println("  --type <type>      Notification type (required)")
-	fmt.Println("  --dry-run          Show email without sending")
-	fmt.Println()
-	fmt.Println("Notification types:")
+// sendChannelNotifications posts a notification to Slack or Teams channels
+func sendChannelNotifications(config *Config, projectDir string, specs []string, notifyType NotificationType, data EmailData, dryRun bool) {
+	msg, err := ChatNotification(notifyType, data)
+	if err != nil {
+		fmt.Printf("Error generating chat message: %v\n", err)
+		return
+	}
+
+	for _, spec := range specs {
+		channel, err := ParseChannel(config, spec)
+		if err != nil {
+			fmt.Printf("   Error: %v\n", err)
+			continue
+		}
+		if dryRun {
+			fmt.Printf("Would post to: %s\n", channel.Name())
+			fmt.Printf("Title: %s\n", msg.Title)
+			fmt.Println("---")
+			fmt.Println(msg.Text)
+			fmt.Println("---")
+			continue
+		}
+		err = channel.Send(msg)
+		RecordNotification(projectDir, channel.Name(), err)
+		if err != nil {
+			fmt.Printf("   Failed to post to %s: %v\n", channel.Name(), err)
+		} else {
+			fmt.Printf("   Posted to: %s\n", channel.Name())
+		}
+	}
+}
+
+func showNotifyHelp() {
+	fmt.Println("Usage: portunix pft notify <item-id> [options]")
+	fmt.Println()
+	fmt.Println("Send notification emails to users, or post to Slack and Microsoft Teams")
+	fmt.Println("channels, requesting action on feedback items.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --user <email>     Send to specific user")
+	fmt.Println("  --all-voc          Send to all users with VoC role")
+	fmt.Println("  --all-vos          Send to all users with VoS role")
+	fmt.Println("  --channel <spec>   Post to a chat channel: slack[:#channel] or teams[:channel]")
+	fmt.Println("                     (repeatable, see 'pft configure --slack-webhook')")
+	fmt.Println("  --type <type>      Notification type (required)")
+	fmt.Println("  --preview <user>   Show the personalized email of a user (email or name)")
+	fmt.Println("                     without sending anything")
+	fmt.Println("  --dry-run          Show email without sending")
+	fmt.Println()
+	fmt.Println("Emails are personalized for each recipient: name, organization, role,")
+	fmt.Println("a link to the item and the recipient's own open items.")
+	fmt.Println()
+	fmt.Println("Notification types:")
 	fmt.Println("  vote        - Request user to vote for/against requirement")
 	fmt.Println("  description - Request user to provide more details")
 	fmt.Println("  acceptance  - Request user to define acceptance criteria")
@@ -3015,6 +4194,8 @@ func showNotifyHelp() {
 	fmt.Println("  portunix pft notify UC001 --user user@example.com --type vote")
 	fmt.Println("  portunix pft notify REQ001 --all-voc --type description")
 	fmt.Println("  portunix pft notify UC001 --user test@test.com --type vote --dry-run")
+	fmt.Println("  portunix pft notify UC001 --preview alice@example.com --type vote")
+	fmt.Println("  portunix pft notify UC001 --channel slack:#product --channel teams --type vote")
 }
 
 // loadFeedbackItem loads a feedback item from local files
@@ -3085,7 +4266,7 @@ func tryLoadFromDir(dir, itemID, configURL, defaultURL string) (*FeedbackItem, s
 	}
 
 	// Parse markdown file
-	item := &FeedbackItem{ID: itemID}
+	item := &FeedbackItem{ID: itemID, FilePath: matches[0]}
 	lines := strings.Split(string(content), "\n")
 	var fiderID int
 
@@ -3191,18 +4372,30 @@ func handleReportCommand(args []string) {
 	// Generate report
 	var report strings.Builder
 
-	report.WriteString(fmt.Sprintf("# Feedback Report: %s\n\n", config.Name))
-	report.WriteString(fmt.Sprintf("Generated: %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	l := GetLocale(config.GetLanguage())
+	report.WriteString(fmt.Sprintf("# %s\n\n", l.T("report.title", config.Name)))
+	report.WriteString(l.T("report.generated", time.Now().Format("2006-01-02 15:04:05")) + "\n\n")
 
 	switch reportType {
 	case "summary":
-		generateSummaryReport(&report, vocItems, vosItems)
+		generateSummaryReport(&report, l, vocItems, vosItems)
+		generateEffortReport(&report, l, allItems)
+		generateSLAReport(&report, l, collectSLAChecks(config, projectDir, time.Now()))
 	case "detailed":
-		generateDetailedReport(&report, allItems)
+		generateDetailedReport(&report, l, allItems)
 	case "status":
-		generateStatusReport(&report, allItems)
+		generateStatusReport(&report, l, allItems)
+	case "sla":
+		generateSLAReport(&report, l, collectSLAChecks(config, projectDir, time.Now()))
+	case "effort":
+		if !hasEffort(allItems) {
+			report.WriteString(l.T("effort.none") + "\n")
+		}
+		generateEffortReport(&report, l, allItems)
 	default:
-		generateSummaryReport(&report, vocItems, vosItems)
+		generateSummaryReport(&report, l, vocItems, vosItems)
+		generateEffortReport(&report, l, allItems)
+		generateSLAReport(&report, l, collectSLAChecks(config, projectDir, time.Now()))
 	}
 
 	// Output
@@ -3217,11 +4410,11 @@ func handleReportCommand(args []string) {
 	}
 }
 
-func generateSummaryReport(report *strings.Builder, vocItems, vosItems []FeedbackItem) {
-	report.WriteString("## Summary\n\n")
-	report.WriteString(fmt.Sprintf("- **Voice of Customer (VoC)**: %d items\n", len(vocItems)))
-	report.WriteString(fmt.Sprintf("- **Voice of Stakeholder (VoS)**: %d items\n", len(vosItems)))
-	report.WriteString(fmt.Sprintf("- **Total**: %d items\n\n", len(vocItems)+len(vosItems)))
+func generateSummaryReport(report *strings.Builder, l *Locale, vocItems, vosItems []FeedbackItem) {
+	report.WriteString(fmt.Sprintf("## %s\n\n", l.T("report.summary")))
+	report.WriteString(fmt.Sprintf("- **%s**: %s\n", l.T("report.voc"), l.T("report.items", len(vocItems))))
+	report.WriteString(fmt.Sprintf("- **%s**: %s\n", l.T("report.vos"), l.T("report.items", len(vosItems))))
+	report.WriteString(fmt.Sprintf("- **%s**: %s\n\n", l.T("report.total"), l.T("report.items", len(vocItems)+len(vosItems))))
 
 	// Count by status
 	statusCounts := make(map[string]int)
@@ -3240,7 +4433,7 @@ func generateSummaryReport(report *strings.Builder, vocItems, vosItems []Feedbac
 		statusCounts[status]++
 	}
 
-	report.WriteString("## Status Distribution\n\n")
+	report.WriteString(fmt.Sprintf("## %s\n\n", l.T("report.status_distribution")))
 	for status, count := range statusCounts {
 		report.WriteString(fmt.Sprintf("- %s: %d\n", status, count))
 	}
@@ -3260,13 +4453,13 @@ func generateSummaryReport(report *strings.Builder, vocItems, vosItems []Feedbac
 		}
 	}
 
-	report.WriteString("## Category Distribution\n\n")
+	report.WriteString(fmt.Sprintf("## %s\n\n", l.T("report.category_distribution")))
 	if len(categoryCounts) > 0 {
 		for cat, count := range categoryCounts {
 			report.WriteString(fmt.Sprintf("- %s: %d\n", cat, count))
 		}
 	}
-	report.WriteString(fmt.Sprintf("- (uncategorized): %d\n", uncategorizedCount))
+	report.WriteString(fmt.Sprintf("- %s: %d\n", l.T("report.uncategorized"), uncategorizedCount))
 	report.WriteString("\n")
 
 	// Count synced vs unsynced
@@ -3283,26 +4476,51 @@ func generateSummaryReport(report *strings.Builder, vocItems, vosItems []Feedbac
 	}
 	unsyncedCount := len(vocItems) + len(vosItems) - syncedCount
 
-	report.WriteString("## Sync Status\n\n")
-	report.WriteString(fmt.Sprintf("- Synced with Fider: %d\n", syncedCount))
-	report.WriteString(fmt.Sprintf("- Local only: %d\n", unsyncedCount))
+	report.WriteString(fmt.Sprintf("## %s\n\n", l.T("report.sync_status")))
+	report.WriteString(fmt.Sprintf("- %s: %d\n", l.T("report.synced_with_fider"), syncedCount))
+	report.WriteString(fmt.Sprintf("- %s: %d\n", l.T("report.local_only"), unsyncedCount))
+
+	generateAttentionReport(report, l, allItems)
 }
 
-func generateDetailedReport(report *strings.Builder, items []FeedbackItem) {
-	report.WriteString("## All Feedback Items\n\n")
+// generateAttentionReport lists the most urgent and negative scored items
+func generateAttentionReport(report *strings.Builder, l *Locale, items []FeedbackItem) {
+	attention := attentionItems(items, 10)
+	if len(attention) == 0 {
+		return
+	}
+	report.WriteString(fmt.Sprintf("\n## %s\n\n", l.T("report.needs_attention")))
+	report.WriteString(l.T("report.attention_intro") + "\n\n")
+	report.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", l.T("report.id"), l.T("report.item_title"), l.T("report.urgency"), l.T("report.sentiment")))
+	report.WriteString("|-----|-------|---------|-----------|\n")
+	for _, item := range attention {
+		report.WriteString(fmt.Sprintf("| %s | %s | %.2f | %+.2f |\n",
+			item.ID, truncateStr(item.Title, 40), item.Scores.Urgency, item.Scores.Sentiment))
+	}
+}
+
+func generateDetailedReport(report *strings.Builder, l *Locale, items []FeedbackItem) {
+	report.WriteString(fmt.Sprintf("## %s\n\n", l.T("report.all_items")))
 
 	for _, item := range items {
 		report.WriteString(fmt.Sprintf("### %s: %s\n\n", item.ID, item.Title))
-		report.WriteString(fmt.Sprintf("- **Type**: %s\n", item.Type))
-		report.WriteString(fmt.Sprintf("- **Status**: %s\n", item.Status))
+		report.WriteString(fmt.Sprintf("- **%s**: %s\n", l.T("report.type"), item.Type))
+		report.WriteString(fmt.Sprintf("- **%s**: %s\n", l.T("report.status"), item.Status))
 		if len(item.Categories) > 0 {
-			report.WriteString(fmt.Sprintf("- **Categories**: %s\n", strings.Join(item.Categories, ", ")))
+			report.WriteString(fmt.Sprintf("- **%s**: %s\n", l.T("report.categories"), strings.Join(item.Categories, ", ")))
 		}
 		if item.ExternalID != "" {
-			report.WriteString(fmt.Sprintf("- **Fider ID**: %s\n", item.ExternalID))
+			report.WriteString(fmt.Sprintf("- **%s**: %s\n", l.T("report.fider_id"), item.ExternalID))
 		}
 		if item.Votes > 0 {
-			report.WriteString(fmt.Sprintf("- **Votes**: %d\n", item.Votes))
+			report.WriteString(fmt.Sprintf("- **%s**: %d\n", l.T("report.votes"), item.Votes))
+		}
+		if item.Estimate != "" || item.ActualEffort != "" {
+			report.WriteString(fmt.Sprintf("- **%s**: %s, **%s**: %s\n", l.T("effort.estimated"), firstNonEmpty(item.Estimate, "-"),
+				l.T("effort.actual"), firstNonEmpty(item.ActualEffort, "-")))
+		}
+		if item.Scores != nil {
+			report.WriteString(fmt.Sprintf("- **%s**: %.2f, **%s**: %+.2f\n", l.T("report.urgency"), item.Scores.Urgency, l.T("report.sentiment"), item.Scores.Sentiment))
 		}
 		report.WriteString("\n")
 		if item.Description != "" {
@@ -3312,9 +4530,10 @@ func generateDetailedReport(report *strings.Builder, items []FeedbackItem) {
 	}
 }
 
-func generateStatusReport(report *strings.Builder, items []FeedbackItem) {
-	report.WriteString("## Status Report\n\n")
-	report.WriteString("| ID | Title | Type | Status | Categories | Synced |\n")
+func generateStatusReport(report *strings.Builder, l *Locale, items []FeedbackItem) {
+	report.WriteString(fmt.Sprintf("## %s\n\n", l.T("report.status_report")))
+	report.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n", l.T("report.id"), l.T("report.item_title"),
+		l.T("report.type"), l.T("report.status"), l.T("report.categories"), l.T("report.synced")))
 	report.WriteString("|-----|-------|------|--------|------------|--------|\n")
 
 	for _, item := range items {
@@ -3322,9 +4541,9 @@ func generateStatusReport(report *strings.Builder, items []FeedbackItem) {
 		if status == "" {
 			status = "open"
 		}
-		synced := "No"
+		synced := l.T("report.no")
 		if item.ExternalID != "" {
-			synced = "Yes"
+			synced = l.T("report.yes")
 		}
 		categories := "-"
 		if len(item.Categories) > 0 {
@@ -3341,7 +4560,7 @@ func showReportHelp() {
 	fmt.Println("Generate a feedback report")
 	fmt.Println()
 	fmt.Println("Options:")
-	fmt.Println("  --type <type>   Report type: summary, detailed, status (default: summary)")
+	fmt.Println("  --type <type>   Report type: summary, detailed, status, sla, effort (default: summary)")
 	fmt.Println("  --output, -o    Output file (default: stdout)")
 	fmt.Println("  --help, -h      Show this help")
 	fmt.Println()
@@ -3349,6 +4568,7 @@ func showReportHelp() {
 	fmt.Println("  portunix pft report")
 	fmt.Println("  portunix pft report --type detailed")
 	fmt.Println("  portunix pft report --type status -o report.md")
+	fmt.Println("  portunix pft report --type effort")
 }
 
 func handleExportCommand(args []string) {
@@ -3434,6 +4654,11 @@ func handleExportCommand(args []string) {
 				item.ID, item.Title, item.Type, item.Status, categories, item.Votes, synced))
 		}
 		output = csv.String()
+	case "sprint":
+		if output, err = sprintCSV(allItems); err != nil {
+			fmt.Printf("Error creating CSV: %v\n", err)
+			return
+		}
 	default: // md
 		var md strings.Builder
 		md.WriteString(fmt.Sprintf("# Feedback Export: %s\n\n", config.Name))
@@ -3471,7 +4696,8 @@ func showExportHelp() {
 	fmt.Println("Export feedback items to various formats")
 	fmt.Println()
 	fmt.Println("Options:")
-	fmt.Println("  --format <fmt>  Export format: md, json, csv (default: md)")
+	fmt.Println("  --format <fmt>  Export format: md, json, csv, sprint (default: md)")
+	fmt.Println("                  sprint: CSV of unresolved items with effort in hours")
 	fmt.Println("  --output, -o    Output file (default: stdout)")
 	fmt.Println("  --voc           Export only VoC items")
 	fmt.Println("  --vos           Export only VoS items")
@@ -3481,637 +4707,2470 @@ func showExportHelp() {
 	fmt.Println("  portunix pft export")
 	fmt.Println("  portunix pft export --format json -o items.json")
 	fmt.Println("  portunix pft export --format csv --voc -o voc.csv")
+	fmt.Println("  portunix pft export --format sprint -o sprint.csv")
 }
 
-func handleCacheCommand(args []string) {
-	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
-		showCacheHelp()
-		return
-	}
-
-	subCmd := args[0]
-	subArgs := args[1:]
-
-	switch subCmd {
-	case "status":
-		handleCacheStatus(subArgs)
-	case "clear":
-		handleCacheClear(subArgs)
-	case "cleanup":
-		handleCacheCleanup(subArgs)
-	default:
-		fmt.Printf("Unknown cache subcommand: %s\n", subCmd)
-		showCacheHelp()
-	}
-}
+func handlePublishCommand(args []string) {
+	format := "site"
+	outputDir := "./public"
+	var title, configPath string
+	var publishVoC, publishVoS bool
 
-func handleCacheStatus(args []string) {
-	projectDir := "."
 	for i := 0; i < len(args); i++ {
-		if args[i] == "--path" && i+1 < len(args) {
-			projectDir = args[i+1]
-			i++
-		}
-	}
-
-	cache := NewSyncCache(projectDir)
-	if err := cache.Load(); err != nil {
-		fmt.Printf("Error loading cache: %v\n", err)
-		return
-	}
-
-	cache.PrintCacheStatus()
-
-	// Show recent entries
-	entries := cache.GetAll()
-	if len(entries) > 0 {
-		fmt.Println()
-		fmt.Println("Recent entries:")
-		count := min(5, len(entries))
-		for i := 0; i < count; i++ {
-			e := entries[i]
-			syncStatus := "not synced"
-			if e.ExternalID != "" {
-				syncStatus = fmt.Sprintf("synced (#%s)", e.ExternalID)
+		switch args[i] {
+		case "--format":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
 			}
-			fmt.Printf("   %s: %s [%s]\n", e.ID, truncate(e.Title, 40), syncStatus)
-		}
-		if len(entries) > 5 {
-			fmt.Printf("   ... and %d more\n", len(entries)-5)
+		case "--output", "-o":
+			if i+1 < len(args) {
+				outputDir = args[i+1]
+				i++
+			}
+		case "--title":
+			if i+1 < len(args) {
+				title = args[i+1]
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				configPath = args[i+1]
+				i++
+			}
+		case "--voc":
+			publishVoC = true
+		case "--vos":
+			publishVoS = true
+		case "--help", "-h":
+			showPublishHelp()
+			return
 		}
-	}
-}
-
-func handleCacheClear(args []string) {
-	projectDir := "."
-	for i := 0; i < len(args); i++ {
-		if args[i] == "--path" && i+1 < len(args) {
-			projectDir = args[i+1]
-			i++
+		if strings.HasPrefix(args[i], "--format=") {
+			format = strings.TrimPrefix(args[i], "--format=")
 		}
 	}
 
-	cache := NewSyncCache(projectDir)
-	if err := cache.Load(); err != nil {
-		fmt.Printf("Error loading cache: %v\n", err)
+	if format != "site" {
+		fmt.Printf("Error: unsupported publish format %q (supported: site)\n", format)
 		return
 	}
+	// The portal is customer-facing: stakeholder items only on request
+	if !publishVoC && !publishVoS {
+		publishVoC = true
+	}
 
-	entriesCount := len(cache.Entries)
-	cache.Clear()
-
-	if err := cache.Save(); err != nil {
-		fmt.Printf("Error saving cache: %v\n", err)
+	config, configFilePath, err := LoadConfigWithFilePath()
+	if err != nil && configPath == "" {
+		fmt.Println("No configuration found. Run 'portunix pft configure' first.")
 		return
 	}
-
-	fmt.Printf("✓ Cache cleared (%d entries removed)\n", entriesCount)
-}
-
-func handleCacheCleanup(args []string) {
-	projectDir := "."
-	for i := 0; i < len(args); i++ {
-		if args[i] == "--path" && i+1 < len(args) {
-			projectDir = args[i+1]
-			i++
-		}
+	if config == nil {
+		config = &Config{}
 	}
-
-	cache := NewSyncCache(projectDir)
-	if err := cache.Load(); err != nil {
-		fmt.Printf("Error loading cache: %v\n", err)
-		return
+	projectDir := ResolveProjectPath(config, configFilePath, configPath)
+	if title == "" {
+		title = config.Name
+	}
+	if title == "" {
+		title = "Product Feedback"
 	}
 
-	removed := cache.CleanupOrphans()
+	var allItems []FeedbackItem
+	if publishVoC {
+		vocItems, _ := scanLocalDirectory(getVoiceDir(projectDir, "voc"), "voc")
+		allItems = append(allItems, vocItems...)
+	}
+	if publishVoS {
+		vosItems, _ := scanLocalDirectory(getVoiceDir(projectDir, "vos"), "vos")
+		allItems = append(allItems, vosItems...)
+	}
 
-	if err := cache.Save(); err != nil {
-		fmt.Printf("Error saving cache: %v\n", err)
+	siteItems, err := PublishSite(outputDir, title, allItems)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	if removed > 0 {
-		fmt.Printf("✓ Cleaned up %d orphan entries\n", removed)
-	} else {
-		fmt.Println("✓ No orphan entries found")
+	counts := map[string]int{}
+	for _, item := range siteItems {
+		counts[item.Column]++
 	}
+	fmt.Printf("Published %d items to: %s\n", len(siteItems), outputDir)
+	for _, column := range roadmapColumns {
+		fmt.Printf("  %-14s %d\n", column.Label+":", counts[column.Key])
+	}
+	if counts["closed"] > 0 {
+		fmt.Printf("  %-14s %d\n", "Closed:", counts["closed"])
+	}
+	fmt.Println()
+	fmt.Printf("Open %s in a browser, or serve the directory with GitHub Pages or Caddy (file_server).\n", filepath.Join(outputDir, "index.html"))
 }
 
-func showCacheHelp() {
-	fmt.Println("Usage: portunix pft cache <subcommand> [options]")
-	fmt.Println()
-	fmt.Println("Manage local sync cache")
+func showPublishHelp() {
+	fmt.Println("Usage: portunix pft publish [options]")
 	fmt.Println()
-	fmt.Println("Subcommands:")
-	fmt.Println("  status    Show cache status and statistics")
-	fmt.Println("  clear     Clear all cache entries")
-	fmt.Println("  cleanup   Remove orphan entries (files that no longer exist)")
+	fmt.Println("Generate a static, read-only feedback portal from local markdown:")
+	fmt.Println("a roadmap board, the status and vote count of every item and one")
+	fmt.Println("page per item. Authors, scores and internal links are not published.")
 	fmt.Println()
 	fmt.Println("Options:")
-	fmt.Println("  --path <dir>  Project directory (default: current)")
-	fmt.Println("  --help, -h    Show this help")
+	fmt.Println("  --format <fmt>   Output format: site (default: site)")
+	fmt.Println("  --output, -o     Output directory (default: ./public)")
+	fmt.Println("  --title <text>   Portal title (default: project name)")
+	fmt.Println("  --voc            Publish VoC items (default)")
+	fmt.Println("  --vos            Publish VoS items")
+	fmt.Println("  --path <dir>     Project directory")
+	fmt.Println("  --help, -h       Show this help")
+	fmt.Println()
+	fmt.Println("The site uses relative links only and includes .nojekyll, so it can be")
+	fmt.Println("hosted on GitHub Pages or served by Caddy's file_server as-is.")
 	fmt.Println()
 	fmt.Println("Examples:")
-	fmt.Println("  portunix pft cache status")
-	fmt.Println("  portunix pft cache clear")
-	fmt.Println("  portunix pft cache cleanup --path ./my-project")
+	fmt.Println("  portunix pft publish --format site --output ./public")
+	fmt.Println("  portunix pft publish --voc --vos --title \"Acme Roadmap\"")
 }
 
-// Example command - creates demo with VoC/VoS structure and 2x Fider
-func handleExampleCommand(args []string) {
-	// Parse --path flag
-	demoPath := ""
-	noDeploy := false
+func handleReleaseNotesCommand(args []string) {
+	opts := ReleaseOptions{GroupBy: "category"}
+	var outputFile, configPath string
+	var includeVoC, includeVoS, jsonOutput bool
+
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
+		case "--since":
+			if i+1 < len(args) {
+				opts.Since = args[i+1]
+				i++
+			}
+		case "--until":
+			if i+1 < len(args) {
+				opts.Until = args[i+1]
+				i++
+			}
+		case "--group-by":
+			if i+1 < len(args) {
+				opts.GroupBy = args[i+1]
+				i++
+			}
+		case "--title":
+			if i+1 < len(args) {
+				opts.Title = args[i+1]
+				i++
+			}
+		case "--output", "-o":
+			if i+1 < len(args) {
+				outputFile = args[i+1]
+				i++
+			}
 		case "--path":
 			if i+1 < len(args) {
-				demoPath = args[i+1]
+				configPath = args[i+1]
 				i++
 			}
-		case "--no-deploy":
-			noDeploy = true
+		case "--voc":
+			includeVoC = true
+		case "--vos":
+			includeVoS = true
+		case "--json":
+			jsonOutput = true
 		case "--help", "-h":
-			showExampleHelp()
+			showReleaseNotesHelp()
 			return
 		}
 	}
 
-	// Default demo path
-	if demoPath == "" {
-		cwd, _ := os.Getwd()
-		demoPath = filepath.Join(cwd, "pft-demo")
-	}
-
-	// Resolve to absolute path
-	absPath, err := filepath.Abs(demoPath)
-	if err != nil {
-		fmt.Printf("Error resolving path: %v\n", err)
+	if opts.Since == "" {
+		fmt.Println("Error: --since <tag|commit|date> is required")
+		fmt.Println("Run 'portunix pft release-notes --help' for more information.")
 		return
 	}
-	demoPath = absPath
-
-	fmt.Println("Product Feedback Tool - Quick Example (ISO 16355 QFD)")
-	fmt.Println("=====================================================")
-	fmt.Println()
-
-	// Step 1: Create demo directory structure
-	fmt.Printf("1. Creating demo directory structure: %s\n", demoPath)
-	vocPath := filepath.Join(demoPath, "voc")
-	vosPath := filepath.Join(demoPath, "vos")
-
-	if err := os.MkdirAll(vocPath, 0755); err != nil {
-		fmt.Printf("   Error creating voc/: %v\n", err)
+	if opts.GroupBy != "category" && opts.GroupBy != "product" {
+		fmt.Printf("Error: invalid --group-by %q (use category or product)\n", opts.GroupBy)
 		return
 	}
-	fmt.Println("   ✓ Created: voc/ (Voice of Customer - public)")
+	if !includeVoC && !includeVoS {
+		includeVoC = true
+		includeVoS = true
+	}
 
-	if err := os.MkdirAll(vosPath, 0755); err != nil {
-		fmt.Printf("   Error creating vos/: %v\n", err)
+	config, configFilePath, err := LoadConfigWithFilePath()
+	if err != nil && configPath == "" {
+		fmt.Println("No configuration found. Run 'portunix pft configure' first.")
 		return
 	}
-	fmt.Println("   ✓ Created: vos/ (Voice of Stakeholder - internal)")
+	if config == nil {
+		config = &Config{}
+	}
+	projectDir := ResolveProjectPath(config, configFilePath, configPath)
+	if opts.Title == "" {
+		opts.Title = config.Name
+	}
 
-	// Step 2: Create VoC samples (customer feedback)
-	fmt.Println()
-	fmt.Println("2. Creating VoC samples (customer feedback)...")
-	vocSamples := getVoCSamples()
-	for _, sample := range vocSamples {
-		samplePath := filepath.Join(vocPath, sample.Filename)
-		if err := os.WriteFile(samplePath, []byte(sample.Content), 0644); err != nil {
-			fmt.Printf("   Error creating %s: %v\n", sample.Filename, err)
+	var allItems []FeedbackItem
+	categoryNames := map[string]string{}
+	for _, area := range []string{"voc", "vos"} {
+		if (area == "voc" && !includeVoC) || (area == "vos" && !includeVoS) {
 			continue
 		}
-		fmt.Printf("   ✓ voc/%s: %s\n", sample.Filename, sample.Title)
+		items, _ := scanLocalDirectory(getVoiceDir(projectDir, area), area)
+		allItems = append(allItems, items...)
+		if registry, err := LoadCategoryRegistry(projectDir, area); err == nil {
+			for _, category := range registry.Categories {
+				categoryNames[category.ID] = category.Name
+			}
+		}
 	}
 
-	// Step 3: Create VoS samples (stakeholder requirements)
-	fmt.Println()
-	fmt.Println("3. Creating VoS samples (stakeholder requirements)...")
-	vosSamples := getVoSSamples()
-	for _, sample := range vosSamples {
-		samplePath := filepath.Join(vosPath, sample.Filename)
-		if err := os.WriteFile(samplePath, []byte(sample.Content), 0644); err != nil {
-			fmt.Printf("   Error creating %s: %v\n", sample.Filename, err)
-			continue
+	notes, err := CollectReleaseNotes(projectDir, allItems, categoryNames, opts)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	output := notes.Markdown()
+	if jsonOutput {
+		data, err := json.MarshalIndent(notes, "", "  ")
+		if err != nil {
+			fmt.Printf("Error creating JSON: %v\n", err)
+			return
 		}
-		fmt.Printf("   ✓ vos/%s: %s\n", sample.Filename, sample.Title)
+		output = string(data)
+	}
+	if outputFile == "" {
+		fmt.Println(output)
+		return
+	}
+	if err := os.WriteFile(outputFile, []byte(output), 0644); err != nil {
+		fmt.Printf("Error writing release notes: %v\n", err)
+		return
 	}
+	fmt.Printf("Release notes with %d items written to: %s\n", len(notes.Entries), outputFile)
+}
 
-	// Step 4: Configure ptx-pft
+func showReleaseNotesHelp() {
+	fmt.Println("Usage: portunix pft release-notes --since <tag|commit|date> [options]")
 	fmt.Println()
-	fmt.Println("4. Configuring ptx-pft...")
-	config := NewDefaultConfig()
-	config.Name = "Demo Product"
-	config.Path = demoPath
-	// Configure VoC with Fider
-	config.VoC = &AreaConfig{
-		Provider: "fider",
-		URL:      "http://localhost:3000",
-	}
+	fmt.Println("Generate customer-facing release notes from feedback items delivered")
+	fmt.Println("since a git tag, commit or date. An item is included when its status")
+	fmt.Println("changed to implemented/released/done in the range, or when a commit in")
+	fmt.Println("the range references the issue linked with 'pft link'.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --since <rev>       Git tag, commit or date YYYY-MM-DD (required)")
+	fmt.Println("  --until <rev>       End of the range (default: HEAD and working tree)")
+	fmt.Println("  --group-by <field>  Group by category or product (default: category)")
+	fmt.Println("  --title <text>      Title (default: project name)")
+	fmt.Println("  --output, -o        Output file (default: stdout)")
+	fmt.Println("  --voc               Only VoC items")
+	fmt.Println("  --vos               Only VoS items")
+	fmt.Println("  --json              Output entries as JSON")
+	fmt.Println("  --path <dir>        Project directory")
+	fmt.Println("  --help, -h          Show this help")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft release-notes --since v1.3.0")
+	fmt.Println("  portunix pft release-notes --since v1.3.0 --until v1.4.0 -o RELEASE-NOTES.md")
+	fmt.Println("  portunix pft release-notes --since 2026-01-01 --group-by product")
+}
 
-	if err := config.Save(demoPath); err != nil {
-		fmt.Printf("   Error saving config: %v\n", err)
+func handleBoardCommand(args []string) {
+	area := "voc"
+	var configPath string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--area":
+			if i+1 < len(args) {
+				area = strings.ToLower(args[i+1])
+				i++
+			}
+		case "--voc", "--vos", "--vob", "--voe":
+			area = strings.TrimPrefix(args[i], "--")
+		case "--path":
+			if i+1 < len(args) {
+				configPath = args[i+1]
+				i++
+			}
+		case "--help", "-h":
+			showBoardHelp()
+			return
+		}
+	}
+	if !IsValidArea(area) {
+		fmt.Printf("Error: invalid area '%s'. Valid options: voc, vos, vob, voe\n", area)
 		return
 	}
-	fmt.Printf("   ✓ Configuration saved to %s\n", GetConfigPath(demoPath))
 
-	// Step 5: Deploy feedback tools (unless --no-deploy)
-	if noDeploy {
-		fmt.Println()
-		fmt.Println("5. Skipping deployment (--no-deploy flag)")
-		fmt.Println()
-		showExampleSummary(demoPath, vocSamples, vosSamples, false)
+	config, configFilePath, err := loadOrCreateConfig(configPath)
+	if err != nil {
+		fmt.Printf("Error loading configuration: %v\n", err)
 		return
 	}
+	projectDir := ResolveProjectPath(config, configFilePath, configPath)
+
+	if err := tui.RunScreen(NewBoard(projectDir, area)); err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
 
+func showBoardHelp() {
+	fmt.Println("Usage: portunix pft board [options]")
 	fmt.Println()
-	fmt.Println("5. Checking compose readiness...")
+	fmt.Println("Interactive kanban board of the items of an area. Columns are statuses;")
+	fmt.Println("every change is written to the item markdown files right away.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --area <area>   Area to show (voc, vos, vob, voe; default: voc)")
+	fmt.Println("  --path <dir>    Project directory")
+	fmt.Println("  --help, -h      Show this help")
+	fmt.Println()
+	fmt.Println("Keys:")
+	fmt.Println("  ←/→, h/l        Select column")
+	fmt.Println("  ↑/↓, k/j        Select item")
+	fmt.Println("  <, >            Move item to the previous/next column (sets its status)")
+	fmt.Println("  1-6             Move item to column 1-6")
+	fmt.Println("  Enter, o        Open the item file")
+	fmt.Println("  c               Add or remove a category")
+	fmt.Println("  u               Assign a user of the user registry")
+	fmt.Println("  Tab             Switch area")
+	fmt.Println("  R               Reload")
+	fmt.Println("  q, Esc          Quit")
+	fmt.Println()
+	fmt.Println("Columns: Open, Under Review, Planned, In Progress, Done, Closed (declined)")
+}
 
-	// Preflight check - verify compose is ready before attempting deployment
-	preflight, err := CheckComposePreflight()
-	if err != nil {
-		fmt.Printf("   ⚠ Could not check compose readiness: %v\n", err)
-		fmt.Println()
-		showExampleSummary(demoPath, vocSamples, vosSamples, false)
+func handleSLACommand(args []string) {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		showSLAHelp()
 		return
 	}
+	switch args[0] {
+	case "status":
+		handleSLAStatus(args[1:])
+	default:
+		fmt.Printf("Unknown sla subcommand: %s\n", args[0])
+		showSLAHelp()
+	}
+}
 
-	if !preflight.Ready {
-		fmt.Println("   ❌ Compose is NOT ready")
-		fmt.Println()
-		fmt.Printf("   Problem: %s\n", preflight.ErrorMessage)
-		fmt.Println()
+func handleSLAStatus(args []string) {
+	var area, configPath string
+	var showAll, jsonOutput bool
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--area":
+			if i+1 < len(args) {
+				area = strings.ToLower(args[i+1])
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				configPath = args[i+1]
+				i++
+			}
+		case "--all", "-a":
+			showAll = true
+		case "--json":
+			jsonOutput = true
+		case "--help", "-h":
+			showSLAHelp()
+			return
+		}
+	}
 
-		// Check if we can offer to fix it automatically (no sudo needed for user socket)
-		if strings.Contains(preflight.FixInstructions, "systemctl --user") {
-			fmt.Printf("   To fix this, run: %s\n", preflight.FixInstructions)
-			fmt.Println()
-			fmt.Print("   Do you want to run this command now? [Y/n]: ")
+	config, configFilePath, err := LoadConfigWithFilePath()
+	if err != nil && configPath == "" {
+		fmt.Println("No configuration found. Run 'portunix pft configure' first.")
+		return
+	}
+	if config == nil {
+		config = &Config{}
+	}
+	projectDir := ResolveProjectPath(config, configFilePath, configPath)
 
-			reader := bufio.NewReader(os.Stdin)
-			answer, _ := reader.ReadString('\n')
-			answer = strings.TrimSpace(strings.ToLower(answer))
+	var checks []SLACheck
+	for _, check := range collectSLAChecks(config, projectDir, time.Now()) {
+		if area == "" || check.Area == area {
+			checks = append(checks, check)
+		}
+	}
+	if len(checks) == 0 && !jsonOutput {
+		fmt.Println("No SLA policies configured or no items with a creation date.")
+		fmt.Println("Example: portunix pft configure --area voc --sla-triage-days 5")
+		return
+	}
 
-			if answer == "" || answer == "y" || answer == "yes" || answer == "a" || answer == "ano" {
-				fmt.Println()
-				fmt.Printf("   Running: %s\n", preflight.FixInstructions)
+	var listed []SLACheck
+	for _, check := range checks {
+		if showAll || check.State == SLABreached || check.State == SLAUpcoming {
+			listed = append(listed, check)
+		}
+	}
 
-				// Parse and execute the command
-				parts := strings.Fields(preflight.FixInstructions)
-				cmd := exec.Command(parts[0], parts[1:]...)
-				cmd.Stdout = os.Stdout
-				cmd.Stderr = os.Stderr
+	if jsonOutput {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"metrics": SummarizeSLA(checks),
+			"checks":  listed,
+		}, "", "  ")
+		if err != nil {
+			fmt.Printf("Error creating JSON: %v\n", err)
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
 
-				if err := cmd.Run(); err != nil {
-					fmt.Printf("   ❌ Command failed: %v\n", err)
-					fmt.Println()
-					showExampleSummary(demoPath, vocSamples, vosSamples, false)
+	fmt.Println("SLA compliance:")
+	for _, m := range SummarizeSLA(checks) {
+		fmt.Printf("  %-4s %-8s %3.0f%%  met %d, missed %d, breached %d, upcoming %d",
+			strings.ToUpper(m.Area), m.Target, m.Compliance()*100, m.Met, m.Missed, m.Breached, m.Upcoming)
+		if m.AvgDays > 0 {
+			fmt.Printf(", avg %.1f days", m.AvgDays)
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+
+	if len(listed) == 0 {
+		fmt.Println("✓ No SLA breaches or upcoming deadlines")
+		return
+	}
+	fmt.Printf("%-10s %-4s %-8s %-10s %-24s %s\n", "ID", "AREA", "TARGET", "DEADLINE", "STATE", "TITLE")
+	for _, check := range listed {
+		mark := " "
+		switch check.State {
+		case SLABreached:
+			mark = "✗"
+		case SLAUpcoming:
+			mark = "!"
+		}
+		fmt.Printf("%-10s %-4s %-8s %-10s %s %-22s %s\n", check.ItemID, strings.ToUpper(check.Area), check.Target,
+			check.Deadline.Format("2006-01-02"), mark, slaStateText(check), truncateStr(check.Title, 40))
+	}
+}
+
+func handleMetricsCommand(args []string) {
+	var listen, textfile, configPath string
+	var interval time.Duration
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--listen":
+			listen = DefaultMetricsListen
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				listen = args[i+1]
+				i++
+			}
+		case "--textfile":
+			if i+1 < len(args) {
+				textfile = args[i+1]
+				i++
+			}
+		case "--interval":
+			if i+1 < len(args) {
+				d, err := time.ParseDuration(args[i+1])
+				if err != nil || d < time.Second {
+					fmt.Printf("Error: invalid --interval '%s' (e.g. 1m)\n", args[i+1])
 					return
 				}
+				interval = d
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				configPath = args[i+1]
+				i++
+			}
+		case "--help", "-h":
+			showMetricsHelp()
+			return
+		}
+	}
 
-				fmt.Println("   ✓ Socket started successfully")
-				fmt.Println()
+	config, configFilePath, err := loadOrCreateConfig(configPath)
+	if err != nil {
+		fmt.Printf("Error loading configuration: %v\n", err)
+		return
+	}
+	projectDir := ResolveProjectPath(config, configFilePath, configPath)
 
-				// Re-check preflight
-				preflight, err = CheckComposePreflight()
-				if err != nil || !preflight.Ready {
-					fmt.Println("   ⚠ Socket started but compose still not ready")
-					fmt.Println("   Please try running 'portunix pft example' again.")
-					fmt.Println()
-					showExampleSummary(demoPath, vocSamples, vosSamples, false)
+	switch {
+	case listen != "":
+		fmt.Printf("Serving metrics on http://%s/metrics\n", listen)
+		if err := http.ListenAndServe(listen, MetricsHandler(config, projectDir)); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	case textfile != "":
+		for {
+			if err := WriteMetricsTextfile(textfile, config, projectDir); err != nil {
+				logging.Capture(zerolog.ErrorLevel, "metrics textfile failed", "file", textfile, "error", err)
+				fmt.Printf("Error: %v\n", err)
+				if interval == 0 {
 					return
 				}
-				fmt.Println("   ✓ Compose is now ready")
-			} else {
-				fmt.Println()
-				fmt.Println("   After fixing the issue, run 'portunix pft example' again.")
-				fmt.Println()
-				showExampleSummary(demoPath, vocSamples, vosSamples, false)
+			}
+			if interval == 0 {
+				fmt.Printf("✓ Metrics written to %s\n", textfile)
 				return
 			}
-		} else {
-			fmt.Printf("   Solution: %s\n", preflight.FixInstructions)
-			fmt.Println()
-			fmt.Println("   After fixing the issue, run 'portunix pft example' again.")
-			fmt.Println()
-			showExampleSummary(demoPath, vocSamples, vosSamples, false)
-			return
+			time.Sleep(interval)
+		}
+	default:
+		if err := WriteMetrics(os.Stdout, config, projectDir, time.Now()); err != nil {
+			fmt.Printf("Error: %v\n", err)
 		}
-	} else {
-		fmt.Println("   ✓ Compose is ready")
 	}
+}
 
+func showMetricsHelp() {
+	fmt.Println("Usage: portunix pft metrics [options]")
 	fmt.Println()
-	fmt.Println("6. Deploying feedback tools (2x Fider)...")
-
-	// Deploy VoC Fider (port 3100)
+	fmt.Println("Export the feedback flow as Prometheus metrics: items by area and status,")
+	fmt.Println("items not yet synced, last sync times and lag, sync failures and sent and")
+	fmt.Println("failed notifications. Sync runs and notifications are recorded in")
+	fmt.Println(".pft-metrics.json by 'pft sync', 'pft notify' and 'pft intake email'.")
+	fmt.Println("Without options the metrics are printed once.")
 	fmt.Println()
-	fmt.Println("   6a. VoC Fider (public, port 3100)...")
-	vocConfig := NewDefaultConfig()
-	vocConfig.Name = "Demo Product - VoC"
-	vocConfig.Path = vocPath
-	vocConfig.VoC = &AreaConfig{
-		Provider: "fider",
-		URL:      "http://localhost:3100",
-	}
-
-	vocResult, vocErr := DeployInstance("voc", 3100, vocConfig)
-	if vocErr != nil {
-		fmt.Printf("   ⚠ VoC deployment failed: %v\n", vocErr)
-	} else {
-		fmt.Println("   ✓ VoC Fider deployed on port 3100")
-		_ = vocResult
-	}
+	fmt.Println("Options:")
+	fmt.Println("  --listen [addr]     Serve /metrics for Prometheus (default: :9301)")
+	fmt.Println("  --textfile <file>   Write a .prom file for the node_exporter textfile collector")
+	fmt.Println("  --interval <dur>    Rewrite the textfile periodically, e.g. 1m")
+	fmt.Println("  --path <dir>        Project directory")
+	fmt.Println("  --help, -h          Show this help")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft metrics --listen :9301")
+	fmt.Println("  portunix pft metrics --textfile /var/lib/node_exporter/textfile/pft.prom --interval 1m")
+}
 
-	// Deploy VoS Fider (port 3101)
+func showSLAHelp() {
+	fmt.Println("Usage: portunix pft sla status [options]")
 	fmt.Println()
-	fmt.Println("   6b. VoS Fider (internal, port 3101)...")
-	vosConfig := NewDefaultConfig()
-	vosConfig.Name = "Demo Product - VoS"
-	vosConfig.Path = vosPath
-	vosConfig.VoS = &AreaConfig{
-		Provider: "fider",
-		URL:      "http://localhost:3101",
+	fmt.Println("Track feedback response times against per-area SLA policies. Items")
+	fmt.Println("must leave the open status (triage) and be implemented or declined")
+	fmt.Println("(resolve) within the configured number of business days, counted from")
+	fmt.Println("their creation date. 'pft update --status' records when that happened.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --area <area>   Only one area (voc, vos, vob, voe)")
+	fmt.Println("  --all, -a       Also list met, missed and on-track targets")
+	fmt.Println("  --json          Output metrics and checks as JSON")
+	fmt.Println("  --path <dir>    Project directory")
+	fmt.Println("  --help, -h      Show this help")
+	fmt.Println()
+	fmt.Println("Policies are set per area in .pft-config.json:")
+	fmt.Println("  \"voc\": {\"sla\": {\"triage_days\": 5, \"resolve_days\": 60}}")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft configure --area voc --sla-triage-days 5 --sla-resolve-days 60")
+	fmt.Println("  portunix pft sla status")
+	fmt.Println("  portunix pft sla status --area voc --all")
+	fmt.Println("  portunix pft report --type sla")
+}
+
+func handleIntakeCommand(args []string) {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		showIntakeHelp()
+		return
+	}
+	switch args[0] {
+	case "email":
+		handleIntakeEmail(args[1:])
+	case "chat":
+		handleIntakeChat(args[1:])
+	default:
+		fmt.Printf("Unknown intake source: %s\n", args[0])
+		showIntakeHelp()
 	}
+}
 
-	vosResult, vosErr := DeployInstance("vos", 3101, vosConfig)
-	if vosErr != nil {
-		fmt.Printf("   ⚠ VoS deployment failed: %v\n", vosErr)
-	} else {
-		fmt.Println("   ✓ VoS Fider deployed on port 3101")
-		_ = vosResult
+func handleIntakeEmail(args []string) {
+	var imapAddr, user, password, mailbox, area, configPath string
+	var interval time.Duration
+	var plaintext, autoReply, dryRun, jsonOutput bool
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--imap":
+			if i+1 < len(args) {
+				imapAddr = args[i+1]
+				i++
+			}
+		case "--user":
+			if i+1 < len(args) {
+				user = args[i+1]
+				i++
+			}
+		case "--password":
+			if i+1 < len(args) {
+				password = args[i+1]
+				i++
+			}
+		case "--mailbox":
+			if i+1 < len(args) {
+				mailbox = args[i+1]
+				i++
+			}
+		case "--area":
+			if i+1 < len(args) {
+				area = strings.ToLower(args[i+1])
+				i++
+			}
+		case "--interval":
+			if i+1 < len(args) {
+				d, err := time.ParseDuration(args[i+1])
+				if err != nil || d < time.Minute {
+					fmt.Printf("Error: invalid --interval '%s' (e.g. 5m, at least 1m)\n", args[i+1])
+					return
+				}
+				interval = d
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				configPath = args[i+1]
+				i++
+			}
+		case "--plaintext":
+			plaintext = true
+		case "--auto-reply":
+			autoReply = true
+		case "--dry-run":
+			dryRun = true
+		case "--json":
+			jsonOutput = true
+		case "--help", "-h":
+			showIntakeHelp()
+			return
+		}
 	}
 
-	fmt.Println()
-	showExampleSummary(demoPath, vocSamples, vosSamples, vocErr == nil && vosErr == nil)
+	config, configFilePath, err := loadOrCreateConfig(configPath)
+	if err != nil {
+		fmt.Printf("Error loading configuration: %v\n", err)
+		return
+	}
+	projectDir := ResolveProjectPath(config, configFilePath, configPath)
+
+	// Command line flags override the configured mailbox
+	imap := IMAPConfig{}
+	if config.IMAP != nil {
+		imap = *config.IMAP
+	}
+	if imapAddr != "" {
+		host, port, err := net.SplitHostPort(imapAddr)
+		if err != nil {
+			host, port = imapAddr, ""
+		}
+		imap.Host = host
+		imap.Port, _ = strconv.Atoi(port)
+	}
+	if user != "" {
+		imap.Username = user
+	}
+	if password != "" {
+		imap.Password = password
+	}
+	if mailbox != "" {
+		imap.Mailbox = mailbox
+	}
+	if plaintext {
+		imap.Plaintext = true
+	}
+	if imap.Host == "" {
+		fmt.Println("Error: no mailbox configured. Use --imap <host[:port]> or 'pft configure --imap-host <host>'")
+		return
+	}
+	opts := IntakeOptions{Area: imap.Area, AutoReply: autoReply || imap.AutoReply, DryRun: dryRun}
+	if area != "" {
+		opts.Area = area
+	}
+	if opts.Area != "" && !IsValidArea(opts.Area) {
+		fmt.Printf("Error: invalid area '%s'. Valid options: voc, vos, vob, voe\n", opts.Area)
+		return
+	}
+
+	for {
+		entries, err := RunEmailIntake(config, projectDir, imap, opts)
+		printIntakeEntries(entries, jsonOutput, dryRun)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			if interval == 0 {
+				return
+			}
+		}
+		if interval == 0 {
+			return
+		}
+		time.Sleep(interval)
+	}
 }
 
-func showExampleSummary(demoPath string, vocSamples, vosSamples []SampleDocument, deployed bool) {
-	fmt.Println("=====================================================")
-	fmt.Println("Demo setup complete!")
-	fmt.Println()
-	fmt.Println("Directory structure:")
-	fmt.Printf("  %s/\n", demoPath)
-	fmt.Println("  ├── voc/                 (Voice of Customer - public)")
-	for _, s := range vocSamples {
-		fmt.Printf("  │   └── %s\n", s.Filename)
+func handleIntakeChat(args []string) {
+	listen := ":8085"
+	var area, configPath string
+	var dryRun bool
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--listen":
+			if i+1 < len(args) {
+				listen = args[i+1]
+				i++
+			}
+		case "--area":
+			if i+1 < len(args) {
+				area = strings.ToLower(args[i+1])
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				configPath = args[i+1]
+				i++
+			}
+		case "--dry-run":
+			dryRun = true
+		case "--help", "-h":
+			showIntakeHelp()
+			return
+		}
 	}
-	fmt.Println("  ├── vos/                 (Voice of Stakeholder - internal)")
-	for _, s := range vosSamples {
-		fmt.Printf("  │   └── %s\n", s.Filename)
+
+	config, configFilePath, err := loadOrCreateConfig(configPath)
+	if err != nil {
+		fmt.Printf("Error loading configuration: %v\n", err)
+		return
 	}
-	fmt.Println("  └── .pft-config.json")
-	fmt.Println()
+	projectDir := ResolveProjectPath(config, configFilePath, configPath)
 
-	if deployed {
-		fmt.Println("Fider instances:")
-		fmt.Println("  VoC (public):   http://localhost:3100")
-		fmt.Println("  VoS (internal): http://localhost:3101")
-		fmt.Println()
-		fmt.Println("Email capture (Mailhog):")
-		fmt.Println("  VoC Mailhog:    http://localhost:3200")
-		fmt.Println("  VoS Mailhog:    http://localhost:3201")
-		fmt.Println()
-		fmt.Println("Registration steps:")
-		fmt.Println("  1. Open http://localhost:3100 (VoC Fider)")
-		fmt.Println("  2. Fill in the signup form:")
-		fmt.Println("       - Your name: e.g., 'Admin'")
-		fmt.Println("       - Email: e.g., 'admin@local.test' (fake, captured by Mailhog)")
-		fmt.Println("       - Site name: e.g., 'Customer Feedback'")
-		fmt.Println("  3. Open http://localhost:3200 (Mailhog)")
-		fmt.Println("  4. Click confirmation link in the email")
-		fmt.Println("  5. Repeat for VoS (ports 3101/3201, site: 'Stakeholder Requirements')")
-		fmt.Println()
-		fmt.Println("To stop and remove:")
-		fmt.Println("  portunix pft destroy           # keep data")
-		fmt.Println("  portunix pft destroy --volumes # remove everything")
-	} else {
-		fmt.Println("To deploy manually:")
-		fmt.Printf("  cd %s && portunix pft deploy\n", demoPath)
+	opts := IntakeOptions{DryRun: dryRun}
+	if config.Chat != nil {
+		opts.Area = config.Chat.Area
+	}
+	if area != "" {
+		opts.Area = area
+	}
+	if opts.Area != "" && !IsValidArea(opts.Area) {
+		fmt.Printf("Error: invalid area '%s'. Valid options: voc, vos, vob, voe\n", opts.Area)
+		return
+	}
+
+	server := &ChatIntakeServer{Config: config, ProjectDir: projectDir, Options: opts}
+	endpoints := server.Endpoints()
+	if len(endpoints) == 0 {
+		fmt.Println("Error: no chat intake configured. Use 'pft configure --slack-signing-secret <secret>'")
+		fmt.Println("or 'pft configure --teams-secret <token>' to accept signed requests.")
+		return
+	}
+	fmt.Printf("Listening on %s\n", listen)
+	for _, endpoint := range endpoints {
+		fmt.Printf("  POST %s\n", endpoint)
+	}
+	if err := http.ListenAndServe(listen, server.Handler()); err != nil {
+		fmt.Printf("Error: %v\n", err)
 	}
 }
 
-func showExampleHelp() {
-	fmt.Println("Usage: portunix pft example [options]")
+func printIntakeEntries(entries []IntakeEntry, jsonOutput, dryRun bool) {
+	if jsonOutput {
+		data, _ := json.MarshalIndent(entries, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	if len(entries) == 0 {
+		fmt.Printf("[%s] No new messages\n", time.Now().Format("15:04:05"))
+		return
+	}
+	prefix := ""
+	if dryRun {
+		prefix = "[dry-run] "
+	}
+	for _, entry := range entries {
+		switch {
+		case entry.Error != "" && entry.ItemID == "":
+			fmt.Printf("%s✗ %s\n", prefix, entry.Error)
+		case entry.Outcome == IntakeCreated:
+			fmt.Printf("%s✓ %s created from %s: %s\n", prefix, entry.ItemID, entry.From, truncateStr(entry.Subject, 50))
+		case entry.Outcome == IntakeFollowUp:
+			fmt.Printf("%s↳ %s follow-up from %s\n", prefix, entry.ItemID, entry.From)
+		default:
+			fmt.Printf("%s- %s already imported\n", prefix, entry.ItemID)
+		}
+		if entry.Replied {
+			fmt.Printf("  Auto-reply sent to %s\n", entry.From)
+		}
+		if entry.Error != "" && entry.ItemID != "" {
+			fmt.Printf("  Warning: %s\n", entry.Error)
+		}
+	}
+}
+
+func showIntakeHelp() {
+	fmt.Println("Usage: portunix pft intake <email|chat> [options]")
 	fmt.Println()
-	fmt.Println("Creates a demo with VoC/VoS structure per ISO 16355 QFD.")
+	fmt.Println("Email:")
+	fmt.Println()
+	fmt.Println("Import unread emails of an IMAP mailbox as feedback items. The subject")
+	fmt.Println("becomes the title, the sender the author and the body (without quoted")
+	fmt.Println("replies and signature) the verbatim. Replies to imported messages, or")
+	fmt.Println("messages with [<item-id>] in the subject, are added to the existing item.")
+	fmt.Println("Processed messages are flagged as seen and tracked in .pft-intake.json.")
 	fmt.Println()
 	fmt.Println("Options:")
-	fmt.Println("  --path <path>   Directory for demo files (default: ./pft-demo)")
-	fmt.Println("  --no-deploy     Create files only, don't deploy containers")
+	fmt.Println("  --imap <host[:port]>  IMAP server (default: from 'pft configure --imap-host')")
+	fmt.Println("  --user <user>         IMAP username")
+	fmt.Println("  --password <pass>     IMAP password or secret:<name> reference")
+	fmt.Println("  --mailbox <name>      Mailbox to poll (default: INBOX)")
+	fmt.Println("  --plaintext           Connect without TLS (default port 143)")
+	fmt.Println("  --area <area>         Area of created items (default: voc)")
+	fmt.Println("  --auto-reply          Reply to senders with the item ID (uses SMTP settings)")
+	fmt.Println("  --interval <dur>      Keep polling, e.g. 5m (default: single run)")
+	fmt.Println("  --dry-run             Show what would be imported without changes")
+	fmt.Println("  --json                Output results as JSON")
+	fmt.Println("  --path <dir>          Project directory")
+	fmt.Println("  --help, -h            Show this help")
 	fmt.Println()
-	fmt.Println("This command will:")
-	fmt.Println("  1. Create voc/ directory with 3 customer feedback samples")
-	fmt.Println("  2. Create vos/ directory with 3 stakeholder requirement samples")
-	fmt.Println("  3. Deploy 2x Fider instances:")
-	fmt.Println("     - VoC Fider on port 3100 (public, customer-facing)")
-	fmt.Println("     - VoS Fider on port 3101 (internal, stakeholders)")
+	fmt.Println("Chat:")
 	fmt.Println()
-	fmt.Println("VoC = Voice of Customer (public feedback)")
-	fmt.Println("VoS = Voice of Stakeholder (internal requirements)")
+	fmt.Println("Serve a Slack slash command (POST /slack/command) and a Teams outgoing")
+	fmt.Println("webhook (POST /teams/webhook) creating items from chat messages. The first")
+	fmt.Println("line becomes the title; [<item-id>] adds the message to an existing item.")
+	fmt.Println("Requests must be signed with the configured Slack signing secret or Teams")
+	fmt.Println("security token.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --listen <addr>       Listen address (default: :8085)")
+	fmt.Println("  --area <area>         Area of created items (default: voc)")
+	fmt.Println("  --dry-run             Reply without creating items")
+	fmt.Println("  --path <dir>          Project directory")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft configure --imap-host imap.example.com --imap-user feedback@example.com --imap-pass ...")
+	fmt.Println("  portunix pft intake email --auto-reply")
+	fmt.Println("  portunix pft intake email --imap imap.example.com:993 --user feedback@example.com --password secret:pft/imap")
+	fmt.Println("  portunix pft intake email --interval 5m")
+	fmt.Println("  portunix pft configure --slack-signing-secret ... --teams-secret ...")
+	fmt.Println("  portunix pft intake chat --listen :8085")
 }
 
-// SampleDocument represents a sample document (VoC or VoS)
-type SampleDocument struct {
-	Filename string
-	Title    string
-	Content  string
-}
+func handleCacheCommand(args []string) {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		showCacheHelp()
+		return
+	}
 
-// getVoCSamples returns Voice of Customer sample documents
-func getVoCSamples() []SampleDocument {
-	return []SampleDocument{
-		{
-			Filename: "UC001-user-login.md",
-			Title:    "User Login",
-			Content: `# UC001: User Login
+	subCmd := args[0]
+	subArgs := args[1:]
 
-## Summary
-As a user, I want to log into the system securely so that I can access my personal dashboard.
+	switch subCmd {
+	case "status":
+		handleCacheStatus(subArgs)
+	case "clear":
+		handleCacheClear(subArgs)
+	case "cleanup":
+		handleCacheCleanup(subArgs)
+	default:
+		fmt.Printf("Unknown cache subcommand: %s\n", subCmd)
+		showCacheHelp()
+	}
+}
 
-## Priority
-High
+func handleCacheStatus(args []string) {
+	projectDir := "."
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--path" && i+1 < len(args) {
+			projectDir = args[i+1]
+			i++
+		}
+	}
 
-## Status
-Open
+	cache := NewSyncCache(projectDir)
+	if err := cache.Load(); err != nil {
+		fmt.Printf("Error loading cache: %v\n", err)
+		return
+	}
 
-## Description
-Users need a secure authentication mechanism to access protected areas of the application.
+	cache.PrintCacheStatus()
 
-### Acceptance Criteria
-- [ ] User can enter username/email and password
-- [ ] System validates credentials against database
-- [ ] Failed attempts are logged and rate-limited
-- [ ] Successful login redirects to dashboard
-- [ ] Session is created with appropriate timeout
-`,
-		},
-		{
-			Filename: "UC002-data-export.md",
-			Title:    "Data Export",
-			Content: `# UC002: Data Export
+	// Show recent entries
+	entries := cache.GetAll()
+	if len(entries) > 0 {
+		fmt.Println()
+		fmt.Println("Recent entries:")
+		count := min(5, len(entries))
+		for i := 0; i < count; i++ {
+			e := entries[i]
+			syncStatus := "not synced"
+			if e.ExternalID != "" {
+				syncStatus = fmt.Sprintf("synced (#%s)", e.ExternalID)
+			}
+			fmt.Printf("   %s: %s [%s]\n", e.ID, truncate(e.Title, 40), syncStatus)
+		}
+		if len(entries) > 5 {
+			fmt.Printf("   ... and %d more\n", len(entries)-5)
+		}
+	}
+}
 
-## Summary
-As a user, I want to export my data in various formats so that I can use it in other applications.
+func handleCacheClear(args []string) {
+	projectDir := "."
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--path" && i+1 < len(args) {
+			projectDir = args[i+1]
+			i++
+		}
+	}
 
-## Priority
-Medium
+	cache := NewSyncCache(projectDir)
+	if err := cache.Load(); err != nil {
+		fmt.Printf("Error loading cache: %v\n", err)
+		return
+	}
 
-## Status
-Open
+	entriesCount := len(cache.Entries)
+	cache.Clear()
 
-## Description
-Users should be able to export their data for backup, analysis, or migration purposes.
+	if err := cache.Save(); err != nil {
+		fmt.Printf("Error saving cache: %v\n", err)
+		return
+	}
 
-### Acceptance Criteria
-- [ ] Support CSV export format
-- [ ] Support JSON export format
-- [ ] Support PDF report generation
-- [ ] Large exports are processed asynchronously
-- [ ] User receives notification when export is ready
-`,
-		},
-		{
-			Filename: "UC003-notification-preferences.md",
-			Title:    "Notification Preferences",
-			Content: `# UC003: Notification Preferences
+	fmt.Printf("✓ Cache cleared (%d entries removed)\n", entriesCount)
+}
 
-## Summary
-As a user, I want to configure my notification preferences so that I only receive relevant alerts.
+func handleCacheCleanup(args []string) {
+	projectDir := "."
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--path" && i+1 < len(args) {
+			projectDir = args[i+1]
+			i++
+		}
+	}
 
-## Priority
-Low
+	cache := NewSyncCache(projectDir)
+	if err := cache.Load(); err != nil {
+		fmt.Printf("Error loading cache: %v\n", err)
+		return
+	}
 
-## Status
-Open
+	removed := cache.CleanupOrphans()
 
-## Description
-Users need granular control over what notifications they receive and through which channels.
+	if err := cache.Save(); err != nil {
+		fmt.Printf("Error saving cache: %v\n", err)
+		return
+	}
 
-### Acceptance Criteria
-- [ ] User can enable/disable email notifications
-- [ ] User can enable/disable in-app notifications
-- [ ] User can set notification frequency (immediate, daily digest, weekly)
-- [ ] User can select notification categories
-- [ ] Settings are saved and applied immediately
-`,
-		},
+	if removed > 0 {
+		fmt.Printf("✓ Cleaned up %d orphan entries\n", removed)
+	} else {
+		fmt.Println("✓ No orphan entries found")
 	}
 }
 
-// getVoSSamples returns Voice of Stakeholder sample documents
-func getVoSSamples() []SampleDocument {
-	return []SampleDocument{
-		{
-			Filename: "REQ001-gdpr-compliance.md",
-			Title:    "GDPR Compliance",
-			Content: `# REQ001: GDPR Compliance
+func showCacheHelp() {
+	fmt.Println("Usage: portunix pft cache <subcommand> [options]")
+	fmt.Println()
+	fmt.Println("Manage local sync cache")
+	fmt.Println()
+	fmt.Println("Subcommands:")
+	fmt.Println("  status    Show cache status and statistics")
+	fmt.Println("  clear     Clear all cache entries")
+	fmt.Println("  cleanup   Remove orphan entries (files that no longer exist)")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --path <dir>  Project directory (default: current)")
+	fmt.Println("  --help, -h    Show this help")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft cache status")
+	fmt.Println("  portunix pft cache clear")
+	fmt.Println("  portunix pft cache cleanup --path ./my-project")
+}
 
-## Type
-Regulatory
+// Example command - creates demo with VoC/VoS structure and 2x Fider
+func handleExampleCommand(args []string) {
+	// Parse --path flag
+	demoPath := ""
+	noDeploy := false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--path":
+			if i+1 < len(args) {
+				demoPath = args[i+1]
+				i++
+			}
+		case "--no-deploy":
+			noDeploy = true
+		case "--help", "-h":
+			showExampleHelp()
+			return
+		}
+	}
 
-## Stakeholder
-Legal / Compliance
+	// Default demo path
+	if demoPath == "" {
+		cwd, _ := os.Getwd()
+		demoPath = filepath.Join(cwd, "pft-demo")
+	}
 
-## Priority
-Critical
+	// Resolve to absolute path
+	absPath, err := filepath.Abs(demoPath)
+	if err != nil {
+		fmt.Printf("Error resolving path: %v\n", err)
+		return
+	}
+	demoPath = absPath
 
-## Status
-Open
+	fmt.Println("Product Feedback Tool - Quick Example (ISO 16355 QFD)")
+	fmt.Println("=====================================================")
+	fmt.Println()
 
-## Description
-System must comply with GDPR (General Data Protection Regulation) requirements.
+	// Step 1: Create demo directory structure
+	fmt.Printf("1. Creating demo directory structure: %s\n", demoPath)
+	vocPath := filepath.Join(demoPath, "voc")
+	vosPath := filepath.Join(demoPath, "vos")
 
-### Requirements
-- [ ] User consent management for data processing
-- [ ] Right to access personal data (data export)
-- [ ] Right to erasure (data deletion on request)
-- [ ] Data portability support
-- [ ] Privacy by design principles
-- [ ] Data breach notification procedures
-- [ ] DPO (Data Protection Officer) contact information
+	if err := os.MkdirAll(vocPath, 0755); err != nil {
+		fmt.Printf("   Error creating voc/: %v\n", err)
+		return
+	}
+	fmt.Println("   ✓ Created: voc/ (Voice of Customer - public)")
 
-## Compliance Deadline
-Mandatory - must be implemented before launch
-`,
-		},
-		{
-			Filename: "REQ002-performance-sla.md",
-			Title:    "Performance SLA",
-			Content: `# REQ002: Performance SLA Requirements
+	if err := os.MkdirAll(vosPath, 0755); err != nil {
+		fmt.Printf("   Error creating vos/: %v\n", err)
+		return
+	}
+	fmt.Println("   ✓ Created: vos/ (Voice of Stakeholder - internal)")
 
-## Type
-Technical / Business
+	// Step 2: Create VoC samples (customer feedback)
+	fmt.Println()
+	fmt.Println("2. Creating VoC samples (customer feedback)...")
+	vocSamples := getVoCSamples()
+	for _, sample := range vocSamples {
+		samplePath := filepath.Join(vocPath, sample.Filename)
+		if err := os.WriteFile(samplePath, []byte(sample.Content), 0644); err != nil {
+			fmt.Printf("   Error creating %s: %v\n", sample.Filename, err)
+			continue
+		}
+		fmt.Printf("   ✓ voc/%s: %s\n", sample.Filename, sample.Title)
+	}
 
-## Stakeholder
-Operations / Business
+	// Step 3: Create VoS samples (stakeholder requirements)
+	fmt.Println()
+	fmt.Println("3. Creating VoS samples (stakeholder requirements)...")
+	vosSamples := getVoSSamples()
+	for _, sample := range vosSamples {
+		samplePath := filepath.Join(vosPath, sample.Filename)
+		if err := os.WriteFile(samplePath, []byte(sample.Content), 0644); err != nil {
+			fmt.Printf("   Error creating %s: %v\n", sample.Filename, err)
+			continue
+		}
+		fmt.Printf("   ✓ vos/%s: %s\n", sample.Filename, sample.Title)
+	}
 
-## Priority
-High
+	// Step 4: Configure ptx-pft
+	fmt.Println()
+	fmt.Println("4. Configuring ptx-pft...")
+	config := NewDefaultConfig()
+	config.Name = "Demo Product"
+	config.Path = demoPath
+	// Configure VoC with Fider
+	config.VoC = &AreaConfig{
+		Provider: "fider",
+		URL:      "http://localhost:3000",
+	}
 
-## Status
-Open
+	if err := config.Save(demoPath); err != nil {
+		fmt.Printf("   Error saving config: %v\n", err)
+		return
+	}
+	fmt.Printf("   ✓ Configuration saved to %s\n", GetConfigPath(demoPath))
 
-## Description
-System must meet defined Service Level Agreement performance targets.
+	// Step 5: Deploy feedback tools (unless --no-deploy)
+	if noDeploy {
+		fmt.Println()
+		fmt.Println("5. Skipping deployment (--no-deploy flag)")
+		fmt.Println()
+		showExampleSummary(demoPath, vocSamples, vosSamples, false)
+		return
+	}
 
-### Requirements
-- [ ] API response time < 200ms (95th percentile)
-- [ ] Page load time < 2 seconds
-- [ ] System uptime >= 99.9%
-- [ ] Database query time < 100ms
-- [ ] Support for 10,000 concurrent users
-- [ ] Horizontal scaling capability
+	fmt.Println()
+	fmt.Println("5. Checking compose readiness...")
 
-## Monitoring
-- Real-time performance dashboards
-- Automated alerting on SLA violations
-- Monthly SLA compliance reports
+	// Preflight check - verify compose is ready before attempting deployment
+	preflight, err := CheckComposePreflight()
+	if err != nil {
+		fmt.Printf("   ⚠ Could not check compose readiness: %v\n", err)
+		fmt.Println()
+		showExampleSummary(demoPath, vocSamples, vosSamples, false)
+		return
+	}
+
+	if !preflight.Ready {
+		fmt.Println("   ❌ Compose is NOT ready")
+		fmt.Println()
+		fmt.Printf("   Problem: %s\n", preflight.ErrorMessage)
+		fmt.Println()
+
+		// Check if we can offer to fix it automatically (no sudo needed for user socket)
+		if strings.Contains(preflight.FixInstructions, "systemctl --user") {
+			fmt.Printf("   To fix this, run: %s\n", preflight.FixInstructions)
+			fmt.Println()
+			fmt.Print("   Do you want to run this command now? [Y/n]: ")
+
+			reader := bufio.NewReader(os.Stdin)
+			answer, _ := reader.ReadString('\n')
+			answer = strings.TrimSpace(strings.ToLower(answer))
+
+			if answer == "" || answer == "y" || answer == "yes" || answer == "a" || answer == "ano" {
+				fmt.Println()
+				fmt.Printf("   Running: %s\n", preflight.FixInstructions)
+
+				// Parse and execute the command
+				parts := strings.Fields(preflight.FixInstructions)
+				cmd := exec.Command(parts[0], parts[1:]...)
+				cmd.Stdout = os.Stdout
+				cmd.Stderr = os.Stderr
+
+				if err := cmd.Run(); err != nil {
+					fmt.Printf("   ❌ Command failed: %v\n", err)
+					fmt.Println()
+					showExampleSummary(demoPath, vocSamples, vosSamples, false)
+					return
+				}
+
+				fmt.Println("   ✓ Socket started successfully")
+				fmt.Println()
+
+				// Re-check preflight
+				preflight, err = CheckComposePreflight()
+				if err != nil || !preflight.Ready {
+					fmt.Println("   ⚠ Socket started but compose still not ready")
+					fmt.Println("   Please try running 'portunix pft example' again.")
+					fmt.Println()
+					showExampleSummary(demoPath, vocSamples, vosSamples, false)
+					return
+				}
+				fmt.Println("   ✓ Compose is now ready")
+			} else {
+				fmt.Println()
+				fmt.Println("   After fixing the issue, run 'portunix pft example' again.")
+				fmt.Println()
+				showExampleSummary(demoPath, vocSamples, vosSamples, false)
+				return
+			}
+		} else {
+			fmt.Printf("   Solution: %s\n", preflight.FixInstructions)
+			fmt.Println()
+			fmt.Println("   After fixing the issue, run 'portunix pft example' again.")
+			fmt.Println()
+			showExampleSummary(demoPath, vocSamples, vosSamples, false)
+			return
+		}
+	} else {
+		fmt.Println("   ✓ Compose is ready")
+	}
+
+	fmt.Println()
+	fmt.Println("6. Deploying feedback tools (2x Fider)...")
+
+	// Deploy VoC Fider (port 3100)
+	fmt.Println()
+	fmt.Println("   6a. VoC Fider (public, port 3100)...")
+	vocConfig := NewDefaultConfig()
+	vocConfig.Name = "Demo Product - VoC"
+	vocConfig.Path = vocPath
+	vocConfig.VoC = &AreaConfig{
+		Provider: "fider",
+		URL:      "http://localhost:3100",
+	}
+
+	vocResult, vocErr := DeployInstance("voc", 3100, vocConfig)
+	if vocErr != nil {
+		fmt.Printf("   ⚠ VoC deployment failed: %v\n", vocErr)
+	} else {
+		fmt.Println("   ✓ VoC Fider deployed on port 3100")
+		_ = vocResult
+	}
+
+	// Deploy VoS Fider (port 3101)
+	fmt.Println()
+	fmt.Println("   6b. VoS Fider (internal, port 3101)...")
+	vosConfig := NewDefaultConfig()
+	vosConfig.Name = "Demo Product - VoS"
+	vosConfig.Path = vosPath
+	vosConfig.VoS = &AreaConfig{
+		Provider: "fider",
+		URL:      "http://localhost:3101",
+	}
+
+	vosResult, vosErr := DeployInstance("vos", 3101, vosConfig)
+	if vosErr != nil {
+		fmt.Printf("   ⚠ VoS deployment failed: %v\n", vosErr)
+	} else {
+		fmt.Println("   ✓ VoS Fider deployed on port 3101")
+		_ = vosResult
+	}
+
+	fmt.Println()
+	showExampleSummary(demoPath, vocSamples, vosSamples, vocErr == nil && vosErr == nil)
+}
+
+func showExampleSummary(demoPath string, vocSamples, vosSamples []SampleDocument, deployed bool) {
+	fmt.Println("=====================================================")
+	fmt.Println("Demo setup complete!")
+	fmt.Println()
+	fmt.Println("Directory structure:")
+	fmt.Printf("  %s/\n", demoPath)
+	fmt.Println("  ├── voc/                 (Voice of Customer - public)")
+	for _, s := range vocSamples {
+		fmt.Printf("  │   └── %s\n", s.Filename)
+	}
+	fmt.Println("  ├── vos/                 (Voice of Stakeholder - internal)")
+	for _, s := range vosSamples {
+		fmt.Printf("  │   └── %s\n", s.Filename)
+	}
+	fmt.Println("  └── .pft-config.json")
+	fmt.Println()
+
+	if deployed {
+		fmt.Println("Fider instances:")
+		fmt.Println("  VoC (public):   http://localhost:3100")
+		fmt.Println("  VoS (internal): http://localhost:3101")
+		fmt.Println()
+		fmt.Println("Email capture (Mailhog):")
+		fmt.Println("  VoC Mailhog:    http://localhost:3200")
+		fmt.Println("  VoS Mailhog:    http://localhost:3201")
+		fmt.Println()
+		fmt.Println("Registration steps:")
+		fmt.Println("  1. Open http://localhost:3100 (VoC Fider)")
+		fmt.Println("  2. Fill in the signup form:")
+		fmt.Println("       - Your name: e.g., 'Admin'")
+		fmt.Println("       - Email: e.g., 'admin@local.test' (fake, captured by Mailhog)")
+		fmt.Println("       - Site name: e.g., 'Customer Feedback'")
+		fmt.Println("  3. Open http://localhost:3200 (Mailhog)")
+		fmt.Println("  4. Click confirmation link in the email")
+		fmt.Println("  5. Repeat for VoS (ports 3101/3201, site: 'Stakeholder Requirements')")
+		fmt.Println()
+		fmt.Println("To stop and remove:")
+		fmt.Println("  portunix pft destroy           # keep data")
+		fmt.Println("  portunix pft destroy --volumes # remove everything")
+	} else {
+		fmt.Println("To deploy manually:")
+		fmt.Printf("  cd %s && portunix pft deploy\n", demoPath)
+	}
+}
+
+func showExampleHelp() {
+	fmt.Println("Usage: portunix pft example [options]")
+	fmt.Println()
+	fmt.Println("Creates a demo with VoC/VoS structure per ISO 16355 QFD.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --path <path>   Directory for demo files (default: ./pft-demo)")
+	fmt.Println("  --no-deploy     Create files only, don't deploy containers")
+	fmt.Println()
+	fmt.Println("This command will:")
+	fmt.Println("  1. Create voc/ directory with 3 customer feedback samples")
+	fmt.Println("  2. Create vos/ directory with 3 stakeholder requirement samples")
+	fmt.Println("  3. Deploy 2x Fider instances:")
+	fmt.Println("     - VoC Fider on port 3100 (public, customer-facing)")
+	fmt.Println("     - VoS Fider on port 3101 (internal, stakeholders)")
+	fmt.Println()
+	fmt.Println("VoC = Voice of Customer (public feedback)")
+	fmt.Println("VoS = Voice of Stakeholder (internal requirements)")
+}
+
+// SampleDocument represents a sample document (VoC or VoS)
+type SampleDocument struct {
+	Filename string
+	Title    string
+	Content  string
+}
+
+// getVoCSamples returns Voice of Customer sample documents
+func getVoCSamples() []SampleDocument {
+	return []SampleDocument{
+		{
+			Filename: "UC001-user-login.md",
+			Title:    "User Login",
+			Content: `# UC001: User Login
+
+## Summary
+As a user, I want to log into the system securely so that I can access my personal dashboard.
+
+## Priority
+High
+
+## Status
+Open
+
+## Description
+Users need a secure authentication mechanism to access protected areas of the application.
+
+### Acceptance Criteria
+- [ ] User can enter username/email and password
+- [ ] System validates credentials against database
+- [ ] Failed attempts are logged and rate-limited
+- [ ] Successful login redirects to dashboard
+- [ ] Session is created with appropriate timeout
 `,
 		},
 		{
-			Filename: "REQ003-security-audit.md",
-			Title:    "Security Audit Requirements",
-			Content: `# REQ003: Security Audit Requirements
+			Filename: "UC002-data-export.md",
+			Title:    "Data Export",
+			Content: `# UC002: Data Export
 
-## Type
-Security / Compliance
+## Summary
+As a user, I want to export my data in various formats so that I can use it in other applications.
 
-## Stakeholder
-Security Team / CISO
+## Priority
+Medium
+
+## Status
+Open
+
+## Description
+Users should be able to export their data for backup, analysis, or migration purposes.
+
+### Acceptance Criteria
+- [ ] Support CSV export format
+- [ ] Support JSON export format
+- [ ] Support PDF report generation
+- [ ] Large exports are processed asynchronously
+- [ ] User receives notification when export is ready
+`,
+		},
+		{
+			Filename: "UC003-notification-preferences.md",
+			Title:    "Notification Preferences",
+			Content: `# UC003: Notification Preferences
+
+## Summary
+As a user, I want to configure my notification preferences so that I only receive relevant alerts.
+
+## Priority
+Low
+
+## Status
+Open
+
+## Description
+Users need granular control over what notifications they receive and through which channels.
+
+### Acceptance Criteria
+- [ ] User can enable/disable email notifications
+- [ ] User can enable/disable in-app notifications
+- [ ] User can set notification frequency (immediate, daily digest, weekly)
+- [ ] User can select notification categories
+- [ ] Settings are saved and applied immediately
+`,
+		},
+	}
+}
+
+// getVoSSamples returns Voice of Stakeholder sample documents
+func getVoSSamples() []SampleDocument {
+	return []SampleDocument{
+		{
+			Filename: "REQ001-gdpr-compliance.md",
+			Title:    "GDPR Compliance",
+			Content: `# REQ001: GDPR Compliance
+
+## Type
+Regulatory
+
+## Stakeholder
+Legal / Compliance
+
+## Priority
+Critical
+
+## Status
+Open
+
+## Description
+System must comply with GDPR (General Data Protection Regulation) requirements.
+
+### Requirements
+- [ ] User consent management for data processing
+- [ ] Right to access personal data (data export)
+- [ ] Right to erasure (data deletion on request)
+- [ ] Data portability support
+- [ ] Privacy by design principles
+- [ ] Data breach notification procedures
+- [ ] DPO (Data Protection Officer) contact information
+
+## Compliance Deadline
+Mandatory - must be implemented before launch
+`,
+		},
+		{
+			Filename: "REQ002-performance-sla.md",
+			Title:    "Performance SLA",
+			Content: `# REQ002: Performance SLA Requirements
+
+## Type
+Technical / Business
+
+## Stakeholder
+Operations / Business
+
+## Priority
+High
+
+## Status
+Open
+
+## Description
+System must meet defined Service Level Agreement performance targets.
+
+### Requirements
+- [ ] API response time < 200ms (95th percentile)
+- [ ] Page load time < 2 seconds
+- [ ] System uptime >= 99.9%
+- [ ] Database query time < 100ms
+- [ ] Support for 10,000 concurrent users
+- [ ] Horizontal scaling capability
+
+## Monitoring
+- Real-time performance dashboards
+- Automated alerting on SLA violations
+- Monthly SLA compliance reports
+`,
+		},
+		{
+			Filename: "REQ003-security-audit.md",
+			Title:    "Security Audit Requirements",
+			Content: `# REQ003: Security Audit Requirements
+
+## Type
+Security / Compliance
+
+## Stakeholder
+Security Team / CISO
+
+## Priority
+Critical
+
+## Status
+Open
+
+## Description
+System must pass security audit and maintain security certifications.
+
+### Requirements
+- [ ] Annual penetration testing
+- [ ] OWASP Top 10 vulnerability protection
+- [ ] Encrypted data at rest (AES-256)
+- [ ] Encrypted data in transit (TLS 1.3)
+- [ ] Multi-factor authentication support
+- [ ] Role-based access control (RBAC)
+- [ ] Audit logging for all sensitive operations
+- [ ] Secure key management (HSM or cloud KMS)
+
+## Certifications
+- SOC 2 Type II compliance
+- ISO 27001 certification (planned)
+`,
+		},
+	}
+}
+
+// User command handlers
+func handleUserCommand(args []string) {
+	if len(args) == 0 {
+		showUserHelp()
+		return
+	}
+
+	// Extract --path from args first
+	var configPath string
+	var filteredArgs []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--path" && i+1 < len(args) {
+			configPath = args[i+1]
+			i++ // skip next arg
+		} else {
+			filteredArgs = append(filteredArgs, args[i])
+		}
+	}
+
+	if len(filteredArgs) == 0 {
+		showUserHelp()
+		return
+	}
+
+	subcommand := filteredArgs[0]
+	subArgs := filteredArgs[1:]
+
+	// Determine project directory
+	var projectDir string
+	if configPath != "" {
+		projectDir = configPath
+	} else {
+		projectDir = getProjectDir()
+	}
+
+	switch subcommand {
+	case "list":
+		handleUserListCommand(subArgs, projectDir)
+	case "add":
+		handleUserAddCommand(subArgs, projectDir)
+	case "update":
+		handleUserUpdateCommand(subArgs, projectDir)
+	case "role":
+		handleUserRoleCommand(subArgs, projectDir)
+	case "link":
+		handleUserLinkCommand(subArgs, projectDir)
+	case "remove":
+		handleUserRemoveCommand(subArgs, projectDir)
+	case "show":
+		handleUserShowCommand(subArgs, projectDir)
+	case "sync":
+		handleUserSyncCommand(subArgs, projectDir)
+	case "import":
+		handleUserImportCommand(subArgs, projectDir)
+	case "purge":
+		handleUserPurgeCommand(subArgs, projectDir)
+	case "--help", "-h":
+		showUserHelp()
+	default:
+		fmt.Printf("Unknown user subcommand: %s\n", subcommand)
+		showUserHelp()
+	}
+}
+
+func showUserHelp() {
+	fmt.Println("Usage: portunix pft user <command> [options]")
+	fmt.Println()
+	fmt.Println("User Registry Commands:")
+	fmt.Println()
+	fmt.Println("  list [--voc|--vos|--vob|--voe]  List users (optionally filter by category)")
+	fmt.Println("  add --id <email> --name <name>  Add a new user")
+	fmt.Println("  update <id> [--name|--org]      Update user details")
+	fmt.Println("  show <id>                       Show user details")
+	fmt.Println("  role <id> --voc|--vos|--vob|--voe <role> [--proxy]")
+	fmt.Println("                                  Assign role to user in category")
+	fmt.Println("  role <id> --voc|--vos|--vob|--voe --remove")
+	fmt.Println("                                  Remove role from category")
+	fmt.Println("  link <id> --fider <fider-id>    Link user to Fider ID")
+	fmt.Println("  remove <id>                     Remove user from registry")
+	fmt.Println("  sync [--voc|--vos] [--dry-run]  Sync users from Fider")
+	fmt.Println("  import --csv <file>|--ldap <url> [--map <rule>]...")
+	fmt.Println("                                  Import users with role mapping rules")
+	fmt.Println("  purge <email> [--anonymize-items] [--delete-account]")
+	fmt.Println("                                  Erase a user (GDPR erasure request)")
+	fmt.Println()
+	fmt.Println("Options for 'add':")
+	fmt.Println("  --id <email>      User ID (typically email)")
+	fmt.Println("  --name <name>     User display name")
+	fmt.Println("  --org <org>       Organization (optional)")
+	fmt.Println()
+	fmt.Println("Options for 'update':")
+	fmt.Println("  --name <name>     New display name")
+	fmt.Println("  --org <org>       New organization")
+	fmt.Println()
+	fmt.Println("Options for 'sync':")
+	fmt.Println("  --voc             Sync only from VoC Fider instance")
+	fmt.Println("  --vos             Sync only from VoS Fider instance")
+	fmt.Println("  --dry-run         Show what would be synced without changes")
+	fmt.Println("  --voc-token <tok> Set VoC Fider API token")
+	fmt.Println("  --vos-token <tok> Set VoS Fider API token")
+	fmt.Println()
+	fmt.Println("Options for 'purge':")
+	fmt.Println("  --anonymize-items Replace the user in their items by \"Anonymous\"")
+	fmt.Println("  --delete-account  Delete the user's accounts in the feedback tools")
+	fmt.Println("  --dry-run         Show what would be erased without changes")
+	fmt.Println("  --yes             Do not ask for confirmation")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft user add --id user@example.com --name \"John Doe\"")
+	fmt.Println("  portunix pft user update user@example.com --name \"Jane Doe\"")
+	fmt.Println("  portunix pft user role user@example.com --vos developer")
+	fmt.Println("  portunix pft user role user@example.com --vos cio --proxy")
+	fmt.Println("  portunix pft user link user@example.com --fider 42")
+	fmt.Println("  portunix pft user sync --voc")
+	fmt.Println("  portunix pft user import --csv users.csv --map voc=customer")
+	fmt.Println("  portunix pft user purge user@example.com --anonymize-items --delete-account")
+	fmt.Println()
+	fmt.Println("Run 'portunix pft user import --help' for CSV columns and LDAP options.")
+}
+
+func handleUserListCommand(args []string, projectDir string) {
+	// Parse category filter
+	var category string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--voc":
+			category = "voc"
+		case "--vos":
+			category = "vos"
+		case "--vob":
+			category = "vob"
+		case "--voe":
+			category = "voe"
+		}
+	}
+
+	registry, err := LoadUserRegistry(projectDir)
+	if err != nil {
+		fmt.Printf("Error loading users: %v\n", err)
+		return
+	}
+
+	var users []User
+	if category != "" {
+		users = registry.ListUsersByCategory(category)
+		fmt.Printf("Users with %s roles:\n\n", GetCategoryName(category))
+	} else {
+		users = registry.Users
+		fmt.Println("All users:")
+		fmt.Println()
+	}
+
+	PrintUserList(users, category)
+}
+
+func handleUserAddCommand(args []string, projectDir string) {
+	var id, name, org string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--id":
+			if i+1 < len(args) {
+				id = args[i+1]
+				i++
+			}
+		case "--name":
+			if i+1 < len(args) {
+				name = args[i+1]
+				i++
+			}
+		case "--org":
+			if i+1 < len(args) {
+				org = args[i+1]
+				i++
+			}
+		}
+	}
+
+	if id == "" {
+		fmt.Println("Error: --id is required")
+		fmt.Println("Usage: portunix pft user add --id <email> --name <name>")
+		return
+	}
+
+	if name == "" {
+		fmt.Println("Error: --name is required")
+		fmt.Println("Usage: portunix pft user add --id <email> --name <name>")
+		return
+	}
+
+	registry, err := LoadUserRegistry(projectDir)
+	if err != nil {
+		fmt.Printf("Error loading users: %v\n", err)
+		return
+	}
+
+	user := User{
+		ID:           id,
+		Name:         name,
+		Organization: org,
+	}
+
+	if err := registry.AddUser(user); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	if err := SaveUserRegistry(projectDir, registry); err != nil {
+		fmt.Printf("Error saving users: %v\n", err)
+		return
+	}
+
+	fmt.Printf("✓ User '%s' added successfully\n", id)
+}
+
+func handleUserUpdateCommand(args []string, projectDir string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: portunix pft user update <id> [--name <name>] [--org <org>]")
+		return
+	}
+
+	id := args[0]
+	var name, org string
+	var clearOrg bool
+
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--name":
+			if i+1 < len(args) {
+				name = args[i+1]
+				i++
+			}
+		case "--org":
+			if i+1 < len(args) {
+				org = args[i+1]
+				i++
+			}
+		case "--org=":
+			clearOrg = true
+		}
+	}
+
+	if name == "" && org == "" && !clearOrg {
+		fmt.Println("Error: at least one of --name or --org is required")
+		fmt.Println("Usage: portunix pft user update <id> [--name <name>] [--org <org>]")
+		return
+	}
+
+	registry, err := LoadUserRegistry(projectDir)
+	if err != nil {
+		fmt.Printf("Error loading users: %v\n", err)
+		return
+	}
+
+	user := registry.FindUser(id)
+	if user == nil {
+		fmt.Printf("User '%s' not found\n", id)
+		return
+	}
+
+	// Apply updates
+	updated := false
+	if name != "" {
+		user.Name = name
+		updated = true
+		fmt.Printf("  Name updated to: %s\n", name)
+	}
+	if org != "" {
+		user.Organization = org
+		updated = true
+		fmt.Printf("  Organization updated to: %s\n", org)
+	}
+	if clearOrg {
+		user.Organization = ""
+		updated = true
+		fmt.Println("  Organization cleared")
+	}
+
+	if updated {
+		user.UpdatedAt = time.Now()
+		if err := SaveUserRegistry(projectDir, registry); err != nil {
+			fmt.Printf("Error saving users: %v\n", err)
+			return
+		}
+		fmt.Printf("✓ User '%s' updated successfully\n", id)
+	}
+}
+
+func handleUserShowCommand(args []string, projectDir string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: portunix pft user show <id>")
+		return
+	}
+
+	id := args[0]
+
+	registry, err := LoadUserRegistry(projectDir)
+	if err != nil {
+		fmt.Printf("Error loading users: %v\n", err)
+		return
+	}
+
+	user := registry.FindUser(id)
+	if user == nil {
+		fmt.Printf("User '%s' not found\n", id)
+		return
+	}
+
+	PrintUser(user)
+}
+
+func handleUserRoleCommand(args []string, projectDir string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: portunix pft user role <id> --voc|--vos|--vob|--voe <role> [--proxy]")
+		return
+	}
+
+	id := args[0]
+	var category, role string
+	var proxy, remove bool
+
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--voc":
+			category = "voc"
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				role = args[i+1]
+				i++
+			}
+		case "--vos":
+			category = "vos"
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				role = args[i+1]
+				i++
+			}
+		case "--vob":
+			category = "vob"
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				role = args[i+1]
+				i++
+			}
+		case "--voe":
+			category = "voe"
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				role = args[i+1]
+				i++
+			}
+		case "--proxy":
+			proxy = true
+		case "--remove":
+			remove = true
+		}
+	}
+
+	if category == "" {
+		fmt.Println("Error: category required (--voc, --vos, --vob, or --voe)")
+		return
+	}
+
+	registry, err := LoadUserRegistry(projectDir)
+	if err != nil {
+		fmt.Printf("Error loading users: %v\n", err)
+		return
+	}
+
+	user := registry.FindUser(id)
+	if user == nil {
+		fmt.Printf("User '%s' not found\n", id)
+		return
+	}
+
+	if remove {
+		if err := user.RemoveRole(category); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("✓ Removed %s role from user '%s'\n", GetCategoryName(category), id)
+	} else {
+		if role == "" {
+			fmt.Println("Error: role name required")
+			fmt.Println("Usage: portunix pft user role <id> --vos <role> [--proxy]")
+			return
+		}
+
+		// Validate role
+		valid, err := ValidateRole(projectDir, category, role)
+		if err != nil {
+			fmt.Printf("Error validating role: %v\n", err)
+			return
+		}
+		if !valid {
+			fmt.Printf("Error: role '%s' is not valid for category '%s'\n", role, category)
+			fmt.Printf("Run 'portunix pft role list --%s' to see available roles\n", category)
+			return
+		}
+
+		if err := user.SetRole(category, role, proxy); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		proxyStr := ""
+		if proxy {
+			proxyStr = " (proxy)"
+		}
+		fmt.Printf("✓ Assigned %s role '%s'%s to user '%s'\n", GetCategoryName(category), role, proxyStr, id)
+	}
+
+	if err := SaveUserRegistry(projectDir, registry); err != nil {
+		fmt.Printf("Error saving users: %v\n", err)
+		return
+	}
+}
+
+func handleUserLinkCommand(args []string, projectDir string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: portunix pft user link <id> --fider <fider-id>")
+		return
+	}
+
+	id := args[0]
+	var fiderID int
+
+	for i := 1; i < len(args); i++ {
+		if args[i] == "--fider" && i+1 < len(args) {
+			fmt.Sscanf(args[i+1], "%d", &fiderID)
+			i++
+		}
+	}
+
+	if fiderID == 0 {
+		fmt.Println("Error: --fider <id> is required")
+		return
+	}
+
+	registry, err := LoadUserRegistry(projectDir)
+	if err != nil {
+		fmt.Printf("Error loading users: %v\n", err)
+		return
+	}
+
+	user := registry.FindUser(id)
+	if user == nil {
+		fmt.Printf("User '%s' not found\n", id)
+		return
+	}
+
+	user.LinkFider(fiderID)
+
+	if err := SaveUserRegistry(projectDir, registry); err != nil {
+		fmt.Printf("Error saving users: %v\n", err)
+		return
+	}
+
+	fmt.Printf("✓ Linked user '%s' to Fider ID %d\n", id, fiderID)
+}
+
+func handleUserRemoveCommand(args []string, projectDir string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: portunix pft user remove <id>")
+		return
+	}
+
+	id := args[0]
+
+	registry, err := LoadUserRegistry(projectDir)
+	if err != nil {
+		fmt.Printf("Error loading users: %v\n", err)
+		return
+	}
+
+	if err := registry.RemoveUser(id); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	if err := SaveUserRegistry(projectDir, registry); err != nil {
+		fmt.Printf("Error saving users: %v\n", err)
+		return
+	}
+
+	fmt.Printf("✓ User '%s' removed\n", id)
+}
+
+func handleUserPurgeCommand(args []string, projectDir string) {
+	var email string
+	var opts PurgeOptions
+	var yes bool
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--anonymize-items":
+			opts.AnonymizeItems = true
+		case "--delete-account":
+			opts.DeleteAccounts = true
+		case "--dry-run":
+			opts.DryRun = true
+		case "--yes", "-y":
+			yes = true
+		default:
+			if !strings.HasPrefix(args[i], "-") && email == "" {
+				email = args[i]
+			}
+		}
+	}
+
+	if email == "" {
+		fmt.Println("Usage: portunix pft user purge <email> [--anonymize-items] [--delete-account] [--dry-run] [--yes]")
+		return
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		if opts.DeleteAccounts {
+			fmt.Println("No configuration found. Run 'portunix pft configure' first.")
+			return
+		}
+		config = NewDefaultConfig()
+	}
+
+	if !opts.DryRun && !yes {
+		fmt.Printf("WARNING: This will erase '%s' from the user registry", email)
+		if opts.AnonymizeItems {
+			fmt.Print(", anonymize their items")
+		}
+		if opts.DeleteAccounts {
+			fmt.Print(" and delete their feedback tool accounts")
+		}
+		fmt.Println(". This cannot be undone!")
+		fmt.Print("Are you sure? (y/N): ")
+		var response string
+		fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			fmt.Println("Aborted.")
+			return
+		}
+	}
+
+	result, err := PurgeUser(config, projectDir, email, opts)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	// report prints a performed or, in a dry run, a planned change
+	report := func(done, planned string, args ...interface{}) {
+		if opts.DryRun {
+			fmt.Printf("[dry-run] Would "+planned+"\n", args...)
+		} else {
+			fmt.Printf("✓ "+done+"\n", args...)
+		}
+	}
+
+	if result.UserRemoved {
+		report("Removed user '%s' from the registry", "remove user '%s' from the registry", email)
+	} else {
+		fmt.Printf("User '%s' is not in the registry\n", email)
+	}
+	if opts.AnonymizeItems {
+		for _, id := range result.Items {
+			report("Anonymized %s", "anonymize %s", id)
+		}
+		if result.IntakeRecords > 0 {
+			report("Dropped %d email intake record(s)", "drop %d email intake record(s)", result.IntakeRecords)
+		}
+	} else if len(result.Items) > 0 {
+		fmt.Printf("%d item(s) still reference the user: %s\n", len(result.Items), strings.Join(result.Items, ", "))
+		fmt.Println("Run again with --anonymize-items to anonymize them.")
+	}
+	for _, account := range result.Accounts {
+		report("Deleted account in %s", "delete account in %s", account)
+	}
+	for _, account := range result.AccountsManual {
+		fmt.Printf("! Delete the account manually in %s\n", account)
+	}
+}
+
+func handleUserSyncCommand(args []string, projectDir string) {
+	// Parse flags
+	var syncVoC, syncVoS, dryRun bool
+	var vocToken, vosToken string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--voc":
+			syncVoC = true
+		case "--vos":
+			syncVoS = true
+		case "--dry-run":
+			dryRun = true
+		case "--voc-token":
+			if i+1 < len(args) {
+				vocToken = args[i+1]
+				i++
+			}
+		case "--vos-token":
+			if i+1 < len(args) {
+				vosToken = args[i+1]
+				i++
+			}
+		case "--help", "-h":
+			showUserSyncHelp()
+			return
+		}
+	}
+
+	// If neither specified, sync both
+	if !syncVoC && !syncVoS {
+		syncVoC = true
+		syncVoS = true
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		fmt.Println("No configuration found. Run 'portunix pft configure' first.")
+		return
+	}
+
+	// Update config with tokens if provided
+	if vocToken != "" {
+		config.VoC.APIToken = storeToken(config, "voc", vocToken)
+	}
+	if vosToken != "" {
+		config.VoS.APIToken = storeToken(config, "vos", vosToken)
+	}
+
+	// Load user registry
+	registry, err := LoadUserRegistry(projectDir)
+	if err != nil {
+		fmt.Printf("Error loading user registry: %v\n", err)
+		return
+	}
+
+	fmt.Println("Synchronizing users from Fider...")
+	if dryRun {
+		fmt.Println("(dry-run mode - no changes will be made)")
+	}
+	fmt.Println()
+
+	totalAdded := 0
+	totalUpdated := 0
+	totalSkipped := 0
+
+	// Sync VoC users
+	if syncVoC {
+		fmt.Println("👥 VoC (Voice of Customer) users:")
+
+		vocURL := config.VoC.URL
+		if vocURL == "" {
+			vocURL = "http://localhost:3100"
+		}
+		vocAPIToken := config.VoC.Token()
+		if vocAPIToken == "" {
+			vocAPIToken = config.GetAPIToken()
+		}
+
+		if vocAPIToken == "" {
+			fmt.Println("   ✗ No API token configured for VoC")
+			fmt.Println("   Run: portunix pft user sync --voc --voc-token <your-token>")
+		} else {
+			client := NewFiderClient(vocURL, vocAPIToken)
+			added, updated, skipped, err := syncUsersFromFider(client, registry, "voc", dryRun)
+			if err != nil {
+				fmt.Printf("   ✗ Sync failed: %v\n", err)
+			} else {
+				fmt.Printf("   Added: %d, Updated: %d, Skipped: %d\n", added, updated, skipped)
+				totalAdded += added
+				totalUpdated += updated
+				totalSkipped += skipped
+			}
+		}
+		fmt.Println()
+	}
+
+	// Sync VoS users
+	if syncVoS {
+		fmt.Println("👥 VoS (Voice of Stakeholder) users:")
+
+		vosURL := config.VoS.URL
+		if vosURL == "" {
+			vosURL = "http://localhost:3101"
+		}
+		vosAPIToken := config.VoS.Token()
+		if vosAPIToken == "" {
+			vosAPIToken = config.GetAPIToken()
+		}
+
+		if vosAPIToken == "" {
+			fmt.Println("   ✗ No API token configured for VoS")
+			fmt.Println("   Run: portunix pft user sync --vos --vos-token <your-token>")
+		} else {
+			client := NewFiderClient(vosURL, vosAPIToken)
+			added, updated, skipped, err := syncUsersFromFider(client, registry, "vos", dryRun)
+			if err != nil {
+				fmt.Printf("   ✗ Sync failed: %v\n", err)
+			} else {
+				fmt.Printf("   Added: %d, Updated: %d, Skipped: %d\n", added, updated, skipped)
+				totalAdded += added
+				totalUpdated += updated
+				totalSkipped += skipped
+			}
+		}
+		fmt.Println()
+	}
+
+	// Save registry if not dry-run
+	if !dryRun && (totalAdded > 0 || totalUpdated > 0) {
+		if err := SaveUserRegistry(projectDir, registry); err != nil {
+			fmt.Printf("Error saving user registry: %v\n", err)
+			return
+		}
+	}
+
+	// Save updated config with tokens if they were provided
+	if vocToken != "" || vosToken != "" {
+		configPath, _ := findConfigFile()
+		if configPath != "" {
+			config.SaveToPath(configPath)
+			fmt.Println("Configuration updated with API tokens.")
+		}
+	}
+
+	fmt.Printf("User sync complete. Added: %d, Updated: %d, Skipped: %d\n", totalAdded, totalUpdated, totalSkipped)
+}
+
+// syncUsersFromFider fetches users from Fider and syncs them to local registry
+func syncUsersFromFider(client *FiderClient, registry *UserRegistry, category string, dryRun bool) (added, updated, skipped int, err error) {
+	// Fetch users from Fider
+	fiderUsers, err := client.ListUsers()
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to fetch users from Fider: %w", err)
+	}
+
+	fmt.Printf("   Found %d users in Fider\n", len(fiderUsers))
+
+	for _, fiderUser := range fiderUsers {
+		// Skip users without email
+		if fiderUser.Email == "" {
+			fmt.Printf("   ⚠ Skipping user '%s' (no email)\n", fiderUser.Name)
+			skipped++
+			continue
+		}
+
+		// Try to find existing user by Fider ID first
+		existingUser := registry.FindUserByFiderID(fiderUser.ID)
+		if existingUser != nil {
+			// User already linked by Fider ID
+			fmt.Printf("   ⏭ %s (already linked via Fider ID %d)\n", fiderUser.Email, fiderUser.ID)
+			skipped++
+			continue
+		}
+
+		// Try to find by email
+		existingUser = registry.FindUserByEmail(fiderUser.Email)
+		if existingUser != nil {
+			// User exists, update Fider ID link
+			if dryRun {
+				fmt.Printf("   🔗 Would link: %s → Fider ID %d\n", fiderUser.Email, fiderUser.ID)
+			} else {
+				existingUser.LinkFider(fiderUser.ID)
+				fmt.Printf("   🔗 Linked: %s → Fider ID %d\n", fiderUser.Email, fiderUser.ID)
+			}
+			updated++
+			continue
+		}
+
+		// New user - add to registry
+		if dryRun {
+			fmt.Printf("   ➕ Would add: %s (%s) with Fider ID %d\n", fiderUser.Email, fiderUser.Name, fiderUser.ID)
+		} else {
+			newUser := User{
+				ID:   fiderUser.Email,
+				Name: fiderUser.Name,
+				ExternalIDs: &ExternalIDs{
+					Fider: fiderUser.ID,
+				},
+			}
+
+			// Assign default role based on category
+			switch category {
+			case "voc":
+				newUser.Roles.VoC = &RoleAssignment{Role: "customer", Proxy: false}
+			case "vos":
+				newUser.Roles.VoS = &RoleAssignment{Role: "developer", Proxy: false}
+			}
+
+			if err := registry.AddUser(newUser); err != nil {
+				fmt.Printf("   ✗ Failed to add %s: %v\n", fiderUser.Email, err)
+				continue
+			}
+			fmt.Printf("   ➕ Added: %s (%s) with Fider ID %d\n", fiderUser.Email, fiderUser.Name, fiderUser.ID)
+		}
+		added++
+	}
+
+	return added, updated, skipped, nil
+}
+
+func showUserSyncHelp() {
+	fmt.Println("Usage: portunix pft user sync [options]")
+	fmt.Println()
+	fmt.Println("Synchronize users from Fider instances to local registry.")
+	fmt.Println()
+	fmt.Println("This command will:")
+	fmt.Println("  1. Fetch all users from Fider API")
+	fmt.Println("  2. Add new users to local registry")
+	fmt.Println("  3. Link existing users by email to their Fider IDs")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --voc              Sync only from VoC Fider instance")
+	fmt.Println("  --vos              Sync only from VoS Fider instance")
+	fmt.Println("  --voc-token <tok>  Set VoC Fider API token")
+	fmt.Println("  --vos-token <tok>  Set VoS Fider API token")
+	fmt.Println("  --dry-run          Show what would be synced without making changes")
+	fmt.Println()
+	fmt.Println("New users are assigned default roles:")
+	fmt.Println("  VoC: customer")
+	fmt.Println("  VoS: developer")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft user sync --voc")
+	fmt.Println("  portunix pft user sync --dry-run")
+	fmt.Println("  portunix pft user sync --voc --voc-token abc123")
+}
+
+func handleUserImportCommand(args []string, projectDir string) {
+	var csvPath, ldapURL string
+	var ldapOpts LDAPImportOptions
+	var rules []RoleMappingRule
+	var dryRun bool
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--csv":
+			if i+1 < len(args) {
+				csvPath = args[i+1]
+				i++
+			}
+		case "--ldap":
+			if i+1 < len(args) {
+				ldapURL = args[i+1]
+				i++
+			}
+		case "--bind-dn":
+			if i+1 < len(args) {
+				ldapOpts.BindDN = args[i+1]
+				i++
+			}
+		case "--bind-password":
+			if i+1 < len(args) {
+				ldapOpts.BindPassword = args[i+1]
+				i++
+			}
+		case "--base-dn":
+			if i+1 < len(args) {
+				ldapOpts.BaseDN = args[i+1]
+				i++
+			}
+		case "--filter":
+			if i+1 < len(args) {
+				ldapOpts.Filter = args[i+1]
+				i++
+			}
+		case "--start-tls":
+			ldapOpts.StartTLS = true
+		case "--map":
+			if i+1 < len(args) {
+				rule, err := ParseRoleMappingRule(args[i+1])
+				if err != nil {
+					fmt.Printf("Error: %v\n", err)
+					return
+				}
+				rules = append(rules, rule)
+				i++
+			}
+		case "--dry-run":
+			dryRun = true
+		case "--help", "-h":
+			showUserImportHelp()
+			return
+		}
+	}
+
+	if (csvPath == "") == (ldapURL == "") {
+		fmt.Println("Error: specify either --csv <file> or --ldap <url>")
+		fmt.Println("Usage: portunix pft user import --csv <file> | --ldap <url> --base-dn <dn> [--map <rule>]...")
+		return
+	}
+
+	var records []UserImportRecord
+	if csvPath != "" {
+		file, err := os.Open(csvPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("Importing users from %s...\n", csvPath)
+		records, err = ReadUsersCSV(file)
+		file.Close()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+	} else {
+		ldapOpts.URL = ldapURL
+		ldapOpts.BindPassword = resolveSecret(ldapOpts.BindPassword)
+		for _, rule := range rules {
+			if rule.Attribute != "" && rule.Attribute != "dn" {
+				ldapOpts.Attributes = append(ldapOpts.Attributes, rule.Attribute)
+			}
+		}
+		fmt.Printf("Importing users from %s...\n", ldapURL)
+		var err error
+		records, err = ReadUsersLDAP(ldapOpts)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+	}
+	if dryRun {
+		fmt.Println("(dry-run mode - no changes will be made)")
+	}
 
-## Priority
-Critical
+	registry, err := LoadUserRegistry(projectDir)
+	if err != nil {
+		fmt.Printf("Error loading users: %v\n", err)
+		return
+	}
 
-## Status
-Open
+	result, err := ImportUsers(projectDir, registry, records, rules, dryRun)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
 
-## Description
-System must pass security audit and maintain security certifications.
+	if !dryRun && result.Added+result.Updated > 0 {
+		if err := SaveUserRegistry(projectDir, registry); err != nil {
+			fmt.Printf("Error saving users: %v\n", err)
+			return
+		}
+	}
 
-### Requirements
-- [ ] Annual penetration testing
-- [ ] OWASP Top 10 vulnerability protection
-- [ ] Encrypted data at rest (AES-256)
-- [ ] Encrypted data in transit (TLS 1.3)
-- [ ] Multi-factor authentication support
-- [ ] Role-based access control (RBAC)
-- [ ] Audit logging for all sensitive operations
-- [ ] Secure key management (HSM or cloud KMS)
+	fmt.Println()
+	fmt.Printf("Added: %d, Updated: %d, Unchanged: %d\n", result.Added, result.Updated, result.Unchanged)
+}
 
-## Certifications
-- SOC 2 Type II compliance
-- ISO 27001 certification (planned)
-`,
-		},
-	}
+func showUserImportHelp() {
+	fmt.Println("Usage: portunix pft user import --csv <file> [options]")
+	fmt.Println("       portunix pft user import --ldap <url> --base-dn <dn> [options]")
+	fmt.Println()
+	fmt.Println("Import users from a CSV file or an LDAP/Active Directory server.")
+	fmt.Println("New users are added; existing users (matched by email) get their name,")
+	fmt.Println("organization and mapped roles updated. Other roles are kept.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --csv <file>             CSV file with a header row")
+	fmt.Println("  --ldap <url>             LDAP server (ldap://host or ldaps://host)")
+	fmt.Println("  --base-dn <dn>           Search base, e.g. OU=Staff,DC=example,DC=com")
+	fmt.Println("  --filter <filter>        LDAP search filter (default: " + DefaultLDAPFilter + ")")
+	fmt.Println("  --bind-dn <dn>           DN to bind as (anonymous bind if omitted)")
+	fmt.Println("  --bind-password <pwd>    Bind password or secret:<name> reference")
+	fmt.Println("  --start-tls              Upgrade an ldap:// connection with StartTLS")
+	fmt.Println("  --map <rule>             Role mapping rule (repeatable, first match per area wins)")
+	fmt.Println("  --dry-run                Show what would be imported without changes")
+	fmt.Println()
+	fmt.Println("CSV columns:")
+	fmt.Println("  id|email|mail            User ID (required)")
+	fmt.Println("  name|display_name        Display name")
+	fmt.Println("  organization|org|company Organization")
+	fmt.Println("  voc|vos|vob|voe          Role in that area, overrides mapping rules")
+	fmt.Println("  Other columns can be matched by rules; ';' separates multiple values.")
+	fmt.Println()
+	fmt.Println("Role mapping rules:")
+	fmt.Println("  <area>=<role>[:proxy]                       Role of every imported user")
+	fmt.Println("  <attribute>=<pattern>:<area>=<role>[:proxy] Role of users whose attribute")
+	fmt.Println("                                              matches ('*' is a wildcard)")
+	fmt.Println("  LDAP attributes include mail, displayName, company, department, title,")
+	fmt.Println("  memberOf and dn.")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft user import --csv users.csv --map voc=customer")
+	fmt.Println("  portunix pft user import --csv staff.csv --map \"department=Support:vos=support\"")
+	fmt.Println("  portunix pft user import --ldap ldaps://dc.example.com --base-dn \"DC=example,DC=com\" \\")
+	fmt.Println("    --bind-dn \"CN=pft,OU=Service,DC=example,DC=com\" --bind-password secret:ldap \\")
+	fmt.Println("    --filter \"(&(objectClass=user)(memberOf=CN=Product,OU=Groups,DC=example,DC=com))\" \\")
+	fmt.Println("    --map \"memberOf=CN=Architects,*:vos=architect\" --map vos=developer")
 }
 
-// User command handlers
-func handleUserCommand(args []string) {
+// Role command handlers
+func handleRoleListCommand(args []string) {
 	if len(args) == 0 {
-		showUserHelp()
+		showRoleHelp()
 		return
 	}
 
@@ -4128,7 +7187,7 @@ func handleUserCommand(args []string) {
 	}
 
 	if len(filteredArgs) == 0 {
-		showUserHelp()
+		showRoleHelp()
 		return
 	}
 
@@ -4145,1412 +7204,1391 @@ func handleUserCommand(args []string) {
 
 	switch subcommand {
 	case "list":
-		handleUserListCommand(subArgs, projectDir)
-	case "add":
-		handleUserAddCommand(subArgs, projectDir)
-	case "update":
-		handleUserUpdateCommand(subArgs, projectDir)
-	case "role":
-		handleUserRoleCommand(subArgs, projectDir)
-	case "link":
-		handleUserLinkCommand(subArgs, projectDir)
-	case "remove":
-		handleUserRemoveCommand(subArgs, projectDir)
-	case "show":
-		handleUserShowCommand(subArgs, projectDir)
-	case "sync":
-		handleUserSyncCommand(subArgs, projectDir)
+		handleRoleListSubCommand(subArgs, projectDir)
+	case "init":
+		handleRoleInitCommand(projectDir)
 	case "--help", "-h":
-		showUserHelp()
+		showRoleHelp()
 	default:
-		fmt.Printf("Unknown user subcommand: %s\n", subcommand)
-		showUserHelp()
+		// Treat as category flag for list
+		handleRoleListSubCommand(filteredArgs, projectDir)
 	}
 }
 
-func showUserHelp() {
-	fmt.Println("Usage: portunix pft user <command> [options]")
-	fmt.Println()
-	fmt.Println("User Registry Commands:")
-	fmt.Println()
-	fmt.Println("  list [--voc|--vos|--vob|--voe]  List users (optionally filter by category)")
-	fmt.Println("  add --id <email> --name <name>  Add a new user")
-	fmt.Println("  update <id> [--name|--org]      Update user details")
-	fmt.Println("  show <id>                       Show user details")
-	fmt.Println("  role <id> --voc|--vos|--vob|--voe <role> [--proxy]")
-	fmt.Println("                                  Assign role to user in category")
-	fmt.Println("  role <id> --voc|--vos|--vob|--voe --remove")
-	fmt.Println("                                  Remove role from category")
-	fmt.Println("  link <id> --fider <fider-id>    Link user to Fider ID")
-	fmt.Println("  remove <id>                     Remove user from registry")
-	fmt.Println("  sync [--voc|--vos] [--dry-run]  Sync users from Fider")
+func showRoleHelp() {
+	fmt.Println("Usage: portunix pft role <command> [options]")
 	fmt.Println()
-	fmt.Println("Options for 'add':")
-	fmt.Println("  --id <email>      User ID (typically email)")
-	fmt.Println("  --name <name>     User display name")
-	fmt.Println("  --org <org>       Organization (optional)")
+	fmt.Println("Role Management Commands:")
 	fmt.Println()
-	fmt.Println("Options for 'update':")
-	fmt.Println("  --name <name>     New display name")
-	fmt.Println("  --org <org>       New organization")
+	fmt.Println("  list --voc|--vos|--vob|--voe  List roles for category")
+	fmt.Println("  init                          Initialize default role files")
 	fmt.Println()
-	fmt.Println("Options for 'sync':")
-	fmt.Println("  --voc             Sync only from VoC Fider instance")
-	fmt.Println("  --vos             Sync only from VoS Fider instance")
-	fmt.Println("  --dry-run         Show what would be synced without changes")
-	fmt.Println("  --voc-token <tok> Set VoC Fider API token")
-	fmt.Println("  --vos-token <tok> Set VoS Fider API token")
+	fmt.Println("Categories:")
+	fmt.Println("  --voc    Voice of Customer (customer roles)")
+	fmt.Println("  --vos    Voice of Stakeholder (internal stakeholder roles)")
+	fmt.Println("  --vob    Voice of Business (business roles)")
+	fmt.Println("  --voe    Voice of Engineer (engineering roles)")
 	fmt.Println()
 	fmt.Println("Examples:")
-	fmt.Println("  portunix pft user add --id user@example.com --name \"John Doe\"")
-	fmt.Println("  portunix pft user update user@example.com --name \"Jane Doe\"")
-	fmt.Println("  portunix pft user role user@example.com --vos developer")
-	fmt.Println("  portunix pft user role user@example.com --vos cio --proxy")
-	fmt.Println("  portunix pft user link user@example.com --fider 42")
-	fmt.Println("  portunix pft user sync --voc")
+	fmt.Println("  portunix pft role list --vos")
+	fmt.Println("  portunix pft role init")
 }
 
-func handleUserListCommand(args []string, projectDir string) {
-	// Parse category filter
+func handleRoleListSubCommand(args []string, projectDir string) {
 	var category string
-	for i := 0; i < len(args); i++ {
-		switch args[i] {
+	for _, arg := range args {
+		switch arg {
 		case "--voc":
-			category = "voc"
-		case "--vos":
-			category = "vos"
-		case "--vob":
-			category = "vob"
-		case "--voe":
-			category = "voe"
-		}
-	}
-
-	registry, err := LoadUserRegistry(projectDir)
-	if err != nil {
-		fmt.Printf("Error loading users: %v\n", err)
-		return
-	}
-
-	var users []User
-	if category != "" {
-		users = registry.ListUsersByCategory(category)
-		fmt.Printf("Users with %s roles:\n\n", GetCategoryName(category))
-	} else {
-		users = registry.Users
-		fmt.Println("All users:")
-		fmt.Println()
-	}
-
-	PrintUserList(users, category)
-}
-
-func handleUserAddCommand(args []string, projectDir string) {
-	var id, name, org string
-
-	for i := 0; i < len(args); i++ {
-		switch args[i] {
-		case "--id":
-			if i+1 < len(args) {
-				id = args[i+1]
-				i++
-			}
-		case "--name":
-			if i+1 < len(args) {
-				name = args[i+1]
-				i++
-			}
-		case "--org":
-			if i+1 < len(args) {
-				org = args[i+1]
-				i++
-			}
-		}
-	}
-
-	if id == "" {
-		fmt.Println("Error: --id is required")
-		fmt.Println("Usage: portunix pft user add --id <email> --name <name>")
-		return
-	}
-
-	if name == "" {
-		fmt.Println("Error: --name is required")
-		fmt.Println("Usage: portunix pft user add --id <email> --name <name>")
-		return
-	}
-
-	registry, err := LoadUserRegistry(projectDir)
-	if err != nil {
-		fmt.Printf("Error loading users: %v\n", err)
-		return
-	}
-
-	user := User{
-		ID:           id,
-		Name:         name,
-		Organization: org,
-	}
-
-	if err := registry.AddUser(user); err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
-	}
-
-	if err := SaveUserRegistry(projectDir, registry); err != nil {
-		fmt.Printf("Error saving users: %v\n", err)
-		return
-	}
-
-	fmt.Printf("✓ User '%s' added successfully\n", id)
-}
-
-func handleUserUpdateCommand(args []string, projectDir string) {
-	if len(args) == 0 {
-		fmt.Println("Usage: portunix pft user update <id> [--name <name>] [--org <org>]")
-		return
-	}
-
-	id := args[0]
-	var name, org string
-	var clearOrg bool
-
-	for i := 1; i < len(args); i++ {
-		switch args[i] {
-		case "--name":
-			if i+1 < len(args) {
-				name = args[i+1]
-				i++
-			}
-		case "--org":
-			if i+1 < len(args) {
-				org = args[i+1]
-				i++
-			}
-		case "--org=":
-			clearOrg = true
+			category = "voc"
+		case "--vos":
+			category = "vos"
+		case "--vob":
+			category = "vob"
+		case "--voe":
+			category = "voe"
 		}
 	}
 
-	if name == "" && org == "" && !clearOrg {
-		fmt.Println("Error: at least one of --name or --org is required")
-		fmt.Println("Usage: portunix pft user update <id> [--name <name>] [--org <org>]")
+	if category == "" {
+		fmt.Println("Error: category required (--voc, --vos, --vob, or --voe)")
+		fmt.Println()
+		showRoleHelp()
 		return
 	}
 
-	registry, err := LoadUserRegistry(projectDir)
+	roles, err := LoadRoles(projectDir, category)
 	if err != nil {
-		fmt.Printf("Error loading users: %v\n", err)
+		fmt.Printf("Error loading roles: %v\n", err)
 		return
 	}
 
-	user := registry.FindUser(id)
-	if user == nil {
-		fmt.Printf("User '%s' not found\n", id)
+	fmt.Printf("%s Roles:\n\n", GetCategoryName(category))
+	PrintRoles(roles)
+}
+
+func handleRoleInitCommand(projectDir string) {
+	fmt.Println("Initializing default role files...")
+	fmt.Println()
+
+	if err := InitializeRoles(projectDir); err != nil {
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	// Apply updates
-	updated := false
-	if name != "" {
-		user.Name = name
-		updated = true
-		fmt.Printf("  Name updated to: %s\n", name)
-	}
-	if org != "" {
-		user.Organization = org
-		updated = true
-		fmt.Printf("  Organization updated to: %s\n", org)
-	}
-	if clearOrg {
-		user.Organization = ""
-		updated = true
-		fmt.Println("  Organization cleared")
-	}
+	fmt.Println()
+	fmt.Println("✓ Role files initialized")
+}
 
-	if updated {
-		user.UpdatedAt = time.Now()
-		if err := SaveUserRegistry(projectDir, registry); err != nil {
-			fmt.Printf("Error saving users: %v\n", err)
-			return
-		}
-		fmt.Printf("✓ User '%s' updated successfully\n", id)
+func getProjectDir() string {
+	config, configFilePath, err := LoadConfigWithFilePath()
+	if err == nil {
+		return ResolveProjectPath(config, configFilePath, "")
 	}
+	cwd, _ := os.Getwd()
+	return cwd
 }
 
-func handleUserShowCommand(args []string, projectDir string) {
+// Category command handlers
+func handleCategoryCommand(args []string) {
 	if len(args) == 0 {
-		fmt.Println("Usage: portunix pft user show <id>")
+		showCategoryHelp()
 		return
 	}
 
-	id := args[0]
-
-	registry, err := LoadUserRegistry(projectDir)
-	if err != nil {
-		fmt.Printf("Error loading users: %v\n", err)
-		return
+	// Extract --path from args first
+	var configPath string
+	var filteredArgs []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--path" && i+1 < len(args) {
+			configPath = args[i+1]
+			i++ // skip next arg
+		} else {
+			filteredArgs = append(filteredArgs, args[i])
+		}
 	}
 
-	user := registry.FindUser(id)
-	if user == nil {
-		fmt.Printf("User '%s' not found\n", id)
+	if len(filteredArgs) == 0 {
+		showCategoryHelp()
 		return
 	}
 
-	PrintUser(user)
-}
+	subcommand := filteredArgs[0]
+	subArgs := filteredArgs[1:]
 
-func handleUserRoleCommand(args []string, projectDir string) {
-	if len(args) == 0 {
-		fmt.Println("Usage: portunix pft user role <id> --voc|--vos|--vob|--voe <role> [--proxy]")
-		return
+	// Determine project directory
+	var projectDir string
+	if configPath != "" {
+		projectDir = configPath
+	} else {
+		projectDir = getProjectDir()
 	}
 
-	id := args[0]
-	var category, role string
-	var proxy, remove bool
+	switch subcommand {
+	case "list":
+		handleCategoryListCommand(subArgs, projectDir)
+	case "add":
+		handleCategoryAddCommand(subArgs, projectDir)
+	case "remove":
+		handleCategoryRemoveCommand(subArgs, projectDir)
+	case "rename":
+		handleCategoryRenameCommand(subArgs, projectDir)
+	case "show":
+		handleCategoryShowCommand(subArgs, projectDir)
+	case "--help", "-h":
+		showCategoryHelp()
+	default:
+		fmt.Printf("Unknown category subcommand: %s\n", subcommand)
+		showCategoryHelp()
+	}
+}
 
-	for i := 1; i < len(args); i++ {
+func showCategoryHelp() {
+	fmt.Println("Usage: portunix pft category <command> [options]")
+	fmt.Println()
+	fmt.Println("Category Management Commands:")
+	fmt.Println()
+	fmt.Println("  list [--area <area>]              List categories")
+	fmt.Println("  add <id> --name <name> --area <area>")
+	fmt.Println("                                    Create new category")
+	fmt.Println("  remove <id> --area <area> [--force]")
+	fmt.Println("                                    Delete category")
+	fmt.Println("  rename <id> --name <name> --area <area>")
+	fmt.Println("                                    Rename category")
+	fmt.Println("  show <id> --area <area>           Show category details")
+	fmt.Println()
+	fmt.Println("Areas:")
+	fmt.Println("  voc    Voice of Customer")
+	fmt.Println("  vos    Voice of Stakeholder")
+	fmt.Println("  vob    Voice of Business")
+	fmt.Println("  voe    Voice of Engineer")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --name <name>         Category display name")
+	fmt.Println("  --description <desc>  Category description")
+	fmt.Println("  --color <hex>         Category color (e.g., #3B82F6)")
+	fmt.Println("  --force               Force removal even if items assigned")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft category list --area voc")
+	fmt.Println("  portunix pft category add user-auth --name \"User Authentication\" --area voc")
+	fmt.Println("  portunix pft category remove user-auth --area voc")
+}
+
+func handleCategoryListCommand(args []string, projectDir string) {
+	var area string
+	for i := 0; i < len(args); i++ {
 		switch args[i] {
-		case "--voc":
-			category = "voc"
-			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
-				role = args[i+1]
-				i++
-			}
-		case "--vos":
-			category = "vos"
-			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
-				role = args[i+1]
-				i++
-			}
-		case "--vob":
-			category = "vob"
-			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
-				role = args[i+1]
-				i++
-			}
-		case "--voe":
-			category = "voe"
-			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
-				role = args[i+1]
+		case "--area":
+			if i+1 < len(args) {
+				area = args[i+1]
 				i++
 			}
-		case "--proxy":
-			proxy = true
-		case "--remove":
-			remove = true
 		}
 	}
 
-	if category == "" {
-		fmt.Println("Error: category required (--voc, --vos, --vob, or --voe)")
+	// If no area specified, list all areas
+	if area == "" {
+		for _, a := range ValidAreaNames {
+			printCategoriesForArea(projectDir, a)
+		}
 		return
 	}
 
-	registry, err := LoadUserRegistry(projectDir)
-	if err != nil {
-		fmt.Printf("Error loading users: %v\n", err)
+	if !IsValidArea(area) {
+		fmt.Printf("Error: invalid area '%s' (valid: %s)\n", area, strings.Join(ValidAreaNames, ", "))
 		return
 	}
 
-	user := registry.FindUser(id)
-	if user == nil {
-		fmt.Printf("User '%s' not found\n", id)
+	printCategoriesForArea(projectDir, area)
+}
+
+func printCategoriesForArea(projectDir, area string) {
+	cats, err := GetAllCategoriesWithCounts(projectDir, area)
+	if err != nil {
+		fmt.Printf("Error loading categories for %s: %v\n", area, err)
 		return
 	}
 
-	if remove {
-		if err := user.RemoveRole(category); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
-		}
-		fmt.Printf("✓ Removed %s role from user '%s'\n", GetCategoryName(category), id)
-	} else {
-		if role == "" {
-			fmt.Println("Error: role name required")
-			fmt.Println("Usage: portunix pft user role <id> --vos <role> [--proxy]")
-			return
-		}
-
-		// Validate role
-		valid, err := ValidateRole(projectDir, category, role)
-		if err != nil {
-			fmt.Printf("Error validating role: %v\n", err)
-			return
-		}
-		if !valid {
-			fmt.Printf("Error: role '%s' is not valid for category '%s'\n", role, category)
-			fmt.Printf("Run 'portunix pft role list --%s' to see available roles\n", category)
-			return
-		}
+	areaNames := map[string]string{
+		"voc": "Voice of Customer",
+		"vos": "Voice of Stakeholder",
+		"vob": "Voice of Business",
+		"voe": "Voice of Engineer",
+	}
 
-		if err := user.SetRole(category, role, proxy); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
-		}
+	fmt.Printf("\n📁 %s (%s)\n", areaNames[area], area)
+	fmt.Println(strings.Repeat("-", 50))
 
-		proxyStr := ""
-		if proxy {
-			proxyStr = " (proxy)"
-		}
-		fmt.Printf("✓ Assigned %s role '%s'%s to user '%s'\n", GetCategoryName(category), role, proxyStr, id)
+	if len(cats) == 0 {
+		fmt.Println("   (no categories)")
+		return
 	}
 
-	if err := SaveUserRegistry(projectDir, registry); err != nil {
-		fmt.Printf("Error saving users: %v\n", err)
-		return
+	fmt.Printf("   %-20s %-25s %s\n", "ID", "NAME", "ITEMS")
+	fmt.Println(strings.Repeat("-", 50))
+	for _, cat := range cats {
+		color := ""
+		if cat.Color != "" {
+			color = " " + cat.Color
+		}
+		fmt.Printf("   %-20s %-25s %d%s\n", cat.ID, truncateStr(cat.Name, 25), cat.Count, color)
 	}
 }
 
-func handleUserLinkCommand(args []string, projectDir string) {
+func handleCategoryAddCommand(args []string, projectDir string) {
 	if len(args) == 0 {
-		fmt.Println("Usage: portunix pft user link <id> --fider <fider-id>")
+		fmt.Println("Error: category ID required")
+		fmt.Println("Usage: portunix pft category add <id> --name <name> --area <area>")
 		return
 	}
 
-	id := args[0]
-	var fiderID int
+	categoryID := args[0]
+	var name, description, color, area string
 
 	for i := 1; i < len(args); i++ {
-		if args[i] == "--fider" && i+1 < len(args) {
-			fmt.Sscanf(args[i+1], "%d", &fiderID)
-			i++
+		switch args[i] {
+		case "--name":
+			if i+1 < len(args) {
+				name = args[i+1]
+				i++
+			}
+		case "--description":
+			if i+1 < len(args) {
+				description = args[i+1]
+				i++
+			}
+		case "--color":
+			if i+1 < len(args) {
+				color = args[i+1]
+				i++
+			}
+		case "--area":
+			if i+1 < len(args) {
+				area = args[i+1]
+				i++
+			}
 		}
 	}
 
-	if fiderID == 0 {
-		fmt.Println("Error: --fider <id> is required")
+	if area == "" {
+		fmt.Println("Error: --area is required")
 		return
 	}
-
-	registry, err := LoadUserRegistry(projectDir)
-	if err != nil {
-		fmt.Printf("Error loading users: %v\n", err)
+	if name == "" {
+		fmt.Println("Error: --name is required")
 		return
 	}
 
-	user := registry.FindUser(id)
-	if user == nil {
-		fmt.Printf("User '%s' not found\n", id)
+	registry, err := LoadCategoryRegistry(projectDir, area)
+	if err != nil {
+		fmt.Printf("Error loading categories: %v\n", err)
 		return
 	}
 
-	user.LinkFider(fiderID)
-
-	if err := SaveUserRegistry(projectDir, registry); err != nil {
-		fmt.Printf("Error saving users: %v\n", err)
-		return
+	cat := Category{
+		ID:          categoryID,
+		Name:        name,
+		Description: description,
+		Color:       color,
 	}
 
-	fmt.Printf("✓ Linked user '%s' to Fider ID %d\n", id, fiderID)
-}
-
-func handleUserRemoveCommand(args []string, projectDir string) {
-	if len(args) == 0 {
-		fmt.Println("Usage: portunix pft user remove <id>")
+	if err := registry.AddCategory(cat); err != nil {
+		fmt.Printf("Error adding category: %v\n", err)
 		return
 	}
 
-	id := args[0]
-
-	registry, err := LoadUserRegistry(projectDir)
-	if err != nil {
-		fmt.Printf("Error loading users: %v\n", err)
+	if err := SaveCategoryRegistry(projectDir, area, registry); err != nil {
+		fmt.Printf("Error saving categories: %v\n", err)
 		return
 	}
 
-	if err := registry.RemoveUser(id); err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
-	}
+	fmt.Printf("✓ Category '%s' added to %s\n", NormalizeCategoryID(categoryID), area)
+}
 
-	if err := SaveUserRegistry(projectDir, registry); err != nil {
-		fmt.Printf("Error saving users: %v\n", err)
+func handleCategoryRemoveCommand(args []string, projectDir string) {
+	if len(args) == 0 {
+		fmt.Println("Error: category ID required")
+		fmt.Println("Usage: portunix pft category remove <id> --area <area> [--force]")
 		return
 	}
 
-	fmt.Printf("✓ User '%s' removed\n", id)
-}
-
-func handleUserSyncCommand(args []string, projectDir string) {
-	// Parse flags
-	var syncVoC, syncVoS, dryRun bool
-	var vocToken, vosToken string
+	categoryID := args[0]
+	var area string
+	var force bool
 
-	for i := 0; i < len(args); i++ {
+	for i := 1; i < len(args); i++ {
 		switch args[i] {
-		case "--voc":
-			syncVoC = true
-		case "--vos":
-			syncVoS = true
-		case "--dry-run":
-			dryRun = true
-		case "--voc-token":
-			if i+1 < len(args) {
-				vocToken = args[i+1]
-				i++
-			}
-		case "--vos-token":
+		case "--area":
 			if i+1 < len(args) {
-				vosToken = args[i+1]
+				area = args[i+1]
 				i++
 			}
-		case "--help", "-h":
-			showUserSyncHelp()
-			return
+		case "--force":
+			force = true
 		}
 	}
 
-	// If neither specified, sync both
-	if !syncVoC && !syncVoS {
-		syncVoC = true
-		syncVoS = true
+	if area == "" {
+		fmt.Println("Error: --area is required")
+		return
 	}
 
-	config, err := LoadConfig()
+	// Check if category has items assigned
+	count, err := CountItemsInCategory(projectDir, area, categoryID)
 	if err != nil {
-		fmt.Println("No configuration found. Run 'portunix pft configure' first.")
+		fmt.Printf("Error counting items: %v\n", err)
 		return
 	}
 
-	// Update config with tokens if provided
-	if vocToken != "" {
-		config.VoC.APIToken = vocToken
-	}
-	if vosToken != "" {
-		config.VoS.APIToken = vosToken
+	if count > 0 && !force {
+		fmt.Printf("Error: category '%s' has %d items assigned\n", categoryID, count)
+		fmt.Println("Use --force to remove anyway (items will become uncategorized)")
+		return
 	}
 
-	// Load user registry
-	registry, err := LoadUserRegistry(projectDir)
+	registry, err := LoadCategoryRegistry(projectDir, area)
 	if err != nil {
-		fmt.Printf("Error loading user registry: %v\n", err)
+		fmt.Printf("Error loading categories: %v\n", err)
 		return
 	}
 
-	fmt.Println("Synchronizing users from Fider...")
-	if dryRun {
-		fmt.Println("(dry-run mode - no changes will be made)")
+	if err := registry.RemoveCategory(categoryID); err != nil {
+		fmt.Printf("Error removing category: %v\n", err)
+		return
 	}
-	fmt.Println()
-
-	totalAdded := 0
-	totalUpdated := 0
-	totalSkipped := 0
-
-	// Sync VoC users
-	if syncVoC {
-		fmt.Println("👥 VoC (Voice of Customer) users:")
 
-		vocURL := config.VoC.URL
-		if vocURL == "" {
-			vocURL = "http://localhost:3100"
-		}
-		vocAPIToken := config.VoC.APIToken
-		if vocAPIToken == "" {
-			vocAPIToken = config.GetAPIToken()
-		}
+	if err := SaveCategoryRegistry(projectDir, area, registry); err != nil {
+		fmt.Printf("Error saving categories: %v\n", err)
+		return
+	}
 
-		if vocAPIToken == "" {
-			fmt.Println("   ✗ No API token configured for VoC")
-			fmt.Println("   Run: portunix pft user sync --voc --voc-token <your-token>")
-		} else {
-			client := NewFiderClient(vocURL, vocAPIToken)
-			added, updated, skipped, err := syncUsersFromFider(client, registry, "voc", dryRun)
-			if err != nil {
-				fmt.Printf("   ✗ Sync failed: %v\n", err)
-			} else {
-				fmt.Printf("   Added: %d, Updated: %d, Skipped: %d\n", added, updated, skipped)
-				totalAdded += added
-				totalUpdated += updated
-				totalSkipped += skipped
-			}
-		}
-		fmt.Println()
+	fmt.Printf("✓ Category '%s' removed from %s\n", NormalizeCategoryID(categoryID), area)
+	if count > 0 {
+		fmt.Printf("  Note: %d items are now uncategorized\n", count)
 	}
+}
 
-	// Sync VoS users
-	if syncVoS {
-		fmt.Println("👥 VoS (Voice of Stakeholder) users:")
+func handleCategoryRenameCommand(args []string, projectDir string) {
+	if len(args) == 0 {
+		fmt.Println("Error: category ID required")
+		fmt.Println("Usage: portunix pft category rename <id> --name <name> --area <area>")
+		return
+	}
 
-		vosURL := config.VoS.URL
-		if vosURL == "" {
-			vosURL = "http://localhost:3101"
-		}
-		vosAPIToken := config.VoS.APIToken
-		if vosAPIToken == "" {
-			vosAPIToken = config.GetAPIToken()
-		}
+	categoryID := args[0]
+	var name, description, color, area string
 
-		if vosAPIToken == "" {
-			fmt.Println("   ✗ No API token configured for VoS")
-			fmt.Println("   Run: portunix pft user sync --vos --vos-token <your-token>")
-		} else {
-			client := NewFiderClient(vosURL, vosAPIToken)
-			added, updated, skipped, err := syncUsersFromFider(client, registry, "vos", dryRun)
-			if err != nil {
-				fmt.Printf("   ✗ Sync failed: %v\n", err)
-			} else {
-				fmt.Printf("   Added: %d, Updated: %d, Skipped: %d\n", added, updated, skipped)
-				totalAdded += added
-				totalUpdated += updated
-				totalSkipped += skipped
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--name":
+			if i+1 < len(args) {
+				name = args[i+1]
+				i++
+			}
+		case "--description":
+			if i+1 < len(args) {
+				description = args[i+1]
+				i++
+			}
+		case "--color":
+			if i+1 < len(args) {
+				color = args[i+1]
+				i++
+			}
+		case "--area":
+			if i+1 < len(args) {
+				area = args[i+1]
+				i++
 			}
-		}
-		fmt.Println()
-	}
-
-	// Save registry if not dry-run
-	if !dryRun && (totalAdded > 0 || totalUpdated > 0) {
-		if err := SaveUserRegistry(projectDir, registry); err != nil {
-			fmt.Printf("Error saving user registry: %v\n", err)
-			return
 		}
 	}
 
-	// Save updated config with tokens if they were provided
-	if vocToken != "" || vosToken != "" {
-		configPath, _ := findConfigFile()
-		if configPath != "" {
-			config.SaveToPath(configPath)
-			fmt.Println("Configuration updated with API tokens.")
-		}
+	if area == "" {
+		fmt.Println("Error: --area is required")
+		return
 	}
 
-	fmt.Printf("User sync complete. Added: %d, Updated: %d, Skipped: %d\n", totalAdded, totalUpdated, totalSkipped)
-}
-
-// syncUsersFromFider fetches users from Fider and syncs them to local registry
-func syncUsersFromFider(client *FiderClient, registry *UserRegistry, category string, dryRun bool) (added, updated, skipped int, err error) {
-	// Fetch users from Fider
-	fiderUsers, err := client.ListUsers()
+	registry, err := LoadCategoryRegistry(projectDir, area)
 	if err != nil {
-		return 0, 0, 0, fmt.Errorf("failed to fetch users from Fider: %w", err)
+		fmt.Printf("Error loading categories: %v\n", err)
+		return
 	}
 
-	fmt.Printf("   Found %d users in Fider\n", len(fiderUsers))
+	updates := Category{
+		Name:        name,
+		Description: description,
+		Color:       color,
+	}
 
-	for _, fiderUser := range fiderUsers {
-		// Skip users without email
-		if fiderUser.Email == "" {
-			fmt.Printf("   ⚠ Skipping user '%s' (no email)\n", fiderUser.Name)
-			skipped++
-			continue
-		}
+	if err := registry.UpdateCategory(categoryID, updates); err != nil {
+		fmt.Printf("Error updating category: %v\n", err)
+		return
+	}
 
-		// Try to find existing user by Fider ID first
-		existingUser := registry.FindUserByFiderID(fiderUser.ID)
-		if existingUser != nil {
-			// User already linked by Fider ID
-			fmt.Printf("   ⏭ %s (already linked via Fider ID %d)\n", fiderUser.Email, fiderUser.ID)
-			skipped++
-			continue
-		}
+	if err := SaveCategoryRegistry(projectDir, area, registry); err != nil {
+		fmt.Printf("Error saving categories: %v\n", err)
+		return
+	}
 
-		// Try to find by email
-		existingUser = registry.FindUserByEmail(fiderUser.Email)
-		if existingUser != nil {
-			// User exists, update Fider ID link
-			if dryRun {
-				fmt.Printf("   🔗 Would link: %s → Fider ID %d\n", fiderUser.Email, fiderUser.ID)
-			} else {
-				existingUser.LinkFider(fiderUser.ID)
-				fmt.Printf("   🔗 Linked: %s → Fider ID %d\n", fiderUser.Email, fiderUser.ID)
-			}
-			updated++
-			continue
-		}
+	fmt.Printf("✓ Category '%s' updated in %s\n", NormalizeCategoryID(categoryID), area)
+}
 
-		// New user - add to registry
-		if dryRun {
-			fmt.Printf("   ➕ Would add: %s (%s) with Fider ID %d\n", fiderUser.Email, fiderUser.Name, fiderUser.ID)
-		} else {
-			newUser := User{
-				ID:   fiderUser.Email,
-				Name: fiderUser.Name,
-				ExternalIDs: &ExternalIDs{
-					Fider: fiderUser.ID,
-				},
-			}
+func handleCategoryShowCommand(args []string, projectDir string) {
+	if len(args) == 0 {
+		fmt.Println("Error: category ID required")
+		fmt.Println("Usage: portunix pft category show <id> --area <area>")
+		return
+	}
 
-			// Assign default role based on category
-			switch category {
-			case "voc":
-				newUser.Roles.VoC = &RoleAssignment{Role: "customer", Proxy: false}
-			case "vos":
-				newUser.Roles.VoS = &RoleAssignment{Role: "developer", Proxy: false}
-			}
+	categoryID := args[0]
+	var area string
 
-			if err := registry.AddUser(newUser); err != nil {
-				fmt.Printf("   ✗ Failed to add %s: %v\n", fiderUser.Email, err)
-				continue
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--area":
+			if i+1 < len(args) {
+				area = args[i+1]
+				i++
 			}
-			fmt.Printf("   ➕ Added: %s (%s) with Fider ID %d\n", fiderUser.Email, fiderUser.Name, fiderUser.ID)
 		}
-		added++
 	}
 
-	return added, updated, skipped, nil
-}
-
-func showUserSyncHelp() {
-	fmt.Println("Usage: portunix pft user sync [options]")
-	fmt.Println()
-	fmt.Println("Synchronize users from Fider instances to local registry.")
-	fmt.Println()
-	fmt.Println("This command will:")
-	fmt.Println("  1. Fetch all users from Fider API")
-	fmt.Println("  2. Add new users to local registry")
-	fmt.Println("  3. Link existing users by email to their Fider IDs")
-	fmt.Println()
-	fmt.Println("Options:")
-	fmt.Println("  --voc              Sync only from VoC Fider instance")
-	fmt.Println("  --vos              Sync only from VoS Fider instance")
-	fmt.Println("  --voc-token <tok>  Set VoC Fider API token")
-	fmt.Println("  --vos-token <tok>  Set VoS Fider API token")
-	fmt.Println("  --dry-run          Show what would be synced without making changes")
-	fmt.Println()
-	fmt.Println("New users are assigned default roles:")
-	fmt.Println("  VoC: customer")
-	fmt.Println("  VoS: developer")
-	fmt.Println()
-	fmt.Println("Examples:")
-	fmt.Println("  portunix pft user sync --voc")
-	fmt.Println("  portunix pft user sync --dry-run")
-	fmt.Println("  portunix pft user sync --voc --voc-token abc123")
-}
-
-// Role command handlers
-func handleRoleListCommand(args []string) {
-	if len(args) == 0 {
-		showRoleHelp()
+	if area == "" {
+		fmt.Println("Error: --area is required")
 		return
 	}
 
-	// Extract --path from args first
-	var configPath string
-	var filteredArgs []string
-	for i := 0; i < len(args); i++ {
-		if args[i] == "--path" && i+1 < len(args) {
-			configPath = args[i+1]
-			i++ // skip next arg
-		} else {
-			filteredArgs = append(filteredArgs, args[i])
-		}
+	registry, err := LoadCategoryRegistry(projectDir, area)
+	if err != nil {
+		fmt.Printf("Error loading categories: %v\n", err)
+		return
 	}
 
-	if len(filteredArgs) == 0 {
-		showRoleHelp()
+	cat, err := registry.GetCategory(categoryID)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	subcommand := filteredArgs[0]
-	subArgs := filteredArgs[1:]
+	count, _ := CountItemsInCategory(projectDir, area, categoryID)
 
-	// Determine project directory
-	var projectDir string
-	if configPath != "" {
-		projectDir = configPath
-	} else {
-		projectDir = getProjectDir()
+	fmt.Printf("Category: %s\n", cat.ID)
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Printf("  Name: %s\n", cat.Name)
+	if cat.Description != "" {
+		fmt.Printf("  Description: %s\n", cat.Description)
 	}
-
-	switch subcommand {
-	case "list":
-		handleRoleListSubCommand(subArgs, projectDir)
-	case "init":
-		handleRoleInitCommand(projectDir)
-	case "--help", "-h":
-		showRoleHelp()
-	default:
-		// Treat as category flag for list
-		handleRoleListSubCommand(filteredArgs, projectDir)
+	if cat.Color != "" {
+		fmt.Printf("  Color: %s\n", cat.Color)
 	}
+	fmt.Printf("  Order: %d\n", cat.Order)
+	fmt.Printf("  Items: %d\n", count)
+	fmt.Printf("  Created: %s\n", cat.CreatedAt)
+	fmt.Printf("  Updated: %s\n", cat.UpdatedAt)
 }
 
-func showRoleHelp() {
-	fmt.Println("Usage: portunix pft role <command> [options]")
-	fmt.Println()
-	fmt.Println("Role Management Commands:")
-	fmt.Println()
-	fmt.Println("  list --voc|--vos|--vob|--voe  List roles for category")
-	fmt.Println("  init                          Initialize default role files")
-	fmt.Println()
-	fmt.Println("Categories:")
-	fmt.Println("  --voc    Voice of Customer (customer roles)")
-	fmt.Println("  --vos    Voice of Stakeholder (internal stakeholder roles)")
-	fmt.Println("  --vob    Voice of Business (business roles)")
-	fmt.Println("  --voe    Voice of Engineer (engineering roles)")
-	fmt.Println()
-	fmt.Println("Examples:")
-	fmt.Println("  portunix pft role list --vos")
-	fmt.Println("  portunix pft role init")
-}
+// Assign/Unassign command handlers
+func handleAssignCommand(args []string) {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		showAssignHelp()
+		return
+	}
 
-func handleRoleListSubCommand(args []string, projectDir string) {
-	var category string
-	for _, arg := range args {
-		switch arg {
-		case "--voc":
-			category = "voc"
-		case "--vos":
-			category = "vos"
-		case "--vob":
-			category = "vob"
-		case "--voe":
-			category = "voe"
+	// Parse arguments - first non-flag argument is itemID
+	var itemID string
+	var categoryID string
+	var configPath string
+	var setMode bool
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--category", "-c":
+			if i+1 < len(args) {
+				categoryID = args[i+1]
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				configPath = args[i+1]
+				i++
+			}
+		case "--set", "-s":
+			setMode = true
+		case "--help", "-h":
+			showAssignHelp()
+			return
+		default:
+			if !strings.HasPrefix(args[i], "-") && itemID == "" {
+				itemID = args[i]
+			}
 		}
 	}
 
-	if category == "" {
-		fmt.Println("Error: category required (--voc, --vos, --vob, or --voe)")
-		fmt.Println()
-		showRoleHelp()
+	if itemID == "" {
+		fmt.Println("Error: item ID is required")
+		showAssignHelp()
 		return
 	}
 
-	roles, err := LoadRoles(projectDir, category)
-	if err != nil {
-		fmt.Printf("Error loading roles: %v\n", err)
+	if categoryID == "" {
+		fmt.Println("Error: --category is required")
+		showAssignHelp()
 		return
 	}
 
-	fmt.Printf("%s Roles:\n\n", GetCategoryName(category))
-	PrintRoles(roles)
-}
+	config, configFilePath, err := loadOrCreateConfig(configPath)
+	if err != nil {
+		fmt.Println("No configuration found. Run 'portunix pft configure' first.")
+		return
+	}
 
-func handleRoleInitCommand(projectDir string) {
-	fmt.Println("Initializing default role files...")
-	fmt.Println()
+	// Use cross-platform path resolution
+	projectDir := ResolveProjectPath(config, configFilePath, configPath)
 
-	if err := InitializeRoles(projectDir); err != nil {
+	// Find the item file
+	filePath, feedbackType, err := findFeedbackItemFile(projectDir, itemID)
+	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	fmt.Println()
-	fmt.Println("✓ Role files initialized")
-}
+	// Verify category exists in the area
+	registry, err := LoadCategoryRegistry(projectDir, feedbackType)
+	if err != nil {
+		fmt.Printf("Error loading categories: %v\n", err)
+		return
+	}
 
-func getProjectDir() string {
-	config, configFilePath, err := LoadConfigWithFilePath()
-	if err == nil {
-		return ResolveProjectPath(config, configFilePath, "")
+	if !registry.HasCategory(categoryID) {
+		fmt.Printf("Error: category '%s' not found in %s\n", categoryID, feedbackType)
+		fmt.Println("Use 'portunix pft category list --area " + feedbackType + "' to see available categories")
+		return
+	}
+
+	// Set or add category to file
+	if setMode {
+		// Replace all categories with the new one
+		if err := SetCategoryToFile(filePath, categoryID); err != nil {
+			fmt.Printf("Error setting category: %v\n", err)
+			return
+		}
+		fmt.Printf("✓ Set category '%s' to %s (replaced all previous)\n", categoryID, itemID)
+	} else {
+		// Add category to existing ones
+		if err := AddCategoryToFile(filePath, categoryID); err != nil {
+			fmt.Printf("Error assigning category: %v\n", err)
+			return
+		}
+		fmt.Printf("✓ Assigned category '%s' to %s\n", categoryID, itemID)
 	}
-	cwd, _ := os.Getwd()
-	return cwd
 }
 
-// Category command handlers
-func handleCategoryCommand(args []string) {
-	if len(args) == 0 {
-		showCategoryHelp()
+func handleUnassignCommand(args []string) {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		showUnassignHelp()
 		return
 	}
 
-	// Extract --path from args first
+	// Parse arguments - first non-flag argument is itemID
+	var itemID string
+	var categoryID string
 	var configPath string
-	var filteredArgs []string
+	var removeAll bool
+
 	for i := 0; i < len(args); i++ {
-		if args[i] == "--path" && i+1 < len(args) {
-			configPath = args[i+1]
-			i++ // skip next arg
-		} else {
-			filteredArgs = append(filteredArgs, args[i])
+		switch args[i] {
+		case "--category", "-c":
+			if i+1 < len(args) {
+				categoryID = args[i+1]
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				configPath = args[i+1]
+				i++
+			}
+		case "--all":
+			removeAll = true
+		case "--help", "-h":
+			showUnassignHelp()
+			return
+		default:
+			if !strings.HasPrefix(args[i], "-") && itemID == "" {
+				itemID = args[i]
+			}
 		}
 	}
 
-	if len(filteredArgs) == 0 {
-		showCategoryHelp()
+	if itemID == "" {
+		fmt.Println("Error: item ID is required")
+		showUnassignHelp()
 		return
 	}
 
-	subcommand := filteredArgs[0]
-	subArgs := filteredArgs[1:]
+	if categoryID == "" && !removeAll {
+		fmt.Println("Error: --category or --all is required")
+		showUnassignHelp()
+		return
+	}
 
-	// Determine project directory
-	var projectDir string
-	if configPath != "" {
-		projectDir = configPath
-	} else {
-		projectDir = getProjectDir()
+	config, configFilePath, err := loadOrCreateConfig(configPath)
+	if err != nil {
+		fmt.Println("No configuration found. Run 'portunix pft configure' first.")
+		return
 	}
 
-	switch subcommand {
-	case "list":
-		handleCategoryListCommand(subArgs, projectDir)
-	case "add":
-		handleCategoryAddCommand(subArgs, projectDir)
-	case "remove":
-		handleCategoryRemoveCommand(subArgs, projectDir)
-	case "rename":
-		handleCategoryRenameCommand(subArgs, projectDir)
-	case "show":
-		handleCategoryShowCommand(subArgs, projectDir)
-	case "--help", "-h":
-		showCategoryHelp()
-	default:
-		fmt.Printf("Unknown category subcommand: %s\n", subcommand)
-		showCategoryHelp()
+	// Use cross-platform path resolution
+	projectDir := ResolveProjectPath(config, configFilePath, configPath)
+
+	// Find the item file
+	filePath, _, err := findFeedbackItemFile(projectDir, itemID)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
 	}
-}
 
-func showCategoryHelp() {
-	fmt.Println("Usage: portunix pft category <command> [options]")
-	fmt.Println()
-	fmt.Println("Category Management Commands:")
-	fmt.Println()
-	fmt.Println("  list [--area <area>]              List categories")
-	fmt.Println("  add <id> --name <name> --area <area>")
-	fmt.Println("                                    Create new category")
-	fmt.Println("  remove <id> --area <area> [--force]")
-	fmt.Println("                                    Delete category")
-	fmt.Println("  rename <id> --name <name> --area <area>")
-	fmt.Println("                                    Rename category")
-	fmt.Println("  show <id> --area <area>           Show category details")
-	fmt.Println()
-	fmt.Println("Areas:")
-	fmt.Println("  voc    Voice of Customer")
-	fmt.Println("  vos    Voice of Stakeholder")
-	fmt.Println("  vob    Voice of Business")
-	fmt.Println("  voe    Voice of Engineer")
-	fmt.Println()
-	fmt.Println("Options:")
-	fmt.Println("  --name <name>         Category display name")
-	fmt.Println("  --description <desc>  Category description")
-	fmt.Println("  --color <hex>         Category color (e.g., #3B82F6)")
-	fmt.Println("  --force               Force removal even if items assigned")
-	fmt.Println()
-	fmt.Println("Examples:")
-	fmt.Println("  portunix pft category list --area voc")
-	fmt.Println("  portunix pft category add user-auth --name \"User Authentication\" --area voc")
-	fmt.Println("  portunix pft category remove user-auth --area voc")
+	if removeAll {
+		if err := ClearCategoriesFromFile(filePath); err != nil {
+			fmt.Printf("Error removing categories: %v\n", err)
+			return
+		}
+		fmt.Printf("✓ Removed all categories from %s\n", itemID)
+	} else {
+		if err := RemoveCategoryFromFile(filePath, categoryID); err != nil {
+			fmt.Printf("Error removing category: %v\n", err)
+			return
+		}
+		fmt.Printf("✓ Removed category '%s' from %s\n", categoryID, itemID)
+	}
 }
 
-func handleCategoryListCommand(args []string, projectDir string) {
-	var area string
+func handleEstimateCommand(args []string) {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		showEstimateHelp()
+		return
+	}
+
+	// Parse arguments - the item ID, then the estimate; "1w 2d" may be
+	// passed quoted or as separate arguments
+	var itemID string
+	var estimateParts []string
+	var actual string
+	var configPath string
+	var clear bool
+
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
-		case "--area":
+		case "--actual", "-a":
 			if i+1 < len(args) {
-				area = args[i+1]
+				actual = args[i+1]
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				configPath = args[i+1]
 				i++
 			}
+		case "--clear":
+			clear = true
+		case "--help", "-h":
+			showEstimateHelp()
+			return
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				continue
+			}
+			if itemID == "" {
+				itemID = args[i]
+			} else {
+				estimateParts = append(estimateParts, args[i])
+			}
 		}
 	}
 
-	// If no area specified, list all areas
-	if area == "" {
-		for _, a := range ValidAreaNames {
-			printCategoriesForArea(projectDir, a)
-		}
+	if itemID == "" {
+		fmt.Println("Error: item ID is required")
+		showEstimateHelp()
 		return
 	}
 
-	if !IsValidArea(area) {
-		fmt.Printf("Error: invalid area '%s' (valid: %s)\n", area, strings.Join(ValidAreaNames, ", "))
-		return
+	// Normalize the efforts, so reports and exports read them back
+	fields := map[string]string{}
+	if len(estimateParts) > 0 {
+		hours, err := ParseEffort(strings.Join(estimateParts, " "))
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fields["estimate"] = FormatEffort(hours)
+	}
+	if actual != "" {
+		hours, err := ParseEffort(actual)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fields["actual_effort"] = FormatEffort(hours)
+	}
+	if clear {
+		fields["estimate"] = ""
+		fields["actual_effort"] = ""
 	}
 
-	printCategoriesForArea(projectDir, area)
-}
-
-func printCategoriesForArea(projectDir, area string) {
-	cats, err := GetAllCategoriesWithCounts(projectDir, area)
+	config, configFilePath, err := loadOrCreateConfig(configPath)
 	if err != nil {
-		fmt.Printf("Error loading categories for %s: %v\n", area, err)
+		fmt.Println("No configuration found. Run 'portunix pft configure' first.")
 		return
 	}
 
-	areaNames := map[string]string{
-		"voc": "Voice of Customer",
-		"vos": "Voice of Stakeholder",
-		"vob": "Voice of Business",
-		"voe": "Voice of Engineer",
-	}
-
-	fmt.Printf("\n📁 %s (%s)\n", areaNames[area], area)
-	fmt.Println(strings.Repeat("-", 50))
+	// Use cross-platform path resolution
+	projectDir := ResolveProjectPath(config, configFilePath, configPath)
 
-	if len(cats) == 0 {
-		fmt.Println("   (no categories)")
+	filePath, _, err := findFeedbackItemFile(projectDir, itemID)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	fmt.Printf("   %-20s %-25s %s\n", "ID", "NAME", "ITEMS")
-	fmt.Println(strings.Repeat("-", 50))
-	for _, cat := range cats {
-		color := ""
-		if cat.Color != "" {
-			color = " " + cat.Color
+	if len(fields) == 0 {
+		// No new values: show the current ones
+		item, err := ParseMarkdownFile(filePath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
 		}
-		fmt.Printf("   %-20s %-25s %d%s\n", cat.ID, truncateStr(cat.Name, 25), cat.Count, color)
+		fmt.Printf("%s: estimate %s, actual %s\n", itemID, firstNonEmpty(item.Estimate, "-"), firstNonEmpty(item.ActualEffort, "-"))
+		return
 	}
-}
 
-func handleCategoryAddCommand(args []string, projectDir string) {
-	if len(args) == 0 {
-		fmt.Println("Error: category ID required")
-		fmt.Println("Usage: portunix pft category add <id> --name <name> --area <area>")
+	if err := UpdateFileFields(filePath, fields); err != nil {
+		fmt.Printf("Error updating effort: %v\n", err)
+		return
+	}
+	if clear {
+		fmt.Printf("✓ Cleared effort of %s\n", itemID)
 		return
 	}
+	if value, ok := fields["estimate"]; ok {
+		fmt.Printf("✓ Estimated %s at %s\n", itemID, value)
+	}
+	if value, ok := fields["actual_effort"]; ok {
+		fmt.Printf("✓ Recorded actual effort of %s for %s\n", value, itemID)
+	}
+}
 
-	categoryID := args[0]
-	var name, description, color, area string
+func handleAnalyzeCommand(args []string) {
+	var cluster, score, useLLM, force, dryRun, jsonOutput bool
+	var area string
+	var applyPath, approve, provider, configPath string
+	var threshold float64
 
-	for i := 1; i < len(args); i++ {
+	for i := 0; i < len(args); i++ {
 		switch args[i] {
-		case "--name":
+		case "--cluster":
+			cluster = true
+		case "--score":
+			score = true
+		case "--llm":
+			useLLM = true
+		case "--force":
+			force = true
+		case "--area":
 			if i+1 < len(args) {
-				name = args[i+1]
+				area = strings.ToLower(args[i+1])
 				i++
 			}
-		case "--description":
+		case "--threshold":
 			if i+1 < len(args) {
-				description = args[i+1]
+				fmt.Sscanf(args[i+1], "%g", &threshold)
 				i++
 			}
-		case "--color":
+		case "--provider":
 			if i+1 < len(args) {
-				color = args[i+1]
+				provider = args[i+1]
 				i++
 			}
-		case "--area":
+		case "--apply":
 			if i+1 < len(args) {
-				area = args[i+1]
+				applyPath = args[i+1]
+				i++
+			}
+		case "--approve":
+			if i+1 < len(args) {
+				approve = args[i+1]
+				i++
+			}
+		case "--dry-run":
+			dryRun = true
+		case "--json":
+			jsonOutput = true
+		case "--path":
+			if i+1 < len(args) {
+				configPath = args[i+1]
 				i++
 			}
+		case "--help", "-h":
+			showAnalyzeHelp()
+			return
 		}
 	}
 
-	if area == "" {
-		fmt.Println("Error: --area is required")
-		return
-	}
-	if name == "" {
-		fmt.Println("Error: --name is required")
+	if !cluster && !score && applyPath == "" {
+		showAnalyzeHelp()
 		return
 	}
 
-	registry, err := LoadCategoryRegistry(projectDir, area)
+	config, configFilePath, err := loadOrCreateConfig(configPath)
 	if err != nil {
-		fmt.Printf("Error loading categories: %v\n", err)
-		return
-	}
-
-	cat := Category{
-		ID:          categoryID,
-		Name:        name,
-		Description: description,
-		Color:       color,
-	}
-
-	if err := registry.AddCategory(cat); err != nil {
-		fmt.Printf("Error adding category: %v\n", err)
+		fmt.Println("No configuration found. Run 'portunix pft configure' first.")
 		return
 	}
+	projectDir := ResolveProjectPath(config, configFilePath, configPath)
 
-	if err := SaveCategoryRegistry(projectDir, area, registry); err != nil {
-		fmt.Printf("Error saving categories: %v\n", err)
+	if applyPath != "" {
+		analysis, err := LoadClusterAnalysis(applyPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if approve != "" {
+			approveClusters(analysis, approve)
+		}
+		changes, err := ApplyClusterAnalysis(projectDir, analysis, dryRun)
+		for _, change := range changes {
+			if dryRun {
+				fmt.Printf("  [DRY-RUN] Would %s\n", change)
+			} else {
+				fmt.Printf("  ✓ %s\n", strings.ToUpper(change[:1])+change[1:])
+			}
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if len(changes) == 0 {
+			fmt.Println("No approved clusters. Set \"approved\": true in the analysis file or use --approve <ids|all>.")
+		}
 		return
 	}
 
-	fmt.Printf("✓ Category '%s' added to %s\n", NormalizeCategoryID(categoryID), area)
-}
-
-func handleCategoryRemoveCommand(args []string, projectDir string) {
-	if len(args) == 0 {
-		fmt.Println("Error: category ID required")
-		fmt.Println("Usage: portunix pft category remove <id> --area <area> [--force]")
+	if area != "" && !IsValidArea(area) {
+		fmt.Printf("Error: invalid area '%s' (valid: %s)\n", area, strings.Join(ValidAreaNames, ", "))
 		return
 	}
-
-	categoryID := args[0]
-	var area string
-	var force bool
-
-	for i := 1; i < len(args); i++ {
-		switch args[i] {
-		case "--area":
-			if i+1 < len(args) {
-				area = args[i+1]
-				i++
-			}
-		case "--force":
-			force = true
+	ai := config.AI
+	if provider != "" {
+		override := AIConfig{Provider: provider}
+		if ai != nil && ai.Provider == provider {
+			override = *ai
 		}
+		ai = &override
 	}
 
-	if area == "" {
-		fmt.Println("Error: --area is required")
+	if score {
+		scoreAreaItems(projectDir, area, ai, useLLM, force, dryRun, jsonOutput)
 		return
 	}
 
-	// Check if category has items assigned
-	count, err := CountItemsInCategory(projectDir, area, categoryID)
+	if area == "" {
+		area = "voc"
+	}
+	embedder, err := NewEmbedder(ai)
 	if err != nil {
-		fmt.Printf("Error counting items: %v\n", err)
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
-
-	if count > 0 && !force {
-		fmt.Printf("Error: category '%s' has %d items assigned\n", categoryID, count)
-		fmt.Println("Use --force to remove anyway (items will become uncategorized)")
+	items, err := scanLocalDirectory(getVoiceDir(projectDir, area), area)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
-
 	registry, err := LoadCategoryRegistry(projectDir, area)
 	if err != nil {
 		fmt.Printf("Error loading categories: %v\n", err)
 		return
 	}
 
-	if err := registry.RemoveCategory(categoryID); err != nil {
-		fmt.Printf("Error removing category: %v\n", err)
+	if !jsonOutput {
+		fmt.Printf("Clustering %d %s items with %s...\n", len(items), strings.ToUpper(area), embedder.Name())
+	}
+	analysis, err := AnalyzeClusters(area, items, registry.Categories, embedder, threshold)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	path, err := SaveClusterAnalysis(projectDir, analysis)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	if err := SaveCategoryRegistry(projectDir, area, registry); err != nil {
-		fmt.Printf("Error saving categories: %v\n", err)
+	if jsonOutput {
+		data, _ := json.MarshalIndent(analysis, "", "  ")
+		fmt.Println(string(data))
 		return
 	}
+	for _, warning := range analysis.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+	for _, c := range analysis.Clusters {
+		fmt.Printf("\n🧩 Cluster %d: %s (%d items)\n", c.ID, strings.Join(c.Keywords, ", "), len(c.Items))
+		fmt.Printf("   %s\n", c.Summary)
+		status := "new"
+		if c.Category.Existing {
+			status = "existing"
+		}
+		fmt.Printf("   Proposed category: %s - %s (%s)\n", c.Category.ID, c.Category.Name, status)
+		for _, item := range c.Items {
+			fmt.Printf("     %-10s %s\n", item.ID, truncateStr(item.Title, 50))
+		}
+	}
+	if len(analysis.Unclustered) > 0 {
+		fmt.Printf("\nNot grouped: %d items\n", len(analysis.Unclustered))
+	}
+	fmt.Printf("\nAnalysis saved to %s (workshop report: %s)\n", path, strings.TrimSuffix(path, ".json")+".md")
+	fmt.Println("Review the proposals, then apply them:")
+	fmt.Printf("  portunix pft analyze --apply %s --approve <ids|all>\n", path)
+}
 
-	fmt.Printf("✓ Category '%s' removed from %s\n", NormalizeCategoryID(categoryID), area)
-	if count > 0 {
-		fmt.Printf("  Note: %d items are now uncategorized\n", count)
+// scoreAreaItems scores the verbatims of an area, or of all areas when area
+// is empty, and prints the most urgent and negative ones
+func scoreAreaItems(projectDir, area string, ai *AIConfig, useLLM, force, dryRun, jsonOutput bool) {
+	scorer, err := NewScorer(ai, useLLM)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	areas := ValidAreaNames
+	if area != "" {
+		areas = []string{area}
+	}
+	var items []FeedbackItem
+	for _, a := range areas {
+		areaItems, err := scanLocalDirectory(getVoiceDir(projectDir, a), a)
+		if err == nil {
+			items = append(items, areaItems...)
+		}
+	}
+
+	scored, warnings, err := ScoreItems(items, scorer, force, dryRun)
+	if jsonOutput {
+		data, _ := json.MarshalIndent(scored, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		for _, warning := range warnings {
+			fmt.Printf("Warning: %s\n", warning)
+		}
+		sortItemsByScore(scored, "urgency")
+		for _, item := range scored {
+			fmt.Printf("   %-10s urgency %.2f  sentiment %+.2f  %s\n", item.ID, item.Scores.Urgency, item.Scores.Sentiment, truncateStr(item.Title, 45))
+		}
+		action := "Scored"
+		if dryRun {
+			action = "[DRY-RUN] Would score"
+		}
+		fmt.Printf("\n%s %d of %d items with %s", action, len(scored), len(items), scorer.Name())
+		if skipped := len(items) - len(scored); skipped > 0 && err == nil {
+			fmt.Printf(" (%d already scored; --force rescores)", skipped)
+		}
+		fmt.Println()
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 	}
 }
 
-func handleCategoryRenameCommand(args []string, projectDir string) {
-	if len(args) == 0 {
-		fmt.Println("Error: category ID required")
-		fmt.Println("Usage: portunix pft category rename <id> --name <name> --area <area>")
+// approveClusters marks clusters approved from a comma separated list of
+// cluster IDs or "all"
+func approveClusters(analysis *ClusterAnalysis, ids string) {
+	selected := make(map[string]bool)
+	for _, id := range strings.Split(ids, ",") {
+		selected[strings.TrimSpace(id)] = true
+	}
+	for i := range analysis.Clusters {
+		if selected["all"] || selected[fmt.Sprint(analysis.Clusters[i].ID)] {
+			analysis.Clusters[i].Approved = true
+		}
+	}
+}
+
+func showAnalyzeHelp() {
+	fmt.Println("Usage: portunix pft analyze --cluster [options]")
+	fmt.Println("       portunix pft analyze --apply <analysis.json> [options]")
+	fmt.Println("       portunix pft analyze --score [options]")
+	fmt.Println()
+	fmt.Println("Group similar feedback items by embedding similarity, propose a category")
+	fmt.Println("for each group and write a thematic summary for QFD workshops. Results are")
+	fmt.Println("saved to .pft-analysis/ as JSON and a markdown report; nothing changes until")
+	fmt.Println("the reviewed proposals are applied.")
+	fmt.Println()
+	fmt.Println("--score rates the sentiment (-1..1) and urgency (0..1) of each verbatim and")
+	fmt.Println("stores them in the item frontmatter, for 'pft list --sort urgency' and the")
+	fmt.Println("'Needs attention' section of reports.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --cluster              Cluster the items of an area")
+	fmt.Println("  --score                Score sentiment and urgency of verbatims")
+	fmt.Println("  --llm                  Score with the configured language model instead of rules")
+	fmt.Println("  --force                Rescore items that already have scores")
+	fmt.Println("  --area <area>          Area to analyze (default: voc; --score: all areas)")
+	fmt.Println("  --provider <type>      Embedding provider: local, ollama, openai")
+	fmt.Println("                         (default: configured with --ai-provider, else local)")
+	fmt.Println("  --threshold <0-1>      Similarity needed to group items (default per provider)")
+	fmt.Println("  --json                 Print the analysis as JSON")
+	fmt.Println("  --apply <file>         Apply the approved clusters of a saved analysis")
+	fmt.Println("  --approve <ids|all>    Approve clusters while applying, e.g. 1,3")
+	fmt.Println("  --dry-run              Show what --apply or --score would change")
+	fmt.Println("  --path <path>          Project directory")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft analyze --cluster")
+	fmt.Println("  portunix pft analyze --cluster --area vos --provider ollama")
+	fmt.Println("  portunix pft analyze --score --llm")
+	fmt.Println("  portunix pft analyze --apply .pft-analysis/clusters-voc-20260101-100000.json --approve 1,2 --dry-run")
+}
+
+func showAssignHelp() {
+	fmt.Println("Usage: portunix pft assign <item-id> --category <category-id> [options]")
+	fmt.Println()
+	fmt.Println("Assign a category to a feedback item.")
+	fmt.Println("Items can have multiple categories (0..N).")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --category, -c <id>  Category ID to assign")
+	fmt.Println("  --set, -s            Replace all categories (instead of adding)")
+	fmt.Println("  --path <dir>         Path to PFT project directory")
+	fmt.Println("  --help, -h           Show this help")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft assign UC001 --category user-auth        # Add category")
+	fmt.Println("  portunix pft assign UC001 --category A --set          # Replace all with A")
+	fmt.Println("  portunix pft assign P01 -c A -s --path docs/project   # Replace with path")
+}
+
+func showUnassignHelp() {
+	fmt.Println("Usage: portunix pft unassign <item-id> --category <category-id> [options]")
+	fmt.Println("       portunix pft unassign <item-id> --all [options]")
+	fmt.Println()
+	fmt.Println("Remove category(s) from a feedback item.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --category, -c <id>  Category ID to remove")
+	fmt.Println("  --all                Remove all categories")
+	fmt.Println("  --path <dir>         Path to PFT project directory")
+	fmt.Println("  --help, -h           Show this help")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft unassign UC001 --category user-auth")
+	fmt.Println("  portunix pft unassign UC001 --all")
+	fmt.Println("  portunix pft unassign P01 --category A --path docs/pft-project")
+}
+
+func showEstimateHelp() {
+	fmt.Println("Usage: portunix pft estimate <item-id> [effort] [options]")
+	fmt.Println()
+	fmt.Println("Set the estimated and actual effort of a feedback item.")
+	fmt.Println("Efforts are working time: h (hours), d (8h days), w (5d weeks).")
+	fmt.Println("Without an effort the current values are shown.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --actual, -a <effort>  Record the effort actually spent")
+	fmt.Println("  --clear                Remove the estimate and actual effort")
+	fmt.Println("  --path <dir>           Path to PFT project directory")
+	fmt.Println("  --help, -h             Show this help")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  portunix pft estimate UC001 3d")
+	fmt.Println("  portunix pft estimate UC001 \"1w 2d\"")
+	fmt.Println("  portunix pft estimate UC001 --actual 4d")
+	fmt.Println("  portunix pft estimate UC001")
+}
+
+// handleShareCommand creates read-only views of single items for
+// stakeholders without access to the repository or the feedback tool
+func handleShareCommand(args []string) {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		showShareHelp()
+		return
+	}
+	switch args[0] {
+	case "serve":
+		handleShareServe(args[1:])
+		return
+	case "verify":
+		handleShareVerify(args[1:])
+		return
+	case "rotate-key":
+		handleShareRotateKey(args[1:])
 		return
 	}
 
-	categoryID := args[0]
-	var name, description, color, area string
+	var itemID, output, title, configPath string
+	var tokenOnly bool
+	expiry := DefaultShareExpiry
 
-	for i := 1; i < len(args); i++ {
+	for i := 0; i < len(args); i++ {
 		switch args[i] {
-		case "--name":
+		case "--expires":
 			if i+1 < len(args) {
-				name = args[i+1]
+				d, err := ParseShareExpiry(args[i+1])
+				if err != nil {
+					fmt.Printf("Error: %v\n", err)
+					return
+				}
+				expiry = d
 				i++
 			}
-		case "--description":
+		case "--output", "-o":
 			if i+1 < len(args) {
-				description = args[i+1]
+				output = args[i+1]
 				i++
 			}
-		case "--color":
+		case "--title":
 			if i+1 < len(args) {
-				color = args[i+1]
+				title = args[i+1]
 				i++
 			}
-		case "--area":
+		case "--token":
+			tokenOnly = true
+		case "--path":
 			if i+1 < len(args) {
-				area = args[i+1]
+				configPath = args[i+1]
 				i++
 			}
+		case "--help", "-h":
+			showShareHelp()
+			return
+		default:
+			if !strings.HasPrefix(args[i], "-") && itemID == "" {
+				itemID = args[i]
+			}
 		}
 	}
 
-	if area == "" {
-		fmt.Println("Error: --area is required")
+	if itemID == "" {
+		fmt.Println("Error: item ID is required")
+		showShareHelp()
 		return
 	}
 
-	registry, err := LoadCategoryRegistry(projectDir, area)
+	config, configFilePath, err := loadOrCreateConfig(configPath)
 	if err != nil {
-		fmt.Printf("Error loading categories: %v\n", err)
+		fmt.Printf("Error loading configuration: %v\n", err)
 		return
 	}
+	projectDir := ResolveProjectPath(config, configFilePath, configPath)
 
-	updates := Category{
-		Name:        name,
-		Description: description,
-		Color:       color,
+	item, _, err := findFeedbackItem(projectDir, itemID)
+	if err != nil {
+		fmt.Printf("Item '%s' not found: %v\n", itemID, err)
+		return
 	}
-
-	if err := registry.UpdateCategory(categoryID, updates); err != nil {
-		fmt.Printf("Error updating category: %v\n", err)
+	key, err := shareKey(config, configFilePath, true)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	if err := SaveCategoryRegistry(projectDir, area, registry); err != nil {
-		fmt.Printf("Error saving categories: %v\n", err)
+	now := time.Now()
+	expires := now.Add(expiry)
+	if tokenOnly {
+		token := SignShareToken(key, ShareClaims{ID: item.ID, Expires: expires.Unix()})
+		fmt.Printf("✓ Share link of %s, valid until %s\n", item.ID, expires.Format("2006-01-02 15:04"))
+		fmt.Printf("  %s\n", ShareURL(config, token))
+		if config.Share == nil || config.Share.URL == "" {
+			fmt.Println("\nServe it with 'portunix pft share serve'; set the public address with")
+			fmt.Println("'portunix pft configure --share-url <url>'.")
+		}
 		return
 	}
 
-	fmt.Printf("✓ Category '%s' updated in %s\n", NormalizeCategoryID(categoryID), area)
-}
-
-func handleCategoryShowCommand(args []string, projectDir string) {
-	if len(args) == 0 {
-		fmt.Println("Error: category ID required")
-		fmt.Println("Usage: portunix pft category show <id> --area <area>")
+	if title == "" {
+		title = config.Name
+	}
+	if title == "" {
+		title = "Product Feedback"
+	}
+	if output == "" {
+		output = sitePageName.ReplaceAllString(item.ID, "-") + "-share.html"
+	}
+	page, _, err := ShareExport(key, *item, title, expires, now)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if err := os.WriteFile(output, page, 0644); err != nil {
+		fmt.Printf("Error: failed to write %s: %v\n", output, err)
 		return
 	}
+	fmt.Printf("✓ Read-only export of %s written to %s\n", item.ID, output)
+	fmt.Printf("  Valid until %s\n", expires.Format("2006-01-02 15:04"))
+	fmt.Printf("  Check it with: portunix pft share verify %s\n", output)
+}
 
-	categoryID := args[0]
-	var area string
+func handleShareServe(args []string) {
+	listen := DefaultShareListen
+	var title, configPath string
 
-	for i := 1; i < len(args); i++ {
+	for i := 0; i < len(args); i++ {
 		switch args[i] {
-		case "--area":
+		case "--listen":
 			if i+1 < len(args) {
-				area = args[i+1]
+				listen = args[i+1]
+				i++
+			}
+		case "--title":
+			if i+1 < len(args) {
+				title = args[i+1]
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				configPath = args[i+1]
 				i++
 			}
+		case "--help", "-h":
+			showShareHelp()
+			return
 		}
 	}
 
-	if area == "" {
-		fmt.Println("Error: --area is required")
-		return
-	}
-
-	registry, err := LoadCategoryRegistry(projectDir, area)
+	config, configFilePath, err := loadOrCreateConfig(configPath)
 	if err != nil {
-		fmt.Printf("Error loading categories: %v\n", err)
+		fmt.Printf("Error loading configuration: %v\n", err)
 		return
 	}
-
-	cat, err := registry.GetCategory(categoryID)
+	key, err := shareKey(config, configFilePath, false)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return
 	}
-
-	count, _ := CountItemsInCategory(projectDir, area, categoryID)
-
-	fmt.Printf("Category: %s\n", cat.ID)
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Printf("  Name: %s\n", cat.Name)
-	if cat.Description != "" {
-		fmt.Printf("  Description: %s\n", cat.Description)
+	if title == "" {
+		title = config.Name
 	}
-	if cat.Color != "" {
-		fmt.Printf("  Color: %s\n", cat.Color)
+	if title == "" {
+		title = "Product Feedback"
 	}
-	fmt.Printf("  Order: %d\n", cat.Order)
-	fmt.Printf("  Items: %d\n", count)
-	fmt.Printf("  Created: %s\n", cat.CreatedAt)
-	fmt.Printf("  Updated: %s\n", cat.UpdatedAt)
-}
 
-// Assign/Unassign command handlers
-func handleAssignCommand(args []string) {
-	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
-		showAssignHelp()
-		return
+	server := &ShareServer{ProjectDir: ResolveProjectPath(config, configFilePath, configPath), Key: key, Title: title}
+	fmt.Printf("Serving share links on %s\n", listen)
+	fmt.Println("  GET /share/<token>")
+	if err := http.ListenAndServe(listen, server.Handler()); err != nil {
+		fmt.Printf("Error: %v\n", err)
 	}
+}
 
-	// Parse arguments - first non-flag argument is itemID
-	var itemID string
-	var categoryID string
-	var configPath string
-	var setMode bool
-
+func handleShareVerify(args []string) {
+	var target, configPath string
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
-		case "--category", "-c":
-			if i+1 < len(args) {
-				categoryID = args[i+1]
-				i++
-			}
 		case "--path":
 			if i+1 < len(args) {
 				configPath = args[i+1]
 				i++
 			}
-		case "--set", "-s":
-			setMode = true
 		case "--help", "-h":
-			showAssignHelp()
+			showShareHelp()
 			return
 		default:
-			if !strings.HasPrefix(args[i], "-") && itemID == "" {
-				itemID = args[i]
+			if !strings.HasPrefix(args[i], "-") && target == "" {
+				target = args[i]
 			}
 		}
 	}
-
-	if itemID == "" {
-		fmt.Println("Error: item ID is required")
-		showAssignHelp()
-		return
-	}
-
-	if categoryID == "" {
-		fmt.Println("Error: --category is required")
-		showAssignHelp()
+	if target == "" {
+		fmt.Println("Error: export file or token is required")
 		return
 	}
 
 	config, configFilePath, err := loadOrCreateConfig(configPath)
 	if err != nil {
-		fmt.Println("No configuration found. Run 'portunix pft configure' first.")
+		fmt.Printf("Error loading configuration: %v\n", err)
 		return
 	}
-
-	// Use cross-platform path resolution
-	projectDir := ResolveProjectPath(config, configFilePath, configPath)
-
-	// Find the item file
-	filePath, feedbackType, err := findFeedbackItemFile(projectDir, itemID)
+	key, err := shareKey(config, configFilePath, false)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	// Verify category exists in the area
-	registry, err := LoadCategoryRegistry(projectDir, feedbackType)
-	if err != nil {
-		fmt.Printf("Error loading categories: %v\n", err)
-		return
-	}
-
-	if !registry.HasCategory(categoryID) {
-		fmt.Printf("Error: category '%s' not found in %s\n", categoryID, feedbackType)
-		fmt.Println("Use 'portunix pft category list --area " + feedbackType + "' to see available categories")
-		return
-	}
-
-	// Set or add category to file
-	if setMode {
-		// Replace all categories with the new one
-		if err := SetCategoryToFile(filePath, categoryID); err != nil {
-			fmt.Printf("Error setting category: %v\n", err)
-			return
-		}
-		fmt.Printf("✓ Set category '%s' to %s (replaced all previous)\n", categoryID, itemID)
+	var claims *ShareClaims
+	if data, readErr := os.ReadFile(target); readErr == nil {
+		claims, err = VerifyShareExport(key, data, time.Now())
 	} else {
-		// Add category to existing ones
-		if err := AddCategoryToFile(filePath, categoryID); err != nil {
-			fmt.Printf("Error assigning category: %v\n", err)
-			return
-		}
-		fmt.Printf("✓ Assigned category '%s' to %s\n", categoryID, itemID)
+		claims, err = VerifyShareToken(key, target, time.Now())
 	}
-}
-
-func handleUnassignCommand(args []string) {
-	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
-		showUnassignHelp()
+	if err != nil {
+		if claims != nil {
+			fmt.Printf("✗ %s: %v (%s, expires %s)\n", target, err, claims.ID, claims.ExpiresAt().Format("2006-01-02 15:04"))
+		} else {
+			fmt.Printf("✗ %s: %v\n", target, err)
+		}
 		return
 	}
+	fmt.Printf("✓ Valid share of %s, expires %s\n", claims.ID, claims.ExpiresAt().Format("2006-01-02 15:04"))
+}
 
-	// Parse arguments - first non-flag argument is itemID
-	var itemID string
-	var categoryID string
+func handleShareRotateKey(args []string) {
 	var configPath string
-	var removeAll bool
-
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
-		case "--category", "-c":
-			if i+1 < len(args) {
-				categoryID = args[i+1]
-				i++
-			}
 		case "--path":
 			if i+1 < len(args) {
 				configPath = args[i+1]
 				i++
 			}
-		case "--all":
-			removeAll = true
 		case "--help", "-h":
-			showUnassignHelp()
+			showShareHelp()
 			return
-		default:
-			if !strings.HasPrefix(args[i], "-") && itemID == "" {
-				itemID = args[i]
-			}
 		}
 	}
 
-	if itemID == "" {
-		fmt.Println("Error: item ID is required")
-		showUnassignHelp()
-		return
-	}
-
-	if categoryID == "" && !removeAll {
-		fmt.Println("Error: --category or --all is required")
-		showUnassignHelp()
-		return
-	}
-
 	config, configFilePath, err := loadOrCreateConfig(configPath)
 	if err != nil {
-		fmt.Println("No configuration found. Run 'portunix pft configure' first.")
+		fmt.Printf("Error loading configuration: %v\n", err)
 		return
 	}
-
-	// Use cross-platform path resolution
-	projectDir := ResolveProjectPath(config, configFilePath, configPath)
-
-	// Find the item file
-	filePath, _, err := findFeedbackItemFile(projectDir, itemID)
-	if err != nil {
+	if _, err := rotateShareKey(config, configFilePath); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return
 	}
-
-	if removeAll {
-		if err := ClearCategoriesFromFile(filePath); err != nil {
-			fmt.Printf("Error removing categories: %v\n", err)
-			return
-		}
-		fmt.Printf("✓ Removed all categories from %s\n", itemID)
-	} else {
-		if err := RemoveCategoryFromFile(filePath, categoryID); err != nil {
-			fmt.Printf("Error removing category: %v\n", err)
-			return
-		}
-		fmt.Printf("✓ Removed category '%s' from %s\n", categoryID, itemID)
-	}
+	fmt.Println("✓ Share key replaced; all earlier share links and exports are now invalid")
 }
 
-func showAssignHelp() {
-	fmt.Println("Usage: portunix pft assign <item-id> --category <category-id> [options]")
-	fmt.Println()
-	fmt.Println("Assign a category to a feedback item.")
-	fmt.Println("Items can have multiple categories (0..N).")
+func showShareHelp() {
+	fmt.Println("Usage: portunix pft share <item-id> [options]")
+	fmt.Println("       portunix pft share serve [--listen :8086]")
+	fmt.Println("       portunix pft share verify <file|token>")
+	fmt.Println("       portunix pft share rotate-key")
 	fmt.Println()
-	fmt.Println("Options:")
-	fmt.Println("  --category, -c <id>  Category ID to assign")
-	fmt.Println("  --set, -s            Replace all categories (instead of adding)")
-	fmt.Println("  --path <dir>         Path to PFT project directory")
-	fmt.Println("  --help, -h           Show this help")
+	fmt.Println("Share a single feedback item read-only with stakeholders who have no access")
+	fmt.Println("to the repository or the feedback tool. Only the public fields are shown:")
+	fmt.Println("title, status, votes, categories, summary and description.")
 	fmt.Println()
-	fmt.Println("Examples:")
-	fmt.Println("  portunix pft assign UC001 --category user-auth        # Add category")
-	fmt.Println("  portunix pft assign UC001 --category A --set          # Replace all with A")
-	fmt.Println("  portunix pft assign P01 -c A -s --path docs/project   # Replace with path")
-}
-
-func showUnassignHelp() {
-	fmt.Println("Usage: portunix pft unassign <item-id> --category <category-id> [options]")
-	fmt.Println("       portunix pft unassign <item-id> --all [options]")
+	fmt.Println("By default a self-contained HTML page is written. Its signature covers the")
+	fmt.Println("page and the expiry; after the expiry the page hides the item. With --token")
+	fmt.Println("a link to 'pft share serve' is printed instead, which shows the current")
+	fmt.Println("status of the item until the link expires.")
 	fmt.Println()
-	fmt.Println("Remove category(s) from a feedback item.")
+	fmt.Println("Subcommands:")
+	fmt.Println("  serve                 Serve share links at /share/<token>")
+	fmt.Println("  verify <file|token>   Check the signature and expiry of an export or token")
+	fmt.Println("  rotate-key            Replace the signing key, revoking all shares")
 	fmt.Println()
 	fmt.Println("Options:")
-	fmt.Println("  --category, -c <id>  Category ID to remove")
-	fmt.Println("  --all                Remove all categories")
-	fmt.Println("  --path <dir>         Path to PFT project directory")
-	fmt.Println("  --help, -h           Show this help")
+	fmt.Println("  --expires <age>       Lifetime of the share, e.g. 12h, 7d, 2w (default 7d)")
+	fmt.Println("  --output, -o <file>   HTML file to write (default <id>-share.html)")
+	fmt.Println("  --token               Print a link to the share server instead of a file")
+	fmt.Println("  --title <name>        Page title (default: project name)")
+	fmt.Println("  --listen <addr>       Address of 'share serve' (default :8086)")
+	fmt.Println("  --path <dir>          Path to PFT project directory")
+	fmt.Println("  --help, -h            Show this help")
 	fmt.Println()
 	fmt.Println("Examples:")
-	fmt.Println("  portunix pft unassign UC001 --category user-auth")
-	fmt.Println("  portunix pft unassign UC001 --all")
-	fmt.Println("  portunix pft unassign P01 --category A --path docs/pft-project")
+	fmt.Println("  portunix pft share UC001 --expires 7d")
+	fmt.Println("  portunix pft share UC001 --token --expires 2w")
+	fmt.Println("  portunix pft share serve --listen :8086")
+	fmt.Println("  portunix pft share verify UC001-share.html")
 }
 
 // findFeedbackItemFile finds the file path for a feedback item by ID
@@ -5584,6 +8622,10 @@ func init() {
 }
 
 func main() {
+	// Logging options are inherited from the parent portunix process
+	logging.InitFromEnv("ptx-pft")
+	defer logging.Close()
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,261 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"portunix.ai/app/fileutil"
+	"portunix.ai/portunix/pkg/logging"
+)
+
+// MetricsStateFileName records sync runs and notification deliveries, which
+// 'pft metrics' exports next to the item counts
+const MetricsStateFileName = ".pft-metrics.json"
+
+// DefaultMetricsListen is the default address of 'pft metrics --listen'
+const DefaultMetricsListen = ":9301"
+
+// syncRecord tracks the sync runs of an area
+type syncRecord struct {
+	LastRun     time.Time `json:"last_run"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+	Failures    int       `json:"failures,omitempty"`
+}
+
+type metricsState struct {
+	Syncs                map[string]*syncRecord `json:"syncs"`                 // Area -> sync runs
+	Notifications        map[string]int         `json:"notifications"`         // Channel -> sent
+	NotificationFailures map[string]int         `json:"notification_failures"` // Channel -> failed
+}
+
+func loadMetricsState(projectDir string) (*metricsState, error) {
+	state := &metricsState{}
+	data, err := os.ReadFile(filepath.Join(projectDir, MetricsStateFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", MetricsStateFileName, err)
+		}
+	}
+	if state.Syncs == nil {
+		state.Syncs = map[string]*syncRecord{}
+	}
+	if state.Notifications == nil {
+		state.Notifications = map[string]int{}
+	}
+	if state.NotificationFailures == nil {
+		state.NotificationFailures = map[string]int{}
+	}
+	return state, nil
+}
+
+// updateMetricsState changes the metrics state under its lock, so concurrent
+// pft commands never lose a count
+func updateMetricsState(projectDir string, update func(*metricsState)) error {
+	path := filepath.Join(projectDir, MetricsStateFileName)
+	return fileutil.WithLock(path, func() error {
+		state, err := loadMetricsState(projectDir)
+		if err != nil {
+			return err
+		}
+		update(state)
+		data, err := json.MarshalIndent(state, "", "  ")
+		if err != nil {
+			return err
+		}
+		return fileutil.WriteAtomic(path, data, 0644)
+	})
+}
+
+// RecordSync records a sync run of an area; err is the first failure of the
+// run, nil for a successful run
+func RecordSync(projectDir, area string, err error) {
+	now := time.Now()
+	recordErr := updateMetricsState(projectDir, func(state *metricsState) {
+		record := state.Syncs[area]
+		if record == nil {
+			record = &syncRecord{}
+			state.Syncs[area] = record
+		}
+		record.LastRun = now
+		if err != nil {
+			record.Failures++
+		} else {
+			record.LastSuccess = now
+		}
+	})
+	if recordErr != nil {
+		logging.Warn("failed to record sync metrics", "voice", area, "error", recordErr)
+	}
+}
+
+// RecordNotification records a notification sent to a channel (email, slack
+// or teams); err is the delivery failure
+func RecordNotification(projectDir, channel string, err error) {
+	channel, _, _ = strings.Cut(channel, ":") // One series per kind, not per chat channel
+	recordErr := updateMetricsState(projectDir, func(state *metricsState) {
+		if err != nil {
+			state.NotificationFailures[channel]++
+		} else {
+			state.Notifications[channel]++
+		}
+	})
+	if recordErr != nil {
+		logging.Warn("failed to record notification metrics", "channel", channel, "error", recordErr)
+	}
+}
+
+// metricFamily is a metric in the Prometheus text exposition format
+type metricFamily struct {
+	name    string
+	help    string
+	kind    string // gauge or counter
+	samples []metricSample
+}
+
+type metricSample struct {
+	labels []string // Name, value pairs
+	value  float64
+}
+
+func (f *metricFamily) add(value float64, labels ...string) {
+	f.samples = append(f.samples, metricSample{labels: labels, value: value})
+}
+
+func (f *metricFamily) write(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+	for _, sample := range f.samples {
+		var labels []string
+		for i := 0; i+1 < len(sample.labels); i += 2 {
+			labels = append(labels, sample.labels[i]+`="`+escapeLabelValue(sample.labels[i+1])+`"`)
+		}
+		value := strconv.FormatFloat(sample.value, 'f', -1, 64)
+		if len(labels) > 0 {
+			fmt.Fprintf(w, "%s{%s} %s\n", f.name, strings.Join(labels, ","), value)
+		} else {
+			fmt.Fprintf(w, "%s %s\n", f.name, value)
+		}
+	}
+}
+
+// escapeLabelValue escapes a label value as the exposition format requires
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// WriteMetrics writes the feedback flow of a project as Prometheus metrics:
+// item counts by area and status, items not yet synced, sync runs and lag,
+// and notification deliveries
+func WriteMetrics(w io.Writer, config *Config, projectDir string, now time.Time) error {
+	state, err := loadMetricsState(projectDir)
+	if err != nil {
+		return err
+	}
+
+	items := &metricFamily{name: "pft_items", help: "Feedback items by area and status.", kind: "gauge"}
+	unsynced := &metricFamily{name: "pft_items_unsynced", help: "Items of synced areas without an external ID.", kind: "gauge"}
+	for _, area := range []string{"voc", "vos", "vob", "voe"} {
+		scanned, err := ScanFeedbackDirectory(getVoiceDir(projectDir, area), area)
+		if err != nil {
+			return err
+		}
+		counts := map[string]int{}
+		pending := 0
+		for _, item := range scanned {
+			counts[item.Status]++
+			if item.ExternalID == "" {
+				pending++
+			}
+		}
+		for _, status := range sortedCountKeys(counts) {
+			items.add(float64(counts[status]), "area", area, "status", status)
+		}
+		if config.GetAreaProvider(area) != "local" {
+			unsynced.add(float64(pending), "area", area)
+		}
+	}
+
+	lastRun := &metricFamily{name: "pft_sync_last_run_timestamp_seconds", help: "Time of the last sync run.", kind: "gauge"}
+	lastSuccess := &metricFamily{name: "pft_sync_last_success_timestamp_seconds", help: "Time of the last successful sync run.", kind: "gauge"}
+	lag := &metricFamily{name: "pft_sync_lag_seconds", help: "Seconds since the last successful sync run.", kind: "gauge"}
+	failures := &metricFamily{name: "pft_sync_failures_total", help: "Failed sync runs.", kind: "counter"}
+	areas := make([]string, 0, len(state.Syncs))
+	for area := range state.Syncs {
+		areas = append(areas, area)
+	}
+	sort.Strings(areas)
+	for _, area := range areas {
+		record := state.Syncs[area]
+		lastRun.add(float64(record.LastRun.Unix()), "area", area)
+		if !record.LastSuccess.IsZero() {
+			lastSuccess.add(float64(record.LastSuccess.Unix()), "area", area)
+			lag.add(now.Sub(record.LastSuccess).Seconds(), "area", area)
+		}
+		failures.add(float64(record.Failures), "area", area)
+	}
+
+	sent := &metricFamily{name: "pft_notifications_sent_total", help: "Notifications delivered by channel.", kind: "counter"}
+	for _, channel := range sortedCountKeys(state.Notifications) {
+		sent.add(float64(state.Notifications[channel]), "channel", channel)
+	}
+	failed := &metricFamily{name: "pft_notifications_failed_total", help: "Notifications that failed by channel.", kind: "counter"}
+	for _, channel := range sortedCountKeys(state.NotificationFailures) {
+		failed.add(float64(state.NotificationFailures[channel]), "channel", channel)
+	}
+
+	for _, family := range []*metricFamily{items, unsynced, lastRun, lastSuccess, lag, failures, sent, failed} {
+		family.write(w)
+	}
+	return nil
+}
+
+// MetricsHandler serves the metrics at /metrics, computed on every scrape
+func MetricsHandler(config *Config, projectDir string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		var buf strings.Builder
+		if err := WriteMetrics(&buf, config, projectDir, time.Now()); err != nil {
+			logging.Capture(zerolog.ErrorLevel, "metrics failed", "dir", projectDir, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		io.WriteString(w, buf.String())
+	})
+	return mux
+}
+
+// WriteMetricsTextfile writes the metrics to a file for the node_exporter
+// textfile collector, replacing it atomically so it is never read half written
+func WriteMetricsTextfile(path string, config *Config, projectDir string) error {
+	var buf strings.Builder
+	if err := WriteMetrics(&buf, config, projectDir, time.Now()); err != nil {
+		return err
+	}
+	return fileutil.WriteAtomic(path, []byte(buf.String()), 0644)
+}
+
+// sortedCountKeys returns the keys of counts in order
+func sortedCountKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
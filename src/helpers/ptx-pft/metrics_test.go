@@ -0,0 +1,92 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	projectDir := t.TempDir()
+	needs := filepath.Join(getVoiceDir(projectDir, "voc"), "needs")
+	os.MkdirAll(needs, 0755)
+	os.WriteFile(filepath.Join(needs, "VOC-001.md"), []byte("---\nid: VOC-001\ntitle: Dark mode\nstatus: open\nexternal_id: \"12\"\n---\n"), 0644)
+	os.WriteFile(filepath.Join(needs, "VOC-002.md"), []byte("---\nid: VOC-002\ntitle: Export\nstatus: open\n---\n"), 0644)
+	os.WriteFile(filepath.Join(needs, "VOC-003.md"), []byte("---\nid: VOC-003\ntitle: Search\nstatus: implemented\n---\n"), 0644)
+
+	RecordSync(projectDir, "voc", nil)
+	RecordSync(projectDir, "voc", errors.New("connection refused"))
+	RecordNotification(projectDir, "email", nil)
+	RecordNotification(projectDir, "slack:#product", nil)
+	RecordNotification(projectDir, "slack:#support", errors.New("channel_not_found"))
+
+	state, err := loadMetricsState(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastSuccess := state.Syncs["voc"].LastSuccess
+
+	config := NewDefaultConfig()
+	config.VoC = &AreaConfig{Provider: "fider", URL: "http://localhost:3100"}
+
+	var out strings.Builder
+	if err := WriteMetrics(&out, config, projectDir, lastSuccess.Add(90*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	metrics := out.String()
+	for _, want := range []string{
+		"# TYPE pft_items gauge\n",
+		`pft_items{area="voc",status="open"} 2` + "\n",
+		`pft_items{area="voc",status="implemented"} 1` + "\n",
+		`pft_items_unsynced{area="voc"} 2` + "\n",
+		`pft_sync_lag_seconds{area="voc"} 90` + "\n",
+		`pft_sync_failures_total{area="voc"} 1` + "\n",
+		"# TYPE pft_notifications_sent_total counter\n",
+		`pft_notifications_sent_total{channel="email"} 1` + "\n",
+		`pft_notifications_sent_total{channel="slack"} 1` + "\n",
+		`pft_notifications_failed_total{channel="slack"} 1` + "\n",
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("%q missing in\n%s", want, metrics)
+		}
+	}
+	if strings.Contains(metrics, `pft_items_unsynced{area="vos"}`) {
+		t.Error("local area reported as unsynced")
+	}
+	if strings.Contains(metrics, "e+") {
+		t.Errorf("timestamps in exponent notation:\n%s", metrics)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	server := httptest.NewServer(MetricsHandler(NewDefaultConfig(), t.TempDir()))
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("status %d, content type %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), "# HELP pft_items ") {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	if got := escapeLabelValue("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("got %s", got)
+	}
+}
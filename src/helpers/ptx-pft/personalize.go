@@ -0,0 +1,142 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ItemLink is an item listed in a personalized notification
+type ItemLink struct {
+	ID     string
+	Title  string
+	Status string
+	URL    string // Deep link to the item in the feedback tool, empty when not synced
+}
+
+// itemURL returns the deep link of a synced item in the feedback tool of its
+// area, empty for local items and tools without item pages
+func itemURL(config *Config, area, externalID string) string {
+	areaConfig := config.GetAreaConfig(area)
+	if externalID == "" || areaConfig == nil || areaConfig.URL == "" {
+		return ""
+	}
+	base := strings.TrimRight(areaConfig.URL, "/")
+	switch config.GetAreaProvider(area) {
+	case "fider":
+		return base + "/posts/" + externalID
+	case "discourse":
+		return base + "/t/" + externalID
+	}
+	return ""
+}
+
+// itemArea returns the area of an item file from its directory
+func itemArea(projectDir, path string) string {
+	for _, area := range []string{"voc", "vos", "vob", "voe"} {
+		if strings.HasPrefix(path, getVoiceDir(projectDir, area)+string(filepath.Separator)) {
+			return area
+		}
+	}
+	return ""
+}
+
+// openItem is an unresolved item with the fields that tie it to users
+type openItem struct {
+	link        ItemLink
+	authorEmail string
+	author      string
+	assignee    string
+}
+
+// loadOpenItems reads the unresolved items of all areas once, for the
+// recipients of a notification to pick their own from
+func loadOpenItems(config *Config, projectDir string) ([]openItem, error) {
+	var open []openItem
+	for _, area := range []string{"voc", "vos", "vob", "voe"} {
+		items, err := ScanFeedbackDirectory(getVoiceDir(projectDir, area), area)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if isResolvedStatus(item.Status) {
+				continue
+			}
+			content, err := os.ReadFile(item.FilePath)
+			if err != nil {
+				return nil, err
+			}
+			fm, _ := ParseFrontmatter(string(content))
+			if fm == nil {
+				continue
+			}
+			open = append(open, openItem{
+				link:        ItemLink{ID: item.ID, Title: item.Title, Status: item.Status, URL: itemURL(config, area, item.ExternalID)},
+				authorEmail: fm.Get("author_email"),
+				author:      fm.Get("author"),
+				assignee:    item.Assignee,
+			})
+		}
+	}
+	sort.Slice(open, func(i, j int) bool { return open[i].link.ID < open[j].link.ID })
+	return open, nil
+}
+
+// ownedItems returns the open items a user authored or is assigned to. The
+// author name counts only for items without an author email.
+func ownedItems(open []openItem, email string, user *User) []ItemLink {
+	var links []ItemLink
+	for _, item := range open {
+		owned := strings.EqualFold(item.authorEmail, email)
+		if user != nil {
+			owned = owned || (item.assignee != "" && strings.EqualFold(item.assignee, user.ID)) ||
+				(item.authorEmail == "" && user.Name != "" && strings.EqualFold(item.author, user.Name))
+		}
+		if owned {
+			links = append(links, item.link)
+		}
+	}
+	return links
+}
+
+// roleName returns the display name of a role of an area
+func roleName(projectDir, area, roleID string) string {
+	if roles, err := LoadRoles(projectDir, area); err == nil {
+		for _, role := range roles.Roles {
+			if role.ID == roleID {
+				return role.Name
+			}
+		}
+	}
+	return roleID
+}
+
+// personalizeEmail fills the mail merge fields of a recipient: name,
+// organization, role in the area of the item and the recipient's own open
+// items, leaving the notified item out of the list
+func personalizeEmail(data EmailData, projectDir, area, email string, user *User, open []openItem) EmailData {
+	data.UserName = email
+	data.Organization, data.Role = "", ""
+	if user != nil {
+		if user.Name != "" {
+			data.UserName = user.Name
+		}
+		data.Organization = user.Organization
+		if roleID := user.GetRoleForArea(area); roleID != "" {
+			data.Role = roleName(projectDir, area, roleID)
+		}
+	}
+
+	data.OpenItems = nil
+	for _, link := range ownedItems(open, email, user) {
+		if link.ID != data.ItemID {
+			data.OpenItems = append(data.OpenItems, link)
+		}
+	}
+	return data
+}
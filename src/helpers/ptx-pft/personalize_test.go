@@ -0,0 +1,99 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPersonalizeEmail(t *testing.T) {
+	projectDir := t.TempDir()
+	needs := filepath.Join(projectDir, "VoC", "needs")
+	os.MkdirAll(needs, 0755)
+	items := map[string]string{
+		"UC001-dark-mode.md": "---\nid: UC001\ntitle: Dark mode\nstatus: open\nauthor_email: alice@example.com\nexternal_id: \"12\"\n---\n",
+		"UC002-export.md":    "---\nid: UC002\ntitle: Export\nstatus: planned\nauthor: Alice Novak\n---\n",
+		"UC003-search.md":    "---\nid: UC003\ntitle: Search\nstatus: done\nauthor_email: alice@example.com\n---\n",
+		"UC004-login.md":     "---\nid: UC004\ntitle: Login\nstatus: open\nauthor_email: bob@example.com\nassignee: alice@example.com\n---\n",
+		"UC005-theme.md":     "---\nid: UC005\ntitle: Theme\nstatus: open\nauthor_email: bob@example.com\n---\n",
+	}
+	for name, content := range items {
+		os.WriteFile(filepath.Join(needs, name), []byte(content), 0644)
+	}
+
+	config := &Config{VoC: &AreaConfig{Provider: "fider", URL: "http://fider.local/"}}
+	open, err := loadOpenItems(config, projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &User{
+		ID:           "alice@example.com",
+		Name:         "Alice Novak",
+		Organization: "Acme",
+		Roles:        UserRoles{VoC: &RoleAssignment{Role: "customer-admin"}},
+	}
+	data := personalizeEmail(EmailData{ItemID: "UC004", Provider: "email"}, projectDir, "voc", user.ID, user, open)
+
+	if data.UserName != "Alice Novak" || data.Organization != "Acme" || data.Role != "Customer Admin" {
+		t.Errorf("personalized %+v", data)
+	}
+	var ids []string
+	for _, link := range data.OpenItems {
+		ids = append(ids, link.ID)
+	}
+	// Resolved UC003, notified UC004 and foreign UC005 are left out
+	if strings.Join(ids, ",") != "UC001,UC002" {
+		t.Errorf("open items %v", ids)
+	}
+	if data.OpenItems[0].URL != "http://fider.local/posts/12" || data.OpenItems[1].URL != "" {
+		t.Errorf("links %+v", data.OpenItems)
+	}
+
+	// An address not in the registry gets only the items it authored
+	data = personalizeEmail(EmailData{ItemID: "UC001"}, projectDir, "voc", "bob@example.com", nil, open)
+	if data.UserName != "bob@example.com" || data.Role != "" || len(data.OpenItems) != 2 {
+		t.Errorf("unregistered recipient %+v", data)
+	}
+}
+
+func TestPersonalizedTemplates(t *testing.T) {
+	data := EmailData{
+		ProductName:  "Portunix",
+		Title:        "Dark mode",
+		ItemID:       "UC001",
+		Provider:     "email",
+		UserName:     "Alice Novak",
+		Organization: "Acme",
+		Role:         "Customer Admin",
+		ItemURL:      "http://fider.local/posts/12",
+		OpenItems:    []ItemLink{{ID: "UC002", Title: "Export", Status: "planned", URL: "http://fider.local/posts/14"}},
+	}
+	for _, language := range []string{"en", "cs", "de"} {
+		for _, notifyType := range []NotificationType{NotifyVote, NotifyDescription, NotifyAcceptance} {
+			data.Language = language
+			_, body, err := GenerateNotification(notifyType, data)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", language, notifyType, err)
+			}
+			for _, want := range []string{"http://fider.local/posts/12", "UC002 Export (planned) http://fider.local/posts/14", "Customer Admin", "Acme"} {
+				if !strings.Contains(body, want) {
+					t.Errorf("%s/%s: %q missing in\n%s", language, notifyType, want, body)
+				}
+			}
+		}
+	}
+
+	// Without personalization fields the templates render as before
+	_, body, err := GenerateNotification(NotifyVote, EmailData{ProductName: "Portunix", Title: "Dark mode", ItemID: "UC001", Provider: "email"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(body, "open items") || strings.Contains(body, "You receive") {
+		t.Errorf("empty fields rendered:\n%s", body)
+	}
+}
@@ -7,21 +7,29 @@ package main
 // FeedbackItem represents a single feedback entry that can be synchronized
 // between local documents and external feedback systems
 type FeedbackItem struct {
-	ID          string            `json:"id"`
-	ExternalID  string            `json:"external_id,omitempty"`
-	Title       string            `json:"title"`
-	Summary     string            `json:"summary,omitempty"`
-	Description string            `json:"description"`
-	Status      string            `json:"status"`
-	Priority    string            `json:"priority,omitempty"`
-	Type        string            `json:"type,omitempty"` // "voc" or "vos"
-	FilePath    string            `json:"file_path,omitempty"`
-	Tags        []string          `json:"tags,omitempty"`
-	Categories  []string          `json:"categories,omitempty"` // 0..N category IDs
-	Votes       int               `json:"votes,omitempty"`
-	CreatedAt   string            `json:"created_at,omitempty"`
-	UpdatedAt   string            `json:"updated_at,omitempty"`
-	Metadata    map[string]string `json:"metadata,omitempty"`
+	ID           string            `json:"id"`
+	ExternalID   string            `json:"external_id,omitempty"`
+	Title        string            `json:"title"`
+	Summary      string            `json:"summary,omitempty"`
+	Description  string            `json:"description"`
+	Status       string            `json:"status"`
+	Priority     string            `json:"priority,omitempty"`
+	Type         string            `json:"type,omitempty"` // "voc" or "vos"
+	FilePath     string            `json:"file_path,omitempty"`
+	Tags         []string          `json:"tags,omitempty"`
+	Categories   []string          `json:"categories,omitempty"` // 0..N category IDs
+	Products     []string          `json:"products,omitempty"`
+	LinkedIssue  string            `json:"linked_issue,omitempty"`  // Local issue from 'pft link'
+	Assignee     string            `json:"assignee,omitempty"`      // User ID from the user registry
+	Estimate     string            `json:"estimate,omitempty"`      // Estimated effort, e.g. "3d"
+	ActualEffort string            `json:"actual_effort,omitempty"` // Effort spent so far
+	Votes        int               `json:"votes,omitempty"`
+	CreatedAt    string            `json:"created_at,omitempty"`
+	UpdatedAt    string            `json:"updated_at,omitempty"`
+	TriagedAt    string            `json:"triaged_at,omitempty"`  // When the status left open
+	ResolvedAt   string            `json:"resolved_at,omitempty"` // When the item was implemented or declined
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Scores       *ItemScores       `json:"scores,omitempty"` // Sentiment and urgency from 'pft analyze --score'
 }
 
 // ProviderConfig holds configuration for connecting to a feedback provider
@@ -60,6 +68,14 @@ type FeedbackProvider interface {
 	Close() error
 }
 
+// UserAccountDeleter is implemented by providers that can delete the account
+// of a user in the external system (GDPR erasure, 'pft user purge')
+type UserAccountDeleter interface {
+	// DeleteUserAccount deletes the account with the given email; it returns
+	// false when the system has no such account
+	DeleteUserAccount(email string) (bool, error)
+}
+
 // ProviderRegistry manages available feedback providers
 type ProviderRegistry struct {
 	providers map[string]func() FeedbackProvider
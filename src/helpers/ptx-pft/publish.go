@@ -0,0 +1,256 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"portunix.ai/portunix/src/helpers/ptx-pft/assets/templates"
+)
+
+// roadmapColumns are the columns of the published roadmap board, in order.
+// Items in the "closed" column (declined, duplicate) get a page and appear
+// in the item list, but not on the board.
+var roadmapColumns = []struct{ Key, Label string }{
+	{"under-review", "Under Review"},
+	{"planned", "Planned"},
+	{"in-progress", "In Progress"},
+	{"done", "Done"},
+}
+
+// roadmapColumn maps an item status (Fider, ClearFlask or hand-written) to
+// a roadmap column key
+func roadmapColumn(status string) string {
+	switch strings.ToLower(strings.ReplaceAll(strings.TrimSpace(status), " ", "-")) {
+	case "planned", "accepted", "approved":
+		return "planned"
+	case "started", "in-progress", "in_progress", "inprogress", "active", "implementing":
+		return "in-progress"
+	case "completed", "done", "released", "implemented", "resolved":
+		return "done"
+	case "declined", "rejected", "duplicate", "closed", "wontfix":
+		return "closed"
+	}
+	return "under-review"
+}
+
+// SiteItem is the public view of a feedback item. Only these fields are
+// published; authors, scores and links to internal issues stay private.
+type SiteItem struct {
+	ID         string        `json:"id"`
+	Area       string        `json:"area"`
+	Title      string        `json:"title"`
+	Status     string        `json:"status"`
+	Column     string        `json:"roadmap"`
+	Votes      int           `json:"votes"`
+	Categories []string      `json:"categories,omitempty"`
+	Summary    string        `json:"summary,omitempty"`
+	Updated    string        `json:"updated,omitempty"`
+	Page       string        `json:"page"`
+	Body       template.HTML `json:"-"`
+}
+
+type siteColumn struct {
+	Key, Label string
+	Items      []SiteItem
+}
+
+type sitePage struct {
+	Title     string
+	PageTitle string
+	Root      string
+	Generated string
+	Columns   []siteColumn
+	Items     []SiteItem
+	Item      SiteItem
+}
+
+var sitePageName = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+func newSiteItem(item FeedbackItem) SiteItem {
+	status := item.Status
+	if status == "" {
+		status = "Open"
+	}
+	updated := item.UpdatedAt
+	if updated == "" {
+		updated = item.CreatedAt
+	}
+	if len(updated) > 10 {
+		updated = updated[:10]
+	}
+	body := strings.TrimSpace(strings.TrimPrefix(item.Description, item.Summary))
+	return SiteItem{
+		ID:         item.ID,
+		Area:       item.Type,
+		Title:      item.Title,
+		Status:     status,
+		Column:     roadmapColumn(status),
+		Votes:      item.Votes,
+		Categories: item.Categories,
+		Summary:    item.Summary,
+		Updated:    updated,
+		Page:       sitePageName.ReplaceAllString(item.ID, "-") + ".html",
+		Body:       renderSiteMarkdown(body),
+	}
+}
+
+// renderSiteMarkdown converts the subset of markdown used in feedback items
+// (paragraphs, bullet lists, headings, quotes) to HTML. All text is escaped.
+func renderSiteMarkdown(text string) template.HTML {
+	var sb strings.Builder
+	var paragraph []string
+	inList := false
+	flush := func() {
+		if len(paragraph) > 0 {
+			sb.WriteString("<p>" + strings.Join(paragraph, "<br>\n") + "</p>\n")
+			paragraph = nil
+		}
+		if inList {
+			sb.WriteString("</ul>\n")
+			inList = false
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "#"):
+			flush()
+			sb.WriteString("<h3>" + html.EscapeString(strings.TrimLeft(line, "# ")) + "</h3>\n")
+		case strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* "):
+			if len(paragraph) > 0 {
+				flush()
+			}
+			if !inList {
+				sb.WriteString("<ul>\n")
+				inList = true
+			}
+			sb.WriteString("<li>" + html.EscapeString(line[2:]) + "</li>\n")
+		case strings.HasPrefix(line, ">"):
+			flush()
+			sb.WriteString("<blockquote>" + html.EscapeString(strings.TrimSpace(strings.TrimPrefix(line, ">"))) + "</blockquote>\n")
+		default:
+			if inList {
+				flush()
+			}
+			paragraph = append(paragraph, html.EscapeString(line))
+		}
+	}
+	flush()
+	return template.HTML(sb.String())
+}
+
+// PublishSite writes a static, read-only feedback portal to outputDir: a
+// roadmap board with an item list (index.html), one page per item
+// (items/<id>.html), style.css and feedback.json. All links are relative,
+// so the site works from any sub-path (GitHub Pages, Caddy file_server).
+func PublishSite(outputDir, title string, items []FeedbackItem) ([]SiteItem, error) {
+	tmpl, err := template.ParseFS(templates.SiteTemplates, "site/*.html.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse site templates: %w", err)
+	}
+
+	siteItems := make([]SiteItem, 0, len(items))
+	for _, item := range items {
+		siteItems = append(siteItems, newSiteItem(item))
+	}
+	sort.SliceStable(siteItems, func(i, j int) bool {
+		if siteItems[i].Votes != siteItems[j].Votes {
+			return siteItems[i].Votes > siteItems[j].Votes
+		}
+		return siteItems[i].ID < siteItems[j].ID
+	})
+
+	var columns []siteColumn
+	for _, c := range roadmapColumns {
+		column := siteColumn{Key: c.Key, Label: c.Label}
+		for _, item := range siteItems {
+			if item.Column == c.Key {
+				column.Items = append(column.Items, item)
+			}
+		}
+		columns = append(columns, column)
+	}
+
+	itemsDir := filepath.Join(outputDir, "items")
+	if err := os.MkdirAll(itemsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	page := sitePage{
+		Title:     title,
+		PageTitle: title + " - Feedback",
+		Generated: time.Now().Format("2006-01-02 15:04"),
+		Columns:   columns,
+		Items:     siteItems,
+	}
+	if err := writeSitePage(tmpl, "index.html.tmpl", filepath.Join(outputDir, "index.html"), page); err != nil {
+		return nil, err
+	}
+
+	written := map[string]bool{}
+	for _, item := range siteItems {
+		itemPage := page
+		itemPage.Root = "../"
+		itemPage.PageTitle = item.Title + " - " + title
+		itemPage.Item = item
+		if err := writeSitePage(tmpl, "item.html.tmpl", filepath.Join(itemsDir, item.Page), itemPage); err != nil {
+			return nil, err
+		}
+		written[item.Page] = true
+	}
+	// Remove pages of items that no longer exist
+	if entries, err := os.ReadDir(itemsDir); err == nil {
+		for _, entry := range entries {
+			if strings.HasSuffix(entry.Name(), ".html") && !written[entry.Name()] {
+				os.Remove(filepath.Join(itemsDir, entry.Name()))
+			}
+		}
+	}
+
+	css, err := templates.SiteTemplates.ReadFile("site/style.css")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stylesheet: %w", err)
+	}
+	data, err := json.MarshalIndent(siteItems, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feedback.json: %w", err)
+	}
+	files := map[string][]byte{
+		"style.css":     css,
+		"feedback.json": data,
+		// GitHub Pages must not run Jekyll on the export
+		".nojekyll": nil,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(outputDir, name), content, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return siteItems, nil
+}
+
+func writeSitePage(tmpl *template.Template, name, path string, data sitePage) error {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
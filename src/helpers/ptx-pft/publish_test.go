@@ -0,0 +1,67 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPublishSite(t *testing.T) {
+	vocDir := t.TempDir()
+	os.WriteFile(filepath.Join(vocDir, "UC001.md"), []byte("---\nid: UC001\nstatus: planned\nvotes: 12\ncategories:\n  - EXPORT\n---\n\n# PDF export\n\n## Summary\nExport reports to PDF\n\n## Description\nWe print <monthly> reports.\n\n- one\n- two\n"), 0644)
+	os.WriteFile(filepath.Join(vocDir, "UC002.md"), []byte("# Dark mode\n\n## Status\nstarted\n\n## Metadata\n- Fider ID: 7\n- Author: Jane Customer\n- Votes: 3\n"), 0644)
+	os.WriteFile(filepath.Join(vocDir, "UC003.md"), []byte("# Fax support\n\n## Status\ndeclined\n"), 0644)
+
+	items, err := scanLocalDirectory(vocDir, "voc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputDir := t.TempDir()
+	os.MkdirAll(filepath.Join(outputDir, "items"), 0755)
+	os.WriteFile(filepath.Join(outputDir, "items", "UC999.html"), []byte("stale"), 0644)
+
+	published, err := PublishSite(outputDir, "Acme", items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(published) != 3 || published[0].ID != "UC001" || published[0].Votes != 12 || published[1].Votes != 3 {
+		t.Fatalf("items should be ordered by votes: %+v", published)
+	}
+	if published[1].Column != "in-progress" || published[2].Column != "closed" {
+		t.Errorf("unexpected roadmap columns %+v", published)
+	}
+
+	index, err := os.ReadFile(filepath.Join(outputDir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	board := string(index)[:strings.Index(string(index), "All feedback")]
+	if !strings.Contains(board, `href="items/UC001.html"`) || strings.Contains(board, "Fax support") {
+		t.Errorf("board should link planned items and hide declined ones:\n%s", board)
+	}
+	page, err := os.ReadFile(filepath.Join(outputDir, "items", "UC001.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`href="../style.css"`, "12 votes", "&lt;monthly&gt;", "<li>two</li>"} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("item page is missing %q:\n%s", want, page)
+		}
+	}
+	if other, _ := os.ReadFile(filepath.Join(outputDir, "items", "UC002.html")); strings.Contains(string(other), "Jane") {
+		t.Error("authors must not be published")
+	}
+	for _, name := range []string{"style.css", "feedback.json", ".nojekyll"} {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Errorf("%s not written: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "items", "UC999.html")); !os.IsNotExist(err) {
+		t.Error("pages of removed items should be deleted")
+	}
+}
@@ -0,0 +1,241 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// 'pft user purge' handles erasure requests (GDPR Art. 17): the user leaves
+// the registry, the items keep their content but lose the author, and the
+// feedback tool accounts are deleted where the provider API allows it.
+// Notifications are sent when they are triggered, pft keeps no queue of them.
+
+// anonymousAuthor replaces the author of anonymized items
+const anonymousAuthor = "Anonymous"
+
+// redactedEmail replaces the email address of a purged user in item text
+const redactedEmail = "[redacted]"
+
+// PurgeOptions controls what 'pft user purge' erases
+type PurgeOptions struct {
+	AnonymizeItems bool // Anonymize the items authored by the user
+	DeleteAccounts bool // Delete the accounts in the feedback tools of the areas
+	DryRun         bool
+}
+
+// PurgeResult reports what a purge erased
+type PurgeResult struct {
+	UserRemoved    bool     `json:"user_removed"`
+	Items          []string `json:"items,omitempty"` // Items referencing the user, anonymized unless dry-run
+	IntakeRecords  int      `json:"intake_records,omitempty"`
+	Accounts       []string `json:"accounts,omitempty"`        // Provider accounts deleted
+	AccountsManual []string `json:"accounts_manual,omitempty"` // Providers needing deletion in their UI
+}
+
+// PurgeUser erases a user: with AnonymizeItems the author fields and email
+// address in items and the intake records of their threads, with
+// DeleteAccounts the accounts in the feedback tools, and then the registry
+// entry
+func PurgeUser(config *Config, projectDir, email string, opts PurgeOptions) (*PurgeResult, error) {
+	result := &PurgeResult{}
+
+	registry, err := LoadUserRegistry(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	identities := []string{email}
+	userID := email
+	user := registry.FindUserByEmail(email)
+	if user != nil {
+		userID = user.ID
+		if user.Name != "" {
+			identities = append(identities, user.Name)
+		}
+	}
+
+	// Items authored by or assigned to the user
+	anonymized := map[string]bool{}
+	for _, area := range []string{"voc", "vos", "vob", "voe"} {
+		items, err := ScanFeedbackDirectory(getVoiceDir(projectDir, area), area)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			content, err := os.ReadFile(item.FilePath)
+			if err != nil {
+				return nil, err
+			}
+			updated, changed := anonymizeItemContent(string(content), email, userID, identities)
+			if !changed {
+				continue
+			}
+			result.Items = append(result.Items, item.ID)
+			if !opts.AnonymizeItems || opts.DryRun {
+				continue
+			}
+			if err := os.WriteFile(item.FilePath, []byte(updated), 0644); err != nil {
+				return nil, fmt.Errorf("failed to anonymize %s: %w", item.ID, err)
+			}
+			anonymized[item.ID] = true
+		}
+	}
+
+	// Replies of the user must not reach anonymized items any more
+	if opts.AnonymizeItems {
+		state, err := loadIntakeState(projectDir)
+		if err != nil {
+			return nil, err
+		}
+		for messageID, itemID := range state.Messages {
+			if anonymized[itemID] || (opts.DryRun && containsString(result.Items, itemID)) {
+				delete(state.Messages, messageID)
+				result.IntakeRecords++
+			}
+		}
+		if result.IntakeRecords > 0 && !opts.DryRun {
+			if err := state.save(projectDir); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if opts.DeleteAccounts {
+		deleteProviderAccounts(config, email, opts.DryRun, result)
+	}
+
+	// The registry entry goes last, so a purge that fails on the way can be
+	// run again and still finds the name the items record the user by
+	if user != nil {
+		if !opts.DryRun {
+			if err := registry.RemoveUser(user.ID); err != nil {
+				return nil, err
+			}
+			if err := SaveUserRegistry(projectDir, registry); err != nil {
+				return nil, err
+			}
+		}
+		result.UserRemoved = true
+	}
+
+	return result, nil
+}
+
+// deleteProviderAccounts deletes the accounts of the user in the feedback
+// tools of the configured areas, each tool once
+func deleteProviderAccounts(config *Config, email string, dryRun bool, result *PurgeResult) {
+	seen := map[string]bool{}
+	for _, area := range []string{"voc", "vos", "vob", "voe"} {
+		name := config.GetAreaProvider(area)
+		providerConfig := config.GetAreaProviderConfig(area)
+		key := name + " " + providerConfig.Endpoint
+		if name == "local" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		label := fmt.Sprintf("%s (%s)", name, providerConfig.Endpoint)
+
+		provider, ok := GetProvider(name)
+		if !ok {
+			result.AccountsManual = append(result.AccountsManual, label)
+			continue
+		}
+		deleter, ok := provider.(UserAccountDeleter)
+		if !ok {
+			result.AccountsManual = append(result.AccountsManual, label)
+			continue
+		}
+		if dryRun {
+			result.Accounts = append(result.Accounts, label)
+			continue
+		}
+		if err := provider.Connect(providerConfig); err != nil {
+			result.AccountsManual = append(result.AccountsManual, fmt.Sprintf("%s: %v", label, err))
+			continue
+		}
+		deleted, err := deleter.DeleteUserAccount(email)
+		provider.Close()
+		switch {
+		case err != nil:
+			result.AccountsManual = append(result.AccountsManual, fmt.Sprintf("%s: %v", label, err))
+		case deleted:
+			result.Accounts = append(result.Accounts, label)
+		}
+	}
+}
+
+// anonymizeItemContent removes a user from an item file: the author and
+// author_email fields, the assignee, "- Author:" metadata lines, follow-up
+// headings and the email address anywhere in the text. identities are the
+// email and name the author may be recorded by. It reports whether the item
+// referenced the user.
+func anonymizeItemContent(content, email, userID string, identities []string) (string, bool) {
+	isUser := func(value string) bool {
+		value = strings.TrimSpace(value)
+		for _, identity := range identities {
+			if strings.EqualFold(value, identity) {
+				return true
+			}
+		}
+		return false
+	}
+	emailPattern := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(email))
+	changed := false
+
+	fm, body := ParseFrontmatter(content)
+	if fm != nil {
+		if isUser(fm.Get("author")) {
+			fm.Set("author", anonymousAuthor)
+			changed = true
+		}
+		if fm.Has("author_email") && isUser(fm.Get("author_email")) {
+			fm.Delete("author_email")
+			changed = true
+		}
+		if fm.Get("assignee") != "" && strings.EqualFold(fm.Get("assignee"), userID) {
+			fm.Delete("assignee")
+			changed = true
+		}
+		if fm.ReplaceValues(emailPattern, redactedEmail) {
+			changed = true
+		}
+	}
+
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if author, ok := strings.CutPrefix(line, "- Author:"); ok && isUser(author) {
+			lines[i] = "- Author: " + anonymousAuthor
+			changed = true
+		} else if strings.HasPrefix(line, "## Follow-up ") && strings.HasSuffix(line, ")") {
+			if open := strings.LastIndex(line, " ("); open != -1 && isUser(line[open+2:len(line)-1]) {
+				lines[i] = line[:open] + " (" + anonymousAuthor + ")"
+				changed = true
+			}
+		}
+		if emailPattern.MatchString(lines[i]) {
+			lines[i] = emailPattern.ReplaceAllString(lines[i], redactedEmail)
+			changed = true
+		}
+	}
+	body = strings.Join(lines, "\n")
+
+	if fm == nil {
+		return body, changed
+	}
+	return fm.String() + body, changed
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const purgeItem = `---
+id: VOC-001
+title: Export to PDF fails
+status: pending
+author: Alice Novak
+author_email: alice@example.com
+source: email
+---
+
+# Export to PDF fails
+
+- Author: Alice Novak
+
+Export fails for large reports. Contact me at Alice@Example.com.
+
+## Follow-up 2026-10-12 (Alice Novak)
+
+> Still broken.
+`
+
+func TestAnonymizeItemContent(t *testing.T) {
+	content, changed := anonymizeItemContent(purgeItem, "alice@example.com", "alice@example.com", []string{"alice@example.com", "Alice Novak"})
+	if !changed {
+		t.Fatal("item of the user not changed")
+	}
+	for _, leak := range []string{"Alice", "alice", "author_email"} {
+		if strings.Contains(content, leak) {
+			t.Errorf("%q left in\n%s", leak, content)
+		}
+	}
+	for _, want := range []string{"author: Anonymous", "- Author: Anonymous", "## Follow-up 2026-10-12 (Anonymous)", "Contact me at [redacted].", "source: email"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("%q missing in\n%s", want, content)
+		}
+	}
+
+	// Email addresses inside list and nested values
+	listItem := strings.Replace(purgeItem, "source: email\n", "source: email\nreporters:\n  - carol@example.com\n  - alice@example.com\nnotify:\n  cc: [Alice@example.com]\n", 1)
+	content, changed = anonymizeItemContent(listItem, "alice@example.com", "alice@example.com", []string{"alice@example.com", "Alice Novak"})
+	if !changed || strings.Contains(strings.ToLower(content), "alice@") {
+		t.Errorf("email left in list values:\n%s", content)
+	}
+	fm, _ := ParseFrontmatter(content)
+	if got := strings.Join(fm.List("reporters"), ","); got != "carol@example.com,"+redactedEmail {
+		t.Errorf("reporters after anonymizing: %s", got)
+	}
+
+	if _, changed := anonymizeItemContent(purgeItem, "bob@example.com", "bob@example.com", []string{"bob@example.com", "Bob"}); changed {
+		t.Error("item of another user changed")
+	}
+}
+
+func TestPurgeUser(t *testing.T) {
+	projectDir := t.TempDir()
+	needs := filepath.Join(getVoiceDir(projectDir, "voc"), "needs")
+	os.MkdirAll(needs, 0755)
+	itemPath := filepath.Join(needs, "VOC-001-export.md")
+	os.WriteFile(itemPath, []byte(purgeItem), 0644)
+	other := strings.NewReplacer("VOC-001", "VOC-002", "Alice Novak", "Bob", "alice@example.com", "bob@example.com", "Alice@Example.com", "bob@example.com").Replace(purgeItem)
+	os.WriteFile(filepath.Join(needs, "VOC-002-other.md"), []byte(other), 0644)
+
+	registry := &UserRegistry{}
+	registry.AddUser(User{ID: "alice@example.com", Name: "Alice Novak"})
+	registry.AddUser(User{ID: "bob@example.com", Name: "Bob"})
+	SaveUserRegistry(projectDir, registry)
+	state := &intakeState{Messages: map[string]string{"<m1@example.com>": "VOC-001", "<m2@example.com>": "VOC-002"}}
+	state.save(projectDir)
+
+	config := NewDefaultConfig()
+	opts := PurgeOptions{AnonymizeItems: true, DryRun: true}
+	result, err := PurgeUser(config, projectDir, "alice@example.com", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.UserRemoved || strings.Join(result.Items, ",") != "VOC-001" || result.IntakeRecords != 1 {
+		t.Errorf("dry run: %+v", result)
+	}
+	if content, _ := os.ReadFile(itemPath); string(content) != purgeItem {
+		t.Error("dry run changed the item")
+	}
+
+	opts.DryRun = false
+	if _, err := PurgeUser(config, projectDir, "alice@example.com", opts); err != nil {
+		t.Fatal(err)
+	}
+	registry, _ = LoadUserRegistry(projectDir)
+	if registry.FindUser("alice@example.com") != nil || registry.FindUser("bob@example.com") == nil {
+		t.Errorf("registry after purge: %+v", registry.Users)
+	}
+	if content, _ := os.ReadFile(itemPath); strings.Contains(strings.ToLower(string(content)), "alice") {
+		t.Errorf("item not anonymized:\n%s", content)
+	}
+	state, _ = loadIntakeState(projectDir)
+	if _, ok := state.Messages["<m1@example.com>"]; ok || len(state.Messages) != 1 {
+		t.Errorf("intake records %v", state.Messages)
+	}
+
+	// A second purge finds nothing
+	if result, err = PurgeUser(config, projectDir, "alice@example.com", opts); err != nil || result.UserRemoved || len(result.Items) != 0 {
+		t.Errorf("second purge: %+v, %v", result, err)
+	}
+}
+
+func TestPurgeUserDeleteAccounts(t *testing.T) {
+	anonymized := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/c/ideas/show.json":
+			json.NewEncoder(w).Encode(map[string]interface{}{"category": DiscourseCategory{ID: 5, Slug: "ideas"}})
+		case r.Method == "GET" && r.URL.Path == "/admin/users/list/all.json":
+			// The email filter matches substrings
+			json.NewEncoder(w).Encode([]DiscourseUser{{ID: 7, Username: "malice", Email: "malice@example.com"}, {ID: 8, Username: "alice", Email: r.URL.Query().Get("email")}})
+		case r.Method == "PUT" && r.URL.Path == "/admin/users/8/anonymize.json":
+			anonymized++
+			w.Write([]byte(`{"success":"OK"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig()
+	config.VoC = &AreaConfig{Provider: "discourse", URL: server.URL, APIToken: "key", Category: "ideas"}
+	config.VoS = &AreaConfig{Provider: "discourse", URL: server.URL, APIToken: "key", Category: "ideas"}
+	config.VoB = &AreaConfig{Provider: "fider", URL: "http://fider.example.com"}
+
+	result, err := PurgeUser(config, t.TempDir(), "alice@example.com", PurgeOptions{DeleteAccounts: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anonymized != 1 || len(result.Accounts) != 1 {
+		t.Errorf("discourse account: anonymized %d times, %+v", anonymized, result)
+	}
+	if len(result.AccountsManual) != 1 || !strings.HasPrefix(result.AccountsManual[0], "fider") {
+		t.Errorf("fider account not reported for manual deletion: %v", result.AccountsManual)
+	}
+}
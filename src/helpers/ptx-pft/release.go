@@ -0,0 +1,217 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReleaseEntry is a feedback item delivered in a release
+type ReleaseEntry struct {
+	Item FeedbackItem `json:"item"`
+	// Reason is "status" when the item status changed to implemented or
+	// released, or "issue" when a commit references its linked issue
+	Reason string `json:"reason"`
+	Group  string `json:"group"`
+}
+
+// ReleaseNotes collects the items delivered between two revisions
+type ReleaseNotes struct {
+	Title   string         `json:"title"`
+	Since   string         `json:"since"`
+	Until   string         `json:"until"`
+	Entries []ReleaseEntry `json:"entries"`
+}
+
+// ReleaseOptions selects the range and grouping of release notes
+type ReleaseOptions struct {
+	// Since is a git tag, commit or a date (YYYY-MM-DD)
+	Since string
+	// Until is a git revision, default HEAD
+	Until string
+	// GroupBy is "category" (default) or "product"
+	GroupBy string
+	Title   string
+}
+
+func isReleasedStatus(status string) bool {
+	return roadmapColumn(status) == "done"
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// releaseBase resolves --since to the base commit. A date resolves to the
+// last commit before it; an empty base means the history starts later.
+func releaseBase(projectDir, since, until string) (string, error) {
+	if date, err := time.Parse("2006-01-02", since); err == nil {
+		return gitOutput(projectDir, "rev-list", "-1", "--before="+date.Format(time.RFC3339), until)
+	}
+	return gitOutput(projectDir, "rev-parse", "--verify", "--quiet", since+"^{commit}")
+}
+
+// statusAt returns the status of a feedback file at a revision, or "" when
+// the file did not exist yet
+func statusAt(projectDir, base, filePath string) string {
+	if base == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(projectDir, filePath)
+	if err != nil {
+		return ""
+	}
+	content, err := gitOutput(projectDir, "show", base+":./"+filepath.ToSlash(rel))
+	if err != nil {
+		return ""
+	}
+	item, err := parseMarkdownContent(filePath, []byte(content))
+	if err != nil {
+		return ""
+	}
+	return item.Status
+}
+
+// mentionsIssue reports whether commit messages reference an issue ID
+// such as "#107" or "ISSUE-42"
+func mentionsIssue(messages, issue string) bool {
+	pattern := `(^|[^\w#-])` + regexp.QuoteMeta(issue) + `($|[^\w-])`
+	matched, _ := regexp.MatchString(pattern, messages)
+	return matched
+}
+
+// CollectReleaseNotes selects the items delivered since a git revision or
+// date. An item is included when its status changed to implemented or
+// released in the range, or when a commit in the range references its
+// linked issue. The project directory must be inside a git repository.
+func CollectReleaseNotes(projectDir string, items []FeedbackItem, categoryNames map[string]string, opts ReleaseOptions) (*ReleaseNotes, error) {
+	if opts.Until == "" {
+		opts.Until = "HEAD"
+	}
+	if _, err := gitOutput(projectDir, "rev-parse", "--git-dir"); err != nil {
+		return nil, fmt.Errorf("release notes need the project in a git repository: %w", err)
+	}
+	base, err := releaseBase(projectDir, opts.Since, opts.Until)
+	if err != nil {
+		return nil, fmt.Errorf("unknown revision %q: %w", opts.Since, err)
+	}
+
+	logRange := opts.Until
+	if base != "" {
+		logRange = base + ".." + opts.Until
+	}
+	messages, err := gitOutput(projectDir, "log", "--format=%B", logRange)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := &ReleaseNotes{Title: opts.Title, Since: opts.Since, Until: opts.Until}
+	for _, item := range items {
+		// Items are read from the working tree; an explicit --until uses
+		// the status committed at that revision
+		status := item.Status
+		if opts.Until != "HEAD" {
+			status = statusAt(projectDir, opts.Until, item.FilePath)
+		}
+		reason := ""
+		switch {
+		case isReleasedStatus(status):
+			if !isReleasedStatus(statusAt(projectDir, base, item.FilePath)) {
+				reason = "status"
+			}
+		case item.LinkedIssue != "" && mentionsIssue(messages, item.LinkedIssue):
+			reason = "issue"
+		}
+		if reason == "" {
+			continue
+		}
+		notes.Entries = append(notes.Entries, ReleaseEntry{
+			Item:   item,
+			Reason: reason,
+			Group:  releaseGroup(item, opts.GroupBy, categoryNames),
+		})
+	}
+
+	sort.SliceStable(notes.Entries, func(i, j int) bool {
+		a, b := notes.Entries[i], notes.Entries[j]
+		if a.Group != b.Group {
+			// The catch-all group goes last
+			if a.Group == otherReleaseGroup || b.Group == otherReleaseGroup {
+				return b.Group == otherReleaseGroup
+			}
+			return a.Group < b.Group
+		}
+		if a.Item.Votes != b.Item.Votes {
+			return a.Item.Votes > b.Item.Votes
+		}
+		return a.Item.Title < b.Item.Title
+	})
+	return notes, nil
+}
+
+const otherReleaseGroup = "Other Improvements"
+
+func releaseGroup(item FeedbackItem, groupBy string, categoryNames map[string]string) string {
+	if groupBy == "product" {
+		if len(item.Products) > 0 {
+			return item.Products[0]
+		}
+		return otherReleaseGroup
+	}
+	if len(item.Categories) == 0 {
+		return otherReleaseGroup
+	}
+	if name, ok := categoryNames[item.Categories[0]]; ok && name != "" {
+		return name
+	}
+	return item.Categories[0]
+}
+
+// Markdown renders customer-facing release notes. Internal IDs and issue
+// references are left out.
+func (n *ReleaseNotes) Markdown() string {
+	var sb strings.Builder
+	title := "Release Notes"
+	if n.Title != "" {
+		title += ": " + n.Title
+	}
+	sb.WriteString("# " + title + "\n\n")
+	sb.WriteString(fmt.Sprintf("_Changes since %s, %s_\n\n", n.Since, time.Now().Format("2006-01-02")))
+	if len(n.Entries) == 0 {
+		sb.WriteString("No customer-facing changes in this release.\n")
+		return sb.String()
+	}
+
+	group := ""
+	for _, entry := range n.Entries {
+		if entry.Group != group {
+			if group != "" {
+				sb.WriteString("\n")
+			}
+			group = entry.Group
+			sb.WriteString("## " + group + "\n\n")
+		}
+		line := "- **" + strings.TrimPrefix(entry.Item.Title, entry.Item.ID+": ") + "**"
+		if summary := strings.TrimSpace(entry.Item.Summary); summary != "" && summary != entry.Item.Title {
+			line += " - " + summary
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,78 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCollectReleaseNotes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	projectDir := t.TempDir()
+	vocDir := filepath.Join(projectDir, "VoC")
+	os.MkdirAll(vocDir, 0755)
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", projectDir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(id, frontmatter, title string) {
+		content := "---\nid: " + id + "\n" + frontmatter + "---\n\n# " + id + ": " + title + "\n\n## Summary\n" + title + " for everyone\n"
+		os.WriteFile(filepath.Join(vocDir, id+".md"), []byte(content), 0644)
+	}
+
+	git("init", "-q")
+	write("UC001", "status: implemented\n", "Old feature")
+	write("UC002", "status: planned\ncategories:\n  - EXPORT\n", "PDF export")
+	write("UC003", "status: open\nlinked_issue: #107\n", "Dark mode")
+	write("UC004", "status: open\nlinked_issue: #10\n", "Fax support")
+	git("add", "-A")
+	git("commit", "-q", "-m", "Initial feedback")
+	git("tag", "v1.3.0")
+
+	write("UC002", "status: released\ncategories:\n  - EXPORT\n", "PDF export")
+	git("commit", "-q", "-am", "Add dark theme (#107)")
+
+	items, err := scanLocalDirectory(vocDir, "voc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes, err := CollectReleaseNotes(projectDir, items, map[string]string{"EXPORT": "Reporting & Export"}, ReleaseOptions{Since: "v1.3.0", Title: "Acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes.Entries) != 2 {
+		t.Fatalf("expected UC002 and UC003, got %+v", notes.Entries)
+	}
+	export, other := notes.Entries[0], notes.Entries[1]
+	if export.Item.ID != "UC002" || export.Reason != "status" || export.Group != "Reporting & Export" {
+		t.Errorf("unexpected status entry %+v", export)
+	}
+	if other.Item.ID != "UC003" || other.Reason != "issue" || other.Group != otherReleaseGroup {
+		t.Errorf("unexpected linked issue entry %+v", other)
+	}
+
+	markdown := notes.Markdown()
+	for _, want := range []string{"# Release Notes: Acme", "## Reporting & Export", "- **PDF export** - PDF export for everyone"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("release notes missing %q:\n%s", want, markdown)
+		}
+	}
+	if strings.Contains(markdown, "UC00") || strings.Contains(markdown, "#107") {
+		t.Errorf("internal IDs must not be published:\n%s", markdown)
+	}
+
+	if _, err := CollectReleaseNotes(projectDir, items, nil, ReleaseOptions{Since: "v9.9.9"}); err == nil {
+		t.Error("unknown tag should fail")
+	}
+}
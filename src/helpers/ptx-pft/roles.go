@@ -10,6 +10,8 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+
+	"portunix.ai/app/fileutil"
 )
 
 // RoleDefinition defines a role
@@ -136,7 +138,7 @@ func SaveRoles(projectDir, category string, roleFile *RoleFile) error {
 		return fmt.Errorf("failed to marshal roles: %w", err)
 	}
 
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
+	if err := fileutil.WriteFile(filePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write roles.json: %w", err)
 	}
 
@@ -0,0 +1,289 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ItemScores holds the sentiment and urgency of a verbatim, stored in the
+// frontmatter of its file
+type ItemScores struct {
+	// Sentiment ranges from -1 (very negative) to 1 (very positive)
+	Sentiment float64 `json:"sentiment"`
+	// Urgency ranges from 0 (no time pressure) to 1 (blocking, act now)
+	Urgency float64 `json:"urgency"`
+	// ScoredBy names the scorer, e.g. "rules" or "ollama/llama3.2"
+	ScoredBy string `json:"scored_by,omitempty"`
+	ScoredAt string `json:"scored_at,omitempty"`
+}
+
+func (s *ItemScores) setField(key, value string) {
+	switch key {
+	case "sentiment":
+		s.Sentiment, _ = strconv.ParseFloat(value, 64)
+	case "urgency":
+		s.Urgency, _ = strconv.ParseFloat(value, 64)
+	case "scored_by":
+		s.ScoredBy = value
+	case "scored_at":
+		s.ScoredAt = value
+	}
+}
+
+// fields returns the frontmatter fields of the scores
+func (s ItemScores) fields() map[string]string {
+	return map[string]string{
+		"sentiment": strconv.FormatFloat(s.Sentiment, 'f', 2, 64),
+		"urgency":   strconv.FormatFloat(s.Urgency, 'f', 2, 64),
+		"scored_by": s.ScoredBy,
+		"scored_at": s.ScoredAt,
+	}
+}
+
+// Scorer rates the sentiment and urgency of a verbatim text
+type Scorer interface {
+	Name() string
+	Score(text string) (ItemScores, error)
+}
+
+// sentimentLexicon weights words by polarity. Entries ending in "*" match
+// word prefixes, which covers inflected Czech forms.
+var sentimentLexicon = map[string]float64{
+	"love": 3, "great": 3, "excellent": 3, "awesome": 3, "amazing": 3, "perfect": 3,
+	"good": 2, "nice": 2, "like": 1.5, "helpful": 2, "easy": 1.5, "fast": 1.5,
+	"happy": 2, "thanks": 1.5, "thank": 1.5, "useful": 2, "intuitive": 2, "works": 1,
+	"bad": -2, "terrible": -3, "awful": -3, "horrible": -3, "hate": -3, "useless": -3,
+	"slow": -2, "broken": -2.5, "crash": -2.5, "crashes": -2.5, "crashed": -2.5,
+	"bug": -1.5, "buggy": -2, "error": -1.5, "errors": -1.5, "fail": -2, "fails": -2,
+	"failed": -2, "failure": -2, "confusing": -2, "annoying": -2, "frustrating": -2.5,
+	"frustrated": -2.5, "disappointed": -2.5, "difficult": -1.5, "hard": -1,
+	"missing": -1, "lost": -2, "problem": -1.5, "problems": -1.5, "issue": -1,
+	"unusable": -3, "worse": -2, "worst": -3, "painful": -2, "cancel": -2, "refund": -2,
+	"skvěl*": 3, "výborn*": 3, "super": 2.5, "dobr*": 2, "rychl*": 1.5, "díky": 1.5,
+	"špatn*": -2, "pomal*": -2, "chyb*": -1.5, "problém*": -1.5, "nefunguj*": -2.5,
+	"padá": -2.5, "spadl*": -2.5, "hrozn*": -3, "nepoužiteln*": -3, "otravn*": -2,
+}
+
+// urgencyLexicon weights words signalling time pressure or business impact
+var urgencyLexicon = map[string]float64{
+	"urgent": 3, "urgently": 3, "asap": 3, "immediately": 3, "now": 1, "today": 1.5,
+	"critical": 2.5, "blocker": 3, "blocking": 3, "blocked": 3, "blocks": 2.5,
+	"outage": 3, "down": 1.5, "production": 1.5, "deadline": 2, "security": 2,
+	"vulnerability": 2.5, "leak": 2.5, "breach": 3, "crash": 1.5, "crashes": 1.5,
+	"cannot": 1.5, "can't": 1.5, "unable": 1.5, "stuck": 2, "escalate": 2.5,
+	"escalation": 2.5, "lose": 1.5, "losing": 2, "churn": 2.5, "cancel": 2,
+	"switch": 1, "competitor": 1.5, "customers": 0.5, "revenue": 2, "emergency": 3,
+	"urgentn*": 3, "okamžit*": 3, "kritick*": 2.5, "blokuj*": 3, "nutn*": 2, "hned": 1.5,
+	"výpadek": 3, "nejde": 1.5, "nemůž*": 1.5,
+}
+
+var scoreNegators = map[string]bool{
+	"not": true, "no": true, "never": true, "don't": true, "doesn't": true, "isn't": true,
+	"wasn't": true, "won't": true, "without": true, "ne": true, "není": true, "nikdy": true,
+}
+
+var scoreIntensifiers = map[string]float64{
+	"very": 1.5, "really": 1.4, "extremely": 1.8, "totally": 1.5, "completely": 1.6,
+	"so": 1.3, "always": 1.3, "velmi": 1.5, "hodně": 1.4, "úplně": 1.6, "vždy": 1.3,
+}
+
+func lexiconWeight(lexicon map[string]float64, word string) float64 {
+	if w, ok := lexicon[word]; ok {
+		return w
+	}
+	for entry, w := range lexicon {
+		if strings.HasSuffix(entry, "*") && strings.HasPrefix(word, strings.TrimSuffix(entry, "*")) {
+			return w
+		}
+	}
+	return 0
+}
+
+// ruleScorer is the baseline scorer: word lexicons with negation and
+// intensifiers, plus exclamation marks and capitals for urgency
+type ruleScorer struct{}
+
+func (ruleScorer) Name() string { return "rules" }
+
+func (ruleScorer) Score(text string) (ItemScores, error) {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	var sentiment, urgency float64
+	for i, raw := range words {
+		word := strings.ToLower(raw)
+		factor := 1.0
+		negated := false
+		// The nearest negator or intensifier within three words applies
+		for j := i - 1; j >= max(0, i-3); j-- {
+			previous := strings.ToLower(words[j])
+			if scoreNegators[previous] {
+				negated = true
+				break
+			}
+			if f, ok := scoreIntensifiers[previous]; ok {
+				factor *= f
+			}
+		}
+		if len([]rune(raw)) > 2 && raw == strings.ToUpper(raw) && strings.ToLower(raw) != raw {
+			factor *= 1.3
+			urgency += 0.5
+		}
+		if w := lexiconWeight(sentimentLexicon, word); w != 0 {
+			if negated {
+				w = -w * 0.75
+			}
+			sentiment += w * factor
+		}
+		if w := lexiconWeight(urgencyLexicon, word); w != 0 && !negated {
+			urgency += w * factor
+		}
+	}
+	exclamations := strings.Count(text, "!")
+	urgency += math.Min(float64(exclamations), 4) * 0.5
+	if sentiment < 0 {
+		sentiment -= math.Min(float64(exclamations), 4) * 0.3
+	}
+
+	return ItemScores{
+		// Normalized like VADER: x / sqrt(x² + 15)
+		Sentiment: round2(sentiment / math.Sqrt(sentiment*sentiment+15)),
+		Urgency:   round2(1 - math.Exp(-urgency/4)),
+		ScoredBy:  "rules",
+	}, nil
+}
+
+// llmScorer asks a language model for the scores
+type llmScorer struct {
+	model LanguageModel
+}
+
+func (s llmScorer) Name() string { return s.model.ModelName() }
+
+func (s llmScorer) Score(text string) (ItemScores, error) {
+	prompt := `Rate this product feedback verbatim. Answer with a JSON object
+{"sentiment": <number from -1 very negative to 1 very positive>,
+ "urgency": <number from 0 no time pressure to 1 blocking, needs action now>}.
+
+Verbatim:
+` + truncateString(text, 2000)
+	answer, err := s.model.Complete(prompt, true)
+	if err != nil {
+		return ItemScores{}, err
+	}
+	var scores ItemScores
+	start, end := strings.Index(answer, "{"), strings.LastIndex(answer, "}")
+	if start < 0 || end < start {
+		return ItemScores{}, fmt.Errorf("no JSON object in model answer: %s", truncateString(answer, 100))
+	}
+	if err := json.Unmarshal([]byte(answer[start:end+1]), &scores); err != nil {
+		return ItemScores{}, fmt.Errorf("invalid model answer: %w", err)
+	}
+	scores.Sentiment = round2(math.Max(-1, math.Min(1, scores.Sentiment)))
+	scores.Urgency = round2(math.Max(0, math.Min(1, scores.Urgency)))
+	scores.ScoredBy = s.Name()
+	return scores, nil
+}
+
+// NewScorer returns the rule-based scorer, or with useLLM the language
+// model of the AI configuration
+func NewScorer(ai *AIConfig, useLLM bool) (Scorer, error) {
+	if !useLLM {
+		return ruleScorer{}, nil
+	}
+	embedder, err := NewEmbedder(ai)
+	if err != nil {
+		return nil, err
+	}
+	model, ok := embedder.(LanguageModel)
+	if !ok {
+		return nil, fmt.Errorf("--llm needs an AI provider with a language model (pft configure --ai-provider ollama|openai)")
+	}
+	return llmScorer{model: model}, nil
+}
+
+// verbatimText returns the text scored for an item: its Verbatim section
+// when present, otherwise title and description
+func verbatimText(item FeedbackItem) string {
+	if item.FilePath != "" {
+		if content, err := os.ReadFile(item.FilePath); err == nil {
+			if verbatim := extractSection(string(content), "Verbatim"); verbatim != "" {
+				return strings.TrimSpace(strings.ReplaceAll(verbatim, "> ", ""))
+			}
+		}
+	}
+	return strings.TrimSpace(item.Title + "\n" + item.Summary + "\n" + item.Description)
+}
+
+// ScoreItems scores items and stores the scores in their frontmatter.
+// Scored items are skipped unless force is set; a failing LLM answer falls
+// back to the rule-based scores. It returns the items with their scores.
+func ScoreItems(items []FeedbackItem, scorer Scorer, force, dryRun bool) ([]FeedbackItem, []string, error) {
+	var warnings []string
+	var scored []FeedbackItem
+	for _, item := range items {
+		if item.Scores != nil && !force {
+			continue
+		}
+		text := verbatimText(item)
+		scores, err := scorer.Score(text)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v; rule-based scores used", item.ID, err))
+			scores, _ = ruleScorer{}.Score(text)
+		}
+		scores.ScoredAt = time.Now().UTC().Format(time.RFC3339)
+		item.Scores = &scores
+		if !dryRun && item.FilePath != "" {
+			if err := UpdateFileFields(item.FilePath, scores.fields()); err != nil {
+				return scored, warnings, fmt.Errorf("failed to store scores of %s: %w", item.ID, err)
+			}
+		}
+		scored = append(scored, item)
+	}
+	return scored, warnings, nil
+}
+
+// sortItemsByScore orders items by urgency (highest first) or sentiment
+// (most negative first); unscored items go last
+func sortItemsByScore(items []FeedbackItem, by string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].Scores, items[j].Scores
+		if a == nil || b == nil {
+			return a != nil
+		}
+		if by == "sentiment" {
+			return a.Sentiment < b.Sentiment
+		}
+		return a.Urgency > b.Urgency
+	})
+}
+
+// attentionItems returns up to limit scored items that are negative or
+// urgent, most urgent first
+func attentionItems(items []FeedbackItem, limit int) []FeedbackItem {
+	var selected []FeedbackItem
+	for _, item := range items {
+		if item.Scores != nil && (item.Scores.Urgency >= 0.5 || item.Scores.Sentiment <= -0.3) {
+			selected = append(selected, item)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		a, b := selected[i].Scores, selected[j].Scores
+		return a.Urgency-a.Sentiment/2 > b.Urgency-b.Sentiment/2
+	})
+	if len(selected) > limit {
+		selected = selected[:limit]
+	}
+	return selected
+}
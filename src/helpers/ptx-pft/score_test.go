@@ -0,0 +1,101 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRuleScorer(t *testing.T) {
+	scorer := ruleScorer{}
+	angry, _ := scorer.Score("The export is BROKEN and crashes every time! We cannot ship, this is a blocker. Fix it ASAP!")
+	happy, _ := scorer.Score("I love the new dashboard, it is fast and really easy to use.")
+	negated, _ := scorer.Score("The import is not bad, no problems so far.")
+	czech, _ := scorer.Score("Aplikace nefunguje a padá, potřebujeme to okamžitě opravit!")
+
+	if angry.Sentiment >= -0.5 || angry.Urgency < 0.8 {
+		t.Errorf("angry verbatim scored %+v", angry)
+	}
+	if happy.Sentiment <= 0.5 || happy.Urgency > 0.1 {
+		t.Errorf("happy verbatim scored %+v", happy)
+	}
+	if negated.Sentiment <= 0 {
+		t.Errorf("negated complaints should not be negative: %+v", negated)
+	}
+	if czech.Sentiment >= 0 || czech.Urgency < 0.5 {
+		t.Errorf("czech verbatim scored %+v", czech)
+	}
+}
+
+func TestScoreItemsStoresFrontmatter(t *testing.T) {
+	dir := t.TempDir()
+	urgentPath := filepath.Join(dir, "VB001.md")
+	os.WriteFile(urgentPath, []byte("---\nid: VB001\nstatus: open\ncategories:\n  - EXPORT\n---\n\n# Export crash\n\n## Verbatim\n\n> Export crashes, we are blocked and losing customers. Urgent!\n"), 0644)
+	plainPath := filepath.Join(dir, "VB002.md")
+	os.WriteFile(plainPath, []byte("# Nice charts\n\nThe charts are nice.\n"), 0644)
+
+	items, err := scanLocalDirectory(dir, "voc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scored, _, err := ScoreItems(items, ruleScorer{}, false, false)
+	if err != nil || len(scored) != 2 {
+		t.Fatalf("expected 2 scored items, got %d %v", len(scored), err)
+	}
+
+	item, err := ParseMarkdownFile(urgentPath)
+	if err != nil || item.Scores == nil || item.Scores.Urgency < 0.7 || item.Scores.ScoredBy != "rules" {
+		t.Fatalf("scores not stored in frontmatter: %+v %v", item, err)
+	}
+	if len(item.Categories) != 1 || item.Status != "open" {
+		t.Errorf("existing frontmatter lost: %+v", item)
+	}
+	if item, _ := ParseMarkdownFile(plainPath); item == nil || item.Scores == nil || item.Title != "Nice charts" {
+		t.Errorf("file without frontmatter not scored: %+v", item)
+	}
+
+	items, _ = scanLocalDirectory(dir, "voc")
+	if rescored, _, _ := ScoreItems(items, ruleScorer{}, false, false); len(rescored) != 0 {
+		t.Errorf("scored items must be skipped without --force")
+	}
+	sortItemsByScore(items, "urgency")
+	if items[0].ID != "VB001" {
+		t.Errorf("most urgent item should come first: %s", items[0].ID)
+	}
+	if attention := attentionItems(items, 10); len(attention) != 1 || attention[0].ID != "VB001" {
+		t.Errorf("unexpected attention items %+v", attention)
+	}
+	content, _ := os.ReadFile(urgentPath)
+	if strings.Count(string(content), "urgency:") != 1 {
+		t.Errorf("rescoring must replace fields:\n%s", content)
+	}
+}
+
+func TestLLMScorer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response": "{\"sentiment\": -0.8, \"urgency\": 1.4}"}`))
+	}))
+	defer server.Close()
+
+	scorer, err := NewScorer(&AIConfig{Provider: "ollama", URL: server.URL}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scores, err := scorer.Score("Everything is down")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scores.Sentiment != -0.8 || scores.Urgency != 1 || scores.ScoredBy != "ollama/llama3.2" {
+		t.Errorf("unexpected scores %+v", scores)
+	}
+	if _, err := NewScorer(nil, true); err == nil {
+		t.Error("--llm without a language model should fail")
+	}
+}
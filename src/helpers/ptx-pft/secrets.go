@@ -0,0 +1,68 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"portunix.ai/app/secret"
+)
+
+// secretConsumer names pft in the access log of the portunix secret store
+const secretConsumer = "pft"
+
+var secretNameInvalid = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// secretName returns the secret store name of a project credential, e.g.
+// pft/my-product/voc
+func secretName(config *Config, key string) string {
+	project := strings.Trim(secretNameInvalid.ReplaceAllString(strings.ToLower(config.Name), "-"), "-.")
+	if project == "" {
+		project = "default"
+	}
+	return "pft/" + project + "/" + key
+}
+
+// storeSecret moves a credential into the portunix secret store and
+// returns the "secret:<name>" reference kept in the configuration file.
+// When the store cannot be used the plain value is kept.
+func storeSecret(name, value string) string {
+	if value == "" || secret.IsReference(value) {
+		return value
+	}
+	if err := secret.Set(name, value, "", secretConsumer); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot use the secret store (%v); credential kept in the configuration file\n", err)
+		return value
+	}
+	return secret.Reference(name)
+}
+
+// storeToken stores the API token of an area
+func storeToken(config *Config, area, token string) string {
+	return storeSecret(secretName(config, area), token)
+}
+
+// resolveSecret returns the credential named by a "secret:<name>" value;
+// plain values are returned unchanged
+func resolveSecret(value string) string {
+	resolved, err := secret.Resolve(value, secretConsumer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return ""
+	}
+	return resolved
+}
+
+// Token returns the API token of the area, resolved from the secret store
+// when the configuration holds a reference
+func (a *AreaConfig) Token() string {
+	if a == nil {
+		return ""
+	}
+	return resolveSecret(a.APIToken)
+}
@@ -0,0 +1,267 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"portunix.ai/portunix/src/helpers/ptx-pft/assets/templates"
+)
+
+const (
+	// DefaultShareExpiry is how long share links stay valid
+	DefaultShareExpiry = 7 * 24 * time.Hour
+	// DefaultShareListen is the address of 'pft share serve'
+	DefaultShareListen = ":8086"
+)
+
+// errShareExpired is returned for a correctly signed link past its expiry
+var errShareExpired = errors.New("share link expired")
+
+// ShareClaims are the signed contents of a share token. Digest is set for
+// HTML exports and covers the exported page, so edits invalidate it.
+type ShareClaims struct {
+	ID      string `json:"id"`
+	Expires int64  `json:"exp"`
+	Digest  string `json:"sha,omitempty"`
+}
+
+// ExpiresAt returns the expiry of the claims
+func (c ShareClaims) ExpiresAt() time.Time {
+	return time.Unix(c.Expires, 0)
+}
+
+// SignShareToken returns the token "<payload>.<signature>", both base64url
+// encoded, the signature an HMAC-SHA256 of the payload
+func SignShareToken(key []byte, claims ShareClaims) string {
+	payload, _ := json.Marshal(claims)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + shareSignature(key, encoded)
+}
+
+func shareSignature(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyShareToken checks the signature and expiry of a token. Expired
+// tokens return their claims with errShareExpired.
+func VerifyShareToken(key []byte, token string, now time.Time) (*ShareClaims, error) {
+	payload, signature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(shareSignature(key, payload))) {
+		return nil, fmt.Errorf("invalid share token")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid share token")
+	}
+	var claims ShareClaims
+	if err := json.Unmarshal(data, &claims); err != nil || claims.ID == "" {
+		return nil, fmt.Errorf("invalid share token")
+	}
+	if now.After(claims.ExpiresAt()) {
+		return &claims, errShareExpired
+	}
+	return &claims, nil
+}
+
+// ParseShareExpiry parses the lifetime of a share link: days (7d), weeks
+// (2w) or a Go duration (36h)
+func ParseShareExpiry(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, err := strconv.Atoi(strings.TrimSuffix(value, suffix)); err == nil && strings.HasSuffix(value, suffix) {
+			if n <= 0 {
+				return 0, fmt.Errorf("invalid expiry '%s'", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid expiry '%s' (e.g. 7d, 2w, 12h)", value)
+	}
+	return d, nil
+}
+
+// shareKey returns the signing key of the project. With create a missing
+// key is generated, stored in the secret store and saved to configPath.
+func shareKey(config *Config, configPath string, create bool) ([]byte, error) {
+	if config.Share != nil && config.Share.Key != "" {
+		key := resolveSecret(config.Share.Key)
+		if key == "" {
+			return nil, fmt.Errorf("cannot read the share key %s", config.Share.Key)
+		}
+		return []byte(key), nil
+	}
+	if !create {
+		return nil, fmt.Errorf("no share links were created yet; use 'pft share <id>'")
+	}
+	return rotateShareKey(config, configPath)
+}
+
+// rotateShareKey replaces the signing key, invalidating all share links
+// and exports
+func rotateShareKey(config *Config, configPath string) ([]byte, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate share key: %w", err)
+	}
+	key := hex.EncodeToString(random)
+	if config.Share == nil {
+		config.Share = &ShareConfig{}
+	}
+	config.Share.Key = storeSecret(secretName(config, "share-key"), key)
+	if err := config.SaveToPath(configPath); err != nil {
+		return nil, fmt.Errorf("failed to save share key: %w", err)
+	}
+	return []byte(key), nil
+}
+
+// sharePage is the data of share.html.tmpl
+type sharePage struct {
+	Title       string
+	Token       string
+	Style       template.CSS
+	Item        SiteItem
+	Generated   string
+	Expires     string
+	ExpiresUnix int64
+}
+
+// renderSharePage renders the self-contained read-only page of an item;
+// only the public fields of SiteItem are shown
+func renderSharePage(item FeedbackItem, title, token string, expires, now time.Time) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates.SiteTemplates, "site/layout.html.tmpl", "site/share.html.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse share template: %w", err)
+	}
+	css, err := templates.SiteTemplates.ReadFile("site/style.css")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stylesheet: %w", err)
+	}
+	var buf bytes.Buffer
+	err = tmpl.ExecuteTemplate(&buf, "share.html.tmpl", sharePage{
+		Title:       title,
+		Token:       token,
+		Style:       template.CSS(css),
+		Item:        newSiteItem(item),
+		Generated:   now.Format("2006-01-02 15:04"),
+		Expires:     expires.Format("2006-01-02 15:04"),
+		ExpiresUnix: expires.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render share page: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+var shareMetaToken = regexp.MustCompile(`<meta name="pft-share" content="([^"]*)">`)
+
+// ShareExport renders the signed read-only HTML export of an item. The
+// token in the page signs a digest of the page rendered without it.
+func ShareExport(key []byte, item FeedbackItem, title string, expires, now time.Time) ([]byte, string, error) {
+	page, err := renderSharePage(item, title, "", expires, now)
+	if err != nil {
+		return nil, "", err
+	}
+	digest := sha256.Sum256(page)
+	token := SignShareToken(key, ShareClaims{ID: item.ID, Expires: expires.Unix(), Digest: hex.EncodeToString(digest[:])})
+	page = bytes.Replace(page, []byte(`name="pft-share" content=""`), []byte(`name="pft-share" content="`+token+`"`), 1)
+	return page, token, nil
+}
+
+// VerifyShareExport checks that an HTML export was signed with key and
+// not modified since
+func VerifyShareExport(key, page []byte, now time.Time) (*ShareClaims, error) {
+	match := shareMetaToken.FindSubmatchIndex(page)
+	if match == nil {
+		return nil, fmt.Errorf("not a pft share export")
+	}
+	claims, err := VerifyShareToken(key, string(page[match[2]:match[3]]), now)
+	if claims == nil {
+		return nil, err
+	}
+	unsigned := append(append([]byte{}, page[:match[2]]...), page[match[3]:]...)
+	digest := sha256.Sum256(unsigned)
+	if claims.Digest != hex.EncodeToString(digest[:]) {
+		return claims, fmt.Errorf("the export was modified after it was signed")
+	}
+	return claims, err
+}
+
+// ShareServer serves the items of share tokens at /share/<token> with
+// their current status, for stakeholders without access to the repository
+type ShareServer struct {
+	ProjectDir string
+	Key        []byte
+	Title      string
+	now        func() time.Time
+}
+
+// Handler returns the HTTP handler of the share server
+func (s *ShareServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/share/", s.handleShare)
+	return mux
+}
+
+func (s *ShareServer) handleShare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+	claims, err := VerifyShareToken(s.Key, strings.TrimPrefix(r.URL.Path, "/share/"), now)
+	if errors.Is(err, errShareExpired) {
+		http.Error(w, fmt.Sprintf("This shared link expired on %s.", claims.ExpiresAt().Format("2006-01-02")), http.StatusGone)
+		return
+	}
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	item, _, err := findFeedbackItem(s.ProjectDir, claims.ID)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	page, err := renderSharePage(*item, s.Title, "", claims.ExpiresAt(), now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.Write(page)
+}
+
+// ShareURL returns the link of a token on the share server
+func ShareURL(config *Config, token string) string {
+	base := "http://localhost" + DefaultShareListen
+	if config.Share != nil && config.Share.URL != "" {
+		base = config.Share.URL
+	}
+	return strings.TrimRight(base, "/") + "/share/" + token
+}
@@ -0,0 +1,117 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShareToken(t *testing.T) {
+	key := []byte("share-key")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	token := SignShareToken(key, ShareClaims{ID: "UC001", Expires: now.Add(DefaultShareExpiry).Unix()})
+
+	claims, err := VerifyShareToken(key, token, now)
+	if err != nil || claims.ID != "UC001" {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+	if claims, err := VerifyShareToken(key, token, now.Add(8*24*time.Hour)); !errors.Is(err, errShareExpired) || claims.ID != "UC001" {
+		t.Errorf("expired token: %+v, %v", claims, err)
+	}
+	if _, err := VerifyShareToken([]byte("other-key"), token, now); err == nil {
+		t.Error("token of another key accepted")
+	}
+	forged := SignShareToken([]byte("other-key"), ShareClaims{ID: "UC002", Expires: claims.Expires})
+	if _, err := VerifyShareToken(key, strings.Split(forged, ".")[0]+"."+strings.Split(token, ".")[1], now); err == nil {
+		t.Error("token with a swapped payload accepted")
+	}
+}
+
+func TestParseShareExpiry(t *testing.T) {
+	for value, want := range map[string]time.Duration{"7d": 7 * 24 * time.Hour, "2w": 14 * 24 * time.Hour, "36h": 36 * time.Hour} {
+		if got, err := ParseShareExpiry(value); err != nil || got != want {
+			t.Errorf("%s: got %v, %v", value, got, err)
+		}
+	}
+	for _, value := range []string{"", "0d", "-1w", "soon"} {
+		if _, err := ParseShareExpiry(value); err == nil {
+			t.Errorf("%q accepted", value)
+		}
+	}
+}
+
+func TestShareExport(t *testing.T) {
+	key := []byte("share-key")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	item := FeedbackItem{ID: "UC001", Title: "PDF export", Status: "planned", Votes: 12, Assignee: "jane.customer",
+		Description: "We print <monthly> reports."}
+
+	page, token, err := ShareExport(key, item, "Acme", now.Add(DefaultShareExpiry), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(page)
+	for _, want := range []string{`content="` + token + `"`, "PDF export", "&lt;monthly&gt;", "valid until 2026-03-08 12:00", "<style>"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("export misses %q", want)
+		}
+	}
+	if strings.Contains(html, "jane.customer") {
+		t.Error("export shows the assignee")
+	}
+
+	if claims, err := VerifyShareExport(key, page, now); err != nil || claims.ID != "UC001" || claims.Digest == "" {
+		t.Errorf("export rejected: %+v, %v", claims, err)
+	}
+	edited := bytes.Replace(page, []byte("planned"), []byte("done"), 1)
+	if _, err := VerifyShareExport(key, edited, now); err == nil || !strings.Contains(err.Error(), "modified") {
+		t.Errorf("edited export: %v", err)
+	}
+	if _, err := VerifyShareExport(key, page, now.Add(30*24*time.Hour)); !errors.Is(err, errShareExpired) {
+		t.Errorf("expired export: %v", err)
+	}
+	if _, err := VerifyShareExport(key, []byte("<html></html>"), now); err == nil {
+		t.Error("page without token accepted")
+	}
+}
+
+func TestShareServer(t *testing.T) {
+	projectDir := t.TempDir()
+	vocDir := getVoiceDir(projectDir, "voc")
+	os.MkdirAll(vocDir, 0755)
+	os.WriteFile(filepath.Join(vocDir, "UC001.md"), []byte("---\nid: UC001\nstatus: started\nvotes: 4\n---\n\n# Dark mode\n"), 0644)
+
+	key := []byte("share-key")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	server := &ShareServer{ProjectDir: projectDir, Key: key, Title: "Acme", now: func() time.Time { return now }}
+	handler := server.Handler()
+	get := func(token string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/share/"+token, nil))
+		return rec
+	}
+
+	rec := get(SignShareToken(key, ShareClaims{ID: "UC001", Expires: now.Add(time.Hour).Unix()}))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Dark mode") || !strings.Contains(rec.Body.String(), "started") {
+		t.Errorf("share page: %d\n%s", rec.Code, rec.Body.String())
+	}
+	if rec := get(SignShareToken(key, ShareClaims{ID: "UC001", Expires: now.Add(-time.Hour).Unix()})); rec.Code != http.StatusGone {
+		t.Errorf("expired link: %d", rec.Code)
+	}
+	if rec := get(SignShareToken([]byte("other-key"), ShareClaims{ID: "UC001", Expires: now.Add(time.Hour).Unix()})); rec.Code != http.StatusNotFound {
+		t.Errorf("foreign link: %d", rec.Code)
+	}
+	if rec := get(SignShareToken(key, ShareClaims{ID: "UC404", Expires: now.Add(time.Hour).Unix()})); rec.Code != http.StatusNotFound {
+		t.Errorf("missing item: %d", rec.Code)
+	}
+}
@@ -0,0 +1,339 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SLA check states
+const (
+	SLABreached = "breached" // Still open after the deadline
+	SLAUpcoming = "upcoming" // Open, deadline within the warning window
+	SLAOnTrack  = "on-track" // Open, deadline further away
+	SLAMet      = "met"      // Done before the deadline
+	SLAMissed   = "missed"   // Done after the deadline
+)
+
+const defaultSLAWarnDays = 2
+
+// untriagedStatuses are the statuses of items nobody has looked at yet
+var untriagedStatuses = map[string]bool{
+	"": true, "open": true, "new": true, "pending": true, "submitted": true, "triage": true,
+}
+
+func isTriagedStatus(status string) bool {
+	return !untriagedStatuses[strings.ToLower(strings.TrimSpace(status))]
+}
+
+func isResolvedStatus(status string) bool {
+	column := roadmapColumn(status)
+	return column == "done" || column == "closed"
+}
+
+// recordStatusDates sets when an item was triaged and resolved as its
+// status changes
+func recordStatusDates(status string, triaged, resolved *string) {
+	today := time.Now().Format("2006-01-02")
+	if *triaged == "" && isTriagedStatus(status) {
+		*triaged = today
+	}
+	if !isResolvedStatus(status) {
+		*resolved = ""
+	} else if *resolved == "" {
+		*resolved = today
+	}
+}
+
+func (p SLAPolicy) String() string {
+	var parts []string
+	unit := "business days"
+	if p.CalendarDays {
+		unit = "days"
+	}
+	if p.TriageDays > 0 {
+		parts = append(parts, fmt.Sprintf("triage within %d %s", p.TriageDays, unit))
+	}
+	if p.ResolveDays > 0 {
+		parts = append(parts, fmt.Sprintf("resolve within %d %s", p.ResolveDays, unit))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// deadline returns the end of the day the given number of days after start
+func (p SLAPolicy) deadline(start time.Time, days int) time.Time {
+	day := start
+	if p.CalendarDays {
+		day = day.AddDate(0, 0, days)
+	} else {
+		for days > 0 {
+			day = day.AddDate(0, 0, 1)
+			if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+				days--
+			}
+		}
+	}
+	year, month, date := day.Date()
+	return time.Date(year, month, date, 23, 59, 59, 0, day.Location())
+}
+
+// parseItemDate parses the dates written by pft (2006-01-02) and providers
+// (RFC 3339)
+func parseItemDate(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(value), time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// SLACheck is the state of one SLA target of an item
+type SLACheck struct {
+	ItemID   string    `json:"item_id"`
+	Title    string    `json:"title"`
+	Area     string    `json:"area"`
+	Target   string    `json:"target"` // triage or resolve
+	Created  time.Time `json:"created"`
+	Deadline time.Time `json:"deadline"`
+	// Done is when the target was reached, zero while open
+	Done  *time.Time `json:"done,omitempty"`
+	State string     `json:"state"`
+	// Days overdue (breached, missed) or remaining (upcoming, on-track)
+	Days int `json:"days"`
+}
+
+// EvaluateSLA checks the items of an area against its policy. Items
+// without a creation date are not tracked, and neither are targets that
+// were reached without a recorded date.
+func EvaluateSLA(area string, policy SLAPolicy, items []FeedbackItem, now time.Time) []SLACheck {
+	warn := policy.WarnDays
+	if warn <= 0 {
+		warn = defaultSLAWarnDays
+	}
+	var checks []SLACheck
+	for _, item := range items {
+		created, ok := parseItemDate(item.CreatedAt)
+		if !ok {
+			continue
+		}
+		resolvedAt := item.ResolvedAt
+		if resolvedAt == "" && isResolvedStatus(item.Status) {
+			resolvedAt = item.UpdatedAt
+		}
+		triagedAt := item.TriagedAt
+		if triagedAt == "" && isTriagedStatus(item.Status) {
+			triagedAt = resolvedAt
+			if triagedAt == "" {
+				triagedAt = item.UpdatedAt
+			}
+		}
+
+		targets := []struct {
+			name    string
+			days    int
+			reached bool
+			at      string
+		}{
+			{"triage", policy.TriageDays, isTriagedStatus(item.Status), triagedAt},
+			{"resolve", policy.ResolveDays, isResolvedStatus(item.Status), resolvedAt},
+		}
+		for _, target := range targets {
+			if target.days <= 0 {
+				continue
+			}
+			check := SLACheck{
+				ItemID:   item.ID,
+				Title:    item.Title,
+				Area:     area,
+				Target:   target.name,
+				Created:  created,
+				Deadline: policy.deadline(created, target.days),
+			}
+			if target.reached {
+				done, ok := parseItemDate(target.at)
+				if !ok {
+					continue
+				}
+				check.Done = &done
+				check.State = SLAMet
+				if done.After(check.Deadline) {
+					check.State = SLAMissed
+					check.Days = daysBetween(check.Deadline, done)
+				}
+			} else if now.After(check.Deadline) {
+				check.State = SLABreached
+				check.Days = daysBetween(check.Deadline, now)
+			} else {
+				check.Days = daysBetween(now, check.Deadline)
+				check.State = SLAOnTrack
+				if check.Days <= warn {
+					check.State = SLAUpcoming
+				}
+			}
+			checks = append(checks, check)
+		}
+	}
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checks[i].Deadline.Before(checks[j].Deadline)
+	})
+	return checks
+}
+
+// daysBetween returns the started days from a to b
+func daysBetween(a, b time.Time) int {
+	return int(math.Ceil(b.Sub(a).Hours() / 24))
+}
+
+// SLAMetrics summarizes the checks of one area and target
+type SLAMetrics struct {
+	Area     string  `json:"area"`
+	Target   string  `json:"target"`
+	Met      int     `json:"met"`
+	Missed   int     `json:"missed"`
+	Breached int     `json:"breached"`
+	Upcoming int     `json:"upcoming"`
+	OnTrack  int     `json:"on_track"`
+	AvgDays  float64 `json:"avg_days"` // Average response time of reached targets
+}
+
+// Compliance returns the share of due or reached targets that met the SLA
+func (m SLAMetrics) Compliance() float64 {
+	total := m.Met + m.Missed + m.Breached
+	if total == 0 {
+		return 1
+	}
+	return float64(m.Met) / float64(total)
+}
+
+// SummarizeSLA computes metrics per area and target
+func SummarizeSLA(checks []SLACheck) []SLAMetrics {
+	index := map[string]int{}
+	var metrics []SLAMetrics
+	durations := map[string][]float64{}
+	for _, check := range checks {
+		key := check.Area + "/" + check.Target
+		i, ok := index[key]
+		if !ok {
+			i = len(metrics)
+			index[key] = i
+			metrics = append(metrics, SLAMetrics{Area: check.Area, Target: check.Target})
+		}
+		switch check.State {
+		case SLAMet:
+			metrics[i].Met++
+		case SLAMissed:
+			metrics[i].Missed++
+		case SLABreached:
+			metrics[i].Breached++
+		case SLAUpcoming:
+			metrics[i].Upcoming++
+		case SLAOnTrack:
+			metrics[i].OnTrack++
+		}
+		if check.Done != nil {
+			durations[key] = append(durations[key], check.Done.Sub(check.Created).Hours()/24)
+		}
+	}
+	for i := range metrics {
+		values := durations[metrics[i].Area+"/"+metrics[i].Target]
+		if len(values) == 0 {
+			continue
+		}
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		metrics[i].AvgDays = round2(sum / float64(len(values)))
+	}
+	sort.SliceStable(metrics, func(i, j int) bool {
+		if metrics[i].Area != metrics[j].Area {
+			return metrics[i].Area < metrics[j].Area
+		}
+		// triage before resolve
+		return metrics[i].Target > metrics[j].Target
+	})
+	return metrics
+}
+
+// collectSLAChecks evaluates every area with an SLA policy
+func collectSLAChecks(config *Config, projectDir string, now time.Time) []SLACheck {
+	var checks []SLACheck
+	for _, area := range []string{"voc", "vos", "vob", "voe"} {
+		areaCfg := config.GetAreaConfig(area)
+		if areaCfg == nil || areaCfg.SLA == nil {
+			continue
+		}
+		items, _ := scanLocalDirectory(getVoiceDir(projectDir, area), area)
+		checks = append(checks, EvaluateSLA(area, *areaCfg.SLA, items, now)...)
+	}
+	return checks
+}
+
+// generateSLAReport adds SLA metrics and open breaches to a report
+func generateSLAReport(report *strings.Builder, l *Locale, checks []SLACheck) {
+	if len(checks) == 0 {
+		return
+	}
+	report.WriteString(fmt.Sprintf("\n## %s\n\n", l.T("sla.title")))
+	report.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s |\n", l.T("sla.area"), l.T("sla.target"), l.T("sla.compliance"),
+		l.T("sla.met"), l.T("sla.missed"), l.T("sla.breached"), l.T("sla.upcoming"), l.T("sla.avg_days")))
+	report.WriteString("|------|--------|------------|-----|--------|----------|----------|-----------|\n")
+	for _, m := range SummarizeSLA(checks) {
+		report.WriteString(fmt.Sprintf("| %s | %s | %.0f%% | %d | %d | %d | %d | %.1f |\n",
+			strings.ToUpper(m.Area), m.Target, m.Compliance()*100, m.Met, m.Missed, m.Breached, m.Upcoming, m.AvgDays))
+	}
+
+	var open []SLACheck
+	for _, check := range checks {
+		if check.State == SLABreached || check.State == SLAUpcoming {
+			open = append(open, check)
+		}
+	}
+	if len(open) == 0 {
+		return
+	}
+	report.WriteString(fmt.Sprintf("\n### %s\n\n", l.T("sla.open")))
+	report.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", l.T("report.id"), l.T("report.item_title"), l.T("sla.target"), l.T("sla.deadline"), l.T("sla.state")))
+	report.WriteString("|-----|-------|--------|----------|-------|\n")
+	for _, check := range open {
+		report.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+			check.ItemID, truncateStr(check.Title, 40), check.Target, check.Deadline.Format("2006-01-02"), localizedSLAState(l, check)))
+	}
+}
+
+// localizedSLAState describes the state of a check in the report language
+func localizedSLAState(l *Locale, check SLACheck) string {
+	switch check.State {
+	case SLABreached:
+		return l.T("sla.state_breached", check.Days)
+	case SLAMissed:
+		return l.T("sla.state_missed", check.Days)
+	case SLAUpcoming:
+		return l.T("sla.state_upcoming", check.Days)
+	case SLAOnTrack:
+		return l.T("sla.state_on_track", check.Days)
+	}
+	return slaStateText(check)
+}
+
+func slaStateText(check SLACheck) string {
+	switch check.State {
+	case SLABreached:
+		return fmt.Sprintf("breached, %dd overdue", check.Days)
+	case SLAMissed:
+		return fmt.Sprintf("missed by %dd", check.Days)
+	case SLAUpcoming, SLAOnTrack:
+		return fmt.Sprintf("%s, due in %dd", check.State, check.Days)
+	}
+	return check.State
+}
@@ -0,0 +1,77 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSLADeadline(t *testing.T) {
+	friday := time.Date(2026, 10, 9, 10, 0, 0, 0, time.Local)
+	if got := (SLAPolicy{}).deadline(friday, 1); got.Format("2006-01-02 15:04") != "2026-10-12 23:59" {
+		t.Errorf("one business day after Friday should end on Monday, got %s", got)
+	}
+	if got := (SLAPolicy{CalendarDays: true}).deadline(friday, 1); got.Format("2006-01-02") != "2026-10-10" {
+		t.Errorf("calendar days should count the weekend, got %s", got)
+	}
+}
+
+func TestEvaluateSLA(t *testing.T) {
+	policy := SLAPolicy{TriageDays: 5, ResolveDays: 20, WarnDays: 3}
+	items := []FeedbackItem{
+		{ID: "UC001", Title: "Ignored", Status: "open", CreatedAt: "2026-10-05"},
+		{ID: "UC002", Title: "Fresh", Status: "open", CreatedAt: "2026-10-09T08:00:00Z"},
+		{ID: "UC003", Title: "Triaged", Status: "planned", CreatedAt: "2026-10-01", TriagedAt: "2026-10-02"},
+		{ID: "UC004", Title: "Declined late", Status: "declined", CreatedAt: "2026-09-01", TriagedAt: "2026-09-02", ResolvedAt: "2026-09-30"},
+		{ID: "UC005", Title: "No date", Status: "open"},
+	}
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)
+	checks := EvaluateSLA("voc", policy, items, now)
+
+	states := map[string]string{}
+	for _, check := range checks {
+		states[check.ItemID+"/"+check.Target] = slaStateText(check)
+	}
+	expected := map[string]string{
+		"UC001/triage":  "breached, 2d overdue",
+		"UC002/triage":  "upcoming",
+		"UC003/triage":  "met",
+		"UC004/triage":  "met",
+		"UC004/resolve": "missed by 1d",
+	}
+	for key, want := range expected {
+		if !strings.HasPrefix(states[key], want) {
+			t.Errorf("%s: got %q, want %q", key, states[key], want)
+		}
+	}
+	if _, ok := states["UC005/triage"]; ok {
+		t.Error("items without a creation date must not be tracked")
+	}
+
+	metrics := SummarizeSLA(checks)
+	if len(metrics) != 2 || metrics[0].Target != "triage" {
+		t.Fatalf("unexpected metrics %+v", metrics)
+	}
+	triage := metrics[0]
+	if triage.Met != 2 || triage.Breached != 1 || triage.Upcoming != 1 || triage.Compliance() < 0.66 || triage.Compliance() > 0.67 {
+		t.Errorf("unexpected triage metrics %+v", triage)
+	}
+
+	var report strings.Builder
+	generateSLAReport(&report, GetLocale("en"), checks)
+	if !strings.Contains(report.String(), "| VOC | triage | 67% |") || !strings.Contains(report.String(), "| UC001 | Ignored | triage | 2026-10-12 |") {
+		t.Errorf("unexpected SLA report:\n%s", report.String())
+	}
+}
+
+func TestUpdateKeepsCreationDate(t *testing.T) {
+	content := generateFeedbackMarkdown(FeedbackItemParams{ID: "UC001", Title: "Dark mode", Area: "voc", Status: "planned", Created: "2026-01-05", Triaged: "2026-01-06"})
+	params := parseExistingItem(content)
+	if params.Created != "2026-01-05" || params.Triaged != "2026-01-06" {
+		t.Errorf("SLA dates lost on update: %+v", params)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"portunix.ai/app/container"
+)
+
+// statusWatchDebounce groups the events of containers restarting together
+// into one status refresh
+const statusWatchDebounce = 2 * time.Second
+
+// StatusUpdate is a line of `pft status --watch --json`: a container event
+// or the status after events
+type StatusUpdate struct {
+	Event  *container.Event `json:"event,omitempty"`
+	Status *StatusReport    `json:"status,omitempty"`
+}
+
+// stackContainerPatterns returns name patterns of the containers of a
+// provider's stack, including its instances
+func stackContainerPatterns(provider string) []string {
+	if provider == "email" {
+		return []string{"pft-mailhog"}
+	}
+	return []string{provider + "-*"}
+}
+
+// printStatusUpdate prints an event or a status refresh of the watch
+func printStatusUpdate(update StatusUpdate, jsonOutput bool) {
+	if jsonOutput {
+		data, _ := json.Marshal(update)
+		fmt.Println(string(data))
+		return
+	}
+	if e := update.Event; e != nil {
+		line := fmt.Sprintf("[%s] %s %s", e.Time.Local().Format("15:04:05"), e.Name, e.Action)
+		if e.ExitCode != nil {
+			line += fmt.Sprintf(" (exit code %d)", *e.ExitCode)
+		}
+		fmt.Println(line)
+		return
+	}
+
+	report := update.Status
+	state := "healthy"
+	if !report.Healthy {
+		state = "UNHEALTHY"
+	}
+	fmt.Printf("[%s] Infrastructure: %s, %s\n", report.CheckedAt.Local().Format("15:04:05"), report.Infrastructure, state)
+	for _, p := range report.Probes {
+		if !p.Healthy {
+			fmt.Printf("  ✗ %-5s %-10s %s (%s)\n", p.Name, p.Provider, p.URL, p.Error)
+		}
+	}
+}
+
+// WatchStatus prints the status of the local deployment and refreshes it
+// whenever a container of the stack starts, stops, dies or runs out of
+// memory, until interrupted. Events come from `portunix container events`.
+func WatchStatus(config *Config, jsonOutput bool) error {
+	if config.Remote != "" {
+		return fmt.Errorf("container events of the deployment on %s cannot be watched; run 'portunix pft status' instead", config.Remote)
+	}
+	portunixPath, err := findPortunix()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	args := []string{"container", "events", "--json"}
+	for _, pattern := range stackContainerPatterns(config.GetProvider()) {
+		args = append(args, "--filter", "name="+pattern)
+	}
+	cmd := exec.CommandContext(ctx, portunixPath, args...)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to watch container events: %w", err)
+	}
+
+	events := make(chan *container.Event)
+	go func() {
+		defer close(events)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			var e container.Event
+			if json.Unmarshal(scanner.Bytes(), &e) == nil && e.Action != "" {
+				events <- &e
+			}
+		}
+	}()
+
+	if !jsonOutput {
+		fmt.Printf("Watching %s containers (Ctrl+C to stop)...\n", config.GetProvider())
+	}
+	printStatusUpdate(StatusUpdate{Status: BuildStatusReport(config)}, jsonOutput)
+
+	refresh := time.NewTimer(statusWatchDebounce)
+	refresh.Stop()
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				err := cmd.Wait()
+				if ctx.Err() != nil {
+					return nil
+				}
+				if err != nil {
+					return fmt.Errorf("container events stopped: %w", err)
+				}
+				return fmt.Errorf("container events stopped")
+			}
+			printStatusUpdate(StatusUpdate{Event: e}, jsonOutput)
+			refresh.Reset(statusWatchDebounce)
+		case <-refresh.C:
+			printStatusUpdate(StatusUpdate{Status: BuildStatusReport(config)}, jsonOutput)
+		case <-ctx.Done():
+			// Wait for the events process and the reader to end
+			for range events {
+			}
+			cmd.Wait()
+			return nil
+		}
+	}
+}
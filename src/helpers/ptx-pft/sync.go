@@ -10,6 +10,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -20,83 +22,79 @@ func ParseMarkdownFile(filePath string) (*FeedbackItem, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
+	return parseMarkdownContent(filePath, content)
+}
 
+// parseMarkdownContent parses the content of a feedback markdown file
+func parseMarkdownContent(filePath string, content []byte) (*FeedbackItem, error) {
 	item := &FeedbackItem{
 		ID:       strings.TrimSuffix(filepath.Base(filePath), ".md"),
 		FilePath: filePath,
 	}
 
-	contentStr := string(content)
-
 	// Parse YAML frontmatter if present
-	if strings.HasPrefix(contentStr, "---") {
-		endIndex := strings.Index(contentStr[3:], "---")
-		if endIndex != -1 {
-			frontmatter := contentStr[3 : endIndex+3]
-			lines := strings.Split(frontmatter, "\n")
-
-			var currentArrayField string
-			for _, line := range lines {
-				line = strings.TrimSpace(line)
-				if line == "" {
-					continue
-				}
-
-				// Check if this is an array item
-				if strings.HasPrefix(line, "- ") {
-					value := strings.TrimPrefix(line, "- ")
-					switch currentArrayField {
-					case "categories":
-						item.Categories = append(item.Categories, value)
-					case "tags":
-						item.Tags = append(item.Tags, value)
-					}
-					continue
-				}
-
-				// Parse key: value
-				parts := strings.SplitN(line, ":", 2)
-				if len(parts) != 2 {
-					continue
-				}
-
-				key := strings.TrimSpace(parts[0])
-				value := strings.TrimSpace(parts[1])
+	fm, body := ParseFrontmatter(string(content))
+	if fm != nil {
+		for _, key := range fm.Keys() {
+			switch key {
+			case "categories":
+				item.Categories = append(item.Categories, fm.List(key)...)
+				continue
+			case "tags":
+				item.Tags = append(item.Tags, fm.List(key)...)
+				continue
+			case "products":
+				item.Products = append(item.Products, fm.List(key)...)
+				continue
+			}
 
-				// Check if this starts an array
-				if value == "" {
-					currentArrayField = key
-					continue
-				}
-				currentArrayField = ""
-
-				switch key {
-				case "id":
-					item.ID = value
-				case "title":
-					item.Title = value
-				case "status":
-					item.Status = value
-				case "priority":
-					item.Priority = value
-				case "category":
-					// Single category field - add to categories slice
-					if value != "" {
-						item.Categories = append(item.Categories, value)
-					}
-				case "external_id":
-					item.ExternalID = value
-				case "created_at":
-					item.CreatedAt = value
-				case "updated_at":
-					item.UpdatedAt = value
+			value := fm.Get(key)
+			if value == "" {
+				continue
+			}
+			switch key {
+			case "id":
+				item.ID = value
+			case "title":
+				item.Title = value
+			case "status":
+				item.Status = value
+			case "priority":
+				item.Priority = value
+			case "category":
+				// Single category field - add to categories slice
+				item.Categories = append(item.Categories, value)
+			case "external_id":
+				item.ExternalID = value
+			case "created_at", "created":
+				item.CreatedAt = value
+			case "updated_at", "updated":
+				item.UpdatedAt = value
+			case "triaged_at", "triaged":
+				item.TriagedAt = value
+			case "resolved_at", "resolved":
+				item.ResolvedAt = value
+			case "linked_issue":
+				item.LinkedIssue = value
+			case "assignee":
+				item.Assignee = value
+			case "estimate":
+				item.Estimate = value
+			case "actual_effort":
+				item.ActualEffort = value
+			case "votes":
+				item.Votes, _ = strconv.Atoi(value)
+			case "sentiment", "urgency", "scored_by", "scored_at":
+				if item.Scores == nil {
+					item.Scores = &ItemScores{}
 				}
+				item.Scores.setField(key, value)
 			}
 		}
 	}
 
 	// Also parse markdown sections for backward compatibility
-	scanner := bufio.NewScanner(strings.NewReader(contentStr))
+	scanner := bufio.NewScanner(strings.NewReader(body))
 	var currentSection string
 	var descriptionLines []string
 	var inDescription bool
@@ -104,6 +102,12 @@ func ParseMarkdownFile(filePath string) (*FeedbackItem, error) {
 	for scanner.Scan() {
 		line := scanner.Text()
 
+		// Files without frontmatter carry the issue link as a comment
+		if issue, ok := strings.CutPrefix(line, "<!-- linked_issue:"); ok && item.LinkedIssue == "" {
+			item.LinkedIssue = strings.TrimSpace(strings.TrimSuffix(issue, "-->"))
+			continue
+		}
+
 		// Parse main title (# heading)
 		if strings.HasPrefix(line, "# ") && item.Title == "" {
 			item.Title = strings.TrimPrefix(line, "# ")
@@ -113,6 +117,9 @@ func ParseMarkdownFile(filePath string) (*FeedbackItem, error) {
 		// Parse section headers
 		if strings.HasPrefix(line, "## ") {
 			currentSection = strings.TrimPrefix(line, "## ")
+			if isLocalizedHeading(currentSection, "item.description") {
+				currentSection = "Description"
+			}
 			inDescription = currentSection == "Description"
 			continue
 		}
@@ -152,6 +159,14 @@ func ParseMarkdownFile(filePath string) (*FeedbackItem, error) {
 			}
 		case "Description":
 			descriptionLines = append(descriptionLines, line)
+		case "Metadata":
+			// Pulled items record the vote count as "- Votes: N"
+			if votes, ok := strings.CutPrefix(trimmedLine, "- Votes:"); ok && item.Votes == 0 {
+				item.Votes, _ = strconv.Atoi(strings.TrimSpace(votes))
+			}
+			if created, ok := strings.CutPrefix(trimmedLine, "- Created:"); ok && item.CreatedAt == "" {
+				item.CreatedAt = strings.TrimSpace(created)
+			}
 		}
 	}
 
@@ -836,56 +851,56 @@ func PrintConflicts(conflicts []SyncConflict) {
 	}
 }
 
-// UpdateFileCategories updates categories in YAML frontmatter
-func UpdateFileCategories(filePath string, categories []string) error {
+// UpdateFileFields sets scalar fields in YAML frontmatter, replacing
+// existing values; an empty value removes the field. A file without
+// frontmatter gets one.
+func UpdateFileFields(filePath string, fields map[string]string) error {
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return err
 	}
 
-	contentStr := string(content)
-
-	// Check if file has YAML frontmatter
-	if !strings.HasPrefix(contentStr, "---") {
-		return fmt.Errorf("file does not have YAML frontmatter")
+	fm, body := ParseFrontmatter(string(content))
+	if fm == nil {
+		fm, body = NewFrontmatter(), "\n"+body
 	}
-
-	// Find end of frontmatter
-	endIndex := strings.Index(contentStr[3:], "---")
-	if endIndex == -1 {
-		return fmt.Errorf("invalid YAML frontmatter (no closing ---)")
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fm.Set(key, fields[key])
 	}
 
-	frontmatter := contentStr[3 : endIndex+3]
-	afterFrontmatter := contentStr[endIndex+6:] // Skip past "---\n"
-
-	// Remove old 'category:' line (singular) from frontmatter
-	frontmatter = regexp.MustCompile(`(?m)^category:.*\n?`).ReplaceAllString(frontmatter, "")
+	return os.WriteFile(filePath, []byte(fm.String()+body), 0644)
+}
 
-	// Remove old 'categories:' block from frontmatter (including array items)
-	frontmatter = regexp.MustCompile(`(?m)^categories:\s*\n(  - .*\n)*`).ReplaceAllString(frontmatter, "")
-	frontmatter = regexp.MustCompile(`(?m)^categories:.*\n?`).ReplaceAllString(frontmatter, "")
+// UpdateFileCategories updates categories in YAML frontmatter
+func UpdateFileCategories(filePath string, categories []string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
 
-	// Add new categories as YAML array (if any)
-	if len(categories) > 0 {
-		var categoriesYAML strings.Builder
-		categoriesYAML.WriteString("categories:\n")
-		for _, cat := range categories {
-			categoriesYAML.WriteString("  - " + cat + "\n")
-		}
-		// Add before closing of frontmatter (at the end)
-		frontmatter = strings.TrimRight(frontmatter, "\n") + "\n" + categoriesYAML.String()
+	fm, body := ParseFrontmatter(string(content))
+	if fm == nil {
+		return fmt.Errorf("file does not have YAML frontmatter")
 	}
 
-	// Remove ## Categories markdown section if exists (clean up old format)
-	afterFrontmatter = regexp.MustCompile(`(?m)^## Categories\n[^\n#]*\n?`).ReplaceAllString(afterFrontmatter, "")
-	// Remove duplicate empty lines that might result
-	afterFrontmatter = regexp.MustCompile(`\n{3,}`).ReplaceAllString(afterFrontmatter, "\n\n")
+	// Replace the old 'category:' field (singular) with the 'categories:' list
+	fm.Delete("category")
+	fm.SetList("categories", categories)
 
-	// Rebuild file
-	result := "---" + frontmatter + "---" + afterFrontmatter
+	return os.WriteFile(filePath, []byte(fm.String()+removeCategoriesSection(body)), 0644)
+}
 
-	return os.WriteFile(filePath, []byte(result), 0644)
+// removeCategoriesSection removes the ## Categories markdown section of the
+// old format, whose categories now live in the frontmatter
+func removeCategoriesSection(body string) string {
+	body = regexp.MustCompile(`(?m)^## Categories\n[^\n#]*\n?`).ReplaceAllString(body, "")
+	// Remove duplicate empty lines that might result
+	return regexp.MustCompile(`\n{3,}`).ReplaceAllString(body, "\n\n")
 }
 
 // AddCategoryToFile adds a category to a file's Categories section
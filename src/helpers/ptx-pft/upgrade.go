@@ -0,0 +1,340 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultUpgradeTimeout is how long an upgrade waits for the tool to become healthy
+const DefaultUpgradeTimeout = 3 * time.Minute
+
+// upgradeTarget describes a deployment that can be upgraded
+type upgradeTarget struct {
+	Provider     string
+	ProjectName  string
+	ComposeFile  string
+	EnvFile      string
+	DeployDir    func() (string, error)
+	WriteCompose func(deployDir string, config *Config) (string, error)
+	WriteEnv     func(deployDir string, config *Config) (string, error)
+	AppServices  []string // Services whose tag --version sets
+	DBService    string
+	DumpCommand  string // Shell command in DBService writing a dump to stdout
+	DefaultURL   string
+}
+
+var upgradeTargets = map[string]*upgradeTarget{
+	"fider": {
+		Provider:     "fider",
+		ProjectName:  fiderProjectName,
+		ComposeFile:  fiderComposeFile,
+		EnvFile:      fiderEnvFile,
+		DeployDir:    getDeployDir,
+		WriteCompose: writeComposeFile,
+		WriteEnv:     writeEnvFile,
+		AppServices:  []string{"fider"},
+		DBService:    "db",
+		DumpCommand:  `pg_dump -U fider --clean --if-exists fider`,
+		DefaultURL:   "http://localhost:3000",
+	},
+	"clearflask": {
+		Provider:     "clearflask",
+		ProjectName:  clearflaskProjectName,
+		ComposeFile:  clearflaskComposeFile,
+		EnvFile:      clearflaskEnvFile,
+		DeployDir:    getClearFlaskDeployDir,
+		WriteCompose: writeClearFlaskComposeFile,
+		WriteEnv:     writeClearFlaskEnvFile,
+		AppServices:  []string{"clearflask-server", "clearflask-connect"},
+		DBService:    "mariadb",
+		DumpCommand:  `mysqldump -uroot -p"$MYSQL_ROOT_PASSWORD" --single-transaction --databases clearflask`,
+		DefaultURL:   "http://localhost:3100",
+	},
+}
+
+// UpgradeOptions controls an upgrade
+type UpgradeOptions struct {
+	Images     map[string]string // Service to image
+	Version    string            // Tag of the application services
+	SkipBackup bool
+	PinOnly    bool // Save the pins without upgrading
+	DryRun     bool
+	Timeout    time.Duration
+}
+
+// ImageChange is a service whose image an upgrade changes
+type ImageChange struct {
+	Service string
+	From    string
+	To      string
+}
+
+// UpgradeResult contains upgrade information
+type UpgradeResult struct {
+	Changes     []ImageChange
+	BackupFile  string
+	ComposeFile string // Compose file before the upgrade
+	URL         string
+}
+
+// imageWithTag replaces the tag of an image reference
+func imageWithTag(image, tag string) string {
+	if at := strings.Index(image, "@"); at >= 0 {
+		image = image[:at]
+	}
+	if colon := strings.LastIndex(image, ":"); colon > strings.LastIndex(image, "/") {
+		image = image[:colon]
+	}
+	return image + ":" + tag
+}
+
+// planImageUpgrade pins the requested images in config and returns the
+// services whose image changes. services maps the services of the stack to
+// their default images.
+func planImageUpgrade(services map[string]string, target *upgradeTarget, config *Config, opts UpgradeOptions) ([]ImageChange, error) {
+	requested := make(map[string]string)
+	if opts.Version != "" {
+		for _, service := range target.AppServices {
+			image, ok := services[service]
+			if !ok {
+				return nil, fmt.Errorf("service '%s' not found in the %s stack template", service, target.Provider)
+			}
+			requested[service] = imageWithTag(config.GetImage(target.Provider, service, image), opts.Version)
+		}
+	}
+	for service, image := range opts.Images {
+		if _, ok := services[service]; !ok {
+			return nil, fmt.Errorf("unknown service '%s' (available: %s)", service, strings.Join(sortedKeys(services), ", "))
+		}
+		requested[service] = image
+	}
+
+	var changes []ImageChange
+	for service, image := range requested {
+		current := config.GetImage(target.Provider, service, services[service])
+		config.PinImage(target.Provider, service, image)
+		if image != current {
+			changes = append(changes, ImageChange{Service: service, From: current, To: image})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Service < changes[j].Service })
+	return changes, nil
+}
+
+// composeCommand returns a portunix container compose command of the target
+func (t *upgradeTarget) composeCommand(deployDir string, args ...string) (*exec.Cmd, error) {
+	portunixPath, err := findPortunix()
+	if err != nil {
+		return nil, err
+	}
+
+	fullArgs := []string{
+		"container", "compose",
+		"-f", filepath.Join(deployDir, t.ComposeFile),
+		"--env-file", filepath.Join(deployDir, t.EnvFile),
+		"-p", t.ProjectName,
+	}
+	cmd := exec.Command(portunixPath, append(fullArgs, args...)...)
+	cmd.Dir = deployDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd, nil
+}
+
+// backupDatabase dumps the database of the running deployment and keeps a
+// copy of the compose file, both in <deployDir>/backups
+func (t *upgradeTarget) backupDatabase(deployDir string) (string, string, error) {
+	backupDir := filepath.Join(deployDir, "backups")
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		return "", "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	stamp := time.Now().Format("20060102-150405")
+
+	composeData, err := os.ReadFile(filepath.Join(deployDir, t.ComposeFile))
+	if err != nil {
+		return "", "", err
+	}
+	composeBackup := filepath.Join(backupDir, fmt.Sprintf("docker-compose-%s.yaml", stamp))
+	if err := os.WriteFile(composeBackup, composeData, 0644); err != nil {
+		return "", "", fmt.Errorf("failed to back up compose file: %w", err)
+	}
+
+	dumpPath := filepath.Join(backupDir, fmt.Sprintf("%s-%s.sql", t.Provider, stamp))
+	dump, err := os.OpenFile(dumpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create backup file: %w", err)
+	}
+	cmd, err := t.composeCommand(deployDir, "exec", "-T", t.DBService, "sh", "-c", t.DumpCommand)
+	if err != nil {
+		dump.Close()
+		os.Remove(dumpPath)
+		return "", "", err
+	}
+	cmd.Stdout = dump
+	err = cmd.Run()
+	if closeErr := dump.Close(); err == nil {
+		err = closeErr
+	}
+	if info, statErr := os.Stat(dumpPath); err == nil && (statErr != nil || info.Size() == 0) {
+		err = fmt.Errorf("dump is empty")
+	}
+	if err != nil {
+		os.Remove(dumpPath)
+		return "", "", fmt.Errorf("database backup failed: %w", err)
+	}
+
+	return dumpPath, composeBackup, nil
+}
+
+// waitForHealthy polls url until it answers with a success status
+func waitForHealthy(url string, timeout, interval time.Duration) error {
+	client := &http.Client{Timeout: 5 * time.Second}
+	deadline := time.Now().Add(timeout)
+	lastErr := fmt.Errorf("no response")
+	for {
+		resp, err := client.Get(url)
+		if err == nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode < 400 {
+				return nil
+			}
+			lastErr = fmt.Errorf("HTTP %d", resp.StatusCode)
+		} else {
+			lastErr = err
+		}
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("%s not healthy after %s: %v", url, timeout, lastErr)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// Upgrade upgrades the local deployment of the configured provider: the
+// requested images are pinned in the configuration, the database is backed
+// up, the new images are pulled and started with compose, and the tool is
+// checked to be healthy. Database migrations run when the new version
+// starts, so the backup is the way back if the upgrade fails.
+func Upgrade(config *Config, configPath string, opts UpgradeOptions) (*UpgradeResult, error) {
+	provider := config.GetProvider()
+	target, ok := upgradeTargets[provider]
+	if !ok {
+		return nil, fmt.Errorf("provider '%s' cannot be upgraded (supported: fider, clearflask)", provider)
+	}
+
+	services, err := stackServices(provider)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := planImageUpgrade(services, target, config, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &UpgradeResult{Changes: changes}
+
+	if len(changes) == 0 {
+		fmt.Println("Images: unchanged (current tags are pulled again)")
+	} else {
+		fmt.Println("Images:")
+		for _, change := range changes {
+			fmt.Printf("  %s: %s → %s\n", change.Service, change.From, change.To)
+		}
+	}
+	fmt.Println()
+
+	if opts.DryRun {
+		return result, nil
+	}
+	if opts.PinOnly {
+		return result, config.SaveToPath(configPath)
+	}
+	if config.Remote != "" {
+		return nil, fmt.Errorf("%s is deployed to %s; pin the images with --pin-only and run 'portunix pft deploy --remote %s'", provider, config.Remote, config.Remote)
+	}
+
+	deployDir, err := target.DeployDir()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(deployDir, target.ComposeFile)); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s is not deployed; run 'portunix pft deploy' first", provider)
+	}
+
+	if opts.SkipBackup {
+		fmt.Println("⚠ Skipping database backup")
+	} else {
+		fmt.Println("Backing up database...")
+		dumpPath, composeBackup, err := target.backupDatabase(deployDir)
+		if err != nil {
+			return nil, fmt.Errorf("%w (use --skip-backup to upgrade without a backup)", err)
+		}
+		result.BackupFile, result.ComposeFile = dumpPath, composeBackup
+		fmt.Printf("  ✓ %s\n", dumpPath)
+	}
+
+	if _, err := target.WriteCompose(deployDir, config); err != nil {
+		return nil, err
+	}
+	if _, err := target.WriteEnv(deployDir, config); err != nil {
+		return nil, err
+	}
+
+	fmt.Println()
+	fmt.Println("Pulling container images...")
+	cmd, err := target.composeCommand(deployDir, "pull")
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to pull images: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Println("Restarting services...")
+	if cmd, err = target.composeCommand(deployDir, "up", "-d"); err != nil {
+		return nil, err
+	}
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to start services: %w", err)
+	}
+
+	// The new images are running, so record them even if unhealthy
+	if err := config.SaveToPath(configPath); err != nil {
+		return nil, fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	result.URL = config.GetEndpoint()
+	if result.URL == "" {
+		result.URL = target.DefaultURL
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultUpgradeTimeout
+	}
+	fmt.Println()
+	fmt.Printf("Waiting for %s to become healthy...\n", provider)
+	healthURL := result.URL
+	if path := healthPaths[provider]; path != "" {
+		healthURL = strings.TrimSuffix(result.URL, "/") + path
+	}
+	if err := waitForHealthy(healthURL, timeout, 5*time.Second); err != nil {
+		if result.BackupFile != "" {
+			return result, fmt.Errorf("%w\nTo roll back, restore %s as %s and the database from %s",
+				err, result.ComposeFile, filepath.Join(deployDir, target.ComposeFile), result.BackupFile)
+		}
+		return result, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,83 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestImageWithTag(t *testing.T) {
+	tests := []struct{ image, want string }{
+		{"getfider/fider:stable", "getfider/fider:v0.25.0"},
+		{"getfider/fider", "getfider/fider:v0.25.0"},
+		{"registry.example.com:5000/fider", "registry.example.com:5000/fider:v0.25.0"},
+		{"getfider/fider@sha256:abc", "getfider/fider:v0.25.0"},
+	}
+	for _, tt := range tests {
+		if got := imageWithTag(tt.image, "v0.25.0"); got != tt.want {
+			t.Errorf("imageWithTag(%s) = %s, want %s", tt.image, got, tt.want)
+		}
+	}
+}
+
+func TestPlanImageUpgrade(t *testing.T) {
+	services, err := stackServices("fider")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := upgradeTargets["fider"]
+	config := &Config{}
+
+	changes, err := planImageUpgrade(services, target, config, UpgradeOptions{Version: "v0.25.0", Images: map[string]string{"db": "postgres:15-alpine"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].From != "getfider/fider:stable" || changes[0].To != "getfider/fider:v0.25.0" {
+		t.Errorf("unexpected changes %+v", changes)
+	}
+	if config.GetImage("fider", "db", "") != "postgres:15-alpine" {
+		t.Error("unchanged image must be pinned as well")
+	}
+
+	// Pins replace the images of generated compose files
+	stack, err := RenderStack(StackSpec{Template: "fider"}, config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(stack.YAML), "image: getfider/fider:v0.25.0") {
+		t.Errorf("pin not applied:\n%s", stack.YAML)
+	}
+
+	if _, err := planImageUpgrade(services, target, config, UpgradeOptions{Images: map[string]string{"web": "nginx"}}); err == nil {
+		t.Error("unknown service must be rejected")
+	}
+}
+
+func TestWaitForHealthy(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	if err := waitForHealthy(server.URL+"/_health", time.Second, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 probes, got %d", calls.Load())
+	}
+
+	calls.Store(-1000)
+	if err := waitForHealthy(server.URL, 50*time.Millisecond, 10*time.Millisecond); err == nil {
+		t.Error("unhealthy server must time out")
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"portunix.ai/app/fileutil"
 )
 
 // RoleAssignment represents a role in a specific category
@@ -77,7 +79,7 @@ func SaveUserRegistry(projectDir string, registry *UserRegistry) error {
 		return fmt.Errorf("failed to marshal users: %w", err)
 	}
 
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
+	if err := fileutil.WriteFile(filePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write users.json: %w", err)
 	}
 
@@ -0,0 +1,425 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"crypto/tls"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// DefaultLDAPFilter selects directory entries imported as users
+const DefaultLDAPFilter = "(&(objectClass=person)(mail=*))"
+
+// UserImportRecord is a user read from a CSV file or a directory
+type UserImportRecord struct {
+	ID           string
+	Name         string
+	Organization string
+	// Roles given explicitly per area, e.g. by a "vos" CSV column
+	Roles map[string]string
+	// Attributes by lower-case name, matched by role mapping rules
+	Attributes map[string][]string
+}
+
+// RoleMappingRule assigns a role to imported users with a matching attribute
+type RoleMappingRule struct {
+	Attribute string // Empty matches every user
+	Pattern   string // Case-insensitive, '*' matches any text
+	Area      string
+	Role      string
+	Proxy     bool
+}
+
+// ParseRoleMappingRule parses "<attribute>=<pattern>:<area>=<role>[:proxy]",
+// or "<area>=<role>[:proxy]" for a role of every imported user
+func ParseRoleMappingRule(spec string) (RoleMappingRule, error) {
+	var rule RoleMappingRule
+	rest := spec
+	if trimmed, ok := strings.CutSuffix(rest, ":proxy"); ok {
+		rule.Proxy = true
+		rest = trimmed
+	}
+
+	target := rest
+	if idx := strings.LastIndex(rest, ":"); idx != -1 {
+		condition := rest[:idx]
+		target = rest[idx+1:]
+		attribute, pattern, ok := strings.Cut(condition, "=")
+		if !ok || strings.TrimSpace(attribute) == "" || pattern == "" {
+			return rule, fmt.Errorf("invalid rule '%s': condition must be <attribute>=<pattern>", spec)
+		}
+		rule.Attribute = strings.ToLower(strings.TrimSpace(attribute))
+		rule.Pattern = pattern
+	}
+
+	area, role, ok := strings.Cut(target, "=")
+	area = strings.ToLower(strings.TrimSpace(area))
+	role = strings.TrimSpace(role)
+	if !ok || role == "" || !IsValidArea(area) {
+		return rule, fmt.Errorf("invalid rule '%s': target must be <area>=<role> with area %s", spec, strings.Join(ValidAreaNames, ", "))
+	}
+	rule.Area = area
+	rule.Role = role
+	return rule, nil
+}
+
+// Matches checks if the rule applies to a record
+func (r RoleMappingRule) Matches(record UserImportRecord) bool {
+	if r.Attribute == "" {
+		return true
+	}
+	re := globRegexp(r.Pattern)
+	for _, value := range record.Attributes[r.Attribute] {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// globRegexp converts a pattern where '*' matches any text
+func globRegexp(pattern string) *regexp.Regexp {
+	quoted := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	return regexp.MustCompile("(?i)^" + quoted + "$")
+}
+
+// ReadUsersCSV reads users from CSV with a header row. The user ID is taken
+// from an "id", "email" or "mail" column, the name from "name" or
+// "display_name", the organization from "organization", "org" or "company".
+// Columns named after an area (voc, vos, vob, voe) assign that role. All
+// columns are available to role mapping rules; ';' separates multiple values.
+// Files exported with ';' as the delimiter are detected.
+func ReadUsersCSV(r io.Reader) ([]UserImportRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	content := strings.TrimPrefix(string(data), "\ufeff") // Excel writes a BOM
+
+	reader := csv.NewReader(strings.NewReader(content))
+	header, _, _ := strings.Cut(content, "\n")
+	if strings.Count(header, ";") > strings.Count(header, ",") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+
+	columns := make([]string, len(rows[0]))
+	for i, name := range rows[0] {
+		columns[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	first := func(attributes map[string][]string, names ...string) string {
+		for _, name := range names {
+			if values := attributes[name]; len(values) > 0 {
+				return values[0]
+			}
+		}
+		return ""
+	}
+	if !hasColumn(columns, "id", "email", "mail") {
+		return nil, fmt.Errorf("CSV header must have an id, email or mail column")
+	}
+
+	var records []UserImportRecord
+	for line, row := range rows[1:] {
+		record := UserImportRecord{
+			Roles:      make(map[string]string),
+			Attributes: make(map[string][]string),
+		}
+		for i, value := range row {
+			if i >= len(columns) || strings.TrimSpace(value) == "" {
+				continue
+			}
+			for _, part := range strings.Split(value, ";") {
+				if part = strings.TrimSpace(part); part != "" {
+					record.Attributes[columns[i]] = append(record.Attributes[columns[i]], part)
+				}
+			}
+			if IsValidArea(columns[i]) {
+				record.Roles[columns[i]] = strings.TrimSpace(value)
+			}
+		}
+		record.ID = first(record.Attributes, "id", "email", "mail")
+		if record.ID == "" {
+			fmt.Printf("   ⚠ Line %d: no user ID, skipped\n", line+2)
+			continue
+		}
+		record.Name = first(record.Attributes, "name", "display_name", "displayname")
+		record.Organization = first(record.Attributes, "organization", "org", "company")
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func hasColumn(columns []string, names ...string) bool {
+	for _, column := range columns {
+		for _, name := range names {
+			if column == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LDAPImportOptions selects the directory entries imported as users
+type LDAPImportOptions struct {
+	URL          string // ldap:// or ldaps://
+	StartTLS     bool
+	BindDN       string
+	BindPassword string
+	BaseDN       string
+	Filter       string
+	Attributes   []string // Extra attributes used by role mapping rules
+}
+
+// ldapUserAttributes are read from every entry
+var ldapUserAttributes = []string{"mail", "userPrincipalName", "displayName", "cn", "company", "o", "department", "title", "memberOf"}
+
+// ReadUsersLDAP reads users from an LDAP or Active Directory server. The
+// user ID is the mail or userPrincipalName attribute; entries without one
+// are skipped.
+func ReadUsersLDAP(opts LDAPImportOptions) ([]UserImportRecord, error) {
+	if opts.BaseDN == "" {
+		return nil, fmt.Errorf("base DN is required")
+	}
+	if opts.Filter == "" {
+		opts.Filter = DefaultLDAPFilter
+	}
+
+	conn, err := ldap.DialURL(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", opts.URL, err)
+	}
+	defer conn.Close()
+
+	if opts.StartTLS {
+		serverName := ""
+		if u, err := url.Parse(opts.URL); err == nil {
+			serverName = u.Hostname()
+		}
+		if err := conn.StartTLS(&tls.Config{ServerName: serverName}); err != nil {
+			return nil, fmt.Errorf("StartTLS failed: %w", err)
+		}
+	}
+	if opts.BindDN != "" {
+		if err := conn.Bind(opts.BindDN, opts.BindPassword); err != nil {
+			return nil, fmt.Errorf("bind as %s failed: %w", opts.BindDN, err)
+		}
+	}
+
+	request := ldap.NewSearchRequest(opts.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, 0, false, opts.Filter, append(append([]string{}, ldapUserAttributes...), opts.Attributes...), nil)
+	result, err := conn.SearchWithPaging(request, 500)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	var records []UserImportRecord
+	for _, entry := range result.Entries {
+		if record, ok := ldapEntryToRecord(entry); ok {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// ldapEntryToRecord converts a directory entry; "dn" holds its DN
+func ldapEntryToRecord(entry *ldap.Entry) (UserImportRecord, bool) {
+	record := UserImportRecord{
+		Roles:      make(map[string]string),
+		Attributes: map[string][]string{"dn": {entry.DN}},
+	}
+	for _, attribute := range entry.Attributes {
+		name := strings.ToLower(attribute.Name)
+		record.Attributes[name] = append(record.Attributes[name], attribute.Values...)
+	}
+
+	record.ID = firstNonEmpty(entry.GetAttributeValue("mail"), entry.GetAttributeValue("userPrincipalName"))
+	if record.ID == "" {
+		return record, false
+	}
+	record.Name = firstNonEmpty(entry.GetAttributeValue("displayName"), entry.GetAttributeValue("cn"), record.ID)
+	record.Organization = firstNonEmpty(entry.GetAttributeValue("company"), entry.GetAttributeValue("o"))
+	return record, true
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// UserImportResult counts the outcome of an import
+type UserImportResult struct {
+	Added     int
+	Updated   int
+	Unchanged int
+}
+
+// ImportUsers adds imported users to the registry and updates existing ones,
+// matched by ID case-insensitively. Explicit roles take precedence over
+// mapping rules, of which the first matching one per area applies; roles
+// not given by the import are kept. An explicit role missing from the
+// area's role file is reported and skipped; a rule with one is an error.
+func ImportUsers(projectDir string, registry *UserRegistry, records []UserImportRecord, rules []RoleMappingRule, dryRun bool) (UserImportResult, error) {
+	var result UserImportResult
+
+	validRoles := make(map[string]map[string]bool)
+	isValidRole := func(area, role string) bool {
+		if validRoles[area] == nil {
+			validRoles[area] = make(map[string]bool)
+			if roles, err := LoadRoles(projectDir, area); err == nil {
+				for _, definition := range roles.Roles {
+					validRoles[area][definition.ID] = true
+				}
+			}
+		}
+		return validRoles[area][role]
+	}
+
+	for _, rule := range rules {
+		if !isValidRole(rule.Area, rule.Role) {
+			return result, fmt.Errorf("unknown %s role '%s' in mapping rule", rule.Area, rule.Role)
+		}
+	}
+
+	for _, record := range records {
+		roles := make(map[string]RoleAssignment)
+		for _, area := range ValidAreaNames {
+			role, ok := record.Roles[area]
+			if !ok {
+				continue
+			}
+			if !isValidRole(area, role) {
+				fmt.Printf("   ⚠ %s: unknown %s role '%s', skipped\n", record.ID, area, role)
+				continue
+			}
+			roles[area] = RoleAssignment{Role: role}
+		}
+		for _, rule := range rules {
+			if _, assigned := roles[rule.Area]; !assigned && rule.Matches(record) {
+				roles[rule.Area] = RoleAssignment{Role: rule.Role, Proxy: rule.Proxy}
+			}
+		}
+
+		user := registry.FindUserByEmail(record.ID)
+		if user == nil {
+			name := firstNonEmpty(record.Name, record.ID)
+			if dryRun {
+				fmt.Printf("   ➕ Would add: %s (%s)%s\n", record.ID, name, formatImportRoles(roles))
+				result.Added++
+				continue
+			}
+			if err := registry.AddUser(User{ID: record.ID, Name: name, Organization: record.Organization}); err != nil {
+				return result, err
+			}
+			user = registry.FindUser(record.ID)
+			for _, area := range ValidAreaNames {
+				if assignment, ok := roles[area]; ok {
+					user.SetRole(area, assignment.Role, assignment.Proxy)
+				}
+			}
+			fmt.Printf("   ➕ Added: %s (%s)%s\n", record.ID, name, formatImportRoles(roles))
+			result.Added++
+			continue
+		}
+
+		var changes []string
+		if record.Name != "" && record.Name != user.Name {
+			changes = append(changes, "name")
+		}
+		if record.Organization != "" && record.Organization != user.Organization {
+			changes = append(changes, "organization")
+		}
+		for _, area := range ValidAreaNames {
+			assignment, ok := roles[area]
+			if !ok {
+				continue
+			}
+			current := user.roleAssignment(area)
+			if current == nil || *current != assignment {
+				changes = append(changes, area+" role")
+			}
+		}
+		if len(changes) == 0 {
+			result.Unchanged++
+			continue
+		}
+
+		result.Updated++
+		if dryRun {
+			fmt.Printf("   ✎ Would update: %s (%s)\n", user.ID, strings.Join(changes, ", "))
+			continue
+		}
+		registry.UpdateUser(user.ID, func(u *User) {
+			if record.Name != "" {
+				u.Name = record.Name
+			}
+			if record.Organization != "" {
+				u.Organization = record.Organization
+			}
+			for _, area := range ValidAreaNames {
+				if assignment, ok := roles[area]; ok {
+					u.SetRole(area, assignment.Role, assignment.Proxy)
+				}
+			}
+		})
+		fmt.Printf("   ✎ Updated: %s (%s)\n", user.ID, strings.Join(changes, ", "))
+	}
+
+	return result, nil
+}
+
+// roleAssignment returns the role of an area, nil if none
+func (u *User) roleAssignment(area string) *RoleAssignment {
+	switch area {
+	case "voc":
+		return u.Roles.VoC
+	case "vos":
+		return u.Roles.VoS
+	case "vob":
+		return u.Roles.VoB
+	case "voe":
+		return u.Roles.VoE
+	}
+	return nil
+}
+
+func formatImportRoles(roles map[string]RoleAssignment) string {
+	var parts []string
+	for _, area := range ValidAreaNames {
+		if assignment, ok := roles[area]; ok {
+			part := area + "=" + assignment.Role
+			if assignment.Proxy {
+				part += " (proxy)"
+			}
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " - " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,109 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestParseRoleMappingRule(t *testing.T) {
+	rule, err := ParseRoleMappingRule("memberOf=CN=Architects,OU=Groups,*:vos=architect:proxy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.Attribute != "memberof" || rule.Pattern != "CN=Architects,OU=Groups,*" || rule.Area != "vos" || rule.Role != "architect" || !rule.Proxy {
+		t.Errorf("unexpected rule %+v", rule)
+	}
+	if rule, err := ParseRoleMappingRule("voc=customer"); err != nil || rule.Attribute != "" || rule.Role != "customer" {
+		t.Errorf("unexpected rule %+v %v", rule, err)
+	}
+	for _, spec := range []string{"customer", "xyz=customer", "department:vos=support", "vos="} {
+		if _, err := ParseRoleMappingRule(spec); err == nil {
+			t.Errorf("%q must be rejected", spec)
+		}
+	}
+}
+
+func TestImportUsersFromCSV(t *testing.T) {
+	csvData := "\ufeffEmail;Name;Company;Department;VoS\n" +
+		"jana@example.com;Jana Nováková;Acme;Support;\n" +
+		"petr@example.com;Petr Svoboda;Acme;Development;tester\n" +
+		";No ID;;;\n" +
+		"EVA@example.com;Eva Dvořáková;;Sales;nonexistent\n"
+	records, err := ReadUsersCSV(strings.NewReader(csvData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0].Name != "Jana Nováková" || records[0].Organization != "Acme" {
+		t.Fatalf("unexpected records %+v", records)
+	}
+
+	registry := &UserRegistry{}
+	registry.AddUser(User{ID: "eva@example.com", Name: "Eva", Roles: UserRoles{VoC: &RoleAssignment{Role: "customer"}}})
+	rules := []RoleMappingRule{
+		{Attribute: "department", Pattern: "support", Area: "vos", Role: "support"},
+		{Area: "vos", Role: "developer"},
+	}
+
+	projectDir := t.TempDir()
+	if result, _ := ImportUsers(projectDir, registry, records, rules, true); result.Added != 2 || result.Updated != 1 || len(registry.Users) != 1 {
+		t.Errorf("dry run changed the registry or counted wrong: %+v", result)
+	}
+
+	result, err := ImportUsers(projectDir, registry, records, rules, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Added != 2 || result.Updated != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	for id, want := range map[string]string{"jana@example.com": "support", "petr@example.com": "tester", "eva@example.com": "developer"} {
+		if user := registry.FindUserByEmail(id); user == nil || user.GetRoleForArea("vos") != want {
+			t.Errorf("%s: want vos role %s, got %+v", id, want, user)
+		}
+	}
+	eva := registry.FindUserByEmail("eva@example.com")
+	if eva.Name != "Eva Dvořáková" || eva.GetRoleForArea("voc") != "customer" {
+		t.Errorf("existing user not updated or lost a role: %+v", eva)
+	}
+
+	// A repeated import changes nothing
+	if result, _ := ImportUsers(projectDir, registry, records, rules, false); result.Unchanged != 3 {
+		t.Errorf("unexpected repeated result %+v", result)
+	}
+
+	if _, err := ImportUsers(projectDir, registry, records, []RoleMappingRule{{Area: "voc", Role: "ceo"}}, true); err == nil {
+		t.Error("a rule with an unknown role must be rejected")
+	}
+	if _, err := ReadUsersCSV(strings.NewReader("name,org\nJana,Acme\n")); err == nil {
+		t.Error("CSV without an ID column must be rejected")
+	}
+}
+
+func TestLDAPEntryToRecord(t *testing.T) {
+	entry := ldap.NewEntry("CN=Jana,OU=Staff,DC=example,DC=com", map[string][]string{
+		"userPrincipalName": {"jana@example.com"},
+		"cn":                {"Jana"},
+		"memberOf":          {"CN=Architects,OU=Groups,DC=example,DC=com", "CN=All,OU=Groups,DC=example,DC=com"},
+	})
+	record, ok := ldapEntryToRecord(entry)
+	if !ok || record.ID != "jana@example.com" || record.Name != "Jana" {
+		t.Fatalf("unexpected record %+v", record)
+	}
+	rule, _ := ParseRoleMappingRule("memberOf=cn=architects,*:vos=architect")
+	if !rule.Matches(record) {
+		t.Error("group rule must match")
+	}
+	if rule, _ := ParseRoleMappingRule("dn=*,OU=Contractors,*:vos=tech-consultant"); rule.Matches(record) {
+		t.Error("DN rule must not match")
+	}
+
+	if _, ok := ldapEntryToRecord(ldap.NewEntry("CN=Printer,DC=example,DC=com", map[string][]string{"cn": {"Printer"}})); ok {
+		t.Error("entries without mail must be skipped")
+	}
+}
@@ -0,0 +1,250 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultWatchDebounce is how long 'pft watch' waits for the events of a save
+// to settle; editors write through temporary files and renames
+const DefaultWatchDebounce = 300 * time.Millisecond
+
+// ValidateItem checks an item file as pft reads it: a frontmatter block of
+// valid YAML with an id and a title. It returns the problems found, none for
+// a valid item.
+func ValidateItem(path string, content []byte) []string {
+	source, _, ok := splitFrontmatter(string(content))
+	if !ok {
+		return []string{"missing frontmatter (a block between --- lines at the top)"}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(source), &doc); err != nil {
+		return []string{"frontmatter is not valid YAML: " + strings.TrimPrefix(err.Error(), "yaml: ")}
+	}
+	if len(doc.Content) > 0 && doc.Content[0].Kind != yaml.MappingNode {
+		return []string{"frontmatter is not a list of key: value fields"}
+	}
+
+	var problems []string
+	fm, _ := ParseFrontmatter(string(content))
+	id := fm.Get("id")
+	if id == "" {
+		problems = append(problems, "missing id")
+	} else if name := filepath.Base(path); !strings.HasPrefix(name, id+"-") && name != id+".md" {
+		problems = append(problems, fmt.Sprintf("id %s does not match the file name", id))
+	}
+	if fm.Get("title") == "" {
+		problems = append(problems, "missing title")
+	}
+	return problems
+}
+
+// ItemWatcher validates the item files of a project as they change and keeps
+// the sync cache current
+type ItemWatcher struct {
+	projectDir string
+	areaDirs   map[string]string // Area directory -> area
+	out        io.Writer
+	push       func(area string) // Called for areas with changed items; nil to not push
+}
+
+// NewItemWatcher returns a watcher of the directories of areas
+func NewItemWatcher(projectDir string, areas []string, out io.Writer) *ItemWatcher {
+	w := &ItemWatcher{projectDir: projectDir, areaDirs: make(map[string]string), out: out}
+	for _, area := range areas {
+		w.areaDirs[getVoiceDir(projectDir, area)] = area
+	}
+	return w
+}
+
+// areaOf returns the area of an item file, empty outside the watched areas
+func (w *ItemWatcher) areaOf(path string) string {
+	for dir, area := range w.areaDirs {
+		if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return area
+		}
+	}
+	return ""
+}
+
+// isItemFile reports whether a path is an item, not an area README or an
+// editor's temporary file
+func isItemFile(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasSuffix(name, ".md") && name != "README.md" && !strings.HasPrefix(name, ".") && !strings.HasPrefix(name, "#")
+}
+
+// Process validates changed item files and updates their cache entries. A
+// path that no longer exists drops its entry. Areas whose items all passed
+// validation are pushed when the watcher pushes.
+func (w *ItemWatcher) Process(paths []string) {
+	cache := NewSyncCache(w.projectDir)
+	if err := cache.Load(); err != nil {
+		fmt.Fprintf(w.out, "✗ %v\n", err)
+		return
+	}
+
+	sort.Strings(paths)
+	stamp := time.Now().Format("15:04:05")
+	changed := make(map[string]bool)
+	invalid := make(map[string]bool)
+	for _, path := range paths {
+		area := w.areaOf(path)
+		display := path
+		if rel, err := filepath.Rel(w.projectDir, path); err == nil {
+			display = rel
+		}
+
+		content, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			for id, entry := range cache.Entries {
+				if entry.FilePath == path {
+					cache.Delete(id)
+				}
+			}
+			fmt.Fprintf(w.out, "%s 🗑  %s removed\n", stamp, display)
+			changed[area] = true
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(w.out, "%s ✗ %s: %v\n", stamp, display, err)
+			invalid[area] = true
+			continue
+		}
+
+		problems := ValidateItem(path, content)
+		var item *FeedbackItem
+		if len(problems) == 0 {
+			item, _ = parseMarkdownContent(path, content)
+			if entry, ok := cache.Get(item.ID); ok && entry.FilePath != "" && entry.FilePath != path {
+				if _, err := os.Stat(entry.FilePath); err == nil {
+					problems = append(problems, fmt.Sprintf("duplicate id %s, also used by %s", item.ID, entry.FilePath))
+				}
+			}
+		}
+		if len(problems) > 0 {
+			fmt.Fprintf(w.out, "%s ✗ %s\n", stamp, display)
+			for _, problem := range problems {
+				fmt.Fprintf(w.out, "     - %s\n", problem)
+			}
+			invalid[area] = true
+			continue
+		}
+
+		// Keep the hash of the last sync, so the item still shows as changed
+		entry, _ := cache.Get(item.ID)
+		for id, other := range cache.Entries {
+			if other.FilePath == path && id != item.ID {
+				cache.Delete(id) // The id of the file was changed
+			}
+		}
+		entry.ID = item.ID
+		entry.Title = item.Title
+		entry.FilePath = path
+		if item.ExternalID != "" {
+			entry.ExternalID = item.ExternalID
+		}
+		cache.Set(entry)
+		fmt.Fprintf(w.out, "%s ✓ %s (%s)\n", stamp, display, item.ID)
+		changed[area] = true
+	}
+
+	if err := cache.Save(); err != nil {
+		fmt.Fprintf(w.out, "✗ %v\n", err)
+	}
+	if w.push == nil {
+		return
+	}
+	for _, area := range sortedAreas(changed) {
+		if invalid[area] {
+			fmt.Fprintf(w.out, "   %s not pushed until its items are valid\n", strings.ToUpper(area))
+			continue
+		}
+		w.push(area)
+	}
+}
+
+// sortedAreas returns the areas of a set in order
+func sortedAreas(set map[string]bool) []string {
+	areas := make([]string, 0, len(set))
+	for area := range set {
+		areas = append(areas, area)
+	}
+	sort.Strings(areas)
+	return areas
+}
+
+// Run watches the area directories until stop is closed, processing the
+// changes of each save once its events settled for debounce
+func (w *ItemWatcher) Run(debounce time.Duration, stop <-chan struct{}) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	// fsnotify does not watch subdirectories (needs/, verbatims/, ...)
+	addTree := func(root string) error {
+		return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return err
+			}
+			return watcher.Add(path)
+		})
+	}
+	for dir := range w.areaDirs {
+		if err := addTree(dir); err != nil {
+			return err
+		}
+	}
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					addTree(event.Name)
+					continue
+				}
+			}
+			if !isItemFile(event.Name) || event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			pending[event.Name] = true
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(w.out, "✗ Watch error: %v\n", err)
+		case <-timer.C:
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			pending = make(map[string]bool)
+			w.Process(paths)
+		}
+	}
+}
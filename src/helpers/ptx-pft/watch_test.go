@@ -0,0 +1,138 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValidateItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		problem string
+	}{
+		{"valid", "P01-dark-mode.md", "---\nid: P01\ntitle: Dark mode\n---\n\nBody\n", ""},
+		{"no frontmatter", "P01.md", "# Dark mode\n", "missing frontmatter"},
+		{"unclosed frontmatter", "P01.md", "---\nid: P01\ntitle: Dark mode\n", "missing frontmatter"},
+		{"invalid yaml", "P01.md", "---\nid: P01\ntitle: [Dark mode\n---\n", "not valid YAML"},
+		{"missing title", "P01.md", "---\nid: P01\n---\n", "missing title"},
+		{"missing id", "P01.md", "---\ntitle: Dark mode\n---\n", "missing id"},
+		{"id mismatch", "P02-dark-mode.md", "---\nid: P01\ntitle: Dark mode\n---\n", "does not match the file name"},
+	}
+	for _, tt := range tests {
+		problems := ValidateItem(tt.file, []byte(tt.content))
+		if tt.problem == "" {
+			if len(problems) > 0 {
+				t.Errorf("%s: unexpected problems %v", tt.name, problems)
+			}
+			continue
+		}
+		if !strings.Contains(strings.Join(problems, "; "), tt.problem) {
+			t.Errorf("%s: problems %v, want %q", tt.name, problems, tt.problem)
+		}
+	}
+}
+
+func TestItemWatcherProcess(t *testing.T) {
+	projectDir := t.TempDir()
+	needs := filepath.Join(projectDir, "VoC", "needs")
+	os.MkdirAll(needs, 0755)
+	valid := filepath.Join(needs, "P01-dark-mode.md")
+	invalid := filepath.Join(needs, "P02-export.md")
+	os.WriteFile(valid, []byte("---\nid: P01\ntitle: Dark mode\nexternal_id: \"12\"\n---\n"), 0644)
+	os.WriteFile(invalid, []byte("---\nid: P02\ntitle: [Export\n---\n"), 0644)
+
+	cache := NewSyncCache(projectDir)
+	cache.Set(CacheEntry{ID: "P01", Title: "Old title", Hash: "abc", FilePath: valid})
+	cache.Set(CacheEntry{ID: "P03", FilePath: filepath.Join(needs, "P03-gone.md")})
+	cache.Save()
+
+	var out strings.Builder
+	var pushed []string
+	watcher := NewItemWatcher(projectDir, []string{"voc"}, &out)
+	watcher.push = func(area string) { pushed = append(pushed, area) }
+	watcher.Process([]string{valid, invalid, filepath.Join(needs, "P03-gone.md")})
+
+	report := out.String()
+	for _, want := range []string{"✓ VoC/needs/P01-dark-mode.md (P01)", "✗ VoC/needs/P02-export.md", "not valid YAML", "P03-gone.md removed", "VOC not pushed"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("%q missing in\n%s", want, report)
+		}
+	}
+	if len(pushed) != 0 {
+		t.Errorf("pushed %v with an invalid item", pushed)
+	}
+
+	cache = NewSyncCache(projectDir)
+	cache.Load()
+	entry, ok := cache.Get("P01")
+	if !ok || entry.Title != "Dark mode" || entry.ExternalID != "12" || entry.Hash != "abc" {
+		t.Errorf("cache entry %+v", entry)
+	}
+	if _, ok := cache.Get("P03"); ok {
+		t.Error("entry of the removed file kept")
+	}
+	if _, ok := cache.Get("P02"); ok {
+		t.Error("invalid item cached")
+	}
+
+	// Once the item is fixed, the area is pushed
+	os.WriteFile(invalid, []byte("---\nid: P02\ntitle: Export\n---\n"), 0644)
+	watcher.Process([]string{invalid})
+	if strings.Join(pushed, ",") != "voc" {
+		t.Errorf("pushed %v", pushed)
+	}
+}
+
+// syncBuffer is a writer the watcher goroutine and the test share
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestItemWatcherRun(t *testing.T) {
+	projectDir := t.TempDir()
+	needs := filepath.Join(projectDir, "VoC", "needs")
+	os.MkdirAll(needs, 0755)
+
+	out := &syncBuffer{}
+	watcher := NewItemWatcher(projectDir, []string{"voc"}, out)
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() { done <- watcher.Run(20*time.Millisecond, stop) }()
+	time.Sleep(50 * time.Millisecond)
+
+	os.WriteFile(filepath.Join(needs, "P01-dark-mode.md"), []byte("---\nid: P01\n---\n"), 0644)
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), "missing title") && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "missing title") {
+		t.Errorf("saved item not validated:\n%s", out.String())
+	}
+}
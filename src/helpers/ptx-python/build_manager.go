@@ -5,10 +5,13 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
 )
@@ -30,6 +33,27 @@ func NewBuildManager() (*BuildManager, error) {
 	}, nil
 }
 
+// buildToolsVenv prefixes the dedicated per-project venvs used for PyInstaller/cx_Freeze
+// builds when neither --venv nor a project-local ./.venv is available
+const buildToolsVenv = "build"
+
+var buildVenvNameInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+
+// projectBuildVenv names the dedicated build venv of a project, e.g. build-myapp-1a2b3c4d.
+// Each project gets its own venv, so requirements of different projects never mix.
+func projectBuildVenv(projectDir, pythonVersion string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(projectDir)))
+	name := buildToolsVenv
+	if base := strings.Trim(buildVenvNameInvalid.ReplaceAllString(strings.ToLower(filepath.Base(projectDir)), "-"), "-"); base != "" {
+		name += "-" + base
+	}
+	name += "-" + hex.EncodeToString(sum[:4])
+	if pythonVersion != "" {
+		name += "-py" + pythonVersion
+	}
+	return name
+}
+
 // BuildExeOptions holds options for building executables with PyInstaller
 type BuildExeOptions struct {
 	Script    string
@@ -40,6 +64,7 @@ type BuildExeOptions struct {
 	Windowed  bool
 	VenvName  string
 	OutputDir string
+	SpecFile  string // Existing spec file to use instead of generating one
 	ExtraArgs []string
 }
 
@@ -48,224 +73,315 @@ type BuildFreezeOptions struct {
 	Script        string
 	Name          string
 	Icon          string
+	Windowed      bool
 	VenvName      string
 	TargetVersion string
 	OutputDir     string
 	ExtraArgs     []string
 }
 
+// defaultBuildOutputDir returns the platform-specific output directory, e.g. dist/linux-amd64
+func defaultBuildOutputDir(projectDir string) string {
+	return filepath.Join(projectDir, "dist", runtime.GOOS+"-"+runtime.GOARCH)
+}
+
+// resolveBuildVenv selects the venv used for building and makes sure the tool is installed in it.
+// Order: --venv <name>, project-local ./.venv, the project's dedicated build venv (with
+// requirements installed).
+// pythonVersion selects the interpreter when the dedicated build venv has to be created.
+func (bm *BuildManager) resolveBuildVenv(venvName, projectDir, pythonVersion string, tool ToolPackage) (string, error) {
+	vm := bm.venvManager
+
+	var venvPath string
+	switch {
+	case venvName != "":
+		venvPath = filepath.Join(vm.venvBaseDir, venvName)
+		if !vm.VenvExistsAtPath(venvPath) {
+			return "", fmt.Errorf("virtual environment '%s' does not exist", venvName)
+		}
+	case vm.VenvExistsAtPath(filepath.Join(projectDir, ".venv")):
+		venvPath = filepath.Join(projectDir, ".venv")
+	default:
+		name := projectBuildVenv(projectDir, pythonVersion)
+		if pythonVersion != "" {
+			if !vm.VenvExistsAtPath(vm.toolsVenvDir(name)) {
+				if err := vm.CreateLocalVenv(vm.toolsVenvDir(name), false, pythonVersion); err != nil {
+					return "", err
+				}
+			}
+		}
+
+		path, err := vm.EnsureToolVenv(name)
+		if err != nil {
+			return "", err
+		}
+		venvPath = path
+
+		// Dedicated build venv does not contain project dependencies yet
+		requirements := filepath.Join(projectDir, "requirements.txt")
+		if fileExists(requirements) {
+			if err := vm.InstallRequirementsAtPath(venvPath, requirements); err != nil {
+				return "", fmt.Errorf("failed to install project requirements into build venv: %v", err)
+			}
+		}
+	}
+
+	if _, err := os.Stat(vm.getVenvScript(venvPath, tool.Binary)); err != nil {
+		fmt.Printf("%s not found. Installing %s into %s...\n", tool.Package, tool.Package, venvPath)
+		if err := vm.InstallPackageAtPath(venvPath, tool.Package); err != nil {
+			return "", fmt.Errorf("failed to install %s: %v", tool.Package, err)
+		}
+	}
+
+	return venvPath, nil
+}
+
 // BuildExe builds a Python script into a standalone executable using PyInstaller
 func (bm *BuildManager) BuildExe(opts BuildExeOptions) error {
-	// Validate script exists
-	if _, err := os.Stat(opts.Script); os.IsNotExist(err) {
+	script, err := filepath.Abs(opts.Script)
+	if err != nil {
+		return fmt.Errorf("invalid script path: %v", err)
+	}
+	if _, err := os.Stat(script); os.IsNotExist(err) {
 		return fmt.Errorf("script file not found: %s", opts.Script)
 	}
+	projectDir := filepath.Dir(script)
 
-	// Ensure PyInstaller is installed
-	if err := bm.ensurePyInstallerInstalled(opts.VenvName); err != nil {
-		return fmt.Errorf("failed to ensure PyInstaller is installed: %v", err)
+	if opts.Name == "" {
+		opts.Name = strings.TrimSuffix(filepath.Base(script), filepath.Ext(script))
 	}
 
-	// Build PyInstaller command
-	args := bm.buildPyInstallerArgs(opts)
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		outputDir = defaultBuildOutputDir(projectDir)
+	}
+	outputDir, err = filepath.Abs(outputDir)
+	if err != nil {
+		return fmt.Errorf("invalid output directory: %v", err)
+	}
+	workDir := filepath.Join(projectDir, "build", runtime.GOOS+"-"+runtime.GOARCH)
 
-	// Get pip executable path to determine venv
-	var pyinstallerCmd string
-	if opts.VenvName != "" {
-		venvPath := filepath.Join(bm.venvManager.venvBaseDir, opts.VenvName)
-		if runtime.GOOS == "windows" {
-			pyinstallerCmd = filepath.Join(venvPath, "Scripts", "pyinstaller.exe")
+	venvPath, err := bm.resolveBuildVenv(opts.VenvName, projectDir, "", ToolPackage{Package: "pyinstaller", Binary: "pyinstaller"})
+	if err != nil {
+		return err
+	}
+
+	// Use existing spec file or generate one with sensible defaults
+	specFile := opts.SpecFile
+	if specFile == "" {
+		specFile = filepath.Join(projectDir, opts.Name+".spec")
+		reuse := false
+		if existing, err := os.ReadFile(specFile); err == nil {
+			if reuse, err = reuseSpec(specFile, existing, opts); err != nil {
+				return err
+			}
+		}
+		if reuse {
+			fmt.Printf("Using existing spec file: %s\n", specFile)
 		} else {
-			pyinstallerCmd = filepath.Join(venvPath, "bin", "pyinstaller")
+			if err := os.WriteFile(specFile, []byte(generatePyInstallerSpec(script, opts)), 0644); err != nil {
+				return fmt.Errorf("failed to write spec file: %v", err)
+			}
+			fmt.Printf("Generated spec file: %s\n", specFile)
 		}
-	} else {
-		pyinstallerCmd = "pyinstaller"
 	}
 
-	// Execute PyInstaller
+	args := []string{specFile, "--distpath", outputDir, "--workpath", workDir, "--noconfirm", "--clean"}
+	args = append(args, opts.ExtraArgs...)
+
 	fmt.Printf("Building executable from %s...\n", opts.Script)
-	cmd := exec.Command(pyinstallerCmd, args...)
+	cmd := exec.Command(bm.venvManager.getVenvScript(venvPath, "pyinstaller"), args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	cmd.Dir = filepath.Dir(opts.Script)
+	cmd.Dir = projectDir
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("PyInstaller build failed: %v", err)
 	}
 
-	// Display success message
-	outputPath := "dist"
-	if opts.OutputDir != "" {
-		outputPath = opts.OutputDir
-	}
-
 	exeName := opts.Name
-	if exeName == "" {
-		exeName = strings.TrimSuffix(filepath.Base(opts.Script), filepath.Ext(opts.Script))
-	}
 	if runtime.GOOS == "windows" {
 		exeName += ".exe"
 	}
+	outputPath := filepath.Join(outputDir, exeName)
+	if !opts.OneFile {
+		outputPath = filepath.Join(outputDir, opts.Name, exeName)
+	}
 
 	fmt.Printf("\n✅ Executable built successfully!\n")
-	fmt.Printf("Output: %s/%s\n", outputPath, exeName)
+	fmt.Printf("Output: %s\n", outputPath)
 
 	return nil
 }
 
-// buildPyInstallerArgs constructs PyInstaller command arguments
-func (bm *BuildManager) buildPyInstallerArgs(opts BuildExeOptions) []string {
-	args := []string{}
-
-	// Basic script argument
-	args = append(args, opts.Script)
+// Generated spec files record the options they were generated with and a digest
+// of their content, to tell unedited specs (regenerated when the options change)
+// from edited ones
+const (
+	specOptionsMarker = "# portunix-build-options: "
+	specDigestMarker  = "# portunix-build-digest: "
+)
 
-	// Name option
-	if opts.Name != "" {
-		args = append(args, "--name", opts.Name)
+// specOptions describes the options that shape a generated spec file
+func specOptions(opts BuildExeOptions) string {
+	icon := opts.Icon
+	if icon != "" {
+		if absIcon, err := filepath.Abs(icon); err == nil {
+			icon = absIcon
+		}
 	}
+	return fmt.Sprintf("onefile=%t windowed=%t icon=%s", opts.OneFile, opts.Windowed, icon)
+}
 
-	// One file mode
-	if opts.OneFile {
-		args = append(args, "--onefile")
+// reuseSpec decides whether an existing spec file is used as it is. Unedited
+// generated specs are regenerated when the options change; edited and
+// hand-written specs are never overwritten, so conflicting options are errors.
+func reuseSpec(specFile string, content []byte, opts BuildExeOptions) (bool, error) {
+	var options, digest string
+	text := string(content)
+	rest := text
+	for rest != "" {
+		line, next, _ := strings.Cut(rest, "\n")
+		rest = next
+		switch {
+		case strings.HasPrefix(line, specOptionsMarker):
+			options = strings.TrimPrefix(line, specOptionsMarker)
+		case strings.HasPrefix(line, specDigestMarker):
+			digest = strings.TrimPrefix(line, specDigestMarker)
+			sum := sha256.Sum256([]byte(rest))
+			if hex.EncodeToString(sum[:]) != digest {
+				digest = ""
+			}
+			rest = ""
+		}
 	}
 
-	// Console/Windowed mode
-	if opts.Windowed {
-		args = append(args, "--windowed", "--noconsole")
-	} else if opts.Console {
-		args = append(args, "--console")
+	if options == "" {
+		if opts.OneFile || opts.Windowed || opts.Icon != "" {
+			return false, fmt.Errorf("%s was not generated by portunix; --onefile, --windowed and --icon only apply to generated spec files. Edit the spec file, or delete it to generate a new one", specFile)
+		}
+		return true, nil
 	}
-
-	// Icon
-	if opts.Icon != "" {
-		args = append(args, "--icon", opts.Icon)
+	if options == specOptions(opts) {
+		return true, nil
 	}
-
-	// Output directory
-	if opts.OutputDir != "" {
-		args = append(args, "--distpath", opts.OutputDir)
+	if digest != "" {
+		// Unedited: regenerate for the new options
+		return false, nil
 	}
-
-	// Clean build
-	args = append(args, "--clean")
-
-	// Extra arguments
-	args = append(args, opts.ExtraArgs...)
-
-	return args
+	return false, fmt.Errorf("%s was edited and generated with %q, not %q; adjust it, or delete it to generate a new one", specFile, options, specOptions(opts))
 }
 
-// ensurePyInstallerInstalled checks if PyInstaller is installed and installs it if not
-func (bm *BuildManager) ensurePyInstallerInstalled(venvName string) error {
-	var checkCmd *exec.Cmd
+// generatePyInstallerSpec renders a PyInstaller spec file for the given options
+func generatePyInstallerSpec(script string, opts BuildExeOptions) string {
+	body := generatePyInstallerSpecBody(script, opts)
+	sum := sha256.Sum256([]byte(body))
+
+	var b strings.Builder
+	b.WriteString("# -*- mode: python ; coding: utf-8 -*-\n")
+	b.WriteString("# Generated by portunix python build exe - edit freely, edited files are never overwritten\n")
+	b.WriteString(specOptionsMarker + specOptions(opts) + "\n")
+	b.WriteString(specDigestMarker + hex.EncodeToString(sum[:]) + "\n")
+	b.WriteString(body)
+	return b.String()
+}
 
-	if venvName != "" {
-		// Check in specific venv
-		venvPath := filepath.Join(bm.venvManager.venvBaseDir, venvName)
-		pipExe := bm.venvManager.getPipExecutable(venvPath)
-		checkCmd = exec.Command(pipExe, "show", "pyinstaller")
-	} else {
-		// Check global Python
-		checkCmd = exec.Command("pip", "show", "pyinstaller")
+func generatePyInstallerSpecBody(script string, opts BuildExeOptions) string {
+	console := "True"
+	if opts.Windowed {
+		console = "False"
 	}
 
-	output, err := checkCmd.CombinedOutput()
-	if err == nil && strings.Contains(string(output), "Name: pyinstaller") {
-		// PyInstaller is already installed
-		return nil
+	icon := "None"
+	if opts.Icon != "" {
+		if absIcon, err := filepath.Abs(opts.Icon); err == nil {
+			icon = pyString(absIcon)
+		}
 	}
 
-	// Install PyInstaller
-	fmt.Println("PyInstaller not found. Installing PyInstaller...")
+	var b strings.Builder
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "a = Analysis(\n    [%s],\n    pathex=[%s],\n", pyString(script), pyString(filepath.Dir(script)))
+	b.WriteString("    binaries=[],\n    datas=[],\n    hiddenimports=[],\n    hookspath=[],\n")
+	b.WriteString("    hooksconfig={},\n    runtime_hooks=[],\n    excludes=[],\n    noarchive=False,\n)\n")
+	b.WriteString("pyz = PYZ(a.pure)\n\n")
 
-	if venvName != "" {
-		return bm.venvManager.InstallPackage(venvName, "pyinstaller")
-	} else {
-		installCmd := exec.Command("pip", "install", "pyinstaller")
-		installCmd.Stdout = os.Stdout
-		installCmd.Stderr = os.Stderr
-		return installCmd.Run()
-	}
+	if opts.OneFile {
+		b.WriteString("exe = EXE(\n    pyz,\n    a.scripts,\n    a.binaries,\n    a.datas,\n    [],\n")
+		fmt.Fprintf(&b, "    name=%s,\n", pyString(opts.Name))
+		b.WriteString("    debug=False,\n    strip=False,\n    upx=True,\n    runtime_tmpdir=None,\n")
+		fmt.Fprintf(&b, "    console=%s,\n    icon=%s,\n)\n", console, icon)
+		return b.String()
+	}
+
+	b.WriteString("exe = EXE(\n    pyz,\n    a.scripts,\n    [],\n    exclude_binaries=True,\n")
+	fmt.Fprintf(&b, "    name=%s,\n", pyString(opts.Name))
+	b.WriteString("    debug=False,\n    strip=False,\n    upx=True,\n")
+	fmt.Fprintf(&b, "    console=%s,\n    icon=%s,\n)\n", console, icon)
+	b.WriteString("coll = COLLECT(\n    exe,\n    a.binaries,\n    a.datas,\n    strip=False,\n    upx=True,\n")
+	fmt.Fprintf(&b, "    name=%s,\n)\n", pyString(opts.Name))
+	return b.String()
+}
+
+// pyString quotes a value as a single-quoted Python string literal
+func pyString(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "'", "\\'")
+	return "'" + value + "'"
 }
 
 // BuildFreeze builds a Python script using cx_Freeze
 func (bm *BuildManager) BuildFreeze(opts BuildFreezeOptions) error {
-	// Validate script exists
-	if _, err := os.Stat(opts.Script); os.IsNotExist(err) {
+	script, err := filepath.Abs(opts.Script)
+	if err != nil {
+		return fmt.Errorf("invalid script path: %v", err)
+	}
+	if _, err := os.Stat(script); os.IsNotExist(err) {
 		return fmt.Errorf("script file not found: %s", opts.Script)
 	}
+	projectDir := filepath.Dir(script)
 
-	// Ensure cx_Freeze is installed
-	if err := bm.ensureCxFreezeInstalled(opts.VenvName); err != nil {
-		return fmt.Errorf("failed to ensure cx_Freeze is installed: %v", err)
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		outputDir = defaultBuildOutputDir(projectDir)
+	}
+	outputDir, err = filepath.Abs(outputDir)
+	if err != nil {
+		return fmt.Errorf("invalid output directory: %v", err)
 	}
 
-	// Build cx_Freeze command
-	args := []string{opts.Script, "build"}
-
-	if opts.OutputDir != "" {
-		args = append(args, "--build-exe", opts.OutputDir)
+	venvPath, err := bm.resolveBuildVenv(opts.VenvName, projectDir, opts.TargetVersion, ToolPackage{Package: "cx_Freeze", Binary: "cxfreeze"})
+	if err != nil {
+		return err
 	}
 
-	// Get cxfreeze executable
-	var cxfreezeCmd string
-	if opts.VenvName != "" {
-		venvPath := filepath.Join(bm.venvManager.venvBaseDir, opts.VenvName)
-		if runtime.GOOS == "windows" {
-			cxfreezeCmd = filepath.Join(venvPath, "Scripts", "cxfreeze.exe")
-		} else {
-			cxfreezeCmd = filepath.Join(venvPath, "bin", "cxfreeze")
-		}
-	} else {
-		cxfreezeCmd = "cxfreeze"
+	args := []string{"--script", script, "--target-dir", outputDir}
+	if opts.Name != "" {
+		args = append(args, "--target-name", opts.Name)
 	}
+	if opts.Windowed {
+		args = append(args, "--base", "gui")
+	}
+	if opts.Icon != "" {
+		args = append(args, "--icon", opts.Icon)
+	}
+	args = append(args, opts.ExtraArgs...)
 
-	// Execute cx_Freeze
 	fmt.Printf("Building executable with cx_Freeze from %s...\n", opts.Script)
-	cmd := exec.Command(cxfreezeCmd, args...)
+	cmd := exec.Command(bm.venvManager.getVenvScript(venvPath, "cxfreeze"), args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	cmd.Dir = filepath.Dir(opts.Script)
+	cmd.Dir = projectDir
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("cx_Freeze build failed: %v", err)
 	}
 
 	fmt.Printf("\n✅ Executable built successfully with cx_Freeze!\n")
+	fmt.Printf("Output: %s\n", outputDir)
 	return nil
 }
 
-// ensureCxFreezeInstalled checks if cx_Freeze is installed and installs it if not
-func (bm *BuildManager) ensureCxFreezeInstalled(venvName string) error {
-	var checkCmd *exec.Cmd
-
-	if venvName != "" {
-		venvPath := filepath.Join(bm.venvManager.venvBaseDir, venvName)
-		pipExe := bm.venvManager.getPipExecutable(venvPath)
-		checkCmd = exec.Command(pipExe, "show", "cx_Freeze")
-	} else {
-		checkCmd = exec.Command("pip", "show", "cx_Freeze")
-	}
-
-	output, err := checkCmd.CombinedOutput()
-	if err == nil && strings.Contains(string(output), "Name: cx-Freeze") {
-		return nil
-	}
-
-	// Install cx_Freeze
-	fmt.Println("cx_Freeze not found. Installing cx_Freeze...")
-
-	if venvName != "" {
-		return bm.venvManager.InstallPackage(venvName, "cx_Freeze")
-	} else {
-		installCmd := exec.Command("pip", "install", "cx_Freeze")
-		installCmd.Stdout = os.Stdout
-		installCmd.Stderr = os.Stderr
-		return installCmd.Run()
-	}
-}
-
 // BuildWheel builds a Python wheel distribution
 func (bm *BuildManager) BuildWheel(venvName string, projectPath string) error {
 	// Ensure build tools are installed
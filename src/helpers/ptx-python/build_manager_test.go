@@ -0,0 +1,55 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProjectBuildVenv(t *testing.T) {
+	a := projectBuildVenv("/work/My App", "")
+	b := projectBuildVenv("/other/My App", "")
+	if !strings.HasPrefix(a, "build-my-app-") || a == b {
+		t.Errorf("projects must get their own build venv: %q, %q", a, b)
+	}
+	if a != projectBuildVenv("/work/My App/", "") {
+		t.Error("build venv name depends on a trailing separator")
+	}
+	if v := projectBuildVenv("/work/My App", "3.11"); v != a+"-py3.11" {
+		t.Errorf("python version not in name: %q", v)
+	}
+}
+
+func TestReuseSpec(t *testing.T) {
+	folder := BuildExeOptions{Name: "app"}
+	onefile := BuildExeOptions{Name: "app", OneFile: true}
+	generated := generatePyInstallerSpec("/work/app.py", folder)
+	edited := strings.Replace(generated, "hiddenimports=[]", "hiddenimports=['pkg']", 1)
+
+	for _, c := range []struct {
+		name    string
+		content string
+		opts    BuildExeOptions
+		reuse   bool
+		err     string
+	}{
+		{"same options", generated, folder, true, ""},
+		{"edited, same options", edited, folder, true, ""},
+		{"unedited, new options", generated, onefile, false, ""},
+		{"edited, new options", edited, onefile, false, "was edited"},
+		{"hand-written", "a = Analysis(['app.py'])\n", folder, true, ""},
+		{"hand-written with options", "a = Analysis(['app.py'])\n", BuildExeOptions{Windowed: true}, false, "not generated by portunix"},
+	} {
+		reuse, err := reuseSpec("app.spec", []byte(c.content), c.opts)
+		if reuse != c.reuse || (err == nil) != (c.err == "") || (err != nil && !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("%s: reuse %v, %v", c.name, reuse, err)
+		}
+	}
+
+	if !strings.Contains(generatePyInstallerSpec("/work/app.py", onefile), "a.binaries,\n    a.datas,\n    [],\n    name='app'") {
+		t.Error("one-file spec does not bundle binaries into the executable")
+	}
+}
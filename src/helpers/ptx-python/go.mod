@@ -2,9 +2,16 @@ module portunix.ai/helpers/ptx-python
 
 go 1.24.2
 
-require github.com/spf13/cobra v1.10.1
+require (
+	github.com/spf13/cobra v1.10.1
+	portunix.ai/app v0.0.0
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.9 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// Shared configuration library from the parent app module
+replace portunix.ai/app => ../../app
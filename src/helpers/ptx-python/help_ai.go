@@ -0,0 +1,97 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import "portunix.ai/app/aihelp"
+
+// showHelpAI prints machine-readable help for --help-ai
+func showHelpAI() {
+	venvName := aihelp.Argument{Name: "name", Type: "string", Required: true, Description: "Centralized venv name"}
+	paths := aihelp.Argument{Name: "paths", Type: "path", Variadic: true, Description: "Files or directories (default: project root)"}
+	target := []aihelp.Flag{
+		{Name: "local", Type: "boolean", Description: "Use project-local venv (./.venv)"},
+		{Name: "path", Type: "path", Description: "Use venv at custom location"},
+		{Name: "venv", Type: "string", Description: "Use centralized venv by name"},
+		{Name: "global", Type: "boolean", Description: "Operate on system Python"},
+	}
+	jsonFlag := aihelp.Flag{Name: "json", Type: "boolean", Description: "Output a machine-readable result"}
+
+	aihelp.Help{
+		Tool:        "ptx-python",
+		Version:     version,
+		Description: "Python 3 development: virtual environments, packages, quality tools, tests and builds",
+		Commands: []aihelp.Command{
+			{
+				Name:        "python init",
+				Description: "Initialize project: create ./.venv and install dependencies",
+				Flags: []aihelp.Flag{
+					{Name: "force", Type: "boolean", Description: "Recreate existing venv"},
+					{Name: "python", Type: "version", Description: "Python version (e.g. 3.11)"},
+				},
+				Examples: []string{"portunix python init", "portunix python init --python 3.11"},
+			},
+			{Name: "python project info", Description: "Show detected pyproject.toml project manager (poetry/uv)"},
+			{Name: "python project install", Description: "Install dependencies (poetry install / uv sync)"},
+			{
+				Name:        "python project run",
+				Description: "Run a command in the project environment",
+				Arguments:   []aihelp.Argument{{Name: "cmd", Type: "string", Required: true}, {Name: "args", Type: "string", Variadic: true}},
+			},
+			{Name: "python project build", Description: "Build wheel and sdist"},
+			{
+				Name:        "python venv create",
+				Description: "Create a virtual environment (centralized in ~/.portunix/python/venvs by default)",
+				Arguments:   []aihelp.Argument{{Name: "name", Type: "string", Description: "Venv name (not needed with --local/--path)"}},
+				Flags:       append(target[:2:2], aihelp.Flag{Name: "python", Type: "version", Description: "Python version"}),
+				Examples:    []string{"portunix python venv create myenv", "portunix python venv create --local"},
+			},
+			{
+				Name:        "python venv list",
+				Description: "List all virtual environments",
+				Flags:       []aihelp.Flag{{Name: "group-by-version", Type: "boolean", Description: "Group venvs by Python version"}},
+			},
+			{Name: "python venv exists", Description: "Check if a venv exists (exit code 0/1)", Arguments: []aihelp.Argument{venvName}},
+			{
+				Name:        "python venv info",
+				Description: "Show venv details (auto-detects ./.venv)",
+				Flags:       []aihelp.Flag{{Name: "verbose", Shorthand: "v", Type: "boolean", Description: "Include component versions"}, jsonFlag},
+			},
+			{Name: "python venv delete", Description: "Remove a virtual environment", Arguments: []aihelp.Argument{venvName}, Flags: target[:1]},
+			{Name: "python venv activate", Description: "Show activation command", Arguments: []aihelp.Argument{venvName}},
+			{Name: "python venv scan", Description: "Discover virtual environments in a directory", Arguments: []aihelp.Argument{{Name: "path", Type: "path"}}},
+			{
+				Name:        "python pip install",
+				Description: "Install packages (auto-detects ./.venv)",
+				Arguments:   []aihelp.Argument{{Name: "package", Type: "string", Variadic: true}},
+				Flags:       append([]aihelp.Flag{{Name: "r", Type: "path", Description: "Install from requirements file"}}, target...),
+				Examples:    []string{"portunix python pip install requests", "portunix python pip install -r requirements.txt"},
+			},
+			{Name: "python pip uninstall", Description: "Remove packages", Arguments: []aihelp.Argument{{Name: "package", Type: "string", Required: true, Variadic: true}}, Flags: target},
+			{Name: "python pip list", Description: "List installed packages", Flags: target},
+			{Name: "python pip freeze", Description: "Print installed packages in requirements format", Flags: target},
+			{Name: "python lint", Description: "Lint with ruff (exit 0 clean, 1 issues, 2 tool error)", Arguments: []aihelp.Argument{paths}, Flags: []aihelp.Flag{{Name: "fix", Type: "boolean", Description: "Apply automatic fixes"}, jsonFlag}},
+			{Name: "python format", Description: "Format with black (exit 0 clean, 1 issues, 2 tool error)", Arguments: []aihelp.Argument{paths}, Flags: []aihelp.Flag{{Name: "check", Type: "boolean", Description: "Only report files that would change"}, jsonFlag}},
+			{Name: "python typecheck", Description: "Type check with mypy (exit 0 clean, 1 issues, 2 tool error)", Arguments: []aihelp.Argument{paths}, Flags: []aihelp.Flag{jsonFlag}},
+			{Name: "python jupyter create", Description: "Create venv with jupyterlab + ipykernel and register the kernel", Arguments: []aihelp.Argument{venvName}},
+			{Name: "python jupyter start", Description: "Launch JupyterLab", Arguments: []aihelp.Argument{venvName}, Flags: []aihelp.Flag{{Name: "container", Type: "boolean", Description: "Run in an isolated container"}}},
+			{Name: "python jupyter delete", Description: "Remove kernel and venv", Arguments: []aihelp.Argument{venvName}},
+			{
+				Name:        "python test",
+				Description: "Run pytest (auto-detects ./.venv)",
+				Arguments:   []aihelp.Argument{paths},
+				Flags: []aihelp.Flag{
+					{Name: "coverage", Type: "boolean", Description: "Coverage report (terminal + XML + HTML)"},
+					{Name: "junit", Type: "path", Description: "Write JUnit XML report"},
+				},
+				Examples: []string{"portunix python test --coverage", "portunix python test --junit report.xml"},
+			},
+			{Name: "python build exe", Description: "Build standalone executable with PyInstaller", Arguments: []aihelp.Argument{{Name: "script", Type: "path", Required: true}}},
+			{Name: "python build freeze", Description: "Build with cx_Freeze", Arguments: []aihelp.Argument{{Name: "script", Type: "path", Required: true}}},
+			{Name: "python build wheel", Description: "Build wheel distribution package"},
+			{Name: "python build sdist", Description: "Build source distribution package"},
+			{Name: "python check", Description: "Check Python installation"},
+		},
+	}.Print()
+}
@@ -0,0 +1,217 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// jupyterRequirementsFile is the pip freeze snapshot of the host venv, mounted
+// read-only into the container at /requirements
+const jupyterRequirementsFile = "requirements.txt"
+
+// JupyterOptions holds options for Jupyter environment commands
+type JupyterOptions struct {
+	Name          string
+	PythonVersion string
+	NotebookDir   string
+	Port          int
+	Container     bool
+	Start         bool
+}
+
+// JupyterManager bootstraps JupyterLab environments on top of centralized venvs
+type JupyterManager struct {
+	venvManager *VenvManager
+}
+
+// NewJupyterManager creates a new Jupyter manager
+func NewJupyterManager() (*JupyterManager, error) {
+	vm, err := NewVenvManager()
+	if err != nil {
+		return nil, err
+	}
+
+	return &JupyterManager{
+		venvManager: vm,
+	}, nil
+}
+
+// Create creates a venv, installs jupyterlab and ipykernel, and registers the kernel
+func (jm *JupyterManager) Create(opts JupyterOptions) error {
+	vm := jm.venvManager
+	venvPath := filepath.Join(vm.venvBaseDir, opts.Name)
+
+	if vm.VenvExists(opts.Name) {
+		fmt.Printf("Using existing virtual environment '%s'\n", opts.Name)
+	} else if err := vm.CreateVenv(opts.Name, opts.PythonVersion); err != nil {
+		return err
+	}
+
+	for _, pkg := range []string{"jupyterlab", "ipykernel"} {
+		fmt.Printf("Installing %s...\n", pkg)
+		if err := vm.InstallPackageAtPath(venvPath, pkg); err != nil {
+			return fmt.Errorf("failed to install %s: %v", pkg, err)
+		}
+	}
+
+	fmt.Println("Registering Jupyter kernel...")
+	cmd := exec.Command(vm.getPythonExecutable(venvPath), "-m", "ipykernel", "install", "--user",
+		"--name", opts.Name, "--display-name", fmt.Sprintf("Python (%s)", opts.Name))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to register kernel: %v\nOutput: %s", err, string(output))
+	}
+
+	fmt.Printf("✅ Jupyter environment '%s' ready (kernel: Python (%s))\n", opts.Name, opts.Name)
+	return nil
+}
+
+// Start launches JupyterLab locally from the venv or inside a container
+func (jm *JupyterManager) Start(opts JupyterOptions) error {
+	vm := jm.venvManager
+	venvPath := filepath.Join(vm.venvBaseDir, opts.Name)
+
+	if !vm.VenvExists(opts.Name) {
+		return fmt.Errorf("jupyter environment '%s' does not exist (create it with: portunix python jupyter create %s)", opts.Name, opts.Name)
+	}
+
+	notebookDir, err := filepath.Abs(opts.NotebookDir)
+	if err != nil {
+		return fmt.Errorf("invalid notebook directory: %v", err)
+	}
+
+	if opts.Container {
+		return jm.startInContainer(opts, venvPath, notebookDir)
+	}
+
+	fmt.Printf("🚀 Starting JupyterLab on port %d (Ctrl+C to stop)...\n", opts.Port)
+	cmd := exec.Command(vm.getVenvScript(venvPath, "jupyter"), "lab",
+		"--notebook-dir", notebookDir, "--port", strconv.Itoa(opts.Port))
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("jupyter lab failed: %v", err)
+	}
+	return nil
+}
+
+// startInContainer runs JupyterLab in an isolated container via ptx-container,
+// replicating the venv's packages from a pip freeze snapshot
+func (jm *JupyterManager) startInContainer(opts JupyterOptions, venvPath, notebookDir string) error {
+	vm := jm.venvManager
+
+	version, err := vm.getPythonVersion(venvPath)
+	if err != nil {
+		return fmt.Errorf("failed to detect Python version of '%s': %v", opts.Name, err)
+	}
+	image, err := jupyterContainerImage(version)
+	if err != nil {
+		return err
+	}
+
+	freeze, err := exec.Command(vm.getPythonExecutable(venvPath), "-m", "pip", "freeze").Output()
+	if err != nil {
+		return fmt.Errorf("failed to snapshot venv packages: %v", err)
+	}
+
+	// The snapshot lives outside the notebook directory; one directory per
+	// environment is reused so restarts don't accumulate temp files
+	requirementsDir := filepath.Join(os.TempDir(), "ptx-jupyter-"+opts.Name)
+	if err := os.MkdirAll(requirementsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create requirements directory: %v", err)
+	}
+	requirementsPath := filepath.Join(requirementsDir, jupyterRequirementsFile)
+	if err := os.WriteFile(requirementsPath, []byte(containerRequirements(string(freeze))), 0644); err != nil {
+		return fmt.Errorf("failed to write requirements snapshot: %v", err)
+	}
+
+	portunixPath, err := findPortunixBinary()
+	if err != nil {
+		return err
+	}
+
+	containerName := "ptx-jupyter-" + opts.Name
+	// Pins that don't resolve on Linux must not keep JupyterLab from starting
+	script := fmt.Sprintf("pip install --quiet -r /requirements/%s || "+
+		"echo 'Warning: some venv packages could not be installed in the container'; "+
+		"pip install --quiet jupyterlab && "+
+		"jupyter lab --ip=0.0.0.0 --port=8888 --no-browser --allow-root --notebook-dir=/workspace",
+		jupyterRequirementsFile)
+
+	args := []string{"container", "run", "-d",
+		"--name", containerName,
+		"-p", fmt.Sprintf("%d:8888", opts.Port),
+		"-v", notebookDir + ":/workspace",
+		"-v", requirementsDir + ":/requirements:ro",
+		image, "sh", "-c", script,
+	}
+
+	fmt.Printf("🐳 Starting JupyterLab in container '%s' (%s)...\n", containerName, image)
+	cmd := exec.Command(portunixPath, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to start Jupyter container: %v", err)
+	}
+
+	fmt.Println()
+	fmt.Printf("✅ JupyterLab container started: http://localhost:%d\n", opts.Port)
+	fmt.Printf("Access token: portunix container logs %s\n", containerName)
+	fmt.Printf("Stop with:    portunix container rm -f %s\n", containerName)
+	return nil
+}
+
+// jupyterContainerImage returns the official Python image matching the
+// major.minor version of the venv interpreter, e.g. "3.11.5" -> python:3.11-slim
+func jupyterContainerImage(version string) (string, error) {
+	parts := strings.Split(strings.TrimSpace(version), ".")
+	if len(parts) < 2 {
+		return "", fmt.Errorf("unrecognized Python version '%s'", version)
+	}
+	for _, part := range parts[:2] {
+		if _, err := strconv.Atoi(part); err != nil {
+			return "", fmt.Errorf("unrecognized Python version '%s'", version)
+		}
+	}
+	return fmt.Sprintf("python:%s.%s-slim", parts[0], parts[1]), nil
+}
+
+// containerRequirements drops pip freeze entries that only exist on the host:
+// editable installs and packages installed from local paths
+func containerRequirements(freeze string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(freeze, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-e ") || strings.HasPrefix(line, "#") ||
+			strings.Contains(line, " @ file:") {
+			continue
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Delete unregisters the kernel and removes the venv
+func (jm *JupyterManager) Delete(name string) error {
+	vm := jm.venvManager
+	venvPath := filepath.Join(vm.venvBaseDir, name)
+
+	if vm.VenvExists(name) {
+		cmd := exec.Command(vm.getVenvScript(venvPath, "jupyter"), "kernelspec", "remove", "-f", name)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			fmt.Printf("Warning: failed to remove kernel: %v\n%s", err, string(output))
+		}
+	}
+
+	return vm.DeleteVenv(name)
+}
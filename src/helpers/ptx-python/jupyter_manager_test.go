@@ -0,0 +1,41 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import "testing"
+
+func TestJupyterContainerImage(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+		wantErr bool
+	}{
+		{version: "3.11.5", want: "python:3.11-slim"},
+		{version: "3.13.0rc1", want: "python:3.13-slim"},
+		{version: "3.9", want: "python:3.9-slim"},
+		{version: "3", wantErr: true},
+		{version: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := jupyterContainerImage(tt.version)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("jupyterContainerImage(%q) = %q, %v; want %q, error %v", tt.version, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestContainerRequirements(t *testing.T) {
+	freeze := "numpy==1.26.4\n" +
+		"-e git+https://example.com/repo.git@abc123#egg=mylib\n" +
+		"localpkg @ file:///home/user/src/localpkg\n" +
+		"# Editable install with no version control (devtool==0.1)\n" +
+		"requests==2.31.0\n"
+
+	want := "numpy==1.26.4\nrequests==2.31.0\n"
+	if got := containerRequirements(freeze); got != want {
+		t.Errorf("containerRequirements() = %q, want %q", got, want)
+	}
+}
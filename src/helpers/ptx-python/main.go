@@ -5,9 +5,13 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -67,6 +71,8 @@ func handleCommand(args []string) {
 		fmt.Println("Portunix Python Development Helper")
 	case "--list-commands":
 		fmt.Println("python")
+	case "--help-ai":
+		showHelpAI()
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
 		fmt.Println("Supported commands: python")
@@ -85,6 +91,12 @@ func showPythonHelp() {
 	fmt.Println("  init --force                 - Recreate existing venv")
 	fmt.Println("  init --python <version>      - Specify Python version (e.g., 3.11)")
 	fmt.Println()
+	fmt.Println("pyproject.toml Projects (poetry / uv):")
+	fmt.Println("  project info                 - Show detected project manager")
+	fmt.Println("  project install              - Install dependencies (poetry install / uv sync)")
+	fmt.Println("  project run <cmd> [args...]  - Run command in project environment")
+	fmt.Println("  project build                - Build wheel and sdist")
+	fmt.Println()
 	fmt.Println("Virtual Environment Management:")
 	fmt.Println("  venv create <name>           - Create centralized venv (~/.portunix/python/venvs/)")
 	fmt.Println("  venv create --local          - Create project-local venv (./.venv)")
@@ -109,6 +121,22 @@ func showPythonHelp() {
 	fmt.Println("  pip list                     - List installed packages")
 	fmt.Println("  pip freeze                   - Generate requirements.txt")
 	fmt.Println()
+	fmt.Println("Code Quality:")
+	fmt.Println("  lint [paths] [--fix]         - Lint with ruff")
+	fmt.Println("  format [paths] [--check]     - Format with black")
+	fmt.Println("  typecheck [paths]            - Type check with mypy")
+	fmt.Println("  (all support --json; exit codes: 0 clean, 1 issues, 2 tool error)")
+	fmt.Println()
+	fmt.Println("Jupyter:")
+	fmt.Println("  jupyter create <name>        - Create venv with jupyterlab + ipykernel, register kernel")
+	fmt.Println("  jupyter start <name>         - Launch JupyterLab (--container for isolation)")
+	fmt.Println("  jupyter delete <name>        - Remove kernel and venv")
+	fmt.Println()
+	fmt.Println("Testing:")
+	fmt.Println("  test [paths]                 - Run pytest (auto-detects ./.venv)")
+	fmt.Println("  test --coverage              - Coverage report (terminal + XML + HTML)")
+	fmt.Println("  test --junit <out.xml>       - Write JUnit XML report for CI")
+	fmt.Println()
 	fmt.Println("Build & Distribution:")
 	fmt.Println("  build exe <script.py>        - Build standalone executable with PyInstaller")
 	fmt.Println("  build freeze <script.py>     - Build with cx_Freeze")
@@ -138,12 +166,22 @@ func handlePythonCommand(args []string) {
 		handleVenvCommand(subArgs)
 	case "pip":
 		handlePipCommand(subArgs)
+	case "project":
+		handleProjectCommand(subArgs)
 	case "build":
 		handleBuildCommand(subArgs)
+	case "test":
+		handleTestCommand(subArgs)
+	case "jupyter":
+		handleJupyterCommand(subArgs)
+	case "lint", "format", "typecheck":
+		handleQualityCommand(subcommand, subArgs)
 	case "check":
 		handleCheckCommand()
 	case "--help", "-h":
 		showPythonHelp()
+	case "--help-ai":
+		showHelpAI()
 	default:
 		fmt.Printf("Unknown python subcommand: %s\n", subcommand)
 		fmt.Println("Run 'portunix python --help' for available commands")
@@ -480,7 +518,8 @@ func displayVenvsGrouped(venvs []*VenvInfo) {
 		grouped[venv.PythonVersion] = append(grouped[venv.PythonVersion], venv)
 	}
 
-	fmt.Println("Virtual Environments grouped by Python version:\n")
+	fmt.Println("Virtual Environments grouped by Python version:")
+	fmt.Println()
 	for version, venvList := range grouped {
 		fmt.Printf("Python %s (%d environment(s)):\n", version, len(venvList))
 		for _, venv := range venvList {
@@ -975,6 +1014,458 @@ func handlePipFreeze(args []string) {
 	}
 }
 
+// Jupyter command handlers
+func handleJupyterCommand(args []string) {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		showJupyterHelp()
+		return
+	}
+
+	subcommand := args[0]
+	opts := JupyterOptions{
+		NotebookDir: ".",
+		Port:        8888,
+	}
+
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--python":
+			if i+1 < len(args) {
+				opts.PythonVersion = args[i+1]
+				i++
+			}
+		case "--dir":
+			if i+1 < len(args) {
+				opts.NotebookDir = args[i+1]
+				i++
+			}
+		case "--port":
+			if i+1 < len(args) {
+				port, err := strconv.Atoi(args[i+1])
+				if err != nil {
+					fmt.Printf("Error: invalid port: %s\n", args[i+1])
+					os.Exit(1)
+				}
+				opts.Port = port
+				i++
+			}
+		case "--container":
+			opts.Container = true
+		case "--start":
+			opts.Start = true
+		default:
+			if !strings.HasPrefix(args[i], "-") && opts.Name == "" {
+				opts.Name = args[i]
+			}
+		}
+	}
+
+	if opts.Name == "" {
+		fmt.Println("Error: Jupyter environment name required")
+		fmt.Printf("Usage: portunix python jupyter %s <name>\n", subcommand)
+		os.Exit(1)
+	}
+
+	jm, err := NewJupyterManager()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch subcommand {
+	case "create":
+		err = jm.Create(opts)
+		if err == nil && (opts.Start || opts.Container) {
+			err = jm.Start(opts)
+		}
+	case "start":
+		err = jm.Start(opts)
+	case "delete", "rm":
+		err = jm.Delete(opts.Name)
+	default:
+		fmt.Printf("Unknown jupyter subcommand: %s\n", subcommand)
+		fmt.Println("Run 'portunix python jupyter --help' for available commands")
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func showJupyterHelp() {
+	fmt.Println("Usage: portunix python jupyter [subcommand] <name> [options]")
+	fmt.Println()
+	fmt.Println("Jupyter Environment Commands:")
+	fmt.Println("  create <name>           - Create venv, install jupyterlab + ipykernel, register kernel")
+	fmt.Println("  start <name>            - Launch JupyterLab from the environment")
+	fmt.Println("  delete <name>           - Unregister kernel and remove the environment")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --python <version>      - Python version for the new venv")
+	fmt.Println("  --start                 - Launch JupyterLab after create")
+	fmt.Println("  --container             - Launch in an isolated container (via portunix container)")
+	fmt.Println("  --port <port>           - Host port (default: 8888)")
+	fmt.Println("  --dir <path>            - Notebook directory (default: current directory)")
+}
+
+// Test command handler
+func handleTestCommand(args []string) {
+	opts := TestOptions{}
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--venv":
+			if i+1 < len(args) {
+				opts.VenvName = args[i+1]
+				i++
+			}
+		case "--local", "-l":
+			opts.Local = true
+		case "--path":
+			if i+1 < len(args) {
+				opts.Path = args[i+1]
+				i++
+			}
+		case "--coverage", "--cov":
+			opts.Coverage = true
+		case "--cov-source":
+			if i+1 < len(args) {
+				opts.CoverageSource = args[i+1]
+				opts.Coverage = true
+				i++
+			}
+		case "--cov-xml":
+			if i+1 < len(args) {
+				opts.CoverageXML = args[i+1]
+				opts.Coverage = true
+				i++
+			}
+		case "--cov-html":
+			if i+1 < len(args) {
+				opts.CoverageDir = args[i+1]
+				opts.Coverage = true
+				i++
+			}
+		case "--junit":
+			if i+1 < len(args) {
+				opts.JUnitXML = args[i+1]
+				i++
+			}
+		case "--help", "-h":
+			showTestHelp()
+			return
+		case "--":
+			opts.ExtraArgs = append(opts.ExtraArgs, args[i+1:]...)
+			i = len(args)
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				// Unknown flag, pass through to pytest
+				opts.ExtraArgs = append(opts.ExtraArgs, args[i])
+			} else {
+				opts.Targets = append(opts.Targets, args[i])
+			}
+		}
+	}
+
+	tr, err := NewTestRunner()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	exitCode, err := tr.Run(opts)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		fmt.Println("Tip: Create a local venv first with: portunix python init")
+		os.Exit(1)
+	}
+
+	if opts.Coverage && exitCode == 0 {
+		fmt.Println()
+		fmt.Println("Coverage reports:")
+		fmt.Printf("  XML:  %s\n", valueOrDefault(opts.CoverageXML, "coverage.xml"))
+		fmt.Printf("  HTML: %s/index.html\n", valueOrDefault(opts.CoverageDir, "htmlcov"))
+	}
+	if opts.JUnitXML != "" {
+		fmt.Printf("JUnit report: %s\n", opts.JUnitXML)
+	}
+
+	os.Exit(exitCode)
+}
+
+func showTestHelp() {
+	fmt.Println("Usage: portunix python test [paths...] [options]")
+	fmt.Println()
+	fmt.Println("Run pytest inside a virtual environment (pytest is installed automatically).")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --venv <name>           - Use centralized venv")
+	fmt.Println("  --local                 - Use ./.venv (default when present)")
+	fmt.Println("  --path <dir>            - Use venv at custom location")
+	fmt.Println("  --coverage              - Enable coverage (terminal + coverage.xml + htmlcov/)")
+	fmt.Println("  --cov-source <pkg>      - Coverage source (default: .)")
+	fmt.Println("  --cov-xml <file>        - Coverage XML report path")
+	fmt.Println("  --cov-html <dir>        - Coverage HTML report directory")
+	fmt.Println("  --junit <out.xml>       - Write JUnit XML report")
+	fmt.Println("  -- <args>               - Pass remaining arguments to pytest")
+	fmt.Println()
+	fmt.Println("Exit code is pytest's exit code.")
+}
+
+func valueOrDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// Code quality command handlers
+func handleQualityCommand(command string, args []string) {
+	opts := QualityOptions{}
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--json":
+			opts.JSON = true
+		case "--fix":
+			opts.Fix = true
+		case "--check":
+			opts.Check = true
+		case "--help", "-h":
+			showQualityHelp(command)
+			return
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				// Unknown flag, pass through to the underlying tool
+				opts.ExtraArgs = append(opts.ExtraArgs, args[i])
+			} else {
+				opts.Paths = append(opts.Paths, args[i])
+			}
+		}
+	}
+
+	qm, err := NewQualityManager()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(QualityExitError)
+	}
+
+	var result *QualityResult
+	switch command {
+	case "lint":
+		result = qm.Lint(opts)
+	case "format":
+		result = qm.Format(opts)
+	case "typecheck":
+		result = qm.Typecheck(opts)
+	}
+
+	if opts.JSON {
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		outputQualityResultText(result)
+	}
+
+	os.Exit(result.ExitCode)
+}
+
+func outputQualityResultText(result *QualityResult) {
+	fmt.Println()
+	switch result.ExitCode {
+	case QualityExitOK:
+		fmt.Printf("✅ %s: no issues found\n", result.Tool)
+	case QualityExitIssues:
+		if result.IssueCount > 0 {
+			fmt.Printf("❌ %s: %d issues found\n", result.Tool, result.IssueCount)
+		} else {
+			fmt.Printf("❌ %s: issues found\n", result.Tool)
+		}
+	default:
+		fmt.Printf("Error: %s\n", result.Error)
+	}
+}
+
+func showQualityHelp(command string) {
+	fmt.Printf("Usage: portunix python %s [paths...] [options]\n", command)
+	fmt.Println()
+	switch command {
+	case "lint":
+		fmt.Println("Lint Python code with ruff.")
+		fmt.Println()
+		fmt.Println("  --fix                   - Apply automatic fixes")
+	case "format":
+		fmt.Println("Format Python code with black.")
+		fmt.Println()
+		fmt.Println("  --check                 - Only check, don't rewrite files")
+	case "typecheck":
+		fmt.Println("Type check Python code with mypy.")
+	}
+	fmt.Println("  --json                  - Output diagnostics as JSON")
+	fmt.Println()
+	fmt.Println("Tools are installed automatically into ~/.portunix/python/tools-venv.")
+	fmt.Println("Other flags are passed through to the underlying tool.")
+	fmt.Println()
+	fmt.Println("Exit codes: 0 = no issues, 1 = issues found, 2 = tool error")
+}
+
+// Project command handlers
+func handleProjectCommand(args []string) {
+	if len(args) == 0 {
+		showProjectHelp()
+		return
+	}
+
+	subcommand := args[0]
+	subArgs := args[1:]
+
+	switch subcommand {
+	case "info":
+		handleProjectInfo(subArgs)
+	case "install":
+		handleProjectInstall(subArgs)
+	case "run":
+		handleProjectRun(subArgs)
+	case "build":
+		handleProjectBuild(subArgs)
+	case "--help", "-h":
+		showProjectHelp()
+	default:
+		fmt.Printf("Unknown project subcommand: %s\n", subcommand)
+		fmt.Println("Run 'portunix python project --help' for available commands")
+	}
+}
+
+func showProjectHelp() {
+	fmt.Println("Usage: portunix python project [subcommand]")
+	fmt.Println()
+	fmt.Println("pyproject.toml Project Commands:")
+	fmt.Println("  info                    - Show detected project and project manager")
+	fmt.Println("  install                 - Install dependencies (poetry install / uv sync)")
+	fmt.Println("  run <cmd> [args...]     - Run command inside the project environment")
+	fmt.Println("  build                   - Build wheel and sdist distributions")
+	fmt.Println()
+	fmt.Println("Options (before the command or tool arguments):")
+	fmt.Println("  --tool <poetry|uv>      - Override project manager detection")
+	fmt.Println("  --path <path>           - Project path (default: current directory)")
+	fmt.Println()
+	fmt.Println("'project run' exits with the exit code of the command.")
+	fmt.Println("Detection order: poetry.lock, uv.lock, [tool.poetry], [tool.uv], default uv.")
+	fmt.Println("Missing tools are installed automatically via 'portunix install <tool>'.")
+}
+
+// parseProjectFlags extracts leading --tool and --path flags; remaining args are
+// returned untouched. Parsing stops at the first other argument (the command or a
+// tool flag) or at "--", so arguments of the command are never consumed.
+func parseProjectFlags(args []string) (tool string, projectPath string, rest []string) {
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--tool":
+			if i+1 < len(args) {
+				tool = args[i+1]
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				projectPath = args[i+1]
+				i++
+			}
+		case "--":
+			return tool, projectPath, args[i+1:]
+		default:
+			return tool, projectPath, args[i:]
+		}
+	}
+	return
+}
+
+// detectProject resolves the project for project subcommands or exits with an error
+func detectProject(tool, projectPath string) (*ProjectManager, *ProjectInfo) {
+	pm, err := NewProjectManager(projectPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	info, err := pm.Detect(tool)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		fmt.Println("Tip: Use 'portunix python init' for requirements.txt based projects")
+		os.Exit(1)
+	}
+
+	return pm, info
+}
+
+func handleProjectInfo(args []string) {
+	tool, projectPath, _ := parseProjectFlags(args)
+	_, info := detectProject(tool, projectPath)
+
+	name := info.Name
+	if name == "" {
+		name = "(unnamed)"
+	}
+
+	fmt.Printf("Project: %s\n", name)
+	fmt.Printf("Location: %s\n", info.Dir)
+	fmt.Printf("Project manager: %s (detected by %s)\n", info.Tool, info.DetectedBy)
+	if info.HasLockFile {
+		fmt.Println("Lock file: present")
+	} else {
+		fmt.Println("Lock file: missing")
+	}
+
+	if path, err := findProjectTool(info.Tool); err == nil {
+		fmt.Printf("%s: %s\n", info.Tool, path)
+	} else {
+		fmt.Printf("%s: not installed (will be installed on first use)\n", info.Tool)
+	}
+}
+
+func handleProjectInstall(args []string) {
+	tool, projectPath, rest := parseProjectFlags(args)
+	pm, info := detectProject(tool, projectPath)
+
+	if err := pm.Install(info, rest); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("✅ Dependencies installed successfully")
+}
+
+func handleProjectRun(args []string) {
+	tool, projectPath, rest := parseProjectFlags(args)
+	if len(rest) == 0 {
+		fmt.Println("Error: Command required")
+		fmt.Println("Usage: portunix python project run <cmd> [args...]")
+		os.Exit(1)
+	}
+
+	pm, info := detectProject(tool, projectPath)
+
+	if err := pm.Run(info, rest); err != nil {
+		// The command reported its own failure; exit with its status
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func handleProjectBuild(args []string) {
+	tool, projectPath, rest := parseProjectFlags(args)
+	pm, info := detectProject(tool, projectPath)
+
+	if err := pm.Build(info, rest); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
 // Build command handlers
 func handleBuildCommand(args []string) {
 	if len(args) == 0 {
@@ -1018,13 +1509,22 @@ func showBuildHelp() {
 	fmt.Println("  --console               - Create console application (default)")
 	fmt.Println("  --windowed              - Create windowed application (no console)")
 	fmt.Println("  --icon <file.ico>       - Set application icon")
-	fmt.Println("  --distpath <path>       - Output directory (default: dist)")
+	fmt.Println("  --spec <file.spec>      - Use existing spec file (default: generate <name>.spec)")
+	fmt.Println("                            A generated <name>.spec is regenerated when --onefile,")
+	fmt.Println("                            --windowed or --icon change, unless it was edited")
+	fmt.Println("  --distpath <path>       - Output directory (default: dist/<os>-<arch>)")
 	fmt.Println()
 	fmt.Println("Build freeze options:")
 	fmt.Println("  --venv <name>           - Use specific virtual environment")
 	fmt.Println("  --name <name>           - Set custom executable name")
-	fmt.Println("  --target-version <ver>  - Target Python version")
-	fmt.Println("  --distpath <path>       - Output directory")
+	fmt.Println("  --windowed              - Create GUI application (no console)")
+	fmt.Println("  --icon <file>           - Set application icon")
+	fmt.Println("  --target-version <ver>  - Python version for the build venv")
+	fmt.Println("  --distpath <path>       - Output directory (default: dist/<os>-<arch>)")
+	fmt.Println()
+	fmt.Println("Without --venv, ./.venv is used when present; otherwise a build venv of the project")
+	fmt.Println("(~/.portunix/python/build-<project>-<hash>-venv) is created and requirements.txt")
+	fmt.Println("installed into it.")
 	fmt.Println()
 	fmt.Println("Build wheel/sdist options:")
 	fmt.Println("  --venv <name>           - Use specific virtual environment")
@@ -1065,6 +1565,11 @@ func handleBuildExe(args []string) {
 				opts.OutputDir = args[i+1]
 				i++
 			}
+		case "--spec":
+			if i+1 < len(args) {
+				opts.SpecFile = args[i+1]
+				i++
+			}
 		case "--onefile":
 			opts.OneFile = true
 		case "--console":
@@ -1117,11 +1622,22 @@ func handleBuildFreeze(args []string) {
 				opts.TargetVersion = args[i+1]
 				i++
 			}
+		case "--icon":
+			if i+1 < len(args) {
+				opts.Icon = args[i+1]
+				i++
+			}
 		case "--distpath":
 			if i+1 < len(args) {
 				opts.OutputDir = args[i+1]
 				i++
 			}
+		case "--windowed":
+			opts.Windowed = true
+		case "--onefile":
+			fmt.Println("Warning: cx_Freeze does not support single-file output; use 'build exe --onefile' instead")
+		default:
+			opts.ExtraArgs = append(opts.ExtraArgs, args[i])
 		}
 	}
 
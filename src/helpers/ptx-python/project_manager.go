@@ -0,0 +1,277 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Supported pyproject.toml project managers
+const (
+	ProjectToolPoetry = "poetry"
+	ProjectToolUV     = "uv"
+)
+
+// ProjectInfo describes a pyproject.toml based Python project
+type ProjectInfo struct {
+	Dir         string // Project root directory
+	PyProject   string // Path to pyproject.toml
+	Name        string // Project name from [project] or [tool.poetry]
+	Tool        string // Project manager (poetry or uv)
+	DetectedBy  string // Reason the tool was selected
+	HasLockFile bool   // True if poetry.lock / uv.lock exists
+}
+
+// ProjectManager handles poetry/uv based project operations
+type ProjectManager struct {
+	projectDir string
+}
+
+// NewProjectManager creates a new project manager for the given directory
+func NewProjectManager(projectDir string) (*ProjectManager, error) {
+	if projectDir == "" {
+		projectDir = "."
+	}
+
+	absDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project path: %v", err)
+	}
+
+	return &ProjectManager{
+		projectDir: absDir,
+	}, nil
+}
+
+// Detect inspects pyproject.toml and lock files to decide which tool manages the project.
+// toolOverride forces a specific tool (poetry or uv) regardless of detection.
+func (pm *ProjectManager) Detect(toolOverride string) (*ProjectInfo, error) {
+	pyproject := filepath.Join(pm.projectDir, "pyproject.toml")
+	data, err := os.ReadFile(pyproject)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no pyproject.toml found in %s", pm.projectDir)
+		}
+		return nil, fmt.Errorf("failed to read pyproject.toml: %v", err)
+	}
+
+	content := string(data)
+	info := &ProjectInfo{
+		Dir:       pm.projectDir,
+		PyProject: pyproject,
+		Name:      parsePyProjectName(content),
+	}
+
+	if toolOverride != "" {
+		switch toolOverride {
+		case ProjectToolPoetry, ProjectToolUV:
+			info.Tool = toolOverride
+			info.DetectedBy = "--tool flag"
+		default:
+			return nil, fmt.Errorf("unsupported project tool: %s (supported: poetry, uv)", toolOverride)
+		}
+	} else {
+		switch {
+		case fileExists(filepath.Join(pm.projectDir, "poetry.lock")):
+			info.Tool = ProjectToolPoetry
+			info.DetectedBy = "poetry.lock"
+		case fileExists(filepath.Join(pm.projectDir, "uv.lock")):
+			info.Tool = ProjectToolUV
+			info.DetectedBy = "uv.lock"
+		case strings.Contains(content, "[tool.poetry]"):
+			info.Tool = ProjectToolPoetry
+			info.DetectedBy = "[tool.poetry] section"
+		case strings.Contains(content, "[tool.uv]"):
+			info.Tool = ProjectToolUV
+			info.DetectedBy = "[tool.uv] section"
+		default:
+			// PEP 621 project without tool-specific hints: uv is the Portunix default
+			info.Tool = ProjectToolUV
+			info.DetectedBy = "default for PEP 621 projects"
+		}
+	}
+
+	switch info.Tool {
+	case ProjectToolPoetry:
+		info.HasLockFile = fileExists(filepath.Join(pm.projectDir, "poetry.lock"))
+	case ProjectToolUV:
+		info.HasLockFile = fileExists(filepath.Join(pm.projectDir, "uv.lock"))
+	}
+
+	return info, nil
+}
+
+// Install installs project dependencies with the detected tool
+func (pm *ProjectManager) Install(info *ProjectInfo, extraArgs []string) error {
+	var args []string
+	switch info.Tool {
+	case ProjectToolPoetry:
+		args = append([]string{"install"}, extraArgs...)
+	case ProjectToolUV:
+		args = append([]string{"sync"}, extraArgs...)
+	}
+
+	fmt.Printf("📦 Installing dependencies with %s...\n", info.Tool)
+	return pm.runTool(info, args)
+}
+
+// Run executes a command inside the project environment
+func (pm *ProjectManager) Run(info *ProjectInfo, command []string) error {
+	if len(command) == 0 {
+		return fmt.Errorf("command required")
+	}
+
+	args := append([]string{"run"}, command...)
+	return pm.runTool(info, args)
+}
+
+// Build builds wheel and sdist distributions with the detected tool
+func (pm *ProjectManager) Build(info *ProjectInfo, extraArgs []string) error {
+	args := append([]string{"build"}, extraArgs...)
+
+	fmt.Printf("🔨 Building project with %s...\n", info.Tool)
+	if err := pm.runTool(info, args); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n✅ Project built successfully!\n")
+	fmt.Printf("Output: %s\n", filepath.Join(info.Dir, "dist"))
+	return nil
+}
+
+// runTool ensures the project tool is available and runs it in the project directory
+func (pm *ProjectManager) runTool(info *ProjectInfo, args []string) error {
+	toolPath, err := ensureProjectTool(info.Tool)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(toolPath, args...)
+	cmd.Dir = info.Dir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s failed: %w", info.Tool, args[0], err)
+	}
+	return nil
+}
+
+// ensureProjectTool locates poetry/uv and installs it through portunix install when missing
+func ensureProjectTool(tool string) (string, error) {
+	if path, err := findProjectTool(tool); err == nil {
+		return path, nil
+	}
+
+	fmt.Printf("%s not found. Installing via portunix install %s...\n", tool, tool)
+
+	portunixPath, err := findPortunixBinary()
+	if err != nil {
+		return "", fmt.Errorf("%s is not installed and portunix binary was not found: %v", tool, err)
+	}
+
+	cmd := exec.Command(portunixPath, "install", tool)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to install %s: %v", tool, err)
+	}
+
+	path, err := findProjectTool(tool)
+	if err != nil {
+		return "", fmt.Errorf("%s was installed but is not on PATH; restart your shell and retry", tool)
+	}
+	return path, nil
+}
+
+// findProjectTool looks for the tool in PATH and in the default user install locations
+func findProjectTool(tool string) (string, error) {
+	if path, err := exec.LookPath(tool); err == nil {
+		return path, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	binName := tool
+	if runtime.GOOS == "windows" {
+		binName += ".exe"
+	}
+
+	// Official installers of uv and poetry place binaries in ~/.local/bin (or ~/.cargo/bin for older uv)
+	candidates := []string{
+		filepath.Join(homeDir, ".local", "bin", binName),
+		filepath.Join(homeDir, ".cargo", "bin", binName),
+	}
+	if runtime.GOOS == "windows" {
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			candidates = append(candidates, filepath.Join(appData, "Python", "Scripts", binName))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if fileExists(candidate) {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s not found", tool)
+}
+
+// findPortunixBinary finds the main portunix binary (same directory as helper, then PATH)
+func findPortunixBinary() (string, error) {
+	portunixName := "portunix"
+	if runtime.GOOS == "windows" {
+		portunixName = "portunix.exe"
+	}
+
+	if execPath, err := os.Executable(); err == nil {
+		portunixPath := filepath.Join(filepath.Dir(execPath), portunixName)
+		if fileExists(portunixPath) {
+			return portunixPath, nil
+		}
+	}
+
+	if path, err := exec.LookPath(portunixName); err == nil {
+		return path, nil
+	}
+
+	return "", fmt.Errorf("portunix not found next to helper or in PATH")
+}
+
+// parsePyProjectName extracts the project name from [project] or [tool.poetry] sections
+func parsePyProjectName(content string) string {
+	section := ""
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			section = line
+			continue
+		}
+		if section != "[project]" && section != "[tool.poetry]" {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found || strings.TrimSpace(key) != "name" {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(value), "\"'")
+	}
+	return ""
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,29 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseProjectFlags(t *testing.T) {
+	for _, c := range []struct {
+		args             []string
+		tool, path, rest string
+	}{
+		{[]string{"--tool", "uv", "--path", "app", "pytest", "-x"}, "uv", "app", "pytest -x"},
+		// Flags after the command belong to the command
+		{[]string{"script.py", "--path", "x", "--tool", "y"}, "", "", "script.py --path x --tool y"},
+		{[]string{"--path", "app", "--", "--tool", "z"}, "", "app", "--tool z"},
+		{[]string{"--no-root", "--path", "x"}, "", "", "--no-root --path x"},
+		{nil, "", "", ""},
+	} {
+		tool, path, rest := parseProjectFlags(c.args)
+		if tool != c.tool || path != c.path || strings.Join(rest, " ") != c.rest {
+			t.Errorf("%v: got %q %q %q", c.args, tool, path, rest)
+		}
+	}
+}
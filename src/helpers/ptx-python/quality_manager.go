@@ -0,0 +1,365 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Unified exit codes for code quality commands
+const (
+	QualityExitOK     = 0 // No issues found
+	QualityExitIssues = 1 // Tool ran successfully and reported issues
+	QualityExitError  = 2 // Tool could not be installed or executed
+)
+
+// qualityToolsVenv is the name of the dedicated venv holding ruff, black and mypy
+const qualityToolsVenv = "tools"
+
+// Diagnostic is a single issue reported by a code quality tool
+type Diagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// QualityResult is the unified result of a lint/format/typecheck run
+type QualityResult struct {
+	Tool        string       `json:"tool"`
+	Command     string       `json:"command"`
+	ExitCode    int          `json:"exit_code"`
+	IssueCount  int          `json:"issue_count"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// QualityOptions holds options shared by code quality commands
+type QualityOptions struct {
+	Paths     []string
+	Fix       bool // lint: apply automatic fixes
+	Check     bool // format: only check, don't rewrite files
+	JSON      bool
+	ExtraArgs []string
+}
+
+// QualityManager runs ruff, black and mypy from a dedicated tools venv
+type QualityManager struct {
+	venvManager *VenvManager
+}
+
+// NewQualityManager creates a new code quality manager
+func NewQualityManager() (*QualityManager, error) {
+	vm, err := NewVenvManager()
+	if err != nil {
+		return nil, err
+	}
+
+	return &QualityManager{
+		venvManager: vm,
+	}, nil
+}
+
+// Lint runs ruff check
+func (qm *QualityManager) Lint(opts QualityOptions) *QualityResult {
+	result := &QualityResult{Tool: "ruff", Command: "lint", Diagnostics: []Diagnostic{}}
+
+	ruff, err := qm.toolPath(ToolPackage{Package: "ruff", Binary: "ruff"})
+	if err != nil {
+		return result.fail(err)
+	}
+
+	args := []string{"check"}
+	if opts.Fix {
+		args = append(args, "--fix")
+	}
+	// The concise text format has one parseable line per violation
+	if opts.JSON {
+		args = append(args, "--output-format", "json")
+	} else {
+		args = append(args, "--output-format", "concise")
+	}
+	args = append(args, opts.ExtraArgs...)
+	args = append(args, qualityPaths(opts.Paths)...)
+
+	stdout, stderr, exitCode, err := qm.run(ruff, args, !opts.JSON)
+	if err != nil {
+		return result.fail(err)
+	}
+
+	// ruff: 0 = clean, 1 = violations, 2 = abnormal termination
+	if exitCode > 1 {
+		return result.fail(fmt.Errorf("ruff failed: %s", strings.TrimSpace(stderr)))
+	}
+
+	if opts.JSON {
+		diags, err := parseRuffJSON(stdout)
+		if err != nil {
+			return result.fail(err)
+		}
+		result.Diagnostics = diags
+	} else {
+		result.Diagnostics = parseRuffConcise(stdout)
+	}
+
+	return result.finish(exitCode)
+}
+
+// Format runs black (or black --check)
+func (qm *QualityManager) Format(opts QualityOptions) *QualityResult {
+	result := &QualityResult{Tool: "black", Command: "format", Diagnostics: []Diagnostic{}}
+
+	black, err := qm.toolPath(ToolPackage{Package: "black", Binary: "black"})
+	if err != nil {
+		return result.fail(err)
+	}
+
+	args := []string{}
+	if opts.Check {
+		args = append(args, "--check")
+	}
+	args = append(args, opts.ExtraArgs...)
+	args = append(args, qualityPaths(opts.Paths)...)
+
+	_, stderr, exitCode, err := qm.run(black, args, !opts.JSON)
+	if err != nil {
+		return result.fail(err)
+	}
+
+	// black: 0 = nothing to do, 1 = files would be reformatted (--check), 123 = internal error
+	if exitCode > 1 {
+		return result.fail(fmt.Errorf("black failed: %s", strings.TrimSpace(stderr)))
+	}
+
+	result.Diagnostics = parseBlackOutput(stderr, opts.Check)
+	if !opts.Check {
+		// Files were rewritten: report them, but formatting itself is not a failure
+		return result.finish(QualityExitOK)
+	}
+	return result.finish(exitCode)
+}
+
+// Typecheck runs mypy
+func (qm *QualityManager) Typecheck(opts QualityOptions) *QualityResult {
+	result := &QualityResult{Tool: "mypy", Command: "typecheck", Diagnostics: []Diagnostic{}}
+
+	mypy, err := qm.toolPath(ToolPackage{Package: "mypy", Binary: "mypy"})
+	if err != nil {
+		return result.fail(err)
+	}
+
+	args := []string{"--show-column-numbers", "--show-error-codes", "--no-error-summary", "--no-color-output"}
+	args = append(args, opts.ExtraArgs...)
+	args = append(args, qualityPaths(opts.Paths)...)
+
+	stdout, stderr, exitCode, err := qm.run(mypy, args, !opts.JSON)
+	if err != nil {
+		return result.fail(err)
+	}
+
+	// mypy: 0 = clean, 1 = type errors, 2 = fatal error
+	if exitCode > 1 {
+		return result.fail(fmt.Errorf("mypy failed: %s", strings.TrimSpace(stderr+stdout)))
+	}
+
+	result.Diagnostics = parseMypyOutput(stdout)
+
+	return result.finish(exitCode)
+}
+
+// toolPath ensures the tool is installed in the tools venv and returns its script path
+func (qm *QualityManager) toolPath(tool ToolPackage) (string, error) {
+	venvPath, err := qm.venvManager.EnsureToolVenv(qualityToolsVenv, tool)
+	if err != nil {
+		return "", err
+	}
+	return qm.venvManager.getVenvScript(venvPath, tool.Binary), nil
+}
+
+// run executes a tool, optionally streaming its output, and returns captured output and exit code.
+// The output is captured in both modes so that issues are counted in text mode too.
+func (qm *QualityManager) run(binary string, args []string, stream bool) (string, string, int, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(binary, args...)
+	if stream {
+		cmd.Stdout = &teeWriter{buf: &stdout, out: os.Stdout}
+		cmd.Stderr = &teeWriter{buf: &stderr, out: os.Stderr}
+	} else {
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return stdout.String(), stderr.String(), exitErr.ExitCode(), nil
+		}
+		return "", "", QualityExitError, fmt.Errorf("failed to run %s: %v", binary, err)
+	}
+
+	return stdout.String(), stderr.String(), 0, nil
+}
+
+// fail marks the result as a tool error
+func (r *QualityResult) fail(err error) *QualityResult {
+	r.ExitCode = QualityExitError
+	r.Error = err.Error()
+	return r
+}
+
+// finish maps the tool exit code to the unified exit codes. Notes and
+// informational diagnostics (mypy hints, reformatted files) are not issues.
+func (r *QualityResult) finish(toolExitCode int) *QualityResult {
+	r.IssueCount = 0
+	for _, d := range r.Diagnostics {
+		if d.Severity == "error" || d.Severity == "warning" {
+			r.IssueCount++
+		}
+	}
+	if toolExitCode == 0 {
+		r.ExitCode = QualityExitOK
+	} else {
+		r.ExitCode = QualityExitIssues
+	}
+	return r
+}
+
+// teeWriter writes to an output stream while keeping a copy in a buffer
+type teeWriter struct {
+	buf *bytes.Buffer
+	out *os.File
+}
+
+func (t *teeWriter) Write(p []byte) (int, error) {
+	t.buf.Write(p)
+	return t.out.Write(p)
+}
+
+// qualityPaths defaults the target paths to the current directory
+func qualityPaths(paths []string) []string {
+	if len(paths) == 0 {
+		return []string{"."}
+	}
+	return paths
+}
+
+// parseRuffJSON converts ruff --output-format json into diagnostics
+func parseRuffJSON(output string) ([]Diagnostic, error) {
+	var raw []struct {
+		Code     string `json:"code"`
+		Message  string `json:"message"`
+		Filename string `json:"filename"`
+		Location struct {
+			Row    int `json:"row"`
+			Column int `json:"column"`
+		} `json:"location"`
+	}
+
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return []Diagnostic{}, nil
+	}
+	if err := json.Unmarshal([]byte(output), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse ruff output: %v", err)
+	}
+
+	diags := make([]Diagnostic, 0, len(raw))
+	for _, r := range raw {
+		diags = append(diags, Diagnostic{
+			File:     r.Filename,
+			Line:     r.Location.Row,
+			Column:   r.Location.Column,
+			Code:     r.Code,
+			Severity: "error",
+			Message:  r.Message,
+		})
+	}
+	return diags, nil
+}
+
+// ruffConcisePattern matches "file.py:1:8: F401 [*] message"; syntax errors have no code
+var ruffConcisePattern = regexp.MustCompile(`^(.+?):(\d+):(\d+): (?:([A-Z]+\d+) )?(?:\[\*\] )?(.*)$`)
+
+// parseRuffConcise converts ruff --output-format concise into diagnostics
+func parseRuffConcise(output string) []Diagnostic {
+	diags := []Diagnostic{}
+	for _, line := range strings.Split(output, "\n") {
+		m := ruffConcisePattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+		lineNo, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		diags = append(diags, Diagnostic{
+			File:     m[1],
+			Line:     lineNo,
+			Column:   col,
+			Code:     m[4],
+			Severity: "error",
+			Message:  m[5],
+		})
+	}
+	return diags
+}
+
+// parseBlackOutput converts black's "would reformat" / "reformatted" lines into diagnostics
+func parseBlackOutput(output string, check bool) []Diagnostic {
+	prefix := "reformatted "
+	message := "file was reformatted"
+	severity := "info"
+	if check {
+		prefix = "would reformat "
+		message = "file would be reformatted"
+		severity = "warning"
+	}
+
+	diags := []Diagnostic{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, prefix) {
+			diags = append(diags, Diagnostic{
+				File:     strings.TrimPrefix(line, prefix),
+				Code:     "format",
+				Severity: severity,
+				Message:  message,
+			})
+		}
+	}
+	return diags
+}
+
+// mypyLinePattern matches "file.py:12:5: error: message  [code]"
+var mypyLinePattern = regexp.MustCompile(`^(.+?):(\d+):(?:(\d+):)? (error|warning|note): (.*?)(?:\s+\[([\w-]+)\])?$`)
+
+// parseMypyOutput converts mypy text output into diagnostics
+func parseMypyOutput(output string) []Diagnostic {
+	diags := []Diagnostic{}
+	for _, line := range strings.Split(output, "\n") {
+		m := mypyLinePattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+		lineNo, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		diags = append(diags, Diagnostic{
+			File:     m[1],
+			Line:     lineNo,
+			Column:   col,
+			Code:     m[6],
+			Severity: m[4],
+			Message:  m[5],
+		})
+	}
+	return diags
+}
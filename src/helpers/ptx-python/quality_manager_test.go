@@ -0,0 +1,137 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRuffJSON(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		output string
+		want   []Diagnostic
+	}{
+		{"clean", "[]\n", []Diagnostic{}},
+		{"empty", "", []Diagnostic{}},
+		{"violations", `[
+  {
+    "cell": null,
+    "code": "F401",
+    "end_location": {"column": 10, "row": 1},
+    "filename": "/work/app/main.py",
+    "fix": {"applicability": "safe", "edits": [], "message": "Remove unused import: ` + "`os`" + `"},
+    "location": {"column": 8, "row": 1},
+    "message": "` + "`os`" + ` imported but unused",
+    "noqa_row": 1,
+    "url": "https://docs.astral.sh/ruff/rules/unused-import"
+  },
+  {
+    "code": "E711",
+    "filename": "/work/app/util.py",
+    "location": {"column": 9, "row": 12},
+    "message": "Comparison to ` + "`None`" + ` should be ` + "`cond is None`" + `"
+  }
+]`, []Diagnostic{
+			{File: "/work/app/main.py", Line: 1, Column: 8, Code: "F401", Severity: "error", Message: "`os` imported but unused"},
+			{File: "/work/app/util.py", Line: 12, Column: 9, Code: "E711", Severity: "error", Message: "Comparison to `None` should be `cond is None`"},
+		}},
+	} {
+		got, err := parseRuffJSON(c.output)
+		if err != nil || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %+v, %v", c.name, got, err)
+		}
+	}
+	if _, err := parseRuffJSON("error: Failed to parse pyproject.toml"); err == nil {
+		t.Error("invalid output accepted")
+	}
+}
+
+func TestParseRuffConcise(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		output string
+		want   []Diagnostic
+	}{
+		{"clean", "All checks passed!\n", []Diagnostic{}},
+		{"violations", "app/main.py:1:8: F401 [*] `os` imported but unused\r\n" +
+			"app/util.py:12:9: E711 Comparison to `None` should be `cond is None`\n" +
+			"app/broken.py:3:5: SyntaxError: Expected an expression\n" +
+			"Found 3 errors.\n" +
+			"[*] 1 fixable with the `--fix` option.\n",
+			[]Diagnostic{
+				{File: "app/main.py", Line: 1, Column: 8, Code: "F401", Severity: "error", Message: "`os` imported but unused"},
+				{File: "app/util.py", Line: 12, Column: 9, Code: "E711", Severity: "error", Message: "Comparison to `None` should be `cond is None`"},
+				{File: "app/broken.py", Line: 3, Column: 5, Severity: "error", Message: "SyntaxError: Expected an expression"},
+			}},
+	} {
+		if got := parseRuffConcise(c.output); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %+v", c.name, got)
+		}
+	}
+}
+
+func TestParseBlackOutput(t *testing.T) {
+	check := "would reformat /work/app/main.py\nwould reformat /work/app/util.py\n\nOh no! 💥 💔 💥\n2 files would be reformatted, 3 files would be left unchanged.\n"
+	rewrite := "reformatted /work/app/main.py\n\nAll done! ✨ 🍰 ✨\n1 file reformatted, 4 files left unchanged.\n"
+	for _, c := range []struct {
+		name   string
+		output string
+		check  bool
+		want   []Diagnostic
+	}{
+		{"check", check, true, []Diagnostic{
+			{File: "/work/app/main.py", Code: "format", Severity: "warning", Message: "file would be reformatted"},
+			{File: "/work/app/util.py", Code: "format", Severity: "warning", Message: "file would be reformatted"},
+		}},
+		{"rewrite", rewrite, false, []Diagnostic{
+			{File: "/work/app/main.py", Code: "format", Severity: "info", Message: "file was reformatted"},
+		}},
+		{"unchanged", "All done! ✨ 🍰 ✨\n5 files left unchanged.\n", true, []Diagnostic{}},
+	} {
+		if got := parseBlackOutput(c.output, c.check); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %+v", c.name, got)
+		}
+	}
+}
+
+func TestParseMypyOutput(t *testing.T) {
+	output := "app/main.py:10:12: error: Incompatible return value type (got \"str\", expected \"int\")  [return-value]\n" +
+		"app/main.py:14: error: Name \"undefined_name\" is not defined  [name-defined]\n" +
+		"app/util.py:3:1: note: See https://mypy.readthedocs.io/en/stable/running_mypy.html#missing-imports\n" +
+		"app/util.py:3:1: error: Library stubs not installed for \"requests\"  [import-untyped]\r\n"
+	want := []Diagnostic{
+		{File: "app/main.py", Line: 10, Column: 12, Code: "return-value", Severity: "error", Message: "Incompatible return value type (got \"str\", expected \"int\")"},
+		{File: "app/main.py", Line: 14, Code: "name-defined", Severity: "error", Message: "Name \"undefined_name\" is not defined"},
+		{File: "app/util.py", Line: 3, Column: 1, Severity: "note", Message: "See https://mypy.readthedocs.io/en/stable/running_mypy.html#missing-imports"},
+		{File: "app/util.py", Line: 3, Column: 1, Code: "import-untyped", Severity: "error", Message: "Library stubs not installed for \"requests\""},
+	}
+	if got := parseMypyOutput(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v", got)
+	}
+	if got := parseMypyOutput("Success: no issues found in 4 source files\n"); len(got) != 0 {
+		t.Errorf("clean run: got %+v", got)
+	}
+}
+
+func TestQualityResultFinish(t *testing.T) {
+	for _, c := range []struct {
+		name       string
+		diags      []Diagnostic
+		toolExit   int
+		wantExit   int
+		wantIssues int
+	}{
+		{"clean", []Diagnostic{}, 0, QualityExitOK, 0},
+		{"issues", parseMypyOutput("a.py:1:1: error: x  [misc]\na.py:1:1: note: y\n"), 1, QualityExitIssues, 1},
+		{"reformatted", parseBlackOutput("reformatted a.py\n", false), 0, QualityExitOK, 0},
+	} {
+		r := (&QualityResult{Diagnostics: c.diags}).finish(c.toolExit)
+		if r.ExitCode != c.wantExit || r.IssueCount != c.wantIssues {
+			t.Errorf("%s: exit %d, issues %d", c.name, r.ExitCode, r.IssueCount)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// TestOptions holds options for running pytest
+type TestOptions struct {
+	VenvName       string
+	Local          bool
+	Path           string
+	Coverage       bool
+	CoverageSource string // --cov=<source>, defaults to current directory
+	CoverageDir    string // HTML report directory
+	CoverageXML    string // XML report file
+	JUnitXML       string // JUnit XML report file for CI
+	Targets        []string
+	ExtraArgs      []string
+}
+
+// TestRunner runs pytest inside a project virtual environment
+type TestRunner struct {
+	venvManager *VenvManager
+}
+
+// NewTestRunner creates a new test runner
+func NewTestRunner() (*TestRunner, error) {
+	vm, err := NewVenvManager()
+	if err != nil {
+		return nil, err
+	}
+
+	return &TestRunner{
+		venvManager: vm,
+	}, nil
+}
+
+// Run executes pytest and returns pytest's exit code
+func (tr *TestRunner) Run(opts TestOptions) (int, error) {
+	target, err := tr.venvManager.ResolveVenvPath(opts.Local, opts.Path, opts.VenvName, true)
+	if err != nil {
+		return 0, err
+	}
+
+	if !tr.venvManager.VenvExistsAtPath(target.Path) {
+		return 0, fmt.Errorf("virtual environment does not exist at '%s'", target.Path)
+	}
+
+	required := []string{"pytest"}
+	if opts.Coverage {
+		required = append(required, "pytest-cov")
+	}
+	if err := tr.ensurePackages(target.Path, required); err != nil {
+		return 0, err
+	}
+
+	args := append([]string{"-m", "pytest"}, tr.buildPytestArgs(opts)...)
+
+	fmt.Printf("🧪 Running pytest in %s...\n", target.Path)
+	cmd := exec.Command(tr.venvManager.getPythonExecutable(target.Path), args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return exitErr.ExitCode(), nil
+		}
+		return 0, fmt.Errorf("failed to run pytest: %v", err)
+	}
+
+	return 0, nil
+}
+
+// buildPytestArgs constructs pytest arguments from options
+func (tr *TestRunner) buildPytestArgs(opts TestOptions) []string {
+	args := []string{}
+
+	if opts.Coverage {
+		source := opts.CoverageSource
+		if source == "" {
+			source = "."
+		}
+		xmlFile := opts.CoverageXML
+		if xmlFile == "" {
+			xmlFile = "coverage.xml"
+		}
+		htmlDir := opts.CoverageDir
+		if htmlDir == "" {
+			htmlDir = "htmlcov"
+		}
+
+		args = append(args,
+			"--cov="+source,
+			"--cov-report=term-missing",
+			"--cov-report=xml:"+xmlFile,
+			"--cov-report=html:"+htmlDir,
+		)
+	}
+
+	if opts.JUnitXML != "" {
+		args = append(args, "--junitxml="+opts.JUnitXML)
+	}
+
+	args = append(args, opts.ExtraArgs...)
+	args = append(args, opts.Targets...)
+
+	return args
+}
+
+// ensurePackages installs missing packages into the venv
+func (tr *TestRunner) ensurePackages(venvPath string, packages []string) error {
+	pythonExe := tr.venvManager.getPythonExecutable(venvPath)
+
+	for _, pkg := range packages {
+		checkCmd := exec.Command(pythonExe, "-m", "pip", "show", "--quiet", pkg)
+		if err := checkCmd.Run(); err == nil {
+			continue
+		}
+
+		fmt.Printf("%s not found. Installing %s...\n", pkg, pkg)
+		if err := tr.venvManager.InstallPackageAtPath(venvPath, pkg); err != nil {
+			return fmt.Errorf("failed to install %s: %v", pkg, err)
+		}
+	}
+
+	return nil
+}
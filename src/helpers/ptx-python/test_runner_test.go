@@ -0,0 +1,56 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestBuildPytestArgs(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		opts TestOptions
+		want []string
+	}{
+		{"plain", TestOptions{}, []string{}},
+		{"coverage defaults", TestOptions{Coverage: true}, []string{
+			"--cov=.", "--cov-report=term-missing", "--cov-report=xml:coverage.xml", "--cov-report=html:htmlcov",
+		}},
+		{"coverage options", TestOptions{Coverage: true, CoverageSource: "src/app", CoverageXML: "out/cov.xml", CoverageDir: "out/html"}, []string{
+			"--cov=src/app", "--cov-report=term-missing", "--cov-report=xml:out/cov.xml", "--cov-report=html:out/html",
+		}},
+		{"junit", TestOptions{JUnitXML: "report.xml"}, []string{"--junitxml=report.xml"}},
+		{"extra args before targets", TestOptions{JUnitXML: "report.xml", ExtraArgs: []string{"-k", "smoke", "-x"}, Targets: []string{"tests/unit", "tests/test_api.py"}}, []string{
+			"--junitxml=report.xml", "-k", "smoke", "-x", "tests/unit", "tests/test_api.py",
+		}},
+	} {
+		if got := (&TestRunner{}).buildPytestArgs(c.opts); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRunPassesPytestExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake interpreter is a shell script")
+	}
+	// The fake interpreter reports pip packages as installed and exits
+	// with pytest's "no tests collected" code
+	venv := t.TempDir()
+	os.MkdirAll(filepath.Join(venv, "bin"), 0755)
+	script := "#!/bin/sh\nif [ \"$2\" = pytest ]; then exit 5; fi\nexit 0\n"
+	if err := os.WriteFile(filepath.Join(venv, "bin", "python"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := (&TestRunner{venvManager: &VenvManager{}}).Run(TestOptions{Path: venv, Coverage: true})
+	if err != nil || code != 5 {
+		t.Errorf("Run = %d, %v; want pytest's exit code 5", code, err)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// ToolPackage maps a pip package to the console script it provides
+type ToolPackage struct {
+	Package string // pip package name (e.g., "ruff")
+	Binary  string // console script name inside the venv (e.g., "ruff")
+}
+
+// toolsVenvDir returns the directory of a dedicated portunix-managed tool venv.
+// Tool venvs live next to centralized venvs: ~/.portunix/python/<name>-venv
+func (vm *VenvManager) toolsVenvDir(name string) string {
+	return filepath.Join(filepath.Dir(vm.venvBaseDir), name+"-venv")
+}
+
+// EnsureToolVenv creates the named tool venv if needed and installs missing packages.
+// Progress output goes to stderr so machine-readable stdout stays clean.
+func (vm *VenvManager) EnsureToolVenv(name string, tools ...ToolPackage) (string, error) {
+	venvPath := vm.toolsVenvDir(name)
+
+	if !vm.VenvExistsAtPath(venvPath) {
+		fmt.Fprintf(os.Stderr, "Creating %s tool environment at %s...\n", name, venvPath)
+		pythonCmd := vm.findPythonExecutable("")
+		cmd := exec.Command(pythonCmd, "-m", "venv", venvPath)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to create %s tool venv: %v\nOutput: %s", name, err, string(output))
+		}
+	}
+
+	for _, tool := range tools {
+		if _, err := os.Stat(vm.getVenvScript(venvPath, tool.Binary)); err == nil {
+			continue
+		}
+
+		fmt.Fprintf(os.Stderr, "Installing %s into %s tool environment...\n", tool.Package, name)
+		cmd := exec.Command(vm.getPythonExecutable(venvPath), "-m", "pip", "install", "--quiet", tool.Package)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("failed to install %s: %v", tool.Package, err)
+		}
+	}
+
+	return venvPath, nil
+}
+
+// getVenvScript returns the path of a console script installed in a venv
+func (vm *VenvManager) getVenvScript(venvPath, script string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(venvPath, "Scripts", script+".exe")
+	}
+	return filepath.Join(venvPath, "bin", script)
+}
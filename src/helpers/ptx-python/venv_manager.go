@@ -11,6 +11,8 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+
+	"portunix.ai/app/config"
 )
 
 // VenvInfo holds information about a virtual environment
@@ -47,7 +49,7 @@ func NewVenvManager() (*VenvManager, error) {
 		return nil, fmt.Errorf("failed to get home directory: %v", err)
 	}
 
-	venvBaseDir := filepath.Join(homeDir, ".portunix", "python", "venvs")
+	venvBaseDir := config.GetString("python.venv_dir", filepath.Join(homeDir, ".portunix", "python", "venvs"))
 
 	// Create base directory if it doesn't exist
 	if err := os.MkdirAll(venvBaseDir, 0755); err != nil {
@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"portunix.ai/app/fileutil"
 	"portunix.ai/portunix/src/helpers/ptx-trace/models"
 )
 
@@ -377,7 +378,7 @@ func (h *AlertHistory) save() error {
 		return err
 	}
 
-	return os.WriteFile(h.filePath, data, 0644)
+	return fileutil.WriteFile(h.filePath, data, 0644)
 }
 
 // Stats returns alert statistics
@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"portunix.ai/app/fileutil"
 	"portunix.ai/portunix/src/helpers/ptx-trace/models"
 )
 
@@ -351,7 +352,7 @@ func (s *Storage) writeJSON(path string, data interface{}) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, jsonData, 0644)
+	return fileutil.WriteFile(path, jsonData, 0644)
 }
 
 // DeleteSession deletes a session and all its data
@@ -0,0 +1,69 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"portunix.ai/app/virt/types"
+)
+
+func TestParseWSLList(t *testing.T) {
+	out := "  NAME            STATE           VERSION\r\n" +
+		"* Ubuntu-22.04    Running         2\r\n" +
+		"  dev             Stopped         2\r\n\r\n"
+	distros := parseWSLList(out)
+	if len(distros) != 2 {
+		t.Fatalf("expected 2 distributions, got %+v", distros)
+	}
+	if d := distros[0]; d.Name != "Ubuntu-22.04" || !d.Default || parseWSLState(d.State) != types.VMStateRunning {
+		t.Errorf("unexpected first distribution %+v", d)
+	}
+	if d := distros[1]; d.Name != "dev" || d.Default || d.Version != "2" || parseWSLState(d.State) != types.VMStateStopped {
+		t.Errorf("unexpected second distribution %+v", d)
+	}
+
+	// Old wsl.exe builds print UTF-16
+	utf16 := strings.Join(strings.Split(out, ""), "\x00")
+	if distros := parseWSLList(utf16); len(distros) != 2 || distros[1].Name != "dev" {
+		t.Errorf("UTF-16 output not parsed: %+v", distros)
+	}
+}
+
+func TestWSLSetupScript(t *testing.T) {
+	script := wslSetupScript("portunix", []string{"git", "curl"})
+	for _, want := range []string{
+		"useradd -m -s /bin/bash portunix",
+		"portunix ALL=(ALL) NOPASSWD:ALL",
+		`default=portunix`,
+		"apt-get install -y -q git curl",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("setup script missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Contains(wslSetupScript("portunix", nil), "apt-get") {
+		t.Error("no package manager should run without packages")
+	}
+}
+
+func TestParseMultipassList(t *testing.T) {
+	out := `{"list":[{"ipv4":["10.21.0.5"],"name":"scratch","release":"Ubuntu 24.04 LTS","state":"Running"},` +
+		`{"ipv4":[],"name":"old","release":"Ubuntu 22.04 LTS","state":"Suspended"}]}`
+	instances, err := parseMultipassList(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 2 || instances[0].Name != "scratch" || instances[0].IPv4[0] != "10.21.0.5" {
+		t.Fatalf("unexpected instances %+v", instances)
+	}
+	if parseMultipassState(instances[1].State) != types.VMStateSuspended {
+		t.Errorf("unexpected state %s", instances[1].State)
+	}
+	if _, err := parseMultipassList("not json"); err == nil {
+		t.Error("invalid output should fail")
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"portunix.ai/app/virt"
+)
+
+func handleCloudInitCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: portunix virt cloudinit list [--template-dir <dir>] [--json]")
+		fmt.Println("       portunix virt cloudinit render --template <name> [--var name=value]... [--output <file>]")
+		return
+	}
+	flags, _ := parseArgs(args[1:], "json")
+	dirs := virt.CloudInitTemplateDirs(flags["template-dir"]...)
+
+	switch args[0] {
+	case "list", "ls":
+		templates, err := virt.ListCloudInitTemplates(dirs)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if flagValue(flags, "json", "") == "true" {
+			data, _ := json.MarshalIndent(templates, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		for _, t := range templates {
+			fmt.Printf("%-18s %s\n", t.Name, t.Description)
+			if t.Source != "builtin" {
+				fmt.Printf("%-18s from %s\n", "", t.Source)
+			}
+			for _, v := range t.Vars {
+				value := "required"
+				if !v.Required {
+					value = fmt.Sprintf("default %q", v.Default)
+				}
+				fmt.Printf("%-18s   --var %s (%s) %s\n", "", v.Name, value, v.Description)
+			}
+		}
+	case "render":
+		name := flagValue(flags, "template", "")
+		if name == "" {
+			fmt.Println("Error: --template is required")
+			os.Exit(1)
+		}
+		// Outside 'virt create' the key defaults to the user's own key
+		auto := map[string]string{}
+		if key := defaultPublicKey(); key != "" {
+			auto["ssh_key"] = key
+		}
+		userData, _, err := renderCloudInitTemplate(name, flags, auto)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if output := flagValue(flags, "output", ""); output != "" {
+			if err := os.WriteFile(output, []byte(userData), 0600); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✅ user-data written to %s\n", output)
+			return
+		}
+		fmt.Print(userData)
+	default:
+		fmt.Printf("Unknown cloudinit operation: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// renderCloudInitTemplate renders the template with the --var and
+// --template-dir flags. auto holds values portunix knows, such as the VM
+// name; they fill declared variables the user did not set. It returns the
+// user-data and the login user.
+func renderCloudInitTemplate(name string, flags map[string][]string, auto map[string]string) (string, string, error) {
+	t, err := virt.LoadCloudInitTemplate(name, virt.CloudInitTemplateDirs(flags["template-dir"]...))
+	if err != nil {
+		return "", "", err
+	}
+	vars, err := virt.ParseCloudInitVars(flags["var"])
+	if err != nil {
+		return "", "", err
+	}
+	for key, value := range auto {
+		if _, set := vars[key]; !set && value != "" && t.HasVar(key) {
+			vars[key] = value
+		}
+	}
+	userData, err := t.Render(vars)
+	if err != nil {
+		return "", "", err
+	}
+	user := vars["user"]
+	if user == "" {
+		user = auto["user"]
+	}
+	return userData, user, nil
+}
+
+// defaultPublicKey returns the first SSH public key of the current user
+func defaultPublicKey() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	for _, name := range []string{"id_ed25519.pub", "id_ecdsa.pub", "id_rsa.pub"} {
+		if data, err := os.ReadFile(filepath.Join(home, ".ssh", name)); err == nil {
+			return strings.TrimSpace(string(data))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,375 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"portunix.ai/app/virt"
+	"portunix.ai/app/virt/types"
+)
+
+// hypervSwitch is the NAT switch Windows creates for Hyper-V
+const hypervSwitch = "Default Switch"
+
+// HyperVAdapter implements VirtualizationProvider using the Hyper-V
+// PowerShell module
+type HyperVAdapter struct{}
+
+// powershell runs a script and returns its trimmed output
+func (h *HyperVAdapter) powershell(script string) (string, error) {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", "$ErrorActionPreference='Stop'; "+script)
+	output, err := cmd.CombinedOutput()
+	outputStr := strings.TrimSpace(string(output))
+	if err != nil {
+		if strings.Contains(outputStr, "You do not have the required permission") || strings.Contains(outputStr, "UnauthorizedAccess") {
+			return "", fmt.Errorf("permission denied - run as administrator or join the 'Hyper-V Administrators' group")
+		}
+		if outputStr != "" {
+			return "", fmt.Errorf("%s", firstLine(outputStr))
+		}
+		return "", err
+	}
+	return outputStr, nil
+}
+
+// psQuote quotes a value as a PowerShell single-quoted string
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(line)
+}
+
+// Provider Information
+
+// GetName returns the provider name
+func (h *HyperVAdapter) GetName() string {
+	return "hyperv"
+}
+
+// GetVersion returns the version of the Hyper-V management service
+func (h *HyperVAdapter) GetVersion() (string, error) {
+	return h.powershell(`(Get-Item "$env:windir\System32\vmms.exe").VersionInfo.ProductVersion`)
+}
+
+// IsAvailable checks if the Hyper-V PowerShell module is usable
+func (h *HyperVAdapter) IsAvailable() bool {
+	if runtime.GOOS != "windows" {
+		return false
+	}
+	_, err := h.powershell("Get-Command Get-VM | Out-Null")
+	return err == nil
+}
+
+// GetDiagnosticInfo returns diagnostic information
+func (h *HyperVAdapter) GetDiagnosticInfo() *DiagnosticInfo {
+	diag := &DiagnosticInfo{Platform: runtime.GOOS, Suggestions: []string{}, Details: []string{}}
+	if out, err := h.powershell("(Get-VMSwitch).Name -join ', '"); err == nil && out != "" {
+		diag.Details = append(diag.Details, "Switches: "+out)
+	}
+	if _, err := exec.LookPath("qemu-img"); err != nil {
+		diag.Suggestions = append(diag.Suggestions, "Install qemu-img to create VMs from cloud images: portunix install qemu")
+	}
+	return diag
+}
+
+// VM Lifecycle Management
+
+// Create creates a Generation 2 VM booting from an installation ISO
+func (h *HyperVAdapter) Create(config *types.VMConfig) error {
+	if config.ISO == "" {
+		return fmt.Errorf("an installation ISO is required (or use --image for a cloud image)")
+	}
+	ramMB, err := parseSizeMB(config.RAM)
+	if err != nil {
+		return err
+	}
+	diskMB, err := parseSizeMB(config.DiskSize)
+	if err != nil {
+		return err
+	}
+	vhd := filepath.Join(virt.InstanceDir(config.Name), config.Name+".vhdx")
+	if err := os.MkdirAll(filepath.Dir(vhd), 0755); err != nil {
+		return err
+	}
+	script := fmt.Sprintf(`New-VM -Name %s -Generation 2 -MemoryStartupBytes %dMB -NewVHDPath %s -NewVHDSizeBytes %dMB -SwitchName %s | Out-Null
+Set-VMProcessor -VMName %[1]s -Count %[6]d
+Add-VMDvdDrive -VMName %[1]s -Path %[7]s
+Set-VMFirmware -VMName %[1]s -FirstBootDevice (Get-VMDvdDrive -VMName %[1]s)`,
+		psQuote(config.Name), ramMB, psQuote(vhd), diskMB, psQuote(hypervSwitch), config.CPUs, psQuote(config.ISO))
+	_, err = h.powershell(script)
+	return err
+}
+
+// CreateFromImage converts the cloud image to a VHDX, attaches the
+// cloud-init seed ISO and starts the VM
+func (h *HyperVAdapter) CreateFromImage(spec *CloudVMSpec, inst *virt.Instance) error {
+	if _, err := exec.LookPath("qemu-img"); err != nil {
+		return fmt.Errorf("qemu-img is required to convert cloud images (use: portunix install qemu)")
+	}
+	seed, err := spec.CloudInit.BuildSeedISO(spec.Dir)
+	if err != nil {
+		return err
+	}
+	vhd := filepath.Join(spec.Dir, spec.Name+".vhdx")
+	if output, err := exec.Command("qemu-img", "convert", "-O", "vhdx", "-o", "subformat=dynamic", spec.ImagePath, vhd).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to convert image: %s", strings.TrimSpace(string(output)))
+	}
+	script := fmt.Sprintf(`Resize-VHD -Path %[2]s -SizeBytes %[3]dGB
+New-VM -Name %[1]s -Generation 2 -MemoryStartupBytes %[4]dMB -VHDPath %[2]s -SwitchName %[5]s | Out-Null
+Set-VMProcessor -VMName %[1]s -Count %[6]d
+Set-VMFirmware -VMName %[1]s -SecureBootTemplate MicrosoftUEFICertificateAuthority
+Add-VMDvdDrive -VMName %[1]s -Path %[7]s
+Start-VM -Name %[1]s`,
+		psQuote(spec.Name), psQuote(vhd), spec.DiskGB, spec.RAMMB, psQuote(hypervSwitch), spec.CPUs, psQuote(seed))
+	_, err = h.powershell(script)
+	return err
+}
+
+// Start starts a virtual machine
+func (h *HyperVAdapter) Start(vmName string) error {
+	_, err := h.powershell("Start-VM -Name " + psQuote(vmName))
+	return err
+}
+
+// Stop shuts a virtual machine down; force turns it off immediately
+func (h *HyperVAdapter) Stop(vmName string, force bool) error {
+	script := "Stop-VM -Name " + psQuote(vmName) + " -Force"
+	if force {
+		script += " -TurnOff"
+	}
+	_, err := h.powershell(script)
+	return err
+}
+
+// Restart restarts a virtual machine
+func (h *HyperVAdapter) Restart(vmName string) error {
+	_, err := h.powershell("Restart-VM -Name " + psQuote(vmName) + " -Force")
+	return err
+}
+
+// Suspend saves the state of a virtual machine
+func (h *HyperVAdapter) Suspend(vmName string) error {
+	_, err := h.powershell("Save-VM -Name " + psQuote(vmName))
+	return err
+}
+
+// Resume starts a saved virtual machine
+func (h *HyperVAdapter) Resume(vmName string) error {
+	return h.Start(vmName)
+}
+
+// Delete removes a virtual machine and, unless keepDisk is set, its disks
+func (h *HyperVAdapter) Delete(vmName string, keepDisk bool) error {
+	script := fmt.Sprintf(`$vm = Get-VM -Name %s
+$disks = @($vm | Get-VMHardDiskDrive | ForEach-Object { $_.Path })
+if ($vm.State -ne 'Off') { Stop-VM -VM $vm -TurnOff -Force }
+Remove-VM -VM $vm -Force`, psQuote(vmName))
+	if !keepDisk {
+		script += "\n$disks | Where-Object { $_ } | Remove-Item -Force -ErrorAction SilentlyContinue"
+	}
+	_, err := h.powershell(script)
+	return err
+}
+
+// VM Information
+
+// hypervVM is the JSON shape of Get-VM output
+type hypervVM struct {
+	Name           string `json:"Name"`
+	State          string `json:"State"`
+	MemoryAssigned int64  `json:"MemoryAssigned"`
+	MemoryStartup  int64  `json:"MemoryStartup"`
+	ProcessorCount int    `json:"ProcessorCount"`
+}
+
+func (h *HyperVAdapter) getVMs(filter string) ([]hypervVM, error) {
+	out, err := h.powershell("@(Get-VM" + filter + " | Select-Object Name,@{n='State';e={[string]$_.State}},MemoryAssigned,MemoryStartup,ProcessorCount) | ConvertTo-Json -Compress")
+	if err != nil {
+		return nil, err
+	}
+	return parseHyperVList(out)
+}
+
+// parseHyperVList parses Get-VM JSON, which is an object for a single VM
+func parseHyperVList(out string) ([]hypervVM, error) {
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(out, "{") {
+		out = "[" + out + "]"
+	}
+	var vms []hypervVM
+	if err := json.Unmarshal([]byte(out), &vms); err != nil {
+		return nil, fmt.Errorf("failed to parse Hyper-V output: %w", err)
+	}
+	return vms, nil
+}
+
+func (h *HyperVAdapter) toVMInfo(vm hypervVM) *types.VMInfo {
+	memory := vm.MemoryAssigned
+	if memory == 0 {
+		memory = vm.MemoryStartup
+	}
+	return &types.VMInfo{
+		Name:    vm.Name,
+		State:   parseHyperVState(vm.State),
+		Backend: "hyperv",
+		RAM:     strconv.FormatInt(memory/1024/1024, 10) + "M",
+		CPUs:    vm.ProcessorCount,
+	}
+}
+
+// parseHyperVState converts a Hyper-V VM state to VMState
+func parseHyperVState(state string) types.VMState {
+	switch state {
+	case "Running":
+		return types.VMStateRunning
+	case "Off":
+		return types.VMStateStopped
+	case "Saved", "Paused":
+		return types.VMStateSuspended
+	case "Starting":
+		return types.VMStateStarting
+	case "Stopping", "Saving", "Pausing":
+		return types.VMStateStopping
+	default:
+		return types.VMStateUnknown
+	}
+}
+
+// List returns all Hyper-V VMs
+func (h *HyperVAdapter) List() ([]*types.VMInfo, error) {
+	vms, err := h.getVMs("")
+	if err != nil {
+		return nil, err
+	}
+	infos := []*types.VMInfo{}
+	for _, vm := range vms {
+		infos = append(infos, h.toVMInfo(vm))
+	}
+	return infos, nil
+}
+
+// GetInfo gets information about a virtual machine
+func (h *HyperVAdapter) GetInfo(vmName string) (*types.VMInfo, error) {
+	vms, err := h.getVMs(" -Name " + psQuote(vmName))
+	if err != nil || len(vms) == 0 {
+		return nil, fmt.Errorf("VM '%s' not found", vmName)
+	}
+	info := h.toVMInfo(vms[0])
+	if ip, err := h.GetIP(vmName); err == nil {
+		info.IP = ip
+	}
+	return info, nil
+}
+
+// GetState returns the state of a virtual machine
+func (h *HyperVAdapter) GetState(vmName string) types.VMState {
+	vms, err := h.getVMs(" -Name " + psQuote(vmName))
+	if err != nil || len(vms) == 0 {
+		return types.VMStateNotFound
+	}
+	return parseHyperVState(vms[0].State)
+}
+
+// GetIP returns the first IPv4 address reported by the integration services
+func (h *HyperVAdapter) GetIP(vmName string) (string, error) {
+	out, err := h.powershell("(Get-VMNetworkAdapter -VMName " + psQuote(vmName) + ").IPAddresses")
+	if err != nil {
+		return "", err
+	}
+	for _, addr := range strings.Fields(out) {
+		if strings.Count(addr, ".") == 3 {
+			return addr, nil
+		}
+	}
+	return "", fmt.Errorf("could not determine IP address for VM '%s'", vmName)
+}
+
+// VM Connection
+
+// IsSSHReady checks if the SSH port of the VM accepts connections
+func (h *HyperVAdapter) IsSSHReady(vmName string) bool {
+	ip, err := h.GetIP(vmName)
+	return err == nil && sshPortOpen(ip, 22)
+}
+
+// Connect opens an SSH session to a VM created from a cloud image
+func (h *HyperVAdapter) Connect(vmName string, opts SSHOptions) error {
+	return connectInstance(h, vmName, opts)
+}
+
+// Snapshot Management
+
+// CreateSnapshot creates a checkpoint of a virtual machine
+func (h *HyperVAdapter) CreateSnapshot(vmName, snapshotName, description string) error {
+	_, err := h.powershell("Checkpoint-VM -Name " + psQuote(vmName) + " -SnapshotName " + psQuote(snapshotName))
+	return err
+}
+
+// ListSnapshots lists the checkpoints of a virtual machine
+func (h *HyperVAdapter) ListSnapshots(vmName string) ([]*SnapshotInfo, error) {
+	out, err := h.powershell("@(Get-VMSnapshot -VMName " + psQuote(vmName) + " | Select-Object Name,ParentSnapshotName,@{n='Created';e={$_.CreationTime.ToString('o')}}) | ConvertTo-Json -Compress")
+	if err != nil {
+		return nil, err
+	}
+	out = strings.TrimSpace(out)
+	if strings.HasPrefix(out, "{") {
+		out = "[" + out + "]"
+	}
+	var raw []struct {
+		Name               string `json:"Name"`
+		ParentSnapshotName string `json:"ParentSnapshotName"`
+		Created            string `json:"Created"`
+	}
+	if out != "" {
+		if err := json.Unmarshal([]byte(out), &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse Hyper-V output: %w", err)
+		}
+	}
+	snapshots := []*SnapshotInfo{}
+	for _, s := range raw {
+		created, _ := time.Parse(time.RFC3339Nano, s.Created)
+		snapshots = append(snapshots, &SnapshotInfo{Name: s.Name, VM: vmName, CreatedAt: created, Parent: s.ParentSnapshotName})
+	}
+	return snapshots, nil
+}
+
+// RevertSnapshot applies a checkpoint
+func (h *HyperVAdapter) RevertSnapshot(vmName, snapshotName string) error {
+	_, err := h.powershell("Restore-VMSnapshot -VMName " + psQuote(vmName) + " -Name " + psQuote(snapshotName) + " -Confirm:$false")
+	return err
+}
+
+// DeleteSnapshot deletes a checkpoint
+func (h *HyperVAdapter) DeleteSnapshot(vmName, snapshotName string) error {
+	_, err := h.powershell("Remove-VMSnapshot -VMName " + psQuote(vmName) + " -Name " + psQuote(snapshotName))
+	return err
+}
+
+// File Operations
+
+// CopyToVM copies a file to a virtual machine
+func (h *HyperVAdapter) CopyToVM(vmName, localPath, remotePath string) error {
+	return copyInstanceFile(h, vmName, localPath, ":"+remotePath)
+}
+
+// CopyFromVM copies a file from a virtual machine
+func (h *HyperVAdapter) CopyFromVM(vmName, remotePath, localPath string) error {
+	return copyInstanceFile(h, vmName, ":"+remotePath, localPath)
+}
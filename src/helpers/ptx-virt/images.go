@@ -0,0 +1,34 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"portunix.ai/app/fetch"
+	"portunix.ai/app/mirror"
+	"portunix.ai/app/progress"
+)
+
+// downloadImage downloads a cloud image from the mirrors of the network
+// profile, falling back to its source. Interrupted downloads are retried
+// and the .part file next to the image is resumed by the next create.
+func downloadImage(url, path string) error {
+	urls, err := mirror.DownloadURLs(url)
+	if err != nil {
+		return err
+	}
+	if urls[0] != url {
+		fmt.Printf("🪞 Using mirror: %s\n", urls[0])
+	}
+	reporter := progress.NewReporter(os.Stdout)
+	result, err := fetch.File(urls, path, fetch.Options{Reporter: reporter, Stage: "image", Log: os.Stdout})
+	if err != nil {
+		return err
+	}
+	reporter.Done("image", progress.FormatBytes(result.Size))
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"portunix.ai/app/virt"
+)
+
+// instanceTarget loads the record of a VM created from a cloud image and
+// refreshes its IP address from the provider. VMs reached through a port
+// forward keep their recorded address.
+func instanceTarget(p VirtualizationProvider, vmName string) (*virt.Instance, error) {
+	inst, err := virt.LoadInstance(vmName)
+	if err != nil {
+		return nil, err
+	}
+	if inst.SSHPort == 0 {
+		if ip, err := p.GetIP(vmName); err == nil && ip != inst.IP {
+			inst.IP = ip
+			virt.SaveInstance(inst)
+		}
+	}
+	if inst.IP == "" {
+		return nil, fmt.Errorf("VM '%s' has no IP address yet; is it running?", vmName)
+	}
+	return inst, nil
+}
+
+// connectInstance runs ssh against the VM, interactively or with
+// opts.Command
+func connectInstance(p VirtualizationProvider, vmName string, opts SSHOptions) error {
+	inst, err := instanceTarget(p, vmName)
+	if err != nil {
+		return err
+	}
+	args := inst.SSHArgs()
+	if opts.Interactive {
+		args = append([]string{"-t"}, args...)
+	}
+	if opts.Command != "" {
+		args = append(args, opts.Command)
+	}
+	cmd := exec.Command("ssh", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// copyInstanceFile copies a file with scp; the remote side is the path
+// starting with ":"
+func copyInstanceFile(p VirtualizationProvider, vmName, src, dst string) error {
+	inst, err := instanceTarget(p, vmName)
+	if err != nil {
+		return err
+	}
+	remote := inst.User + "@" + inst.IP
+	if strings.HasPrefix(src, ":") {
+		src = remote + src
+	} else {
+		dst = remote + dst
+	}
+	sshArgs := inst.SSHArgs()
+	args := append([]string{"-q"}, sshArgs[:len(sshArgs)-1]...)
+	for i, arg := range args {
+		if arg == "-p" {
+			args[i] = "-P"
+		}
+	}
+	output, err := exec.Command("scp", append(args, src, dst)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("scp failed: %v %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// waitForSSH waits until the VM accepts SSH connections
+func waitForSSH(p VirtualizationProvider, vmName string, timeout time.Duration) (*virt.Instance, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		inst, err := instanceTarget(p, vmName)
+		if err == nil {
+			port := inst.SSHPort
+			if port == 0 {
+				port = 22
+			}
+			if sshPortOpen(inst.IP, port) {
+				return inst, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("VM '%s' did not become reachable over SSH within %s", vmName, timeout)
+		}
+		time.Sleep(3 * time.Second)
+	}
+}
+
+func sshPortOpen(host string, port int) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), 3*time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// freeLocalPort returns a TCP port that is free on the loopback interface
+func freeLocalPort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// parseSizeMB converts sizes such as 4G, 2048M or 2048 (megabytes) to MB
+func parseSizeMB(size string) (int, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	s = strings.TrimSuffix(s, "B")
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(s, "T"):
+		multiplier, s = 1024*1024, strings.TrimSuffix(s, "T")
+	case strings.HasSuffix(s, "G"):
+		multiplier, s = 1024, strings.TrimSuffix(s, "G")
+	case strings.HasSuffix(s, "M"):
+		s = strings.TrimSuffix(s, "M")
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 4G or 2048M)", size)
+	}
+	return n * multiplier, nil
+}
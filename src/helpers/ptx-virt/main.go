@@ -9,9 +9,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"portunix.ai/app/virt"
+	"portunix.ai/app/virt/types"
 )
 
 var version = "dev"
@@ -24,7 +29,7 @@ var rootCmd = &cobra.Command{
 	Use:   "ptx-virt",
 	Short: "Portunix Virtualization Management Helper",
 	Long: `ptx-virt is a helper binary for Portunix that handles all virtualization operations.
-It provides unified interface for VirtualBox, QEMU/KVM, VMware, and Hyper-V management.
+It provides unified interface for VirtualBox, QEMU/KVM, VMware, Hyper-V, Multipass and WSL2 management.
 
 This binary is typically invoked by the main portunix dispatcher and should not be used directly.
 
@@ -32,8 +37,11 @@ Supported virtualization backends:
 - VirtualBox (cross-platform)
 - QEMU/KVM (Linux)
 - VMware (cross-platform)
-- Hyper-V (Windows)`,
-	Version: version,
+- Hyper-V (Windows)
+- Multipass (cross-platform)
+- WSL2 distributions (Windows)`,
+	Version:            version,
+	DisableFlagParsing: true, // subcommands parse their own flags
 	Run: func(cmd *cobra.Command, args []string) {
 		// Handle the dispatched command directly
 		handleCommand(args)
@@ -54,15 +62,17 @@ func showHelpAI() {
 	help := AIHelp{
 		Tool:        "ptx-virt",
 		Version:     version,
-		Description: "Unified virtualization management (VirtualBox, QEMU/KVM, VMware, Hyper-V)",
+		Description: "Unified virtualization management (VirtualBox, QEMU/KVM, VMware, Hyper-V, Multipass, WSL2)",
 		Commands: []CommandInfo{
 			{Name: "virt check", Description: "Check virtualization support and available backends"},
 			{Name: "virt list", Description: "List all virtual machines"},
-			{Name: "virt create", Description: "Create a new virtual machine"},
+			{Name: "virt images", Description: "List cloud images for virt create --image"},
+			{Name: "virt cloudinit", Description: "List and render cloud-init user-data templates"},
+			{Name: "virt create", Description: "Create a new virtual machine from a cloud image (--image) or an ISO (--iso)"},
 			{Name: "virt start", Description: "Start a virtual machine"},
 			{Name: "virt stop", Description: "Stop a virtual machine"},
 			{Name: "virt restart", Description: "Restart a virtual machine"},
-			{Name: "virt delete", Description: "Delete a virtual machine"},
+			{Name: "virt destroy", Description: "Delete a virtual machine and its disks"},
 			{Name: "virt info", Description: "Show detailed VM information"},
 			{Name: "virt status", Description: "Show VM status"},
 			{Name: "virt ssh", Description: "SSH into a virtual machine"},
@@ -80,24 +90,41 @@ func showHelpExpert() {
 	fmt.Println()
 	fmt.Println("DESCRIPTION:")
 	fmt.Println("  Unified interface for managing virtual machines across multiple")
-	fmt.Println("  hypervisors: VirtualBox, QEMU/KVM, VMware, and Hyper-V.")
+	fmt.Println("  hypervisors: VirtualBox, QEMU/KVM, VMware, Hyper-V, Multipass and WSL2.")
+	fmt.Println("  'portunix vm' is an alias of 'portunix virt'.")
 	fmt.Println("  Automatic backend detection with priority: VirtualBox > QEMU/KVM > VMware > Hyper-V.")
 	fmt.Println()
 	fmt.Println("COMMANDS:")
 	fmt.Println("  virt check               Check virtualization support and backends")
 	fmt.Println("  virt list                List all virtual machines")
+	fmt.Println("  virt images              List cloud images")
 	fmt.Println("  virt create <name>       Create a new virtual machine")
-	fmt.Println("    --os <type>              OS type (linux, windows)")
-	fmt.Println("    --memory <MB>            Memory allocation")
-	fmt.Println("    --disk <GB>              Disk size")
+	fmt.Println("    --image <image>          Cloud image configured by cloud-init (e.g. ubuntu-22.04)")
 	fmt.Println("    --iso <path>             Installation ISO")
+	fmt.Println("    --memory <size>          Memory allocation (default 2G)")
+	fmt.Println("    --cpus <n>               Number of CPUs (default 2)")
+	fmt.Println("    --disk <size>            Disk size (default 20G)")
+	fmt.Println("    --user <name>            Login user created by cloud-init (default portunix)")
+	fmt.Println("    --package <name>         Distribution package to install at first boot (repeatable)")
+	fmt.Println("    --cloud-init <file>      Use a custom cloud-config user-data")
+	fmt.Println("    --cloud-init-template <name> Render user-data from the template library")
+	fmt.Println("    --var <name=value>       Template variable (repeatable)")
+	fmt.Println("    --provider <name>        kvm, virtualbox, hyperv, multipass or wsl (default: auto)")
+	fmt.Println("    --backend <name>         Alias of --provider")
+	fmt.Println("    --distro <name>          WSL distribution to install (e.g. Ubuntu-22.04); name defaults to it")
+	fmt.Println("  virt cloudinit list      List cloud-init templates")
+	fmt.Println("  virt cloudinit render    Render user-data from a template")
+	fmt.Println("    --template <name>        Template (dev-workstation, docker-host, k3s-node, edge-proxy)")
+	fmt.Println("    --var <name=value>       Template variable; @file reads the value from a file")
+	fmt.Println("    --template-dir <dir>     Additional directory with custom *.yaml templates")
+	fmt.Println("    --output <file>          Write user-data to a file instead of stdout")
 	fmt.Println("  virt start <name>        Start a virtual machine")
 	fmt.Println("  virt stop <name>         Stop a virtual machine")
 	fmt.Println("  virt restart <name>      Restart a virtual machine")
-	fmt.Println("  virt delete <name>       Delete a virtual machine")
+	fmt.Println("  virt destroy <name>      Delete a virtual machine and its disks")
 	fmt.Println("  virt info <name>         Show detailed VM information")
 	fmt.Println("  virt status <name>       Show VM status")
-	fmt.Println("  virt ssh <name>          SSH into a virtual machine")
+	fmt.Println("  virt ssh <name> [-- cmd] SSH into a virtual machine or run a command")
 	fmt.Println("  virt snapshot <name>     Manage VM snapshots")
 	fmt.Println("    create <snap-name>       Create snapshot")
 	fmt.Println("    list                     List snapshots")
@@ -110,14 +137,20 @@ func showHelpExpert() {
 	fmt.Println("  QEMU/KVM       Linux only (best performance)")
 	fmt.Println("  VMware         Cross-platform (Workstation/Fusion)")
 	fmt.Println("  Hyper-V        Windows only")
+	fmt.Println("  Multipass      Cross-platform, Ubuntu images only")
+	fmt.Println("  WSL2           Windows only, distributions entered with 'vm ssh'")
 	fmt.Println()
 	fmt.Println("EXAMPLES:")
 	fmt.Println("  portunix virt check")
 	fmt.Println("  portunix virt list")
-	fmt.Println("  portunix virt create dev-vm --os linux --memory 4096 --disk 40")
+	fmt.Println("  portunix virt create dev-vm --image ubuntu-22.04 --memory 4G --disk 40G")
 	fmt.Println("  portunix virt start dev-vm")
 	fmt.Println("  portunix virt ssh dev-vm")
 	fmt.Println("  portunix virt snapshot dev-vm create before-upgrade")
+	fmt.Println("  portunix vm create scratch --backend multipass --image ubuntu-24.04")
+	fmt.Println("  portunix vm create --backend wsl --distro Ubuntu-22.04")
+	fmt.Println("  portunix vm cloudinit render --template dev-workstation --var user=alice")
+	fmt.Println("  portunix vm create k3s-1 --image ubuntu-24.04 --cloud-init-template k3s-node")
 }
 
 // handleCommand dispatches the "virt" command routed to this helper by the
@@ -126,7 +159,7 @@ func showHelpExpert() {
 // by the dispatcher for discovery and documentation generation. args arrive
 // without the binary name prefix.
 func handleCommand(args []string) {
-	// Handle dispatched commands: virt
+	// Handle dispatched commands: virt and its alias vm
 	if len(args) == 0 {
 		fmt.Println("No command specified")
 		return
@@ -136,7 +169,7 @@ func handleCommand(args []string) {
 	subArgs := args[1:]
 
 	switch command {
-	case "virt":
+	case "virt", "vm":
 		if len(subArgs) == 0 {
 			// Show virt help
 			showVirtHelp()
@@ -149,13 +182,16 @@ func handleCommand(args []string) {
 		fmt.Println("Portunix Virtualization Management Helper")
 	case "--list-commands":
 		fmt.Println("virt")
+		fmt.Println("vm")
+	case "--help", "-h":
+		showVirtHelp()
 	case "--help-ai":
 		showHelpAI()
 	case "--help-expert":
 		showHelpExpert()
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
-		fmt.Println("Supported commands: virt")
+		fmt.Println("Supported commands: virt, vm")
 	}
 }
 
@@ -165,11 +201,13 @@ func showVirtHelp() {
 	fmt.Println("Virtualization Management Commands:")
 	fmt.Println("  check       - Check virtualization support and available backends")
 	fmt.Println("  list        - List all virtual machines")
+	fmt.Println("  images      - List cloud images for create --image")
+	fmt.Println("  cloudinit   - List and render cloud-init templates")
 	fmt.Println("  create      - Create a new virtual machine")
 	fmt.Println("  start       - Start a virtual machine")
 	fmt.Println("  stop        - Stop a virtual machine")
 	fmt.Println("  restart     - Restart a virtual machine")
-	fmt.Println("  delete      - Delete a virtual machine")
+	fmt.Println("  destroy     - Delete a virtual machine and its disks")
 	fmt.Println("  info        - Show detailed VM information")
 	fmt.Println("  status      - Show VM status")
 	fmt.Println("  ssh         - SSH into a virtual machine")
@@ -178,7 +216,8 @@ func showVirtHelp() {
 	fmt.Println()
 	fmt.Println("Backend Detection:")
 	fmt.Println("  The helper automatically detects available virtualization backends")
-	fmt.Println("  Priority: VirtualBox > QEMU/KVM > VMware > Hyper-V")
+	fmt.Println("  Priority: VirtualBox > QEMU/KVM > VMware > Hyper-V > Multipass")
+	fmt.Println("  WSL2 is used only with --backend wsl or --distro")
 }
 
 func handleVirtCommand(args []string) {
@@ -203,8 +242,12 @@ func handleVirtCommand(args []string) {
 		handleStopCommand(subArgs)
 	case "restart":
 		handleRestartCommand(subArgs)
-	case "delete", "rm":
+	case "delete", "rm", "destroy":
 		handleDeleteCommand(subArgs)
+	case "images":
+		handleImagesCommand()
+	case "cloudinit", "cloud-init":
+		handleCloudInitCommand(subArgs)
 	case "info":
 		handleInfoCommand(subArgs)
 	case "status":
@@ -275,15 +318,45 @@ func handleCheckCommand() {
 }
 
 func handleListCommand(args []string) {
-	fmt.Println("Listing virtual machines...")
+	flags, _ := parseArgs(args, "json")
+	name := flagValue(flags, "name", "")
+	jsonOutput := flagValue(flags, "json", "") == "true"
+	if !jsonOutput && name == "" {
+		fmt.Println("Listing virtual machines...")
+	}
 
-	manager, err := NewVirtManager()
+	var manager *VirtManager
+	var err error
+	if name != "" {
+		manager, err = managerForVM(name)
+	} else {
+		manager, err = NewVirtManager()
+	}
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
-		return
+		os.Exit(1)
 	}
 
 	vms, err := manager.List()
+	if err == nil && name != "" {
+		// --name selects a single VM and fails when it does not exist
+		var selected []*types.VMInfo
+		for _, vm := range vms {
+			if vm.Name == name {
+				selected = append(selected, vm)
+			}
+		}
+		if len(selected) == 0 {
+			fmt.Printf("VM '%s' not found\n", name)
+			os.Exit(1)
+		}
+		vms = selected
+	}
+	if err == nil && jsonOutput {
+		data, _ := json.MarshalIndent(vms, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
 	if err != nil {
 		fmt.Printf("\033[31mError listing VMs: %v\033[0m\n", err)
 
@@ -373,14 +446,282 @@ func handleListCommand(args []string) {
 	}
 }
 
+// parseArgs splits "--flag value" and "--flag=value" options from positional
+// arguments. Flags listed in boolFlags take no value; repeated flags keep
+// every value; everything after "--" is positional.
+func parseArgs(args []string, boolFlags ...string) (map[string][]string, []string) {
+	flags := map[string][]string{}
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		isBool := false
+		for _, b := range boolFlags {
+			isBool = isBool || b == name
+		}
+		switch {
+		case hasValue:
+		case isBool:
+			value = "true"
+		case i+1 < len(args):
+			i++
+			value = args[i]
+		}
+		flags[name] = append(flags[name], value)
+	}
+	return flags, positional
+}
+
+// flagValue returns the last value of a flag or def
+func flagValue(flags map[string][]string, name, def string) string {
+	if values := flags[name]; len(values) > 0 {
+		return values[len(values)-1]
+	}
+	return def
+}
+
+// managerForVM returns a manager for the provider that created the VM, or
+// the default provider for VMs not created from a cloud image
+func managerForVM(vmName string) (*VirtManager, error) {
+	if inst, err := virt.LoadInstance(vmName); err == nil && inst.Provider != "" {
+		return NewVirtManagerWithProvider(inst.Provider)
+	}
+	return NewVirtManager()
+}
+
 func handleCreateCommand(args []string) {
-	if len(args) == 0 {
+	flags, positional := parseArgs(args)
+	distro := flagValue(flags, "distro", "")
+	if len(positional) == 0 && distro == "" {
 		fmt.Println("VM name required")
-		fmt.Println("Usage: portunix virt create [vm-name] [options]")
+		fmt.Println("Usage: portunix virt create <vm-name> --image <image> [options]")
+		fmt.Println("       portunix virt create <vm-name> --iso <path> [options]")
+		fmt.Println("       portunix vm create [name] --backend wsl --distro <distro>")
+		return
+	}
+	// A WSL distribution is named after the distro unless a name is given
+	vmName := distro
+	if len(positional) > 0 {
+		vmName = positional[0]
+	}
+	if err := virt.ValidateInstanceName(vmName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// --backend is an alias of --provider
+	provider := flagValue(flags, "provider", flagValue(flags, "backend", ""))
+	if provider == "" && distro != "" {
+		provider = "wsl"
+	}
+	var manager *VirtManager
+	var err error
+	if provider != "" {
+		manager, err = NewVirtManagerWithProvider(provider)
+	} else {
+		manager, err = NewVirtManager()
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if distro != "" {
+		createDistro(manager, vmName, distro, flags)
 		return
 	}
-	fmt.Printf("Creating VM: %s\n", args[0])
-	// TODO: Implement using existing virt create logic
+
+	ramMB, err := parseSizeMB(flagValue(flags, "memory", "2G"))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	diskMB, err := parseSizeMB(flagValue(flags, "disk", "20G"))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	cpus, err := strconv.Atoi(flagValue(flags, "cpus", "2"))
+	if err != nil || cpus < 1 {
+		fmt.Println("Error: --cpus must be a positive number")
+		os.Exit(1)
+	}
+
+	imageName := flagValue(flags, "image", "")
+	if imageName == "" {
+		iso := flagValue(flags, "iso", "")
+		if iso == "" {
+			fmt.Println("Error: --image or --iso is required")
+			fmt.Println("Run 'portunix virt images' for the available cloud images")
+			os.Exit(1)
+		}
+		fmt.Printf("Creating VM '%s' from %s using %s...\n", vmName, iso, manager.GetProviderName())
+		config := &types.VMConfig{
+			Name:     vmName,
+			ISO:      iso,
+			RAM:      fmt.Sprintf("%dM", ramMB),
+			CPUs:     cpus,
+			DiskSize: fmt.Sprintf("%dM", diskMB),
+			OSType:   flagValue(flags, "os", "linux"),
+			Network:  types.NetworkConfig{Mode: "nat"},
+		}
+		if err := manager.Create(config); err != nil {
+			fmt.Printf("Error creating VM: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ VM '%s' created; finish the installation in its console\n", vmName)
+		return
+	}
+
+	image, err := virt.LookupCloudImage(imageName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := virt.LoadInstance(vmName); err == nil {
+		fmt.Printf("Error: VM '%s' already exists; destroy it first\n", vmName)
+		os.Exit(1)
+	}
+	var imagePath string
+	if managed, ok := manager.GetProvider().(ManagedImageProvider); ok {
+		if !managed.SupportsImage(image) {
+			fmt.Printf("Error: provider %s does not offer image %s\n", manager.GetProviderName(), image.Name)
+			os.Exit(1)
+		}
+	} else if imagePath, err = virt.EnsureCloudImage(image, os.Stdout, downloadImage); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	keyPath, publicKey, err := virt.EnsureSSHKey(vmName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	user := flagValue(flags, "user", "portunix")
+	cloudInit := &virt.CloudInit{
+		Hostname:     vmName,
+		User:         user,
+		SSHPublicKey: publicKey,
+		Packages:     flags["package"],
+	}
+	if path := flagValue(flags, "cloud-init", ""); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		cloudInit.Custom = string(data)
+	} else if name := flagValue(flags, "cloud-init-template", ""); name != "" {
+		userData, templateUser, err := renderCloudInitTemplate(name, flags, map[string]string{
+			"hostname": vmName,
+			"user":     user,
+			"ssh_key":  publicKey,
+		})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		cloudInit.Custom = userData
+		user = templateUser
+	}
+
+	spec := &CloudVMSpec{
+		Name:      vmName,
+		Image:     image,
+		ImagePath: imagePath,
+		RAMMB:     ramMB,
+		CPUs:      cpus,
+		DiskGB:    (diskMB + 1023) / 1024,
+		Dir:       virt.InstanceDir(vmName),
+		CloudInit: cloudInit,
+	}
+	inst := &virt.Instance{
+		Name:      vmName,
+		Provider:  manager.GetProviderName(),
+		Image:     image.Name,
+		User:      user,
+		KeyPath:   keyPath,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	fmt.Printf("Creating VM '%s' from %s using %s...\n", vmName, image.Description, inst.Provider)
+	if err := manager.CreateFromImage(spec, inst); err != nil {
+		fmt.Printf("Error creating VM: %v\n", err)
+		virt.RemoveInstance(vmName)
+		os.Exit(1)
+	}
+	if err := virt.SaveInstance(inst); err != nil {
+		fmt.Printf("Error: failed to record VM: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Waiting for cloud-init to bring up SSH...")
+	timeout, _ := time.ParseDuration(flagValue(flags, "wait", "5m"))
+	if inst, err = waitForSSH(manager.GetProvider(), vmName, timeout); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		fmt.Printf("The VM may still be booting; retry with: portunix virt ssh %s\n", vmName)
+		return
+	}
+	fmt.Printf("✅ VM '%s' is ready at %s\n", vmName, inst.IP)
+	fmt.Println("\nNext steps:")
+	fmt.Printf("  portunix virt ssh %s                 # Log in\n", vmName)
+	fmt.Printf("  portunix --host %s install nodejs    # Install packages in the VM\n", vmName)
+	fmt.Printf("  portunix virt destroy %s             # Remove the VM\n", vmName)
+}
+
+// createDistro installs a distribution with a DistroProvider such as WSL.
+// The environment is entered with 'virt ssh', which uses the provider's own
+// shell instead of SSH.
+func createDistro(manager *VirtManager, vmName, distro string, flags map[string][]string) {
+	if _, err := virt.LoadInstance(vmName); err == nil {
+		fmt.Printf("Error: VM '%s' already exists; destroy it first\n", vmName)
+		os.Exit(1)
+	}
+	spec := &DistroSpec{
+		Name:     vmName,
+		Distro:   distro,
+		User:     flagValue(flags, "user", "portunix"),
+		Packages: flags["package"],
+	}
+	inst := &virt.Instance{
+		Name:      vmName,
+		Provider:  manager.GetProviderName(),
+		Image:     distro,
+		User:      spec.User,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	fmt.Printf("Installing %s as '%s' using %s...\n", distro, vmName, inst.Provider)
+	if err := manager.CreateDistro(spec, inst); err != nil {
+		fmt.Printf("Error creating VM: %v\n", err)
+		virt.RemoveInstance(vmName)
+		os.Exit(1)
+	}
+	if err := virt.SaveInstance(inst); err != nil {
+		fmt.Printf("Error: failed to record VM: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ '%s' is ready\n", vmName)
+	fmt.Println("\nNext steps:")
+	fmt.Printf("  portunix vm ssh %s                 # Open a shell\n", vmName)
+	fmt.Printf("  portunix vm ssh %s -- uname -a     # Run a command\n", vmName)
+	fmt.Printf("  portunix vm destroy %s             # Remove it\n", vmName)
+}
+
+func handleImagesCommand() {
+	fmt.Printf("%-15s %s\n", "IMAGE", "DESCRIPTION")
+	for _, img := range virt.CloudImages {
+		fmt.Printf("%-15s %s\n", img.Name, img.Description)
+	}
 }
 
 func handleStartCommand(args []string) {
@@ -393,7 +734,7 @@ func handleStartCommand(args []string) {
 	vmName := args[0]
 	fmt.Printf("Starting VM '%s'...\n", vmName)
 
-	manager, err := NewVirtManager()
+	manager, err := managerForVM(vmName)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return
@@ -408,12 +749,24 @@ func handleStartCommand(args []string) {
 }
 
 func handleStopCommand(args []string) {
-	if len(args) == 0 {
+	flags, positional := parseArgs(args, "force")
+	if len(positional) == 0 {
 		fmt.Println("VM name required")
+		fmt.Println("Usage: portunix virt stop <vm-name> [--force]")
 		return
 	}
-	fmt.Printf("Stopping VM: %s\n", args[0])
-	// TODO: Implement using existing virt stop logic
+	vmName := positional[0]
+	manager, err := managerForVM(vmName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Stopping VM '%s'...\n", vmName)
+	if err := manager.Stop(vmName, flagValue(flags, "force", "") == "true"); err != nil {
+		fmt.Printf("Error stopping VM: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ VM '%s' stopped\n", vmName)
 }
 
 func handleRestartCommand(args []string) {
@@ -421,26 +774,107 @@ func handleRestartCommand(args []string) {
 		fmt.Println("VM name required")
 		return
 	}
-	fmt.Printf("Restarting VM: %s\n", args[0])
-	// TODO: Implement using existing virt restart logic
+	vmName := args[0]
+	manager, err := managerForVM(vmName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Restarting VM '%s'...\n", vmName)
+	if err := manager.Restart(vmName); err != nil {
+		fmt.Printf("Error restarting VM: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ VM '%s' restarted\n", vmName)
 }
 
 func handleDeleteCommand(args []string) {
-	if len(args) == 0 {
+	flags, positional := parseArgs(args, "keep-disk")
+	if len(positional) == 0 {
 		fmt.Println("VM name required")
+		fmt.Println("Usage: portunix virt destroy <vm-name> [--keep-disk]")
 		return
 	}
-	fmt.Printf("Deleting VM: %s\n", args[0])
-	// TODO: Implement using existing virt delete logic
+	vmName := positional[0]
+	manager, err := managerForVM(vmName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	keepDisk := flagValue(flags, "keep-disk", "") == "true"
+	fmt.Printf("Destroying VM '%s'...\n", vmName)
+	if err := manager.Delete(vmName, keepDisk); err != nil {
+		fmt.Printf("Error deleting VM: %v\n", err)
+		os.Exit(1)
+	}
+	if !keepDisk {
+		virt.RemoveInstance(vmName)
+	}
+	fmt.Printf("✅ VM '%s' destroyed\n", vmName)
+}
+
+// vmDetails is the JSON output of 'virt info'; the SSH fields are set for
+// VMs created from a cloud image
+type vmDetails struct {
+	*types.VMInfo
+	Image   string `json:"image,omitempty"`
+	SSHUser string `json:"ssh_user,omitempty"`
+	SSHKey  string `json:"ssh_key,omitempty"`
+	SSHPort int    `json:"ssh_port,omitempty"`
 }
 
 func handleInfoCommand(args []string) {
-	if len(args) == 0 {
+	flags, positional := parseArgs(args, "json")
+	if len(positional) == 0 {
 		fmt.Println("VM name required")
 		return
 	}
-	fmt.Printf("Getting info for VM: %s\n", args[0])
-	// TODO: Implement using existing virt info logic
+	vmName := positional[0]
+	manager, err := managerForVM(vmName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	info, err := manager.GetInfo(vmName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	details := vmDetails{VMInfo: info}
+	if inst, err := instanceTarget(manager.GetProvider(), vmName); err == nil {
+		details.Image = inst.Image
+		details.IP = inst.IP
+		details.SSHUser = inst.User
+		details.SSHKey = inst.KeyPath
+		details.SSHPort = inst.SSHPort
+		if details.SSHPort == 0 {
+			details.SSHPort = 22
+		}
+	}
+
+	if flagValue(flags, "json", "") == "true" {
+		data, _ := json.MarshalIndent(details, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	fmt.Printf("Name:     %s\n", info.Name)
+	fmt.Printf("State:    %s\n", info.State)
+	fmt.Printf("Provider: %s\n", manager.GetProviderName())
+	if details.Image != "" {
+		fmt.Printf("Image:    %s\n", details.Image)
+	}
+	if info.RAM != "" {
+		fmt.Printf("RAM:      %s\n", info.RAM)
+	}
+	if info.CPUs > 0 {
+		fmt.Printf("CPUs:     %d\n", info.CPUs)
+	}
+	if details.IP != "" {
+		fmt.Printf("IP:       %s\n", details.IP)
+	}
+	if details.SSHUser != "" {
+		fmt.Printf("SSH:      %s@%s:%d (key %s)\n", details.SSHUser, details.IP, details.SSHPort, details.SSHKey)
+	}
 }
 
 func handleStatusCommand(args []string) {
@@ -448,27 +882,106 @@ func handleStatusCommand(args []string) {
 		fmt.Println("VM name required")
 		return
 	}
-	fmt.Printf("Getting status for VM: %s\n", args[0])
-	// TODO: Implement using existing virt status logic
+	manager, err := managerForVM(args[0])
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	state := manager.GetState(args[0])
+	fmt.Println(state)
+	if state == types.VMStateNotFound {
+		os.Exit(1)
+	}
 }
 
 func handleSSHCommand(args []string) {
-	if len(args) == 0 {
+	flags, positional := parseArgs(args, "check")
+	if len(positional) == 0 {
 		fmt.Println("VM name required")
+		fmt.Println("Usage: portunix virt ssh <vm-name> [-- command...]")
+		return
+	}
+	vmName := positional[0]
+	manager, err := managerForVM(vmName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if flagValue(flags, "check", "") == "true" {
+		timeout, _ := time.ParseDuration(flagValue(flags, "wait", "0s"))
+		if _, err := waitForSSH(manager.GetProvider(), vmName, timeout); err != nil {
+			fmt.Printf("SSH not ready: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("SSH ready")
 		return
 	}
-	fmt.Printf("SSH into VM: %s\n", args[0])
-	// TODO: Implement using existing virt SSH logic
+
+	opts := SSHOptions{Interactive: len(positional) == 1}
+	if len(positional) > 1 {
+		opts.Command = strings.Join(positional[1:], " ")
+	}
+	if err := manager.Connect(vmName, opts); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
 }
 
 func handleSnapshotCommand(args []string) {
-	if len(args) == 0 {
-		fmt.Println("Snapshot subcommand required")
-		fmt.Println("Usage: portunix virt snapshot [create|list|restore|delete] [vm-name] [snapshot-name]")
+	if len(args) < 2 {
+		fmt.Println("Usage: portunix virt snapshot <vm-name> [create|list|restore|delete] [snapshot-name]")
+		return
+	}
+	vmName, operation := args[0], args[1]
+	manager, err := managerForVM(vmName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	provider := manager.GetProvider()
+
+	if operation == "list" {
+		snapshots, err := provider.ListSnapshots(vmName)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(snapshots) == 0 {
+			fmt.Printf("No snapshots for VM '%s'\n", vmName)
+			return
+		}
+		for _, s := range snapshots {
+			fmt.Println(s.Name)
+		}
 		return
 	}
-	fmt.Printf("Snapshot operation: %s\n", args[0])
-	// TODO: Implement using existing virt snapshot logic
+
+	if len(args) < 3 {
+		fmt.Println("Snapshot name required")
+		os.Exit(1)
+	}
+	snapshotName := args[2]
+	var done string
+	switch operation {
+	case "create":
+		err, done = provider.CreateSnapshot(vmName, snapshotName, ""), "created"
+	case "restore":
+		err, done = provider.RevertSnapshot(vmName, snapshotName), "restored"
+	case "delete":
+		err, done = provider.DeleteSnapshot(vmName, snapshotName), "deleted"
+	default:
+		fmt.Printf("Unknown snapshot operation: %s\n", operation)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Snapshot '%s' %s for VM '%s'\n", snapshotName, done, vmName)
 }
 
 func handleInstallQEMUCommand() {
@@ -9,6 +9,7 @@ import (
 	"runtime"
 
 	"portunix.ai/app/system"
+	"portunix.ai/app/virt"
 	"portunix.ai/app/virt/types"
 	"portunix.ai/app/virt/virtualbox"
 )
@@ -47,6 +48,20 @@ func NewVirtManager() (*VirtManager, error) {
 	return manager, nil
 }
 
+// NewVirtManagerWithProvider creates a manager for a specific provider,
+// e.g. the one that created a VM
+func NewVirtManagerWithProvider(name string) (*VirtManager, error) {
+	manager := &VirtManager{
+		config: &VirtConfig{PreferredProvider: name},
+	}
+	provider := manager.createProvider(name)
+	if provider == nil || !provider.IsAvailable() {
+		return nil, fmt.Errorf("virtualization provider %s is not available", name)
+	}
+	manager.provider = provider
+	return manager, nil
+}
+
 // selectProvider selects the best available virtualization provider
 func (m *VirtManager) selectProvider() (VirtualizationProvider, error) {
 	// Get system info for platform detection
@@ -93,8 +108,11 @@ func (m *VirtManager) createProvider(name string) VirtualizationProvider {
 		// TODO: Implement VMware provider adapter
 		return nil
 	case "hyperv":
-		// TODO: Implement Hyper-V provider adapter
-		return nil
+		return &HyperVAdapter{}
+	case "multipass":
+		return &MultipassAdapter{}
+	case "wsl":
+		return &WSLAdapter{}
 	default:
 		return nil
 	}
@@ -137,6 +155,30 @@ func (m *VirtManager) Create(config *types.VMConfig) error {
 	return m.provider.Create(config)
 }
 
+// CreateFromImage creates a virtual machine from a cloud image
+func (m *VirtManager) CreateFromImage(spec *CloudVMSpec, inst *virt.Instance) error {
+	if m.provider == nil {
+		return fmt.Errorf("no virtualization provider available")
+	}
+	creator, ok := m.provider.(CloudImageProvider)
+	if !ok {
+		return fmt.Errorf("provider %s cannot create VMs from cloud images", m.provider.GetName())
+	}
+	return creator.CreateFromImage(spec, inst)
+}
+
+// CreateDistro creates an environment from a named distribution
+func (m *VirtManager) CreateDistro(spec *DistroSpec, inst *virt.Instance) error {
+	if m.provider == nil {
+		return fmt.Errorf("no virtualization provider available")
+	}
+	creator, ok := m.provider.(DistroProvider)
+	if !ok {
+		return fmt.Errorf("provider %s cannot install distributions; use --image instead", m.provider.GetName())
+	}
+	return creator.CreateDistro(spec, inst)
+}
+
 // Start starts a virtual machine
 func (m *VirtManager) Start(vmName string) error {
 	if m.provider == nil {
@@ -228,7 +270,7 @@ func (m *VirtManager) CheckCapabilities() (*VirtualizationCapabilities, error) {
 	}
 
 	// Check all possible providers
-	allProviders := []string{"virtualbox", "qemu", "kvm", "vmware", "hyperv"}
+	allProviders := []string{"virtualbox", "qemu", "kvm", "vmware", "hyperv", "multipass", "wsl"}
 	for _, providerName := range allProviders {
 		provider := m.createProvider(providerName)
 		if provider == nil {
@@ -0,0 +1,395 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"portunix.ai/app/virt"
+	"portunix.ai/app/virt/types"
+)
+
+// multipassImages maps catalog images to Multipass image aliases; Multipass
+// only ships Ubuntu
+var multipassImages = map[string]string{
+	"ubuntu-22.04": "22.04",
+	"ubuntu-24.04": "24.04",
+}
+
+// MultipassAdapter implements VirtualizationProvider using Canonical
+// Multipass, which manages its own hypervisor and images on every platform
+type MultipassAdapter struct{}
+
+// multipass runs the multipass CLI and returns its trimmed output
+func (m *MultipassAdapter) multipass(args ...string) (string, error) {
+	output, err := exec.Command("multipass", args...).CombinedOutput()
+	outputStr := strings.TrimSpace(string(output))
+	if err != nil {
+		if outputStr != "" {
+			return "", fmt.Errorf("multipass %s: %s", args[0], firstLine(outputStr))
+		}
+		return "", fmt.Errorf("multipass %s: %w", args[0], err)
+	}
+	return outputStr, nil
+}
+
+// Provider Information
+
+// GetName returns the provider name
+func (m *MultipassAdapter) GetName() string {
+	return "multipass"
+}
+
+// GetVersion returns the Multipass version
+func (m *MultipassAdapter) GetVersion() (string, error) {
+	out, err := m.multipass("version")
+	if err != nil {
+		return "", err
+	}
+	// "multipass   1.14.0+mac\nmultipassd  1.14.0+mac"
+	if fields := strings.Fields(firstLine(out)); len(fields) == 2 {
+		return fields[1], nil
+	}
+	return firstLine(out), nil
+}
+
+// IsAvailable checks if the multipass CLI is installed
+func (m *MultipassAdapter) IsAvailable() bool {
+	_, err := exec.LookPath("multipass")
+	return err == nil
+}
+
+// GetDiagnosticInfo returns diagnostic information
+func (m *MultipassAdapter) GetDiagnosticInfo() *DiagnosticInfo {
+	diag := &DiagnosticInfo{Platform: runtime.GOOS, Suggestions: []string{}, Details: []string{}}
+	if path, err := exec.LookPath("multipass"); err == nil {
+		diag.PathEnvironment = path
+	}
+	if out, err := m.multipass("get", "local.driver"); err == nil {
+		diag.Details = append(diag.Details, "Driver: "+out)
+	}
+	return diag
+}
+
+// SupportsImage reports whether Multipass can launch the catalog image
+func (m *MultipassAdapter) SupportsImage(img *virt.CloudImage) bool {
+	_, ok := multipassImages[img.Name]
+	return ok
+}
+
+// VM Lifecycle Management
+
+// Create is not supported: Multipass only launches cloud images
+func (m *MultipassAdapter) Create(config *types.VMConfig) error {
+	return fmt.Errorf("multipass cannot install from an ISO; use --image ubuntu-22.04 or ubuntu-24.04")
+}
+
+// CreateFromImage launches an instance with the generated cloud-init
+// user-data. Multipass downloads the image itself.
+func (m *MultipassAdapter) CreateFromImage(spec *CloudVMSpec, inst *virt.Instance) error {
+	alias, ok := multipassImages[spec.Image.Name]
+	if !ok {
+		return fmt.Errorf("multipass only supports Ubuntu images (ubuntu-22.04, ubuntu-24.04)")
+	}
+	userData, _, err := spec.CloudInit.WriteFiles(spec.Dir)
+	if err != nil {
+		return err
+	}
+	_, err = m.multipass("launch", alias,
+		"--name", spec.Name,
+		"--cpus", strconv.Itoa(spec.CPUs),
+		"--memory", fmt.Sprintf("%dM", spec.RAMMB),
+		"--disk", fmt.Sprintf("%dG", spec.DiskGB),
+		"--cloud-init", userData,
+		"--timeout", "600")
+	return err
+}
+
+// Start starts a virtual machine
+func (m *MultipassAdapter) Start(vmName string) error {
+	_, err := m.multipass("start", vmName)
+	return err
+}
+
+// Stop shuts a virtual machine down; force powers it off
+func (m *MultipassAdapter) Stop(vmName string, force bool) error {
+	args := []string{"stop", vmName}
+	if force {
+		args = append(args, "--force")
+	}
+	_, err := m.multipass(args...)
+	return err
+}
+
+// Restart restarts a virtual machine
+func (m *MultipassAdapter) Restart(vmName string) error {
+	_, err := m.multipass("restart", vmName)
+	return err
+}
+
+// Suspend suspends a virtual machine
+func (m *MultipassAdapter) Suspend(vmName string) error {
+	_, err := m.multipass("suspend", vmName)
+	return err
+}
+
+// Resume starts a suspended virtual machine
+func (m *MultipassAdapter) Resume(vmName string) error {
+	return m.Start(vmName)
+}
+
+// Delete removes a virtual machine. With keepDisk the instance is only
+// marked deleted and can be brought back with 'multipass recover'.
+func (m *MultipassAdapter) Delete(vmName string, keepDisk bool) error {
+	args := []string{"delete", vmName}
+	if !keepDisk {
+		args = append(args, "--purge")
+	}
+	_, err := m.multipass(args...)
+	return err
+}
+
+// VM Information
+
+// multipassInstance is an entry of 'multipass list --format json'
+type multipassInstance struct {
+	Name    string   `json:"name"`
+	State   string   `json:"state"`
+	IPv4    []string `json:"ipv4"`
+	Release string   `json:"release"`
+}
+
+// multipassInfo is an entry of 'multipass info --format json'
+type multipassInfo struct {
+	State    string      `json:"state"`
+	IPv4     []string    `json:"ipv4"`
+	Release  string      `json:"release"`
+	CPUCount json.Number `json:"cpu_count"`
+	Memory   struct {
+		Total int64 `json:"total"`
+	} `json:"memory"`
+	Disks map[string]struct {
+		Total json.Number `json:"total"`
+	} `json:"disks"`
+}
+
+// parseMultipassList parses 'multipass list --format json'
+func parseMultipassList(out string) ([]multipassInstance, error) {
+	var list struct {
+		List []multipassInstance `json:"list"`
+	}
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse multipass output: %w", err)
+	}
+	return list.List, nil
+}
+
+// parseMultipassState converts a Multipass instance state to VMState
+func parseMultipassState(state string) types.VMState {
+	switch state {
+	case "Running":
+		return types.VMStateRunning
+	case "Stopped":
+		return types.VMStateStopped
+	case "Suspended":
+		return types.VMStateSuspended
+	case "Starting", "Restarting":
+		return types.VMStateStarting
+	case "Suspending", "Delayed Shutdown":
+		return types.VMStateStopping
+	case "Deleted":
+		return types.VMStateNotFound
+	default:
+		return types.VMStateUnknown
+	}
+}
+
+// List returns all Multipass instances
+func (m *MultipassAdapter) List() ([]*types.VMInfo, error) {
+	out, err := m.multipass("list", "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+	instances, err := parseMultipassList(out)
+	if err != nil {
+		return nil, err
+	}
+	infos := []*types.VMInfo{}
+	for _, i := range instances {
+		info := &types.VMInfo{
+			Name:    i.Name,
+			State:   parseMultipassState(i.State),
+			Backend: "multipass",
+			OSType:  i.Release,
+		}
+		if len(i.IPv4) > 0 {
+			info.IP = i.IPv4[0]
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func (m *MultipassAdapter) getInfo(vmName string) (*multipassInfo, error) {
+	out, err := m.multipass("info", vmName, "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Info map[string]*multipassInfo `json:"info"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse multipass output: %w", err)
+	}
+	info, ok := result.Info[vmName]
+	if !ok {
+		return nil, fmt.Errorf("VM '%s' not found", vmName)
+	}
+	return info, nil
+}
+
+// GetInfo gets information about a virtual machine
+func (m *MultipassAdapter) GetInfo(vmName string) (*types.VMInfo, error) {
+	mi, err := m.getInfo(vmName)
+	if err != nil {
+		return nil, err
+	}
+	info := &types.VMInfo{
+		Name:    vmName,
+		State:   parseMultipassState(mi.State),
+		Backend: "multipass",
+		OSType:  mi.Release,
+	}
+	if cpus, err := mi.CPUCount.Int64(); err == nil {
+		info.CPUs = int(cpus)
+	}
+	if mi.Memory.Total > 0 {
+		info.RAM = strconv.FormatInt(mi.Memory.Total/1024/1024, 10) + "M"
+	}
+	for _, disk := range mi.Disks {
+		if total, err := disk.Total.Int64(); err == nil && total > 0 {
+			info.DiskSize = strconv.FormatInt(total/1024/1024/1024, 10) + "G"
+			break
+		}
+	}
+	if len(mi.IPv4) > 0 {
+		info.IP = mi.IPv4[0]
+	}
+	return info, nil
+}
+
+// GetState returns the state of a virtual machine
+func (m *MultipassAdapter) GetState(vmName string) types.VMState {
+	mi, err := m.getInfo(vmName)
+	if err != nil {
+		return types.VMStateNotFound
+	}
+	return parseMultipassState(mi.State)
+}
+
+// GetIP returns the first IPv4 address of the instance
+func (m *MultipassAdapter) GetIP(vmName string) (string, error) {
+	mi, err := m.getInfo(vmName)
+	if err != nil {
+		return "", err
+	}
+	if len(mi.IPv4) == 0 {
+		return "", fmt.Errorf("could not determine IP address for VM '%s'", vmName)
+	}
+	return mi.IPv4[0], nil
+}
+
+// VM Connection
+
+// IsSSHReady checks if the SSH port of the VM accepts connections
+func (m *MultipassAdapter) IsSSHReady(vmName string) bool {
+	ip, err := m.GetIP(vmName)
+	return err == nil && sshPortOpen(ip, 22)
+}
+
+// Connect logs in over SSH for instances created by portunix and through
+// 'multipass shell' or 'multipass exec' for the others
+func (m *MultipassAdapter) Connect(vmName string, opts SSHOptions) error {
+	if _, err := virt.LoadInstance(vmName); err == nil {
+		return connectInstance(m, vmName, opts)
+	}
+	args := []string{"shell", vmName}
+	if opts.Command != "" {
+		args = []string{"exec", vmName, "--", "sh", "-c", opts.Command}
+	}
+	cmd := exec.Command("multipass", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// Snapshot Management
+
+// CreateSnapshot snapshots a stopped instance
+func (m *MultipassAdapter) CreateSnapshot(vmName, snapshotName, description string) error {
+	args := []string{"snapshot", vmName, "--name", snapshotName}
+	if description != "" {
+		args = append(args, "--comment", description)
+	}
+	_, err := m.multipass(args...)
+	return err
+}
+
+// ListSnapshots lists the snapshots of an instance
+func (m *MultipassAdapter) ListSnapshots(vmName string) ([]*SnapshotInfo, error) {
+	out, err := m.multipass("list", "--snapshots", "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Info map[string]map[string]struct {
+			Comment string `json:"comment"`
+			Parent  string `json:"parent"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse multipass output: %w", err)
+	}
+	snapshots := []*SnapshotInfo{}
+	for name, s := range result.Info[vmName] {
+		snapshots = append(snapshots, &SnapshotInfo{Name: name, VM: vmName, Description: s.Comment, Parent: s.Parent})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name < snapshots[j].Name })
+	return snapshots, nil
+}
+
+// RevertSnapshot restores a snapshot, discarding the current state
+func (m *MultipassAdapter) RevertSnapshot(vmName, snapshotName string) error {
+	_, err := m.multipass("restore", vmName+"."+snapshotName, "--destructive")
+	return err
+}
+
+// DeleteSnapshot deletes a snapshot
+func (m *MultipassAdapter) DeleteSnapshot(vmName, snapshotName string) error {
+	_, err := m.multipass("delete", vmName+"."+snapshotName, "--purge")
+	return err
+}
+
+// File Operations
+
+// CopyToVM copies a file to a virtual machine
+func (m *MultipassAdapter) CopyToVM(vmName, localPath, remotePath string) error {
+	_, err := m.multipass("transfer", localPath, vmName+":"+remotePath)
+	return err
+}
+
+// CopyFromVM copies a file from a virtual machine
+func (m *MultipassAdapter) CopyFromVM(vmName, remotePath, localPath string) error {
+	_, err := m.multipass("transfer", vmName+":"+remotePath, localPath)
+	return err
+}
@@ -7,6 +7,7 @@ package main
 import (
 	"time"
 
+	"portunix.ai/app/virt"
 	"portunix.ai/app/virt/types"
 )
 
@@ -48,6 +49,47 @@ type VirtualizationProvider interface {
 	CopyFromVM(vmName, remotePath, localPath string) error
 }
 
+// CloudImageProvider is implemented by providers that can boot a cloud
+// image configured by cloud-init
+type CloudImageProvider interface {
+	// CreateFromImage creates and starts the VM. Providers that reach the
+	// guest through a port forward set inst.IP and inst.SSHPort.
+	CreateFromImage(spec *CloudVMSpec, inst *virt.Instance) error
+}
+
+// CloudVMSpec describes a VM created from a cloud image
+type CloudVMSpec struct {
+	Name      string
+	Image     *virt.CloudImage
+	ImagePath string // downloaded base image
+	RAMMB     int
+	CPUs      int
+	DiskGB    int
+	// Dir is the instance directory for cloud-init data and local disks
+	Dir       string
+	CloudInit *virt.CloudInit
+}
+
+// ManagedImageProvider is implemented by cloud image providers that fetch
+// images themselves; 'create --image' does not download the image for them
+type ManagedImageProvider interface {
+	SupportsImage(img *virt.CloudImage) bool
+}
+
+// DistroProvider is implemented by providers that install named
+// distributions rather than disk images, e.g. WSL
+type DistroProvider interface {
+	CreateDistro(spec *DistroSpec, inst *virt.Instance) error
+}
+
+// DistroSpec describes an environment created from a distribution
+type DistroSpec struct {
+	Name     string
+	Distro   string // e.g. Ubuntu-22.04 from 'wsl --list --online'
+	User     string
+	Packages []string
+}
+
 // DiagnosticInfo contains diagnostic information for troubleshooting
 type DiagnosticInfo struct {
 	Platform          string            `json:"platform"`
@@ -109,17 +151,18 @@ var ProviderPriority = []string{
 	"kvm",
 	"vmware",
 	"hyperv",
+	"multipass",
 }
 
 // GetProviderPriority returns the priority order for providers on the current platform
 func GetProviderPriority(platform string) []string {
 	switch platform {
 	case "windows":
-		return []string{"virtualbox", "hyperv", "vmware", "qemu"}
+		return []string{"virtualbox", "hyperv", "vmware", "qemu", "multipass"}
 	case "linux":
-		return []string{"kvm", "qemu", "virtualbox", "vmware"}
+		return []string{"kvm", "qemu", "virtualbox", "vmware", "multipass"}
 	case "darwin":
-		return []string{"virtualbox", "vmware", "qemu"}
+		return []string{"virtualbox", "vmware", "qemu", "multipass"}
 	default:
 		return ProviderPriority
 	}
@@ -7,6 +7,7 @@ package main
 import (
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"portunix.ai/app/system"
@@ -280,11 +281,94 @@ func (q *QEMUAdapter) parseVirshDominfo(output []byte, vmName string) *types.VMI
 	return vm
 }
 
-// VM Lifecycle Management (stubs for basic adapter)
+// VM Lifecycle Management
 
-// Create creates a new virtual machine
+// virsh runs a virsh command and turns its output into a readable error
+func (q *QEMUAdapter) virsh(vmName string, args ...string) error {
+	if err := q.checkLibvirtReady(); err != nil {
+		return err
+	}
+	output, err := exec.Command("virsh", args...).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	outputStr := strings.TrimSpace(string(output))
+	switch {
+	case strings.Contains(outputStr, "Domain not found") || strings.Contains(outputStr, "failed to get domain"):
+		return fmt.Errorf("VM '%s' not found", vmName)
+	case strings.Contains(outputStr, "Permission denied") || strings.Contains(outputStr, "authentication"):
+		return fmt.Errorf("permission denied - add user to libvirt group: sudo usermod -aG libvirt $USER")
+	case outputStr != "":
+		return fmt.Errorf("%s", outputStr)
+	}
+	return fmt.Errorf("virsh %s failed: %v", args[0], err)
+}
+
+// Create creates a new virtual machine booting from an installation ISO
 func (q *QEMUAdapter) Create(config *types.VMConfig) error {
-	return fmt.Errorf("VM creation requires libvirt (use: portunix virt install-qemu)")
+	if config.ISO == "" {
+		return fmt.Errorf("an installation ISO is required (or use --image for a cloud image)")
+	}
+	if err := q.checkLibvirtReady(); err != nil {
+		return err
+	}
+	if _, err := exec.LookPath("virt-install"); err != nil {
+		return fmt.Errorf("virt-install not found (use: portunix install virt-manager)")
+	}
+	ramMB, err := parseSizeMB(config.RAM)
+	if err != nil {
+		return err
+	}
+	diskMB, err := parseSizeMB(config.DiskSize)
+	if err != nil {
+		return err
+	}
+	args := []string{
+		"--name", config.Name,
+		"--memory", strconv.Itoa(ramMB),
+		"--vcpus", strconv.Itoa(config.CPUs),
+		"--disk", fmt.Sprintf("size=%d,format=qcow2,bus=virtio", (diskMB+1023)/1024),
+		"--cdrom", config.ISO,
+		"--osinfo", "detect=on,require=off",
+		"--graphics", "spice",
+		"--noautoconsole",
+	}
+	if output, err := exec.Command("virt-install", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("virt-install failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// CreateFromImage creates a VM on top of the cloud image. virt-install
+// creates a copy-on-write disk in the default storage pool and passes the
+// cloud-init data to the guest.
+func (q *QEMUAdapter) CreateFromImage(spec *CloudVMSpec, inst *virt.Instance) error {
+	if err := q.checkLibvirtReady(); err != nil {
+		return err
+	}
+	if _, err := exec.LookPath("virt-install"); err != nil {
+		return fmt.Errorf("virt-install not found (use: portunix install virt-manager)")
+	}
+	userData, metaData, err := spec.CloudInit.WriteFiles(spec.Dir)
+	if err != nil {
+		return err
+	}
+	args := []string{
+		"--name", spec.Name,
+		"--memory", strconv.Itoa(spec.RAMMB),
+		"--vcpus", strconv.Itoa(spec.CPUs),
+		"--disk", fmt.Sprintf("size=%d,backing_store=%s,backing_format=qcow2,format=qcow2,bus=virtio", spec.DiskGB, spec.ImagePath),
+		"--cloud-init", fmt.Sprintf("user-data=%s,meta-data=%s", userData, metaData),
+		"--osinfo", "detect=on,name=" + spec.Image.OSVariant + ",require=off",
+		"--channel", "unix,target.type=virtio,target.name=org.qemu.guest_agent.0",
+		"--import",
+		"--graphics", "none",
+		"--noautoconsole",
+	}
+	if output, err := exec.Command("virt-install", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("virt-install failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
 }
 
 // Start starts a virtual machine using virsh
@@ -329,29 +413,48 @@ func (q *QEMUAdapter) Start(vmName string) error {
 	return nil
 }
 
-// Stop stops a virtual machine
+// Stop shuts a virtual machine down; force powers it off immediately
 func (q *QEMUAdapter) Stop(vmName string, force bool) error {
-	return fmt.Errorf("VM management requires libvirt (use: portunix virt install-qemu)")
+	if q.GetState(vmName) == types.VMStateStopped {
+		return nil
+	}
+	if force {
+		return q.virsh(vmName, "destroy", vmName)
+	}
+	return q.virsh(vmName, "shutdown", vmName)
 }
 
 // Restart restarts a virtual machine
 func (q *QEMUAdapter) Restart(vmName string) error {
-	return fmt.Errorf("VM management requires libvirt (use: portunix virt install-qemu)")
+	return q.virsh(vmName, "reboot", vmName)
 }
 
 // Suspend suspends a virtual machine
 func (q *QEMUAdapter) Suspend(vmName string) error {
-	return fmt.Errorf("VM management requires libvirt (use: portunix virt install-qemu)")
+	return q.virsh(vmName, "suspend", vmName)
 }
 
 // Resume resumes a virtual machine
 func (q *QEMUAdapter) Resume(vmName string) error {
-	return fmt.Errorf("VM management requires libvirt (use: portunix virt install-qemu)")
+	return q.virsh(vmName, "resume", vmName)
 }
 
-// Delete deletes a virtual machine
+// Delete removes a virtual machine and, unless keepDisk is set, its storage
 func (q *QEMUAdapter) Delete(vmName string, keepDisk bool) error {
-	return fmt.Errorf("VM management requires libvirt (use: portunix virt install-qemu)")
+	state := q.GetState(vmName)
+	if state == types.VMStateNotFound {
+		return fmt.Errorf("VM '%s' not found", vmName)
+	}
+	if state != types.VMStateStopped {
+		if err := q.virsh(vmName, "destroy", vmName); err != nil {
+			return err
+		}
+	}
+	args := []string{"undefine", vmName, "--nvram", "--snapshots-metadata"}
+	if !keepDisk {
+		args = append(args, "--remove-all-storage")
+	}
+	return q.virsh(vmName, args...)
 }
 
 // VM Information
@@ -507,46 +610,65 @@ func (q *QEMUAdapter) parseVirshDomifaddr(output []byte) string {
 
 // VM Connection
 
-// IsSSHReady checks if SSH is ready on the VM
+// IsSSHReady checks if the SSH port of the VM accepts connections
 func (q *QEMUAdapter) IsSSHReady(vmName string) bool {
-	return false
+	ip, err := q.GetIP(vmName)
+	if err != nil {
+		return false
+	}
+	return sshPortOpen(ip, 22)
 }
 
-// Connect connects to a virtual machine via SSH
+// Connect opens an SSH session to a VM created from a cloud image
 func (q *QEMUAdapter) Connect(vmName string, opts SSHOptions) error {
-	return fmt.Errorf("VM SSH connection requires libvirt (use: portunix virt install-qemu)")
+	return connectInstance(q, vmName, opts)
 }
 
 // Snapshot Management
 
 // CreateSnapshot creates a snapshot of a virtual machine
 func (q *QEMUAdapter) CreateSnapshot(vmName, snapshotName, description string) error {
-	return fmt.Errorf("Snapshot management requires libvirt (use: portunix virt install-qemu)")
+	args := []string{"snapshot-create-as", vmName, snapshotName}
+	if description != "" {
+		args = append(args, "--description", description)
+	}
+	return q.virsh(vmName, args...)
 }
 
 // ListSnapshots lists all snapshots of a virtual machine
 func (q *QEMUAdapter) ListSnapshots(vmName string) ([]*SnapshotInfo, error) {
-	return nil, fmt.Errorf("Snapshot management requires libvirt (use: portunix virt install-qemu)")
+	if err := q.checkLibvirtReady(); err != nil {
+		return nil, err
+	}
+	output, err := exec.Command("virsh", "snapshot-list", vmName, "--name").Output()
+	if err != nil {
+		return nil, fmt.Errorf("VM '%s' not found", vmName)
+	}
+	snapshots := []*SnapshotInfo{}
+	for _, name := range strings.Fields(string(output)) {
+		snapshots = append(snapshots, &SnapshotInfo{Name: name, VM: vmName})
+	}
+	return snapshots, nil
 }
 
 // RevertSnapshot reverts to a snapshot
 func (q *QEMUAdapter) RevertSnapshot(vmName, snapshotName string) error {
-	return fmt.Errorf("Snapshot management requires libvirt (use: portunix virt install-qemu)")
+	return q.virsh(vmName, "snapshot-revert", vmName, snapshotName)
 }
 
 // DeleteSnapshot deletes a snapshot
 func (q *QEMUAdapter) DeleteSnapshot(vmName, snapshotName string) error {
-	return fmt.Errorf("Snapshot management requires libvirt (use: portunix virt install-qemu)")
+	return q.virsh(vmName, "snapshot-delete", vmName, snapshotName)
 }
 
 // File Operations
 
 // CopyToVM copies a file to a virtual machine
 func (q *QEMUAdapter) CopyToVM(vmName, localPath, remotePath string) error {
-	return fmt.Errorf("File operations require libvirt (use: portunix virt install-qemu)")
+	return copyInstanceFile(q, vmName, localPath, ":"+remotePath)
 }
 
 // CopyFromVM copies a file from a virtual machine
 func (q *QEMUAdapter) CopyFromVM(vmName, remotePath, localPath string) error {
-	return fmt.Errorf("File operations require libvirt (use: portunix virt install-qemu)")
+	return copyInstanceFile(q, vmName, ":"+remotePath, localPath)
 }
@@ -5,8 +5,14 @@
 package main
 
 import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
+	"portunix.ai/app/virt"
 	"portunix.ai/app/virt/types"
 	"portunix.ai/app/virt/virtualbox"
 )
@@ -60,6 +66,49 @@ func (v *VirtualBoxAdapter) Create(config *types.VMConfig) error {
 	return v.backend.Create(config)
 }
 
+// CreateFromImage converts the cloud image to a VDI, attaches the
+// cloud-init seed ISO and starts the VM headless. The guest sits behind NAT,
+// so SSH is forwarded from a free port on 127.0.0.1.
+func (v *VirtualBoxAdapter) CreateFromImage(spec *CloudVMSpec, inst *virt.Instance) error {
+	if _, err := exec.LookPath("qemu-img"); err != nil {
+		return fmt.Errorf("qemu-img is required to convert cloud images (use: portunix install qemu)")
+	}
+	seed, err := spec.CloudInit.BuildSeedISO(spec.Dir)
+	if err != nil {
+		return err
+	}
+	vdi := filepath.Join(spec.Dir, spec.Name+".vdi")
+	if output, err := exec.Command("qemu-img", "convert", "-O", "vdi", spec.ImagePath, vdi).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to convert image: %s", strings.TrimSpace(string(output)))
+	}
+	port, err := freeLocalPort()
+	if err != nil {
+		return err
+	}
+
+	name := spec.Name
+	commands := [][]string{
+		{"modifymedium", "disk", vdi, "--resize", strconv.Itoa(spec.DiskGB * 1024)},
+		{"createvm", "--name", name, "--ostype", "Linux_64", "--basefolder", spec.Dir, "--register"},
+		{"modifyvm", name, "--memory", strconv.Itoa(spec.RAMMB), "--cpus", strconv.Itoa(spec.CPUs)},
+		// Cloud images expect a serial console and stall at boot without one
+		{"modifyvm", name, "--uart1", "0x3F8", "4", "--uartmode1", "disconnected"},
+		{"modifyvm", name, "--nic1", "nat", "--natpf1", fmt.Sprintf("ssh,tcp,127.0.0.1,%d,,22", port)},
+		{"storagectl", name, "--name", "SATA", "--add", "sata", "--controller", "IntelAhci"},
+		{"storageattach", name, "--storagectl", "SATA", "--port", "0", "--device", "0", "--type", "hdd", "--medium", vdi},
+		{"storageattach", name, "--storagectl", "SATA", "--port", "1", "--device", "0", "--type", "dvddrive", "--medium", seed},
+		{"startvm", name, "--type", "headless"},
+	}
+	for _, args := range commands {
+		if _, err := v.backend.VBoxManage(args...); err != nil {
+			return err
+		}
+	}
+	inst.IP = "127.0.0.1"
+	inst.SSHPort = port
+	return nil
+}
+
 // Start starts a virtual machine
 func (v *VirtualBoxAdapter) Start(vmName string) error {
 	return v.backend.Start(vmName)
@@ -121,6 +170,10 @@ func (v *VirtualBoxAdapter) IsSSHReady(vmName string) bool {
 
 // Connect connects to a virtual machine via SSH
 func (v *VirtualBoxAdapter) Connect(vmName string, opts SSHOptions) error {
+	if _, err := virt.LoadInstance(vmName); err == nil {
+		return connectInstance(v, vmName, opts)
+	}
+
 	// Convert our SSH options to VirtualBox SSH options
 	// Note: VirtualBox types.SSHOptions has different fields, so we use Command only
 	vboxOpts := types.SSHOptions{
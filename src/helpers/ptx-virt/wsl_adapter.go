@@ -0,0 +1,453 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"portunix.ai/app/virt"
+	"portunix.ai/app/virt/types"
+)
+
+// WSLAdapter implements VirtualizationProvider on top of WSL2
+// distributions. A distribution is not a VM with its own network and SSH
+// server, so it is entered with 'wsl -d' instead of ssh.
+type WSLAdapter struct{}
+
+// wsl runs wsl.exe and returns its trimmed output. WSL_UTF8 makes wsl.exe
+// print UTF-8 instead of UTF-16; NUL bytes are stripped for older builds
+// that ignore it.
+func (w *WSLAdapter) wsl(args ...string) (string, error) {
+	cmd := exec.Command("wsl", args...)
+	cmd.Env = append(os.Environ(), "WSL_UTF8=1")
+	output, err := cmd.CombinedOutput()
+	outputStr := strings.TrimSpace(strings.ReplaceAll(string(output), "\x00", ""))
+	if err != nil {
+		if outputStr != "" {
+			return "", fmt.Errorf("%s", firstLine(outputStr))
+		}
+		return "", err
+	}
+	return outputStr, nil
+}
+
+// distroDir is where portunix keeps the disk of a distribution it installed
+// or imported
+func distroDir(name string) string {
+	return filepath.Join(virt.InstanceDir(name), "disk")
+}
+
+// Provider Information
+
+// GetName returns the provider name
+func (w *WSLAdapter) GetName() string {
+	return "wsl"
+}
+
+// GetVersion returns the WSL version
+func (w *WSLAdapter) GetVersion() (string, error) {
+	out, err := w.wsl("--version")
+	if err != nil {
+		return "", err
+	}
+	// "WSL version: 2.3.26.0"
+	if _, v, ok := strings.Cut(firstLine(out), ":"); ok {
+		return strings.TrimSpace(v), nil
+	}
+	return firstLine(out), nil
+}
+
+// IsAvailable checks if wsl.exe is present
+func (w *WSLAdapter) IsAvailable() bool {
+	if runtime.GOOS != "windows" {
+		return false
+	}
+	_, err := exec.LookPath("wsl")
+	return err == nil
+}
+
+// GetDiagnosticInfo returns diagnostic information
+func (w *WSLAdapter) GetDiagnosticInfo() *DiagnosticInfo {
+	diag := &DiagnosticInfo{Platform: runtime.GOOS, Suggestions: []string{}, Details: []string{}}
+	if out, err := w.wsl("--status"); err == nil {
+		for _, line := range strings.Split(out, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				diag.Details = append(diag.Details, line)
+			}
+		}
+	} else {
+		diag.Suggestions = append(diag.Suggestions, "Enable WSL with: wsl --install --no-distribution")
+	}
+	return diag
+}
+
+// VM Lifecycle Management
+
+// Create is not supported: WSL installs distributions, not ISOs
+func (w *WSLAdapter) Create(config *types.VMConfig) error {
+	return fmt.Errorf("WSL cannot install from an ISO; use --distro (see 'wsl --list --online')")
+}
+
+// CreateDistro installs a distribution from the WSL catalog under the
+// instance name and sets up user as its default user with passwordless sudo
+func (w *WSLAdapter) CreateDistro(spec *DistroSpec, inst *virt.Instance) error {
+	if w.GetState(spec.Name) != types.VMStateNotFound {
+		return fmt.Errorf("WSL distribution '%s' already exists", spec.Name)
+	}
+	args := []string{"--install", "--distribution", spec.Distro, "--no-launch"}
+	if spec.Name != spec.Distro {
+		// --name and --location need WSL 2.4.4 or later
+		if err := os.MkdirAll(distroDir(spec.Name), 0755); err != nil {
+			return err
+		}
+		args = append(args, "--name", spec.Name, "--location", distroDir(spec.Name))
+	}
+	if _, err := w.wsl(args...); err != nil {
+		return fmt.Errorf("failed to install %s: %w", spec.Distro, err)
+	}
+	if w.GetState(spec.Name) == types.VMStateNotFound {
+		return fmt.Errorf("%s was installed but not registered as '%s'; update WSL with 'wsl --update'", spec.Distro, spec.Name)
+	}
+
+	script := wslSetupScript(spec.User, spec.Packages)
+	if _, err := w.wsl("-d", spec.Name, "-u", "root", "--exec", "sh", "-c", script); err != nil {
+		return fmt.Errorf("failed to set up user %s: %w", spec.User, err)
+	}
+	// wsl.conf is read when the distribution boots
+	if _, err := w.wsl("--terminate", spec.Name); err != nil {
+		return err
+	}
+	return nil
+}
+
+// wslSetupScript returns the root shell script that creates the default
+// user and installs packages
+func wslSetupScript(user string, packages []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "set -e\nid -u %[1]s >/dev/null 2>&1 || useradd -m -s /bin/bash %[1]s\n", user)
+	fmt.Fprintf(&b, "mkdir -p /etc/sudoers.d\necho '%[1]s ALL=(ALL) NOPASSWD:ALL' > /etc/sudoers.d/%[1]s\nchmod 0440 /etc/sudoers.d/%[1]s\n", user)
+	fmt.Fprintf(&b, "printf '[user]\\ndefault=%s\\n' >> /etc/wsl.conf\n", user)
+	if len(packages) > 0 {
+		pkgs := strings.Join(packages, " ")
+		fmt.Fprintf(&b, "if command -v apt-get >/dev/null; then apt-get update -q && DEBIAN_FRONTEND=noninteractive apt-get install -y -q %[1]s; "+
+			"elif command -v dnf >/dev/null; then dnf install -y -q %[1]s; "+
+			"elif command -v zypper >/dev/null; then zypper -n install %[1]s; fi\n", pkgs)
+	}
+	return b.String()
+}
+
+// Start boots the distribution
+func (w *WSLAdapter) Start(vmName string) error {
+	_, err := w.wsl("-d", vmName, "--exec", "true")
+	return err
+}
+
+// Stop terminates the distribution; WSL has no graceful shutdown of a
+// single distribution
+func (w *WSLAdapter) Stop(vmName string, force bool) error {
+	_, err := w.wsl("--terminate", vmName)
+	return err
+}
+
+// Restart terminates and boots the distribution
+func (w *WSLAdapter) Restart(vmName string) error {
+	if err := w.Stop(vmName, false); err != nil {
+		return err
+	}
+	return w.Start(vmName)
+}
+
+// Suspend is not supported by WSL
+func (w *WSLAdapter) Suspend(vmName string) error {
+	return fmt.Errorf("WSL distributions cannot be suspended")
+}
+
+// Resume is not supported by WSL
+func (w *WSLAdapter) Resume(vmName string) error {
+	return fmt.Errorf("WSL distributions cannot be suspended")
+}
+
+// Delete unregisters the distribution, which deletes its disk. With
+// keepDisk the file system is exported to the instance directory first.
+func (w *WSLAdapter) Delete(vmName string, keepDisk bool) error {
+	if keepDisk {
+		export := filepath.Join(virt.InstanceDir(vmName), vmName+".tar")
+		if err := os.MkdirAll(filepath.Dir(export), 0755); err != nil {
+			return err
+		}
+		if _, err := w.wsl("--export", vmName, export); err != nil {
+			return fmt.Errorf("failed to export %s: %w", vmName, err)
+		}
+		fmt.Printf("File system exported to %s\n", export)
+	}
+	_, err := w.wsl("--unregister", vmName)
+	return err
+}
+
+// VM Information
+
+// wslDistro is a line of 'wsl --list --verbose'
+type wslDistro struct {
+	Name    string
+	State   string
+	Version string
+	Default bool
+}
+
+// parseWSLList parses 'wsl --list --verbose':
+//
+//	  NAME            STATE           VERSION
+//	* Ubuntu-22.04    Running         2
+func parseWSLList(out string) []wslDistro {
+	var distros []wslDistro
+	lines := strings.Split(strings.ReplaceAll(out, "\x00", ""), "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) == 0 {
+			continue // header
+		}
+		d := wslDistro{}
+		if fields[0] == "*" {
+			d.Default = true
+			fields = fields[1:]
+		}
+		if len(fields) < 3 {
+			continue
+		}
+		d.Name, d.State, d.Version = fields[0], fields[1], fields[2]
+		distros = append(distros, d)
+	}
+	return distros
+}
+
+// parseWSLState converts a WSL distribution state to VMState
+func parseWSLState(state string) types.VMState {
+	switch state {
+	case "Running":
+		return types.VMStateRunning
+	case "Stopped":
+		return types.VMStateStopped
+	case "Installing", "Converting":
+		return types.VMStateStarting
+	case "Uninstalling":
+		return types.VMStateStopping
+	default:
+		return types.VMStateUnknown
+	}
+}
+
+func (w *WSLAdapter) distros() ([]wslDistro, error) {
+	out, err := w.wsl("--list", "--verbose")
+	if err != nil {
+		// wsl.exe fails when no distribution is installed
+		if strings.Contains(err.Error(), "no installed distributions") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseWSLList(out), nil
+}
+
+func (w *WSLAdapter) findDistro(vmName string) (*wslDistro, error) {
+	distros, err := w.distros()
+	if err != nil {
+		return nil, err
+	}
+	for i := range distros {
+		if strings.EqualFold(distros[i].Name, vmName) {
+			return &distros[i], nil
+		}
+	}
+	return nil, fmt.Errorf("WSL distribution '%s' not found", vmName)
+}
+
+func (w *WSLAdapter) toVMInfo(d *wslDistro) *types.VMInfo {
+	return &types.VMInfo{
+		Name:    d.Name,
+		State:   parseWSLState(d.State),
+		Backend: "wsl",
+		OSType:  "WSL " + d.Version,
+	}
+}
+
+// List returns all WSL distributions
+func (w *WSLAdapter) List() ([]*types.VMInfo, error) {
+	distros, err := w.distros()
+	if err != nil {
+		return nil, err
+	}
+	infos := []*types.VMInfo{}
+	for i := range distros {
+		infos = append(infos, w.toVMInfo(&distros[i]))
+	}
+	return infos, nil
+}
+
+// GetInfo gets information about a distribution
+func (w *WSLAdapter) GetInfo(vmName string) (*types.VMInfo, error) {
+	d, err := w.findDistro(vmName)
+	if err != nil {
+		return nil, err
+	}
+	info := w.toVMInfo(d)
+	if info.State == types.VMStateRunning {
+		if ip, err := w.GetIP(vmName); err == nil {
+			info.IP = ip
+		}
+	}
+	return info, nil
+}
+
+// GetState returns the state of a distribution
+func (w *WSLAdapter) GetState(vmName string) types.VMState {
+	d, err := w.findDistro(vmName)
+	if err != nil {
+		return types.VMStateNotFound
+	}
+	return parseWSLState(d.State)
+}
+
+// GetIP returns the address of the WSL virtual machine; all WSL2
+// distributions share it
+func (w *WSLAdapter) GetIP(vmName string) (string, error) {
+	out, err := w.wsl("-d", vmName, "--exec", "hostname", "-I")
+	if err != nil {
+		return "", err
+	}
+	if fields := strings.Fields(out); len(fields) > 0 {
+		return fields[0], nil
+	}
+	return "", fmt.Errorf("could not determine IP address for '%s'", vmName)
+}
+
+// VM Connection
+
+// IsSSHReady reports whether the distribution can be entered; WSL is
+// reached through wsl.exe rather than SSH
+func (w *WSLAdapter) IsSSHReady(vmName string) bool {
+	return w.GetState(vmName) != types.VMStateNotFound
+}
+
+// Connect opens a shell in the distribution or runs opts.Command
+func (w *WSLAdapter) Connect(vmName string, opts SSHOptions) error {
+	args := []string{"-d", vmName, "--cd", "~"}
+	if opts.User != "" {
+		args = append(args, "-u", opts.User)
+	}
+	if opts.Command != "" {
+		args = append(args, "--exec", "sh", "-c", opts.Command)
+	}
+	cmd := exec.Command("wsl", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// Snapshot Management
+
+// snapshotPath is the export file of a snapshot
+func snapshotPath(vmName, snapshotName string) string {
+	return filepath.Join(virt.InstanceDir(vmName), "snapshots", snapshotName+".tar")
+}
+
+// CreateSnapshot exports the distribution file system
+func (w *WSLAdapter) CreateSnapshot(vmName, snapshotName, description string) error {
+	if err := virt.ValidateInstanceName(snapshotName); err != nil {
+		return fmt.Errorf("invalid snapshot name %q", snapshotName)
+	}
+	path := snapshotPath(vmName, snapshotName)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	_, err := w.wsl("--export", vmName, path)
+	return err
+}
+
+// ListSnapshots lists the exports of a distribution
+func (w *WSLAdapter) ListSnapshots(vmName string) ([]*SnapshotInfo, error) {
+	entries, err := os.ReadDir(filepath.Dir(snapshotPath(vmName, "x")))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	snapshots := []*SnapshotInfo{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".tar") {
+			continue
+		}
+		s := &SnapshotInfo{Name: strings.TrimSuffix(e.Name(), ".tar"), VM: vmName}
+		if fi, err := e.Info(); err == nil {
+			s.CreatedAt = fi.ModTime()
+			s.Size = fi.Size()
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt) })
+	return snapshots, nil
+}
+
+// RevertSnapshot replaces the distribution with an export
+func (w *WSLAdapter) RevertSnapshot(vmName, snapshotName string) error {
+	path := snapshotPath(vmName, snapshotName)
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("snapshot '%s' not found", snapshotName)
+	}
+	if _, err := w.wsl("--unregister", vmName); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(distroDir(vmName), 0755); err != nil {
+		return err
+	}
+	_, err := w.wsl("--import", vmName, distroDir(vmName), path)
+	return err
+}
+
+// DeleteSnapshot deletes an export
+func (w *WSLAdapter) DeleteSnapshot(vmName, snapshotName string) error {
+	if err := os.Remove(snapshotPath(vmName, snapshotName)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("snapshot '%s' not found", snapshotName)
+		}
+		return err
+	}
+	return nil
+}
+
+// File Operations
+
+// windowsToWSL translates a host path to its /mnt path in the distribution
+func (w *WSLAdapter) windowsToWSL(vmName, path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return w.wsl("-d", vmName, "--exec", "wslpath", "-a", abs)
+}
+
+// CopyToVM copies a file into the distribution
+func (w *WSLAdapter) CopyToVM(vmName, localPath, remotePath string) error {
+	src, err := w.windowsToWSL(vmName, localPath)
+	if err != nil {
+		return err
+	}
+	_, err = w.wsl("-d", vmName, "--exec", "cp", "-r", src, remotePath)
+	return err
+}
+
+// CopyFromVM copies a file out of the distribution
+func (w *WSLAdapter) CopyFromVM(vmName, remotePath, localPath string) error {
+	dst, err := w.windowsToWSL(vmName, localPath)
+	if err != nil {
+		return err
+	}
+	_, err = w.wsl("-d", vmName, "--exec", "cp", "-r", remotePath, dst)
+	return err
+}
@@ -0,0 +1,89 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package platform
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// sysctl reads a sysctl value; replaced in tests
+var sysctl = func(name string) string {
+	out, err := exec.Command("sysctl", "-n", name).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// IsRosettaTranslated returns true if the process is an Intel binary running
+// under Rosetta 2 on Apple Silicon
+func IsRosettaTranslated() bool {
+	return runtime.GOOS == "darwin" && sysctl("sysctl.proc_translated") == "1"
+}
+
+// IsAppleSilicon returns true on Macs with an Apple chip, also when the
+// process itself runs under Rosetta
+func IsAppleSilicon() bool {
+	if runtime.GOOS != "darwin" {
+		return false
+	}
+	return runtime.GOARCH == "arm64" || sysctl("hw.optional.arm64") == "1"
+}
+
+// GetNativeArchitecture returns the architecture of the hardware in the
+// package registry convention. It differs from GetArchitecture only for
+// Intel binaries running under Rosetta, where it reports arm64.
+func GetNativeArchitecture() string {
+	if IsAppleSilicon() {
+		return "arm64"
+	}
+	return GetArchitecture()
+}
+
+// GetCPUBrand returns the CPU model on macOS, e.g. "Apple M2 Pro" or
+// "Intel(R) Core(TM) i7-9750H CPU @ 2.60GHz"; empty on other systems
+func GetCPUBrand() string {
+	if runtime.GOOS != "darwin" {
+		return ""
+	}
+	return sysctl("machdep.cpu.brand_string")
+}
+
+// HomebrewPrefix returns the Homebrew installation prefix: HOMEBREW_PREFIX
+// when set, else /opt/homebrew on Apple Silicon and /usr/local on Intel
+// Macs. It returns "" on other systems.
+func HomebrewPrefix() string {
+	if runtime.GOOS != "darwin" {
+		return ""
+	}
+	if prefix := os.Getenv("HOMEBREW_PREFIX"); prefix != "" {
+		return prefix
+	}
+	return defaultHomebrewPrefix(IsAppleSilicon(), fileExists)
+}
+
+// defaultHomebrewPrefix picks the prefix of an existing brew binary, then
+// the default prefix of the architecture
+func defaultHomebrewPrefix(appleSilicon bool, exists func(string) bool) string {
+	prefixes := []string{"/usr/local", "/opt/homebrew"}
+	if appleSilicon {
+		prefixes = []string{"/opt/homebrew", "/usr/local"}
+	}
+	for _, prefix := range prefixes {
+		if exists(filepath.Join(prefix, "bin", "brew")) {
+			return prefix
+		}
+	}
+	return prefixes[0]
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
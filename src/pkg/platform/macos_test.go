@@ -0,0 +1,38 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package platform
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestDefaultHomebrewPrefix(t *testing.T) {
+	none := func(string) bool { return false }
+	if prefix := defaultHomebrewPrefix(true, none); prefix != "/opt/homebrew" {
+		t.Errorf("Apple Silicon default should be /opt/homebrew, got %s", prefix)
+	}
+	if prefix := defaultHomebrewPrefix(false, none); prefix != "/usr/local" {
+		t.Errorf("Intel default should be /usr/local, got %s", prefix)
+	}
+
+	// An Intel Homebrew kept after migrating to Apple Silicon is still used
+	intelBrew := func(path string) bool { return path == "/usr/local/bin/brew" }
+	if prefix := defaultHomebrewPrefix(true, intelBrew); prefix != "/usr/local" {
+		t.Errorf("existing brew should win, got %s", prefix)
+	}
+}
+
+func TestMacOSDetectionOnOtherSystems(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("checks behavior outside macOS")
+	}
+	if IsAppleSilicon() || IsRosettaTranslated() || GetCPUBrand() != "" || HomebrewPrefix() != "" {
+		t.Error("macOS detection must report nothing on other systems")
+	}
+	if GetNativeArchitecture() != GetArchitecture() {
+		t.Error("native architecture should equal the process architecture")
+	}
+}
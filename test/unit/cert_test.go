@@ -0,0 +1,291 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package unit
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"portunix.ai/app/cert"
+)
+
+func selfSignedPEM(t *testing.T, domains []string, notAfter time.Time) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(0xbeef),
+		Subject:      pkix.Name{CommonName: domains[0]},
+		DNSNames:     domains,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestCertValidateDomains(t *testing.T) {
+	if err := cert.ValidateDomains([]string{"*.example.com", "example.com"}); err != nil {
+		t.Errorf("expected wildcard and apex to be valid: %v", err)
+	}
+	for _, domains := range [][]string{
+		nil,
+		{"example"},
+		{"*.*.example.com"},
+		{"exa mple.com"},
+		{"example.com", "example.com"},
+	} {
+		if err := cert.ValidateDomains(domains); err == nil {
+			t.Errorf("expected %v to be rejected", domains)
+		}
+	}
+	if name := cert.StorageName("*.Example.com."); name != "_wildcard.example.com" {
+		t.Errorf("StorageName = %s", name)
+	}
+}
+
+func TestCertStoreAndExport(t *testing.T) {
+	t.Setenv(cert.EnvDir, t.TempDir())
+
+	domains := []string{"*.example.com", "example.com"}
+	chain, key := selfSignedPEM(t, domains, time.Now().Add(20*24*time.Hour))
+	c := &cert.Certificate{Domains: domains, DNSProvider: "cloudflare", CA: cert.LetsEncryptStagingURL}
+	if err := cert.Store(c, chain, key); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if c.Name != "_wildcard.example.com" || c.Serial != "beef" {
+		t.Errorf("unexpected metadata %+v", c)
+	}
+	if !c.NeedsRenewal(cert.DefaultRenewBefore) || c.NeedsRenewal(10*24*time.Hour) {
+		t.Errorf("NeedsRenewal wrong for certificate expiring in %d days", c.DaysLeft())
+	}
+
+	loaded, err := cert.Load("*.example.com")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !loaded.NotAfter.Equal(c.NotAfter) {
+		t.Errorf("loaded NotAfter %v, want %v", loaded.NotAfter, c.NotAfter)
+	}
+
+	outDir := t.TempDir()
+	result, err := loaded.Export("haproxy", outDir)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	combined, err := os.ReadFile(filepath.Join(outDir, "_wildcard.example.com.pem"))
+	if err != nil {
+		t.Fatalf("haproxy export missing: %v", err)
+	}
+	if string(combined) != string(chain)+string(key) {
+		t.Error("haproxy export must contain the chain followed by the key")
+	}
+	if !strings.Contains(result.Snippet, "crt "+filepath.Join(outDir, "_wildcard.example.com.pem")) {
+		t.Errorf("unexpected snippet %q", result.Snippet)
+	}
+
+	result, err = loaded.Export("nginx", outDir)
+	if err != nil || len(result.Files) != 2 || !strings.Contains(result.Snippet, "ssl_certificate_key") {
+		t.Fatalf("nginx export = %+v, %v", result, err)
+	}
+	if _, err := loaded.Export("apache", outDir); err == nil {
+		t.Error("expected unsupported format error")
+	}
+
+	// Export locations are remembered for renewals
+	reloaded, _ := cert.Load("*.example.com")
+	if len(reloaded.Exports) != 2 {
+		t.Errorf("expected 2 remembered exports, got %+v", reloaded.Exports)
+	}
+
+	list, err := cert.List()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("List = %v, %v", list, err)
+	}
+	if err := cert.Remove("*.example.com"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, err := cert.Load("*.example.com"); err == nil {
+		t.Error("expected certificate to be removed")
+	}
+}
+
+func TestCertCloudflareDNS(t *testing.T) {
+	var created, deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer cf-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/zones":
+			if r.URL.Query().Get("name") == "example.com" {
+				w.Write([]byte(`{"success":true,"result":[{"id":"zone1"}]}`))
+			} else {
+				w.Write([]byte(`{"success":true,"result":[]}`))
+			}
+		case r.Method == http.MethodPost && r.URL.Path == "/zones/zone1/dns_records":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			created = append(created, body["name"].(string)+"="+body["content"].(string))
+			w.Write([]byte(`{"success":true,"result":{"id":"rec` + string(rune('0'+len(created))) + `"}}`))
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/zones/zone1/dns_records/"):
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/zones/zone1/dns_records/"))
+			w.Write([]byte(`{"success":true}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	dns, err := cert.NewDNSProviderWithURL("cloudflare", map[string]string{"CLOUDFLARE_API_TOKEN": "cf-token"}, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	fqdn := "_acme-challenge.api.example.com"
+	if err := dns.Present(ctx, fqdn, "v1"); err != nil {
+		t.Fatalf("Present failed: %v", err)
+	}
+	if err := dns.Present(ctx, fqdn, "v2"); err != nil {
+		t.Fatalf("Present failed: %v", err)
+	}
+	if err := dns.CleanUp(ctx, fqdn, "v1"); err != nil {
+		t.Fatalf("CleanUp failed: %v", err)
+	}
+	if len(created) != 2 || created[0] != fqdn+"=v1" {
+		t.Errorf("created records %v", created)
+	}
+	if len(deleted) != 1 || deleted[0] != "rec1" {
+		t.Errorf("deleted records %v, want rec1", deleted)
+	}
+}
+
+func TestCertHetznerDNS(t *testing.T) {
+	var recordName string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Auth-API-Token") != "hz-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/zones":
+			if r.URL.Query().Get("name") != "example.com" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`{"zones":[{"id":"z1","name":"example.com"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/records":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			recordName = body["name"].(string)
+			w.Write([]byte(`{"record":{"id":"r1"}}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/records/r1":
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	dns, err := cert.NewDNSProviderWithURL("hetzner", map[string]string{"HETZNER_DNS_TOKEN": "hz-token"}, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := dns.Present(ctx, "_acme-challenge.a.example.com", "value"); err != nil {
+		t.Fatalf("Present failed: %v", err)
+	}
+	if recordName != "_acme-challenge.a" {
+		t.Errorf("record name = %s, want name relative to the zone", recordName)
+	}
+	if err := dns.CleanUp(ctx, "_acme-challenge.a.example.com", "value"); err != nil {
+		t.Fatalf("CleanUp failed: %v", err)
+	}
+}
+
+func TestCertRoute53DNS(t *testing.T) {
+	var changes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/us-east-1/route53/aws4_request") {
+			t.Errorf("request not signed: %q", auth)
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/2013-04-01/hostedzonesbyname":
+			w.Write([]byte(`<ListHostedZonesByNameResponse><HostedZones><HostedZone><Id>/hostedzone/Z1</Id><Name>` +
+				r.URL.Query().Get("dnsname") + `.</Name></HostedZone></HostedZones></ListHostedZonesByNameResponse>`))
+		case r.Method == http.MethodPost && r.URL.Path == "/2013-04-01/hostedzone/Z1/rrset":
+			body, _ := io.ReadAll(r.Body)
+			changes = append(changes, string(body))
+			w.Write([]byte(`<ChangeResourceRecordSetsResponse/>`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	dns, err := cert.NewDNSProviderWithURL("route53", map[string]string{"AWS_ACCESS_KEY_ID": "AKID", "AWS_SECRET_ACCESS_KEY": "secret"}, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	fqdn := "_acme-challenge.example.com"
+	dns.Present(ctx, fqdn, "v1")
+	dns.Present(ctx, fqdn, "v2")
+	dns.CleanUp(ctx, fqdn, "v1")
+	if err := dns.CleanUp(ctx, fqdn, "v2"); err != nil {
+		t.Fatalf("CleanUp failed: %v", err)
+	}
+
+	if len(changes) != 4 {
+		t.Fatalf("expected 4 changes, got %d", len(changes))
+	}
+	// The second UPSERT carries both values, the final change deletes the last one
+	if strings.Count(changes[1], "<ResourceRecord>") != 2 || !strings.Contains(changes[1], "v1") || !strings.Contains(changes[1], "v2") {
+		t.Errorf("UPSERT must keep all values:\n%s", changes[1])
+	}
+	if !strings.Contains(changes[3], "<Action>DELETE</Action>") || strings.Contains(changes[3], "v1") {
+		t.Errorf("expected DELETE of remaining value:\n%s", changes[3])
+	}
+}
+
+func TestCertDNSProviderCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	if _, err := cert.NewDNSProvider("route53"); err == nil || !strings.Contains(err.Error(), "AWS_SECRET_ACCESS_KEY") {
+		t.Errorf("expected missing credentials error, got %v", err)
+	}
+	if _, err := cert.NewDNSProvider("godaddy"); err == nil {
+		t.Error("expected unsupported provider error")
+	}
+}
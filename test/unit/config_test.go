@@ -161,3 +161,75 @@ func TestValidateContainerRuntime(t *testing.T) {
 		t.Error("Expected error when setting invalid container runtime 'containerd'")
 	}
 }
+
+func TestConfigScopePriority(t *testing.T) {
+	homeDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(projectDir)
+
+	if err := config.Set(config.ScopeUser, "python.venv_dir", "/user/venvs"); err != nil {
+		t.Fatalf("Failed to set user value: %v", err)
+	}
+	if value, source, _ := config.Lookup("python.venv_dir"); value != "/user/venvs" || source != "user" {
+		t.Errorf("Expected user value, got %q from %q", value, source)
+	}
+
+	if err := config.Set(config.ScopeProject, "python.venv_dir", "/project/venvs"); err != nil {
+		t.Fatalf("Failed to set project value: %v", err)
+	}
+	if value, source, _ := config.Lookup("python.venv_dir"); value != "/project/venvs" || source != "project" {
+		t.Errorf("Expected project value to override user, got %q from %q", value, source)
+	}
+
+	t.Setenv("PORTUNIX_PYTHON_VENV_DIR", "/env/venvs")
+	if value, source, _ := config.Lookup("python.venv_dir"); value != "/env/venvs" || source != "PORTUNIX_PYTHON_VENV_DIR" {
+		t.Errorf("Expected environment to override project, got %q from %q", value, source)
+	}
+
+	// Project file stores dotted keys as nested YAML
+	data, err := os.ReadFile(filepath.Join(projectDir, config.ProjectConfigFile))
+	if err != nil {
+		t.Fatalf("Failed to read project config: %v", err)
+	}
+	if string(data) != "python:\n    venv_dir: /project/venvs\n" {
+		t.Errorf("Unexpected project config content:\n%s", data)
+	}
+}
+
+func TestConfigUnsetAndNamespacedKeys(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(t.TempDir())
+
+	if err := config.Set(config.ScopeUser, "pft.area", "docs"); err != nil {
+		t.Fatalf("Namespaced helper key should be accepted: %v", err)
+	}
+	if err := config.Set(config.ScopeUser, "unknown_key", "x"); err == nil {
+		t.Error("Expected error for unknown top-level key")
+	}
+	if got := config.GetString("pft.area", ""); got != "docs" {
+		t.Errorf("Expected pft.area to be 'docs', got %q", got)
+	}
+
+	// Saving the core config must keep helper keys
+	if err := config.SetConfigValue("container_runtime", "docker"); err != nil {
+		t.Fatalf("Failed to set container_runtime: %v", err)
+	}
+	if got := config.GetString("pft.area", ""); got != "docs" {
+		t.Errorf("Helper key lost after core config update, got %q", got)
+	}
+
+	if err := config.Unset(config.ScopeUser, "pft.area"); err != nil {
+		t.Fatalf("Failed to unset key: %v", err)
+	}
+	if _, _, ok := config.Lookup("pft.area"); ok {
+		t.Error("Expected pft.area to be unset")
+	}
+}
@@ -0,0 +1,222 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package unit
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"portunix.ai/app/daemon"
+	"portunix.ai/app/progress"
+	"portunix.ai/app/system"
+)
+
+type fakeDaemonBackend struct {
+	dir  string
+	args []string
+}
+
+func (b *fakeDaemonBackend) SystemInfo(ctx context.Context) (*system.SystemInfo, error) {
+	return &system.SystemInfo{OS: "linux", Hostname: "test"}, nil
+}
+
+func (b *fakeDaemonBackend) ListContainers(ctx context.Context) (*daemon.ListContainersResponse, error) {
+	return &daemon.ListContainersResponse{
+		Runtime:    "podman",
+		Containers: []daemon.Container{{ID: "abc", Name: "web", State: "running"}},
+	}, nil
+}
+
+func (b *fakeDaemonBackend) Run(ctx context.Context, dir string, args []string, onProgress func(progress.Event)) (*daemon.CommandResponse, error) {
+	b.dir, b.args = dir, args
+	if onProgress != nil {
+		onProgress(progress.Event{Type: progress.EventType, Stage: "download", Percent: 50})
+		onProgress(progress.Event{Type: progress.EventType, Stage: "download", Percent: 100, Done: true})
+	}
+	return &daemon.CommandResponse{Command: args, ExitCode: 0, Output: "ok"}, nil
+}
+
+func startTestDaemon(t *testing.T, backend daemon.Backend) string {
+	t.Helper()
+	t.Setenv(daemon.EnvDir, t.TempDir())
+
+	token, err := daemon.EnsureToken()
+	if err != nil {
+		t.Fatalf("EnsureToken: %v", err)
+	}
+	if info, err := os.Stat(daemon.TokenPath()); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("token file should be private, got %v %v", info.Mode(), err)
+	}
+
+	lis, err := daemon.Listen()
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	server := daemon.NewServer(backend, token, "1.2.3")
+	go server.Serve(lis)
+	t.Cleanup(func() {
+		server.Stop()
+		daemon.Cleanup()
+	})
+	return token
+}
+
+func TestDaemonOperations(t *testing.T) {
+	backend := &fakeDaemonBackend{}
+	startTestDaemon(t, backend)
+
+	client, err := daemon.Connect()
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ping, err := client.Ping(ctx)
+	if err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	if ping.Version != "1.2.3" || ping.PID != os.Getpid() {
+		t.Errorf("unexpected ping response %+v", ping)
+	}
+
+	info, err := client.SystemInfo(ctx)
+	if err != nil || info.Info == nil || info.Info.Hostname != "test" {
+		t.Errorf("SystemInfo = %+v, %v", info, err)
+	}
+
+	resp, err := client.Install(ctx, &daemon.InstallRequest{Package: "nodejs", Variant: "20", DryRun: true})
+	if err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	want := []string{"install", "nodejs", "--variant", "20", "--dry-run"}
+	if !reflect.DeepEqual(backend.args, want) || resp.Output != "ok" {
+		t.Errorf("Install ran %v (output %q), want %v", backend.args, resp.Output, want)
+	}
+
+	containers, err := client.ListContainers(ctx)
+	if err != nil || len(containers.Containers) != 1 || containers.Containers[0].Name != "web" {
+		t.Errorf("ListContainers = %+v, %v", containers, err)
+	}
+
+	if _, err := client.Container(ctx, &daemon.ContainerRequest{Action: "remove", Name: "web"}); err != nil {
+		t.Fatalf("Container: %v", err)
+	}
+	if want := []string{"container", "rm", "web"}; !reflect.DeepEqual(backend.args, want) {
+		t.Errorf("Container ran %v, want %v", backend.args, want)
+	}
+
+	dir := t.TempDir()
+	if _, err := client.PftSync(ctx, &daemon.PftSyncRequest{Dir: dir}); err != nil {
+		t.Fatalf("PftSync: %v", err)
+	}
+	if want := []string{"pft", "sync"}; !reflect.DeepEqual(backend.args, want) || backend.dir != dir {
+		t.Errorf("PftSync ran %v in %s, want %v in %s", backend.args, backend.dir, want, dir)
+	}
+}
+
+func TestDaemonInstallStream(t *testing.T) {
+	backend := &fakeDaemonBackend{}
+	startTestDaemon(t, backend)
+
+	client, err := daemon.Connect()
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var events []progress.Event
+	resp, err := client.InstallStream(ctx, &daemon.InstallRequest{Package: "go"}, func(e progress.Event) {
+		events = append(events, e)
+	})
+	if err != nil {
+		t.Fatalf("InstallStream: %v", err)
+	}
+	if resp.Output != "ok" || !reflect.DeepEqual(backend.args, []string{"install", "go"}) {
+		t.Errorf("InstallStream ran %v with output %q", backend.args, resp.Output)
+	}
+	if len(events) != 2 || events[0].Percent != 50 || !events[1].Done {
+		t.Errorf("progress events %+v", events)
+	}
+
+	_, err = client.InstallStream(ctx, &daemon.InstallRequest{Package: "--force"}, nil)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid package: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestDaemonRejectsInvalidRequests(t *testing.T) {
+	backend := &fakeDaemonBackend{}
+	startTestDaemon(t, backend)
+
+	client, err := daemon.Connect()
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"package with shell characters", func() error {
+			_, err := client.Install(ctx, &daemon.InstallRequest{Package: "node; rm -rf /"})
+			return err
+		}},
+		{"option as package", func() error {
+			_, err := client.Install(ctx, &daemon.InstallRequest{Package: "--force"})
+			return err
+		}},
+		{"unknown container action", func() error {
+			_, err := client.Container(ctx, &daemon.ContainerRequest{Action: "exec", Name: "web"})
+			return err
+		}},
+		{"relative pft directory", func() error {
+			_, err := client.PftSync(ctx, &daemon.PftSyncRequest{Dir: "project"})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		if err := tt.call(); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: got %v, want InvalidArgument", tt.name, err)
+		}
+	}
+	if backend.args != nil {
+		t.Errorf("invalid requests must not run commands, ran %v", backend.args)
+	}
+}
+
+func TestDaemonRequiresToken(t *testing.T) {
+	startTestDaemon(t, &fakeDaemonBackend{})
+
+	target, err := daemon.ReadEndpoint()
+	if err != nil {
+		t.Fatalf("ReadEndpoint: %v", err)
+	}
+	client, err := daemon.NewClient(target, "wrong-token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := client.Ping(ctx); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Ping with wrong token: got %v, want Unauthenticated", err)
+	}
+	if _, err := client.InstallStream(ctx, &daemon.InstallRequest{Package: "go"}, nil); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("InstallStream with wrong token: got %v, want Unauthenticated", err)
+	}
+}
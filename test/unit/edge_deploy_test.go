@@ -0,0 +1,329 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"portunix.ai/app/edge"
+)
+
+func TestEdgeHetznerProvider(t *testing.T) {
+	var created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/ssh_keys":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":{"code":"uniqueness_error","message":"SSH key not unique"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/ssh_keys":
+			if r.URL.Query().Get("name") != "edge-key" {
+				t.Errorf("unexpected key lookup %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"ssh_keys":[{"id":42}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/servers":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"server":{"id":7,"name":"edge1","status":"initializing"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/servers/7":
+			w.Write([]byte(`{"server":{"id":7,"name":"edge1","status":"running","public_net":{"ipv4":{"ip":"203.0.113.7"}}}}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/servers/8":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"not_found","message":"server not found"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	p, err := edge.NewProviderWithURL("hetzner", "secret", server.URL)
+	if err != nil {
+		t.Fatalf("NewProviderWithURL failed: %v", err)
+	}
+	ctx := context.Background()
+
+	keyID, err := p.ImportSSHKey(ctx, "edge-key", "ssh-ed25519 AAAA portunix-edge")
+	if err != nil || keyID != "42" {
+		t.Fatalf("ImportSSHKey = %q, %v; want existing key 42", keyID, err)
+	}
+
+	s, err := p.CreateServer(ctx, edge.ServerSpec{Name: "edge1", Region: "fsn1", Size: "cx22", Image: "ubuntu-24.04", SSHKeys: []string{keyID}})
+	if err != nil {
+		t.Fatalf("CreateServer failed: %v", err)
+	}
+	if s.ID != "7" || s.Ready {
+		t.Errorf("CreateServer = %+v, want id 7 not ready", s)
+	}
+	if created["server_type"] != "cx22" || created["location"] != "fsn1" {
+		t.Errorf("unexpected create request %v", created)
+	}
+
+	ready, err := edge.WaitForServer(ctx, p, "7", time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForServer failed: %v", err)
+	}
+	if ready.PublicIP != "203.0.113.7" {
+		t.Errorf("PublicIP = %s, want 203.0.113.7", ready.PublicIP)
+	}
+
+	err = p.DeleteServer(ctx, "8")
+	if err == nil || !strings.Contains(err.Error(), "server not found") {
+		t.Errorf("DeleteServer error = %v, want API message", err)
+	}
+}
+
+func TestEdgeDigitalOceanProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/account/keys":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"id":"unprocessable_entity","message":"SSH Key is already in use on your account"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/droplets":
+			var body struct {
+				SSHKeys  []string `json:"ssh_keys"`
+				UserData string   `json:"user_data"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if len(body.SSHKeys) != 1 || !strings.Contains(body.SSHKeys[0], ":") {
+				t.Errorf("expected key fingerprint, got %v", body.SSHKeys)
+			}
+			if body.UserData != "#cloud-config\n" {
+				t.Errorf("expected user_data, got %q", body.UserData)
+			}
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"droplet":{"id":99,"name":"edge2","status":"new"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/droplets/99":
+			w.Write([]byte(`{"droplet":{"id":99,"name":"edge2","status":"active","networks":{"v4":[
+				{"ip_address":"10.0.0.5","type":"private"},{"ip_address":"198.51.100.9","type":"public"}]}}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	p, err := edge.NewProviderWithURL("digitalocean", "secret", server.URL)
+	if err != nil {
+		t.Fatalf("NewProviderWithURL failed: %v", err)
+	}
+	ctx := context.Background()
+
+	publicKey, err := edge.EnsureSSHKey(filepath.Join(t.TempDir(), "id_ed25519"))
+	if err != nil {
+		t.Fatalf("EnsureSSHKey failed: %v", err)
+	}
+	fingerprint, err := p.ImportSSHKey(ctx, "edge-key", publicKey)
+	if err != nil {
+		t.Fatalf("ImportSSHKey failed: %v", err)
+	}
+	s, err := p.CreateServer(ctx, edge.ServerSpec{Name: "edge2", SSHKeys: []string{fingerprint}, UserData: "#cloud-config\n"})
+	if err != nil {
+		t.Fatalf("CreateServer failed: %v", err)
+	}
+	ready, err := edge.WaitForServer(ctx, p, s.ID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForServer failed: %v", err)
+	}
+	if ready.PublicIP != "198.51.100.9" {
+		t.Errorf("PublicIP = %s, want public address 198.51.100.9", ready.PublicIP)
+	}
+}
+
+func TestEdgeProviderRequiresToken(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("HCLOUD_TOKEN", "")
+	if _, err := edge.NewProvider("hetzner"); err == nil || !strings.Contains(err.Error(), "HCLOUD_TOKEN") {
+		t.Errorf("expected missing token error, got %v", err)
+	}
+	if _, err := edge.NewProvider("aws"); err == nil {
+		t.Error("expected unsupported provider error")
+	}
+}
+
+func TestEdgeTemplateRender(t *testing.T) {
+	tmpl, err := edge.GetTemplate("caddy-wireguard")
+	if err != nil {
+		t.Fatalf("GetTemplate failed: %v", err)
+	}
+	d := &edge.Deployment{
+		Name:       "edge1",
+		Domain:     "edge.example.com",
+		AdminEmail: "admin@example.com",
+		PublicIP:   "203.0.113.7",
+		WireGuard: edge.WireGuardState{
+			Port:             51820,
+			Network:          "10.100.0.0/24",
+			ServerIP:         "10.100.0.1",
+			ClientIP:         "10.100.0.2",
+			ServerPrivateKey: "server-private",
+			ServerPublicKey:  "server-public",
+			ClientPublicKey:  "client-public",
+		},
+	}
+
+	rendered := map[string]string{}
+	for _, file := range tmpl.Files {
+		data, err := tmpl.Render(file, d, "client-private")
+		if err != nil {
+			t.Fatalf("Render %s failed: %v", file.Name, err)
+		}
+		rendered[file.Name] = string(data)
+	}
+
+	for file, want := range map[string][]string{
+		"wg0.conf":       {"Address = 10.100.0.1/24", "PrivateKey = server-private", "PublicKey = client-public", "ListenPort = 51820"},
+		"wg-client.conf": {"PrivateKey = client-private", "PublicKey = server-public", "Endpoint = 203.0.113.7:51820"},
+		"Caddyfile":      {"edge.example.com", "admin@example.com"},
+	} {
+		for _, s := range want {
+			if !strings.Contains(rendered[file], s) {
+				t.Errorf("%s does not contain %q:\n%s", file, s, rendered[file])
+			}
+		}
+	}
+
+	script, err := edge.PtxbookScript([]byte(rendered[edge.PtxbookFile]), "deploy")
+	if err != nil {
+		t.Fatalf("PtxbookScript failed: %v", err)
+	}
+	if !strings.Contains(script, "wg-quick@wg0") || !strings.Contains(script, "caddy") {
+		t.Errorf("deploy script does not set up WireGuard and Caddy:\n%s", script)
+	}
+	if _, err := edge.PtxbookScript([]byte(rendered[edge.PtxbookFile]), "missing"); err == nil {
+		t.Error("expected error for missing script")
+	}
+}
+
+func TestEdgeDeploymentState(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(edge.EnvStateDir, dir)
+
+	if err := edge.ValidateDeploymentName("Bad_Name"); err == nil {
+		t.Error("expected invalid deployment name")
+	}
+
+	d := &edge.Deployment{Name: "edge1", Provider: "hetzner", ServerID: "7", Status: edge.StatusDeployed}
+	if err := d.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	info, err := os.Stat(edge.DeploymentDir("edge1") + "/state.json")
+	if err != nil {
+		t.Fatalf("state file missing: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("state file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	loaded, err := edge.LoadDeployment("edge1")
+	if err != nil {
+		t.Fatalf("LoadDeployment failed: %v", err)
+	}
+	if loaded.ServerID != "7" || loaded.Status != edge.StatusDeployed {
+		t.Errorf("loaded deployment = %+v", loaded)
+	}
+
+	list, err := edge.ListDeployments()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("ListDeployments = %v, %v", list, err)
+	}
+
+	if err := edge.RemoveDeployment("edge1"); err != nil {
+		t.Fatalf("RemoveDeployment failed: %v", err)
+	}
+	if _, err := edge.LoadDeployment("edge1"); err == nil {
+		t.Error("expected deployment to be removed")
+	}
+}
+
+func TestEdgeDeployDryRun(t *testing.T) {
+	t.Setenv(edge.EnvStateDir, t.TempDir())
+	p, err := edge.NewProviderWithURL("hetzner", "", "")
+	if err != nil {
+		t.Fatalf("NewProviderWithURL failed: %v", err)
+	}
+	var out strings.Builder
+	deployer := &edge.Deployer{Provider: p, Out: &out}
+
+	d, err := deployer.Deploy(context.Background(), edge.DeployOptions{Name: "edge1", Template: "caddy-wireguard", DryRun: true})
+	if err != nil {
+		t.Fatalf("Deploy dry run failed: %v", err)
+	}
+	if d.Region != "fsn1" || d.Size != "cx22" {
+		t.Errorf("provider defaults not applied: %+v", d)
+	}
+	if !strings.Contains(out.String(), "Dry run") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+	if _, err := edge.LoadDeployment("edge1"); err == nil {
+		t.Error("dry run must not store state")
+	}
+
+	_, err = deployer.Deploy(context.Background(), edge.DeployOptions{Name: "edge1", Template: "caddy-wireguard", Domain: "bad domain", DryRun: true})
+	if err == nil {
+		t.Error("expected invalid domain error")
+	}
+}
+
+func TestEdgeRoutes(t *testing.T) {
+	d := &edge.Deployment{Name: "edge1"}
+
+	for _, r := range []edge.Route{
+		{Domain: "bad domain", Target: "10.100.0.2:80"},
+		{Domain: "api.example.com", Target: "10.100.0.2"},
+		{Domain: "api.example.com", Target: "10.100.0.2:99999"},
+		{Domain: "api.example.com", Target: "host;rm -rf /:80"},
+		{Domain: "ssh.example.com", Target: "10.100.0.2:22", TCPPort: 22},
+	} {
+		if _, err := d.SetRoute(r); err == nil {
+			t.Errorf("expected route %+v to be rejected", r)
+		}
+	}
+
+	added, err := d.SetRoute(edge.Route{Domain: "api.example.com", Target: "10.100.0.2:8080"})
+	if err != nil || !added {
+		t.Fatalf("SetRoute = %v, %v", added, err)
+	}
+	added, err = d.SetRoute(edge.Route{Domain: "api.example.com", Target: "10.100.0.2:9090"})
+	if err != nil || added {
+		t.Fatalf("expected route update, got %v, %v", added, err)
+	}
+	if _, err := d.SetRoute(edge.Route{Domain: "db.example.com", Target: "10.100.0.2:5432", TCPPort: 5432}); err != nil {
+		t.Fatalf("SetRoute tcp failed: %v", err)
+	}
+	if _, err := d.SetRoute(edge.Route{Domain: "other.example.com", Target: "10.100.0.3:5432", TCPPort: 5432}); err == nil {
+		t.Error("expected TCP port conflict")
+	}
+	if len(d.Routes) != 2 {
+		t.Fatalf("expected 2 routes, got %+v", d.Routes)
+	}
+
+	caddy := string(edge.RenderCaddyRoutes(d))
+	if !strings.Contains(caddy, "api.example.com {\n\treverse_proxy 10.100.0.2:9090\n\tlog {\n\t\toutput file /var/log/caddy/access.log\n\t}\n}") || strings.Contains(caddy, "db.example.com") {
+		t.Errorf("unexpected Caddy routes:\n%s", caddy)
+	}
+	haproxy := string(edge.RenderHAProxyConfig(d))
+	if !strings.Contains(haproxy, "bind :5432") || !strings.Contains(haproxy, "server target 10.100.0.2:5432") {
+		t.Errorf("unexpected HAProxy config:\n%s", haproxy)
+	}
+
+	if removed := d.RemoveRoute("db.example.com", 0); removed != 1 {
+		t.Errorf("RemoveRoute removed %d routes, want 1", removed)
+	}
+	if edge.RenderHAProxyConfig(d) != nil {
+		t.Error("expected no HAProxy config without TCP routes")
+	}
+}
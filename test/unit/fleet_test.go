@@ -0,0 +1,196 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package unit
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"portunix.ai/app/fleet"
+	"portunix.ai/app/remote"
+)
+
+func testCertificatePEM(t *testing.T, domain string, notAfter time.Time) string {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func testFactOutput(t *testing.T) string {
+	return `### hostname
+web1
+### os-release
+PRETTY_NAME="Ubuntu 24.04.1 LTS"
+ID=ubuntu
+VERSION_ID="24.04"
+### kernel
+6.8.0-45-generic
+### arch
+x86_64
+### packages
+openssh-server
+git
+curl
+### tools
+git
+wg
+### wireguard
+wg0 10.100.0.1/24 2
+### cert /etc/letsencrypt/live/web1.example.com/cert.pem
+` + testCertificatePEM(t, "web1.example.com", time.Now().Add(10*24*time.Hour))
+}
+
+func TestFleetParseFacts(t *testing.T) {
+	f, err := fleet.ParseFacts(testFactOutput(t))
+	if err != nil {
+		t.Fatalf("ParseFacts failed: %v", err)
+	}
+	if f.Hostname != "web1" || f.OS != "ubuntu" || f.OSVersion != "24.04" || f.OSName != "Ubuntu 24.04.1 LTS" || f.Arch != "x86_64" {
+		t.Errorf("unexpected system facts %+v", f)
+	}
+	if strings.Join(f.Packages, " ") != "curl git openssh-server" {
+		t.Errorf("packages = %v", f.Packages)
+	}
+	if len(f.Tunnels) != 1 || f.Tunnels[0].Interface != "wg0" || f.Tunnels[0].Address != "10.100.0.1/24" || f.Tunnels[0].Peers != 2 {
+		t.Errorf("tunnels = %+v", f.Tunnels)
+	}
+	if len(f.Certificates) != 1 || f.Certificates[0].Domains[0] != "web1.example.com" || f.Certificates[0].DaysLeft() != 9 {
+		t.Errorf("certificates = %+v", f.Certificates)
+	}
+	if !f.Has("git") || f.Has("docker") {
+		t.Error("unexpected Has results")
+	}
+	f.Packages = append(f.Packages, "docker.io")
+	if !f.Has("docker") {
+		t.Error("docker.io must satisfy docker")
+	}
+
+	if _, err := fleet.ParseFacts("'sh' is not recognized as a command"); err == nil {
+		t.Error("expected error for output without facts")
+	}
+}
+
+func TestFleetQuery(t *testing.T) {
+	ubuntu, _ := fleet.ParseFacts(testFactOutput(t))
+	debian := &fleet.Facts{OS: "debian", OSName: "Debian GNU/Linux 12 (bookworm)", Packages: []string{"docker-ce"}}
+	hosts := []*fleet.Host{
+		{Name: "web1", Tags: []string{"prod"}, Facts: ubuntu},
+		{Name: "db1", Tags: []string{"prod"}, Facts: debian},
+		{Name: "new", Tags: []string{"staging"}},
+	}
+	names := func(hs []*fleet.Host) string {
+		var n []string
+		for _, h := range hs {
+			n = append(n, h.Name)
+		}
+		return strings.Join(n, ",")
+	}
+	for query, want := range map[string]struct {
+		filter fleet.Filter
+		hosts  string
+	}{
+		"ubuntu without docker": {fleet.Filter{OS: "ubuntu", Missing: []string{"docker"}}, "web1"},
+		"docker":                {fleet.Filter{Has: []string{"docker"}}, "db1"},
+		"os name word":          {fleet.Filter{OS: "Debian"}, "db1"},
+		"tag":                   {fleet.Filter{Tags: []string{"PROD"}}, "web1,db1"},
+		"expiring":              {fleet.Filter{ExpiringDays: 14}, "web1"},
+		"everything":            {fleet.Filter{}, "web1,db1,new"},
+	} {
+		if got := names(fleet.Select(hosts, want.filter)); got != want.hosts {
+			t.Errorf("%s: got %q, want %q", query, got, want.hosts)
+		}
+	}
+}
+
+func TestFleetInventory(t *testing.T) {
+	t.Setenv(fleet.EnvDir, t.TempDir())
+	useGit := true
+	if _, err := exec.LookPath("git"); err != nil {
+		useGit = false
+	}
+	if err := fleet.Init("", useGit); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	h := &fleet.Host{Name: "web1", Address: "deploy@203.0.113.10:2222", Tags: []string{"prod"}}
+	if err := fleet.Save(h, "fleet: add web1"); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := fleet.Save(&fleet.Host{Name: "../evil"}, ""); err == nil {
+		t.Error("expected invalid host name error")
+	}
+	hosts, err := fleet.List()
+	if err != nil || len(hosts) != 1 || hosts[0].Address != h.Address {
+		t.Fatalf("List = %+v, %v", hosts, err)
+	}
+
+	// Inventory names work as --host values
+	target, err := remote.Resolve("web1")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if target.Name != "web1" || target.Destination() != "deploy@203.0.113.10" || target.Port != 2222 {
+		t.Errorf("unexpected target %+v", target)
+	}
+
+	if useGit {
+		out, err := exec.Command("git", "-C", fleet.Dir(), "log", "--format=%s").Output()
+		if err != nil || !strings.Contains(string(out), "fleet: add web1") {
+			t.Errorf("expected inventory change to be committed, got %q, %v", out, err)
+		}
+	}
+
+	if err := fleet.Remove("web1"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, err := fleet.Load("web1"); err == nil {
+		t.Error("expected removed host to be gone")
+	}
+}
+
+func TestFleetScan(t *testing.T) {
+	output := testFactOutput(t)
+	runner := &fakeScanRunner{output: output}
+	f, err := fleet.Scan(runner)
+	if err != nil || f.Hostname != "web1" {
+		t.Fatalf("Scan = %+v, %v", f, err)
+	}
+	if runner.command != "sh -s" || !strings.Contains(runner.script, "### packages") {
+		t.Errorf("scan must pipe the fact script to sh, got %q", runner.command)
+	}
+}
+
+type fakeScanRunner struct {
+	output, command, script string
+}
+
+func (r *fakeScanRunner) Run(command string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+	r.command = command
+	data, _ := io.ReadAll(stdin)
+	r.script = string(data)
+	io.WriteString(stdout, r.output)
+	return nil
+}
@@ -0,0 +1,231 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package unit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"portunix.ai/app/harden"
+)
+
+func TestHardenProfiles(t *testing.T) {
+	p, err := harden.GetProfile("edge", harden.ProfileOptions{SSHPort: 2222, Allow: []string{"8080", "53/udp", "443/tcp"}})
+	if err != nil {
+		t.Fatalf("GetProfile failed: %v", err)
+	}
+	var rules []string
+	for _, r := range p.Rules {
+		rules = append(rules, r.String())
+	}
+	want := "2222/tcp 80/tcp 443/tcp 443/udp 51820/udp 8080/tcp 53/udp"
+	if strings.Join(rules, " ") != want {
+		t.Errorf("rules = %v, want %s", rules, want)
+	}
+
+	ws, err := harden.GetProfile("workstation", harden.ProfileOptions{})
+	if err != nil || len(ws.Rules) != 0 {
+		t.Errorf("workstation must allow no inbound ports, got %+v, %v", ws.Rules, err)
+	}
+
+	for _, spec := range []string{"0/tcp", "70000", "22/icmp", "ssh"} {
+		if _, err := harden.ParseRule(spec); err == nil {
+			t.Errorf("expected rule %q to be rejected", spec)
+		}
+	}
+	if _, err := harden.GetProfile("dmz", harden.ProfileOptions{}); err == nil {
+		t.Error("expected unknown profile error")
+	}
+}
+
+func TestHardenFirewallPlans(t *testing.T) {
+	p, _ := harden.GetProfile("server", harden.ProfileOptions{Allow: []string{"5432/tcp"}})
+	noop := func(name string, args ...string) ([]byte, error) { return nil, nil }
+
+	fw, err := harden.NewFirewallWithRunner("nftables", noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := fw.Plan(p)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	ruleset := plan.Files[0].Content
+	for _, s := range []string{"policy drop;", "ct state established,related accept", `tcp dport 22 accept comment "SSH"`, "tcp dport 5432 accept"} {
+		if !strings.Contains(ruleset, s) {
+			t.Errorf("nftables ruleset missing %q:\n%s", s, ruleset)
+		}
+	}
+
+	fw, _ = harden.NewFirewallWithRunner("ufw", noop)
+	plan, _ = fw.Plan(p)
+	var commands []string
+	for _, c := range plan.Commands {
+		commands = append(commands, strings.Join(c, " "))
+	}
+	if commands[0] != "ufw allow 22/tcp comment SSH" || commands[len(commands)-1] != "ufw --force enable" {
+		t.Errorf("unexpected ufw commands %v", commands)
+	}
+
+	fw, _ = harden.NewFirewallWithRunner("windows", noop)
+	plan, _ = fw.Plan(p)
+	if !strings.Contains(strings.Join(plan.Commands[2], " "), "-Protocol TCP -LocalPort 22") {
+		t.Errorf("unexpected Windows commands %v", plan.Commands)
+	}
+
+	if _, err := harden.NewFirewallWithRunner("pf", noop); err == nil {
+		t.Error("expected unsupported backend error")
+	}
+}
+
+// fakeUfw emulates the ufw commands used by the firewall backend
+type fakeUfw struct {
+	active   bool
+	incoming string
+	added    []string
+	fail     string // command prefix that fails
+}
+
+func (f *fakeUfw) run(name string, args ...string) ([]byte, error) {
+	command := strings.Join(args, " ")
+	if f.fail != "" && strings.HasPrefix(command, f.fail) {
+		return []byte("ERROR: simulated"), errors.New("exit status 1")
+	}
+	switch {
+	case command == "status verbose":
+		status := "Status: inactive\n"
+		if f.active {
+			status = "Status: active\n"
+		}
+		return []byte(status + "Default: " + f.incoming + " (incoming), allow (outgoing), disabled (routed)\n"), nil
+	case command == "show added":
+		out := "Added user rules (see 'ufw status' for running firewall):\n"
+		for _, rule := range f.added {
+			out += "ufw " + rule + "\n"
+		}
+		return []byte(out), nil
+	case strings.HasPrefix(command, "allow "):
+		f.added = append(f.added, command)
+	case strings.HasPrefix(command, "delete "):
+		rule := strings.TrimPrefix(command, "delete ")
+		for i, added := range f.added {
+			if strings.HasPrefix(added, rule) {
+				f.added = append(f.added[:i], f.added[i+1:]...)
+				break
+			}
+		}
+	case strings.HasPrefix(command, "default ") && strings.HasSuffix(command, " incoming"):
+		f.incoming = args[1]
+	case command == "--force enable":
+		f.active = true
+	case command == "disable":
+		f.active = false
+	}
+	return nil, nil
+}
+
+func TestHardenUfwApplyAndRollback(t *testing.T) {
+	t.Setenv(harden.EnvStateDir, t.TempDir())
+	ufw := &fakeUfw{incoming: "allow", added: []string{"allow 3000/tcp"}}
+	fw, err := harden.NewFirewallWithRunner("ufw", ufw.run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := harden.GetProfile("edge", harden.ProfileOptions{})
+	plan, _ := fw.Plan(p)
+
+	if _, err := fw.Apply(plan); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !ufw.active || ufw.incoming != "deny" || len(ufw.added) != 6 {
+		t.Fatalf("unexpected state after apply: %+v", ufw)
+	}
+
+	if _, err := fw.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if ufw.active || ufw.incoming != "allow" || len(ufw.added) != 1 || ufw.added[0] != "allow 3000/tcp" {
+		t.Errorf("rollback did not restore the previous state: %+v", ufw)
+	}
+	if _, err := fw.Rollback(); err == nil {
+		t.Error("expected no snapshot left to roll back to")
+	}
+
+	// A failing step restores the snapshot immediately
+	ufw.fail = "--force enable"
+	if _, err := fw.Apply(plan); err == nil || !strings.Contains(err.Error(), "previous firewall restored") {
+		t.Fatalf("expected failed apply to be restored, got %v", err)
+	}
+	if len(ufw.added) != 1 || ufw.incoming != "allow" {
+		t.Errorf("failed apply left changes behind: %+v", ufw)
+	}
+	if snaps, _ := harden.Snapshots(); len(snaps) != 0 {
+		t.Errorf("failed apply must not keep a snapshot, got %d", len(snaps))
+	}
+}
+
+func TestHardenUfwAddedRules(t *testing.T) {
+	rules := harden.UfwAddedRules("Added user rules (see 'ufw status' for running firewall):\nufw allow 22/tcp comment 'SSH'\nufw limit 2222\n")
+	if len(rules) != 2 || strings.Join(rules[0], " ") != "allow 22/tcp" || strings.Join(rules[1], " ") != "limit 2222" {
+		t.Errorf("unexpected rules %v", rules)
+	}
+}
+
+func TestHardenFail2banPlan(t *testing.T) {
+	plan, err := harden.Fail2banPlan(harden.Fail2banOptions{SSHPort: 2222, Proxy: "caddy", BanTime: 24 * time.Hour, MaxRetry: 3, Banaction: harden.BanactionFor("ufw")})
+	if err != nil {
+		t.Fatalf("Fail2banPlan failed: %v", err)
+	}
+	jail := plan.Files[0].Content
+	for _, s := range []string{"bantime  = 86400", "findtime = 600", "maxretry = 3", "banaction = ufw", "[sshd]\nenabled = true\nport    = 2222", "[portunix-caddy]", "filter   = portunix-caddy"} {
+		if !strings.Contains(jail, s) {
+			t.Errorf("jail configuration missing %q:\n%s", s, jail)
+		}
+	}
+	if len(plan.Files) != 2 || !strings.Contains(plan.Files[1].Content, `"remote_ip":"<HOST>"`) {
+		t.Errorf("expected caddy filter, got %+v", plan.Files)
+	}
+	if last := plan.Commands[len(plan.Commands)-1]; strings.Join(last, " ") != "systemctl reload-or-restart fail2ban" {
+		t.Errorf("unexpected last command %v", last)
+	}
+
+	plan, _ = harden.Fail2banPlan(harden.Fail2banOptions{Proxy: "nginx", Banaction: "nftables-multiport"})
+	if !strings.Contains(plan.Files[0].Content, "[nginx-http-auth]") || len(plan.Files) != 1 {
+		t.Errorf("expected nginx jails:\n%s", plan.Files[0].Content)
+	}
+	if _, err := harden.Fail2banPlan(harden.Fail2banOptions{Proxy: "apache"}); err == nil {
+		t.Error("expected unsupported proxy error")
+	}
+}
+
+func TestHardenFail2banStatus(t *testing.T) {
+	run := func(name string, args ...string) ([]byte, error) {
+		switch strings.Join(args, " ") {
+		case "status":
+			return []byte("Status\n|- Number of jail:\t2\n`- Jail list:\tportunix-caddy, sshd\n"), nil
+		case "status sshd":
+			return []byte("Status for the jail: sshd\n|- Filter\n|  |- Currently failed:\t3\n|  |- Total failed:\t41\n|  `- Journal matches:\t_SYSTEMD_UNIT=sshd.service + _COMM=sshd\n`- Actions\n   |- Currently banned:\t2\n   |- Total banned:\t9\n   `- Banned IP list:\t192.0.2.4 198.51.100.7\n"), nil
+		case "status portunix-caddy":
+			return []byte("Status for the jail: portunix-caddy\n|- Filter\n|  |- Currently failed:\t0\n|  |- Total failed:\t0\n|  `- File list:\t/var/log/caddy/access.log\n`- Actions\n   |- Currently banned:\t0\n   |- Total banned:\t0\n   `- Banned IP list:\t\n"), nil
+		}
+		return nil, errors.New("unexpected command")
+	}
+	jails, err := harden.Fail2banStatus(run)
+	if err != nil {
+		t.Fatalf("Fail2banStatus failed: %v", err)
+	}
+	if len(jails) != 2 || jails[1].Name != "sshd" {
+		t.Fatalf("unexpected jails %+v", jails)
+	}
+	sshd := jails[1]
+	if sshd.CurrentlyFailed != 3 || sshd.TotalFailed != 41 || sshd.CurrentlyBanned != 2 || sshd.TotalBanned != 9 || len(sshd.BannedIPs) != 2 {
+		t.Errorf("unexpected sshd status %+v", sshd)
+	}
+	if len(jails[0].BannedIPs) != 0 {
+		t.Errorf("expected no banned addresses, got %v", jails[0].BannedIPs)
+	}
+}
@@ -0,0 +1,247 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"portunix.ai/app/k8s"
+)
+
+func TestK8sConvertCompose(t *testing.T) {
+	compose := `
+services:
+  web_app:
+    image: nginx:${TAG:-latest}
+    command: nginx -g "daemon off;"
+    ports:
+      - "8080:80"
+      - "127.0.0.1:8443:443/tcp"
+    environment:
+      - MODE=${MODE}
+      - UNSET
+    volumes:
+      - ./site:/usr/share/nginx/html:ro
+      - data:/data
+    deploy:
+      replicas: 2
+    healthcheck:
+      test: ["CMD-SHELL", "curl -f http://localhost/"]
+      interval: 15s
+      retries: 3
+  worker:
+    image: busybox
+    environment:
+      PORT: 9000
+volumes:
+  data:
+`
+	conversion, err := k8s.ConvertCompose([]byte(compose), map[string]string{"MODE": "prod"}, "shop")
+	if err != nil {
+		t.Fatalf("ConvertCompose failed: %v", err)
+	}
+	var kinds []string
+	for _, m := range conversion.Manifests {
+		kinds = append(kinds, m.Kind+"/"+m.Metadata.Name)
+	}
+	want := "Namespace/shop,PersistentVolumeClaim/data,Deployment/web-app,Service/web-app,Deployment/worker"
+	if got := strings.Join(kinds, ","); got != want {
+		t.Errorf("manifests = %s, want %s", got, want)
+	}
+
+	out, err := conversion.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"image: nginx:latest",
+		"replicas: 2",
+		"- daemon off;",
+		"value: prod",
+		"containerPort: 443",
+		"claimName: data",
+		"type: Recreate",
+		"- curl -f http://localhost/",
+		"periodSeconds: 15",
+		`value: "9000"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("manifests missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "UNSET") || strings.Contains(out, "8080") {
+		t.Errorf("unset variables and published ports should be dropped:\n%s", out)
+	}
+	docs := strings.Split(out, "---\n")
+	for _, doc := range docs {
+		var m map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &m); err != nil {
+			t.Errorf("invalid manifest: %v\n%s", err, doc)
+		}
+	}
+
+	warnings := strings.Join(conversion.Warnings, "\n")
+	for _, want := range []string{"named web-app", "bind mount ./site", "worker has no ports"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("warnings missing %q:\n%s", want, warnings)
+		}
+	}
+
+	if _, err := k8s.ConvertCompose([]byte("services:\n  app:\n    build: .\n"), nil, "x"); err == nil {
+		t.Error("service without image should fail")
+	}
+	if _, err := k8s.ConvertCompose([]byte("services:\n  app:\n    image: a:${TAG:?set TAG}\n"), nil, "x"); err == nil {
+		t.Error("missing required variable should fail")
+	}
+}
+
+func TestK8sInterpolate(t *testing.T) {
+	env := map[string]string{"A": "1", "EMPTY": ""}
+	cases := map[string]string{
+		"$A-${A}":         "1-1",
+		"${EMPTY:-x}":     "x",
+		"${EMPTY-x}":      "",
+		"${MISSING-x}":    "x",
+		"$$A":             "$A",
+		"url=${MISSING}/": "url=/",
+	}
+	for in, want := range cases {
+		if got, err := k8s.Interpolate(in, env); err != nil || got != want {
+			t.Errorf("Interpolate(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+}
+
+func TestK8sPFTStack(t *testing.T) {
+	t.Setenv(k8s.EnvStateDir, t.TempDir())
+
+	env, err := k8s.PFTEnv("pft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := k8s.PFTEnv("pft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env["FIDER_DB_PASSWORD"] == "" || env["FIDER_DB_PASSWORD"] != again["FIDER_DB_PASSWORD"] {
+		t.Errorf("secrets should be generated once: %v / %v", env, again)
+	}
+	conversion, err := k8s.ConvertCompose(k8s.PFTCompose(), env, "pft")
+	if err != nil {
+		t.Fatalf("PFT stack conversion failed: %v", err)
+	}
+	out, _ := conversion.YAML()
+	for _, want := range []string{"name: db", "name: fider", "name: mailhog", "port: 5432", "postgres://fider:" + env["FIDER_DB_PASSWORD"] + "@db:5432"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("PFT manifests missing %q", want)
+		}
+	}
+	if len(conversion.Warnings) != 0 {
+		t.Errorf("PFT stack should convert without warnings: %v", conversion.Warnings)
+	}
+}
+
+func TestK8sKubeconfigMerge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	existing := `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://prod:6443
+contexts:
+- name: prod
+  context: {cluster: prod, user: prod}
+users:
+- name: prod
+  user: {token: secret}
+current-context: prod
+`
+	if err := os.WriteFile(path, []byte(existing), 0600); err != nil {
+		t.Fatal(err)
+	}
+	k3s := `apiVersion: v1
+kind: Config
+clusters:
+- name: default
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: default
+  context: {cluster: default, user: default}
+users:
+- name: default
+  user: {client-certificate-data: abc}
+current-context: default
+`
+	renamed, err := k8s.RenameKubeconfig([]byte(k3s), "k3s-dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx, _ := k8s.KubeconfigContext(renamed); ctx != "k3s-dev" {
+		t.Errorf("renamed context = %q", ctx)
+	}
+	if err := k8s.MergeKubeconfig(renamed, path, true); err != nil {
+		t.Fatal(err)
+	}
+	// Merging twice replaces the entries instead of duplicating them
+	if err := k8s.MergeKubeconfig(renamed, path, true); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	merged := string(data)
+	if strings.Count(merged, "name: k3s-dev") != 3 || !strings.Contains(merged, "token: secret") || !strings.Contains(merged, "current-context: k3s-dev") {
+		t.Errorf("unexpected merged kubeconfig:\n%s", merged)
+	}
+	if strings.Contains(merged, "name: default") {
+		t.Errorf("k3s names should be renamed:\n%s", merged)
+	}
+
+	if err := k8s.RemoveFromKubeconfig(path, "k3s-dev"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "k3s-dev") || !strings.Contains(string(data), "name: prod") {
+		t.Errorf("unexpected kubeconfig after removal:\n%s", data)
+	}
+}
+
+func TestK8sClusterRecord(t *testing.T) {
+	t.Setenv(k8s.EnvStateDir, t.TempDir())
+
+	if err := k8s.ValidateClusterName("Dev_1"); err == nil {
+		t.Error("uppercase and underscores should be rejected")
+	}
+	if _, err := k8s.NewProvider("minikube"); err == nil {
+		t.Error("unknown provider should fail")
+	}
+	if cfg := k8s.KindConfig(3); strings.Count(cfg, "role: worker") != 2 {
+		t.Errorf("kind config should have 2 workers:\n%s", cfg)
+	}
+	if args := strings.Join(k8s.K3dCreateArgs("dev", 3), " "); !strings.Contains(args, "--agents 2") || !strings.Contains(args, "--kubeconfig-update-default=false") {
+		t.Errorf("unexpected k3d args %s", args)
+	}
+
+	c := &k8s.Cluster{Name: "dev", Provider: "kind", Nodes: 3, Context: "kind-dev", CreatedAt: time.Now()}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	clusters, err := k8s.ListClusters()
+	if err != nil || len(clusters) != 1 || clusters[0].Context != "kind-dev" {
+		t.Fatalf("ListClusters = %+v, %v", clusters, err)
+	}
+	if err := k8s.RemoveCluster("dev"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k8s.LoadCluster("dev"); err == nil {
+		t.Error("cluster should be removed")
+	}
+}
@@ -0,0 +1,35 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"portunix.ai/app/launchd"
+)
+
+func TestLaunchdAgentPlist(t *testing.T) {
+	agent := &launchd.Agent{
+		Label:                "ai.portunix.test",
+		ProgramArguments:     []string{"/usr/local/bin/portunix", "cert", "renew", "--all"},
+		StartCalendar:        launchd.Daily(3, 17),
+		EnvironmentVariables: map[string]string{"A": "x&y"},
+	}
+	plist := string(agent.Plist())
+	for _, want := range []string{
+		"<key>Label</key>\n\t<string>ai.portunix.test</string>",
+		"\t\t<string>/usr/local/bin/portunix</string>\n\t\t<string>cert</string>",
+		"<key>Hour</key>\n\t\t<integer>3</integer>\n\t\t<key>Minute</key>\n\t\t<integer>17</integer>",
+		"<string>x&amp;y</string>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("plist is missing %q:\n%s", want, plist)
+		}
+	}
+	if strings.Contains(plist, "RunAtLoad") || strings.Contains(plist, "Weekday") {
+		t.Errorf("unset fields must be omitted:\n%s", plist)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package unit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"portunix.ai/portunix/pkg/logging"
+)
+
+func TestLoggingParseFlags(t *testing.T) {
+	t.Setenv(logging.EnvVerbose, "")
+	t.Setenv(logging.EnvQuiet, "")
+	t.Setenv(logging.EnvLogJSON, "")
+
+	opts, rest := logging.ParseFlags([]string{"--verbose", "--log-json", "container", "run", "-v", "/data:/data", "--", "--quiet"})
+	if !opts.Verbose || opts.Quiet || !opts.JSON {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+	expected := []string{"container", "run", "-v", "/data:/data", "--", "--quiet"}
+	if !reflect.DeepEqual(rest, expected) {
+		t.Errorf("expected remaining args %v, got %v", expected, rest)
+	}
+
+	opts, _ = logging.ParseFlags([]string{"--verbose", "--quiet"})
+	if opts.Verbose || !opts.Quiet {
+		t.Errorf("last flag should win, got %+v", opts)
+	}
+}
+
+func TestLoggingParseFlagsLeadingOnly(t *testing.T) {
+	t.Setenv(logging.EnvVerbose, "")
+	t.Setenv(logging.EnvQuiet, "")
+	t.Setenv(logging.EnvLogJSON, "")
+
+	// --verbose after the command belongs to the subcommand
+	args := []string{"python", "venv", "info", "--verbose"}
+	opts, rest := logging.ParseFlags(args)
+	if opts.Verbose {
+		t.Errorf("subcommand flag was consumed: %+v", opts)
+	}
+	if !reflect.DeepEqual(rest, args) {
+		t.Errorf("expected args %v untouched, got %v", args, rest)
+	}
+
+	opts, rest = logging.ParseFlags([]string{"--quiet", "mcp", "test", "--verbose"})
+	if !opts.Quiet || opts.Verbose || !reflect.DeepEqual(rest, []string{"mcp", "test", "--verbose"}) {
+		t.Errorf("unexpected result: %+v %v", opts, rest)
+	}
+}
+
+func TestLoggingFileCapture(t *testing.T) {
+	dir := t.TempDir()
+	if err := logging.Init("test", logging.Options{Quiet: true, LogDir: dir}); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	logging.Debug("debug record", "key", "value")
+	logging.Capture(zerolog.ErrorLevel, "captured failure", "exit_code", 2)
+	logging.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, logging.LogFileName(time.Now())))
+	if err != nil {
+		t.Fatalf("log file not written: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records (file captures debug even with --quiet), got %d", len(lines))
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatalf("record is not JSON: %v", err)
+	}
+	if record["level"] != "error" || record["component"] != "test" || record["message"] != "captured failure" {
+		t.Errorf("unexpected record: %v", record)
+	}
+}
+
+func TestLoggingCleanup(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, logging.LogFileName(time.Now().AddDate(0, 0, -30)))
+	recent := filepath.Join(dir, logging.LogFileName(time.Now()))
+	for _, f := range []string{old, recent} {
+		if err := os.WriteFile(f, []byte("{}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := logging.Cleanup(dir, logging.DefaultRetentionDays)
+	if err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("expected 1 removed file, got %d", removed)
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("recent log file should be kept")
+	}
+}
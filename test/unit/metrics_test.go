@@ -0,0 +1,113 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"portunix.ai/app/metrics"
+)
+
+func TestMetricsCommandFromArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		command string
+		pkg     string
+	}{
+		{[]string{"install", "nodejs", "--variant", "20"}, "install", "nodejs"},
+		{[]string{"--verbose", "container", "run", "-d", "nginx"}, "container run", ""},
+		{[]string{"container", "-v", "/data:/data"}, "container", ""},
+		{[]string{"unzip", "archive.zip"}, "unzip", ""},
+		{nil, "", ""},
+	}
+	for _, tt := range tests {
+		command, pkg := metrics.CommandFromArgs(tt.args)
+		if command != tt.command || pkg != tt.pkg {
+			t.Errorf("CommandFromArgs(%v) = %q, %q; want %q, %q", tt.args, command, pkg, tt.command, tt.pkg)
+		}
+	}
+}
+
+func TestMetricsCategorize(t *testing.T) {
+	tests := map[string]string{
+		"failed to download from https://example.com: dial tcp: no such host": metrics.FailureNetwork,
+		"open /usr/local/bin/node: permission denied":                         metrics.FailurePermission,
+		"checksum mismatch for node-v20.tar.gz":                               metrics.FailureChecksum,
+		"package 'foo' not found in registry":                                 metrics.FailureNotFound,
+		"something odd":                                                       metrics.FailureUnknown,
+	}
+	for message, expected := range tests {
+		if got := metrics.Categorize(message); got != expected {
+			t.Errorf("Categorize(%q) = %s, want %s", message, got, expected)
+		}
+	}
+}
+
+func TestMetricsRecordSummarizeExport(t *testing.T) {
+	t.Setenv(metrics.EnvDir, t.TempDir())
+	t.Setenv("PORTUNIX_METRICS_ENABLED", "true")
+
+	events := []metrics.Event{
+		{Command: "install", Package: "nodejs", Success: false, Failure: metrics.FailureNetwork, DurationMs: 3000},
+		{Command: "install", Package: "nodejs", Success: false, Failure: metrics.FailureNetwork, DurationMs: 1000},
+		{Command: "install", Package: "python", Success: true, DurationMs: 2000},
+		{Command: "container run", Success: true, DurationMs: 100},
+	}
+	for _, e := range events {
+		if err := metrics.Record(e); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	loaded, err := metrics.Load()
+	if err != nil || len(loaded) != 4 {
+		t.Fatalf("expected 4 events, got %d (%v)", len(loaded), err)
+	}
+
+	summary := metrics.Summarize(loaded)
+	if summary.Commands[0].Command != "install" || summary.Commands[0].Runs != 3 || summary.Commands[0].AvgDurationMs != 2000 {
+		t.Errorf("unexpected command stats: %+v", summary.Commands[0])
+	}
+	if summary.Packages[0].Package != "nodejs" || summary.Packages[0].Failures != 2 || summary.Packages[0].Causes[metrics.FailureNetwork] != 2 {
+		t.Errorf("most failing package should be nodejs: %+v", summary.Packages)
+	}
+
+	var received struct {
+		Events []metrics.Event `json:"events"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	if n, err := metrics.Export(server.URL); err != nil || n != 4 || len(received.Events) != 4 {
+		t.Fatalf("export: n=%d err=%v received=%d", n, err, len(received.Events))
+	}
+	if pending, _ := metrics.Pending(); len(pending) != 0 {
+		t.Errorf("expected no pending events after export, got %d", len(pending))
+	}
+}
+
+func TestMetricsTrackerDisabled(t *testing.T) {
+	t.Setenv(metrics.EnvDir, t.TempDir())
+	t.Setenv("PORTUNIX_METRICS_ENABLED", "false")
+
+	tracker := metrics.Start([]string{"install", "nodejs"}, "dev")
+	if tracker != nil {
+		t.Fatal("tracker should be nil when metrics are disabled")
+	}
+	tracker.PrepareHelper()
+	tracker.Finish("ptx-installer", errors.New("boom"))
+
+	if events, _ := metrics.Load(); len(events) != 0 {
+		t.Errorf("nothing should be recorded when disabled, got %d events", len(events))
+	}
+}
@@ -0,0 +1,179 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package unit
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"portunix.ai/app/edge"
+	"portunix.ai/app/remote"
+)
+
+func TestRemoteParseTarget(t *testing.T) {
+	for spec, want := range map[string]remote.Target{
+		"server":                 {Host: "server"},
+		"deploy@server":          {User: "deploy", Host: "server"},
+		"root@10.0.0.5:2222":     {User: "root", Host: "10.0.0.5", Port: 2222},
+		"admin@[2001:db8::1]:22": {User: "admin", Host: "2001:db8::1", Port: 22},
+		"2001:db8::1":            {Host: "2001:db8::1"},
+	} {
+		got, err := remote.ParseTarget(spec)
+		if err != nil {
+			t.Errorf("ParseTarget(%q) failed: %v", spec, err)
+			continue
+		}
+		if got.User != want.User || got.Host != want.Host || got.Port != want.Port {
+			t.Errorf("ParseTarget(%q) = %+v, want %+v", spec, got, want)
+		}
+	}
+	for _, spec := range []string{"", "@server", "user@", "host:99999", "-oProxyCommand=x"} {
+		if _, err := remote.ParseTarget(spec); err == nil {
+			t.Errorf("expected %q to be rejected", spec)
+		}
+	}
+
+	target, _ := remote.ParseTarget("root@server:2222")
+	args := strings.Join(target.SSHArgs(false, "uname"), " ")
+	if args != "-p 2222 -T root@server uname" {
+		t.Errorf("unexpected ssh args %q", args)
+	}
+}
+
+func TestRemoteEdgeTarget(t *testing.T) {
+	t.Setenv(edge.EnvStateDir, t.TempDir())
+	d := &edge.Deployment{Name: "edge1", PublicIP: "203.0.113.7", SSHUser: "root", SSHKey: "/keys/id_ed25519", HostKey: "ssh-ed25519 AAAAC3Nza"}
+	if err := d.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := remote.Resolve("edge1")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if target.Destination() != "root@203.0.113.7" || target.IdentityFile != "/keys/id_ed25519" {
+		t.Errorf("unexpected edge target %+v", target)
+	}
+	pinned, err := os.ReadFile(target.KnownHosts)
+	if err != nil || string(pinned) != "203.0.113.7 ssh-ed25519 AAAAC3Nza\n" {
+		t.Errorf("unexpected known_hosts %q, %v", pinned, err)
+	}
+	if !strings.Contains(strings.Join(target.SSHArgs(true, "true"), " "), "StrictHostKeyChecking=yes -t root@203.0.113.7") {
+		t.Errorf("edge target must pin its host key: %v", target.SSHArgs(true, "true"))
+	}
+
+	// Names that are not deployments are plain host names
+	if target, _ := remote.Resolve("edge2"); target.Host != "edge2" || target.IdentityFile != "" {
+		t.Errorf("unexpected target %+v", target)
+	}
+}
+
+func TestRemoteParseFlags(t *testing.T) {
+	host, agentless, rest, err := remote.ParseFlags([]string{"--host", "edge1", "--agentless", "container", "list"})
+	if err != nil || host != "edge1" || !agentless || strings.Join(rest, " ") != "container list" {
+		t.Errorf("ParseFlags = %q %v %v %v", host, agentless, rest, err)
+	}
+	// --host after the command belongs to the command
+	host, _, rest, _ = remote.ParseFlags([]string{"trace", "serve", "--host", "0.0.0.0"})
+	if host != "" || len(rest) != 4 {
+		t.Errorf("ParseFlags took a command flag: %q %v", host, rest)
+	}
+	if _, _, _, err := remote.ParseFlags([]string{"--host"}); err == nil {
+		t.Error("expected missing value error")
+	}
+}
+
+// fakeHost emulates the remote shell commands used by the executor
+type fakeHost struct {
+	uname     string
+	installed string
+	files     map[string][]byte
+	commands  []string
+}
+
+func (h *fakeHost) Run(command string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+	h.commands = append(h.commands, command)
+	switch {
+	case strings.HasPrefix(command, "echo \"os="):
+		io.WriteString(stdout, h.uname+"\ninstalled="+h.installed+"\n")
+		for path := range h.files {
+			if strings.Contains(command, filepath.Dir(path)+"/*") {
+				io.WriteString(stdout, "cached="+filepath.Base(path)+"\n")
+			}
+		}
+	case strings.HasPrefix(command, "mkdir -p "):
+		data, _ := io.ReadAll(stdin)
+		path := command[strings.LastIndex(command, " ")+1:]
+		h.files[path] = data
+	}
+	return nil
+}
+
+func TestRemoteExecutor(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "portunix"), []byte("portunix-binary"), 0755)
+	os.WriteFile(filepath.Join(dir, "ptx-container"), []byte("helper-binary"), 0755)
+
+	host := &fakeHost{uname: "os=Linux\narch=x86_64", files: map[string][]byte{}}
+	target, _ := remote.ParseTarget("root@server")
+	var stderr bytes.Buffer
+	e := &remote.Executor{Target: target, Runner: host, Helper: "ptx-container", BinaryDir: dir, Stdout: io.Discard, Stderr: &stderr}
+
+	mode, err := e.Execute([]string{"container", "list", "--format", "{{.Names}}"})
+	if err != nil || mode != remote.ModeUploaded {
+		t.Fatalf("Execute = %s, %v", mode, err)
+	}
+	if len(host.files) != 2 || strings.Count(stderr.String(), "Uploading") != 2 {
+		t.Fatalf("expected portunix and helper uploads, got %v", host.files)
+	}
+	last := host.commands[len(host.commands)-1]
+	if !strings.HasPrefix(last, "~/.portunix/remote/") || !strings.HasSuffix(last, "/portunix container list --format '{{.Names}}'") {
+		t.Errorf("unexpected remote command %q", last)
+	}
+
+	// Uploaded binaries are reused
+	stderr.Reset()
+	if _, err := e.Execute([]string{"container", "list"}); err != nil || stderr.Len() != 0 {
+		t.Errorf("expected cached binaries to be reused: %v %s", err, stderr.String())
+	}
+
+	// Without a binary for the platform an installed portunix is used
+	host = &fakeHost{uname: "os=Linux\narch=riscv64", installed: "/usr/local/bin/portunix", files: map[string][]byte{}}
+	e = &remote.Executor{Target: target, Runner: host, Stdout: io.Discard, Stderr: io.Discard}
+	if mode, err := e.Execute([]string{"version"}); err != nil || mode != remote.ModeInstalled {
+		t.Errorf("Execute = %s, %v; want installed", mode, err)
+	}
+
+	// and a shell script when there is neither
+	host.installed = ""
+	if mode, err := e.Execute([]string{"install", "docker"}); err != nil || mode != remote.ModeAgentless {
+		t.Errorf("Execute = %s, %v; want agentless", mode, err)
+	}
+	if host.commands[len(host.commands)-1] != "sh -s" {
+		t.Errorf("agentless mode must run a shell script, got %q", host.commands[len(host.commands)-1])
+	}
+	if _, err := e.Execute([]string{"vm", "start"}); err == nil || !strings.Contains(err.Error(), "needs portunix on the remote host") {
+		t.Errorf("expected unsupported agentless command error, got %v", err)
+	}
+}
+
+func TestRemoteAgentlessScript(t *testing.T) {
+	script, err := remote.AgentlessScript([]string{"install", "docker", "git"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(script, "apt-get install -y docker.io git") || !strings.Contains(script, "dnf install -y docker git") {
+		t.Errorf("unexpected install script:\n%s", script)
+	}
+	for _, args := range [][]string{{"install"}, {"install", "git;rm"}, {"install", "--variant", "x"}} {
+		if _, err := remote.AgentlessScript(args); err == nil {
+			t.Errorf("expected %v to be rejected", args)
+		}
+	}
+}
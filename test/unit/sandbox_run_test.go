@@ -0,0 +1,118 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"portunix.ai/app/sandbox"
+)
+
+func TestSandboxPrepareRun(t *testing.T) {
+	dir := t.TempDir()
+	opts := sandbox.RunOptions{
+		Package:     "java",
+		Args:        []string{"--variant", "17"},
+		Verify:      "java -version",
+		PortunixDir: filepath.Join(dir, "bin"),
+		CacheDir:    filepath.Join(dir, "cache & more"),
+		WorkDir:     filepath.Join(dir, "run"),
+	}
+	plan, err := sandbox.PrepareRun(opts)
+	if err != nil {
+		t.Fatalf("PrepareRun failed: %v", err)
+	}
+
+	data, err := os.ReadFile(plan.WsbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wsb := string(data)
+	for _, want := range []string{
+		"<Networking>Disable</Networking>",
+		"<HostFolder>" + opts.PortunixDir + "</HostFolder>",
+		`<SandboxFolder>C:\Portunix</SandboxFolder>`,
+		"<ReadOnly>true</ReadOnly>",
+		"cache &amp; more</HostFolder>",
+		`<Command>cmd.exe /c C:\PortunixRun\run.cmd</Command>`,
+	} {
+		if !strings.Contains(wsb, want) {
+			t.Errorf("wsb missing %q:\n%s", want, wsb)
+		}
+	}
+
+	script, err := os.ReadFile(filepath.Join(opts.WorkDir, "run.cmd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`set PORTUNIX_CACHE_DIR=C:\PortunixCache`,
+		`C:\Portunix\portunix.exe install java --variant 17 > %R%\install.log 2>&1`,
+		`cmd /c "java -version" > %R%\verify.log 2>&1`,
+		`echo done> %R%\done.txt`,
+	} {
+		if !strings.Contains(string(script), want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+
+	for _, bad := range []sandbox.RunOptions{
+		{Package: "java & del C:\\"},
+		{Package: "-java"},
+		{Package: "java", Args: []string{"%PATH%"}},
+		{Package: "java", Verify: "a\r\nb"},
+	} {
+		bad.WorkDir = filepath.Join(dir, "bad")
+		bad.PortunixDir = dir
+		bad.CacheDir = dir
+		if _, err := sandbox.PrepareRun(bad); err == nil {
+			t.Errorf("PrepareRun(%+v) should fail", bad)
+		}
+	}
+}
+
+func TestSandboxReadRunResult(t *testing.T) {
+	dir := t.TempDir()
+	opts := sandbox.RunOptions{Package: "java", Verify: "java -version", WorkDir: dir}
+
+	if _, err := sandbox.ReadRunResult(opts); err == nil {
+		t.Fatal("unfinished test should fail")
+	}
+
+	files := map[string]string{
+		"install.exit": "0 \r\n",
+		"install.log":  "Installed java\r\n",
+		"verify.exit":  "9009\r\n",
+		"verify.log":   "'java' is not recognized\r\n",
+		"done.txt":     "done\r\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := sandbox.ReadRunResult(opts)
+	if err != nil {
+		t.Fatalf("ReadRunResult failed: %v", err)
+	}
+	if result.InstallExit != 0 || result.VerifyExit == nil || *result.VerifyExit != 9009 {
+		t.Errorf("unexpected exit codes: %+v", result)
+	}
+	if result.Success {
+		t.Error("failed verification must fail the test")
+	}
+	if !strings.Contains(result.InstallLog, "Installed java") {
+		t.Errorf("install log not read: %q", result.InstallLog)
+	}
+
+	opts.Verify = ""
+	if result, err = sandbox.ReadRunResult(opts); err != nil || !result.Success {
+		t.Errorf("install-only test should pass: %+v %v", result, err)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package unit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"portunix.ai/app/tui"
+)
+
+func TestTUIDecodeKeys(t *testing.T) {
+	keys := tui.DecodeKeys([]byte("j\x1b[A\x1b[6~\r\tq\x1b"))
+	expected := []tui.Key{"j", tui.KeyUp, tui.KeyPageDown, tui.KeyEnter, tui.KeyTab, "q", tui.KeyEsc}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v, got %v", expected, keys)
+	}
+}
+
+func newTestModel() *tui.Model {
+	rows := []tui.Row{
+		{ID: "web", Columns: []string{"web", "nginx", "running"}},
+		{ID: "db", Columns: []string{"db", "postgres", "exited"}},
+	}
+	containers := &tui.Tab{
+		Title:   "Containers",
+		Columns: []string{"NAME", "IMAGE", "STATE"},
+		Load:    func() ([]tui.Row, error) { return rows, nil },
+		Actions: []tui.Action{
+			{Key: "s", Label: "start", Command: func(r tui.Row) []string { return []string{"container", "start", r.ID} }},
+			{Key: "x", Label: "stop", Confirm: true, Command: func(r tui.Row) []string { return []string{"container", "stop", r.ID} }},
+		},
+	}
+	empty := &tui.Tab{Title: "Playbooks", Columns: []string{"FILE"}, Load: func() ([]tui.Row, error) { return nil, nil }}
+
+	model := tui.NewModel("Portunix", containers, empty)
+	model.EnsureLoaded()
+	return model
+}
+
+func TestTUIModelActions(t *testing.T) {
+	model := newTestModel()
+
+	model.Update(tui.KeyDown)
+	result := model.Update("s")
+	if !reflect.DeepEqual(result.Run, []string{"container", "start", "db"}) {
+		t.Errorf("unexpected action command: %v", result.Run)
+	}
+
+	// Confirmed actions wait for "y"
+	if result := model.Update("x"); result.Run != nil {
+		t.Errorf("stop should ask for confirmation, got %v", result.Run)
+	}
+	if !strings.Contains(model.View(), "stop db? (y/n)") {
+		t.Error("confirmation prompt not shown")
+	}
+	if result := model.Update("y"); !reflect.DeepEqual(result.Run, []string{"container", "stop", "db"}) {
+		t.Errorf("unexpected confirmed command: %v", result.Run)
+	}
+
+	if result := model.Update("q"); !result.Quit {
+		t.Error("q should quit")
+	}
+}
+
+func TestTUIModelView(t *testing.T) {
+	model := newTestModel()
+	model.SetSize(60, 12)
+
+	view := model.View()
+	for _, expected := range []string{"1 Containers", "NAME", "nginx", "s start", "q quit"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("view does not contain %q", expected)
+		}
+	}
+
+	model.Update(tui.KeyTab)
+	if model.Active().Title != "Playbooks" || !strings.Contains(model.View(), "Nothing to show") {
+		t.Error("expected empty Playbooks tab after Tab")
+	}
+	model.Update("1")
+	if model.Active().Title != "Containers" {
+		t.Error("digit should select the tab")
+	}
+}
@@ -0,0 +1,197 @@
+/*
+ *  This file is part of CassandraGargoyle Community Project
+ *  Licensed under the MIT License - see LICENSE file for details
+ */
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"portunix.ai/app/remote"
+	"portunix.ai/app/virt"
+)
+
+func TestVirtCloudInitUserData(t *testing.T) {
+	c := &virt.CloudInit{
+		Hostname:     "dev-vm",
+		User:         "portunix",
+		SSHPublicKey: "ssh-ed25519 AAAAC3Nza portunix@dev-vm\n",
+		Packages:     []string{"git"},
+		RunCmd:       []string{"touch /tmp/ready"},
+	}
+	userData := c.UserData()
+	for _, want := range []string{
+		"#cloud-config\n",
+		"hostname: dev-vm\n",
+		"  - name: portunix\n",
+		"    sudo: ALL=(ALL) NOPASSWD:ALL\n",
+		"      - ssh-ed25519 AAAAC3Nza portunix@dev-vm\n",
+		"ssh_pwauth: false\n",
+		"  - qemu-guest-agent\n  - git\n",
+		"  - \"touch /tmp/ready\"\n",
+	} {
+		if !strings.Contains(userData, want) {
+			t.Errorf("user-data missing %q:\n%s", want, userData)
+		}
+	}
+	if meta := c.MetaData(); meta != "instance-id: dev-vm\nlocal-hostname: dev-vm\n" {
+		t.Errorf("unexpected meta-data %q", meta)
+	}
+
+	c.Custom = "#cloud-config\nusers: []\n"
+	if c.UserData() != c.Custom {
+		t.Error("custom user-data should replace the generated one")
+	}
+
+	if _, err := virt.LookupCloudImage("ubuntu-22.04"); err != nil {
+		t.Errorf("ubuntu-22.04 should be in the image catalog: %v", err)
+	}
+	if _, err := virt.LookupCloudImage("plan9"); err == nil {
+		t.Error("unknown image should fail")
+	}
+}
+
+func TestVirtInstanceRecord(t *testing.T) {
+	t.Setenv("PORTUNIX_VIRT_DIR", t.TempDir())
+	t.Setenv("PORTUNIX_FLEET_DIR", t.TempDir())
+
+	if err := virt.ValidateInstanceName("bad_name"); err == nil {
+		t.Error("underscore should be rejected in VM names")
+	}
+	if err := virt.ValidateInstanceName("Ubuntu-22.04"); err != nil {
+		t.Errorf("WSL distribution names should be accepted: %v", err)
+	}
+	if _, err := virt.LoadInstance("dev-vm"); err == nil {
+		t.Fatal("missing instance should fail")
+	}
+
+	inst := &virt.Instance{
+		Name:      "dev-vm",
+		Provider:  "virtualbox",
+		Image:     "ubuntu-22.04",
+		User:      "portunix",
+		KeyPath:   filepath.Join(virt.InstanceDir("dev-vm"), "id_ed25519"),
+		IP:        "127.0.0.1",
+		SSHPort:   2222,
+		CreatedAt: time.Now(),
+	}
+	if err := virt.SaveInstance(inst); err != nil {
+		t.Fatalf("SaveInstance failed: %v", err)
+	}
+	loaded, err := virt.LoadInstance("dev-vm")
+	if err != nil {
+		t.Fatalf("LoadInstance failed: %v", err)
+	}
+	if loaded.Provider != "virtualbox" || loaded.SSHPort != 2222 {
+		t.Errorf("unexpected instance %+v", loaded)
+	}
+
+	args := strings.Join(loaded.SSHArgs(), " ")
+	for _, want := range []string{"-i " + inst.KeyPath, "-p 2222", "StrictHostKeyChecking=accept-new", "portunix@127.0.0.1"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("ssh args missing %q: %s", want, args)
+		}
+	}
+
+	target, err := remote.Resolve("dev-vm")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if target.Host != "127.0.0.1" || target.Port != 2222 || target.User != "portunix" || target.IdentityFile != inst.KeyPath {
+		t.Errorf("unexpected target %+v", target)
+	}
+	if args := strings.Join(target.SSHArgs(false, "true"), " "); !strings.Contains(args, "StrictHostKeyChecking=accept-new") {
+		t.Errorf("VM target should accept a new host key: %s", args)
+	}
+
+	if err := virt.RemoveInstance("dev-vm"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := virt.LoadInstance("dev-vm"); err == nil {
+		t.Error("instance should be removed")
+	}
+}
+
+func TestVirtCloudInitTemplates(t *testing.T) {
+	t.Setenv("PORTUNIX_VIRT_DIR", t.TempDir())
+	t.Setenv("PORTUNIX_CLOUDINIT_PATH", "")
+
+	templates, err := virt.ListCloudInitTemplates(virt.CloudInitTemplateDirs())
+	if err != nil {
+		t.Fatalf("ListCloudInitTemplates failed: %v", err)
+	}
+	var names []string
+	for _, tmpl := range templates {
+		names = append(names, tmpl.Name)
+	}
+	if got := strings.Join(names, ","); got != "dev-workstation,docker-host,edge-proxy,k3s-node" {
+		t.Errorf("unexpected built-in templates %s", got)
+	}
+
+	dev, err := virt.LoadCloudInitTemplate("dev-workstation", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.Render(map[string]string{"user": "alice"}); err == nil || !strings.Contains(err.Error(), "ssh_key") {
+		t.Errorf("missing ssh_key should fail, got %v", err)
+	}
+	if _, err := dev.Render(map[string]string{"ssh_key": "k", "colour": "red"}); err == nil {
+		t.Error("unknown variable should fail")
+	}
+	userData, err := dev.Render(map[string]string{"user": "alice", "ssh_key": "ssh-ed25519 AAAA alice@host", "packages": "jq, htop"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{"#cloud-config\n", `  - name: "alice"`, `      - "ssh-ed25519 AAAA alice@host"`, `  - "jq"`, `  - "htop"`, `hostname: "devbox"`} {
+		if !strings.Contains(userData, want) {
+			t.Errorf("user-data missing %q:\n%s", want, userData)
+		}
+	}
+	if strings.Contains(userData, "# portunix:") {
+		t.Error("template header should be removed from user-data")
+	}
+
+	k3s, err := virt.LoadCloudInitTemplate("k3s-node", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k3s.Render(map[string]string{"ssh_key": "k", "server_url": "https://10.0.0.10:6443"}); err == nil {
+		t.Error("agent without a token should fail")
+	}
+	agent, err := k3s.Render(map[string]string{"ssh_key": "k", "server_url": "https://10.0.0.10:6443", "token": "it's-secret"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(agent, `      K3S_TOKEN='it'\''s-secret'`) || strings.Contains(agent, "INSTALL_K3S_EXEC") {
+		t.Errorf("unexpected agent user-data:\n%s", agent)
+	}
+
+	// A custom directory overrides a built-in template
+	dir := t.TempDir()
+	custom := "#cloud-config\n# portunix:description Custom proxy\n# portunix:var hostname=edge Host name\nhostname: {{ quote .hostname }}\n"
+	if err := os.WriteFile(filepath.Join(dir, "edge-proxy.yaml"), []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+	proxy, err := virt.LoadCloudInitTemplate("edge-proxy", virt.CloudInitTemplateDirs(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proxy.Source != filepath.Join(dir, "edge-proxy.yaml") || proxy.Description != "Custom proxy" {
+		t.Errorf("custom template not used: %+v", proxy)
+	}
+	if out, err := proxy.Render(map[string]string{"hostname": "gw"}); err != nil || out != "#cloud-config\nhostname: \"gw\"\n" {
+		t.Errorf("Render = %q, %v", out, err)
+	}
+
+	vars, err := virt.ParseCloudInitVars([]string{"user=alice", "ssh_key=@" + filepath.Join(dir, "edge-proxy.yaml")})
+	if err != nil || vars["user"] != "alice" || !strings.HasPrefix(vars["ssh_key"], "#cloud-config") {
+		t.Errorf("ParseCloudInitVars = %v, %v", vars, err)
+	}
+	if _, err := virt.ParseCloudInitVars([]string{"novalue"}); err == nil {
+		t.Error("variable without '=' should fail")
+	}
+}